@@ -29,6 +29,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/flare"
 	"github.com/DataDog/datadog-agent/pkg/logs"
 	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
+	"github.com/DataDog/datadog-agent/pkg/otlp"
 	"github.com/DataDog/datadog-agent/pkg/secrets"
 	"github.com/DataDog/datadog-agent/pkg/status"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
@@ -55,8 +56,11 @@ func SetupHandlers(r *mux.Router) *mux.Router {
 	r.HandleFunc("/status", getStatus).Methods("GET")
 	r.HandleFunc("/stream-logs", streamLogs).Methods("POST")
 	r.HandleFunc("/dogstatsd-stats", getDogstatsdStats).Methods("GET")
+	r.HandleFunc("/dogstatsd-stats/uds-clients", getDogstatsdUDSClientStats).Methods("GET")
 	r.HandleFunc("/status/formatted", getFormattedStatus).Methods("GET")
 	r.HandleFunc("/status/health", getHealth).Methods("GET")
+	r.HandleFunc("/otlp/health", getOTLPHealth).Methods("GET")
+	r.PathPrefix("/otlp/debug/").HandlerFunc(getOTLPZPages).Methods("GET")
 	r.HandleFunc("/{component}/status", componentStatusGetterHandler).Methods("GET")
 	r.HandleFunc("/{component}/status", componentStatusHandler).Methods("POST")
 	r.HandleFunc("/{component}/configs", componentConfigHandler).Methods("GET")
@@ -69,6 +73,7 @@ func SetupHandlers(r *mux.Router) *mux.Router {
 	r.HandleFunc("/tagger-list", getTaggerList).Methods("GET")
 	r.HandleFunc("/workload-list/short", getShortWorkloadList).Methods("GET")
 	r.HandleFunc("/workload-list/verbose", getVerboseWorkloadList).Methods("GET")
+	r.HandleFunc("/workload-resync/{collector}", workloadResync).Methods("POST")
 	r.HandleFunc("/secrets", secretInfo).Methods("GET")
 
 	return r
@@ -305,6 +310,48 @@ func getDogstatsdStats(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonStats)
 }
 
+func getDogstatsdUDSClientStats(w http.ResponseWriter, r *http.Request) {
+	log.Info("Got a request for the Dogstatsd UDS client stats.")
+
+	if !config.Datadog.GetBool("use_dogstatsd") {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := json.Marshal(map[string]string{
+			"error":      "Dogstatsd not enabled in the Agent configuration",
+			"error_type": "no server",
+		})
+		w.WriteHeader(400)
+		w.Write(body)
+		return
+	}
+
+	if !config.Datadog.GetBool("dogstatsd_origin_detection") {
+		w.Header().Set("Content-Type", "application/json")
+		body, _ := json.Marshal(map[string]string{
+			"error":      "Dogstatsd origin detection not enabled in the Agent configuration, it is required to identify the Unix Socket clients",
+			"error_type": "not enabled",
+		})
+		w.WriteHeader(400)
+		w.Write(body)
+		return
+	}
+
+	if common.DSD == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+		return
+	}
+
+	jsonStats, err := common.DSD.GetJSONUDSClientStats()
+	if err != nil {
+		log.Errorf("Error getting marshalled Dogstatsd UDS client stats: %s", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), 500)
+		return
+	}
+
+	w.Write(jsonStats)
+}
+
 func getFormattedStatus(w http.ResponseWriter, r *http.Request) {
 	log.Info("Got a request for the formatted status. Making formatted status.")
 	s, err := status.GetAndFormatStatus()
@@ -337,6 +384,36 @@ func getHealth(w http.ResponseWriter, r *http.Request) {
 	w.Write(jsonHealth)
 }
 
+func getOTLPHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !otlp.IsEnabled(config.Datadog) {
+		body, _ := json.Marshal(map[string]string{"error": "OTLP ingest is not enabled"})
+		http.Error(w, string(body), http.StatusNotFound)
+		return
+	}
+
+	jsonHealth, err := json.Marshal(common.OTLP.GetStatus())
+	if err != nil {
+		log.Errorf("Error marshalling OTLP status. Error: %v", err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), 500)
+		return
+	}
+
+	if !common.OTLP.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(jsonHealth)
+}
+
+func getOTLPZPages(w http.ResponseWriter, r *http.Request) {
+	if common.OTLP == nil {
+		http.Error(w, "the OTLP pipeline is not running", http.StatusServiceUnavailable)
+		return
+	}
+	http.StripPrefix("/otlp", common.OTLP.ZPagesHandler()).ServeHTTP(w, r)
+}
+
 func getCSRFToken(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(gui.CsrfToken))
 }
@@ -407,6 +484,16 @@ func workloadList(w http.ResponseWriter, verbose bool) {
 	w.Write(jsonDump)
 }
 
+func workloadResync(w http.ResponseWriter, r *http.Request) {
+	collector := mux.Vars(r)["collector"]
+	if err := workloadmeta.GetGlobalStore().Resync(collector); err != nil {
+		log.Errorf("Unable to resync workloadmeta collector %q: %s", collector, err)
+		body, _ := json.Marshal(map[string]string{"error": err.Error()})
+		http.Error(w, string(body), 500)
+		return
+	}
+}
+
 func secretInfo(w http.ResponseWriter, r *http.Request) {
 	info, err := secrets.GetDebugInfo()
 	if err != nil {
@@ -0,0 +1,70 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package metadata implements the api endpoints for the `/metadata` prefix.
+// This group of endpoints renders the metadata payloads the agent sends,
+// without sending them, so that their content can be checked.
+package metadata
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/report"
+	"github.com/DataDog/datadog-agent/pkg/metadata"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Kinds of metadata payloads that can be rendered
+const (
+	HostKind                   = "host"
+	InventoriesKind            = "inventories"
+	NetworkDevicesMetadataKind = "network-devices-metadata"
+)
+
+// Kinds lists the kinds of metadata payloads that can be rendered
+var Kinds = []string{HostKind, InventoriesKind, NetworkDevicesMetadataKind}
+
+// SetupHandlers adds the specific handlers for /metadata endpoints
+func SetupHandlers(r *mux.Router) *mux.Router {
+	r.HandleFunc("/v1/"+HostKind, getCollectorPayload("host")).Methods("GET")
+	r.HandleFunc("/v1/"+InventoriesKind, getCollectorPayload("inventories")).Methods("GET")
+	r.HandleFunc("/v1/"+NetworkDevicesMetadataKind, getNetworkDevicesMetadata).Methods("GET")
+
+	return r
+}
+
+// getCollectorPayload renders the payload of a metadata collector
+func getCollectorPayload(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		payload, err := metadata.GetPayload(r.Context(), name)
+		if err != nil {
+			setJSONError(w, log.Errorf("Unable to render the %s metadata payload: %s", name, err), 500)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(payload)
+	}
+}
+
+// getNetworkDevicesMetadata renders the last network devices metadata payloads of the SNMP checks,
+// the devices are polled by the checks so the payloads can't be built on demand
+func getNetworkDevicesMetadata(w http.ResponseWriter, r *http.Request) {
+	payload, err := json.Marshal(report.GetNetworkDevicesMetadata())
+	if err != nil {
+		setJSONError(w, log.Errorf("Unable to render the network devices metadata payloads: %s", err), 500)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(payload)
+}
+
+func setJSONError(w http.ResponseWriter, err error, errorCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	http.Error(w, string(body), errorCode)
+}
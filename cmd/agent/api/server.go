@@ -27,6 +27,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/cmd/agent/api/agent"
 	"github.com/DataDog/datadog-agent/cmd/agent/api/check"
+	"github.com/DataDog/datadog-agent/cmd/agent/api/metadata"
 	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	remoteconfig "github.com/DataDog/datadog-agent/pkg/config/remote/service"
@@ -119,12 +120,15 @@ func StartServer(configService *remoteconfig.Service) error {
 	// create the REST HTTP router
 	agentMux := gorilla.NewRouter()
 	checkMux := gorilla.NewRouter()
+	metadataMux := gorilla.NewRouter()
 	// Validate token for every request
 	agentMux.Use(validateToken)
 	checkMux.Use(validateToken)
+	metadataMux.Use(validateToken)
 
 	mux.Handle("/agent/", http.StripPrefix("/agent", agent.SetupHandlers(agentMux)))
 	mux.Handle("/check/", http.StripPrefix("/check", check.SetupHandlers(checkMux)))
+	mux.Handle("/metadata/", http.StripPrefix("/metadata", metadata.SetupHandlers(metadataMux)))
 	mux.Handle("/", gwmux)
 
 	// apply server_timeout to all handlers in the mux (with a few exceptions
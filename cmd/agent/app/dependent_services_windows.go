@@ -35,7 +35,7 @@ var subservices = []Servicedef{
 	},
 	{
 		name:        "process",
-		configKeys:  []string{"process_config.enabled", "network_config.enabled", "system_probe_config.enabled"},
+		configKeys:  []string{"process_config.enabled", "process_config.process_collection.enabled", "process_config.container_collection.enabled", "network_config.enabled", "system_probe_config.enabled"},
 		serviceName: "datadog-process-agent",
 		serviceInit: processInit,
 	},
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common/scaffold"
+)
+
+var (
+	newCheckRepoRoot string
+	newCheckForce    bool
+)
+
+func init() {
+	AgentCmd.AddCommand(devCmd)
+	devCmd.AddCommand(newCheckCmd)
+
+	newCheckCmd.Flags().StringVarP(&newCheckRepoRoot, "repo", "r", ".", "root of the datadog-agent repository where the check is generated")
+	newCheckCmd.Flags().BoolVarP(&newCheckForce, "force", "f", false, "overwrite the existing files")
+}
+
+var devCmd = &cobra.Command{
+	Use:   "dev",
+	Short: "Helpers for the development of the Agent",
+	Long:  ``,
+}
+
+var newCheckCmd = &cobra.Command{
+	Use:   "new-check <name>",
+	Short: "Generate the skeleton of a new Go core check",
+	Long: `Generate the skeleton of a new Go core check in the datadog-agent repository: the check
+package, with its configuration, Configure and Run methods and factory registration,
+its tests based on the mock sender, and an example configuration file.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagNoColor {
+			color.NoColor = true
+		}
+
+		data, err := scaffold.NewCheckData(args[0])
+		if err != nil {
+			return err
+		}
+
+		paths, err := scaffold.Generate(newCheckRepoRoot, data, newCheckForce)
+		for _, path := range paths {
+			fmt.Printf("%s %s\n", color.GreenString("Created"), path)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println()
+		fmt.Printf("To register the check, import its package in cmd/agent/app/run.go:\n\n")
+		fmt.Printf("\t_ \"%s\"\n\n", data.ImportPath())
+		fmt.Printf("Then run its tests with `go test ./pkg/collector/corechecks/%s/`\n", data.Package)
+		return nil
+	},
+}
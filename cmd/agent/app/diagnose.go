@@ -6,17 +6,31 @@
 package app
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/DataDog/datadog-agent/cmd/agent/api/metadata"
 	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/diagnose"
 
+	// register the connectivity diagnosis
+	_ "github.com/DataDog/datadog-agent/pkg/diagnose/connectivity"
+
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var diagnoseJSON bool
+
 func init() {
+	diagnoseDatadogConnectivityCommand.Flags().BoolVarP(&diagnoseJSON, "json", "", false, "print the results as JSON")
+	diagnoseCommand.AddCommand(diagnoseDatadogConnectivityCommand)
+	diagnoseCommand.AddCommand(diagnoseShowMetadataCommand)
 	AgentCmd.AddCommand(diagnoseCommand)
 }
 
@@ -27,7 +41,50 @@ var diagnoseCommand = &cobra.Command{
 	RunE:  doDiagnose,
 }
 
+var diagnoseDatadogConnectivityCommand = &cobra.Command{
+	Use:   "datadog-connectivity",
+	Short: "Check that the agent can run and reach Datadog from its environment",
+	Long: `Runs every diagnosis and exits with a non-zero status when one of them fails,
+so that it can be used as an init container to gate the rollout of the agent.`,
+	RunE: doDiagnoseDatadogConnectivity,
+}
+
+var diagnoseShowMetadataCommand = &cobra.Command{
+	Use:   "show-metadata <kind>",
+	Short: "Print the metadata payload of a running agent, without sending it",
+	Long: fmt.Sprintf(`Prints the payload that the running agent would send for a kind of metadata.
+Available kinds: %s.
+The network devices metadata are the last payloads built by the SNMP checks.`, strings.Join(metadata.Kinds, ", ")),
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: metadata.Kinds,
+	RunE:      doDiagnoseShowMetadata,
+}
+
 func doDiagnose(cmd *cobra.Command, args []string) error {
+	if err := setupDiagnose(""); err != nil {
+		return err
+	}
+
+	return diagnose.RunAll(color.Output)
+}
+
+func doDiagnoseDatadogConnectivity(cmd *cobra.Command, args []string) error {
+	if !diagnoseJSON {
+		if err := setupDiagnose(""); err != nil {
+			return err
+		}
+		return diagnose.RunAllAndCheck(color.Output)
+	}
+
+	// the logs would corrupt the JSON output
+	if err := setupDiagnose("off"); err != nil {
+		return err
+	}
+	return diagnose.RunAllJSON(os.Stdout)
+}
+
+// setupDiagnose loads the configuration and sets up the logger, `logLevel` overrides the configured log level when set
+func setupDiagnose(logLevel string) error {
 	// Global config setup
 	err := common.SetupConfig(confFilePath)
 	if err != nil {
@@ -38,9 +95,13 @@ func doDiagnose(cmd *cobra.Command, args []string) error {
 		color.NoColor = true
 	}
 
+	if logLevel == "" {
+		logLevel = config.Datadog.GetString("log_level")
+	}
+
 	err = config.SetupLogger(
 		loggerName,
-		config.Datadog.GetString("log_level"),
+		logLevel,
 		common.DefaultLogFile,
 		config.GetSyslogURI(),
 		config.Datadog.GetBool("syslog_rfc"),
@@ -51,5 +112,38 @@ func doDiagnose(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("Error while setting up logging, exiting: %v", err)
 	}
 
-	return diagnose.RunAll(color.Output)
+	return nil
+}
+
+func doDiagnoseShowMetadata(cmd *cobra.Command, args []string) error {
+	// the logs would corrupt the JSON output
+	if err := setupDiagnose("off"); err != nil {
+		return err
+	}
+
+	c := util.GetClient(false) // FIX: get certificates right then make this true
+	if err := util.SetAuthToken(); err != nil {
+		return err
+	}
+	ipcAddress, err := config.GetIPCAddress()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("https://%v:%v/metadata/v1/%s", ipcAddress, config.Datadog.GetInt("cmd_port"), args[0])
+	r, err := util.DoGet(c, url)
+	if err != nil {
+		if r != nil && string(r) != "" {
+			return fmt.Errorf("the agent ran into an error while rendering the metadata payload: %s", string(r))
+		}
+		return fmt.Errorf("failed to query the agent (running?): %s", err)
+	}
+
+	var payload bytes.Buffer
+	if err := json.Indent(&payload, r, "", "  "); err != nil {
+		return err
+	}
+	payload.WriteByte('\n')
+	_, err = payload.WriteTo(os.Stdout)
+	return err
 }
@@ -23,7 +23,8 @@ import (
 )
 
 var (
-	dsdStatsFilePath string
+	dsdStatsFilePath   string
+	dsdStatsUDSClients bool
 )
 
 func init() {
@@ -31,6 +32,7 @@ func init() {
 	dogstatsdStatsCmd.Flags().BoolVarP(&jsonStatus, "json", "j", false, "print out raw json")
 	dogstatsdStatsCmd.Flags().BoolVarP(&prettyPrintJSON, "pretty-json", "p", false, "pretty print JSON")
 	dogstatsdStatsCmd.Flags().StringVarP(&dsdStatsFilePath, "file", "o", "", "Output the dogstatsd-stats command to a file")
+	dogstatsdStatsCmd.Flags().BoolVarP(&dsdStatsUDSClients, "uds-clients", "u", false, "print the traffic of each Unix Socket client (PID and container) instead of the metrics")
 }
 
 var dogstatsdStatsCmd = &cobra.Command{
@@ -68,6 +70,11 @@ func requestDogstatsdStats() error {
 		return err
 	}
 	urlstr := fmt.Sprintf("https://%v:%v/agent/dogstatsd-stats", ipcAddress, config.Datadog.GetInt("cmd_port"))
+	format := dogstatsd.FormatDebugStats
+	if dsdStatsUDSClients {
+		urlstr += "/uds-clients"
+		format = dogstatsd.FormatUDSClientStats
+	}
 
 	// Set session token
 	e = util.SetAuthToken()
@@ -102,7 +109,7 @@ func requestDogstatsdStats() error {
 	} else if jsonStatus {
 		s = string(r)
 	} else {
-		s, e = dogstatsd.FormatDebugStats(r)
+		s, e = format(r)
 		if e != nil {
 			fmt.Printf("Could not format the statistics, the data must be inconsistent. You may want to try the JSON output. Contact the support if you continue having issues.\n")
 			return nil
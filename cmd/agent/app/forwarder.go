@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder"
+)
+
+var (
+	inspectDropIndexes []int
+	inspectDropFile    bool
+)
+
+func init() {
+	AgentCmd.AddCommand(forwarderCmd)
+	forwarderCmd.AddCommand(forwarderInspectCmd)
+
+	forwarderInspectCmd.Flags().IntSliceVar(&inspectDropIndexes, "drop", nil, "indexes of the transactions to remove from the file")
+	forwarderInspectCmd.Flags().BoolVar(&inspectDropFile, "drop-all", false, "remove the file with all its transactions")
+}
+
+var forwarderCmd = &cobra.Command{
+	Use:   "forwarder",
+	Short: "Forwarder related commands",
+	Long:  ``,
+}
+
+var forwarderInspectCmd = &cobra.Command{
+	Use:   "inspect [file]",
+	Short: "List the transactions persisted on disk by the forwarder",
+	Long: `List the .retry files where the forwarder persists the transactions to retry, or the
+transactions of a file with their age, payload type and size. The transactions that can't
+be sent, or the whole file when it can't be read, can be removed with --drop or --drop-all
+once the agent is stopped, as the running agent holds the files.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if flagNoColor {
+			color.NoColor = true
+		}
+
+		err := common.SetupConfigWithoutSecrets(confFilePath, "")
+		if err != nil {
+			return fmt.Errorf("unable to set up global agent configuration: %v", err)
+		}
+
+		err = config.SetupLogger(loggerName, config.GetEnvDefault("DD_LOG_LEVEL", "off"), "", "", false, true, false)
+		if err != nil {
+			fmt.Printf("Cannot setup logger, exiting: %v\n", err)
+			return err
+		}
+
+		if len(args) == 0 {
+			if len(inspectDropIndexes) > 0 || inspectDropFile {
+				return fmt.Errorf("a file is required to drop transactions")
+			}
+			return listRetryFiles()
+		}
+
+		filePath := args[0]
+		if (len(inspectDropIndexes) > 0 || inspectDropFile) && isAgentRunning() {
+			return fmt.Errorf("the agent is running and holds the files of the transactions to retry, stop it before dropping transactions")
+		}
+
+		switch {
+		case inspectDropFile:
+			if err := forwarder.RemoveRetryFile(filePath); err != nil {
+				return err
+			}
+			fmt.Printf("%s %s\n", color.GreenString("Removed"), filePath)
+			return nil
+		case len(inspectDropIndexes) > 0:
+			if err := forwarder.DropRetryTransactions(filePath, inspectDropIndexes); err != nil {
+				return err
+			}
+			fmt.Printf("%s %d transaction(s) from %s\n", color.GreenString("Dropped"), len(inspectDropIndexes), filePath)
+			if _, err := os.Stat(filePath); os.IsNotExist(err) {
+				return nil
+			}
+		}
+
+		return inspectRetryFile(filePath)
+	},
+}
+
+// isAgentRunning returns whether the agent answers on its IPC API
+func isAgentRunning() bool {
+	if err := util.SetAuthToken(); err != nil {
+		// the agent creates the token when it starts
+		return false
+	}
+	ipcAddress, err := config.GetIPCAddress()
+	if err != nil {
+		return false
+	}
+
+	c := util.GetClient(false) // FIX: get certificates right then make this true
+	urlstr := fmt.Sprintf("https://%v:%v/agent/version", ipcAddress, config.Datadog.GetInt("cmd_port"))
+	_, err = util.DoGet(c, urlstr)
+	return err == nil
+}
+
+func listRetryFiles() error {
+	files, err := forwarder.ListRetryFiles()
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		fmt.Println("No transaction persisted on disk")
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FILE\tDOMAIN\tAGE\tSIZE\tVERSION\tTRANSACTIONS")
+	for _, f := range files {
+		transactions := fmt.Sprintf("%d", len(f.Transactions))
+		if f.Error != "" {
+			transactions = color.RedString("unreadable: %s", f.Error)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%d\t%s\n", f.Path, f.Domain, now.Sub(f.ModTime).Round(time.Second), f.Size, f.Version, transactions)
+	}
+	return w.Flush()
+}
+
+func inspectRetryFile(filePath string) error {
+	f, err := forwarder.InspectRetryFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("File: %s\nFormat version: %d\nSize: %d bytes\n", f.Path, f.Version, f.Size)
+	if f.Error != "" {
+		fmt.Printf("%s %s\nThe file can be removed with --drop-all\n", color.RedString("Unreadable:"), f.Error)
+		return nil
+	}
+	fmt.Println()
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tAGE\tENDPOINT\tPAYLOAD SIZE\tERRORS\tPRIORITY\tRETRYABLE")
+	for _, tr := range f.Transactions {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%s\t%t\n", tr.Index, now.Sub(tr.CreatedAt).Round(time.Second), tr.Endpoint, tr.PayloadSize, tr.ErrorCount, tr.Priority, tr.Retryable)
+	}
+	return w.Flush()
+}
@@ -63,6 +63,9 @@ var (
 	pythonMinorVersion  string
 	reqAgentReleasePath string
 	constraintsPath     string
+	bundleDir           string
+	bundleKeyPath       string
+	freezeManifestPath  string
 )
 
 func init() {
@@ -86,6 +89,15 @@ func init() {
 	installCmd.Flags().BoolVarP(
 		&thirdParty, "third-party", "t", false, "install a community or vendor-contributed integration",
 	)
+	installCmd.Flags().StringVarP(
+		&bundleDir, "bundle", "b", "", "install from the wheels of a local offline bundle directory instead of downloading them, all the packages of the bundle are installed when no package is specified",
+	)
+	installCmd.Flags().StringVarP(
+		&bundleKeyPath, "bundle-key", "k", "", "path to the base64 encoded ed25519 public key verifying the signature of the bundle manifest",
+	)
+	freezeCmd.Flags().StringVarP(
+		&freezeManifestPath, "manifest", "m", "", "also write the installed packages to a JSON manifest file, to build an offline bundle on a mirror",
+	)
 }
 
 var integrationCmd = &cobra.Command{
@@ -100,7 +112,12 @@ var installCmd = &cobra.Command{
 	Long: `Install Datadog integration core/extra packages
 You must specify a version of the package to install using the syntax: <package>==<version>, with
  - <package> of the form datadog-<integration-name>
- - <version> of the form x.y.z`,
+ - <version> of the form x.y.z
+
+With --bundle, the wheels are installed from a local offline bundle directory instead of being
+downloaded. The bundle holds the wheels, a manifest.json file listing them with their SHA-256
+checksum and a manifest.json.sig file with the base64 encoded ed25519 signature of the manifest,
+verified with the public key set with --bundle-key.`,
 	RunE: install,
 }
 
@@ -350,10 +367,6 @@ func install(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	if err := validateArgs(args, localWheel); err != nil {
-		return err
-	}
-
 	pipArgs := []string{
 		"install",
 		"--constraint", constraintsPath,
@@ -366,6 +379,19 @@ func install(cmd *cobra.Command, args []string) error {
 		"--no-deps",
 	}
 
+	if bundleDir != "" {
+		if localWheel {
+			return fmt.Errorf("--bundle and --local-wheel cannot be used together")
+		}
+		if len(args) == 0 {
+			return installBundle(nil, pipArgs)
+		}
+	}
+
+	if err := validateArgs(args, localWheel); err != nil {
+		return err
+	}
+
 	if localWheel {
 		// Specific case when installing from locally available wheel
 		// No compatibility verifications are performed, just install the wheel (with --no-deps still)
@@ -418,6 +444,68 @@ func install(cmd *cobra.Command, args []string) error {
 	if err != nil || versionToInstall == nil {
 		return fmt.Errorf("unable to get version of %s to install: %v", integration, err)
 	}
+
+	if bundleDir != "" {
+		return installBundle([]bundlePackage{{Name: integration, Version: strings.TrimSpace(intVer[1])}}, pipArgs)
+	}
+
+	rootLayoutType := "core"
+	if thirdParty {
+		rootLayoutType = "extras"
+	}
+
+	return installWheel(integration, versionToInstall, pipArgs, func() (string, error) {
+		// Download the wheel
+		wheelPath, err := downloadWheel(integration, semverToPEP440(versionToInstall), rootLayoutType)
+		if err != nil {
+			return "", fmt.Errorf("error when downloading the wheel for %s %s: %v", integration, versionToInstall, err)
+		}
+		return wheelPath, nil
+	})
+}
+
+// installBundle installs packages from the bundle set with --bundle, all of them if packages is nil
+func installBundle(packages []bundlePackage, pipArgs []string) error {
+	manifest, err := loadBundleManifest(bundleDir, bundleKeyPath)
+	if err != nil {
+		return err
+	}
+	if packages == nil {
+		packages = manifest.Packages
+	}
+
+	for _, pkg := range packages {
+		integration := normalizePackageName(strings.TrimSpace(pkg.Name))
+		if integration == "datadog-checks-base" {
+			fmt.Printf("Skipping %s, this command does not allow installing it\n", integration)
+			continue
+		}
+		versionToInstall, err := PEP440ToSemver(pkg.Version)
+		if err != nil {
+			return fmt.Errorf("unable to get version of %s to install: %v", integration, err)
+		}
+		bundled, err := manifest.find(integration, pkg.Version)
+		if err != nil {
+			return err
+		}
+
+		err = installWheel(integration, versionToInstall, pipArgs, func() (string, error) {
+			wheelPath, err := bundled.wheelPath(bundleDir)
+			if err != nil {
+				return "", fmt.Errorf("unable to verify the wheel for %s %s: %v", integration, versionToInstall, err)
+			}
+			return wheelPath, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installWheel installs a version of an integration with pip once its compatibility with the agent is verified,
+// the wheel is retrieved by getWheel
+func installWheel(integration string, versionToInstall *semver.Version, pipArgs []string, getWheel func() (string, error)) error {
 	currentVersion, found, err := installedVersion(integration)
 	if err != nil {
 		return fmt.Errorf("could not get current version of %s: %v", integration, err)
@@ -438,15 +526,9 @@ func install(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	rootLayoutType := "core"
-	if thirdParty {
-		rootLayoutType = "extras"
-	}
-
-	// Download the wheel
-	wheelPath, err := downloadWheel(integration, semverToPEP440(versionToInstall), rootLayoutType)
+	wheelPath, err := getWheel()
 	if err != nil {
-		return fmt.Errorf("error when downloading the wheel for %s %s: %v", integration, versionToInstall, err)
+		return err
 	}
 
 	// Verify datadog-checks-base is compatible with the requirements
@@ -870,9 +952,17 @@ func list(cmd *cobra.Command, args []string) error {
 	pythonLibs := strings.Split(pipStdo.String(), "\n")
 
 	// The agent integration freeze command should only show datadog packages and nothing else
+	var datadogLibs []string
 	for i := range pythonLibs {
 		if strings.HasPrefix(pythonLibs[i], "datadog-") {
 			fmt.Println(pythonLibs[i])
+			datadogLibs = append(datadogLibs, pythonLibs[i])
+		}
+	}
+
+	if freezeManifestPath != "" {
+		if err := writeFreezeManifest(freezeManifestPath, datadogLibs); err != nil {
+			return fmt.Errorf("unable to write the manifest %s: %v", freezeManifestPath, err)
 		}
 	}
 	return nil
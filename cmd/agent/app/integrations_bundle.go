@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build python
+
+package app
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	bundleManifestFile  = "manifest.json"
+	bundleSignatureFile = "manifest.json.sig"
+)
+
+// bundleManifest lists the integration packages of an offline bundle. `agent integration freeze --manifest`
+// writes it with the installed packages, the mirror then adds the wheel and its checksum to each package
+// and signs the manifest.
+type bundleManifest struct {
+	PythonVersion string          `json:"python_version"`
+	Packages      []bundlePackage `json:"packages"`
+}
+
+type bundlePackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// Wheel is the name of the wheel file, relative to the bundle directory
+	Wheel string `json:"wheel,omitempty"`
+	// SHA256 is the hex encoded checksum of the wheel file
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// newFreezeManifest returns the manifest of the installed packages, listed in the `<package>==<version>` format
+func newFreezeManifest(packages []string) (*bundleManifest, error) {
+	manifest := &bundleManifest{
+		PythonVersion: pythonMajorVersion,
+		Packages:      []bundlePackage{},
+	}
+	for _, pkg := range packages {
+		nameVersion := strings.Split(pkg, "==")
+		if len(nameVersion) != 2 {
+			return nil, fmt.Errorf("unexpected package %s, expected <package>==<version>", pkg)
+		}
+		manifest.Packages = append(manifest.Packages, bundlePackage{
+			Name:    normalizePackageName(strings.TrimSpace(nameVersion[0])),
+			Version: strings.TrimSpace(nameVersion[1]),
+		})
+	}
+	return manifest, nil
+}
+
+// writeFreezeManifest writes the manifest of the installed packages to path
+func writeFreezeManifest(path string, packages []string) error {
+	manifest, err := newFreezeManifest(packages)
+	if err != nil {
+		return err
+	}
+
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// readBase64File reads a file containing base64 encoded data
+func readBase64File(path string) ([]byte, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+}
+
+// loadBundleManifest reads the manifest of the bundle in bundleDir and verifies its signature
+// with the base64 encoded ed25519 public key stored in keyPath
+func loadBundleManifest(bundleDir, keyPath string) (*bundleManifest, error) {
+	if keyPath == "" {
+		return nil, fmt.Errorf("the public key verifying the bundle signature must be set with --bundle-key")
+	}
+	key, err := readBase64File(keyPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the bundle public key %s: %v", keyPath, err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid bundle public key %s: expected an ed25519 key of %d bytes, got %d", keyPath, ed25519.PublicKeySize, len(key))
+	}
+
+	manifestPath := filepath.Join(bundleDir, bundleManifestFile)
+	content, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the bundle manifest: %v", err)
+	}
+	signature, err := readBase64File(filepath.Join(bundleDir, bundleSignatureFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the bundle signature: %v", err)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), content, signature) {
+		return nil, fmt.Errorf("the signature of %s is invalid", manifestPath)
+	}
+
+	var manifest bundleManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("unable to parse the bundle manifest: %v", err)
+	}
+	return &manifest, nil
+}
+
+// find returns the package of the bundle with the given name and version
+func (m *bundleManifest) find(integration, version string) (*bundlePackage, error) {
+	for i := range m.Packages {
+		pkg := &m.Packages[i]
+		if normalizePackageName(pkg.Name) == integration && pkg.Version == version {
+			return pkg, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s is not in the bundle", integration, version)
+}
+
+// wheelPath returns the path of the wheel of a package of the bundle in bundleDir, once its checksum is verified
+func (p *bundlePackage) wheelPath(bundleDir string) (string, error) {
+	if p.Wheel == "" || p.SHA256 == "" {
+		return "", fmt.Errorf("the wheel of %s %s and its checksum are missing from the bundle manifest", p.Name, p.Version)
+	}
+	// the wheels must be in the bundle directory
+	if filepath.Base(p.Wheel) != p.Wheel {
+		return "", fmt.Errorf("invalid wheel file name %s", p.Wheel)
+	}
+
+	path := filepath.Join(bundleDir, p.Wheel)
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, p.SHA256) {
+		return "", fmt.Errorf("the checksum of %s is %s, expected %s", path, sum, p.SHA256)
+	}
+	return path, nil
+}
@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build python
+
+package app
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFreezeManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freeze")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "manifest.json")
+	require.NoError(t, writeFreezeManifest(path, []string{"datadog-checks-base==20.1.0", "datadog_postgres==10.0.0"}))
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	var manifest bundleManifest
+	require.NoError(t, json.Unmarshal(content, &manifest))
+	assert.Equal(t, []bundlePackage{
+		{Name: "datadog-checks-base", Version: "20.1.0"},
+		{Name: "datadog-postgres", Version: "10.0.0"},
+	}, manifest.Packages)
+
+	assert.Error(t, writeFreezeManifest(path, []string{"datadog-postgres"}))
+}
+
+type testBundle struct {
+	dir     string
+	keyPath string
+	private ed25519.PrivateKey
+}
+
+func newTestBundle(t *testing.T, wheels map[string]string) *testBundle {
+	dir, err := ioutil.TempDir("", "bundle")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyPath := filepath.Join(dir, "bundle.pub")
+	require.NoError(t, ioutil.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(public)+"\n"), 0644))
+
+	manifest := bundleManifest{PythonVersion: "3"}
+	for name, content := range wheels {
+		wheel := name + "-10.0.0-py2.py3-none-any.whl"
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, wheel), []byte(content), 0644))
+		sum := sha256.Sum256([]byte(content))
+		manifest.Packages = append(manifest.Packages, bundlePackage{
+			Name:    name,
+			Version: "10.0.0",
+			Wheel:   wheel,
+			SHA256:  hex.EncodeToString(sum[:]),
+		})
+	}
+
+	b := &testBundle{dir: dir, keyPath: keyPath, private: private}
+	b.writeManifest(t, manifest)
+	return b
+}
+
+func (b *testBundle) writeManifest(t *testing.T, manifest bundleManifest) {
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(b.dir, bundleManifestFile), content, 0644))
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(b.private, content))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(b.dir, bundleSignatureFile), []byte(signature), 0644))
+}
+
+func TestLoadBundleManifest(t *testing.T) {
+	b := newTestBundle(t, map[string]string{"datadog-postgres": "postgres wheel"})
+
+	manifest, err := loadBundleManifest(b.dir, b.keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, "3", manifest.PythonVersion)
+	require.Len(t, manifest.Packages, 1)
+
+	pkg, err := manifest.find("datadog-postgres", "10.0.0")
+	require.NoError(t, err)
+	path, err := pkg.wheelPath(b.dir)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(b.dir, "datadog-postgres-10.0.0-py2.py3-none-any.whl"), path)
+
+	_, err = manifest.find("datadog-postgres", "11.0.0")
+	assert.Error(t, err)
+
+	_, err = loadBundleManifest(b.dir, "")
+	assert.Error(t, err)
+}
+
+func TestLoadBundleManifestInvalidSignature(t *testing.T) {
+	b := newTestBundle(t, map[string]string{"datadog-postgres": "postgres wheel"})
+
+	content, err := ioutil.ReadFile(filepath.Join(b.dir, bundleManifestFile))
+	require.NoError(t, err)
+	content = append(content, ' ')
+	require.NoError(t, ioutil.WriteFile(filepath.Join(b.dir, bundleManifestFile), content, 0644))
+
+	_, err = loadBundleManifest(b.dir, b.keyPath)
+	assert.Error(t, err)
+}
+
+func TestBundleWheelPath(t *testing.T) {
+	b := newTestBundle(t, map[string]string{"datadog-postgres": "postgres wheel"})
+	manifest, err := loadBundleManifest(b.dir, b.keyPath)
+	require.NoError(t, err)
+	pkg := manifest.Packages[0]
+
+	// the wheel was tampered with
+	require.NoError(t, ioutil.WriteFile(filepath.Join(b.dir, pkg.Wheel), []byte("malicious wheel"), 0644))
+	_, err = pkg.wheelPath(b.dir)
+	assert.Error(t, err)
+
+	// the wheel is outside of the bundle
+	outside := pkg
+	outside.Wheel = "../" + pkg.Wheel
+	_, err = outside.wheelPath(b.dir)
+	assert.Error(t, err)
+
+	// the manifest was produced by freeze and not completed by the mirror
+	frozen := bundlePackage{Name: pkg.Name, Version: pkg.Version}
+	_, err = frozen.wheelPath(b.dir)
+	assert.Error(t, err)
+}
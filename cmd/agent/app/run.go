@@ -8,11 +8,13 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
 	"runtime"
 	"syscall"
+	"time"
 
 	_ "expvar" // Blank import used because this isn't directly used in this file
 
@@ -46,7 +48,10 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/fips"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/startup"
 	"github.com/DataDog/datadog-agent/pkg/version"
 	"github.com/spf13/cobra"
 	"gopkg.in/DataDog/dd-trace-go.v1/profiler"
@@ -87,6 +92,8 @@ var (
 	orchestratorForwarder  *forwarder.DefaultForwarder
 	eventPlatformForwarder epforwarder.EventPlatformForwarder
 	configService          *remoteconfig.Service
+	watchdog               *health.Watchdog
+	openMetricsServer      *http.Server
 
 	runCmd = &cobra.Command{
 		Use:   "run",
@@ -248,6 +255,10 @@ func StartAgent() error {
 
 	log.Infof("Starting Datadog Agent v%v", version.AgentVersion)
 
+	if err := fips.Verify(); err != nil {
+		return fmt.Errorf("invalid FIPS configuration: %v", err)
+	}
+
 	if err := util.SetupCoreDump(); err != nil {
 		log.Warnf("Can't setup core dumps: %v, core dumps might not be available after a crash", err)
 	}
@@ -298,6 +309,33 @@ func StartAgent() error {
 		log.Debugf("Health check listening on port %d", healthPort)
 	}
 
+	// Setup the OpenMetrics telemetry port, scraped by third-party Prometheus servers
+	if config.Datadog.GetBool("telemetry.openmetrics.enabled") {
+		openMetricsServer, err = telemetry.StartOpenMetricsServer(telemetry.OpenMetricsServerConfig{
+			Addr:         net.JoinHostPort(config.Datadog.GetString("telemetry.openmetrics.bind_host"), config.Datadog.GetString("telemetry.openmetrics.port")),
+			CertFile:     config.Datadog.GetString("telemetry.openmetrics.tls_cert_file"),
+			KeyFile:      config.Datadog.GetString("telemetry.openmetrics.tls_key_file"),
+			ClientCAFile: config.Datadog.GetString("telemetry.openmetrics.client_ca_file"),
+		})
+		if err != nil {
+			return log.Errorf("Error starting the OpenMetrics telemetry server, exiting: %v", err)
+		}
+		log.Infof("OpenMetrics telemetry listening on %s", openMetricsServer.Addr)
+	}
+
+	// Setup the watchdog restarting the wedged components
+	if config.Datadog.GetBool("watchdog.enabled") {
+		watchdog = health.NewWatchdog(
+			config.Datadog.GetDuration("watchdog.wedged_timeout")*time.Second,
+			config.Datadog.GetInt("watchdog.max_restarts"),
+			config.Datadog.GetString("run_path"),
+			func() {
+				go func() { signals.ErrorStopper <- true }()
+			},
+		)
+		watchdog.Start()
+	}
+
 	if pidfilePath != "" {
 		err = pidfile.WritePID(pidfilePath)
 		if err != nil {
@@ -360,110 +398,162 @@ func StartAgent() error {
 		log.Errorf("Error while starting GUI: %v", err)
 	}
 
-	// setup the forwarder
-	keysPerDomain, err := config.GetMultipleEndpoints()
-	if err != nil {
-		log.Error("Misconfiguration of agent endpoints: ", err)
-	}
+	// Detect Cloud Provider
+	go util.DetectCloudProvider(context.Background())
+
+	// Append version and timestamp to version history log file if this Agent is different than the last run version
+	util.LogVersionHistory()
+
+	// The components are started concurrently, each one once the components it depends on are started.
+	// Only the errors the agent can't run without are returned by the start functions.
+	var (
+		s   *serializer.Serializer
+		agg *aggregator.BufferedAggregator
+	)
+	components := startup.NewGraph()
+
+	components.Add("forwarder", func() error {
+		keysPerDomain, err := config.GetMultipleEndpoints()
+		if err != nil {
+			log.Error("Misconfiguration of agent endpoints: ", err)
+		}
 
-	// Enable core agent specific features like persistence-to-disk
-	options := forwarder.NewOptions(keysPerDomain)
-	options.EnabledFeatures = forwarder.SetFeature(options.EnabledFeatures, forwarder.CoreFeatures)
+		// Enable core agent specific features like persistence-to-disk
+		options := forwarder.NewOptions(keysPerDomain)
+		options.EnabledFeatures = forwarder.SetFeature(options.EnabledFeatures, forwarder.CoreFeatures)
 
-	common.Forwarder = forwarder.NewDefaultForwarder(options)
-	log.Debugf("Starting forwarder")
-	common.Forwarder.Start() //nolint:errcheck
-	log.Debugf("Forwarder started")
+		common.Forwarder = forwarder.NewDefaultForwarder(options)
+		log.Debugf("Starting forwarder")
+		common.Forwarder.Start() //nolint:errcheck
+		log.Debugf("Forwarder started")
+		return nil
+	})
 
 	// setup the orchestrator forwarder (only on cluster check runners)
-	orchestratorForwarder = orchcfg.NewOrchestratorForwarder()
-	if orchestratorForwarder != nil {
-		orchestratorForwarder.Start() //nolint:errcheck
-	}
+	components.Add("orchestrator_forwarder", func() error {
+		orchestratorForwarder = orchcfg.NewOrchestratorForwarder()
+		if orchestratorForwarder != nil {
+			orchestratorForwarder.Start() //nolint:errcheck
+		}
+		return nil
+	})
 
-	eventPlatformForwarder = epforwarder.NewEventPlatformForwarder()
-	eventPlatformForwarder.Start()
+	components.Add("event_platform_forwarder", func() error {
+		eventPlatformForwarder = epforwarder.NewEventPlatformForwarder()
+		eventPlatformForwarder.Start()
+		return nil
+	})
 
 	// setup the aggregator
-	s := serializer.NewSerializer(common.Forwarder, orchestratorForwarder)
-	agg := aggregator.InitAggregator(s, eventPlatformForwarder, hostname)
-	agg.AddAgentStartupTelemetry(version.AgentVersion)
+	components.Add("aggregator", func() error {
+		s = serializer.NewSerializer(common.Forwarder, orchestratorForwarder)
+		agg = aggregator.InitAggregator(s, eventPlatformForwarder, hostname)
+		agg.AddAgentStartupTelemetry(version.AgentVersion)
+		return nil
+	}, "forwarder", "orchestrator_forwarder", "event_platform_forwarder")
 
 	// start dogstatsd
-	if config.Datadog.GetBool("use_dogstatsd") {
-		var err error
-		common.DSD, err = dogstatsd.NewServer(agg, nil)
-		if err != nil {
-			log.Errorf("Could not start dogstatsd: %s", err)
+	components.Add("dogstatsd", func() error {
+		if config.Datadog.GetBool("use_dogstatsd") {
+			var err error
+			common.DSD, err = dogstatsd.NewServer(agg, nil)
+			if err != nil {
+				log.Errorf("Could not start dogstatsd: %s", err)
+			}
 		}
-	}
-	log.Debugf("statsd started")
+		log.Debugf("statsd started")
+		return nil
+	}, "aggregator")
 
 	// Start OTLP intake
-	if otlp.IsEnabled(config.Datadog) {
-		var err error
-		common.OTLP, err = otlp.BuildAndStart(common.MainCtx, config.Datadog, s)
-		if err != nil {
-			log.Errorf("Could not start OTLP: %s", err)
+	components.Add("otlp", func() error {
+		if otlp.IsEnabled(config.Datadog) {
+			var err error
+			common.OTLP, err = otlp.BuildAndStart(common.MainCtx, config.Datadog, s)
+			if err != nil {
+				log.Errorf("Could not start OTLP: %s", err)
+			}
 		}
-	}
-	log.Debug("OTLP pipeline started")
+		log.Debug("OTLP pipeline started")
+		return nil
+	}, "aggregator")
 
 	// Start SNMP trap server
-	if traps.IsEnabled() {
-		if config.Datadog.GetBool("logs_enabled") {
-			err = traps.StartServer()
-			if err != nil {
-				log.Errorf("Failed to start snmp-traps server: %s", err)
+	components.Add("snmp_traps", func() error {
+		if traps.IsEnabled() {
+			if config.Datadog.GetBool("logs_enabled") {
+				if err := traps.StartServer(); err != nil {
+					log.Errorf("Failed to start snmp-traps server: %s", err)
+				}
+			} else {
+				log.Warn(
+					"snmp-traps server did not start, as log collection is disabled. " +
+						"Please enable log collection to collect and forward traps.",
+				)
+			}
+		}
+		return nil
+	})
+
+	// start logs-agent, it forwards the traps received by the snmp-traps server
+	components.Add("logs", func() error {
+		if config.Datadog.GetBool("logs_enabled") || config.Datadog.GetBool("log_enabled") {
+			if config.Datadog.GetBool("log_enabled") {
+				log.Warn(`"log_enabled" is deprecated, use "logs_enabled" instead`)
+			}
+			if err := logs.Start(func() *autodiscovery.AutoConfig { return common.AC }); err != nil {
+				log.Error("Could not start logs-agent: ", err)
 			}
 		} else {
-			log.Warn(
-				"snmp-traps server did not start, as log collection is disabled. " +
-					"Please enable log collection to collect and forward traps.",
-			)
+			log.Info("logs-agent disabled")
 		}
-	}
+		return nil
+	}, "snmp_traps")
 
-	// start logs-agent
-	if config.Datadog.GetBool("logs_enabled") || config.Datadog.GetBool("log_enabled") {
-		if config.Datadog.GetBool("log_enabled") {
-			log.Warn(`"log_enabled" is deprecated, use "logs_enabled" instead`)
-		}
-		if err := logs.Start(func() *autodiscovery.AutoConfig { return common.AC }); err != nil {
-			log.Error("Could not start logs-agent: ", err)
+	components.Add("system_probe_config", func() error {
+		if err := common.SetupSystemProbeConfig(sysProbeConfFilePath); err != nil {
+			log.Infof("System probe config not found, disabling pulling system probe info in the status page: %v", err)
 		}
-	} else {
-		log.Info("logs-agent disabled")
-	}
+		return nil
+	})
 
-	if err = common.SetupSystemProbeConfig(sysProbeConfFilePath); err != nil {
-		log.Infof("System probe config not found, disabling pulling system probe info in the status page: %v", err)
-	}
+	// create and setup the Autoconfig instance, the logs-agent is started first to get the logs configs
+	components.Add("autoconfig", func() error {
+		common.LoadComponents(config.Datadog.GetString("confd_path"))
+		// start the autoconfig, this will immediately run any configured check
+		common.StartAutoConfig()
+		return nil
+	}, "aggregator", "logs", "system_probe_config")
 
-	// Detect Cloud Provider
-	go util.DetectCloudProvider(context.Background())
+	// setup the metadata collector
+	components.Add("metadata", func() error {
+		common.MetadataScheduler = metadata.NewScheduler(s)
+		if err := metadata.SetupMetadataCollection(common.MetadataScheduler, metadata.AllDefaultCollectors); err != nil {
+			return err
+		}
 
-	// Append version and timestamp to version history log file if this Agent is different than the last run version
-	util.LogVersionHistory()
+		if config.Datadog.GetBool("inventories_enabled") {
+			if err := metadata.SetupInventories(common.MetadataScheduler, common.AC, common.Coll); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, "aggregator", "autoconfig")
 
-	// create and setup the Autoconfig instance
-	common.LoadComponents(config.Datadog.GetString("confd_path"))
-	// start the autoconfig, this will immediately run any configured check
-	common.StartAutoConfig()
+	if err := components.Start(); err != nil {
+		return err
+	}
+	log.Infof("Agent components started: %s", components.DurationsSummary())
 
 	// check for common misconfigurations and report them to log
 	misconfig.ToLog()
 
-	// setup the metadata collector
-	common.MetadataScheduler = metadata.NewScheduler(s)
-	if err := metadata.SetupMetadataCollection(common.MetadataScheduler, metadata.AllDefaultCollectors); err != nil {
-		return err
-	}
-
-	if config.Datadog.GetBool("inventories_enabled") {
-		if err := metadata.SetupInventories(common.MetadataScheduler, common.AC, common.Coll); err != nil {
-			return err
-		}
+	// send the host tags again when the node labels or taints they are extracted from change
+	if refreshInterval := config.Datadog.GetInt("kubernetes_node_tags_refresh_interval"); refreshInterval > 0 &&
+		config.IsFeaturePresent(config.Kubernetes) && common.MetadataScheduler.IsScheduled("host") {
+		go hostinfo.RefreshTagsOnChange(common.MainCtx, time.Duration(refreshInterval)*time.Second, func() {
+			common.MetadataScheduler.TriggerAndResetCollectorTimer("host", 0)
+		})
 	}
 
 	// start dependent services
@@ -483,6 +573,14 @@ func StopAgent() {
 		log.Warnf("Some components were unhealthy: %v", health.Unhealthy)
 	}
 
+	// the components being stopped mustn't be taken for wedged ones
+	if watchdog != nil {
+		watchdog.Stop()
+	}
+	if openMetricsServer != nil {
+		openMetricsServer.Close()
+	}
+
 	// gracefully shut down any component
 	common.MainCtxCancel()
 
@@ -502,10 +600,7 @@ func StopAgent() {
 	api.StopServer()
 	clcrunnerapi.StopCLCRunnerServer()
 	jmx.StopJmxfetch()
-	aggregator.StopDefaultAggregator()
-	if common.Forwarder != nil {
-		common.Forwarder.Stop()
-	}
+	stopAggregatorAndForwarder()
 	if orchestratorForwarder != nil {
 		orchestratorForwarder.Stop()
 	}
@@ -520,3 +615,33 @@ func StopAgent() {
 	log.Info("See ya!")
 	log.Flush()
 }
+
+// stopAggregatorAndForwarder flushes the aggregator a last time and drains the forwarder
+// before the shutdown deadline, so that the data of the last interval isn't lost
+func stopAggregatorAndForwarder() {
+	deadlineSeconds := config.Datadog.GetInt("shutdown_flush_deadline")
+	if deadlineSeconds <= 0 {
+		aggregator.StopDefaultAggregator()
+		if common.Forwarder != nil {
+			common.Forwarder.Stop()
+		}
+		return
+	}
+
+	deadline := time.Now().Add(time.Duration(deadlineSeconds) * time.Second)
+	aggregator.StopDefaultAggregatorWithFinalFlush(deadline)
+
+	defaultForwarder, ok := common.Forwarder.(*forwarder.DefaultForwarder)
+	if !ok {
+		if common.Forwarder != nil {
+			common.Forwarder.Stop()
+		}
+		return
+	}
+	report := defaultForwarder.StopWithDeadline(deadline)
+	if report.DroppedCount() > 0 {
+		log.Warnf("Forwarder stopped, some transactions were dropped: %s", report)
+	} else {
+		log.Infof("Forwarder stopped: %s", report)
+	}
+}
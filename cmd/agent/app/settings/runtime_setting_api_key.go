@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// apiKeyUpdater is implemented by the forwarders supporting API key rotation
+type apiKeyUpdater interface {
+	UpdateAPIKeys(domain string, apiKeys []string) error
+}
+
+// APIKeyRuntimeSetting wraps operations to rotate the API key of the main endpoint at runtime.
+// The additional endpoints using the same API key are rotated as well.
+type APIKeyRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s APIKeyRuntimeSetting) Description() string {
+	return "Rotate the API key used to send data to the main endpoint and to the additional endpoints using it without restarting the agent"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s APIKeyRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s APIKeyRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting, the API key is obfuscated
+func (s APIKeyRuntimeSetting) Get() (interface{}, error) {
+	apiKey := config.Datadog.GetString("api_key")
+	if len(apiKey) > 5 {
+		apiKey = apiKey[len(apiKey)-5:]
+	}
+	return "***************************" + apiKey, nil
+}
+
+// Set changes the value of the runtime setting
+func (s APIKeyRuntimeSetting) Set(v interface{}) error {
+	apiKey, ok := v.(string)
+	apiKey = strings.TrimSpace(apiKey)
+	if !ok || apiKey == "" {
+		return fmt.Errorf("APIKeyRuntimeSetting: the API key must be a non empty string")
+	}
+
+	fwd, ok := common.Forwarder.(apiKeyUpdater)
+	if !ok {
+		return fmt.Errorf("APIKeyRuntimeSetting: the forwarder doesn't support API key rotation")
+	}
+
+	oldAPIKey := strings.TrimSpace(config.Datadog.GetString("api_key"))
+	config.Datadog.Set("api_key", apiKey)
+
+	additionalEndpoints := config.Datadog.GetStringMapStringSlice("additional_endpoints")
+	for _, keys := range additionalEndpoints {
+		for i, key := range keys {
+			if strings.TrimSpace(key) == oldAPIKey {
+				keys[i] = apiKey
+			}
+		}
+	}
+	config.Datadog.Set("additional_endpoints", additionalEndpoints)
+
+	keysPerDomain, err := config.GetMultipleEndpoints()
+	if err != nil {
+		return fmt.Errorf("APIKeyRuntimeSetting: %v", err)
+	}
+
+	// every domain is updated, the domains of the additional endpoints may share the rotated API key
+	var errs []string
+	for domain, keys := range keysPerDomain {
+		if err := fwd.UpdateAPIKeys(domain, keys); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("APIKeyRuntimeSetting: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
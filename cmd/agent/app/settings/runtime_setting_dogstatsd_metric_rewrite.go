@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/dogstatsd"
+)
+
+// DsdMetricRewriteRuntimeSetting wraps operations to change the dogstatsd metric name rewriting rules at runtime.
+type DsdMetricRewriteRuntimeSetting string
+
+// Description returns the runtime setting's description
+func (s DsdMetricRewriteRuntimeSetting) Description() string {
+	return `Set the dogstatsd metric name rewriting rules. Possible values: a JSON object, e.g. {"strip_prefixes": ["statsd."], "lowercase": true, "deny": ["^tmp\\."], "add_prefix": ""}`
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (s DsdMetricRewriteRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (s DsdMetricRewriteRuntimeSetting) Name() string {
+	return string(s)
+}
+
+// Get returns the current value of the runtime setting
+func (s DsdMetricRewriteRuntimeSetting) Get() (interface{}, error) {
+	return common.DSD.GetMetricRewriteConfig(), nil
+}
+
+// Set changes the value of the runtime setting
+func (s DsdMetricRewriteRuntimeSetting) Set(v interface{}) error {
+	var newValue dogstatsd.MetricRewriteConfig
+
+	switch value := v.(type) {
+	case dogstatsd.MetricRewriteConfig:
+		newValue = value
+	case string:
+		if err := json.Unmarshal([]byte(value), &newValue); err != nil {
+			return fmt.Errorf("DsdMetricRewriteRuntimeSetting: invalid JSON value: %v", err)
+		}
+	default:
+		return fmt.Errorf("DsdMetricRewriteRuntimeSetting: unsupported type %T", v)
+	}
+
+	if err := common.DSD.SetMetricRewriteConfig(newValue); err != nil {
+		return fmt.Errorf("DsdMetricRewriteRuntimeSetting: %v", err)
+	}
+
+	config.Datadog.Set("dogstatsd_metric_rewrite.strip_prefixes", newValue.StripPrefixes)
+	config.Datadog.Set("dogstatsd_metric_rewrite.lowercase", newValue.Lowercase)
+	config.Datadog.Set("dogstatsd_metric_rewrite.deny", newValue.Deny)
+	config.Datadog.Set("dogstatsd_metric_rewrite.add_prefix", newValue.AddPrefix)
+	return nil
+}
@@ -73,3 +73,39 @@ func TestDogstatsdMetricsStats(t *testing.T) {
 	assert.Nil(err)
 	assert.Equal(v, true)
 }
+
+func TestDogstatsdMetricRewrite(t *testing.T) {
+	var err error
+
+	serializer := serializer.NewSerializer(common.Forwarder, nil)
+	agg := aggregator.InitAggregator(serializer, nil, "")
+	common.DSD, err = dogstatsd.NewServer(agg, nil)
+	require.Nil(t, err)
+
+	s := DsdMetricRewriteRuntimeSetting("dogstatsd_metric_rewrite")
+
+	err = s.Set(`{"strip_prefixes": ["statsd."], "lowercase": true, "deny": ["^tmp\\."]}`)
+	assert.Nil(t, err)
+	expected := dogstatsd.MetricRewriteConfig{
+		StripPrefixes: []string{"statsd."},
+		Lowercase:     true,
+		Deny:          []string{`^tmp\.`},
+	}
+	v, err := s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+
+	// invalid values keep the current rules
+	assert.NotNil(t, s.Set(`{"deny": ["["]}`))
+	assert.NotNil(t, s.Set("not json"))
+	assert.NotNil(t, s.Set(true))
+	v, err = s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, v)
+
+	err = s.Set(dogstatsd.MetricRewriteConfig{})
+	assert.Nil(t, err)
+	v, err = s.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, dogstatsd.MetricRewriteConfig{}, v)
+}
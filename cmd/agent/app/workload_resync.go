@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package app
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/common"
+	"github.com/DataDog/datadog-agent/pkg/api/util"
+	"github.com/DataDog/datadog-agent/pkg/config"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	AgentCmd.AddCommand(workloadResyncCommand)
+}
+
+var workloadResyncCommand = &cobra.Command{
+	Use:   "workload-resync <collector>",
+	Short: "Restart a workloadmeta collector of a running agent",
+	Long: `Restart a workloadmeta collector of a running agent, e.g. the containerd one when its events stream is stuck.
+The entities the collector doesn't report anymore once restarted are removed from the workload store.
+The collectors are listed, along with their health, in the agent status.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		if flagNoColor {
+			color.NoColor = true
+		}
+
+		err := common.SetupConfigWithoutSecrets(confFilePath, "")
+		if err != nil {
+			return fmt.Errorf("unable to set up global agent configuration: %v", err)
+		}
+
+		err = config.SetupLogger(loggerName, config.GetEnvDefault("DD_LOG_LEVEL", "off"), "", "", false, true, false)
+		if err != nil {
+			fmt.Printf("Cannot setup logger, exiting: %v\n", err)
+			return err
+		}
+
+		c := util.GetClient(false) // FIX: get certificates right then make this true
+
+		// Set session token
+		err = util.SetAuthToken()
+		if err != nil {
+			return err
+		}
+		ipcAddress, err := config.GetIPCAddress()
+		if err != nil {
+			return err
+		}
+
+		urlstr := fmt.Sprintf("https://%v:%v/agent/workload-resync/%s", ipcAddress, config.Datadog.GetInt("cmd_port"), args[0])
+		r, err := util.DoPost(c, urlstr, "application/json", bytes.NewBuffer([]byte{}))
+		if err != nil {
+			if r != nil && string(r) != "" {
+				fmt.Fprintf(color.Output, "The agent ran into an error while resyncing the collector: %s\n", string(r))
+			} else {
+				fmt.Fprintf(color.Output, "Failed to query the agent (running?): %s\n", err)
+			}
+			return err
+		}
+
+		fmt.Fprintf(color.Output, "Workloadmeta collector %s resynced\n", color.GreenString(args[0]))
+		return nil
+	},
+}
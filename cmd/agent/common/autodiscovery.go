@@ -6,6 +6,8 @@
 package common
 
 import (
+	"time"
+
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/scheduler"
@@ -26,7 +28,11 @@ var (
 
 func setupAutoDiscovery(confSearchPaths []string, metaScheduler *scheduler.MetaScheduler) *autodiscovery.AutoConfig {
 	ad := autodiscovery.NewAutoConfig(metaScheduler)
-	ad.AddConfigProvider(providers.NewFileConfigProvider(confSearchPaths), false, 0)
+	ad.AddConfigProvider(
+		providers.NewFileConfigProvider(confSearchPaths),
+		config.Datadog.GetBool("autoconf_config_files_poll"),
+		time.Duration(config.Datadog.GetInt("autoconf_config_files_poll_interval"))*time.Second,
+	)
 
 	// Autodiscovery cannot easily use config.RegisterOverrideFunc() due to Unmarshalling
 	extraConfigProviders, extraConfigListeners := confad.DiscoverComponentsFromConfig()
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package scaffold generates the skeleton of a new Go core check in the agent repository.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var checkNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// CheckData is the data the templates are rendered with
+type CheckData struct {
+	// Name is the name of the check, used in its configuration directory and as metric prefix
+	Name string
+	// Package is the name of the Go package of the check
+	Package string
+}
+
+// generatedFile is a file rendered from a template
+type generatedFile struct {
+	template string
+	path     string
+	isGo     bool
+}
+
+// NewCheckData validates the name of a check and returns its template data
+func NewCheckData(name string) (CheckData, error) {
+	if !checkNameRegex.MatchString(name) {
+		return CheckData{}, fmt.Errorf("invalid check name %q: it must start with a lowercase letter and only contain lowercase letters, digits and underscores", name)
+	}
+	return CheckData{
+		Name:    name,
+		Package: strings.Replace(name, "_", "", -1),
+	}, nil
+}
+
+// ImportPath returns the import path of the package of the check, which must
+// be imported by the agent for the check to be registered
+func (d CheckData) ImportPath() string {
+	return "github.com/DataDog/datadog-agent/pkg/collector/corechecks/" + d.Package
+}
+
+func (d CheckData) files() []generatedFile {
+	checkDir := filepath.Join("pkg", "collector", "corechecks", d.Package)
+	return []generatedFile{
+		{template: "check.go.tmpl", path: filepath.Join(checkDir, d.Name+".go"), isGo: true},
+		{template: "check_test.go.tmpl", path: filepath.Join(checkDir, d.Name+"_test.go"), isGo: true},
+		{template: "conf.yaml.example.tmpl", path: filepath.Join("cmd", "agent", "dist", "conf.d", d.Name+".d", "conf.yaml.example")},
+	}
+}
+
+// Generate renders the files of a new core check in the agent repository
+// rooted at repoRoot, and returns their paths. Existing files are only
+// overwritten when force is set.
+func Generate(repoRoot string, data CheckData, force bool) ([]string, error) {
+	files := data.files()
+
+	if !force {
+		for _, f := range files {
+			path := filepath.Join(repoRoot, f.path)
+			if _, err := os.Stat(path); err == nil {
+				return nil, fmt.Errorf("%s already exists, use --force to overwrite it", path)
+			}
+		}
+	}
+
+	var paths []string
+	for _, f := range files {
+		content, err := render(f, data)
+		if err != nil {
+			return paths, err
+		}
+
+		path := filepath.Join(repoRoot, f.path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return paths, err
+		}
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func render(f generatedFile, data CheckData) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, "templates/"+f.template)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("unable to render %s: %s", f.template, err)
+	}
+
+	if !f.isGo {
+		return buf.Bytes(), nil
+	}
+	content, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("unable to format %s: %s", f.path, err)
+	}
+	return content, nil
+}
@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestNewCheckData(t *testing.T) {
+	data, err := NewCheckData("my_check")
+	require.NoError(t, err)
+	assert.Equal(t, "my_check", data.Name)
+	assert.Equal(t, "mycheck", data.Package)
+	assert.Equal(t, "github.com/DataDog/datadog-agent/pkg/collector/corechecks/mycheck", data.ImportPath())
+
+	for _, name := range []string{"", "MyCheck", "1check", "my-check", "../check"} {
+		_, err := NewCheckData(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	repoRoot, err := ioutil.TempDir("", "scaffold")
+	require.NoError(t, err)
+	defer os.RemoveAll(repoRoot)
+
+	data, err := NewCheckData("my_check")
+	require.NoError(t, err)
+
+	paths, err := Generate(repoRoot, data, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(repoRoot, "pkg/collector/corechecks/mycheck/my_check.go"),
+		filepath.Join(repoRoot, "pkg/collector/corechecks/mycheck/my_check_test.go"),
+		filepath.Join(repoRoot, "cmd/agent/dist/conf.d/my_check.d/conf.yaml.example"),
+	}, paths)
+
+	for _, path := range paths[:2] {
+		f, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		require.NoError(t, err, path)
+		assert.Equal(t, "mycheck", f.Name.Name)
+	}
+
+	content, err := ioutil.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `const CheckName = "my_check"`)
+
+	content, err = ioutil.ReadFile(paths[2])
+	require.NoError(t, err)
+	var conf struct {
+		Instances []map[string]interface{} `yaml:"instances"`
+	}
+	require.NoError(t, yaml.Unmarshal(content, &conf))
+	assert.Equal(t, []map[string]interface{}{{"timeout": 5}}, conf.Instances)
+
+	// existing files are not overwritten without force
+	_, err = Generate(repoRoot, data, false)
+	assert.Error(t, err)
+	_, err = Generate(repoRoot, data, true)
+	assert.NoError(t, err)
+}
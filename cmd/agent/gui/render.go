@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"expvar"
+	"fmt"
 	"html/template"
 	"io"
 	"strings"
@@ -21,8 +22,14 @@ func init() {
 	fmap["lastErrorMessage"] = lastErrorMessage
 	fmap["pythonLoaderError"] = pythonLoaderError
 	fmap["status"] = displayStatus
+	fmap["sparkline"] = runHistorySparkline
 }
 
+const (
+	sparklineWidth  = 160
+	sparklineHeight = 20
+)
+
 // Data is a struct used for filling templates
 type Data struct {
 	Name       string
@@ -139,6 +146,51 @@ func lastErrorMessage(value string) string {
 	return "UNKNOWN ERROR"
 }
 
+// runHistorySparkline renders a field of the run history of a check instance as an
+// inline SVG sparkline, the runs that returned an error are marked with a dot
+func runHistorySparkline(history interface{}, field string) template.HTML {
+	runs, ok := history.([]interface{})
+	if !ok || len(runs) == 0 {
+		return ""
+	}
+
+	values := make([]float64, len(runs))
+	failed := make([]bool, len(runs))
+	var max float64
+	for i, r := range runs {
+		run, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		values[i], _ = run[field].(float64)
+		failed[i], _ = run["Error"].(bool)
+		if values[i] > max {
+			max = values[i]
+		}
+	}
+
+	var points, dots strings.Builder
+	for i, value := range values {
+		x := 0.0
+		if len(values) > 1 {
+			x = float64(i) * sparklineWidth / float64(len(values)-1)
+		}
+		y := float64(sparklineHeight)
+		if max > 0 {
+			y -= value / max * sparklineHeight
+		}
+		fmt.Fprintf(&points, "%.1f,%.1f ", x, y)
+		if failed[i] {
+			fmt.Fprintf(&dots, `<circle cx="%.1f" cy="%.1f" r="2"/>`, x, y)
+		}
+	}
+
+	return template.HTML(fmt.Sprintf(
+		`<svg class="sparkline" width="%d" height="%d" viewBox="-2 -2 %d %d"><title>max: %v</title><polyline points="%s"/>%s</svg>`,
+		sparklineWidth, sparklineHeight, sparklineWidth+4, sparklineHeight+4, max, strings.TrimSpace(points.String()), dots.String(),
+	))
+}
+
 func displayStatus(check map[string]interface{}) template.HTML {
 	if check["LastError"].(string) != "" {
 		return template.HTML("[<span class=\"error\">ERROR</span>]")
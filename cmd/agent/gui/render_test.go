@@ -0,0 +1,31 @@
+package gui
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHistorySparkline(t *testing.T) {
+	var history interface{}
+	err := json.Unmarshal([]byte(`[
+		{"Timestamp": 1, "ExecutionTime": 100, "MetricSamples": 10, "Warnings": 0, "Error": false},
+		{"Timestamp": 2, "ExecutionTime": 200, "MetricSamples": 0, "Warnings": 0, "Error": true},
+		{"Timestamp": 3, "ExecutionTime": 0, "MetricSamples": 5, "Warnings": 1, "Error": false}
+	]`), &history)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		`<svg class="sparkline" width="160" height="20" viewBox="-2 -2 164 24"><title>max: 200</title>`+
+			`<polyline points="0.0,10.0 80.0,0.0 160.0,20.0"/><circle cx="80.0" cy="0.0" r="2"/></svg>`,
+		string(runHistorySparkline(history, "ExecutionTime")))
+	assert.Equal(t,
+		`<svg class="sparkline" width="160" height="20" viewBox="-2 -2 164 24"><title>max: 10</title>`+
+			`<polyline points="0.0,0.0 80.0,20.0 160.0,10.0"/><circle cx="80.0" cy="20.0" r="2"/></svg>`,
+		string(runHistorySparkline(history, "MetricSamples")))
+
+	assert.Empty(t, runHistorySparkline(nil, "ExecutionTime"))
+	assert.Empty(t, runHistorySparkline([]interface{}{}, "ExecutionTime"))
+}
@@ -51,6 +51,16 @@ func TestValidateTokenMiddleware(t *testing.T) {
 			"abc123",
 			http.StatusOK,
 		},
+		{
+			"/api/v1/tags/node/node1/taints",
+			"abc123",
+			http.StatusOK,
+		},
+		{
+			"/api/v1/tags/node/node1/taints",
+			"imposter",
+			http.StatusForbidden,
+		},
 		{
 			"/version",
 			"bandit!",
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build clusterchecks
+
+package v1
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/checktemplates"
+)
+
+// installCheckTemplatesEndpoints registers v1 API endpoints for check templates
+func installCheckTemplatesEndpoints(r *mux.Router, sc clusteragent.ServerContext) {
+	r.HandleFunc("/checktemplates", getCheckTemplates(sc)).Methods("GET")
+}
+
+// getCheckTemplates is used by the node-agent's check templates config provider.
+// The agents poll it with the ETag of the templates they have, the templates are
+// only sent when they changed. The `version` parameter pins a version of the templates.
+func getCheckTemplates(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.CheckTemplatesStore == nil {
+		return checkTemplatesDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		response, err := sc.CheckTemplatesStore.Get(r.Context(), r.URL.Query().Get("version"))
+		switch err {
+		case nil:
+		case checktemplates.ErrVersionNotFound:
+			http.Error(w, err.Error(), http.StatusNotFound)
+			incrementRequestMetric("getCheckTemplates", http.StatusNotFound)
+			return
+		case checktemplates.ErrNotReady:
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			incrementRequestMetric("getCheckTemplates", http.StatusServiceUnavailable)
+			return
+		default:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			incrementRequestMetric("getCheckTemplates", http.StatusInternalServerError)
+			return
+		}
+
+		etag := `"` + response.Version + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			incrementRequestMetric("getCheckTemplates", http.StatusNotModified)
+			return
+		}
+
+		writeJSONResponse(w, response, "getCheckTemplates")
+	}
+}
+
+// checkTemplatesDisabledHandler returns a 412 response when the check templates are disabled
+func checkTemplatesDisabledHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusPreconditionFailed)
+	w.Write([]byte("Check templates are not enabled"))
+}
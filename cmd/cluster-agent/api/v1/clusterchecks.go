@@ -28,6 +28,8 @@ func installClusterCheckEndpoints(r *mux.Router, sc clusteragent.ServerContext)
 	r.HandleFunc("/clusterchecks/status/{identifier}", postCheckStatus(sc)).Methods("POST")
 	r.HandleFunc("/clusterchecks/configs/{identifier}", getCheckConfigs(sc)).Methods("GET")
 	r.HandleFunc("/clusterchecks/rebalance", postRebalanceChecks(sc)).Methods("POST")
+	r.HandleFunc("/clusterchecks/snmp/leases/{identifier}", postSNMPLeases(sc)).Methods("POST")
+	r.HandleFunc("/clusterchecks/snmp/leases", getSNMPLeases(sc)).Methods("GET")
 	r.HandleFunc("/clusterchecks", getState(sc)).Methods("GET")
 }
 
@@ -118,6 +120,55 @@ func postRebalanceChecks(sc clusteragent.ServerContext) func(w http.ResponseWrit
 	}
 }
 
+// postSNMPLeases is used by the SNMP discovery of the node-agents and CLC runners
+func postSNMPLeases(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.ClusterCheckHandler == nil {
+		return clusterChecksDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !shouldHandle(w, r, sc.ClusterCheckHandler, "postSNMPLeases") {
+			return
+		}
+
+		vars := mux.Vars(r)
+		identifier := vars["identifier"]
+
+		decoder := json.NewDecoder(r.Body)
+		var leaseRequest cctypes.SNMPLeaseRequest
+		err := decoder.Decode(&leaseRequest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			incrementRequestMetric("postSNMPLeases", http.StatusBadRequest)
+			return
+		}
+
+		response, err := sc.ClusterCheckHandler.LeaseSNMPDevices(identifier, leaseRequest)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			incrementRequestMetric("postSNMPLeases", http.StatusInternalServerError)
+			return
+		}
+
+		writeJSONResponse(w, response, "postSNMPLeases")
+	}
+}
+
+// getSNMPLeases returns the SNMP device leases, for troubleshooting
+func getSNMPLeases(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
+	if sc.ClusterCheckHandler == nil {
+		return clusterChecksDisabledHandler
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !shouldHandle(w, r, sc.ClusterCheckHandler, "getSNMPLeases") {
+			return
+		}
+
+		writeJSONResponse(w, sc.ClusterCheckHandler.GetSNMPLeases(), "getSNMPLeases")
+	}
+}
+
 // getState is used by the clustercheck config
 func getState(sc clusteragent.ServerContext) func(w http.ResponseWriter, r *http.Request) {
 	if sc.ClusterCheckHandler == nil {
@@ -42,3 +42,9 @@ func InstallChecksEndpoints(r *mux.Router, sc clusteragent.ServerContext) {
 	installClusterCheckEndpoints(r, sc)
 	installEndpointsCheckEndpoints(r, sc)
 }
+
+// InstallCheckTemplatesEndpoints registers endpoints for check templates
+func InstallCheckTemplatesEndpoints(r *mux.Router, sc clusteragent.ServerContext) {
+	log.Debug("Registering check templates endpoints")
+	installCheckTemplatesEndpoints(r, sc)
+}
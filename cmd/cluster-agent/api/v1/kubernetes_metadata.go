@@ -21,6 +21,7 @@ func installKubernetesMetadataEndpoints(r *mux.Router) {
 	r.HandleFunc("/tags/pod/{nodeName}", getPodMetadataForNode).Methods("GET")
 	r.HandleFunc("/tags/pod", getAllMetadata).Methods("GET")
 	r.HandleFunc("/tags/node/{nodeName}", getNodeMetadata).Methods("GET")
+	r.HandleFunc("/tags/node/{nodeName}/taints", getNodeTaints).Methods("GET")
 	r.HandleFunc("/tags/namespace/{ns}", getNamespaceMetadata).Methods("GET")
 	r.HandleFunc("/cluster/id", getClusterID).Methods("GET")
 }
@@ -99,6 +100,63 @@ func getNodeMetadata(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(fmt.Sprintf("Could not find labels on the node: %s", nodeName)))
 }
 
+// getNodeTaints is only used when the node agent hits the DCA for the list of taints
+func getNodeTaints(w http.ResponseWriter, r *http.Request) {
+	/*
+		Input
+			localhost:5001/api/v1/tags/node/localhost/taints
+		Outputs
+			Status: 200
+			Returns: []string
+			Example: ["dedicated=gpu:NoSchedule", "node.kubernetes.io/unschedulable:NoSchedule"]
+
+			Status: 500
+			Returns: string
+			Example: "no cached metadata found for the node localhost"
+	*/
+
+	// As HTTP query handler, we do not retry getting the APIServer
+	// Client will have to retry query in case of failure
+	cl, err := as.GetAPIClient()
+	if err != nil {
+		log.Errorf("Can't create client to query the API Server: %v", err) //nolint:errcheck
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiRequests.Inc(
+			"getNodeTaints",
+			strconv.Itoa(http.StatusInternalServerError),
+		)
+		return
+	}
+
+	nodeName := mux.Vars(r)["nodeName"]
+	nodeTaints, err := as.GetNodeTaints(cl, nodeName)
+	if err != nil {
+		log.Errorf("Could not retrieve the node taints of %s: %v", nodeName, err.Error()) //nolint:errcheck
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiRequests.Inc(
+			"getNodeTaints",
+			strconv.Itoa(http.StatusInternalServerError),
+		)
+		return
+	}
+	taintBytes, err := json.Marshal(nodeTaints)
+	if err != nil {
+		log.Errorf("Could not process the taints of the node %s from the informer's cache: %v", nodeName, err.Error()) //nolint:errcheck
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apiRequests.Inc(
+			"getNodeTaints",
+			strconv.Itoa(http.StatusInternalServerError),
+		)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(taintBytes)
+	apiRequests.Inc(
+		"getNodeTaints",
+		strconv.Itoa(http.StatusOK),
+	)
+}
+
 // getNamespaceMetadata is only used when the node agent hits the DCA for the list of labels
 func getNamespaceMetadata(w http.ResponseWriter, r *http.Request) {
 	/*
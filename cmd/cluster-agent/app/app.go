@@ -35,6 +35,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/clusteragent"
 	admissionpkg "github.com/DataDog/datadog-agent/pkg/clusteragent/admission"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/checktemplates"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/config/resolver"
@@ -317,6 +318,19 @@ func start(cmd *cobra.Command, args []string) error {
 		log.Debug("Cluster check Autodiscovery disabled")
 	}
 
+	if config.Datadog.GetBool("cluster_agent.check_templates.enabled") {
+		// Serve the check templates to the node-agents
+		checkTemplatesStore, err := checktemplates.NewStoreFromConfig()
+		if err == nil {
+			go checkTemplatesStore.Run(mainCtx)
+			api.ModifyAPIRouter(func(r *mux.Router) {
+				dcav1.InstallCheckTemplatesEndpoints(r, clusteragent.ServerContext{CheckTemplatesStore: checkTemplatesStore})
+			})
+		} else {
+			log.Errorf("Error while setting up the check templates, the check templates API endpoint won't be available, err: %v", err)
+		}
+	}
+
 	wg := sync.WaitGroup{}
 	// Autoscaler Controller Goroutine
 	if config.Datadog.GetBool("external_metrics_provider.enabled") {
@@ -59,5 +59,8 @@ func getSettingsClient() (commonsettings.Client, error) {
 
 // initRuntimeSettings builds the map of runtime Cluster Agent settings configurable at runtime.
 func initRuntimeSettings() error {
-	return commonsettings.RegisterRuntimeSetting(commonsettings.LogLevelRuntimeSetting{})
+	if err := commonsettings.RegisterRuntimeSetting(commonsettings.LogLevelRuntimeSetting{}); err != nil {
+		return err
+	}
+	return commonsettings.RegisterRuntimeSetting(commonsettings.LogLevelByModuleRuntimeSetting{})
 }
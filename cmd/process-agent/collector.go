@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -45,6 +46,13 @@ type checkPayload struct {
 	headers http.Header
 }
 
+// endpointForwarder is a forwarder sending the payloads to the intake domain
+// it's named after, or to all the domains when it's empty
+type endpointForwarder struct {
+	forwarder.Forwarder
+	domain string
+}
+
 // Collector will collect metrics from the local system and ship to the backend.
 type Collector struct {
 	// Set to 1 if enabled 0 is not. We're using an integer
@@ -60,6 +68,16 @@ type Collector struct {
 	runCounters   sync.Map
 	enabledChecks []checks.Check
 
+	// post-processors run on the payloads before they are encoded
+	postProcessors []checks.PayloadPostProcessor
+
+	// encoder encodes the payloads sent to the process intake
+	encoder *api.PayloadEncoder
+	// the process intake domains which rejected the zstd dictionary, the payloads
+	// compressed with it are encoded again without it for them
+	noDictionaryDomains sync.Map
+	noDictionaryCount   int32
+
 	// Controls the real-time interval, can change live.
 	realTimeInterval time.Duration
 
@@ -83,16 +101,23 @@ func NewCollector(cfg *config.AgentConfig) (Collector, error) {
 		}
 	}
 
+	if cfg.SoftwareInventory.Enabled {
+		checks.SoftwareInventory.Init(cfg, sysInfo)
+	}
+
 	return NewCollectorWithChecks(cfg, enabledChecks), nil
 }
 
 // NewCollectorWithChecks creates a new Collector
-func NewCollectorWithChecks(cfg *config.AgentConfig, checks []checks.Check) Collector {
+func NewCollectorWithChecks(cfg *config.AgentConfig, enabledChecks []checks.Check) Collector {
 	return Collector{
 		rtIntervalCh:  make(chan time.Duration),
 		cfg:           cfg,
 		groupID:       rand.Int31(),
-		enabledChecks: checks,
+		enabledChecks: enabledChecks,
+
+		postProcessors: checks.NewPayloadPostProcessors(cfg),
+		encoder:        api.NewPayloadEncoder(cfg.ZstdDictionary),
 
 		// Defaults for real-time on start
 		realTimeInterval: 2 * time.Second,
@@ -171,11 +196,7 @@ func (l *Collector) messagesToResults(start time.Time, name string, messages []m
 	sizeInBytes := 0
 
 	for _, m := range messages {
-		body, err := api.EncodePayload(m)
-		if err != nil {
-			log.Errorf("Unable to encode message: %s", err)
-			continue
-		}
+		checks.PostProcessPayload(l.postProcessors, name, m)
 
 		extraHeaders := make(http.Header)
 		extraHeaders.Set(headers.TimestampHeader, strconv.Itoa(int(start.Unix())))
@@ -183,6 +204,19 @@ func (l *Collector) messagesToResults(start time.Time, name string, messages []m
 		extraHeaders.Set(headers.ProcessVersionHeader, Version)
 		extraHeaders.Set(headers.ContainerCountHeader, strconv.Itoa(getContainerCount(m)))
 
+		var body []byte
+		var err error
+		if name == checks.Pod.Name() {
+			// the orchestrator intake doesn't support the zstd dictionary
+			body, err = api.EncodePayload(m)
+		} else {
+			body, err = l.encoder.Encode(m, extraHeaders)
+		}
+		if err != nil {
+			log.Errorf("Unable to encode message: %s", err)
+			continue
+		}
+
 		if l.cfg.Orchestrator.OrchestrationCollectionEnabled {
 			if cid, err := clustername.GetClusterID(); err == nil && cid != "" {
 				extraHeaders.Set(headers.ClusterIDHeader, cid)
@@ -265,47 +299,39 @@ func (l *Collector) run(exit chan struct{}) error {
 		}
 	}()
 
-	processForwarderOpts := forwarder.NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(apicfg.KeysPerDomains(l.cfg.APIEndpoints)))
-	processForwarderOpts.DisableAPIKeyChecking = true
-	processForwarderOpts.RetryQueuePayloadsTotalMaxSize = l.cfg.ProcessQueueBytes // Allow more in-flight requests than the default
-	processForwarder := forwarder.NewDefaultForwarder(processForwarderOpts)
-
-	// rt forwarder can reuse processForwarder's config
-	rtProcessForwarder := forwarder.NewDefaultForwarder(processForwarderOpts)
+	processForwarders := l.newProcessForwarders()
+	// rt forwarders can reuse processForwarders' config
+	rtProcessForwarders := l.newProcessForwarders()
+	podForwarders := []*endpointForwarder{newEndpointForwarder("", apicfg.KeysPerDomains(l.cfg.Orchestrator.OrchestratorEndpoints), l.cfg.ProcessQueueBytes)}
 
-	podForwarderOpts := forwarder.NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(apicfg.KeysPerDomains(l.cfg.Orchestrator.OrchestratorEndpoints)))
-	podForwarderOpts.DisableAPIKeyChecking = true
-	podForwarderOpts.RetryQueuePayloadsTotalMaxSize = l.cfg.ProcessQueueBytes // Allow more in-flight requests than the default
-	podForwarder := forwarder.NewDefaultForwarder(podForwarderOpts)
-
-	if err := processForwarder.Start(); err != nil {
+	if err := startForwarders(processForwarders); err != nil {
 		return fmt.Errorf("error starting forwarder: %s", err)
 	}
 
-	if err := rtProcessForwarder.Start(); err != nil {
+	if err := startForwarders(rtProcessForwarders); err != nil {
 		return fmt.Errorf("error starting RT forwarder: %s", err)
 	}
 
-	if err := podForwarder.Start(); err != nil {
+	if err := startForwarders(podForwarders); err != nil {
 		return fmt.Errorf("error starting pod forwarder: %s", err)
 	}
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		l.consumePayloads(l.processResults, processForwarder, exit)
+		l.consumePayloads(l.processResults, processForwarders, exit)
 	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		l.consumePayloads(l.rtProcessResults, rtProcessForwarder, exit)
+		l.consumePayloads(l.rtProcessResults, rtProcessForwarders, exit)
 	}()
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		l.consumePayloads(l.podResults, podForwarder, exit)
+		l.consumePayloads(l.podResults, podForwarders, exit)
 	}()
 
 	for _, c := range l.enabledChecks {
@@ -321,15 +347,64 @@ func (l *Collector) run(exit chan struct{}) error {
 		}()
 	}
 
+	if l.cfg.SoftwareInventory.Enabled {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.runSoftwareInventory(l.processResults, exit)
+		}()
+	}
+
 	<-exit
 	wg.Wait()
 
-	processForwarder.Stop()
-	rtProcessForwarder.Stop()
-	podForwarder.Stop()
+	stopForwarders(processForwarders)
+	stopForwarders(rtProcessForwarders)
+	stopForwarders(podForwarders)
+	return nil
+}
+
+// newProcessForwarders returns the forwarders of the process intake. With the zstd
+// dictionary, each domain has its own forwarder so that a payload can be sent
+// again without the dictionary to the domains which reject it.
+func (l *Collector) newProcessForwarders() []*endpointForwarder {
+	keysPerDomains := apicfg.KeysPerDomains(l.cfg.APIEndpoints)
+	if !l.cfg.ZstdDictionary {
+		return []*endpointForwarder{newEndpointForwarder("", keysPerDomains, l.cfg.ProcessQueueBytes)}
+	}
+
+	forwarders := make([]*endpointForwarder, 0, len(keysPerDomains))
+	for domain, keys := range keysPerDomains {
+		forwarders = append(forwarders, newEndpointForwarder(domain, map[string][]string{domain: keys}, l.cfg.ProcessQueueBytes))
+	}
+	return forwarders
+}
+
+func newEndpointForwarder(domain string, keysPerDomains map[string][]string, queueBytes int) *endpointForwarder {
+	opts := forwarder.NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysPerDomains))
+	opts.DisableAPIKeyChecking = true
+	opts.RetryQueuePayloadsTotalMaxSize = queueBytes // Allow more in-flight requests than the default
+	return &endpointForwarder{
+		Forwarder: forwarder.NewDefaultForwarder(opts),
+		domain:    domain,
+	}
+}
+
+func startForwarders(forwarders []*endpointForwarder) error {
+	for _, fwd := range forwarders {
+		if err := fwd.Start(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+func stopForwarders(forwarders []*endpointForwarder) {
+	for _, fwd := range forwarders {
+		fwd.Stop()
+	}
+}
+
 func (l *Collector) resultsQueueForCheck(name string) *api.WeightedQueue {
 	switch name {
 	case checks.Pod.Name():
@@ -395,7 +470,51 @@ func (l *Collector) basicRunner(c checks.Check, results *api.WeightedQueue, exit
 	}
 }
 
-func (l *Collector) consumePayloads(results *api.WeightedQueue, fwd forwarder.Forwarder, exit chan struct{}) {
+// runSoftwareInventory periodically sends the inventory of the binaries of the running processes
+func (l *Collector) runSoftwareInventory(results *api.WeightedQueue, exit chan struct{}) {
+	l.collectSoftwareInventory(results)
+
+	ticker := time.NewTicker(l.cfg.SoftwareInventory.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.collectSoftwareInventory(results)
+		case <-exit:
+			return
+		}
+	}
+}
+
+func (l *Collector) collectSoftwareInventory(results *api.WeightedQueue) {
+	start := time.Now()
+	inventory, err := checks.SoftwareInventory.Collect(l.cfg, start)
+	if err != nil {
+		log.Errorf("Unable to collect the software inventory: %s", err)
+		return
+	}
+
+	body, err := json.Marshal(inventory)
+	if err != nil {
+		log.Errorf("Unable to encode the software inventory: %s", err)
+		return
+	}
+
+	extraHeaders := make(http.Header)
+	extraHeaders.Set(headers.TimestampHeader, strconv.Itoa(int(start.Unix())))
+	extraHeaders.Set(headers.HostHeader, l.cfg.HostName)
+	extraHeaders.Set(headers.ProcessVersionHeader, Version)
+	extraHeaders.Set("Content-Type", "application/json")
+
+	results.Add(&checkResult{
+		name:        checks.SoftwareInventory.Name(),
+		payloads:    []checkPayload{{body: body, headers: extraHeaders}},
+		sizeInBytes: int64(len(body)),
+	})
+	log.Debugf("Collected the software inventory in %s: %d binaries", time.Since(start), len(inventory.Binaries))
+}
+
+func (l *Collector) consumePayloads(results *api.WeightedQueue, forwarders []*endpointForwarder, exit chan struct{}) {
 	for {
 		// results.Poll() will block until either `exit` is closed, or an item is available on the queue (a check run occurs and adds data)
 		item, ok := results.Poll(exit)
@@ -404,47 +523,99 @@ func (l *Collector) consumePayloads(results *api.WeightedQueue, fwd forwarder.Fo
 		}
 		result := item.(*checkResult)
 		for _, payload := range result.payloads {
-			var (
-				forwarderPayload = forwarder.Payloads{&payload.body}
-				responses        chan forwarder.Response
-				err              error
-				updateRTStatus   = true
-			)
-
-			switch result.name {
-			case checks.Process.Name():
-				responses, err = fwd.SubmitProcessChecks(forwarderPayload, payload.headers)
-			case checks.Process.RealTimeName():
-				responses, err = fwd.SubmitRTProcessChecks(forwarderPayload, payload.headers)
-			case checks.Container.Name():
-				responses, err = fwd.SubmitContainerChecks(forwarderPayload, payload.headers)
-			case checks.RTContainer.Name():
-				responses, err = fwd.SubmitRTContainerChecks(forwarderPayload, payload.headers)
-			case checks.Connections.Name():
-				responses, err = fwd.SubmitConnectionChecks(forwarderPayload, payload.headers)
-			case checks.Pod.Name():
-				// Orchestrator intake response does not change RT checks enablement or interval
-				updateRTStatus = false
-				responses, err = fwd.SubmitOrchestratorChecks(forwarderPayload, payload.headers, int(orchestrator.K8sPod))
-			case checks.ProcessDiscovery.Name():
-				// A Process Discovery check does not change the RT mode
-				updateRTStatus = false
-				responses, err = fwd.SubmitProcessDiscoveryChecks(forwarderPayload, payload.headers)
-			default:
-				err = fmt.Errorf("unsupported payload type: %s", result.name)
+			var statuses []*model.CollectorStatus
+			for _, fwd := range forwarders {
+				statuses = append(statuses, l.sendPayload(result.name, payload, fwd)...)
 			}
 
-			if err != nil {
-				log.Errorf("Unable to submit payload: %s", err)
-				continue
+			if len(statuses) > 0 && updatesRTStatus(result.name) {
+				l.updateRTStatus(statuses)
 			}
+		}
+	}
+}
 
-			if statuses := readResponseStatuses(result.name, responses); len(statuses) > 0 {
-				if updateRTStatus {
-					l.updateRTStatus(statuses)
-				}
-			}
+// sendPayload sends a payload through a forwarder and returns the statuses of the
+// responses. A payload compressed with the zstd dictionary is sent without it to
+// the domains which don't support it.
+func (l *Collector) sendPayload(checkName string, payload checkPayload, fwd *endpointForwarder) []*model.CollectorStatus {
+	body, extraHeaders := payload.body, payload.headers
+	withDictionary := api.UsesDictionary(extraHeaders)
+	if withDictionary && l.dictionaryRejectedBy(fwd.domain) {
+		var err error
+		if body, extraHeaders, err = api.EncodeWithoutDictionary(body, extraHeaders); err != nil {
+			log.Errorf("[%s] Unable to encode message without the zstd dictionary: %s", checkName, err)
+			return nil
 		}
+		withDictionary = false
+	}
+
+	responses, err := submitPayload(fwd, checkName, body, extraHeaders)
+	if err != nil {
+		log.Errorf("Unable to submit payload: %s", err)
+		return nil
+	}
+
+	statuses, dictionaryRejected := readResponseStatuses(checkName, responses, withDictionary)
+	if dictionaryRejected {
+		l.rejectDictionary(fwd.domain)
+		return l.sendPayload(checkName, payload, fwd)
+	}
+	return statuses
+}
+
+func submitPayload(fwd forwarder.Forwarder, checkName string, body []byte, extraHeaders http.Header) (chan forwarder.Response, error) {
+	forwarderPayload := forwarder.Payloads{&body}
+
+	switch checkName {
+	case checks.Process.Name():
+		return fwd.SubmitProcessChecks(forwarderPayload, extraHeaders)
+	case checks.Process.RealTimeName():
+		return fwd.SubmitRTProcessChecks(forwarderPayload, extraHeaders)
+	case checks.Container.Name():
+		return fwd.SubmitContainerChecks(forwarderPayload, extraHeaders)
+	case checks.RTContainer.Name():
+		return fwd.SubmitRTContainerChecks(forwarderPayload, extraHeaders)
+	case checks.Connections.Name():
+		return fwd.SubmitConnectionChecks(forwarderPayload, extraHeaders)
+	case checks.Pod.Name():
+		return fwd.SubmitOrchestratorChecks(forwarderPayload, extraHeaders, int(orchestrator.K8sPod))
+	case checks.ProcessDiscovery.Name():
+		return fwd.SubmitProcessDiscoveryChecks(forwarderPayload, extraHeaders)
+	case checks.SoftwareInventory.Name():
+		return fwd.SubmitSoftwareInventory(forwarderPayload, extraHeaders)
+	default:
+		return nil, fmt.Errorf("unsupported payload type: %s", checkName)
+	}
+}
+
+// updatesRTStatus returns whether the responses to the payloads of a check change the RT mode
+func updatesRTStatus(checkName string) bool {
+	switch checkName {
+	// Orchestrator intake response does not change RT checks enablement or interval,
+	// and neither do the Process Discovery check and the software inventory
+	case checks.Pod.Name(), checks.ProcessDiscovery.Name(), checks.SoftwareInventory.Name():
+		return false
+	}
+	return true
+}
+
+// dictionaryRejectedBy returns whether a domain rejected the payloads compressed with the zstd dictionary
+func (l *Collector) dictionaryRejectedBy(domain string) bool {
+	_, rejected := l.noDictionaryDomains.Load(domain)
+	return rejected
+}
+
+// rejectDictionary stops sending the payloads compressed with the zstd dictionary to a domain.
+// The payloads aren't compressed with the dictionary anymore once all the domains rejected it.
+func (l *Collector) rejectDictionary(domain string) {
+	if _, loaded := l.noDictionaryDomains.LoadOrStore(domain, struct{}{}); loaded {
+		return
+	}
+	log.Warnf("%s doesn't support the zstd dictionary, falling back to the default compression for this endpoint", domain)
+
+	if int(atomic.AddInt32(&l.noDictionaryCount, 1)) >= len(apicfg.KeysPerDomains(l.cfg.APIEndpoints)) {
+		l.encoder.DisableDictionary()
 	}
 }
 
@@ -507,8 +678,11 @@ func getContainerCount(mb model.MessageBody) int {
 	return 0
 }
 
-func readResponseStatuses(checkName string, responses <-chan forwarder.Response) []*model.CollectorStatus {
+// readResponseStatuses returns the statuses of the responses, and whether the payload
+// compressed with the zstd dictionary was rejected
+func readResponseStatuses(checkName string, responses <-chan forwarder.Response, withDictionary bool) ([]*model.CollectorStatus, bool) {
 	var statuses []*model.CollectorStatus
+	dictionaryRejected := false
 
 	for response := range responses {
 		if response.Err != nil {
@@ -516,6 +690,12 @@ func readResponseStatuses(checkName string, responses <-chan forwarder.Response)
 			continue
 		}
 
+		if response.StatusCode == http.StatusUnsupportedMediaType && withDictionary {
+			log.Debugf("[%s] %s rejected the payload compressed with the zstd dictionary", checkName, response.Domain)
+			dictionaryRejected = true
+			continue
+		}
+
 		if response.StatusCode >= 300 {
 			log.Errorf("[%s] Invalid response from %s: %d -> %s", checkName, response.Domain, response.StatusCode, response.Err)
 			continue
@@ -540,5 +720,5 @@ func readResponseStatuses(checkName string, responses <-chan forwarder.Response)
 		}
 	}
 
-	return statuses
+	return statuses, dictionaryRejected
 }
@@ -15,6 +15,7 @@ import (
 
 	model "github.com/DataDog/agent-payload/process"
 	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/net"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 )
 
@@ -61,7 +62,16 @@ const (
   Logs: {{.Status.Config.LogFile}}{{if .Status.ProxyURL}}
   HttpProxy: {{.Status.ProxyURL}}{{end}}{{if ne .Status.ContainerID ""}}
   Container ID: {{.Status.ContainerID}}{{end}}
-
+{{if .Status.Config.EnableSystemProbe}}
+  System Probe
+  ============
+    Path: {{.Status.SystemProbe.Path}}
+    State: {{.Status.SystemProbe.State}}{{if .Status.SystemProbe.LastSuccess}}
+    Last successful request: {{.Status.SystemProbe.LastSuccess}}{{end}}{{if .Status.SystemProbe.ConsecutiveErrors}}
+    Consecutive errors: {{.Status.SystemProbe.ConsecutiveErrors}}
+    Last error: {{.Status.SystemProbe.LastError}}
+    Next retry: {{.Status.SystemProbe.RetryAt}}{{end}}
+{{end}}
 `
 	infoNotRunningTmplSrc = `{{.Banner}}
 {{.Program}}
@@ -230,6 +240,10 @@ func publishContainerID() interface{} {
 	return containerID
 }
 
+func publishSystemProbeStatus() interface{} {
+	return net.GetStatus()
+}
+
 func getProgramBanner(version string) (string, string) {
 	program := fmt.Sprintf("Processes and Containers Agent (v %s)", version)
 	banner := strings.Repeat("=", len(program))
@@ -263,6 +277,7 @@ type StatusInfo struct {
 	PodQueueBytes       int                    `json:"pod_queue_bytes"`
 	ContainerID         string                 `json:"container_id"`
 	ProxyURL            string                 `json:"proxy_url"`
+	SystemProbe         net.Status             `json:"system_probe"`
 }
 
 func initInfo(_ *config.AgentConfig) error {
@@ -291,6 +306,7 @@ func initInfo(_ *config.AgentConfig) error {
 		expvar.Publish("rtprocess_queue_bytes", expvar.Func(publishRTProcessQueueBytes))
 		expvar.Publish("pod_queue_bytes", expvar.Func(publishPodQueueBytes))
 		expvar.Publish("container_id", expvar.Func(publishContainerID))
+		expvar.Publish("system_probe", expvar.Func(publishSystemProbeStatus))
 
 		infoTmpl, err = template.New("info").Funcs(funcMap).Parse(infoTmplSrc)
 		if err != nil {
@@ -31,6 +31,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/tagger/remote"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	ddutil "github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/fips"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/profiling"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
@@ -182,6 +183,11 @@ func runAgent(exit chan struct{}) {
 		cleanupAndExit(1)
 	}
 
+	if err := fips.Verify(); err != nil {
+		log.Criticalf("Invalid FIPS configuration: %s", err)
+		cleanupAndExit(1)
+	}
+
 	mainCtx, mainCancel := context.WithCancel(context.Background())
 	defer mainCancel()
 	err = manager.ConfigureAutoExit(mainCtx)
@@ -197,7 +203,7 @@ func runAgent(exit chan struct{}) {
 
 	// Tagger must be initialized after agent config has been setup
 	var t tagger.Tagger
-	if ddconfig.Datadog.GetBool("process_config.remote_tagger") {
+	if cfg.RemoteTagger {
 		t = remote.NewTagger()
 	} else {
 		// Start workload metadata store before tagger
@@ -314,7 +320,11 @@ func debugCheckResults(cfg *config.AgentConfig, check string) error {
 		checks.Process.Run(cfg, 0) //nolint:errcheck
 	}
 
-	names := make([]string, 0, len(checks.All))
+	if check == checks.ProcessEvents.Name() {
+		return runProcessEventsCheck(cfg, sysInfo)
+	}
+
+	names := make([]string, 0, len(checks.All)+1)
 	for _, ch := range checks.All {
 		names = append(names, ch.Name())
 
@@ -329,9 +339,35 @@ func debugCheckResults(cfg *config.AgentConfig, check string) error {
 			return runCheckAsRealTime(cfg, withRealTime)
 		}
 	}
+	names = append(names, checks.ProcessEvents.Name())
 	return fmt.Errorf("invalid check '%s', choose from: %v", check, names)
 }
 
+// runProcessEventsCheck prints the process events received for a few seconds
+func runProcessEventsCheck(cfg *config.AgentConfig, sysInfo *process.SystemInfo) error {
+	if err := checks.ProcessEvents.Init(cfg, sysInfo); err != nil {
+		return fmt.Errorf("initialization error: %s", err)
+	}
+	defer checks.ProcessEvents.Stop()
+
+	time.Sleep(5 * time.Second)
+
+	printResultsBanner(checks.ProcessEvents.Name())
+
+	batches, err := checks.ProcessEvents.Run(cfg, 1)
+	if err != nil {
+		return fmt.Errorf("collection error: %s", err)
+	}
+	for _, batch := range batches {
+		b, err := json.MarshalIndent(batch, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal error: %s", err)
+		}
+		fmt.Println(string(b))
+	}
+	return nil
+}
+
 func runCheck(cfg *config.AgentConfig, ch checks.Check) error {
 	// Run the check once to prime the cache.
 	if _, err := ch.Run(cfg, 0); err != nil {
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux_bpf
+
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/bytecode/runtime"
+	"github.com/spf13/cobra"
+)
+
+var privateKeyPath string
+
+func init() {
+	runtimeCacheSignCommand.Flags().StringVarP(&privateKeyPath, "private-key", "k", "", "Path to the PEM encoded ed25519 private key signing the programs")
+	_ = runtimeCacheSignCommand.MarkFlagRequired("private-key")
+	runtimeCacheCommand.AddCommand(runtimeCacheSignCommand)
+	SysprobeCmd.AddCommand(runtimeCacheCommand)
+}
+
+var (
+	runtimeCacheCommand = &cobra.Command{
+		Use:   "runtime-cache",
+		Short: "Manage the shared cache of runtime compiled programs",
+		Long:  ``,
+	}
+
+	runtimeCacheSignCommand = &cobra.Command{
+		Use:   "sign [program.o]...",
+		Short: "Sign runtime compiled programs so they can be loaded from a shared cache",
+		Long: `Sign the programs found in the runtime compiler output directory of a host, so that they can be
+copied to the shared cache directory (runtime_compiler_shared_cache_dir) of the hosts running the same kernel
+release on the same architecture.
+The signature of each program is written next to it, in a file with the .sig extension.`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: signRuntimeCompiledPrograms,
+	}
+)
+
+func signRuntimeCompiledPrograms(_ *cobra.Command, args []string) error {
+	encodedKey, err := ioutil.ReadFile(privateKeyPath)
+	if err != nil {
+		return fmt.Errorf("unable to read the private key: %w", err)
+	}
+	privateKey, err := runtime.ParsePrivateKey(encodedKey)
+	if err != nil {
+		return err
+	}
+
+	for _, program := range args {
+		content, err := ioutil.ReadFile(program)
+		if err != nil {
+			return fmt.Errorf("unable to read %s: %w", program, err)
+		}
+		if err := ioutil.WriteFile(program+runtime.SignatureExt, runtime.SignCompiledOutput(content, privateKey), 0644); err != nil {
+			return fmt.Errorf("unable to write the signature of %s: %w", program, err)
+		}
+		fmt.Printf("Signed %s\n", program)
+	}
+	return nil
+}
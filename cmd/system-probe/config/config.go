@@ -33,6 +33,7 @@ const (
 	NetworkTracerModule        ModuleName = "network_tracer"
 	OOMKillProbeModule         ModuleName = "oom_kill_probe"
 	TCPQueueLengthTracerModule ModuleName = "tcp_queue_length_tracer"
+	TCPRetransmitTracerModule  ModuleName = "tcp_retransmit_tracer"
 	SecurityRuntimeModule      ModuleName = "security_runtime"
 	ProcessModule              ModuleName = "process"
 )
@@ -208,6 +209,10 @@ func load(configPath string) (*Config, error) {
 		log.Info("system_probe_config.enable_oom_kill detected, will enable system-probe with OOM Kill check")
 		c.EnabledModules[OOMKillProbeModule] = struct{}{}
 	}
+	if cfg.GetBool(key(spNS, "enable_tcp_retransmit")) {
+		log.Info("system_probe_config.enable_tcp_retransmit detected, will enable system-probe with TCP retransmit check")
+		c.EnabledModules[TCPRetransmitTracerModule] = struct{}{}
+	}
 	if cfg.GetBool("runtime_security_config.enabled") || cfg.GetBool("runtime_security_config.fim_enabled") {
 		log.Info("runtime_security_config.enabled or runtime_security_config.fim_enabled detected, enabling system-probe")
 		c.EnabledModules[SecurityRuntimeModule] = struct{}{}
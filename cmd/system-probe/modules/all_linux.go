@@ -12,6 +12,7 @@ import (
 var All = []module.Factory{
 	NetworkTracer,
 	TCPQueueLength,
+	TCPRetransmit,
 	OOMKillProbe,
 	SecurityRuntime,
 	Process,
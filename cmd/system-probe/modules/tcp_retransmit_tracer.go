@@ -0,0 +1,48 @@
+// +build linux
+
+package modules
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/DataDog/datadog-agent/cmd/system-probe/api/module"
+	"github.com/DataDog/datadog-agent/cmd/system-probe/config"
+	"github.com/DataDog/datadog-agent/cmd/system-probe/utils"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe"
+	"github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// TCPRetransmit Factory
+var TCPRetransmit = module.Factory{
+	Name: config.TCPRetransmitTracerModule,
+	Fn: func(cfg *config.Config) (module.Module, error) {
+		log.Infof("Starting the TCP retransmit tracer")
+		t, err := probe.NewTCPRetransmitTracer(ebpf.NewConfig())
+		if err != nil {
+			return nil, fmt.Errorf("unable to start the TCP retransmit tracer: %w", err)
+		}
+
+		return &tcpRetransmitModule{t}, nil
+	},
+}
+
+var _ module.Module = &tcpRetransmitModule{}
+
+type tcpRetransmitModule struct {
+	*probe.TCPRetransmitTracer
+}
+
+func (t *tcpRetransmitModule) Register(httpMux *module.Router) error {
+	httpMux.HandleFunc("/check/tcp_retransmit", func(w http.ResponseWriter, req *http.Request) {
+		stats := t.TCPRetransmitTracer.GetAndFlush()
+		utils.WriteAsJSON(w, stats)
+	})
+
+	return nil
+}
+
+func (t *tcpRetransmitModule) GetStats() map[string]interface{} {
+	return nil
+}
@@ -6,6 +6,7 @@
 package aggregator
 
 import (
+	"context"
 	"errors"
 	"expvar"
 	"fmt"
@@ -40,6 +41,9 @@ const MetricSamplePoolBatchSize = 32
 // tagsetTlm handles telemetry for large tagsets.
 var tagsetTlm *tagsetTelemetry
 
+// tagCardinalityTlm reports the tag keys with the most unique values.
+var tagCardinalityTlm *tagCardinalityTelemetry
+
 // Stats stores a statistic from several past flushes allowing computations like median or percentiles
 type Stats struct {
 	Flushes    [32]int64 // circular buffer of recent flushes stat
@@ -89,8 +93,12 @@ func expMetricTags() interface{} {
 	return tagsetTlm.exp()
 }
 
+func expTagCardinality() interface{} {
+	return tagCardinalityTlm.TopOffenders()
+}
+
 func timeNowNano() float64 {
-	return float64(time.Now().UnixNano()) / float64(time.Second) // Unix time with nanosecond precision
+	return float64(correctTime(time.Now()).UnixNano()) / float64(time.Second) // Unix time with nanosecond precision
 }
 
 var (
@@ -174,6 +182,9 @@ func init() {
 	tagsetTlm = newTagsetTelemetry([]uint64{90, 100})
 
 	aggregatorExpvars.Set("MetricTags", expvar.Func(expMetricTags))
+
+	tagCardinalityTlm = &tagCardinalityTelemetry{}
+	aggregatorExpvars.Set("TagCardinality", expvar.Func(expTagCardinality))
 }
 
 // InitAggregator returns the Singleton instance
@@ -198,6 +209,11 @@ func SetDefaultAggregator(agg *BufferedAggregator) {
 	go aggregatorInstance.run()
 }
 
+// GetDefaultAggregator returns the default Aggregator, nil if it wasn't initialized.
+func GetDefaultAggregator() *BufferedAggregator {
+	return aggregatorInstance
+}
+
 // StopDefaultAggregator stops the default aggregator. Based on 'flushData'
 // waiting metrics (from checks or closed dogstatsd buckets) will be sent to
 // the serializer before stopping.
@@ -207,6 +223,14 @@ func StopDefaultAggregator() {
 	}
 }
 
+// StopDefaultAggregatorWithFinalFlush stops the default aggregator after a final flush, see StopWithFinalFlush
+func StopDefaultAggregatorWithFinalFlush(deadline time.Time) bool {
+	if aggregatorInstance != nil {
+		return aggregatorInstance.StopWithFinalFlush(deadline)
+	}
+	return true
+}
+
 // BufferedAggregator aggregates metrics in buckets for dogstatsd Metrics
 type BufferedAggregator struct {
 	bufferedMetricIn       chan []metrics.MetricSample
@@ -232,22 +256,27 @@ type BufferedAggregator struct {
 	serviceChecks          metrics.ServiceChecks
 	events                 metrics.Events
 	flushInterval          time.Duration
+	flushIntervals         FlushIntervals
+	flushTicks             int64
 	mu                     sync.Mutex // to protect the checkSamplers field
 	flushMutex             sync.Mutex // to start multiple flushes in parallel
 	serializer             serializer.MetricSerializer
 	eventPlatformForwarder epforwarder.EventPlatformForwarder
 	hostname               string
 	hostnameUpdate         chan string
-	hostnameUpdateDone     chan struct{}    // signals that the hostname update is finished
-	TickerChan             <-chan time.Time // For test/benchmark purposes: it allows the flush to be controlled from the outside
-	ServerlessFlush        chan bool
-	ServerlessFlushDone    chan struct{}
+	hostnameUpdateDone     chan struct{}     // signals that the hostname update is finished
+	TickerChan             <-chan time.Time  // For test/benchmark purposes: it allows the flush to be controlled from the outside
+	flushRequests          chan flushRequest // synchronous flushes requested with FlushWithDeadline
 	stopChan               chan struct{}
 	health                 *health.Handle
 	agentName              string // Name of the agent for telemetry metrics
 
 	tlmContainerTagsEnabled bool                                              // Whether we should call the tagger to tag agent telemetry metrics
 	agentTags               func(collectors.TagCardinality) ([]string, error) // This function gets the agent tags from the tagger (defined as a struct field to ease testing)
+
+	// the series and sketches drained from the samplers whose flush isn't due yet, protected by flushMutex
+	pendingSeries   metrics.Series
+	pendingSketches metrics.SketchSeriesList
 }
 
 // NewBufferedAggregator instantiates a BufferedAggregator
@@ -287,6 +316,7 @@ func NewBufferedAggregator(s serializer.MetricSerializer, eventPlatformForwarder
 		statsdSampler:           *NewTimeSampler(bucketSize),
 		checkSamplers:           make(map[check.ID]*CheckSampler),
 		flushInterval:           flushInterval,
+		flushIntervals:          flushIntervalsFromConfig(flushInterval),
 		serializer:              s,
 		eventPlatformForwarder:  eventPlatformForwarder,
 		hostname:                hostname,
@@ -297,8 +327,7 @@ func NewBufferedAggregator(s serializer.MetricSerializer, eventPlatformForwarder
 		agentName:               agentName,
 		tlmContainerTagsEnabled: config.Datadog.GetBool("basic_telemetry_add_container_tags"),
 		agentTags:               tagger.AgentTags,
-		ServerlessFlush:         make(chan bool),
-		ServerlessFlushDone:     make(chan struct{}),
+		flushRequests:           make(chan flushRequest),
 	}
 
 	return aggregator
@@ -320,6 +349,14 @@ func (agg *BufferedAggregator) IsInputQueueEmpty() bool {
 	return false
 }
 
+// pendingInputs returns the number of inputs queued in all the input channels
+func (agg *BufferedAggregator) pendingInputs() int {
+	return len(agg.bufferedMetricIn) + len(agg.bufferedMetricInWithTs) + len(agg.bufferedServiceCheckIn) + len(agg.bufferedEventIn) +
+		len(agg.metricIn) + len(agg.serviceCheckIn) + len(agg.eventIn) +
+		len(agg.checkMetricIn) + len(agg.checkHistogramBucketIn) +
+		len(agg.orchestratorMetadataIn) + len(agg.eventPlatformIn)
+}
+
 // GetChannels returns a channel which can be subsequently used to send MetricSamples, Event or ServiceCheck
 func (agg *BufferedAggregator) GetChannels() (chan *metrics.MetricSample, chan metrics.Event, chan metrics.ServiceCheck) {
 	return agg.metricIn, agg.eventIn, agg.serviceCheckIn
@@ -381,6 +418,23 @@ func (agg *BufferedAggregator) registerSender(id check.ID) error {
 	return nil
 }
 
+// persistSenderBaselines enables or disables the persistence of the baselines of the check sampler
+func (agg *BufferedAggregator) persistSenderBaselines(id check.ID, persist bool) {
+	agg.mu.Lock()
+	defer agg.mu.Unlock()
+
+	checkSampler, ok := agg.checkSamplers[id]
+	if !ok {
+		log.Debugf("CheckSampler with ID '%s' doesn't exist, can't persist its baselines", id)
+		return
+	}
+	if !persist {
+		checkSampler.baselines = nil
+	} else if checkSampler.baselines == nil {
+		checkSampler.baselines = newCheckBaselines(id)
+	}
+}
+
 func (agg *BufferedAggregator) deregisterSender(id check.ID) {
 	agg.mu.Lock()
 	delete(agg.checkSamplers, id)
@@ -427,7 +481,7 @@ func (agg *BufferedAggregator) handleEventPlatformEvent(event senderEventPlatfor
 // addServiceCheck adds the service check to the slice of current service checks
 func (agg *BufferedAggregator) addServiceCheck(sc metrics.ServiceCheck) {
 	if sc.Ts == 0 {
-		sc.Ts = time.Now().Unix()
+		sc.Ts = correctTime(time.Now()).Unix()
 	}
 	tb := tagset.NewHashlessTagsAccumulatorFromSlice(sc.Tags)
 	tagger.EnrichTags(tb, sc.OriginID, sc.K8sOriginID, sc.Cardinality)
@@ -441,7 +495,7 @@ func (agg *BufferedAggregator) addServiceCheck(sc metrics.ServiceCheck) {
 // addEvent adds the event to the slice of current events
 func (agg *BufferedAggregator) addEvent(e metrics.Event) {
 	if e.Ts == 0 {
-		e.Ts = time.Now().Unix()
+		e.Ts = correctTime(time.Now()).Unix()
 	}
 	tb := tagset.NewHashlessTagsAccumulatorFromSlice(e.Tags)
 	tagger.EnrichTags(tb, e.OriginID, e.K8sOriginID, e.Cardinality)
@@ -462,14 +516,20 @@ func (agg *BufferedAggregator) addSample(metricSample *metrics.MetricSample, tim
 // from the time sampler. Metrics and sketches before this timestamp should be returned.
 func (agg *BufferedAggregator) GetSeriesAndSketches(before time.Time) (metrics.Series, metrics.SketchSeriesList) {
 	agg.mu.Lock()
-	defer agg.mu.Unlock()
 
-	series, sketches := agg.statsdSampler.flush(float64(before.UnixNano()) / float64(time.Second))
+	// copy the live contexts before the flush expires them, their tag values are counted
+	// once the lock is released
+	tagCardinalityContexts := tagCardinalityTlm.snapshot(before, &agg.statsdSampler, agg.checkSamplers)
+
+	series, sketches := agg.statsdSampler.flush(float64(correctTime(before).UnixNano()) / float64(time.Second))
 	for _, checkSampler := range agg.checkSamplers {
 		s, sk := checkSampler.flush()
 		series = append(series, s...)
 		sketches = append(sketches, sk...)
 	}
+	agg.mu.Unlock()
+
+	tagCardinalityTlm.count(tagCardinalityContexts)
 	return series, sketches
 }
 
@@ -531,7 +591,7 @@ func (agg *BufferedAggregator) sendSeries(start time.Time, series metrics.Series
 			updatedPoints = append(updatedPoints,
 				metrics.Point{
 					Value: point.Value,
-					Ts:    float64(start.Unix()),
+					Ts:    float64(correctTime(start).Unix()),
 				})
 		}
 		newSerie.Points = updatedPoints
@@ -543,7 +603,7 @@ func (agg *BufferedAggregator) sendSeries(start time.Time, series metrics.Series
 	// a `datadog.`-prefixed metric allows identifying this host as an Agent host, used for dogbone icon)
 	series = append(series, &metrics.Serie{
 		Name:           fmt.Sprintf("datadog.%s.running", agg.agentName),
-		Points:         []metrics.Point{{Value: 1, Ts: float64(start.Unix())}},
+		Points:         []metrics.Point{{Value: 1, Ts: float64(correctTime(start).Unix())}},
 		Tags:           agg.tags(true),
 		Host:           agg.hostname,
 		MType:          metrics.APIGaugeType,
@@ -553,7 +613,7 @@ func (agg *BufferedAggregator) sendSeries(start time.Time, series metrics.Series
 	// Send along a metric that counts the number of times we dropped some payloads because we couldn't split them.
 	series = append(series, &metrics.Serie{
 		Name:           fmt.Sprintf("n_o_i_n_d_e_x.datadog.%s.payload.dropped", agg.agentName),
-		Points:         []metrics.Point{{Value: float64(split.GetPayloadDrops()), Ts: float64(start.Unix())}},
+		Points:         []metrics.Point{{Value: float64(split.GetPayloadDrops()), Ts: float64(correctTime(start).Unix())}},
 		Tags:           agg.tags(false),
 		Host:           agg.hostname,
 		MType:          metrics.APIGaugeType,
@@ -589,11 +649,25 @@ func (agg *BufferedAggregator) sendSketches(start time.Time, sketches metrics.Sk
 	}
 }
 
-func (agg *BufferedAggregator) flushSeriesAndSketches(start time.Time, waitForSerializer bool) {
+// flushSeriesAndSketches drains the samplers and sends the series and the sketches whose flush
+// is due, the other ones are kept until their next flush. Must be called with flushMutex held.
+func (agg *BufferedAggregator) flushSeriesAndSketches(start time.Time, waitForSerializer bool, flushSeries, flushSketches bool) {
 	series, sketches := agg.GetSeriesAndSketches(start)
+	series = append(agg.pendingSeries, series...)
+	sketches = append(agg.pendingSketches, sketches...)
+	agg.pendingSeries, agg.pendingSketches = nil, nil
 
-	agg.sendSketches(start, sketches, waitForSerializer)
-	agg.sendSeries(start, series, waitForSerializer)
+	if flushSketches {
+		agg.sendSketches(start, sketches, waitForSerializer)
+	} else {
+		agg.pendingSketches = sketches
+	}
+	if flushSeries {
+		agg.flushIntervals.stampSeriesInterval(agg.flushInterval, series)
+		agg.sendSeries(start, series, waitForSerializer)
+	} else {
+		agg.pendingSeries = series
+	}
 }
 
 // GetServiceChecks grabs all the service checks from the queue and clears the queue
@@ -702,11 +776,22 @@ func (agg *BufferedAggregator) flushEvents(start time.Time, waitForSerializer bo
 // Flush flushes the data contained in the BufferedAggregator into the Forwarder.
 // This method can be called from multiple routines.
 func (agg *BufferedAggregator) Flush(start time.Time, waitForSerializer bool) {
+	agg.flush(start, waitForSerializer, flushAll)
+}
+
+// flush flushes the given types of data contained in the BufferedAggregator into the Forwarder
+func (agg *BufferedAggregator) flush(start time.Time, waitForSerializer bool, types flushTypes) {
 	agg.flushMutex.Lock()
 	defer agg.flushMutex.Unlock()
-	agg.flushSeriesAndSketches(start, waitForSerializer)
-	agg.flushServiceChecks(start, waitForSerializer)
-	agg.flushEvents(start, waitForSerializer)
+	if types.series || types.sketches {
+		agg.flushSeriesAndSketches(start, waitForSerializer, types.series, types.sketches)
+	}
+	if types.serviceChecks {
+		agg.flushServiceChecks(start, waitForSerializer)
+	}
+	if types.events {
+		agg.flushEvents(start, waitForSerializer)
+	}
 	agg.updateChecksTelemetry()
 }
 
@@ -732,10 +817,99 @@ func (agg *BufferedAggregator) Stop() {
 
 }
 
+// StopWithFinalFlush flushes all the data of the aggregator to the serializer, including the dogstatsd
+// buckets still open as no more samples are expected, then stops it. Like FlushWithDeadline, the run loop
+// processes the inputs already queued before the flush, until half of the time left before the deadline
+// elapsed. It waits for the end of the flush even past the deadline, so that nothing is sent to the
+// forwarder once it returns, and returns false if the deadline is reached before the end of the flush.
+func (agg *BufferedAggregator) StopWithFinalFlush(deadline time.Time) bool {
+	req := flushRequest{
+		flushBy: time.Now().Add(time.Until(deadline) / 2),
+		done:    make(chan struct{}),
+	}
+	agg.flushRequests <- req
+	<-req.done
+	agg.stopChan <- struct{}{}
+
+	if time.Now().After(deadline) {
+		log.Errorf("final flush of the aggregator ended after the shutdown deadline")
+		return false
+	}
+	return true
+}
+
+// flushRequest is a synchronous flush requested with FlushWithDeadline
+type flushRequest struct {
+	// flushBy is the time after which the flush starts even if inputs are still queued,
+	// zero when the request has no deadline
+	flushBy time.Time
+	done    chan struct{}
+}
+
+// flushRequestsDue returns whether the requested flushes can start: once the input queues are
+// drained, or when the flushBy time of a request is reached
+func (agg *BufferedAggregator) flushRequestsDue(requests []flushRequest, now time.Time) bool {
+	if agg.pendingInputs() == 0 {
+		return true
+	}
+	for _, req := range requests {
+		if !req.flushBy.IsZero() && !now.Before(req.flushBy) {
+			return true
+		}
+	}
+	return false
+}
+
+// FlushWithDeadline synchronously flushes all the data of the aggregator to the serializer, including
+// the dogstatsd buckets still open, and waits for the serializer to send the payloads. The run loop
+// processes the inputs already queued before the flush, until half of the time left before the deadline
+// of the context elapsed. It returns the error of the context if it's done before the end of the flush,
+// in which case the flush goes on in the background.
+func (agg *BufferedAggregator) FlushWithDeadline(ctx context.Context) error {
+	req := flushRequest{done: make(chan struct{})}
+	if deadline, ok := ctx.Deadline(); ok {
+		// the other half is left to the flush itself
+		req.flushBy = time.Now().Add(time.Until(deadline) / 2)
+	}
+
+	select {
+	case agg.flushRequests <- req:
+	case <-ctx.Done():
+		log.Errorf("timed out requesting a flush of the aggregator")
+		return ctx.Err()
+	}
+
+	select {
+	case <-req.done:
+		return nil
+	case <-ctx.Done():
+		log.Errorf("timed out waiting for the end of the aggregator flush")
+		return ctx.Err()
+	}
+}
+
+// flushOnRequest flushes the aggregator for the requests of FlushWithDeadline
+func (agg *BufferedAggregator) flushOnRequest(requests []flushRequest) {
+	start := time.Now()
+	// flush the aggregator to have the serializer/forwarder send data to the backend.
+	// We add the bucket interval to ensure that we're getting the buckets still open
+	agg.Flush(start.Add(time.Duration(agg.statsdSampler.interval)*time.Second), true)
+	addFlushTime("MainFlushTime", int64(time.Since(start)))
+	aggregatorNumberOfFlush.Add(1)
+	for _, req := range requests {
+		close(req.done)
+	}
+}
+
 func (agg *BufferedAggregator) run() {
+	flushTick := agg.flushInterval
+	if agg.flushInterval != 0 {
+		flushTick = agg.flushIntervals.tick()
+		agg.flushIntervals.logIfCustom(agg.flushInterval)
+	}
 	if agg.TickerChan == nil {
 		if agg.flushInterval != 0 {
-			agg.TickerChan = time.NewTicker(agg.flushInterval).C
+			agg.TickerChan = time.NewTicker(flushTick).C
 		} else {
 			log.Debugf("aggregator flushInterval set to 0: aggregator won't flush data")
 		}
@@ -744,7 +918,16 @@ func (agg *BufferedAggregator) run() {
 	// ensures event platform errors are logged at most once per flush
 	aggregatorEventPlatformErrorLogged := false
 
+	// the flushes requested while inputs are still queued, they're handled once these inputs are processed
+	var flushRequests []flushRequest
+
 	for {
+		if len(flushRequests) > 0 && agg.flushRequestsDue(flushRequests, time.Now()) {
+			agg.flushOnRequest(flushRequests)
+			flushRequests = nil
+			aggregatorEventPlatformErrorLogged = false
+		}
+
 		select {
 		case <-agg.stopChan:
 			log.Info("Stopping aggregator")
@@ -752,19 +935,13 @@ func (agg *BufferedAggregator) run() {
 		case <-agg.health.C:
 		case <-agg.TickerChan:
 			start := time.Now()
-			agg.Flush(start, false)
-			addFlushTime("MainFlushTime", int64(time.Since(start)))
-			aggregatorNumberOfFlush.Add(1)
-			aggregatorEventPlatformErrorLogged = false
-		case <-agg.ServerlessFlush:
-			start := time.Now()
-			// flush the aggregator to have the serializer/forwarder send data to the backend.
-			// We add 10 seconds to the interval to ensure that we're getting the whole sketches bucket
-			agg.Flush(start.Add(time.Second*10), true)
+			agg.flushTicks++
+			agg.flush(start, false, agg.flushIntervals.due(flushTick, agg.flushTicks))
 			addFlushTime("MainFlushTime", int64(time.Since(start)))
 			aggregatorNumberOfFlush.Add(1)
 			aggregatorEventPlatformErrorLogged = false
-			agg.ServerlessFlushDone <- struct{}{}
+		case req := <-agg.flushRequests:
+			flushRequests = append(flushRequests, req)
 		case checkMetric := <-agg.checkMetricIn:
 			aggregatorChecksMetricSample.Add(1)
 			tlmProcessed.Inc("metrics")
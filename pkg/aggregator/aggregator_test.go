@@ -9,6 +9,7 @@ package aggregator
 
 import (
 	// stdlib
+	"context"
 	"errors"
 	"expvar"
 	"fmt"
@@ -299,6 +300,82 @@ func TestDistributionsTooManyTags(t *testing.T) {
 	t.Run("huge", test(110))
 }
 
+func TestFlushWithDeadline(t *testing.T) {
+	t.Run("flushed", func(t *testing.T) {
+		resetAggregator()
+		s := &serializer.MockSerializer{}
+		agg := InitAggregator(s, nil, "hostname")
+
+		var flushedSeries metrics.Series
+		s.On("SendServiceChecks", mock.Anything).Return(nil).Times(1)
+		s.On("SendSeries", mock.Anything).Return(nil).Times(1).Run(func(args mock.Arguments) {
+			flushedSeries = args.Get(0).(metrics.Series)
+		})
+
+		// the sample still queued is processed and its open bucket flushed
+		agg.metricIn <- &metrics.MetricSample{Name: "my.gauge", Value: 1, Mtype: metrics.GaugeType, SampleRate: 1}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		require.NoError(t, agg.FlushWithDeadline(ctx))
+		s.AssertExpectations(t)
+
+		var names []string
+		for _, serie := range flushedSeries {
+			names = append(names, serie.Name)
+		}
+		assert.Contains(t, names, "my.gauge")
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		// the run loop of the aggregator isn't started, the flush can't happen
+		agg := NewBufferedAggregator(nil, nil, "hostname", DefaultFlushInterval)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.Equal(t, context.DeadlineExceeded, agg.FlushWithDeadline(ctx))
+	})
+
+	t.Run("due", func(t *testing.T) {
+		agg := NewBufferedAggregator(nil, nil, "hostname", DefaultFlushInterval)
+		now := time.Now()
+		noDeadline := []flushRequest{{}}
+		withDeadline := []flushRequest{{}, {flushBy: now.Add(time.Second)}}
+
+		assert.True(t, agg.flushRequestsDue(noDeadline, now))
+
+		// the queued inputs are processed first, until the flushBy time of a request
+		agg.metricIn <- &metrics.MetricSample{Name: "my.gauge", Value: 1, Mtype: metrics.GaugeType, SampleRate: 1}
+		assert.False(t, agg.flushRequestsDue(noDeadline, now))
+		assert.False(t, agg.flushRequestsDue(withDeadline, now))
+		assert.True(t, agg.flushRequestsDue(withDeadline, now.Add(time.Second)))
+	})
+}
+
+func TestStopWithFinalFlush(t *testing.T) {
+	s := &serializer.MockSerializer{}
+	agg := NewBufferedAggregator(s, nil, "hostname", DefaultFlushInterval)
+	go agg.run()
+
+	var flushedSeries metrics.Series
+	s.On("SendServiceChecks", mock.Anything).Return(nil).Times(1)
+	s.On("SendSeries", mock.Anything).Return(nil).Times(1).Run(func(args mock.Arguments) {
+		flushedSeries = args.Get(0).(metrics.Series)
+	})
+
+	// the sample still queued is processed and its open bucket flushed before stopping
+	agg.metricIn <- &metrics.MetricSample{Name: "my.gauge", Value: 1, Mtype: metrics.GaugeType, SampleRate: 1}
+
+	assert.True(t, agg.StopWithFinalFlush(time.Now().Add(5*time.Second)))
+	s.AssertExpectations(t)
+
+	var names []string
+	for _, serie := range flushedSeries {
+		names = append(names, serie.Name)
+	}
+	assert.Contains(t, names, "my.gauge")
+}
+
 func TestRecurentSeries(t *testing.T) {
 	resetAggregator()
 	s := &serializer.MockSerializer{}
@@ -0,0 +1,96 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"encoding/json"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/ckey"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/persistentcache"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// checkBaselinesCachePrefix is the directory of the run path storing the baselines of the checks
+const checkBaselinesCachePrefix = "check_cache"
+
+// checkBaselines persists the baselines of the rates and monotonic counts of a
+// check in the run path, so that they're flushed on the first run following an
+// agent restart instead of waiting for a second sample.
+type checkBaselines struct {
+	cacheKey string
+	maxAge   float64
+	restored bool
+}
+
+// newCheckBaselines returns the baselines store of a check, nil when
+// `check_cache.enabled` is disabled.
+func newCheckBaselines(id check.ID) *checkBaselines {
+	if id == "" || !config.Datadog.GetBool("check_cache.enabled") {
+		return nil
+	}
+	return &checkBaselines{
+		cacheKey: checkBaselinesCachePrefix + ":" + string(id),
+		maxAge:   config.Datadog.GetDuration("check_cache.max_age").Seconds(),
+	}
+}
+
+// load returns the persisted baselines that are more recent than the max age
+func (b *checkBaselines) load(timestamp float64) map[ckey.ContextKey]metrics.Baseline {
+	content, err := persistentcache.Read(b.cacheKey)
+	if err != nil {
+		log.Debugf("Couldn't read the baselines of %s: %s", b.cacheKey, err)
+		return nil
+	}
+	if content == "" {
+		return nil
+	}
+
+	var baselines map[ckey.ContextKey]metrics.Baseline
+	if err := json.Unmarshal([]byte(content), &baselines); err != nil {
+		log.Warnf("Ignoring the invalid baselines of %s: %s", b.cacheKey, err)
+		return nil
+	}
+	for key, baseline := range baselines {
+		if b.maxAge > 0 && timestamp-baseline.Timestamp > b.maxAge {
+			delete(baselines, key)
+		}
+	}
+	return baselines
+}
+
+// save persists the baselines of the check
+func (b *checkBaselines) save(baselines map[ckey.ContextKey]metrics.Baseline) {
+	content, err := json.Marshal(baselines)
+	if err != nil {
+		log.Debugf("Couldn't marshal the baselines of %s: %s", b.cacheKey, err)
+		return
+	}
+	if err := persistentcache.Write(b.cacheKey, string(content)); err != nil {
+		log.Debugf("Couldn't persist the baselines of %s: %s", b.cacheKey, err)
+	}
+}
+
+// beforeCommit restores the persisted baselines on the first commit of the check
+func (b *checkBaselines) beforeCommit(cm *metrics.CheckMetrics, timestamp float64) {
+	if b == nil || b.restored {
+		return
+	}
+	b.restored = true
+	if baselines := b.load(timestamp); len(baselines) > 0 {
+		cm.RestoreBaselines(baselines)
+	}
+}
+
+// afterCommit persists the baselines left by the flush of the commit
+func (b *checkBaselines) afterCommit(cm *metrics.CheckMetrics, timestamp float64) {
+	if b == nil {
+		return
+	}
+	b.save(cm.Baselines(timestamp))
+}
@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestCheckBaselinesDisabled(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("check_cache.enabled", false)
+	assert.Nil(t, newCheckBaselines("io"))
+
+	mockConfig.Set("check_cache.enabled", true)
+	assert.Nil(t, newCheckBaselines(""))
+	assert.NotNil(t, newCheckBaselines("io"))
+}
+
+func TestCheckBaselinesAcrossRestarts(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("run_path", t.TempDir())
+	mockConfig.Set("check_cache.enabled", true)
+	mockConfig.Set("check_cache.max_age", 15*time.Minute)
+
+	newSampler := func(id check.ID) *CheckSampler {
+		cs := newCheckSampler(1, true, time.Hour)
+		cs.baselines = newCheckBaselines(id)
+		return cs
+	}
+	samples := func(ts, value float64) []*metrics.MetricSample {
+		return []*metrics.MetricSample{
+			{Name: "my.rate", Value: value, Mtype: metrics.RateType, Tags: []string{"foo"}, SampleRate: 1, Timestamp: ts},
+			{Name: "my.count", Value: value, Mtype: metrics.MonotonicCountType, Tags: []string{"foo"}, SampleRate: 1, Timestamp: ts},
+		}
+	}
+	run := func(cs *CheckSampler, ts, value float64) map[string]float64 {
+		for _, sample := range samples(ts, value) {
+			cs.addSample(sample)
+		}
+		cs.commit(ts)
+		series, _ := cs.flush()
+		values := make(map[string]float64)
+		for _, serie := range series {
+			values[serie.Name] = serie.Points[0].Value
+		}
+		return values
+	}
+
+	// first run ever: nothing can be computed
+	assert.Empty(t, run(newSampler("io"), 1000, 10))
+
+	// after a restart, the first run is completed by the persisted baselines
+	cs := newSampler("io")
+	assert.Equal(t, map[string]float64{"my.rate": 2, "my.count": 20}, run(cs, 1010, 30))
+	assert.Equal(t, map[string]float64{"my.rate": 1, "my.count": 10}, run(cs, 1020, 40))
+
+	// the baselines aren't shared between checks
+	assert.Empty(t, run(newSampler("other"), 1030, 50))
+
+	// baselines older than check_cache.max_age are ignored
+	assert.Empty(t, run(newSampler("io"), 1020+16*60, 100))
+
+	// the baselines aren't persisted when disabled
+	cs = newCheckSampler(1, true, time.Hour)
+	run(cs, 1990, 1000)
+	assert.Equal(t, map[string]float64{"my.rate": 10, "my.count": 300}, run(newSampler("io"), 2010, 400))
+}
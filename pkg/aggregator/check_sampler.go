@@ -24,6 +24,8 @@ type CheckSampler struct {
 	metrics         metrics.CheckMetrics
 	sketchMap       sketchMap
 	lastBucketValue map[ckey.ContextKey]int64
+	// baselines persists the baselines of the rates and monotonic counts, nil when disabled
+	baselines *checkBaselines
 }
 
 // newCheckSampler returns a newly initialized CheckSampler
@@ -40,6 +42,19 @@ func newCheckSampler(expirationCount int, expireMetrics bool, statefulTimeout ti
 
 func (cs *CheckSampler) addSample(metricSample *metrics.MetricSample) {
 	contextKey := cs.contextResolver.trackContext(metricSample)
+	if metricSample.Metadata != nil {
+		if context, ok := cs.contextResolver.get(contextKey); ok {
+			context.Metadata = metricSample.Metadata
+		}
+	}
+
+	// distributions are aggregated in sketches, like the histogram buckets
+	if metricSample.Mtype == metrics.DistributionType {
+		if !cs.sketchMap.insert(int64(metricSample.Timestamp), contextKey, metricSample.Value, metricSample.SampleRate) {
+			log.Debugf("Ignoring distribution sample '%s' on host '%s' and tags '%s': sample with value '%v'", metricSample.Name, metricSample.Host, metricSample.Tags, metricSample.Value)
+		}
+		return
+	}
 
 	if err := cs.metrics.AddSample(contextKey, metricSample, metricSample.Timestamp, 1); err != nil {
 		log.Debugf("Ignoring sample '%s' on host '%s' and tags '%s': %s", metricSample.Name, metricSample.Host, metricSample.Tags, err)
@@ -139,6 +154,10 @@ func (cs *CheckSampler) commitSeries(timestamp float64) {
 		serie.Tags = context.Tags
 		serie.Host = context.Host
 		serie.SourceTypeName = checksSourceTypeName // this source type is required for metrics coming from the checks
+		if context.Metadata != nil {
+			serie.Unit = context.Metadata.Unit
+			serie.Resources = context.Metadata.Resources
+		}
 
 		cs.series = append(cs.series, serie)
 	}
@@ -159,7 +178,9 @@ func (cs *CheckSampler) commitSketches(timestamp float64) {
 }
 
 func (cs *CheckSampler) commit(timestamp float64) {
+	cs.baselines.beforeCommit(&cs.metrics, timestamp)
 	cs.commitSeries(timestamp)
+	cs.baselines.afterCommit(&cs.metrics, timestamp)
 	cs.commitSketches(timestamp)
 
 	cs.metrics.RemoveExpired(timestamp)
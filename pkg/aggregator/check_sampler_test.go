@@ -90,6 +90,45 @@ func TestCheckGaugeSampling(t *testing.T) {
 	metrics.AssertSeriesEqual(t, expectedSeries, series)
 }
 
+func TestCheckSamplingMetadata(t *testing.T) {
+	checkSampler := newCheckSampler(1, true, 1*time.Second)
+	metadata := &metrics.SerieMetadata{
+		Unit:      "byte",
+		Resources: []metrics.Resource{{Type: "container", Name: "abcdef"}},
+	}
+
+	checkSampler.addSample(&metrics.MetricSample{
+		Name:       "container.memory.usage",
+		Value:      1024,
+		Mtype:      metrics.GaugeType,
+		Tags:       []string{"foo"},
+		SampleRate: 1,
+		Timestamp:  12345.0,
+		Metadata:   metadata,
+	})
+	checkSampler.addSample(&metrics.MetricSample{
+		Name:       "my.metric.name",
+		Value:      1,
+		Mtype:      metrics.GaugeType,
+		Tags:       []string{"foo"},
+		SampleRate: 1,
+		Timestamp:  12345.0,
+	})
+
+	checkSampler.commit(12349.0)
+	series, _ := checkSampler.flush()
+	require.Len(t, series, 2)
+	for _, serie := range series {
+		if serie.Name == "container.memory.usage" {
+			assert.Equal(t, "byte", serie.Unit)
+			assert.Equal(t, metadata.Resources, serie.Resources)
+		} else {
+			assert.Empty(t, serie.Unit)
+			assert.Empty(t, serie.Resources)
+		}
+	}
+}
+
 func TestCheckRateSampling(t *testing.T) {
 	checkSampler := newCheckSampler(1, true, 1*time.Second)
 
@@ -273,6 +312,55 @@ func TestCheckHistogramBucketSampling(t *testing.T) {
 	assert.Equal(t, len(checkSampler.lastBucketValue), 0)
 }
 
+func TestCheckDistributionSampling(t *testing.T) {
+	checkSampler := newCheckSampler(1, true, 1*time.Second)
+
+	mSample1 := metrics.MetricSample{
+		Name:       "my.distribution",
+		Value:      1,
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"foo", "bar"},
+		SampleRate: 1,
+		Timestamp:  12345.0,
+	}
+	mSample2 := metrics.MetricSample{
+		Name:       "my.distribution",
+		Value:      2,
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"foo", "bar"},
+		SampleRate: 1,
+		Timestamp:  12345.5,
+	}
+	mSample3 := metrics.MetricSample{
+		Name:       "my.distribution",
+		Value:      math.NaN(),
+		Mtype:      metrics.DistributionType,
+		Tags:       []string{"foo", "bar"},
+		SampleRate: 1,
+		Timestamp:  12345.5,
+	}
+	checkSampler.addSample(&mSample1)
+	checkSampler.addSample(&mSample2)
+	checkSampler.addSample(&mSample3)
+
+	checkSampler.commit(12349.0)
+	series, sketches := checkSampler.flush()
+	assert.Len(t, series, 0)
+	require.Len(t, sketches, 1)
+
+	expSketch := &quantile.Sketch{}
+	expSketch.Insert(quantile.Default(), 1, 2)
+
+	metrics.AssertSketchSeriesApproxEqual(t, metrics.SketchSeries{
+		Name: "my.distribution",
+		Tags: []string{"foo", "bar"},
+		Points: []metrics.SketchPoint{
+			{Ts: 12345.0, Sketch: expSketch},
+		},
+		ContextKey: generateContextKey(&mSample1),
+	}, sketches[0], .01)
+}
+
 func TestCheckHistogramBucketDontFlushFirstValue(t *testing.T) {
 	checkSampler := newCheckSampler(1, true, 1*time.Second)
 
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	// clockOffsetNano is the offset applied to the timestamps generated by the
+	// aggregator, in nanoseconds. Access must be atomic.
+	clockOffsetNano int64
+
+	tlmClockOffset = telemetry.NewGauge("aggregator", "clock_offset_correction",
+		nil, "Offset in seconds applied to the timestamps generated by the aggregator")
+	tlmClockOffsetCapped = telemetry.NewCounter("aggregator", "clock_offset_capped",
+		nil, "Count of measured clock offsets capped to clock_offset_correction.max_offset")
+)
+
+// SetClockOffset sets the offset of the local clock measured by the ntp check,
+// so that the timestamps generated by the aggregator are corrected by it when
+// `clock_offset_correction.enabled` is set. Offsets lower than
+// `clock_offset_correction.min_offset` are not corrected, and offsets are
+// capped to `clock_offset_correction.max_offset`. It returns the applied offset.
+func SetClockOffset(offset time.Duration) time.Duration {
+	if !config.Datadog.GetBool("clock_offset_correction.enabled") {
+		offset = 0
+	}
+
+	minOffset := config.Datadog.GetDuration("clock_offset_correction.min_offset")
+	maxOffset := config.Datadog.GetDuration("clock_offset_correction.max_offset")
+	switch {
+	case absDuration(offset) < minOffset:
+		offset = 0
+	case maxOffset > 0 && offset > maxOffset:
+		log.Warnf("Clock offset %s is higher than clock_offset_correction.max_offset, correcting timestamps by %s only", offset, maxOffset)
+		tlmClockOffsetCapped.Inc()
+		offset = maxOffset
+	case maxOffset > 0 && offset < -maxOffset:
+		log.Warnf("Clock offset %s is higher than clock_offset_correction.max_offset, correcting timestamps by %s only", offset, -maxOffset)
+		tlmClockOffsetCapped.Inc()
+		offset = -maxOffset
+	}
+
+	if previous := time.Duration(atomic.SwapInt64(&clockOffsetNano, int64(offset))); previous != offset {
+		log.Infof("Correcting the timestamps generated by the aggregator by %s", offset)
+	}
+	tlmClockOffset.Set(offset.Seconds())
+	return offset
+}
+
+// clockOffset returns the offset applied to the timestamps generated by the aggregator
+func clockOffset() time.Duration {
+	return time.Duration(atomic.LoadInt64(&clockOffsetNano))
+}
+
+// correctTime applies the clock offset to t
+func correctTime(t time.Time) time.Time {
+	return t.Add(clockOffset())
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
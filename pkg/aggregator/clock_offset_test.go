@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestSetClockOffset(t *testing.T) {
+	mockConfig := config.Mock()
+	defer SetClockOffset(0)
+
+	// disabled by default
+	assert.Equal(t, time.Duration(0), SetClockOffset(10*time.Minute))
+	assert.Equal(t, time.Duration(0), clockOffset())
+
+	mockConfig.Set("clock_offset_correction.enabled", true)
+	mockConfig.Set("clock_offset_correction.min_offset", time.Second)
+	mockConfig.Set("clock_offset_correction.max_offset", time.Hour)
+
+	assert.Equal(t, 10*time.Minute, SetClockOffset(10*time.Minute))
+	assert.Equal(t, 10*time.Minute, clockOffset())
+	now := time.Now()
+	assert.Equal(t, now.Add(10*time.Minute), correctTime(now))
+
+	assert.Equal(t, -5*time.Minute, SetClockOffset(-5*time.Minute))
+	assert.Equal(t, -5*time.Minute, clockOffset())
+
+	// small offsets aren't corrected
+	assert.Equal(t, time.Duration(0), SetClockOffset(500*time.Millisecond))
+	assert.Equal(t, time.Duration(0), clockOffset())
+
+	// offsets are capped
+	assert.Equal(t, time.Hour, SetClockOffset(3*time.Hour))
+	assert.Equal(t, -time.Hour, SetClockOffset(-3*time.Hour))
+	assert.Equal(t, -time.Hour, clockOffset())
+}
+
+func TestClockOffsetServiceCheckTimestamp(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("clock_offset_correction.enabled", true)
+	defer SetClockOffset(0)
+	SetClockOffset(30 * time.Minute)
+
+	s := initSender("1", "")
+	s.sender.ServiceCheck("my_service.can_connect", 0, "", nil, "")
+	sc := <-s.serviceCheckChan
+	assert.InDelta(t, time.Now().Add(30*time.Minute).Unix(), sc.Ts, 2)
+}
@@ -18,6 +18,8 @@ type Context struct {
 	Name string
 	Tags []string
 	Host string
+	// Metadata is the optional metadata of the timeseries, it isn't part of the context key
+	Metadata *metrics.SerieMetadata
 }
 
 // contextResolver allows tracking and expiring contexts
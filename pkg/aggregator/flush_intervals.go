@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// FlushIntervals are the intervals at which each type of data is flushed by the aggregator
+type FlushIntervals struct {
+	Series        time.Duration
+	Sketches      time.Duration
+	ServiceChecks time.Duration
+	Events        time.Duration
+}
+
+// flushTypes are the types of data flushed by a flush of the aggregator
+type flushTypes struct {
+	series        bool
+	sketches      bool
+	serviceChecks bool
+	events        bool
+}
+
+var flushAll = flushTypes{series: true, sketches: true, serviceChecks: true, events: true}
+
+// flushIntervalsFromConfig returns the flush intervals set by `aggregator_flush_intervals`,
+// the unset ones default to flushInterval
+func flushIntervalsFromConfig(flushInterval time.Duration) FlushIntervals {
+	interval := func(key string) time.Duration {
+		seconds := config.Datadog.GetInt("aggregator_flush_intervals." + key)
+		if seconds <= 0 {
+			return flushInterval
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	return FlushIntervals{
+		Series:        interval("series"),
+		Sketches:      interval("sketches"),
+		ServiceChecks: interval("service_checks"),
+		Events:        interval("events"),
+	}
+}
+
+// tick returns the interval of the flush ticker, so that every data type is flushed on one of
+// its ticks: the greatest common divisor of the flush intervals
+func (f FlushIntervals) tick() time.Duration {
+	gcd := func(a, b time.Duration) time.Duration {
+		for b != 0 {
+			a, b = b, a%b
+		}
+		return a
+	}
+	return gcd(gcd(f.Series, f.Sketches), gcd(f.ServiceChecks, f.Events))
+}
+
+// due returns the types of data to flush on the n-th tick of the flush ticker,
+// everything is flushed on every tick when the intervals aren't set
+func (f FlushIntervals) due(tick time.Duration, n int64) flushTypes {
+	isDue := func(interval time.Duration) bool {
+		if tick <= 0 || interval <= tick {
+			return true
+		}
+		return n%int64(interval/tick) == 0
+	}
+	return flushTypes{
+		series:        isDue(f.Series),
+		sketches:      isDue(f.Sketches),
+		serviceChecks: isDue(f.ServiceChecks),
+		events:        isDue(f.Events),
+	}
+}
+
+// stampSeriesInterval sets the interval of the series flushed at a custom interval which don't have one,
+// like the series of the checks, so that their counts and rates are normalized with the right interval
+func (f FlushIntervals) stampSeriesInterval(flushInterval time.Duration, series metrics.Series) {
+	if f.Series == flushInterval || f.Series < time.Second {
+		return
+	}
+	interval := int64(f.Series / time.Second)
+	for _, serie := range series {
+		if serie.Interval == 0 {
+			serie.Interval = interval
+		}
+	}
+}
+
+// logIfCustom logs the flush intervals when they differ from the main flush interval
+func (f FlushIntervals) logIfCustom(flushInterval time.Duration) {
+	if f == (FlushIntervals{flushInterval, flushInterval, flushInterval, flushInterval}) {
+		return
+	}
+	log.Infof("Aggregator flush intervals: series %s, sketches %s, service checks %s, events %s",
+		f.Series, f.Sketches, f.ServiceChecks, f.Events)
+}
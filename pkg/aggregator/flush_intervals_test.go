@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/serializer"
+)
+
+func TestFlushIntervalsFromConfig(t *testing.T) {
+	mockConfig := config.Mock()
+	defer mockConfig.Set("aggregator_flush_intervals.series", 0)
+	defer mockConfig.Set("aggregator_flush_intervals.service_checks", 0)
+
+	assert.Equal(t, FlushIntervals{
+		Series:        15 * time.Second,
+		Sketches:      15 * time.Second,
+		ServiceChecks: 15 * time.Second,
+		Events:        15 * time.Second,
+	}, flushIntervalsFromConfig(15*time.Second))
+
+	mockConfig.Set("aggregator_flush_intervals.series", 60)
+	mockConfig.Set("aggregator_flush_intervals.service_checks", 10)
+	assert.Equal(t, FlushIntervals{
+		Series:        60 * time.Second,
+		Sketches:      15 * time.Second,
+		ServiceChecks: 10 * time.Second,
+		Events:        15 * time.Second,
+	}, flushIntervalsFromConfig(15*time.Second))
+}
+
+func TestFlushIntervalsDue(t *testing.T) {
+	f := FlushIntervals{
+		Series:        60 * time.Second,
+		Sketches:      15 * time.Second,
+		ServiceChecks: 10 * time.Second,
+		Events:        15 * time.Second,
+	}
+	tick := f.tick()
+	require.Equal(t, 5*time.Second, tick)
+
+	flushed := map[string][]int64{}
+	for n := int64(1); n <= 12; n++ {
+		due := f.due(tick, n)
+		if due.series {
+			flushed["series"] = append(flushed["series"], n)
+		}
+		if due.sketches {
+			flushed["sketches"] = append(flushed["sketches"], n)
+		}
+		if due.serviceChecks {
+			flushed["service_checks"] = append(flushed["service_checks"], n)
+		}
+		if due.events {
+			flushed["events"] = append(flushed["events"], n)
+		}
+	}
+	assert.Equal(t, map[string][]int64{
+		"series":         {12},
+		"sketches":       {3, 6, 9, 12},
+		"service_checks": {2, 4, 6, 8, 10, 12},
+		"events":         {3, 6, 9, 12},
+	}, flushed)
+
+	// the default intervals flush everything on every tick
+	f = FlushIntervals{DefaultFlushInterval, DefaultFlushInterval, DefaultFlushInterval, DefaultFlushInterval}
+	assert.Equal(t, DefaultFlushInterval, f.tick())
+	assert.Equal(t, flushAll, f.due(f.tick(), 1))
+
+	// the aggregator doesn't flush with a 0 interval, but a ticker can still be injected
+	f = FlushIntervals{}
+	assert.Zero(t, f.tick())
+	assert.Equal(t, flushAll, f.due(f.tick(), 1))
+}
+
+func TestStampSeriesInterval(t *testing.T) {
+	series := metrics.Series{
+		&metrics.Serie{Name: "check.metric"},
+		&metrics.Serie{Name: "dogstatsd.metric", Interval: bucketSize},
+	}
+
+	// the default series flush interval doesn't change the payloads
+	f := FlushIntervals{Series: DefaultFlushInterval}
+	f.stampSeriesInterval(DefaultFlushInterval, series)
+	assert.Zero(t, series[0].Interval)
+
+	f = FlushIntervals{Series: 60 * time.Second}
+	f.stampSeriesInterval(DefaultFlushInterval, series)
+	assert.Equal(t, int64(60), series[0].Interval)
+	assert.Equal(t, int64(bucketSize), series[1].Interval)
+}
+
+func TestFlushPendingSeries(t *testing.T) {
+	resetAggregator()
+	s := &serializer.MockSerializer{}
+	agg := NewBufferedAggregator(s, nil, "hostname", DefaultFlushInterval)
+	start := time.Now()
+
+	agg.addSample(&metrics.MetricSample{
+		Name:  "test.gauge",
+		Value: 13.0,
+		Mtype: metrics.GaugeType,
+		Host:  agg.hostname,
+	}, float64(start.Unix()-20))
+
+	// the series aren't due, they are kept until their next flush
+	s.On("SendServiceChecks", mock.Anything).Return(nil).Times(2)
+	agg.flush(start, true, flushTypes{sketches: true, serviceChecks: true})
+	s.AssertNotCalled(t, "SendSeries")
+	require.Len(t, agg.pendingSeries, 1)
+
+	// the points keep the interval of the dogstatsd buckets and not the one of the series flush
+	s.On("SendSeries", mock.MatchedBy(func(series metrics.Series) bool {
+		for _, serie := range series {
+			if serie.Name == "test.gauge" {
+				return serie.Interval == bucketSize && len(serie.Points) == 1
+			}
+		}
+		return false
+	})).Return(nil).Times(1)
+	agg.flush(start, true, flushAll)
+	assert.Empty(t, agg.pendingSeries)
+	s.AssertExpectations(t)
+}
@@ -6,6 +6,9 @@
 package mocksender
 
 import (
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +27,43 @@ func (m *MockSender) AssertServiceCheck(t *testing.T, checkName string, status m
 	return okCall && notOkCalls
 }
 
+// AssertServiceCheckData allows to assert a ServiceCheck was emitted with given structured data.
+func (m *MockSender) AssertServiceCheckData(t *testing.T, checkName string, status metrics.ServiceCheckStatus, data metrics.ServiceCheckData) bool {
+	m.serviceChecksDataLock.Lock()
+	defer m.serviceChecksDataLock.Unlock()
+
+	var submitted []metrics.ServiceCheckData
+	for _, sc := range m.serviceChecksData {
+		if sc.checkName != checkName || sc.status != status {
+			continue
+		}
+		if assert.ObjectsAreEqual(data, sc.data) {
+			return true
+		}
+		submitted = append(submitted, sc.data)
+	}
+	return assert.Fail(t, "Service check data not found", "Expected %s:%s with data %v, submitted with %v", checkName, status, data, submitted)
+}
+
+// AssertMetricMetadata allows to assert a metric was emitted with given metadata.
+// Additional tags over the ones specified don't make it fail
+func (m *MockSender) AssertMetricMetadata(t *testing.T, metric string, tags []string, metadata metrics.SerieMetadata) bool {
+	m.metricsMetadataLock.Lock()
+	defer m.metricsMetadataLock.Unlock()
+
+	var submitted []metrics.SerieMetadata
+	for _, mm := range m.metricsMetadata {
+		if mm.metric != metric || !expectedInActual(tags, mm.tags) {
+			continue
+		}
+		if assert.ObjectsAreEqual(metadata, mm.metadata) {
+			return true
+		}
+		submitted = append(submitted, mm.metadata)
+	}
+	return assert.Fail(t, "Metric metadata not found", "Expected %s %v with metadata %v, submitted with %v", metric, tags, metadata, submitted)
+}
+
 // AssertMetric allows to assert a metric was emitted with given parameters.
 // Additional tags over the ones specified don't make it fail
 func (m *MockSender) AssertMetric(t *testing.T, method string, metric string, value float64, hostname string, tags []string) bool {
@@ -48,6 +88,40 @@ func (m *MockSender) AssertMetricInRange(t *testing.T, method string, metric str
 	return m.Mock.AssertCalled(t, method, metric, AssertFloatInRange(min, max), hostname, MatchTagsContains(tags))
 }
 
+// AssertMetricWithTagsSubset allows to assert a metric was emitted with given parameters.
+// The tags are a subset of the emitted ones, their order doesn't matter. On failure, the
+// calls submitted for the metric are listed.
+func (m *MockSender) AssertMetricWithTagsSubset(t *testing.T, method string, metric string, value float64, hostname string, tags []string) bool {
+	return m.assertMetricCall(t, method, metric, hostname, tags, fmt.Sprintf("value %v", value), func(actual float64) bool {
+		return actual == value
+	})
+}
+
+// AssertMetricInDelta allows to assert a metric was emitted with given parameters, with a value
+// within delta of the expected one. Additional tags over the ones specified don't make it fail.
+func (m *MockSender) AssertMetricInDelta(t *testing.T, method string, metric string, value float64, delta float64, hostname string, tags []string) bool {
+	return m.assertMetricCall(t, method, metric, hostname, tags, fmt.Sprintf("value %v±%v", value, delta), func(actual float64) bool {
+		return floatInDelta(value, actual, delta)
+	})
+}
+
+func (m *MockSender) assertMetricCall(t *testing.T, method string, metric string, hostname string, tags []string, expectedValue string, matchValue func(float64) bool) bool {
+	calls := m.MetricCalls(method, metric)
+	for _, c := range calls {
+		if c.Hostname == hostname && expectedInActual(tags, c.Tags) && matchValue(c.Value) {
+			return true
+		}
+	}
+
+	submitted := make([]string, 0, len(calls))
+	for _, c := range calls {
+		submitted = append(submitted, c.String())
+	}
+	return assert.Fail(t, "Metric not found",
+		"Expected %s(%s) with %s, hostname %q and tags %v, submitted:\n%s",
+		method, metric, expectedValue, hostname, tags, strings.Join(submitted, "\n"))
+}
+
 // AssertMetricTaggedWith allows to assert a metric was emitted with given tags, value and hostname not tested.
 // Additional tags over the ones specified don't make it fail
 func (m *MockSender) AssertMetricTaggedWith(t *testing.T, method string, metric string, tags []string) bool {
@@ -70,6 +144,11 @@ func (m *MockSender) AssertEventPlatformEvent(t *testing.T, expectedRawEvent str
 	return m.Mock.AssertCalled(t, "EventPlatformEvent", expectedRawEvent, expectedEventType)
 }
 
+// AssertTryEventPlatformEvent assert the expected event was submitted through TryEventPlatformEvent with the following values
+func (m *MockSender) AssertTryEventPlatformEvent(t *testing.T, expectedRawEvent string, expectedEventType string) bool {
+	return m.Mock.AssertCalled(t, "TryEventPlatformEvent", expectedRawEvent, expectedEventType)
+}
+
 // AssertEventMissing assert the expectedEvent was never emitted with the following values:
 // AggregationKey, Priority, SourceTypeName, EventType, Host and a Ts range weighted with the parameter allowedDelta
 func (m *MockSender) AssertEventMissing(t *testing.T, expectedEvent metrics.Event, allowedDelta time.Duration) bool {
@@ -108,6 +187,21 @@ func AssertFloatInRange(min float64, max float64) interface{} {
 	})
 }
 
+// MatchFloatInDelta is a mock.argumentMatcher builder to be used in asserts.
+// It allows to check if a metric value is within delta of the expected one instead of matching exactly.
+func MatchFloatInDelta(expected float64, delta float64) interface{} {
+	return mock.MatchedBy(func(actual float64) bool {
+		return floatInDelta(expected, actual, delta)
+	})
+}
+
+func floatInDelta(expected, actual, delta float64) bool {
+	if math.IsNaN(expected) || math.IsNaN(actual) {
+		return math.IsNaN(expected) && math.IsNaN(actual)
+	}
+	return math.Abs(expected-actual) <= delta
+}
+
 // MatchEventLike is a mock.argumentMatcher builder to be used in asserts.
 // It allows to check if an event is Equal on the following Event elements:
 // AggregationKey, Priority, SourceTypeName, EventType, Host and Tag list
@@ -111,6 +111,21 @@ func TestMockedServiceCheck(t *testing.T) {
 	sender.AssertServiceCheck(t, "docker.exit", metrics.ServiceCheckWarning, "", tags, message)
 }
 
+func TestMockedServiceCheckWithData(t *testing.T) {
+	sender := NewMockSender("1")
+	sender.SetupAcceptAll()
+
+	tags := []string{"one", "two"}
+	data := metrics.ServiceCheckData{"device_id": "default:1.2.3.4", "error_code": 2}
+	sender.ServiceCheckWithData("snmp.can_check", metrics.ServiceCheckCritical, "", tags, "timeout", data)
+	sender.AssertServiceCheck(t, "snmp.can_check", metrics.ServiceCheckCritical, "", tags, "timeout")
+	sender.AssertServiceCheckData(t, "snmp.can_check", metrics.ServiceCheckCritical, data)
+
+	sender.ResetCalls()
+	sender.AssertNotCalled(t, "ServiceCheck", "snmp.can_check", metrics.ServiceCheckCritical, "", tags, "timeout")
+	assert.Empty(t, sender.serviceChecksData)
+}
+
 func TestMockedEvent(t *testing.T) {
 	sender := NewMockSender("2")
 	sender.SetupAcceptAll()
@@ -148,3 +163,79 @@ func TestMockedEvent(t *testing.T) {
 	allowedDelta := time.Since(time.Unix(eventTimestamp, 0))
 	sender.AssertEvent(t, eventTwo, allowedDelta)
 }
+
+func TestAssertMetricWithTagsSubset(t *testing.T) {
+	sender := NewMockSender("3")
+	sender.SetupAcceptAll()
+
+	sender.Gauge("snmp.ifInErrors", 141, "", []string{"snmp_device:1.2.3.4", "interface:eth0", "loader:core"})
+	sender.Gauge("snmp.ifInErrors", 142, "", []string{"snmp_device:1.2.3.4", "interface:eth1", "loader:core"})
+
+	sender.AssertMetricWithTagsSubset(t, "Gauge", "snmp.ifInErrors", 141, "", []string{"interface:eth0", "snmp_device:1.2.3.4"})
+	sender.AssertMetricWithTagsSubset(t, "Gauge", "snmp.ifInErrors", 142, "", []string{"interface:eth1"})
+
+	for _, tc := range []struct {
+		name   string
+		method string
+		value  float64
+		tags   []string
+	}{
+		{"wrong method", "Rate", 141, []string{"interface:eth0"}},
+		{"wrong value", "Gauge", 142, []string{"interface:eth0"}},
+		{"missing tag", "Gauge", 141, []string{"interface:eth0", "interface:eth1"}},
+	} {
+		localTester := &testing.T{}
+		assert.False(t, sender.AssertMetricWithTagsSubset(localTester, tc.method, "snmp.ifInErrors", tc.value, "", tc.tags), tc.name)
+		assert.True(t, localTester.Failed(), tc.name)
+	}
+}
+
+func TestAssertMetricInDelta(t *testing.T) {
+	sender := NewMockSender("4")
+	sender.SetupAcceptAll()
+
+	sender.Rate("system.cpu.user", 0.1+0.2, "host", []string{"b", "a"})
+
+	sender.AssertMetricInDelta(t, "Rate", "system.cpu.user", 0.3, 1e-9, "host", []string{"a", "b"})
+	sender.AssertMetricInDelta(t, "Rate", "system.cpu.user", 0.25, 0.05, "host", nil)
+	sender.AssertCalled(t, "Rate", "system.cpu.user", MatchFloatInDelta(0.3, 1e-9), "host", MatchTagsContains([]string{"a"}))
+
+	localTester := &testing.T{}
+	assert.False(t, sender.AssertMetricInDelta(localTester, "Rate", "system.cpu.user", 0.2, 0.05, "host", nil))
+	assert.True(t, localTester.Failed())
+
+	localTester = &testing.T{}
+	assert.False(t, sender.AssertMetricInDelta(localTester, "Rate", "system.cpu.user", 0.3, 0.05, "other_host", nil))
+	assert.True(t, localTester.Failed())
+}
+
+func TestMetricCalls(t *testing.T) {
+	sender := NewMockSender("5")
+	sender.SetupAcceptAll()
+
+	sender.Gauge("snmp.devices_monitored", 1, "", []string{"loader:core"})
+	sender.MonotonicCount("snmp.ifInErrors", 10, "", []string{"interface:eth0"})
+	sender.MonotonicCount("snmp.ifInErrors", 20, "", []string{"interface:eth1"})
+	sender.MonotonicCount("snmp.ifInErrors", 30, "", []string{"interface:eth0"})
+	sender.ServiceCheck("snmp.can_check", metrics.ServiceCheckOK, "", nil, "")
+
+	assert.Equal(t, []MetricCall{
+		{Method: "MonotonicCount", Metric: "snmp.ifInErrors", Value: 10, Tags: []string{"interface:eth0"}},
+		{Method: "MonotonicCount", Metric: "snmp.ifInErrors", Value: 20, Tags: []string{"interface:eth1"}},
+		{Method: "MonotonicCount", Metric: "snmp.ifInErrors", Value: 30, Tags: []string{"interface:eth0"}},
+	}, sender.MetricCalls("MonotonicCount", "snmp.ifInErrors"))
+	assert.Empty(t, sender.MetricCalls("Gauge", "snmp.ifInErrors"))
+	assert.Len(t, sender.MetricCalls("", "snmp.devices_monitored"), 1)
+
+	assert.Equal(t, []string{"snmp.devices_monitored", "snmp.ifInErrors"}, sender.MetricNames(""))
+	assert.Equal(t, []string{"snmp.devices_monitored"}, sender.MetricNames("Gauge"))
+
+	call, found := sender.LastMetricCall("MonotonicCount", "snmp.ifInErrors", []string{"interface:eth0"})
+	assert.True(t, found)
+	assert.Equal(t, float64(30), call.Value)
+	_, found = sender.LastMetricCall("MonotonicCount", "snmp.ifInErrors", []string{"interface:eth2"})
+	assert.False(t, found)
+
+	sender.ResetCalls()
+	assert.Empty(t, sender.MetricNames(""))
+}
@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package mocksender
+
+import (
+	"fmt"
+	"sort"
+)
+
+// MetricCall is a metric submitted to the MockSender
+type MetricCall struct {
+	Method   string
+	Metric   string
+	Value    float64
+	Hostname string
+	Tags     []string
+}
+
+// String returns a representation of the call used in the assertion failures
+func (c MetricCall) String() string {
+	return fmt.Sprintf("%s(%s, %v, %q, %v)", c.Method, c.Metric, c.Value, c.Hostname, c.Tags)
+}
+
+// MetricCalls returns the calls recorded for a metric, in the order they were made.
+// An empty method returns the calls of every metric method (Gauge, Rate, MonotonicCount...).
+func (m *MockSender) MetricCalls(method string, metric string) []MetricCall {
+	var calls []MetricCall
+	for _, c := range m.metricCalls() {
+		if (method == "" || c.Method == method) && c.Metric == metric {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// MetricNames returns the sorted names of the metrics submitted with a method,
+// or with any metric method if the method is empty.
+func (m *MockSender) MetricNames(method string) []string {
+	seen := make(map[string]struct{})
+	names := []string{}
+	for _, c := range m.metricCalls() {
+		if method != "" && c.Method != method {
+			continue
+		}
+		if _, ok := seen[c.Metric]; !ok {
+			seen[c.Metric] = struct{}{}
+			names = append(names, c.Metric)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LastMetricCall returns the last call recorded for a metric with the given tags,
+// additional tags over the ones specified are ignored.
+func (m *MockSender) LastMetricCall(method string, metric string, tags []string) (MetricCall, bool) {
+	calls := m.MetricCalls(method, metric)
+	for i := len(calls) - 1; i >= 0; i-- {
+		if expectedInActual(tags, calls[i].Tags) {
+			return calls[i], true
+		}
+	}
+	return MetricCall{}, false
+}
+
+// metricCalls returns the recorded calls of the metric methods, the ones
+// with a `metric, value, hostname, tags` signature
+func (m *MockSender) metricCalls() []MetricCall {
+	var calls []MetricCall
+	for _, c := range m.Mock.Calls {
+		if len(c.Arguments) < 4 {
+			continue
+		}
+		metric, ok1 := c.Arguments[0].(string)
+		value, ok2 := c.Arguments[1].(float64)
+		hostname, ok3 := c.Arguments[2].(string)
+		tags, ok4 := c.Arguments[3].([]string)
+		if !ok1 || !ok2 || !ok3 || !ok4 {
+			continue
+		}
+		calls = append(calls, MetricCall{
+			Method:   c.Method,
+			Metric:   metric,
+			Value:    value,
+			Hostname: hostname,
+			Tags:     tags,
+		})
+	}
+	return calls
+}
@@ -7,6 +7,7 @@ package mocksender
 
 import (
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 )
@@ -46,21 +47,53 @@ func (m *MockSender) Historate(metric string, value float64, hostname string, ta
 	m.Called(metric, value, hostname, tags)
 }
 
+//Distribution adds a distribution type to the mock calls.
+func (m *MockSender) Distribution(metric string, value float64, hostname string, tags []string) {
+	m.Called(metric, value, hostname, tags)
+}
+
 //Gauge adds a gauge type to the mock calls.
 func (m *MockSender) Gauge(metric string, value float64, hostname string, tags []string) {
 	m.Called(metric, value, hostname, tags)
 }
 
+//MetricWithMetadata adds a metric to the mock calls. It's recorded as a call
+//of the method of its type, e.g. Gauge, so that AssertMetric applies, and its
+//metadata is kept for AssertMetricMetadata.
+func (m *MockSender) MetricWithMetadata(mType metrics.MetricType, metric string, value float64, hostname string, tags []string, metadata *metrics.SerieMetadata) {
+	m.MethodCalled(mType.String(), metric, value, hostname, tags)
+
+	m.metricsMetadataLock.Lock()
+	defer m.metricsMetadataLock.Unlock()
+	m.metricsMetadata = append(m.metricsMetadata, submittedMetricMetadata{metric, tags, *metadata})
+}
+
 //ServiceCheck enables the service check mock call.
 func (m *MockSender) ServiceCheck(checkName string, status metrics.ServiceCheckStatus, hostname string, tags []string, message string) {
 	m.Called(checkName, status, hostname, tags, message)
 }
 
+//ServiceCheckWithData enables the service check mock call. It's recorded as a
+//ServiceCheck call, so that AssertServiceCheck applies, and its data is kept
+//for AssertServiceCheckData.
+func (m *MockSender) ServiceCheckWithData(checkName string, status metrics.ServiceCheckStatus, hostname string, tags []string, message string, data metrics.ServiceCheckData) {
+	m.MethodCalled("ServiceCheck", checkName, status, hostname, tags, message)
+
+	m.serviceChecksDataLock.Lock()
+	defer m.serviceChecksDataLock.Unlock()
+	m.serviceChecksData = append(m.serviceChecksData, submittedServiceCheckData{checkName, status, data})
+}
+
 //DisableDefaultHostname enables the hostname mock call.
 func (m *MockSender) DisableDefaultHostname(d bool) {
 	m.Called(d)
 }
 
+//PersistCounterBaselines enables the counter baselines persistence mock call.
+func (m *MockSender) PersistCounterBaselines(persist bool) {
+	m.Called(persist)
+}
+
 //Event enables the event mock call.
 func (m *MockSender) Event(e metrics.Event) {
 	m.Called(e)
@@ -71,6 +104,18 @@ func (m *MockSender) EventPlatformEvent(rawEvent string, eventType string) {
 	m.Called(rawEvent, eventType)
 }
 
+//TryEventPlatformEvent enables the non-blocking event platform event mock call.
+func (m *MockSender) TryEventPlatformEvent(rawEvent string, eventType string) error {
+	args := m.Called(rawEvent, eventType)
+	return args.Error(0)
+}
+
+//TryEventPlatformEventWithAck enables the acknowledged event platform event mock call.
+func (m *MockSender) TryEventPlatformEventWithAck(rawEvent string, eventType string, ack chan<- epforwarder.Ack) error {
+	args := m.Called(rawEvent, eventType, ack)
+	return args.Error(0)
+}
+
 //HistogramBucket enables the histogram bucket mock call.
 func (m *MockSender) HistogramBucket(metric string, value int64, lowerBound, upperBound float64, monotonic bool, hostname string, tags []string, flushFirstValue bool) {
 	m.Called(metric, value, lowerBound, upperBound, monotonic, hostname, tags, flushFirstValue)
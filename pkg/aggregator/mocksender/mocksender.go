@@ -6,12 +6,14 @@
 package mocksender
 
 import (
+	"sync"
 	"time"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 )
 
 // NewMockSender initiates the aggregator and returns a
@@ -33,11 +35,31 @@ func SetSender(sender *MockSender, id check.ID) {
 //MockSender allows mocking of the checks sender for unit testing
 type MockSender struct {
 	mock.Mock
+
+	serviceChecksDataLock sync.Mutex
+	serviceChecksData     []submittedServiceCheckData
+
+	metricsMetadataLock sync.Mutex
+	metricsMetadata     []submittedMetricMetadata
+}
+
+// submittedServiceCheckData is the structured data of a service check submitted with ServiceCheckWithData
+type submittedServiceCheckData struct {
+	checkName string
+	status    metrics.ServiceCheckStatus
+	data      metrics.ServiceCheckData
+}
+
+// submittedMetricMetadata is the metadata of a metric submitted with MetricWithMetadata
+type submittedMetricMetadata struct {
+	metric   string
+	tags     []string
+	metadata metrics.SerieMetadata
 }
 
 // SetupAcceptAll sets mock expectations to accept any call in the Sender interface
 func (m *MockSender) SetupAcceptAll() {
-	metricCalls := []string{"Rate", "Count", "MonotonicCount", "Counter", "Histogram", "Historate", "Gauge"}
+	metricCalls := []string{"Rate", "Count", "MonotonicCount", "Counter", "Histogram", "Historate", "Distribution", "Gauge"}
 	for _, call := range metricCalls {
 		m.On(call,
 			mock.AnythingOfType("string"),   // Metric
@@ -62,6 +84,8 @@ func (m *MockSender) SetupAcceptAll() {
 	).Return()
 	m.On("Event", mock.AnythingOfType("metrics.Event")).Return()
 	m.On("EventPlatformEvent", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return()
+	m.On("TryEventPlatformEvent", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(nil)
+	m.On("TryEventPlatformEventWithAck", mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.Anything).Return(nil)
 	m.On("HistogramBucket",
 		mock.AnythingOfType("string"),   // metric name
 		mock.AnythingOfType("int64"),    // value
@@ -74,6 +98,7 @@ func (m *MockSender) SetupAcceptAll() {
 	).Return()
 	m.On("GetSenderStats", mock.AnythingOfType("check.SenderStats")).Return()
 	m.On("DisableDefaultHostname", mock.AnythingOfType("bool")).Return()
+	m.On("PersistCounterBaselines", mock.AnythingOfType("bool")).Return()
 	m.On("SetCheckCustomTags", mock.AnythingOfType("[]string")).Return()
 	m.On("SetCheckService", mock.AnythingOfType("string")).Return()
 	m.On("FinalizeCheckServiceTag").Return()
@@ -83,4 +108,8 @@ func (m *MockSender) SetupAcceptAll() {
 // ResetCalls makes the mock forget previous calls
 func (m *MockSender) ResetCalls() {
 	m.Mock.Calls = m.Mock.Calls[0:0]
+
+	m.serviceChecksDataLock.Lock()
+	m.serviceChecksData = nil
+	m.serviceChecksDataLock.Unlock()
 }
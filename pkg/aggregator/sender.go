@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -32,12 +34,18 @@ type Sender interface {
 	Counter(metric string, value float64, hostname string, tags []string)
 	Histogram(metric string, value float64, hostname string, tags []string)
 	Historate(metric string, value float64, hostname string, tags []string)
+	Distribution(metric string, value float64, hostname string, tags []string)
+	MetricWithMetadata(mType metrics.MetricType, metric string, value float64, hostname string, tags []string, metadata *metrics.SerieMetadata)
 	ServiceCheck(checkName string, status metrics.ServiceCheckStatus, hostname string, tags []string, message string)
+	ServiceCheckWithData(checkName string, status metrics.ServiceCheckStatus, hostname string, tags []string, message string, data metrics.ServiceCheckData)
 	HistogramBucket(metric string, value int64, lowerBound, upperBound float64, monotonic bool, hostname string, tags []string, flushFirstValue bool)
 	Event(e metrics.Event)
 	EventPlatformEvent(rawEvent string, eventType string)
+	TryEventPlatformEvent(rawEvent string, eventType string) error
+	TryEventPlatformEventWithAck(rawEvent string, eventType string, ack chan<- epforwarder.Ack) error
 	GetSenderStats() check.SenderStats
 	DisableDefaultHostname(disable bool)
+	PersistCounterBaselines(persist bool)
 	SetCheckCustomTags(tags []string)
 	SetCheckService(service string)
 	FinalizeCheckServiceTag()
@@ -65,8 +73,10 @@ type checkSender struct {
 	histogramBucketOut      chan<- senderHistogramBucket
 	orchestratorOut         chan<- senderOrchestratorMetadata
 	eventPlatformOut        chan<- senderEventPlatformEvent
+	eventPlatformForwarder  epforwarder.EventPlatformForwarder
 	checkTags               []string
 	service                 string
+	guard                   *senderGuard
 }
 
 type senderMetricSample struct {
@@ -115,6 +125,7 @@ func newCheckSender(id check.ID, defaultHostname string, smsOut chan<- senderMet
 		histogramBucketOut: bucketOut,
 		orchestratorOut:    orchestratorOut,
 		eventPlatformOut:   eventPlatformOut,
+		guard:              newSenderGuard(id),
 	}
 }
 
@@ -158,6 +169,7 @@ func GetDefaultSender() (Sender, error) {
 		var defaultCheckID check.ID                       // the default value is the zero value
 		aggregatorInstance.registerSender(defaultCheckID) //nolint:errcheck
 		senderInstance = newCheckSender(defaultCheckID, aggregatorInstance.hostname, aggregatorInstance.checkMetricIn, aggregatorInstance.serviceCheckIn, aggregatorInstance.eventIn, aggregatorInstance.checkHistogramBucketIn, aggregatorInstance.orchestratorMetadataIn, aggregatorInstance.eventPlatformIn)
+		senderInstance.eventPlatformForwarder = aggregatorInstance.eventPlatformForwarder
 	})
 
 	return senderInstance, nil
@@ -178,6 +190,15 @@ func (s *checkSender) DisableDefaultHostname(disable bool) {
 	s.defaultHostnameDisabled = disable
 }
 
+// PersistCounterBaselines allows check to persist the last samples of its rates and monotonic
+// counts when `check_cache.enabled` is set, so that they're flushed on the first run following
+// an agent restart instead of waiting for a second run.
+func (s *checkSender) PersistCounterBaselines(persist bool) {
+	if aggregatorInstance != nil {
+		aggregatorInstance.persistSenderBaselines(s.id, persist)
+	}
+}
+
 // SetCheckCustomTags stores the tags set in the check configuration file.
 // They will be appended to each send (metric, event and service)
 func (s *checkSender) SetCheckCustomTags(tags []string) {
@@ -199,9 +220,20 @@ func (s *checkSender) FinalizeCheckServiceTag() {
 
 // Commit commits the metric samples & histogram buckets that were added during a check run
 // Should be called at the end of every check run
+// Commits following the previous one too closely are ignored, their samples are
+// committed with the next one.
 func (s *checkSender) Commit() {
+	if !s.guard.allowCommit(time.Now()) {
+		return
+	}
 	// we use a metric sample to commit both for metrics & sketches
 	s.smsOut <- senderMetricSample{s.id, &metrics.MetricSample{}, true}
+	if serviceCheck := s.guard.commitStatus(); serviceCheck != nil {
+		serviceCheck.Host = s.defaultHostname
+		serviceCheck.Ts = correctTime(time.Now()).Unix()
+		serviceCheck.Tags = append(serviceCheck.Tags, s.checkTags...)
+		s.serviceCheckOut <- *serviceCheck
+	}
 	s.cyclemetricStats()
 }
 
@@ -224,7 +256,10 @@ func (s *checkSender) SendRawMetricSample(sample *metrics.MetricSample) {
 	s.smsOut <- senderMetricSample{s.id, sample, false}
 }
 
-func (s *checkSender) sendMetricSample(metric string, value float64, hostname string, tags []string, mType metrics.MetricType, flushFirstValue bool) {
+func (s *checkSender) sendMetricSample(metric string, value float64, hostname string, tags []string, mType metrics.MetricType, flushFirstValue bool, metadata *metrics.SerieMetadata) {
+	if !s.guard.allowSample() {
+		return
+	}
 	tags = append(tags, s.checkTags...)
 
 	log.Trace(mType.String(), " sample: ", metric, ": ", value, " for hostname: ", hostname, " tags: ", tags)
@@ -238,6 +273,7 @@ func (s *checkSender) sendMetricSample(metric string, value float64, hostname st
 		SampleRate:      1,
 		Timestamp:       timeNowNano(),
 		FlushFirstValue: flushFirstValue,
+		Metadata:        metadata,
 	}
 
 	if hostname == "" && !s.defaultHostnameDisabled {
@@ -253,45 +289,48 @@ func (s *checkSender) sendMetricSample(metric string, value float64, hostname st
 
 // Gauge should be used to send a simple gauge value to the aggregator. Only the last value sampled is kept at commit time.
 func (s *checkSender) Gauge(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.GaugeType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.GaugeType, false, nil)
 }
 
 // Rate should be used to track the rate of a metric over each check run
 func (s *checkSender) Rate(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.RateType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.RateType, false, nil)
 }
 
 // Count should be used to count a number of events that occurred during the check run
 func (s *checkSender) Count(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.CountType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.CountType, false, nil)
 }
 
 // MonotonicCount should be used to track the increase of a monotonic raw counter
 func (s *checkSender) MonotonicCount(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.MonotonicCountType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.MonotonicCountType, false, nil)
 }
 
 // MonotonicCountWithFlushFirstValue should be used to track the increase of a monotonic raw counter,
 // and allows specifying whether the aggregator should flush the first sampled value as-is.
 func (s *checkSender) MonotonicCountWithFlushFirstValue(metric string, value float64, hostname string, tags []string, flushFirstValue bool) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.MonotonicCountType, flushFirstValue)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.MonotonicCountType, flushFirstValue, nil)
 }
 
 // Counter is DEPRECATED and only implemented to preserve backward compatibility with python checks. Prefer using either:
 // * `Gauge` if you're counting states
 // * `Count` if you're counting events
 func (s *checkSender) Counter(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.CounterType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.CounterType, false, nil)
 }
 
 // Histogram should be used to track the statistical distribution of a set of values during a check run
 // Should be called multiple times on the same (metric, hostname, tags) so that a distribution can be computed
 func (s *checkSender) Histogram(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.HistogramType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.HistogramType, false, nil)
 }
 
 // HistogramBucket should be called to directly send raw buckets to be submitted as distribution metrics
 func (s *checkSender) HistogramBucket(metric string, value int64, lowerBound, upperBound float64, monotonic bool, hostname string, tags []string, flushFirstValue bool) {
+	if !s.guard.allowSample() {
+		return
+	}
 	tags = append(tags, s.checkTags...)
 
 	log.Tracef(
@@ -331,7 +370,20 @@ func (s *checkSender) HistogramBucket(metric string, value int64, lowerBound, up
 // Historate should be used to create a histogram metric for "rate" like metrics.
 // Warning this doesn't use the harmonic mean, beware of what it means when using it.
 func (s *checkSender) Historate(metric string, value float64, hostname string, tags []string) {
-	s.sendMetricSample(metric, value, hostname, tags, metrics.HistorateType, false)
+	s.sendMetricSample(metric, value, hostname, tags, metrics.HistorateType, false, nil)
+}
+
+// Distribution should be used to track the global distribution of a set of values, the samples are aggregated
+// in a sketch by the agent so that percentiles can be computed across hosts, like the distributions of DogStatsD.
+func (s *checkSender) Distribution(metric string, value float64, hostname string, tags []string) {
+	s.sendMetricSample(metric, value, hostname, tags, metrics.DistributionType, false, nil)
+}
+
+// MetricWithMetadata sends a sample of the given type along with the metadata of its timeseries, like its unit or
+// the resources it is attributed to. The metadata of the last sample of a timeseries is the one flushed with it.
+// The metadata is referenced by the sample rather than copied, it can be shared by samples but mustn't be modified.
+func (s *checkSender) MetricWithMetadata(mType metrics.MetricType, metric string, value float64, hostname string, tags []string, metadata *metrics.SerieMetadata) {
+	s.sendMetricSample(metric, value, hostname, tags, mType, false, metadata)
 }
 
 // SendRawServiceCheck sends the raw service check
@@ -342,14 +394,20 @@ func (s *checkSender) SendRawServiceCheck(sc *metrics.ServiceCheck) {
 
 // ServiceCheck submits a service check
 func (s *checkSender) ServiceCheck(checkName string, status metrics.ServiceCheckStatus, hostname string, tags []string, message string) {
+	s.ServiceCheckWithData(checkName, status, hostname, tags, message, nil)
+}
+
+// ServiceCheckWithData submits a service check with structured data
+func (s *checkSender) ServiceCheckWithData(checkName string, status metrics.ServiceCheckStatus, hostname string, tags []string, message string, data metrics.ServiceCheckData) {
 	log.Trace("Service check submitted: ", checkName, ": ", status.String(), " for hostname: ", hostname, " tags: ", tags)
 	serviceCheck := metrics.ServiceCheck{
 		CheckName: checkName,
 		Status:    status,
 		Host:      hostname,
-		Ts:        time.Now().Unix(),
+		Ts:        correctTime(time.Now()).Unix(),
 		Tags:      append(tags, s.checkTags...),
 		Message:   message,
+		Data:      data,
 	}
 
 	if hostname == "" && !s.defaultHostnameDisabled {
@@ -392,6 +450,44 @@ func (s *checkSender) EventPlatformEvent(rawEvent string, eventType string) {
 	s.metricStats.EventPlatformEvents[eventType] = s.metricStats.EventPlatformEvents[eventType] + 1
 }
 
+// TryEventPlatformEvent submits an event platform event without blocking. The event
+// is handed to the event platform forwarder directly and the returned error matches
+// epforwarder.IsBackpressure when the pipeline is saturated or when the check used
+// up its quota: checks are then expected to skip the remaining events of this run.
+func (s *checkSender) TryEventPlatformEvent(rawEvent string, eventType string) error {
+	return s.tryEventPlatformEvent(rawEvent, eventType, nil)
+}
+
+// TryEventPlatformEventWithAck submits an event platform event like TryEventPlatformEvent, and reports
+// on the `ack` channel whether it was delivered to the intake, so that checks sending critical payloads
+// can send them again on their next run. Nothing is reported on the channel when an error is returned.
+// The channel should be buffered: acknowledgements are dropped when it is full.
+func (s *checkSender) TryEventPlatformEventWithAck(rawEvent string, eventType string, ack chan<- epforwarder.Ack) error {
+	return s.tryEventPlatformEvent(rawEvent, eventType, ack)
+}
+
+func (s *checkSender) tryEventPlatformEvent(rawEvent string, eventType string, ack chan<- epforwarder.Ack) error {
+	if s.eventPlatformForwarder == nil {
+		return errors.New("event platform forwarder not initialized")
+	}
+	m := &message.Message{Content: []byte(rawEvent)}
+	aggregatorEventPlatformEvents.Add(eventType, 1)
+	var err error
+	if ack != nil {
+		err = s.eventPlatformForwarder.TrySendEventPlatformEventWithAck(m, eventType, string(s.id), ack)
+	} else {
+		err = s.eventPlatformForwarder.TrySendEventPlatformEvent(m, eventType, string(s.id))
+	}
+	if err != nil {
+		aggregatorEventPlatformEventsErrors.Add(eventType, 1)
+		return err
+	}
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	s.metricStats.EventPlatformEvents[eventType] = s.metricStats.EventPlatformEvents[eventType] + 1
+	return nil
+}
+
 // OrchestratorMetadata submit orchestrator metadata messages
 func (s *checkSender) OrchestratorMetadata(msgs []serializer.ProcessMessageBody, clusterID string, nodeType int) {
 	om := senderOrchestratorMetadata{
@@ -431,6 +527,7 @@ func (sp *checkSenderPool) mkSender(id check.ID) (Sender, error) {
 
 	err := aggregatorInstance.registerSender(id)
 	sender := newCheckSender(id, aggregatorInstance.hostname, aggregatorInstance.checkMetricIn, aggregatorInstance.serviceCheckIn, aggregatorInstance.eventIn, aggregatorInstance.checkHistogramBucketIn, aggregatorInstance.orchestratorMetadataIn, aggregatorInstance.eventPlatformIn)
+	sender.eventPlatformForwarder = aggregatorInstance.eventPlatformForwarder
 	sp.senders[id] = sender
 	return sender, err
 }
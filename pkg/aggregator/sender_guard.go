@@ -0,0 +1,125 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// senderGuardServiceCheck is the service check reporting whether a check is throttled by its sender
+const senderGuardServiceCheck = "datadog.agent.check_sender"
+
+var (
+	tlmSenderDroppedSamples = telemetry.NewCounter("aggregator", "sender_dropped_samples",
+		[]string{"check_name"}, "Samples dropped because a check exceeded check_sender.max_samples_per_commit")
+	tlmSenderThrottledCommits = telemetry.NewCounter("aggregator", "sender_throttled_commits",
+		[]string{"check_name"}, "Commits ignored because a check committed more often than check_sender.min_commit_interval")
+)
+
+// senderGuard throttles the checks submitting too many samples or committing
+// in a tight loop, so that a misbehaving check can't flood the aggregator.
+type senderGuard struct {
+	checkName           string
+	maxSamplesPerCommit int
+	minCommitInterval   time.Duration
+
+	m                  sync.Mutex
+	samplesSinceCommit int
+	droppedSamples     int
+	throttledCommits   int
+	lastCommit         time.Time
+	throttled          bool
+}
+
+// newSenderGuard returns the guard of a check sender, nil for the default
+// sender which is shared by several agent components.
+func newSenderGuard(id check.ID) *senderGuard {
+	if id == "" {
+		return nil
+	}
+	return &senderGuard{
+		checkName:           check.IDToCheckName(id),
+		maxSamplesPerCommit: config.Datadog.GetInt("check_sender.max_samples_per_commit"),
+		minCommitInterval:   config.Datadog.GetDuration("check_sender.min_commit_interval"),
+	}
+}
+
+// allowSample returns whether a sample can be submitted in the current commit
+func (g *senderGuard) allowSample() bool {
+	if g == nil || g.maxSamplesPerCommit <= 0 {
+		return true
+	}
+	g.m.Lock()
+	defer g.m.Unlock()
+	if g.samplesSinceCommit >= g.maxSamplesPerCommit {
+		g.droppedSamples++
+		return false
+	}
+	g.samplesSinceCommit++
+	return true
+}
+
+// allowCommit returns whether the check committed long enough after its previous commit
+func (g *senderGuard) allowCommit(now time.Time) bool {
+	if g == nil || g.minCommitInterval <= 0 {
+		return true
+	}
+	g.m.Lock()
+	defer g.m.Unlock()
+	if !g.lastCommit.IsZero() && now.Sub(g.lastCommit) < g.minCommitInterval {
+		g.throttledCommits++
+		return false
+	}
+	g.lastCommit = now
+	return true
+}
+
+// commitStatus resets the counters of the commit and returns the service check
+// to submit: WARNING when the check was throttled since the previous commit,
+// OK when it's not throttled anymore, nil otherwise.
+func (g *senderGuard) commitStatus() *metrics.ServiceCheck {
+	if g == nil {
+		return nil
+	}
+	g.m.Lock()
+	defer g.m.Unlock()
+
+	dropped, throttledCommits := g.droppedSamples, g.throttledCommits
+	g.samplesSinceCommit, g.droppedSamples, g.throttledCommits = 0, 0, 0
+
+	if dropped == 0 && throttledCommits == 0 {
+		if !g.throttled {
+			return nil
+		}
+		g.throttled = false
+		return &metrics.ServiceCheck{
+			CheckName: senderGuardServiceCheck,
+			Status:    metrics.ServiceCheckOK,
+			Tags:      []string{"check:" + g.checkName},
+		}
+	}
+
+	g.throttled = true
+	tlmSenderDroppedSamples.Add(float64(dropped), g.checkName)
+	tlmSenderThrottledCommits.Add(float64(throttledCommits), g.checkName)
+	message := fmt.Sprintf("check %s was throttled: %d samples dropped above the limit of %d per commit, %d commits ignored less than %s after the previous one",
+		g.checkName, dropped, g.maxSamplesPerCommit, throttledCommits, g.minCommitInterval)
+	log.Warn(message)
+	return &metrics.ServiceCheck{
+		CheckName: senderGuardServiceCheck,
+		Status:    metrics.ServiceCheckWarning,
+		Tags:      []string{"check:" + g.checkName},
+		Message:   message,
+	}
+}
@@ -0,0 +1,85 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestSenderGuardDefaultSender(t *testing.T) {
+	guard := newSenderGuard("")
+	assert.Nil(t, guard)
+	assert.True(t, guard.allowSample())
+	assert.True(t, guard.allowCommit(time.Now()))
+	assert.Nil(t, guard.commitStatus())
+}
+
+func TestSenderGuardMaxSamples(t *testing.T) {
+	guard := &senderGuard{checkName: "noisy", maxSamplesPerCommit: 2}
+
+	assert.True(t, guard.allowSample())
+	assert.True(t, guard.allowSample())
+	assert.False(t, guard.allowSample())
+	assert.False(t, guard.allowSample())
+
+	status := guard.commitStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, metrics.ServiceCheckWarning, status.Status)
+	assert.Equal(t, []string{"check:noisy"}, status.Tags)
+	assert.Contains(t, status.Message, "2 samples dropped")
+
+	// the limit is reset by the commit
+	assert.True(t, guard.allowSample())
+	status = guard.commitStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, metrics.ServiceCheckOK, status.Status)
+
+	// no service check once recovered
+	assert.Nil(t, guard.commitStatus())
+}
+
+func TestSenderGuardMinCommitInterval(t *testing.T) {
+	guard := &senderGuard{checkName: "noisy", minCommitInterval: time.Second}
+	now := time.Now()
+
+	assert.True(t, guard.allowCommit(now))
+	assert.False(t, guard.allowCommit(now.Add(100*time.Millisecond)))
+	assert.False(t, guard.allowCommit(now.Add(200*time.Millisecond)))
+	assert.True(t, guard.allowCommit(now.Add(time.Second)))
+
+	status := guard.commitStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, metrics.ServiceCheckWarning, status.Status)
+	assert.Contains(t, status.Message, "2 commits ignored")
+}
+
+func TestCheckSenderThrottling(t *testing.T) {
+	s := initSender(checkID1, "default-hostname")
+	s.sender.guard = &senderGuard{checkName: "noisy", maxSamplesPerCommit: 1, minCommitInterval: time.Hour}
+
+	s.sender.Gauge("my.metric", 1.0, "", nil)
+	s.sender.Gauge("my.metric", 2.0, "", nil)
+	s.sender.Commit()
+	// ignored, less than min_commit_interval after the previous commit
+	s.sender.Commit()
+
+	sample := <-s.senderMetricSampleChan
+	assert.Equal(t, 1.0, sample.metricSample.Value)
+	commit := <-s.senderMetricSampleChan
+	assert.True(t, commit.commit)
+	assert.Len(t, s.senderMetricSampleChan, 0)
+
+	serviceCheck := <-s.serviceCheckChan
+	assert.Equal(t, senderGuardServiceCheck, serviceCheck.CheckName)
+	assert.Equal(t, metrics.ServiceCheckWarning, serviceCheck.Status)
+	assert.Equal(t, "default-hostname", serviceCheck.Host)
+}
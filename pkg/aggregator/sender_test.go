@@ -20,6 +20,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/metrics"
 )
 
@@ -175,7 +177,7 @@ func TestGetSenderServiceTagMetrics(t *testing.T) {
 	// only tags added by the check
 	s.sender.SetCheckService("")
 	s.sender.FinalizeCheckServiceTag()
-	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false)
+	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false, nil)
 	sms := <-s.senderMetricSampleChan
 	assert.Equal(t, checkTags, sms.metricSample.Tags)
 
@@ -183,7 +185,7 @@ func TestGetSenderServiceTagMetrics(t *testing.T) {
 	s.sender.SetCheckService("service1")
 	s.sender.SetCheckService("service2")
 	s.sender.FinalizeCheckServiceTag()
-	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false)
+	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false, nil)
 	sms = <-s.senderMetricSampleChan
 	assert.Equal(t, append(checkTags, "service:service2"), sms.metricSample.Tags)
 }
@@ -248,13 +250,13 @@ func TestGetSenderAddCheckCustomTagsMetrics(t *testing.T) {
 
 	s := initSender(checkID1, "")
 	// no custom tags
-	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", nil, metrics.CounterType, false)
+	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", nil, metrics.CounterType, false, nil)
 	sms := <-s.senderMetricSampleChan
 	assert.Nil(t, sms.metricSample.Tags)
 
 	// only tags added by the check
 	checkTags := []string{"check:tag1", "check:tag2"}
-	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false)
+	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false, nil)
 	sms = <-s.senderMetricSampleChan
 	assert.Equal(t, checkTags, sms.metricSample.Tags)
 
@@ -264,12 +266,12 @@ func TestGetSenderAddCheckCustomTagsMetrics(t *testing.T) {
 	assert.Len(t, s.sender.checkTags, 2)
 
 	// only tags coming from the configuration file
-	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", nil, metrics.CounterType, false)
+	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", nil, metrics.CounterType, false, nil)
 	sms = <-s.senderMetricSampleChan
 	assert.Equal(t, customTags, sms.metricSample.Tags)
 
 	// tags added by the check + tags coming from the configuration file
-	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false)
+	s.sender.sendMetricSample("metric.test", 42.0, "testhostname", checkTags, metrics.CounterType, false, nil)
 	sms = <-s.senderMetricSampleChan
 	assert.Equal(t, append(checkTags, customTags...), sms.metricSample.Tags)
 }
@@ -393,6 +395,8 @@ func TestCheckSenderInterface(t *testing.T) {
 	s.sender.MonotonicCountWithFlushFirstValue("my.monotonic_count_metric", 12.0, "my-hostname", []string{"foo", "bar"}, true)
 	s.sender.Counter("my.counter_metric", 1.0, "my-hostname", []string{"foo", "bar"})
 	s.sender.Histogram("my.histo_metric", 3.0, "my-hostname", []string{"foo", "bar"})
+	s.sender.Distribution("my.distribution_metric", 4.0, "my-hostname", []string{"foo", "bar"})
+	s.sender.MetricWithMetadata(metrics.GaugeType, "my.metadata_metric", 5.0, "my-hostname", []string{"foo", "bar"}, &metrics.SerieMetadata{Unit: "byte"})
 	s.sender.HistogramBucket("my.histogram_bucket", 42, 1.0, 2.0, true, "my-hostname", []string{"foo", "bar"}, true)
 	s.sender.Commit()
 	s.sender.ServiceCheck("my_service.can_connect", metrics.ServiceCheckOK, "my-hostname", []string{"foo", "bar"}, "message")
@@ -447,6 +451,18 @@ func TestCheckSenderInterface(t *testing.T) {
 	assert.Equal(t, metrics.HistogramType, histoSenderSample.metricSample.Mtype)
 	assert.Equal(t, false, histoSenderSample.commit)
 
+	distributionSenderSample := <-s.senderMetricSampleChan
+	assert.EqualValues(t, checkID1, distributionSenderSample.id)
+	assert.Equal(t, metrics.DistributionType, distributionSenderSample.metricSample.Mtype)
+	assert.Equal(t, 4.0, distributionSenderSample.metricSample.Value)
+	assert.Equal(t, false, distributionSenderSample.commit)
+
+	metadataSenderSample := <-s.senderMetricSampleChan
+	assert.EqualValues(t, checkID1, metadataSenderSample.id)
+	assert.Equal(t, metrics.GaugeType, metadataSenderSample.metricSample.Mtype)
+	assert.Equal(t, &metrics.SerieMetadata{Unit: "byte"}, metadataSenderSample.metricSample.Metadata)
+	assert.Equal(t, false, metadataSenderSample.commit)
+
 	commitSenderSample := <-s.senderMetricSampleChan
 	assert.EqualValues(t, checkID1, commitSenderSample.id)
 	assert.Equal(t, true, commitSenderSample.commit)
@@ -566,3 +582,57 @@ func TestChangeAllSendersDefaultHostname(t *testing.T) {
 	gaugeSenderSample = <-s.senderMetricSampleChan
 	assert.Equal(t, "hostname1", gaugeSenderSample.metricSample.Host)
 }
+
+type fakeEventPlatformForwarder struct {
+	epforwarder.EventPlatformForwarder
+	err     error
+	sources []string
+}
+
+func (f *fakeEventPlatformForwarder) TrySendEventPlatformEvent(e *message.Message, eventType string, source string) error {
+	f.sources = append(f.sources, source)
+	return f.err
+}
+
+func (f *fakeEventPlatformForwarder) TrySendEventPlatformEventWithAck(e *message.Message, eventType string, source string, ack chan<- epforwarder.Ack) error {
+	if err := f.TrySendEventPlatformEvent(e, eventType, source); err != nil {
+		return err
+	}
+	ack <- epforwarder.Ack{EventType: eventType, Delivered: true}
+	return nil
+}
+
+func TestTryEventPlatformEvent(t *testing.T) {
+	s := initSender(checkID1, "default-hostname")
+	assert.Error(t, s.sender.TryEventPlatformEvent("raw-event", "dbm-samples"))
+
+	fwd := &fakeEventPlatformForwarder{}
+	s.sender.eventPlatformForwarder = fwd
+	assert.NoError(t, s.sender.TryEventPlatformEvent("raw-event", "dbm-samples"))
+	assert.Equal(t, []string{string(checkID1)}, fwd.sources)
+	// nothing goes through the aggregator, Commit can't be blocked by the event platform
+	assert.Len(t, s.eventPlatformEventChan, 0)
+
+	fwd.err = epforwarder.ErrPipelineFull
+	err := s.sender.TryEventPlatformEvent("raw-event", "dbm-samples")
+	assert.True(t, epforwarder.IsBackpressure(err))
+
+	s.sender.cyclemetricStats()
+	assert.Equal(t, int64(1), s.sender.GetSenderStats().EventPlatformEvents["dbm-samples"])
+}
+
+func TestTryEventPlatformEventWithAck(t *testing.T) {
+	s := initSender(checkID1, "default-hostname")
+	acks := make(chan epforwarder.Ack, 1)
+	assert.Error(t, s.sender.TryEventPlatformEventWithAck("raw-event", "dbm-samples", acks))
+
+	fwd := &fakeEventPlatformForwarder{}
+	s.sender.eventPlatformForwarder = fwd
+	assert.NoError(t, s.sender.TryEventPlatformEventWithAck("raw-event", "dbm-samples", acks))
+	assert.Equal(t, []string{string(checkID1)}, fwd.sources)
+	assert.Equal(t, epforwarder.Ack{EventType: "dbm-samples", Delivered: true}, <-acks)
+
+	fwd.err = epforwarder.ErrPipelineFull
+	assert.True(t, epforwarder.IsBackpressure(s.sender.TryEventPlatformEventWithAck("raw-event", "dbm-samples", acks)))
+	assert.Len(t, acks, 0)
+}
@@ -0,0 +1,196 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package aggregator
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+// dogstatsdOriginPrefix prefixes the origin of the DogStatsD contexts, followed by their metric namespace
+const dogstatsdOriginPrefix = "dogstatsd:"
+
+var tlmTagCardinality = telemetry.NewGauge("aggregator", "tag_cardinality",
+	[]string{"origin", "tag_key"}, "Number of unique values of the tag keys with the highest cardinality, in the live contexts of their origin")
+
+// TagCardinality is the number of unique values of a tag key in the live contexts of an origin,
+// which is the ID of a check or the metric namespace of the DogStatsD metrics.
+type TagCardinality struct {
+	Origin       string
+	TagKey       string
+	UniqueValues int
+	Contexts     int
+}
+
+type tagCardinalityKey struct {
+	origin string
+	tagKey string
+}
+
+type tagCardinalityCounter struct {
+	values   map[string]struct{}
+	contexts int
+}
+
+// tagCardinalityAccumulator counts the unique values of each tag key by origin
+type tagCardinalityAccumulator struct {
+	counters map[tagCardinalityKey]*tagCardinalityCounter
+}
+
+func newTagCardinalityAccumulator() *tagCardinalityAccumulator {
+	return &tagCardinalityAccumulator{
+		counters: make(map[tagCardinalityKey]*tagCardinalityCounter),
+	}
+}
+
+// visitContexts counts the tag values of contexts
+func (a *tagCardinalityAccumulator) visitContexts(contexts []*Context, origin func(ctx *Context) string) {
+	for _, ctx := range contexts {
+		o := origin(ctx)
+		for _, tag := range ctx.Tags {
+			// the tags without value can't explode the cardinality of a key
+			sep := strings.IndexByte(tag, ':')
+			if sep <= 0 {
+				continue
+			}
+
+			key := tagCardinalityKey{origin: o, tagKey: tag[:sep]}
+			counter, found := a.counters[key]
+			if !found {
+				counter = &tagCardinalityCounter{values: make(map[string]struct{})}
+				a.counters[key] = counter
+			}
+			counter.values[tag[sep+1:]] = struct{}{}
+			counter.contexts++
+		}
+	}
+}
+
+// topOffenders returns the n tag keys with the most unique values
+func (a *tagCardinalityAccumulator) topOffenders(n int) []TagCardinality {
+	offenders := make([]TagCardinality, 0, len(a.counters))
+	for key, counter := range a.counters {
+		offenders = append(offenders, TagCardinality{
+			Origin:       key.origin,
+			TagKey:       key.tagKey,
+			UniqueValues: len(counter.values),
+			Contexts:     counter.contexts,
+		})
+	}
+
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].UniqueValues != offenders[j].UniqueValues {
+			return offenders[i].UniqueValues > offenders[j].UniqueValues
+		}
+		if offenders[i].Origin != offenders[j].Origin {
+			return offenders[i].Origin < offenders[j].Origin
+		}
+		return offenders[i].TagKey < offenders[j].TagKey
+	})
+
+	if len(offenders) > n {
+		offenders = offenders[:n]
+	}
+	return offenders
+}
+
+// dogstatsdOrigin returns the origin of a DogStatsD context: its metric namespace
+func dogstatsdOrigin(ctx *Context) string {
+	if sep := strings.IndexByte(ctx.Name, '.'); sep > 0 {
+		return dogstatsdOriginPrefix + ctx.Name[:sep]
+	}
+	return dogstatsdOriginPrefix + ctx.Name
+}
+
+// tagCardinalityContexts are the live contexts of an origin, the contexts aren't modified once
+// created so they can be counted without holding the lock of the aggregator
+type tagCardinalityContexts struct {
+	// checkID is the origin of the contexts of a check, it's empty for the DogStatsD contexts
+	checkID  check.ID
+	contexts []*Context
+}
+
+func newTagCardinalityContexts(checkID check.ID, cr *contextResolver) tagCardinalityContexts {
+	contexts := make([]*Context, 0, len(cr.contextsByKey))
+	for _, ctx := range cr.contextsByKey {
+		contexts = append(contexts, ctx)
+	}
+	return tagCardinalityContexts{checkID: checkID, contexts: contexts}
+}
+
+// tagCardinalityTelemetry periodically reports the tag keys with the most unique values in the
+// live contexts of the aggregator, so that a tag explosion can be attributed to a key and an origin.
+type tagCardinalityTelemetry struct {
+	lastRun time.Time
+
+	mu        sync.RWMutex
+	offenders []TagCardinality
+}
+
+// snapshot returns the live contexts to count when `aggregator_tag_cardinality.interval` elapsed
+// since the last count, or nil. It must be called with the samplers locked, and only copies the
+// pointers to the contexts so that the count doesn't hold the lock.
+func (t *tagCardinalityTelemetry) snapshot(now time.Time, statsdSampler *TimeSampler, checkSamplers map[check.ID]*CheckSampler) []tagCardinalityContexts {
+	interval := time.Duration(config.Datadog.GetInt("aggregator_tag_cardinality.interval")) * time.Second
+	top := config.Datadog.GetInt("aggregator_tag_cardinality.top")
+	if interval <= 0 || top <= 0 || now.Sub(t.lastRun) < interval {
+		return nil
+	}
+	t.lastRun = now
+
+	snapshot := make([]tagCardinalityContexts, 0, len(checkSamplers)+1)
+	snapshot = append(snapshot, newTagCardinalityContexts("", statsdSampler.contextResolver.resolver))
+	for id, sampler := range checkSamplers {
+		snapshot = append(snapshot, newTagCardinalityContexts(id, sampler.contextResolver.resolver))
+	}
+	return snapshot
+}
+
+// count counts the tag values of the contexts returned by snapshot, and reports the tag keys with
+// the most unique values
+func (t *tagCardinalityTelemetry) count(snapshot []tagCardinalityContexts) {
+	// the count can be disabled since the snapshot
+	top := config.Datadog.GetInt("aggregator_tag_cardinality.top")
+	if snapshot == nil || top <= 0 {
+		return
+	}
+
+	acc := newTagCardinalityAccumulator()
+	for _, s := range snapshot {
+		if s.checkID == "" {
+			acc.visitContexts(s.contexts, dogstatsdOrigin)
+			continue
+		}
+		origin := string(s.checkID)
+		acc.visitContexts(s.contexts, func(*Context) string { return origin })
+	}
+	offenders := acc.topOffenders(top)
+
+	t.mu.Lock()
+	previous := t.offenders
+	t.offenders = offenders
+	t.mu.Unlock()
+
+	for _, o := range previous {
+		tlmTagCardinality.Delete(o.Origin, o.TagKey)
+	}
+	for _, o := range offenders {
+		tlmTagCardinality.Set(float64(o.UniqueValues), o.Origin, o.TagKey)
+	}
+}
+
+// TopOffenders returns the tag keys with the most unique values at the last count
+func (t *tagCardinalityTelemetry) TopOffenders() []TagCardinality {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.offenders
+}
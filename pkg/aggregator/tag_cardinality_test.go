@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package aggregator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+func TestTagCardinalityAccumulator(t *testing.T) {
+	cr := newContextResolver()
+	for i := 0; i < 5; i++ {
+		cr.trackContext(&metrics.MetricSample{
+			Name: "myapp.requests",
+			Tags: []string{"env:prod", fmt.Sprintf("user_id:%d", i), "canary"},
+		})
+	}
+	cr.trackContext(&metrics.MetricSample{Name: "other", Tags: []string{"user_id:0"}})
+
+	acc := newTagCardinalityAccumulator()
+	acc.visitContexts(newTagCardinalityContexts("", cr).contexts, dogstatsdOrigin)
+
+	assert.Equal(t, []TagCardinality{
+		{Origin: "dogstatsd:myapp", TagKey: "user_id", UniqueValues: 5, Contexts: 5},
+		{Origin: "dogstatsd:myapp", TagKey: "env", UniqueValues: 1, Contexts: 5},
+		{Origin: "dogstatsd:other", TagKey: "user_id", UniqueValues: 1, Contexts: 1},
+	}, acc.topOffenders(10))
+
+	assert.Len(t, acc.topOffenders(1), 1)
+}
+
+func TestTagCardinalityTelemetryUpdate(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("aggregator_tag_cardinality.interval", 60)
+	mockConfig.Set("aggregator_tag_cardinality.top", 2)
+	defer mockConfig.Set("aggregator_tag_cardinality.interval", 0)
+	defer mockConfig.Set("aggregator_tag_cardinality.top", 10)
+
+	statsdSampler := NewTimeSampler(10)
+	statsdSampler.addSample(&metrics.MetricSample{Name: "myapp.requests", Tags: []string{"pod_name:a"}, Mtype: metrics.GaugeType}, 1000)
+	statsdSampler.addSample(&metrics.MetricSample{Name: "myapp.requests", Tags: []string{"pod_name:b"}, Mtype: metrics.GaugeType}, 1000)
+
+	checkSampler := newCheckSampler(2, true, 1000)
+	for i := 0; i < 3; i++ {
+		checkSampler.addSample(&metrics.MetricSample{Name: "my.check.metric", Tags: []string{fmt.Sprintf("url:%d", i)}, Mtype: metrics.GaugeType})
+	}
+	checkSamplers := map[check.ID]*CheckSampler{"http_check:123": checkSampler}
+
+	tlm := &tagCardinalityTelemetry{}
+	now := time.Now()
+	mockConfig.Set("aggregator_tag_cardinality.interval", 0)
+	assert.Nil(t, tlm.snapshot(now, statsdSampler, checkSamplers))
+
+	mockConfig.Set("aggregator_tag_cardinality.interval", 60)
+	tlm.count(tlm.snapshot(now, statsdSampler, checkSamplers))
+	assert.Equal(t, []TagCardinality{
+		{Origin: "http_check:123", TagKey: "url", UniqueValues: 3, Contexts: 3},
+		{Origin: "dogstatsd:myapp", TagKey: "pod_name", UniqueValues: 2, Contexts: 2},
+	}, tlm.TopOffenders())
+
+	// the contexts aren't counted again before the interval elapsed
+	statsdSampler.addSample(&metrics.MetricSample{Name: "myapp.requests", Tags: []string{"pod_name:c"}, Mtype: metrics.GaugeType}, 1000)
+	statsdSampler.addSample(&metrics.MetricSample{Name: "myapp.requests", Tags: []string{"pod_name:d"}, Mtype: metrics.GaugeType}, 1000)
+	assert.Nil(t, tlm.snapshot(now.Add(30*time.Second), statsdSampler, checkSamplers))
+	assert.Equal(t, 2, tlm.TopOffenders()[1].UniqueValues)
+
+	// the contexts copied are counted even once expired
+	snapshot := tlm.snapshot(now.Add(time.Minute), statsdSampler, checkSamplers)
+	statsdSampler.flush(2000)
+	tlm.count(snapshot)
+	assert.Equal(t, []TagCardinality{
+		{Origin: "dogstatsd:myapp", TagKey: "pod_name", UniqueValues: 4, Contexts: 4},
+		{Origin: "http_check:123", TagKey: "url", UniqueValues: 3, Contexts: 3},
+	}, tlm.TopOffenders())
+}
@@ -11,6 +11,8 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 )
 
 // GetClient is a convenience function returning an http client
@@ -18,12 +20,13 @@ import (
 // localhost (ie, for Agent commands).
 func GetClient(verify bool) *http.Client {
 	if verify {
-		return &http.Client{}
+		return &http.Client{Transport: httputils.NewEgressValidatedTransport()}
 	}
 
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 	}
+	httputils.GetEgressValidator().Apply(tr)
 
 	return &http.Client{Transport: tr}
 }
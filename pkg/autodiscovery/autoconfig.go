@@ -225,29 +225,7 @@ func (ac *AutoConfig) GetAllConfigs() []integration.Config {
 		}
 
 		if fileConfPd, ok := pd.provider.(*providers.FileConfigProvider); ok {
-			var goodConfs []integration.Config
-			for _, cfg := range cfgs {
-				// JMX checks can have 2 YAML files: one containing the metrics to collect, one containing the
-				// instance configuration
-				// If the file provider finds any of these metric YAMLs, we store them in a map for future access
-				if cfg.MetricConfig != nil {
-					// We don't want to save metric files, it's enough to store them in the map
-					ac.store.setJMXMetricsForConfigName(cfg.Name, cfg.MetricConfig)
-					continue
-				}
-
-				goodConfs = append(goodConfs, cfg)
-
-				// Clear any old errors if a valid config file is found
-				errorStats.removeConfigError(cfg.Name)
-			}
-
-			// Grab any errors that occurred when reading the YAML file
-			for name, e := range fileConfPd.Errors {
-				errorStats.setConfigError(name, e)
-			}
-
-			cfgs = goodConfs
+			cfgs = ac.processFileConfigs(fileConfPd, cfgs)
 		}
 		// Store all raw configs in the provider
 		pd.configs = cfgs
@@ -427,9 +405,24 @@ func (ac *AutoConfig) RemoveScheduler(name string) {
 func decryptConfig(conf integration.Config) (integration.Config, error) {
 	if config.Datadog.GetBool("secret_backend_skip_checks") {
 		log.Tracef("'secret_backend_skip_checks' is enabled, not decrypting configuration %q", conf.Name)
-		return conf, nil
+	} else {
+		var err error
+		if conf, err = decryptEncSecrets(conf); err != nil {
+			return conf, err
+		}
 	}
 
+	// %%secret_<handle>%% template variables, fetched again at each scheduling. They're
+	// explicitly requested by the template, so 'secret_backend_skip_checks' doesn't apply.
+	if err := configresolver.SubstituteTemplateSecrets(&conf); err != nil {
+		return conf, fmt.Errorf("error while resolving secret template variables: %s", err)
+	}
+
+	return conf, nil
+}
+
+// decryptEncSecrets replaces the ENC[<handle>] values of the config
+func decryptEncSecrets(conf integration.Config) (integration.Config, error) {
 	var err error
 
 	// init_config
@@ -670,6 +663,30 @@ func (ac *AutoConfig) processDelService(svc listeners.Service) {
 	})
 }
 
+// processFileConfigs stores the JMX metric configs found by the file provider,
+// refreshes the config errors and returns the configs that can be scheduled
+func (ac *AutoConfig) processFileConfigs(fileConfPd *providers.FileConfigProvider, cfgs []integration.Config) []integration.Config {
+	var goodConfs []integration.Config
+	for _, cfg := range cfgs {
+		// JMX checks can have 2 YAML files: one containing the metrics to collect, one containing the
+		// instance configuration
+		// If the file provider finds any of these metric YAMLs, we store them in a map for future access
+		if cfg.MetricConfig != nil {
+			// We don't want to save metric files, it's enough to store them in the map
+			ac.store.setJMXMetricsForConfigName(cfg.Name, cfg.MetricConfig)
+			continue
+		}
+
+		goodConfs = append(goodConfs, cfg)
+	}
+
+	// The errors are tracked per file by the provider, a valid file must not
+	// clear the error of another file of the same integration
+	errorStats.setConfigErrors(fileConfPd.IntegrationErrors())
+
+	return goodConfs
+}
+
 // GetAutodiscoveryErrors fetches AD errors from each ConfigProvider
 func (ac *AutoConfig) GetAutodiscoveryErrors() map[string]map[string]providers.ErrorMsgSet {
 	errors := map[string]map[string]providers.ErrorMsgSet{}
@@ -96,6 +96,10 @@ func (pd *configPoller) poll(ac *AutoConfig) {
 			// retrieve the list of newly added configurations as well
 			// as removed configurations
 			newConfigs, removedConfigs := pd.collect(ctx)
+			if fileConfPd, ok := pd.provider.(*providers.FileConfigProvider); ok {
+				newConfigs = ac.processFileConfigs(fileConfPd, newConfigs)
+				removedConfigs = withoutMetricConfigs(removedConfigs)
+			}
 			if len(newConfigs) > 0 || len(removedConfigs) > 0 {
 				log.Infof("%v provider: collected %d new configurations, removed %d", pd.provider, len(newConfigs), len(removedConfigs))
 			} else {
@@ -143,3 +147,15 @@ func (pd *configPoller) collect(ctx context.Context) ([]integration.Config, []in
 	}
 	return newConf, removedConf
 }
+
+// withoutMetricConfigs filters out the JMX metric configs, they are never
+// scheduled so they must not be unscheduled either.
+func withoutMetricConfigs(configs []integration.Config) []integration.Config {
+	var filtered []integration.Config
+	for _, c := range configs {
+		if c.MetricConfig == nil {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
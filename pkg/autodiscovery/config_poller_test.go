@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package autodiscovery
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/scheduler"
+)
+
+func TestWithoutMetricConfigs(t *testing.T) {
+	configs := []integration.Config{
+		{Name: "foo", Instances: []integration.Data{integration.Data("{}")}},
+		{Name: "foo", MetricConfig: integration.Data("{}")},
+	}
+	filtered := withoutMetricConfigs(configs)
+	require.Len(t, filtered, 1)
+	assert.Nil(t, filtered[0].MetricConfig)
+}
+
+func TestFileConfigPollerCollect(t *testing.T) {
+	defer func(stats *acErrorStats) { errorStats = stats }(errorStats)
+	errorStats = newAcErrorStats()
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	confDir := filepath.Join(dir, "foo.d")
+	require.NoError(t, os.Mkdir(confDir, 0755))
+	now := time.Now()
+	write := func(name string, content string, modTime time.Time) {
+		path := filepath.Join(confDir, name)
+		require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	write("conf.yaml", "instances:\n  - host: a\n", now)
+	write("metrics.yaml", "jmx_metrics:\n  - include: {}\n", now)
+
+	ac := NewAutoConfig(scheduler.NewMetaScheduler())
+	fileProvider := providers.NewFileConfigProvider([]string{dir})
+	pd := newConfigPoller(fileProvider, true, time.Minute)
+
+	newConfigs, removedConfigs := pd.collect(ctx)
+	newConfigs = ac.processFileConfigs(fileProvider, newConfigs)
+	require.Len(t, newConfigs, 1)
+	assert.Len(t, removedConfigs, 0)
+	assert.NotNil(t, ac.store.getJMXMetricsForConfigName("foo"))
+	assert.Len(t, errorStats.getConfigErrors(), 0)
+
+	// an invalid file is reported without unscheduling the valid configs
+	write("other.yaml", "instances: [\n", now)
+	newConfigs, removedConfigs = pd.collect(ctx)
+	newConfigs = ac.processFileConfigs(fileProvider, newConfigs)
+	assert.Len(t, newConfigs, 0)
+	assert.Len(t, withoutMetricConfigs(removedConfigs), 0)
+	assert.Contains(t, errorStats.getConfigErrors(), "foo")
+
+	// a modified file replaces its previous config
+	write("conf.yaml", "instances:\n  - host: b\n", now.Add(time.Second))
+	newConfigs, removedConfigs = pd.collect(ctx)
+	newConfigs = ac.processFileConfigs(fileProvider, newConfigs)
+	require.Len(t, newConfigs, 1)
+	assert.Contains(t, string(newConfigs[0].Instances[0]), "host: b")
+	require.Len(t, withoutMetricConfigs(removedConfigs), 1)
+	assert.Contains(t, errorStats.getConfigErrors(), "foo")
+}
@@ -8,14 +8,18 @@ package configresolver
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"strconv"
 
+	yaml "gopkg.in/yaml.v2"
+
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/listeners"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/pkg/secrets"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/tmplvar"
@@ -50,6 +54,135 @@ func SubstituteTemplateEnvVars(config *integration.Config) error {
 	return retErr
 }
 
+// testing purpose
+var secretsFetch = secrets.Fetch
+
+// SubstituteTemplateSecrets replaces %%secret_<handle>%% in the string values of
+// the config init, instances, and logs config with the secrets returned by the
+// secret backend. The data is decoded before the replacement and encoded again
+// afterwards, so that the secrets containing YAML or JSON special characters
+// can't alter the config. Unlike the ENC[<handle>] values, these secrets aren't
+// cached: they're fetched every time the config is scheduled, so that the checks
+// rescheduled after a secret rotation use the new secret. All the handles of the
+// config are fetched with a single execution of the secret backend.
+func SubstituteTemplateSecrets(config *integration.Config) error {
+	type decodedData struct {
+		data   *integration.Data
+		value  interface{}
+		isJSON bool
+	}
+
+	var toReplace []decodedData
+	var handles []string
+	seen := make(map[string]struct{})
+	for _, toResolve := range dataToResolve(config) {
+		if !bytes.Contains(*toResolve, []byte("%%secret")) {
+			continue
+		}
+
+		d := decodedData{data: toResolve, isJSON: json.Valid(*toResolve)}
+		var err error
+		if d.isJSON {
+			err = json.Unmarshal(*toResolve, &d.value)
+		} else {
+			err = yaml.Unmarshal(*toResolve, &d.value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to decode the config to resolve its secrets: %s", err)
+		}
+
+		err = walkStrings(&d.value, func(str string) (string, error) {
+			for _, tVar := range tmplvar.Parse([]byte(str)) {
+				if "secret" != string(tVar.Name) {
+					continue
+				}
+				if len(tVar.Key) == 0 {
+					return str, fmt.Errorf("secret handle is missing in %s", tVar.Raw)
+				}
+				if _, found := seen[string(tVar.Key)]; !found {
+					seen[string(tVar.Key)] = struct{}{}
+					handles = append(handles, string(tVar.Key))
+				}
+			}
+			return str, nil
+		})
+		if err != nil {
+			return err
+		}
+		toReplace = append(toReplace, d)
+	}
+
+	if len(handles) == 0 {
+		return nil
+	}
+
+	values, err := secretsFetch(handles, config.Name)
+	if err != nil {
+		return fmt.Errorf("failed to fetch secrets %v: %s", handles, err)
+	}
+
+	for _, d := range toReplace {
+		walkStrings(&d.value, func(str string) (string, error) { //nolint:errcheck
+			res := []byte(str)
+			for _, tVar := range tmplvar.Parse(res) {
+				if "secret" == string(tVar.Name) {
+					res = bytes.Replace(res, tVar.Raw, []byte(values[string(tVar.Key)]), -1)
+				}
+			}
+			return string(res), nil
+		})
+
+		var data []byte
+		if d.isJSON {
+			data, err = json.Marshal(d.value)
+		} else {
+			data, err = yaml.Marshal(d.value)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to encode the config after resolving its secrets: %s", err)
+		}
+		*d.data = data
+	}
+
+	return nil
+}
+
+// walkStrings calls callback on every string value of a decoded YAML or JSON
+// document, and replaces the value by its result
+func walkStrings(data *interface{}, callback func(string) (string, error)) error {
+	switch v := (*data).(type) {
+	case string:
+		newValue, err := callback(v)
+		if err != nil {
+			return err
+		}
+		*data = newValue
+	case map[interface{}]interface{}:
+		for k := range v {
+			value := v[k]
+			if err := walkStrings(&value, callback); err != nil {
+				return err
+			}
+			v[k] = value
+		}
+	case map[string]interface{}:
+		for k := range v {
+			value := v[k]
+			if err := walkStrings(&value, callback); err != nil {
+				return err
+			}
+			v[k] = value
+		}
+	case []interface{}:
+		for i := range v {
+			if err := walkStrings(&v[i], callback); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Resolve takes a template and a service and generates a config with
 // valid connection info and relevant tags.
 // Resolve also returns the hash of the tags to the config.
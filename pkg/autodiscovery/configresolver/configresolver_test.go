@@ -16,6 +16,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/listeners"
+	"github.com/DataDog/datadog-agent/pkg/secrets"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 
 	// we need some valid check in the catalog to run tests
@@ -726,6 +727,88 @@ func TestResolve(t *testing.T) {
 	}
 }
 
+func TestSubstituteTemplateSecrets(t *testing.T) {
+	defer func() { secretsFetch = secrets.Fetch }()
+
+	fetches := 0
+	password := "password1"
+	secretsFetch = func(handles []string, origin string) (map[string]string, error) {
+		fetches++
+		assert.ElementsMatch(t, []string{"db_user", "db_password"}, handles)
+		assert.Equal(t, "postgres", origin)
+		return map[string]string{
+			"db_password": password,
+			"db_user":     "datadog",
+		}, nil
+	}
+
+	tpl := integration.Config{
+		Name:       "postgres",
+		InitConfig: integration.Data("{}"),
+		Instances: []integration.Data{
+			integration.Data("username: '%%secret_db_user%%'\npassword: '%%secret_db_password%%'"),
+			integration.Data("username: '%%secret_db_user%%'\npassword: '%%secret_db_password%%'\nhost: '%%host%%'"),
+		},
+	}
+
+	cfg := tpl
+	cfg.Instances = append([]integration.Data(nil), tpl.Instances...)
+	require.NoError(t, SubstituteTemplateSecrets(&cfg))
+	assert.Equal(t, 1, fetches)
+	assert.Equal(t, integration.Data("{}"), cfg.InitConfig)
+	assert.Equal(t, integration.Data("password: password1\nusername: datadog\n"), cfg.Instances[0])
+	// other template variables are left untouched
+	assert.Equal(t, integration.Data("host: '%%host%%'\npassword: password1\nusername: datadog\n"), cfg.Instances[1])
+
+	// the secrets are fetched again at each substitution
+	password = "rotated_password1"
+	cfg = tpl
+	cfg.Instances = append([]integration.Data(nil), tpl.Instances...)
+	require.NoError(t, SubstituteTemplateSecrets(&cfg))
+	assert.Equal(t, 2, fetches)
+	assert.Equal(t, integration.Data("password: rotated_password1\nusername: datadog\n"), cfg.Instances[0])
+
+	// the secrets are string values, whatever their characters
+	password = "p4ss: #word\nhost: evil"
+	cfg = tpl
+	cfg.Instances = append([]integration.Data(nil), tpl.Instances...)
+	require.NoError(t, SubstituteTemplateSecrets(&cfg))
+	assert.Equal(t, integration.Data("password: |-\n  p4ss: #word\n  host: evil\nusername: datadog\n"), cfg.Instances[0])
+
+	// the JSON data, e.g. the logs config of the container labels, stays JSON
+	password = `pass"word`
+	cfg = integration.Config{
+		Name:       "postgres",
+		LogsConfig: integration.Data(`[{"type":"file","path":"/var/log/%%secret_db_user%%.log","tags":["password:%%secret_db_password%%"]}]`),
+	}
+	require.NoError(t, SubstituteTemplateSecrets(&cfg))
+	assert.Equal(t, integration.Data(`[{"path":"/var/log/datadog.log","tags":["password:pass\"word"],"type":"file"}]`), cfg.LogsConfig)
+
+	// the keys aren't resolved
+	fetchesBefore := fetches
+	cfg = integration.Config{Name: "postgres", Instances: []integration.Data{integration.Data("'%%secret_db_user%%': user")}}
+	require.NoError(t, SubstituteTemplateSecrets(&cfg))
+	assert.Equal(t, fetchesBefore, fetches)
+
+	// no fetch without secret template variables
+	cfg = integration.Config{Name: "postgres", Instances: []integration.Data{integration.Data("password: ENC[db_password]")}}
+	require.NoError(t, SubstituteTemplateSecrets(&cfg))
+	assert.Equal(t, fetchesBefore, fetches)
+
+	cfg = integration.Config{Name: "postgres", Instances: []integration.Data{integration.Data("password: '%%secret%%'")}}
+	assert.Error(t, SubstituteTemplateSecrets(&cfg))
+}
+
+func TestSubstituteTemplateSecretsError(t *testing.T) {
+	defer func() { secretsFetch = secrets.Fetch }()
+	secretsFetch = func(handles []string, origin string) (map[string]string, error) {
+		return nil, fmt.Errorf("backend error")
+	}
+
+	cfg := integration.Config{Name: "postgres", Instances: []integration.Data{integration.Data("password: %%secret_db_password%%")}}
+	assert.Error(t, SubstituteTemplateSecrets(&cfg))
+}
+
 func newFakeContainerPorts() []listeners.ContainerPort {
 	return []listeners.ContainerPort{
 		{Port: 1, Name: "foo"},
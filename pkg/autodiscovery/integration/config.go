@@ -61,12 +61,14 @@ type Config struct {
 
 // CommonInstanceConfig holds the reserved fields for the yaml instance data
 type CommonInstanceConfig struct {
-	MinCollectionInterval int      `yaml:"min_collection_interval"`
-	EmptyDefaultHostname  bool     `yaml:"empty_default_hostname"`
-	Tags                  []string `yaml:"tags"`
-	Service               string   `yaml:"service"`
-	Name                  string   `yaml:"name"`
-	Namespace             string   `yaml:"namespace"`
+	MinCollectionInterval   int      `yaml:"min_collection_interval"`
+	AlignCollectionInterval bool     `yaml:"align_collection_interval,omitempty"`
+	CollectionJitter        int      `yaml:"collection_jitter,omitempty"`
+	EmptyDefaultHostname    bool     `yaml:"empty_default_hostname"`
+	Tags                    []string `yaml:"tags"`
+	Service                 string   `yaml:"service"`
+	Name                    string   `yaml:"name"`
+	Namespace               string   `yaml:"namespace"`
 }
 
 // CommonGlobalConfig holds the reserved fields for the yaml init_config data
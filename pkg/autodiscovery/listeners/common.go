@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"hash/fnv"
 	"strconv"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/common/types"
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -146,18 +147,32 @@ func newContainerFilters() (*containerFilters, error) {
 	}, nil
 }
 
-func (f *containerFilters) IsExcluded(filter containers.FilterType, name, image, ns string) bool {
+func (f *containerFilters) IsExcluded(filter containers.FilterType, info containers.ContainerFilterInfo) bool {
 	switch filter {
 	case containers.GlobalFilter:
-		return f.global.IsExcluded(name, image, ns)
+		return f.global.IsContainerExcluded(info)
 	case containers.MetricsFilter:
-		return f.metrics.IsExcluded(name, image, ns)
+		return f.metrics.IsContainerExcluded(info)
 	case containers.LogsFilter:
-		return f.logs.IsExcluded(name, image, ns)
+		return f.logs.IsContainerExcluded(info)
 	}
 	return false
 }
 
+// AgeFilterChangeTime returns the next time an age filter of the chosen
+// filter type starts or stops matching the container, zero if none does.
+func (f *containerFilters) AgeFilterChangeTime(filter containers.FilterType, info containers.ContainerFilterInfo, now time.Time) time.Time {
+	switch filter {
+	case containers.GlobalFilter:
+		return f.global.AgeFilterChangeTime(info, now)
+	case containers.MetricsFilter:
+		return f.metrics.AgeFilterChangeTime(info, now)
+	case containers.LogsFilter:
+		return f.logs.AgeFilterChangeTime(info, now)
+	}
+	return time.Time{}
+}
+
 // getPrometheusIncludeAnnotations returns the Prometheus AD include annotations based on the Prometheus config
 func getPrometheusIncludeAnnotations() types.PrometheusAnnotations {
 	annotations := types.PrometheusAnnotations{}
@@ -55,12 +55,13 @@ func (l *ContainerListener) createContainerService(
 	container := entity.(*workloadmeta.Container)
 
 	containerImg := container.Image
-	if l.IsExcluded(
-		containers.GlobalFilter,
-		container.Name,
-		containerImg.RawName,
-		"",
-	) {
+	filterInfo := containers.ContainerFilterInfo{
+		Name:      container.Name,
+		Image:     containerImg.RawName,
+		Labels:    container.Labels,
+		StartedAt: container.State.StartedAt,
+	}
+	if l.IsExcluded(containers.GlobalFilter, filterInfo) {
 		log.Debugf("container %s filtered out: name %q image %q", container.ID, container.Name, containerImg.RawName)
 		return
 	}
@@ -132,18 +133,8 @@ func (l *ContainerListener) createContainerService(
 		svc.ready = true
 		svc.hosts = hosts
 		svc.checkNames = checkNames
-		svc.metricsExcluded = l.IsExcluded(
-			containers.MetricsFilter,
-			container.Name,
-			containerImg.RawName,
-			"",
-		)
-		svc.logsExcluded = l.IsExcluded(
-			containers.LogsFilter,
-			container.Name,
-			containerImg.RawName,
-			"",
-		)
+		svc.metricsExcluded = l.IsExcluded(containers.MetricsFilter, filterInfo)
+		svc.logsExcluded = l.IsExcluded(containers.LogsFilter, filterInfo)
 	}
 
 	svcID := buildSvcID(container.GetID())
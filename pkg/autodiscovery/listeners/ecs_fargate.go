@@ -69,12 +69,13 @@ func (l *ECSFargateListener) createContainerService(
 	}
 
 	containerImg := container.Image
-	if l.IsExcluded(
-		containers.GlobalFilter,
-		container.Name,
-		containerImg.RawName,
-		"",
-	) {
+	filterInfo := containers.ContainerFilterInfo{
+		Name:      container.Name,
+		Image:     containerImg.RawName,
+		Labels:    container.Labels,
+		StartedAt: container.State.StartedAt,
+	}
+	if l.IsExcluded(containers.GlobalFilter, filterInfo) {
 		log.Debugf("container %s filtered out: name %q image %q", container.ID, container.Name, container.Image.RawName)
 		return
 	}
@@ -91,21 +92,11 @@ func (l *ECSFargateListener) createContainerService(
 			containerImg.RawName,
 			container.Labels,
 		),
-		creationTime: creationTime,
-		hosts:        hosts,
-		metricsExcluded: l.IsExcluded(
-			containers.MetricsFilter,
-			container.Name,
-			containerImg.RawName,
-			"",
-		),
-		logsExcluded: l.IsExcluded(
-			containers.LogsFilter,
-			container.Name,
-			containerImg.RawName,
-			"",
-		),
-		ready: true,
+		creationTime:    creationTime,
+		hosts:           hosts,
+		metricsExcluded: l.IsExcluded(containers.MetricsFilter, filterInfo),
+		logsExcluded:    l.IsExcluded(containers.LogsFilter, filterInfo),
+		ready:           true,
 	}
 
 	var err error
@@ -214,7 +214,11 @@ func processService(ksvc *v1.Service, firstRun bool) *KubeServiceService {
 	svc.tags = append(svc.tags, getStandardTags(ksvc.GetLabels())...)
 
 	// Hosts, only use internal ClusterIP for now
-	svc.hosts = map[string]string{"cluster": ksvc.Spec.ClusterIP}
+	// Headless services don't have one, their checks should be endpoint checks
+	svc.hosts = map[string]string{}
+	if ksvc.Spec.ClusterIP != v1.ClusterIPNone && ksvc.Spec.ClusterIP != "" {
+		svc.hosts["cluster"] = ksvc.Spec.ClusterIP
+	}
 
 	// Ports
 	var ports []ContainerPort
@@ -82,6 +82,25 @@ func TestProcessService(t *testing.T) {
 	assert.Equal(t, integration.After, svc.GetCreationTime())
 }
 
+func TestProcessHeadlessService(t *testing.T) {
+	ksvc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			UID:       types.UID("test"),
+			Name:      "myservice",
+			Namespace: "default",
+		},
+		Spec: v1.ServiceSpec{
+			ClusterIP: v1.ClusterIPNone,
+			Ports:     []v1.ServicePort{{Name: "test1", Port: 123}},
+		},
+	}
+
+	svc := processService(ksvc, true)
+	hosts, err := svc.GetHosts(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, hosts)
+}
+
 func TestServicesDiffer(t *testing.T) {
 	for name, tc := range map[string]struct {
 		first  *v1.Service
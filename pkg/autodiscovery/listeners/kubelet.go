@@ -119,12 +119,14 @@ func (l *KubeletListener) createContainerService(
 	creationTime integration.CreationTime,
 ) {
 	containerImg := container.Image
-	if l.IsExcluded(
-		containers.GlobalFilter,
-		container.Name,
-		containerImg.RawName,
-		pod.Namespace,
-	) {
+	filterInfo := containers.ContainerFilterInfo{
+		Name:      container.Name,
+		Image:     containerImg.RawName,
+		Namespace: pod.Namespace,
+		Labels:    pod.Labels,
+		StartedAt: container.State.StartedAt,
+	}
+	if l.IsExcluded(containers.GlobalFilter, filterInfo) {
 		log.Debugf("container %s filtered out: name %q image %q namespace %q", container.ID, container.Name, container.Image.RawName, pod.Namespace)
 		return
 	}
@@ -165,18 +167,8 @@ func (l *KubeletListener) createContainerService(
 
 		// Exclude non-running containers (including init containers)
 		// from metrics collection but keep them for collecting logs.
-		metricsExcluded: l.IsExcluded(
-			containers.MetricsFilter,
-			container.Name,
-			containerImg.RawName,
-			pod.Namespace,
-		) || !container.State.Running,
-		logsExcluded: l.IsExcluded(
-			containers.LogsFilter,
-			container.Name,
-			containerImg.RawName,
-			pod.Namespace,
-		),
+		metricsExcluded: l.IsExcluded(containers.MetricsFilter, filterInfo) || !container.State.Running,
+		logsExcluded:    l.IsExcluded(containers.LogsFilter, filterInfo),
 	}
 
 	adIdentifier := container.Name
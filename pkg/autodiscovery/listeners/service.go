@@ -180,6 +180,11 @@ func svcEqual(a, b Service) bool {
 		return false
 	}
 
+	if a.HasFilter(containers.MetricsFilter) != b.HasFilter(containers.MetricsFilter) ||
+		a.HasFilter(containers.LogsFilter) != b.HasFilter(containers.LogsFilter) {
+		return false
+	}
+
 	return a.GetCreationTime() == b.GetCreationTime() &&
 		a.IsReady(ctx) == b.IsReady(ctx)
 }
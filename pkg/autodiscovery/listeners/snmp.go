@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/persistentcache"
 	"github.com/DataDog/datadog-agent/pkg/snmp"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicelease"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -41,6 +42,7 @@ type SNMPListener struct {
 	stop       chan bool
 	config     snmp.ListenerConfig
 	services   map[string]Service
+	leaser     *devicelease.Leaser
 }
 
 // SNMPService implements and store results from the Service interface for the SNMP listener
@@ -168,6 +170,9 @@ func (l *SNMPListener) checkDevice(job snmpJob) {
 		} else if len(value.Variables) < 1 || value.Variables[0].Value == nil {
 			log.Debugf("SNMP get to %s no data", deviceIP)
 			l.deleteService(entityID, job.subnet)
+		} else if len(l.leaser.Acquire(snmpDeviceID(job.subnet, deviceIP))) == 0 {
+			log.Debugf("SNMP device %s is polled by another agent", deviceIP)
+			l.removeService(entityID, job.subnet)
 		} else {
 			log.Debugf("SNMP get to %s success: %v", deviceIP, value.Variables[0].Value)
 			l.createService(entityID, job.subnet, deviceIP, true)
@@ -219,6 +224,8 @@ func (l *SNMPListener) checkDevices() {
 		l.config.DiscoveryInterval = defaultDiscoveryInterval
 	}
 
+	l.leaser = devicelease.NewLeaser(time.Duration(l.config.DiscoveryInterval) * time.Second)
+
 	jobs := make(chan snmpJob)
 	for w := 0; w < l.config.Workers; w++ {
 		go worker(l, jobs)
@@ -299,6 +306,7 @@ func (l *SNMPListener) deleteService(entityID string, subnet *snmpSubnet) {
 		}
 
 		if l.config.AllowedFailures != -1 && failure >= l.config.AllowedFailures {
+			l.leaser.Release(snmpDeviceID(subnet, subnet.devices[entityID]))
 			l.delService <- svc
 			delete(l.services, entityID)
 			delete(subnet.devices, entityID)
@@ -307,6 +315,24 @@ func (l *SNMPListener) deleteService(entityID string, subnet *snmpSubnet) {
 	}
 }
 
+// removeService removes the service of a device leased to another agent
+func (l *SNMPListener) removeService(entityID string, subnet *snmpSubnet) {
+	l.Lock()
+	defer l.Unlock()
+	if svc, present := l.services[entityID]; present {
+		l.delService <- svc
+		delete(l.services, entityID)
+		delete(subnet.devices, entityID)
+		delete(subnet.deviceFailures, entityID)
+		l.writeCache(subnet)
+	}
+}
+
+// snmpDeviceID returns the ID of a device, used as lease key
+func snmpDeviceID(subnet *snmpSubnet, deviceIP string) string {
+	return subnet.config.Namespace + ":" + deviceIP
+}
+
 func incrementIP(ip net.IP) {
 	for i := len(ip) - 1; i >= 0; i-- {
 		ip[i]++
@@ -9,6 +9,7 @@ package listeners
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
@@ -17,6 +18,11 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
+// ageFilterRecheckInterval is the interval at which the entities whose
+// services may be filtered differently since an age filter started or stopped
+// matching their containers are processed again.
+const ageFilterRecheckInterval = 10 * time.Second
+
 // workloadmetaListener is a generic subscriber to workloadmeta events that
 // generates AD services.
 type workloadmetaListener interface {
@@ -34,7 +40,7 @@ type workloadmetaListener interface {
 
 	// IsExcluded returns whether a container should be excluded according
 	// to the chosen ft filter.
-	IsExcluded(ft containers.FilterType, name, image, ns string) bool
+	IsExcluded(ft containers.FilterType, info containers.ContainerFilterInfo) bool
 }
 
 // workloadmetaListenerImpl implements workloadmetaListener.
@@ -51,12 +57,28 @@ type workloadmetaListenerImpl struct {
 	services map[string]Service
 	children map[string]map[string]struct{}
 
+	// processing is the entity being processed by processFn, and
+	// processingSvcSeen whether its own service has been added.
+	processing             workloadmeta.Entity
+	processingCreationTime integration.CreationTime
+	processingSvcSeen      bool
+
+	// ageFilterRechecks holds, by service ID, the entities to process
+	// again once an age filter changes for their containers.
+	ageFilterRechecks map[string]ageFilterRecheck
+
 	newService chan<- Service
 	delService chan<- Service
 }
 
 var _ workloadmetaListener = &workloadmetaListenerImpl{}
 
+type ageFilterRecheck struct {
+	entity       workloadmeta.Entity
+	creationTime integration.CreationTime
+	at           time.Time
+}
+
 // newWorkloadmetaListener returns a new workloadmetaListener. It filters
 // workloadmeta events with the passed in workloadFilters, and processes each
 // event with processFn. processFn is expected to create AD services by calling
@@ -85,6 +107,8 @@ func newWorkloadmetaListener(
 
 		services: make(map[string]Service),
 		children: make(map[string]map[string]struct{}),
+
+		ageFilterRechecks: make(map[string]ageFilterRecheck),
 	}, nil
 }
 
@@ -93,6 +117,10 @@ func (l *workloadmetaListenerImpl) Store() workloadmeta.Store {
 }
 
 func (l *workloadmetaListenerImpl) AddService(svcID string, svc Service, parentSvcID string) {
+	if l.processing != nil && svcID == buildSvcID(l.processing.GetID()) {
+		l.processingSvcSeen = true
+	}
+
 	if old, found := l.services[svcID]; found {
 		if svcEqual(old, svc) {
 			log.Tracef("%s received a duplicated service '%s', ignoring", l.name, svc.GetEntity())
@@ -115,8 +143,45 @@ func (l *workloadmetaListenerImpl) AddService(svcID string, svc Service, parentS
 	}
 }
 
-func (l *workloadmetaListenerImpl) IsExcluded(ft containers.FilterType, name, image, ns string) bool {
-	return l.containerFilters.IsExcluded(ft, name, image, ns)
+// IsExcluded returns whether a container should be excluded according to the
+// chosen ft filter. When an age filter will start or stop matching the
+// container, the entity being processed is processed again at that time.
+func (l *workloadmetaListenerImpl) IsExcluded(ft containers.FilterType, info containers.ContainerFilterInfo) bool {
+	if l.processing != nil {
+		l.scheduleAgeFilterRecheck(l.containerFilters.AgeFilterChangeTime(ft, info, time.Now()))
+	}
+
+	return l.containerFilters.IsExcluded(ft, info)
+}
+
+func (l *workloadmetaListenerImpl) scheduleAgeFilterRecheck(at time.Time) {
+	if at.IsZero() {
+		return
+	}
+
+	svcID := buildSvcID(l.processing.GetID())
+	if recheck, found := l.ageFilterRechecks[svcID]; found && !recheck.at.After(at) {
+		return
+	}
+
+	l.ageFilterRechecks[svcID] = ageFilterRecheck{
+		entity:       l.processing,
+		creationTime: l.processingCreationTime,
+		at:           at,
+	}
+}
+
+// recheckAgeFilters processes again the entities for which an age filter
+// changed since they were last processed.
+func (l *workloadmetaListenerImpl) recheckAgeFilters(now time.Time) {
+	for _, recheck := range l.ageFilterRechecks {
+		if recheck.at.After(now) {
+			continue
+		}
+
+		log.Debugf("%s processing %q again, an age filter changed", l.name, recheck.entity.GetID().ID)
+		l.processSetEntity(recheck.entity, recheck.creationTime)
+	}
 }
 
 func (l *workloadmetaListenerImpl) Listen(newSvc chan<- Service, delSvc chan<- Service) {
@@ -126,6 +191,7 @@ func (l *workloadmetaListenerImpl) Listen(newSvc chan<- Service, delSvc chan<- S
 	ch := l.store.Subscribe(l.name, l.workloadFilters)
 	health := health.RegisterLiveness(l.name)
 	creationTime := integration.Before
+	ageFilterTicker := time.NewTicker(ageFilterRecheckInterval)
 
 	log.Infof("%s initialized successfully", l.name)
 
@@ -138,7 +204,12 @@ func (l *workloadmetaListenerImpl) Listen(newSvc chan<- Service, delSvc chan<- S
 
 			case <-health.C:
 
+			case now := <-ageFilterTicker.C:
+				l.recheckAgeFilters(now)
+
 			case <-l.stop:
+				ageFilterTicker.Stop()
+
 				err := health.Deregister()
 				if err != nil {
 					log.Warnf("error de-registering health check: %s", err)
@@ -179,6 +250,8 @@ func (l *workloadmetaListenerImpl) processEvents(evBundle workloadmeta.EventBund
 
 func (l *workloadmetaListenerImpl) processSetEntity(entity workloadmeta.Entity, creationTime integration.CreationTime) {
 	svcID := buildSvcID(entity.GetID())
+	_, hadSvc := l.services[svcID]
+	delete(l.ageFilterRechecks, svcID)
 
 	// keep track of children of this entity from previous iterations ...
 	unseen := make(map[string]struct{})
@@ -190,8 +263,20 @@ func (l *workloadmetaListenerImpl) processSetEntity(entity workloadmeta.Entity,
 	// iteration.
 	l.children[svcID] = make(map[string]struct{})
 
+	l.processing = entity
+	l.processingCreationTime = creationTime
+	l.processingSvcSeen = false
+
 	l.processFn(entity, creationTime)
 
+	l.processing = nil
+
+	// the service of the entity itself is removed when it's not created
+	// anymore, e.g. once its container is excluded by an age filter
+	if hadSvc && !l.processingSvcSeen {
+		l.removeService(svcID)
+	}
+
 	// remove the children seen in this iteration from the unseen list ...
 	for childSvcID := range l.children[svcID] {
 		delete(unseen, childSvcID)
@@ -207,6 +292,7 @@ func (l *workloadmetaListenerImpl) processUnsetEntity(entity workloadmeta.Entity
 	entityID := entity.GetID()
 	parentSvcID := buildSvcID(entityID)
 
+	delete(l.ageFilterRechecks, parentSvcID)
 	l.removeService(parentSvcID)
 
 	childrenSvcIDs := l.children[parentSvcID]
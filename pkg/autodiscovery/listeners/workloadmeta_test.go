@@ -9,9 +9,12 @@ package listeners
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 	workloadmetatesting "github.com/DataDog/datadog-agent/pkg/workloadmeta/testing"
@@ -48,8 +51,8 @@ func (l *testWorkloadmetaListener) AddService(svcID string, svc Service, parentS
 	}
 }
 
-func (l *testWorkloadmetaListener) IsExcluded(ft containers.FilterType, name string, image string, ns string) bool {
-	return l.filters.IsExcluded(ft, name, image, ns)
+func (l *testWorkloadmetaListener) IsExcluded(ft containers.FilterType, info containers.ContainerFilterInfo) bool {
+	return l.filters.IsExcluded(ft, info)
 }
 
 func (l *testWorkloadmetaListener) assertServices(expectedServices map[string]wlmListenerSvc) {
@@ -83,3 +86,89 @@ func newTestWorkloadmetaListener(t *testing.T) *testWorkloadmetaListener {
 		services: make(map[string]wlmListenerSvc),
 	}
 }
+
+func TestWorkloadmetaListenerAgeFilterRecheck(t *testing.T) {
+	global, err := containers.NewFilter(nil, []string{"age<5m"})
+	require.NoError(t, err)
+	metrics, err := containers.NewFilter(nil, nil)
+	require.NoError(t, err)
+	logs, err := containers.NewFilter(nil, []string{"age>1h"})
+	require.NoError(t, err)
+
+	newSvc := make(chan Service, 10)
+	delSvc := make(chan Service, 10)
+	l := &ContainerListener{}
+	impl := &workloadmetaListenerImpl{
+		name:              "test",
+		containerFilters:  &containerFilters{global: global, metrics: metrics, logs: logs},
+		services:          make(map[string]Service),
+		children:          make(map[string]map[string]struct{}),
+		ageFilterRechecks: make(map[string]ageFilterRecheck),
+		newService:        newSvc,
+		delService:        delSvc,
+	}
+	impl.processFn = l.createContainerService
+	l.workloadmetaListener = impl
+
+	container := &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   "foobarquux",
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Name: "foobarquux",
+		},
+		Image: workloadmeta.ContainerImage{
+			RawName: "foo/bar:latest",
+		},
+		State: workloadmeta.ContainerState{
+			Running:   true,
+			StartedAt: time.Now().Add(-4 * time.Minute),
+		},
+		Runtime: workloadmeta.ContainerRuntimeDocker,
+	}
+	svcID := buildSvcID(container.GetID())
+
+	// the young container is excluded until it's 5 minutes old
+	impl.processSetEntity(container, integration.Before)
+	assert.Empty(t, impl.services)
+	require.Contains(t, impl.ageFilterRechecks, svcID)
+	recheckAt := impl.ageFilterRechecks[svcID].at
+	assert.Equal(t, container.State.StartedAt.Add(5*time.Minute), recheckAt)
+
+	impl.recheckAgeFilters(recheckAt.Add(-time.Second))
+	assert.Empty(t, impl.services)
+
+	// time passes, the container isn't excluded anymore
+	container.State.StartedAt = container.State.StartedAt.Add(-2 * time.Minute)
+	impl.recheckAgeFilters(recheckAt)
+	require.Contains(t, impl.services, svcID)
+	assert.False(t, impl.services[svcID].HasFilter(containers.LogsFilter))
+	assert.Len(t, newSvc, 1)
+	<-newSvc
+
+	// until its logs are excluded after an hour
+	recheckAt = impl.ageFilterRechecks[svcID].at
+	assert.Equal(t, container.State.StartedAt.Add(time.Hour), recheckAt)
+	container.State.StartedAt = container.State.StartedAt.Add(-time.Hour)
+	impl.recheckAgeFilters(recheckAt)
+	assert.True(t, impl.services[svcID].HasFilter(containers.LogsFilter))
+	assert.Len(t, delSvc, 1)
+	assert.Len(t, newSvc, 1)
+	assert.NotContains(t, impl.ageFilterRechecks, svcID)
+
+	// the service is removed once the container is excluded again
+	global, err = containers.NewFilter(nil, []string{"age>1h"})
+	require.NoError(t, err)
+	impl.containerFilters.global = global
+	impl.processSetEntity(container, integration.After)
+	assert.Empty(t, impl.services)
+	assert.Len(t, delSvc, 2)
+
+	// the rechecks of the removed entities are dropped
+	container.State.StartedAt = time.Now()
+	impl.processSetEntity(container, integration.After)
+	require.Contains(t, impl.ageFilterRechecks, svcID)
+	impl.processUnsetEntity(container)
+	assert.Empty(t, impl.ageFilterRechecks)
+}
@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// CheckTemplatesConfigProvider implements the ConfigProvider interface for
+// the check templates served by the cluster-agent. It replaces the conf.d
+// folder of the agents joined to a cluster-agent.
+type CheckTemplatesConfigProvider struct {
+	dcaClient clusteragent.DCAClientInterface
+	// version pins the version of the templates, the latest one is used when it's empty
+	version string
+
+	etag    string
+	configs []integration.Config
+
+	m      sync.RWMutex
+	errors map[string]ErrorMsgSet
+}
+
+// NewCheckTemplatesConfigProvider returns a new ConfigProvider collecting
+// the check templates from the cluster-agent.
+// Connectivity is not checked at this stage to allow for retries, Collect will do it.
+func NewCheckTemplatesConfigProvider(cfg config.ConfigurationProviders) (ConfigProvider, error) {
+	c := &CheckTemplatesConfigProvider{
+		version: config.Datadog.GetString("cluster_agent.check_templates.version"),
+		errors:  make(map[string]ErrorMsgSet),
+	}
+	if err := c.initClient(); err != nil {
+		log.Warnf("Cannot get dca client: %v", err)
+	}
+	return c, nil
+}
+
+func (c *CheckTemplatesConfigProvider) initClient() error {
+	dcaClient, err := clusteragent.GetClusterAgentClient()
+	if err == nil {
+		c.dcaClient = dcaClient
+	}
+	return err
+}
+
+// String returns a string representation of the CheckTemplatesConfigProvider
+func (c *CheckTemplatesConfigProvider) String() string {
+	return names.CheckTemplates
+}
+
+// IsUpToDate queries the cluster-agent with the ETag of the templates, and
+// updates them when they changed. The templates are kept when the cluster-agent
+// cannot be reached.
+func (c *CheckTemplatesConfigProvider) IsUpToDate(ctx context.Context) (bool, error) {
+	if c.dcaClient == nil {
+		if err := c.initClient(); err != nil {
+			return c.etag != "", err
+		}
+	}
+
+	reply, etag, modified, err := c.dcaClient.GetCheckTemplates(ctx, c.version, c.etag)
+	if err != nil {
+		return c.etag != "", err
+	}
+	if !modified {
+		log.Tracef("Check templates up to date with version %s", etag)
+		return true, nil
+	}
+
+	log.Infof("Received version %s of the check templates from the cluster-agent", reply.Version)
+	configs, errors := parseCheckTemplates(reply)
+	c.configs = configs
+	c.etag = etag
+
+	c.m.Lock()
+	c.errors = errors
+	c.m.Unlock()
+	return false, nil
+}
+
+// Collect returns the check templates received from the cluster-agent
+func (c *CheckTemplatesConfigProvider) Collect(ctx context.Context) ([]integration.Config, error) {
+	if c.etag == "" {
+		if _, err := c.IsUpToDate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return c.configs, nil
+}
+
+// GetConfigErrors returns the errors of the invalid templates
+func (c *CheckTemplatesConfigProvider) GetConfigErrors() map[string]ErrorMsgSet {
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	return c.errors
+}
+
+// parseCheckTemplates returns the configurations of the valid templates, and
+// the errors of the invalid ones by template path.
+func parseCheckTemplates(reply types.CheckTemplatesResponse) ([]integration.Config, map[string]ErrorMsgSet) {
+	configs := make([]integration.Config, 0, len(reply.Templates))
+	errors := make(map[string]ErrorMsgSet)
+	for _, template := range reply.Templates {
+		conf, err := parseIntegrationConfig(template.Name, template.Path, []byte(template.Content))
+		if err != nil {
+			log.Warnf("Invalid check template %s at version %s: %v", template.Path, reply.Version, err)
+			errors[template.Path] = ErrorMsgSet{err.Error(): struct{}{}}
+			continue
+		}
+		conf.Source = fmt.Sprintf("%s:%s@%s", names.CheckTemplates, template.Path, reply.Version)
+		configs = append(configs, conf)
+	}
+	return configs, errors
+}
+
+func init() {
+	RegisterProvider("checktemplates", NewCheckTemplatesConfigProvider)
+}
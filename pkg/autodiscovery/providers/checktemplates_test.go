@@ -0,0 +1,42 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func TestParseCheckTemplates(t *testing.T) {
+	reply := types.CheckTemplatesResponse{
+		Version: "42",
+		Templates: []types.CheckTemplate{
+			{
+				Name:    "http_check",
+				Path:    "http_check.d/conf.yaml",
+				Content: "init_config:\ninstances:\n  - name: foo\n    url: http://foo\n",
+			},
+			{
+				Name:    "redisdb",
+				Path:    "redisdb.yaml",
+				Content: "init_config:\n",
+			},
+		},
+	}
+
+	configs, errors := parseCheckTemplates(reply)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "http_check", configs[0].Name)
+	assert.Len(t, configs[0].Instances, 1)
+	assert.Equal(t, "check-templates:http_check.d/conf.yaml@42", configs[0].Source)
+
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors, "redisdb.yaml")
+}
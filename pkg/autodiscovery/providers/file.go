@@ -12,7 +12,10 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/configresolver"
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
@@ -49,18 +52,43 @@ type configEntry struct {
 	err        error
 }
 
+// fileState is the cached result of reading a single configuration file.
+// conf holds the last valid configuration found in the file, err the error
+// returned by the last read, if any.
+type fileState struct {
+	modTime time.Time
+	size    int64
+	name    string
+	conf    integration.Config
+	valid   bool
+	err     error
+}
+
 // FileConfigProvider collect configuration files from disk
 type FileConfigProvider struct {
-	paths  []string
+	// Errors holds the parse errors of the configuration files, keyed by
+	// integration name. The map is replaced rather than modified by Collect,
+	// IntegrationErrors returns a copy of it that is safe to use concurrently.
 	Errors map[string]string
+
+	paths []string
+
+	// m guards all the fields below and Errors, Collect and IsUpToDate are
+	// called from the poller goroutine while the errors are read from the API
+	m          sync.RWMutex
+	files      map[string]fileState // absolute file path -> state
+	seen       map[string]struct{}  // files found during the ongoing Collect
+	fileErrors map[string]string    // absolute file path -> parse error
 }
 
 // NewFileConfigProvider creates a new FileConfigProvider searching for
 // configuration files on the given paths
 func NewFileConfigProvider(paths []string) *FileConfigProvider {
 	return &FileConfigProvider{
-		paths:  paths,
-		Errors: make(map[string]string),
+		Errors:     make(map[string]string),
+		paths:      paths,
+		files:      make(map[string]fileState),
+		fileErrors: make(map[string]string),
 	}
 }
 
@@ -72,6 +100,10 @@ func (c *FileConfigProvider) Collect(ctx context.Context) ([]integration.Config,
 	configNames := make(map[string]struct{}) // use this map as a python set
 	defaultConfigs := []integration.Config{}
 
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.seen = make(map[string]struct{})
+
 	for _, path := range c.paths {
 		log.Infof("%v: searching for configuration files at: %s", c, path)
 
@@ -130,12 +162,108 @@ func (c *FileConfigProvider) Collect(ctx context.Context) ([]integration.Config,
 		}
 	}
 
+	// forget about the files that have been removed since the last Collect
+	for absPath := range c.files {
+		if _, found := c.seen[absPath]; !found {
+			delete(c.files, absPath)
+		}
+	}
+	c.updateErrors()
+
 	return configs, nil
 }
 
-// IsUpToDate is not implemented for the file Providers as the files are not meant to change very often.
+// IsUpToDate returns true if no configuration file was added, removed or
+// modified since the last call to Collect.
 func (c *FileConfigProvider) IsUpToDate(ctx context.Context) (bool, error) {
-	return false, nil
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	found := 0
+	for _, path := range c.paths {
+		entries, err := readDirPtr(path)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				found++
+				if !c.isFileUpToDate(path, entry) {
+					return false, nil
+				}
+				continue
+			}
+
+			if filepath.Ext(entry.Name()) != ".d" {
+				continue
+			}
+			dirPath := filepath.Join(path, entry.Name())
+			subEntries, err := ioutil.ReadDir(dirPath)
+			if err != nil {
+				continue
+			}
+			for _, sEntry := range subEntries {
+				if sEntry.IsDir() {
+					continue
+				}
+				found++
+				if !c.isFileUpToDate(dirPath, sEntry) {
+					return false, nil
+				}
+			}
+		}
+	}
+
+	// a file was removed
+	return found == len(c.files), nil
+}
+
+// isFileUpToDate returns true if the file was already read with the same
+// modification time and size. It must be called with c.m held.
+func (c *FileConfigProvider) isFileUpToDate(path string, file os.FileInfo) bool {
+	state, found := c.files[filepath.Join(path, file.Name())]
+	return found && state.modTime.Equal(file.ModTime()) && state.size == file.Size()
+}
+
+// updateErrors rebuilds the errors reported by the provider from the errors
+// of the individual files, so that a valid file never hides the error of
+// another file of the same integration. It must be called with c.m held.
+func (c *FileConfigProvider) updateErrors() {
+	fileErrors := make(map[string]string)
+	byName := make(map[string][]string)
+	for absPath, state := range c.files {
+		if state.err == nil {
+			continue
+		}
+		fileErrors[absPath] = state.err.Error()
+		byName[state.name] = append(byName[state.name], absPath)
+	}
+
+	integrationErrors := make(map[string]string, len(byName))
+	for name, absPaths := range byName {
+		sort.Strings(absPaths)
+		msgs := make([]string, 0, len(absPaths))
+		for _, absPath := range absPaths {
+			msgs = append(msgs, fmt.Sprintf("%s: %s", absPath, fileErrors[absPath]))
+		}
+		integrationErrors[name] = strings.Join(msgs, "\n")
+	}
+	c.fileErrors = fileErrors
+	c.Errors = integrationErrors
+}
+
+// IntegrationErrors returns the parse errors of the configuration files,
+// keyed by integration name
+func (c *FileConfigProvider) IntegrationErrors() map[string]string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	errs := make(map[string]string, len(c.Errors))
+	for name, err := range c.Errors {
+		errs[name] = err
+	}
+	return errs
 }
 
 // String returns a string representation of the FileConfigProvider
@@ -143,9 +271,17 @@ func (c *FileConfigProvider) String() string {
 	return names.File
 }
 
-// GetConfigErrors is not implemented for the FileConfigProvider
+// GetConfigErrors returns the parse errors of the configuration files, keyed
+// by file path
 func (c *FileConfigProvider) GetConfigErrors() map[string]ErrorMsgSet {
-	return make(map[string]ErrorMsgSet)
+	c.m.RLock()
+	defer c.m.RUnlock()
+
+	errs := make(map[string]ErrorMsgSet, len(c.fileErrors))
+	for absPath, err := range c.fileErrors {
+		errs[absPath] = ErrorMsgSet{err: struct{}{}}
+	}
+	return errs
 }
 
 // collectEntry collects a file entry and return it's configuration if valid
@@ -160,6 +296,7 @@ func (c *FileConfigProvider) collectEntry(file os.FileInfo, path string, integra
 	// skip auto conf files based on the agent configuration
 	if fileName == "auto_conf.yaml" && containsString(config.Datadog.GetStringSlice("ignore_autoconf"), integrationName) {
 		log.Infof("Skipping 'auto_conf.yaml' for integration '%s'", integrationName)
+		c.trackFile(file, absPath, integrationName)
 		entry.err = fmt.Errorf("'auto_conf.yaml' for integration '%s' is skipped", integrationName)
 		return entry
 	}
@@ -188,16 +325,13 @@ func (c *FileConfigProvider) collectEntry(file os.FileInfo, path string, integra
 
 	if ext != ".yaml" && ext != ".yml" {
 		log.Tracef("Skipping file: %s", absPath)
+		c.trackFile(file, absPath, integrationName)
 		entry.err = errors.New("Invalid config file extension")
 		return entry
 	}
 
-	var err error
-	entry.conf, err = GetIntegrationConfigFromFile(integrationName, absPath)
-	if err != nil {
-		log.Warnf("%s is not a valid config file: %s", absPath, err)
-		c.Errors[integrationName] = err.Error()
-		entry.err = errors.New("Invalid config file format")
+	entry.conf, entry.err = c.readFile(file, absPath, integrationName)
+	if entry.err != nil {
 		return entry
 	}
 
@@ -206,11 +340,62 @@ func (c *FileConfigProvider) collectEntry(file os.FileInfo, path string, integra
 		entry.isLogsOnly = true
 	}
 
-	delete(c.Errors, integrationName) // noop if entry is nonexistant
-	log.Debug("Found valid configuration in file:", absPath)
 	return entry
 }
 
+// trackFile records a file that is not parsed, so that IsUpToDate doesn't
+// consider it as new. It must be called with c.m held.
+func (c *FileConfigProvider) trackFile(file os.FileInfo, absPath string, integrationName string) {
+	c.seen[absPath] = struct{}{}
+	c.files[absPath] = fileState{
+		modTime: file.ModTime(),
+		size:    file.Size(),
+		name:    integrationName,
+	}
+}
+
+// readFile parses the given file, unless it didn't change since the last
+// Collect. If a previously valid file becomes invalid, its last valid
+// configuration is kept so that a typo doesn't unschedule running checks,
+// and the error is reported through GetConfigErrors. It must be called with
+// c.m held.
+func (c *FileConfigProvider) readFile(file os.FileInfo, absPath string, integrationName string) (integration.Config, error) {
+	c.seen[absPath] = struct{}{}
+
+	state, found := c.files[absPath]
+	if found && state.name == integrationName && state.modTime.Equal(file.ModTime()) && state.size == file.Size() {
+		if !state.valid {
+			return state.conf, errors.New("Invalid config file format")
+		}
+		return state.conf, nil
+	}
+
+	if !found || state.name != integrationName {
+		state = fileState{name: integrationName}
+	}
+	state.modTime = file.ModTime()
+	state.size = file.Size()
+
+	conf, err := GetIntegrationConfigFromFile(integrationName, absPath)
+	if err != nil {
+		state.err = err
+		c.files[absPath] = state
+		if state.valid {
+			log.Warnf("%s is not a valid config file, keeping its last valid configuration: %s", absPath, err)
+			return state.conf, nil
+		}
+		log.Warnf("%s is not a valid config file: %s", absPath, err)
+		return conf, errors.New("Invalid config file format")
+	}
+
+	state.conf = conf
+	state.valid = true
+	state.err = nil
+	c.files[absPath] = state
+	log.Debug("Found valid configuration in file:", absPath)
+	return conf, nil
+}
+
 // collectDir collects entries in subdirectories of the main conf folder
 func (c *FileConfigProvider) collectDir(parentPath string, folder os.FileInfo) configPkg {
 	configs := []integration.Config{}
@@ -261,15 +446,29 @@ func (c *FileConfigProvider) collectDir(parentPath string, folder os.FileInfo) c
 
 // GetIntegrationConfigFromFile returns an instance of integration.Config if `fpath` points to a valid config file
 func GetIntegrationConfigFromFile(name, fpath string) (integration.Config, error) {
-	cf := configFormat{}
-	config := integration.Config{Name: name}
-
 	// Read file contents
 	// FIXME: ReadFile reads the entire file, possible security implications
 	yamlFile, err := readFilePtr(fpath)
+	if err != nil {
+		return integration.Config{Name: name}, err
+	}
+
+	config, err := parseIntegrationConfig(name, fpath, yamlFile)
 	if err != nil {
 		return config, err
 	}
+	config.Source = "file:" + fpath
+
+	return config, nil
+}
+
+// parseIntegrationConfig returns an instance of integration.Config if `yamlFile`
+// is a valid config file, `fpath` is its location used in the warnings
+func parseIntegrationConfig(name, fpath string, yamlFile []byte) (integration.Config, error) {
+	cf := configFormat{}
+	config := integration.Config{Name: name}
+
+	yamlFile = expandEnvVars(yamlFile)
 
 	// Parse configuration
 	// Try UnmarshalStrict first, so we can warn about duplicated keys
@@ -329,9 +528,20 @@ func GetIntegrationConfigFromFile(name, fpath string) (integration.Config, error
 	// Interpolate env vars. Returns an error a variable wasn't subsituted, ignore it.
 	_ = configresolver.SubstituteTemplateEnvVars(&config)
 
-	config.Source = "file:" + fpath
+	return config, nil
+}
 
-	return config, err
+// expandEnvVars expands the ${VAR:-default} references in the values of a configuration
+// file, the invalid files are returned as is for the parsing to report the error
+func expandEnvVars(yamlFile []byte) []byte {
+	if !config.Datadog.GetBool("config_env_var_expansion") {
+		return yamlFile
+	}
+	expandedFile, err := config.ExpandEnvVars(yamlFile)
+	if err != nil {
+		return yamlFile
+	}
+	return expandedFile
 }
 
 func containsString(slice []string, str string) bool {
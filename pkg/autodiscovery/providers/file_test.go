@@ -7,8 +7,11 @@ package providers
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -166,3 +169,146 @@ func TestEnvVarReplacement(t *testing.T) {
 	assert.Len(t, rc[0].Instances, 2)
 	assert.Contains(t, string(rc[0].Instances[1]), "test_envvar_not_set")
 }
+
+func TestEnvVarExpansion(t *testing.T) {
+	os.Setenv("TEST_EXPANSION_HOST", "db.local")
+	os.Unsetenv("TEST_EXPANSION_PORT")
+	defer os.Unsetenv("TEST_EXPANSION_HOST")
+
+	dir, err := ioutil.TempDir("", "expansion")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "conf.yaml")
+	writeConfigFile(t, path, "instances:\n  - host: ${TEST_EXPANSION_HOST}\n    port: ${TEST_EXPANSION_PORT:-5432}\n    query: $${literal}\n", time.Now())
+
+	mockConfig := config.Mock()
+	mockConfig.Set("config_env_var_expansion", true)
+	defer mockConfig.Set("config_env_var_expansion", false)
+	conf, err := GetIntegrationConfigFromFile("postgres", path)
+	require.NoError(t, err)
+	require.Len(t, conf.Instances, 1)
+	assert.Equal(t, "host: db.local\nport: \"5432\"\nquery: ${literal}\n", string(conf.Instances[0]))
+
+	mockConfig.Set("config_env_var_expansion", false)
+	conf, err = GetIntegrationConfigFromFile("postgres", path)
+	require.NoError(t, err)
+	assert.Contains(t, string(conf.Instances[0]), "${TEST_EXPANSION_HOST}")
+}
+
+func writeConfigFile(t *testing.T, path string, content string, modTime time.Time) {
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestCollectPartialApply(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	confDir := filepath.Join(dir, "foo.d")
+	require.NoError(t, os.Mkdir(confDir, 0755))
+	now := time.Now()
+	goodPath := filepath.Join(confDir, "good.yaml")
+	badPath := filepath.Join(confDir, "bad.yaml")
+	writeConfigFile(t, goodPath, "instances:\n  - host: a\n", now)
+	writeConfigFile(t, badPath, "instances: [\n", now)
+
+	provider := NewFileConfigProvider([]string{dir})
+	configs, err := provider.Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, "foo", configs[0].Name)
+	assert.Contains(t, string(configs[0].Instances[0]), "host: a")
+
+	// the error is reported for the invalid file only
+	errs := provider.GetConfigErrors()
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs, badPath)
+	assert.Contains(t, provider.IntegrationErrors(), "foo")
+
+	// the valid file is reloaded despite the invalid one
+	writeConfigFile(t, goodPath, "instances:\n  - host: b\n", now.Add(time.Second))
+	configs, err = provider.Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Contains(t, string(configs[0].Instances[0]), "host: b")
+	assert.Contains(t, provider.GetConfigErrors(), badPath)
+
+	// fixing the invalid file clears its error
+	writeConfigFile(t, badPath, "instances:\n  - host: c\n", now.Add(time.Second))
+	configs, err = provider.Collect(ctx)
+	require.NoError(t, err)
+	assert.Len(t, configs, 2)
+	assert.Len(t, provider.GetConfigErrors(), 0)
+	assert.Len(t, provider.IntegrationErrors(), 0)
+}
+
+func TestCollectKeepsLastValidConfig(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "foo.yaml")
+	now := time.Now()
+	writeConfigFile(t, path, "instances:\n  - host: a\n", now)
+
+	provider := NewFileConfigProvider([]string{dir})
+	configs, err := provider.Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	valid := configs[0]
+
+	writeConfigFile(t, path, "instances: [\n", now.Add(time.Second))
+	configs, err = provider.Collect(ctx)
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Equal(t, valid.Digest(), configs[0].Digest())
+	assert.Contains(t, provider.GetConfigErrors(), path)
+	assert.Contains(t, provider.IntegrationErrors(), "foo")
+}
+
+func TestFileIsUpToDate(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	confDir := filepath.Join(dir, "foo.d")
+	require.NoError(t, os.Mkdir(confDir, 0755))
+	now := time.Now()
+	path := filepath.Join(confDir, "conf.yaml")
+	otherPath := filepath.Join(dir, "bar.yaml")
+	writeConfigFile(t, path, "instances:\n  - host: a\n", now)
+	writeConfigFile(t, otherPath, "instances:\n  - host: a\n", now)
+
+	provider := NewFileConfigProvider([]string{dir})
+
+	// nothing was collected yet
+	upToDate, err := provider.IsUpToDate(ctx)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+
+	_, err = provider.Collect(ctx)
+	require.NoError(t, err)
+	upToDate, err = provider.IsUpToDate(ctx)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+
+	// modified file
+	writeConfigFile(t, path, "instances:\n  - host: b\n", now.Add(time.Second))
+	upToDate, err = provider.IsUpToDate(ctx)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+
+	_, err = provider.Collect(ctx)
+	require.NoError(t, err)
+	upToDate, err = provider.IsUpToDate(ctx)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+
+	// removed file
+	require.NoError(t, os.Remove(otherPath))
+	upToDate, err = provider.IsUpToDate(ctx)
+	require.NoError(t, err)
+	assert.False(t, upToDate)
+
+	configs, err := provider.Collect(ctx)
+	require.NoError(t, err)
+	assert.Len(t, configs, 1)
+	upToDate, err = provider.IsUpToDate(ctx)
+	require.NoError(t, err)
+	assert.True(t, upToDate)
+}
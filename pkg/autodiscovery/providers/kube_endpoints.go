@@ -14,9 +14,11 @@ import (
 	"sync"
 
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/labels"
 	listersv1 "k8s.io/client-go/listers/core/v1"
+	listersdiscoveryv1 "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/common/utils"
@@ -40,10 +42,11 @@ const (
 // kubeEndpointsConfigProvider implements the ConfigProvider interface for the apiserver.
 type kubeEndpointsConfigProvider struct {
 	sync.RWMutex
-	serviceLister      listersv1.ServiceLister
-	endpointsLister    listersv1.EndpointsLister
-	upToDate           bool
-	monitoredEndpoints map[string]bool
+	serviceLister        listersv1.ServiceLister
+	endpointsLister      listersv1.EndpointsLister
+	endpointSlicesLister listersdiscoveryv1.EndpointSliceLister
+	upToDate             bool
+	monitoredEndpoints   map[string]bool
 }
 
 // configInfo contains an endpoint check config template with its name and namespace
@@ -79,6 +82,24 @@ func NewKubeEndpointsConfigProvider(config config.ConfigurationProviders) (Confi
 		DeleteFunc: p.invalidate,
 	})
 
+	// EndpointSlices are required on large clusters, where Endpoints are truncated to 1000 addresses
+	if config.Datadog.GetBool("cluster_checks.endpoint_slices_enabled") {
+		endpointSlicesInformer := ac.InformerFactory.Discovery().V1().EndpointSlices()
+		if endpointSlicesInformer == nil {
+			return nil, fmt.Errorf("cannot get endpoint slice informer: %s", err)
+		}
+
+		p.endpointSlicesLister = endpointSlicesInformer.Lister()
+
+		endpointSlicesInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    p.invalidateEndpointSlice,
+			UpdateFunc: p.invalidateIfChangedEndpointSlice,
+			DeleteFunc: p.invalidateEndpointSlice,
+		})
+
+		return p, nil
+	}
+
 	endpointsInformer := ac.InformerFactory.Core().V1().Endpoints()
 	if endpointsInformer == nil {
 		return nil, fmt.Errorf("cannot get endpoint informer: %s", err)
@@ -109,12 +130,22 @@ func (k *kubeEndpointsConfigProvider) Collect(ctx context.Context) ([]integratio
 	var generatedConfigs []integration.Config
 	parsedConfigsInfo := parseServiceAnnotationsForEndpoints(services)
 	for _, config := range parsedConfigsInfo {
-		kep, err := k.endpointsLister.Endpoints(config.namespace).Get(config.name)
-		if err != nil {
-			log.Errorf("Cannot get Kubernetes endpoints: %s", err)
-			continue
+		if k.endpointSlicesLister != nil {
+			selector := labels.Set{discoveryv1.LabelServiceName: config.name}.AsSelector()
+			slices, err := k.endpointSlicesLister.EndpointSlices(config.namespace).List(selector)
+			if err != nil {
+				log.Errorf("Cannot get Kubernetes endpoint slices: %s", err)
+				continue
+			}
+			generatedConfigs = append(generatedConfigs, generateConfigsFromSlices(config.tpl, config.resolveMode, config.namespace, config.name, slices)...)
+		} else {
+			kep, err := k.endpointsLister.Endpoints(config.namespace).Get(config.name)
+			if err != nil {
+				log.Errorf("Cannot get Kubernetes endpoints: %s", err)
+				continue
+			}
+			generatedConfigs = append(generatedConfigs, generateConfigs(config.tpl, config.resolveMode, kep)...)
 		}
-		generatedConfigs = append(generatedConfigs, generateConfigs(config.tpl, config.resolveMode, kep)...)
 		endpointsID := apiserver.EntityForEndpoints(config.namespace, config.name, "")
 		k.Lock()
 		k.monitoredEndpoints[endpointsID] = true
@@ -198,6 +229,60 @@ func (k *kubeEndpointsConfigProvider) invalidateIfChangedEndpoints(old, obj inte
 	return
 }
 
+func (k *kubeEndpointsConfigProvider) invalidateEndpointSlice(obj interface{}) {
+	// Slices are deleted through a tombstone when the deletion was missed by the informer
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	castedObj, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		log.Errorf("Expected an EndpointSlice type, got: %T", obj)
+		return
+	}
+	// Slices are added and removed as the service scales, invalidate monitored ones
+	endpointsID := endpointsIDForSlice(castedObj)
+	k.Lock()
+	defer k.Unlock()
+	if found := k.monitoredEndpoints[endpointsID]; found {
+		log.Tracef("Invalidating configs on new/deleted endpoint slice, endpoints entity: %s", endpointsID)
+		k.upToDate = false
+	}
+}
+
+func (k *kubeEndpointsConfigProvider) invalidateIfChangedEndpointSlice(old, obj interface{}) {
+	// Cast the updated object, don't invalidate on casting error.
+	// nil pointers are safely handled by the casting logic.
+	castedObj, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		log.Errorf("Expected an EndpointSlice type, got: %T", obj)
+		return
+	}
+	// Cast the old object, invalidate on casting error
+	castedOld, ok := old.(*discoveryv1.EndpointSlice)
+	if !ok {
+		log.Errorf("Expected an EndpointSlice type, got: %T", old)
+		k.setUpToDate(false)
+		return
+	}
+	// Quick exit if resversion did not change
+	if castedObj.ResourceVersion == castedOld.ResourceVersion {
+		return
+	}
+	// Make sure we invalidate a monitored endpoints object
+	endpointsID := endpointsIDForSlice(castedObj)
+	k.Lock()
+	defer k.Unlock()
+	if found := k.monitoredEndpoints[endpointsID]; found && !equality.Semantic.DeepEqual(castedObj.Endpoints, castedOld.Endpoints) {
+		// Invalidate only when endpoints change
+		k.upToDate = false
+	}
+}
+
+// endpointsIDForSlice returns the endpoints entity of the service owning an EndpointSlice
+func endpointsIDForSlice(slice *discoveryv1.EndpointSlice) string {
+	return apiserver.EntityForEndpoints(slice.Namespace, slice.Labels[discoveryv1.LabelServiceName], "")
+}
+
 // setUpToDate is a thread-safe method to update the upToDate value
 func (k *kubeEndpointsConfigProvider) setUpToDate(v bool) {
 	k.Lock()
@@ -242,9 +327,58 @@ func generateConfigs(tpl integration.Config, resolveMode endpointResolveMode, ke
 		log.Warn("Nil Kubernetes Endpoints object, cannot generate config templates")
 		return []integration.Config{tpl}
 	}
+
+	var addresses []v1.EndpointAddress
+	for i := range kep.Subsets {
+		addresses = append(addresses, kep.Subsets[i].Addresses...)
+	}
+	return generateConfigsForAddresses(tpl, resolveMode, kep.Namespace, kep.Name, addresses)
+}
+
+// generateConfigsFromSlices creates a config template for each ready IP of the
+// EndpointSlices of a service. Headless services without selector are supported
+// as long as their slices are labelled with the service name.
+func generateConfigsFromSlices(tpl integration.Config, resolveMode endpointResolveMode, namespace, name string, slices []*discoveryv1.EndpointSlice) []integration.Config {
+	// The same address can be listed by several slices while they are updated
+	seen := make(map[string]struct{})
+	addresses := []v1.EndpointAddress{}
+	for _, slice := range slices {
+		if slice == nil || slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		for _, endpoint := range slice.Endpoints {
+			// Ready is unset when unknown, which must be interpreted as ready.
+			// It's set for the not ready endpoints of services publishing them.
+			if ready := endpoint.Conditions.Ready; ready != nil && !*ready {
+				continue
+			}
+			for _, ip := range endpoint.Addresses {
+				if _, found := seen[ip]; found {
+					continue
+				}
+				seen[ip] = struct{}{}
+				addresses = append(addresses, v1.EndpointAddress{
+					IP:        ip,
+					Hostname:  stringValue(endpoint.Hostname),
+					NodeName:  endpoint.NodeName,
+					TargetRef: endpoint.TargetRef,
+				})
+			}
+		}
+	}
+	return generateConfigsForAddresses(tpl, resolveMode, namespace, name, addresses)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// generateConfigsForAddresses creates a config template for each address of the endpoints of a service
+func generateConfigsForAddresses(tpl integration.Config, resolveMode endpointResolveMode, namespace, name string, addresses []v1.EndpointAddress) []integration.Config {
 	generatedConfigs := []integration.Config{}
-	namespace := kep.Namespace
-	name := kep.Name
 
 	// Check resolve annotation to know how we should process this endpoint
 	var resolveFunc func(*integration.Config, v1.EndpointAddress)
@@ -262,30 +396,28 @@ func generateConfigs(tpl integration.Config, resolveMode endpointResolveMode, ke
 		resolveFunc = utils.ResolveEndpointConfigAuto
 	}
 
-	for i := range kep.Subsets {
-		for j := range kep.Subsets[i].Addresses {
-			// Set a new entity containing the endpoint's IP
-			entity := apiserver.EntityForEndpoints(namespace, name, kep.Subsets[i].Addresses[j].IP)
-			newConfig := integration.Config{
-				Entity:                  entity,
-				Name:                    tpl.Name,
-				Instances:               tpl.Instances,
-				InitConfig:              tpl.InitConfig,
-				MetricConfig:            tpl.MetricConfig,
-				LogsConfig:              tpl.LogsConfig,
-				ADIdentifiers:           []string{entity},
-				ClusterCheck:            true,
-				Provider:                tpl.Provider,
-				Source:                  tpl.Source,
-				IgnoreAutodiscoveryTags: tpl.IgnoreAutodiscoveryTags,
-			}
-
-			if resolveFunc != nil {
-				resolveFunc(&newConfig, kep.Subsets[i].Addresses[j])
-			}
+	for i := range addresses {
+		// Set a new entity containing the endpoint's IP
+		entity := apiserver.EntityForEndpoints(namespace, name, addresses[i].IP)
+		newConfig := integration.Config{
+			Entity:                  entity,
+			Name:                    tpl.Name,
+			Instances:               tpl.Instances,
+			InitConfig:              tpl.InitConfig,
+			MetricConfig:            tpl.MetricConfig,
+			LogsConfig:              tpl.LogsConfig,
+			ADIdentifiers:           []string{entity},
+			ClusterCheck:            true,
+			Provider:                tpl.Provider,
+			Source:                  tpl.Source,
+			IgnoreAutodiscoveryTags: tpl.IgnoreAutodiscoveryTags,
+		}
 
-			generatedConfigs = append(generatedConfigs, newConfig)
+		if resolveFunc != nil {
+			resolveFunc(&newConfig, addresses[i])
 		}
+
+		generatedConfigs = append(generatedConfigs, newConfig)
 	}
 	return generatedConfigs
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -288,6 +289,103 @@ func TestGenerateConfigs(t *testing.T) {
 	}
 }
 
+func TestGenerateConfigsFromSlices(t *testing.T) {
+	notReady := false
+	hostname := "web-0"
+	template := integration.Config{
+		Name:          "http_check",
+		ADIdentifiers: []string{"kube_endpoint_uid://default/myservice/"},
+		InitConfig:    integration.Data("{}"),
+		Instances:     []integration.Data{integration.Data("{\"url\":\"http://%%host%%\"}")},
+	}
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myservice-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "myservice"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{
+					Addresses: []string{"10.0.0.1"},
+					Hostname:  &hostname,
+					NodeName:  &nodename1,
+					TargetRef: &v1.ObjectReference{UID: types.UID("pod-uid-1"), Kind: "Pod"},
+				},
+				{
+					Addresses:  []string{"10.0.0.2"},
+					Conditions: discoveryv1.EndpointConditions{Ready: &notReady},
+					NodeName:   &nodename2,
+				},
+			},
+		},
+		{
+			// the same endpoint can be listed by two slices while they are updated
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myservice-fghij",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "myservice"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{
+				{
+					Addresses: []string{"10.0.0.1"},
+					NodeName:  &nodename1,
+					TargetRef: &v1.ObjectReference{UID: types.UID("pod-uid-1"), Kind: "Pod"},
+				},
+				{
+					Addresses: []string{"10.0.0.3"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myservice-fqdn",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "myservice"},
+			},
+			AddressType: discoveryv1.AddressTypeFQDN,
+			Endpoints: []discoveryv1.Endpoint{
+				{Addresses: []string{"myservice.example.com"}},
+			},
+		},
+	}
+
+	expectedOut := []integration.Config{
+		{
+			Entity:        "kube_endpoint_uid://default/myservice/10.0.0.1",
+			Name:          "http_check",
+			ADIdentifiers: []string{"kube_endpoint_uid://default/myservice/10.0.0.1", "kubernetes_pod://pod-uid-1"},
+			InitConfig:    integration.Data("{}"),
+			Instances:     []integration.Data{integration.Data("{\"url\":\"http://%%host%%\"}")},
+			ClusterCheck:  true,
+			NodeName:      "node1",
+		},
+		{
+			Entity:        "kube_endpoint_uid://default/myservice/10.0.0.3",
+			Name:          "http_check",
+			ADIdentifiers: []string{"kube_endpoint_uid://default/myservice/10.0.0.3"},
+			InitConfig:    integration.Data("{}"),
+			Instances:     []integration.Data{integration.Data("{\"url\":\"http://%%host%%\"}")},
+			ClusterCheck:  true,
+		},
+	}
+	cfgs := generateConfigsFromSlices(template, kubeEndpointResolveAuto, "default", "myservice", slices)
+	assert.EqualValues(t, expectedOut, cfgs)
+}
+
+func TestEndpointsIDForSlice(t *testing.T) {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "myservice-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "myservice"},
+		},
+	}
+	assert.Equal(t, apiserver.EntityForEndpoints("default", "myservice", ""), endpointsIDForSlice(slice))
+}
+
 func TestInvalidateIfChangedService(t *testing.T) {
 	s88 := &v1.Service{
 		ObjectMeta: metav1.ObjectMeta{
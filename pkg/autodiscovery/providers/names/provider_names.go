@@ -9,6 +9,7 @@ package names
 const (
 	Consul             = "consul"
 	Container          = "container"
+	CheckTemplates     = "check-templates"
 	CloudFoundryBBS    = "cloudfoundry-bbs"
 	ClusterChecks      = "cluster-checks"
 	ECS                = "ecs"
@@ -40,6 +40,17 @@ func (es *acErrorStats) removeConfigError(checkName string) {
 	delete(es.config, checkName)
 }
 
+// setConfigErrors will safely replace the errors of all the check configuration files
+func (es *acErrorStats) setConfigErrors(errs map[string]string) {
+	es.m.Lock()
+	defer es.m.Unlock()
+
+	es.config = make(map[string]string, len(errs))
+	for k, v := range errs {
+		es.config[k] = v
+	}
+}
+
 // getConfigErrors will safely get the errors a check config file
 func (es *acErrorStats) getConfigErrors() map[string]string {
 	es.m.RLock()
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package checktemplates
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/common"
+)
+
+// configMapBackend reads the templates from a ConfigMap, each key being a
+// `<name>.yaml` configuration file. The version of the templates is the
+// resource version of the ConfigMap.
+type configMapBackend struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+func newConfigMapBackendFromConfig() (Backend, error) {
+	apiCl, err := apiserver.GetAPIClient()
+	if err != nil {
+		return nil, err
+	}
+
+	namespace := config.Datadog.GetString("cluster_agent.check_templates.configmap.namespace")
+	if namespace == "" {
+		namespace = common.GetResourcesNamespace()
+	}
+
+	return &configMapBackend{
+		client:    apiCl.Cl,
+		namespace: namespace,
+		name:      config.Datadog.GetString("cluster_agent.check_templates.configmap.name"),
+	}, nil
+}
+
+// Fetch returns the templates of the ConfigMap
+func (b *configMapBackend) Fetch(ctx context.Context) (*types.CheckTemplatesResponse, error) {
+	cm, err := b.client.CoreV1().ConfigMaps(b.namespace).Get(ctx, b.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &types.CheckTemplatesResponse{
+		Version:   cm.ResourceVersion,
+		Templates: []types.CheckTemplate{},
+	}
+	for key, content := range cm.Data {
+		if !isTemplateFile(key) {
+			continue
+		}
+		response.Templates = append(response.Templates, types.CheckTemplate{
+			Name:    templateName(key),
+			Path:    key,
+			Content: content,
+		})
+	}
+	sort.Slice(response.Templates, func(i, j int) bool {
+		return response.Templates[i].Path < response.Templates[j].Path
+	})
+
+	return response, nil
+}
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checktemplates
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// commitRegexp matches the versions of the templates served by the git backend
+var commitRegexp = regexp.MustCompile("^[0-9a-f]{40}$")
+
+// gitBackend reads the templates from a Git repository, mirrored locally with
+// the git command. The version of the templates is the commit they are read from.
+type gitBackend struct {
+	repositoryURL string
+	ref           string
+	path          string
+	cachePath     string
+
+	// serializes the git commands updating the mirror, the commits are
+	// immutable so reading them doesn't need it
+	m sync.Mutex
+}
+
+func newGitBackendFromConfig() (*gitBackend, error) {
+	repositoryURL := config.Datadog.GetString("cluster_agent.check_templates.git.repository_url")
+	if repositoryURL == "" {
+		return nil, errors.New("cluster_agent.check_templates.git.repository_url must be set to use the git backend")
+	}
+	cachePath := config.Datadog.GetString("cluster_agent.check_templates.git.cache_path")
+	if cachePath == "" {
+		cachePath = filepath.Join(config.Datadog.GetString("run_path"), "check_templates")
+	}
+
+	return &gitBackend{
+		repositoryURL: repositoryURL,
+		ref:           config.Datadog.GetString("cluster_agent.check_templates.git.ref"),
+		path:          strings.Trim(config.Datadog.GetString("cluster_agent.check_templates.git.path"), "/"),
+		cachePath:     cachePath,
+	}, nil
+}
+
+// Fetch updates the mirror of the repository and returns the templates of the configured ref
+func (b *gitBackend) Fetch(ctx context.Context) (*types.CheckTemplatesResponse, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+
+	if err := b.updateMirror(ctx); err != nil {
+		return nil, err
+	}
+
+	commit, err := b.git(ctx, "rev-parse", "--verify", b.ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %q: %v", b.ref, err)
+	}
+
+	return b.templatesAt(ctx, strings.TrimSpace(string(commit)))
+}
+
+// FetchVersion returns the templates of a commit of the mirror
+func (b *gitBackend) FetchVersion(ctx context.Context, version string) (*types.CheckTemplatesResponse, error) {
+	if !commitRegexp.MatchString(version) {
+		return nil, ErrVersionNotFound
+	}
+	if _, err := b.git(ctx, "cat-file", "-e", version+"^{commit}"); err != nil {
+		return nil, ErrVersionNotFound
+	}
+
+	return b.templatesAt(ctx, version)
+}
+
+func (b *gitBackend) updateMirror(ctx context.Context) error {
+	if _, err := os.Stat(filepath.Join(b.cachePath, "HEAD")); err != nil {
+		log.Debugf("Cloning the check templates repository in %s", b.cachePath)
+		if err := os.MkdirAll(filepath.Dir(b.cachePath), 0700); err != nil {
+			return err
+		}
+		_, err := b.runGit(ctx, "", "clone", "--mirror", "--quiet", "--", b.repositoryURL, b.cachePath)
+		return err
+	}
+
+	_, err := b.git(ctx, "fetch", "--prune", "--quiet", "origin")
+	return err
+}
+
+func (b *gitBackend) templatesAt(ctx context.Context, commit string) (*types.CheckTemplatesResponse, error) {
+	args := []string{"ls-tree", "-r", "--name-only", commit}
+	if b.path != "" {
+		args = append(args, "--", b.path)
+	}
+	files, err := b.git(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &types.CheckTemplatesResponse{
+		Version:   commit,
+		Templates: []types.CheckTemplate{},
+	}
+	for _, file := range strings.Split(strings.TrimSpace(string(files)), "\n") {
+		if !isTemplateFile(file) {
+			continue
+		}
+		content, err := b.git(ctx, "show", commit+":"+file)
+		if err != nil {
+			return nil, err
+		}
+		response.Templates = append(response.Templates, types.CheckTemplate{
+			Name:    templateName(file),
+			Path:    file,
+			Content: string(content),
+		})
+	}
+
+	return response, nil
+}
+
+func (b *gitBackend) git(ctx context.Context, args ...string) ([]byte, error) {
+	return b.runGit(ctx, b.cachePath, args...)
+}
+
+func (b *gitBackend) runGit(ctx context.Context, gitDir string, args ...string) ([]byte, error) {
+	subcommand := args[0]
+	if gitDir != "" {
+		args = append([]string{"--git-dir", gitDir}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// never prompt for credentials, they must be part of the URL or of the git configuration
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", subcommand, err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
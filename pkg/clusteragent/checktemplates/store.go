@@ -0,0 +1,230 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checktemplates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	// ErrNotReady is returned when the templates were never fetched from the backend
+	ErrNotReady = errors.New("the check templates were not fetched yet")
+	// ErrVersionNotFound is returned when the requested version of the templates is unknown
+	ErrVersionNotFound = errors.New("unknown check templates version")
+)
+
+// maxFetchedVersions is the number of versions fetched from a versionedBackend
+// kept by the Store
+const maxFetchedVersions = 16
+
+// Backend is a source of check templates, like a Git repository or a ConfigMap.
+type Backend interface {
+	// Fetch returns the latest version of the templates.
+	Fetch(ctx context.Context) (*types.CheckTemplatesResponse, error)
+}
+
+// versionedBackend is a backend able to return any version of the templates,
+// and not only the latest one.
+type versionedBackend interface {
+	FetchVersion(ctx context.Context, version string) (*types.CheckTemplatesResponse, error)
+}
+
+// Store periodically fetches the check templates from its backend, and keeps
+// the last versions so that node-agents can pin a version of the templates
+// while a new one is rolled out.
+type Store struct {
+	backend         Backend
+	refreshInterval time.Duration
+	historySize     int
+
+	m       sync.RWMutex
+	history []*types.CheckTemplatesResponse // oldest first
+	lastErr error
+
+	// The versions out of the history fetched from a versionedBackend, nil
+	// when the version is unknown. The node-agents pinned to a version poll it,
+	// it's only fetched once.
+	fetchedM        sync.Mutex
+	fetched         map[string]*types.CheckTemplatesResponse
+	fetchedVersions []string // oldest first
+}
+
+// NewStore returns a new Store
+func NewStore(backend Backend, refreshInterval time.Duration, historySize int) *Store {
+	if historySize < 1 {
+		historySize = 1
+	}
+	return &Store{
+		backend:         backend,
+		refreshInterval: refreshInterval,
+		historySize:     historySize,
+		fetched:         make(map[string]*types.CheckTemplatesResponse),
+	}
+}
+
+// NewStoreFromConfig returns a Store using the backend configured in
+// `cluster_agent.check_templates`
+func NewStoreFromConfig() (*Store, error) {
+	var backend Backend
+	var err error
+	switch backendType := config.Datadog.GetString("cluster_agent.check_templates.backend"); backendType {
+	case "git":
+		backend, err = newGitBackendFromConfig()
+	case "configmap":
+		backend, err = newConfigMapBackendFromConfig()
+	default:
+		err = fmt.Errorf("unknown check templates backend %q, must be git or configmap", backendType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(
+		backend,
+		config.Datadog.GetDuration("cluster_agent.check_templates.refresh_interval")*time.Second,
+		config.Datadog.GetInt("cluster_agent.check_templates.history_size"),
+	), nil
+}
+
+// Run refreshes the templates until the context is cancelled
+func (s *Store) Run(ctx context.Context) {
+	s.refresh(ctx)
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.refresh(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Store) refresh(ctx context.Context) {
+	templates, err := s.backend.Fetch(ctx)
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if err != nil {
+		if s.lastErr == nil {
+			log.Warnf("Could not fetch the check templates, serving the last version: %v", err)
+		}
+		s.lastErr = err
+		return
+	}
+	if s.lastErr != nil {
+		log.Infof("Check templates fetched again successfully")
+	}
+	s.lastErr = nil
+
+	if len(s.history) > 0 && s.history[len(s.history)-1].Version == templates.Version {
+		return
+	}
+
+	log.Infof("New version of the check templates: %s (%d templates)", templates.Version, len(templates.Templates))
+	s.history = append(s.history, templates)
+	if len(s.history) > s.historySize {
+		s.history = s.history[len(s.history)-s.historySize:]
+	}
+}
+
+// Get returns the templates at the given version, or the latest version when
+// version is empty.
+func (s *Store) Get(ctx context.Context, version string) (*types.CheckTemplatesResponse, error) {
+	s.m.RLock()
+	templates, err := s.getLocked(version)
+	s.m.RUnlock()
+	if err != ErrVersionNotFound {
+		return templates, err
+	}
+
+	// versions which are not in the history anymore can be fetched from a Git repository
+	b, ok := s.backend.(versionedBackend)
+	if !ok {
+		return nil, err
+	}
+
+	s.fetchedM.Lock()
+	templates, found := s.fetched[version]
+	s.fetchedM.Unlock()
+	if !found {
+		// the backend is called without holding the lock, a version requested
+		// concurrently may be fetched twice
+		var fetchErr error
+		templates, fetchErr = b.FetchVersion(ctx, version)
+		if fetchErr != nil && fetchErr != ErrVersionNotFound {
+			log.Debugf("Could not fetch the check templates version %s: %v", version, fetchErr)
+			return nil, err
+		}
+		s.addFetched(version, templates)
+	}
+
+	if templates == nil {
+		return nil, ErrVersionNotFound
+	}
+	return templates, nil
+}
+
+// addFetched keeps a version fetched from the backend, evicting the oldest one
+// when there are more than maxFetchedVersions
+func (s *Store) addFetched(version string, templates *types.CheckTemplatesResponse) {
+	s.fetchedM.Lock()
+	defer s.fetchedM.Unlock()
+
+	if _, found := s.fetched[version]; found {
+		return
+	}
+	s.fetched[version] = templates
+	s.fetchedVersions = append(s.fetchedVersions, version)
+	if len(s.fetchedVersions) > maxFetchedVersions {
+		delete(s.fetched, s.fetchedVersions[0])
+		s.fetchedVersions = s.fetchedVersions[1:]
+	}
+}
+
+func (s *Store) getLocked(version string) (*types.CheckTemplatesResponse, error) {
+	if len(s.history) == 0 {
+		return nil, ErrNotReady
+	}
+	if version == "" {
+		return s.history[len(s.history)-1], nil
+	}
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if s.history[i].Version == version {
+			return s.history[i], nil
+		}
+	}
+	return nil, ErrVersionNotFound
+}
+
+// templateName returns the integration name of a template file, following
+// the conf.d layout: `<name>.d/<file>.yaml` or `<name>.yaml`
+func templateName(filePath string) string {
+	if dir := path.Base(path.Dir(filePath)); strings.HasSuffix(dir, ".d") {
+		return strings.TrimSuffix(dir, ".d")
+	}
+	name := path.Base(filePath)
+	return strings.SplitN(name, ".", 2)[0]
+}
+
+// isTemplateFile returns whether the file is a check configuration file
+func isTemplateFile(filePath string) bool {
+	ext := path.Ext(filePath)
+	return ext == ".yaml" || ext == ".yml"
+}
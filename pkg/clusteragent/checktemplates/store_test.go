@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package checktemplates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+type fakeBackend struct {
+	templates *types.CheckTemplatesResponse
+	err       error
+}
+
+func (b *fakeBackend) Fetch(ctx context.Context) (*types.CheckTemplatesResponse, error) {
+	return b.templates, b.err
+}
+
+type fakeVersionedBackend struct {
+	fakeBackend
+	versions map[string]*types.CheckTemplatesResponse
+	calls    int
+}
+
+func (b *fakeVersionedBackend) FetchVersion(ctx context.Context, version string) (*types.CheckTemplatesResponse, error) {
+	b.calls++
+	if templates, found := b.versions[version]; found {
+		return templates, nil
+	}
+	return nil, ErrVersionNotFound
+}
+
+func templatesVersion(version string) *types.CheckTemplatesResponse {
+	return &types.CheckTemplatesResponse{
+		Version: version,
+		Templates: []types.CheckTemplate{
+			{Name: "http_check", Path: "http_check.d/conf.yaml", Content: "instances: [{}]"},
+		},
+	}
+}
+
+func TestStoreNotReady(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{err: errors.New("unreachable")}
+	store := NewStore(backend, time.Minute, 3)
+
+	store.refresh(ctx)
+	_, err := store.Get(ctx, "")
+	assert.Equal(t, ErrNotReady, err)
+
+	// the templates are served once the backend is reachable
+	backend.templates, backend.err = templatesVersion("1"), nil
+	store.refresh(ctx)
+	templates, err := store.Get(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1", templates.Version)
+}
+
+func TestStoreHistory(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{}
+	store := NewStore(backend, time.Minute, 2)
+
+	for _, version := range []string{"1", "2", "2", "3"} {
+		backend.templates = templatesVersion(version)
+		store.refresh(ctx)
+	}
+	assert.Len(t, store.history, 2)
+
+	templates, err := store.Get(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "3", templates.Version)
+
+	templates, err = store.Get(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, "2", templates.Version)
+
+	// the oldest version was evicted
+	_, err = store.Get(ctx, "1")
+	assert.Equal(t, ErrVersionNotFound, err)
+
+	// the last version is kept when the backend fails
+	backend.err = errors.New("unreachable")
+	store.refresh(ctx)
+	templates, err = store.Get(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, "3", templates.Version)
+}
+
+func TestStoreVersionedBackend(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeVersionedBackend{
+		fakeBackend: fakeBackend{templates: templatesVersion("2")},
+		versions:    map[string]*types.CheckTemplatesResponse{"1": templatesVersion("1")},
+	}
+	store := NewStore(backend, time.Minute, 1)
+	store.refresh(ctx)
+
+	// versions out of the history are fetched from the backend
+	templates, err := store.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "1", templates.Version)
+
+	_, err = store.Get(ctx, "0")
+	assert.Equal(t, ErrVersionNotFound, err)
+
+	// the fetched versions, known or not, are cached
+	templates, err = store.Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "1", templates.Version)
+	_, err = store.Get(ctx, "0")
+	assert.Equal(t, ErrVersionNotFound, err)
+	assert.Equal(t, 2, backend.calls)
+
+	// the oldest fetched versions are evicted
+	for i := 0; i < maxFetchedVersions; i++ {
+		store.Get(ctx, fmt.Sprintf("unknown-%d", i))
+	}
+	assert.Len(t, store.fetched, maxFetchedVersions)
+	_, found := store.fetched["1"]
+	assert.False(t, found)
+}
+
+func TestTemplateName(t *testing.T) {
+	for path, name := range map[string]string{
+		"http_check.yaml":                  "http_check",
+		"http_check.d/conf.yaml":           "http_check",
+		"checks/http_check.d/custom.yml":   "http_check",
+		"checks/redisdb.yaml.example.yaml": "redisdb",
+	} {
+		assert.Equal(t, name, templateName(path), path)
+	}
+
+	assert.True(t, isTemplateFile("http_check.d/conf.yaml"))
+	assert.True(t, isTemplateFile("http_check.yml"))
+	assert.False(t, isTemplateFile("README.md"))
+}
@@ -10,6 +10,7 @@ package clusterchecks
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
 )
@@ -89,6 +90,19 @@ func (h *Handler) GetAllEndpointsCheckConfigs() (types.ConfigResponse, error) {
 	return response, err
 }
 
+// LeaseSNMPDevices grants SNMP devices to the node-agent or CLC runner polling them
+func (h *Handler) LeaseSNMPDevices(identifier string, req types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error) {
+	if identifier == "" {
+		return types.SNMPLeaseResponse{}, fmt.Errorf("empty identifier")
+	}
+	return h.snmpLeases.lease(identifier, req, time.Now()), nil
+}
+
+// GetSNMPLeases returns the owner of every leased SNMP device
+func (h *Handler) GetSNMPLeases() map[string]string {
+	return h.snmpLeases.owners(time.Now())
+}
+
 func (h *Handler) RebalanceClusterChecks() ([]types.RebalanceResponse, error) {
 	if !h.dispatcher.advancedDispatching {
 		return nil, fmt.Errorf("no checks to rebalance: advanced dispatching is not enabled")
@@ -13,14 +13,15 @@ import (
 )
 
 // getEndpointsConfigs provides configs templates of endpoints checks queried by node name.
-// Exposed to node agents by the cluster agent api.
+// Exposed to node agents by the cluster agent api, the query is used as the node agent heartbeat.
 func (d *dispatcher) getEndpointsConfigs(nodeName string) ([]integration.Config, error) {
 	nodeConfigs := []integration.Config{}
-	d.store.RLock()
+	d.store.Lock()
+	d.store.endpointsHeartbeats[nodeName] = timestampNow()
 	for _, v := range d.store.endpointsConfigs[nodeName] {
 		nodeConfigs = append(nodeConfigs, v)
 	}
-	d.store.RUnlock()
+	d.store.Unlock()
 	return nodeConfigs, nil
 }
 
@@ -44,14 +45,28 @@ func (d *dispatcher) addEndpointConfig(config integration.Config, nodename strin
 	if d.store.endpointsConfigs[nodename] == nil {
 		d.store.endpointsConfigs[nodename] = map[string]integration.Config{}
 	}
+	if _, found := d.store.endpointsHeartbeats[nodename]; !found {
+		// Give the node agent the time to query its configs before failing them over
+		d.store.endpointsHeartbeats[nodename] = timestampNow()
+	}
 	d.store.endpointsConfigs[nodename][config.Digest()] = config
 }
 
-// removeEndpointConfig deletes a given endpoint configuration
+// removeEndpointConfig deletes a given endpoint configuration,
+// and the cluster check it was failed over to, if any
 func (d *dispatcher) removeEndpointConfig(config integration.Config, nodename string) {
+	digest := config.Digest()
+
 	d.store.Lock()
-	defer d.store.Unlock()
-	delete(d.store.endpointsConfigs[nodename], config.Digest())
+	delete(d.store.endpointsConfigs[nodename], digest)
+	clusterDigest, failedOver := d.store.endpointsFailovers[digest]
+	delete(d.store.endpointsFailovers, digest)
+	delete(d.store.endpointsProbes, digest)
+	d.store.Unlock()
+
+	if failedOver {
+		d.removeConfig(clusterDigest)
+	}
 }
 
 // patchEndpointsConfiguration transforms the endpoint configuration from AD into a config
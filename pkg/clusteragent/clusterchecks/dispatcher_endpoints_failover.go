@@ -0,0 +1,410 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/configresolver"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/listeners"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	le "github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver/leaderelection/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	kubeEndpointIDPrefix = "kube_endpoint_uid://"
+	endpointDialTimeout  = 2 * time.Second
+	// endpointProbeConcurrency is the maximum number of endpoints probed at the same time
+	endpointProbeConcurrency = 10
+	// endpointProbeBackoffBase and endpointProbeBackoffMax bound the delay, in seconds,
+	// before probing again an unreachable endpoint
+	endpointProbeBackoffBase = 30
+	endpointProbeBackoffMax  = 600
+)
+
+// endpointsFailoverCandidate is an endpoint configuration of a node without agent
+type endpointsFailoverCandidate struct {
+	nodeName string
+	digest   string
+	config   integration.Config
+}
+
+// endpointProbe is the state of the reachability probes of an endpoint configuration
+// of a node without agent
+type endpointProbe struct {
+	inFlight  bool
+	failures  int
+	nextProbe int64
+}
+
+// failoverEndpointsConfigs dispatches the endpoints checks of the nodes whose agent
+// has not queried its configs for more than the expiration duration to the cluster
+// check runners, as long as their endpoint is reachable. They are given back to the
+// node agent as soon as it queries its configs again. The endpoints are probed in the
+// background, and the unreachable ones are probed again with an exponential backoff.
+func (d *dispatcher) failoverEndpointsConfigs() {
+	now := timestampNow()
+	cutoffTimestamp := now - d.nodeExpirationSeconds
+	var candidates []endpointsFailoverCandidate
+	var recovered []string
+
+	d.store.Lock()
+	for nodeName, heartbeat := range d.store.endpointsHeartbeats {
+		if heartbeat < cutoffTimestamp && len(d.store.endpointsConfigs[nodeName]) == 0 {
+			delete(d.store.endpointsHeartbeats, nodeName)
+		}
+	}
+	probed := make(map[string]struct{}, len(d.store.endpointsProbes))
+	for nodeName, configs := range d.store.endpointsConfigs {
+		agentless := d.store.endpointsHeartbeats[nodeName] < cutoffTimestamp
+		for digest, config := range configs {
+			clusterDigest, failedOver := d.store.endpointsFailovers[digest]
+			if failedOver && !agentless {
+				log.Infof("Node agent of %s is back, giving the endpoints check %s:%s back to it", nodeName, config.Name, digest)
+				delete(d.store.endpointsFailovers, digest)
+				recovered = append(recovered, clusterDigest)
+			} else if !failedOver && agentless {
+				probed[digest] = struct{}{}
+				probe, found := d.store.endpointsProbes[digest]
+				if !found {
+					probe = &endpointProbe{}
+					d.store.endpointsProbes[digest] = probe
+				}
+				if probe.inFlight || probe.nextProbe > now {
+					continue
+				}
+				probe.inFlight = true
+				candidates = append(candidates, endpointsFailoverCandidate{nodeName: nodeName, digest: digest, config: config})
+			}
+		}
+	}
+	unreachable := 0
+	for digest, probe := range d.store.endpointsProbes {
+		if _, found := probed[digest]; !found && !probe.inFlight {
+			// The node agent is back or the endpoint configuration was removed
+			delete(d.store.endpointsProbes, digest)
+		} else if probe.failures > 0 {
+			unreachable++
+		}
+	}
+	failedOverEndpointsConfigs.Set(float64(len(d.store.endpointsFailovers)), le.JoinLeaderValue)
+	d.store.Unlock()
+	unreachableEndpointsConfigs.Set(float64(unreachable), le.JoinLeaderValue)
+
+	for _, clusterDigest := range recovered {
+		d.removeConfig(clusterDigest)
+	}
+
+	for _, candidate := range candidates {
+		// Probe outside of the dispatcher loop, the endpoints may not answer before the timeout
+		d.endpointProbes.Add(1)
+		go d.probeEndpointsFailoverCandidate(candidate)
+	}
+}
+
+// probeEndpointsFailoverCandidate dispatches an endpoint configuration of a node
+// without agent as a cluster check if its endpoint is reachable
+func (d *dispatcher) probeEndpointsFailoverCandidate(candidate endpointsFailoverCandidate) {
+	defer d.endpointProbes.Done()
+
+	d.endpointProbeSlots <- struct{}{}
+	defer func() { <-d.endpointProbeSlots }()
+
+	resolved, err := resolveEndpointsFailoverConfiguration(candidate.config)
+	if err != nil {
+		log.Warnf("Cannot resolve endpoint configuration %s for failover: %s", candidate.digest, err)
+		d.endpointProbeDone(candidate.digest, false)
+		return
+	}
+
+	if !d.endpointReachable(resolved) {
+		log.Debugf("Endpoints check %s:%s of node %s without agent is not reachable, not dispatching it", candidate.config.Name, candidate.digest, candidate.nodeName)
+		d.endpointProbeDone(candidate.digest, false)
+		return
+	}
+
+	failover, err := patchEndpointsFailoverConfiguration(resolved)
+	if err != nil {
+		log.Warnf("Cannot patch endpoint configuration %s for failover: %s", candidate.digest, err)
+		d.endpointProbeDone(candidate.digest, false)
+		return
+	}
+
+	if !d.endpointProbeDone(candidate.digest, true) {
+		// The node agent is back or the endpoint configuration was removed while probing it
+		return
+	}
+
+	log.Infof("No agent queried the endpoints checks of node %s recently, dispatching %s:%s as a cluster check", candidate.nodeName, candidate.config.Name, candidate.digest)
+	d.add(failover)
+
+	d.store.Lock()
+	_, stillScheduled := d.store.endpointsConfigs[candidate.nodeName][candidate.digest]
+	if stillScheduled {
+		d.store.endpointsFailovers[candidate.digest] = failover.Digest()
+	}
+	d.store.Unlock()
+
+	if !stillScheduled {
+		// The endpoint configuration was removed while dispatching it
+		d.removeConfig(failover.Digest())
+	}
+}
+
+// endpointProbeDone records the result of the probe of an endpoint configuration,
+// it returns whether the endpoint configuration is still a failover candidate
+func (d *dispatcher) endpointProbeDone(digest string, reachable bool) bool {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	probe, found := d.store.endpointsProbes[digest]
+	if !found {
+		return false
+	}
+	if reachable {
+		delete(d.store.endpointsProbes, digest)
+		return true
+	}
+
+	probe.inFlight = false
+	probe.failures++
+	backoff := int64(endpointProbeBackoffMax)
+	if probe.failures < 16 {
+		if b := int64(endpointProbeBackoffBase) << (probe.failures - 1); b < backoff {
+			backoff = b
+		}
+	}
+	probe.nextProbe = timestampNow() + backoff
+	return false
+}
+
+// resolveEndpointsFailoverConfiguration resolves the template variables of an
+// endpoint configuration with its endpoint, like the node agents do
+func resolveEndpointsFailoverConfiguration(config integration.Config) (integration.Config, error) {
+	svc, err := newEndpointFailoverService(config)
+	if err != nil {
+		return config, err
+	}
+
+	resolved, _, err := configresolver.Resolve(config, svc)
+	return resolved, err
+}
+
+// patchEndpointsFailoverConfiguration transforms a resolved endpoint configuration
+// into a cluster check configuration. It does the following changes:
+//   - empty the ADIdentifiers array and clear the node name, the config is resolved
+//   - add the empty_default_hostname option to all instances
+func patchEndpointsFailoverConfiguration(in integration.Config) (integration.Config, error) {
+	out := in
+	out.ADIdentifiers = nil
+	out.NodeName = ""
+
+	// Deep copy the instances to avoid modifying the original
+	out.Instances = make([]integration.Data, len(in.Instances))
+	copy(out.Instances, in.Instances)
+
+	for i := range out.Instances {
+		err := out.Instances[i].SetField("empty_default_hostname", true)
+		if err != nil {
+			return in, err
+		}
+	}
+
+	return out, nil
+}
+
+// endpointFailoverService is the service of an endpoint used to resolve its
+// configuration in the cluster agent. The ports of the endpoint aren't known,
+// the configurations using %%port%% can't be failed over.
+type endpointFailoverService struct {
+	entity string
+	ip     string
+	tags   []string
+}
+
+var _ listeners.Service = &endpointFailoverService{}
+
+func newEndpointFailoverService(config integration.Config) (*endpointFailoverService, error) {
+	for _, id := range config.ADIdentifiers {
+		if !strings.HasPrefix(id, kubeEndpointIDPrefix) {
+			continue
+		}
+		// kube_endpoint_uid://<namespace>/<name>/<ip>
+		parts := strings.Split(strings.TrimPrefix(id, kubeEndpointIDPrefix), "/")
+		if len(parts) != 3 {
+			break
+		}
+		return &endpointFailoverService{
+			entity: id,
+			ip:     parts[2],
+			tags: []string{
+				fmt.Sprintf("kube_service:%s", parts[1]),
+				fmt.Sprintf("kube_namespace:%s", parts[0]),
+				fmt.Sprintf("kube_endpoint_ip:%s", parts[2]),
+			},
+		}, nil
+	}
+	return nil, fmt.Errorf("no endpoint identifier found in %v", config.ADIdentifiers)
+}
+
+// GetEntity returns the unique entity name linked to that service
+func (s *endpointFailoverService) GetEntity() string {
+	return s.entity
+}
+
+// GetTaggerEntity returns the unique entity name linked to that service
+func (s *endpointFailoverService) GetTaggerEntity() string {
+	return s.entity
+}
+
+// GetADIdentifiers returns the service AD identifiers
+func (s *endpointFailoverService) GetADIdentifiers(context.Context) ([]string, error) {
+	return []string{s.entity}, nil
+}
+
+// GetHosts returns the endpoint IP
+func (s *endpointFailoverService) GetHosts(context.Context) (map[string]string, error) {
+	return map[string]string{"endpoint": s.ip}, nil
+}
+
+// GetPorts returns no port, they're unknown
+func (s *endpointFailoverService) GetPorts(context.Context) ([]listeners.ContainerPort, error) {
+	return []listeners.ContainerPort{}, nil
+}
+
+// GetTags returns the tags of the endpoint
+func (s *endpointFailoverService) GetTags() ([]string, string, error) {
+	return s.tags, "", nil
+}
+
+// GetPid is not supported
+func (s *endpointFailoverService) GetPid(context.Context) (int, error) {
+	return -1, listeners.ErrNotSupported
+}
+
+// GetHostname is not supported
+func (s *endpointFailoverService) GetHostname(context.Context) (string, error) {
+	return "", listeners.ErrNotSupported
+}
+
+// GetCreationTime returns integration.After, the endpoint is not discovered at startup
+func (s *endpointFailoverService) GetCreationTime() integration.CreationTime {
+	return integration.After
+}
+
+// IsReady returns true, the endpoint configuration is only failed over once reachable
+func (s *endpointFailoverService) IsReady(context.Context) bool {
+	return true
+}
+
+// GetCheckNames is not supported
+func (s *endpointFailoverService) GetCheckNames(context.Context) []string {
+	return nil
+}
+
+// HasFilter always returns false
+func (s *endpointFailoverService) HasFilter(containers.FilterType) bool {
+	return false
+}
+
+// GetExtraConfig is not supported
+func (s *endpointFailoverService) GetExtraConfig([]byte) ([]byte, error) {
+	return []byte{}, listeners.ErrNotSupported
+}
+
+// isEndpointReachable validates that the endpoint of a check accepts connections from
+// the cluster, by dialing the addresses its instances connect to
+func isEndpointReachable(config integration.Config) bool {
+	addresses := endpointAddresses(config)
+	if len(addresses) == 0 {
+		log.Debugf("Cannot find the address of the endpoint of %s:%s", config.Name, config.Digest())
+		return false
+	}
+
+	for _, address := range addresses {
+		conn, err := net.DialTimeout("tcp", address, endpointDialTimeout)
+		if err != nil {
+			log.Debugf("Cannot connect to %s for %s:%s: %s", address, config.Name, config.Digest(), err)
+			return false
+		}
+		conn.Close()
+	}
+	return true
+}
+
+// endpointAddresses returns the addresses on the endpoint IP found in the
+// port and URL fields of the instances of an endpoint configuration
+func endpointAddresses(config integration.Config) []string {
+	var ip string
+	for _, id := range config.ADIdentifiers {
+		if strings.HasPrefix(id, kubeEndpointIDPrefix) {
+			ip = id[strings.LastIndex(id, "/")+1:]
+			break
+		}
+	}
+	if ip == "" {
+		return nil
+	}
+
+	seen := make(map[string]struct{})
+	var addresses []string
+	addAddress := func(port string) {
+		address := net.JoinHostPort(ip, port)
+		if _, found := seen[address]; !found {
+			seen[address] = struct{}{}
+			addresses = append(addresses, address)
+		}
+	}
+
+	for _, instance := range config.Instances {
+		fields := make(map[string]interface{})
+		if err := yaml.Unmarshal(instance, &fields); err != nil {
+			continue
+		}
+
+		for key, value := range fields {
+			switch v := value.(type) {
+			case int:
+				if key == "port" {
+					addAddress(strconv.Itoa(v))
+				}
+			case string:
+				if key == "port" {
+					if _, err := strconv.Atoi(v); err == nil {
+						addAddress(v)
+					}
+					continue
+				}
+				u, err := url.Parse(v)
+				if err != nil || u.Hostname() != ip {
+					continue
+				}
+				switch {
+				case u.Port() != "":
+					addAddress(u.Port())
+				case u.Scheme == "http":
+					addAddress("80")
+				case u.Scheme == "https":
+					addAddress("443")
+				}
+			}
+		}
+	}
+
+	return addresses
+}
@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func generateEndpointsIntegration(name, nodename, ip string) integration.Config {
+	return integration.Config{
+		Name:          name,
+		ADIdentifiers: []string{"kube_endpoint_uid://default/redis/" + ip, "kubernetes_pod://abcd"},
+		Instances:     []integration.Data{integration.Data("host: %%host%%\nport: 6379")},
+		NodeName:      nodename,
+	}
+}
+
+func TestEndpointAddresses(t *testing.T) {
+	config := integration.Config{
+		ADIdentifiers: []string{"kube_endpoint_uid://default/web/10.0.0.1"},
+		Instances: []integration.Data{
+			integration.Data("host: 10.0.0.1\nport: 8080"),
+			integration.Data("url: http://10.0.0.1/status"),
+			integration.Data("openmetrics_endpoint: https://10.0.0.1:9090/metrics\nport: \"8080\""),
+			integration.Data("url: http://10.0.0.2:8000/status"),
+		},
+	}
+	assert.ElementsMatch(t, []string{"10.0.0.1:8080", "10.0.0.1:80", "10.0.0.1:9090"}, endpointAddresses(config))
+
+	config.ADIdentifiers = []string{"kubernetes_pod://abcd"}
+	assert.Empty(t, endpointAddresses(config))
+}
+
+func TestResolveEndpointsFailoverConfiguration(t *testing.T) {
+	resolved, err := resolveEndpointsFailoverConfiguration(generateEndpointsIntegration("redisdb", "node1", "10.0.0.1"))
+	require.NoError(t, err)
+	require.Len(t, resolved.Instances, 1)
+
+	rawConfig := integration.RawMap{}
+	require.NoError(t, yaml.Unmarshal(resolved.Instances[0], &rawConfig))
+	assert.Equal(t, "10.0.0.1", rawConfig["host"])
+	assert.ElementsMatch(t, []interface{}{"kube_service:redis", "kube_namespace:default", "kube_endpoint_ip:10.0.0.1"}, rawConfig["tags"])
+	assert.Equal(t, []string{"10.0.0.1:6379"}, endpointAddresses(resolved))
+
+	// The ports of the endpoints are unknown
+	config := generateEndpointsIntegration("redisdb", "node1", "10.0.0.1")
+	config.Instances = []integration.Data{integration.Data("host: %%host%%\nport: %%port%%")}
+	_, err = resolveEndpointsFailoverConfiguration(config)
+	assert.Error(t, err)
+
+	config.ADIdentifiers = []string{"kubernetes_pod://abcd"}
+	_, err = resolveEndpointsFailoverConfiguration(config)
+	assert.Error(t, err)
+}
+
+func TestPatchEndpointsFailoverConfiguration(t *testing.T) {
+	in, err := resolveEndpointsFailoverConfiguration(generateEndpointsIntegration("redisdb", "node1", "10.0.0.1"))
+	require.NoError(t, err)
+	resolvedInstance := in.Instances[0]
+
+	out, err := patchEndpointsFailoverConfiguration(in)
+	require.NoError(t, err)
+	assert.Nil(t, out.ADIdentifiers)
+	assert.Empty(t, out.NodeName)
+	require.Len(t, out.Instances, 1)
+
+	rawConfig := integration.RawMap{}
+	require.NoError(t, yaml.Unmarshal(out.Instances[0], &rawConfig))
+	assert.Equal(t, true, rawConfig["empty_default_hostname"])
+	assert.Equal(t, 6379, rawConfig["port"])
+
+	// The original configuration is untouched
+	assert.Equal(t, "node1", in.NodeName)
+	assert.Len(t, in.ADIdentifiers, 2)
+	assert.Equal(t, resolvedInstance, in.Instances[0])
+}
+
+func TestFailoverEndpointsConfigs(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.store.active = true
+	reachable := map[string]bool{"node1": true, "node2": false}
+	probes := map[string]int{}
+	var probesLock sync.Mutex
+	dispatcher.endpointReachable = func(config integration.Config) bool {
+		probesLock.Lock()
+		defer probesLock.Unlock()
+		probes[config.NodeName]++
+		return reachable[config.NodeName]
+	}
+	failover := func() {
+		dispatcher.failoverEndpointsConfigs()
+		dispatcher.endpointProbes.Wait()
+	}
+
+	// One CLC runner
+	_, err := dispatcher.processNodeStatus("runner1", "10.0.1.1", types.NodeStatus{})
+	require.NoError(t, err)
+
+	config1 := generateEndpointsIntegration("redisdb", "node1", "10.0.0.1")
+	config2 := generateEndpointsIntegration("redisdb", "node2", "10.0.0.2")
+	config3 := generateEndpointsIntegration("redisdb", "node3", "10.0.0.3")
+	dispatcher.addEndpointConfig(config1, "node1")
+	dispatcher.addEndpointConfig(config2, "node2")
+	dispatcher.addEndpointConfig(config3, "node3")
+
+	// Nodes still in their grace period are not failed over
+	failover()
+	configs, _, err := dispatcher.getClusterCheckConfigs("runner1")
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+
+	// The agents of node1 and node2 never queried their configs, the agent of node3 did
+	dispatcher.store.endpointsHeartbeats["node1"] -= 2 * dispatcher.nodeExpirationSeconds
+	dispatcher.store.endpointsHeartbeats["node2"] -= 2 * dispatcher.nodeExpirationSeconds
+	_, err = dispatcher.getEndpointsConfigs("node3")
+	require.NoError(t, err)
+
+	// Only the reachable endpoint of node1 is dispatched, resolved with the endpoint IP
+	failover()
+	configs, _, err = dispatcher.getClusterCheckConfigs("runner1")
+	require.NoError(t, err)
+	require.Len(t, configs, 1)
+	assert.Nil(t, configs[0].ADIdentifiers)
+	assert.Empty(t, configs[0].NodeName)
+	assert.Contains(t, string(configs[0].Instances[0]), "host: 10.0.0.1")
+	assert.Len(t, dispatcher.store.endpointsFailovers, 1)
+	require.Contains(t, dispatcher.store.endpointsProbes, config2.Digest())
+	assert.Equal(t, 1, dispatcher.store.endpointsProbes[config2.Digest()].failures)
+
+	// Dispatched only once, and the unreachable endpoint isn't probed again before its backoff
+	failover()
+	configs, _, err = dispatcher.getClusterCheckConfigs("runner1")
+	require.NoError(t, err)
+	assert.Len(t, configs, 1)
+	assert.Equal(t, map[string]int{"node1": 1, "node2": 1}, probes)
+
+	dispatcher.store.endpointsProbes[config2.Digest()].nextProbe = timestampNow()
+	failover()
+	assert.Equal(t, 2, probes["node2"])
+	assert.Equal(t, 2, dispatcher.store.endpointsProbes[config2.Digest()].failures)
+
+	// The agent of node1 is back
+	_, err = dispatcher.getEndpointsConfigs("node1")
+	require.NoError(t, err)
+	failover()
+	configs, _, err = dispatcher.getClusterCheckConfigs("runner1")
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+	assert.Empty(t, dispatcher.store.endpointsFailovers)
+
+	requireNotLocked(t, dispatcher.store)
+}
+
+func TestRemoveFailedOverEndpointConfig(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.store.active = true
+	dispatcher.endpointReachable = func(integration.Config) bool { return true }
+
+	_, err := dispatcher.processNodeStatus("runner1", "10.0.1.1", types.NodeStatus{})
+	require.NoError(t, err)
+
+	config := generateEndpointsIntegration("redisdb", "node1", "10.0.0.1")
+	dispatcher.addEndpointConfig(config, "node1")
+	dispatcher.store.endpointsHeartbeats["node1"] -= 2 * dispatcher.nodeExpirationSeconds
+
+	dispatcher.failoverEndpointsConfigs()
+	dispatcher.endpointProbes.Wait()
+	configs, _, err := dispatcher.getClusterCheckConfigs("runner1")
+	require.NoError(t, err)
+	assert.Len(t, configs, 1)
+
+	dispatcher.removeEndpointConfig(config, "node1")
+	configs, _, err = dispatcher.getClusterCheckConfigs("runner1")
+	require.NoError(t, err)
+	assert.Empty(t, configs)
+	stored, err := dispatcher.getAllConfigs()
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	assert.Empty(t, dispatcher.store.endpointsFailovers)
+
+	requireNotLocked(t, dispatcher.store)
+}
@@ -10,6 +10,7 @@ package clusterchecks
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
@@ -32,6 +33,10 @@ type dispatcher struct {
 	extraTags             []string
 	clcRunnersClient      clusteragent.CLCRunnerClientInterface
 	advancedDispatching   bool
+	endpointsFailover     bool
+	endpointReachable     func(integration.Config) bool
+	endpointProbes        sync.WaitGroup
+	endpointProbeSlots    chan struct{}
 }
 
 func newDispatcher() *dispatcher {
@@ -52,6 +57,10 @@ func newDispatcher() *dispatcher {
 		d.extraTags = append(d.extraTags, fmt.Sprintf("kube_cluster_name:%s", clusterTagValue))
 	}
 
+	d.endpointsFailover = config.Datadog.GetBool("cluster_checks.endpoints_failover_enabled")
+	d.endpointReachable = isEndpointReachable
+	d.endpointProbeSlots = make(chan struct{}, endpointProbeConcurrency)
+
 	d.advancedDispatching = config.Datadog.GetBool("cluster_checks.advanced_dispatching_enabled")
 	if !d.advancedDispatching {
 		return d
@@ -139,6 +148,9 @@ func (d *dispatcher) add(config integration.Config) {
 	}
 
 	d.addConfig(config, target)
+	if d.advancedDispatching && target != "" {
+		d.addEstimatedBusyness(config, target)
+	}
 }
 
 // remove deletes a given configuration
@@ -181,6 +193,11 @@ func (d *dispatcher) run(ctx context.Context) {
 			// Expire old nodes, orphaned configs are moved to dangling
 			d.expireNodes()
 
+			// Dispatch the endpoints checks of nodes without agent
+			if d.endpointsFailover {
+				d.failoverEndpointsConfigs()
+			}
+
 			// Re-dispatch dangling configs
 			if d.shouldDispatchDanling() {
 				danglingConfs := d.retrieveAndClearDangling()
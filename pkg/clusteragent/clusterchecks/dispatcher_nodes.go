@@ -69,7 +69,8 @@ func (d *dispatcher) processNodeStatus(nodeName, clientIP string, status types.N
 }
 
 // getLeastBusyNode returns the name of the node that is assigned
-// the lowest number of checks. In case of equality, one is chosen
+// the lowest number of checks, or the lowest busyness when advanced
+// dispatching is enabled. In case of equality, one is chosen
 // randomly, based on map iterations being randomized.
 func (d *dispatcher) getLeastBusyNode() string {
 	var leastBusyNode string
@@ -157,6 +158,7 @@ func (d *dispatcher) updateRunnersStats() {
 
 	d.store.Lock()
 	defer d.store.Unlock()
+	updatedNodes := make([]*nodeStore, 0, len(d.store.nodes))
 	for name, node := range d.store.nodes {
 		node.RLock()
 		ip := node.clientIP
@@ -182,9 +184,47 @@ func (d *dispatcher) updateRunnersStats() {
 		}
 		node.clcRunnerStats = stats
 		log.Tracef("Updated CLC Runner stats on node: %s, node IP: %s, stats: %v", name, node.clientIP, stats)
-		node.busyness = calculateBusyness(stats)
-		log.Debugf("Updated busyness on node: %s, node IP: %s, busyness value: %d", name, node.clientIP, node.busyness)
+		node.Unlock()
+		updatedNodes = append(updatedNodes, node)
+	}
+
+	nodesStats := make([]types.CLCRunnersStats, 0, len(d.store.nodes))
+	for _, node := range d.store.nodes {
+		node.RLock()
+		nodesStats = append(nodesStats, node.clcRunnerStats)
+		node.RUnlock()
+	}
+	d.store.costProfiles = calculateCostProfiles(nodesStats)
+	log.Tracef("Updated check cost profiles: %v", d.store.costProfiles)
+
+	for _, node := range updatedNodes {
+		node.Lock()
+		// The checks dispatched since the last stats collection are accounted for with their estimated weight
+		node.busyness = calculateBusyness(node.clcRunnerStats) + d.store.estimatePendingBusyness(node)
+		log.Debugf("Updated busyness on node: %s, node IP: %s, busyness value: %d", node.name, node.clientIP, node.busyness)
 		busyness.Set(float64(node.busyness), node.name, le.JoinLeaderValue)
 		node.Unlock()
 	}
 }
+
+// addEstimatedBusyness adds the estimated weight of a configuration to the busyness of the node
+// it is dispatched to, so that the next dispatching decisions account for it until the node
+// reports its stats. Without it, all the configs scheduled between two stats collections would
+// be dispatched to the same node.
+func (d *dispatcher) addEstimatedBusyness(config integration.Config, nodeName string) {
+	d.store.Lock()
+	defer d.store.Unlock()
+
+	node, found := d.store.getNodeStore(nodeName)
+	if !found {
+		return
+	}
+
+	node.Lock()
+	defer node.Unlock()
+	if node.busyness == defaultBusynessValue {
+		// No stats collected yet, the node is dispatched to based on its check count
+		return
+	}
+	node.busyness += len(config.Instances) * estimateCost(d.store.costProfiles, config.Name)
+}
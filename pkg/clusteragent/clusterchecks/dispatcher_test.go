@@ -18,6 +18,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/clustername"
 	"github.com/DataDog/datadog-agent/pkg/version"
@@ -261,6 +262,54 @@ func TestGetLeastBusyNode(t *testing.T) {
 	requireNotLocked(t, dispatcher.store)
 }
 
+func TestDispatchByCostProfile(t *testing.T) {
+	dispatcher := newDispatcher()
+	dispatcher.advancedDispatching = true
+	dispatcher.store.active = true
+	dispatcher.store.costProfiles = map[string]int{
+		"snmp":       500,
+		"http_check": 10,
+	}
+
+	dispatcher.processNodeStatus("node1", "10.0.0.1", types.NodeStatus{})
+	dispatcher.processNodeStatus("node2", "10.0.0.2", types.NodeStatus{})
+	dispatcher.store.nodes["node1"].busyness = 100
+	dispatcher.store.nodes["node2"].busyness = 120
+
+	instanceConfig := func(name, instance string) integration.Config {
+		config := generateIntegration(name)
+		config.Instances = []integration.Data{integration.Data(instance)}
+		return config
+	}
+	snmp1 := instanceConfig("snmp", "ip_address: 10.0.1.1")
+	http1 := instanceConfig("http_check", "url: http://a")
+	http2 := instanceConfig("http_check", "url: http://b")
+	snmp2 := instanceConfig("snmp", "ip_address: 10.0.1.2")
+
+	// The estimated cost of each config is added to the busyness of its node,
+	// the light checks don't all land on the same node as the heavy ones
+	dispatcher.add(snmp1)
+	assert.Equal(t, "node1", dispatcher.store.digestToNode[snmp1.Digest()])
+	assert.Equal(t, 600, dispatcher.store.nodes["node1"].busyness)
+	dispatcher.add(http1)
+	assert.Equal(t, "node2", dispatcher.store.digestToNode[http1.Digest()])
+	dispatcher.add(http2)
+	assert.Equal(t, "node2", dispatcher.store.digestToNode[http2.Digest()])
+	assert.Equal(t, 140, dispatcher.store.nodes["node2"].busyness)
+	dispatcher.add(snmp2)
+	assert.Equal(t, "node2", dispatcher.store.digestToNode[snmp2.Digest()])
+	assert.Equal(t, 640, dispatcher.store.nodes["node2"].busyness)
+
+	// Pending instances keep their estimated weight until their stats are reported
+	dispatcher.store.nodes["node1"].clcRunnerStats = types.CLCRunnersStats{}
+	assert.Equal(t, 500, dispatcher.store.estimatePendingBusyness(dispatcher.store.nodes["node1"]))
+	id := check.BuildID(snmp1.Name, snmp1.Instances[0], snmp1.InitConfig)
+	dispatcher.store.nodes["node1"].clcRunnerStats[string(id)] = types.CLCRunnerStats{AverageExecutionTime: 100}
+	assert.Equal(t, 0, dispatcher.store.estimatePendingBusyness(dispatcher.store.nodes["node1"]))
+
+	requireNotLocked(t, dispatcher.store)
+}
+
 func TestExpireNodes(t *testing.T) {
 	dispatcher := newDispatcher()
 
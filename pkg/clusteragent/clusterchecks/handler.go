@@ -44,6 +44,7 @@ type pluggableAutoConfig interface {
 type Handler struct {
 	autoconfig           pluggableAutoConfig
 	dispatcher           *dispatcher
+	snmpLeases           *snmpLeaseStore
 	leaderStatusFreq     time.Duration
 	warmupDuration       time.Duration
 	leaderStatusCallback types.LeaderIPCallback
@@ -66,6 +67,7 @@ func NewHandler(ac pluggableAutoConfig) (*Handler, error) {
 		warmupDuration:   config.Datadog.GetDuration("cluster_checks.warmup_duration") * time.Second,
 		leadershipChan:   make(chan state, 1),
 		dispatcher:       newDispatcher(),
+		snmpLeases:       newSNMPLeaseStore(),
 		port:             config.Datadog.GetInt("cluster_agent.cmd_port"),
 	}
 
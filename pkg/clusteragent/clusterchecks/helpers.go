@@ -13,10 +13,12 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
 )
 
 const (
 	checkExecutionTimeWeight = 0.8
+	checkCPUTimeWeight       = 0.8
 	checkMetricSamplesWeight = 0.2
 )
 
@@ -51,7 +53,43 @@ func busynessFunc(s types.CLCRunnerStats) int {
 		// The check is failing, its weight is 0
 		return 0
 	}
-	return int(checkExecutionTimeWeight*float64(s.AverageExecutionTime) + checkMetricSamplesWeight*float64(s.MetricSamples))
+	return int(checkExecutionTimeWeight*float64(s.AverageExecutionTime) +
+		checkCPUTimeWeight*float64(s.AverageCPUTime) +
+		checkMetricSamplesWeight*float64(s.MetricSamples))
+}
+
+// calculateCostProfiles returns the average weight of the check instances of each check name
+// reported by the nodes, used to estimate the weight of the instances without stats
+func calculateCostProfiles(nodesStats []types.CLCRunnersStats) map[string]int {
+	weights := make(map[string]int)
+	counts := make(map[string]int)
+	for _, checkStats := range nodesStats {
+		for id, stats := range checkStats {
+			name := check.IDToCheckName(check.ID(id))
+			weights[name] += busynessFunc(stats)
+			counts[name]++
+		}
+	}
+	for name, count := range counts {
+		weights[name] /= count
+	}
+	return weights
+}
+
+// estimateCost returns the estimated weight of a check instance: the cost profile of its
+// check name if known, the average of the cost profiles otherwise
+func estimateCost(costProfiles map[string]int, checkName string) int {
+	if cost, found := costProfiles[checkName]; found {
+		return cost
+	}
+	if len(costProfiles) == 0 {
+		return 0
+	}
+	total := 0
+	for _, cost := range costProfiles {
+		total += cost
+	}
+	return total / len(costProfiles)
 }
 
 // orderedKeys sorts the keys of a map and return them in a slice
@@ -10,6 +10,8 @@ package clusterchecks
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
 )
 
@@ -54,6 +56,15 @@ func Test_busynessFunc(t *testing.T) {
 			},
 			want: 0,
 		},
+		{
+			name: "cpu time reported",
+			stats: types.CLCRunnerStats{
+				AverageExecutionTime: 100,
+				AverageCPUTime:       50,
+				MetricSamples:        100,
+			},
+			want: 140,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -100,3 +111,36 @@ func Test_calculateBusyness(t *testing.T) {
 		})
 	}
 }
+
+func Test_calculateCostProfiles(t *testing.T) {
+	nodesStats := []types.CLCRunnersStats{
+		{
+			"snmp:a":       types.CLCRunnerStats{AverageExecutionTime: 1000, AverageCPUTime: 500},
+			"http_check:a": types.CLCRunnerStats{AverageExecutionTime: 100},
+		},
+		{
+			"snmp:b":       types.CLCRunnerStats{AverageExecutionTime: 2000, AverageCPUTime: 1000},
+			"http_check:b": types.CLCRunnerStats{AverageExecutionTime: 100, LastExecFailed: true},
+		},
+		{},
+	}
+
+	assert.Equal(t, map[string]int{
+		"snmp":       1800,
+		"http_check": 40,
+	}, calculateCostProfiles(nodesStats))
+	assert.Empty(t, calculateCostProfiles(nil))
+}
+
+func Test_estimateCost(t *testing.T) {
+	costProfiles := map[string]int{
+		"snmp":       1800,
+		"http_check": 40,
+	}
+
+	assert.Equal(t, 1800, estimateCost(costProfiles, "snmp"))
+	assert.Equal(t, 40, estimateCost(costProfiles, "http_check"))
+	// unknown check name: average of the profiles
+	assert.Equal(t, 920, estimateCost(costProfiles, "tcp_check"))
+	assert.Equal(t, 0, estimateCost(map[string]int{}, "tcp_check"))
+}
@@ -37,6 +37,12 @@ var (
 	updateStatsDuration = telemetry.NewGaugeWithOpts("cluster_checks", "updating_stats_duration_seconds",
 		[]string{le.JoinLeaderLabel}, "Duration of collecting stats from check runners and updating cache",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
+	failedOverEndpointsConfigs = telemetry.NewGaugeWithOpts("cluster_checks", "endpoints_configs_failed_over",
+		[]string{le.JoinLeaderLabel}, "Number of endpoints check configurations of nodes without agent dispatched as cluster checks.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
+	unreachableEndpointsConfigs = telemetry.NewGaugeWithOpts("cluster_checks", "endpoints_configs_unreachable",
+		[]string{le.JoinLeaderLabel}, "Number of endpoints check configurations of nodes without agent not reachable from the cluster check runners.",
+		telemetry.Options{NoDoubleUnderscoreSep: true})
 	busyness = telemetry.NewGaugeWithOpts("cluster_checks", "busyness",
 		[]string{"node", le.JoinLeaderLabel}, "Busyness of a node per the number of metrics submitted and average duration of all checks run",
 		telemetry.Options{NoDoubleUnderscoreSep: true})
@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+// defaultSNMPLeaseTTL is used when the lease request doesn't specify a duration
+const defaultSNMPLeaseTTL = 2 * time.Hour
+
+type snmpLease struct {
+	owner  string
+	expiry time.Time
+}
+
+// snmpLeaseStore grants SNMP devices to a single node-agent or CLC runner, so that
+// agents discovering overlapping subnets don't poll the same devices.
+// Leases are held in memory: after a leader change they are granted
+// again to the first agents renewing them.
+type snmpLeaseStore struct {
+	m      sync.Mutex
+	leases map[string]snmpLease
+}
+
+func newSNMPLeaseStore() *snmpLeaseStore {
+	return &snmpLeaseStore{
+		leases: make(map[string]snmpLease),
+	}
+}
+
+// lease releases and grants the devices of the request to owner. A device is granted
+// when it's not leased, when its lease expired, or when owner already holds it, in
+// which case the lease is renewed.
+func (s *snmpLeaseStore) lease(owner string, req types.SNMPLeaseRequest, now time.Time) types.SNMPLeaseResponse {
+	ttl := time.Duration(req.TTL) * time.Second
+	if ttl <= 0 {
+		ttl = defaultSNMPLeaseTTL
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	for device, lease := range s.leases {
+		if now.After(lease.expiry) {
+			delete(s.leases, device)
+		}
+	}
+
+	for _, device := range req.Release {
+		if lease, found := s.leases[device]; found && lease.owner == owner {
+			delete(s.leases, device)
+		}
+	}
+
+	response := types.SNMPLeaseResponse{
+		Granted: []string{},
+		Owners:  map[string]string{},
+	}
+	for _, device := range req.Devices {
+		if lease, found := s.leases[device]; found && lease.owner != owner {
+			response.Owners[device] = lease.owner
+			continue
+		}
+		s.leases[device] = snmpLease{owner: owner, expiry: now.Add(ttl)}
+		response.Granted = append(response.Granted, device)
+	}
+	return response
+}
+
+// owners returns the owner of every leased device
+func (s *snmpLeaseStore) owners(now time.Time) map[string]string {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	owners := make(map[string]string, len(s.leases))
+	for device, lease := range s.leases {
+		if now.After(lease.expiry) {
+			continue
+		}
+		owners[device] = lease.owner
+	}
+	return owners
+}
@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build clusterchecks
+
+package clusterchecks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+func TestSNMPLeaseStore(t *testing.T) {
+	store := newSNMPLeaseStore()
+	now := time.Now()
+
+	// First runner gets both devices
+	response := store.lease("runner1", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1", "default:10.0.0.2"}, TTL: 60}, now)
+	assert.ElementsMatch(t, []string{"default:10.0.0.1", "default:10.0.0.2"}, response.Granted)
+	assert.Empty(t, response.Owners)
+
+	// Second runner only gets the device not leased yet
+	response = store.lease("runner2", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.2", "default:10.0.0.3"}, TTL: 60}, now)
+	assert.Equal(t, []string{"default:10.0.0.3"}, response.Granted)
+	assert.Equal(t, map[string]string{"default:10.0.0.2": "runner1"}, response.Owners)
+
+	// Same IP in another namespace is another device
+	response = store.lease("runner2", types.SNMPLeaseRequest{Devices: []string{"other:10.0.0.1"}, TTL: 60}, now)
+	assert.Equal(t, []string{"other:10.0.0.1"}, response.Granted)
+
+	// Renewal by the owner
+	response = store.lease("runner1", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1"}, TTL: 60}, now.Add(50*time.Second))
+	assert.Equal(t, []string{"default:10.0.0.1"}, response.Granted)
+
+	// default:10.0.0.2 expired, default:10.0.0.1 was renewed
+	response = store.lease("runner2", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1", "default:10.0.0.2"}, TTL: 60}, now.Add(90*time.Second))
+	assert.Equal(t, []string{"default:10.0.0.2"}, response.Granted)
+	assert.Equal(t, map[string]string{"default:10.0.0.1": "runner1"}, response.Owners)
+
+	// Only the owner can release a device
+	store.lease("runner2", types.SNMPLeaseRequest{Release: []string{"default:10.0.0.1"}}, now.Add(90*time.Second))
+	assert.Equal(t, "runner1", store.owners(now.Add(90 * time.Second))["default:10.0.0.1"])
+	store.lease("runner1", types.SNMPLeaseRequest{Release: []string{"default:10.0.0.1"}}, now.Add(90*time.Second))
+	response = store.lease("runner2", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1"}, TTL: 60}, now.Add(90*time.Second))
+	assert.Equal(t, []string{"default:10.0.0.1"}, response.Granted)
+
+	// default:10.0.0.3 and other:10.0.0.1 weren't renewed
+	assert.Equal(t, map[string]string{
+		"default:10.0.0.1": "runner2",
+		"default:10.0.0.2": "runner2",
+	}, store.owners(now.Add(100*time.Second)))
+}
+
+func TestSNMPLeaseStoreDefaultTTL(t *testing.T) {
+	store := newSNMPLeaseStore()
+	now := time.Now()
+
+	store.lease("runner1", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1"}}, now)
+	response := store.lease("runner2", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1"}}, now.Add(defaultSNMPLeaseTTL-time.Second))
+	assert.Empty(t, response.Granted)
+	response = store.lease("runner2", types.SNMPLeaseRequest{Devices: []string{"default:10.0.0.1"}}, now.Add(defaultSNMPLeaseTTL+time.Second))
+	assert.Equal(t, []string{"default:10.0.0.1"}, response.Granted)
+}
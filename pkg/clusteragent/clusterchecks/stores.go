@@ -23,13 +23,17 @@ import (
 // operations involving several calls.
 type clusterStore struct {
 	sync.RWMutex
-	active           bool
-	digestToConfig   map[string]integration.Config            // All configurations to dispatch
-	digestToNode     map[string]string                        // Node running a config
-	nodes            map[string]*nodeStore                    // All nodes known to the cluster-agent
-	danglingConfigs  map[string]integration.Config            // Configs we could not dispatch to any node
-	endpointsConfigs map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
-	idToDigest       map[check.ID]string                      // link check IDs to check configs
+	active              bool
+	digestToConfig      map[string]integration.Config            // All configurations to dispatch
+	digestToNode        map[string]string                        // Node running a config
+	nodes               map[string]*nodeStore                    // All nodes known to the cluster-agent
+	danglingConfigs     map[string]integration.Config            // Configs we could not dispatch to any node
+	endpointsConfigs    map[string]map[string]integration.Config // Endpoints configs to be consumed by node agents
+	endpointsHeartbeats map[string]int64                         // Last endpoints configs query of the node agents, by node name
+	endpointsFailovers  map[string]string                        // Endpoints configs dispatched as cluster checks, endpoint digest to cluster check digest
+	endpointsProbes     map[string]*endpointProbe                // Reachability probes of the endpoints configs of nodes without agent, by endpoint digest
+	idToDigest          map[check.ID]string                      // link check IDs to check configs
+	costProfiles        map[string]int                           // average weight of a check instance, by check name
 }
 
 func newClusterStore() *clusterStore {
@@ -46,7 +50,11 @@ func (s *clusterStore) reset() {
 	s.nodes = make(map[string]*nodeStore)
 	s.danglingConfigs = make(map[string]integration.Config)
 	s.endpointsConfigs = make(map[string]map[string]integration.Config)
+	s.endpointsHeartbeats = make(map[string]int64)
+	s.endpointsFailovers = make(map[string]string)
+	s.endpointsProbes = make(map[string]*endpointProbe)
 	s.idToDigest = make(map[check.ID]string)
+	s.costProfiles = make(map[string]int)
 }
 
 // getNodeStore retrieves the store struct for a given node name, if it exists
@@ -72,6 +80,22 @@ func (s *clusterStore) getOrCreateNodeStore(nodeName, clientIP string) *nodeStor
 	return node
 }
 
+// estimatePendingBusyness returns the estimated weight of the check instances dispatched
+// to a node that are not in its runner stats yet.
+// The node lock is to be held by the caller.
+func (s *clusterStore) estimatePendingBusyness(node *nodeStore) int {
+	pending := 0
+	for _, config := range node.digestToConfig {
+		for _, instance := range config.Instances {
+			id := check.BuildID(config.Name, instance, config.InitConfig)
+			if _, found := node.clcRunnerStats[string(id)]; !found {
+				pending += estimateCost(s.costProfiles, config.Name)
+			}
+		}
+	}
+	return pending
+}
+
 // clearDangling resets the danglingConfigs map to a new empty one
 func (s *clusterStore) clearDangling() {
 	s.danglingConfigs = make(map[string]integration.Config)
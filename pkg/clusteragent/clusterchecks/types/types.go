@@ -76,7 +76,35 @@ type CLCRunnersStats map[string]CLCRunnerStats
 // CLCRunnerStats is used to unmarshall the stats of each CLC Runner
 type CLCRunnerStats struct {
 	AverageExecutionTime int  `json:"AverageExecutionTime"`
+	AverageCPUTime       int  `json:"AverageCPUTime"`
 	MetricSamples        int  `json:"MetricSamples"`
 	IsClusterCheck       bool `json:"IsClusterCheck"`
 	LastExecFailed       bool `json:"LastExecFailed"`
 }
+
+// SNMPLeaseRequest holds the SNMP devices a node-agent or a CLC runner
+// wants to poll, and the ones it doesn't poll anymore
+type SNMPLeaseRequest struct {
+	Devices []string `json:"devices"`
+	Release []string `json:"release"`
+	TTL     int      `json:"ttl"` // lease duration in seconds
+}
+
+// SNMPLeaseResponse holds the DCA response for a SNMP lease request
+type SNMPLeaseResponse struct {
+	Granted []string          `json:"granted"`
+	Owners  map[string]string `json:"owners"` // owner of the devices that weren't granted
+}
+
+// CheckTemplate is a check configuration file served by the DCA to the node-agents
+type CheckTemplate struct {
+	Name    string `json:"name"` // integration name
+	Path    string `json:"path"` // path of the file in the backend
+	Content string `json:"content"`
+}
+
+// CheckTemplatesResponse holds the DCA response for a check templates query
+type CheckTemplatesResponse struct {
+	Version   string          `json:"version"`
+	Templates []CheckTemplate `json:"templates"`
+}
@@ -5,9 +5,13 @@
 
 package clusteragent
 
-import "github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks"
+import (
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/checktemplates"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks"
+)
 
 // ServerContext holds business logic classes required to setup API endpoints
 type ServerContext struct {
 	ClusterCheckHandler *clusterchecks.Handler
+	CheckTemplatesStore *checktemplates.Store
 }
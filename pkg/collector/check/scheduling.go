@@ -0,0 +1,54 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+)
+
+// SchedulingOptions control when the runs of a check instance happen within its collection interval
+type SchedulingOptions struct {
+	// Align runs the instance when the wall-clock time is a multiple of its interval,
+	// for example at :00, :15, :30 and :45 with an interval of 15 minutes
+	Align bool
+	// Jitter is the maximum random delay added to the runs of the instance, so that
+	// the instances with the same interval don't run at the same time
+	Jitter time.Duration
+}
+
+// SchedulingOptionsProvider is implemented by the checks whose instance configures their scheduling
+type SchedulingOptionsProvider interface {
+	// SchedulingOptions returns the scheduling options of the check instance
+	SchedulingOptions() SchedulingOptions
+}
+
+// GetSchedulingOptions returns the scheduling options of a check, or the default ones
+// if the check doesn't implement SchedulingOptionsProvider
+func GetSchedulingOptions(c Check) SchedulingOptions {
+	if p, ok := c.(SchedulingOptionsProvider); ok {
+		return p.SchedulingOptions()
+	}
+	return SchedulingOptions{}
+}
+
+// NewSchedulingOptions returns the scheduling options set in the common options of an instance,
+// the jitter can't exceed the interval of the instance
+func NewSchedulingOptions(commonOptions integration.CommonInstanceConfig, interval time.Duration) (SchedulingOptions, error) {
+	if commonOptions.CollectionJitter < 0 {
+		return SchedulingOptions{}, fmt.Errorf("collection_jitter must be positive, got %d", commonOptions.CollectionJitter)
+	}
+	opts := SchedulingOptions{
+		Align:  commonOptions.AlignCollectionInterval,
+		Jitter: time.Duration(commonOptions.CollectionJitter) * time.Second,
+	}
+	if interval > 0 && opts.Jitter > interval {
+		opts.Jitter = interval
+	}
+	return opts, nil
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package check
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+)
+
+func TestNewSchedulingOptions(t *testing.T) {
+	opts, err := NewSchedulingOptions(integration.CommonInstanceConfig{}, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, SchedulingOptions{}, opts)
+
+	opts, err = NewSchedulingOptions(integration.CommonInstanceConfig{AlignCollectionInterval: true, CollectionJitter: 5}, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, SchedulingOptions{Align: true, Jitter: 5 * time.Second}, opts)
+
+	opts, err = NewSchedulingOptions(integration.CommonInstanceConfig{CollectionJitter: 60}, 15*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, SchedulingOptions{Jitter: 15 * time.Second}, opts)
+
+	_, err = NewSchedulingOptions(integration.CommonInstanceConfig{CollectionJitter: -1}, 15*time.Second)
+	assert.Error(t, err)
+}
+
+func TestGetSchedulingOptions(t *testing.T) {
+	assert.Equal(t, SchedulingOptions{}, GetSchedulingOptions(&StubCheck{}))
+}
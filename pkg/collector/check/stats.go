@@ -18,6 +18,12 @@ import (
 const (
 	runCheckFailureTag = "fail"
 	runCheckSuccessTag = "ok"
+
+	// maxWarningHistory is the number of distinct warnings kept in the warning history of a check instance
+	maxWarningHistory = 20
+
+	// maxRunHistory is the number of recent runs kept in the run history of a check instance
+	maxRunHistory = 32
 )
 
 // EventPlatformNameTranslations contains human readable translations for event platform event types
@@ -70,6 +76,24 @@ func (s SenderStats) Copy() (result SenderStats) {
 	return result
 }
 
+// WarningRecord is an entry of the warning history of a check instance, the occurrences of a
+// warning with the same message are grouped in a single record
+type WarningRecord struct {
+	Message   string
+	FirstSeen int64 // first occurrence date, unix timestamp in seconds
+	LastSeen  int64 // most recent occurrence date, unix timestamp in seconds
+	Count     uint64
+}
+
+// RunRecord is an entry of the run history of a check instance
+type RunRecord struct {
+	Timestamp     int64 // execution date, unix timestamp in seconds
+	ExecutionTime int64 // run duration in milliseconds
+	MetricSamples int64
+	Warnings      int
+	Error         bool
+}
+
 // Stats holds basic runtime statistics about check instances
 type Stats struct {
 	CheckName                string
@@ -87,13 +111,18 @@ type Stats struct {
 	TotalServiceChecks       uint64
 	EventPlatformEvents      map[string]int64
 	TotalEventPlatformEvents map[string]int64
-	ExecutionTimes           [32]int64 // circular buffer of recent run durations, most recent at [(TotalRuns+31) % 32]
-	AverageExecutionTime     int64     // average run duration
-	LastExecutionTime        int64     // most recent run duration, provided for convenience
-	LastSuccessDate          int64     // most recent successful execution date, unix timestamp in seconds
-	LastError                string    // error that occurred in the last run, if any
-	LastWarnings             []string  // warnings that occurred in the last run, if any
-	UpdateTimestamp          int64     // latest update to this instance, unix timestamp in seconds
+	ExecutionTimes           [32]int64       // circular buffer of recent run durations, most recent at [(TotalRuns+31) % 32]
+	AverageExecutionTime     int64           // average run duration
+	LastExecutionTime        int64           // most recent run duration, provided for convenience
+	CPUTimes                 [32]int64       // circular buffer of recent run CPU times, when the platform reports them
+	AverageCPUTime           int64           // average run CPU time
+	LastSuccessDate          int64           // most recent successful execution date, unix timestamp in seconds
+	LastError                string          // error that occurred in the last run, if any
+	LastWarnings             []string        // warnings that occurred in the last run, if any
+	WarningHistory           []WarningRecord // distinct warnings of the recent runs, most recently seen last
+	RunHistory               []RunRecord     // recent runs, most recent last
+	UpdateTimestamp          int64           // latest update to this instance, unix timestamp in seconds
+	cpuTimeRuns              uint64
 	m                        sync.Mutex
 	telemetry                bool // do we want telemetry on this Check
 }
@@ -156,6 +185,7 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 		cs.LastSuccessDate = time.Now().Unix()
 	}
 	cs.LastWarnings = []string{}
+	now := time.Now().Unix()
 	if len(warnings) != 0 {
 		if cs.telemetry {
 			tlmWarnings.Add(float64(len(warnings)), cs.CheckName)
@@ -163,9 +193,17 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 		for _, w := range warnings {
 			cs.TotalWarnings++
 			cs.LastWarnings = append(cs.LastWarnings, w.Error())
+			cs.recordWarning(w.Error(), now)
 		}
 	}
-	cs.UpdateTimestamp = time.Now().Unix()
+	cs.UpdateTimestamp = now
+	cs.recordRun(RunRecord{
+		Timestamp:     now,
+		ExecutionTime: tms,
+		MetricSamples: metricStats.MetricSamples,
+		Warnings:      len(warnings),
+		Error:         err != nil,
+	})
 
 	if metricStats.MetricSamples > 0 {
 		cs.MetricSamples = metricStats.MetricSamples
@@ -198,6 +236,58 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 	}
 }
 
+// recordWarning adds an occurrence of a warning to the warning history. The history keeps the
+// last maxWarningHistory distinct messages, an occurrence of a message already in the history
+// moves its record to the end instead of adding a new one. The history is rebuilt rather than
+// updated in place, as the status serializes it without holding the stats lock.
+func (cs *Stats) recordWarning(message string, timestamp int64) {
+	record := WarningRecord{Message: message, FirstSeen: timestamp}
+	history := make([]WarningRecord, 0, maxWarningHistory)
+	for _, r := range cs.WarningHistory {
+		if r.Message == message {
+			record = r
+			continue
+		}
+		history = append(history, r)
+	}
+	record.LastSeen = timestamp
+	record.Count++
+
+	if len(history) >= maxWarningHistory {
+		history = history[len(history)-maxWarningHistory+1:]
+	}
+	cs.WarningHistory = append(history, record)
+}
+
+// recordRun adds a run to the run history, which keeps the last maxRunHistory runs. Like the
+// warning history, it is rebuilt rather than updated in place.
+func (cs *Stats) recordRun(record RunRecord) {
+	history := cs.RunHistory
+	if len(history) >= maxRunHistory {
+		history = history[len(history)-maxRunHistory+1:]
+	}
+	cs.RunHistory = append(append(make([]RunRecord, 0, maxRunHistory), history...), record)
+}
+
+// AddCPUTime tracks the CPU time of the last run
+func (cs *Stats) AddCPUTime(t time.Duration) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+
+	// store CPU times in Milliseconds, like the execution times
+	cs.CPUTimes[cs.cpuTimeRuns%uint64(len(cs.CPUTimes))] = t.Nanoseconds() / 1e6
+	cs.cpuTimeRuns++
+	var totalCPUTime int64
+	ringSize := cs.cpuTimeRuns
+	if ringSize > uint64(len(cs.CPUTimes)) {
+		ringSize = uint64(len(cs.CPUTimes))
+	}
+	for i := uint64(0); i < ringSize; i++ {
+		totalCPUTime += cs.CPUTimes[i]
+	}
+	cs.AverageCPUTime = totalCPUTime / int64(ringSize)
+}
+
 type aggStats struct {
 	EventPlatformEvents       map[string]interface{}
 	EventPlatformEventsErrors map[string]interface{}
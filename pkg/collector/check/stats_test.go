@@ -6,9 +6,12 @@
 package check
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -62,6 +65,69 @@ func TestNewStats(t *testing.T) {
 	assert.Equal(t, stats.CheckConfigSource, "checkConfigSrc")
 }
 
+func TestAddCPUTime(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.AddCPUTime(10 * time.Millisecond)
+	stats.AddCPUTime(30 * time.Millisecond)
+	assert.Equal(t, int64(20), stats.AverageCPUTime)
+
+	// the average is computed over the last 32 runs
+	for i := 0; i < 32; i++ {
+		stats.AddCPUTime(5 * time.Millisecond)
+	}
+	assert.Equal(t, int64(5), stats.AverageCPUTime)
+}
+
+func TestWarningHistory(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.Add(time.Second, nil, []error{errors.New("timeout"), errors.New("no such object")}, SenderStats{})
+	stats.Add(time.Second, nil, []error{}, SenderStats{})
+	stats.Add(time.Second, nil, []error{errors.New("timeout")}, SenderStats{})
+	assert.Equal(t, []string{"timeout"}, stats.LastWarnings)
+
+	// the warnings are kept after a run without warnings, grouped by message
+	assert.Len(t, stats.WarningHistory, 2)
+	assert.Equal(t, "no such object", stats.WarningHistory[0].Message)
+	assert.Equal(t, uint64(1), stats.WarningHistory[0].Count)
+	assert.Equal(t, "timeout", stats.WarningHistory[1].Message)
+	assert.Equal(t, uint64(2), stats.WarningHistory[1].Count)
+	assert.LessOrEqual(t, stats.WarningHistory[1].FirstSeen, stats.WarningHistory[1].LastSeen)
+
+	// only the last distinct warnings are kept
+	for i := 0; i < maxWarningHistory; i++ {
+		stats.Add(time.Second, nil, []error{fmt.Errorf("warning %d", i)}, SenderStats{})
+	}
+	assert.Len(t, stats.WarningHistory, maxWarningHistory)
+	assert.Equal(t, "warning 0", stats.WarningHistory[0].Message)
+	assert.Equal(t, fmt.Sprintf("warning %d", maxWarningHistory-1), stats.WarningHistory[maxWarningHistory-1].Message)
+	assert.Equal(t, uint64(23), stats.TotalWarnings)
+}
+
+func TestRunHistory(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.Add(100*time.Millisecond, nil, []error{}, SenderStats{MetricSamples: 10})
+	stats.Add(2*time.Second, errors.New("timeout"), []error{errors.New("partial results")}, SenderStats{})
+	assert.Len(t, stats.RunHistory, 2)
+	assert.Equal(t, int64(100), stats.RunHistory[0].ExecutionTime)
+	assert.Equal(t, int64(10), stats.RunHistory[0].MetricSamples)
+	assert.False(t, stats.RunHistory[0].Error)
+	assert.Equal(t, int64(2000), stats.RunHistory[1].ExecutionTime)
+	assert.Equal(t, int64(0), stats.RunHistory[1].MetricSamples)
+	assert.Equal(t, 1, stats.RunHistory[1].Warnings)
+	assert.True(t, stats.RunHistory[1].Error)
+
+	// only the last runs are kept
+	for i := 1; i <= maxRunHistory; i++ {
+		stats.Add(time.Duration(i)*time.Millisecond, nil, []error{}, SenderStats{})
+	}
+	assert.Len(t, stats.RunHistory, maxRunHistory)
+	assert.Equal(t, int64(1), stats.RunHistory[0].ExecutionTime)
+	assert.Equal(t, int64(maxRunHistory), stats.RunHistory[maxRunHistory-1].ExecutionTime)
+}
+
 func TestNewStatsStateTelemetryIgnoredWhenGloballyDisabled(t *testing.T) {
 	mockConfig := agentConfig.Mock()
 	mockConfig.Set("telemetry.enabled", false)
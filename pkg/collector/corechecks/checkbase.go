@@ -15,6 +15,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/collector/check/defaults"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	telemetry_utils "github.com/DataDog/datadog-agent/pkg/telemetry/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -42,6 +43,7 @@ type CheckBase struct {
 	checkID        check.ID
 	latestWarnings []error
 	checkInterval  time.Duration
+	scheduling     check.SchedulingOptions
 	source         string
 	telemetry      bool
 }
@@ -118,6 +120,12 @@ func (c *CheckBase) CommonConfigure(instance integration.Data, source string) er
 		c.checkInterval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 	}
 
+	c.scheduling, err = check.NewSchedulingOptions(commonOptions, c.checkInterval)
+	if err != nil {
+		log.Errorf("invalid scheduling options for check %s: %s", string(c.ID()), err)
+		return err
+	}
+
 	// Disable default hostname if specified
 	if commonOptions.EmptyDefaultHostname {
 		s, err := aggregator.GetSender(c.checkID)
@@ -152,6 +160,23 @@ func (c *CheckBase) CommonConfigure(instance integration.Data, source string) er
 	return nil
 }
 
+// PersistCounterBaselines is to be called by the Configure() method of checks
+// submitting rates or monotonic counts of counters, so that their last samples
+// are persisted when `check_cache.enabled` is set and the metrics are reported
+// on the first run following an agent restart.
+func (c *CheckBase) PersistCounterBaselines() error {
+	if !config.Datadog.GetBool("check_cache.enabled") {
+		return nil
+	}
+	s, err := aggregator.GetSender(c.checkID)
+	if err != nil {
+		log.Errorf("failed to retrieve a sender for check %s: %s", string(c.ID()), err)
+		return err
+	}
+	s.PersistCounterBaselines(true)
+	return nil
+}
+
 // Warn sends an integration warning to logs + agent status.
 func (c *CheckBase) Warn(v ...interface{}) error {
 	w := log.Warn(v...)
@@ -192,6 +217,11 @@ func (c *CheckBase) Interval() time.Duration {
 	return c.checkInterval
 }
 
+// SchedulingOptions returns the scheduling options set in the instance configuration
+func (c *CheckBase) SchedulingOptions() check.SchedulingOptions {
+	return c.scheduling
+}
+
 // String returns the name of the check, the same for every instance
 func (c *CheckBase) String() string {
 	return c.checkName
@@ -113,17 +113,30 @@ type KSMConfig struct {
 	// LeaderSkip forces ignoring the leader election when running the check
 	// Can be useful when running the check as cluster check
 	LeaderSkip bool `yaml:"skip_leader_election"`
+
+	// NodeCordonedThreshold is the number of seconds after which a cordoned node is reported
+	// with a critical service check and an event, default 1 hour.
+	NodeCordonedThreshold int `yaml:"node_cordoned_threshold"`
+
+	// PDBBlockedThreshold is the number of seconds after which a PodDisruptionBudget blocking
+	// the eviction of a pod from a cordoned node is reported with a critical service check and an event,
+	// default 30 minutes. It is counted from the time the PodDisruptionBudget stopped allowing disruptions.
+	PDBBlockedThreshold int `yaml:"pdb_blocked_threshold"`
 }
 
 // KSMCheck wraps the config and the metric stores needed to run the check
 type KSMCheck struct {
 	core.CheckBase
-	instance    *KSMConfig
-	store       []cache.Store
-	telemetry   *telemetryCache
-	cancel      context.CancelFunc
-	isCLCRunner bool
-	clusterName string
+	instance     *KSMConfig
+	store        []cache.Store
+	telemetry    *telemetryCache
+	cordons      *disruptionTracker
+	pdbs         *disruptionTracker
+	pdbEvictions *pdbEvictionChecker
+	pdbPending   []pdbCandidate
+	cancel       context.CancelFunc
+	isCLCRunner  bool
+	clusterName  string
 }
 
 // JoinsConfig contains the config parameters for label joins
@@ -192,6 +205,8 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 
 	k.initTags()
 
+	k.initDisruptionTrackers()
+
 	builder := kubestatemetrics.New()
 
 	// Prepare the collectors for the resources specified in the configuration file.
@@ -249,6 +264,8 @@ func (k *KSMCheck) Configure(config, initConfig integration.Data, source string)
 
 	builder.WithKubeClient(c.Cl)
 
+	k.pdbEvictions = newPDBEvictionChecker(c.Cl)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	k.cancel = cancel
 	builder.WithContext(ctx)
@@ -316,9 +333,11 @@ func (k *KSMCheck) Run() error {
 	for _, store := range k.store {
 		metrics := store.(*ksmstore.MetricsStore).Push(ksmstore.GetAllFamilies, ksmstore.GetAllMetrics)
 		k.processMetrics(sender, metrics, labelJoiner)
+		k.processDisruptions(metrics, labelJoiner)
 		k.processTelemetry(metrics)
 	}
 
+	k.sendDisruptions(sender)
 	k.sendTelemetry(sender)
 
 	return nil
@@ -546,6 +565,64 @@ func (k *KSMCheck) initTags() {
 	}
 }
 
+// initDisruptionTrackers creates the trackers of the cordoned nodes and of the PodDisruptionBudgets blocking evictions
+func (k *KSMCheck) initDisruptionTrackers() {
+	nodeThreshold := defaultNodeCordonedThreshold
+	if k.instance.NodeCordonedThreshold > 0 {
+		nodeThreshold = time.Duration(k.instance.NodeCordonedThreshold) * time.Second
+	}
+	pdbThreshold := defaultPDBBlockedThreshold
+	if k.instance.PDBBlockedThreshold > 0 {
+		pdbThreshold = time.Duration(k.instance.PDBBlockedThreshold) * time.Second
+	}
+	k.cordons = newNodeCordonTracker(nodeThreshold)
+	k.pdbs = newPDBBlockedTracker(pdbThreshold)
+}
+
+// processDisruptions records the nodes that are cordoned and the PodDisruptionBudgets that allow no disruption,
+// it can be called multiple times during a check run then sendDisruptions should be called to report them
+func (k *KSMCheck) processDisruptions(metrics map[string][]ksmstore.DDMetricsFam, labelJoiner *labelJoiner) {
+	ts := now()
+	for _, family := range metrics["kube_node_spec_unschedulable"] {
+		lMapperOverride := labelsMapperOverride(family.Name)
+		for _, m := range family.ListMetrics {
+			node, found := m.Labels["node"]
+			if !found {
+				continue
+			}
+			hostname, tags := k.hostnameAndTags(m.Labels, labelJoiner, lMapperOverride)
+			k.cordons.observe(node, m.Val == 1.0, hostname, tags, ts, ts)
+		}
+	}
+
+	for _, family := range metrics["kube_poddisruptionbudget_status_pod_disruptions_allowed"] {
+		lMapperOverride := labelsMapperOverride(family.Name)
+		for _, m := range family.ListMetrics {
+			pdb, found := m.Labels["poddisruptionbudget"]
+			if !found {
+				continue
+			}
+			hostname, tags := k.hostnameAndTags(m.Labels, labelJoiner, lMapperOverride)
+			k.pdbPending = append(k.pdbPending, pdbCandidate{
+				namespace:  m.Labels["namespace"],
+				name:       pdb,
+				hostname:   hostname,
+				tags:       tags,
+				allowsNone: m.Val == 0.0,
+			})
+		}
+	}
+}
+
+// sendDisruptions sends the service checks and the events of the cordoned nodes and of the PodDisruptionBudgets blocking evictions
+func (k *KSMCheck) sendDisruptions(s aggregator.Sender) {
+	ts := now()
+	k.pdbs.resolvePDBs(k.pdbPending, k.cordons.disrupted(), k.pdbEvictions, ts)
+	k.pdbPending = nil
+	k.cordons.flush(s, ts)
+	k.pdbs.flush(s, ts)
+}
+
 // processTelemetry accumulates the telemetry metric values, it can be called multiple times
 // during a check run then sendTelemetry should be called to forward the calculated values
 func (k *KSMCheck) processTelemetry(metrics map[string][]ksmstore.DDMetricsFam) {
@@ -617,6 +694,8 @@ func newKSMCheck(base core.CheckBase, instance *KSMConfig) *KSMCheck {
 		CheckBase:   base,
 		instance:    instance,
 		telemetry:   newTelemetryCache(),
+		cordons:     newNodeCordonTracker(defaultNodeCordonedThreshold),
+		pdbs:        newPDBBlockedTracker(defaultPDBBlockedThreshold),
 		isCLCRunner: config.IsCLCRunner(),
 	}
 }
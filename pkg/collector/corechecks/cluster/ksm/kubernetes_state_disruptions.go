@@ -0,0 +1,260 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package ksm
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// defaultNodeCordonedThreshold is the time a node can stay cordoned before being reported
+	defaultNodeCordonedThreshold = time.Hour
+	// defaultPDBBlockedThreshold is the time a PodDisruptionBudget can block evictions before being reported
+	defaultPDBBlockedThreshold = 30 * time.Minute
+	// pdbEvictionsTimeout is the timeout of the API server queries checking whether a PodDisruptionBudget blocks an eviction
+	pdbEvictionsTimeout = 5 * time.Second
+)
+
+// disruptionState is the state of a node or a PodDisruptionBudget across check runs
+type disruptionState struct {
+	hostname string
+	tags     []string
+	// disrupted is true when the node is cordoned or the PodDisruptionBudget blocks evictions
+	disrupted bool
+	since     time.Time
+	// reported is true when an event was sent because the disruption lasted longer than the threshold
+	reported bool
+	// recovered is true when a reported disruption ended during the current run
+	recovered bool
+	until     time.Time
+	seen      bool
+}
+
+// disruptionTracker reports the objects that stay disrupted for longer than a threshold
+// with a service check and an event, instead of having to reconstruct it from the Kubernetes events.
+type disruptionTracker struct {
+	serviceCheckName string
+	kind             string
+	state            string
+	threshold        time.Duration
+	objects          map[string]*disruptionState
+}
+
+func newNodeCordonTracker(threshold time.Duration) *disruptionTracker {
+	return &disruptionTracker{
+		serviceCheckName: ksmMetricPrefix + "node.schedulable",
+		kind:             "Node",
+		state:            "cordoned",
+		threshold:        threshold,
+		objects:          make(map[string]*disruptionState),
+	}
+}
+
+func newPDBBlockedTracker(threshold time.Duration) *disruptionTracker {
+	return &disruptionTracker{
+		serviceCheckName: ksmMetricPrefix + "pdb.evictions_allowed",
+		kind:             "PodDisruptionBudget",
+		state:            "blocking evictions",
+		threshold:        threshold,
+		objects:          make(map[string]*disruptionState),
+	}
+}
+
+// observe records the current state of an object, it can be called multiple times during a check run.
+// since is the time the disruption started, it is only used when the disruption was not already observed.
+func (d *disruptionTracker) observe(name string, disrupted bool, hostname string, tags []string, since, ts time.Time) {
+	obj, found := d.objects[name]
+	if !found {
+		obj = &disruptionState{}
+		d.objects[name] = obj
+	}
+
+	if disrupted && !obj.disrupted {
+		obj.since = since
+	}
+	if !disrupted && obj.disrupted && obj.reported {
+		obj.recovered = true
+		obj.reported = false
+		obj.until = ts
+	}
+
+	obj.hostname = hostname
+	obj.tags = tags
+	obj.disrupted = disrupted
+	obj.seen = true
+}
+
+// isDisrupted returns whether the object was disrupted when it was last observed
+func (d *disruptionTracker) isDisrupted(name string) bool {
+	obj, found := d.objects[name]
+	return found && obj.disrupted
+}
+
+// disrupted returns the names of the disrupted objects observed during the check run
+func (d *disruptionTracker) disrupted() map[string]struct{} {
+	names := make(map[string]struct{})
+	for name, obj := range d.objects {
+		if obj.seen && obj.disrupted {
+			names[name] = struct{}{}
+		}
+	}
+	return names
+}
+
+// flush sends the service checks of the objects observed during the check run and the events
+// of the disruptions crossing the threshold or ending, the deleted objects are forgotten.
+func (d *disruptionTracker) flush(s aggregator.Sender, ts time.Time) {
+	for name, obj := range d.objects {
+		if !obj.seen {
+			delete(d.objects, name)
+			continue
+		}
+		obj.seen = false
+
+		if obj.recovered {
+			obj.recovered = false
+			d.sendEvent(s, name, obj, metrics.EventAlertTypeSuccess,
+				fmt.Sprintf("%s %s is no longer %s", d.kind, name, d.state),
+				fmt.Sprintf("%s %s was %s from %s to %s.", d.kind, name, d.state,
+					obj.since.UTC().Format(time.RFC3339), obj.until.UTC().Format(time.RFC3339)), ts)
+		}
+
+		if !obj.disrupted {
+			s.ServiceCheck(d.serviceCheckName, metrics.ServiceCheckOK, obj.hostname, obj.tags, "")
+			continue
+		}
+
+		elapsed := ts.Sub(obj.since)
+		message := fmt.Sprintf("%s %s has been %s since %s", d.kind, name, d.state, obj.since.UTC().Format(time.RFC3339))
+		if elapsed < d.threshold {
+			s.ServiceCheck(d.serviceCheckName, metrics.ServiceCheckWarning, obj.hostname, obj.tags, message)
+			continue
+		}
+
+		s.ServiceCheck(d.serviceCheckName, metrics.ServiceCheckCritical, obj.hostname, obj.tags, message)
+		if !obj.reported {
+			obj.reported = true
+			d.sendEvent(s, name, obj, metrics.EventAlertTypeWarning,
+				fmt.Sprintf("%s %s has been %s for more than %s", d.kind, name, d.state, d.threshold), message+".", ts)
+		}
+	}
+}
+
+func (d *disruptionTracker) sendEvent(s aggregator.Sender, name string, obj *disruptionState, alertType metrics.EventAlertType, title, text string, ts time.Time) {
+	s.Event(metrics.Event{
+		Title:          title,
+		Text:           text,
+		Ts:             ts.Unix(),
+		Priority:       metrics.EventPriorityNormal,
+		Host:           obj.hostname,
+		Tags:           obj.tags,
+		AlertType:      alertType,
+		AggregationKey: d.kind + ":" + name,
+		SourceTypeName: "kubernetes",
+		EventType:      kubeStateMetricsCheckName,
+	})
+}
+
+// pdbCandidate is a PodDisruptionBudget observed during the check run, its state is resolved
+// once all the nodes are observed because it only blocks evictions when a node is being drained.
+type pdbCandidate struct {
+	namespace string
+	name      string
+	hostname  string
+	tags      []string
+	// allowsNone is true when the PodDisruptionBudget allows no disruption
+	allowsNone bool
+}
+
+// pdbEvictionChecker queries the API server to know whether a PodDisruptionBudget allowing
+// no disruption actually blocks an eviction and since when it allows no disruption.
+type pdbEvictionChecker struct {
+	client kubernetes.Interface
+}
+
+func newPDBEvictionChecker(client kubernetes.Interface) *pdbEvictionChecker {
+	return &pdbEvictionChecker{client: client}
+}
+
+// blockedSince returns true when one of the pods covered by the PodDisruptionBudget runs on a cordoned node,
+// meaning that draining the node is blocked, and the time the PodDisruptionBudget stopped allowing disruptions
+// according to its status, or a zero time when the status doesn't have the DisruptionAllowed condition.
+func (c *pdbEvictionChecker) blockedSince(namespace, name string, cordoned map[string]struct{}) (bool, time.Time, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pdbEvictionsTimeout)
+	defer cancel()
+
+	pdb, err := c.client.PolicyV1beta1().PodDisruptionBudgets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	var since time.Time
+	for _, condition := range pdb.Status.Conditions {
+		if condition.Type == policyv1beta1.DisruptionAllowedCondition && condition.Status == metav1.ConditionFalse {
+			since = condition.LastTransitionTime.Time
+		}
+	}
+
+	if pdb.Spec.Selector == nil {
+		return false, since, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return false, since, err
+	}
+
+	pods, err := c.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return false, since, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		if _, found := cordoned[pod.Spec.NodeName]; found {
+			return true, since, nil
+		}
+	}
+
+	return false, since, nil
+}
+
+// resolvePDBs records whether the PodDisruptionBudgets observed during the check run block an eviction.
+// A PodDisruptionBudget allowing no disruption is only blocking when one of its pods runs on a cordoned node,
+// the disruption start is taken from its status so that it survives the agent restarts.
+func (d *disruptionTracker) resolvePDBs(candidates []pdbCandidate, cordoned map[string]struct{}, checker *pdbEvictionChecker, ts time.Time) {
+	for _, pdb := range candidates {
+		key := pdb.namespace + "/" + pdb.name
+		blocked, since := false, ts
+		if pdb.allowsNone && len(cordoned) > 0 && checker != nil {
+			var statusSince time.Time
+			var err error
+			blocked, statusSince, err = checker.blockedSince(pdb.namespace, pdb.name, cordoned)
+			if err != nil {
+				log.Debugf("Cannot check whether PodDisruptionBudget %s blocks evictions: %v", key, err)
+				blocked = d.isDisrupted(key)
+			}
+			if !statusSince.IsZero() {
+				since = statusSince
+			}
+		}
+		d.observe(key, blocked, pdb.hostname, pdb.tags, since, ts)
+	}
+}
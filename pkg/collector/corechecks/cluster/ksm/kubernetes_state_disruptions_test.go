@@ -0,0 +1,188 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package ksm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	ksmstore "github.com/DataDog/datadog-agent/pkg/kubestatemetrics/store"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+
+	v1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func disruptionMetrics(nodeUnschedulable, pdbDisruptionsAllowed float64) map[string][]ksmstore.DDMetricsFam {
+	return map[string][]ksmstore.DDMetricsFam{
+		"kube_node_spec_unschedulable": {
+			{
+				Type: "*v1.Node",
+				Name: "kube_node_spec_unschedulable",
+				ListMetrics: []ksmstore.DDMetric{
+					{
+						Labels: map[string]string{"node": "foo"},
+						Val:    nodeUnschedulable,
+					},
+				},
+			},
+		},
+		"kube_poddisruptionbudget_status_pod_disruptions_allowed": {
+			{
+				Type: "*v1beta1.PodDisruptionBudget",
+				Name: "kube_poddisruptionbudget_status_pod_disruptions_allowed",
+				ListMetrics: []ksmstore.DDMetric{
+					{
+						Labels: map[string]string{"namespace": "default", "poddisruptionbudget": "redis"},
+						Val:    pdbDisruptionsAllowed,
+					},
+				},
+			},
+		},
+	}
+}
+
+func redisPDBObjects(blockedSince time.Time, nodeName string) []runtime.Object {
+	return []runtime.Object{
+		&policyv1beta1.PodDisruptionBudget{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis"},
+			Spec: policyv1beta1.PodDisruptionBudgetSpec{
+				Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "redis"}},
+			},
+			Status: policyv1beta1.PodDisruptionBudgetStatus{
+				Conditions: []metav1.Condition{
+					{
+						Type:               policyv1beta1.DisruptionAllowedCondition,
+						Status:             metav1.ConditionFalse,
+						Reason:             policyv1beta1.InsufficientPodsReason,
+						LastTransitionTime: metav1.NewTime(blockedSince),
+					},
+				},
+			},
+		},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "redis-0", Labels: map[string]string{"app": "redis"}},
+			Spec:       v1.PodSpec{NodeName: nodeName},
+			Status:     v1.PodStatus{Phase: v1.PodRunning},
+		},
+	}
+}
+
+func TestProcessDisruptions(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	defer func() { now = time.Now }()
+
+	kubeStateMetricsSCheck := newKSMCheck(core.NewCheckBase(kubeStateMetricsCheckName), &KSMConfig{LabelsMapper: defaultLabelsMapper})
+	kubeStateMetricsSCheck.pdbEvictions = newPDBEvictionChecker(fake.NewSimpleClientset(redisPDBObjects(start, "foo")...))
+	labelJoiner := newLabelJoiner(nil)
+	nodeTags := []string{"node:foo"}
+	pdbTags := []string{"kube_namespace:default", "poddisruptionbudget:redis"}
+
+	run := func(ts time.Time, nodeUnschedulable, pdbDisruptionsAllowed float64) *mocksender.MockSender {
+		now = func() time.Time { return ts }
+		mocked := mocksender.NewMockSender(kubeStateMetricsSCheck.ID())
+		mocked.SetupAcceptAll()
+		kubeStateMetricsSCheck.processDisruptions(disruptionMetrics(nodeUnschedulable, pdbDisruptionsAllowed), labelJoiner)
+		kubeStateMetricsSCheck.sendDisruptions(mocked)
+		return mocked
+	}
+
+	// nothing is disrupted
+	mocked := run(start, 0, 1)
+	mocked.AssertServiceCheck(t, "kubernetes_state.node.schedulable", metrics.ServiceCheckOK, "foo", nodeTags, "")
+	mocked.AssertServiceCheck(t, "kubernetes_state.pdb.evictions_allowed", metrics.ServiceCheckOK, "", pdbTags, "")
+	mocked.AssertNotCalled(t, "Event")
+
+	// the PDB allows no disruption but no node is drained, no eviction is blocked
+	mocked = run(start.Add(time.Minute), 0, 0)
+	mocked.AssertServiceCheck(t, "kubernetes_state.node.schedulable", metrics.ServiceCheckOK, "foo", nodeTags, "")
+	mocked.AssertServiceCheck(t, "kubernetes_state.pdb.evictions_allowed", metrics.ServiceCheckOK, "", pdbTags, "")
+	mocked.AssertNotCalled(t, "Event")
+
+	// the node running a pod of the PDB is cordoned, the PDB blocks its eviction since its status changed
+	mocked = run(start.Add(2*time.Minute), 1, 0)
+	mocked.AssertServiceCheck(t, "kubernetes_state.node.schedulable", metrics.ServiceCheckWarning, "foo", nodeTags, "Node foo has been cordoned since 2020-09-13T12:28:40Z")
+	mocked.AssertServiceCheck(t, "kubernetes_state.pdb.evictions_allowed", metrics.ServiceCheckWarning, "", pdbTags, "PodDisruptionBudget default/redis has been blocking evictions since 2020-09-13T12:26:40Z")
+	mocked.AssertNotCalled(t, "Event")
+
+	// the PDB crosses its threshold
+	mocked = run(start.Add(defaultPDBBlockedThreshold), 1, 0)
+	mocked.AssertServiceCheck(t, "kubernetes_state.node.schedulable", metrics.ServiceCheckWarning, "foo", nodeTags, "Node foo has been cordoned since 2020-09-13T12:28:40Z")
+	mocked.AssertServiceCheck(t, "kubernetes_state.pdb.evictions_allowed", metrics.ServiceCheckCritical, "", pdbTags, "PodDisruptionBudget default/redis has been blocking evictions since 2020-09-13T12:26:40Z")
+	mocked.AssertEvent(t, metrics.Event{
+		AggregationKey: "PodDisruptionBudget:default/redis",
+		Priority:       metrics.EventPriorityNormal,
+		SourceTypeName: "kubernetes",
+		EventType:      kubeStateMetricsCheckName,
+		Ts:             start.Add(defaultPDBBlockedThreshold).Unix(),
+	}, time.Second)
+	mocked.AssertNumberOfCalls(t, "Event", 1)
+
+	// the node crosses its threshold, the PDB event is not sent again
+	mocked = run(start.Add(2*time.Minute+defaultNodeCordonedThreshold), 1, 0)
+	mocked.AssertServiceCheck(t, "kubernetes_state.node.schedulable", metrics.ServiceCheckCritical, "foo", nodeTags, "Node foo has been cordoned since 2020-09-13T12:28:40Z")
+	mocked.AssertServiceCheck(t, "kubernetes_state.pdb.evictions_allowed", metrics.ServiceCheckCritical, "", pdbTags, "PodDisruptionBudget default/redis has been blocking evictions since 2020-09-13T12:26:40Z")
+	mocked.AssertEvent(t, metrics.Event{
+		AggregationKey: "Node:foo",
+		Priority:       metrics.EventPriorityNormal,
+		SourceTypeName: "kubernetes",
+		EventType:      kubeStateMetricsCheckName,
+		Host:           "foo",
+		Ts:             start.Add(2*time.Minute + defaultNodeCordonedThreshold).Unix(),
+	}, time.Second)
+	mocked.AssertNumberOfCalls(t, "Event", 1)
+
+	// the node is uncordoned and the PDB allows evictions again
+	mocked = run(start.Add(2*time.Hour), 0, 1)
+	mocked.AssertServiceCheck(t, "kubernetes_state.node.schedulable", metrics.ServiceCheckOK, "foo", nodeTags, "")
+	mocked.AssertServiceCheck(t, "kubernetes_state.pdb.evictions_allowed", metrics.ServiceCheckOK, "", pdbTags, "")
+	mocked.AssertNumberOfCalls(t, "Event", 2)
+
+	// the deleted objects are forgotten
+	mocked = mocksender.NewMockSender(kubeStateMetricsSCheck.ID())
+	mocked.SetupAcceptAll()
+	kubeStateMetricsSCheck.sendDisruptions(mocked)
+	mocked.AssertNotCalled(t, "ServiceCheck")
+	assert.Empty(t, kubeStateMetricsSCheck.cordons.objects)
+	assert.Empty(t, kubeStateMetricsSCheck.pdbs.objects)
+}
+
+func TestInitDisruptionTrackers(t *testing.T) {
+	k := &KSMCheck{instance: &KSMConfig{NodeCordonedThreshold: 600}}
+	k.initDisruptionTrackers()
+	assert.Equal(t, 10*time.Minute, k.cordons.threshold)
+	assert.Equal(t, defaultPDBBlockedThreshold, k.pdbs.threshold)
+}
+
+func TestPDBEvictionCheckerBlockedSince(t *testing.T) {
+	blockedSince := time.Unix(1600000000, 0)
+	cordoned := map[string]struct{}{"foo": {}}
+
+	// the pod of the PDB runs on the cordoned node
+	checker := newPDBEvictionChecker(fake.NewSimpleClientset(redisPDBObjects(blockedSince, "foo")...))
+	blocked, since, err := checker.blockedSince("default", "redis", cordoned)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	assert.True(t, blockedSince.Equal(since))
+
+	// the pod of the PDB runs on another node
+	checker = newPDBEvictionChecker(fake.NewSimpleClientset(redisPDBObjects(blockedSince, "bar")...))
+	blocked, _, err = checker.blockedSince("default", "redis", cordoned)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	// the PDB doesn't exist
+	_, _, err = checker.blockedSince("default", "unknown", cordoned)
+	assert.Error(t, err)
+}
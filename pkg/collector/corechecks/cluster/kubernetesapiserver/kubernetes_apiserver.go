@@ -54,6 +54,10 @@ type KubeASConfig struct {
 	LeaderSkip               bool     `yaml:"skip_leader_election"`
 	ResyncPeriodEvents       int      `yaml:"kubernetes_event_resync_period_s"`
 	UseComponentStatus       bool     `yaml:"use_component_status"`
+	CollectedEventNamespaces []string `yaml:"collected_event_namespaces"`
+	CollectedEventReasons    []string `yaml:"collected_event_reasons"`
+	EventDedupWindow         int      `yaml:"kubernetes_event_dedup_window_s"`
+	UnbundleEvents           bool     `yaml:"unbundle_events"`
 }
 
 // EventC holds the information pertaining to which event we collected last and when we last re-synced.
@@ -68,6 +72,8 @@ type KubeASCheck struct {
 	instance        *KubeASConfig
 	eventCollection EventC
 	ignoredEvents   string
+	eventFilter     *eventFilter
+	eventDedup      *eventDeduplicator
 	ac              *apiserver.APIClient
 	oshiftAPILevel  apiserver.OpenShiftAPILevel
 	providerIDCache *cache.Cache
@@ -116,7 +122,9 @@ func (k *KubeASCheck) Configure(config, initConfig integration.Data, source stri
 	if k.instance.MaxEventCollection == 0 {
 		k.instance.MaxEventCollection = maxEventCardinality
 	}
-	k.ignoredEvents = convertFilter(k.instance.FilteredEventTypes)
+	k.ignoredEvents = eventFieldSelector(k.instance.FilteredEventTypes, k.instance.CollectedEventNamespaces, k.instance.CollectedEventReasons)
+	k.eventFilter = newEventFilter(k.instance.CollectedEventNamespaces, k.instance.CollectedEventReasons)
+	k.eventDedup = newEventDeduplicator(time.Duration(k.instance.EventDedupWindow) * time.Second)
 
 	return nil
 }
@@ -294,14 +302,22 @@ func (k *KubeASCheck) parseComponentStatus(sender aggregator.Sender, componentsS
 }
 
 // processEvents:
-// - iterates over the Kubernetes Events
+// - iterates over the Kubernetes Events, skipping the ones not allowed or seen recently
 // - extracts some attributes and builds a structure ready to be submitted as a Datadog event (bundle)
 // - formats the bundle and submit the Datadog event
 func (k *KubeASCheck) processEvents(sender aggregator.Sender, events []*v1.Event) error {
 	eventsByObject := make(map[string]*kubernetesEventBundle)
 
-	for _, event := range events {
+	k.eventDedup.purge()
+	for i, event := range events {
+		if !k.eventFilter.allows(event) || k.eventDedup.isDuplicate(event) {
+			continue
+		}
 		id := bundleID(event)
+		if k.instance.UnbundleEvents {
+			// every event is submitted on its own
+			id = fmt.Sprintf("%s/%d", id, i)
+		}
 		bundle, found := eventsByObject[id]
 		if found == false {
 			bundle = newKubernetesEventBundler(event)
@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// eventFieldSelector returns the field selector used to collect the events: the exclusion filters,
+// and the allowlists made of a single value. Field selectors can't express a choice between
+// several values, longer allowlists are applied by the check with an eventFilter.
+func eventFieldSelector(filteredTypes, namespaces, reasons []string) string {
+	selector := convertFilter(filteredTypes)
+	var allowlists []string
+	if len(namespaces) == 1 {
+		allowlists = append(allowlists, fmt.Sprintf("involvedObject.namespace=%s", namespaces[0]))
+	}
+	if len(reasons) == 1 {
+		allowlists = append(allowlists, fmt.Sprintf("reason=%s", reasons[0]))
+	}
+	if len(allowlists) == 0 {
+		return selector
+	}
+	if selector == "" {
+		return strings.Join(allowlists, ",")
+	}
+	return selector + "," + strings.Join(allowlists, ",")
+}
+
+// eventFilter keeps the events matching the namespace and reason allowlists, an empty allowlist allows everything
+type eventFilter struct {
+	namespaces map[string]struct{}
+	reasons    map[string]struct{}
+}
+
+func newEventFilter(namespaces, reasons []string) *eventFilter {
+	if len(namespaces) == 0 && len(reasons) == 0 {
+		return nil
+	}
+	return &eventFilter{
+		namespaces: toSet(namespaces),
+		reasons:    toSet(reasons),
+	}
+}
+
+func toSet(values []string) map[string]struct{} {
+	if len(values) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(values))
+	for _, v := range values {
+		set[v] = struct{}{}
+	}
+	return set
+}
+
+func (f *eventFilter) allows(event *v1.Event) bool {
+	if f == nil {
+		return true
+	}
+	if f.namespaces != nil {
+		if _, ok := f.namespaces[event.InvolvedObject.Namespace]; !ok {
+			return false
+		}
+	}
+	if f.reasons != nil {
+		if _, ok := f.reasons[event.Reason]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// eventDeduplicator drops the events identical to an event submitted less than a window ago: same
+// object, type, reason and message. Repeated events are updated by Kubernetes every time they occur,
+// which would submit them again at every update.
+type eventDeduplicator struct {
+	mu     sync.Mutex
+	window time.Duration
+	seen   map[string]time.Time
+	now    func() time.Time
+}
+
+func newEventDeduplicator(window time.Duration) *eventDeduplicator {
+	if window <= 0 {
+		return nil
+	}
+	return &eventDeduplicator{
+		window: window,
+		seen:   make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+func dedupKey(event *v1.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%s", event.InvolvedObject.UID, event.Type, event.Reason, event.Message)
+}
+
+// isDuplicate returns whether an identical event was seen in the window, and records the event otherwise
+func (d *eventDeduplicator) isDuplicate(event *v1.Event) bool {
+	if d == nil {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	key := dedupKey(event)
+	if seen, ok := d.seen[key]; ok && now.Sub(seen) < d.window {
+		return true
+	}
+	d.seen[key] = now
+	return false
+}
+
+// purge forgets the events seen before the window
+func (d *eventDeduplicator) purge() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := d.now()
+	for key, seen := range d.seen {
+		if now.Sub(seen) >= d.window {
+			delete(d.seen, key)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+// +build kubeapiserver
+
+package kubernetesapiserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+)
+
+func TestEventFieldSelector(t *testing.T) {
+	assert.Equal(t, "", eventFieldSelector(nil, nil, nil))
+	assert.Equal(t, "reason!=OOM", eventFieldSelector([]string{"OOM"}, []string{"default", "kube-system"}, []string{"BackOff", "Failed"}))
+	assert.Equal(t, "involvedObject.namespace=default,reason=BackOff", eventFieldSelector(nil, []string{"default"}, []string{"BackOff"}))
+	assert.Equal(t, "type!=Normal,reason=BackOff", eventFieldSelector([]string{"type!=Normal"}, nil, []string{"BackOff"}))
+}
+
+func TestEventFilter(t *testing.T) {
+	backOff := createEvent(1, "default", "pod", "Pod", "uid", "kubelet", "node", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	scheduled := createEvent(1, "default", "pod", "Pod", "uid", "default-scheduler", "node", "Scheduled", "Successfully assigned pod", "Normal", 709662600)
+	otherNamespace := createEvent(1, "kube-system", "pod", "Pod", "uid", "kubelet", "node", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+
+	var noFilter *eventFilter
+	assert.Nil(t, newEventFilter(nil, nil))
+	assert.True(t, noFilter.allows(scheduled))
+
+	f := newEventFilter([]string{"default", "monitoring"}, nil)
+	assert.True(t, f.allows(backOff))
+	assert.True(t, f.allows(scheduled))
+	assert.False(t, f.allows(otherNamespace))
+
+	f = newEventFilter([]string{"default", "kube-system"}, []string{"BackOff", "Failed"})
+	assert.True(t, f.allows(backOff))
+	assert.False(t, f.allows(scheduled))
+	assert.True(t, f.allows(otherNamespace))
+}
+
+func TestEventDeduplicator(t *testing.T) {
+	assert.Nil(t, newEventDeduplicator(0))
+
+	now := time.Now()
+	d := newEventDeduplicator(time.Minute)
+	d.now = func() time.Time { return now }
+
+	ev1 := createEvent(1, "default", "pod", "Pod", "uid", "kubelet", "node", "BackOff", "Back-off restarting failed container", "Warning", 709662600)
+	ev2 := createEvent(2, "default", "pod", "Pod", "uid", "kubelet", "node", "BackOff", "Back-off restarting failed container", "Warning", 709662630)
+	ev3 := createEvent(1, "default", "pod", "Pod", "uid", "kubelet", "node", "Failed", "Error: ImagePullBackOff", "Warning", 709662630)
+
+	assert.False(t, d.isDuplicate(ev1))
+	// the same event updated by Kubernetes is a duplicate, not a different event of the object
+	assert.True(t, d.isDuplicate(ev2))
+	assert.False(t, d.isDuplicate(ev3))
+
+	now = now.Add(time.Minute)
+	d.purge()
+	assert.Empty(t, d.seen)
+	assert.False(t, d.isDuplicate(ev2))
+}
+
+func TestProcessEventsFilteredAndDeduplicated(t *testing.T) {
+	ev1 := createEvent(2, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "default-scheduler", "machine-blue", "Scheduled", "Successfully assigned dca-789976f5d7-2ljx6 to ip-10-0-0-54", "Normal", 709662600)
+	ev2 := createEvent(3, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Normal", 709662600)
+	ev3 := createEvent(4, "default", "dca-789976f5d7-2ljx6", "Pod", "e6417a7f-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Normal", 709662630)
+	ev4 := createEvent(1, "kube-system", "coredns-5d4dd4b4db-2d6nf", "Pod", "e63e74fa-f566-11e7-9749-0e4863e1cbf4", "kubelet", "machine-blue", "BackOff", "Back-off restarting failed container", "Normal", 709662600)
+
+	kubeASCheck := NewKubeASCheck(core.NewCheckBase(kubernetesAPIServerCheckName), &KubeASConfig{UnbundleEvents: true})
+	kubeASCheck.eventFilter = newEventFilter([]string{"default"}, nil)
+	kubeASCheck.eventDedup = newEventDeduplicator(time.Minute)
+
+	mocked := mocksender.NewMockSender(kubeASCheck.ID())
+	mocked.On("Event", mock.AnythingOfType("metrics.Event"))
+	kubeASCheck.processEvents(mocked, []*v1.Event{ev1, ev2, ev3, ev4})
+
+	// ev3 is a duplicate of ev2, ev4 isn't in an allowed namespace,
+	// ev1 and ev2 are submitted separately although they are about the same object
+	mocked.AssertNumberOfCalls(t, "Event", 2)
+	mocked.AssertExpectations(t)
+}
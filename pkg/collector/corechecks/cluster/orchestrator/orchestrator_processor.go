@@ -42,6 +42,9 @@ func processStatefulSetList(statefulSetList []*v1.StatefulSet, groupID int32, cf
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sStatefulSet.String(), statefulSet.Annotations)
+
 		// extract statefulSet info
 		statefulSetModel := extractStatefulSet(statefulSet)
 
@@ -52,6 +55,11 @@ func processStatefulSetList(statefulSetList []*v1.StatefulSet, groupID int32, cf
 			log.Warnf("Could not marshal StatefulSet to JSON: %s", err)
 			continue
 		}
+		jsonStatefulSet, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sStatefulSet.String(), jsonStatefulSet)
+		if err != nil {
+			log.Warnf("Could not scrub StatefulSet manifest: %s", err)
+			continue
+		}
 		statefulSetModel.Yaml = jsonStatefulSet
 
 		statefulSetMsgs = append(statefulSetMsgs, statefulSetModel)
@@ -85,6 +93,9 @@ func processDaemonSetList(daemonSetList []*v1.DaemonSet, groupID int32, cfg *con
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sDaemonSet.String(), daemonSet.Annotations)
+
 		// extract daemonSet info
 		daemonSetModel := extractDaemonSet(daemonSet)
 
@@ -95,6 +106,11 @@ func processDaemonSetList(daemonSetList []*v1.DaemonSet, groupID int32, cfg *con
 			log.Warnf("Could not marshal DaemonSet to JSON: %s", err)
 			continue
 		}
+		jsonDaemonSet, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sDaemonSet.String(), jsonDaemonSet)
+		if err != nil {
+			log.Warnf("Could not scrub DaemonSet manifest: %s", err)
+			continue
+		}
 		daemonSetModel.Yaml = jsonDaemonSet
 
 		daemonSetMsgs = append(daemonSetMsgs, daemonSetModel)
@@ -129,6 +145,9 @@ func processCronJobList(cronJobList []*batchv1beta1.CronJob, groupID int32, cfg
 		}
 		redact.RemoveLastAppliedConfigurationAnnotation(cronJob.Annotations)
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sCronJob.String(), cronJob.Annotations)
+
 		// extract cronJob info
 		cronJobModel := extractCronJob(cronJob)
 		// scrub & generate YAML
@@ -147,6 +166,11 @@ func processCronJobList(cronJobList []*batchv1beta1.CronJob, groupID int32, cfg
 			log.Warnf("Could not marshal CronJob to JSON: %s", err)
 			continue
 		}
+		jsonCronJob, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sCronJob.String(), jsonCronJob)
+		if err != nil {
+			log.Warnf("Could not scrub CronJob manifest: %s", err)
+			continue
+		}
 		cronJobModel.Yaml = jsonCronJob
 
 		cronJobMsgs = append(cronJobMsgs, cronJobModel)
@@ -194,6 +218,9 @@ func processDeploymentList(deploymentList []*v1.Deployment, groupID int32, cfg *
 		}
 		redact.RemoveLastAppliedConfigurationAnnotation(depl.Annotations)
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sDeployment.String(), depl.Annotations)
+
 		// extract deployment info
 		deployModel := extractDeployment(depl)
 		// scrub & generate YAML
@@ -212,6 +239,11 @@ func processDeploymentList(deploymentList []*v1.Deployment, groupID int32, cfg *
 			log.Warnf("Could not marshal Deployment to JSON: %s", err)
 			continue
 		}
+		jsonDeploy, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sDeployment.String(), jsonDeploy)
+		if err != nil {
+			log.Warnf("Could not scrub Deployment manifest: %s", err)
+			continue
+		}
 		deployModel.Yaml = jsonDeploy
 
 		deployMsgs = append(deployMsgs, deployModel)
@@ -258,6 +290,9 @@ func processJobList(jobList []*batchv1.Job, groupID int32, cfg *config.Orchestra
 		}
 		redact.RemoveLastAppliedConfigurationAnnotation(job.Annotations)
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sJob.String(), job.Annotations)
+
 		// extract job info
 		jobModel := extractJob(job)
 		// scrub & generate YAML
@@ -276,6 +311,11 @@ func processJobList(jobList []*batchv1.Job, groupID int32, cfg *config.Orchestra
 			log.Warnf("Could not marshal Job to JSON: %s", err)
 			continue
 		}
+		jsonJob, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sJob.String(), jsonJob)
+		if err != nil {
+			log.Warnf("Could not scrub Job manifest: %s", err)
+			continue
+		}
 		jobModel.Yaml = jsonJob
 
 		jobMsgs = append(jobMsgs, jobModel)
@@ -323,6 +363,9 @@ func processReplicaSetList(rsList []*v1.ReplicaSet, groupID int32, cfg *config.O
 		}
 		redact.RemoveLastAppliedConfigurationAnnotation(r.Annotations)
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sReplicaSet.String(), r.Annotations)
+
 		// extract replica set info
 		rsModel := extractReplicaSet(r)
 
@@ -343,6 +386,11 @@ func processReplicaSetList(rsList []*v1.ReplicaSet, groupID int32, cfg *config.O
 			log.Warnf("Could not marshal ReplicaSet to JSON: %s", err)
 			continue
 		}
+		jsonRS, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sReplicaSet.String(), jsonRS)
+		if err != nil {
+			log.Warnf("Could not scrub ReplicaSet manifest: %s", err)
+			continue
+		}
 		rsModel.Yaml = jsonRS
 
 		rsMsgs = append(rsMsgs, rsModel)
@@ -390,6 +438,9 @@ func processServiceList(serviceList []*corev1.Service, groupID int32, cfg *confi
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sService.String(), svc.Annotations)
+
 		serviceModel := extractService(svc)
 
 		// k8s objects only have json "omitempty" annotations
@@ -399,6 +450,11 @@ func processServiceList(serviceList []*corev1.Service, groupID int32, cfg *confi
 			log.Warnf("Could not marshal Service to JSON: %s", err)
 			continue
 		}
+		jsonSvc, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sService.String(), jsonSvc)
+		if err != nil {
+			log.Warnf("Could not scrub Service manifest: %s", err)
+			continue
+		}
 		serviceModel.Yaml = jsonSvc
 
 		serviceMsgs = append(serviceMsgs, serviceModel)
@@ -494,6 +550,9 @@ func processNodesList(nodesList []*corev1.Node, groupID int32, cfg *config.Orche
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sNode.String(), node.Annotations)
+
 		nodeModel := extractNode(node)
 		// k8s objects only have json "omitempty" annotations
 		// + marshalling is more performant than YAML
@@ -502,6 +561,11 @@ func processNodesList(nodesList []*corev1.Node, groupID int32, cfg *config.Orche
 			log.Warnf("Could not marshal Node to JSON: %s", err)
 			continue
 		}
+		jsonNode, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sNode.String(), jsonNode)
+		if err != nil {
+			log.Warnf("Could not scrub Node manifest: %s", err)
+			continue
+		}
 		nodeModel.Yaml = jsonNode
 
 		// additional tags
@@ -638,6 +702,9 @@ func processPersistentVolumeList(pvList []*corev1.PersistentVolume, groupID int3
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sPersistentVolume.String(), pv.Annotations)
+
 		pvModel := extractPersistentVolume(pv)
 
 		// k8s objects only have json "omitempty" annotations
@@ -647,6 +714,11 @@ func processPersistentVolumeList(pvList []*corev1.PersistentVolume, groupID int3
 			log.Warnf("Could not marshal PersistentVolume to JSON: %s", err)
 			continue
 		}
+		jsonPv, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sPersistentVolume.String(), jsonPv)
+		if err != nil {
+			log.Warnf("Could not scrub PersistentVolume manifest: %s", err)
+			continue
+		}
 		pvModel.Yaml = jsonPv
 
 		addAdditionalPVTags(pvModel)
@@ -706,6 +778,9 @@ func processPersistentVolumeClaimList(pvcList []*corev1.PersistentVolumeClaim, g
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sPersistentVolumeClaim.String(), pvc.Annotations)
+
 		pvModel := extractPersistentVolumeClaim(pvc)
 
 		// k8s objects only have json "omitempty" annotations
@@ -715,6 +790,11 @@ func processPersistentVolumeClaimList(pvcList []*corev1.PersistentVolumeClaim, g
 			log.Warnf("Could not marshal PersistentVolumeClaim to JSON: %s", err)
 			continue
 		}
+		jsonPvc, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sPersistentVolumeClaim.String(), jsonPvc)
+		if err != nil {
+			log.Warnf("Could not scrub PersistentVolumeClaim manifest: %s", err)
+			continue
+		}
 		pvModel.Yaml = jsonPvc
 
 		pvcMsgs = append(pvcMsgs, pvModel)
@@ -762,6 +842,9 @@ func processRoleList(roleList []*rbacv1.Role, groupID int32, cfg *config.Orchest
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sRole.String(), role.Annotations)
+
 		roleModel := extractRole(role)
 
 		// k8s objects only have json "omitempty" annotations
@@ -771,6 +854,11 @@ func processRoleList(roleList []*rbacv1.Role, groupID int32, cfg *config.Orchest
 			log.Warnf("Could not marshal Role to JSON: %s", err)
 			continue
 		}
+		jsonRole, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sRole.String(), jsonRole)
+		if err != nil {
+			log.Warnf("Could not scrub Role manifest: %s", err)
+			continue
+		}
 		roleModel.Yaml = jsonRole
 
 		roleMsgs = append(roleMsgs, roleModel)
@@ -818,6 +906,9 @@ func processRoleBindingList(roleBindingList []*rbacv1.RoleBinding, groupID int32
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sRoleBinding.String(), roleBinding.Annotations)
+
 		roleBindingModel := extractRoleBinding(roleBinding)
 
 		// k8s objects only have json "omitempty" annotations
@@ -827,6 +918,11 @@ func processRoleBindingList(roleBindingList []*rbacv1.RoleBinding, groupID int32
 			log.Warnf("Could not marshal RoleBinding to JSON: %s", err)
 			continue
 		}
+		jsonRole, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sRoleBinding.String(), jsonRole)
+		if err != nil {
+			log.Warnf("Could not scrub RoleBinding manifest: %s", err)
+			continue
+		}
 		roleBindingModel.Yaml = jsonRole
 
 		roleBindingMsgs = append(roleBindingMsgs, roleBindingModel)
@@ -874,6 +970,9 @@ func processClusterRoleList(clusterRoleList []*rbacv1.ClusterRole, groupID int32
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sClusterRole.String(), clusterRole.Annotations)
+
 		clusterRoleModel := extractClusterRole(clusterRole)
 
 		// k8s objects only have json "omitempty" annotations
@@ -883,6 +982,11 @@ func processClusterRoleList(clusterRoleList []*rbacv1.ClusterRole, groupID int32
 			log.Warnf("Could not marshal ClusterRole to JSON: %s", err)
 			continue
 		}
+		jsonRole, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sClusterRole.String(), jsonRole)
+		if err != nil {
+			log.Warnf("Could not scrub ClusterRole manifest: %s", err)
+			continue
+		}
 		clusterRoleModel.Yaml = jsonRole
 
 		clusterRoleMsgs = append(clusterRoleMsgs, clusterRoleModel)
@@ -930,6 +1034,9 @@ func processClusterRoleBindingList(clusterRoleBindingList []*rbacv1.ClusterRoleB
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sClusterRoleBinding.String(), clusterRoleBinding.Annotations)
+
 		clusterRoleBindingModel := extractClusterRoleBinding(clusterRoleBinding)
 
 		// k8s objects only have json "omitempty" annotations
@@ -939,6 +1046,11 @@ func processClusterRoleBindingList(clusterRoleBindingList []*rbacv1.ClusterRoleB
 			log.Warnf("Could not marshal ClusterRoleBinding to JSON: %s", err)
 			continue
 		}
+		jsonRole, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sClusterRoleBinding.String(), jsonRole)
+		if err != nil {
+			log.Warnf("Could not scrub ClusterRoleBinding manifest: %s", err)
+			continue
+		}
 		clusterRoleBindingModel.Yaml = jsonRole
 
 		clusterRoleBindingMsgs = append(clusterRoleBindingMsgs, clusterRoleBindingModel)
@@ -986,6 +1098,9 @@ func processServiceAccountList(serviceAccountList []*corev1.ServiceAccount, grou
 			continue
 		}
 
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sServiceAccount.String(), serviceAcount.Annotations)
+
 		clusterRoleBindingModel := extractServiceAccount(serviceAcount)
 
 		// k8s objects only have json "omitempty" annotations
@@ -995,6 +1110,11 @@ func processServiceAccountList(serviceAccountList []*corev1.ServiceAccount, grou
 			log.Warnf("Could not marshal ServiceAccount to JSON: %s", err)
 			continue
 		}
+		jsonRole, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sServiceAccount.String(), jsonRole)
+		if err != nil {
+			log.Warnf("Could not scrub ServiceAccount manifest: %s", err)
+			continue
+		}
 		clusterRoleBindingModel.Yaml = jsonRole
 
 		serviceAccountMsgs = append(serviceAccountMsgs, clusterRoleBindingModel)
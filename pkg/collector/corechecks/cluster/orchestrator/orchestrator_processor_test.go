@@ -12,6 +12,12 @@ import (
 
 	model "github.com/DataDog/agent-payload/process"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/config"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/redact"
 )
 
 func TestChunkDeployments(t *testing.T) {
@@ -162,3 +168,32 @@ func TestConvertNodeStatusToTags(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessDeploymentListDroppedAnnotations(t *testing.T) {
+	scrubber, err := redact.NewManifestScrubber([]redact.ManifestScrubbingRule{
+		{Kinds: []string{"Deployment"}, DropAnnotations: []string{"vault.hashicorp.com/*"}},
+	})
+	require.NoError(t, err)
+	cfg := &config.OrchestratorConfig{MaxPerMessage: 100, ManifestScrubber: scrubber}
+
+	deploy := &v1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "web",
+			UID:             "dropped-annotations-deployment",
+			ResourceVersion: "1",
+			Annotations: map[string]string{
+				"vault.hashicorp.com/agent-inject-secret-db": "database/creds/db",
+				"deployment.kubernetes.io/revision":          "3",
+			},
+		},
+	}
+
+	messages, err := processDeploymentList([]*v1.Deployment{deploy}, 1, cfg, "cluster-id")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	deployments := messages[0].(*model.CollectorDeployment).Deployments
+	require.Len(t, deployments, 1)
+
+	assert.Equal(t, []string{"deployment.kubernetes.io/revision:3"}, deployments[0].Metadata.Annotations)
+	assert.NotContains(t, string(deployments[0].Yaml), "vault.hashicorp.com")
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/containers/providers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/system"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 const (
@@ -139,7 +140,7 @@ func computeEvents(events []containerdEvent, sender aggregator.Sender, fil *ddCo
 			continue
 		}
 		if split[1] == "images" {
-			if fil.IsExcluded("", e.ID, "") {
+			if fil.IsContainerExcluded(ddContainers.ContainerFilterInfo{Image: e.ID}) {
 				continue
 			}
 		}
@@ -264,8 +265,19 @@ func isExcluded(ctn containers.Container, fil *ddContainers.Filter) bool {
 	if config.Datadog.GetBool("exclude_pause_container") && ddContainers.IsPauseContainer(ctn.Labels) {
 		return true
 	}
-	// The container name is not available in Containerd, we only rely on image name and kube namespace based exclusion
-	return fil.IsExcluded("", ctn.Image, ctn.Labels["io.kubernetes.pod.namespace"])
+	// The container name is not available in Containerd, we only rely on image name, kube namespace,
+	// label and age based exclusion
+	filterInfo := ddContainers.ContainerFilterInfo{
+		Image:     ctn.Image,
+		Namespace: ctn.Labels["io.kubernetes.pod.namespace"],
+	}
+	if fil.HasLabelOrAgeFilters() {
+		filterInfo = workloadmeta.GetContainerFilterInfo(workloadmeta.GetGlobalStore(), ctn.ID, filterInfo)
+		if filterInfo.Labels == nil {
+			filterInfo.Labels = ctn.Labels
+		}
+	}
+	return fil.IsContainerExcluded(filterInfo)
 }
 
 func computeLinuxSpecificMetrics(metrics *v1.Metrics, sender aggregator.Sender, cu cutil.ContainerdItf, ctn containerd.Container, createdAt time.Time, currentTime time.Time, tags []string) {
@@ -586,4 +586,20 @@ func TestIsExcluded(t *testing.T) {
 		},
 	}
 	require.True(t, isExcluded(c, containerdCheck.filters))
+
+	// Label based filtering
+	config.Datadog.Set("container_exclude", "label:team=ci")
+	containersutil.ResetSharedFilter()
+	containerdCheck.filters, err = containersutil.GetSharedMetricFilter()
+	require.NoError(t, err)
+	c = containers.Container{
+		ID:    "ci",
+		Image: "kubernetes/pawz",
+		Labels: map[string]string{
+			"team": "ci",
+		},
+	}
+	require.True(t, isExcluded(c, containerdCheck.filters))
+	c.Labels["team"] = "web"
+	require.False(t, isExcluded(c, containerdCheck.filters))
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/cri"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 const (
@@ -165,5 +166,22 @@ func (c *CRICheck) isExcluded(ctr *pb.ContainerStatus) bool {
 		image = imSpec.GetImage()
 	}
 
-	return c.filter.IsExcluded(name, image, ctr.GetLabels()["io.kubernetes.pod.namespace"])
+	filterInfo := containers.ContainerFilterInfo{
+		Name:      name,
+		Image:     image,
+		Namespace: ctr.GetLabels()["io.kubernetes.pod.namespace"],
+	}
+	if ctr.GetStartedAt() > 0 {
+		filterInfo.StartedAt = time.Unix(0, ctr.GetStartedAt())
+	}
+	if c.filter.HasLabelOrAgeFilters() {
+		// the labels of the CRI containers are the ones set by the kubelet, the pod labels
+		// are read from the workloadmeta store
+		filterInfo = workloadmeta.GetContainerFilterInfo(workloadmeta.GetGlobalStore(), ctr.GetId(), filterInfo)
+		if filterInfo.Labels == nil {
+			filterInfo.Labels = ctr.GetLabels()
+		}
+	}
+
+	return c.filter.IsContainerExcluded(filterInfo)
 }
@@ -14,14 +14,27 @@ type MetricsAdapter interface {
 	// AdaptTags can be used to change Tagger tags before submitting the metrics
 	AdaptTags(tags []string, c *workloadmeta.Container) []string
 	// AdaptMetrics can be used to change metrics (change name or value) before submitting the metric.
+	// Returning an empty name drops the metric.
 	AdaptMetrics(metricName string, value float64) (string, float64)
 }
 
+// DeviceTagsAdapter can be implemented by a MetricsAdapter to change the tags of the per-device metrics,
+// on top of the `device_name` tag
+type DeviceTagsAdapter interface {
+	AdaptDeviceTags(tags []string, deviceName string) []string
+}
+
 // ContainerLister abstracts away how to list all known containers
 type ContainerLister interface {
 	List() ([]*workloadmeta.Container, error)
 }
 
+// PodLister is implemented by the ContainerLister able to return the pod of a container,
+// the labels of the pod are used to filter the containers
+type PodLister interface {
+	GetKubernetesPodForContainer(containerID string) (*workloadmeta.KubernetesPod, error)
+}
+
 // MetadataContainerLister implements ContainerLister interface using Workload meta service
 type MetadataContainerLister struct{}
 
@@ -30,6 +43,11 @@ func (l MetadataContainerLister) List() ([]*workloadmeta.Container, error) {
 	return workloadmeta.GetGlobalStore().ListContainers()
 }
 
+// GetKubernetesPodForContainer returns the pod of a container
+func (l MetadataContainerLister) GetKubernetesPodForContainer(containerID string) (*workloadmeta.KubernetesPod, error) {
+	return workloadmeta.GetGlobalStore().GetKubernetesPodForContainer(containerID)
+}
+
 // GenericMetricsAdapter implements MetricsAdapter API in a basic way.
 // Adds `runtime` tag and do not change metrics.
 type GenericMetricsAdapter struct{}
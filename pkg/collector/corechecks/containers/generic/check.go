@@ -12,8 +12,10 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	ddConfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/v2/metrics"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 const (
@@ -60,6 +62,14 @@ func (c *ContainerCheck) Configure(config, initConfig integration.Data, source s
 	}
 
 	c.processor = NewProcessor(metrics.GetProvider(), MetadataContainerLister{}, GenericMetricsAdapter{}, filter)
+
+	// the compatibility adapter only covers a subset of the docker check metrics, the docker check stays the default
+	if ddConfig.Datadog.GetBool("container_check_docker_compatibility") {
+		RegisterMetricsAdapter(DockerCompatibilityAdapterName, workloadmeta.ContainerRuntimeDocker, DockerCompatibilityAdapter{})
+	} else {
+		UnregisterMetricsAdapter(DockerCompatibilityAdapterName, workloadmeta.ContainerRuntimeDocker)
+	}
+
 	return c.instance.Parse(config)
 }
 
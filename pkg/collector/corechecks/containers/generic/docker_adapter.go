@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const (
+	// DockerCompatibilityAdapterName is the name the docker compatibility adapter is registered with
+	DockerCompatibilityAdapterName = "docker_compatibility"
+
+	// the docker check reports the CPU times in USER_HZ ticks, i.e. hundredths of a second
+	nanosecondsPerTick = 1e7
+)
+
+// dockerMetric is the legacy docker check metric a generic metric is mapped to
+type dockerMetric struct {
+	name string
+	// scale divides the value of the generic metric, 0 keeps it as is
+	scale float64
+}
+
+// dockerMetricsMapping maps the generic metrics to the metrics of the docker check. Only the metrics reported with
+// the same type and unit as the docker check are mapped: docker.cpu.limit is a rate of the docker check while the
+// generic check reports a gauge, so it's left to the docker check. The metrics the docker check computes from several
+// stats, like docker.mem.in_use, the ones the generic check doesn't collect, like docker.net.*,
+// docker.container.open_fds, docker.containers.* and docker.images.*, and the docker.service_up and docker.exit
+// service checks aren't reported by the adapter either.
+var dockerMetricsMapping = map[string]dockerMetric{
+	"container.uptime":                {name: "docker.uptime"},
+	"container.cpu.usage":             {name: "docker.cpu.usage", scale: nanosecondsPerTick},
+	"container.cpu.user":              {name: "docker.cpu.user", scale: nanosecondsPerTick},
+	"container.cpu.system":            {name: "docker.cpu.system", scale: nanosecondsPerTick},
+	"container.cpu.throttled.time":    {name: "docker.cpu.throttled.time"},
+	"container.cpu.throttled.periods": {name: "docker.cpu.throttled"},
+	"container.cpu.shares":            {name: "docker.cpu.shares"},
+	"container.memory.cache":          {name: "docker.mem.cache"},
+	"container.memory.rss":            {name: "docker.mem.rss"},
+	"container.memory.swap":           {name: "docker.mem.swap"},
+	"container.memory.oomevents":      {name: "docker.mem.failed_count"},
+	"container.memory.limit":          {name: "docker.mem.limit"},
+	"container.memory.soft_limit":     {name: "docker.mem.soft_limit"},
+	"container.memory.kernel":         {name: "docker.kmem.usage"},
+	"container.memory.working_set":    {name: "docker.mem.private_working_set"},
+	"container.memory.commit":         {name: "docker.mem.commit_bytes"},
+	"container.memory.commit.peak":    {name: "docker.mem.commit_peak_bytes"},
+	"container.io.read":               {name: "docker.io.read_bytes"},
+	"container.io.write":              {name: "docker.io.write_bytes"},
+	"container.io.read.operations":    {name: "docker.io.read_operations"},
+	"container.io.write.operations":   {name: "docker.io.write_operations"},
+	"container.pid.thread_count":      {name: "docker.thread.count"},
+	"container.pid.thread_limit":      {name: "docker.thread.limit"},
+}
+
+// DockerCompatibilityAdapter reports the docker containers stats collected by the generic check under the
+// names, units and tags of the docker check metrics. It only covers a subset of the docker check metrics,
+// see dockerMetricsMapping, so it doesn't replace the docker check.
+type DockerCompatibilityAdapter struct{}
+
+var _ DeviceTagsAdapter = DockerCompatibilityAdapter{}
+
+// AdaptTags keeps the tagger tags, the docker check doesn't add a `runtime` tag
+func (a DockerCompatibilityAdapter) AdaptTags(tags []string, c *workloadmeta.Container) []string {
+	return tags
+}
+
+// AdaptMetrics renames the generic metrics to their docker check counterpart and drops the others
+func (a DockerCompatibilityAdapter) AdaptMetrics(metricName string, value float64) (string, float64) {
+	metric, found := dockerMetricsMapping[metricName]
+	if !found {
+		return "", 0
+	}
+	if metric.scale != 0 {
+		value /= metric.scale
+	}
+	return metric.name, value
+}
+
+// AdaptDeviceTags adds the `device` tag the docker check reports along with `device_name`
+func (a DockerCompatibilityAdapter) AdaptDeviceTags(tags []string, deviceName string) []string {
+	return append(tags, "device:"+deviceName)
+}
@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	aggmetrics "github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/containers/v2/metrics"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+func TestDockerCompatibilityAdapter(t *testing.T) {
+	RegisterMetricsAdapter(DockerCompatibilityAdapterName, workloadmeta.ContainerRuntimeDocker, DockerCompatibilityAdapter{})
+	defer UnregisterMetricsAdapter(DockerCompatibilityAdapterName, workloadmeta.ContainerRuntimeDocker)
+
+	containersMeta := []*workloadmeta.Container{
+		createContainerMeta("docker", "cID401"),
+		createContainerMeta("containerd", "cID402"),
+	}
+
+	containersStats := map[string]metrics.MockContainerEntry{
+		"cID401": {
+			ContainerStats: metrics.ContainerStats{
+				CPU: &metrics.ContainerCPUStats{
+					Total:            util.Float64Ptr(2e9),
+					ThrottledPeriods: util.Float64Ptr(3),
+					Limit:            util.Float64Ptr(50),
+				},
+				Memory: &metrics.ContainerMemStats{
+					UsageTotal:   util.Float64Ptr(100),
+					RSS:          util.Float64Ptr(300),
+					KernelMemory: util.Float64Ptr(40),
+					OOMEvents:    util.Float64Ptr(2),
+				},
+				IO: &metrics.ContainerIOStats{
+					Devices: map[string]metrics.DeviceIOStats{
+						"/dev/foo": {
+							ReadBytes: util.Float64Ptr(100),
+						},
+					},
+				},
+			},
+		},
+		"cID402": {
+			ContainerStats: metrics.ContainerStats{
+				CPU: &metrics.ContainerCPUStats{
+					Total: util.Float64Ptr(4e9),
+				},
+			},
+		},
+	}
+
+	mockSender, processor := createTestProcessor(containersMeta, nil, containersStats)
+	err := processor.Run(mockSender, 0)
+	assert.ErrorIs(t, err, nil)
+
+	// the generic metrics are still sent
+	mockSender.AssertMetric(t, "Rate", "container.cpu.usage", 2e9, "", []string{"runtime:docker"})
+	mockSender.AssertMetric(t, "Rate", "container.cpu.usage", 4e9, "", []string{"runtime:containerd"})
+
+	// the CPU times are converted to USER_HZ ticks, like the docker check
+	mockSender.AssertMetric(t, "Rate", "docker.cpu.usage", 200, "", nil)
+	mockSender.AssertMetric(t, "Rate", "docker.cpu.throttled", 3, "", nil)
+	mockSender.AssertMetric(t, "Gauge", "docker.mem.rss", 300, "", nil)
+	mockSender.AssertMetric(t, "Gauge", "docker.kmem.usage", 40, "", nil)
+	mockSender.AssertMetric(t, "Gauge", "docker.mem.failed_count", 2, "", nil)
+	mockSender.AssertMetric(t, "Rate", "docker.io.read_bytes", 100, "", []string{"device:/dev/foo", "device_name:/dev/foo"})
+	mockSender.AssertNotCalled(t, "Gauge", "docker.mem.usage", mock.Anything, mock.Anything, mock.Anything)
+	// the docker check reports docker.cpu.limit as a rate, it isn't reported with a different type
+	mockSender.AssertNotCalled(t, "Gauge", "docker.cpu.limit", mock.Anything, mock.Anything, mock.Anything)
+	mockSender.AssertNotCalled(t, "Rate", "docker.cpu.usage", 400.0, mock.Anything, mock.Anything)
+
+	// the docker metrics have no runtime tag and no unit, their units are known by the backend
+	mockSender.AssertNotCalled(t, "Rate", "docker.cpu.usage", mock.Anything, mock.Anything, mocksender.MatchTagsContains([]string{"runtime:docker"}))
+	mockSender.AssertMetricMetadata(t, "docker.cpu.usage", nil, aggmetrics.SerieMetadata{
+		Resources: []aggmetrics.Resource{{Type: "container", Name: "cID401"}},
+	})
+}
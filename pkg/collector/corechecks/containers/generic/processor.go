@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	aggmetrics "github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/security/log"
 	"github.com/DataDog/datadog-agent/pkg/tagger"
 	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
@@ -19,6 +20,33 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
+// metricUnits are the units of the generic container metrics
+var metricUnits = map[string]string{
+	"container.uptime":              "second",
+	"container.cpu.usage":           "nanocore",
+	"container.cpu.user":            "nanocore",
+	"container.cpu.system":          "nanocore",
+	"container.cpu.throttled.time":  "nanosecond",
+	"container.cpu.limit":           "nanocore",
+	"container.memory.usage":        "byte",
+	"container.memory.kernel":       "byte",
+	"container.memory.limit":        "byte",
+	"container.memory.soft_limit":   "byte",
+	"container.memory.rss":          "byte",
+	"container.memory.cache":        "byte",
+	"container.memory.swap":         "byte",
+	"container.memory.oomevents":    "event",
+	"container.memory.working_set":  "byte",
+	"container.memory.commit":       "byte",
+	"container.memory.commit.peak":  "byte",
+	"container.io.read":             "byte",
+	"container.io.read.operations":  "operation",
+	"container.io.write":            "byte",
+	"container.io.write.operations": "operation",
+	"container.pid.thread_count":    "thread",
+	"container.pid.thread_limit":    "thread",
+}
+
 // Processor contains the core logic of the generic check, allowing reusability
 type Processor struct {
 	metricsProvider metrics.Provider
@@ -37,6 +65,24 @@ func NewProcessor(provider metrics.Provider, lister ContainerLister, adapter Met
 	}
 }
 
+// filterInfo returns the attributes of the container used to filter it, the labels
+// are the labels of its pod when it runs in one
+func (p *Processor) filterInfo(container *workloadmeta.Container) containers.ContainerFilterInfo {
+	info := containers.ContainerFilterInfo{
+		Name:      container.Name,
+		Image:     container.Image.Name,
+		Namespace: container.Labels["io.kubernetes.pod.namespace"],
+		Labels:    container.Labels,
+		StartedAt: container.State.StartedAt,
+	}
+	if podLister, ok := p.ctrLister.(PodLister); ok && info.Namespace != "" {
+		if pod, err := podLister.GetKubernetesPodForContainer(container.ID); err == nil {
+			info.Labels = pod.Labels
+		}
+	}
+	return info
+}
+
 // Run executes the check
 func (p *Processor) Run(sender aggregator.Sender, cacheValidity time.Duration) error {
 	allContainers, err := p.ctrLister.List()
@@ -63,7 +109,7 @@ func (p *Processor) Run(sender aggregator.Sender, cacheValidity time.Duration) e
 			continue
 		}
 
-		if p.ctrFilter.IsExcluded(container.Name, container.Image.Name, container.Labels["io.kubernetes.pod.namespace"]) {
+		if p.ctrFilter.IsContainerExcluded(p.filterInfo(container)) {
 			log.Tracef("Container excluded due to filter, name: %s - image: %s - namespace: %s", container.Name, container.Image.Name, container.Labels["io.kubernetes.pod.namespace"])
 			continue
 		}
@@ -74,8 +120,6 @@ func (p *Processor) Run(sender aggregator.Sender, cacheValidity time.Duration) e
 			log.Errorf("Could not collect tags for container %s: %s", container.ID[:12], err)
 			continue
 		}
-		tags = p.metricsAdapter.AdaptTags(tags, container)
-
 		collector := getCollector(container.Runtime)
 		if collector == nil {
 			log.Warnf("Collector not found for container: %v, metrics will ne missing", container)
@@ -88,9 +132,13 @@ func (p *Processor) Run(sender aggregator.Sender, cacheValidity time.Duration) e
 			continue
 		}
 
-		if err := p.processContainer(sender, tags, container, containerStats); err != nil {
-			log.Debugf("Generating metrics for container: %v failed, metrics may be missing, err: %w", container, err)
-			continue
+		// The stats are collected once and sent through the adapters registered for the runtime as well
+		adapters := append([]MetricsAdapter{p.metricsAdapter}, getMetricsAdapters(container.Runtime)...)
+		for _, adapter := range adapters {
+			adaptedTags := adapter.AdaptTags(extraTags(tags), container)
+			if err := p.processContainer(sender, adapter, adaptedTags, container, containerStats); err != nil {
+				log.Debugf("Generating metrics for container: %v failed, metrics may be missing, err: %w", container, err)
+			}
 		}
 
 		// TODO: Implement container stats. We currently don't have enough information from Metadata service to do it.
@@ -100,70 +148,107 @@ func (p *Processor) Run(sender aggregator.Sender, cacheValidity time.Duration) e
 	return nil
 }
 
-func (p *Processor) processContainer(sender aggregator.Sender, tags []string, container *workloadmeta.Container, containerStats *metrics.ContainerStats) error {
+func (p *Processor) processContainer(sender aggregator.Sender, adapter MetricsAdapter, tags []string, container *workloadmeta.Container, containerStats *metrics.ContainerStats) error {
+	metadata := newContainerMetadata(container.ID)
+
 	if uptime := time.Since(container.State.StartedAt); uptime > 0 {
-		p.sendMetric(sender.Gauge, "container.uptime", util.Float64Ptr(uptime.Seconds()), tags)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.uptime", util.Float64Ptr(uptime.Seconds()), tags, metadata)
 	}
 
 	if containerStats.CPU != nil {
-		p.sendMetric(sender.Rate, "container.cpu.usage", containerStats.CPU.Total, tags)
-		p.sendMetric(sender.Rate, "container.cpu.user", containerStats.CPU.User, tags)
-		p.sendMetric(sender.Rate, "container.cpu.system", containerStats.CPU.System, tags)
-		p.sendMetric(sender.Rate, "container.cpu.throttled.time", containerStats.CPU.ThrottledTime, tags)
-		p.sendMetric(sender.Rate, "container.cpu.throttled.periods", containerStats.CPU.ThrottledPeriods, tags)
-		p.sendMetric(sender.Gauge, "container.cpu.shares", containerStats.CPU.Shares, tags)
+		sendMetric(sender, aggmetrics.RateType, adapter, "container.cpu.usage", containerStats.CPU.Total, tags, metadata)
+		sendMetric(sender, aggmetrics.RateType, adapter, "container.cpu.user", containerStats.CPU.User, tags, metadata)
+		sendMetric(sender, aggmetrics.RateType, adapter, "container.cpu.system", containerStats.CPU.System, tags, metadata)
+		sendMetric(sender, aggmetrics.RateType, adapter, "container.cpu.throttled.time", containerStats.CPU.ThrottledTime, tags, metadata)
+		sendMetric(sender, aggmetrics.RateType, adapter, "container.cpu.throttled.periods", containerStats.CPU.ThrottledPeriods, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.cpu.shares", containerStats.CPU.Shares, tags, metadata)
 		// Convert CPU Limit to nanoseconds to allow easy percentage computation in the App.
 		if containerStats.CPU.Limit != nil {
-			p.sendMetric(sender.Gauge, "container.cpu.limit", util.Float64Ptr(*containerStats.CPU.Limit*float64(time.Second/100)), tags)
+			sendMetric(sender, aggmetrics.GaugeType, adapter, "container.cpu.limit", util.Float64Ptr(*containerStats.CPU.Limit*float64(time.Second/100)), tags, metadata)
 		}
 	}
 
 	if containerStats.Memory != nil {
-		p.sendMetric(sender.Gauge, "container.memory.usage", containerStats.Memory.UsageTotal, tags)
-		p.sendMetric(sender.Gauge, "container.memory.kernel", containerStats.Memory.KernelMemory, tags)
-		p.sendMetric(sender.Gauge, "container.memory.limit", containerStats.Memory.Limit, tags)
-		p.sendMetric(sender.Gauge, "container.memory.soft_limit", containerStats.Memory.Softlimit, tags)
-		p.sendMetric(sender.Gauge, "container.memory.rss", containerStats.Memory.RSS, tags)
-		p.sendMetric(sender.Gauge, "container.memory.cache", containerStats.Memory.Cache, tags)
-		p.sendMetric(sender.Gauge, "container.memory.swap", containerStats.Memory.Swap, tags)
-		p.sendMetric(sender.Gauge, "container.memory.oomevents", containerStats.Memory.OOMEvents, tags)
-		p.sendMetric(sender.Gauge, "container.memory.working_set", containerStats.Memory.PrivateWorkingSet, tags)
-		p.sendMetric(sender.Gauge, "container.memory.commit", containerStats.Memory.CommitBytes, tags)
-		p.sendMetric(sender.Gauge, "container.memory.commit.peak", containerStats.Memory.CommitPeakBytes, tags)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.usage", containerStats.Memory.UsageTotal, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.kernel", containerStats.Memory.KernelMemory, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.limit", containerStats.Memory.Limit, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.soft_limit", containerStats.Memory.Softlimit, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.rss", containerStats.Memory.RSS, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.cache", containerStats.Memory.Cache, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.swap", containerStats.Memory.Swap, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.oomevents", containerStats.Memory.OOMEvents, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.working_set", containerStats.Memory.PrivateWorkingSet, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.commit", containerStats.Memory.CommitBytes, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.memory.commit.peak", containerStats.Memory.CommitPeakBytes, tags, metadata)
 	}
 
 	if containerStats.IO != nil {
 		for deviceName, deviceStats := range containerStats.IO.Devices {
 			deviceTags := extraTags(tags, "device_name:"+deviceName)
-			p.sendMetric(sender.Rate, "container.io.read", deviceStats.ReadBytes, deviceTags)
-			p.sendMetric(sender.Rate, "container.io.read.operations", deviceStats.ReadOperations, deviceTags)
-			p.sendMetric(sender.Rate, "container.io.write", deviceStats.WriteBytes, deviceTags)
-			p.sendMetric(sender.Rate, "container.io.write.operations", deviceStats.WriteOperations, deviceTags)
+			if deviceAdapter, ok := adapter.(DeviceTagsAdapter); ok {
+				deviceTags = deviceAdapter.AdaptDeviceTags(deviceTags, deviceName)
+			}
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.read", deviceStats.ReadBytes, deviceTags, metadata)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.read.operations", deviceStats.ReadOperations, deviceTags, metadata)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.write", deviceStats.WriteBytes, deviceTags, metadata)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.write.operations", deviceStats.WriteOperations, deviceTags, metadata)
 		}
 
 		if len(containerStats.IO.Devices) == 0 {
-			p.sendMetric(sender.Rate, "container.io.read", containerStats.IO.ReadBytes, tags)
-			p.sendMetric(sender.Rate, "container.io.read.operations", containerStats.IO.ReadOperations, tags)
-			p.sendMetric(sender.Rate, "container.io.write", containerStats.IO.WriteBytes, tags)
-			p.sendMetric(sender.Rate, "container.io.write.operations", containerStats.IO.WriteOperations, tags)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.read", containerStats.IO.ReadBytes, tags, metadata)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.read.operations", containerStats.IO.ReadOperations, tags, metadata)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.write", containerStats.IO.WriteBytes, tags, metadata)
+			sendMetric(sender, aggmetrics.RateType, adapter, "container.io.write.operations", containerStats.IO.WriteOperations, tags, metadata)
 		}
 	}
 
 	if containerStats.PID != nil {
-		p.sendMetric(sender.Gauge, "container.pid.thread_count", containerStats.PID.ThreadCount, tags)
-		p.sendMetric(sender.Gauge, "container.pid.thread_limit", containerStats.PID.ThreadLimit, tags)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.pid.thread_count", containerStats.PID.ThreadCount, tags, metadata)
+		sendMetric(sender, aggmetrics.GaugeType, adapter, "container.pid.thread_limit", containerStats.PID.ThreadLimit, tags, metadata)
 	}
 
 	return nil
 }
 
-func (p *Processor) sendMetric(senderFunc func(string, float64, string, []string), metricName string, value *float64, tags []string) {
+func sendMetric(sender aggregator.Sender, mType aggmetrics.MetricType, adapter MetricsAdapter, metricName string, value *float64, tags []string, metadata *containerMetadata) {
 	if value == nil {
 		return
 	}
 
-	metricName, val := p.metricsAdapter.AdaptMetrics(metricName, *value)
-	senderFunc(metricName, val, "", tags)
+	adaptedName, val := adapter.AdaptMetrics(metricName, *value)
+	if adaptedName == "" {
+		return
+	}
+
+	// an adapter renaming a metric may also convert its value, the unit is only known for the generic metrics
+	var unit string
+	if adaptedName == metricName {
+		unit = metricUnits[metricName]
+	}
+	sender.MetricWithMetadata(mType, adaptedName, val, "", tags, metadata.forUnit(unit))
+}
+
+// containerMetadata holds the metadata of the series of a container, there's one per unit
+// shared by all the samples of the container
+type containerMetadata struct {
+	resources []aggmetrics.Resource
+	byUnit    map[string]*aggmetrics.SerieMetadata
+}
+
+func newContainerMetadata(containerID string) *containerMetadata {
+	return &containerMetadata{
+		resources: []aggmetrics.Resource{{Type: "container", Name: containerID}},
+		byUnit:    make(map[string]*aggmetrics.SerieMetadata),
+	}
+}
+
+func (m *containerMetadata) forUnit(unit string) *aggmetrics.SerieMetadata {
+	metadata, ok := m.byUnit[unit]
+	if !ok {
+		metadata = &aggmetrics.SerieMetadata{Unit: unit, Resources: m.resources}
+		m.byUnit[unit] = metadata
+	}
+	return metadata
 }
 
 func extraTags(tags []string, extraTags ...string) []string {
@@ -11,11 +11,13 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	aggmetrics "github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/v2/metrics"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 type mockContainerLister struct {
@@ -182,6 +184,11 @@ func TestProcessorRunFullStatsLinux(t *testing.T) {
 
 	mockSender.AssertMetric(t, "Gauge", "container.pid.thread_count", 10, "", expectedTags)
 	mockSender.AssertMetric(t, "Gauge", "container.pid.thread_limit", 20, "", expectedTags)
+
+	expectedResources := []aggmetrics.Resource{{Type: "container", Name: "cID100"}}
+	mockSender.AssertMetricMetadata(t, "container.memory.usage", expectedTags, aggmetrics.SerieMetadata{Unit: "byte", Resources: expectedResources})
+	mockSender.AssertMetricMetadata(t, "container.cpu.usage", expectedTags, aggmetrics.SerieMetadata{Unit: "nanocore", Resources: expectedResources})
+	mockSender.AssertMetricMetadata(t, "container.cpu.shares", expectedTags, aggmetrics.SerieMetadata{Resources: expectedResources})
 }
 
 func TestProcessorRunPartialStats(t *testing.T) {
@@ -205,3 +212,98 @@ func TestProcessorRunPartialStats(t *testing.T) {
 	mockSender.AssertNumberOfCalls(t, "Rate", 0)
 	mockSender.AssertNumberOfCalls(t, "Gauge", 0)
 }
+
+type dockerTestAdapter struct{}
+
+func (a dockerTestAdapter) AdaptTags(tags []string, c *workloadmeta.Container) []string {
+	return append(tags, "docker_test:true")
+}
+
+func (a dockerTestAdapter) AdaptMetrics(metricName string, value float64) (string, float64) {
+	if metricName == "container.cpu.usage" {
+		return "docker.cpu.usage", value / 1e9
+	}
+	return "", value
+}
+
+func TestProcessorRunRegisteredAdapters(t *testing.T) {
+	RegisterMetricsAdapter("docker-test", workloadmeta.ContainerRuntimeDocker, dockerTestAdapter{})
+	defer UnregisterMetricsAdapter("docker-test", workloadmeta.ContainerRuntimeDocker)
+
+	containersMeta := []*workloadmeta.Container{
+		createContainerMeta("docker", "cID301"),
+		createContainerMeta("containerd", "cID302"),
+	}
+
+	containersStats := map[string]metrics.MockContainerEntry{
+		"cID301": {
+			ContainerStats: metrics.ContainerStats{
+				CPU: &metrics.ContainerCPUStats{
+					Total: util.Float64Ptr(2e9),
+					User:  util.Float64Ptr(1e9),
+				},
+			},
+		},
+		"cID302": {
+			ContainerStats: metrics.ContainerStats{
+				CPU: &metrics.ContainerCPUStats{
+					Total: util.Float64Ptr(4e9),
+				},
+			},
+		},
+	}
+
+	mockSender, processor := createTestProcessor(containersMeta, nil, containersStats)
+	err := processor.Run(mockSender, 0)
+	assert.ErrorIs(t, err, nil)
+
+	// the generic metrics are still sent, the adapter only adds the migrated ones
+	mockSender.AssertNumberOfCalls(t, "Rate", 4)
+	mockSender.AssertMetric(t, "Rate", "container.cpu.usage", 2e9, "", []string{"runtime:docker"})
+	mockSender.AssertMetric(t, "Rate", "container.cpu.user", 1e9, "", []string{"runtime:docker"})
+	mockSender.AssertMetric(t, "Rate", "container.cpu.usage", 4e9, "", []string{"runtime:containerd"})
+	mockSender.AssertMetric(t, "Rate", "docker.cpu.usage", 2, "", []string{"docker_test:true"})
+	mockSender.AssertNotCalled(t, "Rate", "docker.cpu.user", mock.Anything, mock.Anything, mock.Anything)
+}
+
+type mockPodLister struct {
+	mockContainerLister
+	pods map[string]*workloadmeta.KubernetesPod
+}
+
+func (l *mockPodLister) GetKubernetesPodForContainer(containerID string) (*workloadmeta.KubernetesPod, error) {
+	if pod, found := l.pods[containerID]; found {
+		return pod, nil
+	}
+	return nil, fmt.Errorf("pod not found for container %s", containerID)
+}
+
+func TestProcessorFilterInfo(t *testing.T) {
+	podContainer := createContainerMeta("containerd", "cID100")
+	podContainer.Name = "app"
+	podContainer.Image.Name = "app"
+	podContainer.Labels = map[string]string{"io.kubernetes.pod.namespace": "ci"}
+	dockerContainer := createContainerMeta("docker", "cID101")
+	dockerContainer.Labels = map[string]string{"team": "ci"}
+
+	lister := &mockPodLister{
+		pods: map[string]*workloadmeta.KubernetesPod{
+			"cID100": {EntityMeta: workloadmeta.EntityMeta{Labels: map[string]string{"team": "ci"}}},
+		},
+	}
+	p := &Processor{ctrLister: lister}
+
+	info := p.filterInfo(podContainer)
+	assert.Equal(t, containers.ContainerFilterInfo{
+		Name:      "app",
+		Image:     "app",
+		Namespace: "ci",
+		Labels:    map[string]string{"team": "ci"},
+		StartedAt: podContainer.State.StartedAt,
+	}, info)
+
+	// the labels of the container are used when it doesn't run in a pod
+	info = p.filterInfo(dockerContainer)
+	assert.Equal(t, map[string]string{"team": "ci"}, info.Labels)
+	assert.Equal(t, "", info.Namespace)
+}
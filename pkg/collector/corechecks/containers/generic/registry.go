@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package generic
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+var (
+	runtimeAdaptersLock sync.RWMutex
+	runtimeAdapters     = make(map[workloadmeta.ContainerRuntime]map[string]MetricsAdapter)
+)
+
+// RegisterMetricsAdapter registers an additional MetricsAdapter for the containers of a runtime.
+// The Processor sends the metrics of these containers through each registered adapter on top
+// of its own, which allows runtime-specific checks to move their metrics (`docker.*`, `containerd.*`)
+// to the stats collected by the generic check, one metric at a time.
+// Registering an adapter under an existing name replaces it.
+func RegisterMetricsAdapter(name string, runtime workloadmeta.ContainerRuntime, adapter MetricsAdapter) {
+	runtimeAdaptersLock.Lock()
+	defer runtimeAdaptersLock.Unlock()
+
+	if _, found := runtimeAdapters[runtime]; !found {
+		runtimeAdapters[runtime] = make(map[string]MetricsAdapter)
+	}
+	runtimeAdapters[runtime][name] = adapter
+}
+
+// UnregisterMetricsAdapter removes an adapter registered with RegisterMetricsAdapter
+func UnregisterMetricsAdapter(name string, runtime workloadmeta.ContainerRuntime) {
+	runtimeAdaptersLock.Lock()
+	defer runtimeAdaptersLock.Unlock()
+
+	delete(runtimeAdapters[runtime], name)
+	if len(runtimeAdapters[runtime]) == 0 {
+		delete(runtimeAdapters, runtime)
+	}
+}
+
+// getMetricsAdapters returns the adapters registered for a runtime, ordered by name
+func getMetricsAdapters(runtime workloadmeta.ContainerRuntime) []MetricsAdapter {
+	runtimeAdaptersLock.RLock()
+	defer runtimeAdaptersLock.RUnlock()
+
+	names := make([]string, 0, len(runtimeAdapters[runtime]))
+	for name := range runtimeAdapters[runtime] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	adapters := make([]MetricsAdapter, 0, len(names))
+	for _, name := range names {
+		adapters = append(adapters, runtimeAdapters[runtime][name])
+	}
+	return adapters
+}
@@ -0,0 +1,19 @@
+#ifndef TCP_RETRANSMIT_KERN_USER_H
+#define TCP_RETRANSMIT_KERN_USER_H
+
+#include <linux/types.h>
+
+// Number of buckets of the smoothed RTT histogram.
+// Bucket `i` counts the samples between 2^i and 2^(i+1) microseconds, the last one holds everything above.
+#define TCP_RTT_BUCKETS 24
+
+struct retransmit_key {
+    char cgroup_name[129];
+};
+
+struct retransmit_value {
+    __u64 retransmits;
+    __u64 rtt_buckets[TCP_RTT_BUCKETS];
+};
+
+#endif /* defined(TCP_RETRANSMIT_KERN_USER_H) */
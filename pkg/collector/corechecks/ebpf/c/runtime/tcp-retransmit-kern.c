@@ -0,0 +1,140 @@
+#include <linux/compiler.h>
+
+#include <linux/kconfig.h>
+#include <linux/ptrace.h>
+#include <linux/types.h>
+#include <linux/version.h>
+#include <linux/tcp.h>
+
+#include "bpf_helpers.h"
+#include "bpf-common.h"
+#include "tcp-retransmit-kern-user.h"
+
+#if LINUX_VERSION_CODE < KERNEL_VERSION(4, 8, 0)
+// 4.8 is the first version where `bpf_get_current_task` is available
+#error Versions of Linux previous to 4.8.0 are not supported by this probe
+#endif
+
+/*
+ * The `tcp_retransmit_stats` map is used to share with the userland program system-probe
+ * the number of retransmitted segments and the histogram of the smoothed RTT per cgroup
+ */
+
+struct bpf_map_def SEC("maps/tcp_retransmit_stats") tcp_retransmit_stats = {
+    .type = BPF_MAP_TYPE_PERCPU_HASH,
+    .key_size = sizeof(struct retransmit_key),
+    .value_size = sizeof(struct retransmit_value),
+    .max_entries = 1024,
+    .pinning = 0,
+    .namespace = "",
+};
+
+/*
+ * get_sock_cgroup_name reads the name of the cgroup of the socket. The current task can't be used:
+ * the segments are retransmitted by the TCP timers and received in softirq context, on behalf of any task.
+ * The cgroup is the one of the cgroup v2 hierarchy, the socket holds the net_prio and net_cls IDs
+ * instead (one of the lowest two bits is set, like `sock_cgroup_ptr` checks) when these cgroup v1
+ * controllers are used.
+ */
+static __always_inline int get_sock_cgroup_name(struct sock *sk, char *buf, size_t sz) {
+    memset(buf, 0, sz);
+
+#ifdef CONFIG_SOCK_CGROUP_DATA
+    // the first word of `sk_cgrp_data` is the cgroup pointer, whether in the `val` union of the kernels
+    // before 5.15 or in the `cgroup` field of the later ones
+    u64 val = 0;
+    if (bpf_probe_read(&val, sizeof(val), (void *)&sk->sk_cgrp_data) < 0)
+        return -1;
+    if (val == 0 || (val & 3))
+        return -1;
+    struct cgroup *cgrp = (struct cgroup *)val;
+
+    struct kernfs_node *kn;
+    if (bpf_probe_read(&kn, sizeof(kn), &cgrp->kn) < 0)
+        return -1;
+
+    const char *name;
+    if (bpf_probe_read(&name, sizeof(name), &kn->name) < 0)
+        return -1;
+
+    if (bpf_probe_read_str(buf, sz, (void *)name) < 0)
+        return -1;
+
+    return 0;
+#else
+    return -1;
+#endif
+}
+
+static __always_inline struct retransmit_value *get_stats(struct sock *sk) {
+    struct retransmit_value zero = {};
+
+    struct retransmit_key k;
+    get_sock_cgroup_name(sk, k.cgroup_name, sizeof(k.cgroup_name));
+
+    bpf_map_update_elem(&tcp_retransmit_stats, &k, &zero, BPF_NOEXIST);
+    return bpf_map_lookup_elem(&tcp_retransmit_stats, &k);
+}
+
+// log2 without loop so that it can be used on kernels without bounded loops support
+static __always_inline u32 log2_u32(u32 v) {
+    u32 r, shift;
+
+    r = (v > 0xFFFF) << 4;
+    v >>= r;
+    shift = (v > 0xFF) << 3;
+    v >>= shift;
+    r |= shift;
+    shift = (v > 0xF) << 2;
+    v >>= shift;
+    r |= shift;
+    shift = (v > 0x3) << 1;
+    v >>= shift;
+    r |= shift;
+    r |= (v >> 1);
+    return r;
+}
+
+// TODO: replace all `bpf_probe_read` by `bpf_probe_read_kernel` once we can assume that we have at least kernel 5.5
+SEC("kprobe/tcp_retransmit_skb")
+int kprobe__tcp_retransmit_skb(struct pt_regs *ctx) {
+    struct sock *sk = (struct sock *)PT_REGS_PARM1(ctx);
+    struct retransmit_value *v = get_stats(sk);
+    if (!v) {
+        return 0;
+    }
+
+    // per-CPU map, no need for an atomic operation
+    v->retransmits++;
+    return 0;
+}
+
+SEC("kprobe/tcp_rcv_established")
+int kprobe__tcp_rcv_established(struct pt_regs *ctx) {
+    struct sock *sk = (struct sock *)PT_REGS_PARM1(ctx);
+
+    const struct tcp_sock *tp = tcp_sk(sk);
+    u32 srtt_us = 0;
+    bpf_probe_read(&srtt_us, sizeof(srtt_us), (void *)&tp->srtt_us); // smoothed RTT << 3, in usecs
+    srtt_us >>= 3;
+    if (srtt_us == 0) {
+        return 0;
+    }
+
+    struct retransmit_value *v = get_stats(sk);
+    if (!v) {
+        return 0;
+    }
+
+    u32 bucket = log2_u32(srtt_us);
+    if (bucket >= TCP_RTT_BUCKETS) {
+        bucket = TCP_RTT_BUCKETS - 1;
+    }
+    v->rtt_buckets[bucket]++;
+    return 0;
+}
+
+// This number will be interpreted by elf-loader to set the current running kernel version
+__u32 _version SEC("version") = 0xFFFFFFFE; // NOLINT(bugprone-reserved-identifier)
+
+char _license[] SEC("license") = "GPL"; // NOLINT(bugprone-reserved-identifier)
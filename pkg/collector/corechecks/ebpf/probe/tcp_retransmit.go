@@ -0,0 +1,136 @@
+// +build linux_bpf
+
+//go:generate go run ../../../../ebpf/include_headers.go ../c/runtime/tcp-retransmit-kern.c ../../../../ebpf/bytecode/build/runtime/tcp-retransmit.c ../../../../ebpf/c
+//go:generate go run ../../../../ebpf/bytecode/runtime/integrity.go ../../../../ebpf/bytecode/build/runtime/tcp-retransmit.c ../../../../ebpf/bytecode/runtime/tcp-retransmit.go runtime
+
+package probe
+
+import (
+	"fmt"
+	"math"
+	"unsafe"
+
+	"github.com/iovisor/gobpf/pkg/cpupossible"
+	"golang.org/x/sys/unix"
+
+	bpflib "github.com/DataDog/ebpf"
+	"github.com/DataDog/ebpf/manager"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/ebpf/bytecode/runtime"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+/*
+#include <string.h>
+#include "../c/runtime/tcp-retransmit-kern-user.h"
+*/
+import "C"
+
+const retransmitStatsMapName = "tcp_retransmit_stats"
+
+// TCPRetransmitTracer counts the retransmitted TCP segments and samples the round-trip time of the TCP connections
+type TCPRetransmitTracer struct {
+	m        *manager.Manager
+	statsMap *bpflib.Map
+}
+
+// NewTCPRetransmitTracer compiles and starts the TCP retransmit tracer
+func NewTCPRetransmitTracer(cfg *ebpf.Config) (*TCPRetransmitTracer, error) {
+	compiledOutput, err := runtime.TcpRetransmit.Compile(cfg, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer compiledOutput.Close()
+
+	probes := []*manager.Probe{
+		{Section: "kprobe/tcp_retransmit_skb"},
+		{Section: "kprobe/tcp_rcv_established"},
+	}
+
+	maps := []*manager.Map{
+		{Name: retransmitStatsMapName},
+	}
+
+	m := &manager.Manager{
+		Probes: probes,
+		Maps:   maps,
+	}
+
+	managerOptions := manager.Options{
+		RLimit: &unix.Rlimit{
+			Cur: math.MaxUint64,
+			Max: math.MaxUint64,
+		},
+	}
+
+	if err := m.InitWithOptions(compiledOutput, managerOptions); err != nil {
+		return nil, fmt.Errorf("failed to init manager: %w", err)
+	}
+
+	if err := m.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start manager: %w", err)
+	}
+
+	statsMap, ok, err := m.GetMap(retransmitStatsMapName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get map '%s': %w", retransmitStatsMapName, err)
+	} else if !ok {
+		return nil, fmt.Errorf("failed to get map '%s'", retransmitStatsMapName)
+	}
+
+	return &TCPRetransmitTracer{
+		m:        m,
+		statsMap: statsMap,
+	}, nil
+}
+
+// Close stops the tracer
+func (t *TCPRetransmitTracer) Close() {
+	t.m.Stop(manager.CleanAll)
+}
+
+// GetAndFlush returns the statistics collected since the last call, summed over all the CPUs
+func (t *TCPRetransmitTracer) GetAndFlush() TCPRetransmitStats {
+	cpus, err := cpupossible.Get()
+	if err != nil {
+		log.Errorf("Failed to get online CPUs: %v", err)
+		return TCPRetransmitStats{}
+	}
+	nbCpus := len(cpus)
+
+	result := make(TCPRetransmitStats)
+
+	var statsKey C.struct_retransmit_key
+	statsValue := make([]C.struct_retransmit_value, nbCpus)
+	it := t.statsMap.Iterate()
+	for it.Next(unsafe.Pointer(&statsKey), unsafe.Pointer(&statsValue[0])) {
+		containerID := C.GoString(&statsKey.cgroup_name[0])
+		// This cannot happen because statsKey.cgroup_name is filled by bpf_probe_read_str which ensures a NULL-terminated string
+		if len(containerID) >= C.sizeof_struct_retransmit_key {
+			log.Critical("statsKey.cgroup_name wasn’t properly NULL-terminated")
+			break
+		}
+
+		sum := TCPRetransmitStatsValue{
+			RTTBuckets: make([]uint64, C.TCP_RTT_BUCKETS),
+		}
+		for _, cpu := range cpus {
+			sum.Retransmits += uint64(statsValue[cpu].retransmits)
+			for i := range sum.RTTBuckets {
+				sum.RTTBuckets[i] += uint64(statsValue[cpu].rtt_buckets[i])
+			}
+		}
+		result[containerID] = sum
+
+		if err := t.statsMap.Delete(unsafe.Pointer(&statsKey)); err != nil {
+			log.Warnf("failed to delete stat: %s", err)
+		}
+	}
+
+	if err := it.Err(); err != nil {
+		log.Warnf("failed to iterate on TCP retransmit stats while flushing: %s", err)
+	}
+
+	return result
+}
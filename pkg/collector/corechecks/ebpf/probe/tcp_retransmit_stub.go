@@ -0,0 +1,23 @@
+// +build !linux_bpf
+
+package probe
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/ebpf"
+)
+
+// TCPRetransmitTracer is not implemented on non-linux systems
+type TCPRetransmitTracer struct{}
+
+// NewTCPRetransmitTracer is not implemented on non-linux systems
+func NewTCPRetransmitTracer(cfg *ebpf.Config) (*TCPRetransmitTracer, error) {
+	return nil, ebpf.ErrNotImplemented
+}
+
+// Close is not implemented on non-linux systems
+func (t *TCPRetransmitTracer) Close() {}
+
+// GetAndFlush is not implemented on non-linux systems
+func (t *TCPRetransmitTracer) GetAndFlush() TCPRetransmitStats {
+	return nil
+}
@@ -0,0 +1,77 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux_bpf
+
+package probe
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+)
+
+func TestTCPRetransmitTracer(t *testing.T) {
+	kv, err := kernel.HostVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kv < kernel.VersionCode(4, 8, 0) {
+		t.Skipf("Kernel version %v is not supported by the TCP retransmit probe", kv)
+	}
+
+	tracer, err := NewTCPRetransmitTracer(ebpf.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tracer.Close()
+
+	// flush what was collected before the test
+	tracer.GetAndFlush()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		io.Copy(conn, conn)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := make([]byte, 1024*1024)
+	go func() {
+		conn.Write(msg)
+		conn.(*net.TCPConn).CloseWrite()
+	}()
+	if _, err := io.Copy(ioutil.Discard, conn); err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	// the test may run in any cgroup, sum the samples of all of them
+	var samples uint64
+	for _, stats := range tracer.GetAndFlush() {
+		for _, count := range stats.RTTBuckets {
+			samples += count
+		}
+	}
+	if samples == 0 {
+		t.Error("no RTT sample collected")
+	}
+}
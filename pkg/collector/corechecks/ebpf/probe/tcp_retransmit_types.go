@@ -0,0 +1,18 @@
+package probe
+
+// TCPRetransmitStatsValue is the type of the `TCPRetransmitStats` map value: the number of retransmitted segments
+// and the histogram of the smoothed round-trip time of the TCP connections
+type TCPRetransmitStatsValue struct {
+	Retransmits uint64 `json:"retransmits"`
+	// RTTBuckets[i] is the number of RTT samples between 2^i and 2^(i+1) microseconds,
+	// the last bucket also holds the samples above its upper bound
+	RTTBuckets []uint64 `json:"rtt_buckets"`
+}
+
+// TCPRetransmitStats is the map of the TCP retransmit statistics per container
+type TCPRetransmitStats map[string]TCPRetransmitStatsValue
+
+// RTTBucketBounds returns the bounds, in milliseconds, of the i-th RTT bucket
+func RTTBucketBounds(i int) (lowerBound, upperBound float64) {
+	return float64(uint64(1)<<uint(i)) / 1000.0, float64(uint64(1)<<uint(i+1)) / 1000.0
+}
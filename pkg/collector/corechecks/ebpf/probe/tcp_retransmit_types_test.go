@@ -0,0 +1,22 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRTTBucketBounds(t *testing.T) {
+	lower, upper := RTTBucketBounds(0)
+	assert.Equal(t, 0.001, lower)
+	assert.Equal(t, 0.002, upper)
+
+	lower, upper = RTTBucketBounds(10)
+	assert.Equal(t, 1.024, lower)
+	assert.Equal(t, 2.048, upper)
+}
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// FIXME: we require the `cgo` build tag because of this dep relationship:
+// github.com/DataDog/datadog-agent/pkg/process/net depends on `github.com/DataDog/agent-payload/process`,
+// which has a hard dependency on `github.com/DataDog/zstd_0`, which requires CGO.
+// Should be removed once `github.com/DataDog/agent-payload/process` can be imported with CGO disabled.
+// +build cgo
+// +build linux
+
+package ebpf
+
+import (
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe"
+	dd_config "github.com/DataDog/datadog-agent/pkg/config"
+	process_net "github.com/DataDog/datadog-agent/pkg/process/net"
+	"github.com/DataDog/datadog-agent/pkg/tagger"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	tcpRetransmitCheckName = "tcp_retransmit"
+)
+
+// TCPRetransmitConfig is the config of the TCP retransmit check
+type TCPRetransmitConfig struct {
+	CollectTCPRetransmit bool `yaml:"collect_tcp_retransmit"`
+	CollectRTTHistogram  bool `yaml:"collect_rtt_histogram"`
+}
+
+// TCPRetransmitCheck grabs TCP retransmit and round-trip time metrics
+type TCPRetransmitCheck struct {
+	core.CheckBase
+	instance *TCPRetransmitConfig
+}
+
+func init() {
+	core.RegisterCheck(tcpRetransmitCheckName, TCPRetransmitFactory)
+}
+
+// TCPRetransmitFactory is exported for integration testing
+func TCPRetransmitFactory() check.Check {
+	return &TCPRetransmitCheck{
+		CheckBase: core.NewCheckBase(tcpRetransmitCheckName),
+		instance:  &TCPRetransmitConfig{},
+	}
+}
+
+// Parse parses the check configuration
+func (c *TCPRetransmitConfig) Parse(data []byte) error {
+	// default values
+	c.CollectTCPRetransmit = true
+	c.CollectRTTHistogram = true
+
+	return yaml.Unmarshal(data, c)
+}
+
+// Configure parses the check configuration and init the check
+func (t *TCPRetransmitCheck) Configure(config, initConfig integration.Data, source string) error {
+	// TODO: Remove that hard-code and put it somewhere else
+	process_net.SetSystemProbePath(dd_config.Datadog.GetString("system_probe_config.sysprobe_socket"))
+
+	err := t.CommonConfigure(config, source)
+	if err != nil {
+		return err
+	}
+
+	return t.instance.Parse(config)
+}
+
+// Run executes the check
+func (t *TCPRetransmitCheck) Run() error {
+	if !t.instance.CollectTCPRetransmit && !t.instance.CollectRTTHistogram {
+		return nil
+	}
+
+	sysProbeUtil, err := process_net.GetRemoteSystemProbeUtil()
+	if err != nil {
+		return err
+	}
+
+	data, err := sysProbeUtil.GetCheck(tcpRetransmitCheckName)
+	if err != nil {
+		return err
+	}
+
+	sender, err := aggregator.GetSender(t.ID())
+	if err != nil {
+		return err
+	}
+
+	stats, ok := data.(probe.TCPRetransmitStats)
+	if !ok {
+		return log.Errorf("Raw data has incorrect type")
+	}
+
+	for k, v := range stats {
+		entityID := containers.BuildTaggerEntityName(k)
+		var tags []string
+		if entityID != "" {
+			tags, err = tagger.Tag(entityID, collectors.HighCardinality)
+			if err != nil {
+				log.Errorf("Error collecting tags for container %s: %s", k, err)
+			}
+		}
+
+		submitTCPRetransmitStats(sender, v, t.instance, tags)
+	}
+
+	sender.Commit()
+	return nil
+}
+
+// submitTCPRetransmitStats submits the statistics of one container, the statistics are
+// flushed by system-probe on every read so they are submitted as counts
+func submitTCPRetransmitStats(sender aggregator.Sender, stats probe.TCPRetransmitStatsValue, conf *TCPRetransmitConfig, tags []string) {
+	if conf.CollectTCPRetransmit {
+		sender.Count("tcp_retransmit.retransmits", float64(stats.Retransmits), "", tags)
+	}
+
+	if !conf.CollectRTTHistogram {
+		return
+	}
+	for i, count := range stats.RTTBuckets {
+		if count == 0 {
+			continue
+		}
+		lowerBound, upperBound := probe.RTTBucketBounds(i)
+		sender.HistogramBucket("tcp_retransmit.rtt", int64(count), lowerBound, upperBound, false, "", tags, true)
+	}
+}
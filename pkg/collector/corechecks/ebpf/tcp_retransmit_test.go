@@ -0,0 +1,69 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build cgo
+// +build linux
+
+package ebpf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe"
+)
+
+func TestTCPRetransmitConfigParse(t *testing.T) {
+	conf := &TCPRetransmitConfig{}
+	assert.NoError(t, conf.Parse([]byte("collect_rtt_histogram: false")))
+	assert.True(t, conf.CollectTCPRetransmit)
+	assert.False(t, conf.CollectRTTHistogram)
+}
+
+func TestSubmitTCPRetransmitStats(t *testing.T) {
+	sender := mocksender.NewMockSender("tcp_retransmit")
+	sender.SetupAcceptAll()
+
+	stats := probe.TCPRetransmitStatsValue{
+		Retransmits: 3,
+		RTTBuckets:  []uint64{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 5},
+	}
+	tags := []string{"container_name:foo"}
+
+	submitTCPRetransmitStats(sender, stats, &TCPRetransmitConfig{CollectTCPRetransmit: true, CollectRTTHistogram: true}, tags)
+	sender.AssertMetric(t, "Count", "tcp_retransmit.retransmits", 3, "", tags)
+	sender.AssertHistogramBucket(t, "HistogramBucket", "tcp_retransmit.rtt", 5, 1.024, 2.048, false, "", tags, true)
+	sender.AssertNumberOfCalls(t, "HistogramBucket", 1)
+}
+
+func TestSubmitTCPRetransmitStatsWithoutHistogram(t *testing.T) {
+	sender := mocksender.NewMockSender("tcp_retransmit")
+	sender.SetupAcceptAll()
+
+	stats := probe.TCPRetransmitStatsValue{
+		Retransmits: 1,
+		RTTBuckets:  []uint64{1},
+	}
+
+	submitTCPRetransmitStats(sender, stats, &TCPRetransmitConfig{CollectTCPRetransmit: true}, nil)
+	sender.AssertMetric(t, "Count", "tcp_retransmit.retransmits", 1, "", nil)
+	sender.AssertNumberOfCalls(t, "HistogramBucket", 0)
+}
+
+func TestSubmitTCPRetransmitStatsOnlyHistogram(t *testing.T) {
+	sender := mocksender.NewMockSender("tcp_retransmit")
+	sender.SetupAcceptAll()
+
+	stats := probe.TCPRetransmitStatsValue{
+		Retransmits: 1,
+		RTTBuckets:  []uint64{1},
+	}
+
+	submitTCPRetransmitStats(sender, stats, &TCPRetransmitConfig{CollectRTTHistogram: true}, nil)
+	sender.AssertNumberOfCalls(t, "Count", 0)
+	sender.AssertNumberOfCalls(t, "HistogramBucket", 1)
+}
@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package net
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// hostNetworkNamespace is the network_namespace tag value of the network namespace of the init process
+const hostNetworkNamespace = "host"
+
+// conntrackStats are the conntrack statistics of the system
+type conntrackStats struct {
+	// max is the size of the conntrack table, it is shared by the network namespaces
+	max        uint64
+	namespaces []namespaceConntrackStats
+}
+
+// namespaceConntrackStats are the conntrack statistics of a network namespace
+type namespaceConntrackStats struct {
+	namespace    string
+	entries      uint64
+	insertFailed uint64
+	drop         uint64
+	earlyDrop    uint64
+}
+
+// readConntrackStats reads the conntrack statistics of each network namespace of the processes under procfsPath.
+// The statistics of a namespace are read from /proc/<pid>/net/stat/nf_conntrack of one of its processes.
+func readConntrackStats(procfsPath string) (conntrackStats, error) {
+	var stats conntrackStats
+
+	max, err := readUintFile(filepath.Join(procfsPath, "sys/net/netfilter/nf_conntrack_max"))
+	if err != nil {
+		return stats, fmt.Errorf("unable to read the conntrack table size, is the nf_conntrack module loaded? %s", err)
+	}
+	stats.max = max
+
+	pids, err := ioutil.ReadDir(procfsPath)
+	if err != nil {
+		return stats, err
+	}
+
+	hostNamespace, _ := os.Readlink(filepath.Join(procfsPath, "1/ns/net"))
+	seenNamespaces := make(map[string]struct{})
+	for _, pid := range pids {
+		if _, err := strconv.Atoi(pid.Name()); err != nil {
+			continue
+		}
+
+		// the link is net:[<inode>], it can't be read for the processes that exited
+		namespace, err := os.Readlink(filepath.Join(procfsPath, pid.Name(), "ns/net"))
+		if err != nil {
+			continue
+		}
+		if _, found := seenNamespaces[namespace]; found {
+			continue
+		}
+
+		nsStats, err := readNfConntrackStat(filepath.Join(procfsPath, pid.Name(), "net/stat/nf_conntrack"))
+		if err != nil {
+			log.Debugf("Unable to read the conntrack statistics of the network namespace %s of %s: %s", namespace, pid.Name(), err)
+			continue
+		}
+		seenNamespaces[namespace] = struct{}{}
+
+		if namespace == hostNamespace {
+			nsStats.namespace = hostNetworkNamespace
+		} else {
+			nsStats.namespace = strings.TrimSuffix(strings.TrimPrefix(namespace, "net:["), "]")
+		}
+		stats.namespaces = append(stats.namespaces, nsStats)
+	}
+
+	return stats, nil
+}
+
+// readNfConntrackStat parses a nf_conntrack statistics file: a header naming the columns, followed by a line of
+// hexadecimal counters per CPU. The entries are counted per namespace, the other counters are summed.
+func readNfConntrackStat(path string) (namespaceConntrackStats, error) {
+	var stats namespaceConntrackStats
+
+	f, err := os.Open(path)
+	if err != nil {
+		return stats, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return stats, errors.New("nf_conntrack statistics file is empty")
+	}
+	columns := strings.Fields(scanner.Text())
+
+	for scanner.Scan() {
+		values := strings.Fields(scanner.Text())
+		if len(values) != len(columns) {
+			return stats, fmt.Errorf("nf_conntrack statistics file is not formatted correctly, expected %d columns", len(columns))
+		}
+
+		for i, column := range columns {
+			value, err := strconv.ParseUint(values[i], 16, 64)
+			if err != nil {
+				return stats, err
+			}
+			switch column {
+			case "entries":
+				stats.entries = value
+			case "insert_failed":
+				stats.insertFailed += value
+			case "drop":
+				stats.drop += value
+			case "early_drop":
+				stats.earlyDrop += value
+			}
+		}
+	}
+
+	return stats, scanner.Err()
+}
+
+func readUintFile(path string) (uint64, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+}
+
+func submitConntrackMetrics(sender aggregator.Sender, stats conntrackStats) {
+	sender.Gauge("system.net.conntrack.max", float64(stats.max), "", nil)
+
+	for _, nsStats := range stats.namespaces {
+		tags := []string{"network_namespace:" + nsStats.namespace}
+		sender.Gauge("system.net.conntrack.count", float64(nsStats.entries), "", tags)
+		if stats.max > 0 {
+			sender.Gauge("system.net.conntrack.usage", float64(nsStats.entries)/float64(stats.max), "", tags)
+		}
+		sender.MonotonicCount("system.net.conntrack.insert_failed", float64(nsStats.insertFailed), "", tags)
+		sender.MonotonicCount("system.net.conntrack.drop", float64(nsStats.drop), "", tags)
+		sender.MonotonicCount("system.net.conntrack.early_drop", float64(nsStats.earlyDrop), "", tags)
+	}
+}
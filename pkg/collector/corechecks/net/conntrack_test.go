@@ -0,0 +1,110 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package net
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+)
+
+const nfConntrackStat = `entries  searched found new invalid ignore delete delete_list insert insert_failed drop early_drop icmp_error  expect_new expect_create expect_delete search_restart
+000000c8  00000000 00000000 00000000 00000010 00000020 00000000 00000000 00000000 00000002 00000001 00000000 00000000  00000000 00000000 00000000 00000000
+000000c8  00000000 00000000 00000000 00000010 00000020 00000000 00000000 00000000 00000003 00000004 00000001 00000000  00000000 00000000 00000000 00000000
+`
+
+func writeProcessNetNamespace(t *testing.T, procfsPath string, pid string, namespace string, stat string) {
+	pidPath := filepath.Join(procfsPath, pid)
+	require.NoError(t, os.MkdirAll(filepath.Join(pidPath, "ns"), 0755))
+	require.NoError(t, os.Symlink(namespace, filepath.Join(pidPath, "ns/net")))
+	if stat != "" {
+		require.NoError(t, os.MkdirAll(filepath.Join(pidPath, "net/stat"), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(pidPath, "net/stat/nf_conntrack"), []byte(stat), 0644))
+	}
+}
+
+func TestReadNfConntrackStat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nf_conntrack")
+	require.NoError(t, ioutil.WriteFile(path, []byte(nfConntrackStat), 0644))
+
+	stats, err := readNfConntrackStat(path)
+	require.NoError(t, err)
+	assert.Equal(t, namespaceConntrackStats{entries: 200, insertFailed: 5, drop: 5, earlyDrop: 1}, stats)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("entries drop\n00000001\n"), 0644))
+	_, err = readNfConntrackStat(path)
+	assert.Error(t, err)
+}
+
+func TestReadConntrackStats(t *testing.T) {
+	procfsPath := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(procfsPath, "sys/net/netfilter"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(procfsPath, "sys/net/netfilter/nf_conntrack_max"), []byte("262144\n"), 0644))
+
+	writeProcessNetNamespace(t, procfsPath, "1", "net:[4026531992]", nfConntrackStat)
+	// same namespace as the init process
+	writeProcessNetNamespace(t, procfsPath, "42", "net:[4026531992]", nfConntrackStat)
+	// the statistics of a namespace are read from another process when they can't be read from the first one
+	writeProcessNetNamespace(t, procfsPath, "100", "net:[4026532281]", "")
+	writeProcessNetNamespace(t, procfsPath, "101", "net:[4026532281]", "entries drop\n0000000a 00000002\n")
+
+	stats, err := readConntrackStats(procfsPath)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(262144), stats.max)
+	assert.ElementsMatch(t, []namespaceConntrackStats{
+		{namespace: "host", entries: 200, insertFailed: 5, drop: 5, earlyDrop: 1},
+		{namespace: "4026532281", entries: 10, drop: 2},
+	}, stats.namespaces)
+}
+
+func TestReadConntrackStatsNoModule(t *testing.T) {
+	_, err := readConntrackStats(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestNetworkCheckConntrack(t *testing.T) {
+	net := &fakeNetworkStats{
+		conntrackStatsValues: conntrackStats{
+			max: 1000,
+			namespaces: []namespaceConntrackStats{
+				{namespace: "host", entries: 250, insertFailed: 3, drop: 2, earlyDrop: 1},
+			},
+		},
+	}
+
+	networkCheck := NetworkCheck{
+		net: net,
+	}
+
+	err := networkCheck.Configure([]byte(`collect_conntrack_metrics: true`), []byte(``), "test")
+	assert.Nil(t, err)
+
+	mockSender := mocksender.NewMockSender(networkCheck.ID())
+	mockSender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("Commit").Return()
+
+	err = networkCheck.Run()
+	assert.Nil(t, err)
+
+	hostTags := []string{"network_namespace:host"}
+	mockSender.AssertCalled(t, "Gauge", "system.net.conntrack.max", float64(1000), "", []string(nil))
+	mockSender.AssertCalled(t, "Gauge", "system.net.conntrack.count", float64(250), "", hostTags)
+	mockSender.AssertCalled(t, "Gauge", "system.net.conntrack.usage", 0.25, "", hostTags)
+	mockSender.AssertCalled(t, "MonotonicCount", "system.net.conntrack.insert_failed", float64(3), "", hostTags)
+	mockSender.AssertCalled(t, "MonotonicCount", "system.net.conntrack.drop", float64(2), "", hostTags)
+	mockSender.AssertCalled(t, "MonotonicCount", "system.net.conntrack.early_drop", float64(1), "", hostTags)
+	mockSender.AssertCalled(t, "Commit")
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/shirou/gopsutil/net"
 	yaml "gopkg.in/yaml.v2"
@@ -78,6 +79,7 @@ type NetworkCheck struct {
 
 type networkInstanceConfig struct {
 	CollectConnectionState   bool     `yaml:"collect_connection_state"`
+	CollectConntrackMetrics  bool     `yaml:"collect_conntrack_metrics"`
 	ExcludedInterfaces       []string `yaml:"excluded_interfaces"`
 	ExcludedInterfaceRe      string   `yaml:"excluded_interface_re"`
 	ExcludedInterfacePattern *regexp.Regexp
@@ -95,6 +97,7 @@ type networkStats interface {
 	ProtoCounters(protocols []string) ([]net.ProtoCountersStat, error)
 	Connections(kind string) ([]net.ConnectionStat, error)
 	NetstatTCPExtCounters() (map[string]int64, error)
+	ConntrackStats() (conntrackStats, error)
 }
 
 type defaultNetworkStats struct{}
@@ -115,6 +118,14 @@ func (n defaultNetworkStats) NetstatTCPExtCounters() (map[string]int64, error) {
 	return netstatTCPExtCounters()
 }
 
+func (n defaultNetworkStats) ConntrackStats() (conntrackStats, error) {
+	procfsPath := "/proc"
+	if config.Datadog.IsSet("procfs_path") {
+		procfsPath = config.Datadog.GetString("procfs_path")
+	}
+	return readConntrackStats(procfsPath)
+}
+
 // Run executes the check
 func (c *NetworkCheck) Run() error {
 	sender, err := aggregator.GetSender(c.ID())
@@ -178,6 +189,15 @@ func (c *NetworkCheck) Run() error {
 		submitConnectionsMetrics(sender, "tcp6", tcpStateMetricsSuffixMapping, connectionsStats)
 	}
 
+	if c.config.instance.CollectConntrackMetrics {
+		conntrackStats, err := c.net.ConntrackStats()
+		if err != nil {
+			c.Warnf("Unable to collect the conntrack metrics: %s", err) //nolint:errcheck
+		} else {
+			submitConntrackMetrics(sender, conntrackStats)
+		}
+	}
+
 	sender.Commit()
 	return nil
 }
@@ -31,6 +31,8 @@ type fakeNetworkStats struct {
 	connectionStatsTCP6Error    error
 	netstatTCPExtCountersValues map[string]int64
 	netstatTCPExtCountersError  error
+	conntrackStatsValues        conntrackStats
+	conntrackStatsError         error
 }
 
 // IOCounters returns the inner values of counterStats and counterStatsError
@@ -62,6 +64,10 @@ func (n *fakeNetworkStats) NetstatTCPExtCounters() (map[string]int64, error) {
 	return n.netstatTCPExtCountersValues, n.netstatTCPExtCountersError
 }
 
+func (n *fakeNetworkStats) ConntrackStats() (conntrackStats, error) {
+	return n.conntrackStatsValues, n.conntrackStatsError
+}
+
 func TestDefaultConfiguration(t *testing.T) {
 	check := NetworkCheck{}
 	check.Configure([]byte(``), []byte(``), "test")
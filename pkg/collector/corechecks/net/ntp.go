@@ -182,6 +182,9 @@ func (c *NTPCheck) Run() error {
 		sender.Gauge("ntp.offset", clockOffset, "", nil)
 		ntpExpVar.Set(clockOffset)
 		tlmNtpOffset.Set(clockOffset)
+
+		// the offset is added to the local time to obtain the time of the ntp servers
+		aggregator.SetClockOffset(time.Duration(clockOffset * float64(time.Second)))
 	}
 
 	sender.ServiceCheck("ntp.in_sync", serviceCheckStatus, "", nil, serviceCheckMessage)
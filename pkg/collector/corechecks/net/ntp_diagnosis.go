@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package net
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+func init() {
+	diagnosis.Register("NTP offset", diagnoseNTPOffset)
+}
+
+// diagnoseNTPOffset checks the clock drift of the host against the default NTP servers,
+// a large drift makes the intake reject or misplace the submitted points
+func diagnoseNTPOffset() error {
+	cfg := new(ntpConfig)
+	if err := cfg.parse([]byte(""), []byte(""), getLocalDefinedNTPServers); err != nil {
+		return err
+	}
+	c := &NTPCheck{cfg: cfg}
+
+	clockOffset, err := c.queryOffset()
+	if err != nil {
+		return err
+	}
+	if int(math.Abs(clockOffset)) > cfg.instance.OffsetThreshold {
+		return fmt.Errorf("offset %v is higher than offset threshold (%v secs)", clockOffset, cfg.instance.OffsetThreshold)
+	}
+	log.Infof("Clock offset is %v secs", clockOffset)
+	return nil
+}
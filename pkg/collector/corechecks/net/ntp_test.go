@@ -400,3 +400,17 @@ func TestNTPUseLocalDefinedServers(t *testing.T) {
 	assert.False(t, defaultConfig.instance.UseLocalDefinedServers)
 	assert.NotEqual(t, configUseLocalServer.instance.Hosts, defaultConfig.instance.Hosts)
 }
+
+func TestDiagnoseNTPOffset(t *testing.T) {
+	ntpQuery = testNTPQuery
+	defer func() { ntpQuery = ntp.QueryWithOptions }()
+
+	offset = 10
+	assert.NoError(t, diagnoseNTPOffset())
+
+	offset = -100
+	assert.Error(t, diagnoseNTPOffset())
+
+	ntpQuery = testNTPQueryError
+	assert.Error(t, diagnoseNTPOffset())
+}
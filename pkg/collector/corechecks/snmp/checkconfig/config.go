@@ -1,6 +1,7 @@
 package checkconfig
 
 import (
+	"encoding/hex"
 	"fmt"
 	"hash/fnv"
 	"net"
@@ -39,6 +40,7 @@ const defaultDiscoveryInterval = 3600
 const subnetTagKey = "autodiscovery_subnet"
 const deviceNamespaceTagKey = "device_namespace"
 const deviceIPTagKey = "snmp_device"
+const contextTagKey = "snmp_context"
 
 // DefaultBulkMaxRepetitions is the default max rep
 // Using too high max repetitions might lead to tooBig SNMP error messages.
@@ -59,31 +61,49 @@ type InitConfig struct {
 	BulkMaxRepetitions    Number           `yaml:"bulk_max_repetitions"`
 	CollectDeviceMetadata Boolean          `yaml:"collect_device_metadata"`
 	UseDeviceIDAsHostname Boolean          `yaml:"use_device_id_as_hostname"`
+	UseGetNextOnly        Boolean          `yaml:"use_getnext_only"`
 	MinCollectionInterval int              `yaml:"min_collection_interval"`
 	Namespace             string           `yaml:"namespace"`
 }
 
+// SNMPContextConfig is an SNMPv3 context polled in addition to the default one,
+// e.g. some devices expose their per-VRF tables behind different contexts
+type SNMPContextConfig struct {
+	Name string   `yaml:"name"`
+	Tags []string `yaml:"tags"` // added to the metrics and metadata of the context
+}
+
+// GetTags returns the tags of the series and metadata fetched from the context
+func (c SNMPContextConfig) GetTags() []string {
+	return append([]string{contextTagKey + ":" + c.Name}, c.Tags...)
+}
+
 // InstanceConfig is used to deserialize integration instance config
 type InstanceConfig struct {
-	Name                  string            `yaml:"name"`
-	IPAddress             string            `yaml:"ip_address"`
-	Port                  Number            `yaml:"port"`
-	CommunityString       string            `yaml:"community_string"`
-	SnmpVersion           string            `yaml:"snmp_version"`
-	Timeout               Number            `yaml:"timeout"`
-	Retries               Number            `yaml:"retries"`
-	User                  string            `yaml:"user"`
-	AuthProtocol          string            `yaml:"authProtocol"`
-	AuthKey               string            `yaml:"authKey"`
-	PrivProtocol          string            `yaml:"privProtocol"`
-	PrivKey               string            `yaml:"privKey"`
-	ContextName           string            `yaml:"context_name"`
-	Metrics               []MetricsConfig   `yaml:"metrics"`     // SNMP metrics definition
-	MetricTags            []MetricTagConfig `yaml:"metric_tags"` // SNMP metric tags definition
-	Profile               string            `yaml:"profile"`
-	UseGlobalMetrics      bool              `yaml:"use_global_metrics"`
-	CollectDeviceMetadata *Boolean          `yaml:"collect_device_metadata"`
-	UseDeviceIDAsHostname *Boolean          `yaml:"use_device_id_as_hostname"`
+	Name                  string              `yaml:"name"`
+	IPAddress             string              `yaml:"ip_address"`
+	Port                  Number              `yaml:"port"`
+	CommunityString       string              `yaml:"community_string"`
+	SnmpVersion           string              `yaml:"snmp_version"`
+	Timeout               Number              `yaml:"timeout"`
+	Retries               Number              `yaml:"retries"`
+	User                  string              `yaml:"user"`
+	AuthProtocol          string              `yaml:"authProtocol"`
+	AuthKey               string              `yaml:"authKey"`
+	PrivProtocol          string              `yaml:"privProtocol"`
+	PrivKey               string              `yaml:"privKey"`
+	ContextEngineID       string              `yaml:"context_engine_id"`
+	ContextName           string              `yaml:"context_name"`
+	Contexts              []SNMPContextConfig `yaml:"contexts"`
+	Metrics               []MetricsConfig     `yaml:"metrics"`     // SNMP metrics definition
+	MetricTags            []MetricTagConfig   `yaml:"metric_tags"` // SNMP metric tags definition
+	Profile               string              `yaml:"profile"`
+	UseGlobalMetrics      bool                `yaml:"use_global_metrics"`
+	CollectDeviceMetadata *Boolean            `yaml:"collect_device_metadata"`
+	UseDeviceIDAsHostname *Boolean            `yaml:"use_device_id_as_hostname"`
+	// The use_getnext_only config fetches the tables using GetNext instead of GetBulk, for the devices
+	// that mis-implement GetBulk. It is also enabled automatically when GetBulk fails but GetNext succeeds.
+	UseGetNextOnly *Boolean `yaml:"use_getnext_only"`
 
 	// ExtraTags is a workaround to pass tags from snmp listener to snmp integration via AD template
 	// (see cmd/agent/dist/conf.d/snmp.d/auto_conf.yaml) that only works with strings.
@@ -129,7 +149,9 @@ type CheckConfig struct {
 	AuthKey               string
 	PrivProtocol          string
 	PrivKey               string
+	ContextEngineID       string
 	ContextName           string
+	Contexts              []SNMPContextConfig
 	OidConfig             OidConfig
 	Metrics               []MetricsConfig
 	MetricTags            []MetricTagConfig
@@ -143,6 +165,7 @@ type CheckConfig struct {
 	InstanceTags          []string
 	CollectDeviceMetadata bool
 	UseDeviceIDAsHostname bool
+	UseGetNextOnly        bool
 	DeviceID              string
 	DeviceIDTags          []string
 	ResolvedSubnetName    string
@@ -174,6 +197,10 @@ func (c *CheckConfig) RefreshWithProfile(profile string) error {
 	c.OidConfig.addScalarOids(parseScalarOids(definition.Metrics, definition.MetricTags))
 	c.OidConfig.addColumnOids(parseColumnOids(definition.Metrics))
 
+	if definition.Device.CollectEntityMetadata {
+		c.OidConfig.addColumnOids(metadata.EntityColumnOIDs)
+	}
+
 	if definition.Device.Vendor != "" {
 		tags = append(tags, "device_vendor:"+definition.Device.Vendor)
 	}
@@ -199,9 +226,31 @@ func (c *CheckConfig) GetStaticTags() []string {
 	if c.IPAddress != "" {
 		tags = append(tags, deviceIPTagKey+":"+c.IPAddress)
 	}
+	if c.ContextName != "" {
+		tags = append(tags, c.GetContextTag())
+	}
 	return tags
 }
 
+// parseContextEngineID decodes the hex encoded `context_engine_id`, gosnmp expects the raw
+// bytes of the engine ID, which are 5 to 32 bytes long (RFC 3411)
+func parseContextEngineID(rawContextEngineID string) (string, error) {
+	contextEngineID, err := hex.DecodeString(strings.TrimPrefix(rawContextEngineID, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("`context_engine_id` must be hex encoded: %s", err)
+	}
+	if len(contextEngineID) < 5 || len(contextEngineID) > 32 {
+		return "", fmt.Errorf("`context_engine_id` must be 5 to 32 bytes long, got %d bytes", len(contextEngineID))
+	}
+	return string(contextEngineID), nil
+}
+
+// GetContextTag returns the tag of the default SNMPv3 context, the metrics of
+// the different contexts (e.g. VRFs) of a device are distinguished by this tag
+func (c *CheckConfig) GetContextTag() string {
+	return contextTagKey + ":" + c.ContextName
+}
+
 // GetNetworkTags returns network tags
 // network tags are not part of the static tags since we don't want the deviceID
 // to change if the network/subnet changes e.g. 10.0.0.0/29 to 10.0.0.0/30
@@ -294,6 +343,12 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 		c.UseDeviceIDAsHostname = bool(initConfig.UseDeviceIDAsHostname)
 	}
 
+	if instance.UseGetNextOnly != nil {
+		c.UseGetNextOnly = bool(*instance.UseGetNextOnly)
+	} else {
+		c.UseGetNextOnly = bool(initConfig.UseGetNextOnly)
+	}
+
 	if instance.ExtraTags != "" {
 		c.ExtraTags = strings.Split(instance.ExtraTags, ",")
 	}
@@ -363,8 +418,25 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 	c.AuthKey = instance.AuthKey
 	c.PrivProtocol = instance.PrivProtocol
 	c.PrivKey = instance.PrivKey
+	if instance.ContextEngineID != "" {
+		contextEngineID, err := parseContextEngineID(instance.ContextEngineID)
+		if err != nil {
+			return nil, err
+		}
+		c.ContextEngineID = contextEngineID
+	}
 	c.ContextName = instance.ContextName
 
+	if len(instance.Contexts) > 0 && c.User == "" {
+		return nil, fmt.Errorf("`contexts` are only supported by SNMPv3")
+	}
+	for _, snmpContext := range instance.Contexts {
+		if snmpContext.Name == "" {
+			return nil, fmt.Errorf("`contexts` entries must have a name")
+		}
+	}
+	c.Contexts = instance.Contexts
+
 	c.Metrics = instance.Metrics
 
 	if instance.OidBatchSize != 0 {
@@ -499,6 +571,7 @@ func (c *CheckConfig) DeviceDigest(address string) DeviceDigest {
 	h.Write([]byte(c.AuthProtocol))            //nolint:errcheck
 	h.Write([]byte(c.PrivKey))                 //nolint:errcheck
 	h.Write([]byte(c.PrivProtocol))            //nolint:errcheck
+	h.Write([]byte(c.ContextEngineID))         //nolint:errcheck
 	h.Write([]byte(c.ContextName))             //nolint:errcheck
 
 	// Sort the addresses to get a stable digest
@@ -536,8 +609,11 @@ func (c *CheckConfig) Copy() *CheckConfig {
 	newConfig.AuthKey = c.AuthKey
 	newConfig.PrivProtocol = c.PrivProtocol
 	newConfig.PrivKey = c.PrivKey
+	newConfig.ContextEngineID = c.ContextEngineID
 	newConfig.ContextName = c.ContextName
-	newConfig.ContextName = c.ContextName
+	for _, snmpContext := range c.Contexts {
+		newConfig.Contexts = append(newConfig.Contexts, SNMPContextConfig{Name: snmpContext.Name, Tags: common.CopyStrings(snmpContext.Tags)})
+	}
 	newConfig.OidConfig = c.OidConfig
 	newConfig.Metrics = make([]MetricsConfig, 0, len(c.Metrics))
 	for _, metric := range c.Metrics {
@@ -557,6 +633,7 @@ func (c *CheckConfig) Copy() *CheckConfig {
 	newConfig.InstanceTags = common.CopyStrings(c.InstanceTags)
 	newConfig.CollectDeviceMetadata = c.CollectDeviceMetadata
 	newConfig.UseDeviceIDAsHostname = c.UseDeviceIDAsHostname
+	newConfig.UseGetNextOnly = c.UseGetNextOnly
 	newConfig.DeviceID = c.DeviceID
 
 	newConfig.DeviceIDTags = common.CopyStrings(c.DeviceIDTags)
@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/valuestore"
@@ -14,6 +15,8 @@ type SymbolConfig struct {
 	OID          string `yaml:"OID"`
 	Name         string `yaml:"name"`
 	ExtractValue string `yaml:"extract_value"`
+	// Unit is the unit of the values of the symbol, e.g. byte, it's sent along with the metric
+	Unit string `yaml:"unit"`
 
 	ExtractValuePattern *regexp.Regexp
 }
@@ -30,6 +33,8 @@ type MetricTagConfig struct {
 	OID  string `yaml:"OID"`
 	Name string `yaml:"symbol"`
 
+	// IndexTransform selects sub-identifiers of the row index: used alone, the tag value is the
+	// selected sub-identifiers, used with a column, it's the index of the column row
 	IndexTransform []MetricIndexTransform `yaml:"index_transform"`
 
 	Mapping map[string]string `yaml:"mapping"`
@@ -57,6 +62,14 @@ type MetricsConfigOption struct {
 	MetricSuffix string `yaml:"metric_suffix"`
 }
 
+// MetricServiceCheckConfig holds the config of the service check a symbol is reported as, when the metric type
+// is `service_check`
+type MetricServiceCheckConfig struct {
+	// StatusMapping maps the values of the symbol to the status of the service check: ok, warning, critical or
+	// unknown. The values that aren't mapped are reported as unknown.
+	StatusMapping map[string]string `yaml:"status_mapping"`
+}
+
 // MetricsConfig holds configs for a metric
 type MetricsConfig struct {
 	// Symbol configs
@@ -71,8 +84,16 @@ type MetricsConfig struct {
 
 	MetricTags MetricTagConfigList `yaml:"metric_tags"`
 
-	ForcedType string              `yaml:"forced_type"`
+	ForcedType string `yaml:"forced_type"`
+	// MetricType is the new name of ForcedType, it's moved to ForcedType when the metrics are normalized
+	MetricType string              `yaml:"metric_type"`
 	Options    MetricsConfigOption `yaml:"options"`
+
+	// States maps the values of the symbols to the name of their state when the metric type is `state`, a 0/1
+	// gauge being reported per state
+	States map[string]string `yaml:"states"`
+
+	ServiceCheck MetricServiceCheckConfig `yaml:"service_check"`
 }
 
 // GetTags retrieve tags using the metric config and values
@@ -87,16 +108,25 @@ func (m *MetricsConfig) GetTags(fullIndex string, values *valuestore.ResultValue
 				log.Debugf("error getting tags. index `%d` not found in indexes `%v`", metricTag.Index, indexes)
 				continue
 			}
-			var tagValue string
-			if len(metricTag.Mapping) > 0 {
-				mappedValue, ok := metricTag.Mapping[indexes[index]]
-				if !ok {
-					log.Debugf("error getting tags. mapping for `%s` does not exist. mapping=`%v`, indexes=`%v`", indexes[index], metricTag.Mapping, indexes)
-					continue
-				}
-				tagValue = mappedValue
-			} else {
-				tagValue = indexes[index]
+			tagValue, ok := metricTag.getIndexTagValue(indexes[index])
+			if !ok {
+				log.Debugf("error getting tags. mapping for `%s` does not exist. mapping=`%v`, indexes=`%v`", indexes[index], metricTag.Mapping, indexes)
+				continue
+			}
+			rowTags = append(rowTags, metricTag.Tag+":"+tagValue)
+		}
+		// get tag using the sub-identifiers of composite indexes selected by `index_transform`
+		if metricTag.Index == 0 && metricTag.Column.OID == "" && len(metricTag.IndexTransform) > 0 {
+			newIndexes := transformIndex(indexes, metricTag.IndexTransform)
+			if len(newIndexes) == 0 {
+				log.Debugf("error getting tags. index transform `%v` does not match indexes `%v`", metricTag.IndexTransform, indexes)
+				continue
+			}
+			subIndex := strings.Join(newIndexes, ".")
+			tagValue, ok := metricTag.getIndexTagValue(subIndex)
+			if !ok {
+				log.Debugf("error getting tags. mapping for `%s` does not exist. mapping=`%v`, indexes=`%v`", subIndex, metricTag.Mapping, indexes)
+				continue
 			}
 			rowTags = append(rowTags, metricTag.Tag+":"+tagValue)
 		}
@@ -151,6 +181,15 @@ func (m *MetricsConfig) IsScalar() bool {
 	return m.Symbol.OID != "" && m.Symbol.Name != ""
 }
 
+// GetStatus returns the status of the service check for a value of the symbol
+func (sc *MetricServiceCheckConfig) GetStatus(value string) metrics.ServiceCheckStatus {
+	status, ok := serviceCheckStatuses[sc.StatusMapping[value]]
+	if !ok {
+		return metrics.ServiceCheckUnknown
+	}
+	return status
+}
+
 // GetTags returns tags based on MetricTagConfig and a value
 func (mtc *MetricTagConfig) GetTags(value string) []string {
 	var tags []string
@@ -176,6 +215,15 @@ func (mtc *MetricTagConfig) GetTags(value string) []string {
 	return tags
 }
 
+// getIndexTagValue returns the tag value of an index, mapped if the tag has a mapping
+func (mtc *MetricTagConfig) getIndexTagValue(index string) (string, bool) {
+	if len(mtc.Mapping) == 0 {
+		return index, true
+	}
+	value, ok := mtc.Mapping[index]
+	return value, ok
+}
+
 func regexReplaceValue(value string, pattern *regexp.Regexp, normalizedTemplate string) string {
 	result := []byte{}
 	for _, submatches := range pattern.FindAllStringSubmatchIndex(value, 1) {
@@ -210,6 +258,7 @@ func transformIndex(indexes []string, transformRules []MetricIndexTransform) []s
 // normalizeMetrics converts legacy syntax to new syntax
 // 1/ converts old symbol syntax to new symbol syntax
 //    metric.Name and metric.OID info are moved to metric.Symbol.Name and metric.Symbol.OID
+// 2/ moves metric.MetricType to metric.ForcedType, the option used when reporting the metrics
 func normalizeMetrics(metrics []MetricsConfig) {
 	for i := range metrics {
 		metric := &metrics[i]
@@ -221,5 +270,10 @@ func normalizeMetrics(metrics []MetricsConfig) {
 			metric.Name = ""
 			metric.OID = ""
 		}
+
+		if metric.MetricType != "" {
+			metric.ForcedType = metric.MetricType
+			metric.MetricType = ""
+		}
 	}
 }
@@ -10,6 +10,7 @@ import (
 
 	"gopkg.in/yaml.v2"
 
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/cihub/seelog"
 	"github.com/stretchr/testify/assert"
@@ -431,6 +432,78 @@ metric_tags:
 				{"[DEBUG] GetTags: error getting tags. index `100` not found in indexes `[1]`", 1},
 			},
 		},
+		{
+			name: "index transform of composite index",
+			// language=yaml
+			rawMetricConfig: []byte(`
+table:
+  OID:  1.3.6.1.4.1.9.9.166.1.15
+  name: cbQosCMStatsTable
+symbols:
+  - OID: 1.3.6.1.4.1.9.9.166.1.15.1.1.2
+    name: cbQosCMPrePolicyPkt
+metric_tags:
+  - index_transform:
+      - start: 0
+        end: 0
+    tag: policy_index
+  - index_transform:
+      - start: 1
+        end: 2
+    tag: object_index
+  - index: 2
+    tag: object
+`),
+			fullIndex:    "1048.2195.7",
+			values:       &valuestore.ResultValueStore{},
+			expectedTags: []string{"policy_index:1048", "object_index:2195.7", "object:2195"},
+		},
+		{
+			name: "index transform with mapping",
+			// language=yaml
+			rawMetricConfig: []byte(`
+table:
+  OID:  1.2.3.4.5
+  name: cpiPduBranchTable
+symbols:
+  - OID: 1.2.3.4.5.1.2
+    name: cpiPduBranchCurrent
+metric_tags:
+  - index_transform:
+      - start: 1
+        end: 2
+    tag: direction
+    mapping:
+      1.1: inbound
+      1.2: outbound
+`),
+			fullIndex:    "10.1.2",
+			values:       &valuestore.ResultValueStore{},
+			expectedTags: []string{"direction:outbound"},
+		},
+		{
+			name: "index transform not matching",
+			// language=yaml
+			rawMetricConfig: []byte(`
+table:
+  OID:  1.2.3.4.5
+  name: cpiPduBranchTable
+symbols:
+  - OID: 1.2.3.4.5.1.2
+    name: cpiPduBranchCurrent
+metric_tags:
+  - index_transform:
+      - start: 1
+        end: 3
+    tag: abc
+`),
+			fullIndex:    "1.2",
+			values:       &valuestore.ResultValueStore{},
+			expectedTags: []string(nil),
+			expectedLogs: []logCount{
+				{"[DEBUG] GetTags: error getting tags. index transform `[{1 3}]` does not match indexes `[1 2]`", 1},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -491,3 +564,48 @@ func Test_normalizeRegexReplaceValue(t *testing.T) {
 		})
 	}
 }
+
+func Test_normalizeMetrics_metricType(t *testing.T) {
+	profileMetrics := []byte(`
+- MIB: HOST-RESOURCES-MIB
+  symbol:
+    OID: 1.3.6.1.2.1.25.3.2.1.5.1
+    name: hrDeviceStatus
+  metric_type: state
+  states:
+    1: unknown
+    2: running
+    5: down
+- MIB: HOST-RESOURCES-MIB
+  symbol:
+    OID: 1.3.6.1.2.1.25.3.5.1.2.1
+    name: hrPrinterDetectedErrorState
+  metric_type: service_check
+  options:
+    placement: 2
+    metric_suffix: noPaper
+  service_check:
+    status_mapping:
+      0: ok
+      1: critical
+- MIB: IF-MIB
+  symbol:
+    OID: 1.3.6.1.2.1.2.2.1.14
+    name: ifInErrors
+  forced_type: monotonic_count
+`)
+	var metricsConfig []MetricsConfig
+	assert.NoError(t, yaml.Unmarshal(profileMetrics, &metricsConfig))
+	normalizeMetrics(metricsConfig)
+	assert.Empty(t, validateEnrichMetrics(metricsConfig))
+
+	assert.Equal(t, "state", metricsConfig[0].ForcedType)
+	assert.Equal(t, map[string]string{"1": "unknown", "2": "running", "5": "down"}, metricsConfig[0].States)
+
+	assert.Equal(t, "service_check", metricsConfig[1].ForcedType)
+	assert.Equal(t, metrics.ServiceCheckOK, metricsConfig[1].ServiceCheck.GetStatus("0"))
+	assert.Equal(t, metrics.ServiceCheckCritical, metricsConfig[1].ServiceCheck.GetStatus("1"))
+	assert.Equal(t, metrics.ServiceCheckUnknown, metricsConfig[1].ServiceCheck.GetStatus("2"))
+
+	assert.Equal(t, "monotonic_count", metricsConfig[2].ForcedType)
+}
@@ -33,7 +33,12 @@ authProtocol: sha
 authKey: my-authKey
 privProtocol: aes
 privKey: my-privKey
+context_engine_id: 80000009030000c1b1129980
 context_name: my-contextName
+contexts:
+  - name: vrf-blue
+    tags:
+      - vrf:blue
 metrics:
 - symbol:
     OID: 1.3.6.1.2.1.2.1
@@ -129,8 +134,11 @@ bulk_max_repetitions: 20
 	assert.Equal(t, "my-authKey", config.AuthKey)
 	assert.Equal(t, "aes", config.PrivProtocol)
 	assert.Equal(t, "my-privKey", config.PrivKey)
+	assert.Equal(t, "\x80\x00\x00\x09\x03\x00\x00\xc1\xb1\x12\x99\x80", config.ContextEngineID)
 	assert.Equal(t, "my-contextName", config.ContextName)
-	assert.Equal(t, []string{"device_namespace:default", "snmp_device:1.2.3.4"}, config.GetStaticTags())
+	assert.Equal(t, []SNMPContextConfig{{Name: "vrf-blue", Tags: []string{"vrf:blue"}}}, config.Contexts)
+	assert.Equal(t, []string{"snmp_context:vrf-blue", "vrf:blue"}, config.Contexts[0].GetTags())
+	assert.Equal(t, []string{"device_namespace:default", "snmp_device:1.2.3.4", "snmp_context:my-contextName"}, config.GetStaticTags())
 	metrics := []MetricsConfig{
 		{Symbol: SymbolConfig{OID: "1.3.6.1.2.1.2.1", Name: "ifNumber"}},
 		{Symbol: SymbolConfig{OID: "1.3.6.1.2.1.2.2", Name: "ifNumber2"}, MetricTags: MetricTagConfigList{
@@ -476,6 +484,55 @@ bulk_max_repetitions: -5
 	assert.EqualError(t, err, "bulk max repetition must be a positive integer. Invalid value: -5")
 }
 
+func TestContextEngineIDConfigurations(t *testing.T) {
+	SetConfdPathAndCleanProfiles()
+
+	tests := []struct {
+		name                    string
+		contextEngineID         string
+		expectedContextEngineID string
+		expectedError           string
+	}{
+		{
+			name:                    "hex encoded",
+			contextEngineID:         "80000009030000c1b1129980",
+			expectedContextEngineID: "\x80\x00\x00\x09\x03\x00\x00\xc1\xb1\x12\x99\x80",
+		},
+		{
+			name:                    "hex encoded with prefix",
+			contextEngineID:         "0x80000009030000c1b1129980",
+			expectedContextEngineID: "\x80\x00\x00\x09\x03\x00\x00\xc1\xb1\x12\x99\x80",
+		},
+		{
+			name:            "not hex encoded",
+			contextEngineID: "my-contextEngineID",
+			expectedError:   "`context_engine_id` must be hex encoded: encoding/hex: invalid byte: U+006D 'm'",
+		},
+		{
+			name:            "too short",
+			contextEngineID: "80000009",
+			expectedError:   "`context_engine_id` must be 5 to 32 bytes long, got 4 bytes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// language=yaml
+			rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+user: my-user
+context_engine_id: "` + tt.contextEngineID + `"
+`)
+			config, err := NewCheckConfig(rawInstanceConfig, []byte(``))
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedContextEngineID, config.ContextEngineID)
+		})
+	}
+}
+
 func TestGlobalMetricsConfigurations(t *testing.T) {
 	SetConfdPathAndCleanProfiles()
 
@@ -603,6 +660,37 @@ network_address: 10.0.0.0/24
 				"`ip_address` or `network` config must be provided",
 			},
 		},
+		{
+			name: "contexts without snmpv3",
+			// language=yaml
+			rawInstanceConfig: []byte(`
+ip_address: 1.2.3.4
+community_string: public
+contexts:
+  - name: vrf-blue
+`),
+			// language=yaml
+			rawInitConfig: []byte(``),
+			expectedErrors: []string{
+				"`contexts` are only supported by SNMPv3",
+			},
+		},
+		{
+			name: "context without name",
+			// language=yaml
+			rawInstanceConfig: []byte(`
+ip_address: 1.2.3.4
+user: my-user
+contexts:
+  - tags:
+      - vrf:blue
+`),
+			// language=yaml
+			rawInitConfig: []byte(``),
+			expectedErrors: []string{
+				"`contexts` entries must have a name",
+			},
+		},
 		{
 			name: "invalid subnet cidr",
 			// language=yaml
@@ -1148,6 +1236,40 @@ use_device_id_as_hostname: true
 	assert.Equal(t, false, config.UseDeviceIDAsHostname)
 }
 
+func Test_buildConfig_UseGetNextOnly(t *testing.T) {
+	// language=yaml
+	rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+community_string: "abc"
+`)
+	// language=yaml
+	rawInitConfig := []byte(`
+oid_batch_size: 10
+`)
+	config, err := NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, false, config.UseGetNextOnly)
+
+	// language=yaml
+	rawInitConfig = []byte(`
+oid_batch_size: 10
+use_getnext_only: true
+`)
+	config, err = NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, true, config.UseGetNextOnly)
+
+	// language=yaml
+	rawInstanceConfig = []byte(`
+ip_address: 1.2.3.4
+community_string: "abc"
+use_getnext_only: false
+`)
+	config, err = NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, false, config.UseGetNextOnly)
+}
+
 func Test_buildConfig_minCollectionInterval(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -1490,7 +1612,9 @@ func TestCheckConfig_Copy(t *testing.T) {
 		AuthKey:         "123",
 		PrivProtocol:    "des",
 		PrivKey:         "123",
+		ContextEngineID: "123",
 		ContextName:     "",
+		Contexts:        []SNMPContextConfig{{Name: "vrf-blue", Tags: []string{"vrf:blue"}}},
 		OidConfig: OidConfig{
 			ScalarOids: []string{"1.2.3"},
 			ColumnOids: []string{"1.2.3", "2.3.4"},
@@ -1520,6 +1644,7 @@ func TestCheckConfig_Copy(t *testing.T) {
 		InstanceTags:          []string{"InstanceTags:tag"},
 		CollectDeviceMetadata: true,
 		UseDeviceIDAsHostname: true,
+		UseGetNextOnly:        true,
 		DeviceID:              "123",
 		DeviceIDTags:          []string{"DeviceIDTags:tag"},
 		ResolvedSubnetName:    "1.2.3.4/28",
@@ -1540,7 +1665,9 @@ func TestCheckConfig_Copy(t *testing.T) {
 	assert.Equal(t, config.AuthKey, configCopy.AuthKey)
 	assert.Equal(t, config.PrivProtocol, configCopy.PrivProtocol)
 	assert.Equal(t, config.PrivKey, configCopy.PrivKey)
+	assert.Equal(t, config.ContextEngineID, configCopy.ContextEngineID)
 	assert.Equal(t, config.ContextName, configCopy.ContextName)
+	assertNotSameButEqualElements(t, config.Contexts, configCopy.Contexts)
 	assert.Equal(t, config.OidConfig, configCopy.OidConfig)
 
 	assertNotSameButEqualElements(t, config.Metrics, configCopy.Metrics)
@@ -1558,6 +1685,7 @@ func TestCheckConfig_Copy(t *testing.T) {
 	assertNotSameButEqualElements(t, config.InstanceTags, configCopy.InstanceTags)
 	assert.Equal(t, config.CollectDeviceMetadata, configCopy.CollectDeviceMetadata)
 	assert.Equal(t, config.UseDeviceIDAsHostname, configCopy.UseDeviceIDAsHostname)
+	assert.Equal(t, config.UseGetNextOnly, configCopy.UseGetNextOnly)
 	assert.Equal(t, config.DeviceID, configCopy.DeviceID)
 	assertNotSameButEqualElements(t, config.DeviceIDTags, configCopy.DeviceIDTags)
 	assert.Equal(t, config.ResolvedSubnetName, configCopy.ResolvedSubnetName)
@@ -3,8 +3,17 @@ package checkconfig
 import (
 	"fmt"
 	"regexp"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 )
 
+var serviceCheckStatuses = map[string]metrics.ServiceCheckStatus{
+	"ok":       metrics.ServiceCheckOK,
+	"warning":  metrics.ServiceCheckWarning,
+	"critical": metrics.ServiceCheckCritical,
+	"unknown":  metrics.ServiceCheckUnknown,
+}
+
 // ValidateEnrichMetricTags validates and enrich metric tags
 func ValidateEnrichMetricTags(metricTags []MetricTagConfig) []string {
 	var errors []string
@@ -45,6 +54,28 @@ func validateEnrichMetrics(metrics []MetricsConfig) []string {
 				errors = append(errors, validateEnrichMetricTag(metricTag, metricConfig)...)
 			}
 		}
+		errors = append(errors, validateMetricType(metricConfig)...)
+	}
+	return errors
+}
+
+func validateMetricType(metricConfig *MetricsConfig) []string {
+	var errors []string
+	switch metricConfig.ForcedType {
+	case "state":
+		if len(metricConfig.States) == 0 {
+			errors = append(errors, fmt.Sprintf("`states` mapping must be provided for the metric type `state`: %#v", metricConfig))
+		}
+	case "service_check":
+		statusMapping := metricConfig.ServiceCheck.StatusMapping
+		if len(statusMapping) == 0 {
+			errors = append(errors, fmt.Sprintf("`service_check.status_mapping` must be provided for the metric type `service_check`: %#v", metricConfig))
+		}
+		for value, status := range statusMapping {
+			if _, ok := serviceCheckStatuses[status]; !ok {
+				errors = append(errors, fmt.Sprintf("invalid service check status `%s` for value `%s`, expected ok, warning, critical or unknown: %#v", status, value, metricConfig))
+			}
+		}
 	}
 	return errors
 }
@@ -83,6 +114,9 @@ func validateEnrichMetricTag(metricTag *MetricTagConfig, metricConfig *MetricsCo
 			errors = append(errors, fmt.Sprintf("`tags` mapping must be provided if `match` (`%s`) is defined: %#v", metricTag.Match, metricConfig))
 		}
 	}
+	if metricTag.Index > 0 && metricTag.Column.OID == "" && len(metricTag.IndexTransform) > 0 {
+		errors = append(errors, fmt.Sprintf("`index` and `index_transform` cannot be both used without `column`: %#v", metricConfig))
+	}
 	for _, transform := range metricTag.IndexTransform {
 		if transform.Start > transform.End {
 			errors = append(errors, fmt.Sprintf("transform rule end should be greater than start. Invalid rule: %#v", transform))
@@ -269,6 +269,34 @@ func Test_validateEnrichMetrics(t *testing.T) {
 				"transform rule end should be greater than start. Invalid rule",
 			},
 		},
+		{
+			name: "index and index transform without column",
+			metrics: []MetricsConfig{
+				{
+					Symbols: []SymbolConfig{
+						{
+							OID:  "1.2",
+							Name: "abc",
+						},
+					},
+					MetricTags: MetricTagConfigList{
+						MetricTagConfig{
+							Index: 1,
+							Tag:   "hello",
+							IndexTransform: []MetricIndexTransform{
+								{
+									Start: 0,
+									End:   1,
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedErrors: []string{
+				"`index` and `index_transform` cannot be both used without `column`",
+			},
+		},
 		{
 			name: "compiling extract_value",
 			metrics: []MetricsConfig{
@@ -347,6 +375,54 @@ func Test_validateEnrichMetrics(t *testing.T) {
 				"cannot compile `extract_value`",
 			},
 		},
+		{
+			name: "states must be provided for metric type state",
+			metrics: []MetricsConfig{
+				{
+					Symbol: SymbolConfig{
+						OID:  "1.3.6.1.2.1.25.3.2.1.5.1",
+						Name: "hrDeviceStatus",
+					},
+					ForcedType: "state",
+				},
+			},
+			expectedErrors: []string{
+				"`states` mapping must be provided for the metric type `state`",
+			},
+		},
+		{
+			name: "invalid service check status",
+			metrics: []MetricsConfig{
+				{
+					Symbol: SymbolConfig{
+						OID:  "1.3.6.1.2.1.25.3.2.1.5.1",
+						Name: "hrDeviceStatus",
+					},
+					ForcedType: "service_check",
+					ServiceCheck: MetricServiceCheckConfig{
+						StatusMapping: map[string]string{"2": "ok", "5": "down"},
+					},
+				},
+			},
+			expectedErrors: []string{
+				"invalid service check status `down` for value `5`",
+			},
+		},
+		{
+			name: "service check status mapping must be provided",
+			metrics: []MetricsConfig{
+				{
+					Symbol: SymbolConfig{
+						OID:  "1.3.6.1.2.1.25.3.2.1.5.1",
+						Name: "hrDeviceStatus",
+					},
+					ForcedType: "service_check",
+				},
+			},
+			expectedErrors: []string{
+				"`service_check.status_mapping` must be provided for the metric type `service_check`",
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
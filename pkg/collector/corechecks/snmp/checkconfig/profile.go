@@ -20,6 +20,9 @@ type profileDefinitionMap map[string]profileDefinition
 
 type deviceMeta struct {
 	Vendor string `yaml:"vendor"`
+	// CollectEntityMetadata enables the collection of the serial number, model
+	// and firmware revision of the chassis from the ENTITY-MIB
+	CollectEntityMetadata bool `yaml:"collect_entity_metadata"`
 }
 
 type profileDefinition struct {
@@ -154,6 +157,9 @@ func recursivelyExpandBaseProfiles(definition *profileDefinition, extends []stri
 		}
 		definition.Metrics = append(definition.Metrics, baseDefinition.Metrics...)
 		definition.MetricTags = append(definition.MetricTags, baseDefinition.MetricTags...)
+		if baseDefinition.Device.CollectEntityMetadata {
+			definition.Device.CollectEntityMetadata = true
+		}
 
 		newExtendsHistory := append(common.CopyStrings(extendsHistory), basePath)
 		err = recursivelyExpandBaseProfiles(definition, baseDefinition.Extends, newExtendsHistory)
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/cihub/seelog"
+	"github.com/gosnmp/gosnmp"
 
 	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devices"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
@@ -82,15 +84,24 @@ func (d *DeviceCheck) Run(collectionTime time.Time) error {
 	// Fetch and report metrics
 	var checkErr error
 	var deviceStatus metadata.DeviceStatus
-	deviceReachable, tags, values, checkErr := d.getValuesAndTags(staticTags)
+	deviceReachable, tags, values, contextStores, checkErr := d.getValuesAndTags(staticTags)
+	d.registerDevice()
+	serviceCheckData := metrics.ServiceCheckData{
+		"device_id":  d.config.DeviceID,
+		"ip_address": d.config.IPAddress,
+	}
 	if checkErr != nil {
-		d.sender.ServiceCheck(serviceCheckName, metrics.ServiceCheckCritical, tags, checkErr.Error())
+		serviceCheckData["reachable"] = deviceReachable
+		d.sender.ServiceCheckWithData(serviceCheckName, metrics.ServiceCheckCritical, tags, checkErr.Error(), serviceCheckData)
 	} else {
-		d.sender.ServiceCheck(serviceCheckName, metrics.ServiceCheckOK, tags, "")
+		d.sender.ServiceCheckWithData(serviceCheckName, metrics.ServiceCheckOK, tags, "", serviceCheckData)
 	}
 	if values != nil {
 		d.sender.ReportMetrics(d.config.Metrics, values, tags)
 	}
+	for _, contextStore := range contextStores {
+		d.sender.ReportMetrics(d.config.Metrics, contextStore.Store, append(d.withoutDefaultContextTag(tags), contextStore.Tags...))
+	}
 
 	if d.config.CollectDeviceMetadata {
 		if deviceReachable {
@@ -104,14 +115,14 @@ func (d *DeviceCheck) Run(collectionTime time.Time) error {
 		// Note that we don't add some extra tags like `service` tag that might be present in `checkSender.checkTags`.
 		deviceMetadataTags := append(common.CopyStrings(tags), d.config.InstanceTags...)
 
-		d.sender.ReportNetworkDeviceMetadata(d.config, values, deviceMetadataTags, collectionTime, deviceStatus)
+		d.sender.ReportNetworkDeviceMetadata(d.config, values, contextStores, deviceMetadataTags, collectionTime, deviceStatus)
 	}
 
 	d.submitTelemetryMetrics(startTime, tags)
 	return checkErr
 }
 
-func (d *DeviceCheck) getValuesAndTags(staticTags []string) (bool, []string, *valuestore.ResultValueStore, error) {
+func (d *DeviceCheck) getValuesAndTags(staticTags []string) (bool, []string, *valuestore.ResultValueStore, []report.ContextValueStore, error) {
 	var deviceReachable bool
 	var checkErrors []string
 	tags := common.CopyStrings(staticTags)
@@ -119,7 +130,7 @@ func (d *DeviceCheck) getValuesAndTags(staticTags []string) (bool, []string, *va
 	// Create connection
 	connErr := d.session.Connect()
 	if connErr != nil {
-		return false, tags, nil, fmt.Errorf("snmp connection error: %s", connErr)
+		return false, tags, nil, nil, fmt.Errorf("snmp connection error: %s", connErr)
 	}
 	defer func() {
 		err := d.session.Close()
@@ -156,13 +167,72 @@ func (d *DeviceCheck) getValuesAndTags(staticTags []string) (bool, []string, *va
 		checkErrors = append(checkErrors, fmt.Sprintf("failed to fetch values: %s", err))
 	} else {
 		tags = append(tags, d.sender.GetCheckInstanceMetricTags(d.config.MetricTags, valuesStore)...)
+		tags = append(tags, report.GetDeviceEntityTags(d.config, valuesStore)...)
 	}
 
+	contextStores, contextErrors := d.fetchContexts()
+	checkErrors = append(checkErrors, contextErrors...)
+
 	var joinedError error
 	if len(checkErrors) > 0 {
 		joinedError = errors.New(strings.Join(checkErrors, "; "))
 	}
-	return deviceReachable, tags, valuesStore, joinedError
+	return deviceReachable, tags, valuesStore, contextStores, joinedError
+}
+
+// fetchContexts fetches the values of the additional SNMP contexts of the device
+func (d *DeviceCheck) fetchContexts() ([]report.ContextValueStore, []string) {
+	if len(d.config.Contexts) == 0 {
+		return nil, nil
+	}
+	// restore the default context for the next run
+	defer d.session.SetContextName(d.config.ContextName)
+
+	var contextStores []report.ContextValueStore
+	var checkErrors []string
+	for _, snmpContext := range d.config.Contexts {
+		d.session.SetContextName(snmpContext.Name)
+		valuesStore, err := fetch.Fetch(d.session, d.config)
+		if err != nil {
+			checkErrors = append(checkErrors, fmt.Sprintf("failed to fetch values of context `%s`: %s", snmpContext.Name, err))
+			continue
+		}
+		if log.ShouldLog(seelog.DebugLvl) {
+			log.Debugf("fetched values of context `%s`: %v", snmpContext.Name, valuestore.ResultValueStoreAsString(valuesStore))
+		}
+		contextStores = append(contextStores, report.ContextValueStore{
+			Tags:  snmpContext.GetTags(),
+			Store: valuesStore,
+		})
+	}
+	return contextStores, checkErrors
+}
+
+// withoutDefaultContextTag returns a copy of the tags without the tag of the
+// default context, to tag the values of the additional contexts with their own
+func (d *DeviceCheck) withoutDefaultContextTag(tags []string) []string {
+	if d.config.ContextName == "" {
+		return common.CopyStrings(tags)
+	}
+	contextTag := d.config.GetContextTag()
+	newTags := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag != contextTag {
+			newTags = append(newTags, tag)
+		}
+	}
+	return newTags
+}
+
+// registerDevice makes the device known to the traps listener, so that the traps
+// it sends are tagged like its metrics
+func (d *DeviceCheck) registerDevice() {
+	devices.Register(d.config.IPAddress, devices.Device{
+		Namespace: d.config.Namespace,
+		DeviceID:  d.config.DeviceID,
+		Profile:   d.config.Profile,
+		Tags:      append(d.config.GetStaticTags(), d.config.ProfileTags...),
+	})
 }
 
 func (d *DeviceCheck) doAutodetectProfile(sess session.Session) error {
@@ -172,17 +242,27 @@ func (d *DeviceCheck) doAutodetectProfile(sess session.Session) error {
 		if err != nil {
 			return fmt.Errorf("failed to fetch sysobjectid: %s", err)
 		}
-		d.config.AutodetectProfile = false // do not try to auto detect profile next time
+		return d.DetectProfile(sysObjectID)
+	}
+	return nil
+}
 
-		profile, err := checkconfig.GetProfileForSysObjectID(d.config.Profiles, sysObjectID)
-		if err != nil {
-			return fmt.Errorf("failed to get profile sys object id for `%s`: %s", sysObjectID, err)
-		}
-		err = d.config.RefreshWithProfile(profile)
-		if err != nil {
-			// Should not happen since the profile is one of those we matched in GetProfileForSysObjectID
-			return fmt.Errorf("failed to refresh with profile `%s` detected using sysObjectID `%s`: %s", profile, sysObjectID, err)
-		}
+// DetectProfile selects the profile of the device matching its sysObjectID when
+// the profile is autodetected, e.g. with the sysObjectID known by the discovery
+func (d *DeviceCheck) DetectProfile(sysObjectID string) error {
+	if !d.config.AutodetectProfile {
+		return nil
+	}
+	d.config.AutodetectProfile = false // do not try to auto detect profile next time
+
+	profile, err := checkconfig.GetProfileForSysObjectID(d.config.Profiles, sysObjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get profile sys object id for `%s`: %s", sysObjectID, err)
+	}
+	err = d.config.RefreshWithProfile(profile)
+	if err != nil {
+		// Should not happen since the profile is one of those we matched in GetProfileForSysObjectID
+		return fmt.Errorf("failed to refresh with profile `%s` detected using sysObjectID `%s`: %s", profile, sysObjectID, err)
 	}
 	return nil
 }
@@ -196,4 +276,12 @@ func (d *DeviceCheck) submitTelemetryMetrics(startTime time.Time, tags []string)
 	d.sender.MonotonicCount("datadog.snmp.check_interval", time.Duration(startTime.UnixNano()).Seconds(), newTags)
 	d.sender.Gauge("datadog.snmp.check_duration", time.Since(startTime).Seconds(), newTags)
 	d.sender.Gauge("datadog.snmp.submitted_metrics", float64(d.sender.GetSubmittedMetrics()), newTags)
+
+	// The SNMP operation used to fetch the tables, GetNext is used when configured with `use_getnext_only`
+	// or when the device failed to answer GetBulk
+	fetchMode := "getbulk"
+	if d.config.UseGetNextOnly || d.session.GetVersion() == gosnmp.Version1 {
+		fetchMode = "getnext"
+	}
+	d.sender.Gauge("datadog.snmp.column_fetch_mode", float64(1), append(common.CopyStrings(newTags), "fetch_mode:"+fetchMode))
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devices"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/common"
@@ -46,7 +47,7 @@ profiles:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	deviceCk.SetSender(report.NewMetricSender(sender, ""))
@@ -258,6 +259,15 @@ profiles:
 
 	assert.Equal(t, false, deviceCk.config.AutodetectProfile)
 
+	// the device is registered with the detected profile for the traps listener
+	device, found := devices.Lookup("1.2.3.4")
+	assert.True(t, found)
+	assert.Equal(t, "default:1.2.3.4", device.DeviceID)
+	assert.Equal(t, "default", device.Namespace)
+	assert.Equal(t, "f5-big-ip", device.Profile)
+	assert.ElementsMatch(t, []string{"device_namespace:default", "snmp_device:1.2.3.4", "snmp_profile:f5-big-ip", "device_vendor:f5"}, device.Tags)
+	devices.Unregister("1.2.3.4", device.DeviceID)
+
 	// Make sure we don't auto detect and add metrics twice if we already did that previously
 	firstRunMetrics := deviceCk.config.Metrics
 	firstRunMetricsTags := deviceCk.config.MetricTags
@@ -268,6 +278,38 @@ profiles:
 	assert.Len(t, deviceCk.config.MetricTags, len(firstRunMetricsTags))
 }
 
+func TestDeviceCheck_DetectProfile(t *testing.T) {
+	checkconfig.SetConfdPathAndCleanProfiles()
+
+	// language=yaml
+	rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+community_string: public
+`)
+	// language=yaml
+	rawInitConfig := []byte(`
+profiles:
+ f5-big-ip:
+   definition_file: f5-big-ip.yaml
+`)
+
+	config, err := checkconfig.NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+
+	deviceCk, err := NewDeviceCheck(config, "1.2.3.4")
+	assert.Nil(t, err)
+
+	err = deviceCk.DetectProfile("1.3.6.1.4.1.3375.2.1.3.4.1")
+	assert.Nil(t, err)
+	assert.Equal(t, false, deviceCk.config.AutodetectProfile)
+	assert.Equal(t, "f5-big-ip", deviceCk.config.Profile)
+
+	// the profile is only detected once
+	err = deviceCk.DetectProfile("1.2.3")
+	assert.Nil(t, err)
+	assert.Equal(t, "f5-big-ip", deviceCk.config.Profile)
+}
+
 func TestDeviceCheck_GetHostname(t *testing.T) {
 	checkconfig.SetConfdPathAndCleanProfiles()
 	// language=yaml
@@ -299,3 +341,72 @@ community_string: public
 	deviceCk.sender.Gauge("snmp.devices_monitored", float64(1), []string{"snmp_device:1.2.3.4"})
 	sender.AssertMetric(t, "Gauge", "snmp.devices_monitored", float64(1), "device:123", []string{"snmp_device:1.2.3.4"})
 }
+
+func TestDeviceCheck_Contexts(t *testing.T) {
+	checkconfig.SetConfdPathAndCleanProfiles()
+	sess := session.CreateMockSession()
+	session.NewSession = func(*checkconfig.CheckConfig) (session.Session, error) {
+		return sess, nil
+	}
+
+	// language=yaml
+	rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+user: my-user
+authProtocol: sha
+authKey: my-auth-key
+context_name: default-context
+collect_device_metadata: false
+contexts:
+  - name: vrf-blue
+    tags:
+      - vrf:blue
+metrics:
+- symbol:
+    OID: 1.3.6.1.2.1.1.3.0
+    name: sysUpTimeInstance
+`)
+
+	config, err := checkconfig.NewCheckConfig(rawInstanceConfig, []byte(``))
+	assert.Nil(t, err)
+
+	deviceCk, err := NewDeviceCheck(config, "1.2.3.4")
+	assert.Nil(t, err)
+
+	sender := mocksender.NewMockSender("123") // required to initiate aggregator
+	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	deviceCk.SetSender(report.NewMetricSender(sender, ""))
+
+	packet := gosnmp.SnmpPacket{
+		Variables: []gosnmp.SnmpPDU{
+			{
+				Name:  "1.3.6.1.2.1.1.3.0",
+				Type:  gosnmp.TimeTicks,
+				Value: 20,
+			},
+		},
+	}
+	sess.ContextName = "default-context"
+	var fetchedContexts []string
+	sess.On("GetNext", []string{"1.3"}).Return(&gosnmplib.MockValidReachableGetNextPacket, nil)
+	sess.On("Get", []string{"1.3.6.1.2.1.1.3.0"}).Return(&packet, nil).Run(func(mock.Arguments) {
+		fetchedContexts = append(fetchedContexts, sess.ContextName)
+	})
+
+	err = deviceCk.Run(time.Now())
+	assert.Nil(t, err)
+
+	snmpTags := []string{"snmp_device:1.2.3.4", "snmp_context:default-context"}
+	contextTags := []string{"snmp_device:1.2.3.4", "snmp_context:vrf-blue", "vrf:blue"}
+	sender.AssertMetric(t, "Gauge", "snmp.sysUpTimeInstance", float64(20), "", snmpTags)
+	sender.AssertMetric(t, "Gauge", "snmp.sysUpTimeInstance", float64(20), "", contextTags)
+	// the values of the additional contexts are not tagged with the default context
+	sender.AssertNotCalled(t, "Gauge", "snmp.sysUpTimeInstance", float64(20), "", mocksender.MatchTagsContains([]string{"snmp_context:default-context", "snmp_context:vrf-blue"}))
+	sender.AssertNumberOfCalls(t, "Gauge", 6) // 2 sysUpTimeInstance + 4 telemetry metrics
+
+	assert.Equal(t, []string{"default-context", "vrf-blue"}, fetchedContexts)
+	// the default context is restored for the next run
+	assert.Equal(t, "default-context", sess.ContextName)
+}
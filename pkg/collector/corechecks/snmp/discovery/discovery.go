@@ -7,11 +7,15 @@ import (
 	"sync"
 	"time"
 
+	"github.com/gosnmp/gosnmp"
+
 	"github.com/DataDog/datadog-agent/pkg/persistentcache"
+	"github.com/DataDog/datadog-agent/pkg/snmp/devicelease"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/devicecheck"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/gosnmplib"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/session"
 )
 
@@ -28,6 +32,15 @@ type Discovery struct {
 	// discoveredDevices contains devices with device deviceDigest as map key
 	// see also CheckConfig.DeviceDigest()
 	discoveredDevices map[checkconfig.DeviceDigest]Device
+
+	// leaser leases the discovered devices from the cluster agent, see devicelease.Leaser
+	leaser deviceLeaser
+}
+
+// deviceLeaser makes sure that devices discovered by several agents are polled by only one of them
+type deviceLeaser interface {
+	Acquire(deviceIDs ...string) []string
+	Release(deviceIDs ...string)
 }
 
 // Device implements and store results from the Service interface for the SNMP listener
@@ -36,6 +49,14 @@ type Device struct {
 	deviceIP     string
 	deviceCheck  *devicecheck.DeviceCheck
 }
+
+// cachedDevice is a discovered device persisted in the cache, to poll it right
+// after an agent restart without waiting for the scan of the subnet
+type cachedDevice struct {
+	IP          string `json:"ip"`
+	SysObjectID string `json:"sys_object_id,omitempty"`
+}
+
 type snmpSubnet struct {
 	config     *checkconfig.CheckConfig
 	startingIP net.IP
@@ -43,9 +64,9 @@ type snmpSubnet struct {
 
 	cacheKey string
 
-	// discoveredDevices contains devices ip with device deviceDigest as map key
+	// devices contains the cached devices with device deviceDigest as map key
 	// see also CheckConfig.DeviceDigest()
-	devices map[checkconfig.DeviceDigest]string
+	devices map[checkconfig.DeviceDigest]cachedDevice
 
 	// discoveredDevices contains device failures count with device deviceDigest as map key
 	// see also CheckConfig.DeviceDigest()
@@ -57,16 +78,48 @@ type checkDeviceJob struct {
 	currentIP net.IP
 }
 
-// Start discovery
+// Start discovery, the devices of the cache are created before returning,
+// their leases are acquired and they are re-verified in the background
 func (d *Discovery) Start() {
 	log.Debugf("subnet %s: Start discovery", d.config.Network)
-	go d.discoverDevices()
+	ipAddr, ipNet, err := net.ParseCIDR(d.config.Network)
+	if err != nil {
+		log.Errorf("subnet %s: Couldn't parse SNMP network: %s", d.config.Network, err)
+		return
+	}
+
+	configHash := d.config.DeviceDigest(d.config.Network)
+	cacheKey := fmt.Sprintf("%s:%s", cacheKeyPrefix, configHash)
+
+	subnet := &snmpSubnet{
+		config:     d.config,
+		startingIP: ipAddr.Mask(ipNet.Mask),
+		network:    *ipNet,
+		cacheKey:   cacheKey,
+
+		// Since subnet devices fields (`devices` and `deviceFailures`) are changed at the same time
+		// as Discovery.discoveredDevices, we rely on Discovery.discDevMu mutex to protect against concurrent changes.
+		devices:        map[checkconfig.DeviceDigest]cachedDevice{},
+		deviceFailures: map[checkconfig.DeviceDigest]int{},
+	}
+
+	d.loadCache(subnet)
+
+	go d.discoverDevices(subnet)
 }
 
 // Stop signal discovery to shut down
 func (d *Discovery) Stop() {
 	log.Debugf("subnet %s: Stop discovery", d.config.Network)
 	close(d.stop)
+
+	d.discDevMu.RLock()
+	deviceIDs := make([]string, 0, len(d.discoveredDevices))
+	for _, device := range d.discoveredDevices {
+		deviceIDs = append(deviceIDs, d.deviceID(device.deviceIP))
+	}
+	d.discDevMu.RUnlock()
+	go d.leaser.Release(deviceIDs...)
 }
 
 // GetDiscoveredDeviceConfigs returns discovered device configs
@@ -99,32 +152,7 @@ func (d *Discovery) runWorker(w int, jobs <-chan checkDeviceJob) {
 	}
 }
 
-func (d *Discovery) discoverDevices() {
-	ipAddr, ipNet, err := net.ParseCIDR(d.config.Network)
-	if err != nil {
-		log.Errorf("subnet %s: Couldn't parse SNMP network: %s", d.config.Network, err)
-		return
-	}
-
-	startingIP := ipAddr.Mask(ipNet.Mask)
-
-	configHash := d.config.DeviceDigest(d.config.Network)
-	cacheKey := fmt.Sprintf("%s:%s", cacheKeyPrefix, configHash)
-
-	subnet := snmpSubnet{
-		config:     d.config,
-		startingIP: startingIP,
-		network:    *ipNet,
-		cacheKey:   cacheKey,
-
-		// Since subnet devices fields (`devices` and `deviceFailures`) are changed at the same time
-		// as Discovery.discoveredDevices, we rely on Discovery.discDevMu mutex to protect against concurrent changes.
-		devices:        map[checkconfig.DeviceDigest]string{},
-		deviceFailures: map[checkconfig.DeviceDigest]int{},
-	}
-
-	d.loadCache(&subnet)
-
+func (d *Discovery) discoverDevices(subnet *snmpSubnet) {
 	jobs := make(chan checkDeviceJob)
 	for w := 0; w < d.config.DiscoveryWorkers; w++ {
 		go d.runWorker(w, jobs)
@@ -132,6 +160,24 @@ func (d *Discovery) discoverDevices() {
 
 	discoveryTicker := time.NewTicker(time.Duration(d.config.DiscoveryInterval) * time.Second)
 
+	// The devices loaded from the cache are already polled, they are only
+	// re-verified and the subnet is scanned at the next discovery interval
+	if len(d.cachedDeviceIPs(subnet)) > 0 {
+		d.acquireCachedDevices(subnet)
+		for ip := range d.cachedDeviceIPs(subnet) {
+			if !d.scheduleDevice(jobs, subnet, net.ParseIP(ip)) {
+				return
+			}
+		}
+
+		select {
+		case <-d.stop:
+			log.Debugf("subnet %s: Stop scheduling devices", d.config.Network)
+			return
+		case <-discoveryTicker.C:
+		}
+	}
+
 	for {
 		log.Debugf("subnet %s: Run discovery", d.config.Network)
 		startingIP := make(net.IP, len(subnet.startingIP))
@@ -142,22 +188,10 @@ func (d *Discovery) discoverDevices() {
 				continue
 			}
 
-			jobIP := make(net.IP, len(currentIP))
-			copy(jobIP, currentIP)
-			job := checkDeviceJob{
-				subnet:    &subnet,
-				currentIP: jobIP,
-			}
-			jobs <- job
-
-			select {
-			case <-d.stop:
-				log.Debugf("subnet %s: Stop scheduling devices", d.config.Network)
+			if !d.scheduleDevice(jobs, subnet, currentIP) {
 				return
-			default:
 			}
 		}
-
 		select {
 		case <-d.stop:
 			log.Debugf("subnet %s: Stop scheduling devices", d.config.Network)
@@ -167,11 +201,67 @@ func (d *Discovery) discoverDevices() {
 	}
 }
 
+// scheduleDevice sends the check of an IP to the workers, it returns false when
+// the discovery is stopped
+func (d *Discovery) scheduleDevice(jobs chan<- checkDeviceJob, subnet *snmpSubnet, ip net.IP) bool {
+	jobIP := make(net.IP, len(ip))
+	copy(jobIP, ip)
+	job := checkDeviceJob{
+		subnet:    subnet,
+		currentIP: jobIP,
+	}
+
+	select {
+	case jobs <- job:
+		return true
+	case <-d.stop:
+		log.Debugf("subnet %s: Stop scheduling devices", d.config.Network)
+		return false
+	}
+}
+
+// acquireCachedDevices leases the devices loaded from the cache, the devices
+// polled by another agent are removed
+func (d *Discovery) acquireCachedDevices(subnet *snmpSubnet) {
+	d.discDevMu.RLock()
+	deviceIDs := make([]string, 0, len(subnet.devices))
+	for _, device := range subnet.devices {
+		deviceIDs = append(deviceIDs, d.deviceID(device.IP))
+	}
+	d.discDevMu.RUnlock()
+
+	granted := make(map[string]bool, len(deviceIDs))
+	for _, deviceID := range d.leaser.Acquire(deviceIDs...) {
+		granted[deviceID] = true
+	}
+
+	for ip := range d.cachedDeviceIPs(subnet) {
+		if !granted[d.deviceID(ip)] {
+			log.Debugf("subnet %s: SNMP device %s is polled by another agent", d.config.Network, ip)
+			d.removeDevice(subnet.config.DeviceDigest(ip), subnet)
+		}
+	}
+}
+
+// cachedDeviceIPs returns the IPs of the devices of the subnet loaded from the cache
+func (d *Discovery) cachedDeviceIPs(subnet *snmpSubnet) map[string]struct{} {
+	d.discDevMu.RLock()
+	defer d.discDevMu.RUnlock()
+
+	ips := make(map[string]struct{}, len(subnet.devices))
+	for _, device := range subnet.devices {
+		ips[device.IP] = struct{}{}
+	}
+	return ips
+}
+
 func (d *Discovery) checkDevice(job checkDeviceJob) error {
 	deviceIP := job.currentIP.String()
 	config := *job.subnet.config // shallow copy
 	config.IPAddress = deviceIP
-	sess, err := session.NewSession(&config)
+	// the probes use a dedicated session, the session pool is only meant to share
+	// the connections of the check instances polling the same device
+	sess, err := session.NewGosnmpSession(&config)
 	if err != nil {
 		return fmt.Errorf("error configure session for ip %s: %v", deviceIP, err)
 	}
@@ -183,7 +273,7 @@ func (d *Discovery) checkDevice(job checkDeviceJob) error {
 		defer sess.Close()
 
 		oids := []string{sysObjectIDOid}
-		// Since `params<GoSNMP>.ContextEngineID` is empty
+		// When `params<GoSNMP>.ContextEngineID` is empty
 		// `params.Get` might lead to multiple SNMP GET calls when using SNMP v3
 		// a first call might be needed to retrieve the engineID and then the call to get the oid values.
 		value, err := sess.Get(oids)
@@ -193,22 +283,53 @@ func (d *Discovery) checkDevice(job checkDeviceJob) error {
 		} else if len(value.Variables) < 1 || value.Variables[0].Value == nil {
 			log.Debugf("subnet %s: SNMP get to %s no data", d.config.Network, deviceIP)
 			d.deleteDevice(deviceDigest, job.subnet)
+		} else if len(d.leaser.Acquire(d.deviceID(deviceIP))) == 0 {
+			log.Debugf("subnet %s: SNMP device %s is polled by another agent", d.config.Network, deviceIP)
+			d.removeDevice(deviceDigest, job.subnet)
 		} else {
 			log.Debugf("subnet %s: SNMP get to %s success: %v", d.config.Network, deviceIP, value.Variables[0].Value)
-			d.createDevice(deviceDigest, job.subnet, deviceIP, true)
+			d.createDevice(deviceDigest, job.subnet, cachedDevice{IP: deviceIP, SysObjectID: sysObjectIDFromPDU(value.Variables[0])}, true)
 		}
 	}
 	return nil
 }
 
-func (d *Discovery) createDevice(deviceDigest checkconfig.DeviceDigest, subnet *snmpSubnet, deviceIP string, writeCache bool) {
-	deviceCk, err := devicecheck.NewDeviceCheck(subnet.config, deviceIP)
+// sysObjectIDFromPDU returns the sysObjectID of a device, or an empty string if
+// it can't be read
+func sysObjectIDFromPDU(pduVar gosnmp.SnmpPDU) string {
+	_, value, err := gosnmplib.GetValueFromPDU(pduVar)
+	if err != nil {
+		return ""
+	}
+	sysObjectID, err := value.ToString()
+	if err != nil {
+		return ""
+	}
+	return sysObjectID
+}
+
+func (d *Discovery) createDevice(deviceDigest checkconfig.DeviceDigest, subnet *snmpSubnet, cached cachedDevice, writeCache bool) {
+	d.discDevMu.RLock()
+	device, present := d.discoveredDevices[deviceDigest]
+	d.discDevMu.RUnlock()
+	if present {
+		d.updateCachedDevice(deviceDigest, subnet, cached)
+		return
+	}
+
+	deviceCk, err := devicecheck.NewDeviceCheck(subnet.config, cached.IP)
 	if err != nil {
 		// should not happen since the deviceCheck is expected to be valid at this point
 		// and are only changing the device ip
-		log.Warnf("subnet %s: failed to create new device check `%s`: %s", d.config.Network, deviceIP, err)
+		log.Warnf("subnet %s: failed to create new device check `%s`: %s", d.config.Network, cached.IP, err)
 		return
 	}
+	if cached.SysObjectID != "" {
+		// the profile is known before the first run of the check
+		if err := deviceCk.DetectProfile(cached.SysObjectID); err != nil {
+			log.Debugf("subnet %s: failed to detect the profile of device `%s`: %s", d.config.Network, cached.IP, err)
+		}
+	}
 
 	d.discDevMu.Lock()
 	defer d.discDevMu.Unlock()
@@ -216,13 +337,13 @@ func (d *Discovery) createDevice(deviceDigest checkconfig.DeviceDigest, subnet *
 	if _, present := d.discoveredDevices[deviceDigest]; present {
 		return
 	}
-	device := Device{
+	device = Device{
 		deviceDigest: deviceDigest,
-		deviceIP:     deviceIP,
+		deviceIP:     cached.IP,
 		deviceCheck:  deviceCk,
 	}
 	d.discoveredDevices[deviceDigest] = device
-	subnet.devices[deviceDigest] = deviceIP
+	subnet.devices[deviceDigest] = cached
 	subnet.deviceFailures[deviceDigest] = 0
 
 	if writeCache {
@@ -230,6 +351,20 @@ func (d *Discovery) createDevice(deviceDigest checkconfig.DeviceDigest, subnet *
 	}
 }
 
+// updateCachedDevice updates the cache when the sysObjectID of a discovered
+// device changes, e.g. for devices loaded from a cache without sysObjectIDs
+func (d *Discovery) updateCachedDevice(deviceDigest checkconfig.DeviceDigest, subnet *snmpSubnet, cached cachedDevice) {
+	d.discDevMu.Lock()
+	defer d.discDevMu.Unlock()
+
+	current, present := subnet.devices[deviceDigest]
+	if !present || cached.SysObjectID == "" || current.SysObjectID == cached.SysObjectID {
+		return
+	}
+	subnet.devices[deviceDigest] = cached
+	d.writeCache(subnet)
+}
+
 // deleteDevice removes a device from discovered devices list and cache
 // if the allowed device failures count is reached
 func (d *Discovery) deleteDevice(deviceDigest checkconfig.DeviceDigest, subnet *snmpSubnet) {
@@ -246,26 +381,55 @@ func (d *Discovery) deleteDevice(deviceDigest checkconfig.DeviceDigest, subnet *
 		}
 
 		if d.config.DiscoveryAllowedFailures != -1 && failure >= d.config.DiscoveryAllowedFailures {
-			delete(d.discoveredDevices, deviceDigest)
-			delete(subnet.devices, deviceDigest)
-			delete(subnet.deviceFailures, deviceDigest)
-			d.writeCache(subnet)
+			d.leaser.Release(d.deviceID(d.discoveredDevices[deviceDigest].deviceIP))
+			d.removeDeviceLocked(deviceDigest, subnet)
 		}
 	}
 }
 
-func (d *Discovery) readCache(subnet *snmpSubnet) ([]net.IP, error) {
+// removeDevice removes a device leased to another agent from discovered devices list and cache
+func (d *Discovery) removeDevice(deviceDigest checkconfig.DeviceDigest, subnet *snmpSubnet) {
+	d.discDevMu.Lock()
+	defer d.discDevMu.Unlock()
+	if _, present := d.discoveredDevices[deviceDigest]; present {
+		d.removeDeviceLocked(deviceDigest, subnet)
+	}
+}
+
+func (d *Discovery) removeDeviceLocked(deviceDigest checkconfig.DeviceDigest, subnet *snmpSubnet) {
+	delete(d.discoveredDevices, deviceDigest)
+	delete(subnet.devices, deviceDigest)
+	delete(subnet.deviceFailures, deviceDigest)
+	d.writeCache(subnet)
+}
+
+// deviceID returns the ID of a device, used as lease key
+func (d *Discovery) deviceID(deviceIP string) string {
+	return d.config.Namespace + ":" + deviceIP
+}
+
+func (d *Discovery) readCache(subnet *snmpSubnet) ([]cachedDevice, error) {
 	cacheValue, err := persistentcache.Read(subnet.cacheKey)
 	if err != nil {
 		return nil, fmt.Errorf("couldn't read cache for %s: %s", subnet.cacheKey, err)
 	}
 	if cacheValue == "" {
-		return []net.IP{}, nil
+		return []cachedDevice{}, nil
 	}
-	var devices []net.IP
-	if err = json.Unmarshal([]byte(cacheValue), &devices); err != nil {
+	var devices []cachedDevice
+	if err = json.Unmarshal([]byte(cacheValue), &devices); err == nil {
+		return devices, nil
+	}
+
+	// The caches written by the previous versions only hold the IPs of the devices
+	var deviceIPs []net.IP
+	if err = json.Unmarshal([]byte(cacheValue), &deviceIPs); err != nil {
 		return nil, fmt.Errorf("couldn't unmarshal cache for %s: %s", subnet.cacheKey, err)
 	}
+	devices = make([]cachedDevice, 0, len(deviceIPs))
+	for _, deviceIP := range deviceIPs {
+		devices = append(devices, cachedDevice{IP: deviceIP.String()})
+	}
 	return devices, nil
 }
 
@@ -275,15 +439,18 @@ func (d *Discovery) loadCache(subnet *snmpSubnet) {
 		log.Errorf("subnet %s: error reading cache: %s", d.config.Network, err)
 		return
 	}
-	for _, deviceIP := range devices {
-		deviceDigest := subnet.config.DeviceDigest(deviceIP.String())
-		d.createDevice(deviceDigest, subnet, deviceIP.String(), false)
+	// the leases are acquired in the background by acquireCachedDevices, not to
+	// block the configuration of the check on the cluster agent
+	for _, device := range devices {
+		deviceDigest := subnet.config.DeviceDigest(device.IP)
+		d.createDevice(deviceDigest, subnet, device, false)
 	}
+	log.Debugf("subnet %s: %d devices loaded from the cache", d.config.Network, len(subnet.devices))
 }
 
 func (d *Discovery) writeCache(subnet *snmpSubnet) {
 	// We don't lock the subnet for now, because the discovery ought to be already locked
-	devices := make([]string, 0, len(subnet.devices))
+	devices := make([]cachedDevice, 0, len(subnet.devices))
 	for _, v := range subnet.devices {
 		devices = append(devices, v)
 	}
@@ -305,5 +472,6 @@ func NewDiscovery(config *checkconfig.CheckConfig) Discovery {
 		discoveredDevices: make(map[checkconfig.DeviceDigest]Device),
 		stop:              make(chan struct{}),
 		config:            config,
+		leaser:            devicelease.NewLeaser(time.Duration(config.DiscoveryInterval) * time.Second),
 	}
 }
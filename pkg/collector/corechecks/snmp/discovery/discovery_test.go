@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/session"
+	"github.com/DataDog/datadog-agent/pkg/persistentcache"
 	"github.com/gosnmp/gosnmp"
 	"github.com/stretchr/testify/assert"
 	"net"
+	"sync"
 	"testing"
 	"time"
 )
@@ -117,10 +119,9 @@ func TestDiscoveryCache(t *testing.T) {
 	}
 	discovery2 := NewDiscovery(checkConfig)
 	discovery2.Start()
-	time.Sleep(100 * time.Millisecond)
-	discovery2.Stop()
-
+	// the devices are loaded from the cache by Start
 	deviceConfigsFromCache := discovery2.GetDiscoveredDeviceConfigs()
+	discovery2.Stop()
 
 	var actualDiscoveredIpsFromCache []string
 	for _, deviceCk := range deviceConfigsFromCache {
@@ -129,6 +130,95 @@ func TestDiscoveryCache(t *testing.T) {
 	assert.ElementsMatch(t, expectedDiscoveredIps, actualDiscoveredIpsFromCache)
 }
 
+func TestDiscoveryCacheSysObjectID(t *testing.T) {
+	SetTestRunPath()
+	sess := session.CreateMockSession()
+	session.NewSession = func(*checkconfig.CheckConfig) (session.Session, error) {
+		return sess, nil
+	}
+
+	packet := gosnmp.SnmpPacket{
+		Variables: []gosnmp.SnmpPDU{
+			{
+				Name:  "1.3.6.1.2.1.1.2.0",
+				Type:  gosnmp.ObjectIdentifier,
+				Value: "1.3.6.1.4.1.3375.2.1.3.4.1",
+			},
+		},
+	}
+	sess.On("Get", []string{"1.3.6.1.2.1.1.2.0"}).Return(&packet, nil)
+
+	checkConfig := &checkconfig.CheckConfig{
+		Network:           "10.0.0.0/31",
+		CommunityString:   "public",
+		DiscoveryInterval: 3600,
+		DiscoveryWorkers:  1,
+	}
+	subnet := &snmpSubnet{cacheKey: fmt.Sprintf("%s:%s", cacheKeyPrefix, checkConfig.DeviceDigest(checkConfig.Network))}
+
+	// the caches of the previous versions only hold the IPs of the devices
+	err := persistentcache.Write(subnet.cacheKey, `["10.0.0.0"]`)
+	assert.Nil(t, err)
+
+	discovery := NewDiscovery(checkConfig)
+	devices, err := discovery.readCache(subnet)
+	assert.Nil(t, err)
+	assert.Equal(t, []cachedDevice{{IP: "10.0.0.0"}}, devices)
+
+	discovery.Start()
+	assert.Len(t, discovery.GetDiscoveredDeviceConfigs(), 1)
+
+	// the cached devices are verified in the background, and their sysObjectID cached
+	assert.Eventually(t, func() bool {
+		devices, err := discovery.readCache(subnet)
+		return err == nil && len(devices) == 1 && devices[0].SysObjectID != ""
+	}, time.Second, 10*time.Millisecond)
+	// the subnet is only scanned at the next discovery interval
+	time.Sleep(100 * time.Millisecond)
+	discovery.Stop()
+
+	devices, err = discovery.readCache(subnet)
+	assert.Nil(t, err)
+	assert.Equal(t, []cachedDevice{{IP: "10.0.0.0", SysObjectID: "1.3.6.1.4.1.3375.2.1.3.4.1"}}, devices)
+	assert.Len(t, discovery.GetDiscoveredDeviceConfigs(), 1)
+}
+
+func TestDiscoveryCacheLeases(t *testing.T) {
+	SetTestRunPath()
+	sess := session.CreateMockSession()
+	session.NewSession = func(*checkconfig.CheckConfig) (session.Session, error) {
+		return sess, nil
+	}
+
+	checkConfig := &checkconfig.CheckConfig{
+		Network:           "10.0.1.0/30",
+		CommunityString:   "public",
+		DiscoveryInterval: 3600,
+		DiscoveryWorkers:  0, // no workers, the cached devices are not re-verified
+		Namespace:         "default",
+	}
+	subnet := &snmpSubnet{cacheKey: fmt.Sprintf("%s:%s", cacheKeyPrefix, checkConfig.DeviceDigest(checkConfig.Network))}
+	err := persistentcache.Write(subnet.cacheKey, `[{"ip":"10.0.1.0"},{"ip":"10.0.1.1"}]`)
+	assert.Nil(t, err)
+
+	blocked := make(chan struct{})
+	leaser := &fakeLeaser{denied: map[string]bool{"default:10.0.1.1": true}, blocked: blocked}
+	discovery := NewDiscovery(checkConfig)
+	discovery.leaser = leaser
+
+	// the cached devices are created without waiting for their leases
+	discovery.Start()
+	assert.Len(t, discovery.GetDiscoveredDeviceConfigs(), 2)
+
+	// the devices leased to another agent are removed once the leases are acquired
+	close(blocked)
+	assert.Eventually(t, func() bool {
+		return len(discovery.GetDiscoveredDeviceConfigs()) == 1
+	}, time.Second, 10*time.Millisecond)
+	discovery.Stop()
+	assert.Equal(t, "10.0.1.0", discovery.GetDiscoveredDeviceConfigs()[0].GetIPAddress())
+}
+
 func TestDiscoveryTicker(t *testing.T) {
 	t.Skip() // TODO: FIX ME, currently this test is leading to data race when ran with other tests
 
@@ -182,7 +272,7 @@ func TestDiscovery_checkDevice(t *testing.T) {
 		startingIP:     startingIP,
 		network:        *ipNet,
 		cacheKey:       "abc:123",
-		devices:        map[checkconfig.DeviceDigest]string{},
+		devices:        map[checkconfig.DeviceDigest]cachedDevice{},
 		deviceFailures: map[checkconfig.DeviceDigest]int{},
 	}
 
@@ -297,16 +387,16 @@ func TestDiscovery_createDevice(t *testing.T) {
 		startingIP:     startingIP,
 		network:        *ipNet,
 		cacheKey:       "abc:123",
-		devices:        map[checkconfig.DeviceDigest]string{},
+		devices:        map[checkconfig.DeviceDigest]cachedDevice{},
 		deviceFailures: map[checkconfig.DeviceDigest]int{},
 	}
 
 	device1Digest := subnet.config.DeviceDigest("192.168.0.1")
 	device2Digest := subnet.config.DeviceDigest("192.168.0.2")
 	device3Digest := subnet.config.DeviceDigest("192.168.0.3")
-	discovery.createDevice(device1Digest, subnet, "192.168.0.1", true)
-	discovery.createDevice(device2Digest, subnet, "192.168.0.2", true)
-	discovery.createDevice(device3Digest, subnet, "192.168.0.3", false)
+	discovery.createDevice(device1Digest, subnet, cachedDevice{IP: "192.168.0.1"}, true)
+	discovery.createDevice(device2Digest, subnet, cachedDevice{IP: "192.168.0.2"}, true)
+	discovery.createDevice(device3Digest, subnet, cachedDevice{IP: "192.168.0.3"}, false)
 
 	assert.Equal(t, 3, len(discovery.discoveredDevices))
 
@@ -337,3 +427,84 @@ func TestDiscovery_createDevice(t *testing.T) {
 	discovery.deleteDevice(device1Digest, subnet) // really deletes the device
 	assert.Equal(t, 2, len(discovery.discoveredDevices))
 }
+
+type fakeLeaser struct {
+	mu       sync.Mutex
+	denied   map[string]bool
+	released []string
+	// blocked delays the leases until it is closed, like a slow cluster agent
+	blocked chan struct{}
+}
+
+func (l *fakeLeaser) Acquire(deviceIDs ...string) []string {
+	if l.blocked != nil {
+		<-l.blocked
+	}
+	var granted []string
+	for _, deviceID := range deviceIDs {
+		if !l.denied[deviceID] {
+			granted = append(granted, deviceID)
+		}
+	}
+	return granted
+}
+
+func (l *fakeLeaser) Release(deviceIDs ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.released = append(l.released, deviceIDs...)
+}
+
+func TestDiscoveryLeases(t *testing.T) {
+	sess := session.CreateMockSession()
+	session.NewSession = func(*checkconfig.CheckConfig) (session.Session, error) {
+		return sess, nil
+	}
+
+	packet := gosnmp.SnmpPacket{
+		Variables: []gosnmp.SnmpPDU{
+			{
+				Name:  "1.3.6.1.2.1.1.2.0",
+				Type:  gosnmp.ObjectIdentifier,
+				Value: "1.3.6.1.4.1.3375.2.1.3.4.1",
+			},
+		},
+	}
+	sess.On("Get", []string{"1.3.6.1.2.1.1.2.0"}).Return(&packet, nil)
+
+	checkConfig := &checkconfig.CheckConfig{
+		Network:           "192.168.0.0/30",
+		CommunityString:   "public",
+		DiscoveryInterval: 3600,
+		DiscoveryWorkers:  1,
+		Namespace:         "default",
+	}
+	discovery := NewDiscovery(checkConfig)
+	leaser := &fakeLeaser{denied: map[string]bool{"default:192.168.0.2": true}}
+	discovery.leaser = leaser
+	discovery.Start()
+	time.Sleep(100 * time.Millisecond)
+	discovery.Stop()
+
+	var actualDiscoveredIps []string
+	for _, deviceCk := range discovery.GetDiscoveredDeviceConfigs() {
+		actualDiscoveredIps = append(actualDiscoveredIps, deviceCk.GetIPAddress())
+	}
+	expectedDiscoveredIps := []string{
+		"192.168.0.0",
+		"192.168.0.1",
+		// 192.168.0.2 is leased to another agent
+		"192.168.0.3",
+	}
+	assert.ElementsMatch(t, expectedDiscoveredIps, actualDiscoveredIps)
+
+	// the leases are released when the discovery stops
+	assert.Eventually(t, func() bool {
+		leaser.mu.Lock()
+		defer leaser.mu.Unlock()
+		return len(leaser.released) == 3
+	}, time.Second, 10*time.Millisecond)
+	leaser.mu.Lock()
+	assert.ElementsMatch(t, []string{"default:192.168.0.0", "default:192.168.0.1", "default:192.168.0.3"}, leaser.released)
+	leaser.mu.Unlock()
+}
@@ -3,6 +3,8 @@ package fetch
 import (
 	"fmt"
 
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/session"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/valuestore"
@@ -22,7 +24,16 @@ func Fetch(sess session.Session, config *checkconfig.CheckConfig) (*valuestore.R
 	for _, value := range config.OidConfig.ColumnOids {
 		oids[value] = value
 	}
-	columnResults, err := fetchColumnOidsWithBatching(sess, oids, config.OidBatchSize, config.BulkMaxRepetitions)
+	fetchStrategy := useGetBulk
+	if config.UseGetNextOnly {
+		fetchStrategy = useGetNext
+	}
+	columnResults, fetchStrategy, err := fetchColumnOidsWithBatching(sess, oids, config.OidBatchSize, config.BulkMaxRepetitions, fetchStrategy)
+	if fetchStrategy == useGetNext && !config.UseGetNextOnly {
+		// do not try GetBulk next time, the device doesn't implement it properly
+		log.Warnf("Device %s doesn't answer GetBulk requests properly, its columns are fetched using GetNext from now on", config.IPAddress)
+		config.UseGetNextOnly = true
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch oids with batching: %v", err)
 	}
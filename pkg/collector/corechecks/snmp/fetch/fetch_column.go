@@ -15,14 +15,31 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/valuestore"
 )
 
-func fetchColumnOidsWithBatching(sess session.Session, oids map[string]string, oidBatchSize int, bulkMaxRepetitions uint32) (valuestore.ColumnResultValuesType, error) {
+// columnFetchStrategy is the SNMP operation used to walk the columns
+type columnFetchStrategy int
+
+const (
+	useGetBulk columnFetchStrategy = iota
+	useGetNext
+)
+
+func (s columnFetchStrategy) String() string {
+	if s == useGetNext {
+		return "GetNext"
+	}
+	return "GetBulk"
+}
+
+// fetchColumnOidsWithBatching returns the values of the columns and the strategy in use at the end of the
+// fetch, it is useGetNext when the device failed to answer the GetBulk requests but answered the GetNext ones
+func fetchColumnOidsWithBatching(sess session.Session, oids map[string]string, oidBatchSize int, bulkMaxRepetitions uint32, fetchStrategy columnFetchStrategy) (valuestore.ColumnResultValuesType, columnFetchStrategy, error) {
 	retValues := make(valuestore.ColumnResultValuesType, len(oids))
 
 	columnOids := getOidsMapKeys(oids)
 	sort.Strings(columnOids) // sorting ColumnOids to make them deterministic for testing purpose
 	batches, err := common.CreateStringBatches(columnOids, oidBatchSize)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create column oid batches: %s", err)
+		return nil, fetchStrategy, fmt.Errorf("failed to create column oid batches: %s", err)
 	}
 
 	for _, batchColumnOids := range batches {
@@ -31,9 +48,10 @@ func fetchColumnOidsWithBatching(sess session.Session, oids map[string]string, o
 			oidsToFetch[oid] = oids[oid]
 		}
 
-		results, err := fetchColumnOids(sess, oidsToFetch, bulkMaxRepetitions)
+		var results valuestore.ColumnResultValuesType
+		results, fetchStrategy, err = fetchColumnOids(sess, oidsToFetch, bulkMaxRepetitions, fetchStrategy)
 		if err != nil {
-			return nil, fmt.Errorf("failed to fetch column oids: %s", err)
+			return nil, fetchStrategy, fmt.Errorf("failed to fetch column oids: %s", err)
 		}
 
 		for columnOid, instanceOids := range results {
@@ -46,13 +64,13 @@ func fetchColumnOidsWithBatching(sess session.Session, oids map[string]string, o
 			}
 		}
 	}
-	return retValues, nil
+	return retValues, fetchStrategy, nil
 }
 
 // fetchColumnOids has an `oids` argument representing a `map[string]string`,
 // the key of the map is the column oid, and the value is the oid used to fetch the next value for the column.
 // The value oid might be equal to column oid or a row oid of the same column.
-func fetchColumnOids(sess session.Session, oids map[string]string, bulkMaxRepetitions uint32) (valuestore.ColumnResultValuesType, error) {
+func fetchColumnOids(sess session.Session, oids map[string]string, bulkMaxRepetitions uint32, fetchStrategy columnFetchStrategy) (valuestore.ColumnResultValuesType, columnFetchStrategy, error) {
 	returnValues := make(valuestore.ColumnResultValuesType, len(oids))
 	alreadyProcessedOids := make(map[string]bool)
 	curOids := oids
@@ -78,44 +96,71 @@ func fetchColumnOids(sess session.Session, oids map[string]string, bulkMaxRepeti
 		sort.Strings(columnOids)
 		sort.Strings(requestOids)
 
-		results, err := getResults(sess, requestOids, bulkMaxRepetitions)
+		results, newFetchStrategy, err := getResults(sess, requestOids, bulkMaxRepetitions, fetchStrategy)
 		if err != nil {
-			return nil, err
+			return nil, fetchStrategy, err
 		}
+		fetchStrategy = newFetchStrategy
 		newValues, nextOids := gosnmplib.ResultToColumnValues(columnOids, results)
 		updateColumnResultValues(returnValues, newValues)
 		curOids = nextOids
 	}
-	return returnValues, nil
+	return returnValues, fetchStrategy, nil
 }
 
-func getResults(sess session.Session, requestOids []string, bulkMaxRepetitions uint32) (*gosnmp.SnmpPacket, error) {
-	var results *gosnmp.SnmpPacket
-	if sess.GetVersion() == gosnmp.Version1 {
+func getResults(sess session.Session, requestOids []string, bulkMaxRepetitions uint32, fetchStrategy columnFetchStrategy) (*gosnmp.SnmpPacket, columnFetchStrategy, error) {
+	if sess.GetVersion() == gosnmp.Version1 || fetchStrategy == useGetNext {
 		// snmp v1 doesn't support GetBulk
-		getNextResults, err := sess.GetNext(requestOids)
-		if err != nil {
-			log.Debugf("fetch column: failed getting oids `%v` using GetNext: %s", requestOids, err)
-			return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetNext: %s", requestOids, err)
-		}
-		results = getNextResults
-		if log.ShouldLog(seelog.DebugLvl) {
-			log.Debugf("fetch column: GetNext results: %v", gosnmplib.PacketAsString(results))
-		}
-	} else {
-		getBulkResults, err := sess.GetBulk(requestOids, bulkMaxRepetitions)
-		if err != nil {
-			log.Debugf("fetch column: failed getting oids `%v` using GetBulk: %s", requestOids, err)
-			return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetBulk: %s", requestOids, err)
-		}
-		results = getBulkResults
-		if log.ShouldLog(seelog.DebugLvl) {
-			log.Debugf("fetch column: GetBulk results: %v", gosnmplib.PacketAsString(results))
+		results, err := getNextResults(sess, requestOids)
+		return results, fetchStrategy, err
+	}
+
+	getBulkResults, err := sess.GetBulk(requestOids, bulkMaxRepetitions)
+	if err == nil {
+		err = checkBulkResults(getBulkResults)
+	}
+	if err != nil {
+		log.Debugf("fetch column: failed getting oids `%v` using GetBulk: %s", requestOids, err)
+		bulkErr := fmt.Errorf("fetch column: failed getting oids `%v` using GetBulk: %s", requestOids, err)
+
+		// Some devices mis-implement GetBulk, fall back to GetNext if the device answers it
+		results, getNextErr := getNextResults(sess, requestOids)
+		if getNextErr != nil {
+			return nil, fetchStrategy, bulkErr
 		}
+		log.Warnf("fetch column: GetBulk failed but GetNext succeeded, falling back to GetNext for this device: %s", bulkErr)
+		return results, useGetNext, nil
+	}
+	if log.ShouldLog(seelog.DebugLvl) {
+		log.Debugf("fetch column: GetBulk results: %v", gosnmplib.PacketAsString(getBulkResults))
+	}
+	return getBulkResults, fetchStrategy, nil
+}
+
+func getNextResults(sess session.Session, requestOids []string) (*gosnmp.SnmpPacket, error) {
+	results, err := sess.GetNext(requestOids)
+	if err != nil {
+		log.Debugf("fetch column: failed getting oids `%v` using GetNext: %s", requestOids, err)
+		return nil, fmt.Errorf("fetch column: failed getting oids `%v` using GetNext: %s", requestOids, err)
+	}
+	if log.ShouldLog(seelog.DebugLvl) {
+		log.Debugf("fetch column: GetNext results: %v", gosnmplib.PacketAsString(results))
 	}
 	return results, nil
 }
 
+// checkBulkResults returns an error if a GetBulk response is unusable: the device reported an error
+// or returned no variable, while it must return at least an endOfMibView per requested oid
+func checkBulkResults(results *gosnmp.SnmpPacket) error {
+	if results == nil || len(results.Variables) == 0 {
+		return fmt.Errorf("empty GetBulk response")
+	}
+	if results.Error != gosnmp.NoError {
+		return fmt.Errorf("GetBulk response error: %v, error index: %d", results.Error, results.ErrorIndex)
+	}
+	return nil
+}
+
 func updateColumnResultValues(valuesToUpdate valuestore.ColumnResultValuesType, extraValues valuestore.ColumnResultValuesType) {
 	for columnOid, columnValues := range extraValues {
 		for oid, value := range columnValues {
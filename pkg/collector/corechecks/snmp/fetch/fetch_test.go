@@ -10,6 +10,7 @@ import (
 	"github.com/cihub/seelog"
 	"github.com/gosnmp/gosnmp"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -85,7 +86,7 @@ func Test_fetchColumnOids(t *testing.T) {
 
 	oids := map[string]string{"1.1.1": "1.1.1", "1.1.2": "1.1.2"}
 
-	columnValues, err := fetchColumnOidsWithBatching(sess, oids, 100, checkconfig.DefaultBulkMaxRepetitions)
+	columnValues, _, err := fetchColumnOidsWithBatching(sess, oids, 100, checkconfig.DefaultBulkMaxRepetitions, useGetBulk)
 	assert.Nil(t, err)
 
 	expectedColumnValues := valuestore.ColumnResultValuesType{
@@ -176,7 +177,7 @@ func Test_fetchColumnOidsBatch_usingGetBulk(t *testing.T) {
 
 	oids := map[string]string{"1.1.1": "1.1.1", "1.1.2": "1.1.2"}
 
-	columnValues, err := fetchColumnOidsWithBatching(sess, oids, 2, 10)
+	columnValues, _, err := fetchColumnOidsWithBatching(sess, oids, 2, 10, useGetBulk)
 	assert.Nil(t, err)
 
 	expectedColumnValues := valuestore.ColumnResultValuesType{
@@ -273,7 +274,7 @@ func Test_fetchColumnOidsBatch_usingGetNext(t *testing.T) {
 
 	oids := map[string]string{"1.1.1": "1.1.1", "1.1.2": "1.1.2", "1.1.3": "1.1.3"}
 
-	columnValues, err := fetchColumnOidsWithBatching(sess, oids, 2, 10)
+	columnValues, _, err := fetchColumnOidsWithBatching(sess, oids, 2, 10, useGetBulk)
 	assert.Nil(t, err)
 
 	expectedColumnValues := valuestore.ColumnResultValuesType{
@@ -644,6 +645,7 @@ func Test_fetchValues_errors(t *testing.T) {
 			sess := session.CreateMockSession()
 			sess.On("Get", []string{"1.1", "2.2"}).Return(&gosnmp.SnmpPacket{}, fmt.Errorf("get error"))
 			sess.On("GetBulk", []string{"1.1", "2.2"}, checkconfig.DefaultBulkMaxRepetitions).Return(&gosnmp.SnmpPacket{}, fmt.Errorf("bulk error"))
+			sess.On("GetNext", []string{"1.1", "2.2"}).Return(&gosnmp.SnmpPacket{}, fmt.Errorf("getnext error"))
 
 			_, err := Fetch(sess, &tt.config)
 
@@ -652,6 +654,110 @@ func Test_fetchValues_errors(t *testing.T) {
 	}
 }
 
+func Test_fetchColumnOids_getBulkFallback(t *testing.T) {
+	tests := []struct {
+		name       string
+		bulkPacket *gosnmp.SnmpPacket
+		bulkErr    error
+	}{
+		{
+			name:       "bulk error",
+			bulkPacket: &gosnmp.SnmpPacket{},
+			bulkErr:    fmt.Errorf("bulk error"),
+		},
+		{
+			name: "bulk response error",
+			bulkPacket: &gosnmp.SnmpPacket{
+				Error:      gosnmp.GenErr,
+				ErrorIndex: 1,
+				Variables: []gosnmp.SnmpPDU{
+					{Name: "1.1.1.1", Type: gosnmp.Null},
+				},
+			},
+		},
+		{
+			name:       "empty bulk response",
+			bulkPacket: &gosnmp.SnmpPacket{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sess := session.CreateMockSession()
+			sess.On("GetBulk", []string{"1.1.1", "1.1.2"}, checkconfig.DefaultBulkMaxRepetitions).Return(tt.bulkPacket, tt.bulkErr)
+			sess.On("GetNext", []string{"1.1.1", "1.1.2"}).Return(&gosnmp.SnmpPacket{
+				Variables: []gosnmp.SnmpPDU{
+					{Name: "1.1.1.1", Type: gosnmp.TimeTicks, Value: 11},
+					{Name: "1.1.2.1", Type: gosnmp.TimeTicks, Value: 21},
+				},
+			}, nil)
+			sess.On("GetNext", []string{"1.1.1.1", "1.1.2.1"}).Return(&gosnmp.SnmpPacket{
+				Variables: []gosnmp.SnmpPDU{
+					{Name: "1.1.9.1", Type: gosnmp.TimeTicks, Value: 91},
+					{Name: "1.1.9.1", Type: gosnmp.TimeTicks, Value: 91},
+				},
+			}, nil)
+
+			config := &checkconfig.CheckConfig{
+				BulkMaxRepetitions: checkconfig.DefaultBulkMaxRepetitions,
+				OidBatchSize:       10,
+				OidConfig: checkconfig.OidConfig{
+					ColumnOids: []string{"1.1.1", "1.1.2"},
+				},
+			}
+			values, err := Fetch(sess, config)
+			require.NoError(t, err)
+
+			expectedColumnValues := valuestore.ColumnResultValuesType{
+				"1.1.1": {
+					"1": valuestore.ResultValue{Value: float64(11)},
+				},
+				"1.1.2": {
+					"1": valuestore.ResultValue{Value: float64(21)},
+				},
+			}
+			assert.Equal(t, expectedColumnValues, values.ColumnValues)
+			// the next runs use GetNext directly
+			assert.True(t, config.UseGetNextOnly)
+			sess.AssertNumberOfCalls(t, "GetBulk", 1)
+
+			_, err = Fetch(sess, config)
+			require.NoError(t, err)
+			sess.AssertNumberOfCalls(t, "GetBulk", 1)
+		})
+	}
+}
+
+func Test_fetchColumnOids_useGetNextOnly(t *testing.T) {
+	sess := session.CreateMockSession()
+	sess.On("GetNext", []string{"1.1.1"}).Return(&gosnmp.SnmpPacket{
+		Variables: []gosnmp.SnmpPDU{
+			{Name: "1.1.1.1", Type: gosnmp.TimeTicks, Value: 11},
+		},
+	}, nil)
+	sess.On("GetNext", []string{"1.1.1.1"}).Return(&gosnmp.SnmpPacket{
+		Variables: []gosnmp.SnmpPDU{
+			{Name: "1.1.9.1", Type: gosnmp.TimeTicks, Value: 91},
+		},
+	}, nil)
+
+	config := &checkconfig.CheckConfig{
+		BulkMaxRepetitions: checkconfig.DefaultBulkMaxRepetitions,
+		OidBatchSize:       10,
+		UseGetNextOnly:     true,
+		OidConfig: checkconfig.OidConfig{
+			ColumnOids: []string{"1.1.1"},
+		},
+	}
+	values, err := Fetch(sess, config)
+	require.NoError(t, err)
+	assert.Equal(t, valuestore.ColumnResultValuesType{
+		"1.1.1": {
+			"1": valuestore.ResultValue{Value: float64(11)},
+		},
+	}, values.ColumnValues)
+	sess.AssertNotCalled(t, "GetBulk", mock.Anything, mock.Anything)
+}
+
 func Test_fetchColumnOids_alreadyProcessed(t *testing.T) {
 	var b bytes.Buffer
 	w := bufio.NewWriter(&b)
@@ -753,7 +859,7 @@ func Test_fetchColumnOids_alreadyProcessed(t *testing.T) {
 
 	oids := map[string]string{"1.1.1": "1.1.1", "1.1.2": "1.1.2"}
 
-	columnValues, err := fetchColumnOidsWithBatching(sess, oids, 100, checkconfig.DefaultBulkMaxRepetitions)
+	columnValues, _, err := fetchColumnOidsWithBatching(sess, oids, 100, checkconfig.DefaultBulkMaxRepetitions, useGetBulk)
 	assert.Nil(t, err)
 
 	expectedColumnValues := valuestore.ColumnResultValuesType{
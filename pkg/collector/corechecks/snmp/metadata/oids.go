@@ -41,3 +41,26 @@ var ColumnOIDs = []string{
 	IfAdminStatusOID,
 	IfOperStatusOID,
 }
+
+const (
+	// EntPhysicalClassOID is the OID for ENTITY-MIB entPhysicalClass
+	EntPhysicalClassOID = "1.3.6.1.2.1.47.1.1.1.1.5"
+	// EntPhysicalFirmwareRevOID is the OID for ENTITY-MIB entPhysicalFirmwareRev
+	EntPhysicalFirmwareRevOID = "1.3.6.1.2.1.47.1.1.1.1.9"
+	// EntPhysicalSerialNumOID is the OID for ENTITY-MIB entPhysicalSerialNum
+	EntPhysicalSerialNumOID = "1.3.6.1.2.1.47.1.1.1.1.11"
+	// EntPhysicalModelNameOID is the OID for ENTITY-MIB entPhysicalModelName
+	EntPhysicalModelNameOID = "1.3.6.1.2.1.47.1.1.1.1.13"
+
+	// EntPhysicalClassChassis is the entPhysicalClass of the chassis of the device
+	EntPhysicalClassChassis = 3
+)
+
+// EntityColumnOIDs is the list of the ENTITY-MIB column OIDs collected when
+// the profile enables `collect_entity_metadata`
+var EntityColumnOIDs = []string{
+	EntPhysicalClassOID,
+	EntPhysicalFirmwareRevOID,
+	EntPhysicalSerialNumOID,
+	EntPhysicalModelNameOID,
+}
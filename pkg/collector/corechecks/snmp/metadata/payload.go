@@ -36,6 +36,11 @@ type DeviceMetadata struct {
 	Subnet      string       `json:"subnet"`
 	Tags        []string     `json:"tags"`
 	Status      DeviceStatus `json:"status"`
+
+	// ENTITY-MIB chassis details, collected when the profile enables `collect_entity_metadata`
+	SerialNumber    string `json:"serial_number,omitempty"`
+	Model           string `json:"model,omitempty"`
+	FirmwareVersion string `json:"firmware_version,omitempty"`
 }
 
 // InterfaceMetadata contains interface metadata
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/metadata"
+)
+
+// metadataPayloadsTTL is the duration after which the payloads of a device
+// that isn't monitored anymore are forgotten
+const metadataPayloadsTTL = time.Hour
+
+type storedMetadataPayloads struct {
+	payloads    []metadata.NetworkDevicesMetadata
+	collectTime time.Time
+}
+
+// lastMetadataPayloads holds the last network devices metadata payloads built for each device,
+// so that their content can be inspected through the agent API without capturing the traffic
+var lastMetadataPayloads = struct {
+	sync.RWMutex
+	devices map[string]storedMetadataPayloads
+}{
+	devices: make(map[string]storedMetadataPayloads),
+}
+
+func storeNetworkDevicesMetadata(deviceID string, payloads []metadata.NetworkDevicesMetadata, collectTime time.Time) {
+	lastMetadataPayloads.Lock()
+	defer lastMetadataPayloads.Unlock()
+
+	lastMetadataPayloads.devices[deviceID] = storedMetadataPayloads{payloads: payloads, collectTime: collectTime}
+	for id, stored := range lastMetadataPayloads.devices {
+		if collectTime.Sub(stored.collectTime) > metadataPayloadsTTL {
+			delete(lastMetadataPayloads.devices, id)
+		}
+	}
+}
+
+// GetNetworkDevicesMetadata returns the last network devices metadata payloads built by the SNMP
+// check instances, ordered by device ID. The payloads are the ones sent, or that would have been
+// sent if the pipeline wasn't saturated.
+func GetNetworkDevicesMetadata() []metadata.NetworkDevicesMetadata {
+	lastMetadataPayloads.RLock()
+	defer lastMetadataPayloads.RUnlock()
+
+	deviceIDs := make([]string, 0, len(lastMetadataPayloads.devices))
+	for id := range lastMetadataPayloads.devices {
+		deviceIDs = append(deviceIDs, id)
+	}
+	sort.Strings(deviceIDs)
+
+	payloads := []metadata.NetworkDevicesMetadata{}
+	for _, id := range deviceIDs {
+		payloads = append(payloads, lastMetadataPayloads.devices[id].payloads...)
+	}
+	return payloads
+}
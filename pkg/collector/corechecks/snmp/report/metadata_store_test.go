@@ -0,0 +1,37 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/metadata"
+)
+
+func TestStoreNetworkDevicesMetadata(t *testing.T) {
+	defer func() {
+		lastMetadataPayloads.devices = make(map[string]storedMetadataPayloads)
+	}()
+
+	now := time.Date(2021, time.September, 1, 12, 0, 0, 0, time.UTC)
+	payloadA := metadata.NetworkDevicesMetadata{Namespace: "default", Devices: []metadata.DeviceMetadata{{ID: "default:1.2.3.4"}}, CollectTimestamp: now.Unix()}
+	payloadB := metadata.NetworkDevicesMetadata{Namespace: "default", Devices: []metadata.DeviceMetadata{{ID: "default:1.2.3.5"}}, CollectTimestamp: now.Unix()}
+
+	assert.Empty(t, GetNetworkDevicesMetadata())
+
+	storeNetworkDevicesMetadata("default:1.2.3.5", []metadata.NetworkDevicesMetadata{payloadB}, now)
+	storeNetworkDevicesMetadata("default:1.2.3.4", []metadata.NetworkDevicesMetadata{payloadA}, now)
+	assert.Equal(t, []metadata.NetworkDevicesMetadata{payloadA, payloadB}, GetNetworkDevicesMetadata())
+
+	// the last payloads of a device replace the previous ones
+	payloadA.CollectTimestamp = now.Add(time.Minute).Unix()
+	storeNetworkDevicesMetadata("default:1.2.3.4", []metadata.NetworkDevicesMetadata{payloadA}, now.Add(time.Minute))
+	assert.Equal(t, []metadata.NetworkDevicesMetadata{payloadA, payloadB}, GetNetworkDevicesMetadata())
+
+	// the devices that aren't monitored anymore are forgotten
+	later := now.Add(metadataPayloadsTTL + 2*time.Minute)
+	payloadA.CollectTimestamp = later.Unix()
+	storeNetworkDevicesMetadata("default:1.2.3.4", []metadata.NetworkDevicesMetadata{payloadA}, later)
+	assert.Equal(t, []metadata.NetworkDevicesMetadata{payloadA}, GetNetworkDevicesMetadata())
+}
@@ -4,6 +4,7 @@ import (
 	json "encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/epforwarder"
@@ -19,8 +20,15 @@ import (
 // interfaceNameTagKey matches the `interface` tag used in `_generic-if.yaml` for ifName
 var interfaceNameTagKey = "interface"
 
-// ReportNetworkDeviceMetadata reports device metadata
-func (ms *MetricSender) ReportNetworkDeviceMetadata(config *checkconfig.CheckConfig, store *valuestore.ResultValueStore, origTags []string, collectTime time.Time, deviceStatus metadata.DeviceStatus) {
+// ContextValueStore holds the values fetched from an additional SNMP context
+type ContextValueStore struct {
+	Tags  []string
+	Store *valuestore.ResultValueStore
+}
+
+// ReportNetworkDeviceMetadata reports device metadata, the interfaces of the
+// additional SNMP contexts are reported with the tags of their context
+func (ms *MetricSender) ReportNetworkDeviceMetadata(config *checkconfig.CheckConfig, store *valuestore.ResultValueStore, contextStores []ContextValueStore, origTags []string, collectTime time.Time, deviceStatus metadata.DeviceStatus) {
 	tags := common.CopyStrings(origTags)
 	tags = util.SortUniqInPlace(tags)
 
@@ -30,8 +38,20 @@ func (ms *MetricSender) ReportNetworkDeviceMetadata(config *checkconfig.CheckCon
 	if err != nil {
 		log.Debugf("Unable to build interfaces metadata: %s", err)
 	}
+	for _, contextStore := range contextStores {
+		contextInterfaces, err := buildNetworkInterfacesMetadata(config.DeviceID, contextStore.Store)
+		if err != nil {
+			log.Debugf("Unable to build interfaces metadata of context %v: %s", contextStore.Tags, err)
+			continue
+		}
+		for i := range contextInterfaces {
+			contextInterfaces[i].IDTags = append(contextInterfaces[i].IDTags, contextStore.Tags...)
+		}
+		interfaces = append(interfaces, contextInterfaces...)
+	}
 
 	metadataPayloads := batchPayloads(config.Namespace, config.ResolvedSubnetName, collectTime, metadata.PayloadMetadataBatchSize, device, interfaces)
+	storeNetworkDevicesMetadata(config.DeviceID, metadataPayloads, collectTime)
 
 	for _, payload := range metadataPayloads {
 		payloadBytes, err := json.Marshal(payload)
@@ -39,7 +59,16 @@ func (ms *MetricSender) ReportNetworkDeviceMetadata(config *checkconfig.CheckCon
 			log.Errorf("Error marshalling device metadata: %s", err)
 			return
 		}
-		ms.sender.EventPlatformEvent(string(payloadBytes), epforwarder.EventTypeNetworkDevicesMetadata)
+		err = ms.sender.TryEventPlatformEvent(string(payloadBytes), epforwarder.EventTypeNetworkDevicesMetadata)
+		if epforwarder.IsBackpressure(err) {
+			// metadata is sent again on the next run, don't block the check while the pipeline is saturated
+			log.Debugf("Skipping device metadata for device %s this run: %s", config.DeviceID, err)
+			return
+		}
+		if err != nil {
+			log.Errorf("Error sending device metadata: %s", err)
+			return
+		}
 	}
 }
 
@@ -55,6 +84,8 @@ func buildNetworkDeviceMetadata(deviceID string, idTags []string, config *checkc
 		vendor = config.ProfileDef.Device.Vendor
 	}
 
+	entity := getDeviceEntity(config, store)
+
 	return metadata.DeviceMetadata{
 		ID:          deviceID,
 		IDTags:      idTags,
@@ -67,7 +98,75 @@ func buildNetworkDeviceMetadata(deviceID string, idTags []string, config *checkc
 		Tags:        tags,
 		Subnet:      config.ResolvedSubnetName,
 		Status:      deviceStatus,
+
+		SerialNumber:    entity.serialNumber,
+		Model:           entity.model,
+		FirmwareVersion: entity.firmwareVersion,
+	}
+}
+
+// deviceEntity holds the ENTITY-MIB details of the chassis of a device
+type deviceEntity struct {
+	serialNumber    string
+	model           string
+	firmwareVersion string
+}
+
+// getDeviceEntity returns the details of the chassis of the device, the
+// physical entity of class chassis with the lowest index. They are only
+// collected when the profile enables `collect_entity_metadata`.
+func getDeviceEntity(config *checkconfig.CheckConfig, store *valuestore.ResultValueStore) deviceEntity {
+	if store == nil || config.ProfileDef == nil || !config.ProfileDef.Device.CollectEntityMetadata {
+		return deviceEntity{}
+	}
+	indexes, err := store.GetColumnIndexes(metadata.EntPhysicalClassOID)
+	if err != nil {
+		log.Debugf("no physical entity found: %s", err)
+		return deviceEntity{}
+	}
+
+	chassisIndex := -1
+	for _, strIndex := range indexes {
+		if int(store.GetColumnValueAsFloat(metadata.EntPhysicalClassOID, strIndex)) != metadata.EntPhysicalClassChassis {
+			continue
+		}
+		index, err := strconv.Atoi(strIndex)
+		if err != nil {
+			continue
+		}
+		if chassisIndex == -1 || index < chassisIndex {
+			chassisIndex = index
+		}
+	}
+	if chassisIndex == -1 {
+		log.Debugf("no physical entity of class chassis found")
+		return deviceEntity{}
+	}
+
+	strIndex := strconv.Itoa(chassisIndex)
+	return deviceEntity{
+		serialNumber:    strings.TrimSpace(store.GetColumnValueAsString(metadata.EntPhysicalSerialNumOID, strIndex)),
+		model:           strings.TrimSpace(store.GetColumnValueAsString(metadata.EntPhysicalModelNameOID, strIndex)),
+		firmwareVersion: strings.TrimSpace(store.GetColumnValueAsString(metadata.EntPhysicalFirmwareRevOID, strIndex)),
+	}
+}
+
+// GetDeviceEntityTags returns the tags of the device built from the details
+// of its chassis, when the profile enables `collect_entity_metadata`
+func GetDeviceEntityTags(config *checkconfig.CheckConfig, store *valuestore.ResultValueStore) []string {
+	entity := getDeviceEntity(config, store)
+
+	var tags []string
+	if entity.serialNumber != "" {
+		tags = append(tags, "device_serial_number:"+entity.serialNumber)
+	}
+	if entity.model != "" {
+		tags = append(tags, "device_model:"+entity.model)
+	}
+	if entity.firmwareVersion != "" {
+		tags = append(tags, "device_firmware_version:"+entity.firmwareVersion)
 	}
+	return tags
 }
 
 func buildNetworkInterfacesMetadata(deviceID string, store *valuestore.ResultValueStore) ([]metadata.InterfaceMetadata, error) {
@@ -4,6 +4,8 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"testing"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 
+	"github.com/DataDog/datadog-agent/pkg/epforwarder"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
@@ -32,7 +35,7 @@ func Test_metricSender_reportNetworkDeviceMetadata_withoutInterfaces(t *testing.
 	}
 
 	sender := mocksender.NewMockSender("testID") // required to initiate aggregator
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	ms := &MetricSender{
 		sender: sender,
 	}
@@ -49,7 +52,7 @@ func Test_metricSender_reportNetworkDeviceMetadata_withoutInterfaces(t *testing.
 	collectTime, err := time.Parse(layout, str)
 	assert.NoError(t, err)
 
-	ms.ReportNetworkDeviceMetadata(config, storeWithoutIfName, []string{"tag1", "tag2"}, collectTime, metadata.DeviceStatusReachable)
+	ms.ReportNetworkDeviceMetadata(config, storeWithoutIfName, nil, []string{"tag1", "tag2"}, collectTime, metadata.DeviceStatusReachable)
 
 	// language=json
 	event := []byte(`
@@ -83,7 +86,7 @@ func Test_metricSender_reportNetworkDeviceMetadata_withoutInterfaces(t *testing.
 	err = json.Compact(compactEvent, event)
 	assert.NoError(t, err)
 
-	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+	sender.AssertTryEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 
 	w.Flush()
 	logs := b.String()
@@ -101,7 +104,7 @@ func Test_metricSender_reportNetworkDeviceMetadata_withInterfaces(t *testing.T)
 		},
 	}
 	sender := mocksender.NewMockSender("testID") // required to initiate aggregator
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	ms := &MetricSender{
 		sender: sender,
 	}
@@ -118,7 +121,7 @@ func Test_metricSender_reportNetworkDeviceMetadata_withInterfaces(t *testing.T)
 	str := "2014-11-12 11:45:26"
 	collectTime, err := time.Parse(layout, str)
 	assert.NoError(t, err)
-	ms.ReportNetworkDeviceMetadata(config, storeWithIfName, []string{"tag1", "tag2"}, collectTime, metadata.DeviceStatusReachable)
+	ms.ReportNetworkDeviceMetadata(config, storeWithIfName, nil, []string{"tag1", "tag2"}, collectTime, metadata.DeviceStatusReachable)
 
 	// language=json
 	event := []byte(`
@@ -180,7 +183,7 @@ func Test_metricSender_reportNetworkDeviceMetadata_withInterfaces(t *testing.T)
 	err = json.Compact(compactEvent, event)
 	assert.NoError(t, err)
 
-	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+	sender.AssertTryEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 }
 
 func Test_batchPayloads(t *testing.T) {
@@ -217,3 +220,90 @@ func Test_batchPayloads(t *testing.T) {
 	assert.Equal(t, 51, len(payloads[3].Interfaces))
 	assert.Equal(t, interfaces[299:350], payloads[3].Interfaces)
 }
+
+func Test_metricSender_reportNetworkDeviceMetadata_backpressure(t *testing.T) {
+	ifNames := make(map[string]valuestore.ResultValue)
+	for i := 1; i <= 350; i++ {
+		ifNames[strconv.Itoa(i)] = valuestore.ResultValue{Value: fmt.Sprintf("eth%d", i)}
+	}
+	store := &valuestore.ResultValueStore{
+		ColumnValues: valuestore.ColumnResultValuesType{
+			"1.3.6.1.2.1.31.1.1.1.1": ifNames,
+		},
+	}
+	sender := mocksender.NewMockSender("testID") // required to initiate aggregator
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(epforwarder.ErrPipelineFull)
+	ms := &MetricSender{
+		sender: sender,
+	}
+
+	config := &checkconfig.CheckConfig{
+		IPAddress:          "1.2.3.4",
+		DeviceID:           "1234",
+		ResolvedSubnetName: "127.0.0.0/29",
+		Namespace:          "my-ns",
+	}
+
+	ms.ReportNetworkDeviceMetadata(config, store, nil, []string{"tag1"}, time.Now(), metadata.DeviceStatusReachable)
+
+	// the remaining payloads are skipped once the pipeline reports backpressure
+	sender.AssertNumberOfCalls(t, "TryEventPlatformEvent", 1)
+}
+
+func Test_getDeviceEntity(t *testing.T) {
+	// language=yaml
+	rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+profile: entity-profile
+`)
+	// language=yaml
+	rawInitConfig := []byte(`
+profiles:
+  entity-profile:
+    definition:
+      device:
+        vendor: acme
+        collect_entity_metadata: true
+`)
+	config, err := checkconfig.NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.NoError(t, err)
+	assert.Contains(t, config.OidConfig.ColumnOids, metadata.EntPhysicalSerialNumOID)
+
+	store := &valuestore.ResultValueStore{
+		ColumnValues: valuestore.ColumnResultValuesType{
+			metadata.EntPhysicalClassOID: {
+				"1":    valuestore.ResultValue{Value: float64(3)},
+				"1001": valuestore.ResultValue{Value: float64(9)},
+				"2":    valuestore.ResultValue{Value: float64(3)},
+			},
+			metadata.EntPhysicalSerialNumOID: {
+				"1":    valuestore.ResultValue{Value: "FOC1234X0AB "},
+				"1001": valuestore.ResultValue{Value: "MOD-SERIAL"},
+				"2":    valuestore.ResultValue{Value: "FOC5678X0CD"},
+			},
+			metadata.EntPhysicalModelNameOID: {
+				"1": valuestore.ResultValue{Value: "C9300-48P"},
+			},
+			metadata.EntPhysicalFirmwareRevOID: {
+				"1": valuestore.ResultValue{Value: "17.3.4"},
+			},
+		},
+	}
+
+	device := buildNetworkDeviceMetadata(config.DeviceID, config.DeviceIDTags, config, store, nil, metadata.DeviceStatusReachable)
+	assert.Equal(t, "FOC1234X0AB", device.SerialNumber)
+	assert.Equal(t, "C9300-48P", device.Model)
+	assert.Equal(t, "17.3.4", device.FirmwareVersion)
+
+	assert.Equal(t, []string{
+		"device_serial_number:FOC1234X0AB",
+		"device_model:C9300-48P",
+		"device_firmware_version:17.3.4",
+	}, GetDeviceEntityTags(config, store))
+
+	// not collected unless the profile enables it
+	config.ProfileDef.Device.CollectEntityMetadata = false
+	assert.Empty(t, GetDeviceEntityTags(config, store))
+	device = buildNetworkDeviceMetadata(config.DeviceID, config.DeviceIDTags, config, store, nil, metadata.DeviceStatusReachable)
+	assert.Equal(t, "", device.SerialNumber)
+}
@@ -18,6 +18,8 @@ type MetricSender struct {
 	sender           aggregator.Sender
 	hostname         string
 	submittedMetrics int
+	// unitsMetadata are the series metadata shared by the metrics of each unit
+	unitsMetadata map[string]*metrics.SerieMetadata
 }
 
 // NewMetricSender create a new MetricSender
@@ -64,7 +66,7 @@ func (ms *MetricSender) reportScalarMetrics(metric checkconfig.MetricsConfig, va
 
 	scalarTags := common.CopyStrings(tags)
 	scalarTags = append(scalarTags, metric.GetSymbolTags()...)
-	ms.sendMetric(metric.Symbol.Name, value, scalarTags, metric.ForcedType, metric.Options, metric.Symbol.ExtractValuePattern)
+	ms.reportSymbolValue(metric, metric.Symbol, value, scalarTags)
 }
 
 func (ms *MetricSender) reportColumnMetrics(metricConfig checkconfig.MetricsConfig, values *valuestore.ResultValueStore, tags []string) {
@@ -80,13 +82,26 @@ func (ms *MetricSender) reportColumnMetrics(metricConfig checkconfig.MetricsConf
 				rowTagsCache[fullIndex] = append(common.CopyStrings(tags), metricConfig.GetTags(fullIndex, values)...)
 			}
 			rowTags := rowTagsCache[fullIndex]
-			ms.sendMetric(symbol.Name, value, rowTags, metricConfig.ForcedType, metricConfig.Options, symbol.ExtractValuePattern)
+			ms.reportSymbolValue(metricConfig, symbol, value, rowTags)
 			ms.trySendBandwidthUsageMetric(symbol, fullIndex, values, rowTags)
 		}
 	}
 }
 
-func (ms *MetricSender) sendMetric(metricName string, value valuestore.ResultValue, tags []string, forcedType string, options checkconfig.MetricsConfigOption, extractValuePattern *regexp.Regexp) {
+// reportSymbolValue reports the value of a symbol as a metric, a gauge per state or a service check, depending on
+// the metric type
+func (ms *MetricSender) reportSymbolValue(metricConfig checkconfig.MetricsConfig, symbol checkconfig.SymbolConfig, value valuestore.ResultValue, tags []string) {
+	switch metricConfig.ForcedType {
+	case "state":
+		ms.sendStateMetrics(metricConfig, symbol, value, tags)
+	case "service_check":
+		ms.sendServiceCheck(metricConfig, symbol, value, tags)
+	default:
+		ms.sendMetric(symbol.Name, value, tags, metricConfig.ForcedType, metricConfig.Options, symbol.ExtractValuePattern, symbol.Unit)
+	}
+}
+
+func (ms *MetricSender) sendMetric(metricName string, value valuestore.ResultValue, tags []string, forcedType string, options checkconfig.MetricsConfigOption, extractValuePattern *regexp.Regexp, unit string) {
 	if extractValuePattern != nil {
 		extractedValue, err := value.ExtractStringValue(extractValuePattern)
 		if err != nil {
@@ -127,20 +142,20 @@ func (ms *MetricSender) sendMetric(metricName string, value valuestore.ResultVal
 
 	switch forcedType {
 	case "gauge":
-		ms.Gauge(metricFullName, floatValue, tags)
+		ms.submit(metrics.GaugeType, metricFullName, floatValue, tags, unit)
 		ms.submittedMetrics++
 	case "counter":
-		ms.Rate(metricFullName, floatValue, tags)
+		ms.submit(metrics.RateType, metricFullName, floatValue, tags, unit)
 		ms.submittedMetrics++
 	case "percent":
-		ms.Rate(metricFullName, floatValue*100, tags)
+		ms.submit(metrics.RateType, metricFullName, floatValue*100, tags, unit)
 		ms.submittedMetrics++
 	case "monotonic_count":
-		ms.MonotonicCount(metricFullName, floatValue, tags)
+		ms.submit(metrics.MonotonicCountType, metricFullName, floatValue, tags, unit)
 		ms.submittedMetrics++
 	case "monotonic_count_and_rate":
-		ms.MonotonicCount(metricFullName, floatValue, tags)
-		ms.Rate(metricFullName+".rate", floatValue, tags)
+		ms.submit(metrics.MonotonicCountType, metricFullName, floatValue, tags, unit)
+		ms.submit(metrics.RateType, metricFullName+".rate", floatValue, tags, unit)
 		ms.submittedMetrics += 2
 	default:
 		log.Debugf("metric `%s`: unsupported forcedType: %s", metricFullName, forcedType)
@@ -148,6 +163,29 @@ func (ms *MetricSender) sendMetric(metricName string, value valuestore.ResultVal
 	}
 }
 
+// submit sends a metric of the given type, along with its unit when the profile declares it
+func (ms *MetricSender) submit(mType metrics.MetricType, metric string, value float64, tags []string, unit string) {
+	switch {
+	case unit != "":
+		// we need copy tags before using Sender due to https://github.com/DataDog/datadog-agent/issues/7159
+		metadata, ok := ms.unitsMetadata[unit]
+		if !ok {
+			if ms.unitsMetadata == nil {
+				ms.unitsMetadata = make(map[string]*metrics.SerieMetadata)
+			}
+			metadata = &metrics.SerieMetadata{Unit: unit}
+			ms.unitsMetadata[unit] = metadata
+		}
+		ms.sender.MetricWithMetadata(mType, metric, value, ms.hostname, common.CopyStrings(tags), metadata)
+	case mType == metrics.RateType:
+		ms.Rate(metric, value, tags)
+	case mType == metrics.MonotonicCountType:
+		ms.MonotonicCount(metric, value, tags)
+	default:
+		ms.Gauge(metric, value, tags)
+	}
+}
+
 // Gauge wraps Sender.Gauge
 func (ms *MetricSender) Gauge(metric string, value float64, tags []string) {
 	// we need copy tags before using Sender due to https://github.com/DataDog/datadog-agent/issues/7159
@@ -172,6 +210,12 @@ func (ms *MetricSender) ServiceCheck(checkName string, status metrics.ServiceChe
 	ms.sender.ServiceCheck(checkName, status, ms.hostname, common.CopyStrings(tags), message)
 }
 
+// ServiceCheckWithData wraps Sender.ServiceCheckWithData
+func (ms *MetricSender) ServiceCheckWithData(checkName string, status metrics.ServiceCheckStatus, tags []string, message string, data metrics.ServiceCheckData) {
+	// we need copy tags before using Sender due to https://github.com/DataDog/datadog-agent/issues/7159
+	ms.sender.ServiceCheckWithData(checkName, status, ms.hostname, common.CopyStrings(tags), message, data)
+}
+
 // GetSubmittedMetrics returns submitted metrics count
 func (ms *MetricSender) GetSubmittedMetrics() int {
 	return ms.submittedMetrics
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/mock"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
@@ -267,7 +268,7 @@ func TestSendMetric(t *testing.T) {
 			mockSender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 			mockSender.On("Rate", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 
-			metricSender.sendMetric(tt.metricName, tt.value, tt.tags, tt.forcedType, tt.options, tt.extractValuePattern)
+			metricSender.sendMetric(tt.metricName, tt.value, tt.tags, tt.forcedType, tt.options, tt.extractValuePattern, "")
 			assert.Equal(t, tt.expectedSubMetrics, metricSender.submittedMetrics)
 			if tt.expectedMethod != "" {
 				mockSender.AssertCalled(t, tt.expectedMethod, tt.expectedMetricName, tt.expectedValue, "", tt.expectedTags)
@@ -332,6 +333,28 @@ func Test_metricSender_reportMetrics(t *testing.T) {
 	}
 }
 
+func Test_metricSender_reportMetricsUnit(t *testing.T) {
+	mockSender := mocksender.NewMockSender("foo")
+	mockSender.SetupAcceptAll()
+	metricSender := MetricSender{sender: mockSender, hostname: "my-host"}
+
+	metricsConfig := []checkconfig.MetricsConfig{
+		{Symbol: checkconfig.SymbolConfig{OID: "1.2.3", Name: "memoryUsed", Unit: "byte"}},
+		{Symbol: checkconfig.SymbolConfig{OID: "1.2.4", Name: "sysUpTime"}},
+	}
+	values := &valuestore.ResultValueStore{
+		ScalarValues: valuestore.ScalarResultValuesType{
+			"1.2.3": valuestore.ResultValue{Value: float64(1024)},
+			"1.2.4": valuestore.ResultValue{Value: float64(42)},
+		},
+	}
+	metricSender.ReportMetrics(metricsConfig, values, []string{"device:1"})
+
+	mockSender.AssertMetric(t, "Gauge", "snmp.memoryUsed", 1024, "my-host", []string{"device:1"})
+	mockSender.AssertMetricMetadata(t, "snmp.memoryUsed", []string{"device:1"}, metrics.SerieMetadata{Unit: "byte"})
+	mockSender.AssertMetric(t, "Gauge", "snmp.sysUpTime", 42, "my-host", []string{"device:1"})
+}
+
 func Test_metricSender_getCheckInstanceMetricTags(t *testing.T) {
 	type logCount struct {
 		log   string
@@ -0,0 +1,93 @@
+package report
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/common"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/valuestore"
+)
+
+// sendStateMetrics sends a 0/1 gauge per state declared by the profile, tagged with the name of the state, the
+// gauge of the current state of the symbol being 1
+func (ms *MetricSender) sendStateMetrics(metricConfig checkconfig.MetricsConfig, symbol checkconfig.SymbolConfig, value valuestore.ResultValue, tags []string) {
+	metricFullName := getStateName(symbol, metricConfig.Options)
+	strValue, err := getStateValue(symbol, metricConfig.Options, value)
+	if err != nil {
+		log.Debugf("metric `%s`: failed to get state value: %s", metricFullName, err)
+		return
+	}
+	if _, ok := metricConfig.States[strValue]; !ok {
+		log.Debugf("metric `%s`: value `%s` is not mapped to a state", metricFullName, strValue)
+	}
+
+	// several values can be mapped to the same state
+	states := make(map[string]float64, len(metricConfig.States))
+	for stateValue, state := range metricConfig.States {
+		if stateValue == strValue {
+			states[state] = 1
+		} else if _, ok := states[state]; !ok {
+			states[state] = 0
+		}
+	}
+	for state, floatValue := range states {
+		ms.Gauge(metricFullName, floatValue, append(common.CopyStrings(tags), "state:"+state))
+		ms.submittedMetrics++
+	}
+}
+
+// sendServiceCheck sends a service check whose status is mapped from the value of the symbol
+func (ms *MetricSender) sendServiceCheck(metricConfig checkconfig.MetricsConfig, symbol checkconfig.SymbolConfig, value valuestore.ResultValue, tags []string) {
+	serviceCheckName := getStateName(symbol, metricConfig.Options)
+	strValue, err := getStateValue(symbol, metricConfig.Options, value)
+	if err != nil {
+		log.Debugf("service check `%s`: failed to get value: %s", serviceCheckName, err)
+		return
+	}
+
+	status := metricConfig.ServiceCheck.GetStatus(strValue)
+	var message string
+	if status != metrics.ServiceCheckOK {
+		message = fmt.Sprintf("%s is `%s`", symbol.Name, strValue)
+	}
+	ms.ServiceCheck(serviceCheckName, status, tags, message)
+}
+
+// getStateName returns the name of the metric or service check of a symbol, suffixed like the flag stream
+// metrics when a flag of the symbol is reported
+func getStateName(symbol checkconfig.SymbolConfig, options checkconfig.MetricsConfigOption) string {
+	name := "snmp." + symbol.Name
+	if options.Placement > 0 && options.MetricSuffix != "" {
+		name += "." + options.MetricSuffix
+	}
+	return name
+}
+
+// getStateValue returns the value of a symbol as a string, after extracting it with the `extract_value` pattern
+// or, when a placement is set, as the `0` or `1` flag at the placement of a flag stream
+func getStateValue(symbol checkconfig.SymbolConfig, options checkconfig.MetricsConfigOption, value valuestore.ResultValue) (string, error) {
+	if symbol.ExtractValuePattern != nil {
+		extractedValue, err := value.ExtractStringValue(symbol.ExtractValuePattern)
+		if err != nil {
+			return "", err
+		}
+		value = extractedValue
+	}
+
+	strValue, err := value.ToString()
+	if err != nil {
+		return "", err
+	}
+	if options.Placement == 0 {
+		return strValue, nil
+	}
+
+	flag, err := getFlagStreamValue(options.Placement, strValue)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d", int(flag)), nil
+}
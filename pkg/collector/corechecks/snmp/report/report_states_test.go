@@ -0,0 +1,94 @@
+package report
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/valuestore"
+)
+
+func Test_metricSender_reportStates(t *testing.T) {
+	mockSender := mocksender.NewMockSender("foo")
+	mockSender.SetupAcceptAll()
+	metricSender := MetricSender{sender: mockSender, hostname: "my-host"}
+
+	metricsConfig := []checkconfig.MetricsConfig{
+		{
+			Symbols: []checkconfig.SymbolConfig{{OID: "1.3.6.1.2.1.25.3.2.1.5", Name: "hrDeviceStatus"}},
+			MetricTags: []checkconfig.MetricTagConfig{
+				{Tag: "device_index", Index: 1},
+			},
+			ForcedType: "state",
+			States: map[string]string{
+				"2": "running",
+				"3": "warning",
+				"4": "testing",
+				"5": "down",
+				"1": "unknown",
+			},
+		},
+	}
+	values := &valuestore.ResultValueStore{
+		ColumnValues: valuestore.ColumnResultValuesType{
+			"1.3.6.1.2.1.25.3.2.1.5": {
+				"1": valuestore.ResultValue{Value: float64(2)},
+				"2": valuestore.ResultValue{Value: float64(5)},
+			},
+		},
+	}
+	metricSender.ReportMetrics(metricsConfig, values, []string{"device:1"})
+
+	mockSender.AssertMetric(t, "Gauge", "snmp.hrDeviceStatus", 1, "my-host", []string{"device:1", "device_index:1", "state:running"})
+	mockSender.AssertMetric(t, "Gauge", "snmp.hrDeviceStatus", 0, "my-host", []string{"device:1", "device_index:1", "state:down"})
+	mockSender.AssertMetric(t, "Gauge", "snmp.hrDeviceStatus", 0, "my-host", []string{"device:1", "device_index:1", "state:unknown"})
+	mockSender.AssertMetric(t, "Gauge", "snmp.hrDeviceStatus", 1, "my-host", []string{"device:1", "device_index:2", "state:down"})
+	mockSender.AssertMetric(t, "Gauge", "snmp.hrDeviceStatus", 0, "my-host", []string{"device:1", "device_index:2", "state:running"})
+	assert.Equal(t, 10, metricSender.GetSubmittedMetrics())
+}
+
+func Test_metricSender_reportServiceChecks(t *testing.T) {
+	mockSender := mocksender.NewMockSender("foo")
+	mockSender.SetupAcceptAll()
+	metricSender := MetricSender{sender: mockSender, hostname: "my-host"}
+
+	noPaper := checkconfig.MetricsConfig{
+		Symbol:     checkconfig.SymbolConfig{OID: "1.3.6.1.2.1.25.3.5.1.2.1", Name: "hrPrinterDetectedErrorState"},
+		ForcedType: "service_check",
+		Options:    checkconfig.MetricsConfigOption{Placement: 2, MetricSuffix: "noPaper"},
+		ServiceCheck: checkconfig.MetricServiceCheckConfig{
+			StatusMapping: map[string]string{"0": "ok", "1": "critical"},
+		},
+	}
+	lowToner := noPaper
+	lowToner.Options = checkconfig.MetricsConfigOption{Placement: 4, MetricSuffix: "lowToner"}
+	lowToner.ServiceCheck = checkconfig.MetricServiceCheckConfig{
+		StatusMapping: map[string]string{"0": "ok", "1": "warning"},
+	}
+	fanStatus := checkconfig.MetricsConfig{
+		Symbol:     checkconfig.SymbolConfig{OID: "1.3.6.1.4.1.9.9.13.1.4.1.3.1", Name: "ciscoEnvMonFanState"},
+		ForcedType: "service_check",
+		ServiceCheck: checkconfig.MetricServiceCheckConfig{
+			StatusMapping: map[string]string{"1": "ok", "2": "warning", "3": "critical"},
+		},
+	}
+
+	values := &valuestore.ResultValueStore{
+		ScalarValues: valuestore.ScalarResultValuesType{
+			"1.3.6.1.2.1.25.3.5.1.2.1":     valuestore.ResultValue{Value: "01000000"},
+			"1.3.6.1.4.1.9.9.13.1.4.1.3.1": valuestore.ResultValue{Value: float64(6)},
+		},
+	}
+	metricSender.ReportMetrics([]checkconfig.MetricsConfig{noPaper, lowToner, fanStatus}, values, []string{"device:1"})
+
+	mockSender.AssertServiceCheck(t, "snmp.hrPrinterDetectedErrorState.noPaper", metrics.ServiceCheckCritical, "my-host", []string{"device:1"}, "hrPrinterDetectedErrorState is `1`")
+	mockSender.AssertServiceCheck(t, "snmp.hrPrinterDetectedErrorState.lowToner", metrics.ServiceCheckOK, "my-host", []string{"device:1"}, "")
+	// the values that aren't mapped are reported as unknown
+	mockSender.AssertServiceCheck(t, "snmp.ciscoEnvMonFanState", metrics.ServiceCheckUnknown, "my-host", []string{"device:1"}, "ciscoEnvMonFanState is `6`")
+	mockSender.AssertNotCalled(t, "Gauge", "snmp.ciscoEnvMonFanState", 6.0, "my-host", []string{"device:1"})
+	assert.Equal(t, 0, metricSender.GetSubmittedMetrics())
+}
@@ -0,0 +1,249 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+
+	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
+)
+
+var (
+	globalPool     *sessionPool
+	globalPoolOnce sync.Once
+)
+
+// newSession returns a session sharing its connection with the other check
+// instances polling the same device with the same credentials when the session
+// pool is enabled, or a dedicated session otherwise
+func newSession(config *checkconfig.CheckConfig) (Session, error) {
+	if !coreconfig.Datadog.GetBool("snmp_session_pool.enabled") {
+		return NewGosnmpSession(config)
+	}
+	globalPoolOnce.Do(func() {
+		idleTimeout := time.Duration(coreconfig.Datadog.GetInt("snmp_session_pool.idle_timeout")) * time.Second
+		globalPool = newSessionPool(idleTimeout)
+	})
+	return globalPool.newSession(config)
+}
+
+// connectionKey identifies the connections that can be shared: the requests of
+// the instances polling the same device with the same credentials
+type connectionKey struct {
+	ipAddress       string
+	port            uint16
+	snmpVersion     string
+	communityString string
+	user            string
+	authProtocol    string
+	authKey         string
+	privProtocol    string
+	privKey         string
+}
+
+func newConnectionKey(config *checkconfig.CheckConfig) connectionKey {
+	return connectionKey{
+		ipAddress:       config.IPAddress,
+		port:            config.Port,
+		snmpVersion:     config.SnmpVersion,
+		communityString: config.CommunityString,
+		user:            config.User,
+		authProtocol:    config.AuthProtocol,
+		authKey:         config.AuthKey,
+		privProtocol:    config.PrivProtocol,
+		privKey:         config.PrivKey,
+	}
+}
+
+// pooledConnection is a connection shared by the sessions of a connectionKey.
+// It is used by a single session at a time, from Connect to Close, and closed
+// once it has been idle for the idle timeout of the pool.
+type pooledConnection struct {
+	// mu is held by the session using the connection
+	mu        sync.Mutex
+	session   Session
+	connected bool
+	// removed is set once the connection is closed and removed from the pool
+	removed bool
+	// releases is incremented when the connection is released, to ignore the
+	// idle timers of the previous releases
+	releases  uint64
+	idleTimer *time.Timer
+}
+
+// sessionPool shares the connections to the devices between the check instances
+type sessionPool struct {
+	mu          sync.Mutex
+	idleTimeout time.Duration
+	connections map[connectionKey]*pooledConnection
+	// newConnection creates the session of a new connection, it can be replaced in tests
+	newConnection func(config *checkconfig.CheckConfig) (Session, error)
+}
+
+func newSessionPool(idleTimeout time.Duration) *sessionPool {
+	return &sessionPool{
+		idleTimeout:   idleTimeout,
+		connections:   make(map[connectionKey]*pooledConnection),
+		newConnection: NewGosnmpSession,
+	}
+}
+
+func (p *sessionPool) newSession(config *checkconfig.CheckConfig) (Session, error) {
+	// validate the config before the first connection to the device
+	sess, err := NewGosnmpSession(config)
+	if err != nil {
+		return nil, err
+	}
+	return &PooledSession{
+		pool:        p,
+		config:      config,
+		key:         newConnectionKey(config),
+		version:     sess.GetVersion(),
+		contextName: config.ContextName,
+	}, nil
+}
+
+// acquire returns the connection of the key, locked for the caller, once it is
+// released by the other sessions using it
+func (p *sessionPool) acquire(key connectionKey, config *checkconfig.CheckConfig) (*pooledConnection, error) {
+	for {
+		p.mu.Lock()
+		conn, found := p.connections[key]
+		if !found {
+			sess, err := p.newConnection(config)
+			if err != nil {
+				p.mu.Unlock()
+				return nil, err
+			}
+			conn = &pooledConnection{session: sess}
+			p.connections[key] = conn
+		}
+		p.mu.Unlock()
+
+		conn.mu.Lock()
+		if !conn.removed {
+			if conn.idleTimer != nil {
+				conn.idleTimer.Stop()
+			}
+			return conn, nil
+		}
+		// the connection expired while waiting for it
+		conn.mu.Unlock()
+	}
+}
+
+// release unlocks a connection acquired with acquire and schedules its closing
+// if no session acquires it before the idle timeout
+func (p *sessionPool) release(key connectionKey, conn *pooledConnection) {
+	conn.releases++
+	releases := conn.releases
+	conn.idleTimer = time.AfterFunc(p.idleTimeout, func() {
+		p.expire(key, conn, releases)
+	})
+	conn.mu.Unlock()
+}
+
+func (p *sessionPool) expire(key connectionKey, conn *pooledConnection, releases uint64) {
+	conn.mu.Lock()
+	defer conn.mu.Unlock()
+	if conn.removed || conn.releases != releases {
+		// the connection was used since the timer was scheduled
+		return
+	}
+
+	if conn.connected {
+		if err := conn.session.Close(); err != nil {
+			log.Debugf("failed to close idle snmp connection to %s:%d: %v", key.ipAddress, key.port, err)
+		}
+		conn.connected = false
+	}
+	conn.removed = true
+
+	p.mu.Lock()
+	if p.connections[key] == conn {
+		delete(p.connections, key)
+	}
+	p.mu.Unlock()
+}
+
+// PooledSession is a session using a connection of the session pool. The
+// connection is held by the session from Connect to Close, the requests of the
+// other sessions to the same device wait for it to be released.
+type PooledSession struct {
+	pool        *sessionPool
+	config      *checkconfig.CheckConfig
+	key         connectionKey
+	version     gosnmp.SnmpVersion
+	contextName string
+	conn        *pooledConnection
+}
+
+// Connect acquires the connection of the session, and connects it when it isn't
+// already connected
+func (s *PooledSession) Connect() error {
+	conn, err := s.pool.acquire(s.key, s.config)
+	if err != nil {
+		return err
+	}
+
+	if gosnmpSession, ok := conn.session.(*GosnmpSession); ok {
+		// the sessions sharing the connection can have different timeouts
+		gosnmpSession.gosnmpInst.Timeout = time.Duration(s.config.Timeout) * time.Second
+		gosnmpSession.gosnmpInst.Retries = s.config.Retries
+		gosnmpSession.gosnmpInst.ContextEngineID = s.config.ContextEngineID
+	}
+	conn.session.SetContextName(s.contextName)
+
+	if !conn.connected {
+		if err := conn.session.Connect(); err != nil {
+			s.pool.release(s.key, conn)
+			return err
+		}
+		conn.connected = true
+	}
+	s.conn = conn
+	return nil
+}
+
+// Close releases the connection of the session, it is closed once it has been
+// idle for the idle timeout of the pool
+func (s *PooledSession) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	s.pool.release(s.key, s.conn)
+	s.conn = nil
+	return nil
+}
+
+// Get will send a SNMPGET command
+func (s *PooledSession) Get(oids []string) (result *gosnmp.SnmpPacket, err error) {
+	return s.conn.session.Get(oids)
+}
+
+// GetBulk will send a SNMP BULKGET command
+func (s *PooledSession) GetBulk(oids []string, bulkMaxRepetitions uint32) (result *gosnmp.SnmpPacket, err error) {
+	return s.conn.session.GetBulk(oids, bulkMaxRepetitions)
+}
+
+// GetNext will send a SNMP GETNEXT command
+func (s *PooledSession) GetNext(oids []string) (result *gosnmp.SnmpPacket, err error) {
+	return s.conn.session.GetNext(oids)
+}
+
+// GetVersion returns the snmp version used
+func (s *PooledSession) GetVersion() gosnmp.SnmpVersion {
+	return s.version
+}
+
+// SetContextName sets the SNMPv3 context of the next requests
+func (s *PooledSession) SetContextName(contextName string) {
+	s.contextName = contextName
+	if s.conn != nil {
+		s.conn.session.SetContextName(contextName)
+	}
+}
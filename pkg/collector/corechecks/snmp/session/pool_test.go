@@ -0,0 +1,112 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/checkconfig"
+)
+
+type countingSession struct {
+	MockSession
+	connects int
+	closes   int
+}
+
+func (s *countingSession) Connect() error {
+	s.connects++
+	return s.ConnectErr
+}
+
+func (s *countingSession) Close() error {
+	s.closes++
+	return s.CloseErr
+}
+
+func newTestPool(idleTimeout time.Duration) (*sessionPool, *[]*countingSession) {
+	var connections []*countingSession
+	pool := newSessionPool(idleTimeout)
+	pool.newConnection = func(config *checkconfig.CheckConfig) (Session, error) {
+		sess := &countingSession{MockSession: MockSession{Version: gosnmp.Version2c}}
+		connections = append(connections, sess)
+		return sess, nil
+	}
+	return pool, &connections
+}
+
+func Test_sessionPool_sharesConnections(t *testing.T) {
+	pool, connections := newTestPool(time.Hour)
+
+	config := &checkconfig.CheckConfig{IPAddress: "1.2.3.4", Port: 161, CommunityString: "public", ContextName: "a"}
+	otherContextConfig := *config
+	otherContextConfig.ContextName = "b"
+	otherCommunityConfig := *config
+	otherCommunityConfig.CommunityString = "private"
+
+	sess1, err := pool.newSession(config)
+	require.NoError(t, err)
+	sess2, err := pool.newSession(&otherContextConfig)
+	require.NoError(t, err)
+	sess3, err := pool.newSession(&otherCommunityConfig)
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.Version2c, sess1.GetVersion())
+
+	require.NoError(t, sess1.Connect())
+	assert.Equal(t, "a", (*connections)[0].ContextName)
+
+	// the connection is held by sess1 until it is closed
+	connected := make(chan struct{})
+	go func() {
+		assert.NoError(t, sess2.Connect())
+		close(connected)
+	}()
+	select {
+	case <-connected:
+		assert.Fail(t, "the connection should be held by the first session")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, sess1.Close())
+	<-connected
+	assert.Equal(t, "b", (*connections)[0].ContextName)
+	require.NoError(t, sess2.Close())
+
+	// the sessions with other credentials use their own connection
+	require.NoError(t, sess3.Connect())
+	require.NoError(t, sess3.Close())
+
+	require.Len(t, *connections, 2)
+	assert.Equal(t, 1, (*connections)[0].connects)
+	assert.Equal(t, 0, (*connections)[0].closes)
+	assert.Equal(t, 1, (*connections)[1].connects)
+}
+
+func Test_sessionPool_idleTimeout(t *testing.T) {
+	pool, connections := newTestPool(20 * time.Millisecond)
+
+	sess, err := pool.newSession(&checkconfig.CheckConfig{IPAddress: "1.2.3.4", Port: 161, CommunityString: "public"})
+	require.NoError(t, err)
+
+	require.NoError(t, sess.Connect())
+	time.Sleep(50 * time.Millisecond)
+	// the connection isn't closed while it is used
+	assert.Equal(t, 0, (*connections)[0].closes)
+	require.NoError(t, sess.Close())
+
+	assert.Eventually(t, func() bool {
+		pool.mu.Lock()
+		defer pool.mu.Unlock()
+		return len(pool.connections) == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, (*connections)[0].closes)
+
+	// a new connection is opened by the next run
+	require.NoError(t, sess.Connect())
+	require.NoError(t, sess.Close())
+	require.Len(t, *connections, 2)
+	assert.Equal(t, 1, (*connections)[1].connects)
+}
@@ -16,9 +16,9 @@ import (
 
 const sysObjectIDOid = "1.3.6.1.2.1.1.2.0"
 
-// NewSession returns a new session
+// NewSession returns a new session, using a connection of the session pool when it is enabled
 // Can be replaced in tests to use a mock session
-var NewSession = NewGosnmpSession
+var NewSession = newSession
 
 // Session interface for connecting to a snmp device
 type Session interface {
@@ -28,6 +28,7 @@ type Session interface {
 	GetBulk(oids []string, bulkMaxRepetitions uint32) (result *gosnmp.SnmpPacket, err error)
 	GetNext(oids []string) (result *gosnmp.SnmpPacket, err error)
 	GetVersion() gosnmp.SnmpVersion
+	SetContextName(contextName string)
 }
 
 // GosnmpSession is used to connect to a snmp device
@@ -65,6 +66,11 @@ func (s *GosnmpSession) GetVersion() gosnmp.SnmpVersion {
 	return s.gosnmpInst.Version
 }
 
+// SetContextName sets the SNMPv3 context of the next requests
+func (s *GosnmpSession) SetContextName(contextName string) {
+	s.gosnmpInst.ContextName = contextName
+}
+
 // NewGosnmpSession creates a new session
 func NewGosnmpSession(config *checkconfig.CheckConfig) (Session, error) {
 	s := &GosnmpSession{}
@@ -102,6 +108,7 @@ func NewGosnmpSession(config *checkconfig.CheckConfig) (Session, error) {
 
 		s.gosnmpInst.Version = gosnmp.Version3
 		s.gosnmpInst.MsgFlags = msgFlags
+		s.gosnmpInst.ContextEngineID = config.ContextEngineID
 		s.gosnmpInst.ContextName = config.ContextName
 		s.gosnmpInst.SecurityModel = gosnmp.UserSecurityModel
 		s.gosnmpInst.SecurityParameters = &gosnmp.UsmSecurityParameters{
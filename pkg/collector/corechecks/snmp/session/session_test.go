@@ -29,6 +29,7 @@ func Test_snmpSession_Configure(t *testing.T) {
 		expectedRetries            int
 		expectedCommunity          string
 		expectedMsgFlags           gosnmp.SnmpV3MsgFlags
+		expectedContextEngineID    string
 		expectedContextName        string
 		expectedSecurityParameters gosnmp.SnmpV3SecurityParameters
 	}{
@@ -108,24 +109,26 @@ func Test_snmpSession_Configure(t *testing.T) {
 		{
 			name: "valid v3 AuthPriv config",
 			config: checkconfig.CheckConfig{
-				IPAddress:    "1.2.3.4",
-				Port:         uint16(1234),
-				Timeout:      4,
-				Retries:      3,
-				ContextName:  "myContext",
-				User:         "myUser",
-				AuthKey:      "myAuthKey",
-				AuthProtocol: "md5",
-				PrivKey:      "myPrivKey",
-				PrivProtocol: "aes",
+				IPAddress:       "1.2.3.4",
+				Port:            uint16(1234),
+				Timeout:         4,
+				Retries:         3,
+				ContextEngineID: "myContextEngineID",
+				ContextName:     "myContext",
+				User:            "myUser",
+				AuthKey:         "myAuthKey",
+				AuthProtocol:    "md5",
+				PrivKey:         "myPrivKey",
+				PrivProtocol:    "aes",
 			},
-			expectedVersion:     gosnmp.Version3,
-			expectedError:       nil,
-			expectedTimeout:     time.Duration(4) * time.Second,
-			expectedRetries:     3,
-			expectedCommunity:   "",
-			expectedMsgFlags:    gosnmp.AuthPriv,
-			expectedContextName: "myContext",
+			expectedVersion:         gosnmp.Version3,
+			expectedError:           nil,
+			expectedTimeout:         time.Duration(4) * time.Second,
+			expectedRetries:         3,
+			expectedCommunity:       "",
+			expectedMsgFlags:        gosnmp.AuthPriv,
+			expectedContextEngineID: "myContextEngineID",
+			expectedContextName:     "myContext",
 			expectedSecurityParameters: &gosnmp.UsmSecurityParameters{
 				UserName:                 "myUser",
 				AuthenticationProtocol:   gosnmp.MD5,
@@ -215,6 +218,7 @@ func Test_snmpSession_Configure(t *testing.T) {
 				assert.Equal(t, tt.expectedRetries, gosnmpSess.gosnmpInst.Retries)
 				assert.Equal(t, tt.expectedTimeout, gosnmpSess.gosnmpInst.Timeout)
 				assert.Equal(t, tt.expectedCommunity, gosnmpSess.gosnmpInst.Community)
+				assert.Equal(t, tt.expectedContextEngineID, gosnmpSess.gosnmpInst.ContextEngineID)
 				assert.Equal(t, tt.expectedContextName, gosnmpSess.gosnmpInst.ContextName)
 				assert.Equal(t, tt.expectedMsgFlags, gosnmpSess.gosnmpInst.MsgFlags)
 				assert.Equal(t, tt.expectedSecurityParameters, gosnmpSess.gosnmpInst.SecurityParameters)
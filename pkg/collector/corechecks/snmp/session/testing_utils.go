@@ -13,6 +13,8 @@ type MockSession struct {
 	ConnectErr error
 	CloseErr   error
 	Version    gosnmp.SnmpVersion
+	// ContextName is the SNMPv3 context of the mocked requests
+	ContextName string
 }
 
 // Configure configures the session
@@ -53,6 +55,11 @@ func (s *MockSession) GetVersion() gosnmp.SnmpVersion {
 	return s.Version
 }
 
+// SetContextName sets the SNMPv3 context of the next requests
+func (s *MockSession) SetContextName(contextName string) {
+	s.ContextName = contextName
+}
+
 // CreateMockSession creates a mock session
 func CreateMockSession() *MockSession {
 	session := &MockSession{}
@@ -122,6 +122,11 @@ func (c *Check) Configure(rawInstance integration.Data, rawInitConfig integratio
 		return fmt.Errorf("common configure failed: %s", err)
 	}
 
+	// report the counters on the first run following an agent restart
+	if err := c.PersistCounterBaselines(); err != nil {
+		return fmt.Errorf("failed to persist counter baselines: %s", err)
+	}
+
 	if c.config.IsDiscovery() {
 		c.discovery = discovery.NewDiscovery(c.config)
 		c.discovery.Start()
@@ -87,7 +87,7 @@ tags:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 
 	sender.On("Commit").Return()
 
@@ -285,6 +285,7 @@ tags:
 	sender.AssertMetricTaggedWith(t, "MonotonicCount", "datadog.snmp.check_interval", snmpGlobalTagsWithLoader)
 	sender.AssertMetricTaggedWith(t, "Gauge", "datadog.snmp.check_duration", snmpGlobalTagsWithLoader)
 	sender.AssertMetric(t, "Gauge", "datadog.snmp.submitted_metrics", 7, "", snmpGlobalTagsWithLoader)
+	sender.AssertMetric(t, "Gauge", "datadog.snmp.column_fetch_mode", 1, "", append(common.CopyStrings(snmpGlobalTagsWithLoader), "fetch_mode:getbulk"))
 }
 
 func TestSupportedMetricTypes(t *testing.T) {
@@ -396,7 +397,7 @@ profiles:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	packet := gosnmp.SnmpPacket{
@@ -655,7 +656,7 @@ profiles:
 	err = json.Compact(compactEvent, event)
 	assert.NoError(t, err)
 
-	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+	sender.AssertTryEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 
 	sender.AssertServiceCheck(t, "snmp.can_check", metrics.ServiceCheckOK, "", snmpTags, "")
 }
@@ -694,6 +695,11 @@ community_string: public
 	sender.AssertMetricTaggedWith(t, "Gauge", "datadog.snmp.check_duration", snmpTags)
 	sender.AssertMetricTaggedWith(t, "MonotonicCount", "datadog.snmp.check_interval", snmpTags)
 	sender.AssertServiceCheck(t, "snmp.can_check", metrics.ServiceCheckCritical, "", snmpTags, "snmp connection error: can't connect")
+	sender.AssertServiceCheckData(t, "snmp.can_check", metrics.ServiceCheckCritical, metrics.ServiceCheckData{
+		"device_id":  "default:1.2.3.4",
+		"ip_address": "1.2.3.4",
+		"reachable":  false,
+	})
 }
 
 func TestCheckID(t *testing.T) {
@@ -1038,7 +1044,7 @@ tags:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	packet := gosnmp.SnmpPacket{
@@ -1248,7 +1254,7 @@ tags:
 	err = json.Compact(compactEvent, event)
 	assert.NoError(t, err)
 
-	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+	sender.AssertTryEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 
 	sender.AssertServiceCheck(t, "snmp.can_check", metrics.ServiceCheckCritical, "", snmpTags, "failed to autodetect profile: failed to fetch sysobjectid: cannot get sysobjectid: no value")
 }
@@ -1282,7 +1288,7 @@ tags:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	var nilPacket *gosnmp.SnmpPacket
@@ -1340,7 +1346,7 @@ tags:
 	err = json.Compact(compactEvent, event)
 	assert.NoError(t, err)
 
-	sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+	sender.AssertTryEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 
 	sender.AssertServiceCheck(t, "snmp.can_check", metrics.ServiceCheckCritical, "", snmpTags, expectedErrMsg)
 }
@@ -1398,7 +1404,7 @@ metric_tags:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	packet := gosnmp.SnmpPacket{
@@ -1624,7 +1630,7 @@ metric_tags:
 		err = json.Compact(compactEvent, event)
 		assert.NoError(t, err)
 
-		sender.AssertEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
+		sender.AssertTryEventPlatformEvent(t, compactEvent.String(), "network-devices-metadata")
 	}
 	networkTags := []string{"network:10.10.0.0/30", "autodiscovery_subnet:10.10.0.0/30"}
 	sender.AssertMetric(t, "Gauge", "snmp.discovered_devices_count", 4, "", networkTags)
@@ -1745,7 +1751,7 @@ use_device_id_as_hostname: true
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	sess.On("GetNext", []string{"1.3"}).Return(&gosnmplib.MockValidReachableGetNextPacket, nil)
@@ -1910,7 +1916,7 @@ metrics:
 	sender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("MonotonicCount", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
 	sender.On("ServiceCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
-	sender.On("EventPlatformEvent", mock.Anything, mock.Anything).Return()
+	sender.On("TryEventPlatformEvent", mock.Anything, mock.Anything).Return(nil)
 	sender.On("Commit").Return()
 
 	packet := gosnmp.SnmpPacket{
@@ -39,8 +39,11 @@ type IOCheck struct {
 
 // Configure the IOstats check
 func (c *IOCheck) Configure(data integration.Data, initConfig integration.Data, source string) error {
-	err := c.commonConfigure(data, initConfig, source)
-	return err
+	if err := c.commonConfigure(data, initConfig, source); err != nil {
+		return err
+	}
+	// report the rates of the IO counters on the first run following an agent restart
+	return c.PersistCounterBaselines()
 }
 
 // round a float64 with 2 decimal precision
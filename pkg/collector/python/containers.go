@@ -39,7 +39,14 @@ func IsContainerExcluded(name, image, namespace *C.char) C.int {
 		goNs = C.GoString(namespace)
 	}
 
-	if filter.IsExcluded(goName, goImg, goNs) {
+	// the Python API doesn't pass the ID of the container, so the label and
+	// age filters can't match it
+	filterInfo := containers.ContainerFilterInfo{
+		Name:      goName,
+		Image:     goImg,
+		Namespace: goNs,
+	}
+	if filter.IsContainerExcluded(filterInfo) {
 		return 1
 	}
 	return 0
@@ -142,6 +142,16 @@ func AddCheckStats(
 	s.Add(execTime, err, warnings, mStats)
 }
 
+// AddCheckCPUTime adds the CPU time of a run to the check's expvars, the stats of the run
+// must have been added with AddCheckStats first
+func AddCheckCPUTime(checkID check.ID, cpuTime time.Duration) {
+	s, found := CheckStats(checkID)
+	if !found {
+		return
+	}
+	s.AddCPUTime(cpuTime)
+}
+
 // RemoveCheckStats removes a check from the check stats map
 func RemoveCheckStats(checkID check.ID) {
 	checkStats.statsLock.Lock()
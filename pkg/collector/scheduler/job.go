@@ -7,6 +7,7 @@ package scheduler
 
 import (
 	"fmt"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -54,6 +55,9 @@ func (jb *jobBucket) removeJob(id check.ID) bool {
 	return false
 }
 
+// randIntn returns a random number in [0,n), it is replaced in tests
+var randIntn = rand.Intn
+
 // jobQueue contains a list of checks (called jobs) that need to be
 // scheduled at a certain interval.
 type jobQueue struct {
@@ -123,6 +127,38 @@ func (jq *jobQueue) addJob(c check.Check) {
 	jq.schedulingBucketIdx = (jq.schedulingBucketIdx + jq.sparseStep) % uint(len(jq.buckets))
 }
 
+// addScheduledJob adds a check to the bucket matching its scheduling options:
+// - aligned checks go to the bucket running when the wall-clock time is a multiple of the interval
+// - the jitter moves the check to a random bucket up to the jitter after that one
+// Checks without scheduling options are added with the sparse round-robin, see addJob.
+func (jq *jobQueue) addScheduledJob(c check.Check, now time.Time) {
+	opts := check.GetSchedulingOptions(c)
+	if !opts.Align && opts.Jitter < time.Second {
+		jq.addJob(c)
+		return
+	}
+
+	jq.mu.Lock()
+	defer jq.mu.Unlock()
+
+	nb := uint(len(jq.buckets))
+	idx := jq.schedulingBucketIdx
+	if opts.Align {
+		// the current bucket is processed at the next tick, about one second from now
+		nextTick := uint(now.Add(time.Second).Unix() % int64(nb))
+		idx = jq.currentBucketIdx + (nb-nextTick)%nb
+	} else {
+		jq.schedulingBucketIdx = (jq.schedulingBucketIdx + jq.sparseStep) % nb
+	}
+	if jitter := uint(opts.Jitter / time.Second); jitter > 0 {
+		if jitter > nb {
+			jitter = nb
+		}
+		idx += uint(randIntn(int(jitter)))
+	}
+	jq.buckets[idx%nb].addJob(c)
+}
+
 func (jq *jobQueue) removeJob(id check.ID) error {
 	jq.mu.Lock()
 	defer jq.mu.Unlock()
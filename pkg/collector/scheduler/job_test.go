@@ -6,6 +6,7 @@
 package scheduler
 
 import (
+	"math/rand"
 	"runtime"
 	"testing"
 	"time"
@@ -65,3 +66,44 @@ func TestBucket_RemoveJob(t *testing.T) {
 	// use the bucket, just to keep it alive during the earlier GC run
 	bucket.addJob(&TestJobCheck{id: "here so the GC doesn't GC the entire bucket"})
 }
+
+type TestScheduledJobCheck struct {
+	TestJobCheck
+	opts check.SchedulingOptions
+}
+
+func (c *TestScheduledJobCheck) SchedulingOptions() check.SchedulingOptions { return c.opts }
+
+func bucketOf(jq *jobQueue, id check.ID) int {
+	for i, bucket := range jq.buckets {
+		for _, c := range bucket.jobs {
+			if c.ID() == id {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func TestJobQueue_AddScheduledJob(t *testing.T) {
+	defer func() { randIntn = rand.Intn }()
+	randIntn = func(n int) int { return n - 1 }
+
+	jq := newJobQueue(15 * time.Second)
+	jq.currentBucketIdx = 2
+	// the next tick happens at 1001s, the bucket running at 1005s is the aligned one
+	now := time.Unix(1000, 0)
+
+	jq.addScheduledJob(&TestJobCheck{id: "default"}, now)
+	require.Equal(t, 0, bucketOf(jq, "default"))
+
+	jq.addScheduledJob(&TestScheduledJobCheck{TestJobCheck: TestJobCheck{id: "aligned"}, opts: check.SchedulingOptions{Align: true}}, now)
+	require.Equal(t, 6, bucketOf(jq, "aligned"))
+
+	jq.addScheduledJob(&TestScheduledJobCheck{TestJobCheck: TestJobCheck{id: "aligned-jitter"}, opts: check.SchedulingOptions{Align: true, Jitter: 3 * time.Second}}, now)
+	require.Equal(t, 8, bucketOf(jq, "aligned-jitter"))
+
+	// the jitter is added to the round-robin bucket, and can't exceed the interval
+	jq.addScheduledJob(&TestScheduledJobCheck{TestJobCheck: TestJobCheck{id: "jitter"}, opts: check.SchedulingOptions{Jitter: time.Minute}}, now)
+	require.Equal(t, (int(jq.sparseStep)+14)%15, bucketOf(jq, "jitter"))
+}
@@ -102,7 +102,7 @@ func (s *Scheduler) Enter(check check.Check) error {
 		}
 		schedulerQueuesCount.Add(1)
 	}
-	s.jobQueues[check.Interval()].addJob(check)
+	s.jobQueues[check.Interval()].addScheduledJob(check, time.Now())
 
 	// map each check to the Job Queue it was assigned to
 	s.checkToQueueMutex.Lock()
@@ -8,6 +8,7 @@ package worker
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
@@ -142,9 +143,21 @@ func (w *Worker) Run() {
 
 		w.utilizationTracker.CheckStarted(longRunning)
 
-		// Run the check
+		// Run the check. The goroutine is locked to its thread to measure the CPU time of
+		// the run, the CPU time of the goroutines started by the check isn't accounted for.
 		var checkErr error
-		checkErr = check.Run()
+		var cpuTime time.Duration
+		if longRunning {
+			checkErr = check.Run()
+		} else {
+			runtime.LockOSThread()
+			cpuStart, cpuOk := threadCPUTime()
+			checkErr = check.Run()
+			if cpuEnd, ok := threadCPUTime(); ok && cpuOk {
+				cpuTime = cpuEnd - cpuStart
+			}
+			runtime.UnlockOSThread()
+		}
 
 		w.utilizationTracker.CheckFinished()
 
@@ -191,6 +204,9 @@ func (w *Worker) Run() {
 			if w.shouldAddCheckStatsFunc(check.ID()) {
 				sStats, _ := check.GetSenderStats()
 				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
+				if cpuTime > 0 {
+					expvars.AddCheckCPUTime(check.ID(), cpuTime)
+				}
 			}
 		}
 
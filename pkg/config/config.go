@@ -187,6 +187,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cloud_provider_metadata", []string{"aws", "gcp", "azure", "alibaba"})
 	config.SetDefault("proxy", nil)
 	config.BindEnvAndSetDefault("skip_ssl_validation", false)
+	config.BindEnvAndSetDefault("airgapped", false)
+	config.BindEnvAndSetDefault("airgapped_egress_allowlist", []string{})
 	config.BindEnvAndSetDefault("hostname", "")
 	config.BindEnvAndSetDefault("hostname_file", "")
 	config.BindEnvAndSetDefault("tags", []string{})
@@ -202,6 +204,7 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("log_file_max_size", "10Mb")
 	config.BindEnvAndSetDefault("log_file_max_rolls", 1)
 	config.BindEnvAndSetDefault("log_level", "info")
+	config.BindEnvAndSetDefault("log_level_by_module", "")
 	config.BindEnvAndSetDefault("log_to_syslog", false)
 	config.BindEnvAndSetDefault("log_to_console", true)
 	config.BindEnvAndSetDefault("log_format_rfc3339", false)
@@ -224,6 +227,9 @@ func InitConfig(config Config) {
 	config.BindEnv("bind_host")
 	config.BindEnvAndSetDefault("ipc_address", "localhost")
 	config.BindEnvAndSetDefault("health_port", int64(0))
+	config.BindEnvAndSetDefault("watchdog.enabled", false)
+	config.BindEnvAndSetDefault("watchdog.wedged_timeout", 600) // in seconds
+	config.BindEnvAndSetDefault("watchdog.max_restarts", 3)
 	config.BindEnvAndSetDefault("disable_py3_validation", false)
 	config.BindEnvAndSetDefault("python_version", DefaultPython)
 	config.BindEnvAndSetDefault("allow_arbitrary_tags", false)
@@ -270,6 +276,9 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("tracemalloc_blacklist", "") // deprecated
 	config.BindEnvAndSetDefault("run_path", defaultRunPath)
 	config.BindEnvAndSetDefault("no_proxy_nonexact_match", false)
+	// Windows only: use the proxy settings of the system (static proxy, PAC file or auto-detection)
+	// when no proxy is configured for the Agent
+	config.BindEnvAndSetDefault("use_system_proxy", false)
 
 	// Python 3 linter timeout, in seconds
 	// NOTE: linter is notoriously slow, in the absence of a better solution we
@@ -305,6 +314,9 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("secret_backend_command_allow_group_exec_perm", false)
 	config.BindEnvAndSetDefault("secret_backend_skip_checks", false)
 
+	// Expansion of the ${VAR:-default} references in the configuration files
+	config.BindEnvAndSetDefault("config_env_var_expansion", false)
+
 	// Use to output logs in JSON format
 	config.BindEnvAndSetDefault("log_format_json", false)
 
@@ -314,6 +326,9 @@ func InitConfig(config Config) {
 	// Use to force client side TLS version to 1.2
 	config.BindEnvAndSetDefault("force_tls_12", false)
 
+	// FIPS mode, restricts TLS to approved versions and cipher suites
+	config.BindEnvAndSetDefault("fips.enabled", false)
+
 	// Defaults to safe YAML methods in base and custom checks.
 	config.BindEnvAndSetDefault("disable_unsafe_yaml", true)
 
@@ -364,7 +379,27 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("histogram_aggregates", []string{"max", "median", "avg", "count"})
 	config.BindEnvAndSetDefault("histogram_percentiles", []string{"0.95"})
 	config.BindEnvAndSetDefault("aggregator_stop_timeout", 2)
+	// Deadline in seconds of the final flush and forwarder drain on shutdown, 0 disables it
+	config.BindEnvAndSetDefault("shutdown_flush_deadline", 0)
 	config.BindEnvAndSetDefault("aggregator_buffer_size", 100)
+	// Flush intervals in seconds of each type of data, 0 uses the main flush interval
+	config.BindEnvAndSetDefault("aggregator_flush_intervals.series", 0)
+	config.BindEnvAndSetDefault("aggregator_flush_intervals.sketches", 0)
+	config.BindEnvAndSetDefault("aggregator_flush_intervals.service_checks", 0)
+	config.BindEnvAndSetDefault("aggregator_flush_intervals.events", 0)
+	// Interval in seconds of the count of the tag values of the live contexts, 0 disables it
+	config.BindEnvAndSetDefault("aggregator_tag_cardinality.interval", 0)
+	config.BindEnvAndSetDefault("aggregator_tag_cardinality.top", 10)
+	// Guardrails against checks flooding the aggregator, 0 disables them
+	config.BindEnvAndSetDefault("check_sender.max_samples_per_commit", 1000000)
+	config.BindEnvAndSetDefault("check_sender.min_commit_interval", 100*time.Millisecond)
+	// Persistence of the counter baselines of the checks across restarts, in the run path
+	config.BindEnvAndSetDefault("check_cache.enabled", false)
+	config.BindEnvAndSetDefault("check_cache.max_age", 15*time.Minute)
+	// Correction of the timestamps generated by the aggregator by the clock offset measured by the ntp check
+	config.BindEnvAndSetDefault("clock_offset_correction.enabled", false)
+	config.BindEnvAndSetDefault("clock_offset_correction.min_offset", 1*time.Second)
+	config.BindEnvAndSetDefault("clock_offset_correction.max_offset", 1*time.Hour)
 	config.BindEnvAndSetDefault("basic_telemetry_add_container_tags", false) // configure adding the agent container tags to the basic agent telemetry metrics (e.g. `datadog.agent.running`)
 	// Serializer
 	config.BindEnvAndSetDefault("enable_stream_payload_serialization", true)
@@ -376,6 +411,9 @@ func InitConfig(config Config) {
 	// Warning: do not change the two following values. Your payloads will get dropped by Datadog's intake.
 	config.BindEnvAndSetDefault("serializer_max_payload_size", 2*megaByte+megaByte/2)
 	config.BindEnvAndSetDefault("serializer_max_uncompressed_payload_size", 4*megaByte)
+	// Payloads bigger than this size are written to a temporary file instead of being kept in memory (0 disables it)
+	config.BindEnvAndSetDefault("serializer_max_in_memory_payload_size", 0)
+	config.BindEnvAndSetDefault("serializer_payloads_path", "")
 
 	config.BindEnvAndSetDefault("use_v2_api.events", false)
 	config.BindEnvAndSetDefault("use_v2_api.service_checks", false)
@@ -389,12 +427,22 @@ func InitConfig(config Config) {
 	// Forwarder
 	config.BindEnvAndSetDefault("additional_endpoints", map[string][]string{})
 	config.BindEnvAndSetDefault("forwarder_timeout", 20)
-	config.BindEnv("forwarder_retry_queue_max_size")                                                     // Deprecated in favor of `forwarder_retry_queue_payloads_max_size`
-	config.BindEnv("forwarder_retry_queue_payloads_max_size")                                            // Default value is defined inside `NewOptions` in pkg/forwarder/forwarder.go
+	config.BindEnv("forwarder_retry_queue_max_size")          // Deprecated in favor of `forwarder_retry_queue_payloads_max_size`
+	config.BindEnv("forwarder_retry_queue_payloads_max_size") // Default value is defined inside `NewOptions` in pkg/forwarder/forwarder.go
+	config.BindEnvAndSetDefault("forwarder_retry_queue_drop_policy_by_priority", map[string]string{})
 	config.BindEnvAndSetDefault("forwarder_connection_reset_interval", 0)                                // in seconds, 0 means disabled
 	config.BindEnvAndSetDefault("forwarder_apikey_validation_interval", DefaultAPIKeyValidationInterval) // in minutes
 	config.BindEnvAndSetDefault("forwarder_num_workers", 1)
 	config.BindEnvAndSetDefault("forwarder_stop_timeout", 2)
+	// Routing of the data of other organizations, see GetRoutingRules
+	config.BindEnv("routing_rules")
+	config.SetEnvKeyTransformer("routing_rules", func(in string) interface{} {
+		var rules []RoutingRule
+		if err := json.Unmarshal([]byte(in), &rules); err != nil {
+			log.Errorf(`"routing_rules" can not be parsed: %v`, err)
+		}
+		return rules
+	})
 	// Forwarder retry settings
 	config.BindEnvAndSetDefault("forwarder_backoff_factor", 2)
 	config.BindEnvAndSetDefault("forwarder_backoff_base", 2)
@@ -445,6 +493,8 @@ func InitConfig(config Config) {
 	// contexts will be deleted (see 'dogstatsd_expiry_seconds').
 	config.BindEnvAndSetDefault("dogstatsd_context_expiry_seconds", 300)
 	config.BindEnvAndSetDefault("dogstatsd_origin_detection", false) // Only supported for socket traffic
+	// Number of UDS clients (PID and container) whose traffic is tracked, requires origin detection
+	config.BindEnvAndSetDefault("dogstatsd_uds_client_stats_max_clients", 1000)
 	config.BindEnvAndSetDefault("dogstatsd_so_rcvbuf", 0)
 	config.BindEnvAndSetDefault("dogstatsd_metrics_stats_enable", false)
 	config.BindEnvAndSetDefault("dogstatsd_tags", []string{})
@@ -474,8 +524,15 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("statsd_metric_namespace", "")
 	config.BindEnvAndSetDefault("statsd_metric_namespace_blacklist", StandardStatsdPrefixes)
 	config.BindEnvAndSetDefault("statsd_metric_blocklist", []string{})
+	// Rewriting rules of the names of the dogstatsd metrics, applied before the mapper and the namespace
+	config.BindEnvAndSetDefault("dogstatsd_metric_rewrite.strip_prefixes", []string{})
+	config.BindEnvAndSetDefault("dogstatsd_metric_rewrite.lowercase", false)
+	config.BindEnvAndSetDefault("dogstatsd_metric_rewrite.deny", []string{})
+	config.BindEnvAndSetDefault("dogstatsd_metric_rewrite.add_prefix", "")
 	// Autoconfig
 	config.BindEnvAndSetDefault("autoconf_template_dir", "/datadog/check_configs")
+	config.BindEnvAndSetDefault("autoconf_config_files_poll", false)
+	config.BindEnvAndSetDefault("autoconf_config_files_poll_interval", 60) // in seconds
 	config.BindEnvAndSetDefault("exclude_pause_container", true)
 	config.BindEnvAndSetDefault("ac_include", []string{})
 	config.BindEnvAndSetDefault("ac_exclude", []string{})
@@ -503,9 +560,13 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("docker_query_timeout", int64(5))
 	config.BindEnvAndSetDefault("docker_labels_as_tags", map[string]string{})
 	config.BindEnvAndSetDefault("docker_env_as_tags", map[string]string{})
+	config.BindEnvAndSetDefault("container_check_docker_compatibility", false)
 	config.BindEnvAndSetDefault("kubernetes_pod_labels_as_tags", map[string]string{})
 	config.BindEnvAndSetDefault("kubernetes_pod_annotations_as_tags", map[string]string{})
 	config.BindEnvAndSetDefault("kubernetes_node_labels_as_tags", map[string]string{})
+	config.BindEnvAndSetDefault("kubernetes_node_labels_tags_allowlist", []string{"topology.kubernetes.io/zone", "topology.kubernetes.io/region", "node.kubernetes.io/instance-type"})
+	config.BindEnvAndSetDefault("kubernetes_node_taints_tags_allowlist", []string{})
+	config.BindEnvAndSetDefault("kubernetes_node_tags_refresh_interval", 300) // in seconds, 0 disables the refresh
 	config.BindEnvAndSetDefault("kubernetes_namespace_labels_as_tags", map[string]string{})
 	config.BindEnvAndSetDefault("container_cgroup_prefix", "")
 
@@ -573,6 +634,9 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("snmp_traps_config.bind_host", "localhost")
 	config.BindEnvAndSetDefault("snmp_traps_config.stop_timeout", 5) // in seconds
 
+	config.BindEnvAndSetDefault("snmp_session_pool.enabled", false)
+	config.BindEnvAndSetDefault("snmp_session_pool.idle_timeout", 60) // in seconds
+
 	// Kube ApiServer
 	config.BindEnvAndSetDefault("kubernetes_kubeconfig_path", "")
 	config.BindEnvAndSetDefault("kubernetes_apiserver_ca_path", "")
@@ -588,9 +652,24 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cluster_agent.url", "")
 	config.BindEnvAndSetDefault("cluster_agent.kubernetes_service_name", "datadog-cluster-agent")
 	config.BindEnvAndSetDefault("cluster_agent.tagging_fallback", false)
+	config.BindEnvAndSetDefault("cluster_agent.client.cache_ttl_seconds", 10)
+	config.BindEnvAndSetDefault("cluster_agent.client.hedging_delay_ms", 500)
+	config.BindEnvAndSetDefault("cluster_agent.client.load_shedding.latency_threshold_ms", 1000)
+	config.BindEnvAndSetDefault("cluster_agent.client.load_shedding.cooldown_seconds", 30)
 	config.BindEnvAndSetDefault("cluster_agent.server.read_timeout_seconds", 2)
 	config.BindEnvAndSetDefault("cluster_agent.server.write_timeout_seconds", 2)
 	config.BindEnvAndSetDefault("cluster_agent.server.idle_timeout_seconds", 60)
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.enabled", false)
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.backend", "configmap")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.refresh_interval", 60)
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.history_size", 10)
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.configmap.name", "datadog-check-templates")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.configmap.namespace", "")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.git.repository_url", "")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.git.ref", "HEAD")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.git.path", "")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.git.cache_path", "")
+	config.BindEnvAndSetDefault("cluster_agent.check_templates.version", "") // pinned version on the node-agents
 	config.BindEnvAndSetDefault("metrics_port", "5000")
 
 	// Metadata endpoints
@@ -624,6 +703,12 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("gce_send_project_id_tag", false)
 	config.BindEnvAndSetDefault("gce_metadata_timeout", 1000) // value in milliseconds
 
+	// Oracle Cloud
+	config.BindEnvAndSetDefault("collect_oracle_tags", true)
+
+	// IBM Cloud
+	config.BindEnvAndSetDefault("collect_ibm_tags", true)
+
 	// Cloud Foundry
 	config.BindEnvAndSetDefault("cloud_foundry", false)
 	config.BindEnvAndSetDefault("bosh_id", "")
@@ -742,6 +827,10 @@ func InitConfig(config Config) {
 	bindEnvAndSetLogsConfigKeys(config, "database_monitoring.metrics.")
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.metadata.")
 	config.BindEnvAndSetDefault("network_devices.namespace", "default")
+	config.BindEnvAndSetDefault("network_devices.discovery.cluster_agent_leases", false)
+	// Number of events a single source (e.g. a check instance) can send per event type over each quota window, 0 means unlimited
+	config.BindEnvAndSetDefault("event_platform_source_quota", 0)
+	config.BindEnvAndSetDefault("event_platform_source_quota_window", 15) // Seconds
 
 	config.BindEnvAndSetDefault("logs_config.dd_port", 10516)
 	config.BindEnvAndSetDefault("logs_config.dev_mode_use_proto", true)
@@ -798,6 +887,7 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("external_metrics_provider.rollup", 30)                   // Bucket size to circumvent time aggregation side effects.
 	config.BindEnvAndSetDefault("external_metrics_provider.wpa_controller", false)        // Activates the controller for Watermark Pod Autoscalers.
 	config.BindEnvAndSetDefault("external_metrics_provider.use_datadogmetric_crd", false) // Use DatadogMetric CRD with custom Datadog Queries instead of ConfigMap
+	config.BindEnvAndSetDefault("external_metrics_provider.audit_events", false)          // Emit an event when an external metric crosses the threshold of an autoscaler
 	config.BindEnvAndSetDefault("kubernetes_event_collection_timeout", 100)               // timeout between two successful event collections in milliseconds.
 	config.BindEnvAndSetDefault("kubernetes_informers_resync_period", 60*5)               // value in seconds. Default to 5 minutes
 	config.BindEnvAndSetDefault("external_metrics_provider.config", map[string]string{})  // list of options that can be used to configure the external metrics server
@@ -810,6 +900,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("cluster_checks.extra_tags", []string{})
 	config.BindEnvAndSetDefault("cluster_checks.advanced_dispatching_enabled", false)
 	config.BindEnvAndSetDefault("cluster_checks.clc_runners_port", 5005)
+	config.BindEnvAndSetDefault("cluster_checks.endpoint_slices_enabled", false)    // source endpoint checks from EndpointSlices instead of Endpoints
+	config.BindEnvAndSetDefault("cluster_checks.endpoints_failover_enabled", false) // dispatch the endpoint checks of nodes without agent to the cluster check runners
 	// Cluster check runner
 	config.BindEnvAndSetDefault("clc_runner_enabled", false)
 	config.BindEnvAndSetDefault("clc_runner_id", "")
@@ -847,6 +939,16 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("telemetry.dogstatsd.aggregator_channel_latency_buckets", []string{})
 	// The histogram buckets use to track the time in nanoseconds it takes for a DogStatsD listeners to push data to the server
 	config.BindEnvAndSetDefault("telemetry.dogstatsd.listeners_channel_latency_buckets", []string{})
+	// The histogram buckets use to track the time in nanoseconds spent in each stage of the DogStatsD metrics parsing
+	config.BindEnvAndSetDefault("telemetry.dogstatsd.parser_stage_latency_buckets", []string{})
+	// OpenMetrics exposition of the stable telemetry families on a dedicated port, see docs/agent/telemetry.md
+	config.BindEnvAndSetDefault("telemetry.openmetrics.enabled", false)
+	config.BindEnvAndSetDefault("telemetry.openmetrics.bind_host", "localhost")
+	config.BindEnvAndSetDefault("telemetry.openmetrics.port", 5015)
+	config.BindEnvAndSetDefault("telemetry.openmetrics.tls_cert_file", "")
+	config.BindEnvAndSetDefault("telemetry.openmetrics.tls_key_file", "")
+	// Clients must present a certificate signed by one of the CAs of this file when it is set
+	config.BindEnvAndSetDefault("telemetry.openmetrics.client_ca_file", "")
 
 	// Declare other keys that don't have a default/env var.
 	// Mostly, keys we use IsSet() on, because IsSet always returns true if a key has a default.
@@ -864,9 +966,16 @@ func InitConfig(config Config) {
 	// this option will potentially impact the CPU usage of the agent
 	config.BindEnvAndSetDefault("orchestrator_explorer.container_scrubbing.enabled", true)
 	config.BindEnvAndSetDefault("orchestrator_explorer.custom_sensitive_words", []string{})
+	config.BindEnvAndSetDefault("orchestrator_explorer.manifest_scrubbing.rules", []interface{}{})
 	config.BindEnv("orchestrator_explorer.max_per_message")
 	config.BindEnv("orchestrator_explorer.orchestrator_dd_url")
 	config.BindEnv("orchestrator_explorer.orchestrator_additional_endpoints")
+	config.BindEnv("orchestrator_explorer.api_key")
+	// The orchestrator forwarder inherits the settings of the main forwarder unless they are overridden here
+	config.BindEnv("orchestrator_explorer.forwarder.num_workers")
+	config.BindEnv("orchestrator_explorer.forwarder.retry_queue_payloads_max_size")
+	config.BindEnv("orchestrator_explorer.forwarder.backoff_max")
+	config.BindEnvAndSetDefault("orchestrator_explorer.forwarder.storage_max_size_in_bytes", 0) // 0 means disabled
 
 	// Orchestrator Explorer - process agent
 	// DEPRECATED in favor of `orchestrator_explorer.orchestrator_dd_url` setting. If both are set `orchestrator_explorer.orchestrator_dd_url` will take precedence.
@@ -879,6 +988,8 @@ func InitConfig(config Config) {
 	// Process agent
 	config.SetKnown("process_config.dd_agent_env")
 	config.SetKnown("process_config.enabled")
+	config.SetKnown("process_config.process_collection.enabled")
+	config.SetKnown("process_config.container_collection.enabled")
 	config.SetKnown("process_config.intervals.process_realtime")
 	config.SetKnown("process_config.queue_size")
 	config.SetKnown("process_config.rt_queue_size")
@@ -893,6 +1004,12 @@ func InitConfig(config Config) {
 	config.SetKnown("process_config.custom_sensitive_words")
 	config.SetKnown("process_config.scrub_args")
 	config.SetKnown("process_config.strip_proc_arguments")
+	config.SetKnown("process_config.scrubber.rules")
+	config.SetKnown("process_config.tag_rules")
+	config.BindEnvAndSetDefault("process_config.scrubber.default_rules", true)
+	config.BindEnvAndSetDefault("process_config.scrubber.entropy_detection.enabled", false)
+	config.BindEnvAndSetDefault("process_config.scrubber.entropy_detection.min_length", 20)
+	config.BindEnvAndSetDefault("process_config.scrubber.entropy_detection.threshold", 4.5)
 	config.SetKnown("process_config.windows.args_refresh_interval")
 	config.SetKnown("process_config.windows.add_new_args")
 	config.SetKnown("process_config.windows.use_perf_counters")
@@ -908,6 +1025,16 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("process_config.process_discovery.enabled", false)
 	config.BindEnvAndSetDefault("process_config.process_discovery.interval", 4*time.Hour)
 
+	// Software inventory
+	config.BindEnvAndSetDefault("process_config.software_inventory.enabled", false)
+	config.BindEnvAndSetDefault("process_config.software_inventory.interval", time.Hour)
+
+	// Payload compression with the zstd dictionary of the process payloads
+	config.BindEnvAndSetDefault("process_config.zstd_dictionary.enabled", false)
+
+	// Detection of the systemd service running the processes
+	config.BindEnvAndSetDefault("process_config.systemd_unit.enabled", false)
+
 	// Network
 	config.BindEnv("network.id")
 
@@ -937,11 +1064,17 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("runtime_security_config.map_dentry_resolution_enabled", true)
 	config.BindEnvAndSetDefault("runtime_security_config.dentry_cache_size", 1024)
 	config.BindEnvAndSetDefault("runtime_security_config.policies.dir", DefaultRuntimePoliciesDir)
+	config.BindEnvAndSetDefault("runtime_security_config.policies.bundle_public_key_file", "")
+	config.BindEnvAndSetDefault("runtime_security_config.policies.enforce_signed_bundles", false)
 	config.BindEnvAndSetDefault("runtime_security_config.socket", "/opt/datadog-agent/run/runtime-security.sock")
 	config.BindEnvAndSetDefault("runtime_security_config.enable_approvers", true)
 	config.BindEnvAndSetDefault("runtime_security_config.enable_kernel_filters", true)
 	config.BindEnvAndSetDefault("runtime_security_config.flush_discarder_window", 3)
 	config.BindEnvAndSetDefault("runtime_security_config.syscall_monitor.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.network.domain_resolution.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.confinement_drift.enabled", false)
+	config.BindEnvAndSetDefault("runtime_security_config.user_group_resolution.nss_lookups", false)
+	config.BindEnvAndSetDefault("runtime_security_config.user_group_resolution.cache_ttl", 300)
 	config.BindEnvAndSetDefault("runtime_security_config.events_stats.polling_interval", 20)
 	config.BindEnvAndSetDefault("runtime_security_config.events_stats.tags_cardinality", "high")
 	config.BindEnvAndSetDefault("runtime_security_config.run_path", defaultRunPath)
@@ -955,6 +1088,8 @@ func InitConfig(config Config) {
 	config.BindEnvAndSetDefault("runtime_security_config.cookie_cache_size", 100)
 	config.BindEnvAndSetDefault("runtime_security_config.agent_monitoring_events", true)
 	config.BindEnvAndSetDefault("runtime_security_config.custom_sensitive_words", []string{})
+	config.BindEnvAndSetDefault("runtime_security_config.event_output.include_fields", []string{})
+	config.BindEnvAndSetDefault("runtime_security_config.event_output.exclude_fields", []string{})
 	config.BindEnvAndSetDefault("runtime_security_config.remote_tagger", true)
 	config.BindEnvAndSetDefault("runtime_security_config.log_patterns", []string{})
 	bindEnvAndSetLogsConfigKeys(config, "runtime_security_config.endpoints.")
@@ -1141,6 +1276,12 @@ func load(config Config, origin string, loadSecret bool) (*Warnings, error) {
 		return &warnings, err
 	}
 
+	if config.GetBool("config_env_var_expansion") {
+		if err := expandConfigEnvVars(config); err != nil {
+			return &warnings, fmt.Errorf("unable to expand the environment variables of the configuration: %v", err)
+		}
+	}
+
 	for _, key := range findUnknownKeys(config) {
 		log.Warnf("Unknown key in config file: %v", key)
 	}
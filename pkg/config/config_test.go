@@ -548,6 +548,12 @@ func TestIsCloudProviderEnabled(t *testing.T) {
 	assert.False(t, IsCloudProviderEnabled("Alibaba"))
 	assert.False(t, IsCloudProviderEnabled("Azure"))
 	assert.False(t, IsCloudProviderEnabled("Tencent"))
+
+	// in air-gapped mode the metadata requests are validated against the egress allowlist
+	Datadog.Set("cloud_provider_metadata", []string{"aws"})
+	Datadog.Set("airgapped", true)
+	defer Datadog.Set("airgapped", false)
+	assert.True(t, IsCloudProviderEnabled("AWS"))
 }
 
 func TestEnvNestedConfig(t *testing.T) {
@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ExpandEnvVars replaces the environment variable references of the string values of a
+// YAML configuration:
+//   - `${VAR}` is replaced by the value of VAR, or by an empty string if VAR is unset
+//   - `${VAR:-default}` is replaced by default if VAR is unset or empty
+//   - `${VAR-default}` is replaced by default if VAR is unset
+//   - `$${VAR}` is the escape hatch, it is replaced by the literal `${VAR}`
+//
+// Like the secrets, the references are expanded in the decoded values, so the keys and the
+// comments are left as is, and an expanded value is always a string. It returns the data as
+// is when no reference was expanded.
+func ExpandEnvVars(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, []byte("${")) {
+		return data, nil
+	}
+
+	var config interface{}
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("could not Unmarshal config: %s", err)
+	}
+
+	expanded := false
+	walkEnvVars(&config, func(str string) string {
+		value, ok := expandEnvVarsString(str)
+		expanded = expanded || ok
+		return value
+	})
+	if !expanded {
+		return data, nil
+	}
+
+	return yaml.Marshal(config)
+}
+
+// expandEnvVarsString replaces the environment variable references of a string value,
+// see ExpandEnvVars. It returns whether a reference was expanded.
+func expandEnvVarsString(str string) (string, bool) {
+	if !strings.Contains(str, "${") {
+		return str, false
+	}
+
+	var res strings.Builder
+	expanded := false
+	for i := 0; i < len(str); i++ {
+		if str[i] != '$' {
+			res.WriteByte(str[i])
+			continue
+		}
+
+		// escaped reference
+		if strings.HasPrefix(str[i:], "$${") {
+			res.WriteString("${")
+			i += 2
+			expanded = true
+			continue
+		}
+
+		if !strings.HasPrefix(str[i:], "${") {
+			res.WriteByte(str[i])
+			continue
+		}
+
+		end := strings.IndexByte(str[i:], '}')
+		if end == -1 {
+			res.WriteByte(str[i])
+			continue
+		}
+
+		value, ok := expandEnvVar(str[i+2 : i+end])
+		if !ok {
+			// not a valid reference, e.g. `${1}` in a regular expression, keep it as is
+			res.WriteByte(str[i])
+			continue
+		}
+		res.WriteString(value)
+		i += end
+		expanded = true
+	}
+
+	return res.String(), expanded
+}
+
+// walkEnvVars calls callback on every string value of a decoded YAML document, and replaces
+// the value by its result
+func walkEnvVars(data *interface{}, callback func(string) string) {
+	switch v := (*data).(type) {
+	case string:
+		*data = callback(v)
+	case map[interface{}]interface{}:
+		for k := range v {
+			value := v[k]
+			walkEnvVars(&value, callback)
+			v[k] = value
+		}
+	case []interface{}:
+		for i := range v {
+			walkEnvVars(&v[i], callback)
+		}
+	}
+}
+
+// expandEnvVar returns the value of a reference, given its content between the braces,
+// and false if it isn't a valid reference
+func expandEnvVar(ref string) (string, bool) {
+	name, defaultValue, defaultIfEmpty, hasDefault := ref, "", false, false
+	if idx := strings.Index(ref, ":-"); idx != -1 {
+		name, defaultValue, defaultIfEmpty, hasDefault = ref[:idx], ref[idx+2:], true, true
+	} else if idx := strings.IndexByte(ref, '-'); idx != -1 {
+		name, defaultValue, hasDefault = ref[:idx], ref[idx+1:], true
+	}
+
+	if !isEnvVarName(name) {
+		return "", false
+	}
+
+	value, found := os.LookupEnv(name)
+	switch {
+	case hasDefault && (!found || (defaultIfEmpty && value == "")):
+		return defaultValue, true
+	case !found:
+		log.Warnf("Environment variable %s referenced in the configuration is not set, it is replaced by an empty string", name)
+	}
+	return value, true
+}
+
+func isEnvVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// expandConfigEnvVars expands the environment variable references of the configuration,
+// see ExpandEnvVars
+func expandConfigEnvVars(config Config) error {
+	yamlConf, err := yaml.Marshal(config.AllSettings())
+	if err != nil {
+		return fmt.Errorf("unable to marshal configuration to YAML to expand the environment variables: %v", err)
+	}
+
+	expandedConf, err := ExpandEnvVars(yamlConf)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(expandedConf, yamlConf) {
+		return nil
+	}
+	return config.MergeConfigOverride(bytes.NewReader(expandedConf))
+}
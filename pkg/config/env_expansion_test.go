@@ -0,0 +1,81 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandEnvVars(t *testing.T) {
+	os.Setenv("DD_TEST_EXPANSION_HOST", "db.local")
+	os.Setenv("DD_TEST_EXPANSION_EMPTY", "")
+	os.Unsetenv("DD_TEST_EXPANSION_UNSET")
+	defer os.Unsetenv("DD_TEST_EXPANSION_HOST")
+	defer os.Unsetenv("DD_TEST_EXPANSION_EMPTY")
+
+	for _, tc := range []struct {
+		input  string
+		output string
+	}{
+		{"host: localhost", "host: localhost"},
+		{"host: ${DD_TEST_EXPANSION_HOST}", "host: db.local\n"},
+		{"url: http://${DD_TEST_EXPANSION_HOST}:${DD_TEST_EXPANSION_UNSET:-5432}/db", "url: http://db.local:5432/db\n"},
+		{"host: ${DD_TEST_EXPANSION_UNSET}", "host: \"\"\n"},
+		{"host: ${DD_TEST_EXPANSION_EMPTY:-localhost}", "host: localhost\n"},
+		{"host: ${DD_TEST_EXPANSION_EMPTY-localhost}", "host: \"\"\n"},
+		{"host: ${DD_TEST_EXPANSION_UNSET-localhost}", "host: localhost\n"},
+		{"host: ${DD_TEST_EXPANSION_HOST:-localhost}", "host: db.local\n"},
+		{"host: $${DD_TEST_EXPANSION_HOST}", "host: ${DD_TEST_EXPANSION_HOST}\n"},
+		{"port: ${DD_TEST_EXPANSION_UNSET:-5432}", "port: \"5432\"\n"},
+		{"hosts:\n- ${DD_TEST_EXPANSION_HOST}\n- tags: [\"host:${DD_TEST_EXPANSION_HOST}\"]", "hosts:\n- db.local\n- tags:\n  - host:db.local\n"},
+		{"password: pa$$word", "password: pa$$word"},
+		{"replacement: ${1}", "replacement: ${1}"},
+		{"pattern: ^${", "pattern: ^${"},
+		// the keys and the comments aren't expanded
+		{"# ${DD_TEST_EXPANSION_HOST}\n${DD_TEST_EXPANSION_HOST}: localhost", "# ${DD_TEST_EXPANSION_HOST}\n${DD_TEST_EXPANSION_HOST}: localhost"},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			output, err := ExpandEnvVars([]byte(tc.input))
+			require.NoError(t, err)
+			assert.Equal(t, tc.output, string(output))
+		})
+	}
+
+	_, err := ExpandEnvVars([]byte("host: ${DD_TEST_EXPANSION_HOST}\n\tinvalid"))
+	assert.Error(t, err)
+}
+
+func TestLoadExpandsEnvVars(t *testing.T) {
+	os.Setenv("DD_TEST_EXPANSION_HOST", "db.local")
+	defer os.Unsetenv("DD_TEST_EXPANSION_HOST")
+
+	dir, err := ioutil.TempDir("", "expansion")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "datadog.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte("hostname: ${DD_TEST_EXPANSION_HOST}\ncmd_port: ${DD_TEST_EXPANSION_UNSET:-5010}\n"), 0644))
+
+	conf := setupConf()
+	conf.SetConfigFile(path)
+	conf.Set("config_env_var_expansion", true)
+	_, err = load(conf, "datadog.yaml", false)
+	require.NoError(t, err)
+	assert.Equal(t, "db.local", conf.GetString("hostname"))
+	assert.Equal(t, 5010, conf.GetInt("cmd_port"))
+
+	// the expansion is disabled by default
+	conf = setupConf()
+	conf.SetConfigFile(path)
+	_, err = load(conf, "datadog.yaml", false)
+	require.NoError(t, err)
+	assert.Equal(t, "${DD_TEST_EXPANSION_HOST}", conf.GetString("hostname"))
+}
@@ -84,7 +84,11 @@ func SetupLogger(loggerName LoggerName, logLevel, logFile, syslogURI string, sys
 	if err != nil {
 		return err
 	}
-	seelogConfig, err = buildLoggerConfig(loggerName, seelogLogLevel, logFile, syslogURI, syslogRFC, logToConsole, jsonFormat)
+	moduleLevels, err := log.ParseModuleLogLevels(Datadog.GetString("log_level_by_module"))
+	if err != nil {
+		return err
+	}
+	seelogConfig, err = buildLoggerConfig(loggerName, seelogMinLogLevel(seelogLogLevel, moduleLevels), logFile, syslogURI, syslogRFC, logToConsole, jsonFormat)
 	if err != nil {
 		return err
 	}
@@ -94,6 +98,7 @@ func SetupLogger(loggerName LoggerName, logLevel, logFile, syslogURI string, sys
 	}
 	_ = seelog.ReplaceLogger(loggerInterface)
 	log.SetupLogger(loggerInterface, seelogLogLevel)
+	_ = log.SetModuleLogLevels(moduleLevels)
 	scrubber.AddStrippedKeys(Datadog.GetStringSlice("flare_stripped_keys"))
 	return nil
 }
@@ -448,8 +453,36 @@ func ChangeLogLevel(level string) error {
 	if err != nil {
 		return err
 	}
+	return reloadLogger(seelogLogLevel, log.GetModuleLogLevels())
+}
+
+// ChangeModuleLogLevels immediately changes the log level overrides per module,
+// given as a list of `module:level` pairs like the `log_level_by_module` setting.
+func ChangeModuleLogLevels(levels string) error {
+	moduleLevels, err := log.ParseModuleLogLevels(levels)
+	if err != nil {
+		return err
+	}
+	level, err := log.GetLogLevel()
+	if err != nil {
+		return err
+	}
+	if err := log.SetModuleLogLevels(moduleLevels); err != nil {
+		return err
+	}
+	return reloadLogger(level.String(), moduleLevels)
+}
+
+// seelogMinLogLevel returns the level of the seelog logger, which must not drop the messages
+// of the modules logging at a lower level than the global one.
+func seelogMinLogLevel(seelogLogLevel string, moduleLevels map[string]seelog.LogLevel) string {
+	level, _ := seelog.LogLevelFromString(seelogLogLevel)
+	return log.MinLogLevel(level, moduleLevels).String()
+}
+
+func reloadLogger(seelogLogLevel string, moduleLevels map[string]seelog.LogLevel) error {
 	// We create a new logger to propagate the new log level everywhere seelog is used (including dependencies)
-	seelogConfig.SetLogLevel(seelogLogLevel)
+	seelogConfig.SetLogLevel(seelogMinLogLevel(seelogLogLevel, moduleLevels))
 	configTemplate, err := seelogConfig.Render()
 	if err != nil {
 		return err
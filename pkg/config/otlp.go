@@ -13,6 +13,21 @@ const (
 	ExperimentalOTLPTracePort      = experimentalOTLPPrefix + ".internal_traces_port"
 	ExperimentalOTLPMetricsEnabled = experimentalOTLPPrefix + ".metrics_enabled"
 	ExperimentalOTLPTracesEnabled  = experimentalOTLPPrefix + ".traces_enabled"
+	// ExperimentalOTLPSpanMetricsEnabled enables the computation of metrics from OTLP spans.
+	ExperimentalOTLPSpanMetricsEnabled = experimentalOTLPPrefix + ".span_metrics_enabled"
+
+	// ExperimentalOTLPSamplingEnabled enables the sampling of OTLP traces before they are sent to the trace Agent.
+	ExperimentalOTLPSamplingEnabled = experimentalOTLPPrefix + ".sampling.enabled"
+	// ExperimentalOTLPSamplingRate is the rate of the traces kept when no sampling rule matches.
+	ExperimentalOTLPSamplingRate = experimentalOTLPPrefix + ".sampling.rate"
+	// ExperimentalOTLPSamplingRules are the sampling rates by service and resource.
+	ExperimentalOTLPSamplingRules = experimentalOTLPPrefix + ".sampling.rules"
+	// ExperimentalOTLPSamplingKeepErrors keeps the traces containing an error.
+	ExperimentalOTLPSamplingKeepErrors = experimentalOTLPPrefix + ".sampling.keep_errors"
+	// ExperimentalOTLPSamplingLatencyThreshold keeps the traces containing a span longer than it, in milliseconds.
+	ExperimentalOTLPSamplingLatencyThreshold = experimentalOTLPPrefix + ".sampling.latency_threshold_ms"
+	// ExperimentalOTLPSamplingDecisionWait is the time the spans are buffered before sampling their trace, in seconds.
+	ExperimentalOTLPSamplingDecisionWait = experimentalOTLPPrefix + ".sampling.decision_wait_seconds"
 )
 
 // SetupOTLP related configuration.
@@ -20,6 +35,13 @@ func SetupOTLP(config Config) {
 	config.BindEnvAndSetDefault(ExperimentalOTLPTracePort, 5003)
 	config.BindEnvAndSetDefault(ExperimentalOTLPMetricsEnabled, true)
 	config.BindEnvAndSetDefault(ExperimentalOTLPTracesEnabled, true)
+	config.BindEnvAndSetDefault(ExperimentalOTLPSpanMetricsEnabled, false)
+	config.BindEnvAndSetDefault(ExperimentalOTLPSamplingEnabled, false)
+	config.BindEnvAndSetDefault(ExperimentalOTLPSamplingRate, 1.0)
+	config.SetKnown(ExperimentalOTLPSamplingRules)
+	config.BindEnvAndSetDefault(ExperimentalOTLPSamplingKeepErrors, true)
+	config.BindEnvAndSetDefault(ExperimentalOTLPSamplingLatencyThreshold, 0)
+	config.BindEnvAndSetDefault(ExperimentalOTLPSamplingDecisionWait, 5)
 	config.BindEnv(ExperimentalOTLPHTTPPort, "DD_OTLP_HTTP_PORT")
 	config.BindEnv(ExperimentalOTLPgRPCPort, "DD_OTLP_GRPC_PORT")
 }
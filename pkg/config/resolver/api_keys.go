@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package resolver
+
+import "sync"
+
+// apiKeySet holds the API keys of a `DomainResolver`, which can be rotated at runtime.
+// It keeps track of the rotated keys so that the transactions created with a
+// previous key are sent with the key replacing it.
+type apiKeySet struct {
+	m    sync.RWMutex
+	keys []string
+	// replacements maps a rotated key to the key replacing it, empty when the key was removed
+	replacements map[string]string
+}
+
+func newAPIKeySet(keys []string) *apiKeySet {
+	return &apiKeySet{
+		keys:         keys,
+		replacements: map[string]string{},
+	}
+}
+
+func (s *apiKeySet) get() []string {
+	s.m.RLock()
+	defer s.m.RUnlock()
+	// the slice is replaced, never modified, by set
+	return s.keys
+}
+
+// set replaces the API keys. The keys are replaced position by position:
+// the i-th previous key is replaced by the i-th new one, the previous keys
+// without a new key at their position are removed.
+func (s *apiKeySet) set(keys []string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	newKeys := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		newKeys[key] = true
+		delete(s.replacements, key)
+	}
+
+	for i, key := range s.keys {
+		if newKeys[key] {
+			continue
+		}
+		replacement := ""
+		if i < len(keys) {
+			replacement = keys[i]
+		}
+		s.replacements[key] = replacement
+		// keys rotated several times are replaced by the latest key
+		for rotated, previous := range s.replacements {
+			if previous == key {
+				s.replacements[rotated] = replacement
+			}
+		}
+	}
+
+	s.keys = append([]string(nil), keys...)
+}
+
+func (s *apiKeySet) resolve(key string) (string, bool) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	replacement, rotated := s.replacements[key]
+	if !rotated {
+		return key, true
+	}
+	return replacement, replacement != ""
+}
@@ -30,6 +30,12 @@ type DomainResolver interface {
 	Resolve(endpoint transaction.Endpoint) (string, DestinationType)
 	// GetAPIKeys returns the list of API Keys associated with this `DomainResolver`
 	GetAPIKeys() []string
+	// SetAPIKeys replaces the API Keys associated with this `DomainResolver`, the i-th
+	// previous key being rotated to the i-th new key
+	SetAPIKeys(apiKeys []string)
+	// ResolveAPIKey returns the API Key to use in place of `apiKey`, which differs when `apiKey`
+	// was rotated, and false when `apiKey` was removed
+	ResolveAPIKey(apiKey string) (string, bool)
 	// GetBaseDomain returns the base domain for this `DomainResolver`
 	GetBaseDomain() string
 	// GetAlternateDomains returns all the domains that can be returned by `Resolve()` minus the base domain
@@ -41,14 +47,14 @@ type DomainResolver interface {
 // SingleDomainResolver will always return the same host
 type SingleDomainResolver struct {
 	domain  string
-	apiKeys []string
+	apiKeys *apiKeySet
 }
 
 // NewSingleDomainResolver creates a SingleDomainResolver with its destination domain & API keys
 func NewSingleDomainResolver(domain string, apiKeys []string) *SingleDomainResolver {
 	return &SingleDomainResolver{
-		domain,
-		apiKeys,
+		domain:  domain,
+		apiKeys: newAPIKeySet(apiKeys),
 	}
 }
 
@@ -73,7 +79,17 @@ func (r *SingleDomainResolver) GetBaseDomain() string {
 
 // GetAPIKeys returns the slice of API keys associated with this SingleDomainResolver
 func (r *SingleDomainResolver) GetAPIKeys() []string {
-	return r.apiKeys
+	return r.apiKeys.get()
+}
+
+// SetAPIKeys replaces the API keys associated with this SingleDomainResolver
+func (r *SingleDomainResolver) SetAPIKeys(apiKeys []string) {
+	r.apiKeys.set(apiKeys)
+}
+
+// ResolveAPIKey returns the API key replacing a rotated API key of this SingleDomainResolver
+func (r *SingleDomainResolver) ResolveAPIKey(apiKey string) (string, bool) {
+	return r.apiKeys.resolve(apiKey)
 }
 
 // SetBaseDomain sets the only destination available for a SingleDomainResolver
@@ -94,7 +110,7 @@ type destination struct {
 // MultiDomainResolver holds a default value and can provide alternate domain for some route
 type MultiDomainResolver struct {
 	baseDomain          string
-	apiKeys             []string
+	apiKeys             *apiKeySet
 	overrides           map[string]destination
 	alternateDomainList []string
 }
@@ -102,16 +118,26 @@ type MultiDomainResolver struct {
 // NewMultiDomainResolver initializes a MultiDomainResolver with its API keys and base destination
 func NewMultiDomainResolver(baseDomain string, apiKeys []string) *MultiDomainResolver {
 	return &MultiDomainResolver{
-		baseDomain,
-		apiKeys,
-		make(map[string]destination),
-		[]string{},
+		baseDomain:          baseDomain,
+		apiKeys:             newAPIKeySet(apiKeys),
+		overrides:           make(map[string]destination),
+		alternateDomainList: []string{},
 	}
 }
 
 // GetAPIKeys returns the slice of API keys associated with this SingleDomainResolver
 func (r *MultiDomainResolver) GetAPIKeys() []string {
-	return r.apiKeys
+	return r.apiKeys.get()
+}
+
+// SetAPIKeys replaces the API keys associated with this MultiDomainResolver
+func (r *MultiDomainResolver) SetAPIKeys(apiKeys []string) {
+	r.apiKeys.set(apiKeys)
+}
+
+// ResolveAPIKey returns the API key replacing a rotated API key of this MultiDomainResolver
+func (r *MultiDomainResolver) ResolveAPIKey(apiKey string) (string, bool) {
+	return r.apiKeys.resolve(apiKey)
 }
 
 // Resolve returns the destiation for a given request endpoint
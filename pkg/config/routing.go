@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RoutingRule sends the series, events, service checks and logs having all its
+// tags to another organization, with its API key, instead of the main one
+type RoutingRule struct {
+	Name   string   `mapstructure:"name" json:"name"`
+	Tags   []string `mapstructure:"tags" json:"tags"`
+	APIKey string   `mapstructure:"api_key" json:"api_key"`
+}
+
+// Match returns whether the tags contain all the tags of the rule
+func (r *RoutingRule) Match(tags []string) bool {
+	for _, ruleTag := range r.Tags {
+		found := false
+		for _, tag := range tags {
+			if tag == ruleTag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchRoutingRule returns the first rule matching the tags, or nil when the
+// data is sent to the main organization
+func MatchRoutingRule(rules []RoutingRule, tags []string) *RoutingRule {
+	for i := range rules {
+		if rules[i].Match(tags) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// GetRoutingRules returns the routing rules of the multi-tenant agents
+func GetRoutingRules() ([]RoutingRule, error) {
+	return getRoutingRulesConfig(Datadog)
+}
+
+func getRoutingRulesConfig(config Config) ([]RoutingRule, error) {
+	var rules []RoutingRule
+	if !config.IsSet("routing_rules") {
+		return nil, nil
+	}
+	if err := config.UnmarshalKey("routing_rules", &rules); err != nil {
+		return nil, fmt.Errorf("could not parse routing_rules: %v", err)
+	}
+
+	names := make(map[string]struct{}, len(rules))
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Name == "" || strings.ContainsAny(rule.Name, ", ") {
+			return nil, fmt.Errorf("routing rule %d: the name is required and can't contain commas or spaces", i)
+		}
+		if _, found := names[rule.Name]; found {
+			return nil, fmt.Errorf("routing rule %s: the name is used by another rule", rule.Name)
+		}
+		names[rule.Name] = struct{}{}
+
+		if len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("routing rule %s: at least one tag is required", rule.Name)
+		}
+		rule.APIKey = SanitizeAPIKey(rule.APIKey)
+		if rule.APIKey == "" {
+			return nil, fmt.Errorf("routing rule %s: the API key is required", rule.Name)
+		}
+	}
+	return rules, nil
+}
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRoutingRules(t *testing.T) {
+	testConfig := setupConfFromYAML(`
+routing_rules:
+  - name: payments
+    tags:
+      - team:payments
+    api_key: " payments-api-key "
+  - name: checkout
+    tags:
+      - team:checkout
+      - env:prod
+    api_key: checkout-api-key
+`)
+	rules, err := getRoutingRulesConfig(testConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []RoutingRule{
+		{Name: "payments", Tags: []string{"team:payments"}, APIKey: "payments-api-key"},
+		{Name: "checkout", Tags: []string{"team:checkout", "env:prod"}, APIKey: "checkout-api-key"},
+	}, rules)
+
+	assert.Nil(t, MatchRoutingRule(rules, []string{"team:checkout", "env:staging"}))
+	assert.Equal(t, "checkout", MatchRoutingRule(rules, []string{"env:prod", "service:web", "team:checkout"}).Name)
+	assert.Equal(t, "payments", MatchRoutingRule(rules, []string{"team:payments"}).Name)
+
+	rules, err = getRoutingRulesConfig(setupConf())
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestGetRoutingRulesInvalid(t *testing.T) {
+	for name, yamlConfig := range map[string]string{
+		"no name": `
+routing_rules:
+  - tags: [team:payments]
+    api_key: payments-api-key
+`,
+		"duplicated name": `
+routing_rules:
+  - name: payments
+    tags: [team:payments]
+    api_key: payments-api-key
+  - name: payments
+    tags: [team:billing]
+    api_key: billing-api-key
+`,
+		"no tags": `
+routing_rules:
+  - name: payments
+    api_key: payments-api-key
+`,
+		"no API key": `
+routing_rules:
+  - name: payments
+    tags: [team:payments]
+`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := getRoutingRulesConfig(setupConfFromYAML(yamlConfig))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package settings
+
+import (
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// LogLevelByModuleRuntimeSetting wraps operations to change the log level of some modules at runtime.
+type LogLevelByModuleRuntimeSetting struct {
+	ConfigKey string
+}
+
+// Description returns the runtime setting's description
+func (l LogLevelByModuleRuntimeSetting) Description() string {
+	return "Set/get the log level overrides per module as a comma separated list of module:level pairs, e.g. 'snmp:debug, forwarder:warn'"
+}
+
+// Hidden returns whether or not this setting is hidden from the list of runtime settings
+func (l LogLevelByModuleRuntimeSetting) Hidden() bool {
+	return false
+}
+
+// Name returns the name of the runtime setting
+func (l LogLevelByModuleRuntimeSetting) Name() string {
+	return "log_level_by_module"
+}
+
+// Get returns the current value of the runtime setting
+func (l LogLevelByModuleRuntimeSetting) Get() (interface{}, error) {
+	return log.FormatModuleLogLevels(log.GetModuleLogLevels()), nil
+}
+
+// Set changes the value of the runtime setting
+func (l LogLevelByModuleRuntimeSetting) Set(v interface{}) error {
+	levels, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("invalid value type for log_level_by_module: %T", v)
+	}
+	err := config.ChangeModuleLogLevels(levels)
+	if err != nil {
+		return err
+	}
+	key := "log_level_by_module"
+	if l.ConfigKey != "" {
+		key = l.ConfigKey
+	}
+	config.Datadog.Set(key, levels)
+	return nil
+}
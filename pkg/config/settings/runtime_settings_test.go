@@ -103,6 +103,36 @@ func TestLogLevel(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestLogLevelByModule(t *testing.T) {
+	cleanRuntimeSetting()
+	config.SetupLogger("TEST", "info", "", "", true, true, true)
+
+	ll := LogLevelByModuleRuntimeSetting{}
+	assert.Equal(t, "log_level_by_module", ll.Name())
+
+	err := ll.Set("snmp:debug, forwarder:WARNING")
+	assert.Nil(t, err)
+
+	v, err := ll.Get()
+	assert.Equal(t, "forwarder:warn, snmp:debug", v)
+	assert.Nil(t, err)
+	assert.Equal(t, "snmp:debug, forwarder:WARNING", config.Datadog.GetString("log_level_by_module"))
+
+	err = ll.Set("snmp:invalid")
+	assert.NotNil(t, err)
+
+	v, err = ll.Get()
+	assert.Equal(t, "forwarder:warn, snmp:debug", v)
+	assert.Nil(t, err)
+
+	err = ll.Set("")
+	assert.Nil(t, err)
+
+	v, err = ll.Get()
+	assert.Equal(t, "", v)
+	assert.Nil(t, err)
+}
+
 func TestProfiling(t *testing.T) {
 	cleanRuntimeSetting()
 	setupConf()
@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package connectivity registers the diagnosis checking that the agent can
+// run and reach Datadog from its environment.
+package connectivity
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	"github.com/DataDog/datadog-agent/pkg/forwarder/endpoints"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/version"
+)
+
+const validateTimeout = 10 * time.Second
+
+func init() {
+	diagnosis.Register("Datadog intake connectivity", diagnoseDatadogConnectivity)
+}
+
+// diagnoseDatadogConnectivity validates every configured API key against its endpoint
+func diagnoseDatadogConnectivity() error {
+	keysPerDomain, err := config.GetMultipleEndpoints()
+	if err != nil {
+		return fmt.Errorf("invalid endpoints configuration: %v", err)
+	}
+
+	transport := httputils.CreateHTTPTransport()
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   validateTimeout,
+	}
+
+	var failures []string
+	for domain, apiKeys := range keysPerDomain {
+		logResolvedProxy(transport, domain)
+		for _, apiKey := range apiKeys {
+			if err := validateAPIKey(client, domain, apiKey); err != nil {
+				failures = append(failures, fmt.Sprintf("%s (api key ending with %s): %v", domain, lastChars(apiKey), err))
+				continue
+			}
+			log.Infof("API key ending with %s is valid for %s", lastChars(apiKey), domain)
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("cannot reach Datadog: %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func validateAPIKey(client *http.Client, domain, apiKey string) error {
+	req, err := http.NewRequest("GET", domain+endpoints.V1ValidateEndpoint.Route, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("DD-API-KEY", apiKey)
+	req.Header.Set("User-Agent", fmt.Sprintf("datadog-agent/%s", version.AgentVersion))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusForbidden:
+		return fmt.Errorf("invalid API key")
+	default:
+		return fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+}
+
+// logResolvedProxy logs the proxy used to reach domain, which may come from
+// the system proxy settings (PAC file...) and differ between domains
+func logResolvedProxy(transport *http.Transport, domain string) {
+	if transport.Proxy == nil {
+		log.Infof("No proxy configured to reach %s", domain)
+		return
+	}
+	req, err := http.NewRequest("GET", domain, nil)
+	if err != nil {
+		return
+	}
+	proxyURL, err := transport.Proxy(req)
+	switch {
+	case err != nil:
+		log.Warnf("Could not resolve the proxy to reach %s: %s", domain, err)
+	case proxyURL == nil:
+		log.Infof("Not using any proxy to reach %s", domain)
+	default:
+		log.Infof("Using proxy %s://%s to reach %s", proxyURL.Scheme, proxyURL.Host, domain)
+	}
+}
+
+func lastChars(apiKey string) string {
+	if len(apiKey) <= 5 {
+		return apiKey
+	}
+	return apiKey[len(apiKey)-5:]
+}
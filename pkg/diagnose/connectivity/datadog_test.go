@@ -0,0 +1,38 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package connectivity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAPIKey(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/validate", r.URL.Path)
+		switch r.Header.Get("DD-API-KEY") {
+		case "valid":
+			w.WriteHeader(http.StatusOK)
+		case "invalid":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer ts.Close()
+
+	assert.NoError(t, validateAPIKey(ts.Client(), ts.URL, "valid"))
+	assert.EqualError(t, validateAPIKey(ts.Client(), ts.URL, "invalid"), "invalid API key")
+	assert.EqualError(t, validateAPIKey(ts.Client(), ts.URL, "other"), "unexpected response code 500")
+}
+
+func TestLastChars(t *testing.T) {
+	assert.Equal(t, "abc", lastChars("abc"))
+	assert.Equal(t, "fghij", lastChars("abcdefghij"))
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package connectivity
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	capSysAdmin = 21
+	capBPF      = 39
+)
+
+// minEBPFKernelVersion is the oldest kernel supported by the eBPF based features of system-probe
+var minEBPFKernelVersion = kernel.VersionCode(4, 4, 0)
+
+func init() {
+	diagnosis.RegisterWhen("Cgroup layout", diagnoseCgroupLayout, isContainerEnvironment)
+	diagnosis.RegisterWhen("eBPF capability", diagnoseEBPF, isEBPFConfigured)
+}
+
+// isContainerEnvironment returns whether the agent runs in a container or collects container metrics
+func isContainerEnvironment() bool {
+	if config.IsContainerized() {
+		return true
+	}
+	for _, feature := range []config.Feature{config.Docker, config.Containerd, config.Cri, config.Kubernetes} {
+		if config.IsFeaturePresent(feature) {
+			return true
+		}
+	}
+	return false
+}
+
+// isEBPFConfigured returns whether a feature of system-probe relying on eBPF is enabled
+func isEBPFConfigured() bool {
+	for _, key := range []string{"system_probe_config.enabled", "network_config.enabled", "runtime_security_config.enabled"} {
+		if config.Datadog.GetBool(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// diagnoseCgroupLayout checks that the cgroup hierarchy used to collect container metrics is visible
+func diagnoseCgroupLayout() error {
+	var hostPrefix string
+	procPath := config.Datadog.GetString("container_proc_root")
+	if strings.HasPrefix(procPath, "/host") {
+		hostPrefix = "/host"
+	}
+
+	layout, err := cgroups.DetectLayout(hostPrefix, procPath)
+	if err != nil {
+		return fmt.Errorf("unable to detect the cgroup layout from %s: %v", procPath, err)
+	}
+	log.Infof("Cgroup %s hierarchy detected", layout)
+	return nil
+}
+
+// diagnoseEBPF checks that the kernel and the capabilities of the agent allow loading eBPF programs
+func diagnoseEBPF() error {
+	kv, err := kernel.HostVersion()
+	if err != nil {
+		return fmt.Errorf("unable to get the kernel version: %v", err)
+	}
+	if kv < minEBPFKernelVersion {
+		return fmt.Errorf("kernel %s is older than the minimum %s required by eBPF features", kv, minEBPFKernelVersion)
+	}
+
+	caps, err := effectiveCapabilities("/proc/self/status")
+	if err != nil {
+		return fmt.Errorf("unable to get the capabilities of the agent: %v", err)
+	}
+	if caps&(1<<capSysAdmin) == 0 && caps&(1<<capBPF) == 0 {
+		return fmt.Errorf("neither CAP_SYS_ADMIN nor CAP_BPF is in the effective capabilities of the agent")
+	}
+	log.Infof("Kernel %s with effective capabilities %#x can load eBPF programs", kv, caps)
+	return nil
+}
+
+// effectiveCapabilities returns the CapEff bitmask of the given /proc/<pid>/status file
+func effectiveCapabilities(statusPath string) (uint64, error) {
+	f, err := os.Open(statusPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "CapEff:") {
+			continue
+		}
+		return strconv.ParseUint(strings.TrimSpace(strings.TrimPrefix(line, "CapEff:")), 16, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("no CapEff entry in %s", statusPath)
+}
@@ -0,0 +1,37 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package connectivity
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectiveCapabilities(t *testing.T) {
+	status := `Name:	agent
+CapInh:	0000000000000000
+CapPrm:	000001ffffffffff
+CapEff:	0000000000200000
+CapBnd:	000001ffffffffff
+`
+	path := filepath.Join(t.TempDir(), "status")
+	require.NoError(t, ioutil.WriteFile(path, []byte(status), 0644))
+
+	caps, err := effectiveCapabilities(path)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1<<capSysAdmin), caps)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("Name:	agent\n"), 0644))
+	_, err = effectiveCapabilities(path)
+	assert.Error(t, err)
+}
@@ -13,6 +13,9 @@ type Catalog map[string]Diagnosis
 // DefaultCatalog holds every compiled-in diagnosis
 var DefaultCatalog = make(Catalog)
 
+// conditions holds the conditions of the diagnoses registered with RegisterWhen
+var conditions = make(map[string]func() bool)
+
 // Register a diagnosis that will be called on diagnose
 func Register(name string, d Diagnosis) {
 	if _, ok := DefaultCatalog[name]; ok {
@@ -21,5 +24,19 @@ func Register(name string, d Diagnosis) {
 	DefaultCatalog[name] = d
 }
 
+// RegisterWhen registers a diagnosis that is only called on diagnose when the condition is met,
+// e.g. when the feature it checks is configured. The condition is evaluated once the configuration
+// is loaded, when the diagnoses are run.
+func RegisterWhen(name string, d Diagnosis, condition func() bool) {
+	Register(name, d)
+	conditions[name] = condition
+}
+
+// Applies returns whether the diagnosis registered with the given name must be called on diagnose
+func Applies(name string) bool {
+	condition, ok := conditions[name]
+	return !ok || condition()
+}
+
 // Diagnosis should return an error to report its health
 type Diagnosis func() error
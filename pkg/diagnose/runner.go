@@ -6,9 +6,12 @@
 package diagnose
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"sort"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -19,6 +22,24 @@ import (
 
 // RunAll runs all registered connectivity checks, output it in writer
 func RunAll(w io.Writer) error {
+	_, err := runAll(w)
+	return err
+}
+
+// RunAllAndCheck runs all registered connectivity checks, output it in writer.
+// It returns ErrDiagnosisFailed when at least one of them failed.
+func RunAllAndCheck(w io.Writer) error {
+	passed, err := runAll(w)
+	if err != nil {
+		return err
+	}
+	if !passed {
+		return ErrDiagnosisFailed
+	}
+	return nil
+}
+
+func runAll(w io.Writer) (bool, error) {
 	if w != color.Output {
 		color.NoColor = true
 	}
@@ -26,27 +47,87 @@ func RunAll(w io.Writer) error {
 	// Use temporarily a custom logger to our Writer
 	customLogger, err := seelog.LoggerFromWriterWithMinLevelAndFormat(w, seelog.DebugLvl, "[%LEVEL] %FuncShort: %Msg - %Ns%n")
 	if err != nil {
-		return err
+		return false, err
 	}
 	log.RegisterAdditionalLogger("diagnose", customLogger)
 	defer log.UnregisterAdditionalLogger("diagnose")
 
-	var sortedDiagnosis []string
-	for name := range diagnosis.DefaultCatalog {
-		sortedDiagnosis = append(sortedDiagnosis, name)
-	}
-	sort.Strings(sortedDiagnosis)
-
-	for _, name := range sortedDiagnosis {
+	passed := true
+	for _, name := range sortedDiagnosisNames() {
 		fmt.Fprintln(w, fmt.Sprintf("=== Running %s diagnosis ===", color.BlueString(name)))
 		err := diagnosis.DefaultCatalog[name]()
 		statusString := color.GreenString("PASS")
 		if err != nil {
+			passed = false
 			statusString = color.RedString("FAIL")
 			log.Infof("diagnosis error for %s: %w", name, err)
 		}
 		fmt.Fprintln(w, fmt.Sprintf("===> %s\n", statusString))
 	}
 
+	return passed, nil
+}
+
+// Result is the outcome of a single diagnosis
+type Result struct {
+	Name       string `json:"name"`
+	Passed     bool   `json:"passed"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// Report is the machine-readable outcome of all the registered diagnosis
+type Report struct {
+	Passed    bool     `json:"passed"`
+	Diagnoses []Result `json:"diagnoses"`
+}
+
+// ErrDiagnosisFailed is returned by RunAllAndCheck and RunAllJSON when at least one diagnosis failed
+var ErrDiagnosisFailed = errors.New("at least one diagnosis failed")
+
+// Run runs all registered diagnosis and returns their results, sorted by name
+func Run() Report {
+	report := Report{Passed: true}
+	for _, name := range sortedDiagnosisNames() {
+		start := time.Now()
+		err := diagnosis.DefaultCatalog[name]()
+		result := Result{
+			Name:       name,
+			Passed:     err == nil,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+		}
+		report.Diagnoses = append(report.Diagnoses, result)
+	}
+	return report
+}
+
+// RunAllJSON runs all registered diagnosis and writes their results as JSON in writer.
+// It returns ErrDiagnosisFailed when at least one of them failed, so that callers can
+// exit with a non-zero status.
+func RunAllJSON(w io.Writer) error {
+	report := Run()
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	if !report.Passed {
+		return ErrDiagnosisFailed
+	}
 	return nil
 }
+
+func sortedDiagnosisNames() []string {
+	var sortedDiagnosis []string
+	for name := range diagnosis.DefaultCatalog {
+		if diagnosis.Applies(name) {
+			sortedDiagnosis = append(sortedDiagnosis, name)
+		}
+	}
+	sort.Strings(sortedDiagnosis)
+	return sortedDiagnosis
+}
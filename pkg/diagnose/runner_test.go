@@ -7,12 +7,14 @@ package diagnose
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunAll(t *testing.T) {
@@ -27,3 +29,39 @@ func TestRunAll(t *testing.T) {
 	assert.Contains(t, result, "=== Running failing diagnosis ===\n===> FAIL")
 	assert.Contains(t, result, "=== Running succeeding diagnosis ===\n===> PASS")
 }
+
+func TestRunAllCondition(t *testing.T) {
+	diagnosis.RegisterWhen("not applicable", func() error { return errors.New("fail") }, func() bool { return false })
+	diagnosis.RegisterWhen("applicable", func() error { return nil }, func() bool { return true })
+	defer delete(diagnosis.DefaultCatalog, "not applicable")
+	defer delete(diagnosis.DefaultCatalog, "applicable")
+
+	w := &bytes.Buffer{}
+	RunAll(w)
+
+	result := w.String()
+	assert.NotContains(t, result, "not applicable")
+	assert.Contains(t, result, "=== Running applicable diagnosis ===\n===> PASS")
+}
+
+func TestRunAllJSON(t *testing.T) {
+	diagnosis.Register("failing", func() error { return errors.New("fail") })
+	diagnosis.Register("succeeding", func() error { return nil })
+
+	w := &bytes.Buffer{}
+	err := RunAllJSON(w)
+	assert.Equal(t, ErrDiagnosisFailed, err)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(w.Bytes(), &report))
+	assert.False(t, report.Passed)
+
+	results := make(map[string]Result)
+	for _, r := range report.Diagnoses {
+		results[r.Name] = r
+	}
+	assert.False(t, results["failing"].Passed)
+	assert.Equal(t, "fail", results["failing"].Error)
+	assert.True(t, results["succeeding"].Passed)
+	assert.Empty(t, results["succeeding"].Error)
+}
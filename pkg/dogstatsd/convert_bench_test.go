@@ -55,3 +55,28 @@ func BenchmarkParseMetric(b *testing.B) {
 func BenchmarkParseMultipleMetric(b *testing.B) {
 	runParseMetricBenchmark(b, true)
 }
+
+func runParseMetricSampleBenchmark(b *testing.B, rawSample []byte) {
+	parser := newParser(newFloat64ListPool())
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		parsed, err := parser.parseMetricSample(rawSample)
+		if err != nil {
+			b.Fatal(err)
+		}
+		benchParsedSample = parsed
+	}
+}
+
+// used to store the result and avoid optimizations
+var benchParsedSample dogstatsdMetricSample
+
+func BenchmarkParseMetricUntagged(b *testing.B) {
+	runParseMetricSampleBenchmark(b, []byte("daemon:666|g"))
+}
+
+func BenchmarkParseMetricTagged(b *testing.B) {
+	runParseMetricSampleBenchmark(b, []byte("daemon:666|g|#sometag1:somevalue1,sometag2:somevalue2"))
+}
@@ -93,6 +93,27 @@ func isMetricBlocklisted(metricName string, metricBlocklist []string) bool {
 	return false
 }
 
+// enrichSampleRate returns the sample rate of the values of a metric. When the client
+// hints that it aggregated more samples than the values it sent (by sampling a
+// reservoir of histogram or distribution values), the values are weighted so that
+// the aggregated count matches the number of samples.
+func enrichSampleRate(ddSample dogstatsdMetricSample) float64 {
+	switch ddSample.metricType {
+	case histogramType, distributionType, timingType:
+	default:
+		return ddSample.sampleRate
+	}
+
+	valuesCount := int64(len(ddSample.values))
+	if valuesCount == 0 {
+		valuesCount = 1
+	}
+	if ddSample.aggregatedCount <= valuesCount {
+		return ddSample.sampleRate
+	}
+	return ddSample.sampleRate * float64(valuesCount) / float64(ddSample.aggregatedCount)
+}
+
 func enrichMetricSample(metricSamples []metrics.MetricSample, ddSample dogstatsdMetricSample, namespace string, excludedNamespaces []string,
 	metricBlocklist []string, defaultHostname string, origin string, entityIDPrecedenceEnabled bool, serverlessMode bool) []metrics.MetricSample {
 	metricName := ddSample.name
@@ -111,6 +132,7 @@ func enrichMetricSample(metricSamples []metrics.MetricSample, ddSample dogstatsd
 	}
 
 	mtype := enrichMetricType(ddSample.metricType)
+	sampleRate := enrichSampleRate(ddSample)
 
 	// if 'ddSample.values' contains values we're enriching a multi-value
 	// dogstatsd message and will create a MetricSample per value. If not
@@ -124,7 +146,7 @@ func enrichMetricSample(metricSamples []metrics.MetricSample, ddSample dogstatsd
 					Tags:        tags,
 					Mtype:       mtype,
 					Value:       ddSample.values[idx],
-					SampleRate:  ddSample.sampleRate,
+					SampleRate:  sampleRate,
 					RawValue:    ddSample.setValue,
 					OriginID:    originID,
 					K8sOriginID: k8sOriginID,
@@ -141,7 +163,7 @@ func enrichMetricSample(metricSamples []metrics.MetricSample, ddSample dogstatsd
 		Tags:        tags,
 		Mtype:       mtype,
 		Value:       ddSample.value,
-		SampleRate:  ddSample.sampleRate,
+		SampleRate:  sampleRate,
 		RawValue:    ddSample.setValue,
 		OriginID:    originID,
 		K8sOriginID: k8sOriginID,
@@ -94,6 +94,35 @@ func TestConvertParseMultiple(t *testing.T) {
 	}
 }
 
+func TestConvertParseAggregationHint(t *testing.T) {
+	// 2 values sampled out of 8 aggregated by the client are each weighted 4 times
+	parsed, err := parseAndEnrichMultipleMetricMessage([]byte("daemon:666:777.5|d|a:8"), "", nil, nil, "default-hostname")
+	assert.NoError(t, err)
+	require.Len(t, parsed, 2)
+	assert.InEpsilon(t, 0.25, parsed[0].SampleRate, epsilon)
+	assert.InEpsilon(t, 0.25, parsed[1].SampleRate, epsilon)
+
+	// the hint is combined with the sample rate of the client
+	parsed, err = parseAndEnrichMultipleMetricMessage([]byte("daemon:666|h|@0.5|a:4"), "", nil, nil, "default-hostname")
+	assert.NoError(t, err)
+	require.Len(t, parsed, 1)
+	assert.InEpsilon(t, 0.125, parsed[0].SampleRate, epsilon)
+
+	// a hint not greater than the number of values doesn't change the sample rate
+	parsed, err = parseAndEnrichMultipleMetricMessage([]byte("daemon:666:777.5|ms|a:2"), "", nil, nil, "default-hostname")
+	assert.NoError(t, err)
+	require.Len(t, parsed, 2)
+	assert.InEpsilon(t, 1.0, parsed[0].SampleRate, epsilon)
+
+	// the hint is ignored for the other metric types, their values are already aggregated
+	for _, metricSymbol := range []string{"g", "c"} {
+		parsed, err = parseAndEnrichMultipleMetricMessage([]byte("daemon:666|"+metricSymbol+"|a:8"), "", nil, nil, "default-hostname")
+		assert.NoError(t, err)
+		require.Len(t, parsed, 1)
+		assert.InEpsilon(t, 1.0, parsed[0].SampleRate, epsilon)
+	}
+}
+
 func TestConvertParseSingle(t *testing.T) {
 	for metricSymbol, metricType := range symbolToType {
 
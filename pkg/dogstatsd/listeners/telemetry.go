@@ -23,6 +23,10 @@ var (
 		nil, "Dogstatsd UDS origin detection error count")
 	tlmUDSPacketsBytes = telemetry.NewCounter("dogstatsd", "uds_packets_bytes",
 		nil, "Dogstatsd UDS packets bytes")
+	tlmUDSClientPackets = telemetry.NewCounter("dogstatsd", "uds_client_packets",
+		[]string{"container_id", "state"}, "Dogstatsd UDS packets count per client container")
+	tlmUDSClientPacketsBytes = telemetry.NewCounter("dogstatsd", "uds_client_packets_bytes",
+		[]string{"container_id"}, "Dogstatsd UDS packets bytes per client container")
 
 	tlmListener            = telemetry.NewHistogramNoOp()
 	defaultListenerBuckets = []float64{300, 500, 1000, 1500, 2000, 2500, 3000, 10000, 20000, 50000}
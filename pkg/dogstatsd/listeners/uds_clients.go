@@ -0,0 +1,213 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package listeners
+
+import (
+	"container/list"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/dogstatsd/packets"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+// udsClientShards is the number of shards of the client tracker, the clients are spread by PID
+// so that the listeners don't contend on a single lock for each packet.
+const udsClientShards = 16
+
+// udsClients tracks the traffic of the clients of the UDS listener,
+// it is only fed when origin detection is enabled as the peer credentials are needed.
+var udsClients = newUDSClientTracker(0, udsClientShards)
+
+// UDSClientStats is the traffic sent by a client of the UDS listener,
+// identified by its PID and the container it runs in.
+type UDSClientStats struct {
+	PID         int       `json:"pid"`
+	ContainerID string    `json:"container_id"`
+	Packets     uint64    `json:"packets"`
+	Bytes       uint64    `json:"bytes"`
+	Errors      uint64    `json:"errors"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+type udsClientKey struct {
+	pid       int
+	container string
+}
+
+// udsClient is a tracked client, its telemetry counters are resolved once
+// so that recording a packet doesn't allocate.
+type udsClient struct {
+	key      udsClientKey
+	stats    UDSClientStats
+	packets  telemetry.SimpleCounter
+	errors   telemetry.SimpleCounter
+	bytesTlm telemetry.SimpleCounter
+}
+
+// udsClientShard holds the clients of a shard ordered from the most to the least recently seen
+type udsClientShard struct {
+	sync.Mutex
+	maxClients int
+	clients    map[udsClientKey]*list.Element
+	lru        *list.List
+}
+
+// udsClientTracker keeps the stats of at most maxClients clients, spread over shards.
+// When a shard is full the client it saw least recently is forgotten.
+type udsClientTracker struct {
+	shards []*udsClientShard
+
+	// containers counts the tracked clients of each container, the container
+	// telemetry is deleted with its last client
+	containersMu sync.Mutex
+	containers   map[string]int
+}
+
+func newUDSClientTracker(maxClients int, shards int) *udsClientTracker {
+	t := &udsClientTracker{
+		shards:     make([]*udsClientShard, shards),
+		containers: make(map[string]int),
+	}
+	for i := range t.shards {
+		t.shards[i] = &udsClientShard{}
+	}
+	t.configure(maxClients)
+	return t
+}
+
+// configure sets the maximum number of tracked clients and resets the stats, 0 disables the tracking
+func (t *udsClientTracker) configure(maxClients int) {
+	// the limit is split between the shards, rounded up so that a small limit still tracks clients
+	perShard := (maxClients + len(t.shards) - 1) / len(t.shards)
+	for _, shard := range t.shards {
+		shard.Lock()
+		shard.maxClients = perShard
+		shard.clients = make(map[udsClientKey]*list.Element)
+		shard.lru = list.New()
+		shard.Unlock()
+	}
+
+	t.containersMu.Lock()
+	t.containers = make(map[string]int)
+	t.containersMu.Unlock()
+}
+
+// record adds a packet sent by a client, failed is true when the packet couldn't be read or its origin resolved
+func (t *udsClientTracker) record(pid int, container string, bytes int, failed bool, now time.Time) {
+	if pid <= 0 {
+		return
+	}
+
+	shard := t.shards[pid%len(t.shards)]
+	shard.Lock()
+	if shard.maxClients <= 0 {
+		shard.Unlock()
+		return
+	}
+	key := udsClientKey{pid: pid, container: container}
+	var client *udsClient
+	if elem, found := shard.clients[key]; found {
+		shard.lru.MoveToFront(elem)
+		client = elem.Value.(*udsClient)
+	} else {
+		if shard.lru.Len() >= shard.maxClients {
+			t.evictOldest(shard)
+		}
+		client = t.newClient(key)
+		shard.clients[key] = shard.lru.PushFront(client)
+	}
+	client.stats.Packets++
+	client.stats.Bytes += uint64(bytes)
+	if failed {
+		client.stats.Errors++
+	}
+	client.stats.LastSeen = now
+	shard.Unlock()
+
+	if failed {
+		client.errors.Inc()
+		return
+	}
+	client.packets.Inc()
+	client.bytesTlm.Add(float64(bytes))
+}
+
+// newClient creates a tracked client and resolves its telemetry counters
+func (t *udsClientTracker) newClient(key udsClientKey) *udsClient {
+	t.containersMu.Lock()
+	t.containers[key.container]++
+	t.containersMu.Unlock()
+
+	container := containerTag(key.container)
+	return &udsClient{
+		key:      key,
+		stats:    UDSClientStats{PID: key.pid, ContainerID: key.container},
+		packets:  tlmUDSClientPackets.WithValues(container, "ok"),
+		errors:   tlmUDSClientPackets.WithValues(container, "error"),
+		bytesTlm: tlmUDSClientPacketsBytes.WithValues(container),
+	}
+}
+
+// evictOldest forgets the client the shard saw least recently, must be called with the shard lock held
+func (t *udsClientTracker) evictOldest(shard *udsClientShard) {
+	elem := shard.lru.Back()
+	if elem == nil {
+		return
+	}
+	oldest := shard.lru.Remove(elem).(*udsClient)
+	delete(shard.clients, oldest.key)
+
+	// the telemetry is per container, it's only deleted with the last client of the container
+	t.containersMu.Lock()
+	t.containers[oldest.key.container]--
+	last := t.containers[oldest.key.container] <= 0
+	if last {
+		delete(t.containers, oldest.key.container)
+	}
+	t.containersMu.Unlock()
+	if !last {
+		return
+	}
+	container := containerTag(oldest.key.container)
+	tlmUDSClientPackets.Delete(container, "ok")
+	tlmUDSClientPackets.Delete(container, "error")
+	tlmUDSClientPacketsBytes.Delete(container)
+}
+
+// stats returns a copy of the stats of the clients, the busiest first
+func (t *udsClientTracker) stats() []UDSClientStats {
+	var stats []UDSClientStats
+	for _, shard := range t.shards {
+		shard.Lock()
+		for elem := shard.lru.Front(); elem != nil; elem = elem.Next() {
+			stats = append(stats, elem.Value.(*udsClient).stats)
+		}
+		shard.Unlock()
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Packets != stats[j].Packets {
+			return stats[i].Packets > stats[j].Packets
+		}
+		return stats[i].PID < stats[j].PID
+	})
+	return stats
+}
+
+// GetUDSClientStats returns the traffic of the clients of the UDS listener, the busiest first.
+// It is empty when origin detection is disabled.
+func GetUDSClientStats() []UDSClientStats {
+	return udsClients.stats()
+}
+
+func containerTag(container string) string {
+	if container == packets.NoOrigin {
+		return "none"
+	}
+	return container
+}
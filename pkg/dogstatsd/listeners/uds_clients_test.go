@@ -0,0 +1,91 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package listeners
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUDSClientTrackerRecord(t *testing.T) {
+	tracker := newUDSClientTracker(10, udsClientShards)
+	now := time.Now()
+
+	tracker.record(42, "container_id://foo", 100, false, now)
+	tracker.record(42, "container_id://foo", 50, false, now.Add(time.Second))
+	tracker.record(42, "container_id://foo", 0, true, now.Add(2*time.Second))
+	tracker.record(1234, "", 10, true, now)
+	// packets without peer credentials can't be attributed
+	tracker.record(0, "", 10, false, now)
+
+	stats := tracker.stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, UDSClientStats{
+		PID:         42,
+		ContainerID: "container_id://foo",
+		Packets:     3,
+		Bytes:       150,
+		Errors:      1,
+		LastSeen:    now.Add(2 * time.Second),
+	}, stats[0])
+	assert.Equal(t, UDSClientStats{
+		PID:      1234,
+		Packets:  1,
+		Bytes:    10,
+		Errors:   1,
+		LastSeen: now,
+	}, stats[1])
+}
+
+func TestUDSClientTrackerEvictsOldest(t *testing.T) {
+	tracker := newUDSClientTracker(2, 1)
+	now := time.Now()
+
+	tracker.record(1, "container_id://a", 10, false, now)
+	tracker.record(2, "container_id://b", 10, false, now.Add(time.Second))
+	tracker.record(1, "container_id://a", 10, false, now.Add(2*time.Second))
+	tracker.record(3, "container_id://c", 10, false, now.Add(3*time.Second))
+
+	stats := tracker.stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, 1, stats[0].PID)
+	assert.Equal(t, uint64(2), stats[0].Packets)
+	assert.Equal(t, 3, stats[1].PID)
+}
+
+func TestUDSClientTrackerDisabled(t *testing.T) {
+	tracker := newUDSClientTracker(0, udsClientShards)
+	tracker.record(42, "container_id://foo", 100, false, time.Now())
+	assert.Empty(t, tracker.stats())
+
+	tracker.configure(5)
+	tracker.record(42, "container_id://foo", 100, false, time.Now())
+	assert.Len(t, tracker.stats(), 1)
+
+	tracker.configure(5)
+	assert.Empty(t, tracker.stats())
+}
+
+func TestUDSClientTrackerShards(t *testing.T) {
+	tracker := newUDSClientTracker(8, 4)
+	now := time.Now()
+
+	// every shard tracks 2 clients, the least recently seen client of a full shard is evicted
+	for pid := 1; pid <= 12; pid++ {
+		tracker.record(pid, "container_id://foo", 10, false, now.Add(time.Duration(pid)*time.Second))
+	}
+
+	stats := tracker.stats()
+	require.Len(t, stats, 8)
+	pids := make([]int, 0, len(stats))
+	for _, s := range stats {
+		pids = append(pids, s.PID)
+	}
+	assert.ElementsMatch(t, []int{5, 6, 7, 8, 9, 10, 11, 12}, pids)
+}
@@ -33,6 +33,10 @@ func init() {
 	udsExpvars.Set("PacketReadingErrors", &udsPacketReadingErrors)
 	udsExpvars.Set("Packets", &udsPackets)
 	udsExpvars.Set("Bytes", &udsBytes)
+	// published apart as the status pages render every dogstatsd-uds value as a number
+	expvar.Publish("dogstatsd-uds-clients", expvar.Func(func() interface{} {
+		return GetUDSClientStats()
+	}))
 }
 
 // UDSListener implements the StatsdListener interface for Unix Domain
@@ -114,6 +118,7 @@ func NewUDSListener(packetOut chan packets.Packets, sharedPacketPoolManager *pac
 
 	// Init the oob buffer pool if origin detection is enabled
 	if originDetection {
+		udsClients.configure(config.Datadog.GetInt("dogstatsd_uds_client_stats_max_clients"))
 
 		pool := &sync.Pool{
 			New: func() interface{} {
@@ -148,6 +153,9 @@ func (l *UDSListener) Listen() {
 		packet := l.sharedPacketPoolManager.Get().(*packets.Packet)
 		udsPackets.Add(1)
 
+		var pid int
+		var container string
+		var taggingErr error
 		var capBuff *replay.CaptureBuffer
 		if l.trafficCapture != nil && l.trafficCapture.IsOngoing() {
 			capBuff = replay.CapPool.Get().(*replay.CaptureBuffer)
@@ -170,7 +178,7 @@ func (l *UDSListener) Listen() {
 			t1 = time.Now()
 
 			// Extract container id from credentials
-			pid, container, taggingErr := processUDSOrigin(oobS[:oobn])
+			pid, container, taggingErr = processUDSOrigin(oobS[:oobn])
 
 			if capBuff != nil {
 				capBuff.Pb.Timestamp = time.Now().UnixNano()
@@ -228,9 +236,11 @@ func (l *UDSListener) Listen() {
 			log.Errorf("dogstatsd-uds: error reading packet: %v", err)
 			udsPacketReadingErrors.Add(1)
 			tlmUDSPackets.Inc("error")
+			udsClients.record(pid, container, n, true, t1)
 			continue
 		}
 		tlmUDSPackets.Inc("ok")
+		udsClients.record(pid, container, n, taggingErr != nil, t1)
 
 		udsBytes.Add(int64(n))
 		tlmUDSPacketsBytes.Add(float64(n))
@@ -126,6 +126,7 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 	}
 
 	sampleRate := 1.0
+	var aggregatedCount int64
 	var tags []string
 	var optionalField []byte
 	for message != nil {
@@ -137,17 +138,23 @@ func (p *parser) parseMetricSample(message []byte) (dogstatsdMetricSample, error
 			if err != nil {
 				return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd sample rate %q", optionalField)
 			}
+		} else if bytes.HasPrefix(optionalField, aggregationHintFieldPrefix) {
+			aggregatedCount, err = parseMetricSampleAggregationHint(optionalField[len(aggregationHintFieldPrefix):])
+			if err != nil {
+				return dogstatsdMetricSample{}, fmt.Errorf("could not parse dogstatsd aggregation hint %q", optionalField)
+			}
 		}
 	}
 
 	return dogstatsdMetricSample{
-		name:       p.interner.LoadOrStore(name),
-		value:      value,
-		values:     values,
-		setValue:   string(setValue),
-		metricType: metricType,
-		sampleRate: sampleRate,
-		tags:       tags,
+		name:            p.interner.LoadOrStore(name),
+		value:           value,
+		values:          values,
+		setValue:        string(setValue),
+		metricType:      metricType,
+		sampleRate:      sampleRate,
+		tags:            tags,
+		aggregatedCount: aggregatedCount,
 	}, nil
 }
 
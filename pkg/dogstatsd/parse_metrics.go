@@ -24,8 +24,9 @@ var (
 	setSymbol          = []byte("s")
 	timingSymbol       = []byte("ms")
 
-	tagsFieldPrefix       = []byte("#")
-	sampleRateFieldPrefix = []byte("@")
+	tagsFieldPrefix            = []byte("#")
+	sampleRateFieldPrefix      = []byte("@")
+	aggregationHintFieldPrefix = []byte("a:")
 )
 
 type dogstatsdMetricSample struct {
@@ -39,6 +40,9 @@ type dogstatsdMetricSample struct {
 	metricType metricType
 	sampleRate float64
 	tags       []string
+	// number of samples aggregated by the client into the values,
+	// 0 when the client didn't send an aggregation hint
+	aggregatedCount int64
 }
 
 // sanity checks a given message against the metric sample format
@@ -47,7 +51,7 @@ func hasMetricSampleFormat(message []byte) bool {
 		return false
 	}
 	separatorCount := bytes.Count(message, fieldSeparator)
-	if separatorCount < 1 || separatorCount > 3 {
+	if separatorCount < 1 || separatorCount > 4 {
 		return false
 	}
 	return true
@@ -87,3 +91,14 @@ func parseMetricSampleMetricType(rawMetricType []byte) (metricType, error) {
 func parseMetricSampleSampleRate(rawSampleRate []byte) (float64, error) {
 	return parseFloat64(rawSampleRate)
 }
+
+func parseMetricSampleAggregationHint(rawAggregationHint []byte) (int64, error) {
+	count, err := parseInt64(rawAggregationHint)
+	if err != nil {
+		return 0, err
+	}
+	if count <= 0 {
+		return 0, fmt.Errorf("invalid aggregated samples count: %d", count)
+	}
+	return count, nil
+}
@@ -268,6 +268,23 @@ func TestParseGaugeWithUnicode(t *testing.T) {
 	assert.InEpsilon(t, 1.0, sample.sampleRate, epsilon)
 }
 
+func TestParseDistributionWithAggregationHint(t *testing.T) {
+	sample, err := parseMetricSample([]byte("daemon:3.5:4.5|d|@0.5|#sometag:value|a:8"))
+
+	assert.NoError(t, err)
+
+	assert.Equal(t, "daemon", sample.name)
+	assert.Len(t, sample.values, 2)
+	assert.Equal(t, distributionType, sample.metricType)
+	assert.Equal(t, []string{"sometag:value"}, sample.tags)
+	assert.InEpsilon(t, 0.5, sample.sampleRate, epsilon)
+	assert.Equal(t, int64(8), sample.aggregatedCount)
+
+	sample, err = parseMetricSample([]byte("daemon:3.5|d"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), sample.aggregatedCount)
+}
+
 func TestParseMetricError(t *testing.T) {
 	// not enough information
 	_, err := parseMetricSample([]byte("daemon:666"))
@@ -300,4 +317,14 @@ func TestParseMetricError(t *testing.T) {
 	// invalid sample rate
 	_, err = parseMetricSample([]byte("daemon:666|g|@abc"))
 	assert.Error(t, err)
+
+	// invalid aggregation hint
+	_, err = parseMetricSample([]byte("daemon:666|d|a:abc"))
+	assert.Error(t, err)
+	_, err = parseMetricSample([]byte("daemon:666|d|a:0"))
+	assert.Error(t, err)
+
+	// too many fields
+	_, err = parseMetricSample([]byte("daemon:666|d|@0.5|#tag|a:2|m:test"))
+	assert.Error(t, err)
 }
@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dogstatsd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// MetricRewriteConfig holds the rewriting rules applied to the names of the metrics
+// received by dogstatsd, before the mapper and the namespace
+type MetricRewriteConfig struct {
+	StripPrefixes []string `json:"strip_prefixes"`
+	Lowercase     bool     `json:"lowercase"`
+	Deny          []string `json:"deny"`
+	AddPrefix     string   `json:"add_prefix"`
+}
+
+// getMetricRewriteConfig returns the rewriting rules of the agent configuration
+func getMetricRewriteConfig() MetricRewriteConfig {
+	return MetricRewriteConfig{
+		StripPrefixes: config.Datadog.GetStringSlice("dogstatsd_metric_rewrite.strip_prefixes"),
+		Lowercase:     config.Datadog.GetBool("dogstatsd_metric_rewrite.lowercase"),
+		Deny:          config.Datadog.GetStringSlice("dogstatsd_metric_rewrite.deny"),
+		AddPrefix:     config.Datadog.GetString("dogstatsd_metric_rewrite.add_prefix"),
+	}
+}
+
+// metricRewriter applies the rewriting rules to the metric names
+type metricRewriter struct {
+	config MetricRewriteConfig
+	deny   []*regexp.Regexp
+}
+
+// newMetricRewriter returns the rewriter of the given rules, or nil if there's
+// no rule to apply
+func newMetricRewriter(cfg MetricRewriteConfig) (*metricRewriter, error) {
+	if len(cfg.StripPrefixes) == 0 && !cfg.Lowercase && len(cfg.Deny) == 0 && cfg.AddPrefix == "" {
+		return nil, nil
+	}
+
+	r := &metricRewriter{config: cfg}
+	for _, expr := range cfg.Deny {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deny regular expression %q: %v", expr, err)
+		}
+		r.deny = append(r.deny, re)
+	}
+	return r, nil
+}
+
+// rewrite returns the rewritten name of a metric, and false if the metric is denied
+func (r *metricRewriter) rewrite(name string) (string, bool) {
+	for _, prefix := range r.config.StripPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			name = name[len(prefix):]
+			break
+		}
+	}
+
+	if r.config.Lowercase {
+		name = strings.ToLower(name)
+	}
+
+	for _, re := range r.deny {
+		if re.MatchString(name) {
+			return "", false
+		}
+	}
+
+	return r.config.AddPrefix + name, true
+}
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package dogstatsd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMetricRewriter(t *testing.T) {
+	rewriter, err := newMetricRewriter(MetricRewriteConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, rewriter)
+
+	rewriter, err = newMetricRewriter(MetricRewriteConfig{Deny: []string{"["}})
+	assert.Error(t, err)
+	assert.Nil(t, rewriter)
+}
+
+func TestMetricRewriterRewrite(t *testing.T) {
+	rewriter, err := newMetricRewriter(MetricRewriteConfig{
+		StripPrefixes: []string{"statsd.", "legacy.statsd."},
+		Lowercase:     true,
+		Deny:          []string{`^tmp\.`, `\.debug$`},
+		AddPrefix:     "app.",
+	})
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		expected string
+		allowed  bool
+	}{
+		{"requests.count", "app.requests.count", true},
+		{"statsd.requests.count", "app.requests.count", true},
+		{"legacy.statsd.Requests.Count", "app.requests.count", true},
+		{"Statsd.requests.count", "app.statsd.requests.count", true},
+		{"statsd.statsd.requests.count", "app.statsd.requests.count", true},
+		{"statsd.TMP.requests", "", false},
+		{"requests.Debug", "", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			name, allowed := rewriter.rewrite(tc.name)
+			assert.Equal(t, tc.allowed, allowed)
+			assert.Equal(t, tc.expected, name)
+		})
+	}
+}
+
+func TestMetricRewriteConfig(t *testing.T) {
+	s, err := NewServer(mockAggregator(), nil)
+	require.NoError(t, err, "starting the DogStatsD server shouldn't fail")
+	defer s.Stop()
+
+	parser := newParser(newFloat64ListPool())
+	samples, err := s.parseMetricMessage(nil, parser, []byte("statsd.tmp.metric:666|g"), "", false)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "statsd.tmp.metric", samples[0].Name)
+
+	cfg := MetricRewriteConfig{StripPrefixes: []string{"statsd."}, Deny: []string{`^tmp\.`}}
+	require.NoError(t, s.SetMetricRewriteConfig(cfg))
+	assert.Equal(t, cfg, s.GetMetricRewriteConfig())
+
+	denied := dogstatsdMetricRewriteDenied.Value()
+	samples, err = s.parseMetricMessage(nil, parser, []byte("statsd.tmp.metric:666|g"), "", false)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+	assert.Equal(t, denied+1, dogstatsdMetricRewriteDenied.Value())
+
+	samples, err = s.parseMetricMessage(nil, parser, []byte("statsd.metric:666|g"), "", false)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "metric", samples[0].Name)
+
+	// Invalid rules are rejected and leave the current ones in place
+	assert.Error(t, s.SetMetricRewriteConfig(MetricRewriteConfig{Deny: []string{"["}}))
+	assert.Equal(t, cfg, s.GetMetricRewriteConfig())
+	samples, err = s.parseMetricMessage(nil, parser, []byte("statsd.tmp.metric:666|g"), "", false)
+	require.NoError(t, err)
+	assert.Empty(t, samples)
+
+	// Rules can be removed at runtime
+	require.NoError(t, s.SetMetricRewriteConfig(MetricRewriteConfig{}))
+	samples, err = s.parseMetricMessage(nil, parser, []byte("statsd.tmp.metric:666|g"), "", false)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, "statsd.tmp.metric", samples[0].Name)
+}
@@ -45,6 +45,7 @@ var (
 	dogstatsdMetricPackets            = expvar.Int{}
 	dogstatsdPacketsLastSec           = expvar.Int{}
 	dogstatsdUnterminatedMetricErrors = expvar.Int{}
+	dogstatsdMetricRewriteDenied      = expvar.Int{}
 
 	tlmProcessed = telemetry.NewCounter("dogstatsd", "processed",
 		[]string{"message_type", "state", "origin"}, "Count of service checks/events/metrics processed by dogstatsd")
@@ -60,6 +61,11 @@ var (
 	tlmChannel            = telemetry.NewHistogramNoOp()
 	defaultChannelBuckets = []float64{100, 250, 500, 1000, 10000}
 	once                  sync.Once
+
+	tlmParserStage            = telemetry.NewHistogramNoOp()
+	defaultParserStageBuckets = []float64{100, 250, 500, 1000, 2500, 10000}
+	tlmParserStageParse       = "parse"
+	tlmParserStageEnrich      = "enrich"
 )
 
 func init() {
@@ -70,6 +76,7 @@ func init() {
 	dogstatsdExpvars.Set("MetricParseErrors", &dogstatsdMetricParseErrors)
 	dogstatsdExpvars.Set("MetricPackets", &dogstatsdMetricPackets)
 	dogstatsdExpvars.Set("UnterminatedMetricErrors", &dogstatsdUnterminatedMetricErrors)
+	dogstatsdExpvars.Set("MetricRewriteDenied", &dogstatsdMetricRewriteDenied)
 }
 
 // used in debug mode to add the origin on the processed metric as a tag
@@ -111,6 +118,18 @@ func initLatencyTelemetry() {
 		"Time in millisecond to push metrics to the aggregator input buffer",
 		buckets)
 
+	parserBuckets := get("telemetry.dogstatsd.parser_stage_latency_buckets")
+	if parserBuckets == nil {
+		parserBuckets = defaultParserStageBuckets
+	}
+
+	tlmParserStage = telemetry.NewHistogram(
+		"dogstatsd",
+		"parser_stage_latency",
+		[]string{"stage"},
+		"Time in nanoseconds spent in each stage of the metric messages parsing",
+		parserBuckets)
+
 	listeners.InitTelemetry(get("telemetry.dogstatsd.listeners_latency_buckets"))
 	packets.InitTelemetry(get("telemetry.dogstatsd.listeners_channel_latency_buckets"))
 }
@@ -145,6 +164,9 @@ type Server struct {
 	debugTagsAccumulator      *tagset.HashingTagsAccumulator
 	TCapture                  *replay.TrafficCapture
 	mapper                    *mapper.MetricMapper
+	metricRewriter            atomic.Value // *metricRewriter, updated at runtime
+	metricRewriteConfig       MetricRewriteConfig
+	metricRewriteLock         sync.Mutex
 	eolTerminationUDP         bool
 	eolTerminationUDS         bool
 	eolTerminationNamedPipe   bool
@@ -372,6 +394,13 @@ func NewServer(aggregator *aggregator.BufferedAggregator, extraTags []string) (*
 		s.EnableMetricsStats()
 	}
 
+	// rewrite the metric names
+	// ----------------------
+
+	if err := s.SetMetricRewriteConfig(getMetricRewriteConfig()); err != nil {
+		log.Warnf("Could not create the metric rewriting rules: %v", err)
+	}
+
 	// map some metric name
 	// ----------------------
 
@@ -439,15 +468,15 @@ func (s *Server) forwarder(fcon net.Conn, packetsChannel chan packets.Packets) {
 	}
 }
 
-// ServerlessFlush flushes all the data to the aggregator to them send it to the Datadog intake.
-func (s *Server) ServerlessFlush() {
+// FlushWithDeadline synchronously flushes all the data to the aggregator to then send it to the
+// Datadog intake. It returns the error of the context if it's done before the end of the flush.
+func (s *Server) FlushWithDeadline(ctx context.Context) error {
 	log.Debug("Received a Flush trigger")
 	// make all workers flush their aggregated data (in the batcher) to the aggregator.
 	for _, w := range s.workers {
 		w.flush()
 	}
-	s.aggregator.ServerlessFlush <- true
-	<-s.aggregator.ServerlessFlushDone
+	return s.aggregator.FlushWithDeadline(ctx)
 }
 
 // dropCR drops a terminal \r from the data.
@@ -622,6 +651,13 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		errorCnt = maps.errCnt
 	}
 
+	// Parser stages are only timed when the telemetry is enabled as getting the
+	// time for every message is not free on high-throughput setups.
+	var stageStart time.Time
+	if s.telemetryEnabled {
+		stageStart = time.Now()
+	}
+
 	sample, err := parser.parseMetricSample(message)
 	if err != nil {
 		dogstatsdMetricParseErrors.Add(1)
@@ -629,6 +665,24 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 		return metricSamples, err
 	}
 
+	if s.telemetryEnabled {
+		now := time.Now()
+		tlmParserStage.Observe(float64(now.Sub(stageStart).Nanoseconds()), tlmParserStageParse)
+		stageStart = now
+	}
+
+	if rewriter, _ := s.metricRewriter.Load().(*metricRewriter); rewriter != nil {
+		name, allowed := rewriter.rewrite(sample.name)
+		if !allowed {
+			dogstatsdMetricRewriteDenied.Add(1)
+			if len(sample.values) > 0 {
+				s.sharedFloat64List.put(sample.values)
+			}
+			return metricSamples, nil
+		}
+		sample.name = name
+	}
+
 	if s.mapper != nil {
 		mapResult := s.mapper.Map(sample.name)
 		if mapResult != nil {
@@ -639,6 +693,10 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 	}
 	metricSamples = enrichMetricSample(metricSamples, sample, s.metricPrefix, s.metricPrefixBlacklist, s.metricBlocklist, s.defaultHostname, origin, s.entityIDPrecedenceEnabled, s.ServerlessMode)
 
+	if s.telemetryEnabled {
+		tlmParserStage.Observe(float64(time.Since(stageStart).Nanoseconds()), tlmParserStageEnrich)
+	}
+
 	if len(sample.values) > 0 {
 		s.sharedFloat64List.put(sample.values)
 	}
@@ -657,6 +715,28 @@ func (s *Server) parseMetricMessage(metricSamples []metrics.MetricSample, parser
 	return metricSamples, nil
 }
 
+// SetMetricRewriteConfig replaces the rewriting rules of the metric names, the
+// rules are unchanged if the new ones are invalid
+func (s *Server) SetMetricRewriteConfig(cfg MetricRewriteConfig) error {
+	rewriter, err := newMetricRewriter(cfg)
+	if err != nil {
+		return err
+	}
+
+	s.metricRewriteLock.Lock()
+	defer s.metricRewriteLock.Unlock()
+	s.metricRewriteConfig = cfg
+	s.metricRewriter.Store(rewriter)
+	return nil
+}
+
+// GetMetricRewriteConfig returns the current rewriting rules of the metric names
+func (s *Server) GetMetricRewriteConfig() MetricRewriteConfig {
+	s.metricRewriteLock.Lock()
+	defer s.metricRewriteLock.Unlock()
+	return s.metricRewriteConfig
+}
+
 func (s *Server) parseEventMessage(parser *parser, message []byte, origin string) (*metrics.Event, error) {
 	sample, err := parser.parseEvent(message)
 	if err != nil {
@@ -853,6 +933,44 @@ func FormatDebugStats(stats []byte) (string, error) {
 	return buf.String(), nil
 }
 
+// GetJSONUDSClientStats returns the jsonified traffic of the clients of the UDS listener.
+func (s *Server) GetJSONUDSClientStats() ([]byte, error) {
+	return json.Marshal(listeners.GetUDSClientStats())
+}
+
+// FormatUDSClientStats returns a printable version of the traffic of the UDS clients.
+func FormatUDSClientStats(stats []byte) (string, error) {
+	var clients []listeners.UDSClientStats
+	if err := json.Unmarshal(stats, &clients); err != nil {
+		return "", err
+	}
+
+	// the busiest clients first
+	sort.SliceStable(clients, func(i, j int) bool {
+		return clients[i].Packets > clients[j].Packets
+	})
+
+	buf := bytes.NewBuffer(nil)
+
+	header := fmt.Sprintf("%-10s | %-40s | %-10s | %-12s | %-10s | %-20s\n", "PID", "Container", "Packets", "Bytes", "Errors", "Last Seen")
+	buf.Write([]byte(header))
+	buf.Write([]byte(strings.Repeat("-", len(header)) + "\n"))
+
+	for _, client := range clients {
+		container := client.ContainerID
+		if container == packets.NoOrigin {
+			container = "none"
+		}
+		buf.Write([]byte(fmt.Sprintf("%-10d | %-40s | %-10d | %-12d | %-10d | %-20v\n", client.PID, container, client.Packets, client.Bytes, client.Errors, client.LastSeen)))
+	}
+
+	if len(clients) == 0 {
+		buf.Write([]byte("No Unix Socket client seen yet, make sure dogstatsd_origin_detection is enabled."))
+	}
+
+	return buf.String(), nil
+}
+
 // SetExtraTags sets extra tags. All metrics sent to the DogstatsD will be tagged with them.
 func (s *Server) SetExtraTags(tags []string) {
 	s.extraTags = tags
@@ -6,6 +6,7 @@
 package dogstatsd
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -117,13 +118,19 @@ func BenchmarkParseMetricMessage(b *testing.B) {
 	parser := newParser(newFloat64ListPool())
 	message := []byte("daemon:666|h|@0.5|#sometag1:somevalue1,sometag2:somevalue2")
 
-	b.RunParallel(func(pb *testing.PB) {
-		samplesBench = make([]metrics.MetricSample, 0, 512)
-		for pb.Next() {
-			s.parseMetricMessage(samplesBench, parser, message, "", false)
-			samplesBench = samplesBench[0:0]
-		}
-	})
+	// the parser stages are only timed when the telemetry is enabled
+	for _, telemetryEnabled := range []bool{false, true} {
+		b.Run(fmt.Sprintf("telemetry-%t", telemetryEnabled), func(sb *testing.B) {
+			s.telemetryEnabled = telemetryEnabled
+			sb.RunParallel(func(pb *testing.PB) {
+				samplesBench = make([]metrics.MetricSample, 0, 512)
+				for pb.Next() {
+					s.parseMetricMessage(samplesBench, parser, message, "", false)
+					samplesBench = samplesBench[0:0]
+				}
+			})
+		})
+	}
 }
 
 func BenchmarkWithMapper(b *testing.B) {
@@ -3,4 +3,4 @@
 
 package runtime
 
-var RuntimeSecurity = NewRuntimeAsset("runtime-security.c", "0801d8d68a4177da29d91dbf91d0b4f528210f041fd113589f6a61aace90f442")
+var RuntimeSecurity = NewRuntimeAsset("runtime-security.c", "643f3da7634f649ed474ff8445cb7b8f83d2febe521e243644a408330fbc6ed2")
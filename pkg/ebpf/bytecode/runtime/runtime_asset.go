@@ -5,6 +5,7 @@ package runtime
 import (
 	"bytes"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -15,6 +16,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/ebpf/compiler"
 	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"golang.org/x/sys/unix"
 )
 
 var (
@@ -46,6 +49,7 @@ const (
 	resultReadErr
 	headerFetchErr
 	compiledOutputFound
+	compiledOutputFromSharedCache
 )
 
 type CompiledOutput interface {
@@ -64,6 +68,7 @@ type RuntimeAsset struct {
 	compilationResult   CompilationResult
 	compilationDuration time.Duration
 	headerFetchResult   kernel.HeaderFetchResult
+	sharedCacheResult   SharedCacheResult
 }
 
 func NewRuntimeAsset(filename, hash string) *RuntimeAsset {
@@ -73,6 +78,7 @@ func NewRuntimeAsset(filename, hash string) *RuntimeAsset {
 		compilationEnabled: false,
 		compilationResult:  notAttempted,
 		headerFetchResult:  kernel.NotAttempted,
+		sharedCacheResult:  sharedCacheNotAttempted,
 	}
 }
 
@@ -112,6 +118,11 @@ func (a *RuntimeAsset) Compile(config *ebpf.Config, cflags []string) (CompiledOu
 		a.compilationResult = kernelVersionErr
 		return nil, fmt.Errorf("unable to get kernel version: %w", err)
 	}
+	kernelHash, err := hashKernel()
+	if err != nil {
+		a.compilationResult = kernelVersionErr
+		return nil, fmt.Errorf("unable to get kernel release: %w", err)
+	}
 
 	inputReader, hash, err := a.Verify(config.BPFDir)
 	if err != nil {
@@ -129,15 +140,20 @@ func (a *RuntimeAsset) Compile(config *ebpf.Config, cflags []string) (CompiledOu
 	copy(flags[len(defaultFlags):], cflags)
 	flagHash := hashFlags(flags)
 
-	// filename includes kernel version, input file hash, and cflags hash
-	// this ensures we re-compile when either of the input changes
+	// filename includes kernel version, kernel release and architecture hash, input file hash, and cflags hash
+	// this ensures we re-compile when either of the input changes, and that the kernels sharing a version
+	// code don't share programs through the shared cache
 	baseName := strings.TrimSuffix(a.filename, filepath.Ext(a.filename))
-	outputFile := filepath.Join(config.RuntimeCompilerOutputDir, fmt.Sprintf("%s-%d-%s-%s.o", baseName, kv, hash, flagHash))
+	outputFile := filepath.Join(config.RuntimeCompilerOutputDir, fmt.Sprintf("%s-%d-%s-%s-%s.o", baseName, kv, kernelHash, hash, flagHash))
 	if _, err := os.Stat(outputFile); err != nil {
 		if !os.IsNotExist(err) {
 			a.compilationResult = outputFileErr
 			return nil, fmt.Errorf("error stat-ing output file %s: %w", outputFile, err)
 		}
+		if a.loadFromSharedCache(config, outputFile) {
+			a.compilationResult = compiledOutputFromSharedCache
+			return a.openOutput(outputFile)
+		}
 		dirs, res, err := kernel.GetKernelHeaders(config.KernelHeadersDirs, config.KernelHeadersDownloadDir, config.AptConfigDir, config.YumReposDir, config.ZypperReposDir)
 		a.headerFetchResult = res
 		if err != nil {
@@ -160,6 +176,10 @@ func (a *RuntimeAsset) Compile(config *ebpf.Config, cflags []string) (CompiledOu
 		a.compilationResult = compiledOutputFound
 	}
 
+	return a.openOutput(outputFile)
+}
+
+func (a *RuntimeAsset) openOutput(outputFile string) (CompiledOutput, error) {
 	out, err := os.Open(outputFile)
 	if err != nil {
 		a.compilationResult = resultReadErr
@@ -167,6 +187,26 @@ func (a *RuntimeAsset) Compile(config *ebpf.Config, cflags []string) (CompiledOu
 	return out, err
 }
 
+// loadFromSharedCache copies the compiled program from the shared cache to the output file, if the shared cache
+// is configured and contains a program with a valid signature. Otherwise the program is compiled on the host.
+func (a *RuntimeAsset) loadFromSharedCache(config *ebpf.Config, outputFile string) bool {
+	if config.RuntimeCompilerSharedCacheDir == "" {
+		return false
+	}
+	err := loadFromSharedCache(config.RuntimeCompilerSharedCacheDir, config.RuntimeCompilerSharedCachePublicKey, filepath.Base(outputFile), outputFile)
+	a.sharedCacheResult = sharedCacheResultFromError(err)
+	switch {
+	case err == nil:
+		log.Infof("loaded %s from the shared cache %s", filepath.Base(outputFile), config.RuntimeCompilerSharedCacheDir)
+		return true
+	case errors.Is(err, errSharedCacheMiss):
+		log.Debugf("%s not found in the shared cache %s, compiling it", filepath.Base(outputFile), config.RuntimeCompilerSharedCacheDir)
+	default:
+		log.Warnf("unable to load %s from the shared cache, compiling it: %s", a.filename, err)
+	}
+	return false
+}
+
 func (a *RuntimeAsset) GetTelemetry() map[string]int64 {
 	stats := make(map[string]int64)
 	if a.compilationEnabled {
@@ -174,12 +214,27 @@ func (a *RuntimeAsset) GetTelemetry() map[string]int64 {
 		stats["runtime_compilation_result"] = int64(a.compilationResult)
 		stats["kernel_header_fetch_result"] = int64(a.headerFetchResult)
 		stats["runtime_compilation_duration"] = a.compilationDuration.Nanoseconds()
+		stats["runtime_compilation_shared_cache_result"] = int64(a.sharedCacheResult)
 	} else {
 		stats["runtime_compilation_enabled"] = 0
 	}
 	return stats
 }
 
+// hashKernel hashes the release and the architecture of the running kernel. Distinct kernels,
+// like the builds of different distributions, can have the same version code.
+func hashKernel() (string, error) {
+	var uname unix.Utsname
+	if err := unix.Uname(&uname); err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(unix.ByteSliceToString(uname.Release[:])))
+	h.Write([]byte{0})
+	h.Write([]byte(unix.ByteSliceToString(uname.Machine[:])))
+	return fmt.Sprintf("%x", h.Sum(nil))[:16], nil
+}
+
 func hashFlags(flags []string) string {
 	h := sha256.New()
 	for _, f := range flags {
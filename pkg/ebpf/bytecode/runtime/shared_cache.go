@@ -0,0 +1,164 @@
+// +build linux_bpf
+
+package runtime
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SignatureExt is the extension of the files holding the signatures of the compiled programs of the shared cache
+const SignatureExt = ".sig"
+
+// SharedCacheResult enumerates the outcomes of a lookup in the shared cache of compiled programs
+type SharedCacheResult int
+
+const (
+	sharedCacheNotAttempted SharedCacheResult = iota
+	sharedCacheHit
+	sharedCacheMiss
+	sharedCachePublicKeyErr
+	sharedCacheReadErr
+	sharedCacheSignatureErr
+	sharedCacheWriteErr
+)
+
+var errSharedCacheMiss = errors.New("compiled program not found in the shared cache")
+
+type sharedCacheError struct {
+	result SharedCacheResult
+	err    error
+}
+
+func (e *sharedCacheError) Error() string {
+	return e.err.Error()
+}
+
+func (e *sharedCacheError) Unwrap() error {
+	return e.err
+}
+
+// ParsePublicKey decodes an ed25519 public key, either PEM encoded or as the base64 encoding of the raw key
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(encoded)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("invalid public key type %T, expected an ed25519 key", key)
+		}
+		return edKey, nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key encoding: %w", err)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key size: expected %d bytes, got %d", ed25519.PublicKeySize, len(key))
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// ParsePrivateKey decodes a PEM encoded ed25519 private key, as generated by `openssl genpkey -algorithm ed25519`
+func ParsePrivateKey(encoded []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(encoded)
+	if block == nil {
+		return nil, errors.New("invalid private key: no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("invalid private key type %T, expected an ed25519 key", key)
+	}
+	return edKey, nil
+}
+
+// SignCompiledOutput returns the content of the signature file of a compiled program, which is the
+// base64 encoded ed25519 signature of the program. The signature is stored next to the program
+// in the shared cache, in a file with the same name and the `.sig` extension.
+func SignCompiledOutput(content []byte, privateKey ed25519.PrivateKey) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content)) + "\n")
+}
+
+// verifyCompiledOutput checks the signature of a compiled program
+func verifyCompiledOutput(content, signature []byte, publicKey ed25519.PublicKey) error {
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(publicKey, content, sig) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// loadFromSharedCache copies the program named name from the shared cache directory to outputFile,
+// after having verified its signature. The shared cache is read-only, it is typically a NFS share or
+// a ConfigMap seeded with the programs compiled on a node running the same kernel version.
+func loadFromSharedCache(cacheDir, encodedPublicKey, name, outputFile string) error {
+	publicKey, err := ParsePublicKey(encodedPublicKey)
+	if err != nil {
+		return &sharedCacheError{sharedCachePublicKeyErr, err}
+	}
+
+	cachedFile := filepath.Join(cacheDir, name)
+	content, err := ioutil.ReadFile(cachedFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &sharedCacheError{sharedCacheMiss, errSharedCacheMiss}
+		}
+		return &sharedCacheError{sharedCacheReadErr, fmt.Errorf("error reading %s: %w", cachedFile, err)}
+	}
+	signature, err := ioutil.ReadFile(cachedFile + SignatureExt)
+	if err != nil {
+		return &sharedCacheError{sharedCacheSignatureErr, fmt.Errorf("error reading the signature of %s: %w", cachedFile, err)}
+	}
+	if err := verifyCompiledOutput(content, signature, publicKey); err != nil {
+		return &sharedCacheError{sharedCacheSignatureErr, fmt.Errorf("error verifying %s: %w", cachedFile, err)}
+	}
+
+	// the program is written to a temporary file first, so that a partially written file is never loaded
+	tmpFile, err := ioutil.TempFile(filepath.Dir(outputFile), filepath.Base(outputFile)+".*.tmp")
+	if err != nil {
+		return &sharedCacheError{sharedCacheWriteErr, fmt.Errorf("error creating temporary file: %w", err)}
+	}
+	defer os.Remove(tmpFile.Name())
+
+	_, err = tmpFile.Write(content)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), outputFile)
+	}
+	if err != nil {
+		return &sharedCacheError{sharedCacheWriteErr, fmt.Errorf("error writing %s: %w", outputFile, err)}
+	}
+	return nil
+}
+
+// sharedCacheResultFromError returns the result of a lookup in the shared cache
+func sharedCacheResultFromError(err error) SharedCacheResult {
+	if err == nil {
+		return sharedCacheHit
+	}
+	var scErr *sharedCacheError
+	if errors.As(err, &scErr) {
+		return scErr.result
+	}
+	return sharedCacheReadErr
+}
@@ -0,0 +1,110 @@
+// +build linux_bpf
+
+package runtime
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromSharedCache(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	cacheDir, err := ioutil.TempDir("", "shared-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(cacheDir)
+	outputDir, err := ioutil.TempDir("", "output")
+	require.NoError(t, err)
+	defer os.RemoveAll(outputDir)
+
+	program := []byte("compiled program")
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "tracer.o"), program, 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "tracer.o.sig"), SignCompiledOutput(program, privateKey), 0644))
+	outputFile := filepath.Join(outputDir, "tracer.o")
+
+	t.Run("valid signature", func(t *testing.T) {
+		defer os.Remove(outputFile)
+		err := loadFromSharedCache(cacheDir, encodedKey, "tracer.o", outputFile)
+		require.NoError(t, err)
+		assert.Equal(t, sharedCacheHit, sharedCacheResultFromError(err))
+
+		content, err := ioutil.ReadFile(outputFile)
+		require.NoError(t, err)
+		assert.Equal(t, program, content)
+	})
+
+	t.Run("missing program", func(t *testing.T) {
+		err := loadFromSharedCache(cacheDir, encodedKey, "conntrack.o", outputFile)
+		assert.ErrorIs(t, err, errSharedCacheMiss)
+		assert.Equal(t, sharedCacheMiss, sharedCacheResultFromError(err))
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		err := loadFromSharedCache(cacheDir, "not a key", "tracer.o", outputFile)
+		assert.Error(t, err)
+		assert.Equal(t, sharedCachePublicKeyErr, sharedCacheResultFromError(err))
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		otherKey, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+		err = loadFromSharedCache(cacheDir, base64.StdEncoding.EncodeToString(otherKey), "tracer.o", outputFile)
+		assert.Error(t, err)
+		assert.Equal(t, sharedCacheSignatureErr, sharedCacheResultFromError(err))
+		assert.NoFileExists(t, outputFile)
+	})
+
+	t.Run("tampered program", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "tampered.o"), []byte("tampered program"), 0644))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "tampered.o.sig"), SignCompiledOutput(program, privateKey), 0644))
+		err := loadFromSharedCache(cacheDir, encodedKey, "tampered.o", outputFile)
+		assert.Error(t, err)
+		assert.Equal(t, sharedCacheSignatureErr, sharedCacheResultFromError(err))
+		assert.NoFileExists(t, outputFile)
+	})
+
+	t.Run("missing signature", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(cacheDir, "unsigned.o"), program, 0644))
+		err := loadFromSharedCache(cacheDir, encodedKey, "unsigned.o", outputFile)
+		assert.Error(t, err)
+		assert.Equal(t, sharedCacheSignatureErr, sharedCacheResultFromError(err))
+	})
+}
+
+func TestParseKeys(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	key, err := ParsePublicKey(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, key)
+
+	key, err = ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, key)
+
+	_, err = ParsePublicKey(base64.StdEncoding.EncodeToString(publicKey[:16]))
+	assert.Error(t, err)
+
+	der, err = x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	parsedPrivateKey, err := ParsePrivateKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.Equal(t, privateKey, parsedPrivateKey)
+
+	_, err = ParsePrivateKey([]byte("not a key"))
+	assert.Error(t, err)
+}
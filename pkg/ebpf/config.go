@@ -40,6 +40,13 @@ type Config struct {
 	// RuntimeCompilerOutputDir is the directory where the runtime compiler will store compiled programs
 	RuntimeCompilerOutputDir string
 
+	// RuntimeCompilerSharedCacheDir is a read-only directory seeded with signed programs compiled on other hosts,
+	// they are used instead of compiling the programs when kernel headers are not available on the host
+	RuntimeCompilerSharedCacheDir string
+
+	// RuntimeCompilerSharedCachePublicKey is the base64 encoded ed25519 public key verifying the programs of the shared cache
+	RuntimeCompilerSharedCachePublicKey string
+
 	// AptConfigDir is the path to the apt config directory
 	AptConfigDir string
 
@@ -69,13 +76,15 @@ func NewConfig() *Config {
 		EnableTracepoints:        cfg.GetBool(key(spNS, "enable_tracepoints")),
 		ProcRoot:                 util.GetProcRoot(),
 
-		EnableRuntimeCompiler:    cfg.GetBool(key(spNS, "enable_runtime_compiler")),
-		RuntimeCompilerOutputDir: cfg.GetString(key(spNS, "runtime_compiler_output_dir")),
-		KernelHeadersDirs:        cfg.GetStringSlice(key(spNS, "kernel_header_dirs")),
-		KernelHeadersDownloadDir: cfg.GetString(key(spNS, "kernel_header_download_dir")),
-		AptConfigDir:             cfg.GetString(key(spNS, "apt_config_dir")),
-		YumReposDir:              cfg.GetString(key(spNS, "yum_repos_dir")),
-		ZypperReposDir:           cfg.GetString(key(spNS, "zypper_repos_dir")),
-		AllowPrecompiledFallback: true,
+		EnableRuntimeCompiler:               cfg.GetBool(key(spNS, "enable_runtime_compiler")),
+		RuntimeCompilerOutputDir:            cfg.GetString(key(spNS, "runtime_compiler_output_dir")),
+		RuntimeCompilerSharedCacheDir:       cfg.GetString(key(spNS, "runtime_compiler_shared_cache_dir")),
+		RuntimeCompilerSharedCachePublicKey: cfg.GetString(key(spNS, "runtime_compiler_shared_cache_public_key")),
+		KernelHeadersDirs:                   cfg.GetStringSlice(key(spNS, "kernel_header_dirs")),
+		KernelHeadersDownloadDir:            cfg.GetString(key(spNS, "kernel_header_download_dir")),
+		AptConfigDir:                        cfg.GetString(key(spNS, "apt_config_dir")),
+		YumReposDir:                         cfg.GetString(key(spNS, "yum_repos_dir")),
+		ZypperReposDir:                      cfg.GetString(key(spNS, "zypper_repos_dir")),
+		AllowPrecompiledFallback:            true,
 	}
 }
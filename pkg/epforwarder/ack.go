@@ -0,0 +1,134 @@
+package epforwarder
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	// ErrEventPurged is the error of the acknowledgements of the events purged from the pipelines
+	ErrEventPurged = errors.New("event purged from the event platform pipeline")
+	// ErrForwarderStopped is the error of the acknowledgements of the events still in flight when the forwarder stopped
+	ErrForwarderStopped = errors.New("event platform forwarder stopped before the event was delivered")
+)
+
+// Ack reports the delivery of an event sent with TrySendEventPlatformEventWithAck
+type Ack struct {
+	EventType string
+	// Delivered is true when the payload containing the event was accepted by the intake
+	Delivered bool
+	// Err is the reason why the event wasn't delivered
+	Err error
+}
+
+type pendingAck struct {
+	eventType string
+	ch        chan<- Ack
+}
+
+// ackTracker keeps the acknowledgement channels of the events in flight until the end of their delivery
+type ackTracker struct {
+	mu      sync.Mutex
+	pending map[*message.Message]pendingAck
+}
+
+func newAckTracker() *ackTracker {
+	return &ackTracker{
+		pending: make(map[*message.Message]pendingAck),
+	}
+}
+
+func (t *ackTracker) add(m *message.Message, eventType string, ch chan<- Ack) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[m] = pendingAck{eventType: eventType, ch: ch}
+}
+
+func (t *ackTracker) remove(m *message.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, m)
+}
+
+// resolve acknowledges the event if it was sent with an acknowledgement channel,
+// the event is delivered if err is nil
+func (t *ackTracker) resolve(m *message.Message, err error) {
+	t.mu.Lock()
+	p, ok := t.pending[m]
+	delete(t.pending, m)
+	t.mu.Unlock()
+	if ok {
+		notifyAck(p, err)
+	}
+}
+
+// resolveAll fails the acknowledgements of all the events in flight
+func (t *ackTracker) resolveAll(err error) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[*message.Message]pendingAck)
+	t.mu.Unlock()
+	for _, p := range pending {
+		notifyAck(p, err)
+	}
+}
+
+// notifyAck never blocks the pipeline: the acknowledgement is dropped if the channel is full
+func notifyAck(p pendingAck, err error) {
+	select {
+	case p.ch <- Ack{EventType: p.eventType, Delivered: err == nil, Err: err}:
+	default:
+		log.Debugf("Dropped the acknowledgement of an event of type %s, the channel is full", p.eventType)
+	}
+}
+
+// ackAuditor is the auditor of the passthrough pipelines, it receives the messages once the sender
+// is done with them and acknowledges the delivery of the events
+type ackAuditor struct {
+	channel     chan *message.Message
+	stopChannel chan struct{}
+	acks        *ackTracker
+}
+
+func newAckAuditor(acks *ackTracker) *ackAuditor {
+	return &ackAuditor{
+		channel:     make(chan *message.Message),
+		stopChannel: make(chan struct{}),
+		acks:        acks,
+	}
+}
+
+// GetOffset returns an empty string.
+func (a *ackAuditor) GetOffset(identifier string) string { return "" }
+
+// GetTailingMode returns an empty string.
+func (a *ackAuditor) GetTailingMode(identifier string) string { return "" }
+
+// Start starts the ackAuditor main loop.
+func (a *ackAuditor) Start() {
+	go a.run()
+}
+
+// Stop stops the ackAuditor main loop.
+func (a *ackAuditor) Stop() {
+	a.stopChannel <- struct{}{}
+}
+
+// Channel returns the channel on which should be sent the messages.
+func (a *ackAuditor) Channel() chan *message.Message {
+	return a.channel
+}
+
+func (a *ackAuditor) run() {
+	for {
+		select {
+		case m := <-a.channel:
+			a.acks.resolve(m, m.DeliveryErr)
+		case <-a.stopChannel:
+			return
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package epforwarder
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	pkgconfig "github.com/DataDog/datadog-agent/pkg/config"
 
@@ -69,9 +71,25 @@ var passthroughPipelineDescs = []passthroughPipelineDesc{
 	},
 }
 
+var (
+	// ErrPipelineFull is returned when the pipeline of the event type can't accept more events,
+	// producers should drop or postpone their events instead of retrying immediately
+	ErrPipelineFull = errors.New("event platform forwarder pipeline channel is full")
+	// ErrSourceQuotaExceeded is returned when a source sent more events than its quota allows in the current window
+	ErrSourceQuotaExceeded = errors.New("event platform source quota exceeded")
+)
+
+// IsBackpressure returns whether the error returned by the forwarder signals that the
+// event platform pipeline is saturated, in which case the producer should skip sending events this cycle
+func IsBackpressure(err error) bool {
+	return errors.Is(err, ErrPipelineFull) || errors.Is(err, ErrSourceQuotaExceeded)
+}
+
 // An EventPlatformForwarder forwards Messages to a destination based on their event type
 type EventPlatformForwarder interface {
 	SendEventPlatformEvent(e *message.Message, eventType string) error
+	TrySendEventPlatformEvent(e *message.Message, eventType string, source string) error
+	TrySendEventPlatformEventWithAck(e *message.Message, eventType string, source string, ack chan<- Ack) error
 	Purge() map[string][]*message.Message
 	Start()
 	Stop()
@@ -81,6 +99,8 @@ type defaultEventPlatformForwarder struct {
 	purgeMx         sync.Mutex
 	pipelines       map[string]*passthroughPipeline
 	destinationsCtx *client.DestinationsContext
+	quotas          *sourceQuotas
+	acks            *ackTracker
 }
 
 func (s *defaultEventPlatformForwarder) SendEventPlatformEvent(e *message.Message, eventType string) error {
@@ -92,8 +112,78 @@ func (s *defaultEventPlatformForwarder) SendEventPlatformEvent(e *message.Messag
 	case p.in <- e:
 		return nil
 	default:
-		return fmt.Errorf("event platform forwarder pipeline channel is full for eventType=%s. consider increasing batch_max_concurrent_send", eventType)
+		return fmt.Errorf("%w for eventType=%s. consider increasing batch_max_concurrent_send", ErrPipelineFull, eventType)
+	}
+}
+
+// TrySendEventPlatformEvent sends the event without ever blocking, on behalf of `source` (a check ID for instance).
+// It fails with an error matching IsBackpressure when the pipeline is saturated or when the source used up its quota.
+func (s *defaultEventPlatformForwarder) TrySendEventPlatformEvent(e *message.Message, eventType string, source string) error {
+	if _, ok := s.pipelines[eventType]; !ok {
+		return fmt.Errorf("unknown eventType=%s", eventType)
+	}
+	if !s.quotas.take(source, eventType) {
+		return fmt.Errorf("%w for source=%s eventType=%s", ErrSourceQuotaExceeded, source, eventType)
 	}
+	return s.SendEventPlatformEvent(e, eventType)
+}
+
+// TrySendEventPlatformEventWithAck sends the event like TrySendEventPlatformEvent, and reports on the `ack` channel
+// whether it was delivered once the forwarder is done with it. Nothing is reported on the channel when an error
+// is returned. The channel should be buffered as acknowledgements are dropped when it is full.
+func (s *defaultEventPlatformForwarder) TrySendEventPlatformEventWithAck(e *message.Message, eventType string, source string, ack chan<- Ack) error {
+	s.acks.add(e, eventType, ack)
+	if err := s.TrySendEventPlatformEvent(e, eventType, source); err != nil {
+		s.acks.remove(e)
+		return err
+	}
+	return nil
+}
+
+type quotaKey struct {
+	source    string
+	eventType string
+}
+
+// sourceQuotas limits the number of events each source can send per event type over a fixed window,
+// so that one busy source can't use up the whole pipeline capacity
+type sourceQuotas struct {
+	sync.Mutex
+	limit       int
+	window      time.Duration
+	windowStart time.Time
+	counts      map[quotaKey]int
+	now         func() time.Time
+}
+
+func newSourceQuotas(limit int, window time.Duration) *sourceQuotas {
+	return &sourceQuotas{
+		limit:  limit,
+		window: window,
+		counts: make(map[quotaKey]int),
+		now:    time.Now,
+	}
+}
+
+// take consumes one event from the quota of the source, it returns false when the quota is exhausted
+func (q *sourceQuotas) take(source string, eventType string) bool {
+	if q == nil || q.limit <= 0 {
+		return true
+	}
+	q.Lock()
+	defer q.Unlock()
+
+	now := q.now()
+	if now.Sub(q.windowStart) >= q.window {
+		q.windowStart = now
+		q.counts = make(map[quotaKey]int)
+	}
+	key := quotaKey{source: source, eventType: eventType}
+	if q.counts[key] >= q.limit {
+		return false
+	}
+	q.counts[key]++
+	return true
 }
 
 func purgeChan(in chan *message.Message) (result []*message.Message) {
@@ -117,6 +207,9 @@ func (s *defaultEventPlatformForwarder) Purge() map[string][]*message.Message {
 	result := make(map[string][]*message.Message)
 	for eventType, p := range s.pipelines {
 		result[eventType] = purgeChan(p.in)
+		for _, m := range result[eventType] {
+			s.acks.resolve(m, ErrEventPurged)
+		}
 	}
 	return result
 }
@@ -137,6 +230,7 @@ func (s *defaultEventPlatformForwarder) Stop() {
 	stopper.Stop()
 	// TODO: wait on stop and cancel context only after timeout like logs agent
 	s.destinationsCtx.Stop()
+	s.acks.resolveAll(ErrForwarderStopped)
 	log.Debugf("event platform forwarder shut down complete")
 }
 
@@ -159,7 +253,7 @@ type passthroughPipelineDesc struct {
 
 // newHTTPPassthroughPipeline creates a new HTTP-only event platform pipeline that sends messages directly to intake
 // without any of the processing that exists in regular logs pipelines.
-func newHTTPPassthroughPipeline(desc passthroughPipelineDesc, destinationsContext *client.DestinationsContext, pipelineID int) (p *passthroughPipeline, err error) {
+func newHTTPPassthroughPipeline(desc passthroughPipelineDesc, destinationsContext *client.DestinationsContext, pipelineID int, acks *ackTracker) (p *passthroughPipeline, err error) {
 	configKeys := config.NewLogsConfigKeys(desc.endpointsConfigPrefix, coreConfig.Datadog)
 	endpoints, err := config.BuildHTTPEndpointsWithConfig(configKeys, desc.hostnameEndpointPrefix, desc.intakeTrackType, config.DefaultIntakeProtocol, config.DefaultIntakeOrigin)
 	if err != nil {
@@ -186,7 +280,7 @@ func newHTTPPassthroughPipeline(desc passthroughPipelineDesc, destinationsContex
 	destinations := client.NewDestinations(main, additionals)
 	inputChan := make(chan *message.Message, 100)
 	strategy := sender.NewBatchStrategy(sender.ArraySerializer, endpoints.BatchWait, endpoints.BatchMaxConcurrentSend, pkgconfig.DefaultBatchMaxSize, endpoints.BatchMaxContentSize, desc.eventType, pipelineID)
-	a := auditor.NewNullAuditor()
+	a := newAckAuditor(acks)
 	log.Debugf("Initialized event platform forwarder pipeline. eventType=%s mainHost=%s additionalHosts=%s batch_max_concurrent_send=%d batch_max_content_size=%d batch_max_size=%d",
 		desc.eventType, endpoints.Main.Host, joinHosts(endpoints.Additionals), endpoints.BatchMaxConcurrentSend, endpoints.BatchMaxContentSize, endpoints.BatchMaxSize)
 	return &passthroughPipeline{
@@ -219,9 +313,10 @@ func joinHosts(endpoints []config.Endpoint) string {
 func newDefaultEventPlatformForwarder() *defaultEventPlatformForwarder {
 	destinationsCtx := client.NewDestinationsContext()
 	destinationsCtx.Start()
+	acks := newAckTracker()
 	pipelines := make(map[string]*passthroughPipeline)
 	for i, desc := range passthroughPipelineDescs {
-		p, err := newHTTPPassthroughPipeline(desc, destinationsCtx, i)
+		p, err := newHTTPPassthroughPipeline(desc, destinationsCtx, i, acks)
 		if err != nil {
 			log.Errorf("Failed to initialize event platform forwarder pipeline. eventType=%s, error=%s", desc.eventType, err.Error())
 			continue
@@ -231,6 +326,11 @@ func newDefaultEventPlatformForwarder() *defaultEventPlatformForwarder {
 	return &defaultEventPlatformForwarder{
 		pipelines:       pipelines,
 		destinationsCtx: destinationsCtx,
+		acks:            acks,
+		quotas: newSourceQuotas(
+			coreConfig.Datadog.GetInt("event_platform_source_quota"),
+			time.Duration(coreConfig.Datadog.GetInt("event_platform_source_quota_window"))*time.Second,
+		),
 	}
 }
 
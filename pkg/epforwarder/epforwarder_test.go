@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package epforwarder
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func newTestForwarder(chanSize int, quotas *sourceQuotas) *defaultEventPlatformForwarder {
+	return &defaultEventPlatformForwarder{
+		pipelines: map[string]*passthroughPipeline{
+			EventTypeNetworkDevicesMetadata: {in: make(chan *message.Message, chanSize)},
+		},
+		quotas: quotas,
+		acks:   newAckTracker(),
+	}
+}
+
+func TestTrySendPipelineFull(t *testing.T) {
+	f := newTestForwarder(1, nil)
+
+	require.NoError(t, f.TrySendEventPlatformEvent(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1"))
+	err := f.TrySendEventPlatformEvent(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1")
+	assert.True(t, errors.Is(err, ErrPipelineFull))
+	assert.True(t, IsBackpressure(err))
+
+	err = f.TrySendEventPlatformEvent(&message.Message{}, "unknown", "check1")
+	assert.Error(t, err)
+	assert.False(t, IsBackpressure(err))
+}
+
+func TestTrySendSourceQuota(t *testing.T) {
+	now := time.Now()
+	quotas := newSourceQuotas(2, 15*time.Second)
+	quotas.now = func() time.Time { return now }
+	f := newTestForwarder(10, quotas)
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, f.TrySendEventPlatformEvent(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1"))
+	}
+	err := f.TrySendEventPlatformEvent(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1")
+	assert.True(t, errors.Is(err, ErrSourceQuotaExceeded))
+	assert.True(t, IsBackpressure(err))
+
+	// other sources have their own quota
+	assert.NoError(t, f.TrySendEventPlatformEvent(&message.Message{}, EventTypeNetworkDevicesMetadata, "check2"))
+
+	// the quota is reset at the start of the next window
+	now = now.Add(15 * time.Second)
+	assert.NoError(t, f.TrySendEventPlatformEvent(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1"))
+}
+
+func TestSourceQuotaUnlimited(t *testing.T) {
+	quotas := newSourceQuotas(0, 15*time.Second)
+	for i := 0; i < 1000; i++ {
+		assert.True(t, quotas.take("check1", EventTypeNetworkDevicesMetadata))
+	}
+}
+
+func TestTrySendWithAck(t *testing.T) {
+	f := newTestForwarder(2, nil)
+	acks := make(chan Ack, 10)
+
+	delivered := &message.Message{}
+	rejected := &message.Message{}
+	require.NoError(t, f.TrySendEventPlatformEventWithAck(delivered, EventTypeNetworkDevicesMetadata, "check1", acks))
+	require.NoError(t, f.TrySendEventPlatformEventWithAck(rejected, EventTypeNetworkDevicesMetadata, "check1", acks))
+	// nothing is reported for the events that couldn't be sent
+	assert.True(t, errors.Is(f.TrySendEventPlatformEventWithAck(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1", acks), ErrPipelineFull))
+	assert.Len(t, f.acks.pending, 2)
+
+	a := newAckAuditor(f.acks)
+	a.Start()
+	defer a.Stop()
+
+	rejected.DeliveryErr = errors.New("payload rejected")
+	a.Channel() <- <-f.pipelines[EventTypeNetworkDevicesMetadata].in
+	a.Channel() <- <-f.pipelines[EventTypeNetworkDevicesMetadata].in
+
+	assert.Equal(t, Ack{EventType: EventTypeNetworkDevicesMetadata, Delivered: true}, <-acks)
+	assert.Equal(t, Ack{EventType: EventTypeNetworkDevicesMetadata, Err: rejected.DeliveryErr}, <-acks)
+}
+
+func TestAckPurgedAndStoppedEvents(t *testing.T) {
+	f := newTestForwarder(10, nil)
+	acks := make(chan Ack, 10)
+
+	require.NoError(t, f.TrySendEventPlatformEventWithAck(&message.Message{}, EventTypeNetworkDevicesMetadata, "check1", acks))
+	f.Purge()
+	assert.Equal(t, Ack{EventType: EventTypeNetworkDevicesMetadata, Err: ErrEventPurged}, <-acks)
+
+	// events still in flight are not delivered when the forwarder stops
+	f.acks.add(&message.Message{}, EventTypeNetworkDevicesMetadata, acks)
+	f.acks.resolveAll(ErrForwarderStopped)
+	assert.Equal(t, Ack{EventType: EventTypeNetworkDevicesMetadata, Err: ErrForwarderStopped}, <-acks)
+	assert.Empty(t, f.acks.pending)
+}
+
+func TestAckChannelFull(t *testing.T) {
+	f := newTestForwarder(10, nil)
+	acks := make(chan Ack)
+
+	m := &message.Message{}
+	require.NoError(t, f.TrySendEventPlatformEventWithAck(m, EventTypeNetworkDevicesMetadata, "check1", acks))
+	// the acknowledgement is dropped instead of blocking the pipeline
+	f.acks.resolve(m, nil)
+	assert.Empty(t, f.acks.pending)
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/status"
 	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/scrubber"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
@@ -848,7 +849,7 @@ func zipHTTPCallContent(tempDir, hostname, filename, url string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 4*time.Second)
 	defer cancel()
 
-	client := http.Client{}
+	client := http.Client{Transport: httputils.NewEgressValidatedTransport()}
 	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return err
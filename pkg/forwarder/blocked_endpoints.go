@@ -26,6 +26,12 @@ type blockedEndpoints struct {
 }
 
 func newBlockedEndpoints() *blockedEndpoints {
+	return newBlockedEndpointsWithBackoffMax(config.Datadog.GetFloat64("forwarder_backoff_max"))
+}
+
+// newBlockedEndpointsWithBackoffMax returns a blockedEndpoints with its own maximum backoff time,
+// the other settings of the backoff policy are shared by all the forwarders.
+func newBlockedEndpointsWithBackoffMax(backoffMax float64) *blockedEndpoints {
 	backoffFactor := config.Datadog.GetFloat64("forwarder_backoff_factor")
 	if backoffFactor < 2 {
 		log.Warnf("Configured forwarder_backoff_factor (%v) is less than 2; 2 will be used", backoffFactor)
@@ -38,7 +44,6 @@ func newBlockedEndpoints() *blockedEndpoints {
 		backoffBase = 2
 	}
 
-	backoffMax := config.Datadog.GetFloat64("forwarder_backoff_max")
 	if backoffMax <= 0 {
 		log.Warnf("Configured forwarder_backoff_max (%v) is not positive; 64 seconds will be used", backoffMax)
 		backoffMax = 64
@@ -14,6 +14,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/forwarder/internal/retry"
 	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -196,9 +197,7 @@ func (f *domainForwarder) Start() error {
 	f.init()
 
 	for i := 0; i < f.numberOfWorkers; i++ {
-		w := NewWorker(f.highPrio, f.lowPrio, f.requeuedTransaction, f.blockedList)
-		w.Start()
-		f.workers = append(f.workers, w)
+		f.workers = append(f.workers, f.startWorker())
 	}
 	go f.handleFailedTransactions()
 	if f.connectionResetInterval != 0 {
@@ -209,6 +208,36 @@ func (f *domainForwarder) Start() error {
 	return nil
 }
 
+// startWorker starts a new worker, the watchdog replaces it if it is wedged.
+// The worker is only registered for liveness when the watchdog is enabled.
+func (f *domainForwarder) startWorker() *Worker {
+	w := NewWorker(f.highPrio, f.lowPrio, f.requeuedTransaction, f.blockedList)
+	if config.Datadog.GetBool("watchdog.enabled") {
+		w.health = health.RegisterLivenessWithRestart("forwarder-worker-"+f.domain, func() { f.replaceWorker(w) })
+	}
+	w.Start()
+	return w
+}
+
+// replaceWorker starts a new worker in place of a wedged one, which stops
+// in the background once its current transaction is done.
+func (f *domainForwarder) replaceWorker(wedged *Worker) {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	if f.internalState == Stopped {
+		return
+	}
+
+	for i, w := range f.workers {
+		if w == wedged {
+			f.workers[i] = f.startWorker()
+			go wedged.Stop(false)
+			return
+		}
+	}
+}
+
 // Stop stops a domainForwarder, all transactions not yet flushed will be lost.
 func (f *domainForwarder) Stop(purgeHighPrio bool) {
 	// Lock so we can't start a Forwarder while is stopping
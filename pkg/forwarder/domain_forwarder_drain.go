@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package forwarder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// StopReport reports what happened to the transactions waiting to be sent when the forwarder was stopped
+type StopReport struct {
+	// Sent is the number of transactions sent while stopping
+	Sent int
+	// Dropped is the number of transactions that could not be sent before the deadline, by endpoint
+	Dropped map[string]int
+}
+
+func newStopReport() StopReport {
+	return StopReport{Dropped: make(map[string]int)}
+}
+
+func (r *StopReport) merge(other StopReport) {
+	r.Sent += other.Sent
+	for endpoint, count := range other.Dropped {
+		r.Dropped[endpoint] += count
+	}
+}
+
+// DroppedCount returns the total number of transactions dropped
+func (r StopReport) DroppedCount() int {
+	count := 0
+	for _, c := range r.Dropped {
+		count += c
+	}
+	return count
+}
+
+// String returns a representation of the report that can be logged
+func (r StopReport) String() string {
+	if r.DroppedCount() == 0 {
+		return fmt.Sprintf("%d transactions sent, none dropped", r.Sent)
+	}
+	endpoints := make([]string, 0, len(r.Dropped))
+	for endpoint, count := range r.Dropped {
+		endpoints = append(endpoints, fmt.Sprintf("%s: %d", endpoint, count))
+	}
+	sort.Strings(endpoints)
+	return fmt.Sprintf("%d transactions sent, %d dropped (%s)", r.Sent, r.DroppedCount(), strings.Join(endpoints, ", "))
+}
+
+// drain stops the domainForwarder after having sent the transactions still waiting,
+// including the ones of the in-memory retry queue, until the deadline. The transactions
+// stored on the disk are kept to be sent when the agent restarts. Its queues are closed once
+// drained, so nothing must submit transactions anymore, e.g. the aggregator must be stopped.
+func (f *domainForwarder) drain(deadline time.Time) StopReport {
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	report := newStopReport()
+	if f.internalState == Stopped {
+		log.Warnf("the forwarder is already stopped")
+		return report
+	}
+
+	if f.connectionResetInterval != 0 {
+		f.stopConnectionReset <- true
+	}
+	f.stopRetry <- true
+	// the transactions being processed are cancelled and requeued, they are sent again below
+	for _, w := range f.workers {
+		w.Stop(false)
+	}
+	f.workers = []*Worker{}
+
+	transactions := f.pendingTransactions()
+	f.transactionPrioritySorter.Sort(transactions)
+	if len(transactions) > 0 {
+		log.Infof("Sending %d transactions to %s before stopping", len(transactions), f.domain)
+	}
+
+	input := make(chan transaction.Transaction, len(transactions))
+	for _, t := range transactions {
+		input <- t
+	}
+	close(input)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < f.numberOfWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client := newHTTPClient()
+			for t := range input {
+				sent := f.sendBeforeDeadline(t, client, deadline)
+				mu.Lock()
+				if sent {
+					report.Sent++
+				} else {
+					report.Dropped[t.GetEndpointName()]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for endpoint, count := range report.Dropped {
+		transaction.TransactionsDroppedByEndpoint.Add(endpoint, int64(count))
+		transaction.TransactionsDropped.Add(int64(count))
+		transaction.TlmTxDropped.Add(float64(count), f.domain, endpoint)
+	}
+
+	close(f.highPrio)
+	close(f.lowPrio)
+	close(f.requeuedTransaction)
+	log.Info("domainForwarder stopped")
+	f.internalState = Stopped
+	return report
+}
+
+// pendingTransactions returns the transactions waiting in the queues of the domainForwarder
+func (f *domainForwarder) pendingTransactions() []transaction.Transaction {
+	var transactions []transaction.Transaction
+	for _, ch := range []chan transaction.Transaction{f.highPrio, f.lowPrio, f.requeuedTransaction} {
+	L:
+		for {
+			select {
+			case t := <-ch:
+				transactions = append(transactions, t)
+			default:
+				break L
+			}
+		}
+	}
+	return append(transactions, f.retryQueue.ExtractInMemoryTransactions()...)
+}
+
+// sendBeforeDeadline sends a transaction, unless the deadline is reached or its endpoint is failing
+func (f *domainForwarder) sendBeforeDeadline(t transaction.Transaction, client *http.Client, deadline time.Time) bool {
+	target := t.GetTarget()
+	if time.Now().After(deadline) || f.blockedList.isBlock(target) {
+		return false
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if err := t.Process(ctx, client); err != nil {
+		f.blockedList.close(target)
+		log.Debugf("Error while sending a transaction before stopping: %v", err)
+		return false
+	}
+	f.blockedList.recover(target)
+	return true
+}
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package forwarder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTransactionForDrain(target string, processErr error) *testTransaction {
+	tr := newTestTransaction()
+	tr.On("GetTarget").Return(target)
+	tr.On("GetCreatedAt").Return(time.Now())
+	tr.On("GetPayloadSize").Return(1)
+	tr.On("Process", mock.Anything).Return(processErr)
+	return tr
+}
+
+// startDomainForwarderWithoutWorkers starts a domainForwarder whose transactions stay in its queues
+func startDomainForwarderWithoutWorkers(t *testing.T) *domainForwarder {
+	forwarder := newDomainForwarderForTest(0)
+	require.NoError(t, forwarder.Start())
+	for _, w := range forwarder.workers {
+		w.Stop(false)
+	}
+	forwarder.workers = nil
+	return forwarder
+}
+
+func TestDomainForwarderDrain(t *testing.T) {
+	forwarder := startDomainForwarderWithoutWorkers(t)
+
+	tr1 := newTestTransactionForDrain("target1", nil)
+	tr2 := newTestTransactionForDrain("target2", nil)
+	forwarder.highPrio <- tr1
+	_, err := forwarder.retryQueue.Add(tr2)
+	require.NoError(t, err)
+
+	report := forwarder.drain(time.Now().Add(time.Minute))
+	assert.Equal(t, 2, report.Sent)
+	assert.Equal(t, 0, report.DroppedCount())
+	assert.Equal(t, Stopped, forwarder.State())
+	requireLenForwarderRetryQueue(t, forwarder, 0)
+	tr1.AssertNumberOfCalls(t, "Process", 1)
+	tr2.AssertNumberOfCalls(t, "Process", 1)
+}
+
+func TestDomainForwarderDrainDeadline(t *testing.T) {
+	forwarder := startDomainForwarderWithoutWorkers(t)
+
+	tr := newTestTransactionForDrain("target", nil)
+	forwarder.lowPrio <- tr
+
+	report := forwarder.drain(time.Now().Add(-time.Second))
+	assert.Equal(t, 0, report.Sent)
+	assert.Equal(t, map[string]int{"": 1}, report.Dropped)
+	assert.Equal(t, Stopped, forwarder.State())
+	tr.AssertNotCalled(t, "Process", mock.Anything)
+}
+
+func TestDomainForwarderDrainBlockedEndpoint(t *testing.T) {
+	forwarder := startDomainForwarderWithoutWorkers(t)
+
+	tr1 := newTestTransactionForDrain("target", assert.AnError)
+	tr2 := newTestTransactionForDrain("target", nil)
+	forwarder.requeuedTransaction <- tr1
+	forwarder.requeuedTransaction <- tr2
+
+	// the endpoint is blocked after the first error, the second transaction isn't sent
+	report := forwarder.drain(time.Now().Add(time.Minute))
+	assert.Equal(t, 0, report.Sent)
+	assert.Equal(t, 2, report.DroppedCount())
+	tr1.AssertNumberOfCalls(t, "Process", 1)
+	tr2.AssertNotCalled(t, "Process", mock.Anything)
+}
+
+func TestDomainForwarderDrainStopped(t *testing.T) {
+	forwarder := newDomainForwarderForTest(0)
+	report := forwarder.drain(time.Now().Add(time.Minute))
+	assert.Equal(t, 0, report.Sent)
+	assert.Equal(t, 0, report.DroppedCount())
+}
+
+func TestStopReport(t *testing.T) {
+	report := newStopReport()
+	assert.Equal(t, "0 transactions sent, none dropped", report.String())
+
+	report.merge(StopReport{Sent: 3, Dropped: map[string]int{"series_v1": 2}})
+	report.merge(StopReport{Sent: 1, Dropped: map[string]int{"series_v1": 1, "check_run_v1": 1}})
+	assert.Equal(t, 4, report.Sent)
+	assert.Equal(t, 4, report.DroppedCount())
+	assert.Equal(t, "4 transactions sent, 4 dropped (check_run_v1: 1, series_v1: 3)", report.String())
+}
@@ -55,6 +55,25 @@ func TestDomainForwarderStart(t *testing.T) {
 	forwarder.Stop(false)
 }
 
+func TestDomainForwarderWorkerLiveness(t *testing.T) {
+	mockConfig := config.Mock()
+
+	// the workers are only monitored by the watchdog when it's enabled
+	forwarder := newDomainForwarderForTest(0)
+	require.NoError(t, forwarder.Start())
+	require.Len(t, forwarder.workers, 1)
+	assert.Nil(t, forwarder.workers[0].health)
+	forwarder.Stop(false)
+
+	mockConfig.Set("watchdog.enabled", true)
+	defer mockConfig.Set("watchdog.enabled", false)
+	forwarder = newDomainForwarderForTest(0)
+	require.NoError(t, forwarder.Start())
+	require.Len(t, forwarder.workers, 1)
+	assert.NotNil(t, forwarder.workers[0].health)
+	forwarder.Stop(false)
+}
+
 func TestDomainForwarderInit(t *testing.T) {
 	forwarder := newDomainForwarderForTest(0)
 	forwarder.init()
@@ -8,7 +8,6 @@ package forwarder
 import (
 	"fmt"
 	"net/http"
-	"path"
 	"strconv"
 	"strings"
 	"sync"
@@ -38,6 +37,11 @@ const (
 	arbitraryTagHTTPHeaderKey = "Allow-Arbitrary-Tag-Value"
 )
 
+// RoutingRuleHTTPHeaderKey is the extra header set by the serializer on the payloads
+// routed to another organization, with the name of the routing rule. It isn't sent
+// to the intake: the payloads are sent with the API key of the rule instead.
+const RoutingRuleHTTPHeaderKey = "DD-Agent-Routing-Rule"
+
 // The amount of time the forwarder will wait to receive process-like response payloads before giving up
 // This is a var so that it can be changed for testing
 var defaultResponseTimeout = 30 * time.Second
@@ -69,11 +73,13 @@ type Forwarder interface {
 	SubmitMetadata(payload Payloads, extra http.Header) error
 	SubmitProcessChecks(payload Payloads, extra http.Header) (chan Response, error)
 	SubmitProcessDiscoveryChecks(payload Payloads, extra http.Header) (chan Response, error)
+	SubmitSoftwareInventory(payload Payloads, extra http.Header) (chan Response, error)
 	SubmitRTProcessChecks(payload Payloads, extra http.Header) (chan Response, error)
 	SubmitContainerChecks(payload Payloads, extra http.Header) (chan Response, error)
 	SubmitRTContainerChecks(payload Payloads, extra http.Header) (chan Response, error)
 	SubmitConnectionChecks(payload Payloads, extra http.Header) (chan Response, error)
 	SubmitOrchestratorChecks(payload Payloads, extra http.Header, payloadType int) (chan Response, error)
+	SubmitOrchestratorChecksFile(payload *transaction.FilePayload, extra http.Header, payloadType int) (chan Response, error)
 }
 
 // Compile-time check to ensure that DefaultForwarder implements the Forwarder interface
@@ -103,6 +109,17 @@ type Options struct {
 	DomainResolvers                map[string]resolver.DomainResolver
 	ConnectionResetInterval        time.Duration
 	CompletionHandler              transaction.HTTPCompletionHandler
+	// StorageMaxSizeInBytes is the maximum size of the transactions stored on disk, 0 disables the storage
+	StorageMaxSizeInBytes int64
+	// StorageFolder is the folder of the transactions stored on disk in the storage path,
+	// the storage is only available to the core agent when empty
+	StorageFolder string
+	// BackoffMax is the maximum time in seconds an endpoint is blocked after errors,
+	// `forwarder_backoff_max` is used when 0
+	BackoffMax float64
+	// RoutingRules are the rules of the payloads routed to other organizations,
+	// which are sent to the main domain with the API key of their rule
+	RoutingRules []config.RoutingRule
 }
 
 // SetFeature sets forwarder features in a feature set
@@ -155,6 +172,9 @@ func NewOptionsWithResolvers(domainResolvers map[string]resolver.DomainResolver)
 		retryQueuePayloadsTotalMaxSize = config.Datadog.GetInt(forwarderRetryQueuePayloadsMaxSizeKey)
 	}
 
+	// the errors are reported by the serializer, which doesn't route the payloads then
+	routingRules, _ := config.GetRoutingRules()
+
 	option := &Options{
 		NumberOfWorkers:                config.Datadog.GetInt("forwarder_num_workers"),
 		DisableAPIKeyChecking:          false,
@@ -162,6 +182,8 @@ func NewOptionsWithResolvers(domainResolvers map[string]resolver.DomainResolver)
 		APIKeyValidationInterval:       time.Duration(validationInterval) * time.Minute,
 		DomainResolvers:                domainResolvers,
 		ConnectionResetInterval:        time.Duration(config.Datadog.GetInt("forwarder_connection_reset_interval")) * time.Second,
+		StorageMaxSizeInBytes:          config.Datadog.GetInt64("forwarder_storage_max_size_in_bytes"),
+		RoutingRules:                   routingRules,
 	}
 
 	if config.Datadog.IsSet(forwarderRetryQueueMaxSizeKey) {
@@ -201,6 +223,16 @@ type DefaultForwarder struct {
 	m                sync.Mutex // To control Start/Stop races
 
 	completionHandler transaction.HTTPCompletionHandler
+
+	// routingAPIKeys are the API keys of the routing rules, by rule name
+	routingAPIKeys map[string]string
+	// routingDomain is the domain the routed payloads are sent to
+	routingDomain string
+
+	// filePayloads are the payload files of the transactions, the ones still
+	// referenced by the transactions dropped when the forwarder stops are removed
+	filePayloads   map[*transaction.FilePayload]struct{}
+	filePayloadsMu sync.Mutex
 }
 
 // NewDefaultForwarder returns a new DefaultForwarder.
@@ -216,22 +248,25 @@ func NewDefaultForwarder(options *Options) *DefaultForwarder {
 			validationInterval:    options.APIKeyValidationInterval,
 		},
 		completionHandler: options.CompletionHandler,
+		routingAPIKeys:    make(map[string]string, len(options.RoutingRules)),
+		filePayloads:      map[*transaction.FilePayload]struct{}{},
+	}
+	for _, rule := range options.RoutingRules {
+		f.routingAPIKeys[rule.Name] = rule.APIKey
 	}
+	f.routingDomain, _ = config.AddAgentVersionToDomain(config.GetMainInfraEndpoint(), "app")
+
 	var optionalRemovalPolicy *retry.FileRemovalPolicy
-	storageMaxSize := config.Datadog.GetInt64("forwarder_storage_max_size_in_bytes")
+	storageMaxSize := options.StorageMaxSizeInBytes
 
-	// Disk Persistence is a core-only feature for now.
+	// Disk Persistence is a core-only feature for now, unless the forwarder has its own storage folder.
 	if storageMaxSize == 0 {
 		log.Infof("Retry queue storage on disk is disabled")
 	} else if agentFolder := getAgentFolder(options); agentFolder != "" {
-		storagePath := config.Datadog.GetString("forwarder_storage_path")
-		if storagePath == "" {
-			storagePath = path.Join(config.Datadog.GetString("run_path"), "transactions_to_retry")
-		}
+		storagePath := retryStoragePath(agentFolder)
 		outdatedFileInDays := config.Datadog.GetInt("forwarder_outdated_file_in_days")
 		var err error
 
-		storagePath = path.Join(storagePath, agentFolder)
 		optionalRemovalPolicy, err = retry.NewFileRemovalPolicy(storagePath, outdatedFileInDays, retry.FileRemovalPolicyTelemetry{})
 		if err != nil {
 			log.Errorf("Error when initializing the removal policy: %v", err)
@@ -279,6 +314,9 @@ func NewDefaultForwarder(options *Options) *DefaultForwarder {
 				options.NumberOfWorkers,
 				options.ConnectionResetInterval,
 				domainForwarderSort)
+			if options.BackoffMax > 0 {
+				fwd.blockedList = newBlockedEndpointsWithBackoffMax(options.BackoffMax)
+			}
 			f.domainForwarders[domain] = fwd
 			// Register all alternate domains for each forwarder
 			for _, v := range resolver.GetAlternateDomains() {
@@ -299,8 +337,11 @@ func NewDefaultForwarder(options *Options) *DefaultForwarder {
 }
 
 func getAgentFolder(options *Options) string {
+	if options.StorageFolder != "" {
+		return options.StorageFolder
+	}
 	if HasFeature(options.EnabledFeatures, CoreFeatures) {
-		return "core"
+		return coreAgentFolder
 	}
 	return ""
 }
@@ -380,7 +421,74 @@ func (f *DefaultForwarder) Stop() {
 
 	f.healthChecker = nil
 	f.domainForwarders = map[string]*domainForwarder{}
+	f.removeFilePayloads()
+}
+
+// StopWithDeadline stops the forwarder after having sent the transactions still waiting, including
+// the ones of the retry queue, until the deadline. It returns the number of transactions sent and
+// dropped, so that the data lost when the agent stops can be reported.
+func (f *DefaultForwarder) StopWithDeadline(deadline time.Time) StopReport {
+	log.Infof("stopping the Forwarder, sending the remaining transactions until %s", deadline.Format(time.RFC3339))
+	// Lock so we can't start a Forwarder while is stopping
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	report := newStopReport()
+	if f.internalState == Stopped {
+		log.Warnf("the forwarder is already stopped")
+		return report
+	}
+
+	f.internalState = Stopped
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, df := range f.domainForwarders {
+		wg.Add(1)
+		go func(df *domainForwarder) {
+			defer wg.Done()
+			domainReport := df.drain(deadline)
+			mu.Lock()
+			report.merge(domainReport)
+			mu.Unlock()
+		}(df)
+	}
+	wg.Wait()
+
+	f.healthChecker.Stop()
+
+	f.healthChecker = nil
+	f.domainForwarders = map[string]*domainForwarder{}
+	f.removeFilePayloads()
+	return report
+}
+
+// UpdateAPIKeys replaces at runtime the API keys used to send data to a domain of the
+// forwarder, as configured in `dd_url` or `additional_endpoints`. The i-th previous key is
+// replaced by the i-th new key, including in the transactions waiting to be sent, and the
+// transactions of the previous keys without a new key at their position are dropped.
+func (f *DefaultForwarder) UpdateAPIKeys(domain string, apiKeys []string) error {
+	if len(apiKeys) == 0 {
+		return fmt.Errorf("no API key for domain '%s'", domain)
+	}
+
+	f.m.Lock()
+	defer f.m.Unlock()
+
+	versionDomain, _ := config.AddAgentVersionToDomain(domain, "app")
+	dr, found := f.domainResolvers[versionDomain]
+	if !found {
+		if dr, found = f.domainResolvers[domain]; !found {
+			return fmt.Errorf("unknown domain '%s'", domain)
+		}
+	}
 
+	dr.SetAPIKeys(apiKeys)
+	log.Infof("API keys of domain '%s' updated, %d api key(s)", domain, len(apiKeys))
+	if f.healthChecker != nil {
+		f.healthChecker.apiKeysUpdated()
+	}
+	return nil
 }
 
 // State returns the internal state of the forwarder (Started or Stopped)
@@ -396,47 +504,126 @@ func (f *DefaultForwarder) createHTTPTransactions(endpoint transaction.Endpoint,
 }
 
 func (f *DefaultForwarder) createAdvancedHTTPTransactions(endpoint transaction.Endpoint, payloads Payloads, apiKeyInQueryString bool, extra http.Header, priority transaction.Priority, storableOnDisk bool) []*transaction.HTTPTransaction {
+	if ruleName := extra.Get(RoutingRuleHTTPHeaderKey); ruleName != "" {
+		return f.createRoutedHTTPTransactions(ruleName, endpoint, payloads, apiKeyInQueryString, extra, priority)
+	}
+
 	transactions := make([]*transaction.HTTPTransaction, 0, len(payloads)*len(f.domainForwarders))
 	allowArbitraryTags := config.Datadog.GetBool("allow_arbitrary_tags")
 
 	for _, payload := range payloads {
 		for domain, dr := range f.domainResolvers {
 			for _, apiKey := range dr.GetAPIKeys() {
-				t := transaction.NewHTTPTransaction()
-				t.Domain, _ = dr.Resolve(endpoint)
-				t.Endpoint = endpoint
-				if apiKeyInQueryString {
-					t.Endpoint.Route = fmt.Sprintf("%s?api_key=%s", endpoint.Route, apiKey)
-				}
-				t.Payload = payload
-				t.Priority = priority
+				t := f.newHTTPTransaction(domain, dr, apiKey, endpoint, payload, apiKeyInQueryString, extra, priority, allowArbitraryTags)
 				t.StorableOnDisk = storableOnDisk
-				t.Headers.Set(apiHTTPHeaderKey, apiKey)
-				t.Headers.Set(versionHTTPHeaderKey, version.AgentVersion)
-				t.Headers.Set(useragentHTTPHeaderKey, fmt.Sprintf("datadog-agent/%s", version.AgentVersion))
-				if allowArbitraryTags {
-					t.Headers.Set(arbitraryTagHTTPHeaderKey, "true")
-				}
+				t.APIKeyResolver = dr.ResolveAPIKey
+				transactions = append(transactions, t)
+			}
+		}
+	}
+	return transactions
+}
 
-				if f.completionHandler != nil {
-					t.CompletionHandler = f.completionHandler
-				}
+// createRoutedHTTPTransactions creates the transactions of the payloads routed to
+// another organization, sent to the main domain with the API key of the rule
+func (f *DefaultForwarder) createRoutedHTTPTransactions(ruleName string, endpoint transaction.Endpoint, payloads Payloads, apiKeyInQueryString bool, extra http.Header, priority transaction.Priority) []*transaction.HTTPTransaction {
+	apiKey, found := f.routingAPIKeys[ruleName]
+	if !found {
+		log.Errorf("Dropping %d %s payload(s): unknown routing rule %q", len(payloads), endpoint.Name, ruleName)
+		return nil
+	}
+	dr, found := f.domainResolvers[f.routingDomain]
+	if !found {
+		log.Errorf("Dropping %d %s payload(s) of the routing rule %q: the main domain %s isn't configured", len(payloads), endpoint.Name, ruleName, f.routingDomain)
+		return nil
+	}
+
+	transactions := make([]*transaction.HTTPTransaction, 0, len(payloads))
+	allowArbitraryTags := config.Datadog.GetBool("allow_arbitrary_tags")
+	for _, payload := range payloads {
+		t := f.newHTTPTransaction(f.routingDomain, dr, apiKey, endpoint, payload, apiKeyInQueryString, extra, priority, allowArbitraryTags)
+		// the API keys of the other organizations aren't written on disk
+		t.StorableOnDisk = false
+		transactions = append(transactions, t)
+	}
+	return transactions
+}
 
-				tlmTxInputCount.Inc(domain, endpoint.Name)
-				tlmTxInputBytes.Add(float64(t.GetPayloadSize()), domain, endpoint.Name)
-				transactionsInputCountByEndpoint.Add(endpoint.Name, 1)
-				transactionsInputBytesByEndpoint.Add(endpoint.Name, int64(t.GetPayloadSize()))
+func (f *DefaultForwarder) newHTTPTransaction(domain string, dr resolver.DomainResolver, apiKey string, endpoint transaction.Endpoint, payload *[]byte, apiKeyInQueryString bool, extra http.Header, priority transaction.Priority, allowArbitraryTags bool) *transaction.HTTPTransaction {
+	t := transaction.NewHTTPTransaction()
+	t.Domain, _ = dr.Resolve(endpoint)
+	t.Endpoint = endpoint
+	if apiKeyInQueryString {
+		t.Endpoint.Route = fmt.Sprintf("%s?api_key=%s", endpoint.Route, apiKey)
+	}
+	t.Payload = payload
+	t.Priority = priority
+	t.Headers.Set(apiHTTPHeaderKey, apiKey)
+	t.Headers.Set(versionHTTPHeaderKey, version.AgentVersion)
+	t.Headers.Set(useragentHTTPHeaderKey, fmt.Sprintf("datadog-agent/%s", version.AgentVersion))
+	if allowArbitraryTags {
+		t.Headers.Set(arbitraryTagHTTPHeaderKey, "true")
+	}
 
-				for key := range extra {
-					t.Headers.Set(key, extra.Get(key))
-				}
-				transactions = append(transactions, t)
-			}
+	if f.completionHandler != nil {
+		t.CompletionHandler = f.completionHandler
+	}
+
+	tlmTxInputCount.Inc(domain, endpoint.Name)
+	tlmTxInputBytes.Add(float64(t.GetPayloadSize()), domain, endpoint.Name)
+	transactionsInputCountByEndpoint.Add(endpoint.Name, 1)
+	transactionsInputBytesByEndpoint.Add(endpoint.Name, int64(t.GetPayloadSize()))
+
+	for key := range extra {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(RoutingRuleHTTPHeaderKey) {
+			continue
 		}
+		t.Headers.Set(key, extra.Get(key))
+	}
+	return t
+}
+
+// createFileHTTPTransactions creates the transactions of a payload stored in a file,
+// each transaction holds a reference on the file.
+func (f *DefaultForwarder) createFileHTTPTransactions(endpoint transaction.Endpoint, payload *transaction.FilePayload, apiKeyInQueryString bool, extra http.Header, priority transaction.Priority) []*transaction.HTTPTransaction {
+	transactions := f.createAdvancedHTTPTransactions(endpoint, Payloads{nil}, apiKeyInQueryString, extra, priority, true)
+	f.trackFilePayload(payload)
+	for _, t := range transactions {
+		t.PayloadFile = payload.Retain()
+		tlmTxInputBytes.Add(float64(payload.Size), t.Domain, endpoint.Name)
+		transactionsInputBytesByEndpoint.Add(endpoint.Name, int64(payload.Size))
 	}
 	return transactions
 }
 
+// trackFilePayload keeps a payload file to remove it when the forwarder stops,
+// the payloads released by all their transactions are forgotten.
+func (f *DefaultForwarder) trackFilePayload(payload *transaction.FilePayload) {
+	f.filePayloadsMu.Lock()
+	defer f.filePayloadsMu.Unlock()
+
+	for p := range f.filePayloads {
+		if p.Released() {
+			delete(f.filePayloads, p)
+		}
+	}
+	f.filePayloads[payload] = struct{}{}
+}
+
+// removeFilePayloads removes the payload files still referenced by the
+// transactions dropped when the forwarder stops.
+func (f *DefaultForwarder) removeFilePayloads() {
+	f.filePayloadsMu.Lock()
+	defer f.filePayloadsMu.Unlock()
+
+	for p := range f.filePayloads {
+		if !p.Released() {
+			p.Remove()
+		}
+	}
+	f.filePayloads = map[*transaction.FilePayload]struct{}{}
+}
+
 func (f *DefaultForwarder) sendHTTPTransactions(transactions []*transaction.HTTPTransaction) error {
 	if atomic.LoadUint32(&f.internalState) == Stopped {
 		return fmt.Errorf("the forwarder is not started")
@@ -449,14 +636,16 @@ func (f *DefaultForwarder) sendHTTPTransactions(transactions []*transaction.HTTP
 }
 
 // SubmitEvents will send an event type payload to Datadog backend.
+// Events have a high priority as they can power alerts.
 func (f *DefaultForwarder) SubmitEvents(payload Payloads, extra http.Header) error {
-	transactions := f.createHTTPTransactions(endpoints.EventsEndpoint, payload, false, extra)
+	transactions := f.createAdvancedHTTPTransactions(endpoints.EventsEndpoint, payload, false, extra, transaction.TransactionPriorityHigh, true)
 	return f.sendHTTPTransactions(transactions)
 }
 
 // SubmitServiceChecks will send a service check type payload to Datadog backend.
+// Service checks have a high priority as they can power alerts.
 func (f *DefaultForwarder) SubmitServiceChecks(payload Payloads, extra http.Header) error {
-	transactions := f.createHTTPTransactions(endpoints.ServiceChecksEndpoint, payload, false, extra)
+	transactions := f.createAdvancedHTTPTransactions(endpoints.ServiceChecksEndpoint, payload, false, extra, transaction.TransactionPriorityHigh, true)
 	return f.sendHTTPTransactions(transactions)
 }
 
@@ -501,7 +690,7 @@ func (f *DefaultForwarder) SubmitV1Series(payload Payloads, extra http.Header) e
 // SubmitV1CheckRuns will send service checks to v1 endpoint (this will be removed once
 // the backend handles v2 endpoints).
 func (f *DefaultForwarder) SubmitV1CheckRuns(payload Payloads, extra http.Header) error {
-	transactions := f.createHTTPTransactions(endpoints.V1CheckRunsEndpoint, payload, true, extra)
+	transactions := f.createAdvancedHTTPTransactions(endpoints.V1CheckRunsEndpoint, payload, true, extra, transaction.TransactionPriorityHigh, true)
 	return f.sendHTTPTransactions(transactions)
 }
 
@@ -534,6 +723,11 @@ func (f *DefaultForwarder) SubmitProcessDiscoveryChecks(payload Payloads, extra
 	return f.submitProcessLikePayload(endpoints.ProcessDiscoveryEndpoint, payload, extra, true)
 }
 
+// SubmitSoftwareInventory sends the software inventory
+func (f *DefaultForwarder) SubmitSoftwareInventory(payload Payloads, extra http.Header) (chan Response, error) {
+	return f.submitProcessLikePayload(endpoints.SoftwareInventoryEndpoint, payload, extra, true)
+}
+
 // SubmitRTProcessChecks sends real time process checks
 func (f *DefaultForwarder) SubmitRTProcessChecks(payload Payloads, extra http.Header) (chan Response, error) {
 	return f.submitProcessLikePayload(endpoints.RtProcessesEndpoint, payload, extra, false)
@@ -561,8 +755,23 @@ func (f *DefaultForwarder) SubmitOrchestratorChecks(payload Payloads, extra http
 	return f.submitProcessLikePayload(endpoints.OrchestratorEndpoint, payload, extra, true)
 }
 
+// SubmitOrchestratorChecksFile sends orchestrator checks stored in a file
+func (f *DefaultForwarder) SubmitOrchestratorChecksFile(payload *transaction.FilePayload, extra http.Header, payloadType int) (chan Response, error) {
+	bumpOrchestratorPayload(payloadType)
+
+	transactions := f.createFileHTTPTransactions(endpoints.OrchestratorEndpoint, payload, false, extra, transaction.TransactionPriorityLow)
+	return f.submitProcessLikeTransactions(transactions, true)
+}
+
+// submitProcessLikePayload sends bulky payloads with a low priority. Process-like
+// payloads are sent by dedicated forwarders (process agent, orchestrator), so the
+// priority only orders them against each other unless a retry queue is shared.
 func (f *DefaultForwarder) submitProcessLikePayload(ep transaction.Endpoint, payload Payloads, extra http.Header, retryable bool) (chan Response, error) {
-	transactions := f.createHTTPTransactions(ep, payload, false, extra)
+	transactions := f.createAdvancedHTTPTransactions(ep, payload, false, extra, transaction.TransactionPriorityLow, true)
+	return f.submitProcessLikeTransactions(transactions, retryable)
+}
+
+func (f *DefaultForwarder) submitProcessLikeTransactions(transactions []*transaction.HTTPTransaction, retryable bool) (chan Response, error) {
 	results := make(chan Response, len(transactions))
 	internalResults := make(chan Response, len(transactions))
 	expectedResponses := len(transactions)
@@ -59,12 +59,18 @@ type forwarderHealth struct {
 	keysPerAPIEndpoint    map[string][]string
 	disableAPIKeyChecking bool
 	validationInterval    time.Duration
+	keysUpdated           chan struct{}
 }
 
 func (fh *forwarderHealth) init() {
 	fh.stop = make(chan bool, 1)
 	fh.stopped = make(chan struct{})
+	fh.keysUpdated = make(chan struct{}, 1)
+	fh.computeAPIKeys()
+}
 
+// computeAPIKeys computes the API keys to validate from the domain resolvers
+func (fh *forwarderHealth) computeAPIKeys() {
 	fh.keysPerAPIEndpoint = make(map[string][]string)
 	fh.computeDomainsURL()
 
@@ -109,27 +115,68 @@ func (fh *forwarderHealth) healthCheckLoop() {
 	defer close(fh.stopped)
 
 	valid := fh.hasValidAPIKey()
-	// If no key is valid, no need to keep checking, they won't magically become valid
-	if !valid {
-		log.Errorf("No valid api key found, reporting the forwarder as unhealthy.")
-		return
-	}
 
 	for {
+		// If no key is valid, no need to keep checking until the keys are updated,
+		// they won't magically become valid
+		if !valid {
+			log.Errorf("No valid api key found, reporting the forwarder as unhealthy.")
+			select {
+			case <-fh.stop:
+				return
+			case <-fh.keysUpdated:
+				valid = fh.revalidateAPIKeys()
+			}
+			continue
+		}
+
 		select {
 		case <-fh.stop:
 			return
 		case <-validateTicker.C:
-			valid := fh.hasValidAPIKey()
-			if !valid {
-				log.Errorf("No valid api key found, reporting the forwarder as unhealthy.")
-				return
-			}
+			valid = fh.hasValidAPIKey()
+		case <-fh.keysUpdated:
+			valid = fh.revalidateAPIKeys()
 		case <-fh.health.C:
 		}
 	}
 }
 
+// apiKeysUpdated triggers the validation of the API keys after they were updated at runtime
+func (fh *forwarderHealth) apiKeysUpdated() {
+	if fh.disableAPIKeyChecking || fh.keysUpdated == nil {
+		return
+	}
+	select {
+	case fh.keysUpdated <- struct{}{}:
+	default:
+		// a validation is already planned
+	}
+}
+
+// revalidateAPIKeys validates the updated API keys and removes the status of the previous ones
+func (fh *forwarderHealth) revalidateAPIKeys() bool {
+	fh.computeAPIKeys()
+
+	current := make(map[string]bool)
+	for _, apiKeys := range fh.keysPerAPIEndpoint {
+		for _, apiKey := range apiKeys {
+			current[obfuscateAPIKey(apiKey)] = true
+		}
+	}
+	var removed []string
+	apiKeyStatus.Do(func(kv expvar.KeyValue) {
+		if !current[kv.Key] {
+			removed = append(removed, kv.Key)
+		}
+	})
+	for _, key := range removed {
+		apiKeyStatus.Delete(key)
+	}
+
+	return fh.hasValidAPIKey()
+}
+
 // computeDomainsURL populates a map containing API Endpoints per API keys that belongs to the forwarderHealth struct
 func (fh *forwarderHealth) computeDomainsURL() {
 	for domain, dr := range fh.domainResolvers {
@@ -145,11 +192,14 @@ func (fh *forwarderHealth) computeDomainsURL() {
 }
 
 func (fh *forwarderHealth) setAPIKeyStatus(apiKey string, domain string, status expvar.Var) {
+	apiKeyStatus.Set(obfuscateAPIKey(apiKey), status)
+}
+
+func obfuscateAPIKey(apiKey string) string {
 	if len(apiKey) > 5 {
 		apiKey = apiKey[len(apiKey)-5:]
 	}
-	obfuscatedKey := fmt.Sprintf("API key ending with %s", apiKey)
-	apiKeyStatus.Set(obfuscatedKey, status)
+	return fmt.Sprintf("API key ending with %s", apiKey)
 }
 
 func (fh *forwarderHealth) validateAPIKey(apiKey, domain string) (bool, error) {
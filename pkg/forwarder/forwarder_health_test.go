@@ -108,3 +108,26 @@ func TestHasValidAPIKeyErrors(t *testing.T) {
 	assert.Equal(t, &apiKeyStatusUnknown, apiKeyStatus.Get("API key ending with _key2"))
 	assert.Equal(t, &apiKeyValid, apiKeyStatus.Get("API key ending with key3"))
 }
+
+func TestRevalidateAPIKeys(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("api_key") == "old_api_key" {
+			w.WriteHeader(http.StatusForbidden)
+		} else {
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	dr := resolver.NewSingleDomainResolver(ts.URL, []string{"old_api_key"})
+	fh := forwarderHealth{domainResolvers: map[string]resolver.DomainResolver{ts.URL: dr}}
+	fh.init()
+	assert.False(t, fh.hasValidAPIKey())
+	assert.Equal(t, &apiKeyInvalid, apiKeyStatus.Get("API key ending with i_key"))
+
+	dr.SetAPIKeys([]string{"rotated_key"})
+	assert.True(t, fh.revalidateAPIKeys())
+	assert.Equal(t, &apiKeyValid, apiKeyStatus.Get("API key ending with d_key"))
+	assert.Nil(t, apiKeyStatus.Get("API key ending with i_key"))
+}
@@ -117,6 +117,37 @@ func testStop(t *testing.T) {
 	}
 }
 
+func TestStopRemovesFilePayloads(t *testing.T) {
+	forwarder := NewDefaultForwarder(NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysPerDomains)))
+	forwarder.Start()
+
+	newPayload := func() *transaction.FilePayload {
+		f, err := ioutil.TempFile(t.TempDir(), "payload-*")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		return transaction.NewFilePayload(f.Name(), 0)
+	}
+	endpoint := transaction.Endpoint{Route: "/api/foo", Name: "foo"}
+
+	// the transactions of the first payload are sent, the ones of the second are pending
+	sent := newPayload()
+	for _, tr := range forwarder.createFileHTTPTransactions(endpoint, sent, false, nil, transaction.TransactionPriorityNormal) {
+		tr.Release()
+	}
+	sent.Release()
+	assert.NoFileExists(t, sent.Path)
+
+	pending := newPayload()
+	forwarder.createFileHTTPTransactions(endpoint, pending, false, nil, transaction.TransactionPriorityNormal)
+	pending.Release()
+	assert.FileExists(t, pending.Path)
+	assert.Len(t, forwarder.filePayloads, 1)
+
+	forwarder.Stop()
+	assert.NoFileExists(t, pending.Path)
+	assert.Len(t, forwarder.filePayloads, 0)
+}
+
 func TestSubmitIfStopped(t *testing.T) {
 	forwarder := NewDefaultForwarder(NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(monoKeysDomains)))
 
@@ -288,6 +319,38 @@ func TestArbitraryTagsHTTPHeader(t *testing.T) {
 	assert.Equal(t, "true", transactions[0].Headers.Get(arbitraryTagHTTPHeaderKey))
 }
 
+func TestCreateRoutedHTTPTransactions(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("dd_url", testDomain)
+	defer mockConfig.Set("dd_url", "")
+
+	options := NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysWithMultipleDomains))
+	options.RoutingRules = []config.RoutingRule{{Name: "payments", Tags: []string{"team:payments"}, APIKey: "payments-api-key"}}
+	forwarder := NewDefaultForwarder(options)
+	endpoint := transaction.Endpoint{Route: "/api/foo", Name: "foo"}
+	p1 := []byte("A payload")
+	p2 := []byte("Another payload")
+	headers := make(http.Header)
+	headers.Set("HTTP-MAGIC", "foo")
+	headers.Set(RoutingRuleHTTPHeaderKey, "payments")
+
+	// the routed payloads are only sent to the main domain, with the API key of the rule
+	transactions := forwarder.createHTTPTransactions(endpoint, Payloads{&p1, &p2}, false, headers)
+	require.Len(t, transactions, 2)
+	for _, tr := range transactions {
+		assert.Equal(t, testVersionDomain, tr.Domain)
+		assert.Equal(t, "payments-api-key", tr.Headers.Get("DD-Api-Key"))
+		assert.Equal(t, "foo", tr.Headers.Get("HTTP-MAGIC"))
+		assert.Empty(t, tr.Headers.Get(RoutingRuleHTTPHeaderKey))
+		assert.False(t, tr.StorableOnDisk)
+	}
+	assert.Equal(t, p1, *(transactions[0].Payload))
+	assert.Equal(t, p2, *(transactions[1].Payload))
+
+	headers.Set(RoutingRuleHTTPHeaderKey, "unknown")
+	assert.Empty(t, forwarder.createHTTPTransactions(endpoint, Payloads{&p1}, false, headers))
+}
+
 func TestSendHTTPTransactions(t *testing.T) {
 	forwarder := NewDefaultForwarder(NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysPerDomains)))
 	endpoint := transaction.Endpoint{Route: "/api/foo", Name: "foo"}
@@ -691,3 +754,37 @@ func TestCustomCompletionHandler(t *testing.T) {
 
 	assert.True(t, handlerCalled)
 }
+
+func TestUpdateAPIKeys(t *testing.T) {
+	forwarder := NewDefaultForwarder(NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysWithMultipleDomains)))
+	endpoint := transaction.Endpoint{Route: "/api/foo", Name: "foo"}
+	p1 := []byte("A payload")
+
+	transactions := forwarder.createHTTPTransactions(endpoint, Payloads{&p1}, true, make(http.Header))
+	require.Len(t, transactions, 3)
+
+	// api-key-1 is rotated, api-key-2 is removed
+	require.NoError(t, forwarder.UpdateAPIKeys(testDomain, []string{"api-key-4"}))
+	assert.Error(t, forwarder.UpdateAPIKeys("https://unknown.datadoghq.com", []string{"api-key-5"}))
+	assert.Error(t, forwarder.UpdateAPIKeys(testDomain, nil))
+
+	var sent []string
+	for _, tr := range transactions {
+		if !tr.UpdateAPIKey() {
+			continue
+		}
+		apiKey := tr.Headers.Get("DD-Api-Key")
+		assert.Equal(t, endpoint.Route+"?api_key="+apiKey, tr.Endpoint.Route)
+		sent = append(sent, apiKey)
+	}
+	assert.ElementsMatch(t, []string{"api-key-4", "api-key-3"}, sent)
+
+	// new transactions use the new keys
+	transactions = forwarder.createHTTPTransactions(endpoint, Payloads{&p1}, false, make(http.Header))
+	require.Len(t, transactions, 2)
+	var apiKeys []string
+	for _, tr := range transactions {
+		apiKeys = append(apiKeys, tr.Headers.Get("DD-Api-Key"))
+	}
+	assert.ElementsMatch(t, []string{"api-key-4", "api-key-3"}, apiKeys)
+}
@@ -109,14 +109,22 @@ func (p *FileRemovalPolicy) forEachDomainPath(callback func(folderPath string) (
 }
 
 func (p *FileRemovalPolicy) getFolderPathForDomain(domainName string) (string, error) {
+	folder, err := DomainFolderName(domainName)
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(p.rootPath, folder), nil
+}
+
+// DomainFolderName returns the name of the folder storing the `.retry` files of a domain
+func DomainFolderName(domainName string) (string, error) {
 	// Use md5 for the folder name as the domainName is an url which can contain invalid charaters for a file path.
 	h := md5.New()
 	if _, err := io.WriteString(h, domainName); err != nil {
 		return "", err
 	}
-	folder := fmt.Sprintf("%x", h.Sum(nil))
-
-	return path.Join(p.rootPath, folder), nil
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
 }
 
 func (p *FileRemovalPolicy) removeUnknownDomain(folderPath string) ([]string, error) {
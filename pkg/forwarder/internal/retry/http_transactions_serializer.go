@@ -6,6 +6,8 @@
 package retry
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net/http"
@@ -21,7 +23,17 @@ import (
 	proto "github.com/golang/protobuf/proto"
 )
 
-const transactionsSerializerVersion = 1
+// transactionsSerializerVersion is the version of the format of the `.retry` files written by
+// the serializer. It must be increased on any change of the format that the previous versions
+// can't read, and the files of the previous versions must stay readable until they're outdated.
+// Version 2 identifies the API keys by a hash of the key instead of their position in the sorted keys.
+const transactionsSerializerVersion = 2
+
+// minTransactionsSerializerVersion is the oldest version of the format that can be read
+const minTransactionsSerializerVersion = 1
+
+// indexedAPIKeysSerializerVersion is the last version identifying the API keys by their position
+const indexedAPIKeysSerializerVersion = 1
 
 // Use an non US ASCII char as a separator (Should neither appear in an HTTP header value nor in a URL).
 const squareChar = "\xfe"
@@ -35,23 +47,43 @@ type HTTPTransactionsSerializer struct {
 	collection          HttpTransactionProtoCollection
 	apiKeyToPlaceholder *strings.Replacer
 	placeholderToAPIKey *strings.Replacer
-	resolver            resolver.DomainResolver
+	apiKeys             []string // API keys of the replacers
+	// knownAPIKeys holds every API key used since the serializer was created, by identifier, so that
+	// the transactions stored before a rotation are restored with their key, which the transaction
+	// then replaces by the key rotating it. The keys rotated before a restart are unknown, and the
+	// transactions stored with them are not restored.
+	knownAPIKeys map[string]string
+	resolver     resolver.DomainResolver
 }
 
 // NewHTTPTransactionsSerializer creates a new instance of HTTPTransactionsSerializer
 func NewHTTPTransactionsSerializer(resolver resolver.DomainResolver) *HTTPTransactionsSerializer {
-	apiKeyToPlaceholder, placeholderToAPIKey := createReplacers(resolver.GetAPIKeys())
-
-	return &HTTPTransactionsSerializer{
+	s := &HTTPTransactionsSerializer{
 		collection: HttpTransactionProtoCollection{
 			Version: transactionsSerializerVersion,
 		},
-		apiKeyToPlaceholder: apiKeyToPlaceholder,
-		placeholderToAPIKey: placeholderToAPIKey,
-		resolver:            resolver,
+		knownAPIKeys: make(map[string]string),
+		resolver:     resolver,
+	}
+	s.setAPIKeys(resolver.GetAPIKeys())
+	return s
+}
+
+// updateReplacers rebuilds the replacers when the API keys of the resolver were rotated
+func (s *HTTPTransactionsSerializer) updateReplacers() {
+	if apiKeys := s.resolver.GetAPIKeys(); !stringSlicesEqual(apiKeys, s.apiKeys) {
+		s.setAPIKeys(apiKeys)
 	}
 }
 
+func (s *HTTPTransactionsSerializer) setAPIKeys(apiKeys []string) {
+	for _, key := range apiKeys {
+		s.knownAPIKeys[apiKeyID(key)] = key
+	}
+	s.apiKeyToPlaceholder, s.placeholderToAPIKey = createReplacers(apiKeys, s.knownAPIKeys)
+	s.apiKeys = apiKeys
+}
+
 // Add adds a transaction to the serializer.
 // This function uses references on HTTPTransaction.Payload and HTTPTransaction.Headers
 // and so the transaction must not be updated until a call to `GetBytesAndReset`.
@@ -61,13 +93,24 @@ func (s *HTTPTransactionsSerializer) Add(transaction *transaction.HTTPTransactio
 		return fmt.Errorf("the domain of the transaction %v does not match the domain %v", transaction.Domain, d)
 	}
 
+	// Store the transaction with the current API key so that it can be restored
+	if !transaction.UpdateAPIKey() {
+		log.Debugf("The API key of a transaction was removed, not storing it")
+		return nil
+	}
+	s.updateReplacers()
+
 	priority, err := toTransactionPriorityProto(transaction.Priority)
 	if err != nil {
 		return err
 	}
 
 	var payload []byte
-	if transaction.Payload != nil {
+	if transaction.PayloadFile != nil {
+		if payload, err = transaction.PayloadFile.ReadAll(); err != nil {
+			return fmt.Errorf("cannot read the payload file of the transaction: %v", err)
+		}
+	} else if transaction.Payload != nil {
 		payload = *transaction.Payload
 	}
 
@@ -100,11 +143,16 @@ func (s *HTTPTransactionsSerializer) GetBytesAndReset() ([]byte, error) {
 
 // Deserialize deserializes from bytes.
 func (s *HTTPTransactionsSerializer) Deserialize(bytes []byte) ([]transaction.Transaction, int, error) {
-	collection := HttpTransactionProtoCollection{}
-
-	if err := proto.Unmarshal(bytes, &collection); err != nil {
+	collection, err := unmarshalCollection(bytes)
+	if err != nil {
 		return nil, 0, err
 	}
+	s.updateReplacers()
+
+	placeholderToAPIKey := s.placeholderToAPIKey
+	if collection.Version <= indexedAPIKeysSerializerVersion {
+		placeholderToAPIKey = createIndexedPlaceholderReplacer(s.apiKeys)
+	}
 
 	var httpTransactions []transaction.Transaction
 	errorCount := 0
@@ -115,9 +163,9 @@ func (s *HTTPTransactionsSerializer) Deserialize(bytes []byte) ([]transaction.Tr
 
 		priority, err := fromTransactionPriorityProto(tr.Priority)
 		if err == nil {
-			route, err = s.restoreAPIKeys(e.Route)
+			route, err = restoreAPIKeys(placeholderToAPIKey, e.Route)
 			if err == nil {
-				proto, err = s.fromHeaderProto(tr.Headers)
+				proto, err = fromHeaderProto(placeholderToAPIKey, tr.Headers)
 			}
 		}
 
@@ -139,6 +187,7 @@ func (s *HTTPTransactionsSerializer) Deserialize(bytes []byte) ([]transaction.Tr
 			Retryable:      tr.Retryable,
 			StorableOnDisk: true,
 			Priority:       priority,
+			APIKeyResolver: s.resolver.ResolveAPIKey,
 		}
 		tr.SetDefaultHandlers()
 		httpTransactions = append(httpTransactions, &tr)
@@ -146,12 +195,33 @@ func (s *HTTPTransactionsSerializer) Deserialize(bytes []byte) ([]transaction.Tr
 	return httpTransactions, errorCount, nil
 }
 
+// unmarshalCollection decodes the transactions of a `.retry` file, whose format version must be supported
+func unmarshalCollection(bytes []byte) (*HttpTransactionProtoCollection, error) {
+	collection := HttpTransactionProtoCollection{}
+	if err := proto.Unmarshal(bytes, &collection); err != nil {
+		return nil, err
+	}
+
+	if err := checkCollectionVersion(collection.Version); err != nil {
+		return nil, err
+	}
+	return &collection, nil
+}
+
+func checkCollectionVersion(version int32) error {
+	if version < minTransactionsSerializerVersion || version > transactionsSerializerVersion {
+		return fmt.Errorf("unsupported retry file format version %d, supported versions are %d to %d",
+			version, minTransactionsSerializerVersion, transactionsSerializerVersion)
+	}
+	return nil
+}
+
 func (s *HTTPTransactionsSerializer) replaceAPIKeys(str string) string {
 	return s.apiKeyToPlaceholder.Replace(str)
 }
 
-func (s *HTTPTransactionsSerializer) restoreAPIKeys(str string) (string, error) {
-	newStr := s.placeholderToAPIKey.Replace(str)
+func restoreAPIKeys(placeholderToAPIKey *strings.Replacer, str string) (string, error) {
+	newStr := placeholderToAPIKey.Replace(str)
 
 	if strings.Contains(newStr, placeHolderPrefix) {
 		return "", errors.New("cannot restore the transaction as an API Key is missing")
@@ -159,12 +229,12 @@ func (s *HTTPTransactionsSerializer) restoreAPIKeys(str string) (string, error)
 	return newStr, nil
 }
 
-func (s *HTTPTransactionsSerializer) fromHeaderProto(headersProto map[string]*HeaderValuesProto) (http.Header, error) {
+func fromHeaderProto(placeholderToAPIKey *strings.Replacer, headersProto map[string]*HeaderValuesProto) (http.Header, error) {
 	headers := make(http.Header)
 	for key, headerValuesProto := range headersProto {
 		var headerValues []string
 		for _, v := range headerValuesProto.Values {
-			value, err := s.restoreAPIKeys(v)
+			value, err := restoreAPIKeys(placeholderToAPIKey, v)
 			if err != nil {
 				return nil, err
 			}
@@ -181,6 +251,8 @@ func fromTransactionPriorityProto(priority TransactionPriorityProto) (transactio
 		return transaction.TransactionPriorityNormal, nil
 	case TransactionPriorityProto_HIGH:
 		return transaction.TransactionPriorityHigh, nil
+	case TransactionPriorityProto_LOW:
+		return transaction.TransactionPriorityLow, nil
 	default:
 		return transaction.TransactionPriorityNormal, fmt.Errorf("Unsupported priority %v", priority)
 	}
@@ -201,24 +273,58 @@ func toTransactionPriorityProto(priority transaction.Priority) (TransactionPrior
 		return TransactionPriorityProto_NORMAL, nil
 	case transaction.TransactionPriorityHigh:
 		return TransactionPriorityProto_HIGH, nil
+	case transaction.TransactionPriorityLow:
+		return TransactionPriorityProto_LOW, nil
 	default:
 		return TransactionPriorityProto_NORMAL, fmt.Errorf("Unsupported priority %v", priority)
 	}
 }
 
-func createReplacers(apiKeys []string) (*strings.Replacer, *strings.Replacer) {
+func stringSlicesEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// apiKeyID returns the identifier of an API key in the `.retry` files, a truncated hash of the key
+func apiKeyID(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:8])
+}
+
+// createReplacers returns the replacers of the current API keys by their placeholder, and of the
+// placeholders of the known API keys by their key
+func createReplacers(apiKeys []string, knownAPIKeys map[string]string) (*strings.Replacer, *strings.Replacer) {
+	var apiKeyPlaceholder []string
+	for _, k := range apiKeys {
+		apiKeyPlaceholder = append(apiKeyPlaceholder, k, fmt.Sprintf(placeHolderFormat, apiKeyID(k)))
+	}
+
+	var placeholderToAPIKey []string
+	for id, k := range knownAPIKeys {
+		placeholderToAPIKey = append(placeholderToAPIKey, fmt.Sprintf(placeHolderFormat, id), k)
+	}
+	return strings.NewReplacer(apiKeyPlaceholder...), strings.NewReplacer(placeholderToAPIKey...)
+}
+
+// createIndexedPlaceholderReplacer returns the replacer of the placeholders of the files written before
+// indexedAPIKeysSerializerVersion, which are the positions of the keys in the sorted list of keys
+func createIndexedPlaceholderReplacer(apiKeys []string) *strings.Replacer {
 	// Copy to not modify apiKeys order
 	keys := make([]string, len(apiKeys))
 	copy(keys, apiKeys)
 
 	// Sort to always have the same order
 	sort.Strings(keys)
-	var apiKeyPlaceholder []string
 	var placeholderToAPIKey []string
 	for i, k := range keys {
-		placeholder := fmt.Sprintf(placeHolderFormat, i)
-		apiKeyPlaceholder = append(apiKeyPlaceholder, k, placeholder)
-		placeholderToAPIKey = append(placeholderToAPIKey, placeholder, k)
+		placeholderToAPIKey = append(placeholderToAPIKey, fmt.Sprintf(placeHolderFormat, i), k)
 	}
-	return strings.NewReplacer(apiKeyPlaceholder...), strings.NewReplacer(placeholderToAPIKey...)
+	return strings.NewReplacer(placeholderToAPIKey...)
 }
@@ -6,6 +6,7 @@
 package retry
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
 	"testing"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/config/resolver"
 	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
+	proto "github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -100,10 +102,61 @@ func TestHTTPTransactionSerializerMissingAPIKey(t *testing.T) {
 	r.Equal(1, errorCount)
 }
 
+func TestHTTPTransactionSerializerRotatedAPIKey(t *testing.T) {
+	r := require.New(t)
+
+	res := resolver.NewSingleDomainResolver(domain, []string{apiKey1, apiKey2})
+	serializer := NewHTTPTransactionsSerializer(res)
+	r.NoError(serializer.Add(createHTTPTransactionWithHeaderTests(http.Header{"Key": []string{apiKey2}}, domain)))
+	bytes, err := serializer.GetBytesAndReset()
+	r.NoError(err)
+
+	// apiKey2 is rotated to a key sorted before apiKey1: the transaction keeps apiKey2, which the
+	// resolver replaces by the new key at send time
+	res.SetAPIKeys([]string{apiKey1, "aNewKey"})
+	transactions, errorCount, err := serializer.Deserialize(bytes)
+	r.NoError(err)
+	r.Equal(0, errorCount)
+	r.Len(transactions, 1)
+	r.Equal([]string{apiKey2}, transactions[0].(*transaction.HTTPTransaction).Headers["Key"])
+
+	// after a restart, the rotated key is unknown and the transaction isn't restored with another key
+	serializer = NewHTTPTransactionsSerializer(resolver.NewSingleDomainResolver(domain, []string{apiKey1, "aNewKey"}))
+	transactions, errorCount, err = serializer.Deserialize(bytes)
+	r.NoError(err)
+	r.Equal(1, errorCount)
+	r.Len(transactions, 0)
+}
+
+func TestHTTPTransactionSerializerIndexedAPIKeys(t *testing.T) {
+	r := require.New(t)
+
+	collection := HttpTransactionProtoCollection{
+		Version: indexedAPIKeysSerializerVersion,
+		Values: []*HttpTransactionProto{{
+			Domain:   domain,
+			Endpoint: &EndpointProto{Route: "route" + fmt.Sprintf(placeHolderFormat, 1), Name: "name"},
+			Headers:  map[string]*HeaderValuesProto{"Key": {Values: []string{fmt.Sprintf(placeHolderFormat, 0)}}},
+			Payload:  []byte{1, 2, 3},
+		}},
+	}
+	bytes, err := proto.Marshal(&collection)
+	r.NoError(err)
+
+	serializer := NewHTTPTransactionsSerializer(resolver.NewSingleDomainResolver(domain, []string{apiKey2, apiKey1}))
+	transactions, errorCount, err := serializer.Deserialize(bytes)
+	r.NoError(err)
+	r.Equal(0, errorCount)
+	r.Len(transactions, 1)
+	tr := transactions[0].(*transaction.HTTPTransaction)
+	r.Equal("route"+apiKey2, tr.Endpoint.Route)
+	r.Equal([]string{apiKey1}, tr.Headers["Key"])
+}
+
 func TestHTTPTransactionFieldsCount(t *testing.T) {
 	tr := transaction.HTTPTransaction{}
 	transactionType := reflect.TypeOf(tr)
-	assert.Equalf(t, 11, transactionType.NumField(),
+	assert.Equalf(t, 13, transactionType.NumField(),
 		"A field was added or remove from HTTPTransaction. "+
 			"You probably need to update the implementation of "+
 			"HTTPTransactionsSerializer and then adjust this unit test.")
@@ -0,0 +1,221 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package retry
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+)
+
+// TransactionInfo describes a transaction stored in a `.retry` file
+type TransactionInfo struct {
+	// Index is the position of the transaction in its file
+	Index     int
+	CreatedAt time.Time
+	// Endpoint is the name of the endpoint, which identifies the type of the payload
+	Endpoint string
+	// Route is the route of the endpoint, with placeholders instead of the API keys
+	Route       string
+	PayloadSize int
+	ErrorCount  int
+	Priority    string
+	Retryable   bool
+}
+
+// FileInfo describes a `.retry` file and its transactions
+type FileInfo struct {
+	Path string
+	// Domain is the domain the transactions are sent to, or the name of the
+	// folder of the file when it isn't a known domain
+	Domain       string
+	ModTime      time.Time
+	Size         int64
+	Version      int
+	Transactions []TransactionInfo
+	// Error is set when the file can't be read by this version of the Agent
+	Error string
+}
+
+// InspectFile describes a `.retry` file and its transactions
+func InspectFile(filePath string) (FileInfo, error) {
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return FileInfo{}, err
+	}
+
+	info := FileInfo{
+		Path:    filePath,
+		Domain:  filepath.Base(filepath.Dir(filePath)),
+		ModTime: stat.ModTime(),
+		Size:    stat.Size(),
+	}
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return info, err
+	}
+
+	collection := HttpTransactionProtoCollection{}
+	if err := proto.Unmarshal(content, &collection); err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+	info.Version = int(collection.Version)
+
+	if err := checkCollectionVersion(collection.Version); err != nil {
+		info.Error = err.Error()
+		return info, nil
+	}
+
+	for i, tr := range collection.Values {
+		transactionInfo := TransactionInfo{
+			Index:       i,
+			CreatedAt:   time.Unix(tr.CreatedAt, 0),
+			PayloadSize: len(tr.Payload),
+			ErrorCount:  int(tr.ErrorCount),
+			Priority:    tr.Priority.String(),
+			Retryable:   tr.Retryable,
+		}
+		if tr.Endpoint != nil {
+			transactionInfo.Endpoint = tr.Endpoint.Name
+			transactionInfo.Route = tr.Endpoint.Route
+		}
+		info.Transactions = append(info.Transactions, transactionInfo)
+	}
+
+	return info, nil
+}
+
+// ListFiles describes the `.retry` files of the domain folders of rootPath,
+// from the oldest to the newest. The folders of the given domains are
+// reported with the name of their domain.
+func ListFiles(rootPath string, domains []string) ([]FileInfo, error) {
+	domainsByFolder := make(map[string]string, len(domains))
+	for _, domain := range domains {
+		folder, err := DomainFolderName(domain)
+		if err != nil {
+			return nil, err
+		}
+		domainsByFolder[folder] = domain
+	}
+
+	entries, err := ioutil.ReadDir(rootPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []FileInfo
+	for _, entry := range entries {
+		if !entry.Mode().IsDir() {
+			continue
+		}
+
+		folderPath := path.Join(rootPath, entry.Name())
+		retryFiles, err := ioutil.ReadDir(folderPath)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, retryFile := range retryFiles {
+			if !retryFile.Mode().IsRegular() || filepath.Ext(retryFile.Name()) != retryTransactionsExtension {
+				continue
+			}
+
+			info, err := InspectFile(path.Join(folderPath, retryFile.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if domain, found := domainsByFolder[entry.Name()]; found {
+				info.Domain = domain
+			}
+			files = append(files, info)
+		}
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].ModTime.Before(files[j].ModTime)
+	})
+	return files, nil
+}
+
+// DropTransactions removes the transactions at the given indexes from a
+// `.retry` file, and the file when no transaction remains. The file keeps its
+// modification time, so that its transactions are retried in the same order.
+func DropTransactions(filePath string, indexes []int) error {
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	collection, err := unmarshalCollection(content)
+	if err != nil {
+		return err
+	}
+
+	dropped := make(map[int]struct{}, len(indexes))
+	for _, index := range indexes {
+		if index < 0 || index >= len(collection.Values) {
+			return fmt.Errorf("no transaction at index %d, the file has %d transactions", index, len(collection.Values))
+		}
+		dropped[index] = struct{}{}
+	}
+
+	values := make([]*HttpTransactionProto, 0, len(collection.Values))
+	for i, tr := range collection.Values {
+		if _, found := dropped[i]; !found {
+			values = append(values, tr)
+		}
+	}
+	if len(values) == 0 {
+		return os.Remove(filePath)
+	}
+	collection.Values = values
+
+	content, err = proto.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	// write a file without the `.retry` extension so that it isn't loaded before it's complete
+	tmpFile, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+
+	if _, err := tmpFile.Write(content); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmpFile.Name(), stat.ModTime(), stat.ModTime()); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpFile.Name(), filePath)
+}
+
+// RemoveFile removes a `.retry` file with all its transactions
+func RemoveFile(filePath string) error {
+	if filepath.Ext(filePath) != retryTransactionsExtension {
+		return fmt.Errorf("%s is not a %s file", filePath, retryTransactionsExtension)
+	}
+	return os.Remove(filePath)
+}
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package retry
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	proto "github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config/resolver"
+	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
+)
+
+func writeTransactionsFile(t *testing.T, folder string, endpointNames ...string) string {
+	serializer := NewHTTPTransactionsSerializer(resolver.NewSingleDomainResolver(domain, []string{apiKey1, apiKey2}))
+	for _, name := range endpointNames {
+		tr := createHTTPTransactionTests(domain)
+		tr.Endpoint = transaction.Endpoint{Route: "/api/v1/" + name, Name: name}
+		require.NoError(t, serializer.Add(tr))
+	}
+	bytes, err := serializer.GetBytesAndReset()
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(folder, 0700))
+	filePath := path.Join(folder, "transactions"+retryTransactionsExtension)
+	require.NoError(t, ioutil.WriteFile(filePath, bytes, 0600))
+	return filePath
+}
+
+func TestRetryFiles(t *testing.T) {
+	root, err := ioutil.TempDir("", "retry")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	domainFolder, err := DomainFolderName(domain)
+	require.NoError(t, err)
+	filePath := writeTransactionsFile(t, path.Join(root, domainFolder), "series_v1", "check_run_v1", "events_v1")
+
+	// a file written by a newer version of the agent
+	newerVersion, err := proto.Marshal(&HttpTransactionProtoCollection{Version: transactionsSerializerVersion + 1})
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(path.Join(root, "unknown"), 0700))
+	newerFilePath := path.Join(root, "unknown", "newer"+retryTransactionsExtension)
+	require.NoError(t, ioutil.WriteFile(newerFilePath, newerVersion, 0600))
+	require.NoError(t, os.Chtimes(newerFilePath, time.Now().Add(time.Hour), time.Now().Add(time.Hour)))
+
+	files, err := ListFiles(root, []string{domain})
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	assert.Equal(t, filePath, files[0].Path)
+	assert.Equal(t, domain, files[0].Domain)
+	assert.Equal(t, transactionsSerializerVersion, files[0].Version)
+	assert.Empty(t, files[0].Error)
+	require.Len(t, files[0].Transactions, 3)
+	assert.Equal(t, 1, files[0].Transactions[1].Index)
+	assert.Equal(t, "check_run_v1", files[0].Transactions[1].Endpoint)
+	assert.Equal(t, 3, files[0].Transactions[1].PayloadSize)
+	assert.Equal(t, "HIGH", files[0].Transactions[1].Priority)
+
+	assert.Equal(t, "unknown", files[1].Domain)
+	assert.NotEmpty(t, files[1].Error)
+	assert.Error(t, DropTransactions(newerFilePath, []int{0}))
+	_, _, err = NewHTTPTransactionsSerializer(resolver.NewSingleDomainResolver(domain, nil)).Deserialize(newerVersion)
+	assert.Error(t, err)
+
+	// the remaining transactions are kept in order, in a file with the same modification time
+	assert.Error(t, DropTransactions(filePath, []int{3}))
+	require.NoError(t, DropTransactions(filePath, []int{0, 2}))
+	info, err := InspectFile(filePath)
+	require.NoError(t, err)
+	require.Len(t, info.Transactions, 1)
+	assert.Equal(t, "check_run_v1", info.Transactions[0].Endpoint)
+	assert.Equal(t, files[0].ModTime, info.ModTime)
+
+	require.NoError(t, DropTransactions(filePath, []int{0}))
+	_, err = os.Stat(filePath)
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Error(t, RemoveFile(path.Join(root, "unknown")))
+	require.NoError(t, RemoveFile(newerFilePath))
+	files, err = ListFiles(root, []string{domain})
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
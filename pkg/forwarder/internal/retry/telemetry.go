@@ -62,6 +62,7 @@ var (
 	currentMemSizeInBytesTelemetry    *gaugeExpvar
 	transactionsCountTelemetry        *gaugeExpvar
 	transactionsDroppedCountTelemetry *counterExpvar
+	transactionsRejectedTelemetry     *counterExpvar
 	errorsCountTelemetry              *counterExpvar
 
 	fileStorageExpvar                       = expvar.Map{}
@@ -123,6 +124,12 @@ func init() {
 		domainTag,
 		"The number of transactions dropped because the retry queue is full",
 		&transactionContainerExpvar)
+	transactionsRejectedTelemetry = newCounterExpvar(
+		"transaction_container",
+		"transactions_rejected_count",
+		[]string{"domain", "priority"},
+		"The number of transactions not added to the retry queue because they cannot preempt higher priority transactions",
+		&transactionContainerExpvar)
 	errorsCountTelemetry = newCounterExpvar(
 		"transaction_container",
 		"errors_count",
@@ -229,6 +236,10 @@ func (t TransactionRetryQueueTelemetry) addTransactionsDroppedCount(count int) {
 	transactionsDroppedCountTelemetry.add(float64(count), t.domainName)
 }
 
+func (t TransactionRetryQueueTelemetry) addTransactionsRejectedCount(priority transaction.Priority) {
+	transactionsRejectedTelemetry.add(1, t.domainName, priority.String())
+}
+
 func (t TransactionRetryQueueTelemetry) incErrorsCount() {
 	errorsCountTelemetry.add(1, t.domainName)
 }
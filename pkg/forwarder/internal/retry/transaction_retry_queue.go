@@ -28,6 +28,20 @@ type TransactionPrioritySorter interface {
 	Sort([]transaction.Transaction)
 }
 
+// dropPolicy defines how a transaction makes room for itself when the retry queue is full.
+type dropPolicy string
+
+const (
+	// dropPolicyPreempt only removes transactions whose priority is lower or equal
+	// to the priority of the added transaction. The added transaction is dropped
+	// if it would require removing higher priority transactions.
+	dropPolicyPreempt dropPolicy = "preempt"
+
+	// dropPolicyDropOldest always adds the transaction and removes transactions
+	// by increasing priority, whatever the priority of the added transaction.
+	dropPolicyDropOldest dropPolicy = "drop_oldest"
+)
+
 // TransactionRetryQueue stores transactions in memory and flush them to disk when the memory
 // limit is exceeded.
 type TransactionRetryQueue struct {
@@ -38,6 +52,7 @@ type TransactionRetryQueue struct {
 	dropPrioritySorter            TransactionPrioritySorter
 	optionalTransactionSerializer TransactionSerializer
 	telemetry                     TransactionRetryQueueTelemetry
+	dropPolicies                  map[transaction.Priority]dropPolicy
 	mutex                         sync.RWMutex
 }
 
@@ -66,12 +81,53 @@ func BuildTransactionRetryQueue(
 		}
 	}
 
-	return NewTransactionRetryQueue(
+	queue := NewTransactionRetryQueue(
 		dropPrioritySorter,
 		storage,
 		maxMemSizeInBytes,
 		flushToStorageRatio,
 		NewTransactionRetryQueueTelemetry(resolver.GetBaseDomain()))
+	queue.dropPolicies = dropPoliciesFromConfig()
+	return queue
+}
+
+// dropPoliciesFromConfig reads `forwarder_retry_queue_drop_policy_by_priority`.
+// Priorities without a valid policy use dropPolicyPreempt.
+func dropPoliciesFromConfig() map[transaction.Priority]dropPolicy {
+	policies := make(map[transaction.Priority]dropPolicy)
+	priorities := []transaction.Priority{
+		transaction.TransactionPriorityLow,
+		transaction.TransactionPriorityNormal,
+		transaction.TransactionPriorityHigh,
+	}
+
+	for name, value := range config.Datadog.GetStringMapString("forwarder_retry_queue_drop_policy_by_priority") {
+		policy := dropPolicy(value)
+		if policy != dropPolicyPreempt && policy != dropPolicyDropOldest {
+			log.Warnf("Invalid retry queue drop policy %q for priority %q, using %q", value, name, dropPolicyPreempt)
+			continue
+		}
+
+		found := false
+		for _, priority := range priorities {
+			if priority.String() == name {
+				policies[priority] = policy
+				found = true
+			}
+		}
+		if !found {
+			log.Warnf("Unknown transaction priority %q in 'forwarder_retry_queue_drop_policy_by_priority'", name)
+		}
+	}
+	return policies
+}
+
+// getDropPolicy returns the drop policy of the transactions with the given priority.
+func (tc *TransactionRetryQueue) getDropPolicy(priority transaction.Priority) dropPolicy {
+	if policy, found := tc.dropPolicies[priority]; found {
+		return policy
+	}
+	return dropPolicyPreempt
 }
 
 // NewTransactionRetryQueue creates a new instance of NewTransactionRetryQueue
@@ -99,7 +155,11 @@ func NewTransactionRetryQueue(
 // 100*0.6=60 bytes must be flushed on disk.
 // The first 3 transactions are flushed to the disk as 10 + 20 + 30 >= 60
 // If disk serialization failed or is not enabled, remove old transactions such as
-// `currentMemSizeInBytes` <= `maxMemSizeInBytes`
+// `currentMemSizeInBytes` <= `maxMemSizeInBytes`.
+// Transactions are removed by increasing priority. With the default drop policy,
+// a transaction never preempts transactions with a higher priority: if making room
+// for `t` requires dropping higher priority transactions, `t` is dropped instead.
+// The drop policy can be set per priority with `forwarder_retry_queue_drop_policy_by_priority`.
 func (tc *TransactionRetryQueue) Add(t transaction.Transaction) (int, error) {
 	tc.mutex.Lock()
 	defer tc.mutex.Unlock()
@@ -112,6 +172,7 @@ func (tc *TransactionRetryQueue) Add(t transaction.Transaction) (int, error) {
 			if err := tc.optionalTransactionSerializer.Serialize(payloads); err != nil {
 				diskErr = multierror.Append(diskErr, err)
 			}
+			releaseTransactions(payloads)
 		}
 		if diskErr != nil {
 			diskErr = fmt.Errorf("Cannot store transactions on disk: %v", diskErr)
@@ -123,9 +184,16 @@ func (tc *TransactionRetryQueue) Add(t transaction.Transaction) (int, error) {
 	payloadSizeInBytesToDrop := (tc.currentMemSizeInBytes + payloadSize) - tc.maxMemSizeInBytes
 	inMemTransactionDroppedCount := 0
 	if payloadSizeInBytesToDrop > 0 {
+		if tc.getDropPolicy(t.GetPriority()) == dropPolicyPreempt && !tc.canPreempt(t.GetPriority(), payloadSizeInBytesToDrop) {
+			tc.telemetry.addTransactionsDroppedCount(1)
+			tc.telemetry.addTransactionsRejectedCount(t.GetPriority())
+			t.Release()
+			return 1, diskErr
+		}
 		transactions := tc.extractTransactionsFromMemory(payloadSizeInBytesToDrop)
 		inMemTransactionDroppedCount = len(transactions)
 		tc.telemetry.addTransactionsDroppedCount(inMemTransactionDroppedCount)
+		releaseTransactions(transactions)
 	}
 
 	tc.transactions = append(tc.transactions, t)
@@ -162,6 +230,20 @@ func (tc *TransactionRetryQueue) ExtractTransactions() ([]transaction.Transactio
 	return transactions, nil
 }
 
+// ExtractInMemoryTransactions extracts the transactions stored in memory,
+// the transactions stored on the disk are kept.
+func (tc *TransactionRetryQueue) ExtractInMemoryTransactions() []transaction.Transaction {
+	tc.mutex.Lock()
+	defer tc.mutex.Unlock()
+
+	transactions := tc.transactions
+	tc.transactions = nil
+	tc.currentMemSizeInBytes = 0
+	tc.telemetry.setCurrentMemSizeInBytes(tc.currentMemSizeInBytes)
+	tc.telemetry.setTransactionsCount(len(tc.transactions))
+	return transactions
+}
+
 // GetCurrentMemSizeInBytes gets the current memory usage in bytes
 func (tc *TransactionRetryQueue) getCurrentMemSizeInBytes() int {
 	tc.mutex.RLock()
@@ -204,6 +286,21 @@ func (tc *TransactionRetryQueue) extractTransactionsForDisk(payloadSize int) [][
 	return payloadsGroupToFlush
 }
 
+// canPreempt returns whether `payloadSizeInBytesToDrop` bytes can be removed from
+// memory without dropping a transaction whose priority is greater than `priority`.
+func (tc *TransactionRetryQueue) canPreempt(priority transaction.Priority, payloadSizeInBytesToDrop int) bool {
+	preemptableSizeInBytes := 0
+	for _, t := range tc.transactions {
+		if t.GetPriority() <= priority {
+			preemptableSizeInBytes += t.GetPayloadSize()
+		}
+	}
+
+	// When all the transactions can be preempted, keep the historical behavior which
+	// adds the transaction even if the queue cannot be made small enough.
+	return preemptableSizeInBytes >= payloadSizeInBytesToDrop || preemptableSizeInBytes == tc.currentMemSizeInBytes
+}
+
 func (tc *TransactionRetryQueue) extractTransactionsFromMemory(payloadSizeInBytesToExtract int) []transaction.Transaction {
 	i := 0
 	sizeInBytesExtracted := 0
@@ -220,3 +317,11 @@ func (tc *TransactionRetryQueue) extractTransactionsFromMemory(payloadSizeInByte
 	tc.currentMemSizeInBytes -= sizeInBytesExtracted
 	return transactionsExtracted
 }
+
+// releaseTransactions releases the transactions which are removed from memory,
+// they are either stored on disk or dropped.
+func releaseTransactions(transactions []transaction.Transaction) {
+	for _, t := range transactions {
+		t.Release()
+	}
+}
@@ -1,8 +1,10 @@
 package retry
 
 import (
+	"io/ioutil"
 	"testing"
 
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/config/resolver"
 	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
 	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
@@ -79,6 +81,33 @@ func TestTransactionRetryQueueNoTransactionStorage(t *testing.T) {
 	assertPayloadSizeFromExtractTransactions(a, container, []int{11, 30})
 }
 
+func TestTransactionRetryQueueFilePayloads(t *testing.T) {
+	a := assert.New(t)
+	q, clean := newOnDiskRetryQueueTest(a)
+	defer clean()
+
+	container := NewTransactionRetryQueue(createDropPrioritySorter(), q, 50, 0.1, NewTransactionRetryQueueTelemetry("domain"))
+
+	// The payload file is read when the transaction is flushed to disk and removed
+	stored := createTransactionWithFilePayload(a, 30)
+	storedPath := stored.PayloadFile.Path
+	container.Add(stored)
+	container.Add(createTransactionWithPayloadSize(40))
+	a.NoFileExists(storedPath)
+
+	assertPayloadSizeFromExtractTransactions(a, container, []int{40})
+	assertPayloadSizeFromExtractTransactions(a, container, []int{30})
+
+	// The payload file is removed when the transaction is dropped
+	container = NewTransactionRetryQueue(createDropPrioritySorter(), nil, 50, 0.1, NewTransactionRetryQueueTelemetry("domain"))
+	dropped := createTransactionWithFilePayload(a, 30)
+	droppedPath := dropped.PayloadFile.Path
+	container.Add(dropped)
+	dropCount, _ := container.Add(createTransactionWithPayloadSize(40))
+	a.Equal(1, dropCount)
+	a.NoFileExists(droppedPath)
+}
+
 func TestTransactionRetryQueueZeroMaxMemSizeInBytes(t *testing.T) {
 	a := assert.New(t)
 	q, clean := newOnDiskRetryQueueTest(a)
@@ -97,6 +126,100 @@ func TestTransactionRetryQueueZeroMaxMemSizeInBytes(t *testing.T) {
 	a.Equal(1, inMemTrDropped)
 }
 
+func TestTransactionRetryQueuePreemption(t *testing.T) {
+	a := assert.New(t)
+	container := NewTransactionRetryQueue(createDropPrioritySorter(), nil, 50, 0.1, NewTransactionRetryQueueTelemetry("domain"))
+
+	for _, payloadSize := range []int{20, 20} {
+		_, err := container.Add(createTransactionWithPriority(payloadSize, transaction.TransactionPriorityHigh))
+		a.NoError(err)
+	}
+	_, err := container.Add(createTransactionWithPriority(10, transaction.TransactionPriorityLow))
+	a.NoError(err)
+
+	// A low priority transaction cannot preempt high priority transactions.
+	dropCount, err := container.Add(createTransactionWithPriority(15, transaction.TransactionPriorityLow))
+	a.NoError(err)
+	a.Equal(1, dropCount)
+	a.Equal(20+20+10, container.getCurrentMemSizeInBytes())
+
+	// A high priority transaction preempts the low priority transaction.
+	dropCount, err = container.Add(createTransactionWithPriority(10, transaction.TransactionPriorityHigh))
+	a.NoError(err)
+	a.Equal(1, dropCount)
+	a.Equal(20+20+10, container.getCurrentMemSizeInBytes())
+
+	transactions, err := container.ExtractTransactions()
+	a.NoError(err)
+	a.Len(transactions, 3)
+	for _, tr := range transactions {
+		a.Equal(transaction.TransactionPriorityHigh, tr.GetPriority())
+	}
+}
+
+// In the core agent forwarder, events and service checks (high priority) share the
+// retry queue with series and sketches (normal priority).
+func TestTransactionRetryQueueNormalCannotPreemptHigh(t *testing.T) {
+	a := assert.New(t)
+	container := NewTransactionRetryQueue(createDropPrioritySorter(), nil, 40, 0.1, NewTransactionRetryQueueTelemetry("domain"))
+
+	for _, payloadSize := range []int{20, 20} {
+		_, err := container.Add(createTransactionWithPriority(payloadSize, transaction.TransactionPriorityHigh))
+		a.NoError(err)
+	}
+
+	dropCount, err := container.Add(createTransactionWithPriority(10, transaction.TransactionPriorityNormal))
+	a.NoError(err)
+	a.Equal(1, dropCount)
+	a.Equal(2, container.GetTransactionCount())
+}
+
+func TestTransactionRetryQueueDropOldestPolicy(t *testing.T) {
+	a := assert.New(t)
+	container := NewTransactionRetryQueue(createDropPrioritySorter(), nil, 40, 0.1, NewTransactionRetryQueueTelemetry("domain"))
+	container.dropPolicies = map[transaction.Priority]dropPolicy{transaction.TransactionPriorityNormal: dropPolicyDropOldest}
+
+	for _, payloadSize := range []int{20, 20} {
+		_, err := container.Add(createTransactionWithPriority(payloadSize, transaction.TransactionPriorityHigh))
+		a.NoError(err)
+	}
+
+	// With `drop_oldest`, a normal priority transaction is always added.
+	dropCount, err := container.Add(createTransactionWithPriority(10, transaction.TransactionPriorityNormal))
+	a.NoError(err)
+	a.Equal(1, dropCount)
+	a.Equal(20+10, container.getCurrentMemSizeInBytes())
+
+	// Other priorities keep the default policy.
+	dropCount, err = container.Add(createTransactionWithPriority(20, transaction.TransactionPriorityLow))
+	a.NoError(err)
+	a.Equal(1, dropCount)
+	a.Equal(20+10, container.getCurrentMemSizeInBytes())
+}
+
+func TestDropPoliciesFromConfig(t *testing.T) {
+	a := assert.New(t)
+	mockConfig := config.Mock()
+	mockConfig.Set("forwarder_retry_queue_drop_policy_by_priority", map[string]string{
+		"normal":  "drop_oldest",
+		"high":    "preempt",
+		"low":     "invalid",
+		"unknown": "drop_oldest",
+	})
+	defer mockConfig.Set("forwarder_retry_queue_drop_policy_by_priority", map[string]string{})
+
+	a.Equal(map[transaction.Priority]dropPolicy{
+		transaction.TransactionPriorityNormal: dropPolicyDropOldest,
+		transaction.TransactionPriorityHigh:   dropPolicyPreempt,
+	}, dropPoliciesFromConfig())
+}
+
+func createTransactionWithPriority(payloadSize int, priority transaction.Priority) *transaction.HTTPTransaction {
+	tr := createTransactionWithPayloadSize(payloadSize)
+	tr.Priority = priority
+	return tr
+}
+
 func createTransactionWithPayloadSize(payloadSize int) *transaction.HTTPTransaction {
 	tr := transaction.NewHTTPTransaction()
 	payload := make([]byte, payloadSize)
@@ -104,6 +227,18 @@ func createTransactionWithPayloadSize(payloadSize int) *transaction.HTTPTransact
 	return tr
 }
 
+func createTransactionWithFilePayload(a *assert.Assertions, payloadSize int) *transaction.HTTPTransaction {
+	f, err := ioutil.TempFile("", "payload-*")
+	a.NoError(err)
+	_, err = f.Write(make([]byte, payloadSize))
+	a.NoError(err)
+	a.NoError(f.Close())
+
+	tr := transaction.NewHTTPTransaction()
+	tr.PayloadFile = transaction.NewFilePayload(f.Name(), payloadSize)
+	return tr
+}
+
 func assertPayloadSizeFromExtractTransactions(
 	a *assert.Assertions,
 	container *TransactionRetryQueue,
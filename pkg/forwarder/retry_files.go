@@ -0,0 +1,64 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package forwarder
+
+import (
+	"path"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder/internal/retry"
+)
+
+// coreAgentFolder is the folder of the `.retry` files of the core agent
+const coreAgentFolder = "core"
+
+// RetryFileInfo describes a `.retry` file and the transactions it stores
+type RetryFileInfo = retry.FileInfo
+
+// RetryTransactionInfo describes a transaction stored in a `.retry` file
+type RetryTransactionInfo = retry.TransactionInfo
+
+// retryStoragePath returns the folder of the `.retry` files of an agent
+func retryStoragePath(agentFolder string) string {
+	storagePath := config.Datadog.GetString("forwarder_storage_path")
+	if storagePath == "" {
+		storagePath = path.Join(config.Datadog.GetString("run_path"), "transactions_to_retry")
+	}
+	return path.Join(storagePath, agentFolder)
+}
+
+// ListRetryFiles describes the `.retry` files of the core agent, from the
+// oldest to the newest
+func ListRetryFiles() ([]RetryFileInfo, error) {
+	endpoints, err := config.GetMultipleEndpoints()
+	if err != nil {
+		return nil, err
+	}
+
+	domains := make([]string, 0, len(endpoints))
+	for domain := range endpoints {
+		domain, _ := config.AddAgentVersionToDomain(domain, "app")
+		domains = append(domains, domain)
+	}
+
+	return retry.ListFiles(retryStoragePath(coreAgentFolder), domains)
+}
+
+// InspectRetryFile describes a `.retry` file and its transactions
+func InspectRetryFile(filePath string) (RetryFileInfo, error) {
+	return retry.InspectFile(filePath)
+}
+
+// DropRetryTransactions removes the transactions at the given indexes from a
+// `.retry` file
+func DropRetryTransactions(filePath string, indexes []int) error {
+	return retry.DropTransactions(filePath, indexes)
+}
+
+// RemoveRetryFile removes a `.retry` file with all its transactions
+func RemoveRetryFile(filePath string) error {
+	return retry.RemoveFile(filePath)
+}
@@ -49,6 +49,7 @@ func (f *SyncForwarder) sendHTTPTransactions(transactions []*transaction.HTTPTra
 			log.Debug("Retrying transaction")
 			if err := t.Process(context.Background(), f.client); err != nil {
 				log.Errorf("SyncForwarder.sendHTTPTransactions final attempt: %s", err)
+				t.Release()
 			}
 		}
 	}
@@ -123,6 +124,11 @@ func (f *SyncForwarder) SubmitProcessDiscoveryChecks(payload Payloads, extra htt
 	return f.defaultForwarder.submitProcessLikePayload(endpoints.ProcessDiscoveryEndpoint, payload, extra, true)
 }
 
+// SubmitSoftwareInventory sends the software inventory
+func (f *SyncForwarder) SubmitSoftwareInventory(payload Payloads, extra http.Header) (chan Response, error) {
+	return f.defaultForwarder.submitProcessLikePayload(endpoints.SoftwareInventoryEndpoint, payload, extra, true)
+}
+
 // SubmitRTProcessChecks sends real time process checks
 func (f *SyncForwarder) SubmitRTProcessChecks(payload Payloads, extra http.Header) (chan Response, error) {
 	return f.defaultForwarder.submitProcessLikePayload(endpoints.RtProcessesEndpoint, payload, extra, false)
@@ -147,3 +153,8 @@ func (f *SyncForwarder) SubmitConnectionChecks(payload Payloads, extra http.Head
 func (f *SyncForwarder) SubmitOrchestratorChecks(payload Payloads, extra http.Header, payloadType int) (chan Response, error) {
 	return f.defaultForwarder.SubmitOrchestratorChecks(payload, extra, payloadType)
 }
+
+// SubmitOrchestratorChecksFile sends orchestrator checks stored in a file
+func (f *SyncForwarder) SubmitOrchestratorChecksFile(payload *transaction.FilePayload, extra http.Header, payloadType int) (chan Response, error) {
+	return f.defaultForwarder.SubmitOrchestratorChecksFile(payload, extra, payloadType)
+}
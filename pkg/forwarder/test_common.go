@@ -65,6 +65,8 @@ func (t *testTransaction) GetPayloadSize() int {
 	return t.Called().Get(0).(int)
 }
 
+func (t *testTransaction) Release() {}
+
 func (t *testTransaction) SerializeTo(serializer transaction.TransactionsSerializer) error {
 	return nil
 }
@@ -142,6 +144,11 @@ func (tf *MockedForwarder) SubmitProcessDiscoveryChecks(payload Payloads, extra
 	return nil, tf.Called(payload, extra).Error(0)
 }
 
+// SubmitSoftwareInventory mock
+func (tf *MockedForwarder) SubmitSoftwareInventory(payload Payloads, extra http.Header) (chan Response, error) {
+	return nil, tf.Called(payload, extra).Error(0)
+}
+
 // SubmitRTProcessChecks mock
 func (tf *MockedForwarder) SubmitRTProcessChecks(payload Payloads, extra http.Header) (chan Response, error) {
 	return nil, tf.Called(payload, extra).Error(0)
@@ -166,3 +173,8 @@ func (tf *MockedForwarder) SubmitConnectionChecks(payload Payloads, extra http.H
 func (tf *MockedForwarder) SubmitOrchestratorChecks(payload Payloads, extra http.Header, payloadType int) (chan Response, error) {
 	return nil, tf.Called(payload, extra).Error(0)
 }
+
+// SubmitOrchestratorChecksFile mock
+func (tf *MockedForwarder) SubmitOrchestratorChecksFile(payload *transaction.FilePayload, extra http.Header, payloadType int) (chan Response, error) {
+	return nil, tf.Called(payload, extra).Error(0)
+}
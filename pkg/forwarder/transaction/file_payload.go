@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package transaction
+
+import (
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// FilePayload is a payload stored in a temporary file instead of memory
+// because it was too big. A payload is sent to several domains and API keys,
+// the file is shared by the transactions and removed when the last one
+// releases it.
+type FilePayload struct {
+	// Path is the path of the file holding the payload.
+	Path string
+	// Size is the size of the payload in bytes.
+	Size int
+
+	refs int32
+}
+
+// NewFilePayload returns a payload stored in the file at `path`. The caller
+// holds a reference on the payload and must release it.
+func NewFilePayload(path string, size int) *FilePayload {
+	return &FilePayload{
+		Path: path,
+		Size: size,
+		refs: 1,
+	}
+}
+
+// Retain adds a reference on the payload.
+func (p *FilePayload) Retain() *FilePayload {
+	atomic.AddInt32(&p.refs, 1)
+	return p
+}
+
+// Release removes a reference on the payload, the file is removed when there
+// are no references left.
+func (p *FilePayload) Release() {
+	if atomic.AddInt32(&p.refs, -1) != 0 {
+		return
+	}
+	p.Remove()
+}
+
+// Released returns true when there are no references left on the payload.
+func (p *FilePayload) Released() bool {
+	return atomic.LoadInt32(&p.refs) <= 0
+}
+
+// Remove removes the file holding the payload, whatever the references left
+// on it. The transactions still referencing it fail to open it and are dropped.
+func (p *FilePayload) Remove() {
+	if err := os.Remove(p.Path); err != nil && !os.IsNotExist(err) {
+		log.Warnf("Could not remove the payload file %q: %v", p.Path, err)
+	}
+}
+
+// Open opens the file holding the payload.
+func (p *FilePayload) Open() (*os.File, error) {
+	return os.Open(p.Path)
+}
+
+// ReadAll reads the whole payload in memory.
+func (p *FilePayload) ReadAll() ([]byte, error) {
+	return ioutil.ReadFile(p.Path)
+}
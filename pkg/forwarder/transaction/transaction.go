@@ -11,10 +11,12 @@ import (
 	"crypto/tls"
 	"expvar"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptrace"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -151,9 +153,14 @@ func init() {
 	TransactionsExpvars.Set("HTTPErrorsByCode", &transactionsHTTPErrorsByCode)
 }
 
+// apiKeyHTTPHeaderKey is the header holding the API key of the transactions
+const apiKeyHTTPHeaderKey = "DD-Api-Key"
+
 // Priority defines the priority of a transaction
-// Transactions with priority `TransactionPriorityNormal` are dropped from the retry queue
-// before dropping transactions with priority `TransactionPriorityHigh`.
+// Transactions with priority `TransactionPriorityLow` are dropped from the retry queue
+// before dropping transactions with priority `TransactionPriorityNormal` which are
+// dropped before transactions with priority `TransactionPriorityHigh`.
+// A transaction never preempts a transaction with a higher priority in the retry queue.
 type Priority int
 
 const (
@@ -162,8 +169,27 @@ const (
 
 	// TransactionPriorityHigh defines a transaction with an high priority
 	TransactionPriorityHigh Priority = iota
+
+	// TransactionPriorityLow defines a transaction with a low priority. It is used for
+	// bulky payloads (processes, containers...) that should not take the place of
+	// other payloads if they share a retry queue.
+	TransactionPriorityLow Priority = -1
 )
 
+// String returns a string representation of the priority
+func (p Priority) String() string {
+	switch p {
+	case TransactionPriorityLow:
+		return "low"
+	case TransactionPriorityNormal:
+		return "normal"
+	case TransactionPriorityHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
 // HTTPTransaction represents one Payload for one Endpoint on one Domain.
 type HTTPTransaction struct {
 	// Domain represents the domain target by the HTTPTransaction.
@@ -174,6 +200,9 @@ type HTTPTransaction struct {
 	Headers http.Header
 	// Payload is the content delivered to the backend.
 	Payload *[]byte
+	// PayloadFile holds the content delivered to the backend instead of Payload
+	// when it was too big to be kept in memory.
+	PayloadFile *FilePayload
 	// ErrorCount is the number of times this HTTPTransaction failed to be processed.
 	ErrorCount int
 
@@ -190,6 +219,10 @@ type HTTPTransaction struct {
 	// CompletionHandler will be called with a transaction after it has been successfully sent
 	// This field is not restored when a transaction is deserialized from the disk (the default value is used).
 	CompletionHandler HTTPCompletionHandler
+	// APIKeyResolver returns the API key replacing the API key of the transaction when it was rotated
+	// after the transaction was created, and false when the API key was removed. nil when the API key
+	// of the transaction never changes.
+	APIKeyResolver func(apiKey string) (string, bool)
 
 	Priority Priority
 }
@@ -208,6 +241,10 @@ type Transaction interface {
 	GetEndpointName() string
 	GetPayloadSize() int
 
+	// Release frees the resources held by the transaction once it was sent
+	// or dropped.
+	Release()
+
 	// This method serializes the transaction to `TransactionsSerializer`.
 	// It forces a new implementation of `Transaction` to define how to
 	// serialize the transaction to `TransactionsSerializer` as a `Transaction`
@@ -257,6 +294,9 @@ func (t *HTTPTransaction) GetEndpointName() string {
 
 // GetPayloadSize returns the size of the payload.
 func (t *HTTPTransaction) GetPayloadSize() int {
+	if t.PayloadFile != nil {
+		return t.PayloadFile.Size
+	}
 	if t.Payload != nil {
 		return len(*t.Payload)
 	}
@@ -276,21 +316,52 @@ func (t *HTTPTransaction) Process(ctx context.Context, client *http.Client) erro
 
 	// If the txn is retryable, return the error (if present) to the worker to allow it to be retried
 	// Otherwise, return nil so the txn won't be retried.
-	if t.Retryable {
+	if t.Retryable && err != nil {
 		return err
 	}
 
+	t.Release()
 	return nil
 }
 
+// Release removes the reference of the transaction on its payload file, if any.
+func (t *HTTPTransaction) Release() {
+	if t.PayloadFile != nil {
+		t.PayloadFile.Release()
+		t.PayloadFile = nil
+	}
+}
+
 // internalProcess does the  work of actually sending the http request to the specified domain
 // This will return  (http status code, response body, error).
 func (t *HTTPTransaction) internalProcess(ctx context.Context, client *http.Client) (int, []byte, error) {
-	reader := bytes.NewReader(*t.Payload)
-	url := t.Domain + t.Endpoint.Route
 	transactionEndpointName := t.GetEndpointName()
+	if !t.UpdateAPIKey() {
+		log.Debugf("The API key of the transaction to %q was removed, dropping it", scrubber.ScrubURL(t.Domain+t.Endpoint.Route))
+		TransactionsDroppedByEndpoint.Add(transactionEndpointName, 1)
+		TransactionsDropped.Add(1)
+		TlmTxDropped.Inc(t.Domain, transactionEndpointName)
+		return 0, nil, nil
+	}
+
+	url := t.Domain + t.Endpoint.Route
 	logURL := scrubber.ScrubURL(url) // sanitized url that can be logged
 
+	var reader io.Reader
+	if t.PayloadFile != nil {
+		f, err := t.PayloadFile.Open()
+		if err != nil {
+			log.Errorf("Could not open the payload file of the transaction to %q (dropping transaction): %s", logURL, err)
+			transactionsErrors.Add(1)
+			tlmTxErrors.Inc(t.Domain, transactionEndpointName, "payload_file")
+			return 0, nil, nil
+		}
+		defer f.Close()
+		reader = f
+	} else {
+		reader = bytes.NewReader(*t.Payload)
+	}
+
 	req, err := http.NewRequest("POST", url, reader)
 	if err != nil {
 		log.Errorf("Could not create request for transaction to invalid URL %q (dropping transaction): %s", logURL, err)
@@ -301,6 +372,9 @@ func (t *HTTPTransaction) internalProcess(ctx context.Context, client *http.Clie
 	}
 	req = req.WithContext(ctx)
 	req.Header = t.Headers
+	if t.PayloadFile != nil {
+		req.ContentLength = int64(t.PayloadFile.Size)
+	}
 	resp, err := client.Do(req)
 
 	if err != nil {
@@ -376,6 +450,27 @@ func (t *HTTPTransaction) internalProcess(ctx context.Context, client *http.Clie
 	return resp.StatusCode, body, nil
 }
 
+// UpdateAPIKey replaces the API key of the transaction when it was rotated,
+// it returns false when the API key was removed.
+func (t *HTTPTransaction) UpdateAPIKey() bool {
+	if t.APIKeyResolver == nil {
+		return true
+	}
+	apiKey := t.Headers.Get(apiKeyHTTPHeaderKey)
+	if apiKey == "" {
+		return true
+	}
+	newAPIKey, found := t.APIKeyResolver(apiKey)
+	if !found {
+		return false
+	}
+	if newAPIKey != apiKey {
+		t.Headers.Set(apiKeyHTTPHeaderKey, newAPIKey)
+		t.Endpoint.Route = strings.Replace(t.Endpoint.Route, "api_key="+apiKey, "api_key="+newAPIKey, 1)
+	}
+	return true
+}
+
 // SerializeTo serializes the transaction using TransactionsSerializer
 func (t *HTTPTransaction) SerializeTo(serializer TransactionsSerializer) error {
 	if t.StorableOnDisk {
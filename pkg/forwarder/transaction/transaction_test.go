@@ -7,12 +7,14 @@ package transaction
 
 import (
 	"context"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewHTTPTransaction(t *testing.T) {
@@ -51,6 +53,46 @@ func TestProcess(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+func TestProcessFilePayload(t *testing.T) {
+	var received []byte
+	var contentLength int64
+	statusCode := http.StatusInternalServerError
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, _ = ioutil.ReadAll(r.Body)
+		contentLength = r.ContentLength
+		w.WriteHeader(statusCode)
+	}))
+	defer ts.Close()
+
+	f, err := ioutil.TempFile(t.TempDir(), "payload-*")
+	require.NoError(t, err)
+	_, err = f.WriteString("test payload")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	payload := NewFilePayload(f.Name(), len("test payload"))
+	transaction := NewHTTPTransaction()
+	transaction.Domain = ts.URL
+	transaction.Endpoint.Route = "/endpoint/test"
+	transaction.PayloadFile = payload.Retain()
+	payload.Release()
+	assert.Equal(t, 12, transaction.GetPayloadSize())
+
+	client := &http.Client{}
+
+	// the file is kept while the transaction can be retried
+	assert.NotNil(t, transaction.Process(context.Background(), client))
+	assert.Equal(t, "test payload", string(received))
+	assert.EqualValues(t, 12, contentLength)
+	assert.FileExists(t, f.Name())
+
+	statusCode = http.StatusOK
+	assert.Nil(t, transaction.Process(context.Background(), client))
+	assert.Equal(t, "test payload", string(received))
+	assert.NoFileExists(t, f.Name())
+	assert.Nil(t, transaction.PayloadFile)
+}
+
 func TestProcessInvalidDomain(t *testing.T) {
 	transaction := NewHTTPTransaction()
 	transaction.Domain = "://invalid"
@@ -126,3 +168,46 @@ func TestProcessCancel(t *testing.T) {
 	err := transaction.Process(ctx, client)
 	assert.Nil(t, err)
 }
+
+func TestProcessRotatedAPIKey(t *testing.T) {
+	var receivedAPIKey, receivedQueryAPIKey string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedAPIKey = r.Header.Get("DD-Api-Key")
+		receivedQueryAPIKey = r.URL.Query().Get("api_key")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	newTransaction := func(apiKey string) *HTTPTransaction {
+		transaction := NewHTTPTransaction()
+		transaction.Domain = ts.URL
+		transaction.Endpoint.Route = "/endpoint/test?api_key=" + apiKey
+		transaction.Headers.Set("DD-Api-Key", apiKey)
+		payload := []byte("test payload")
+		transaction.Payload = &payload
+		transaction.APIKeyResolver = func(apiKey string) (string, bool) {
+			switch apiKey {
+			case "rotated":
+				return "new", true
+			case "removed":
+				return "", false
+			}
+			return apiKey, true
+		}
+		return transaction
+	}
+
+	err := newTransaction("rotated").Process(context.Background(), &http.Client{})
+	assert.Nil(t, err)
+	assert.Equal(t, "new", receivedAPIKey)
+	assert.Equal(t, "new", receivedQueryAPIKey)
+
+	err = newTransaction("current").Process(context.Background(), &http.Client{})
+	assert.Nil(t, err)
+	assert.Equal(t, "current", receivedAPIKey)
+
+	receivedAPIKey = ""
+	err = newTransaction("removed").Process(context.Background(), &http.Client{})
+	assert.Nil(t, err)
+	assert.Equal(t, "", receivedAPIKey)
+}
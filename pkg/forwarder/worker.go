@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -36,6 +37,8 @@ type Worker struct {
 	stopChan            chan struct{}
 	stopped             chan struct{}
 	blockedList         *blockedEndpoints
+	// health is the liveness handle of the worker, nil if the worker isn't monitored
+	health *health.Handle
 }
 
 // NewWorker returns a new worker to consume Transaction from inputChan
@@ -71,6 +74,11 @@ func (w *Worker) Stop(purgeHighPrio bool) {
 	w.stopChan <- struct{}{}
 	<-w.stopped
 
+	if w.health != nil {
+		// the watchdog deregisters the workers it replaces
+		w.health.Deregister() //nolint:errcheck
+	}
+
 	if purgeHighPrio {
 		// purging waiting transactions
 	L:
@@ -89,15 +97,21 @@ func (w *Worker) Stop(purgeHighPrio bool) {
 
 // Start starts a Worker.
 func (w *Worker) Start() {
+	var healthChan <-chan time.Time
+	if w.health != nil {
+		healthChan = w.health.C
+	}
+
 	go func() {
 		// notify that the worker did stop
 		defer close(w.stopped)
 
 		for {
-			// handling high priority transactions first
+			// handling high priority transactions first. The channels are closed
+			// when a worker replaced by the watchdog unblocks after its forwarder stopped.
 			select {
-			case t := <-w.HighPrio:
-				if w.callProcess(t) == nil {
+			case t, ok := <-w.HighPrio:
+				if ok && w.callProcess(t) == nil {
 					continue
 				}
 				return
@@ -107,14 +121,19 @@ func (w *Worker) Start() {
 			}
 
 			select {
-			case t := <-w.HighPrio:
-				if w.callProcess(t) != nil {
+			case t, ok := <-w.HighPrio:
+				if !ok || w.callProcess(t) != nil {
 					return
 				}
-			case t := <-w.LowPrio:
-				if w.callProcess(t) != nil {
+			case t, ok := <-w.LowPrio:
+				if !ok || w.callProcess(t) != nil {
 					return
 				}
+			case _, ok := <-healthChan:
+				if !ok {
+					// deregistered by the watchdog, which replaced the worker
+					healthChan = nil
+				}
 			case <-w.stopChan:
 				return
 			}
@@ -169,6 +188,7 @@ func (w *Worker) process(ctx context.Context, t transaction.Transaction) {
 		case w.RequeueChan <- t:
 		default:
 			log.Errorf("dropping transaction because the retry goroutine is too busy to handle another one")
+			t.Release()
 		}
 	}
 
@@ -116,6 +116,22 @@ func BuildEndpoints(httpConnectivity HTTPConnectivity, intakeTrackType IntakeTra
 	return BuildEndpointsWithConfig(defaultLogsConfigKeys(), httpEndpointPrefix, httpConnectivity, intakeTrackType, intakeProtocol, intakeOrigin)
 }
 
+// BuildRoutedEndpoints returns the endpoints of the logs matching the routing rules,
+// which are sent to the main endpoint with the API key of their rule.
+func BuildRoutedEndpoints(main Endpoint) ([]RoutedEndpoint, error) {
+	rules, err := coreConfig.GetRoutingRules()
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]RoutedEndpoint, 0, len(rules))
+	for _, rule := range rules {
+		endpoint := main
+		endpoint.APIKey = rule.APIKey
+		routes = append(routes, RoutedEndpoint{Rule: rule, Endpoint: endpoint})
+	}
+	return routes, nil
+}
+
 // BuildEndpointsWithConfig returns the endpoints to send logs.
 func BuildEndpointsWithConfig(logsConfig *LogsConfigKeys, endpointPrefix string, httpConnectivity HTTPConnectivity, intakeTrackType IntakeTrackType, intakeProtocol IntakeProtocol, intakeOrigin IntakeOrigin) (*Endpoints, error) {
 	if logsConfig.devModeNoSSL() {
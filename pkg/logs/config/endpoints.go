@@ -54,6 +54,13 @@ type Endpoint struct {
 	Origin    IntakeOrigin
 }
 
+// RoutedEndpoint is the endpoint of the logs matching a routing rule, the main
+// endpoint with the API key of the rule
+type RoutedEndpoint struct {
+	Rule     config.RoutingRule
+	Endpoint Endpoint
+}
+
 // Endpoints holds the main endpoint and additional ones to dualship logs.
 type Endpoints struct {
 	Main                   Endpoint
@@ -64,6 +71,9 @@ type Endpoints struct {
 	BatchMaxConcurrentSend int
 	BatchMaxSize           int
 	BatchMaxContentSize    int
+
+	// Routes are the endpoints of the logs sent to other organizations
+	Routes []RoutedEndpoint
 }
 
 // NewEndpoints returns a new endpoints composite with default batching settings
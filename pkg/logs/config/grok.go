@@ -0,0 +1,65 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// grokPatterns are the grok patterns supported in the extract_metric processing rules
+var grokPatterns = map[string]string{
+	"INT":          `[+-]?\d+`,
+	"NUMBER":       `[+-]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?`,
+	"WORD":         `\w+`,
+	"NOTSPACE":     `\S+`,
+	"SPACE":        `\s*`,
+	"DATA":         `.*?`,
+	"GREEDYDATA":   `.*`,
+	"QUOTEDSTRING": `"(?:[^"\\]|\\.)*"`,
+	"IPV4":         `(?:\d{1,3}\.){3}\d{1,3}`,
+	"IPV6":         `[0-9A-Fa-f:]*:[0-9A-Fa-f:.]+`,
+	"IP":           `(?:(?:\d{1,3}\.){3}\d{1,3}|[0-9A-Fa-f:]*:[0-9A-Fa-f:.]+)`,
+	"HOSTNAME":     `[0-9A-Za-z][0-9A-Za-z-]*(?:\.[0-9A-Za-z][0-9A-Za-z-]*)*`,
+	"URIPATH":      `/[^\s?#]*`,
+	"LOGLEVEL":     `(?i:trace|debug|info|notice|warn(?:ing)?|err(?:or)?|crit(?:ical)?|fatal|severe|emerg(?:ency)?|alert)`,
+	"UUID":         `[0-9A-Fa-f]{8}-(?:[0-9A-Fa-f]{4}-){3}[0-9A-Fa-f]{12}`,
+}
+
+// grokReference matches %{PATTERN} and %{PATTERN:capture_name}
+var grokReference = regexp.MustCompile(`%\{(\w+)(?::(\w+))?\}`)
+
+// expandGrok replaces the grok references of a pattern by their regular expression,
+// the references with a capture name are converted to named capture groups.
+func expandGrok(pattern string) (string, error) {
+	var err error
+	expanded := grokReference.ReplaceAllStringFunc(pattern, func(ref string) string {
+		match := grokReference.FindStringSubmatch(ref)
+		re, found := grokPatterns[match[1]]
+		if !found {
+			err = fmt.Errorf("unknown grok pattern %s", match[1])
+			return ref
+		}
+		if match[2] == "" {
+			return "(?:" + re + ")"
+		}
+		return "(?P<" + match[2] + ">" + re + ")"
+	})
+	if err != nil {
+		return "", err
+	}
+	return expanded, nil
+}
+
+// hasGroup returns whether re has a capture group called name, which must not be empty
+func hasGroup(re *regexp.Regexp, name string) bool {
+	for _, group := range re.SubexpNames() {
+		if group == name {
+			return true
+		}
+	}
+	return false
+}
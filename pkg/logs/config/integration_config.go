@@ -19,6 +19,7 @@ const (
 	JournaldType      = "journald"
 	WindowsEventType  = "windows_event"
 	SnmpTrapsType     = "snmp_traps"
+	SyslogType        = "syslog"
 	StringChannelType = "string_channel"
 
 	// UTF16BE for UTF-16 Big endian encoding
@@ -36,6 +37,10 @@ type LogsConfig struct {
 	IdleTimeout string `mapstructure:"idle_timeout" json:"idle_timeout"` // Network
 	Path        string // File, Journald
 
+	Protocol    string `mapstructure:"protocol" json:"protocol"`           // Syslog
+	TLSCertFile string `mapstructure:"tls_cert_file" json:"tls_cert_file"` // Syslog
+	TLSKeyFile  string `mapstructure:"tls_key_file" json:"tls_key_file"`   // Syslog
+
 	Encoding     string   `mapstructure:"encoding" json:"encoding"`             // File
 	ExcludePaths []string `mapstructure:"exclude_paths" json:"exclude_paths"`   // File
 	TailingMode  string   `mapstructure:"start_position" json:"start_position"` // File
@@ -63,6 +68,7 @@ type LogsConfig struct {
 	SourceCategory  string
 	Tags            []string
 	ProcessingRules []*ProcessingRule `mapstructure:"log_processing_rules" json:"log_processing_rules"`
+	RateLimit       *RateLimit        `mapstructure:"log_rate_limit" json:"log_rate_limit"`
 
 	AutoMultiLine               bool    `mapstructure:"auto_multi_line_detection" json:"auto_multi_line_detection"`
 	AutoMultiLineSampleSize     int     `mapstructure:"auto_multi_line_sample_size" json:"auto_multi_line_sample_size"`
@@ -130,6 +136,16 @@ func (c *LogsConfig) Validate() error {
 		return fmt.Errorf("tcp source must have a port")
 	case c.Type == UDPType && c.Port == 0:
 		return fmt.Errorf("udp source must have a port")
+	case c.Type == SyslogType:
+		err := c.validateSyslog()
+		if err != nil {
+			return err
+		}
+	}
+	if c.RateLimit != nil {
+		if err := c.RateLimit.Validate(); err != nil {
+			return err
+		}
 	}
 	err := ValidateProcessingRules(c.ProcessingRules)
 	if err != nil {
@@ -138,6 +154,26 @@ func (c *LogsConfig) Validate() error {
 	return CompileProcessingRules(c.ProcessingRules)
 }
 
+// validateSyslog returns an error if the syslog listener is misconfigured
+func (c *LogsConfig) validateSyslog() error {
+	if c.Port == 0 {
+		return fmt.Errorf("syslog source must have a port")
+	}
+	switch c.Protocol {
+	case "", TCPType:
+	case UDPType:
+		if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+			return fmt.Errorf("syslog source over udp does not support tls")
+		}
+	default:
+		return fmt.Errorf("invalid syslog protocol '%v', must be '%v' or '%v'", c.Protocol, TCPType, UDPType)
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("syslog source must have both a tls_cert_file and a tls_key_file to enable tls")
+	}
+	return nil
+}
+
 func (c *LogsConfig) validateTailingMode() error {
 	mode, found := TailingModeFromString(c.TailingMode)
 	if !found && c.TailingMode != "" {
@@ -21,6 +21,11 @@ func TestValidateShouldSucceedWithValidConfigs(t *testing.T) {
 		{Type: DockerType},
 		{Type: JournaldType, ProcessingRules: []*ProcessingRule{{Name: "foo", Type: ExcludeAtMatch, Pattern: ".*"}}},
 		{Type: SnmpTrapsType},
+		{Type: SyslogType, Port: 514},
+		{Type: SyslogType, Port: 514, Protocol: UDPType},
+		{Type: SyslogType, Port: 6514, Protocol: TCPType, TLSCertFile: "/etc/cert.pem", TLSKeyFile: "/etc/key.pem"},
+		{Type: DockerType, RateLimit: &RateLimit{LinesPerSecond: 100}},
+		{Type: DockerType, RateLimit: &RateLimit{LinesPerSecond: 0.5, Burst: 10}},
 	}
 
 	for _, config := range validConfigs {
@@ -35,6 +40,10 @@ func TestValidateShouldFailWithInvalidConfigs(t *testing.T) {
 		{Type: FileType},
 		{Type: TCPType},
 		{Type: UDPType},
+		{Type: SyslogType},
+		{Type: SyslogType, Port: 514, Protocol: "http"},
+		{Type: SyslogType, Port: 514, Protocol: UDPType, TLSCertFile: "/etc/cert.pem", TLSKeyFile: "/etc/key.pem"},
+		{Type: SyslogType, Port: 6514, TLSCertFile: "/etc/cert.pem"},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo"}}},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo", Type: "bar"}}},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Name: "foo", Type: ExcludeAtMatch}}},
@@ -42,6 +51,8 @@ func TestValidateShouldFailWithInvalidConfigs(t *testing.T) {
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Type: ExcludeAtMatch, Pattern: ".*"}}},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Type: ExcludeAtMatch}}},
 		{Type: DockerType, ProcessingRules: []*ProcessingRule{{Pattern: ".*"}}},
+		{Type: DockerType, RateLimit: &RateLimit{}},
+		{Type: DockerType, RateLimit: &RateLimit{LinesPerSecond: 100, Burst: -1}},
 	}
 
 	for _, config := range invalidConfigs {
@@ -16,6 +16,13 @@ const (
 	IncludeAtMatch = "include_at_match"
 	MaskSequences  = "mask_sequences"
 	MultiLine      = "multi_line"
+	ExtractMetric  = "extract_metric"
+)
+
+// Metric types of the extract_metric rules
+const (
+	CountMetricType        = "count"
+	DistributionMetricType = "distribution"
 )
 
 // ProcessingRule defines an exclusion or a masking rule to
@@ -25,6 +32,13 @@ type ProcessingRule struct {
 	Name               string
 	ReplacePlaceholder string `mapstructure:"replace_placeholder" json:"replace_placeholder"`
 	Pattern            string
+	// extract_metric rules: the pattern is a regular expression which can
+	// contain grok references such as %{NUMBER:duration}
+	MetricName string   `mapstructure:"metric_name" json:"metric_name"`
+	MetricType string   `mapstructure:"metric_type" json:"metric_type"`
+	ValueGroup string   `mapstructure:"value_group" json:"value_group"`
+	TagGroups  []string `mapstructure:"tag_groups" json:"tag_groups"`
+	ExcludeLog bool     `mapstructure:"exclude_log" json:"exclude_log"`
 	// TODO: should be moved out
 	Regex       *regexp.Regexp
 	Placeholder []byte
@@ -44,6 +58,11 @@ func ValidateProcessingRules(rules []*ProcessingRule) error {
 		switch rule.Type {
 		case ExcludeAtMatch, IncludeAtMatch, MaskSequences, MultiLine:
 			break
+		case ExtractMetric:
+			if err := validateExtractMetricRule(rule); err != nil {
+				return err
+			}
+			continue
 		case "":
 			return fmt.Errorf("type must be set for processing rule `%s`", rule.Name)
 		default:
@@ -79,7 +98,61 @@ func CompileProcessingRules(rules []*ProcessingRule) error {
 			if err != nil {
 				return err
 			}
+		case ExtractMetric:
+			rule.Regex, err = compileExtractMetricPattern(rule.Pattern)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
+
+// validateExtractMetricRule validates an extract_metric rule, which must have:
+// - a metric name
+// - a count or distribution metric type, count by default
+// - a value group when it generates a distribution
+// - value and tag groups defined in the pattern
+func validateExtractMetricRule(rule *ProcessingRule) error {
+	if rule.MetricName == "" {
+		return fmt.Errorf("no metric_name provided for processing rule: %s", rule.Name)
+	}
+
+	switch rule.MetricType {
+	case "", CountMetricType:
+	case DistributionMetricType:
+		if rule.ValueGroup == "" {
+			return fmt.Errorf("a value_group must be set for the distribution of processing rule: %s", rule.Name)
+		}
+	default:
+		return fmt.Errorf("metric_type %s is not supported for processing rule: %s", rule.MetricType, rule.Name)
+	}
+
+	if rule.Pattern == "" {
+		return fmt.Errorf("no pattern provided for processing rule: %s", rule.Name)
+	}
+	re, err := compileExtractMetricPattern(rule.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %s for processing rule: %s: %v", rule.Pattern, rule.Name, err)
+	}
+
+	groups := rule.TagGroups
+	if rule.ValueGroup != "" {
+		groups = append([]string{rule.ValueGroup}, groups...)
+	}
+	for _, group := range groups {
+		if group == "" || !hasGroup(re, group) {
+			return fmt.Errorf("capture group %q is not defined in the pattern of processing rule: %s", group, rule.Name)
+		}
+	}
+	return nil
+}
+
+// compileExtractMetricPattern expands the grok references of the pattern and compiles it
+func compileExtractMetricPattern(pattern string) (*regexp.Regexp, error) {
+	expanded, err := expandGrok(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(expanded)
+}
@@ -33,3 +33,35 @@ func TestCompileShouldFailWithInvalidRules(t *testing.T) {
 		assert.Nil(t, rule.Regex)
 	}
 }
+
+func TestValidateExtractMetricRules(t *testing.T) {
+	validRules := []*ProcessingRule{
+		{Type: ExtractMetric, Name: "count", MetricName: "app.errors", Pattern: "ERROR"},
+		{Type: ExtractMetric, Name: "tags", MetricName: "app.errors", Pattern: `status=%{INT:status}`, TagGroups: []string{"status"}},
+		{Type: ExtractMetric, Name: "distribution", MetricName: "app.duration", MetricType: DistributionMetricType, Pattern: `took (?P<duration>\d+)ms`, ValueGroup: "duration"},
+	}
+	assert.NoError(t, ValidateProcessingRules(validRules))
+	assert.NoError(t, CompileProcessingRules(validRules))
+	assert.Equal(t, []string{"", "status"}, validRules[1].Regex.SubexpNames())
+
+	invalidRules := []*ProcessingRule{
+		{Type: ExtractMetric, Name: "no_metric_name", Pattern: "ERROR"},
+		{Type: ExtractMetric, Name: "bad_type", MetricName: "app.errors", MetricType: "gauge", Pattern: "ERROR"},
+		{Type: ExtractMetric, Name: "no_value", MetricName: "app.duration", MetricType: DistributionMetricType, Pattern: "took"},
+		{Type: ExtractMetric, Name: "unknown_value", MetricName: "app.duration", Pattern: "took", ValueGroup: "duration"},
+		{Type: ExtractMetric, Name: "unknown_tag", MetricName: "app.errors", Pattern: "ERROR", TagGroups: []string{"status"}},
+		{Type: ExtractMetric, Name: "unknown_grok", MetricName: "app.errors", Pattern: "%{FOO:bar}"},
+	}
+	for _, rule := range invalidRules {
+		assert.Error(t, ValidateProcessingRules([]*ProcessingRule{rule}), rule.Name)
+	}
+}
+
+func TestExpandGrok(t *testing.T) {
+	expanded, err := expandGrok(`%{WORD} took %{NUMBER:duration}ms`)
+	assert.NoError(t, err)
+	assert.Equal(t, `(?:\w+) took (?P<duration>[+-]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?)ms`, expanded)
+
+	_, err = expandGrok(`%{UNKNOWN:foo}`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package config
+
+import (
+	"fmt"
+	"math"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit defines the maximum throughput of a log source, the lines sent
+// above this rate are dropped
+type RateLimit struct {
+	LinesPerSecond float64 `mapstructure:"lines_per_second" json:"lines_per_second"`
+	// Burst is the number of lines which can be sent at once above the rate,
+	// it defaults to the number of lines per second
+	Burst int `mapstructure:"burst" json:"burst"`
+}
+
+// Validate returns an error if the rate limit is misconfigured
+func (r *RateLimit) Validate() error {
+	if r.LinesPerSecond <= 0 {
+		return fmt.Errorf("log_rate_limit must have a positive lines_per_second")
+	}
+	if r.Burst < 0 {
+		return fmt.Errorf("log_rate_limit burst must not be negative")
+	}
+	return nil
+}
+
+// RateLimiter drops the lines of a log source sent above its rate limit and
+// counts them for the status page
+type RateLimiter struct {
+	limiter *rate.Limiter
+	dropped *CountInfo
+}
+
+// NewRateLimiter returns a rate limiter enforcing the given rate limit
+func NewRateLimiter(r *RateLimit) *RateLimiter {
+	burst := r.Burst
+	if burst == 0 {
+		burst = int(math.Ceil(r.LinesPerSecond))
+	}
+	return &RateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(r.LinesPerSecond), burst),
+		dropped: NewCountInfo("Rate limited lines"),
+	}
+}
+
+// Allow returns false if the line must be dropped as the source is over its rate limit
+func (l *RateLimiter) Allow() bool {
+	if l.limiter.Allow() {
+		return true
+	}
+	l.dropped.Add(1)
+	return false
+}
@@ -49,11 +49,13 @@ type LogSource struct {
 	// the duration between when a message is decoded by the tailer/listener/decoder and when the message is handled by a sender
 	LatencyStats     *util.StatsTracker
 	hiddenFromStatus bool
+	// RateLimiter enforces the log_rate_limit of the source, it is nil when the source isn't rate limited
+	RateLimiter *RateLimiter
 }
 
 // NewLogSource creates a new log source.
 func NewLogSource(name string, config *LogsConfig) *LogSource {
-	return &LogSource{
+	source := &LogSource{
 		Name:             name,
 		Config:           config,
 		Status:           NewLogStatus(),
@@ -65,6 +67,11 @@ func NewLogSource(name string, config *LogsConfig) *LogSource {
 		LatencyStats:     util.NewStatsTracker(time.Hour*24, time.Hour),
 		hiddenFromStatus: false,
 	}
+	if config != nil && config.RateLimit != nil && config.RateLimit.LinesPerSecond > 0 {
+		source.RateLimiter = NewRateLimiter(config.RateLimit)
+		source.RegisterInfo(source.RateLimiter.dropped)
+	}
+	return source
 }
 
 // AddInput registers an input as being handled by this source.
@@ -290,6 +290,7 @@ func (l *Launcher) getFileSource(container *Container, source *config.LogSource)
 		Source:          sourceName,
 		Tags:            source.Config.Tags,
 		ProcessingRules: source.Config.ProcessingRules,
+		RateLimit:       source.Config.RateLimit,
 	})
 	fileSource.SetSourceType(config.DockerSourceType)
 	fileSource.Status = source.Status
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package listener
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/parser"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
+	"github.com/DataDog/datadog-agent/pkg/logs/restart"
+)
+
+// syslogSource is the source of the syslog messages when the configuration does not define one,
+// defining a source per port allows to tag the messages coming from different kinds of devices.
+const syslogSource = "syslog"
+
+// syslogMaxOctetCountDigits is the maximum number of digits of the length of an octet-counted message
+const syslogMaxOctetCountDigits = 10
+
+var errInvalidOctetCount = errors.New("invalid syslog octet count")
+
+// NewSyslogListener returns a TCP or a UDP listener, depending on the protocol of the source,
+// that parses the syslog headers of the messages into structured attributes.
+func NewSyslogListener(pipelineProvider pipeline.Provider, source *config.LogSource, frameSize int) restart.Restartable {
+	if source.Config.Protocol == config.UDPType {
+		listener := NewUDPListener(pipelineProvider, source, frameSize)
+		listener.parser = parser.Syslog
+		listener.defaultSource = syslogSource
+		return listener
+	}
+	listener := NewTCPListener(pipelineProvider, source, frameSize)
+	listener.parser = parser.Syslog
+	listener.defaultSource = syslogSource
+	listener.syslogFraming = true
+	return listener
+}
+
+// syslogFrameReader reads the syslog messages of a TCP connection, framed as described by RFC 6587:
+// a message either starts with its length followed by a space (octet counting),
+// or ends with a line feed (non-transparent framing).
+type syslogFrameReader struct {
+	reader    *bufio.Reader
+	frameSize int
+	// remaining is the number of octets left to read from the current octet-counted message
+	remaining int
+}
+
+func newSyslogFrameReader(reader io.Reader, frameSize int) *syslogFrameReader {
+	return &syslogFrameReader{
+		reader:    bufio.NewReaderSize(reader, frameSize),
+		frameSize: frameSize,
+	}
+}
+
+// next returns the next message, or the next part of a message bigger than the frame size, terminated
+// by a line feed as expected by the decoder. The line feeds of an octet-counted message are replaced
+// by spaces so that the decoder doesn't split it.
+func (f *syslogFrameReader) next() ([]byte, error) {
+	if f.remaining == 0 {
+		first, err := f.reader.Peek(1)
+		if err != nil {
+			return nil, err
+		}
+		if first[0] < '1' || first[0] > '9' {
+			return f.nextLine()
+		}
+		if f.remaining, err = f.readOctetCount(); err != nil {
+			return nil, err
+		}
+	}
+
+	size := f.remaining
+	if size > f.frameSize {
+		size = f.frameSize
+	}
+	frame := make([]byte, size, size+1)
+	if _, err := io.ReadFull(f.reader, frame); err != nil {
+		return nil, err
+	}
+	f.remaining -= size
+
+	last := len(frame)
+	if f.remaining == 0 && frame[last-1] == '\n' {
+		last--
+	}
+	for i := 0; i < last; i++ {
+		if frame[i] == '\n' {
+			frame[i] = ' '
+		}
+	}
+	if f.remaining == 0 && last == len(frame) {
+		frame = append(frame, '\n')
+	}
+	return frame, nil
+}
+
+// nextLine returns the next line of a message using the non-transparent framing,
+// or the next part of it when it's bigger than the frame size.
+func (f *syslogFrameReader) nextLine() ([]byte, error) {
+	line, err := f.reader.ReadSlice('\n')
+	if err == bufio.ErrBufferFull || (err == io.EOF && len(line) > 0) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// the buffer of the reader is reused by the next read
+	frame := make([]byte, len(line))
+	copy(frame, line)
+	return frame, nil
+}
+
+// readOctetCount reads the length of an octet-counted message and the space following it
+func (f *syslogFrameReader) readOctetCount() (int, error) {
+	digits := make([]byte, 0, syslogMaxOctetCountDigits)
+	for {
+		c, err := f.reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if c == ' ' {
+			break
+		}
+		if c < '0' || c > '9' || len(digits) == syslogMaxOctetCountDigits {
+			return 0, errInvalidOctetCount
+		}
+		digits = append(digits, c)
+	}
+	length, err := strconv.Atoi(string(digits))
+	if err != nil || length <= 0 {
+		return 0, errInvalidOctetCount
+	}
+	return length, nil
+}
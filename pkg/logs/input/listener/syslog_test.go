@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package listener
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/pipeline/mock"
+)
+
+func TestSyslogTCPShouldReceiveParsedMessages(t *testing.T) {
+	pp := mock.NewMockProvider()
+	msgChan := pp.NextPipelineChan()
+	listener := NewSyslogListener(pp, config.NewLogSource("", &config.LogsConfig{Type: config.SyslogType, Port: tcpTestPort}), 9000).(*TCPListener)
+	listener.Start()
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("%s", listener.listener.Addr()))
+	assert.Nil(t, err)
+
+	fmt.Fprintf(conn, "<34>Oct 11 22:14:15 mymachine su: 'su root' failed\n")
+	msg := <-msgChan
+	assert.Contains(t, string(msg.Content), `"message":"'su root' failed"`)
+	assert.Contains(t, string(msg.Content), `"hostname":"mymachine"`)
+	assert.Equal(t, message.StatusCritical, msg.GetStatus())
+	assert.Equal(t, "syslog", msg.Origin.Source())
+
+	listener.Stop()
+}
+
+func TestSyslogTCPShouldSplitOctetCountedMessages(t *testing.T) {
+	pp := mock.NewMockProvider()
+	msgChan := pp.NextPipelineChan()
+	listener := NewSyslogListener(pp, config.NewLogSource("", &config.LogsConfig{Type: config.SyslogType, Port: tcpTestPort}), 9000).(*TCPListener)
+	listener.Start()
+
+	conn, err := net.Dial("tcp", fmt.Sprintf("%s", listener.listener.Addr()))
+	assert.Nil(t, err)
+
+	first := "<34>1 2021-03-01T10:00:00Z mymachine su - - - 'su root' failed\nfor lonvick"
+	second := "<13>1 2021-03-01T10:00:01Z mymachine app - - - started"
+	fmt.Fprintf(conn, "%d %s%d %s", len(first), first, len(second), second)
+
+	msg := <-msgChan
+	assert.Contains(t, string(msg.Content), `"message":"'su root' failed for lonvick"`)
+	msg = <-msgChan
+	assert.Contains(t, string(msg.Content), `"message":"started"`)
+
+	listener.Stop()
+}
+
+func TestSyslogFrameReader(t *testing.T) {
+	frames := newSyslogFrameReader(strings.NewReader("5 hello11 hello\nworld<13>line\n12 hello world\n"), 16)
+
+	for _, expected := range []string{"hello\n", "hello world\n", "<13>line\n", "hello world\n"} {
+		frame, err := frames.next()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(frame))
+	}
+	_, err := frames.next()
+	assert.Equal(t, io.EOF, err)
+
+	// the messages bigger than the frame size are returned in several parts
+	frames = newSyslogFrameReader(strings.NewReader("20 aaaaaaaaaaaaaaaaaaaa"), 16)
+	frame, err := frames.next()
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 16), string(frame))
+	frame, err = frames.next()
+	assert.NoError(t, err)
+	assert.Equal(t, "aaaa\n", string(frame))
+
+	frames = newSyslogFrameReader(strings.NewReader("12a hello"), 16)
+	_, err = frames.next()
+	assert.Equal(t, errInvalidOctetCount, err)
+}
+
+func TestSyslogUDPShouldUseTheSourceOfThePort(t *testing.T) {
+	pp := mock.NewMockProvider()
+	msgChan := pp.NextPipelineChan()
+	source := config.NewLogSource("", &config.LogsConfig{Type: config.SyslogType, Protocol: config.UDPType, Port: udpTestPort, Source: "cisco"})
+	listener := NewSyslogListener(pp, source, 9000).(*UDPListener)
+	listener.Start()
+
+	conn, err := net.Dial("udp", fmt.Sprintf("%s", listener.tailer.conn.LocalAddr()))
+	assert.Nil(t, err)
+
+	fmt.Fprintf(conn, "<187>1 2021-03-01T10:00:00Z switch1 ios - LINK-3-UPDOWN - Interface Gi0/1, changed state to down")
+	msg := <-msgChan
+	assert.Contains(t, string(msg.Content), `"message":"Interface Gi0/1, changed state to down"`)
+	assert.Contains(t, string(msg.Content), `"msgid":"LINK-3-UPDOWN"`)
+	assert.Equal(t, message.StatusError, msg.GetStatus())
+	assert.Equal(t, "cisco", msg.Origin.Source())
+
+	listener.Stop()
+}
@@ -24,20 +24,28 @@ type Tailer struct {
 	outputChan chan *message.Message
 	read       func(*Tailer) ([]byte, error)
 	decoder    *decoder.Decoder
-	stop       chan struct{}
-	done       chan struct{}
+	// defaultSource is used as the source of the messages when the configuration does not define one
+	defaultSource string
+	stop          chan struct{}
+	done          chan struct{}
 }
 
 // NewTailer returns a new Tailer
 func NewTailer(source *config.LogSource, conn net.Conn, outputChan chan *message.Message, read func(*Tailer) ([]byte, error)) *Tailer {
+	return newTailer(source, conn, outputChan, read, parser.Noop, "")
+}
+
+// newTailer returns a new Tailer decoding the lines with the given parser
+func newTailer(source *config.LogSource, conn net.Conn, outputChan chan *message.Message, read func(*Tailer) ([]byte, error), lineParser parser.Parser, defaultSource string) *Tailer {
 	return &Tailer{
-		source:     source,
-		conn:       conn,
-		outputChan: outputChan,
-		read:       read,
-		decoder:    decoder.InitializeDecoder(source, parser.Noop),
-		stop:       make(chan struct{}, 1),
-		done:       make(chan struct{}, 1),
+		source:        source,
+		conn:          conn,
+		outputChan:    outputChan,
+		read:          read,
+		decoder:       decoder.InitializeDecoder(source, lineParser),
+		defaultSource: defaultSource,
+		stop:          make(chan struct{}, 1),
+		done:          make(chan struct{}, 1),
 	}
 }
 
@@ -63,7 +71,9 @@ func (t *Tailer) forwardMessages() {
 	}()
 	for output := range t.decoder.OutputChan {
 		if len(output.Content) > 0 {
-			t.outputChan <- message.NewMessageWithSource(output.Content, message.StatusInfo, t.source, output.IngestionTimestamp)
+			origin := message.NewOrigin(t.source)
+			origin.SetSource(t.defaultSource)
+			t.outputChan <- message.NewMessage(output.Content, origin, output.Status, output.IngestionTimestamp)
 		}
 	}
 }
@@ -6,6 +6,7 @@
 package listener
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net"
 	"sync"
@@ -14,6 +15,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/parser"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
 	"github.com/DataDog/datadog-agent/pkg/logs/restart"
 )
@@ -24,6 +26,9 @@ type TCPListener struct {
 	source           *config.LogSource
 	idleTimeout      time.Duration
 	frameSize        int
+	parser           parser.Parser
+	defaultSource    string
+	syslogFraming    bool // splits the messages as described by RFC 6587 instead of on line feeds only
 	listener         net.Listener
 	tailers          []*Tailer
 	mu               sync.Mutex
@@ -47,6 +52,7 @@ func NewTCPListener(pipelineProvider pipeline.Provider, source *config.LogSource
 		source:           source,
 		idleTimeout:      idleTimeout,
 		frameSize:        frameSize,
+		parser:           parser.Noop,
 		tailers:          []*Tailer{},
 		stop:             make(chan struct{}, 1),
 	}
@@ -114,7 +120,28 @@ func (l *TCPListener) run() {
 
 // startListener starts a new listener, returns an error if it failed.
 func (l *TCPListener) startListener() error {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", l.source.Config.Port))
+	address := fmt.Sprintf(":%d", l.source.Config.Port)
+	if l.source.Config.TLSCertFile != "" {
+		return l.startTLSListener(address)
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	l.listener = listener
+	return nil
+}
+
+// startTLSListener starts a new listener accepting TLS connections, returns an error if it failed.
+func (l *TCPListener) startTLSListener(address string) error {
+	cert, err := tls.LoadX509KeyPair(l.source.Config.TLSCertFile, l.source.Config.TLSKeyFile)
+	if err != nil {
+		return fmt.Errorf("can't load the tls certificate: %v", err)
+	}
+	listener, err := tls.Listen("tcp", address, &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	})
 	if err != nil {
 		return err
 	}
@@ -137,11 +164,32 @@ func (l *TCPListener) read(tailer *Tailer) ([]byte, error) {
 	return frame[:n], nil
 }
 
+// readSyslogFrame reads the next syslog message from connection, returns an error if it failed and stop the tailer.
+func (l *TCPListener) readSyslogFrame(tailer *Tailer, frames *syslogFrameReader) ([]byte, error) {
+	if l.idleTimeout > 0 {
+		tailer.conn.SetReadDeadline(time.Now().Add(l.idleTimeout)) //nolint:errcheck
+	}
+	frame, err := frames.next()
+	if err != nil {
+		l.source.Status.Error(err)
+		go l.stopTailer(tailer)
+		return nil, err
+	}
+	return frame, nil
+}
+
 // startTailer creates and starts a new tailer that reads from the connection.
 func (l *TCPListener) startTailer(conn net.Conn) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	tailer := NewTailer(l.source, conn, l.pipelineProvider.NextPipelineChan(), l.read)
+	read := l.read
+	if l.syslogFraming {
+		frames := newSyslogFrameReader(conn, l.frameSize)
+		read = func(tailer *Tailer) ([]byte, error) {
+			return l.readSyslogFrame(tailer, frames)
+		}
+	}
+	tailer := newTailer(l.source, conn, l.pipelineProvider.NextPipelineChan(), read, l.parser, l.defaultSource)
 	l.tailers = append(l.tailers, tailer)
 	tailer.Start()
 }
@@ -12,6 +12,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/parser"
 	"github.com/DataDog/datadog-agent/pkg/logs/pipeline"
 )
 
@@ -31,6 +32,8 @@ type UDPListener struct {
 	pipelineProvider pipeline.Provider
 	source           *config.LogSource
 	frameSize        int
+	parser           parser.Parser
+	defaultSource    string
 	tailer           *Tailer
 }
 
@@ -40,6 +43,7 @@ func NewUDPListener(pipelineProvider pipeline.Provider, source *config.LogSource
 		pipelineProvider: pipelineProvider,
 		source:           source,
 		frameSize:        frameSize,
+		parser:           parser.Noop,
 	}
 }
 
@@ -67,7 +71,7 @@ func (l *UDPListener) startNewTailer() error {
 	if err != nil {
 		return err
 	}
-	l.tailer = NewTailer(l.source, conn, l.pipelineProvider.NextPipelineChan(), l.read)
+	l.tailer = newTailer(l.source, conn, l.pipelineProvider.NextPipelineChan(), l.read, l.parser, l.defaultSource)
 	l.tailer.Start()
 	return nil
 }
@@ -67,7 +67,14 @@ func buildEndpoints(serverless bool) (*config.Endpoints, error) {
 	if endpoints, err := config.BuildHTTPEndpoints(intakeTrackType, AgentJSONIntakeProtocol, config.DefaultIntakeOrigin); err == nil {
 		httpConnectivity = http.CheckConnectivity(endpoints.Main)
 	}
-	return config.BuildEndpoints(httpConnectivity, intakeTrackType, AgentJSONIntakeProtocol, config.DefaultIntakeOrigin)
+	endpoints, err := config.BuildEndpoints(httpConnectivity, intakeTrackType, AgentJSONIntakeProtocol, config.DefaultIntakeOrigin)
+	if err != nil {
+		return nil, err
+	}
+	if endpoints.Routes, err = config.BuildRoutedEndpoints(endpoints.Main); err != nil {
+		log.Errorf("Routing rules are disabled: %v", err)
+	}
+	return endpoints, nil
 }
 
 func start(getAC func() *autodiscovery.AutoConfig, serverless bool, logsChan chan *config.ChannelMessage, extraTags []string) error {
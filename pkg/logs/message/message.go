@@ -25,6 +25,9 @@ type Message struct {
 	// Optional.
 	// Used in the Serverless Agent
 	Lambda *Lambda
+	// DeliveryErr is set by the sender when the payload containing the message could not be delivered,
+	// the message is still forwarded to the next stage of the pipeline
+	DeliveryErr error
 }
 
 // Lambda is a struct storing information about the Lambda function and function execution.
@@ -40,6 +40,18 @@ var (
 	// TlmLogsDropped is the total number of logs dropped per Destination
 	TlmLogsDropped = telemetry.NewCounter("logs", "dropped",
 		[]string{"destination"}, "Total number of logs dropped per Destination")
+	// LogsRateLimited is the total number of logs dropped per source by the log_rate_limit of the source
+	LogsRateLimited = expvar.Map{}
+	// TlmLogsRateLimited is the total number of logs dropped per source by the log_rate_limit of the source
+	TlmLogsRateLimited = telemetry.NewCounter("logs", "rate_limited",
+		[]string{"source"}, "Total number of logs dropped per source by the log_rate_limit of the source")
+	// MetricsExtractionDropped is the total number of metric samples of the extract_metric rules dropped per rule
+	// because the aggregator couldn't keep up
+	MetricsExtractionDropped = expvar.Map{}
+	// TlmMetricsExtractionDropped is the total number of metric samples of the extract_metric rules dropped per rule
+	// because the aggregator couldn't keep up
+	TlmMetricsExtractionDropped = telemetry.NewCounter("logs", "metrics_extraction_dropped",
+		[]string{"rule"}, "Total number of metric samples extracted from logs dropped per rule because the aggregator couldn't keep up")
 	// BytesSent is the total number of sent bytes before encoding if any
 	BytesSent = expvar.Int{}
 	// TlmBytesSent is the total number of sent bytes before encoding if any
@@ -67,6 +79,8 @@ func init() {
 	LogsExpvars.Set("LogsSent", &LogsSent)
 	LogsExpvars.Set("DestinationErrors", &DestinationErrors)
 	LogsExpvars.Set("DestinationLogsDropped", &DestinationLogsDropped)
+	LogsExpvars.Set("LogsRateLimited", &LogsRateLimited)
+	LogsExpvars.Set("MetricsExtractionDropped", &MetricsExtractionDropped)
 	LogsExpvars.Set("BytesSent", &BytesSent)
 	LogsExpvars.Set("EncodedBytesSent", &EncodedBytesSent)
 	LogsExpvars.Set("SenderLatency", &SenderLatency)
@@ -0,0 +1,286 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+// Syslog parses syslog messages following either RFC3164 (BSD syslog) or RFC5424.
+// The header of the message is extracted into structured attributes and the content
+// is returned as a JSON object, for example:
+// `<34>Oct 11 22:14:15 mymachine su: 'su root' failed for lonvick on /dev/pts/8` becomes
+// `{"message":"'su root' failed for lonvick on /dev/pts/8","syslog":{"facility":4,"severity":2,...}}`
+// Lines that can't be parsed are returned unchanged.
+var Syslog Parser = &syslogFormat{now: time.Now}
+
+type syslogFormat struct {
+	now func() time.Time
+}
+
+// nilValue is used in RFC5424 headers for unknown fields.
+const nilValue = "-"
+
+// rfc3164TimestampLayout is the layout of the RFC3164 timestamps, which don't contain the year.
+const rfc3164TimestampLayout = "Jan _2 15:04:05"
+
+// syslogSeverities maps the syslog severities to the status of the log messages.
+var syslogSeverities = []string{
+	message.StatusEmergency,
+	message.StatusAlert,
+	message.StatusCritical,
+	message.StatusError,
+	message.StatusWarning,
+	message.StatusNotice,
+	message.StatusInfo,
+	message.StatusDebug,
+}
+
+// syslogAttributes contains the attributes extracted from the syslog header.
+type syslogAttributes struct {
+	Facility       int                          `json:"facility"`
+	Severity       int                          `json:"severity"`
+	Version        int                          `json:"version,omitempty"`
+	Timestamp      string                       `json:"timestamp,omitempty"`
+	Hostname       string                       `json:"hostname,omitempty"`
+	AppName        string                       `json:"appname,omitempty"`
+	ProcID         string                       `json:"procid,omitempty"`
+	MsgID          string                       `json:"msgid,omitempty"`
+	StructuredData map[string]map[string]string `json:"structured_data,omitempty"`
+}
+
+type syslogMessage struct {
+	Message string           `json:"message"`
+	Syslog  syslogAttributes `json:"syslog"`
+}
+
+// Parse implements Parser#Parse
+func (p *syslogFormat) Parse(msg []byte) ([]byte, string, string, bool, error) {
+	content, status, timestamp, err := p.parse(msg)
+	if err != nil {
+		return msg, message.StatusInfo, "", false, err
+	}
+	return content, status, timestamp, false, nil
+}
+
+// SupportsPartialLine implements Parser#SupportsPartialLine
+func (p *syslogFormat) SupportsPartialLine() bool {
+	return false
+}
+
+func (p *syslogFormat) parse(msg []byte) ([]byte, string, string, error) {
+	msg = trimOctetCount(bytes.TrimRight(msg, "\r\n"))
+
+	priority, rest, err := parsePriority(msg)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	var parsed *syslogMessage
+	// RFC5424 headers start with a version number while RFC3164 headers start with a date
+	if sp := bytes.IndexByte(rest, ' '); sp > 0 && sp <= 3 && isDigits(rest[:sp]) {
+		parsed, err = parseRFC5424(rest)
+	} else {
+		parsed = p.parseRFC3164(rest)
+	}
+	if err != nil {
+		return nil, "", "", err
+	}
+	parsed.Syslog.Facility = priority / 8
+	parsed.Syslog.Severity = priority % 8
+
+	content, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return content, syslogSeverities[parsed.Syslog.Severity], parsed.Syslog.Timestamp, nil
+}
+
+// trimOctetCount removes the length prefix added to the messages
+// by the senders using the octet counting framing (RFC6587).
+func trimOctetCount(msg []byte) []byte {
+	i := bytes.IndexByte(msg, ' ')
+	if i > 0 && isDigits(msg[:i]) && len(msg) > i+1 && msg[i+1] == '<' {
+		return msg[i+1:]
+	}
+	return msg
+}
+
+// parsePriority parses the `<PRI>` part of the header.
+func parsePriority(msg []byte) (int, []byte, error) {
+	if len(msg) < 3 || msg[0] != '<' {
+		return 0, nil, errors.New("cannot parse the syslog priority")
+	}
+	end := bytes.IndexByte(msg, '>')
+	if end < 2 || end > 4 || !isDigits(msg[1:end]) {
+		return 0, nil, errors.New("cannot parse the syslog priority")
+	}
+	priority, _ := strconv.Atoi(string(msg[1:end]))
+	if priority > 191 {
+		return 0, nil, errors.New("invalid syslog priority")
+	}
+	return priority, msg[end+1:], nil
+}
+
+// parseRFC5424 parses `VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]`.
+func parseRFC5424(msg []byte) (*syslogMessage, error) {
+	fields := bytes.SplitN(msg, spaceByte, 7)
+	if len(fields) < 7 {
+		return nil, errors.New("cannot parse the RFC5424 syslog header")
+	}
+	version, _ := strconv.Atoi(string(fields[0]))
+	parsed := &syslogMessage{
+		Syslog: syslogAttributes{
+			Version:   version,
+			Timestamp: nilToEmpty(fields[1]),
+			Hostname:  nilToEmpty(fields[2]),
+			AppName:   nilToEmpty(fields[3]),
+			ProcID:    nilToEmpty(fields[4]),
+			MsgID:     nilToEmpty(fields[5]),
+		},
+	}
+	if parsed.Syslog.Timestamp != "" {
+		if _, err := time.Parse(time.RFC3339Nano, parsed.Syslog.Timestamp); err != nil {
+			return nil, errors.New("cannot parse the RFC5424 syslog timestamp")
+		}
+	}
+
+	structuredData, rest, err := parseStructuredData(fields[6])
+	if err != nil {
+		return nil, err
+	}
+	parsed.Syslog.StructuredData = structuredData
+	// the message may start with a UTF-8 byte order mark
+	parsed.Message = string(bytes.TrimPrefix(rest, []byte("\xef\xbb\xbf")))
+	return parsed, nil
+}
+
+// parseStructuredData parses the `[id key="value" ...]...` elements
+// and returns the remaining message.
+func parseStructuredData(msg []byte) (map[string]map[string]string, []byte, error) {
+	if bytes.HasPrefix(msg, []byte(nilValue)) {
+		return nil, bytes.TrimPrefix(msg[len(nilValue):], spaceByte), nil
+	}
+
+	data := make(map[string]map[string]string)
+	for len(msg) > 0 && msg[0] == '[' {
+		i := 1
+		// SD-ID
+		for i < len(msg) && msg[i] != ' ' && msg[i] != ']' {
+			i++
+		}
+		if i >= len(msg) {
+			return nil, nil, errors.New("unterminated syslog structured data")
+		}
+		params := make(map[string]string)
+		data[string(msg[1:i])] = params
+
+		// SD-PARAMs
+		for i < len(msg) && msg[i] == ' ' {
+			i++
+			start := i
+			for i < len(msg) && msg[i] != '=' {
+				i++
+			}
+			if i+1 >= len(msg) || msg[i+1] != '"' {
+				return nil, nil, errors.New("invalid syslog structured data parameter")
+			}
+			name := string(msg[start:i])
+			i += 2
+
+			var value []byte
+			for i < len(msg) && msg[i] != '"' {
+				// '"', '\' and ']' are escaped with a backslash
+				if msg[i] == '\\' && i+1 < len(msg) && (msg[i+1] == '"' || msg[i+1] == '\\' || msg[i+1] == ']') {
+					i++
+				}
+				value = append(value, msg[i])
+				i++
+			}
+			if i >= len(msg) {
+				return nil, nil, errors.New("unterminated syslog structured data parameter")
+			}
+			params[name] = string(value)
+			i++
+		}
+		if i >= len(msg) || msg[i] != ']' {
+			return nil, nil, errors.New("unterminated syslog structured data")
+		}
+		msg = msg[i+1:]
+	}
+	if len(data) == 0 {
+		return nil, nil, errors.New("cannot parse the syslog structured data")
+	}
+	return data, bytes.TrimPrefix(msg, spaceByte), nil
+}
+
+// parseRFC3164 parses `TIMESTAMP HOSTNAME TAG[PID]: MSG`, the BSD syslog format
+// is loosely defined so the parts of the header that can't be parsed are left in the message.
+func (p *syslogFormat) parseRFC3164(msg []byte) *syslogMessage {
+	parsed := &syslogMessage{}
+
+	if len(msg) >= len(rfc3164TimestampLayout) {
+		if ts, err := time.ParseInLocation(rfc3164TimestampLayout, string(msg[:len(rfc3164TimestampLayout)]), time.Local); err == nil {
+			parsed.Syslog.Timestamp = p.withCurrentYear(ts).Format(time.RFC3339)
+			msg = bytes.TrimPrefix(msg[len(rfc3164TimestampLayout):], spaceByte)
+
+			// the hostname is only present after the timestamp
+			if i := bytes.IndexByte(msg, ' '); i > 0 && msg[i-1] != ':' {
+				parsed.Syslog.Hostname = string(msg[:i])
+				msg = msg[i+1:]
+			}
+		}
+	}
+
+	// TAG[PID]: MSG
+	if i := bytes.Index(msg, []byte(": ")); i > 0 && bytes.IndexByte(msg[:i], ' ') < 0 {
+		tag := msg[:i]
+		if start := bytes.IndexByte(tag, '['); start > 0 && tag[len(tag)-1] == ']' {
+			parsed.Syslog.ProcID = string(tag[start+1 : len(tag)-1])
+			tag = tag[:start]
+		}
+		parsed.Syslog.AppName = string(tag)
+		msg = msg[i+2:]
+	}
+	parsed.Message = string(msg)
+	return parsed
+}
+
+// withCurrentYear sets the year of a RFC3164 timestamp, assuming that
+// timestamps in the future come from the previous year.
+func (p *syslogFormat) withCurrentYear(ts time.Time) time.Time {
+	now := p.now()
+	ts = ts.AddDate(now.Year(), 0, 0)
+	if ts.After(now.Add(24 * time.Hour)) {
+		ts = ts.AddDate(-1, 0, 0)
+	}
+	return ts
+}
+
+func nilToEmpty(field []byte) string {
+	if string(field) == nilValue {
+		return ""
+	}
+	return string(field)
+}
+
+func isDigits(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
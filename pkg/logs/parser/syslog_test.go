@@ -0,0 +1,100 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package parser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSyslogTestParser() *syslogFormat {
+	return &syslogFormat{
+		now: func() time.Time { return time.Date(2021, time.March, 1, 0, 0, 0, 0, time.Local) },
+	}
+}
+
+func parseSyslogTestMessage(t *testing.T, msg string) (syslogMessage, string, string) {
+	content, status, timestamp, partial, err := newSyslogTestParser().Parse([]byte(msg))
+	assert.Nil(t, err)
+	assert.False(t, partial)
+	var parsed syslogMessage
+	assert.Nil(t, json.Unmarshal(content, &parsed))
+	return parsed, status, timestamp
+}
+
+func TestSyslogParserRFC3164(t *testing.T) {
+	parsed, status, timestamp := parseSyslogTestMessage(t, "<34>Oct 11 22:14:15 mymachine su[123]: 'su root' failed for lonvick on /dev/pts/8")
+	assert.Equal(t, message.StatusCritical, status)
+	assert.Equal(t, "'su root' failed for lonvick on /dev/pts/8", parsed.Message)
+	assert.Equal(t, 4, parsed.Syslog.Facility)
+	assert.Equal(t, 2, parsed.Syslog.Severity)
+	assert.Equal(t, "mymachine", parsed.Syslog.Hostname)
+	assert.Equal(t, "su", parsed.Syslog.AppName)
+	assert.Equal(t, "123", parsed.Syslog.ProcID)
+	// the timestamp is in the future for the current year, so it comes from the previous one
+	expected := time.Date(2020, time.October, 11, 22, 14, 15, 0, time.Local).Format(time.RFC3339)
+	assert.Equal(t, expected, timestamp)
+	assert.Equal(t, expected, parsed.Syslog.Timestamp)
+}
+
+func TestSyslogParserRFC3164WithoutHeader(t *testing.T) {
+	parsed, status, timestamp := parseSyslogTestMessage(t, "<13>Use the BFG!")
+	assert.Equal(t, message.StatusNotice, status)
+	assert.Equal(t, "Use the BFG!", parsed.Message)
+	assert.Equal(t, 1, parsed.Syslog.Facility)
+	assert.Equal(t, "", parsed.Syslog.Hostname)
+	assert.Equal(t, "", timestamp)
+
+	parsed, _, _ = parseSyslogTestMessage(t, "<13>Feb  5 17:32:18 10.0.0.99 Use the BFG!")
+	assert.Equal(t, "Use the BFG!", parsed.Message)
+	assert.Equal(t, "10.0.0.99", parsed.Syslog.Hostname)
+	assert.Equal(t, "", parsed.Syslog.AppName)
+}
+
+func TestSyslogParserRFC5424(t *testing.T) {
+	parsed, status, timestamp := parseSyslogTestMessage(t, `<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog - ID47 [exampleSDID@32473 iut="3" eventSource="Application" eventID="1011"][examplePriority@32473 class="high \"quoted\""] An application event log entry...`)
+	assert.Equal(t, message.StatusNotice, status)
+	assert.Equal(t, "2003-10-11T22:14:15.003Z", timestamp)
+	assert.Equal(t, "An application event log entry...", parsed.Message)
+	assert.Equal(t, 20, parsed.Syslog.Facility)
+	assert.Equal(t, 5, parsed.Syslog.Severity)
+	assert.Equal(t, 1, parsed.Syslog.Version)
+	assert.Equal(t, "mymachine.example.com", parsed.Syslog.Hostname)
+	assert.Equal(t, "evntslog", parsed.Syslog.AppName)
+	assert.Equal(t, "", parsed.Syslog.ProcID)
+	assert.Equal(t, "ID47", parsed.Syslog.MsgID)
+	assert.Equal(t, map[string]map[string]string{
+		"exampleSDID@32473":     {"iut": "3", "eventSource": "Application", "eventID": "1011"},
+		"examplePriority@32473": {"class": `high "quoted"`},
+	}, parsed.Syslog.StructuredData)
+}
+
+func TestSyslogParserRFC5424WithoutStructuredData(t *testing.T) {
+	parsed, status, _ := parseSyslogTestMessage(t, "52 <34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 - \xef\xbb\xbf'su root' failed\n")
+	assert.Equal(t, message.StatusCritical, status)
+	assert.Equal(t, "'su root' failed", parsed.Message)
+	assert.Nil(t, parsed.Syslog.StructuredData)
+}
+
+func TestSyslogParserShouldFailWithInvalidInput(t *testing.T) {
+	for _, msg := range []string{
+		"not a syslog message",
+		"<>Oct 11 22:14:15 mymachine su: failed",
+		"<192>Oct 11 22:14:15 mymachine su: failed",
+		"<34>1 2003-10-11T22:14:15.003Z mymachine",
+		"<34>1 yesterday mymachine.example.com su - ID47 - failed",
+		`<34>1 2003-10-11T22:14:15.003Z mymachine.example.com su - ID47 [id key="value failed`,
+	} {
+		content, status, _, _, err := Syslog.Parse([]byte(msg))
+		assert.NotNil(t, err, msg)
+		assert.Equal(t, []byte(msg), content)
+		assert.Equal(t, message.StatusInfo, status)
+	}
+}
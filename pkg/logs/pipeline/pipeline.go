@@ -23,36 +23,30 @@ type Pipeline struct {
 	InputChan chan *message.Message
 	processor *processor.Processor
 	sender    *sender.Sender
+	// router and routedSenders are only set when logs are routed to other organizations
+	router        *router
+	routedSenders []*sender.Sender
 }
 
 // NewPipeline returns a new Pipeline
 func NewPipeline(outputChan chan *message.Message, processingRules []*config.ProcessingRule, endpoints *config.Endpoints, destinationsContext *client.DestinationsContext, diagnosticMessageReceiver diagnostic.MessageReceiver, serverless bool, pipelineID int) *Pipeline {
-	var destinations *client.Destinations
-	if endpoints.UseHTTP {
-		main := http.NewDestination(endpoints.Main, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend)
-		additionals := []client.Destination{}
-		for _, endpoint := range endpoints.Additionals {
-			additionals = append(additionals, http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend))
-		}
-		destinations = client.NewDestinations(main, additionals)
-	} else {
-		main := tcp.NewDestination(endpoints.Main, endpoints.UseProto, destinationsContext)
-		additionals := []client.Destination{}
-		for _, endpoint := range endpoints.Additionals {
-			additionals = append(additionals, tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext))
-		}
-		destinations = client.NewDestinations(main, additionals)
-	}
-
 	senderChan := make(chan *message.Message, config.ChanSize)
+	mainSender := newSender(senderChan, outputChan, endpoints.Main, endpoints.Additionals, endpoints, destinationsContext, serverless, pipelineID)
 
-	var strategy sender.Strategy
-	if endpoints.UseHTTP || serverless {
-		strategy = sender.NewBatchStrategy(sender.ArraySerializer, endpoints.BatchWait, endpoints.BatchMaxConcurrentSend, endpoints.BatchMaxSize, endpoints.BatchMaxContentSize, "logs", pipelineID)
-	} else {
-		strategy = sender.StreamStrategy
+	// the processed messages are sent to the sender of their routing rule, if any
+	processorChan := senderChan
+	var messageRouter *router
+	var routedSenders []*sender.Sender
+	if len(endpoints.Routes) > 0 {
+		routes := make([]route, 0, len(endpoints.Routes))
+		for _, routedEndpoint := range endpoints.Routes {
+			routeChan := make(chan *message.Message, config.ChanSize)
+			routedSenders = append(routedSenders, newSender(routeChan, outputChan, routedEndpoint.Endpoint, nil, endpoints, destinationsContext, serverless, pipelineID))
+			routes = append(routes, route{rule: routedEndpoint.Rule, inputChan: routeChan})
+		}
+		processorChan = make(chan *message.Message, config.ChanSize)
+		messageRouter = newRouter(processorChan, senderChan, routes)
 	}
-	sender := sender.NewSender(senderChan, outputChan, destinations, strategy)
 
 	var encoder processor.Encoder
 	if serverless {
@@ -66,29 +60,77 @@ func NewPipeline(outputChan chan *message.Message, processingRules []*config.Pro
 	}
 
 	inputChan := make(chan *message.Message, config.ChanSize)
-	processor := processor.New(inputChan, senderChan, processingRules, encoder, diagnosticMessageReceiver)
+	processor := processor.New(inputChan, processorChan, processingRules, encoder, diagnosticMessageReceiver)
 
 	return &Pipeline{
-		InputChan: inputChan,
-		processor: processor,
-		sender:    sender,
+		InputChan:     inputChan,
+		processor:     processor,
+		sender:        mainSender,
+		router:        messageRouter,
+		routedSenders: routedSenders,
+	}
+}
+
+// newSender returns a sender of the messages of inputChan to the main endpoint and the additional ones
+func newSender(inputChan, outputChan chan *message.Message, main config.Endpoint, additionals []config.Endpoint, endpoints *config.Endpoints, destinationsContext *client.DestinationsContext, serverless bool, pipelineID int) *sender.Sender {
+	var destinations *client.Destinations
+	if endpoints.UseHTTP {
+		mainDestination := http.NewDestination(main, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend)
+		additionalDestinations := []client.Destination{}
+		for _, endpoint := range additionals {
+			additionalDestinations = append(additionalDestinations, http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend))
+		}
+		destinations = client.NewDestinations(mainDestination, additionalDestinations)
+	} else {
+		mainDestination := tcp.NewDestination(main, endpoints.UseProto, destinationsContext)
+		additionalDestinations := []client.Destination{}
+		for _, endpoint := range additionals {
+			additionalDestinations = append(additionalDestinations, tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext))
+		}
+		destinations = client.NewDestinations(mainDestination, additionalDestinations)
 	}
+
+	var strategy sender.Strategy
+	if endpoints.UseHTTP || serverless {
+		strategy = sender.NewBatchStrategy(sender.ArraySerializer, endpoints.BatchWait, endpoints.BatchMaxConcurrentSend, endpoints.BatchMaxSize, endpoints.BatchMaxContentSize, "logs", pipelineID)
+	} else {
+		strategy = sender.StreamStrategy
+	}
+	return sender.NewSender(inputChan, outputChan, destinations, strategy)
 }
 
 // Start launches the pipeline
 func (p *Pipeline) Start() {
 	p.sender.Start()
+	for _, routedSender := range p.routedSenders {
+		routedSender.Start()
+	}
+	if p.router != nil {
+		p.router.Start()
+	}
 	p.processor.Start()
 }
 
 // Stop stops the pipeline
 func (p *Pipeline) Stop() {
 	p.processor.Stop()
+	if p.router != nil {
+		p.router.Stop()
+	}
 	p.sender.Stop()
+	for _, routedSender := range p.routedSenders {
+		routedSender.Stop()
+	}
 }
 
 // Flush flushes synchronously the processor and sender managed by this pipeline.
 func (p *Pipeline) Flush(ctx context.Context) {
 	p.processor.Flush(ctx) // flush messages in the processor into the sender
-	p.sender.Flush(ctx)    // flush the sender
+	if p.router != nil {
+		p.router.Flush(ctx) // flush messages in the router into the senders
+	}
+	p.sender.Flush(ctx) // flush the sender
+	for _, routedSender := range p.routedSenders {
+		routedSender.Flush(ctx)
+	}
 }
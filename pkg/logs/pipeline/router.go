@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package pipeline
+
+import (
+	"context"
+	"sync"
+
+	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+// route is the sender input of the logs matching a routing rule
+type route struct {
+	rule      coreConfig.RoutingRule
+	inputChan chan *message.Message
+}
+
+// router dispatches the processed messages between the sender of the main
+// endpoint and the senders of the routing rules, by tags.
+type router struct {
+	inputChan chan *message.Message
+	mainChan  chan *message.Message
+	routes    []route
+	done      chan struct{}
+	mu        sync.Mutex
+}
+
+func newRouter(inputChan, mainChan chan *message.Message, routes []route) *router {
+	return &router{
+		inputChan: inputChan,
+		mainChan:  mainChan,
+		routes:    routes,
+		done:      make(chan struct{}),
+	}
+}
+
+// Start starts the router.
+func (r *router) Start() {
+	go r.run()
+}
+
+// Stop stops the router,
+// this call blocks until inputChan is flushed
+func (r *router) Stop() {
+	close(r.inputChan)
+	<-r.done
+}
+
+// Flush dispatches synchronously the messages that this router has to dispatch.
+func (r *router) Flush(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			if len(r.inputChan) == 0 {
+				return
+			}
+			msg := <-r.inputChan
+			r.outputChan(msg) <- msg
+		}
+	}
+}
+
+func (r *router) run() {
+	defer func() {
+		r.done <- struct{}{}
+	}()
+	for msg := range r.inputChan {
+		r.mu.Lock() // block here if we're trying to flush synchronously
+		r.outputChan(msg) <- msg
+		r.mu.Unlock()
+	}
+}
+
+// outputChan returns the input of the sender of the first routing rule matching
+// the tags of the message, or of the main sender
+func (r *router) outputChan(msg *message.Message) chan *message.Message {
+	if msg.Origin == nil {
+		return r.mainChan
+	}
+	tags := msg.Origin.Tags()
+	for i := range r.routes {
+		if r.routes[i].rule.Match(tags) {
+			return r.routes[i].inputChan
+		}
+	}
+	return r.mainChan
+}
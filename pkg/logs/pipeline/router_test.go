@@ -0,0 +1,48 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package pipeline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+)
+
+func TestRouter(t *testing.T) {
+	inputChan := make(chan *message.Message, 10)
+	mainChan := make(chan *message.Message, 10)
+	paymentsChan := make(chan *message.Message, 10)
+	r := newRouter(inputChan, mainChan, []route{
+		{
+			rule:      coreConfig.RoutingRule{Name: "payments", Tags: []string{"team:payments"}, APIKey: "payments-api-key"},
+			inputChan: paymentsChan,
+		},
+	})
+
+	newMessage := func(tags ...string) *message.Message {
+		source := config.NewLogSource("", &config.LogsConfig{Tags: tags})
+		return message.NewMessageWithSource([]byte("message"), message.StatusInfo, source, 0)
+	}
+	checkout := newMessage("team:checkout")
+	payments := newMessage("env:prod", "team:payments")
+	noOrigin := message.NewMessage([]byte("message"), nil, message.StatusInfo, 0)
+
+	r.Start()
+	inputChan <- checkout
+	inputChan <- payments
+	inputChan <- noOrigin
+	r.Stop()
+
+	assert.Equal(t, checkout, <-mainChan)
+	assert.Equal(t, noOrigin, <-mainChan)
+	assert.Equal(t, payments, <-paymentsChan)
+	assert.Empty(t, mainChan)
+	assert.Empty(t, paymentsChan)
+}
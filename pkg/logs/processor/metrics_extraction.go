@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package processor
+
+import (
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator"
+	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	logsmetrics "github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	tlmMetricsExtracted = telemetry.NewCounter("logs", "metrics_extracted",
+		[]string{"rule"}, "Number of metric samples extracted from logs")
+	tlmMetricsExtractionErrors = telemetry.NewCounter("logs", "metrics_extraction_errors",
+		[]string{"rule"}, "Number of logs matching an extract_metric rule without a valid value")
+)
+
+// getAggregatorMetricChan returns the channel the extracted metric samples are sent to,
+// the samples are aggregated like the dogstatsd ones.
+var getAggregatorMetricChan = func() chan *metrics.MetricSample {
+	agg := aggregator.GetDefaultAggregator()
+	if agg == nil {
+		return nil
+	}
+	metricIn, _, _ := agg.GetChannels()
+	return metricIn
+}
+
+// extractMetric generates a metric sample from the content of a log matching an extract_metric rule
+func (p *Processor) extractMetric(rule *config.ProcessingRule, msg *message.Message, submatches [][]byte) {
+	p.metricChanOnce.Do(func() {
+		if p.metricChan == nil {
+			p.metricChan = getAggregatorMetricChan()
+		}
+		if p.metricChan == nil {
+			log.Warn("The aggregator is not running, the metrics of the extract_metric processing rules are dropped")
+		}
+	})
+	if p.metricChan == nil {
+		return
+	}
+
+	sample := &metrics.MetricSample{
+		Name:       rule.MetricName,
+		Value:      1,
+		Mtype:      metrics.CountType,
+		Host:       msg.GetHostname(),
+		SampleRate: 1,
+	}
+	if rule.MetricType == config.DistributionMetricType {
+		sample.Mtype = metrics.DistributionType
+	}
+
+	for i, group := range rule.Regex.SubexpNames() {
+		if group == "" || i >= len(submatches) {
+			continue
+		}
+		if group == rule.ValueGroup {
+			value, err := strconv.ParseFloat(string(submatches[i]), 64)
+			if err != nil {
+				tlmMetricsExtractionErrors.Inc(rule.Name)
+				log.Debugf("Invalid value %q for the metric %s of processing rule %s", submatches[i], rule.MetricName, rule.Name)
+				return
+			}
+			sample.Value = value
+			continue
+		}
+		for _, tagGroup := range rule.TagGroups {
+			if group == tagGroup && len(submatches[i]) > 0 {
+				sample.Tags = append(sample.Tags, group+":"+string(submatches[i]))
+			}
+		}
+	}
+	if service := msg.Origin.Service(); service != "" {
+		sample.Tags = append(sample.Tags, "service:"+service)
+	}
+	if source := msg.Origin.Source(); source != "" {
+		sample.Tags = append(sample.Tags, "source:"+source)
+	}
+
+	// the processor mustn't block the logs pipeline when the aggregator can't keep up
+	select {
+	case p.metricChan <- sample:
+		tlmMetricsExtracted.Inc(rule.Name)
+	default:
+		logsmetrics.MetricsExtractionDropped.Add(rule.Name, 1)
+		logsmetrics.TlmMetricsExtractionDropped.Inc(rule.Name)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
 	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	aggmetrics "github.com/DataDog/datadog-agent/pkg/metrics"
 )
 
 // A Processor updates messages from an inputChan and pushes
@@ -27,6 +28,10 @@ type Processor struct {
 	done                      chan struct{}
 	diagnosticMessageReceiver diagnostic.MessageReceiver
 	mu                        sync.Mutex
+	// metricChan receives the metrics of the extract_metric rules, it is
+	// resolved on first use as the aggregator may start after the logs agent
+	metricChan     chan *aggmetrics.MetricSample
+	metricChanOnce sync.Once
 }
 
 // New returns an initialized Processor.
@@ -87,6 +92,10 @@ func (p *Processor) processMessage(msg *message.Message) {
 	metrics.LogsDecoded.Add(1)
 	metrics.TlmLogsDecoded.Inc()
 	if shouldProcess, redactedMsg := p.applyRedactingRules(msg); shouldProcess {
+		if !p.allowedByRateLimit(msg) {
+			return
+		}
+
 		metrics.LogsProcessed.Add(1)
 		metrics.TlmLogsProcessed.Inc()
 
@@ -104,7 +113,8 @@ func (p *Processor) processMessage(msg *message.Message) {
 }
 
 // applyRedactingRules returns given a message if we should process it or not,
-// and a copy of the message with some fields redacted, depending on config
+// and a copy of the message with some fields redacted, depending on config.
+// The extract_metric rules generate metrics from the matching messages.
 func (p *Processor) applyRedactingRules(msg *message.Message) (bool, []byte) {
 	content := msg.Content
 	rules := append(p.processingRules, msg.Origin.LogSource.Config.ProcessingRules...)
@@ -120,7 +130,28 @@ func (p *Processor) applyRedactingRules(msg *message.Message) (bool, []byte) {
 			}
 		case config.MaskSequences:
 			content = rule.Regex.ReplaceAll(content, rule.Placeholder)
+		case config.ExtractMetric:
+			submatches := rule.Regex.FindSubmatch(content)
+			if submatches == nil {
+				continue
+			}
+			p.extractMetric(rule, msg, submatches)
+			if rule.ExcludeLog {
+				return false, nil
+			}
 		}
 	}
 	return true, content
 }
+
+// allowedByRateLimit returns false if the message must be dropped as its source
+// is over its log_rate_limit, the dropped messages are counted per source.
+func (p *Processor) allowedByRateLimit(msg *message.Message) bool {
+	source := msg.Origin.LogSource
+	if source.RateLimiter == nil || source.RateLimiter.Allow() {
+		return true
+	}
+	metrics.LogsRateLimited.Add(source.Name, 1)
+	metrics.TlmLogsRateLimited.Inc(source.Name)
+	return false
+}
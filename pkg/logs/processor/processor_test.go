@@ -10,7 +10,10 @@ import (
 	"testing"
 
 	"github.com/DataDog/datadog-agent/pkg/logs/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/diagnostic"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	aggmetrics "github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -138,6 +141,74 @@ func TestTruncate(t *testing.T) {
 	assert.Equal(t, []byte("hello"), redactedMessage)
 }
 
+func TestExtractMetric(t *testing.T) {
+	metricChan := make(chan *aggmetrics.MetricSample, 10)
+	p := &Processor{metricChan: metricChan}
+
+	countRule := &config.ProcessingRule{Type: config.ExtractMetric, Name: "errors", MetricName: "app.errors", Pattern: `level=%{LOGLEVEL:level} code=%{INT:code}`, TagGroups: []string{"code"}}
+	durationRule := &config.ProcessingRule{Type: config.ExtractMetric, Name: "duration", MetricName: "app.duration", MetricType: config.DistributionMetricType, Pattern: `took %{NUMBER:duration}ms`, ValueGroup: "duration", ExcludeLog: true}
+	source := config.LogSource{Config: &config.LogsConfig{Service: "app", ProcessingRules: []*config.ProcessingRule{countRule, durationRule}}}
+	assert.NoError(t, config.CompileProcessingRules(source.Config.ProcessingRules))
+
+	shouldProcess, _ := p.applyRedactingRules(newMessage([]byte("level=error code=503 request failed"), &source, ""))
+	assert.True(t, shouldProcess)
+	sample := <-metricChan
+	assert.Equal(t, "app.errors", sample.Name)
+	assert.Equal(t, aggmetrics.CountType, sample.Mtype)
+	assert.Equal(t, 1.0, sample.Value)
+	assert.ElementsMatch(t, []string{"code:503", "service:app"}, sample.Tags)
+
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("request took 12.5ms"), &source, ""))
+	assert.False(t, shouldProcess)
+	sample = <-metricChan
+	assert.Equal(t, "app.duration", sample.Name)
+	assert.Equal(t, aggmetrics.DistributionType, sample.Mtype)
+	assert.Equal(t, 12.5, sample.Value)
+	assert.ElementsMatch(t, []string{"service:app"}, sample.Tags)
+
+	shouldProcess, _ = p.applyRedactingRules(newMessage([]byte("hello"), &source, ""))
+	assert.True(t, shouldProcess)
+	assert.Len(t, metricChan, 0)
+}
+
+func TestExtractMetricDropped(t *testing.T) {
+	metricChan := make(chan *aggmetrics.MetricSample, 1)
+	p := &Processor{metricChan: metricChan}
+
+	rule := &config.ProcessingRule{Type: config.ExtractMetric, Name: "dropped_errors", MetricName: "app.errors", Pattern: `level=%{LOGLEVEL:level}`}
+	source := config.LogSource{Config: &config.LogsConfig{ProcessingRules: []*config.ProcessingRule{rule}}}
+	assert.NoError(t, config.CompileProcessingRules(source.Config.ProcessingRules))
+
+	// the samples are dropped instead of blocking the pipeline when the channel is full
+	for i := 0; i < 3; i++ {
+		shouldProcess, _ := p.applyRedactingRules(newMessage([]byte("level=error request failed"), &source, ""))
+		assert.True(t, shouldProcess)
+	}
+	assert.Len(t, metricChan, 1)
+	assert.Equal(t, "2", metrics.MetricsExtractionDropped.Get("dropped_errors").String())
+}
+
+func TestRateLimit(t *testing.T) {
+	encoded := make(chan *message.Message, 10)
+	p := New(nil, encoded, nil, RawEncoder, diagnostic.NewBufferedMessageReceiver())
+
+	source := config.NewLogSource("runaway", &config.LogsConfig{RateLimit: &config.RateLimit{LinesPerSecond: 0.001, Burst: 2}})
+	for i := 0; i < 5; i++ {
+		p.processMessage(newMessage([]byte("hello"), source, ""))
+	}
+	assert.Len(t, encoded, 2)
+	assert.Equal(t, []string{"3"}, source.GetInfo("Rate limited lines").Info())
+	assert.Equal(t, "3", metrics.LogsRateLimited.Get("runaway").String())
+
+	// the sources without log_rate_limit aren't limited
+	source = config.NewLogSource("", &config.LogsConfig{})
+	assert.Nil(t, source.RateLimiter)
+	for i := 0; i < 5; i++ {
+		p.processMessage(newMessage([]byte("hello"), source, ""))
+	}
+	assert.Len(t, encoded, 7)
+}
+
 func newProcessingRule(ruleType, replacePlaceholder, pattern string) *config.ProcessingRule {
 	return &config.ProcessingRule{
 		Type:               ruleType,
@@ -183,6 +183,9 @@ func (s *batchStrategy) sendMessages(messages []*message.Message, outputChan cha
 			return
 		}
 		log.Warnf("Could not send payload: %v", err)
+		for _, message := range messages {
+			message.DeliveryErr = err
+		}
 	}
 
 	metrics.LogsSent.Add(int64(len(messages)))
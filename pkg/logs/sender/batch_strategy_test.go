@@ -7,6 +7,7 @@ package sender
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -47,6 +48,27 @@ func TestBatchStrategySendsPayloadWhenBufferIsFull(t *testing.T) {
 	<-done
 }
 
+func TestBatchStrategySetsDeliveryError(t *testing.T) {
+	input := make(chan *message.Message)
+	output := make(chan *message.Message)
+	sendErr := errors.New("payload rejected")
+
+	done := make(chan bool)
+	go func() {
+		NewBatchStrategy(LineSerializer, 100*time.Millisecond, 0, 1, 10, "test", 0).Send(input, output, func(payload []byte) error { return sendErr })
+		close(done)
+	}()
+
+	message1 := message.NewMessage([]byte("a"), nil, "", 0)
+	input <- message1
+
+	// the message is forwarded, with the error of its payload
+	assert.Equal(t, message1, <-output)
+	assert.Equal(t, sendErr, message1.DeliveryErr)
+	close(input)
+	<-done
+}
+
 func TestBatchStrategySendsPayloadWhenBufferIsOutdated(t *testing.T) {
 	input := make(chan *message.Message)
 	output := make(chan *message.Message, 10)
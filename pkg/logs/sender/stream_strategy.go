@@ -36,6 +36,7 @@ func (s *streamStrategy) Send(inputChan chan *message.Message, outputChan chan *
 				return
 			}
 			log.Warnf("Could not send payload: %v", err)
+			message.DeliveryErr = err
 		}
 		metrics.LogsSent.Add(1)
 		metrics.TlmLogsSent.Inc()
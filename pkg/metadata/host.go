@@ -13,6 +13,7 @@ import (
 
 	v5 "github.com/DataDog/datadog-agent/pkg/metadata/v5"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 	"github.com/DataDog/datadog-agent/pkg/util"
 )
 
@@ -30,6 +31,12 @@ func (hp *HostCollector) Send(ctx context.Context, s *serializer.Serializer) err
 	return nil
 }
 
+// GetPayload returns the host metadata payload, without sending it
+func (hp *HostCollector) GetPayload(ctx context.Context) (marshaler.JSONMarshaler, error) {
+	hostnameData, _ := util.GetHostnameData(ctx)
+	return v5.GetPayload(ctx, hostnameData), nil
+}
+
 func init() {
 	RegisterCollector("host", new(HostCollector))
 }
@@ -20,7 +20,9 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/alibaba"
 	"github.com/DataDog/datadog-agent/pkg/util/cache"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
+	"github.com/DataDog/datadog-agent/pkg/util/ibm"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/oracle"
 	"github.com/DataDog/datadog-agent/pkg/util/tencent"
 
 	"github.com/DataDog/datadog-agent/pkg/metadata/host/container"
@@ -137,6 +139,13 @@ func getHostAliases(ctx context.Context) []string {
 		aliases = append(aliases, cfAliases...)
 	}
 
+	ibmAlias, err := ibm.GetHostAlias(ctx)
+	if err != nil {
+		log.Debugf("no IBM Cloud Host Alias: %s", err)
+	} else if ibmAlias != "" {
+		aliases = append(aliases, ibmAlias)
+	}
+
 	k8sAlias, err := kubelet.GetHostAlias(ctx)
 	if err != nil {
 		log.Debugf("no Kubernetes Host Alias (through kubelet API): %s", err)
@@ -144,6 +153,13 @@ func getHostAliases(ctx context.Context) []string {
 		aliases = append(aliases, k8sAlias)
 	}
 
+	oracleAlias, err := oracle.GetHostAlias(ctx)
+	if err != nil {
+		log.Debugf("no Oracle Cloud Host Alias: %s", err)
+	} else if oracleAlias != "" {
+		aliases = append(aliases, oracleAlias)
+	}
+
 	tencentAlias, err := tencent.GetHostAlias(ctx)
 	if err != nil {
 		log.Debugf("no Tencent Host Alias: %s", err)
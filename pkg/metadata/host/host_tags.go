@@ -12,9 +12,11 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/docker"
 	"github.com/DataDog/datadog-agent/pkg/util/ec2"
 	"github.com/DataDog/datadog-agent/pkg/util/gce"
+	"github.com/DataDog/datadog-agent/pkg/util/ibm"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/clustername"
 	k8s "github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/oracle"
 )
 
 var retrySleepTime = time.Second
@@ -110,6 +112,14 @@ func GetHostTags(ctx context.Context, cached bool) *Tags {
 		providers["gce"] = &providerDef{1, getGCE, false}
 	}
 
+	if config.Datadog.GetBool("collect_oracle_tags") {
+		providers["oracle"] = &providerDef{1, oracle.GetTags, false}
+	}
+
+	if config.Datadog.GetBool("collect_ibm_tags") {
+		providers["ibm"] = &providerDef{1, ibm.GetTags, false}
+	}
+
 	if config.IsFeaturePresent(config.Kubernetes) {
 		providers["kubernetes"] = &providerDef{10, k8s.GetTags, false}
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 	"github.com/DataDog/datadog-agent/pkg/util"
 )
 
@@ -20,13 +21,17 @@ type inventoriesCollector struct {
 	sc   *Scheduler
 }
 
-func createPayload(ctx context.Context, ac inventories.AutoConfigInterface, coll inventories.CollectorInterface) (*inventories.Payload, error) {
+// payloadBuilder builds the inventories payload: inventories.GetPayload records
+// when the payload is sent, inventories.CreatePayload only renders it
+type payloadBuilder func(ctx context.Context, hostname string, ac inventories.AutoConfigInterface, coll inventories.CollectorInterface) *inventories.Payload
+
+func createPayload(ctx context.Context, ac inventories.AutoConfigInterface, coll inventories.CollectorInterface, build payloadBuilder) (*inventories.Payload, error) {
 	hostname, err := util.GetHostname(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unable to submit inventories metadata payload, no hostname: %s", err)
 	}
 
-	return inventories.GetPayload(ctx, hostname, ac, coll), nil
+	return build(ctx, hostname, ac, coll), nil
 }
 
 // Send collects the data needed and submits the payload
@@ -35,7 +40,7 @@ func (c inventoriesCollector) Send(ctx context.Context, s *serializer.Serializer
 		return nil
 	}
 
-	payload, err := createPayload(ctx, c.ac, c.coll)
+	payload, err := createPayload(ctx, c.ac, c.coll, inventories.GetPayload)
 	if err != nil {
 		return err
 	}
@@ -46,6 +51,11 @@ func (c inventoriesCollector) Send(ctx context.Context, s *serializer.Serializer
 	return nil
 }
 
+// GetPayload returns the inventories payload, without sending it nor delaying the next send
+func (c inventoriesCollector) GetPayload(ctx context.Context) (marshaler.JSONMarshaler, error) {
+	return createPayload(ctx, c.ac, c.coll, inventories.CreatePayload)
+}
+
 // Init initializes the inventory metadata collection
 func (c inventoriesCollector) Init() error {
 	return inventories.StartMetadataUpdatedGoroutine(c.sc, config.Datadog.GetDuration("inventories_min_interval")*time.Second)
@@ -55,7 +65,7 @@ func (c inventoriesCollector) Init() error {
 func SetupInventoriesExpvar(ac inventories.AutoConfigInterface, coll inventories.CollectorInterface) {
 	expvar.Publish("inventories", expvar.Func(func() interface{} {
 		log.Debugf("Creating inventory payload for expvar")
-		p, err := createPayload(context.TODO(), ac, coll)
+		p, err := createPayload(context.TODO(), ac, coll, inventories.GetPayload)
 		if err != nil {
 			log.Errorf("Could not create inventory payload for expvar: %s", err)
 			return &inventories.Payload{}
@@ -155,6 +155,23 @@ func (c *Scheduler) firstRun() error {
 	return p.Send(context.TODO(), c.srl)
 }
 
+// GetPayload returns the payload the collector registered with this name would send, without sending it
+func GetPayload(ctx context.Context, name string) ([]byte, error) {
+	p, found := catalog[name]
+	if !found {
+		return nil, fmt.Errorf("unknown metadata collector: '%s'", name)
+	}
+	withPayload, ok := p.(CollectorWithPayload)
+	if !ok {
+		return nil, fmt.Errorf("the '%s' metadata collector can't render its payload", name)
+	}
+	payload, err := withPayload.GetPayload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return payload.MarshalJSON()
+}
+
 // RegisterCollector adds a Metadata Collector to the catalog
 func RegisterCollector(name string, metadataCollector Collector) {
 	catalog[name] = metadataCollector
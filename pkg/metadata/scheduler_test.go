@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	"github.com/DataDog/datadog-agent/pkg/metadata/inventories"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -194,3 +196,32 @@ func TestTriggerAndResetCollectorTimer(t *testing.T) {
 	}
 
 }
+
+type MockCollectorWithPayload struct{}
+
+func (c MockCollectorWithPayload) Send(ctx context.Context, s *serializer.Serializer) error {
+	return nil
+}
+
+func (c MockCollectorWithPayload) GetPayload(ctx context.Context) (marshaler.JSONMarshaler, error) {
+	return &inventories.Payload{Hostname: "test-host"}, nil
+}
+
+func TestGetPayload(t *testing.T) {
+	RegisterCollector("withPayload", MockCollectorWithPayload{})
+	RegisterCollector("withoutPayload", MockCollectorWithInit{})
+	defer func() {
+		delete(catalog, "withPayload")
+		delete(catalog, "withoutPayload")
+	}()
+
+	payload, err := GetPayload(context.Background(), "withPayload")
+	assert.NoError(t, err)
+	assert.Contains(t, string(payload), `"hostname":"test-host"`)
+
+	_, err = GetPayload(context.Background(), "withoutPayload")
+	assert.Error(t, err)
+
+	_, err = GetPayload(context.Background(), "unknown")
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 )
 
 // Collector is anything capable to collect and send metadata payloads
@@ -24,3 +25,9 @@ type Collector interface {
 type CollectorWithInit interface {
 	Init() error
 }
+
+// CollectorWithPayload is an optional interface that collectors can implement
+// to render the payload they would send, without sending it
+type CollectorWithPayload interface {
+	GetPayload(ctx context.Context) (marshaler.JSONMarshaler, error)
+}
@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metrics
+
+import "github.com/DataDog/datadog-agent/pkg/aggregator/ckey"
+
+// Baseline is the last sample of a counter, from which the next value of a
+// rate or a monotonic count is computed.
+type Baseline struct {
+	Value     float64 `json:"value"`
+	Timestamp float64 `json:"ts"`
+}
+
+// baselineMetric is implemented by the metrics computing their value from the
+// previous sample of a counter, whose baseline can be persisted across restarts.
+type baselineMetric interface {
+	// baseline returns the baseline of the metric, timestamp is used when the
+	// metric doesn't track the timestamp of its samples.
+	baseline(timestamp float64) (Baseline, bool)
+	// restoreBaseline sets the baseline of a metric sampled for the first time
+	restoreBaseline(b Baseline)
+}
+
+func (r *Rate) baseline(timestamp float64) (Baseline, bool) {
+	if r.timestamp != 0 {
+		return Baseline{Value: r.sample, Timestamp: r.timestamp}, true
+	}
+	if r.previousTimestamp != 0 {
+		return Baseline{Value: r.previousSample, Timestamp: r.previousTimestamp}, true
+	}
+	return Baseline{}, false
+}
+
+func (r *Rate) restoreBaseline(b Baseline) {
+	if r.previousTimestamp != 0 || r.timestamp <= b.Timestamp {
+		return
+	}
+	r.previousSample, r.previousTimestamp = b.Value, b.Timestamp
+}
+
+func (mc *MonotonicCount) baseline(timestamp float64) (Baseline, bool) {
+	if mc.sampledSinceLastFlush {
+		return Baseline{Value: mc.currentSample, Timestamp: timestamp}, true
+	}
+	if mc.hasPreviousSample {
+		return Baseline{Value: mc.previousSample, Timestamp: timestamp}, true
+	}
+	return Baseline{}, false
+}
+
+func (mc *MonotonicCount) restoreBaseline(b Baseline) {
+	// only a single sample without previous value can be completed by the baseline
+	if !mc.sampledSinceLastFlush || mc.hasPreviousSample || mc.flushFirstValue {
+		return
+	}
+	mc.previousSample, mc.hasPreviousSample = b.Value, true
+	// a lower value means that the counter was reset, nothing is flushed as
+	// when 2 consecutive samples decrease
+	if diff := mc.currentSample - b.Value; diff >= 0 {
+		mc.value += diff
+	}
+}
+
+// Baselines returns the baselines of the rates and monotonic counts
+func (cm *CheckMetrics) Baselines(timestamp float64) map[ckey.ContextKey]Baseline {
+	baselines := make(map[ckey.ContextKey]Baseline)
+	for key, m := range cm.metrics {
+		if bm, ok := m.(baselineMetric); ok {
+			if b, ok := bm.baseline(timestamp); ok {
+				baselines[key] = b
+			}
+		}
+	}
+	return baselines
+}
+
+// RestoreBaselines sets the baselines of the rates and monotonic counts sampled
+// for the first time, so that they're flushed without waiting for a second sample.
+func (cm *CheckMetrics) RestoreBaselines(baselines map[ckey.ContextKey]Baseline) {
+	for key, b := range baselines {
+		if bm, ok := cm.metrics[key].(baselineMetric); ok {
+			bm.restoreBaseline(b)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/ckey"
+)
+
+func TestRateBaseline(t *testing.T) {
+	mRate := Rate{}
+	_, ok := mRate.baseline(0)
+	assert.False(t, ok)
+
+	// a single sample is the baseline
+	mRate.addSample(&MetricSample{Value: 10}, 50)
+	assert.Equal(t, Baseline{Value: 10, Timestamp: 50}, mustBaseline(t, &mRate))
+	_, err := mRate.flush(60)
+	assert.Error(t, err)
+
+	mRate.addSample(&MetricSample{Value: 20}, 60)
+	_, err = mRate.flush(60)
+	require.NoError(t, err)
+	assert.Equal(t, Baseline{Value: 20, Timestamp: 60}, mustBaseline(t, &mRate))
+
+	// the baseline completes the first sample of a new rate
+	restored := Rate{}
+	restored.addSample(&MetricSample{Value: 40}, 70)
+	restored.restoreBaseline(Baseline{Value: 20, Timestamp: 60})
+	series, err := restored.flush(70)
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.InEpsilon(t, 2., series[0].Points[0].Value, epsilon)
+
+	// a baseline more recent than the sample is ignored
+	restored = Rate{}
+	restored.addSample(&MetricSample{Value: 40}, 70)
+	restored.restoreBaseline(Baseline{Value: 20, Timestamp: 80})
+	_, err = restored.flush(70)
+	assert.Error(t, err)
+}
+
+func TestMonotonicCountBaseline(t *testing.T) {
+	mc := MonotonicCount{}
+	_, ok := mc.baseline(0)
+	assert.False(t, ok)
+
+	mc.addSample(&MetricSample{Value: 10}, 50)
+	assert.Equal(t, Baseline{Value: 10, Timestamp: 55}, mustBaseline(t, &mc, 55))
+	_, err := mc.flush(55)
+	assert.Error(t, err)
+
+	mc.addSample(&MetricSample{Value: 15}, 60)
+	_, err = mc.flush(65)
+	require.NoError(t, err)
+	assert.Equal(t, Baseline{Value: 15, Timestamp: 70}, mustBaseline(t, &mc, 70))
+
+	// the baseline completes the first sample of a new monotonic count
+	restored := MonotonicCount{}
+	restored.addSample(&MetricSample{Value: 22}, 80)
+	restored.restoreBaseline(Baseline{Value: 15, Timestamp: 70})
+	series, err := restored.flush(80)
+	require.NoError(t, err)
+	require.Len(t, series, 1)
+	assert.Equal(t, 7., series[0].Points[0].Value)
+
+	// a counter reset flushes nothing
+	restored = MonotonicCount{}
+	restored.addSample(&MetricSample{Value: 3}, 80)
+	restored.restoreBaseline(Baseline{Value: 15, Timestamp: 70})
+	series, err = restored.flush(80)
+	require.NoError(t, err)
+	assert.Equal(t, 0., series[0].Points[0].Value)
+}
+
+func TestCheckMetricsBaselines(t *testing.T) {
+	t0 := 16_0000_0000.0
+	cm := NewCheckMetrics(true, time.Hour)
+	cm.AddSample(1, &MetricSample{Mtype: RateType, Value: 10}, t0, 1)
+	cm.AddSample(2, &MetricSample{Mtype: MonotonicCountType, Value: 100}, t0, 1)
+	cm.AddSample(3, &MetricSample{Mtype: GaugeType, Value: 1}, t0, 1)
+	cm.Flush(t0)
+
+	baselines := cm.Baselines(t0)
+	assert.Equal(t, map[ckey.ContextKey]Baseline{
+		1: {Value: 10, Timestamp: t0},
+		2: {Value: 100, Timestamp: t0},
+	}, baselines)
+
+	// after a restart
+	cm = NewCheckMetrics(true, time.Hour)
+	cm.AddSample(1, &MetricSample{Mtype: RateType, Value: 30}, t0+10, 1)
+	cm.AddSample(2, &MetricSample{Mtype: MonotonicCountType, Value: 150}, t0+10, 1)
+	cm.RestoreBaselines(baselines)
+
+	series, errs := cm.Flush(t0 + 10)
+	assert.Empty(t, errs)
+	require.Len(t, series, 2)
+	values := map[ckey.ContextKey]float64{}
+	for _, serie := range series {
+		values[serie.ContextKey] = serie.Points[0].Value
+	}
+	assert.Equal(t, map[ckey.ContextKey]float64{1: 2, 2: 50}, values)
+}
+
+func mustBaseline(t *testing.T, m baselineMetric, timestamp ...float64) Baseline {
+	var ts float64
+	if len(timestamp) > 0 {
+		ts = timestamp[0]
+	}
+	b, ok := m.baseline(ts)
+	require.True(t, ok)
+	return b
+}
@@ -80,6 +80,9 @@ type MetricSample struct {
 	OriginID        string
 	K8sOriginID     string
 	Cardinality     string
+	// Metadata is the optional metadata of the timeseries of the sample, the metadata of the last
+	// sample of a context is the one flushed with its series
+	Metadata *SerieMetadata
 }
 
 // Implement the MetricSampleContext interface
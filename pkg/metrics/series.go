@@ -42,6 +42,21 @@ func (p *Point) MarshalJSON() ([]byte, error) {
 	return []byte(fmt.Sprintf("[%v, %v]", int64(p.Ts), p.Value)), nil
 }
 
+// Resource is an entity a timeseries is attributed to, e.g. a container or a network device
+type Resource struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// SerieMetadata holds the optional metadata of a timeseries, declared by the checks that know
+// them so that they don't have to be guessed by the backend
+type SerieMetadata struct {
+	// Unit is the unit of the points, e.g. byte or core
+	Unit string
+	// Resources are the entities the timeseries is attributed to
+	Resources []Resource
+}
+
 // Serie holds a timeseries (w/ json serialization to DD API format)
 type Serie struct {
 	Name           string          `json:"metric"`
@@ -52,6 +67,8 @@ type Serie struct {
 	MType          APIMetricType   `json:"type"`
 	Interval       int64           `json:"interval"`
 	SourceTypeName string          `json:"source_type_name,omitempty"`
+	Unit           string          `json:"-"` // metadata of the series, not part of the v1 payload
+	Resources      []Resource      `json:"-"` // metadata of the series, not part of the v1 payload
 	ContextKey     ckey.ContextKey `json:"-"`
 	NameSuffix     string          `json:"-"`
 }
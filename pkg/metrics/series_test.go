@@ -297,6 +297,35 @@ func TestStreamJSONMarshaler(t *testing.T) {
 	}
 }
 
+func TestStreamJSONMarshalerWithMetadata(t *testing.T) {
+	series := Series{
+		{
+			Points: []Point{
+				{Ts: 12345.0, Value: float64(1024)},
+			},
+			MType:     APIGaugeType,
+			Name:      "container.memory.usage",
+			Interval:  15,
+			Host:      "localHost",
+			Tags:      []string{"tag1"},
+			Unit:      "byte",
+			Resources: []Resource{{Type: "container", Name: "abcdef"}},
+		},
+	}
+
+	// the metadata isn't part of the v1 payload
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, nil, 0)
+	err := series.WriteItem(stream, 0)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(stream.Buffer()), "unit")
+	assert.NotContains(t, string(stream.Buffer()), "resources")
+
+	payload, err := series.MarshalJSON()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(payload), "unit")
+	assert.NotContains(t, string(payload), "resources")
+}
+
 func TestStreamJSONMarshalerWithDevice(t *testing.T) {
 	series := Series{
 		{
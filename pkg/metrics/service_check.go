@@ -80,11 +80,17 @@ type ServiceCheck struct {
 	Status      ServiceCheckStatus `json:"status"`
 	Message     string             `json:"message"`
 	Tags        []string           `json:"tags"`
+	Data        ServiceCheckData   `json:"data,omitempty"`
 	OriginID    string             `json:"-"`
 	K8sOriginID string             `json:"-"`
 	Cardinality string             `json:"-"`
 }
 
+// ServiceCheckData holds structured data attached to a service check, such
+// as error codes or device identifiers, so that they don't need to be packed
+// into its message. Values must be serializable to JSON.
+type ServiceCheckData map[string]interface{}
+
 // ServiceChecks represents a list of service checks ready to be serialize
 type ServiceChecks []*ServiceCheck
 
@@ -248,6 +254,16 @@ func writeServiceCheck(sc *ServiceCheck, stream *jsoniter.Stream) error {
 			return err
 		}
 	}
+
+	if len(sc.Data) > 0 {
+		stream.WriteMore()
+		stream.WriteObjectField("data")
+		stream.WriteVal(sc.Data)
+		if stream.Error != nil {
+			return stream.Error
+		}
+	}
+
 	if err := writer.FinishObject(); err != nil {
 		return err
 	}
@@ -37,6 +37,28 @@ func TestMarshalJSONServiceChecks(t *testing.T) {
 	assert.Equal(t, payload, []byte("[{\"check\":\"my_service.can_connect\",\"host_name\":\"my-hostname\",\"timestamp\":12345,\"status\":0,\"message\":\"my_service is up\",\"tags\":[\"tag1\",\"tag2:yes\"]}]\n"))
 }
 
+func TestMarshalJSONServiceChecksWithData(t *testing.T) {
+	serviceChecks := ServiceChecks{{
+		CheckName: "snmp.can_check",
+		Host:      "my-hostname",
+		Ts:        int64(12345),
+		Status:    ServiceCheckCritical,
+		Message:   "timeout",
+		Tags:      []string{"tag1"},
+		Data:      ServiceCheckData{"device_id": "default:1.2.3.4", "reachable": false},
+	}}
+
+	payload, err := serviceChecks.MarshalJSON()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("[{\"check\":\"snmp.can_check\",\"host_name\":\"my-hostname\",\"timestamp\":12345,\"status\":2,\"message\":\"timeout\",\"tags\":[\"tag1\"],\"data\":{\"device_id\":\"default:1.2.3.4\",\"reachable\":false}}]\n"), payload)
+}
+
+func TestPayloadsServiceCheckWithData(t *testing.T) {
+	serviceCheck := createServiceCheck("checkName")
+	serviceCheck.Data = ServiceCheckData{"error_code": 2}
+	assertEqualToMarshalJSON(t, ServiceChecks{serviceCheck})
+}
+
 func TestSplitServiceChecks(t *testing.T) {
 	var serviceChecks = ServiceChecks{}
 	for i := 0; i < 2; i++ {
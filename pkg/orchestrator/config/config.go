@@ -25,6 +25,7 @@ import (
 const (
 	orchestratorNS  = "orchestrator_explorer"
 	processNS       = "process_config"
+	forwarderNS     = "forwarder"
 	defaultEndpoint = "https://orchestrator.datadoghq.com"
 	maxMessageBatch = 100
 )
@@ -36,6 +37,7 @@ type OrchestratorConfig struct {
 	KubeClusterName                string
 	IsScrubbingEnabled             bool
 	Scrubber                       *redact.DataScrubber
+	ManifestScrubber               *redact.ManifestScrubber
 	OrchestratorEndpoints          []apicfg.Endpoint
 	MaxPerMessage                  int
 	PodQueueBytes                  int // The total number of bytes that can be enqueued for delivery to the orchestrator endpoint
@@ -75,6 +77,10 @@ func (oc *OrchestratorConfig) Load() error {
 	if key := "api_key"; config.Datadog.IsSet(key) {
 		oc.OrchestratorEndpoints[0].APIKey = config.SanitizeAPIKey(config.Datadog.GetString(key))
 	}
+	// The orchestrator intake can use its own API key
+	if k := key(orchestratorNS, "api_key"); config.Datadog.IsSet(k) && config.Datadog.GetString(k) != "" {
+		oc.OrchestratorEndpoints[0].APIKey = config.SanitizeAPIKey(config.Datadog.GetString(k))
+	}
 
 	if err := extractOrchestratorAdditionalEndpoints(URL, &oc.OrchestratorEndpoints); err != nil {
 		return err
@@ -112,6 +118,15 @@ func (oc *OrchestratorConfig) Load() error {
 		}
 	}
 	oc.IsScrubbingEnabled = config.Datadog.GetBool("orchestrator_explorer.container_scrubbing.enabled")
+
+	// User defined rules removing fields from the manifests, per resource kind
+	var rules []redact.ManifestScrubbingRule
+	if err := config.Datadog.UnmarshalKey(key(orchestratorNS, "manifest_scrubbing", "rules"), &rules); err != nil {
+		return fmt.Errorf("invalid manifest scrubbing rules: %v", err)
+	}
+	if oc.ManifestScrubber, err = redact.NewManifestScrubber(rules); err != nil {
+		return err
+	}
 	oc.ExtraTags = config.Datadog.GetStringSlice("orchestrator_explorer.extra_tags")
 
 	return nil
@@ -173,6 +188,40 @@ func NewOrchestratorForwarder() *forwarder.DefaultForwarder {
 	keysPerDomain := apicfg.KeysPerDomains(orchestratorCfg.OrchestratorEndpoints)
 	orchestratorForwarderOpts := forwarder.NewOptionsWithResolvers(resolver.NewSingleDomainResolvers(keysPerDomain))
 	orchestratorForwarderOpts.DisableAPIKeyChecking = true
+	setForwarderOptions(orchestratorForwarderOpts)
 
 	return forwarder.NewDefaultForwarder(orchestratorForwarderOpts)
 }
+
+// setForwarderOptions overrides the options inherited from the main forwarder with the orchestrator
+// specific ones, so that the manifests and the metrics don't share their retry queues and backoff.
+func setForwarderOptions(opts *forwarder.Options) {
+	if k := key(orchestratorNS, forwarderNS, "num_workers"); config.Datadog.IsSet(k) {
+		if numWorkers := config.Datadog.GetInt(k); numWorkers > 0 {
+			opts.NumberOfWorkers = numWorkers
+		} else {
+			log.Warnf("Invalid %s (%d), the value of forwarder_num_workers is used", k, numWorkers)
+		}
+	}
+
+	if k := key(orchestratorNS, forwarderNS, "retry_queue_payloads_max_size"); config.Datadog.IsSet(k) {
+		if maxSize := config.Datadog.GetInt(k); maxSize >= 0 {
+			opts.RetryQueuePayloadsTotalMaxSize = maxSize
+		} else {
+			log.Warnf("Invalid %s (%d), the retry queue size of the main forwarder is used", k, maxSize)
+		}
+	}
+
+	if k := key(orchestratorNS, forwarderNS, "backoff_max"); config.Datadog.IsSet(k) {
+		if backoffMax := config.Datadog.GetFloat64(k); backoffMax > 0 {
+			opts.BackoffMax = backoffMax
+		} else {
+			log.Warnf("Invalid %s (%v), the value of forwarder_backoff_max is used", k, backoffMax)
+		}
+	}
+
+	// The manifests are only stored on disk when enabled for the orchestrator forwarder,
+	// in their own folder so that they don't use the space of the metrics
+	opts.StorageMaxSizeInBytes = config.Datadog.GetInt64(key(orchestratorNS, forwarderNS, "storage_max_size_in_bytes"))
+	opts.StorageFolder = "orchestrator"
+}
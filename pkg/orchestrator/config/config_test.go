@@ -13,6 +13,7 @@ import (
 	"testing"
 
 	coreConfig "github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder"
 	apicfg "github.com/DataDog/datadog-agent/pkg/process/util/api/config"
 	"github.com/stretchr/testify/suite"
 )
@@ -260,6 +261,86 @@ func (suite *YamlConfigTestSuite) TestOnlyEnvContainsConfigArgsScrubbing() {
 	}
 }
 
+func (suite *YamlConfigTestSuite) TestManifestScrubbingRules() {
+	suite.config.Set("orchestrator_explorer.manifest_scrubbing.rules", []map[string]interface{}{
+		{
+			"kinds":            []string{"Deployment"},
+			"drop_annotations": []string{"vault.hashicorp.com/*"},
+		},
+	})
+
+	orchestratorCfg := NewDefaultOrchestratorConfig()
+	err := orchestratorCfg.Load()
+	suite.NoError(err)
+	suite.True(orchestratorCfg.ManifestScrubber.HasRules("Deployment"))
+	suite.False(orchestratorCfg.ManifestScrubber.HasRules("Pod"))
+}
+
+func (suite *YamlConfigTestSuite) TestInvalidManifestScrubbingRules() {
+	suite.config.Set("orchestrator_explorer.manifest_scrubbing.rules", []map[string]interface{}{
+		{
+			"kinds":        []string{"Deployment"},
+			"remove_paths": []string{"spec.template"},
+		},
+	})
+
+	orchestratorCfg := NewDefaultOrchestratorConfig()
+	err := orchestratorCfg.Load()
+	suite.Error(err)
+}
+
+func (suite *YamlConfigTestSuite) TestOrchestratorAPIKey() {
+	suite.config.Set("api_key", "wassupkey")
+	suite.config.Set("orchestrator_explorer.api_key", "orchestratorkey")
+
+	orchestratorCfg := NewDefaultOrchestratorConfig()
+	err := orchestratorCfg.Load()
+	suite.NoError(err)
+	suite.Equal("orchestratorkey", orchestratorCfg.OrchestratorEndpoints[0].APIKey)
+}
+
+func (suite *YamlConfigTestSuite) TestForwarderOptionsInherited() {
+	suite.config.Set("forwarder_num_workers", 4)
+	suite.config.Set("forwarder_retry_queue_payloads_max_size", 1234)
+	suite.config.Set("forwarder_storage_max_size_in_bytes", 5678)
+
+	opts := forwarder.NewOptionsWithResolvers(nil)
+	setForwarderOptions(opts)
+	suite.Equal(4, opts.NumberOfWorkers)
+	suite.Equal(1234, opts.RetryQueuePayloadsTotalMaxSize)
+	suite.Equal(float64(0), opts.BackoffMax)
+	// the manifests are not stored on disk with the metrics
+	suite.Equal(int64(0), opts.StorageMaxSizeInBytes)
+}
+
+func (suite *YamlConfigTestSuite) TestForwarderOptionsOverridden() {
+	suite.config.Set("forwarder_num_workers", 4)
+	suite.config.Set("forwarder_retry_queue_payloads_max_size", 1234)
+	suite.config.Set("orchestrator_explorer.forwarder.num_workers", 2)
+	suite.config.Set("orchestrator_explorer.forwarder.retry_queue_payloads_max_size", 4321)
+	suite.config.Set("orchestrator_explorer.forwarder.backoff_max", 30)
+	suite.config.Set("orchestrator_explorer.forwarder.storage_max_size_in_bytes", 8765)
+
+	opts := forwarder.NewOptionsWithResolvers(nil)
+	setForwarderOptions(opts)
+	suite.Equal(2, opts.NumberOfWorkers)
+	suite.Equal(4321, opts.RetryQueuePayloadsTotalMaxSize)
+	suite.Equal(float64(30), opts.BackoffMax)
+	suite.Equal(int64(8765), opts.StorageMaxSizeInBytes)
+	suite.Equal("orchestrator", opts.StorageFolder)
+}
+
+func (suite *YamlConfigTestSuite) TestForwarderOptionsInvalid() {
+	suite.config.Set("forwarder_num_workers", 4)
+	suite.config.Set("orchestrator_explorer.forwarder.num_workers", 0)
+	suite.config.Set("orchestrator_explorer.forwarder.backoff_max", -1)
+
+	opts := forwarder.NewOptionsWithResolvers(nil)
+	setForwarderOptions(opts)
+	suite.Equal(4, opts.NumberOfWorkers)
+	suite.Equal(float64(0), opts.BackoffMax)
+}
+
 func TestYamlConfigTestSuite(t *testing.T) {
 	suite.Run(t, new(YamlConfigTestSuite))
 }
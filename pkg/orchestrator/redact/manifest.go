@@ -0,0 +1,255 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// allKinds is the kind matching every resource kind in a ManifestScrubbingRule
+const allKinds = "*"
+
+// ManifestScrubbingRule describes the fields removed from the manifests of some
+// resource kinds before they are sent to the orchestrator endpoint.
+type ManifestScrubbingRule struct {
+	// Kinds are the resource kinds the rule applies to (e.g. "Deployment"), "*" matches every kind
+	Kinds []string `mapstructure:"kinds"`
+	// RemovePaths are JSONPath expressions of the fields to remove, e.g.
+	// `$.spec.template.spec.containers[*].env` or `$.metadata.labels['app.kubernetes.io/secret']`
+	RemovePaths []string `mapstructure:"remove_paths"`
+	// DropAnnotations are the annotation keys to remove from the resource and
+	// from its pod template, shell patterns are supported (e.g. "vault.hashicorp.com/*")
+	DropAnnotations []string `mapstructure:"drop_annotations"`
+}
+
+// pathSegment is a parsed JSONPath element: a map key or a wildcard on a list
+type pathSegment struct {
+	key      string
+	wildcard bool
+}
+
+type compiledRule struct {
+	paths           [][]pathSegment
+	dropAnnotations []string
+}
+
+// ManifestScrubber removes the fields matching user defined rules from the
+// JSON manifests of the resources.
+type ManifestScrubber struct {
+	rulesByKind map[string][]compiledRule
+}
+
+// NewManifestScrubber compiles the given rules into a ManifestScrubber
+func NewManifestScrubber(rules []ManifestScrubbingRule) (*ManifestScrubber, error) {
+	s := &ManifestScrubber{rulesByKind: make(map[string][]compiledRule)}
+	for i, rule := range rules {
+		if len(rule.Kinds) == 0 {
+			return nil, fmt.Errorf("manifest scrubbing rule %d: no kind defined", i)
+		}
+
+		compiled := compiledRule{dropAnnotations: rule.DropAnnotations}
+		for _, pattern := range rule.DropAnnotations {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("manifest scrubbing rule %d: invalid annotation pattern %q: %v", i, pattern, err)
+			}
+		}
+		for _, p := range rule.RemovePaths {
+			segments, err := parseJSONPath(p)
+			if err != nil {
+				return nil, fmt.Errorf("manifest scrubbing rule %d: %v", i, err)
+			}
+			compiled.paths = append(compiled.paths, segments)
+		}
+
+		for _, kind := range rule.Kinds {
+			kind = strings.ToLower(kind)
+			s.rulesByKind[kind] = append(s.rulesByKind[kind], compiled)
+		}
+	}
+	return s, nil
+}
+
+// HasRules returns whether the manifests of the given kind are modified by the scrubber
+func (s *ManifestScrubber) HasRules(kind string) bool {
+	if s == nil {
+		return false
+	}
+	return len(s.rulesByKind[strings.ToLower(kind)]) > 0 || len(s.rulesByKind[allKinds]) > 0
+}
+
+// ScrubManifest applies the rules defined for `kind` to the JSON `manifest`.
+// The manifest is returned unchanged when no rule applies to the kind.
+func (s *ManifestScrubber) ScrubManifest(kind string, manifest []byte) ([]byte, error) {
+	if !s.HasRules(kind) {
+		return manifest, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(manifest))
+	// keep numbers untouched, they would be converted to float64 otherwise
+	decoder.UseNumber()
+	var obj interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("could not parse %s manifest: %v", kind, err)
+	}
+
+	var rules []compiledRule
+	rules = append(rules, s.rulesByKind[strings.ToLower(kind)]...)
+	rules = append(rules, s.rulesByKind[allKinds]...)
+	for _, rule := range rules {
+		for _, segments := range rule.paths {
+			removePath(obj, segments)
+		}
+		if len(rule.dropAnnotations) > 0 {
+			dropAnnotations(obj, rule.dropAnnotations)
+		}
+	}
+
+	return json.Marshal(obj)
+}
+
+// DropObjectAnnotations removes the annotations dropped by the rules of `kind` from the annotations of
+// a resource, so that they are neither in its manifest nor in the metadata extracted from it
+func (s *ManifestScrubber) DropObjectAnnotations(kind string, annotations map[string]string) {
+	if !s.HasRules(kind) || len(annotations) == 0 {
+		return
+	}
+
+	var rules []compiledRule
+	rules = append(rules, s.rulesByKind[strings.ToLower(kind)]...)
+	rules = append(rules, s.rulesByKind[allKinds]...)
+	for key := range annotations {
+		if matchesAnnotation(rules, key) {
+			delete(annotations, key)
+		}
+	}
+}
+
+func matchesAnnotation(rules []compiledRule, key string) bool {
+	for _, rule := range rules {
+		for _, pattern := range rule.dropAnnotations {
+			// patterns were validated when compiling the rules
+			if matched, _ := path.Match(pattern, key); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dropAnnotations removes the matching annotations of the resource and of its pod template
+func dropAnnotations(obj interface{}, patterns []string) {
+	annotationPaths := [][]pathSegment{
+		{{key: "metadata"}, {key: "annotations"}},
+		{{key: "spec"}, {key: "template"}, {key: "metadata"}, {key: "annotations"}},
+		{{key: "spec"}, {key: "jobTemplate"}, {key: "spec"}, {key: "template"}, {key: "metadata"}, {key: "annotations"}},
+	}
+	for _, segments := range annotationPaths {
+		annotations, ok := lookup(obj, segments).(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range annotations {
+			for _, pattern := range patterns {
+				// patterns were validated when compiling the rules
+				if matched, _ := path.Match(pattern, key); matched {
+					delete(annotations, key)
+					break
+				}
+			}
+		}
+	}
+}
+
+// lookup returns the value at the given path, which must not contain wildcards
+func lookup(obj interface{}, segments []pathSegment) interface{} {
+	for _, segment := range segments {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		obj = m[segment.key]
+	}
+	return obj
+}
+
+// removePath removes every field matching the path
+func removePath(obj interface{}, segments []pathSegment) {
+	if len(segments) == 0 {
+		return
+	}
+	segment, last := segments[0], len(segments) == 1
+
+	if segment.wildcard {
+		list, ok := obj.([]interface{})
+		if !ok || last {
+			// removing list elements is not supported, the list itself must be removed
+			return
+		}
+		for _, item := range list {
+			removePath(item, segments[1:])
+		}
+		return
+	}
+
+	m, ok := obj.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if last {
+		delete(m, segment.key)
+		return
+	}
+	if child, found := m[segment.key]; found {
+		removePath(child, segments[1:])
+	}
+}
+
+// parseJSONPath parses the subset of JSONPath supported by the scrubber:
+// `$.key`, `$['key']` and `[*]` elements.
+func parseJSONPath(p string) ([]pathSegment, error) {
+	if !strings.HasPrefix(p, "$") {
+		return nil, fmt.Errorf("invalid path %q: must start with '$'", p)
+	}
+
+	var segments []pathSegment
+	rest := p[1:]
+	for len(rest) > 0 {
+		switch {
+		case strings.HasPrefix(rest, "[*]"):
+			segments = append(segments, pathSegment{wildcard: true})
+			rest = rest[3:]
+		case strings.HasPrefix(rest, "['"):
+			end := strings.Index(rest, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid path %q: unterminated bracket", p)
+			}
+			segments = append(segments, pathSegment{key: rest[2:end]})
+			rest = rest[end+2:]
+		case rest[0] == '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", p)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+		default:
+			return nil, fmt.Errorf("invalid path %q: unexpected %q", p, rest)
+		}
+	}
+
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid path %q: the whole manifest cannot be removed", p)
+	}
+	return segments, nil
+}
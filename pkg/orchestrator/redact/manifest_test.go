@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deploymentManifest = `{
+  "metadata": {
+    "name": "web",
+    "generation": 12345678901234567,
+    "annotations": {
+      "vault.hashicorp.com/agent-inject-secret-db": "database/creds/db",
+      "deployment.kubernetes.io/revision": "3"
+    },
+    "labels": {"app.kubernetes.io/name": "web", "team": "a"}
+  },
+  "spec": {
+    "template": {
+      "metadata": {"annotations": {"vault.hashicorp.com/role": "web"}},
+      "spec": {
+        "containers": [
+          {"name": "web", "image": "web:1", "env": [{"name": "TOKEN", "value": "secret"}]},
+          {"name": "sidecar", "image": "sidecar:1"}
+        ]
+      }
+    }
+  }
+}`
+
+func TestScrubManifest(t *testing.T) {
+	scrubber, err := NewManifestScrubber([]ManifestScrubbingRule{
+		{
+			Kinds:           []string{"deployment"},
+			RemovePaths:     []string{"$.spec.template.spec.containers[*].env", "$.metadata.labels['app.kubernetes.io/name']"},
+			DropAnnotations: []string{"vault.hashicorp.com/*"},
+		},
+		{
+			Kinds:       []string{"*"},
+			RemovePaths: []string{"$.metadata.labels.team"},
+		},
+	})
+	require.NoError(t, err)
+
+	scrubbed, err := scrubber.ScrubManifest("Deployment", []byte(deploymentManifest))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+  "metadata": {
+    "name": "web",
+    "generation": 12345678901234567,
+    "annotations": {"deployment.kubernetes.io/revision": "3"},
+    "labels": {}
+  },
+  "spec": {
+    "template": {
+      "metadata": {"annotations": {}},
+      "spec": {
+        "containers": [
+          {"name": "web", "image": "web:1"},
+          {"name": "sidecar", "image": "sidecar:1"}
+        ]
+      }
+    }
+  }
+}`, string(scrubbed))
+	// large integers are not converted to floats
+	assert.Contains(t, string(scrubbed), "12345678901234567")
+}
+
+func TestScrubManifestNoRule(t *testing.T) {
+	scrubber, err := NewManifestScrubber([]ManifestScrubbingRule{
+		{Kinds: []string{"Pod"}, RemovePaths: []string{"$.spec"}},
+	})
+	require.NoError(t, err)
+
+	manifest := []byte(deploymentManifest)
+	scrubbed, err := scrubber.ScrubManifest("Deployment", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, scrubbed)
+
+	// a nil scrubber doesn't modify manifests
+	var nilScrubber *ManifestScrubber
+	scrubbed, err = nilScrubber.ScrubManifest("Deployment", manifest)
+	require.NoError(t, err)
+	assert.Equal(t, manifest, scrubbed)
+}
+
+func TestNewManifestScrubberErrors(t *testing.T) {
+	for _, rule := range []ManifestScrubbingRule{
+		{RemovePaths: []string{"$.spec"}},
+		{Kinds: []string{"Pod"}, RemovePaths: []string{"spec"}},
+		{Kinds: []string{"Pod"}, RemovePaths: []string{"$"}},
+		{Kinds: []string{"Pod"}, RemovePaths: []string{"$.metadata['name"}},
+		{Kinds: []string{"Pod"}, RemovePaths: []string{"$..name"}},
+		{Kinds: []string{"Pod"}, DropAnnotations: []string{"[a-"}},
+	} {
+		_, err := NewManifestScrubber([]ManifestScrubbingRule{rule})
+		assert.Error(t, err, "%+v", rule)
+	}
+}
+
+func TestDropObjectAnnotations(t *testing.T) {
+	scrubber, err := NewManifestScrubber([]ManifestScrubbingRule{
+		{Kinds: []string{"Deployment"}, DropAnnotations: []string{"vault.hashicorp.com/*"}},
+		{Kinds: []string{"*"}, DropAnnotations: []string{"secret"}},
+	})
+	require.NoError(t, err)
+
+	annotations := map[string]string{
+		"vault.hashicorp.com/role": "web",
+		"secret":                   "value",
+		"team":                     "a",
+	}
+	scrubber.DropObjectAnnotations("Pod", annotations)
+	assert.Equal(t, map[string]string{"vault.hashicorp.com/role": "web", "team": "a"}, annotations)
+
+	scrubber.DropObjectAnnotations("deployment", annotations)
+	assert.Equal(t, map[string]string{"team": "a"}, annotations)
+
+	// a nil scrubber doesn't drop anything
+	var noScrubber *ManifestScrubber
+	noScrubber.DropObjectAnnotations("Deployment", annotations)
+	assert.Equal(t, map[string]string{"team": "a"}, annotations)
+}
@@ -7,7 +7,11 @@ package otlp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
@@ -19,21 +23,30 @@ import (
 	"go.uber.org/zap/zapcore"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/ipcextension"
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/samplingprocessor"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/serializerexporter"
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/spanmetricsexporter"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/status/health"
 	"github.com/DataDog/datadog-agent/pkg/util/flavor"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	zapAgent "github.com/DataDog/datadog-agent/pkg/util/log/zap"
 	"github.com/DataDog/datadog-agent/pkg/version"
 )
 
-func getComponents(s serializer.MetricSerializer) (
+// healthCheckInterval is the interval at which the readiness of the pipeline is reported to the Agent health
+const healthCheckInterval = time.Second
+
+func getComponents(s serializer.MetricSerializer, ipc *ipcextension.Handler) (
 	component.Factories,
 	error,
 ) {
 	var errs []error
 
-	extensions, err := component.MakeExtensionFactoryMap()
+	extensions, err := component.MakeExtensionFactoryMap(
+		ipcextension.NewFactory(ipc),
+	)
 	if err != nil {
 		errs = append(errs, err)
 	}
@@ -48,6 +61,7 @@ func getComponents(s serializer.MetricSerializer) (
 	exporters, err := component.MakeExporterFactoryMap(
 		otlpexporter.NewFactory(),
 		serializerexporter.NewFactory(s),
+		spanmetricsexporter.NewFactory(s),
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -55,6 +69,7 @@ func getComponents(s serializer.MetricSerializer) (
 
 	processors, err := component.MakeProcessorFactoryMap(
 		batchprocessor.NewFactory(),
+		samplingprocessor.NewFactory(),
 	)
 	if err != nil {
 		errs = append(errs, err)
@@ -88,11 +103,20 @@ type PipelineConfig struct {
 	MetricsEnabled bool
 	// TracesEnabled states whether OTLP traces support is enabled.
 	TracesEnabled bool
+	// SpanMetricsEnabled states whether request, error and duration metrics are computed from OTLP spans.
+	SpanMetricsEnabled bool
+	// SamplingConfig is the configuration of the processor sampling the traces before they
+	// are sent to the trace Agent, traces are not sampled when it is nil.
+	SamplingConfig map[string]interface{}
 }
 
 // Pipeline is an OTLP pipeline.
 type Pipeline struct {
 	col *service.Collector
+	ipc *ipcextension.Handler
+
+	mu       sync.RWMutex
+	runError error
 }
 
 // NewPipeline defines a new OTLP pipeline.
@@ -102,7 +126,8 @@ func NewPipeline(cfg PipelineConfig, s serializer.MetricSerializer) (*Pipeline,
 		return nil, fmt.Errorf("failed to get build info: %w", err)
 	}
 
-	factories, err := getComponents(s)
+	ipc := &ipcextension.Handler{}
+	factories, err := getComponents(s, ipc)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get components: %w", err)
 	}
@@ -124,12 +149,85 @@ func NewPipeline(cfg PipelineConfig, s serializer.MetricSerializer) (*Pipeline,
 		return nil, err
 	}
 
-	return &Pipeline{col}, nil
+	// Translate the metrics of the collector components into Agent telemetry
+	registerTelemetryExporter()
+
+	return &Pipeline{col: col, ipc: ipc}, nil
 }
 
 // Run the OTLP pipeline.
 func (p *Pipeline) Run(ctx context.Context) error {
-	return p.col.Run(ctx)
+	stopHealth := make(chan struct{})
+	go p.reportHealth(stopHealth)
+
+	err := p.col.Run(ctx)
+	if err == nil {
+		close(stopHealth)
+	}
+	// on errors, the pipeline stays registered and is reported as unhealthy
+
+	p.mu.Lock()
+	p.runError = err
+	p.mu.Unlock()
+	return err
+}
+
+// reportHealth reports the pipeline as unhealthy in `agent health` while its
+// pipelines aren't ready, so that a broken pipeline doesn't fail silently.
+func (p *Pipeline) reportHealth(stop <-chan struct{}) {
+	healthHandle := health.RegisterReadiness("otlp-pipeline")
+	defer health.Deregister(healthHandle) //nolint:errcheck
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !p.ipc.Ready() {
+				continue
+			}
+			select {
+			case <-healthHandle.C:
+			default:
+			}
+		}
+	}
+}
+
+// Ready returns whether the pipelines are started and ready to receive data.
+func (p *Pipeline) Ready() bool {
+	return p != nil && p.ipc.Ready()
+}
+
+// ZPagesHandler returns the handler serving the zPages of the pipeline under
+// the `/debug` path prefix: the pipelines, the extensions, the traces...
+func (p *Pipeline) ZPagesHandler() http.Handler {
+	return p.ipc
+}
+
+// GetStatus returns key-value data for use in status reporting of the OTLP pipeline.
+func (p *Pipeline) GetStatus() map[string]interface{} {
+	status := make(map[string]interface{})
+	if p == nil {
+		status["error"] = "the OTLP pipeline failed to start"
+		return status
+	}
+
+	status["ready"] = p.Ready()
+
+	metricsJSON := []byte(componentMetrics.String())
+	metrics := make(map[string]interface{})
+	json.Unmarshal(metricsJSON, &metrics) //nolint:errcheck
+	status["metrics"] = metrics
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.runError != nil {
+		status["error"] = p.runError.Error()
+	}
+	return status
 }
 
 // Stop the OTLP pipeline.
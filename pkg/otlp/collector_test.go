@@ -16,12 +16,13 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/service"
 
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/ipcextension"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/testutil"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 )
 
 func TestGetComponents(t *testing.T) {
-	_, err := getComponents(&serializer.MockSerializer{})
+	_, err := getComponents(&serializer.MockSerializer{}, &ipcextension.Handler{})
 	// No duplicate component
 	require.NoError(t, err)
 }
@@ -45,11 +46,14 @@ func TestStartPipeline(t *testing.T) {
 
 	assert.Equal(t, service.Starting, <-p.col.GetStateChannel())
 	assert.Equal(t, service.Running, <-p.col.GetStateChannel())
+	assert.True(t, p.Ready())
+	assert.Equal(t, true, p.GetStatus()["ready"])
 
 	p.Stop()
 	p.Stop()
 	<-colDone
 	assert.Equal(t, service.Closing, <-p.col.GetStateChannel())
 	assert.Equal(t, service.Closed, <-p.col.GetStateChannel())
+	assert.False(t, p.Ready())
 
 }
@@ -7,6 +7,7 @@ package otlp
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	colConfig "go.opentelemetry.io/collector/config"
@@ -82,6 +83,47 @@ func fromExperimentalPortReceiverConfig(cfg config.Config, otlpConfig *colConfig
 	return multierr.Combine(errs...)
 }
 
+// samplingRule is a sampling rate for the traces of a service and a resource in the Agent configuration.
+type samplingRule struct {
+	Service  string  `mapstructure:"service"`
+	Resource string  `mapstructure:"resource"`
+	Rate     float64 `mapstructure:"rate"`
+}
+
+// fromExperimentalSamplingConfig builds the sampling processor configuration.
+func fromExperimentalSamplingConfig(cfg config.Config) (map[string]interface{}, error) {
+	var errs []error
+
+	rate := cfg.GetFloat64(config.ExperimentalOTLPSamplingRate)
+	if rate < 0 || rate > 1 {
+		errs = append(errs, fmt.Errorf("sampling rate %v is out of [0, 1] range", rate))
+	}
+
+	var rules []samplingRule
+	if err := cfg.UnmarshalKey(config.ExperimentalOTLPSamplingRules, &rules); err != nil {
+		errs = append(errs, fmt.Errorf("sampling rules are invalid: %w", err))
+	}
+	processorRules := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Rate < 0 || rule.Rate > 1 {
+			errs = append(errs, fmt.Errorf("sampling rate %v of the rule for service %q and resource %q is out of [0, 1] range", rule.Rate, rule.Service, rule.Resource))
+		}
+		processorRules = append(processorRules, map[string]interface{}{
+			"service":       rule.Service,
+			"resource":      rule.Resource,
+			"sampling_rate": rule.Rate,
+		})
+	}
+
+	return map[string]interface{}{
+		"sampling_rate":     rate,
+		"rules":             processorRules,
+		"keep_errors":       cfg.GetBool(config.ExperimentalOTLPSamplingKeepErrors),
+		"latency_threshold": time.Duration(cfg.GetInt(config.ExperimentalOTLPSamplingLatencyThreshold)) * time.Millisecond,
+		"decision_wait":     time.Duration(cfg.GetInt(config.ExperimentalOTLPSamplingDecisionWait)) * time.Second,
+	}, multierr.Combine(errs...)
+}
+
 // fromExperimentalConfig builds a PipelineConfig from the experimental configuration.
 func fromExperimentalConfig(cfg config.Config) (PipelineConfig, error) {
 	var errs []error
@@ -104,11 +146,29 @@ func fromExperimentalConfig(cfg config.Config) (PipelineConfig, error) {
 		errs = append(errs, fmt.Errorf("at least one OTLP signal needs to be enabled"))
 	}
 
+	spanMetricsEnabled := cfg.GetBool(config.ExperimentalOTLPSpanMetricsEnabled)
+	if spanMetricsEnabled && !tracesEnabled {
+		errs = append(errs, fmt.Errorf("OTLP traces need to be enabled to compute span metrics"))
+	}
+
+	var samplingConfig map[string]interface{}
+	if cfg.GetBool(config.ExperimentalOTLPSamplingEnabled) {
+		if !tracesEnabled {
+			errs = append(errs, fmt.Errorf("OTLP traces need to be enabled to sample them"))
+		}
+		samplingConfig, err = fromExperimentalSamplingConfig(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("OTLP sampling configuration is invalid: %w", err))
+		}
+	}
+
 	return PipelineConfig{
 		OTLPReceiverConfig: otlpConfig.ToStringMap(),
 		TracePort:          tracePort,
 		MetricsEnabled:     metricsEnabled,
 		TracesEnabled:      tracesEnabled,
+		SpanMetricsEnabled: spanMetricsEnabled && tracesEnabled,
+		SamplingConfig:     samplingConfig,
 	}, multierr.Combine(errs...)
 }
 
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/testutil"
@@ -101,6 +102,45 @@ func TestFromAgentConfigPort(t *testing.T) {
 			path: "port/alldisabled.yaml",
 			err:  "at least one OTLP signal needs to be enabled",
 		},
+		{
+			path: "port/spanmetrics.yaml",
+			cfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("bindhost", 5678, 1234),
+				TracePort:          5003,
+				MetricsEnabled:     true,
+				TracesEnabled:      true,
+				SpanMetricsEnabled: true,
+			},
+		},
+		{
+			path: "port/spanmetricsnotraces.yaml",
+			err:  "OTLP traces need to be enabled to compute span metrics",
+		},
+		{
+			path: "port/sampling.yaml",
+			cfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("bindhost", 5678, 1234),
+				TracePort:          5003,
+				MetricsEnabled:     true,
+				TracesEnabled:      true,
+				SamplingConfig: map[string]interface{}{
+					"sampling_rate": 0.1,
+					"rules": []interface{}{
+						map[string]interface{}{"service": "checkout", "resource": "", "sampling_rate": 1.0},
+						map[string]interface{}{"service": "frontend", "resource": "GET /health", "sampling_rate": 0.0},
+					},
+					"keep_errors":       true,
+					"latency_threshold": 500 * time.Millisecond,
+					"decision_wait":     5 * time.Second,
+				},
+			},
+		},
+		{
+			path: "port/samplinginvalid.yaml",
+			err: "OTLP sampling configuration is invalid: " +
+				"sampling rate 2 is out of [0, 1] range; " +
+				`sampling rate -1 of the rule for service "checkout" and resource "" is out of [0, 1] range`,
+		},
 	}
 
 	for _, testInstance := range tests {
@@ -0,0 +1,132 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+// Package ipcextension implements an extension exposing the readiness and the
+// zPages of the embedded collector through a Handler, so that they can be
+// served by the Agent IPC server instead of dedicated listeners.
+package ipcextension
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"go.opencensus.io/zpages"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/extensionhelper"
+)
+
+const (
+	// TypeStr defines the IPC extension type string.
+	TypeStr = "agent_ipc"
+
+	// ZPagesPathPrefix is the path prefix of the zPages served by the Handler.
+	ZPagesPathPrefix = "/debug"
+)
+
+var _ config.Extension = (*extensionConfig)(nil)
+
+// extensionConfig is the extension configuration.
+type extensionConfig struct {
+	config.ExtensionSettings `mapstructure:",squash"`
+}
+
+func newDefaultConfig() config.Extension {
+	return &extensionConfig{}
+}
+
+// Handler serves the zPages of the running collector and reports whether its
+// pipelines are ready. The zero value is ready to use: it answers with a 503
+// until the extension is started.
+type Handler struct {
+	mu    sync.RWMutex
+	mux   *http.ServeMux
+	ready bool
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.RLock()
+	mux := h.mux
+	h.mu.RUnlock()
+
+	if mux == nil {
+		http.Error(w, "the OTLP pipeline is not running", http.StatusServiceUnavailable)
+		return
+	}
+	mux.ServeHTTP(w, r)
+}
+
+// Ready returns whether the pipelines of the collector are started and ready to receive data.
+func (h *Handler) Ready() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.ready
+}
+
+func (h *Handler) setMux(mux *http.ServeMux) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.mux = mux
+}
+
+func (h *Handler) setReady(ready bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ready = ready
+}
+
+// NewFactory creates a new IPC extension factory reporting to h.
+func NewFactory(h *Handler) component.ExtensionFactory {
+	return extensionhelper.NewFactory(
+		TypeStr,
+		newDefaultConfig,
+		func(context.Context, component.ExtensionCreateSettings, config.Extension) (component.Extension, error) {
+			return &extension{handler: h}, nil
+		},
+	)
+}
+
+var (
+	_ component.Extension       = (*extension)(nil)
+	_ component.PipelineWatcher = (*extension)(nil)
+)
+
+type extension struct {
+	handler *Handler
+}
+
+// Start registers the zPages of the collector, its pipelines and extensions among them.
+func (e *extension) Start(_ context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	zpages.Handle(mux, ZPagesPathPrefix)
+	if hostZPages, ok := host.(interface {
+		RegisterZPages(mux *http.ServeMux, pathPrefix string)
+	}); ok {
+		hostZPages.RegisterZPages(mux, ZPagesPathPrefix)
+	}
+	e.handler.setMux(mux)
+	return nil
+}
+
+// Shutdown unregisters the zPages.
+func (e *extension) Shutdown(context.Context) error {
+	e.handler.setReady(false)
+	e.handler.setMux(nil)
+	return nil
+}
+
+// Ready is called by the collector once all the pipelines are started.
+func (e *extension) Ready() error {
+	e.handler.setReady(true)
+	return nil
+}
+
+// NotReady is called by the collector before the pipelines are shut down.
+func (e *extension) NotReady() error {
+	e.handler.setReady(false)
+	return nil
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+package samplingprocessor
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+var _ config.Processor = (*processorConfig)(nil)
+
+// rule sets the sampling rate of the traces whose root span matches a service and a resource,
+// an empty service or resource matches any value.
+type rule struct {
+	Service      string  `mapstructure:"service"`
+	Resource     string  `mapstructure:"resource"`
+	SamplingRate float64 `mapstructure:"sampling_rate"`
+}
+
+func (r rule) matches(service, resource string) bool {
+	return (r.Service == "" || r.Service == service) && (r.Resource == "" || r.Resource == resource)
+}
+
+// processorConfig is the processor configuration.
+type processorConfig struct {
+	config.ProcessorSettings `mapstructure:",squash"`
+
+	// SamplingRate is the rate of the traces kept when no rule matches their root span.
+	SamplingRate float64 `mapstructure:"sampling_rate"`
+	// Rules are the sampling rates by service and resource, the first matching rule applies.
+	Rules []rule `mapstructure:"rules"`
+	// KeepErrors keeps the traces containing an error, whatever their sampling rate.
+	KeepErrors bool `mapstructure:"keep_errors"`
+	// LatencyThreshold keeps the traces containing a span longer than it, 0 disables the rule.
+	LatencyThreshold time.Duration `mapstructure:"latency_threshold"`
+	// DecisionWait is the time the spans of a trace are buffered before deciding to keep the trace.
+	DecisionWait time.Duration `mapstructure:"decision_wait"`
+	// MaxTraces is the maximum number of buffered traces, the oldest ones are decided early.
+	MaxTraces int `mapstructure:"max_traces"`
+}
+
+func newDefaultConfig() config.Processor {
+	return &processorConfig{
+		SamplingRate: 1,
+		KeepErrors:   true,
+		DecisionWait: 5 * time.Second,
+		MaxTraces:    50000,
+	}
+}
+
+// Validate checks the sampling rates are valid.
+func (c *processorConfig) Validate() error {
+	if c.SamplingRate < 0 || c.SamplingRate > 1 {
+		return fmt.Errorf("sampling rate %v is out of [0, 1] range", c.SamplingRate)
+	}
+	for _, r := range c.Rules {
+		if r.SamplingRate < 0 || r.SamplingRate > 1 {
+			return fmt.Errorf("sampling rate %v of the rule for service %q and resource %q is out of [0, 1] range", r.SamplingRate, r.Service, r.Resource)
+		}
+	}
+	if c.MaxTraces <= 0 {
+		return fmt.Errorf("the maximum number of buffered traces must be positive")
+	}
+	return nil
+}
+
+// rateFor returns the sampling rate of a trace whose root span has the given service and resource.
+func (c *processorConfig) rateFor(service, resource string) float64 {
+	for _, r := range c.Rules {
+		if r.matches(service, resource) {
+			return r.SamplingRate
+		}
+	}
+	return c.SamplingRate
+}
@@ -0,0 +1,33 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+package samplingprocessor
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+const (
+	// TypeStr defines the sampling processor type string.
+	TypeStr = "sampling"
+)
+
+// NewFactory creates a new sampling processor factory.
+func NewFactory() component.ProcessorFactory {
+	return processorhelper.NewFactory(
+		TypeStr,
+		newDefaultConfig,
+		processorhelper.WithTraces(createTracesProcessor),
+	)
+}
+
+func createTracesProcessor(_ context.Context, params component.ProcessorCreateSettings, cfg config.Processor, next consumer.Traces) (component.TracesProcessor, error) {
+	return newProcessor(params.Logger, cfg.(*processorConfig), next), nil
+}
@@ -0,0 +1,299 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+package samplingprocessor
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+const (
+	// serviceNameAttribute is the resource attribute holding the service of the spans.
+	serviceNameAttribute = "service.name"
+	// samplerHasher is the Knuth hashing factor of the trace Agent samplers
+	samplerHasher = uint64(1111111111111111111)
+	// sampleRateAttribute is the attribute of the root spans holding the rate their trace
+	// was sampled with, the trace Agent weighs the stats of the trace with it.
+	sampleRateAttribute = "_sample_rate"
+)
+
+var _ component.TracesProcessor = (*processor)(nil)
+
+// trace holds the spans of a trace received during the decision wait and
+// the properties used to decide whether the trace is kept.
+type trace struct {
+	id       uint64
+	td       pdata.Traces
+	received time.Time
+
+	hasRoot     bool
+	service     string
+	resource    string
+	hasError    bool
+	maxDuration time.Duration
+}
+
+// add updates the properties of the trace with a span of the given service.
+func (t *trace) add(service string, span pdata.Span) {
+	if span.Status().Code() == pdata.StatusCodeError {
+		t.hasError = true
+	}
+	if end, start := span.EndTimestamp(), span.StartTimestamp(); end >= start {
+		if d := time.Duration(end - start); d > t.maxDuration {
+			t.maxDuration = d
+		}
+	}
+	// the root span names the trace, the first span received is used until it shows up
+	if !t.hasRoot && (span.ParentSpanID().IsEmpty() || t.service == "" && t.resource == "") {
+		t.hasRoot = span.ParentSpanID().IsEmpty()
+		t.service = service
+		t.resource = span.Name()
+	}
+}
+
+// processor buffers the spans of each trace for the decision wait, then keeps the
+// traces with errors or slow spans and samples the others with the rate of their
+// service and resource before handing them to the next consumer.
+type processor struct {
+	logger *zap.Logger
+	cfg    *processorConfig
+	next   consumer.Traces
+	now    func() time.Time
+
+	mu     sync.Mutex
+	traces map[[16]byte]*trace
+	// order holds the IDs of the buffered traces, the oldest first
+	order [][16]byte
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newProcessor(logger *zap.Logger, cfg *processorConfig, next consumer.Traces) *processor {
+	return &processor{
+		logger: logger,
+		cfg:    cfg,
+		next:   next,
+		now:    time.Now,
+		traces: make(map[[16]byte]*trace),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start releases the traces buffered for longer than the decision wait.
+func (p *processor) Start(_ context.Context, _ component.Host) error {
+	if p.cfg.DecisionWait <= 0 {
+		return nil
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case now := <-ticker.C:
+				p.forward(p.releaseExpired(now.Add(-p.cfg.DecisionWait)))
+			}
+		}
+	}()
+	return nil
+}
+
+// Shutdown decides the buffered traces without waiting.
+func (p *processor) Shutdown(context.Context) error {
+	close(p.stop)
+	p.wg.Wait()
+	p.forward(p.releaseExpired(p.now()))
+	return nil
+}
+
+// Capabilities returns the consumer capabilities, the spans are copied to be buffered.
+func (p *processor) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces buffers the spans of td, grouped by trace.
+func (p *processor) ConsumeTraces(_ context.Context, td pdata.Traces) error {
+	now := p.now()
+
+	p.mu.Lock()
+	p.group(td, now)
+	var released []*trace
+	if p.cfg.DecisionWait <= 0 {
+		released = p.release(now, 0)
+	} else if len(p.order) > p.cfg.MaxTraces {
+		released = p.release(time.Time{}, len(p.order)-p.cfg.MaxTraces)
+	}
+	p.mu.Unlock()
+
+	p.forward(released)
+	return nil
+}
+
+// group copies the spans of td to the buffered traces, must be called with the lock held.
+func (p *processor) group(td pdata.Traces, now time.Time) {
+	type destKey struct {
+		id   [16]byte
+		i, j int
+	}
+	// the spans of a trace sharing their resource and instrumentation library are copied together
+	dests := make(map[destKey]pdata.SpanSlice)
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		var service string
+		if v, ok := rs.Resource().Attributes().Get(serviceNameAttribute); ok {
+			service = v.StringVal()
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				id := span.TraceID().Bytes()
+
+				t, found := p.traces[id]
+				if !found {
+					t = &trace{
+						id:       binary.BigEndian.Uint64(id[8:]),
+						td:       pdata.NewTraces(),
+						received: now,
+					}
+					p.traces[id] = t
+					p.order = append(p.order, id)
+				}
+
+				key := destKey{id: id, i: i, j: j}
+				dest, found := dests[key]
+				if !found {
+					destRS := t.td.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(destRS.Resource())
+					destILS := destRS.InstrumentationLibrarySpans().AppendEmpty()
+					ils.InstrumentationLibrary().CopyTo(destILS.InstrumentationLibrary())
+					dest = destILS.Spans()
+					dests[key] = dest
+				}
+				span.CopyTo(dest.AppendEmpty())
+				t.add(service, span)
+			}
+		}
+	}
+}
+
+// releaseExpired removes the traces received up to the deadline from the buffer and returns them.
+func (p *processor) releaseExpired(deadline time.Time) []*trace {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.release(deadline, 0)
+}
+
+// release removes the traces received up to the deadline, or at least the count
+// oldest ones, from the buffer and returns them. It must be called with the lock held.
+func (p *processor) release(deadline time.Time, count int) []*trace {
+	n := 0
+	for n < len(p.order) && (n < count || !p.traces[p.order[n]].received.After(deadline)) {
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+
+	released := make([]*trace, 0, n)
+	for _, id := range p.order[:n] {
+		released = append(released, p.traces[id])
+		delete(p.traces, id)
+	}
+	p.order = append(p.order[:0:0], p.order[n:]...)
+	return released
+}
+
+// keep decides whether a trace is sent to the next consumer, and returns the rate
+// it was sampled with, 1 for the traces kept because of their errors or latency.
+func (p *processor) keep(t *trace) (bool, float64) {
+	if p.cfg.KeepErrors && t.hasError {
+		return true, 1
+	}
+	if p.cfg.LatencyThreshold > 0 && t.maxDuration >= p.cfg.LatencyThreshold {
+		return true, 1
+	}
+	rate := p.cfg.rateFor(t.service, t.resource)
+	return sampleByRate(t.id, rate), rate
+}
+
+// sampleByRate decides whether a trace is kept from its ID like the trace Agent samplers
+// do, so that the decision is consistent with the ones of the other agents.
+func sampleByRate(traceID uint64, rate float64) bool {
+	if rate < 1 {
+		return traceID*samplerHasher < uint64(rate*math.MaxUint64)
+	}
+	return true
+}
+
+// setSampleRate multiplies the sample rate of the root spans of td by rate, so that
+// the stats computed by the trace Agent account for the traces dropped.
+func setSampleRate(td pdata.Traces, rate float64) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !span.ParentSpanID().IsEmpty() {
+					continue
+				}
+				attrs := span.Attributes()
+				spanRate := rate
+				if v, ok := attrs.Get(sampleRateAttribute); ok && v.Type() == pdata.AttributeValueTypeDouble && v.DoubleVal() > 0 && v.DoubleVal() <= 1 {
+					spanRate *= v.DoubleVal()
+				}
+				attrs.UpsertDouble(sampleRateAttribute, spanRate)
+			}
+		}
+	}
+}
+
+// forward sends the kept traces to the next consumer.
+func (p *processor) forward(traces []*trace) {
+	if len(traces) == 0 {
+		return
+	}
+
+	td := pdata.NewTraces()
+	for _, t := range traces {
+		kept, rate := p.keep(t)
+		if !kept {
+			continue
+		}
+		if rate < 1 {
+			setSampleRate(t.td, rate)
+		}
+		t.td.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	}
+	p.logger.Debug("Sampled traces", zap.Int("received", len(traces)), zap.Int("spans_kept", td.SpanCount()))
+
+	if td.SpanCount() == 0 {
+		return
+	}
+	if err := p.next.ConsumeTraces(context.Background(), td); err != nil {
+		p.logger.Warn("Failed to forward sampled traces", zap.Error(err))
+	}
+}
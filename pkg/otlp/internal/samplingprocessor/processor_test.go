@@ -0,0 +1,226 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+//go:build test
+// +build test
+
+package samplingprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+)
+
+type testSpan struct {
+	traceID  byte
+	parent   byte
+	name     string
+	duration time.Duration
+	isError  bool
+}
+
+func newTestTraces(service string, spans ...testSpan) pdata.Traces {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", service)
+
+	ss := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	start := pdata.NewTimestampFromTime(time.Now())
+	for _, s := range spans {
+		span := ss.AppendEmpty()
+		span.SetTraceID(pdata.NewTraceID([16]byte{15: s.traceID}))
+		if s.parent != 0 {
+			span.SetParentSpanID(pdata.NewSpanID([8]byte{s.parent}))
+		}
+		span.SetName(s.name)
+		span.SetStartTimestamp(start)
+		span.SetEndTimestamp(start + pdata.Timestamp(s.duration))
+		if s.isError {
+			span.Status().SetCode(pdata.StatusCodeError)
+		}
+	}
+	return td
+}
+
+func newTestProcessor(cfg *processorConfig) (*processor, *consumertest.TracesSink) {
+	sink := new(consumertest.TracesSink)
+	return newProcessor(zap.NewNop(), cfg, sink), sink
+}
+
+func TestRateFor(t *testing.T) {
+	cfg := &processorConfig{
+		SamplingRate: 0.1,
+		Rules: []rule{
+			{Service: "checkout", Resource: "GET /health", SamplingRate: 0},
+			{Service: "checkout", SamplingRate: 1},
+			{Resource: "POST /login", SamplingRate: 0.5},
+		},
+	}
+
+	assert.Equal(t, 0.0, cfg.rateFor("checkout", "GET /health"))
+	assert.Equal(t, 1.0, cfg.rateFor("checkout", "GET /cart"))
+	assert.Equal(t, 0.5, cfg.rateFor("frontend", "POST /login"))
+	assert.Equal(t, 0.1, cfg.rateFor("frontend", "GET /"))
+}
+
+func TestValidate(t *testing.T) {
+	cfg := newDefaultConfig().(*processorConfig)
+	assert.NoError(t, cfg.Validate())
+
+	cfg.SamplingRate = 1.5
+	assert.Error(t, cfg.Validate())
+
+	cfg.SamplingRate = 1
+	cfg.Rules = []rule{{Service: "checkout", SamplingRate: -1}}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestSampleWithoutDecisionWait(t *testing.T) {
+	p, sink := newTestProcessor(&processorConfig{
+		SamplingRate:     0,
+		Rules:            []rule{{Service: "checkout", Resource: "GET /cart", SamplingRate: 1}},
+		KeepErrors:       true,
+		LatencyThreshold: time.Second,
+		MaxTraces:        10,
+	})
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTestTraces("checkout",
+		// kept by the rule of its root span
+		testSpan{traceID: 1, name: "GET /cart"},
+		testSpan{traceID: 1, parent: 1, name: "SELECT"},
+		// dropped
+		testSpan{traceID: 2, name: "GET /health"},
+		testSpan{traceID: 2, parent: 1, name: "SELECT"},
+		// kept because of the error
+		testSpan{traceID: 3, name: "GET /health"},
+		testSpan{traceID: 3, parent: 1, name: "SELECT", isError: true},
+		// kept because of the latency
+		testSpan{traceID: 4, name: "GET /health", duration: 2 * time.Second},
+	)))
+
+	assert.Equal(t, 5, sink.SpanCount())
+	assert.Empty(t, p.traces)
+	assert.Empty(t, p.order)
+}
+
+func TestSampleAfterDecisionWait(t *testing.T) {
+	p, sink := newTestProcessor(&processorConfig{
+		SamplingRate: 0,
+		KeepErrors:   true,
+		DecisionWait: 5 * time.Second,
+		MaxTraces:    10,
+	})
+	now := time.Now()
+	p.now = func() time.Time { return now }
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTestTraces("checkout",
+		testSpan{traceID: 1, name: "GET /cart"},
+	)))
+	// the error is received in another batch, from another service
+	now = now.Add(time.Second)
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTestTraces("payment",
+		testSpan{traceID: 1, parent: 1, name: "POST /charge", isError: true},
+		testSpan{traceID: 2, name: "GET /health"},
+	)))
+	assert.Equal(t, 0, sink.SpanCount())
+
+	p.forward(p.releaseExpired(now.Add(-time.Second)))
+	assert.Equal(t, 2, sink.SpanCount())
+	require.Len(t, sink.AllTraces(), 1)
+	assert.Equal(t, 2, sink.AllTraces()[0].ResourceSpans().Len())
+	require.Len(t, p.order, 1)
+
+	// the remaining trace is decided on shutdown
+	require.NoError(t, p.Shutdown(context.Background()))
+	assert.Equal(t, 2, sink.SpanCount())
+	assert.Empty(t, p.traces)
+}
+
+func TestSampleMaxTraces(t *testing.T) {
+	p, sink := newTestProcessor(&processorConfig{
+		SamplingRate: 1,
+		DecisionWait: time.Minute,
+		MaxTraces:    2,
+	})
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), newTestTraces("checkout",
+		testSpan{traceID: 1, name: "GET /cart"},
+		testSpan{traceID: 2, name: "GET /cart"},
+		testSpan{traceID: 3, name: "GET /cart"},
+	)))
+
+	// the oldest trace is decided early
+	assert.Equal(t, 1, sink.SpanCount())
+	assert.Equal(t, pdata.NewTraceID([16]byte{15: 1}), sink.AllTraces()[0].ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).TraceID())
+	assert.Len(t, p.order, 2)
+}
+
+func TestSampleRateAttribute(t *testing.T) {
+	p, sink := newTestProcessor(&processorConfig{
+		SamplingRate: 0.5,
+		KeepErrors:   true,
+		MaxTraces:    100,
+	})
+
+	var spans []testSpan
+	for id := byte(1); id <= 20; id++ {
+		spans = append(spans,
+			testSpan{traceID: id, name: "GET /cart"},
+			testSpan{traceID: id, parent: 1, name: "SELECT"},
+		)
+	}
+	// kept because of the error
+	spans = append(spans, testSpan{traceID: 21, name: "GET /cart", isError: true})
+	td := newTestTraces("checkout", spans...)
+	// the rate already set upstream is multiplied
+	td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Attributes().InsertDouble(sampleRateAttribute, 0.5)
+	require.NoError(t, p.ConsumeTraces(context.Background(), td))
+
+	roots := 0
+	for _, td := range sink.AllTraces() {
+		rss := td.ResourceSpans()
+		for i := 0; i < rss.Len(); i++ {
+			spans := rss.At(i).InstrumentationLibrarySpans().At(0).Spans()
+			for j := 0; j < spans.Len(); j++ {
+				span := spans.At(j)
+				rate, found := span.Attributes().Get(sampleRateAttribute)
+				traceID := span.TraceID().Bytes()[15]
+				switch {
+				case !span.ParentSpanID().IsEmpty(), traceID == 21:
+					assert.False(t, found, traceID)
+				case traceID == 1:
+					roots++
+					require.True(t, found)
+					assert.Equal(t, 0.25, rate.DoubleVal())
+				default:
+					roots++
+					require.True(t, found, traceID)
+					assert.Equal(t, 0.5, rate.DoubleVal())
+				}
+			}
+		}
+	}
+	assert.Equal(t, 12, roots)
+}
+
+func TestSampleByRate(t *testing.T) {
+	assert.True(t, sampleByRate(42, 1))
+	assert.False(t, sampleByRate(42, 0))
+
+	kept := 0
+	for id := uint64(1); id <= 10000; id++ {
+		if sampleByRate(id, 0.25) {
+			kept++
+		}
+	}
+	assert.InDelta(t, 2500, kept, 250)
+}
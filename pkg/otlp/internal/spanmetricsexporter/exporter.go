@@ -0,0 +1,243 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+package spanmetricsexporter
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/otlp/model/attributes"
+	"github.com/DataDog/datadog-agent/pkg/quantile"
+	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"github.com/DataDog/datadog-agent/pkg/util"
+)
+
+const (
+	hitsMetricName     = "otlp.span.hits"
+	errorsMetricName   = "otlp.span.errors"
+	durationMetricName = "otlp.span.duration"
+
+	// flushInterval matches the aggregator flush interval: stats are
+	// accumulated over the interval so that each context has a single point
+	// per interval, whatever the number of batches received.
+	flushInterval = 15 * time.Second
+)
+
+var _ config.Exporter = (*exporterConfig)(nil)
+
+// exporterConfig is the exporter configuration.
+type exporterConfig struct {
+	config.ExporterSettings `mapstructure:",squash"`
+}
+
+func newDefaultConfig() config.Exporter {
+	return &exporterConfig{}
+}
+
+// spanStats holds the RED metrics of the spans sharing the same host and tags.
+type spanStats struct {
+	host      string
+	tags      []string
+	hits      float64
+	errors    float64
+	durations quantile.Agent
+}
+
+// exporter computes request, error and duration metrics from OTLP spans
+// and sends them to the agent serializer every flushInterval. Traces are not
+// modified so that metrics are accurate even when traces are sampled afterwards.
+type exporter struct {
+	logger           *zap.Logger
+	s                serializer.MetricSerializer
+	fallbackHostname func(context.Context) (string, error)
+
+	mu    sync.Mutex
+	stats map[string]*spanStats
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newExporter(logger *zap.Logger, s serializer.MetricSerializer) *exporter {
+	return &exporter{
+		logger:           logger,
+		s:                s,
+		fallbackHostname: util.GetHostname,
+		stats:            make(map[string]*spanStats),
+		stop:             make(chan struct{}),
+	}
+}
+
+// start flushes the accumulated stats every flushInterval.
+func (e *exporter) start(_ context.Context, _ component.Host) error {
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-e.stop:
+				return
+			case t := <-ticker.C:
+				if err := e.flush(t.Unix()); err != nil {
+					e.logger.Warn("Failed to flush span metrics", zap.Error(err))
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// shutdown stops the flush loop and flushes the remaining stats.
+func (e *exporter) shutdown(_ context.Context) error {
+	close(e.stop)
+	e.wg.Wait()
+	return e.flush(time.Now().Unix())
+}
+
+// isEntrySpan returns whether the span is the entry point of a service:
+// only those spans are used to compute metrics to avoid counting a request several times.
+func isEntrySpan(span pdata.Span) bool {
+	switch span.Kind() {
+	case pdata.SpanKindServer, pdata.SpanKindConsumer:
+		return true
+	}
+	return span.ParentSpanID().IsEmpty()
+}
+
+func spanKindName(kind pdata.SpanKind) string {
+	switch kind {
+	case pdata.SpanKindServer:
+		return "server"
+	case pdata.SpanKindClient:
+		return "client"
+	case pdata.SpanKindProducer:
+		return "producer"
+	case pdata.SpanKindConsumer:
+		return "consumer"
+	case pdata.SpanKindInternal:
+		return "internal"
+	}
+	return "unspecified"
+}
+
+// ConsumeTraces adds the entry spans of `td` to the stats of the ongoing interval.
+func (e *exporter) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats := e.stats
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttrs := rs.Resource().Attributes()
+		resourceTags := attributes.TagsFromAttributes(resourceAttrs)
+		host, ok := attributes.HostnameFromAttributes(resourceAttrs)
+		if !ok {
+			var err error
+			if host, err = e.fallbackHostname(ctx); err != nil {
+				e.logger.Debug("Failed to get fallback hostname", zap.Error(err))
+			}
+		}
+
+		ilss := rs.InstrumentationLibrarySpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				if !isEntrySpan(span) {
+					continue
+				}
+
+				tags := make([]string, 0, len(resourceTags)+2)
+				tags = append(tags, resourceTags...)
+				tags = append(tags, "span_name:"+span.Name(), "span_kind:"+spanKindName(span.Kind()))
+				sort.Strings(tags)
+
+				key := host + "|" + strings.Join(tags, ",")
+				st, found := stats[key]
+				if !found {
+					st = &spanStats{host: host, tags: tags}
+					stats[key] = st
+				}
+
+				st.hits++
+				if span.Status().Code() == pdata.StatusCodeError {
+					st.errors++
+				}
+				if end, start := span.EndTimestamp(), span.StartTimestamp(); end >= start {
+					st.durations.Insert(float64(end-start)/float64(time.Second), 1)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// flush sends the stats accumulated since the last flush.
+func (e *exporter) flush(ts int64) error {
+	e.mu.Lock()
+	stats := e.stats
+	e.stats = make(map[string]*spanStats)
+	e.mu.Unlock()
+
+	if len(stats) == 0 {
+		return nil
+	}
+
+	var series metrics.Series
+	var sketches metrics.SketchSeriesList
+
+	for _, st := range stats {
+		series = append(series,
+			newCountSerie(hitsMetricName, st, st.hits, ts),
+			newCountSerie(errorsMetricName, st, st.errors, ts),
+		)
+		if sketch := st.durations.Finish(); sketch != nil {
+			sketches = append(sketches, metrics.SketchSeries{
+				Name:     durationMetricName,
+				Tags:     st.tags,
+				Host:     st.host,
+				Interval: int64(flushInterval.Seconds()),
+				Points: []metrics.SketchPoint{{
+					Ts:     ts,
+					Sketch: sketch,
+				}},
+			})
+		}
+	}
+
+	if err := e.s.SendSketch(sketches); err != nil {
+		return fmt.Errorf("failed to flush span metrics sketches: %w", err)
+	}
+	if err := e.s.SendSeries(series); err != nil {
+		return fmt.Errorf("failed to flush span metrics series: %w", err)
+	}
+	return nil
+}
+
+func newCountSerie(name string, st *spanStats, value float64, ts int64) *metrics.Serie {
+	return &metrics.Serie{
+		Name:     name,
+		Points:   []metrics.Point{{Ts: float64(ts), Value: value}},
+		Tags:     st.tags,
+		Host:     st.host,
+		MType:    metrics.APICountType,
+		Interval: int64(flushInterval.Seconds()),
+	}
+}
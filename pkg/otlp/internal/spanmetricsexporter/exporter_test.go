@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+//go:build test
+// +build test
+
+package spanmetricsexporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/pdata"
+	"go.uber.org/zap"
+
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+	"github.com/DataDog/datadog-agent/pkg/serializer"
+)
+
+func newTestTraces() pdata.Traces {
+	td := pdata.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().InsertString("service.name", "checkout")
+	rs.Resource().Attributes().InsertString("host.name", "my-host")
+
+	spans := rs.InstrumentationLibrarySpans().AppendEmpty().Spans()
+	start := pdata.NewTimestampFromTime(time.Now())
+	for i, code := range []pdata.StatusCode{pdata.StatusCodeOk, pdata.StatusCodeError, pdata.StatusCodeUnset} {
+		span := spans.AppendEmpty()
+		span.SetName("GET /cart")
+		span.SetKind(pdata.SpanKindServer)
+		span.SetParentSpanID(pdata.NewSpanID([8]byte{1}))
+		span.SetStartTimestamp(start)
+		span.SetEndTimestamp(start + pdata.Timestamp((i+1)*int(time.Millisecond)))
+		span.Status().SetCode(code)
+	}
+
+	// Client spans with a parent are not entry spans.
+	span := spans.AppendEmpty()
+	span.SetName("SELECT")
+	span.SetKind(pdata.SpanKindClient)
+	span.SetParentSpanID(pdata.NewSpanID([8]byte{2}))
+	return td
+}
+
+func TestConsumeTraces(t *testing.T) {
+	s := &serializer.MockSerializer{}
+	var series metrics.Series
+	var sketches metrics.SketchSeriesList
+	s.On("SendSeries", mock.Anything).Run(func(args mock.Arguments) {
+		series = args.Get(0).(metrics.Series)
+	}).Return(nil)
+	s.On("SendSketch", mock.Anything).Run(func(args mock.Arguments) {
+		sketches = args.Get(0).(metrics.SketchSeriesList)
+	}).Return(nil)
+
+	exp := newExporter(zap.NewNop(), s)
+	// batches received during the same interval are accumulated
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces()))
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces()))
+	s.AssertNotCalled(t, "SendSeries", mock.Anything)
+	require.NoError(t, exp.flush(time.Now().Unix()))
+
+	expectedTags := []string{"service:checkout", "span_kind:server", "span_name:GET /cart"}
+	require.Len(t, series, 2)
+	values := map[string]float64{}
+	for _, serie := range series {
+		assert.Equal(t, "my-host", serie.Host)
+		assert.Equal(t, expectedTags, serie.Tags)
+		assert.Equal(t, metrics.APICountType, serie.MType)
+		assert.Equal(t, int64(15), serie.Interval)
+		require.Len(t, serie.Points, 1)
+		values[serie.Name] = serie.Points[0].Value
+	}
+	assert.Equal(t, map[string]float64{hitsMetricName: 6, errorsMetricName: 2}, values)
+
+	require.Len(t, sketches, 1)
+	assert.Equal(t, durationMetricName, sketches[0].Name)
+	assert.Equal(t, expectedTags, sketches[0].Tags)
+	assert.Equal(t, int64(6), sketches[0].Points[0].Sketch.Basic.Cnt)
+
+	// stats are reset after a flush
+	series, sketches = nil, nil
+	require.NoError(t, exp.flush(time.Now().Unix()))
+	assert.Nil(t, series)
+	assert.Nil(t, sketches)
+}
+
+func TestShutdownFlushes(t *testing.T) {
+	s := &serializer.MockSerializer{}
+	s.On("SendSeries", mock.Anything).Return(nil)
+	s.On("SendSketch", mock.Anything).Return(nil)
+
+	exp := newExporter(zap.NewNop(), s)
+	require.NoError(t, exp.start(context.Background(), nil))
+	require.NoError(t, exp.ConsumeTraces(context.Background(), newTestTraces()))
+	require.NoError(t, exp.shutdown(context.Background()))
+	s.AssertNumberOfCalls(t, "SendSeries", 1)
+}
+
+func TestConsumeTracesNoEntrySpan(t *testing.T) {
+	s := &serializer.MockSerializer{}
+	exp := newExporter(zap.NewNop(), s)
+	require.NoError(t, exp.ConsumeTraces(context.Background(), pdata.NewTraces()))
+	require.NoError(t, exp.flush(time.Now().Unix()))
+	s.AssertNotCalled(t, "SendSeries", mock.Anything)
+}
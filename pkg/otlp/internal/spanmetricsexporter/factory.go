@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+package spanmetricsexporter
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/pkg/serializer"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+const (
+	// TypeStr defines the span metrics exporter type string.
+	TypeStr = "spanmetrics"
+)
+
+type factory struct {
+	s serializer.MetricSerializer
+}
+
+// NewFactory creates a new span metrics exporter factory.
+func NewFactory(s serializer.MetricSerializer) component.ExporterFactory {
+	f := &factory{s}
+
+	return exporterhelper.NewFactory(
+		TypeStr,
+		newDefaultConfig,
+		exporterhelper.WithTraces(f.createTracesExporter),
+	)
+}
+
+func (f *factory) createTracesExporter(_ context.Context, params component.ExporterCreateSettings, cfg config.Exporter) (component.TracesExporter, error) {
+	exp := newExporter(params.Logger, f.s)
+
+	return exporterhelper.NewTracesExporter(cfg, params, exp.ConsumeTraces,
+		// Disable timeout; we don't really do HTTP requests on the ConsumeTraces call.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutSettings{Timeout: 0}),
+		exporterhelper.WithStart(exp.start),
+		exporterhelper.WithShutdown(exp.shutdown),
+	)
+}
@@ -12,6 +12,8 @@ import (
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/service/parserprovider"
+
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/samplingprocessor"
 )
 
 // buildKey creates a key for use in the config.Map.Set function.
@@ -60,6 +62,52 @@ func newTracesMapProvider(tracePort uint) config.MapProvider {
 	)
 }
 
+// spanMetricsConfig adds the span metrics exporter to the traces pipeline.
+// Spans are still forwarded untouched to the trace Agent.
+const spanMetricsConfig string = `
+exporters:
+  spanmetrics:
+
+service:
+  pipelines:
+    traces:
+      exporters: [otlp, spanmetrics]
+`
+
+// sampledSpanMetricsConfig computes the span metrics in their own pipeline
+// when traces are sampled, so that the metrics are computed from all the spans.
+const sampledSpanMetricsConfig string = `
+exporters:
+  spanmetrics:
+
+service:
+  pipelines:
+    traces/spanmetrics:
+      receivers: [otlp]
+      exporters: [spanmetrics]
+`
+
+// samplingProcessorConfig samples the traces before they are sent to the trace Agent.
+const samplingProcessorConfig string = `
+processors:
+  sampling:
+
+service:
+  pipelines:
+    traces:
+      processors: [sampling]
+`
+
+func newSamplingMapProvider(samplingConfig map[string]interface{}) config.MapProvider {
+	configMap := config.NewMapFromStringMap(map[string]interface{}{
+		"processors": map[string]interface{}{samplingprocessor.TypeStr: samplingConfig},
+	})
+	return parserprovider.NewMergeMapProvider(
+		parserprovider.NewInMemoryMapProvider(strings.NewReader(samplingProcessorConfig)),
+		mapProvider(*configMap),
+	)
+}
+
 // defaultMetricsConfig is the metrics OTLP pipeline configuration.
 // TODO (AP-1254): Set service-level configuration when available.
 const defaultMetricsConfig string = `
@@ -84,6 +132,16 @@ func newMetricsMapProvider() config.MapProvider {
 	return parserprovider.NewInMemoryMapProvider(strings.NewReader(defaultMetricsConfig))
 }
 
+// ipcExtensionConfig enables the extension exposing the readiness and the
+// zPages of the pipelines to the Agent IPC server.
+const ipcExtensionConfig string = `
+extensions:
+  agent_ipc:
+
+service:
+  extensions: [agent_ipc]
+`
+
 func newReceiverProvider(otlpReceiverConfig map[string]interface{}) config.MapProvider {
 	configMap := config.NewMapFromStringMap(map[string]interface{}{
 		"receivers": map[string]interface{}{"otlp": otlpReceiverConfig},
@@ -96,10 +154,21 @@ func newMapProvider(cfg PipelineConfig) config.MapProvider {
 	var providers []config.MapProvider
 	if cfg.TracesEnabled {
 		providers = append(providers, newTracesMapProvider(cfg.TracePort))
+		if cfg.SamplingConfig != nil {
+			providers = append(providers, newSamplingMapProvider(cfg.SamplingConfig))
+		}
+		if cfg.SpanMetricsEnabled {
+			spanMetrics := spanMetricsConfig
+			if cfg.SamplingConfig != nil {
+				spanMetrics = sampledSpanMetricsConfig
+			}
+			providers = append(providers, parserprovider.NewInMemoryMapProvider(strings.NewReader(spanMetrics)))
+		}
 	}
 	if cfg.MetricsEnabled {
 		providers = append(providers, newMetricsMapProvider())
 	}
+	providers = append(providers, parserprovider.NewInMemoryMapProvider(strings.NewReader(ipcExtensionConfig)))
 	providers = append(providers, newReceiverProvider(cfg.OTLPReceiverConfig))
 	return parserprovider.NewMergeMapProvider(providers...)
 }
@@ -12,12 +12,14 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/config/configunmarshaler"
 
+	"github.com/DataDog/datadog-agent/pkg/otlp/internal/ipcextension"
 	"github.com/DataDog/datadog-agent/pkg/otlp/internal/testutil"
 	"github.com/DataDog/datadog-agent/pkg/serializer"
 )
@@ -47,7 +49,10 @@ exporters:
     tls:
       insecure: true
     endpoint: localhost:5003
+extensions:
+  agent_ipc:
 service:
+  extensions: [agent_ipc]
   pipelines:
     traces:
       receivers: [otlp]
@@ -79,7 +84,10 @@ exporters:
     endpoint: localhost:5003
   serializer:
 
+extensions:
+  agent_ipc:
 service:
+  extensions: [agent_ipc]
   pipelines:
     traces:
       receivers: [otlp]
@@ -110,11 +118,86 @@ exporters:
     tls:
       insecure: true
     endpoint: localhost:5003
+extensions:
+  agent_ipc:
 service:
+  extensions: [agent_ipc]
   pipelines:
     traces:
       receivers: [otlp]
       exporters: [otlp]
+`,
+		},
+		{
+			name: "only gRPC, traces with span metrics",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("bindhost", 1234, 0),
+				TracePort:          5003,
+				TracesEnabled:      true,
+				SpanMetricsEnabled: true,
+			},
+			ocfg: `
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: bindhost:1234
+exporters:
+  otlp:
+    tls:
+      insecure: true
+    endpoint: localhost:5003
+  spanmetrics:
+extensions:
+  agent_ipc:
+service:
+  extensions: [agent_ipc]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      exporters: [otlp, spanmetrics]
+`,
+		},
+		{
+			name: "only gRPC, sampled traces with span metrics",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("bindhost", 1234, 0),
+				TracePort:          5003,
+				TracesEnabled:      true,
+				SpanMetricsEnabled: true,
+				SamplingConfig: map[string]interface{}{
+					"sampling_rate": 0.5,
+					"keep_errors":   true,
+				},
+			},
+			ocfg: `
+receivers:
+  otlp:
+    protocols:
+      grpc:
+        endpoint: bindhost:1234
+processors:
+  sampling:
+    sampling_rate: 0.5
+    keep_errors: true
+exporters:
+  otlp:
+    tls:
+      insecure: true
+    endpoint: localhost:5003
+  spanmetrics:
+extensions:
+  agent_ipc:
+service:
+  extensions: [agent_ipc]
+  pipelines:
+    traces:
+      receivers: [otlp]
+      processors: [sampling]
+      exporters: [otlp]
+    traces/spanmetrics:
+      receivers: [otlp]
+      exporters: [spanmetrics]
 `,
 		},
 		{
@@ -137,7 +220,10 @@ processors:
 exporters:
   serializer:
 
+extensions:
+  agent_ipc:
 service:
+  extensions: [agent_ipc]
   pipelines:
     metrics:
       receivers: [otlp]
@@ -160,19 +246,62 @@ service:
 }
 
 func TestUnmarshal(t *testing.T) {
-	mapProvider := newMapProvider(PipelineConfig{
-		OTLPReceiverConfig: testutil.OTLPConfigFromPorts("localhost", 4317, 4318),
-		TracePort:          5001,
-		MetricsEnabled:     true,
-		TracesEnabled:      true,
-	})
-	configMap, err := mapProvider.Get(context.Background())
-	require.NoError(t, err)
-
-	components, err := getComponents(&serializer.MockSerializer{})
-	require.NoError(t, err)
-
-	cu := configunmarshaler.NewDefault()
-	_, err = cu.Unmarshal(configMap, components)
-	require.NoError(t, err)
+	tests := []struct {
+		name string
+		pcfg PipelineConfig
+	}{
+		{
+			name: "default",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("localhost", 4317, 4318),
+				TracePort:          5001,
+				MetricsEnabled:     true,
+				TracesEnabled:      true,
+			},
+		},
+		{
+			name: "with span metrics",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("localhost", 4317, 4318),
+				TracePort:          5001,
+				MetricsEnabled:     true,
+				TracesEnabled:      true,
+				SpanMetricsEnabled: true,
+			},
+		},
+		{
+			name: "with sampling",
+			pcfg: PipelineConfig{
+				OTLPReceiverConfig: testutil.OTLPConfigFromPorts("localhost", 4317, 4318),
+				TracePort:          5001,
+				MetricsEnabled:     true,
+				TracesEnabled:      true,
+				SpanMetricsEnabled: true,
+				SamplingConfig: map[string]interface{}{
+					"sampling_rate": 0.25,
+					"rules": []interface{}{
+						map[string]interface{}{"service": "checkout", "resource": "", "sampling_rate": 1.0},
+					},
+					"keep_errors":       true,
+					"latency_threshold": 500 * time.Millisecond,
+					"decision_wait":     5 * time.Second,
+				},
+			},
+		},
+	}
+
+	for _, testInstance := range tests {
+		t.Run(testInstance.name, func(t *testing.T) {
+			mapProvider := newMapProvider(testInstance.pcfg)
+			configMap, err := mapProvider.Get(context.Background())
+			require.NoError(t, err)
+
+			components, err := getComponents(&serializer.MockSerializer{}, &ipcextension.Handler{})
+			require.NoError(t, err)
+
+			cu := configunmarshaler.NewDefault()
+			_, err = cu.Unmarshal(configMap, components)
+			require.NoError(t, err)
+		})
+	}
 }
@@ -0,0 +1,116 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+package otlp
+
+import (
+	"expvar"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opencensus.io/stats/view"
+
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+var (
+	otlpExpvars      = expvar.NewMap("otlp")
+	componentMetrics = expvar.Map{}
+
+	registerTelemetryOnce sync.Once
+)
+
+func init() {
+	otlpExpvars.Set("ComponentMetrics", &componentMetrics)
+}
+
+var _ view.Exporter = (*telemetryExporter)(nil)
+
+// telemetryExporter translates the metrics of the collector components
+// (accepted and refused points and spans of the receivers, sent and failed
+// ones of the exporters...), recorded with OpenCensus, into Agent telemetry.
+type telemetryExporter struct {
+	mu     sync.Mutex
+	gauges map[string]telemetry.Gauge
+}
+
+// registerTelemetryExporter registers the telemetry exporter, once as the OpenCensus views are global.
+func registerTelemetryExporter() {
+	registerTelemetryOnce.Do(func() {
+		view.RegisterExporter(&telemetryExporter{gauges: make(map[string]telemetry.Gauge)})
+	})
+}
+
+// ExportView implements view.Exporter.
+func (e *telemetryExporter) ExportView(vd *view.Data) {
+	if vd.View.Aggregation != nil && vd.View.Aggregation.Type == view.AggTypeDistribution {
+		// distributions can't be translated to a single value
+		return
+	}
+
+	tagKeys := make([]string, 0, len(vd.View.TagKeys))
+	for _, key := range vd.View.TagKeys {
+		tagKeys = append(tagKeys, key.Name())
+	}
+	gauge := e.getGauge(vd.View, tagKeys)
+
+	for _, row := range vd.Rows {
+		var value float64
+		switch data := row.Data.(type) {
+		case *view.SumData:
+			value = data.Value
+		case *view.CountData:
+			value = float64(data.Value)
+		case *view.LastValueData:
+			value = data.Value
+		default:
+			continue
+		}
+
+		rowTags := make(map[string]string, len(row.Tags))
+		for _, tag := range row.Tags {
+			rowTags[tag.Key.Name()] = tag.Value
+		}
+		tagValues := make([]string, 0, len(tagKeys))
+		for _, key := range tagKeys {
+			tagValues = append(tagValues, rowTags[key])
+		}
+		gauge.Set(value, tagValues...)
+
+		v := new(expvar.Float)
+		v.Set(value)
+		componentMetrics.Set(statusMetricName(vd.View.Name, rowTags), v)
+	}
+}
+
+// getGauge returns the telemetry gauge of v, created at the first export of the view.
+func (e *telemetryExporter) getGauge(v *view.View, tagKeys []string) telemetry.Gauge {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if gauge, ok := e.gauges[v.Name]; ok {
+		return gauge
+	}
+	name := strings.NewReplacer("/", "_", ".", "_").Replace(v.Name)
+	gauge := telemetry.NewGauge("otlp", name, tagKeys, v.Description)
+	e.gauges[v.Name] = gauge
+	return gauge
+}
+
+// statusMetricName returns the name of a view row in the `agent status` output,
+// such as `receiver/accepted_spans{receiver=otlp,transport=grpc}`.
+func statusMetricName(viewName string, tags map[string]string) string {
+	if len(tags) == 0 {
+		return viewName
+	}
+	pairs := make([]string, 0, len(tags))
+	for key, value := range tags {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(pairs)
+	return fmt.Sprintf("%s{%s}", viewName, strings.Join(pairs, ","))
+}
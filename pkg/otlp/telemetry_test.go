@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2021-present Datadog, Inc.
+
+//go:build test
+// +build test
+
+package otlp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+func TestTelemetryExporter(t *testing.T) {
+	receiverKey := tag.MustNewKey("receiver")
+	transportKey := tag.MustNewKey("transport")
+	acceptedSpans := &view.View{
+		Name:        "receiver/test_accepted_spans",
+		Description: "Number of spans successfully pushed into the pipeline.",
+		TagKeys:     []tag.Key{receiverKey, transportKey},
+		Aggregation: view.Sum(),
+	}
+	batchSize := &view.View{
+		Name:        "processor/batch/test_batch_send_size",
+		Aggregation: view.Distribution(10, 100),
+	}
+
+	e := &telemetryExporter{gauges: make(map[string]telemetry.Gauge)}
+	e.ExportView(&view.Data{
+		View: acceptedSpans,
+		Rows: []*view.Row{
+			{Tags: []tag.Tag{{Key: transportKey, Value: "grpc"}, {Key: receiverKey, Value: "otlp"}}, Data: &view.SumData{Value: 42}},
+			{Tags: []tag.Tag{{Key: receiverKey, Value: "otlp"}, {Key: transportKey, Value: "http"}}, Data: &view.SumData{Value: 3}},
+		},
+	})
+	e.ExportView(&view.Data{
+		View: batchSize,
+		Rows: []*view.Row{{Data: &view.DistributionData{Count: 1}}},
+	})
+	// the gauge is reused by later exports
+	e.ExportView(&view.Data{
+		View: acceptedSpans,
+		Rows: []*view.Row{{Tags: []tag.Tag{{Key: receiverKey, Value: "otlp"}, {Key: transportKey, Value: "grpc"}}, Data: &view.SumData{Value: 50}}},
+	})
+
+	assert.Len(t, e.gauges, 1)
+	grpc := componentMetrics.Get("receiver/test_accepted_spans{receiver=otlp,transport=grpc}")
+	require.NotNil(t, grpc)
+	assert.Equal(t, "50", grpc.String())
+	http := componentMetrics.Get("receiver/test_accepted_spans{receiver=otlp,transport=http}")
+	require.NotNil(t, http)
+	assert.Equal(t, "3", http.String())
+	assert.Nil(t, componentMetrics.Get("processor/batch/test_batch_send_size"))
+}
@@ -0,0 +1,313 @@
+package checks
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"debug/elf"
+	"debug/pe"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// maxSectionSize is the size above which the sections of a binary are not read
+	maxSectionSize = 16 * 1024 * 1024
+	// maxBuildInfoStringLen is the maximum length of the strings read from the Go build info
+	maxBuildInfoStringLen = 64 * 1024
+	// fixedFileInfoSignature starts the VS_FIXEDFILEINFO structure of the PE version resources
+	fixedFileInfoSignature = 0xfeef04bd
+)
+
+// Binary formats
+const (
+	binaryFormatELF     = "elf"
+	binaryFormatPE      = "pe"
+	binaryFormatUnknown = "unknown"
+)
+
+// Keys of the version strings extracted from the binaries
+const (
+	versionBuildID  = "build_id"
+	versionSoname   = "soname"
+	versionCompiler = "compiler"
+	versionGo       = "go_version"
+	versionModule   = "module_version"
+	versionFile     = "file_version"
+	versionProduct  = "product_version"
+)
+
+var goBuildInfoMagic = []byte("\xff Go buildinf:")
+
+// BinaryFingerprint identifies a binary by its path and the version strings found in its metadata.
+// The same binary installed on several hosts has the same hash, so it can be deduplicated fleet-wide.
+type BinaryFingerprint struct {
+	Hash     string            `json:"hash"`
+	Path     string            `json:"path"`
+	Format   string            `json:"format"`
+	Versions map[string]string `json:"versions,omitempty"`
+}
+
+// fingerprintBinary computes the fingerprint of the binary at path, read from r.
+// Binaries that are neither ELF nor PE files are fingerprinted with their path only.
+func fingerprintBinary(path string, r io.ReaderAt) *BinaryFingerprint {
+	format, versions := binaryFormatUnknown, map[string]string(nil)
+	if f, err := elf.NewFile(r); err == nil {
+		format, versions = binaryFormatELF, elfVersions(f)
+	} else if f, err := pe.NewFile(r); err == nil {
+		format, versions = binaryFormatPE, peVersions(f)
+	}
+	return &BinaryFingerprint{
+		Hash:     fingerprintHash(path, versions),
+		Path:     path,
+		Format:   format,
+		Versions: versions,
+	}
+}
+
+// fingerprintHash returns a stable hash of the path and the version strings
+func fingerprintHash(path string, versions map[string]string) string {
+	keys := make([]string, 0, len(versions))
+	for k := range versions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	h.Write([]byte(path)) //nolint:errcheck
+	for _, k := range keys {
+		h.Write([]byte{0})                     //nolint:errcheck
+		h.Write([]byte(k + "=" + versions[k])) //nolint:errcheck
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// elfVersions extracts the GNU build ID, the shared object name, the compilers
+// and the Go build info of an ELF file.
+func elfVersions(f *elf.File) map[string]string {
+	versions := make(map[string]string)
+
+	if s := f.Section(".note.gnu.build-id"); s != nil {
+		if id := parseELFBuildID(readSection(s, s.Size), f.ByteOrder); id != "" {
+			versions[versionBuildID] = id
+		}
+	}
+
+	if sonames, err := f.DynString(elf.DT_SONAME); err == nil && len(sonames) > 0 {
+		versions[versionSoname] = sonames[0]
+	}
+
+	if s := f.Section(".comment"); s != nil {
+		var compilers []string
+		for _, c := range bytes.Split(readSection(s, s.Size), []byte{0}) {
+			if c := strings.TrimSpace(string(c)); c != "" && !containsString(compilers, c) {
+				compilers = append(compilers, c)
+			}
+		}
+		if len(compilers) > 0 {
+			versions[versionCompiler] = strings.Join(compilers, "; ")
+		}
+	}
+
+	if s := f.Section(".go.buildinfo"); s != nil {
+		readAt := func(addr, size uint64) []byte {
+			for _, p := range f.Progs {
+				if p.Type == elf.PT_LOAD && p.Vaddr <= addr && addr+size <= p.Vaddr+p.Filesz {
+					buf := make([]byte, size)
+					if _, err := p.ReadAt(buf, int64(addr-p.Vaddr)); err == nil {
+						return buf
+					}
+				}
+			}
+			return nil
+		}
+		addGoVersions(versions, readSection(s, s.Size), readAt)
+	}
+
+	return versions
+}
+
+// parseELFBuildID parses the note of the `.note.gnu.build-id` section
+func parseELFBuildID(note []byte, order binary.ByteOrder) string {
+	if len(note) < 12 {
+		return ""
+	}
+	nameSize, descSize := order.Uint32(note[0:4]), order.Uint32(note[4:8])
+	// the name is padded to 4 bytes
+	start := 12 + uint64((nameSize+3)&^3)
+	end := start + uint64(descSize)
+	if descSize == 0 || end > uint64(len(note)) {
+		return ""
+	}
+	return hex.EncodeToString(note[start:end])
+}
+
+// peVersions extracts the file and product versions of the version resource
+// and the Go build info of a PE file.
+func peVersions(f *pe.File) map[string]string {
+	versions := make(map[string]string)
+
+	if s := f.Section(".rsrc"); s != nil {
+		if fileVersion, productVersion, ok := parseFixedFileInfo(readSection(s, uint64(s.Size))); ok {
+			versions[versionFile] = fileVersion
+			versions[versionProduct] = productVersion
+		}
+	}
+
+	if s := f.Section(".data"); s != nil {
+		var imageBase uint64
+		switch h := f.OptionalHeader.(type) {
+		case *pe.OptionalHeader32:
+			imageBase = uint64(h.ImageBase)
+		case *pe.OptionalHeader64:
+			imageBase = h.ImageBase
+		}
+		readAt := func(addr, size uint64) []byte {
+			for _, s := range f.Sections {
+				start := imageBase + uint64(s.VirtualAddress)
+				if start <= addr && addr+size <= start+uint64(s.Size) {
+					buf := make([]byte, size)
+					if _, err := s.ReadAt(buf, int64(addr-start)); err == nil {
+						return buf
+					}
+				}
+			}
+			return nil
+		}
+		// the Go build info is at the start of the data section, aligned on 16 bytes
+		data := readSection(s, uint64(s.Size))
+		for i := 0; i+len(goBuildInfoMagic) <= len(data); i += 16 {
+			if bytes.HasPrefix(data[i:], goBuildInfoMagic) {
+				addGoVersions(versions, data[i:], readAt)
+				break
+			}
+		}
+	}
+
+	return versions
+}
+
+// parseFixedFileInfo finds the VS_FIXEDFILEINFO structure of the version resource
+// and returns the file and product versions
+func parseFixedFileInfo(rsrc []byte) (string, string, bool) {
+	signature := make([]byte, 4)
+	binary.LittleEndian.PutUint32(signature, fixedFileInfoSignature)
+
+	for offset := 0; ; {
+		i := bytes.Index(rsrc[offset:], signature)
+		if i < 0 {
+			return "", "", false
+		}
+		i += offset
+		// the structure is aligned on 4 bytes and contains 13 DWORDs
+		if i%4 == 0 && i+52 <= len(rsrc) {
+			dwords := func(n int) uint32 { return binary.LittleEndian.Uint32(rsrc[i+4*n:]) }
+			return formatFixedVersion(dwords(2), dwords(3)), formatFixedVersion(dwords(4), dwords(5)), true
+		}
+		offset = i + 1
+	}
+}
+
+func formatFixedVersion(ms, ls uint32) string {
+	return strconv.Itoa(int(ms>>16)) + "." + strconv.Itoa(int(ms&0xffff)) + "." +
+		strconv.Itoa(int(ls>>16)) + "." + strconv.Itoa(int(ls&0xffff))
+}
+
+// addGoVersions adds the Go version and the version of the main module found
+// in the Go build info, readAt reads the memory of the binary at a virtual address
+func addGoVersions(versions map[string]string, data []byte, readAt func(addr, size uint64) []byte) {
+	goVersion, mod := parseGoBuildInfo(data, readAt)
+	if goVersion != "" {
+		versions[versionGo] = goVersion
+	}
+	if v := mainModuleVersion(mod); v != "" {
+		versions[versionModule] = v
+	}
+}
+
+// parseGoBuildInfo returns the Go version and the module information of the Go build info blob,
+// which starts with a 16 bytes header containing the magic, the pointer size and flags.
+func parseGoBuildInfo(data []byte, readAt func(addr, size uint64) []byte) (string, string) {
+	if len(data) < 32 || !bytes.HasPrefix(data, goBuildInfoMagic) {
+		return "", ""
+	}
+	ptrSize, flags := int(data[14]), data[15]
+
+	// since go 1.18, the strings are inlined after the header
+	if flags&2 != 0 {
+		goVersion, rest := readVarintString(data[32:])
+		mod, _ := readVarintString(rest)
+		return goVersion, mod
+	}
+
+	// before, the header is followed by pointers to the strings
+	if ptrSize != 4 && ptrSize != 8 {
+		return "", ""
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if flags&1 != 0 {
+		order = binary.BigEndian
+	}
+	readPtr := func(b []byte) uint64 {
+		if ptrSize == 4 {
+			return uint64(order.Uint32(b))
+		}
+		return order.Uint64(b)
+	}
+	readString := func(addr uint64) string {
+		header := readAt(addr, uint64(2*ptrSize))
+		if len(header) < 2*ptrSize {
+			return ""
+		}
+		length := readPtr(header[ptrSize:])
+		if length == 0 || length > maxBuildInfoStringLen {
+			return ""
+		}
+		return string(readAt(readPtr(header), length))
+	}
+	return readString(readPtr(data[16:])), readString(readPtr(data[16+ptrSize:]))
+}
+
+func readVarintString(data []byte) (string, []byte) {
+	length, n := binary.Uvarint(data)
+	if n <= 0 || length > uint64(len(data)-n) {
+		return "", nil
+	}
+	return string(data[n : n+int(length)]), data[n+int(length):]
+}
+
+// mainModuleVersion returns the version of the main module from the module information,
+// which contains a line `mod <path> <version> <sum>` separated by tabs
+func mainModuleVersion(mod string) string {
+	for _, line := range strings.Split(mod, "\n") {
+		fields := strings.Split(line, "\t")
+		if len(fields) >= 3 && fields[0] == "mod" {
+			return fields[2]
+		}
+	}
+	return ""
+}
+
+// readSection returns the content of a section, or nil if it is too big or can't be read
+func readSection(r io.ReaderAt, size uint64) []byte {
+	if size > maxSectionSize {
+		return nil
+	}
+	data := make([]byte, size)
+	if _, err := r.ReadAt(data, 0); err != nil {
+		return nil
+	}
+	return data
+}
+
+func containsString(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
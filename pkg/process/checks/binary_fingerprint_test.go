@@ -0,0 +1,142 @@
+package checks
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintHash(t *testing.T) {
+	versions := map[string]string{versionBuildID: "abcd", versionGo: "go1.16.7"}
+	hash := fingerprintHash("/usr/bin/app", versions)
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, fingerprintHash("/usr/bin/app", map[string]string{versionGo: "go1.16.7", versionBuildID: "abcd"}))
+	assert.NotEqual(t, hash, fingerprintHash("/usr/local/bin/app", versions))
+	assert.NotEqual(t, hash, fingerprintHash("/usr/bin/app", map[string]string{versionBuildID: "abce", versionGo: "go1.16.7"}))
+	assert.NotEqual(t, fingerprintHash("/usr/bin/app", nil), hash)
+}
+
+func TestFingerprintTestBinary(t *testing.T) {
+	exe, err := os.Executable()
+	require.NoError(t, err)
+	f, err := os.Open(exe)
+	require.NoError(t, err)
+	defer f.Close()
+
+	fingerprint := fingerprintBinary("/usr/bin/checks.test", f)
+	assert.Equal(t, "/usr/bin/checks.test", fingerprint.Path)
+	assert.Equal(t, fingerprintHash("/usr/bin/checks.test", fingerprint.Versions), fingerprint.Hash)
+	assert.Equal(t, fingerprint, fingerprintBinary("/usr/bin/checks.test", f))
+
+	switch runtime.GOOS {
+	case "linux":
+		assert.Equal(t, binaryFormatELF, fingerprint.Format)
+		assert.Equal(t, runtime.Version(), fingerprint.Versions[versionGo])
+	case "windows":
+		assert.Equal(t, binaryFormatPE, fingerprint.Format)
+		assert.Equal(t, runtime.Version(), fingerprint.Versions[versionGo])
+	}
+}
+
+func TestFingerprintUnknownFormat(t *testing.T) {
+	fingerprint := fingerprintBinary("/usr/bin/script", bytes.NewReader([]byte("#!/bin/sh\necho hello\n")))
+	assert.Equal(t, binaryFormatUnknown, fingerprint.Format)
+	assert.Empty(t, fingerprint.Versions)
+	assert.Equal(t, fingerprintHash("/usr/bin/script", nil), fingerprint.Hash)
+}
+
+func TestParseELFBuildID(t *testing.T) {
+	note := make([]byte, 12)
+	binary.LittleEndian.PutUint32(note[0:], 4)
+	binary.LittleEndian.PutUint32(note[4:], 4)
+	binary.LittleEndian.PutUint32(note[8:], 3)
+	note = append(note, []byte("GNU\x00")...)
+	note = append(note, 0xde, 0xad, 0xbe, 0xef)
+
+	assert.Equal(t, "deadbeef", parseELFBuildID(note, binary.LittleEndian))
+	assert.Equal(t, "", parseELFBuildID(note[:18], binary.LittleEndian))
+	assert.Equal(t, "", parseELFBuildID(nil, binary.LittleEndian))
+}
+
+func TestParseFixedFileInfo(t *testing.T) {
+	rsrc := []byte("VS_VERSION_INFO\x00\x00")
+	// the signature is ignored when it isn't aligned
+	rsrc = append(rsrc, 0xbd, 0x04, 0xef, 0xfe, 0)
+	for len(rsrc)%4 != 0 {
+		rsrc = append(rsrc, 0)
+	}
+	dwords := []uint32{fixedFileInfoSignature, 0x00010000, 0x00070002, 0x00030004, 0x00070000, 0x00010000, 0, 0, 0, 0, 0, 0, 0}
+	for _, d := range dwords {
+		rsrc = append(rsrc, 0, 0, 0, 0)
+		binary.LittleEndian.PutUint32(rsrc[len(rsrc)-4:], d)
+	}
+
+	fileVersion, productVersion, ok := parseFixedFileInfo(rsrc)
+	assert.True(t, ok)
+	assert.Equal(t, "7.2.3.4", fileVersion)
+	assert.Equal(t, "7.0.1.0", productVersion)
+
+	_, _, ok = parseFixedFileInfo(rsrc[:len(rsrc)-4])
+	assert.False(t, ok)
+}
+
+func TestParseGoBuildInfo(t *testing.T) {
+	mod := "path\tgithub.com/DataDog/app\nmod\tgithub.com/DataDog/app\tv1.2.3\th1:abc=\n"
+
+	t.Run("inlined strings", func(t *testing.T) {
+		data := append([]byte{}, goBuildInfoMagic...)
+		data = append(data, 8, 2)
+		data = append(data, make([]byte, 16)...)
+		data = appendVarintString(data, "go1.18.1")
+		data = appendVarintString(data, mod)
+
+		goVersion, modInfo := parseGoBuildInfo(data, nil)
+		assert.Equal(t, "go1.18.1", goVersion)
+		assert.Equal(t, "v1.2.3", mainModuleVersion(modInfo))
+	})
+
+	t.Run("pointers to strings", func(t *testing.T) {
+		// memory of the binary, starting at address 0x1000: two string headers followed by the strings
+		const base = 0x1000
+		memory := make([]byte, 32)
+		binary.LittleEndian.PutUint64(memory[0:], base+32)
+		binary.LittleEndian.PutUint64(memory[8:], uint64(len("go1.16.7")))
+		binary.LittleEndian.PutUint64(memory[16:], base+32+uint64(len("go1.16.7")))
+		binary.LittleEndian.PutUint64(memory[24:], uint64(len(mod)))
+		memory = append(memory, []byte("go1.16.7"+mod)...)
+		readAt := func(addr, size uint64) []byte {
+			if addr < base || addr+size > base+uint64(len(memory)) {
+				return nil
+			}
+			return memory[addr-base : addr-base+size]
+		}
+
+		data := append([]byte{}, goBuildInfoMagic...)
+		data = append(data, 8, 0)
+		data = append(data, make([]byte, 16)...)
+		binary.LittleEndian.PutUint64(data[16:], base)
+		binary.LittleEndian.PutUint64(data[24:], base+16)
+
+		goVersion, modInfo := parseGoBuildInfo(data, readAt)
+		assert.Equal(t, "go1.16.7", goVersion)
+		assert.Equal(t, "v1.2.3", mainModuleVersion(modInfo))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		goVersion, modInfo := parseGoBuildInfo([]byte("not a build info blob, not at all"), nil)
+		assert.Equal(t, "", goVersion)
+		assert.Equal(t, "", modInfo)
+	})
+}
+
+func appendVarintString(data []byte, s string) []byte {
+	buf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(buf, uint64(len(s)))
+	return append(append(data, buf[:n]...), s...)
+}
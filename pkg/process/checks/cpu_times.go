@@ -0,0 +1,10 @@
+// +build !aix
+
+package checks
+
+import "github.com/DataDog/gopsutil/cpu"
+
+// systemCPUTimes returns the CPU times of the whole host, used to compute the CPU usage percentage of the processes
+func systemCPUTimes() ([]cpu.TimesStat, error) {
+	return cpu.Times(false)
+}
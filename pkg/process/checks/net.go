@@ -85,6 +85,10 @@ func (c *ConnectionsCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.
 		if err == ebpf.ErrNotImplemented || err == ErrTracerStillNotInitialized {
 			return nil, nil
 		}
+		// system-probe is down, this was logged when the first request failed
+		if errors.Is(err, net.ErrSystemProbeUnavailable) {
+			return nil, nil
+		}
 		return nil, err
 	}
 
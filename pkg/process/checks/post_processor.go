@@ -0,0 +1,68 @@
+package checks
+
+import (
+	"sort"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// PayloadPostProcessor mutates the process payloads before they are submitted, for example to redact
+// arguments or to add tags coming from an external source. Post-processors are compiled in the
+// process-agent by packages registering them with RegisterPayloadPostProcessor in their init function.
+type PayloadPostProcessor interface {
+	// Name returns the name of the post-processor, used in logs
+	Name() string
+	// ProcessCollectorProc mutates a CollectorProc payload of the given check
+	ProcessCollectorProc(checkName string, payload *model.CollectorProc) error
+}
+
+// PayloadPostProcessorFactory instantiates a PayloadPostProcessor once the configuration is loaded
+type PayloadPostProcessorFactory func(cfg *config.AgentConfig) (PayloadPostProcessor, error)
+
+var postProcessorFactories = make(map[int][]PayloadPostProcessorFactory)
+
+// RegisterPayloadPostProcessor registers a post-processor, post-processors are run by increasing order
+// and in the order they were registered for the same order.
+func RegisterPayloadPostProcessor(order int, factory PayloadPostProcessorFactory) {
+	postProcessorFactories[order] = append(postProcessorFactories[order], factory)
+}
+
+// NewPayloadPostProcessors instantiates the registered post-processors, the ones failing to
+// be instantiated are skipped
+func NewPayloadPostProcessors(cfg *config.AgentConfig) []PayloadPostProcessor {
+	var orders []int
+	for order := range postProcessorFactories {
+		orders = append(orders, order)
+	}
+	sort.Ints(orders)
+
+	var postProcessors []PayloadPostProcessor
+	for _, order := range orders {
+		for _, factory := range postProcessorFactories[order] {
+			p, err := factory(cfg)
+			if err != nil {
+				log.Errorf("Unable to instantiate a payload post-processor: %s", err)
+				continue
+			}
+			log.Infof("Payload post-processor %s enabled", p.Name())
+			postProcessors = append(postProcessors, p)
+		}
+	}
+	return postProcessors
+}
+
+// PostProcessPayload runs the post-processors on a payload of a check. A post-processor returning
+// an error doesn't prevent the payload from being submitted, nor the next post-processors from running.
+func PostProcessPayload(postProcessors []PayloadPostProcessor, checkName string, m model.MessageBody) {
+	payload, ok := m.(*model.CollectorProc)
+	if !ok {
+		return
+	}
+	for _, p := range postProcessors {
+		if err := p.ProcessCollectorProc(checkName, payload); err != nil {
+			log.Warnf("Payload post-processor %s failed on a %s payload: %s", p.Name(), checkName, err)
+		}
+	}
+}
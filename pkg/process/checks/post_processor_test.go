@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"errors"
+	"testing"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPostProcessor struct {
+	name string
+	err  error
+	fn   func(checkName string, payload *model.CollectorProc)
+}
+
+func (p *testPostProcessor) Name() string { return p.name }
+
+func (p *testPostProcessor) ProcessCollectorProc(checkName string, payload *model.CollectorProc) error {
+	if p.fn != nil {
+		p.fn(checkName, payload)
+	}
+	return p.err
+}
+
+func TestPayloadPostProcessors(t *testing.T) {
+	defer func(factories map[int][]PayloadPostProcessorFactory) { postProcessorFactories = factories }(postProcessorFactories)
+	postProcessorFactories = make(map[int][]PayloadPostProcessorFactory)
+
+	var calls []string
+	register := func(order int, name string, err error) {
+		RegisterPayloadPostProcessor(order, func(_ *config.AgentConfig) (PayloadPostProcessor, error) {
+			return &testPostProcessor{
+				name: name,
+				err:  err,
+				fn: func(checkName string, payload *model.CollectorProc) {
+					calls = append(calls, name)
+					payload.Processes[0].Command.Args = append(payload.Processes[0].Command.Args, name)
+				},
+			}, nil
+		})
+	}
+	register(10, "enrich", nil)
+	register(0, "redact", errors.New("failing post-processor"))
+	register(10, "tag", nil)
+	RegisterPayloadPostProcessor(5, func(_ *config.AgentConfig) (PayloadPostProcessor, error) {
+		return nil, errors.New("not configured")
+	})
+
+	postProcessors := NewPayloadPostProcessors(config.NewDefaultAgentConfig(false))
+	require.Len(t, postProcessors, 3)
+
+	payload := &model.CollectorProc{Processes: []*model.Process{{Command: &model.Command{}}}}
+	PostProcessPayload(postProcessors, config.ProcessCheckName, payload)
+	assert.Equal(t, []string{"redact", "enrich", "tag"}, calls)
+	assert.Equal(t, []string{"redact", "enrich", "tag"}, payload.Processes[0].Command.Args)
+
+	// other payloads are left untouched
+	calls = nil
+	PostProcessPayload(postProcessors, config.RTProcessCheckName, &model.CollectorRealTime{})
+	assert.Empty(t, calls)
+}
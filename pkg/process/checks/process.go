@@ -57,6 +57,9 @@ type ProcessCheck struct {
 
 	// Create times by PID used in the network check
 	createTimes atomic.Value
+
+	// Tags of the process tag rules by PID used in the process events check
+	tags atomic.Value
 }
 
 // Init initializes the singleton ProcessCheck.
@@ -100,7 +103,7 @@ func (p *ProcessCheck) Run(cfg *config.AgentConfig, groupID int32) ([]model.Mess
 
 func (p *ProcessCheck) run(cfg *config.AgentConfig, groupID int32, collectRealTime bool) (*RunResult, error) {
 	start := time.Now()
-	cpuTimes, err := cpu.Times(false)
+	cpuTimes, err := systemCPUTimes()
 	if err != nil {
 		return nil, err
 	}
@@ -163,7 +166,7 @@ func (p *ProcessCheck) run(cfg *config.AgentConfig, groupID int32, collectRealTi
 	}
 
 	connsByPID := Connections.getLastConnectionsByPID()
-	procsByCtr := fmtProcesses(cfg, procs, p.lastProcs, ctrByProc, cpuTimes[0], p.lastCPUTime, p.lastRun, connsByPID)
+	procsByCtr, tagsByPID := fmtProcesses(cfg, procs, p.lastProcs, ctrByProc, cpuTimes[0], p.lastCPUTime, p.lastRun, connsByPID)
 
 	ctrs := fmtContainers(ctrList, p.lastCtrRates, p.lastRun)
 
@@ -177,6 +180,7 @@ func (p *ProcessCheck) run(cfg *config.AgentConfig, groupID int32, collectRealTi
 	p.lastRun = time.Now()
 	p.lastCtrIDForPID = ctrByProc
 	p.storeCreateTimes()
+	p.tags.Store(tagsByPID)
 
 	result := &RunResult{
 		Standard: messages,
@@ -372,7 +376,8 @@ func ctrIDForPID(ctrList []*containers.Container) map[int32]string {
 	return ctrIDForPID
 }
 
-// fmtProcesses goes through each process, converts them to process object and group them by containers
+// fmtProcesses goes through each process, converts them to process object and group them by containers.
+// It also returns the tags of the process tag rules matching each process, by PID
 // non-container processes would be in a single group with key as empty string ""
 func fmtProcesses(
 	cfg *config.AgentConfig,
@@ -381,8 +386,9 @@ func fmtProcesses(
 	syst2, syst1 cpu.TimesStat,
 	lastRun time.Time,
 	connsByPID map[int32][]*model.Connection,
-) map[string][]*model.Process {
+) (map[string][]*model.Process, map[int32][]string) {
 	procsByCtr := make(map[string][]*model.Process)
+	tagsByPID := make(map[int32][]string)
 	connCheckIntervalS := int(cfg.CheckIntervals[config.ConnectionsCheckName] / time.Second)
 
 	for _, fp := range procs {
@@ -390,6 +396,11 @@ func fmtProcesses(
 			continue
 		}
 
+		user := formatUser(fp)
+		if tags := processTags(cfg.ProcessTagRules, fp.Cmdline, user.Name); len(tags) > 0 {
+			tagsByPID[fp.Pid] = tags
+		}
+
 		// Hide blacklisted args if the Scrubber is enabled
 		fp.Cmdline = cfg.Scrubber.ScrubProcessCommand(fp)
 
@@ -409,7 +420,7 @@ func fmtProcesses(
 			Pid:                    fp.Pid,
 			NsPid:                  fp.NsPid,
 			Command:                formatCommand(fp),
-			User:                   formatUser(fp),
+			User:                   user,
 			Memory:                 formatMemory(fp.Stats),
 			Cpu:                    formatCPU(fp.Stats, lastProcs[fp.Pid].Stats, syst2, syst1),
 			CreateTime:             fp.Stats.CreateTime,
@@ -430,7 +441,7 @@ func fmtProcesses(
 
 	cfg.Scrubber.IncrementCacheAge()
 
-	return procsByCtr
+	return procsByCtr, tagsByPID
 }
 
 func formatCommand(fp *procutil.Process) *model.Command {
@@ -548,6 +559,15 @@ func (p *ProcessCheck) storeCreateTimes() {
 	p.createTimes.Store(createTimes)
 }
 
+// tagsForPID returns the tags of the process tag rules matching the process with the given PID
+// in the last run of the check
+func (p *ProcessCheck) tagsForPID(pid int32) []string {
+	if result := p.tags.Load(); result != nil {
+		return result.(map[int32][]string)[pid]
+	}
+	return nil
+}
+
 func (p *ProcessCheck) createTimesforPIDs(pids []int32) map[int32]int64 {
 	createTimeForPID := make(map[int32]int64)
 	if result := p.createTimes.Load(); result != nil {
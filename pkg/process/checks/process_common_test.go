@@ -217,7 +217,7 @@ func TestProcessChunking(t *testing.T) {
 		}
 		networks := make(map[int32][]*model.Connection)
 
-		procs := fmtProcesses(cfg, cur, last, containersByPid(containers), syst2, syst1, lastRun, networks)
+		procs, _ := fmtProcesses(cfg, cur, last, containersByPid(containers), syst2, syst1, lastRun, networks)
 		// only deal with non-container processes
 		chunked := chunkProcesses(procs[emptyCtrID], cfg.MaxPerMessage)
 		assert.Len(t, chunked, tc.expectedProcChunks, "len %d", i)
@@ -237,6 +237,22 @@ func TestProcessChunking(t *testing.T) {
 	}
 }
 
+func TestFmtProcessesTags(t *testing.T) {
+	procs := map[int32]*procutil.Process{
+		1: makeProcess(1, "mysql --password=secret"),
+		2: makeProcess(2, "sleep 1"),
+	}
+	cfg := config.NewDefaultAgentConfig(false)
+	cfg.ProcessTagRules = []config.ProcessTagRule{
+		{Name: "mysql", Cmdline: regexp.MustCompile(`^mysql --password=secret$`), Tags: []string{"service:mysql"}},
+	}
+
+	procsByCtr, tagsByPID := fmtProcesses(cfg, procs, procs, nil, cpu.TimesStat{}, cpu.TimesStat{}, time.Now().Add(-5*time.Second), nil)
+	require.Len(t, procsByCtr[emptyCtrID], 2)
+	// the rules match the command line before it's scrubbed
+	assert.Equal(t, map[int32][]string{1: {"service:mysql"}}, tagsByPID)
+}
+
 func TestPercentCalculation(t *testing.T) {
 	// Capping at NUM CPU * 100 if we get odd values for delta-{Proc,Time}
 	assert.True(t, floatEquals(calculatePct(100, 50, 1), 100))
@@ -0,0 +1,165 @@
+package checks
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ProcessEvents is a ProcessEventsCheck singleton. ProcessEvents should not be instantiated elsewhere.
+var ProcessEvents = &ProcessEventsCheck{}
+
+// maxBufferedProcessEvents is the maximum number of events kept between two runs of the check,
+// the events received once it's reached are dropped
+const maxBufferedProcessEvents = 10000
+
+// ProcessEventType is the type of a process event
+type ProcessEventType string
+
+const (
+	// ProcessEventExec is the event of a process executing a new program
+	ProcessEventExec ProcessEventType = "exec"
+	// ProcessEventExit is the event of a process exiting
+	ProcessEventExit ProcessEventType = "exit"
+)
+
+// ProcessEvent is the start or the end of a process
+type ProcessEvent struct {
+	Type      ProcessEventType `json:"type"`
+	Timestamp time.Time        `json:"timestamp"`
+	Pid       int32            `json:"pid"`
+	// Exe and Cmdline are only set for exec events, if the process is still running when the event is handled
+	Exe     string   `json:"exe,omitempty"`
+	Cmdline []string `json:"cmdline,omitempty"`
+	// ExitCode and ExitSignal are only set for exit events
+	ExitCode   uint32 `json:"exit_code,omitempty"`
+	ExitSignal uint32 `json:"exit_signal,omitempty"`
+	// Tags are the tags of the process tag rules matching the command line of exec events, and the ones
+	// matching the exited process in the last run of the process check for exit events
+	Tags []string `json:"tags,omitempty"`
+}
+
+// CollectorProcEvents is a batch of process events of a run of the check
+type CollectorProcEvents struct {
+	HostName  string          `json:"host_name"`
+	GroupID   int32           `json:"group_id"`
+	GroupSize int32           `json:"group_size"`
+	Events    []*ProcessEvent `json:"events"`
+}
+
+// processEventsListener streams the process events of the host
+type processEventsListener interface {
+	Start(handler func(*ProcessEvent)) error
+	Stop()
+}
+
+// ProcessEventsCheck collects the process exec and exit events, so that short-lived processes missed
+// by the snapshots of the process check are seen, and batches them at every run.
+// The intake payload of the events isn't part of the agent-payload version used by the process-agent
+// yet, so the check isn't part of All: it's only run with `process-agent --check process_events`.
+type ProcessEventsCheck struct {
+	listener processEventsListener
+
+	mu      sync.Mutex
+	events  []*ProcessEvent
+	dropped int
+}
+
+// Init starts listening to the process events. It is a runtime error to call Run without first having called Init.
+func (c *ProcessEventsCheck) Init(cfg *config.AgentConfig, info *model.SystemInfo) error {
+	if c.listener != nil {
+		return nil
+	}
+
+	listener, err := newProcessEventsListener()
+	if err != nil {
+		return err
+	}
+	if err := listener.Start(c.handleEvent); err != nil {
+		return err
+	}
+	c.listener = listener
+	return nil
+}
+
+// Name returns the name of the ProcessEventsCheck.
+func (c *ProcessEventsCheck) Name() string { return config.ProcessEventsCheckName }
+
+// Run returns the batches of the events received since the previous run, each one holding
+// at most cfg.MaxPerMessage events. It is a runtime error to call Run without first having called Init.
+func (c *ProcessEventsCheck) Run(cfg *config.AgentConfig, groupID int32) ([]*CollectorProcEvents, error) {
+	if c.listener == nil {
+		return nil, fmt.Errorf("ProcessEventsCheck.Run called before Init")
+	}
+
+	c.mu.Lock()
+	events, dropped := c.events, c.dropped
+	c.events, c.dropped = nil, 0
+	c.mu.Unlock()
+
+	if dropped > 0 {
+		log.Warnf("Dropped %d process events, more than %d events were received since the previous run", dropped, maxBufferedProcessEvents)
+	}
+
+	for _, event := range events {
+		if len(event.Cmdline) > 0 {
+			// The user of the events isn't known, so only the rules without user pattern apply
+			event.Tags = processTags(cfg.ProcessTagRules, event.Cmdline, "")
+			// Hide blacklisted args if the Scrubber is enabled
+			event.Cmdline = cfg.Scrubber.ScrubProcessCommand(&procutil.Process{Pid: event.Pid, Cmdline: event.Cmdline})
+		} else if event.Type == ProcessEventExit {
+			// The process check evaluated the rules with the user of the process
+			event.Tags = Process.tagsForPID(event.Pid)
+		}
+	}
+	cfg.Scrubber.IncrementCacheAge()
+
+	chunks := chunkProcessEvents(events, cfg.MaxPerMessage)
+	batches := make([]*CollectorProcEvents, 0, len(chunks))
+	for _, chunk := range chunks {
+		batches = append(batches, &CollectorProcEvents{
+			HostName:  cfg.HostName,
+			GroupID:   groupID,
+			GroupSize: int32(len(chunks)),
+			Events:    chunk,
+		})
+	}
+	return batches, nil
+}
+
+// Stop stops listening to the process events
+func (c *ProcessEventsCheck) Stop() {
+	if c.listener != nil {
+		c.listener.Stop()
+		c.listener = nil
+	}
+}
+
+func (c *ProcessEventsCheck) handleEvent(event *ProcessEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.events) >= maxBufferedProcessEvents {
+		c.dropped++
+		return
+	}
+	c.events = append(c.events, event)
+}
+
+// chunkProcessEvents splits the events into chunks of at most size events
+func chunkProcessEvents(events []*ProcessEvent, size int) [][]*ProcessEvent {
+	var chunks [][]*ProcessEvent
+	for i := 0; i < len(events); i += size {
+		end := i + size
+		if end > len(events) {
+			end = len(events)
+		}
+		chunks = append(chunks, events[i:end])
+	}
+	return chunks
+}
@@ -0,0 +1,222 @@
+package checks
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/mdlayher/netlink"
+	"github.com/mdlayher/netlink/nlenc"
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// constants of the netlink process events connector, see linux/cn_proc.h and linux/connector.h
+const (
+	cnIdxProc = 1
+	cnValProc = 1
+
+	procCnMcastListen = 1
+	procCnMcastIgnore = 2
+
+	procEventExec = 0x00000002
+	procEventExit = 0x80000000
+
+	// size of struct cn_msg, without its data
+	cnMsgHeaderSize = 20
+	// size of struct proc_event, without its event data
+	procEventHeaderSize = 16
+
+	// processEventsQueueSize is the number of parsed events waiting to be enriched from procfs,
+	// the events received once it's full are dropped
+	processEventsQueueSize = 4096
+)
+
+// netlinkProcessEventsListener receives the process events from the netlink process events connector,
+// which requires the CAP_NET_ADMIN capability. The events are only parsed by the receive loop, they are
+// read from procfs by another goroutine so that the socket is drained fast enough not to overflow.
+type netlinkProcessEventsListener struct {
+	procRoot  string
+	byteOrder binary.ByteOrder
+	conn      *netlink.Conn
+	done      chan struct{}
+	queue     chan *ProcessEvent
+	// dropped is the number of events dropped because the queue was full
+	dropped uint64
+}
+
+func newProcessEventsListener() (processEventsListener, error) {
+	return &netlinkProcessEventsListener{
+		procRoot:  util.HostProc(),
+		byteOrder: nlenc.NativeEndian(),
+	}, nil
+}
+
+// Start subscribes to the process events and calls the handler for each exec and exit event
+func (l *netlinkProcessEventsListener) Start(handler func(*ProcessEvent)) error {
+	conn, err := netlink.Dial(unix.NETLINK_CONNECTOR, &netlink.Config{Groups: cnIdxProc})
+	if err != nil {
+		return fmt.Errorf("unable to connect to the netlink process events connector: %w", err)
+	}
+
+	if err := l.setMulticast(conn, procCnMcastListen); err != nil {
+		conn.Close()
+		return fmt.Errorf("unable to subscribe to the process events: %w", err)
+	}
+
+	l.conn = conn
+	l.done = make(chan struct{})
+	l.queue = make(chan *ProcessEvent, processEventsQueueSize)
+	go l.enrich(l.queue, l.done, handler)
+	go l.receive(conn)
+	return nil
+}
+
+// Stop unsubscribes from the process events
+func (l *netlinkProcessEventsListener) Stop() {
+	if l.conn == nil {
+		return
+	}
+	close(l.done)
+	if err := l.setMulticast(l.conn, procCnMcastIgnore); err != nil {
+		log.Debugf("Unable to unsubscribe from the process events: %s", err)
+	}
+	l.conn.Close()
+	l.conn = nil
+}
+
+// setMulticast sends a PROC_CN_MCAST_LISTEN or PROC_CN_MCAST_IGNORE operation to the connector
+func (l *netlinkProcessEventsListener) setMulticast(conn *netlink.Conn, op uint32) error {
+	data := make([]byte, cnMsgHeaderSize+4)
+	l.byteOrder.PutUint32(data[0:4], cnIdxProc)
+	l.byteOrder.PutUint32(data[4:8], cnValProc)
+	l.byteOrder.PutUint16(data[16:18], 4)
+	l.byteOrder.PutUint32(data[cnMsgHeaderSize:], op)
+
+	_, err := conn.Send(netlink.Message{
+		Header: netlink.Header{Type: netlink.Done},
+		Data:   data,
+	})
+	return err
+}
+
+// receive parses the events received on the socket and queues them to be enriched
+func (l *netlinkProcessEventsListener) receive(conn *netlink.Conn) {
+	for {
+		msgs, err := conn.Receive()
+		if err != nil {
+			select {
+			case <-l.done:
+				return
+			default:
+			}
+			// the events overflowing the socket buffer are lost, the next ones are still received
+			log.Debugf("Error receiving process events: %s", err)
+			continue
+		}
+
+		for _, msg := range msgs {
+			if event := l.parseEvent(msg.Data); event != nil {
+				l.enqueue(event)
+			}
+		}
+	}
+}
+
+// enqueue queues an event to be enriched, without blocking the receive loop
+func (l *netlinkProcessEventsListener) enqueue(event *ProcessEvent) {
+	select {
+	case l.queue <- event:
+	default:
+		if dropped := atomic.AddUint64(&l.dropped, 1); dropped%1000 == 1 {
+			log.Debugf("The process events queue is full, %d events dropped", dropped)
+		}
+	}
+}
+
+// enrich reads the executable and the command line of the exec events from procfs and calls the handler for each event
+func (l *netlinkProcessEventsListener) enrich(queue <-chan *ProcessEvent, done <-chan struct{}, handler func(*ProcessEvent)) {
+	for {
+		select {
+		case <-done:
+			return
+		case event := <-queue:
+			if event.Type == ProcessEventExec {
+				event.Exe, event.Cmdline = l.readProcess(event.Pid)
+			}
+			handler(event)
+		}
+	}
+}
+
+// parseEvent returns the exec or exit event of a cn_msg holding a proc_event, or nil for the other events
+// and for the exit of the threads which aren't the leader of their thread group
+func (l *netlinkProcessEventsListener) parseEvent(data []byte) *ProcessEvent {
+	if len(data) < cnMsgHeaderSize+procEventHeaderSize {
+		return nil
+	}
+	if l.byteOrder.Uint32(data[0:4]) != cnIdxProc || l.byteOrder.Uint32(data[4:8]) != cnValProc {
+		return nil
+	}
+
+	event := data[cnMsgHeaderSize:]
+	what := l.byteOrder.Uint32(event[0:4])
+	eventData := event[procEventHeaderSize:]
+
+	switch what {
+	case procEventExec:
+		// struct exec_proc_event { pid_t process_pid; pid_t process_tgid; }
+		if len(eventData) < 8 {
+			return nil
+		}
+		// the executable and the command line are read from procfs by enrich
+		pid := int32(l.byteOrder.Uint32(eventData[4:8]))
+		return &ProcessEvent{
+			Type:      ProcessEventExec,
+			Timestamp: time.Now(),
+			Pid:       pid,
+		}
+	case procEventExit:
+		// struct exit_proc_event { pid_t process_pid; pid_t process_tgid; u32 exit_code, exit_signal; ... }
+		if len(eventData) < 12 {
+			return nil
+		}
+		pid := int32(l.byteOrder.Uint32(eventData[0:4]))
+		tgid := int32(l.byteOrder.Uint32(eventData[4:8]))
+		if pid != tgid {
+			return nil
+		}
+		// exit_code is the wait status of the process
+		status := l.byteOrder.Uint32(eventData[8:12])
+		return &ProcessEvent{
+			Type:       ProcessEventExit,
+			Timestamp:  time.Now(),
+			Pid:        tgid,
+			ExitCode:   (status >> 8) & 0xff,
+			ExitSignal: status & 0x7f,
+		}
+	}
+	return nil
+}
+
+// readProcess returns the executable and the command line of a process, they are empty when
+// the process has already exited
+func (l *netlinkProcessEventsListener) readProcess(pid int32) (string, []string) {
+	pidPath := filepath.Join(l.procRoot, strconv.Itoa(int(pid)))
+
+	exe, _ := os.Readlink(filepath.Join(pidPath, "exe"))
+
+	content, err := ioutil.ReadFile(filepath.Join(pidPath, "cmdline"))
+	if err != nil || len(content) == 0 {
+		return exe, nil
+	}
+	return exe, strings.Split(strings.TrimRight(string(content), "\x00"), "\x00")
+}
@@ -0,0 +1,96 @@
+package checks
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func buildProcEvent(byteOrder binary.ByteOrder, what uint32, eventData ...uint32) []byte {
+	data := make([]byte, cnMsgHeaderSize+procEventHeaderSize+4*len(eventData))
+	byteOrder.PutUint32(data[0:4], cnIdxProc)
+	byteOrder.PutUint32(data[4:8], cnValProc)
+	byteOrder.PutUint32(data[cnMsgHeaderSize:], what)
+	for i, value := range eventData {
+		byteOrder.PutUint32(data[cnMsgHeaderSize+procEventHeaderSize+4*i:], value)
+	}
+	return data
+}
+
+func TestParseProcessEvent(t *testing.T) {
+	listener := &netlinkProcessEventsListener{procRoot: t.TempDir(), byteOrder: binary.LittleEndian}
+
+	t.Run("exec", func(t *testing.T) {
+		event := listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExec, 42, 42))
+		require.NotNil(t, event)
+		assert.Equal(t, ProcessEventExec, event.Type)
+		assert.Equal(t, int32(42), event.Pid)
+		// the process is read from procfs by enrich
+		assert.Empty(t, event.Cmdline)
+	})
+
+	t.Run("exit", func(t *testing.T) {
+		// exit status 3
+		event := listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExit, 42, 42, 3<<8, 17))
+		require.NotNil(t, event)
+		assert.Equal(t, ProcessEventExit, event.Type)
+		assert.Equal(t, int32(42), event.Pid)
+		assert.Equal(t, uint32(3), event.ExitCode)
+		assert.Zero(t, event.ExitSignal)
+
+		// killed by SIGKILL
+		event = listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExit, 42, 42, 9, 17))
+		require.NotNil(t, event)
+		assert.Zero(t, event.ExitCode)
+		assert.Equal(t, uint32(9), event.ExitSignal)
+	})
+
+	t.Run("thread exit", func(t *testing.T) {
+		assert.Nil(t, listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExit, 43, 42, 0, 17)))
+	})
+
+	t.Run("fork", func(t *testing.T) {
+		assert.Nil(t, listener.parseEvent(buildProcEvent(listener.byteOrder, 1, 1, 1, 42, 42)))
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		assert.Nil(t, listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExit, 42)))
+		assert.Nil(t, listener.parseEvent([]byte{1, 2, 3}))
+	})
+}
+
+func TestEnrichProcessEvents(t *testing.T) {
+	procRoot := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(procRoot, "42"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(procRoot, "42", "cmdline"), []byte("sleep\x0010\x00"), 0644))
+
+	listener := &netlinkProcessEventsListener{
+		procRoot:  procRoot,
+		byteOrder: binary.LittleEndian,
+		queue:     make(chan *ProcessEvent, 1),
+	}
+	done := make(chan struct{})
+	defer close(done)
+
+	// the queue is full, the events are dropped instead of blocking the receive loop
+	listener.enqueue(listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExec, 42, 42)))
+	listener.enqueue(listener.parseEvent(buildProcEvent(listener.byteOrder, procEventExit, 42, 42, 0, 17)))
+	assert.Equal(t, uint64(1), listener.dropped)
+
+	handled := make(chan *ProcessEvent, 1)
+	go listener.enrich(listener.queue, done, func(event *ProcessEvent) { handled <- event })
+
+	select {
+	case event := <-handled:
+		assert.Equal(t, ProcessEventExec, event.Type)
+		assert.Equal(t, []string{"sleep", "10"}, event.Cmdline)
+	case <-time.After(time.Second):
+		t.Fatal("the exec event wasn't handled")
+	}
+}
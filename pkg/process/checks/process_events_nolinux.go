@@ -0,0 +1,9 @@
+// +build !linux
+
+package checks
+
+import "errors"
+
+func newProcessEventsListener() (processEventsListener, error) {
+	return nil, errors.New("process events are only supported on Linux")
+}
@@ -0,0 +1,104 @@
+package checks
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+)
+
+type testProcessEventsListener struct {
+	handler func(*ProcessEvent)
+	stopped bool
+}
+
+func (l *testProcessEventsListener) Start(handler func(*ProcessEvent)) error {
+	l.handler = handler
+	return nil
+}
+
+func (l *testProcessEventsListener) Stop() {
+	l.stopped = true
+}
+
+func TestProcessEventsCheck(t *testing.T) {
+	cfg := &config.AgentConfig{HostName: "host", MaxPerMessage: 2, Scrubber: config.NewDefaultDataScrubber()}
+	cfg.ProcessTagRules = []config.ProcessTagRule{
+		{Name: "mysql", Cmdline: regexp.MustCompile(`^mysql `), Tags: []string{"service:mysql"}},
+		{Name: "root", User: regexp.MustCompile(`^root$`), Tags: []string{"user:root"}},
+	}
+	listener := &testProcessEventsListener{}
+	check := &ProcessEventsCheck{}
+
+	_, err := check.Run(cfg, 0)
+	assert.Error(t, err, "Run called before Init")
+
+	check.listener = listener
+	require.NoError(t, listener.Start(check.handleEvent))
+
+	listener.handler(&ProcessEvent{Type: ProcessEventExec, Pid: 1, Cmdline: []string{"mysql", "--password=secret"}})
+	listener.handler(&ProcessEvent{Type: ProcessEventExec, Pid: 2, Cmdline: []string{"sleep", "1"}})
+	listener.handler(&ProcessEvent{Type: ProcessEventExit, Pid: 2})
+
+	batches, err := check.Run(cfg, 3)
+	require.NoError(t, err)
+	require.Len(t, batches, 2)
+	for _, batch := range batches {
+		assert.Equal(t, "host", batch.HostName)
+		assert.Equal(t, int32(3), batch.GroupID)
+		assert.Equal(t, int32(2), batch.GroupSize)
+	}
+	require.Len(t, batches[0].Events, 2)
+	require.Len(t, batches[1].Events, 1)
+	assert.Equal(t, []string{"mysql", "--password=********"}, batches[0].Events[0].Cmdline)
+	assert.Equal(t, []string{"service:mysql"}, batches[0].Events[0].Tags)
+	assert.Empty(t, batches[0].Events[1].Tags)
+	assert.Equal(t, ProcessEventExit, batches[1].Events[0].Type)
+
+	// the events are only returned once
+	batches, err = check.Run(cfg, 4)
+	require.NoError(t, err)
+	assert.Empty(t, batches)
+
+	check.Stop()
+	assert.True(t, listener.stopped)
+}
+
+func TestProcessEventsCheckExitTags(t *testing.T) {
+	cfg := &config.AgentConfig{HostName: "host", MaxPerMessage: 10, Scrubber: config.NewDefaultDataScrubber()}
+	listener := &testProcessEventsListener{}
+	check := &ProcessEventsCheck{listener: listener}
+	require.NoError(t, listener.Start(check.handleEvent))
+
+	Process.tags.Store(map[int32][]string{2: {"user:root"}})
+	defer Process.tags.Store(map[int32][]string(nil))
+
+	listener.handler(&ProcessEvent{Type: ProcessEventExit, Pid: 2})
+	listener.handler(&ProcessEvent{Type: ProcessEventExit, Pid: 3})
+
+	batches, err := check.Run(cfg, 0)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0].Events, 2)
+	assert.Equal(t, []string{"user:root"}, batches[0].Events[0].Tags)
+	assert.Empty(t, batches[0].Events[1].Tags)
+}
+
+func TestProcessEventsCheckDropped(t *testing.T) {
+	cfg := &config.AgentConfig{MaxPerMessage: maxBufferedProcessEvents, Scrubber: config.NewDefaultDataScrubber()}
+	check := &ProcessEventsCheck{listener: &testProcessEventsListener{}}
+
+	for i := 0; i < maxBufferedProcessEvents+10; i++ {
+		check.handleEvent(&ProcessEvent{Type: ProcessEventExit, Pid: int32(i)})
+	}
+	assert.Equal(t, 10, check.dropped)
+
+	batches, err := check.Run(cfg, 0)
+	require.NoError(t, err)
+	require.Len(t, batches, 1)
+	assert.Len(t, batches[0].Events, maxBufferedProcessEvents)
+	assert.Zero(t, check.dropped)
+}
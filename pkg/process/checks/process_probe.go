@@ -25,7 +25,7 @@ func getProcessProbe(cfg *config.AgentConfig) procutil.Probe {
 			}
 			log.Info("Using perf counters probe for process data collection")
 		}
-		processProbe = procutil.NewProcessProbe()
+		processProbe = procutil.NewProcessProbe(procutil.WithSystemdUnit(cfg.CollectSystemdUnit))
 	})
 	return processProbe
 }
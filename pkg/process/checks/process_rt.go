@@ -16,7 +16,7 @@ import (
 // runRealtime runs the realtime ProcessCheck to collect statistics about the running processes.
 // Underying procutil.Probe is responsible for the actual implementation
 func (p *ProcessCheck) runRealtime(cfg *config.AgentConfig, groupID int32) (*RunResult, error) {
-	cpuTimes, err := cpu.Times(false)
+	cpuTimes, err := systemCPUTimes()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,35 @@
+package checks
+
+import (
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+)
+
+// processTags returns the tags of the rules matching the command line and the user of a process, without
+// duplicates. The rules with a user pattern don't match the processes whose user is unknown.
+func processTags(rules []config.ProcessTagRule, cmdline []string, username string) []string {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	joined := strings.Join(cmdline, " ")
+
+	var tags []string
+	seen := make(map[string]struct{})
+	for _, rule := range rules {
+		if rule.Cmdline != nil && !rule.Cmdline.MatchString(joined) {
+			continue
+		}
+		if rule.User != nil && (username == "" || !rule.User.MatchString(username)) {
+			continue
+		}
+		for _, tag := range rule.Tags {
+			if _, found := seen[tag]; !found {
+				seen[tag] = struct{}{}
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
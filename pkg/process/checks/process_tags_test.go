@@ -0,0 +1,55 @@
+package checks
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+)
+
+func TestProcessTags(t *testing.T) {
+	rules := []config.ProcessTagRule{
+		{Name: "kafka", Cmdline: regexp.MustCompile(`java .*kafka`), Tags: []string{"service:kafka"}},
+		{Name: "zookeeper", Cmdline: regexp.MustCompile(`java .*zookeeper`), Tags: []string{"service:zookeeper"}},
+		{Name: "jvm", Cmdline: regexp.MustCompile(`^java `), User: regexp.MustCompile(`^(kafka|zookeeper)$`), Tags: []string{"runtime:jvm", "team:streaming"}},
+		{Name: "team", User: regexp.MustCompile(`^kafka$`), Tags: []string{"team:streaming"}},
+	}
+
+	for _, tc := range []struct {
+		name     string
+		cmdline  []string
+		username string
+		expected []string
+	}{
+		{
+			name:     "cmdline and user",
+			cmdline:  []string{"java", "-Xmx1G", "kafka.Kafka", "server.properties"},
+			username: "kafka",
+			expected: []string{"service:kafka", "runtime:jvm", "team:streaming"},
+		},
+		{
+			name:     "cmdline only",
+			cmdline:  []string{"java", "-cp", "zookeeper.jar", "QuorumPeerMain"},
+			username: "root",
+			expected: []string{"service:zookeeper"},
+		},
+		{
+			name:     "unknown user",
+			cmdline:  []string{"java", "kafka.Kafka"},
+			expected: []string{"service:kafka"},
+		},
+		{
+			name:     "no match",
+			cmdline:  []string{"nginx", "-g", "daemon off;"},
+			username: "www-data",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, processTags(rules, tc.cmdline, tc.username))
+		})
+	}
+
+	assert.Nil(t, processTags(nil, []string{"java", "kafka.Kafka"}, "kafka"))
+}
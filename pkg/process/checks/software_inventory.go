@@ -0,0 +1,136 @@
+package checks
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/datadog-agent/pkg/process/config"
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// SoftwareInventory is a SoftwareInventoryCollector singleton. SoftwareInventory should not be instantiated elsewhere.
+var SoftwareInventory = &SoftwareInventoryCollector{}
+
+// SoftwareInventoryPayload is the inventory of the binaries of the processes running on a host.
+// It is encoded in JSON, each binary appears once whatever the number of processes running it.
+type SoftwareInventoryPayload struct {
+	Hostname  string                    `json:"hostname"`
+	Timestamp int64                     `json:"timestamp"`
+	Binaries  []*SoftwareInventoryEntry `json:"binaries"`
+}
+
+// SoftwareInventoryEntry is a binary of the inventory
+type SoftwareInventoryEntry struct {
+	*BinaryFingerprint
+	Processes int `json:"processes"`
+}
+
+// binaryCacheKey identifies a binary file, a binary is fingerprinted again when it is modified
+type binaryCacheKey struct {
+	path    string
+	size    int64
+	modTime int64
+}
+
+// SoftwareInventoryCollector fingerprints the binaries of the running processes.
+// It doesn't implement Check as its payload isn't sent to the process intake.
+type SoftwareInventoryCollector struct {
+	probe      procutil.Probe
+	cache      map[binaryCacheKey]*BinaryFingerprint
+	initCalled bool
+}
+
+// Init initializes the SoftwareInventoryCollector. It is a runtime error to call Collect without first having called Init.
+func (s *SoftwareInventoryCollector) Init(cfg *config.AgentConfig, _ *model.SystemInfo) {
+	s.probe = getProcessProbe(cfg)
+	s.cache = make(map[binaryCacheKey]*BinaryFingerprint)
+	s.initCalled = true
+}
+
+// Name returns the name of the SoftwareInventoryCollector.
+func (s *SoftwareInventoryCollector) Name() string { return config.SoftwareInventoryName }
+
+// Collect returns the inventory of the binaries of the running processes.
+// Binaries are only read the first time they are seen, or when they change.
+func (s *SoftwareInventoryCollector) Collect(cfg *config.AgentConfig, now time.Time) (*SoftwareInventoryPayload, error) {
+	if !s.initCalled {
+		return nil, fmt.Errorf("SoftwareInventoryCollector.Collect called before Init")
+	}
+
+	// Does not need to collect process stats, only metadata
+	procs, err := s.probe.ProcessesByPID(now, false)
+	if err != nil {
+		return nil, err
+	}
+
+	cache := make(map[binaryCacheKey]*BinaryFingerprint, len(s.cache))
+	entries := make(map[string]*SoftwareInventoryEntry)
+	for _, proc := range procs {
+		// kernel threads don't have a binary
+		if proc.Exe == "" {
+			continue
+		}
+		fingerprint, err := s.fingerprint(proc, cache)
+		if err != nil {
+			log.Debugf("Unable to fingerprint the binary %s of process %d: %s", proc.Exe, proc.Pid, err)
+			continue
+		}
+		entry, ok := entries[fingerprint.Hash]
+		if !ok {
+			entry = &SoftwareInventoryEntry{BinaryFingerprint: fingerprint}
+			entries[fingerprint.Hash] = entry
+		}
+		entry.Processes++
+	}
+	// binaries of the processes that exited are forgotten
+	s.cache = cache
+
+	payload := &SoftwareInventoryPayload{
+		Hostname:  cfg.HostName,
+		Timestamp: now.Unix(),
+		Binaries:  make([]*SoftwareInventoryEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		payload.Binaries = append(payload.Binaries, entry)
+	}
+	sort.Slice(payload.Binaries, func(i, j int) bool {
+		if payload.Binaries[i].Path != payload.Binaries[j].Path {
+			return payload.Binaries[i].Path < payload.Binaries[j].Path
+		}
+		return payload.Binaries[i].Hash < payload.Binaries[j].Hash
+	})
+	return payload, nil
+}
+
+// fingerprint returns the fingerprint of the binary of the process, from the cache when it didn't change
+func (s *SoftwareInventoryCollector) fingerprint(proc *procutil.Process, cache map[binaryCacheKey]*BinaryFingerprint) (*BinaryFingerprint, error) {
+	f, err := openProcessBinary(proc)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	key := binaryCacheKey{path: proc.Exe, size: info.Size(), modTime: info.ModTime().UnixNano()}
+	if fingerprint, ok := cache[key]; ok {
+		return fingerprint, nil
+	}
+	fingerprint, ok := s.cache[key]
+	if !ok {
+		fingerprint = fingerprintBinary(proc.Exe, f)
+	}
+	cache[key] = fingerprint
+	return fingerprint, nil
+}
+
+// openProcessBinary opens the binary of a process
+func openProcessBinary(proc *procutil.Process) (*os.File, error) {
+	return os.Open(processBinaryPath(proc))
+}
@@ -0,0 +1,14 @@
+package checks
+
+import (
+	"strconv"
+
+	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+)
+
+// processBinaryPath returns the path of the binary of the process from the host procfs,
+// which resolves to the right file even when the process runs in another mount namespace
+func processBinaryPath(proc *procutil.Process) string {
+	return util.HostProc(strconv.Itoa(int(proc.Pid)), "exe")
+}
@@ -0,0 +1,10 @@
+// +build !linux
+
+package checks
+
+import "github.com/DataDog/datadog-agent/pkg/process/procutil"
+
+// processBinaryPath returns the path of the binary of the process
+func processBinaryPath(proc *procutil.Process) string {
+	return proc.Exe
+}
@@ -26,6 +26,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/fargate"
 	ddgrpc "github.com/DataDog/datadog-agent/pkg/util/grpc"
 	"github.com/DataDog/datadog-agent/pkg/util/hostname/validate"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/profiling"
 	"google.golang.org/grpc"
@@ -49,6 +50,12 @@ const (
 	PodCheckName         = "pod"
 	DiscoveryCheckName   = "process_discovery"
 
+	// ProcessEventsCheckName is the name of the process events check, it's only run for debugging purposes
+	ProcessEventsCheckName = "process_events"
+
+	// SoftwareInventoryName is the name of the software inventory payload, it isn't sent by a check
+	SoftwareInventoryName = "software_inventory"
+
 	NetworkCheckName        = "Network"
 	OOMKillCheckName        = "OOM Kill"
 	TCPQueueLengthCheckName = "TCP queue length"
@@ -61,6 +68,7 @@ const (
 	ConnectionsCheckDefaultInterval      = 30 * time.Second
 	PodCheckDefaultInterval              = 10 * time.Second
 	ProcessDiscoveryCheckDefaultInterval = 4 * time.Hour
+	SoftwareInventoryDefaultInterval     = time.Hour
 )
 
 var (
@@ -133,9 +141,24 @@ type AgentConfig struct {
 	// Internal store of a proxy used for generating the Transport
 	proxy proxyFunc
 
+	// Software inventory config
+	SoftwareInventory SoftwareInventoryConfig
+
+	// ZstdDictionary compresses the process payloads with a zstd dictionary trained on their shape
+	ZstdDictionary bool
+
+	// CollectSystemdUnit reads the systemd service running each process (Linux only)
+	CollectSystemdUnit bool
+
+	// ProcessTagRules tags the processes matching their command line and user patterns
+	ProcessTagRules []ProcessTagRule
+
 	// Windows-specific config
 	Windows WindowsConfig
 
+	// RemoteTagger uses the tagger of the core agent instead of running a local one
+	RemoteTagger bool
+
 	grpcConnectionTimeout time.Duration
 }
 
@@ -163,17 +186,19 @@ const (
 
 // NewDefaultTransport provides a http transport configuration with sane default timeouts
 func NewDefaultTransport() *http.Transport {
-	return &http.Transport{
+	transport := &http.Transport{
 		MaxIdleConns:    5,
 		IdleConnTimeout: 90 * time.Second,
-		Dial: (&net.Dialer{
+		DialContext: (&net.Dialer{
 			Timeout:   10 * time.Second,
 			KeepAlive: 10 * time.Second,
-		}).Dial,
+		}).DialContext,
 		TLSHandshakeTimeout:   5 * time.Second,
 		ResponseHeaderTimeout: 5 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
+	httputils.GetEgressValidator().Apply(transport)
+	return transport
 }
 
 // NewDefaultAgentConfig returns an AgentConfig with defaults initialized
@@ -189,6 +214,7 @@ func NewDefaultAgentConfig(canAccessContainers bool) *AgentConfig {
 		enabledChecks = containerChecks
 	}
 
+	defaults := defaultProcessConfig()
 	ac := &AgentConfig{
 		Enabled:      canAccessContainers, // We'll always run inside of a container.
 		APIEndpoints: []apicfg.Endpoint{{Endpoint: processEndpoint}},
@@ -196,20 +222,17 @@ func NewDefaultAgentConfig(canAccessContainers bool) *AgentConfig {
 		LogLevel:     "info",
 		LogToConsole: false,
 
-		// Allow buffering up to 75 megabytes of payload data in total
-		ProcessQueueBytes: 60 * 1000 * 1000,
-		// This can be fairly high as the input should get throttled by queue bytes first.
-		// Assuming we generate ~8 checks/minute (for process/network), this should allow buffering of ~30 minutes of data assuming it fits within the queue bytes memory budget
-		QueueSize:   256,
-		RTQueueSize: 5, // We set a small queue size for real-time message queue because they get staled very quickly, thus we only keep the latest several payloads
+		ProcessQueueBytes: defaults.ProcessQueueBytes,
+		QueueSize:         defaults.QueueSize,
+		RTQueueSize:       defaults.RTQueueSize,
 
-		MaxPerMessage:             maxMessageBatch,
-		MaxCtrProcessesPerMessage: defaultMaxCtrProcsMessageBatch,
+		MaxPerMessage:             defaults.MaxPerMessage,
+		MaxCtrProcessesPerMessage: defaults.MaxCtrProcsPerMessage,
 		MaxConnsPerMessage:        600,
 		AllowRealTime:             true,
 		HostName:                  "",
 		Transport:                 NewDefaultTransport(),
-		ProcessExpVarPort:         6062,
+		ProcessExpVarPort:         defaults.ExpVarPort,
 		ContainerHostType:         model.ContainerHostType_notSpecified,
 
 		// Statsd for internal instrumentation
@@ -224,28 +247,17 @@ func NewDefaultAgentConfig(canAccessContainers bool) *AgentConfig {
 		Orchestrator: oconfig.NewDefaultOrchestratorConfig(),
 
 		// Check config
-		EnabledChecks: enabledChecks,
-		CheckIntervals: map[string]time.Duration{
-			ProcessCheckName:     ProcessCheckDefaultInterval,
-			RTProcessCheckName:   RTProcessCheckDefaultInterval,
-			ContainerCheckName:   ContainerCheckDefaultInterval,
-			RTContainerCheckName: RTContainerCheckDefaultInterval,
-			ConnectionsCheckName: ConnectionsCheckDefaultInterval,
-			PodCheckName:         PodCheckDefaultInterval,
-			DiscoveryCheckName:   ProcessDiscoveryCheckDefaultInterval,
-		},
+		EnabledChecks:  enabledChecks,
+		CheckIntervals: defaults.CheckIntervals,
 
 		// DataScrubber to hide command line sensitive words
 		Scrubber:  NewDefaultDataScrubber(),
 		Blacklist: make([]*regexp.Regexp, 0),
 
 		// Windows process config
-		Windows: WindowsConfig{
-			ArgsRefreshInterval: 15, // with default 20s check interval we refresh every 5m
-			AddNewArgs:          true,
-		},
+		Windows: defaults.Windows,
 
-		grpcConnectionTimeout: defaultGRPCConnectionTimeout,
+		grpcConnectionTimeout: defaults.GRPCConnectionTimeout,
 	}
 
 	// Set default values for proc/sys paths if unset.
@@ -389,6 +401,7 @@ func initRuntimeSettings() {
 	// NOTE: Any settings you want to register should simply be added here
 	var processRuntimeSettings = []settings.RuntimeSetting{
 		settings.LogLevelRuntimeSetting{},
+		settings.LogLevelByModuleRuntimeSetting{},
 	}
 
 	// Before we begin listening, register runtime settings
@@ -3,11 +3,13 @@ package config
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/DataDog/datadog-agent/pkg/process/procutil"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -21,14 +23,95 @@ var (
 
 const (
 	defaultCacheMaxCycles = 25
+
+	scrubbedValue = "********"
+
+	defaultEntropyMinLength = 20
+	defaultEntropyThreshold = 4.5
+
+	// names of the built-in rules in the scrubber telemetry
+	sensitiveWordsRuleName = "sensitive_words"
+	entropyRuleName        = "entropy"
 )
 
+var tlmScrubberHits = telemetry.NewCounter("process", "scrubber_hits",
+	[]string{"rule"}, "Command lines scrubbed, by scrubber rule")
+
+// secretCandidate matches the values that may be generated secrets: base64 or base64url
+// encoded values
+var secretCandidate = regexp.MustCompile(`^[A-Za-z0-9+/_-]+={0,2}$`)
+
+// ScrubberRule replaces the matches of a regular expression in the command lines
+type ScrubberRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+	// Replacement is a template in the regexp.Expand syntax: ${1} or ${name} are replaced by
+	// the submatches of Pattern
+	Replacement string
+}
+
+// defaultScrubberRules returns the rules applied in addition to the sensitive words by default
+func defaultScrubberRules() []ScrubberRule {
+	return []ScrubberRule{
+		{
+			Name:        "jwt",
+			Pattern:     regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+			Replacement: scrubbedValue,
+		},
+		{
+			Name:        "url_credentials",
+			Pattern:     regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://[^\s:/@]+):[^\s/@]+@`),
+			Replacement: "${1}:" + scrubbedValue + "@",
+		},
+	}
+}
+
+// EntropyDetection configures the scrubbing of the argument values that look like generated
+// secrets: base64 values with a high Shannon entropy
+type EntropyDetection struct {
+	Enabled bool
+	// MinLength is the minimum length of the values scrubbed
+	MinLength int
+	// Threshold is the minimum entropy of the values scrubbed, in bits per character
+	Threshold float64
+}
+
+// isSecret returns whether an argument value looks like a generated secret
+func (e EntropyDetection) isSecret(value string) bool {
+	if len(value) < e.MinLength || strings.HasPrefix(value, "/") || strings.HasPrefix(value, "-") {
+		return false
+	}
+	if !secretCandidate.MatchString(value) ||
+		!strings.ContainsAny(value, "0123456789") ||
+		!strings.ContainsAny(value, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return false
+	}
+	return shannonEntropy(value) >= e.Threshold
+}
+
+// shannonEntropy returns the Shannon entropy of a string, in bits per character
+func shannonEntropy(value string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range value {
+		counts[r]++
+	}
+	var entropy float64
+	length := float64(len(value))
+	for _, count := range counts {
+		frequency := float64(count) / length
+		entropy -= frequency * math.Log2(frequency)
+	}
+	return entropy
+}
+
 // DataScrubber allows the agent to blacklist cmdline arguments that match
 // a list of predefined and custom words
 type DataScrubber struct {
 	Enabled           bool
 	StripAllArguments bool
 	SensitivePatterns []*regexp.Regexp
+	Rules             []ScrubberRule
+	EntropyDetection  EntropyDetection
 	seenProcess       map[string]struct{}
 	scrubbedCmdlines  map[string][]string
 	cacheCycles       uint32 // used to control the cache age
@@ -41,6 +124,7 @@ func NewDefaultDataScrubber() *DataScrubber {
 	newDataScrubber := &DataScrubber{
 		Enabled:           true,
 		SensitivePatterns: CompileStringsToRegex(defaultSensitiveWords),
+		Rules:             defaultScrubberRules(),
 		seenProcess:       make(map[string]struct{}),
 		scrubbedCmdlines:  make(map[string][]string),
 		cacheCycles:       0,
@@ -152,23 +236,55 @@ func (ds *DataScrubber) IncrementCacheAge() {
 	}
 }
 
-// ScrubCommand hides the argument value for any key which matches a "sensitive word" pattern.
+// ScrubCommand hides the argument value for any key which matches a "sensitive word" pattern,
+// the matches of the scrubber rules and, when enabled, the high-entropy values.
 // It returns the updated cmdline, as well as a boolean representing whether it was scrubbed
 func (ds *DataScrubber) ScrubCommand(cmdline []string) ([]string, bool) {
-	newCmdline := cmdline
 	rawCmdline := strings.Join(cmdline, " ")
 	changed := false
 	for _, pattern := range ds.SensitivePatterns {
 		if pattern.MatchString(rawCmdline) {
 			changed = true
-			rawCmdline = pattern.ReplaceAllString(rawCmdline, "${key}${delimiter}********")
+			rawCmdline = pattern.ReplaceAllString(rawCmdline, "${key}${delimiter}"+scrubbedValue)
+			tlmScrubberHits.Inc(sensitiveWordsRuleName)
 		}
 	}
+	for _, rule := range ds.Rules {
+		if rule.Pattern.MatchString(rawCmdline) {
+			changed = true
+			rawCmdline = rule.Pattern.ReplaceAllString(rawCmdline, rule.Replacement)
+			tlmScrubberHits.Inc(rule.Name)
+		}
+	}
+
+	if !changed && !ds.EntropyDetection.Enabled {
+		return cmdline, false
+	}
+
+	newCmdline := strings.Split(rawCmdline, " ")
+	if ds.EntropyDetection.Enabled && ds.scrubHighEntropyValues(newCmdline) {
+		changed = true
+		tlmScrubberHits.Inc(entropyRuleName)
+	}
+	if !changed {
+		return cmdline, false
+	}
+	return newCmdline, true
+}
 
-	if changed {
-		newCmdline = strings.Split(rawCmdline, " ")
+// scrubHighEntropyValues hides the argument values, or the values following a `=` or `:`
+// delimiter, that look like generated secrets. It returns whether an argument was scrubbed.
+func (ds *DataScrubber) scrubHighEntropyValues(args []string) bool {
+	changed := false
+	for i, arg := range args {
+		// the trailing `=` are the base64 padding of the value, not a delimiter
+		valueStart := strings.LastIndexAny(strings.TrimRight(arg, "="), "=:") + 1
+		if ds.EntropyDetection.isSecret(arg[valueStart:]) {
+			args[i] = arg[:valueStart] + scrubbedValue
+			changed = true
+		}
 	}
-	return newCmdline, changed
+	return changed
 }
 
 // Strip away all arguments from the command line
@@ -0,0 +1,524 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// CollectionMode is what the process-agent collects
+type CollectionMode int
+
+const (
+	// CollectionDefault collects the containers when they can be accessed
+	CollectionDefault CollectionMode = iota
+	// CollectionDisabled doesn't collect anything, the process-agent doesn't run
+	CollectionDisabled
+	// CollectionContainers collects the containers
+	CollectionContainers
+	// CollectionProcesses collects the processes, along with their containers
+	CollectionProcesses
+)
+
+// ProcessDiscoveryConfig is the configuration of the process discovery check
+type ProcessDiscoveryConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// SoftwareInventoryConfig is the configuration of the software inventory, which fingerprints
+// the binaries of the running processes
+type SoftwareInventoryConfig struct {
+	Enabled  bool
+	Interval time.Duration
+}
+
+// ScrubberConfig is the configuration of the DataScrubber beyond the sensitive words
+type ScrubberConfig struct {
+	// DefaultRules enables the built-in rules, e.g. the JWT and URL credentials scrubbing
+	DefaultRules bool
+	Rules        []ScrubberRule
+	Entropy      EntropyDetection
+}
+
+// ProcessTagRule tags the processes whose command line and user match its patterns
+type ProcessTagRule struct {
+	Name string
+	// Cmdline is matched against the arguments of the command line joined by spaces, and User against
+	// the name of the user. A nil pattern matches every process, but a rule has at least one of them.
+	Cmdline *regexp.Regexp
+	User    *regexp.Regexp
+	Tags    []string
+}
+
+// ProcessConfig is the typed `process_config` section of the configuration.
+// Every key is read once by LoadProcessConfig, which applies the defaults,
+// validates the values and migrates the deprecated keys, so that the rest of
+// the process-agent doesn't need to read the configuration.
+type ProcessConfig struct {
+	Collection          CollectionMode
+	ProcessDiscovery    ProcessDiscoveryConfig
+	SoftwareInventory   SoftwareInventoryConfig
+	AdditionalEndpoints map[string][]string
+	LogFile             string
+	// CheckIntervals holds the interval of each check, defaults included
+	CheckIntervals        map[string]time.Duration
+	Blacklist             []*regexp.Regexp
+	ExpVarPort            int
+	CmdPort               int
+	ScrubArgs             bool
+	CustomSensitiveWords  []string
+	Scrubber              ScrubberConfig
+	TagRules              []ProcessTagRule
+	StripProcArguments    bool
+	QueueSize             int
+	RTQueueSize           int
+	ProcessQueueBytes     int
+	MaxPerMessage         int
+	MaxCtrProcsPerMessage int
+	DDAgentBin            string
+	GRPCConnectionTimeout time.Duration
+	Windows               WindowsConfig
+	InternalProfiling     bool
+	RemoteTagger          bool
+	ContainerSource       []string
+
+	// ZstdDictionary compresses the payloads with the zstd dictionary of the process payloads
+	ZstdDictionary bool
+	// SystemdUnit reads the systemd service running each process
+	SystemdUnit bool
+}
+
+// defaultProcessConfig returns the ProcessConfig used when `process_config` is empty
+func defaultProcessConfig() *ProcessConfig {
+	return &ProcessConfig{
+		Collection: CollectionDefault,
+		ProcessDiscovery: ProcessDiscoveryConfig{
+			Interval: ProcessDiscoveryCheckDefaultInterval,
+		},
+		SoftwareInventory: SoftwareInventoryConfig{
+			Interval: SoftwareInventoryDefaultInterval,
+		},
+		CheckIntervals: map[string]time.Duration{
+			ProcessCheckName:     ProcessCheckDefaultInterval,
+			RTProcessCheckName:   RTProcessCheckDefaultInterval,
+			ContainerCheckName:   ContainerCheckDefaultInterval,
+			RTContainerCheckName: RTContainerCheckDefaultInterval,
+			ConnectionsCheckName: ConnectionsCheckDefaultInterval,
+			PodCheckName:         PodCheckDefaultInterval,
+			DiscoveryCheckName:   ProcessDiscoveryCheckDefaultInterval,
+		},
+		ExpVarPort: 6062,
+		CmdPort:    6162,
+		ScrubArgs:  true,
+		// This can be fairly high as the input should get throttled by queue bytes first.
+		// Assuming we generate ~8 checks/minute (for process/network), this should allow buffering of ~30 minutes of data assuming it fits within the queue bytes memory budget
+		QueueSize: 256,
+		// We set a small queue size for real-time message queue because they get staled very quickly, thus we only keep the latest several payloads
+		RTQueueSize: 5,
+		// Allow buffering up to 75 megabytes of payload data in total
+		ProcessQueueBytes:     60 * 1000 * 1000,
+		MaxPerMessage:         maxMessageBatch,
+		MaxCtrProcsPerMessage: defaultMaxCtrProcsMessageBatch,
+		DDAgentBin:            defaultDDAgentBin,
+		GRPCConnectionTimeout: defaultGRPCConnectionTimeout,
+		Windows: WindowsConfig{
+			ArgsRefreshInterval: 15, // with default 20s check interval we refresh every 5m
+			AddNewArgs:          true,
+		},
+	}
+}
+
+// LoadProcessConfig reads the `process_config` section of cfg
+func LoadProcessConfig(cfg config.Config) (*ProcessConfig, error) {
+	p := defaultProcessConfig()
+
+	p.Collection = loadCollectionMode(cfg)
+	p.ProcessDiscovery = loadProcessDiscoveryConfig(cfg, p.Collection)
+	p.SoftwareInventory = loadSoftwareInventoryConfig(cfg)
+	p.ZstdDictionary = cfg.GetBool(key(ns, "zstd_dictionary", "enabled"))
+	p.SystemdUnit = cfg.GetBool(key(ns, "systemd_unit", "enabled"))
+
+	if k := key(ns, "additional_endpoints"); cfg.IsSet(k) {
+		p.AdditionalEndpoints = cfg.GetStringMapStringSlice(k)
+	}
+
+	// The full path to the file where process-agent logs will be written.
+	p.LogFile = cfg.GetString(key(ns, "log_file"))
+
+	// The interval, in seconds, at which we will run each check. If you want consistent
+	// behavior between real-time you may set the Container/ProcessRT intervals to 10.
+	// Defaults to 10s for normal checks and 2s for others.
+	for check, checkName := range map[string]string{
+		"container":          ContainerCheckName,
+		"container_realtime": RTContainerCheckName,
+		"process":            ProcessCheckName,
+		"process_realtime":   RTProcessCheckName,
+		"connections":        ConnectionsCheckName,
+	} {
+		k := key(ns, "intervals", check)
+		if !cfg.IsSet(k) {
+			continue
+		}
+		if interval := cfg.GetInt(k); interval != 0 {
+			log.Infof("Overriding %s check interval to %ds", checkName, interval)
+			p.CheckIntervals[checkName] = time.Duration(interval) * time.Second
+		}
+	}
+	if p.ProcessDiscovery.Enabled {
+		p.CheckIntervals[DiscoveryCheckName] = p.ProcessDiscovery.Interval
+	}
+
+	processInterval, rtProcessInterval := p.CheckIntervals[ProcessCheckName], p.CheckIntervals[RTProcessCheckName]
+	if processInterval < rtProcessInterval || processInterval%rtProcessInterval != 0 {
+		// Process check interval must be greater or equal to RTProcess check interval and the intervals must be divisible
+		// in order to be run on the same goroutine
+		log.Warnf(
+			"Invalid process check interval overrides [%s,%s], resetting to defaults [%s,%s]",
+			processInterval,
+			rtProcessInterval,
+			ProcessCheckDefaultInterval,
+			RTProcessCheckDefaultInterval,
+		)
+		p.CheckIntervals[ProcessCheckName] = ProcessCheckDefaultInterval
+		p.CheckIntervals[RTProcessCheckName] = RTProcessCheckDefaultInterval
+	}
+
+	// A list of regex patterns that will exclude a process if matched.
+	if k := key(ns, "blacklist_patterns"); cfg.IsSet(k) {
+		for _, b := range cfg.GetStringSlice(k) {
+			r, err := regexp.Compile(b)
+			if err != nil {
+				log.Warnf("Ignoring invalid blacklist pattern: %s", b)
+				continue
+			}
+			p.Blacklist = append(p.Blacklist, r)
+		}
+	}
+
+	if k := key(ns, "expvar_port"); cfg.IsSet(k) {
+		port := cfg.GetInt(k)
+		if port <= 0 {
+			return nil, fmt.Errorf("invalid %s -- %d", k, port)
+		}
+		p.ExpVarPort = port
+	}
+
+	if k := key(ns, "cmd_port"); cfg.IsSet(k) {
+		p.CmdPort = cfg.GetInt(k)
+	}
+
+	// Enable/Disable the DataScrubber to obfuscate process args
+	if k := key(ns, "scrub_args"); cfg.IsSet(k) {
+		p.ScrubArgs = cfg.GetBool(k)
+	}
+
+	// A custom word list to enhance the default one used by the DataScrubber
+	if k := key(ns, "custom_sensitive_words"); cfg.IsSet(k) {
+		p.CustomSensitiveWords = cfg.GetStringSlice(k)
+	}
+
+	// Regular expression rules and entropy detection used by the DataScrubber
+	p.Scrubber = loadScrubberConfig(cfg)
+
+	// Rules tagging the processes by command line and user
+	p.TagRules = loadProcessTagRules(cfg)
+
+	// Strips all process arguments
+	p.StripProcArguments = cfg.GetBool(key(ns, "strip_proc_arguments"))
+
+	// How many check results to buffer in memory when POST fails. The default is usually fine.
+	p.QueueSize = getPositiveInt(cfg, key(ns, "queue_size"), p.QueueSize)
+	p.RTQueueSize = getPositiveInt(cfg, key(ns, "rt_queue_size"), p.RTQueueSize)
+	p.ProcessQueueBytes = getPositiveInt(cfg, key(ns, "process_queue_bytes"), p.ProcessQueueBytes)
+
+	// The maximum number of processes, or containers per message. Note: Only change if the defaults are causing issues.
+	if k := key(ns, "max_per_message"); cfg.IsSet(k) {
+		if maxPerMessage := cfg.GetInt(k); maxPerMessage <= 0 {
+			log.Warn("Invalid item count per message (<= 0), ignoring...")
+		} else if maxPerMessage <= maxMessageBatch {
+			p.MaxPerMessage = maxPerMessage
+		} else {
+			log.Warn("Overriding the configured item count per message limit because it exceeds maximum")
+		}
+	}
+
+	// The maximum number of processes belonging to a container per message. Note: Only change if the defaults are causing issues.
+	if k := key(ns, "max_ctr_procs_per_message"); cfg.IsSet(k) {
+		if maxCtrProcessesPerMessage := cfg.GetInt(k); maxCtrProcessesPerMessage <= 0 {
+			log.Warnf("Invalid max container processes count per message (<= 0), using default value of %d", defaultMaxCtrProcsMessageBatch)
+		} else if maxCtrProcessesPerMessage <= maxCtrProcsMessageBatch {
+			p.MaxCtrProcsPerMessage = maxCtrProcessesPerMessage
+		} else {
+			log.Warnf("Overriding the configured max container processes count per message limit because it exceeds maximum limit of %d", maxCtrProcsMessageBatch)
+		}
+	}
+
+	// Overrides the path to the Agent bin used for getting the hostname. The default is usually fine.
+	if agentBin := cfg.GetString(key(ns, "dd_agent_bin")); agentBin != "" {
+		p.DDAgentBin = agentBin
+	}
+
+	// Overrides the grpc connection timeout setting to the main agent.
+	if k := key(ns, "grpc_connection_timeout_secs"); cfg.IsSet(k) {
+		p.GRPCConnectionTimeout = time.Duration(cfg.GetInt(k)) * time.Second
+	}
+
+	// Windows: Sets windows process table refresh rate (in number of check runs)
+	if argRefresh := cfg.GetInt(key(ns, "windows", "args_refresh_interval")); argRefresh != 0 {
+		p.Windows.ArgsRefreshInterval = argRefresh
+	}
+
+	// Windows: Controls getting process arguments immediately when a new process is discovered
+	if k := key(ns, "windows", "add_new_args"); cfg.IsSet(k) {
+		p.Windows.AddNewArgs = cfg.GetBool(k)
+	}
+
+	// Windows: Controls using the new check based on performance counters PDH APIs
+	if k := key(ns, "windows", "use_perf_counters"); cfg.IsSet(k) {
+		p.Windows.UsePerfCounters = cfg.GetBool(k)
+	}
+
+	// use `internal_profiling.enabled` field in `process_config` section to enable/disable profiling for process-agent
+	p.InternalProfiling = cfg.GetBool(key(ns, "internal_profiling.enabled"))
+
+	p.RemoteTagger = cfg.GetBool(key(ns, "remote_tagger"))
+
+	// Used to override container source auto-detection
+	// and to enable multiple collector sources if needed.
+	// "docker", "ecs_fargate", "kubelet", "kubelet docker", etc.
+	if k := key(ns, "container_source"); cfg.Get(k) != nil {
+		// container_source can be nil since we're not forcing default values in the main config file
+		// make sure we don't pass nil value to GetStringSlice to avoid spammy warnings
+		p.ContainerSource = cfg.GetStringSlice(k)
+	}
+
+	return p, nil
+}
+
+// loadCollectionMode returns what the process-agent collects. The
+// `process_collection.enabled` and `container_collection.enabled` keys take
+// precedence over the deprecated `enabled` key, the DD_PROCESS_AGENT_ENABLED
+// environment variable takes precedence over all of them.
+func loadCollectionMode(cfg config.Config) CollectionMode {
+	// Note: The enabled environment flag operates differently than that of our YAML configuration
+	if v, ok := os.LookupEnv("DD_PROCESS_AGENT_ENABLED"); ok {
+		// DD_PROCESS_AGENT_ENABLED: true - Process + Container checks enabled
+		//                           false - No checks enabled
+		//                           (none) - Container check enabled (by default)
+		enabled, err := isAffirmative(v)
+		switch {
+		case enabled:
+			return CollectionProcesses
+		case err == nil:
+			return CollectionDisabled
+		}
+		return CollectionDefault
+	}
+
+	processKey, containerKey := key(ns, "process_collection", "enabled"), key(ns, "container_collection", "enabled")
+	if cfg.IsSet(processKey) || cfg.IsSet(containerKey) {
+		switch {
+		case cfg.GetBool(processKey):
+			return CollectionProcesses
+		case cfg.GetBool(containerKey):
+			return CollectionContainers
+		}
+		return CollectionDisabled
+	}
+
+	return migrateEnabled(cfg)
+}
+
+// migrateEnabled returns the collection mode from the deprecated `enabled` key
+func migrateEnabled(cfg config.Config) CollectionMode {
+	k := key(ns, "enabled")
+	if !cfg.IsSet(k) {
+		return CollectionDefault
+	}
+
+	// A string indicate the enabled state of the Agent.
+	//   If "false" (the default) we will only collect containers.
+	//   If "true" we will collect containers and processes.
+	//   If "disabled" the agent will be disabled altogether and won't start.
+	enabled := cfg.GetString(k)
+	ok, err := isAffirmative(enabled)
+	switch {
+	case ok:
+		return CollectionProcesses
+	case enabled == "disabled":
+		return CollectionDisabled
+	case err == nil:
+		return CollectionContainers
+	}
+	return CollectionDefault
+}
+
+// loadProcessDiscoveryConfig returns the configuration of the process discovery check.
+// The check only runs when the containers only are collected, the processes
+// are already collected with their details otherwise.
+func loadProcessDiscoveryConfig(cfg config.Config, collection CollectionMode) ProcessDiscoveryConfig {
+	root := key(ns, "process_discovery")
+
+	discovery := ProcessDiscoveryConfig{
+		Enabled:  cfg.GetBool(key(root, "enabled")) && collection == CollectionContainers,
+		Interval: ProcessDiscoveryCheckDefaultInterval,
+	}
+	if !discovery.Enabled {
+		return discovery
+	}
+
+	// We don't need to check if the key exists since we already bound it to a default in InitConfig.
+	// We use a minimum of 10 minutes for this value.
+	discovery.Interval = cfg.GetDuration(key(root, "interval"))
+	if discovery.Interval < discoveryMinInterval {
+		discovery.Interval = discoveryMinInterval
+		_ = log.Warnf("Invalid interval for process discovery (<= %s) using default value of %[1]s", discoveryMinInterval.String())
+	}
+	return discovery
+}
+
+// loadSoftwareInventoryConfig returns the configuration of the software inventory.
+func loadSoftwareInventoryConfig(cfg config.Config) SoftwareInventoryConfig {
+	root := key(ns, "software_inventory")
+
+	inventory := SoftwareInventoryConfig{
+		Enabled:  cfg.GetBool(key(root, "enabled")),
+		Interval: SoftwareInventoryDefaultInterval,
+	}
+	if !inventory.Enabled {
+		return inventory
+	}
+
+	inventory.Interval = cfg.GetDuration(key(root, "interval"))
+	if inventory.Interval < softwareInventoryMinInterval {
+		inventory.Interval = softwareInventoryMinInterval
+		_ = log.Warnf("Invalid interval for the software inventory (<= %s) using default value of %[1]s", softwareInventoryMinInterval.String())
+	}
+	return inventory
+}
+
+func loadScrubberConfig(cfg config.Config) ScrubberConfig {
+	root := key(ns, "scrubber")
+
+	scrubber := ScrubberConfig{
+		DefaultRules: cfg.GetBool(key(root, "default_rules")),
+		Entropy: EntropyDetection{
+			Enabled:   cfg.GetBool(key(root, "entropy_detection", "enabled")),
+			MinLength: cfg.GetInt(key(root, "entropy_detection", "min_length")),
+			Threshold: cfg.GetFloat64(key(root, "entropy_detection", "threshold")),
+		},
+	}
+	if scrubber.Entropy.MinLength <= 0 {
+		_ = log.Warnf("Invalid %s (<= 0) using default value of %d", key(root, "entropy_detection", "min_length"), defaultEntropyMinLength)
+		scrubber.Entropy.MinLength = defaultEntropyMinLength
+	}
+	if scrubber.Entropy.Threshold <= 0 {
+		_ = log.Warnf("Invalid %s (<= 0) using default value of %v", key(root, "entropy_detection", "threshold"), defaultEntropyThreshold)
+		scrubber.Entropy.Threshold = defaultEntropyThreshold
+	}
+
+	var rules []struct {
+		Name        string `mapstructure:"name"`
+		Pattern     string `mapstructure:"pattern"`
+		Replacement string `mapstructure:"replacement"`
+	}
+	if err := cfg.UnmarshalKey(key(root, "rules"), &rules); err != nil {
+		_ = log.Warnf("Invalid %s, the custom scrubber rules are ignored: %v", key(root, "rules"), err)
+		return scrubber
+	}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("custom_%d", i)
+		}
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			_ = log.Warnf("Invalid pattern for the scrubber rule %s, the rule is ignored: %v", rule.Name, err)
+			continue
+		}
+		if rule.Replacement == "" {
+			rule.Replacement = scrubbedValue
+		}
+		scrubber.Rules = append(scrubber.Rules, ScrubberRule{
+			Name:        rule.Name,
+			Pattern:     pattern,
+			Replacement: rule.Replacement,
+		})
+	}
+	return scrubber
+}
+
+func loadProcessTagRules(cfg config.Config) []ProcessTagRule {
+	k := key(ns, "tag_rules")
+
+	var rules []struct {
+		Name    string   `mapstructure:"name"`
+		Cmdline string   `mapstructure:"cmdline"`
+		User    string   `mapstructure:"user"`
+		Tags    []string `mapstructure:"tags"`
+	}
+	if err := cfg.UnmarshalKey(k, &rules); err != nil {
+		_ = log.Warnf("Invalid %s, the process tag rules are ignored: %v", k, err)
+		return nil
+	}
+
+	var tagRules []ProcessTagRule
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("custom_%d", i)
+		}
+		if rule.Cmdline == "" && rule.User == "" {
+			_ = log.Warnf("No cmdline or user pattern for the process tag rule %s, the rule is ignored", rule.Name)
+			continue
+		}
+		if len(rule.Tags) == 0 {
+			_ = log.Warnf("No tags for the process tag rule %s, the rule is ignored", rule.Name)
+			continue
+		}
+
+		tagRule := ProcessTagRule{Name: rule.Name, Tags: rule.Tags}
+		var err error
+		if rule.Cmdline != "" {
+			if tagRule.Cmdline, err = regexp.Compile(rule.Cmdline); err != nil {
+				_ = log.Warnf("Invalid cmdline pattern for the process tag rule %s, the rule is ignored: %v", rule.Name, err)
+				continue
+			}
+		}
+		if rule.User != "" {
+			if tagRule.User, err = regexp.Compile(rule.User); err != nil {
+				_ = log.Warnf("Invalid user pattern for the process tag rule %s, the rule is ignored: %v", rule.Name, err)
+				continue
+			}
+		}
+		tagRules = append(tagRules, tagRule)
+	}
+	return tagRules
+}
+
+// getPositiveInt returns the value of k when it is set and positive, defaultValue otherwise
+func getPositiveInt(cfg config.Config, k string, defaultValue int) int {
+	if !cfg.IsSet(k) {
+		return defaultValue
+	}
+	if v := cfg.GetInt(k); v > 0 {
+		return v
+	}
+	return defaultValue
+}
+
+// String returns the name of the collection mode
+func (m CollectionMode) String() string {
+	switch m {
+	case CollectionDisabled:
+		return "disabled"
+	case CollectionContainers:
+		return "containers"
+	case CollectionProcesses:
+		return "processes"
+	}
+	return "default"
+}
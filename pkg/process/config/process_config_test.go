@@ -0,0 +1,266 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func newProcessConfigTest(values map[string]interface{}) config.Config {
+	cfg := config.NewConfig("datadog", "DD", strings.NewReplacer(".", "_"))
+	config.InitConfig(cfg)
+	for k, v := range values {
+		cfg.Set(k, v)
+	}
+	return cfg
+}
+
+func TestLoadProcessConfigDefaults(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(nil))
+	require.NoError(t, err)
+
+	expected := defaultProcessConfig()
+	assert.Equal(t, expected.CheckIntervals, p.CheckIntervals)
+	assert.Equal(t, expected.QueueSize, p.QueueSize)
+	assert.Equal(t, expected.RTQueueSize, p.RTQueueSize)
+	assert.Equal(t, expected.ProcessQueueBytes, p.ProcessQueueBytes)
+	assert.Equal(t, expected.MaxPerMessage, p.MaxPerMessage)
+	assert.Equal(t, expected.MaxCtrProcsPerMessage, p.MaxCtrProcsPerMessage)
+	assert.Equal(t, expected.DDAgentBin, p.DDAgentBin)
+	assert.Equal(t, expected.Windows, p.Windows)
+	assert.True(t, p.ScrubArgs)
+	assert.False(t, p.InternalProfiling)
+	assert.Empty(t, p.Blacklist)
+}
+
+func TestLoadProcessConfigCollectionMigration(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		values   map[string]interface{}
+		env      string
+		expected CollectionMode
+	}{
+		{
+			name:     "deprecated enabled true",
+			values:   map[string]interface{}{"process_config.enabled": "true"},
+			expected: CollectionProcesses,
+		},
+		{
+			name:     "deprecated enabled false",
+			values:   map[string]interface{}{"process_config.enabled": "false"},
+			expected: CollectionContainers,
+		},
+		{
+			name:     "deprecated enabled disabled",
+			values:   map[string]interface{}{"process_config.enabled": "disabled"},
+			expected: CollectionDisabled,
+		},
+		{
+			name: "process collection overrides deprecated enabled",
+			values: map[string]interface{}{
+				"process_config.enabled":                    "false",
+				"process_config.process_collection.enabled": true,
+			},
+			expected: CollectionProcesses,
+		},
+		{
+			name: "container collection only",
+			values: map[string]interface{}{
+				"process_config.enabled":                      "true",
+				"process_config.process_collection.enabled":   false,
+				"process_config.container_collection.enabled": true,
+			},
+			expected: CollectionContainers,
+		},
+		{
+			name: "both collections disabled",
+			values: map[string]interface{}{
+				"process_config.process_collection.enabled":   false,
+				"process_config.container_collection.enabled": false,
+			},
+			expected: CollectionDisabled,
+		},
+		{
+			name:     "environment variable takes precedence",
+			values:   map[string]interface{}{"process_config.process_collection.enabled": true},
+			env:      "false",
+			expected: CollectionDisabled,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.env != "" {
+				os.Setenv("DD_PROCESS_AGENT_ENABLED", tc.env)
+				defer os.Unsetenv("DD_PROCESS_AGENT_ENABLED")
+			}
+
+			p, err := LoadProcessConfig(newProcessConfigTest(tc.values))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, p.Collection, "got %s", p.Collection)
+		})
+	}
+}
+
+func TestLoadProcessConfigValidation(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.intervals.process":            3,
+		"process_config.intervals.process_realtime":   2,
+		"process_config.intervals.container":          20,
+		"process_config.max_per_message":              1000,
+		"process_config.max_ctr_procs_per_message":    -1,
+		"process_config.queue_size":                   0,
+		"process_config.blacklist_patterns":           []string{"^/usr/bin/.*", "(invalid"},
+		"process_config.grpc_connection_timeout_secs": 5,
+	}))
+	require.NoError(t, err)
+
+	// not a multiple of the real-time interval, reset to the defaults
+	assert.Equal(t, ProcessCheckDefaultInterval, p.CheckIntervals[ProcessCheckName])
+	assert.Equal(t, RTProcessCheckDefaultInterval, p.CheckIntervals[RTProcessCheckName])
+	assert.Equal(t, 20*time.Second, p.CheckIntervals[ContainerCheckName])
+
+	assert.Equal(t, maxMessageBatch, p.MaxPerMessage)
+	assert.Equal(t, defaultMaxCtrProcsMessageBatch, p.MaxCtrProcsPerMessage)
+	assert.Equal(t, 256, p.QueueSize)
+	assert.Len(t, p.Blacklist, 1)
+	assert.Equal(t, 5*time.Second, p.GRPCConnectionTimeout)
+
+	_, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.expvar_port": -1,
+	}))
+	assert.Error(t, err)
+}
+
+func TestLoadProcessConfigProcessDiscovery(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.enabled":                    "false",
+		"process_config.process_discovery.enabled":  true,
+		"process_config.process_discovery.interval": time.Minute,
+	}))
+	require.NoError(t, err)
+	assert.True(t, p.ProcessDiscovery.Enabled)
+	assert.Equal(t, discoveryMinInterval, p.ProcessDiscovery.Interval)
+	assert.Equal(t, discoveryMinInterval, p.CheckIntervals[DiscoveryCheckName])
+
+	// the processes are already collected
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.process_collection.enabled": true,
+		"process_config.process_discovery.enabled":  true,
+	}))
+	require.NoError(t, err)
+	assert.False(t, p.ProcessDiscovery.Enabled)
+}
+
+func TestLoadProcessConfigSoftwareInventory(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(nil))
+	require.NoError(t, err)
+	assert.False(t, p.SoftwareInventory.Enabled)
+
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.software_inventory.enabled":  true,
+		"process_config.software_inventory.interval": 2 * time.Hour,
+	}))
+	require.NoError(t, err)
+	assert.True(t, p.SoftwareInventory.Enabled)
+	assert.Equal(t, 2*time.Hour, p.SoftwareInventory.Interval)
+
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.software_inventory.enabled":  true,
+		"process_config.software_inventory.interval": time.Minute,
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, softwareInventoryMinInterval, p.SoftwareInventory.Interval)
+}
+
+func TestLoadProcessConfigZstdDictionary(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(nil))
+	require.NoError(t, err)
+	assert.False(t, p.ZstdDictionary)
+
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.zstd_dictionary.enabled": true,
+	}))
+	require.NoError(t, err)
+	assert.True(t, p.ZstdDictionary)
+}
+
+func TestLoadProcessConfigSystemdUnit(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(nil))
+	require.NoError(t, err)
+	assert.False(t, p.SystemdUnit)
+
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.systemd_unit.enabled": true,
+	}))
+	require.NoError(t, err)
+	assert.True(t, p.SystemdUnit)
+}
+
+func TestLoadProcessConfigScrubber(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(nil))
+	require.NoError(t, err)
+	assert.True(t, p.Scrubber.DefaultRules)
+	assert.Empty(t, p.Scrubber.Rules)
+	assert.Equal(t, EntropyDetection{
+		Enabled:   false,
+		MinLength: defaultEntropyMinLength,
+		Threshold: defaultEntropyThreshold,
+	}, p.Scrubber.Entropy)
+
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.scrubber.default_rules":                false,
+		"process_config.scrubber.entropy_detection.enabled":    true,
+		"process_config.scrubber.entropy_detection.min_length": 0,
+		"process_config.scrubber.entropy_detection.threshold":  4.0,
+		"process_config.scrubber.rules": []interface{}{
+			map[string]interface{}{"name": "vault_token", "pattern": `(VAULT_TOKEN=)s\.\w+`, "replacement": "${1}s.********"},
+			map[string]interface{}{"pattern": `sk_live_\w+`},
+			map[string]interface{}{"name": "invalid", "pattern": `(`},
+		},
+	}))
+	require.NoError(t, err)
+	assert.False(t, p.Scrubber.DefaultRules)
+	assert.Equal(t, EntropyDetection{
+		Enabled:   true,
+		MinLength: defaultEntropyMinLength,
+		Threshold: 4.0,
+	}, p.Scrubber.Entropy)
+	require.Len(t, p.Scrubber.Rules, 2)
+	assert.Equal(t, "vault_token", p.Scrubber.Rules[0].Name)
+	assert.Equal(t, "${1}s.********", p.Scrubber.Rules[0].Replacement)
+	assert.Equal(t, "custom_1", p.Scrubber.Rules[1].Name)
+	assert.Equal(t, "********", p.Scrubber.Rules[1].Replacement)
+}
+
+func TestLoadProcessConfigTagRules(t *testing.T) {
+	p, err := LoadProcessConfig(newProcessConfigTest(nil))
+	require.NoError(t, err)
+	assert.Empty(t, p.TagRules)
+
+	p, err = LoadProcessConfig(newProcessConfigTest(map[string]interface{}{
+		"process_config.tag_rules": []interface{}{
+			map[string]interface{}{"name": "kafka", "cmdline": `java .*kafka`, "tags": []interface{}{"service:kafka"}},
+			map[string]interface{}{"user": `^postgres$`, "tags": []interface{}{"service:postgres", "team:db"}},
+			map[string]interface{}{"name": "no_pattern", "tags": []interface{}{"service:none"}},
+			map[string]interface{}{"name": "no_tags", "cmdline": `nginx`},
+			map[string]interface{}{"name": "invalid", "cmdline": `(`, "tags": []interface{}{"service:invalid"}},
+		},
+	}))
+	require.NoError(t, err)
+	require.Len(t, p.TagRules, 2)
+
+	assert.Equal(t, "kafka", p.TagRules[0].Name)
+	assert.Equal(t, `java .*kafka`, p.TagRules[0].Cmdline.String())
+	assert.Nil(t, p.TagRules[0].User)
+	assert.Equal(t, []string{"service:kafka"}, p.TagRules[0].Tags)
+
+	assert.Equal(t, "custom_1", p.TagRules[1].Name)
+	assert.Nil(t, p.TagRules[1].Cmdline)
+	assert.Equal(t, `^postgres$`, p.TagRules[1].User.String())
+	assert.Equal(t, []string{"service:postgres", "team:db"}, p.TagRules[1].Tags)
+}
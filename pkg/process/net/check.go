@@ -48,6 +48,13 @@ func (r *RemoteSysProbeUtil) GetCheck(check string) (interface{}, error) {
 			return nil, err
 		}
 		return stats, nil
+	} else if check == "tcp_retransmit" {
+		var stats probe.TCPRetransmitStats
+		err = json.Unmarshal(body, &stats)
+		if err != nil {
+			return nil, err
+		}
+		return stats, nil
 	}
 
 	return nil, fmt.Errorf("Invalid check name: %s", check)
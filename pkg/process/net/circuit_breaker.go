@@ -0,0 +1,112 @@
+// +build linux windows
+
+package net
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/backoff"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// the requests are rejected for 1 to 2 seconds after the first error, up to 5 minutes after many errors
+	breakerBaseBackoff = 1
+	breakerMaxBackoff  = 300
+
+	statusTimeFormat = "2006-01-02 15:04:05"
+)
+
+// circuitBreaker stops sending requests to system-probe while it's down, and only logs when it goes down or
+// comes back instead of on every failed request
+type circuitBreaker struct {
+	sync.Mutex
+
+	policy  backoff.Policy
+	backoff int
+
+	errors      int
+	lastError   error
+	lastSuccess time.Time
+	retryAt     time.Time
+
+	now func() time.Time
+}
+
+func newCircuitBreaker() *circuitBreaker {
+	return &circuitBreaker{
+		policy: backoff.NewPolicy(2, breakerBaseBackoff, breakerMaxBackoff, 0, true),
+		now:    time.Now,
+	}
+}
+
+// allow returns an error if the request must not be sent. Once the backoff expires a single request is let
+// through, the others being rejected until it completes.
+func (b *circuitBreaker) allow() error {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.errors == 0 {
+		return nil
+	}
+
+	now := b.now()
+	if now.Before(b.retryAt) {
+		return fmt.Errorf("%w, retrying in %s: %v", ErrSystemProbeUnavailable, b.retryAt.Sub(now).Round(time.Second), b.lastError)
+	}
+	b.retryAt = now.Add(b.policy.GetBackoffDuration(b.backoff))
+	return nil
+}
+
+func (b *circuitBreaker) success() {
+	b.Lock()
+	defer b.Unlock()
+
+	if b.errors > 0 {
+		log.Infof("system-probe is available again after %d failed requests", b.errors)
+	}
+	b.errors = 0
+	b.backoff = b.policy.DecError(b.backoff)
+	b.lastError = nil
+	b.lastSuccess = b.now()
+	b.retryAt = time.Time{}
+}
+
+func (b *circuitBreaker) failure(err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.errors++
+	b.backoff = b.policy.IncError(b.backoff)
+	b.lastError = err
+
+	delay := b.policy.GetBackoffDuration(b.backoff)
+	b.retryAt = b.now().Add(delay)
+	if b.errors == 1 {
+		log.Warnf("system-probe is unavailable, requests are suspended for %s and then retried with an increasing delay: %v", delay.Round(time.Second), err)
+	} else {
+		log.Debugf("system-probe still unavailable after %d failed requests, retrying in %s: %v", b.errors, delay.Round(time.Second), err)
+	}
+}
+
+func (b *circuitBreaker) status() Status {
+	b.Lock()
+	defer b.Unlock()
+
+	status := Status{
+		State:             StateUnknown,
+		ConsecutiveErrors: b.errors,
+	}
+	if !b.lastSuccess.IsZero() {
+		status.State = StateConnected
+		status.LastSuccess = b.lastSuccess.Format(statusTimeFormat)
+	}
+	if b.errors > 0 {
+		status.State = StateUnavailable
+		status.LastError = b.lastError.Error()
+		status.RetryAt = b.retryAt.Format(statusTimeFormat)
+	}
+	return status
+}
@@ -0,0 +1,85 @@
+// +build linux windows
+
+package net
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker()
+	b.now = func() time.Time { return now }
+
+	assert.Equal(t, StateUnknown, b.status().State)
+	assert.NoError(t, b.allow())
+
+	b.failure(errors.New("connection refused"))
+	err := b.allow()
+	assert.True(t, errors.Is(err, ErrSystemProbeUnavailable))
+	assert.Contains(t, err.Error(), "connection refused")
+
+	status := b.status()
+	assert.Equal(t, StateUnavailable, status.State)
+	assert.Equal(t, 1, status.ConsecutiveErrors)
+	assert.Equal(t, "connection refused", status.LastError)
+
+	// a single request is let through once the backoff expired
+	now = now.Add(2 * time.Second)
+	assert.NoError(t, b.allow())
+	assert.Error(t, b.allow())
+
+	// the backoff increases with the consecutive errors
+	b.failure(errors.New("connection refused"))
+	firstRetryAt := b.retryAt
+	b.failure(errors.New("connection refused"))
+	assert.False(t, b.retryAt.Before(firstRetryAt))
+	assert.Equal(t, 3, b.status().ConsecutiveErrors)
+
+	b.success()
+	assert.NoError(t, b.allow())
+	status = b.status()
+	assert.Equal(t, StateConnected, status.State)
+	assert.Equal(t, 0, status.ConsecutiveErrors)
+	assert.Empty(t, status.LastError)
+	assert.Empty(t, status.RetryAt)
+}
+
+func TestRemoteSysProbeUtilCircuitBreaker(t *testing.T) {
+	statusCode := http.StatusInternalServerError
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	r := &RemoteSysProbeUtil{breaker: newCircuitBreaker()}
+	get := func() error {
+		req, err := http.NewRequest("GET", server.URL, nil)
+		require.NoError(t, err)
+		resp, err := r.do(req)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	// the server errors open the circuit, the next requests don't reach system-probe
+	assert.NoError(t, get())
+	assert.True(t, errors.Is(get(), ErrSystemProbeUnavailable))
+	assert.Equal(t, 1, requests)
+
+	r.breaker.retryAt = time.Now()
+	statusCode = http.StatusNotFound
+	assert.NoError(t, get())
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, StateConnected, r.breaker.status().State)
+}
@@ -39,6 +39,9 @@ var (
 	globalUtil       *RemoteSysProbeUtil
 	globalUtilOnce   sync.Once
 	globalSocketPath string
+
+	// globalBreaker is shared by the RemoteSysProbeUtil instances to report the status of the connection
+	globalBreaker = newCircuitBreaker()
 )
 
 // RemoteSysProbeUtil wraps interactions with a remote system probe service
@@ -48,6 +51,7 @@ type RemoteSysProbeUtil struct {
 
 	path       string
 	httpClient http.Client
+	breaker    *circuitBreaker
 }
 
 // SetSystemProbePath sets where the System probe is listening for connections
@@ -83,6 +87,13 @@ func GetRemoteSystemProbeUtil() (*RemoteSysProbeUtil, error) {
 	return globalUtil, nil
 }
 
+// GetStatus returns the health of the connection to system-probe
+func GetStatus() Status {
+	status := globalBreaker.status()
+	status.Path = globalSocketPath
+	return status
+}
+
 // GetProcStats returns a set of process stats by querying system-probe
 func (r *RemoteSysProbeUtil) GetProcStats(pids []int32) (*model.ProcStatsWithPermByPID, error) {
 	procReq := &pbgo.ProcessStatRequest{
@@ -101,7 +112,7 @@ func (r *RemoteSysProbeUtil) GetProcStats(pids []int32) (*model.ProcStatsWithPer
 
 	req.Header.Set("Accept", contentTypeProtobuf)
 	req.Header.Set("Content-Type", procEncoding.ContentTypeProtobuf)
-	resp, err := r.httpClient.Do(req)
+	resp, err := r.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -133,7 +144,7 @@ func (r *RemoteSysProbeUtil) GetConnections(clientID string) (*model.Connections
 	}
 
 	req.Header.Set("Accept", contentTypeProtobuf)
-	resp, err := r.httpClient.Do(req)
+	resp, err := r.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -165,15 +176,17 @@ func (r *RemoteSysProbeUtil) GetStats() (map[string]interface{}, error) {
 		return nil, err
 	}
 
-	resp, err := r.httpClient.Do(req)
+	resp, err := r.do(req)
 	if err != nil {
 		return nil, err
-	} else if resp.StatusCode != http.StatusOK {
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("conn request failed: Path %s, url: %s, status code: %d", r.path, statsURL, resp.StatusCode)
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
-	defer resp.Body.Close()
 	if err != nil {
 		return nil, err
 	}
@@ -188,28 +201,63 @@ func (r *RemoteSysProbeUtil) GetStats() (map[string]interface{}, error) {
 }
 
 func newSystemProbe() *RemoteSysProbeUtil {
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
 	return &RemoteSysProbeUtil{
 		path: globalSocketPath,
 		httpClient: http.Client{
 			Timeout: 10 * time.Second,
 			Transport: &http.Transport{
-				MaxIdleConns:    2,
-				IdleConnTimeout: 30 * time.Second,
-				DialContext: func(_ context.Context, _, _ string) (net.Conn, error) {
-					return net.Dial(netType, globalSocketPath)
+				// keep the connections open between the check runs, instead of dialing system-probe for each request
+				MaxIdleConns:        2,
+				MaxIdleConnsPerHost: 2,
+				IdleConnTimeout:     90 * time.Second,
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, netType, globalSocketPath)
 				},
 				TLSHandshakeTimeout:   1 * time.Second,
 				ResponseHeaderTimeout: 5 * time.Second,
 				ExpectContinueTimeout: 50 * time.Millisecond,
 			},
 		},
+		breaker: globalBreaker,
+	}
+}
+
+// do sends a request to system-probe, unless the previous requests failed and the backoff isn't expired. The
+// caller must close the body of the response for the connection to be reused.
+func (r *RemoteSysProbeUtil) do(req *http.Request) (*http.Response, error) {
+	if err := r.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := r.httpClient.Do(req)
+	r.record(resp, err)
+	return resp, err
+}
+
+// record reports the result of a request to the circuit breaker, the 4xx responses don't mean that
+// system-probe is down
+func (r *RemoteSysProbeUtil) record(resp *http.Response, err error) {
+	switch {
+	case err != nil:
+		r.breaker.failure(err)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		r.breaker.failure(fmt.Errorf("%s returned status code %d", resp.Request.URL, resp.StatusCode))
+	default:
+		r.breaker.success()
 	}
 }
 
 func (r *RemoteSysProbeUtil) init() error {
-	if resp, err := r.httpClient.Get(statsURL); err != nil {
+	// the initialization is already retried with a delay, it doesn't wait for the backoff of the circuit breaker
+	resp, err := r.httpClient.Get(statsURL)
+	r.record(resp, err)
+	if err != nil {
 		return err
-	} else if resp.StatusCode != http.StatusOK {
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("remote tracer status check failed: socket %s, url: %s, status code: %d", r.path, statsURL, resp.StatusCode)
 	}
 	return nil
@@ -39,3 +39,8 @@ func (r *RemoteSysProbeUtil) GetStats() (map[string]interface{}, error) {
 func (r *RemoteSysProbeUtil) GetProcStats(pids []int32) (*model.ProcStatsWithPermByPID, error) {
 	return nil, ebpf.ErrNotImplemented
 }
+
+// GetStatus is not supported
+func GetStatus() Status {
+	return Status{State: StateNotSupported}
+}
@@ -0,0 +1,29 @@
+package net
+
+import "errors"
+
+// ErrSystemProbeUnavailable is returned, without reaching system-probe, while the previous requests failed and
+// the backoff isn't expired
+var ErrSystemProbeUnavailable = errors.New("system-probe is unavailable")
+
+const (
+	// StateUnknown is the state of the system-probe connection before any request was sent
+	StateUnknown = "unknown"
+	// StateConnected is the state of the system-probe connection when the last request succeeded
+	StateConnected = "connected"
+	// StateUnavailable is the state of the system-probe connection when the requests fail, they are then
+	// rejected without reaching system-probe until the backoff expires
+	StateUnavailable = "unavailable"
+	// StateNotSupported is the state of the system-probe connection on the platforms system-probe doesn't run on
+	StateNotSupported = "not supported"
+)
+
+// Status is the health of the connection to system-probe, reported by the process-agent status
+type Status struct {
+	State             string `json:"state"`
+	Path              string `json:"path"`
+	ConsecutiveErrors int    `json:"consecutive_errors"`
+	LastError         string `json:"last_error,omitempty"`
+	LastSuccess       string `json:"last_success,omitempty"`
+	RetryAt           string `json:"retry_at,omitempty"`
+}
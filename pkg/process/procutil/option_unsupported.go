@@ -16,6 +16,12 @@ func WithPermission(enabled bool) Option {
 	return func(p Probe) {}
 }
 
+// WithSystemdUnit configures if process collection should read the systemd service
+// running each process from its cgroup
+func WithSystemdUnit(enabled bool) Option {
+	return func(p Probe) {}
+}
+
 // WithBootTimeRefreshInterval configures the boot time refresh interval
 func WithBootTimeRefreshInterval(bootTimeRefreshInterval time.Duration) Option {
 	return func(p Probe) {}
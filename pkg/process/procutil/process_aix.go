@@ -0,0 +1,38 @@
+// +build aix
+
+package procutil
+
+import (
+	"io/ioutil"
+	"path/filepath"
+)
+
+const (
+	cwdLink = "cwd"
+	// AIX doesn't expose the path of the executable in the procfs
+	exeLink = ""
+)
+
+// readPsinfo reads /proc/<pid>/psinfo
+func readPsinfo(pathForPID string) (*psinfo, error) {
+	data, err := readPsinfoFile(pathForPID)
+	if err != nil {
+		return nil, err
+	}
+	return parseAIXPsinfo(data)
+}
+
+// readCPUTimes reads /proc/<pid>/status, AIX doesn't report context switches per process
+func readCPUTimes(pathForPID string) (*cpuTimes, *NumCtxSwitchesStat, error) {
+	data, err := ioutil.ReadFile(filepath.Join(pathForPID, "status"))
+	if err != nil {
+		return nil, nil, err
+	}
+	times, err := parseAIXStatus(data)
+	return times, nil, err
+}
+
+// readCmdline returns nil: AIX has no cmdline file, the arguments are read from the psinfo
+func readCmdline(pathForPID string) []string {
+	return nil
+}
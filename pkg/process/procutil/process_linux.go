@@ -74,6 +74,16 @@ func WithPermission(enabled bool) Option {
 	}
 }
 
+// WithSystemdUnit configures if process collection should read the systemd service
+// running each process from its cgroup
+func WithSystemdUnit(enabled bool) Option {
+	return func(p Probe) {
+		if linuxProbe, ok := p.(*probe); ok {
+			linuxProbe.withSystemdUnit = enabled
+		}
+	}
+}
+
 // WithBootTimeRefreshInterval configures the boot time refresh interval
 func WithBootTimeRefreshInterval(bootTimeRefreshInterval time.Duration) Option {
 	return func(p Probe) {
@@ -96,6 +106,7 @@ type probe struct {
 	// configurations
 	withPermission          bool
 	returnZeroPermStats     bool
+	withSystemdUnit         bool
 	bootTimeRefreshInterval time.Duration
 }
 
@@ -230,15 +241,16 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 		}
 
 		proc := &Process{
-			Pid:     pid,                                       // /proc/[pid]
-			Ppid:    statInfo.ppid,                             // /proc/[pid]/stat
-			Cmdline: cmdline,                                   // /proc/[pid]/cmdline
-			Name:    statusInfo.name,                           // /proc/[pid]/status
-			Uids:    statusInfo.uids,                           // /proc/[pid]/status
-			Gids:    statusInfo.gids,                           // /proc/[pid]/status
-			Cwd:     p.getLinkWithAuthCheck(pathForPID, "cwd"), // /proc/[pid]/cwd, requires permission checks
-			Exe:     p.getLinkWithAuthCheck(pathForPID, "exe"), // /proc/[pid]/exe, requires permission checks
-			NsPid:   statusInfo.nspid,                          // /proc/[pid]/status
+			Pid:         pid,                                       // /proc/[pid]
+			Ppid:        statInfo.ppid,                             // /proc/[pid]/stat
+			Cmdline:     cmdline,                                   // /proc/[pid]/cmdline
+			Name:        statusInfo.name,                           // /proc/[pid]/status
+			Uids:        statusInfo.uids,                           // /proc/[pid]/status
+			Gids:        statusInfo.gids,                           // /proc/[pid]/status
+			Cwd:         p.getLinkWithAuthCheck(pathForPID, "cwd"), // /proc/[pid]/cwd, requires permission checks
+			Exe:         p.getLinkWithAuthCheck(pathForPID, "exe"), // /proc/[pid]/exe, requires permission checks
+			NsPid:       statusInfo.nspid,                          // /proc/[pid]/status
+			SystemdUnit: p.getSystemdUnit(pathForPID),              // /proc/[pid]/cgroup
 			Stats: &Stats{
 				CreateTime:  statInfo.createTime,    // /proc/[pid]/stat
 				Status:      statusInfo.status,      // /proc/[pid]/status
@@ -351,6 +363,50 @@ func (p *probe) getCmdline(pidPath string) []string {
 	return trimAndSplitBytes(cmdline)
 }
 
+// getSystemdUnit retrieves the systemd service of a process from the "cgroup" file in procfs,
+// when enabled
+func (p *probe) getSystemdUnit(pidPath string) string {
+	if !p.withSystemdUnit {
+		return ""
+	}
+	content, err := ioutil.ReadFile(filepath.Join(pidPath, "cgroup"))
+	if err != nil {
+		return ""
+	}
+	return parseSystemdUnit(content)
+}
+
+// parseSystemdUnit returns the innermost systemd service of the systemd cgroup hierarchy,
+// named "name=systemd" with cgroup v1 and the unified hierarchy with cgroup v2. Processes
+// in scopes, like containers and login sessions, have no systemd service.
+func parseSystemdUnit(content []byte) string {
+	var unifiedPath string
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if fields[1] == "name=systemd" {
+			return systemdUnitFromCgroupPath(fields[2])
+		}
+		if fields[0] == "0" && fields[1] == "" {
+			unifiedPath = fields[2]
+		}
+	}
+	return systemdUnitFromCgroupPath(unifiedPath)
+}
+
+func systemdUnitFromCgroupPath(path string) string {
+	components := strings.Split(path, "/")
+	for i := len(components) - 1; i >= 0; i-- {
+		if strings.HasSuffix(components[i], ".service") {
+			return components[i]
+		}
+	}
+	return ""
+}
+
 // parseIO retrieves io info from "io" file for a process in procfs
 func (p *probe) parseIO(pidPath string) *IOCountersStat {
 	path := filepath.Join(pidPath, "io")
@@ -613,6 +613,64 @@ func TestParseIOLine(t *testing.T) {
 	}
 }
 
+func TestParseSystemdUnit(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "cgroup v1",
+			content:  "12:memory:/system.slice/nginx.service\n1:name=systemd:/system.slice/nginx.service\n0::/system.slice/nginx.service\n",
+			expected: "nginx.service",
+		},
+		{
+			name:     "cgroup v2",
+			content:  "0::/system.slice/postgresql@13-main.service\n",
+			expected: "postgresql@13-main.service",
+		},
+		{
+			name:     "user service",
+			content:  "0::/user.slice/user-1000.slice/user@1000.service/app.slice/syncthing.service\n",
+			expected: "syncthing.service",
+		},
+		{
+			name:     "container",
+			content:  "1:name=systemd:/system.slice/docker-0123456789abcdef.scope\n0::/system.slice/docker-0123456789abcdef.scope\n",
+			expected: "",
+		},
+		{
+			name:     "login session",
+			content:  "0::/user.slice/user-1000.slice/session-2.scope\n",
+			expected: "",
+		},
+		{
+			name:     "init",
+			content:  "0::/init.scope\n",
+			expected: "",
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, parseSystemdUnit([]byte(tc.content)))
+		})
+	}
+}
+
+func TestGetSystemdUnit(t *testing.T) {
+	pidPath, err := ioutil.TempDir("", "systemd-unit")
+	require.NoError(t, err)
+	defer os.RemoveAll(pidPath)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(pidPath, "cgroup"), []byte("0::/system.slice/nginx.service\n"), 0644))
+
+	assert.Equal(t, "", (&probe{}).getSystemdUnit(pidPath))
+	assert.Equal(t, "nginx.service", (&probe{withSystemdUnit: true}).getSystemdUnit(pidPath))
+}
+
 func TestParseIOTestFS(t *testing.T) {
 	os.Setenv("HOST_PROC", "resources/test_procfs/proc/")
 	defer os.Unsetenv("HOST_PROC")
@@ -17,6 +17,8 @@ type Process struct {
 	Username string // (Windows only)
 	Uids     []int32
 	Gids     []int32
+	// SystemdUnit is the name of the systemd service running the process (Linux only)
+	SystemdUnit string
 
 	Stats *Stats
 }
@@ -24,13 +26,14 @@ type Process struct {
 // DeepCopy creates a deep copy of Process
 func (p *Process) DeepCopy() *Process {
 	copy := &Process{
-		Pid:      p.Pid,
-		Ppid:     p.Ppid,
-		NsPid:    p.NsPid,
-		Name:     p.Name,
-		Cwd:      p.Cwd,
-		Exe:      p.Exe,
-		Username: p.Username,
+		Pid:         p.Pid,
+		Ppid:        p.Ppid,
+		NsPid:       p.NsPid,
+		Name:        p.Name,
+		Cwd:         p.Cwd,
+		Exe:         p.Exe,
+		Username:    p.Username,
+		SystemdUnit: p.SystemdUnit,
 	}
 	copy.Cmdline = make([]string, len(p.Cmdline))
 	for i := range p.Cmdline {
@@ -0,0 +1,147 @@
+// +build solaris aix
+
+package procutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// probe is an implementation of the process probe for Solaris and AIX, which are not supported
+// by gopsutil. It reads the binary psinfo files of the procfs, system-probe isn't available on these platforms.
+type probe struct {
+	procRootLoc string
+}
+
+// NewProcessProbe returns a Probe object
+func NewProcessProbe(options ...Option) Probe {
+	p := &probe{
+		procRootLoc: util.HostProc(),
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Close cleans up everything related to Probe object
+func (p *probe) Close() {}
+
+// StatsForPIDs returns a map of stats info indexed by PID using the given PIDs
+func (p *probe) StatsForPIDs(pids []int32, now time.Time) (map[int32]*Stats, error) {
+	statsByPID := make(map[int32]*Stats, len(pids))
+	for _, pid := range pids {
+		pathForPID := filepath.Join(p.procRootLoc, strconv.Itoa(int(pid)))
+		info, err := readPsinfo(pathForPID)
+		if err != nil {
+			log.Debugf("Unable to read the psinfo of process %d: %s", pid, err)
+			continue
+		}
+		statsByPID[pid] = p.stats(pathForPID, info, now)
+	}
+	return statsByPID, nil
+}
+
+// ProcessesByPID returns a map of process info indexed by PID
+func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Process, error) {
+	pids, err := p.getActivePIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	procsByPID := make(map[int32]*Process, len(pids))
+	for _, pid := range pids {
+		pathForPID := filepath.Join(p.procRootLoc, strconv.Itoa(int(pid)))
+		info, err := readPsinfo(pathForPID)
+		if err != nil {
+			log.Debugf("Unable to read the psinfo of process %d: %s", pid, err)
+			continue
+		}
+
+		proc := info.toProcess(readCmdline(pathForPID))
+		if len(proc.Cmdline) == 0 {
+			// the agent's process check skips all the processes without cmdline
+			continue
+		}
+		proc.Cwd = readLink(pathForPID, cwdLink)
+		proc.Exe = readLink(pathForPID, exeLink)
+		proc.Stats = p.stats(pathForPID, info, now)
+		procsByPID[pid] = proc
+	}
+	return procsByPID, nil
+}
+
+// StatsWithPermByPID returns the stats that require elevated permission to collect for each process
+func (p *probe) StatsWithPermByPID(pids []int32) (map[int32]*StatsWithPerm, error) {
+	return nil, fmt.Errorf("StatsWithPermByPID is not implemented in this environment")
+}
+
+func (p *probe) stats(pathForPID string, info *psinfo, now time.Time) *Stats {
+	times, ctxSwitches, err := readCPUTimes(pathForPID)
+	if err != nil {
+		log.Debugf("Unable to read the CPU times of process %d: %s", info.pid, err)
+	}
+
+	stats := info.toStats(times, ctxSwitches, now)
+	stats.OpenFdCount = getFDCount(pathForPID)
+	return stats
+}
+
+func (p *probe) getActivePIDs() ([]int32, error) {
+	names, err := readDirNames(p.procRootLoc)
+	if err != nil {
+		return nil, err
+	}
+
+	pids := make([]int32, 0, len(names))
+	for _, name := range names {
+		pid, err := strconv.ParseInt(name, 10, 32)
+		if err != nil {
+			continue
+		}
+		pids = append(pids, int32(pid))
+	}
+	return pids, nil
+}
+
+func readPsinfoFile(pathForPID string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(pathForPID, "psinfo"))
+}
+
+// readLink returns the target of a link of the procfs, or an empty string when the agent isn't allowed to read it
+func readLink(pathForPID, name string) string {
+	if name == "" {
+		return ""
+	}
+	target, err := os.Readlink(filepath.Join(pathForPID, name))
+	if err != nil {
+		return ""
+	}
+	return target
+}
+
+// getFDCount returns the number of open file descriptors, or -1 when the agent isn't allowed to list them
+func getFDCount(pathForPID string) int32 {
+	names, err := readDirNames(filepath.Join(pathForPID, "fd"))
+	if err != nil {
+		return -1
+	}
+	return int32(len(names))
+}
+
+func readDirNames(path string) ([]string, error) {
+	d, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer d.Close()
+
+	return d.Readdirnames(-1)
+}
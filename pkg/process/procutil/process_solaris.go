@@ -0,0 +1,41 @@
+// +build solaris
+
+package procutil
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"path/filepath"
+)
+
+const (
+	cwdLink = "path/cwd"
+	exeLink = "path/a.out"
+)
+
+// readPsinfo reads /proc/<pid>/psinfo, Go only supports Solaris on amd64 which is little-endian
+func readPsinfo(pathForPID string) (*psinfo, error) {
+	data, err := readPsinfoFile(pathForPID)
+	if err != nil {
+		return nil, err
+	}
+	return parseSolarisPsinfo(data, binary.LittleEndian)
+}
+
+// readCPUTimes reads /proc/<pid>/usage
+func readCPUTimes(pathForPID string) (*cpuTimes, *NumCtxSwitchesStat, error) {
+	data, err := ioutil.ReadFile(filepath.Join(pathForPID, "usage"))
+	if err != nil {
+		return nil, nil, err
+	}
+	return parseSolarisUsage(data, binary.LittleEndian)
+}
+
+// readCmdline reads /proc/<pid>/cmdline, only available since Solaris 11.3.5
+func readCmdline(pathForPID string) []string {
+	data, err := ioutil.ReadFile(filepath.Join(pathForPID, "cmdline"))
+	if err != nil {
+		return nil
+	}
+	return splitCmdline(data)
+}
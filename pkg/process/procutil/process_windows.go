@@ -22,6 +22,8 @@ var (
 		pdhutil.CounterAllProcessPctUserTime,
 		pdhutil.CounterAllProcessPctPrivilegedTime,
 		pdhutil.CounterAllProcessWorkingSet,
+		pdhutil.CounterAllProcessWorkingSetPrivate,
+		pdhutil.CounterAllProcessPrivateBytes,
 		pdhutil.CounterAllProcessPoolPagedBytes,
 		pdhutil.CounterAllProcessThreadCount,
 		pdhutil.CounterAllProcessHandleCount,
@@ -32,6 +34,11 @@ var (
 	}
 )
 
+// minCollectionInterval is the minimum interval between two collections of the performance counters.
+// The rates computed by PDH over a shorter interval aren't meaningful and collecting the counters of
+// every process is expensive, the values of the previous collection are reused in the meantime.
+const minCollectionInterval = time.Second
+
 // NewProcessProbe returns a Probe object
 func NewProcessProbe(options ...Option) Probe {
 	p := &probe{}
@@ -49,6 +56,14 @@ type probe struct {
 
 	instanceToPID map[string]int32
 	procs         map[int32]*Process
+
+	// workingSetPrivate is the private working set of the processes, its shared part is
+	// computed from the total working set once all the counters are enumerated
+	workingSetPrivate map[int32]uint64
+	lastCollection    time.Time
+	// metaPending is true when new processes didn't get their parent PID yet, the counters
+	// are then collected even if the previous collection is too recent
+	metaPending bool
 }
 
 func (p *probe) init() {
@@ -89,6 +104,7 @@ func (p *probe) init() {
 	p.procs = make(map[int32]*Process)
 	p.initEnumSpecs()
 	p.instanceToPID = make(map[string]int32)
+	p.workingSetPrivate = make(map[int32]uint64)
 }
 
 type counterEnumSpec struct {
@@ -116,6 +132,14 @@ func (p *probe) initEnumSpecs() {
 			format:   pdhutil.PDH_FMT_LARGE,
 			enumFunc: valueToUint64(p.mapWorkingSet),
 		},
+		pdhutil.CounterAllProcessWorkingSetPrivate: {
+			format:   pdhutil.PDH_FMT_LARGE,
+			enumFunc: valueToUint64(p.mapWorkingSetPrivate),
+		},
+		pdhutil.CounterAllProcessPrivateBytes: {
+			format:   pdhutil.PDH_FMT_LARGE,
+			enumFunc: valueToUint64(p.mapPrivateBytes),
+		},
 		pdhutil.CounterAllProcessPoolPagedBytes: {
 			format:   pdhutil.PDH_FMT_LARGE,
 			enumFunc: valueToUint64(p.mapPoolPagedBytes),
@@ -167,7 +191,7 @@ func (p *probe) Close() {
 }
 
 func (p *probe) StatsForPIDs(pids []int32, now time.Time) (map[int32]*Stats, error) {
-	err := p.enumCounters(false, true)
+	err := p.enumCounters(false, true, now)
 	if err != nil {
 		return nil, err
 	}
@@ -212,6 +236,7 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 			Stats: &Stats{
 				CPUPercent:  &CPUPercentStat{},
 				MemInfo:     &MemoryInfoStat{},
+				MemInfoEx:   &MemoryInfoExStat{},
 				CtxSwitches: &NumCtxSwitchesStat{},
 				IORateStat:  &IOCountersRateStat{},
 			},
@@ -224,6 +249,7 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 		}
 
 		p.procs[pid] = proc
+		p.metaPending = true
 	}
 
 	for pid := range knownPids {
@@ -232,7 +258,7 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 		delete(p.procs, pid)
 	}
 
-	err = p.enumCounters(true, collectStats)
+	err = p.enumCounters(true, collectStats, now)
 	if err != nil {
 		return nil, err
 	}
@@ -245,11 +271,23 @@ func (p *probe) ProcessesByPID(now time.Time, collectStats bool) (map[int32]*Pro
 	return procsToReturn, nil
 }
 
-func (p *probe) enumCounters(collectMeta bool, collectStats bool) error {
-	// Reuse map's capacity across runs
+func (p *probe) enumCounters(collectMeta bool, collectStats bool, now time.Time) error {
+	if now.Sub(p.lastCollection) < minCollectionInterval {
+		if !collectMeta || !p.metaPending {
+			return nil
+		}
+		// the new processes need their parent PID, the stats of the previous collection are kept
+		collectStats = false
+	}
+	p.lastCollection = now
+
+	// Reuse maps' capacity across runs
 	for k := range p.instanceToPID {
 		delete(p.instanceToPID, k)
 	}
+	for k := range p.workingSetPrivate {
+		delete(p.workingSetPrivate, k)
+	}
 
 	status := pdhutil.PdhCollectQueryData(p.hQuery)
 	if status != 0 {
@@ -290,6 +328,13 @@ func (p *probe) enumCounters(collectMeta bool, collectStats bool) error {
 		}
 	}
 
+	if collectStats {
+		p.setProcMemShared()
+	}
+	if collectMeta {
+		p.metaPending = false
+	}
+
 	return nil
 }
 
@@ -415,6 +460,46 @@ func (p *probe) mapPoolPagedBytes(instance string, v uint64) {
 	p.mapToStatUint64(instance, v, p.setProcMemVMS)
 }
 
+func (p *probe) setProcWorkingSetPrivate(pid int32, stats *Stats, instance string, v uint64) {
+	if p.traceStats(pid) {
+		log.Tracef("Mem.WorkingSetPrivate[%s,pid=%d] %d", instance, pid, v)
+	}
+	p.workingSetPrivate[pid] = v
+}
+
+func (p *probe) mapWorkingSetPrivate(instance string, v uint64) {
+	p.mapToStatUint64(instance, v, p.setProcWorkingSetPrivate)
+}
+
+func (p *probe) setProcMemData(pid int32, stats *Stats, instance string, v uint64) {
+	if p.traceStats(pid) {
+		log.Tracef("Mem.Data[%s,pid=%d] %d", instance, pid, v)
+	}
+	stats.MemInfoEx.Data = v
+}
+
+func (p *probe) mapPrivateBytes(instance string, v uint64) {
+	p.mapToStatUint64(instance, v, p.setProcMemData)
+}
+
+// setProcMemShared sets the shared part of the working set of the processes, which isn't
+// exposed by a counter, from their total and private working sets
+func (p *probe) setProcMemShared() {
+	for pid, private := range p.workingSetPrivate {
+		proc, ok := p.procs[pid]
+		if !ok {
+			continue
+		}
+		memInfoEx := proc.Stats.MemInfoEx
+		memInfoEx.RSS = proc.Stats.MemInfo.RSS
+		memInfoEx.VMS = proc.Stats.MemInfo.VMS
+		memInfoEx.Shared = 0
+		if memInfoEx.RSS > private {
+			memInfoEx.Shared = memInfoEx.RSS - private
+		}
+	}
+}
+
 func (p *probe) setProcIOReadOpsRate(pid int32, stats *Stats, instance string, v float64) {
 	if p.traceStats(pid) {
 		log.Tracef("ReadRate[%s,pid=%d] %f", instance, pid, v)
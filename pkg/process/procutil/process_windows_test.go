@@ -3,9 +3,12 @@
 package procutil
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/DataDog/datadog-agent/pkg/util/winutil"
 )
@@ -79,3 +82,20 @@ func TestWindowsStringConversion(t *testing.T) {
 		assert.Equal(t, tc.expected, winutil.ConvertWindowsString16(tc.input))
 	}
 }
+
+func TestToolhelpProbeStats(t *testing.T) {
+	probe := NewWindowsToolhelpProbe()
+	defer probe.Close()
+
+	procs, err := probe.ProcessesByPID(time.Now(), true)
+	require.NoError(t, err)
+
+	proc, ok := procs[int32(os.Getpid())]
+	require.True(t, ok)
+	assert.Greater(t, proc.Stats.OpenFdCount, int32(0))
+	assert.Greater(t, proc.Stats.MemInfo.RSS, uint64(0))
+	require.NotNil(t, proc.Stats.MemInfoEx)
+	assert.Greater(t, proc.Stats.MemInfoEx.Data, uint64(0))
+	assert.GreaterOrEqual(t, proc.Stats.IOStat.ReadBytes, int64(0))
+	assert.GreaterOrEqual(t, proc.Stats.IOStat.WriteBytes, int64(0))
+}
@@ -148,50 +148,8 @@ func (p *windowsToolhelpProbe) ProcessesByPID(now time.Time, collectStats bool)
 
 		var stats *Stats
 		if collectStats {
-			var handleCount uint32
-			if err := getProcessHandleCount(procHandle, &handleCount); err != nil {
-				log.Debugf("could not get handle count for %v %v", pid, err)
-				continue
-			}
-
-			var pmemcounter process.PROCESS_MEMORY_COUNTERS
-			if err := getProcessMemoryInfo(procHandle, &pmemcounter); err != nil {
-				log.Debugf("could not get memory info for %v %v", pid, err)
-				continue
-			}
-
-			// shell out to getprocessiocounters for io stats
-			var ioCounters IO_COUNTERS
-			if err := getProcessIoCounters(procHandle, &ioCounters); err != nil {
-				log.Debugf("could not get IO Counters for %v %v", pid, err)
-				continue
-			}
-
-			utime := float64((int64(CPU.UserTime.HighDateTime) << 32) | int64(CPU.UserTime.LowDateTime))
-			stime := float64((int64(CPU.KernelTime.HighDateTime) << 32) | int64(CPU.KernelTime.LowDateTime))
-
-			stats = &Stats{
-				CreateTime:  ctime,
-				OpenFdCount: int32(handleCount),
-				NumThreads:  int32(pe32.CntThreads),
-				CPUTime: &CPUTimesStat{
-					User:      utime,
-					System:    stime,
-					Timestamp: time.Now().UnixNano(),
-				},
-				MemInfo: &MemoryInfoStat{
-					RSS:  uint64(pmemcounter.WorkingSetSize),
-					VMS:  uint64(pmemcounter.QuotaPagedPoolUsage),
-					Swap: 0,
-				},
-				IOStat: &IOCountersStat{
-					ReadCount:  int64(ioCounters.ReadOperationCount),
-					WriteCount: int64(ioCounters.WriteOperationCount),
-					ReadBytes:  int64(ioCounters.ReadTransferCount),
-					WriteBytes: int64(ioCounters.WriteTransferCount),
-				},
-				CtxSwitches: &NumCtxSwitchesStat{},
-			}
+			stats = collectToolhelpStats(pid, procHandle, &CPU)
+			stats.NumThreads = int32(pe32.CntThreads)
 		} else {
 			stats = &Stats{CreateTime: ctime}
 		}
@@ -215,6 +173,68 @@ func (p *windowsToolhelpProbe) ProcessesByPID(now time.Time, collectStats bool)
 	return procs, nil
 }
 
+// collectToolhelpStats collects the stats of a process from its handle. The process is still
+// reported when its handle count, memory or IO counters can't be read, e.g. for protected
+// processes, the unavailable handle count and IO counters are set to -1 like on Linux.
+func collectToolhelpStats(pid uint32, procHandle windows.Handle, CPU *windows.Rusage) *Stats {
+	utime := float64((int64(CPU.UserTime.HighDateTime) << 32) | int64(CPU.UserTime.LowDateTime))
+	stime := float64((int64(CPU.KernelTime.HighDateTime) << 32) | int64(CPU.KernelTime.LowDateTime))
+
+	stats := &Stats{
+		CreateTime:  CPU.CreationTime.Nanoseconds() / 1000000,
+		OpenFdCount: -1,
+		CPUTime: &CPUTimesStat{
+			User:      utime,
+			System:    stime,
+			Timestamp: time.Now().UnixNano(),
+		},
+		MemInfo: &MemoryInfoStat{},
+		IOStat: &IOCountersStat{
+			ReadCount:  -1,
+			WriteCount: -1,
+			ReadBytes:  -1,
+			WriteBytes: -1,
+		},
+		CtxSwitches: &NumCtxSwitchesStat{},
+	}
+
+	var handleCount uint32
+	if err := getProcessHandleCount(procHandle, &handleCount); err != nil {
+		log.Debugf("could not get handle count for %v %v", pid, err)
+	} else {
+		stats.OpenFdCount = int32(handleCount)
+	}
+
+	var pmemcounter process.PROCESS_MEMORY_COUNTERS
+	if err := getProcessMemoryInfo(procHandle, &pmemcounter); err != nil {
+		log.Debugf("could not get memory info for %v %v", pid, err)
+	} else {
+		stats.MemInfo.RSS = uint64(pmemcounter.WorkingSetSize)
+		stats.MemInfo.VMS = uint64(pmemcounter.QuotaPagedPoolUsage)
+		// the pagefile usage is the commit charge of the process, its private bytes
+		stats.MemInfoEx = &MemoryInfoExStat{
+			RSS:  stats.MemInfo.RSS,
+			VMS:  stats.MemInfo.VMS,
+			Data: uint64(pmemcounter.PagefileUsage),
+		}
+	}
+
+	// shell out to getprocessiocounters for io stats
+	var ioCounters IO_COUNTERS
+	if err := getProcessIoCounters(procHandle, &ioCounters); err != nil {
+		log.Debugf("could not get IO Counters for %v %v", pid, err)
+	} else {
+		stats.IOStat = &IOCountersStat{
+			ReadCount:  int64(ioCounters.ReadOperationCount),
+			WriteCount: int64(ioCounters.WriteOperationCount),
+			ReadBytes:  int64(ioCounters.ReadTransferCount),
+			WriteBytes: int64(ioCounters.WriteTransferCount),
+		}
+	}
+
+	return stats
+}
+
 type cachedProcess struct {
 	userName       string
 	executablePath string
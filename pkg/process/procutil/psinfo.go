@@ -0,0 +1,362 @@
+package procutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The /proc/<pid>/psinfo, /proc/<pid>/usage and /proc/<pid>/status files of Solaris and AIX are binary
+// dumps of C structures (see proc(4) and <sys/procfs.h>). The parsers below don't depend on the target OS
+// so that they can be tested on every platform.
+
+// psinfo holds the fields of the psinfo structure used by the process check
+type psinfo struct {
+	pid      int32
+	ppid     int32
+	uid      int32
+	euid     int32
+	gid      int32
+	egid     int32
+	nlwp     int32
+	sizeKB   uint64 // size of the process image
+	rssizeKB uint64 // resident set size
+	start    time.Time
+	fname    string // name of the executable
+	psargs   string // initial characters of the argument list
+	status   string
+	nice     int32
+}
+
+// cpuTimes holds the user and system CPU time of a process, in seconds
+type cpuTimes struct {
+	user   float64
+	system float64
+}
+
+// nzero is the default nice value on Solaris and AIX, where nice values range from 0 to 39
+const nzero = 20
+
+// solarisTimestruc is timestruc_t of the 64-bit data model
+type solarisTimestruc struct {
+	Sec  int64
+	Nsec int64
+}
+
+func (t solarisTimestruc) seconds() float64 {
+	return float64(t.Sec) + float64(t.Nsec)/1e9
+}
+
+// solarisLwpsinfo is the beginning of lwpsinfo_t, the remaining fields are not used
+type solarisLwpsinfo struct {
+	Flag  int32
+	Lwpid int32
+	Addr  uint64
+	Wchan uint64
+	Stype byte
+	State byte
+	Sname byte
+	Nice  byte
+}
+
+// solarisPsinfo is psinfo_t of the 64-bit data model
+type solarisPsinfo struct {
+	Flag     int32
+	Nlwp     int32
+	Pid      int32
+	Ppid     int32
+	Pgid     int32
+	Sid      int32
+	UID      uint32
+	EUID     uint32
+	GID      uint32
+	EGID     uint32
+	Addr     uint64
+	Size     uint64
+	Rssize   uint64
+	Pad1     uint64
+	Ttydev   uint64
+	Pctcpu   uint16
+	Pctmem   uint16
+	_        [4]byte
+	Start    solarisTimestruc
+	Time     solarisTimestruc
+	Ctime    solarisTimestruc
+	Fname    [16]byte
+	Psargs   [80]byte
+	Wstat    int32
+	Argc     int32
+	Argv     uint64
+	Envp     uint64
+	Dmodel   byte
+	_        [3]byte
+	Taskid   int32
+	Projid   int32
+	Nzomb    int32
+	Poolid   int32
+	Zoneid   int32
+	Contract int32
+	_        int32
+	Lwp      solarisLwpsinfo
+}
+
+// solarisPrusage is the beginning of prusage_t of the 64-bit data model, the remaining fields are not used
+type solarisPrusage struct {
+	Lwpid  int32
+	Count  int32
+	Tstamp solarisTimestruc
+	Create solarisTimestruc
+	Term   solarisTimestruc
+	Rtime  solarisTimestruc
+	Utime  solarisTimestruc
+	Stime  solarisTimestruc
+	// pr_ttime to pr_stoptime, then pr_filltime[6]
+	_     [14]solarisTimestruc
+	Minf  uint64
+	Majf  uint64
+	Nswap uint64
+	Inblk uint64
+	Oublk uint64
+	Msnd  uint64
+	Mrcv  uint64
+	Sigs  uint64
+	Vctx  uint64
+	Ictx  uint64
+}
+
+// solarisStatus converts the pr_sname state of a Solaris process
+var solarisStatus = map[byte]string{
+	'O': "R", // running on a processor
+	'R': "R",
+	'S': "S",
+	'T': "T",
+	'Z': "Z",
+	'W': "W", // waiting for a CPU cap
+}
+
+// parseSolarisPsinfo parses the content of /proc/<pid>/psinfo on Solaris
+func parseSolarisPsinfo(data []byte, order binary.ByteOrder) (*psinfo, error) {
+	var raw solarisPsinfo
+	if err := binary.Read(bytes.NewReader(data), order, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse psinfo: %w", err)
+	}
+
+	return &psinfo{
+		pid:      raw.Pid,
+		ppid:     raw.Ppid,
+		uid:      int32(raw.UID),
+		euid:     int32(raw.EUID),
+		gid:      int32(raw.GID),
+		egid:     int32(raw.EGID),
+		nlwp:     raw.Nlwp,
+		sizeKB:   raw.Size,
+		rssizeKB: raw.Rssize,
+		start:    time.Unix(raw.Start.Sec, raw.Start.Nsec),
+		fname:    cString(raw.Fname[:]),
+		psargs:   cString(raw.Psargs[:]),
+		status:   solarisStatus[raw.Lwp.Sname],
+		nice:     int32(raw.Lwp.Nice) - nzero,
+	}, nil
+}
+
+// parseSolarisUsage parses the content of /proc/<pid>/usage on Solaris
+func parseSolarisUsage(data []byte, order binary.ByteOrder) (*cpuTimes, *NumCtxSwitchesStat, error) {
+	var raw solarisPrusage
+	if err := binary.Read(bytes.NewReader(data), order, &raw); err != nil {
+		return nil, nil, fmt.Errorf("could not parse usage: %w", err)
+	}
+
+	return &cpuTimes{
+		user:   raw.Utime.seconds(),
+		system: raw.Stime.seconds(),
+	}, &NumCtxSwitchesStat{
+		Voluntary:   int64(raw.Vctx),
+		Involuntary: int64(raw.Ictx),
+	}, nil
+}
+
+// aixTimestruc is pr_timestruc64_t
+type aixTimestruc struct {
+	Sec  int64
+	Nsec int32
+	_    int32
+}
+
+func (t aixTimestruc) seconds() float64 {
+	return float64(t.Sec) + float64(t.Nsec)/1e9
+}
+
+// aixLwpsinfo is the beginning of lwpsinfo, the remaining fields are not used
+type aixLwpsinfo struct {
+	Lwpid uint64
+	Addr  uint64
+	Wchan uint64
+	Flag  uint32
+	Wtype byte
+	State byte
+	Sname byte
+	Nice  byte
+}
+
+// aixPsinfo is struct psinfo, which has the same layout for 32-bit and 64-bit processes
+type aixPsinfo struct {
+	Flag   uint32
+	Flag2  uint32
+	Nlwp   uint32
+	_      uint32
+	UID    uint64
+	EUID   uint64
+	GID    uint64
+	EGID   uint64
+	Pid    uint64
+	Ppid   uint64
+	Pgid   uint64
+	Sid    uint64
+	Ttydev uint64
+	Addr   uint64
+	Size   uint64
+	Rssize uint64
+	Start  aixTimestruc
+	Time   aixTimestruc
+	Cid    uint16
+	_      uint16
+	Argc   uint32
+	Argv   uint64
+	Envp   uint64
+	Fname  [16]byte
+	Psargs [80]byte
+	_      [8]uint64
+	Lwp    aixLwpsinfo
+}
+
+// aixPstatus is the beginning of struct pstatus, the remaining fields are not used
+type aixPstatus struct {
+	Flag    uint32
+	Flag2   uint32
+	Flags   uint32
+	Nlwp    uint32
+	Stat    byte
+	Dmodel  byte
+	_       [6]byte
+	Sigpend [4]uint64
+	Brkbase uint64
+	Brksize uint64
+	Stkbase uint64
+	Stksize uint64
+	Pid     uint64
+	Ppid    uint64
+	Pgid    uint64
+	Sid     uint64
+	Utime   aixTimestruc
+	Stime   aixTimestruc
+}
+
+// aixStatus converts the pr_sname state of an AIX process
+var aixStatus = map[byte]string{
+	'A': "R", // active
+	'R': "R",
+	'S': "S",
+	'I': "I",
+	'T': "T",
+	'Z': "Z",
+	'W': "W", // swapped
+}
+
+// parseAIXPsinfo parses the content of /proc/<pid>/psinfo on AIX
+func parseAIXPsinfo(data []byte) (*psinfo, error) {
+	var raw aixPsinfo
+	if err := binary.Read(bytes.NewReader(data), binary.BigEndian, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse psinfo: %w", err)
+	}
+
+	return &psinfo{
+		pid:      int32(raw.Pid),
+		ppid:     int32(raw.Ppid),
+		uid:      int32(raw.UID),
+		euid:     int32(raw.EUID),
+		gid:      int32(raw.GID),
+		egid:     int32(raw.EGID),
+		nlwp:     int32(raw.Nlwp),
+		sizeKB:   raw.Size,
+		rssizeKB: raw.Rssize,
+		start:    time.Unix(raw.Start.Sec, int64(raw.Start.Nsec)),
+		fname:    cString(raw.Fname[:]),
+		psargs:   cString(raw.Psargs[:]),
+		status:   aixStatus[raw.Lwp.Sname],
+		nice:     int32(raw.Lwp.Nice) - nzero,
+	}, nil
+}
+
+// parseAIXStatus parses the CPU times of the content of /proc/<pid>/status on AIX
+func parseAIXStatus(data []byte) (*cpuTimes, error) {
+	var raw aixPstatus
+	if err := binary.Read(bytes.NewReader(data), binary.BigEndian, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse status: %w", err)
+	}
+
+	return &cpuTimes{
+		user:   raw.Utime.seconds(),
+		system: raw.Stime.seconds(),
+	}, nil
+}
+
+// cString converts a NULL-terminated C string
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// splitCmdline converts the NULL-separated content of /proc/<pid>/cmdline
+func splitCmdline(data []byte) []string {
+	data = bytes.TrimRight(data, "\x00")
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(string(data), "\x00")
+}
+
+// toProcess converts the psinfo of a process, cmdline falls back to the truncated psargs when empty
+func (p *psinfo) toProcess(cmdline []string) *Process {
+	if len(cmdline) == 0 {
+		cmdline = strings.Fields(p.psargs)
+	}
+	return &Process{
+		Pid:     p.pid,
+		Ppid:    p.ppid,
+		Name:    p.fname,
+		Cmdline: cmdline,
+		Uids:    []int32{p.uid, p.euid},
+		Gids:    []int32{p.gid, p.egid},
+	}
+}
+
+// toStats converts the psinfo and the CPU times of a process
+func (p *psinfo) toStats(times *cpuTimes, ctxSwitches *NumCtxSwitchesStat, now time.Time) *Stats {
+	stats := &Stats{
+		CreateTime: p.start.UnixNano() / int64(time.Millisecond),
+		Status:     p.status,
+		Nice:       p.nice,
+		NumThreads: p.nlwp,
+		MemInfo: &MemoryInfoStat{
+			RSS: p.rssizeKB * 1024,
+			VMS: p.sizeKB * 1024,
+		},
+		MemInfoEx: &MemoryInfoExStat{
+			RSS: p.rssizeKB * 1024,
+			VMS: p.sizeKB * 1024,
+		},
+		CtxSwitches: ctxSwitches,
+	}
+	if times != nil {
+		stats.CPUTime = &CPUTimesStat{
+			User:      times.user,
+			System:    times.system,
+			Timestamp: now.Unix(),
+		}
+	}
+	return stats
+}
@@ -0,0 +1,131 @@
+package procutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encode(t *testing.T, order binary.ByteOrder, v interface{}) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, binary.Write(&buf, order, v))
+	return buf.Bytes()
+}
+
+func TestParseSolarisPsinfo(t *testing.T) {
+	raw := solarisPsinfo{
+		Nlwp:   4,
+		Pid:    1234,
+		Ppid:   1,
+		UID:    100,
+		EUID:   0,
+		GID:    10,
+		EGID:   0,
+		Size:   2048,
+		Rssize: 512,
+		Start:  solarisTimestruc{Sec: 1600000000, Nsec: 500000000},
+		Lwp:    solarisLwpsinfo{Sname: 'O', Nice: 20},
+	}
+	copy(raw.Fname[:], "sshd")
+	copy(raw.Psargs[:], "/usr/lib/ssh/sshd -D")
+
+	// the structure is 288 bytes long before the lwpsinfo
+	data := encode(t, binary.LittleEndian, raw)
+	assert.Equal(t, byte('O'), data[288+26])
+
+	info, err := parseSolarisPsinfo(data, binary.LittleEndian)
+	require.NoError(t, err)
+
+	proc := info.toProcess(nil)
+	assert.Equal(t, int32(1234), proc.Pid)
+	assert.Equal(t, int32(1), proc.Ppid)
+	assert.Equal(t, "sshd", proc.Name)
+	assert.Equal(t, []string{"/usr/lib/ssh/sshd", "-D"}, proc.Cmdline)
+	assert.Equal(t, []int32{100, 0}, proc.Uids)
+	assert.Equal(t, []int32{10, 0}, proc.Gids)
+
+	now := time.Unix(1600000100, 0)
+	stats := info.toStats(&cpuTimes{user: 1.5, system: 0.5}, nil, now)
+	assert.Equal(t, int64(1600000000500), stats.CreateTime)
+	assert.Equal(t, "R", stats.Status)
+	assert.Equal(t, int32(0), stats.Nice)
+	assert.Equal(t, int32(4), stats.NumThreads)
+	assert.Equal(t, uint64(512*1024), stats.MemInfo.RSS)
+	assert.Equal(t, uint64(2048*1024), stats.MemInfo.VMS)
+	assert.Equal(t, &CPUTimesStat{User: 1.5, System: 0.5, Timestamp: now.Unix()}, stats.CPUTime)
+}
+
+func TestParseSolarisUsage(t *testing.T) {
+	raw := solarisPrusage{
+		Utime: solarisTimestruc{Sec: 3, Nsec: 250000000},
+		Stime: solarisTimestruc{Sec: 1},
+		Vctx:  42,
+		Ictx:  7,
+	}
+	data := encode(t, binary.LittleEndian, raw)
+	// pr_utime and pr_vctx offsets in prusage_t
+	assert.Equal(t, byte(3), data[72])
+	assert.Equal(t, byte(42), data[392])
+
+	times, ctxSwitches, err := parseSolarisUsage(data, binary.LittleEndian)
+	require.NoError(t, err)
+	assert.Equal(t, &cpuTimes{user: 3.25, system: 1}, times)
+	assert.Equal(t, &NumCtxSwitchesStat{Voluntary: 42, Involuntary: 7}, ctxSwitches)
+}
+
+func TestParseAIXPsinfo(t *testing.T) {
+	raw := aixPsinfo{
+		Nlwp:   1,
+		Pid:    4242,
+		Ppid:   1,
+		UID:    202,
+		EUID:   202,
+		GID:    1,
+		EGID:   1,
+		Size:   100,
+		Rssize: 50,
+		Start:  aixTimestruc{Sec: 1600000000},
+		Lwp:    aixLwpsinfo{Sname: 'A', Nice: 24},
+	}
+	copy(raw.Fname[:], "java")
+	copy(raw.Psargs[:], "java -jar app.jar")
+
+	// the structure is 328 bytes long before the lwpsinfo
+	data := encode(t, binary.BigEndian, raw)
+	assert.Equal(t, byte('A'), data[328+30])
+
+	info, err := parseAIXPsinfo(data)
+	require.NoError(t, err)
+
+	proc := info.toProcess(nil)
+	assert.Equal(t, int32(4242), proc.Pid)
+	assert.Equal(t, "java", proc.Name)
+	assert.Equal(t, []string{"java", "-jar", "app.jar"}, proc.Cmdline)
+
+	stats := info.toStats(nil, nil, time.Now())
+	assert.Equal(t, "R", stats.Status)
+	assert.Equal(t, int32(4), stats.Nice)
+	assert.Nil(t, stats.CPUTime)
+}
+
+func TestParseAIXStatus(t *testing.T) {
+	raw := aixPstatus{
+		Utime: aixTimestruc{Sec: 10, Nsec: 100000000},
+		Stime: aixTimestruc{Sec: 2},
+	}
+	data := encode(t, binary.BigEndian, raw)
+
+	times, err := parseAIXStatus(data)
+	require.NoError(t, err)
+	assert.Equal(t, &cpuTimes{user: 10.1, system: 2}, times)
+}
+
+func TestPsinfoCmdline(t *testing.T) {
+	info := &psinfo{psargs: "truncated args"}
+	assert.Equal(t, []string{"/bin/app", "--flag", "value with spaces"}, info.toProcess(splitCmdline([]byte("/bin/app\x00--flag\x00value with spaces\x00"))).Cmdline)
+	assert.Equal(t, []string{"truncated", "args"}, info.toProcess(splitCmdline([]byte{})).Cmdline)
+}
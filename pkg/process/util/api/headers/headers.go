@@ -16,4 +16,8 @@ const (
 	ClusterIDHeader = "X-Dd-Orchestrator-ClusterID"
 	// TimestampHeader contains the timestamp that the check data was created
 	TimestampHeader = "X-DD-Agent-Timestamp"
+	// ContentEncodingHeader contains the compression of the whole payload, when it isn't part of the message encoding
+	ContentEncodingHeader = "Content-Encoding"
+	// ZstdDictionaryHeader contains the ID of the zstd dictionary the payload is compressed with
+	ZstdDictionaryHeader = "X-Dd-Zstd-Dictionary-Id"
 )
@@ -6,9 +6,14 @@
 package api
 
 import (
+	"encoding/binary"
 	"fmt"
+	"io"
 
 	model "github.com/DataDog/agent-payload/process"
+	zstd_0 "github.com/DataDog/zstd_0"
+	"github.com/gogo/protobuf/proto"
+
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 )
 
@@ -40,3 +45,63 @@ func EncodePayload(m model.MessageBody) ([]byte, error) {
 
 	return encoded, err
 }
+
+// messageHeaderV3 is the layout of the V3 header written by model.EncodeMessage
+type messageHeaderV3 struct {
+	Version        uint8
+	Encoding       uint8
+	Type           uint8
+	SubscriptionID uint8
+	OrgID          int32
+	Timestamp      int64
+}
+
+// EncodePayloadTo encodes a process message like EncodePayload, but streams the
+// compressed body to w instead of building the whole payload in memory.
+func EncodePayloadTo(w io.Writer, m model.MessageBody) error {
+	msgType, err := model.DetectMessageType(m)
+	if err != nil {
+		return fmt.Errorf("unable to detect message type: %s", err)
+	}
+
+	typeTag := "type:" + msgType.String()
+	tlmBytesIn.Add(float64(m.Size()), typeTag)
+
+	cw := &countingWriter{w: w}
+	header := messageHeaderV3{
+		Version:  uint8(model.MessageV3),
+		Encoding: uint8(model.MessageEncodingZstdPB),
+		Type:     uint8(msgType),
+	}
+	if err := binary.Write(cw, binary.LittleEndian, header); err != nil {
+		return fmt.Errorf("could not encode header: %s", err)
+	}
+
+	pb, err := proto.Marshal(m)
+	if err != nil {
+		return err
+	}
+	compressor := zstd_0.NewWriter(cw)
+	if _, err := compressor.Write(pb); err != nil {
+		compressor.Close()
+		return err
+	}
+	if err := compressor.Close(); err != nil {
+		return err
+	}
+
+	tlmBytesOut.Add(float64(cw.n), typeTag)
+	return nil
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
@@ -0,0 +1,154 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/zstd"
+	zstd_0 "github.com/DataDog/zstd_0"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util/api/headers"
+)
+
+// processDictionary is a zstd dictionary trained on the process payloads, see dictionaries/README.md
+//
+//go:embed dictionaries/process_v1.zdict
+var processDictionary []byte
+
+const (
+	// processDictionaryID is the ID of processDictionary, sent to the intake to decode the payloads
+	processDictionaryID = "1"
+	zstdContentEncoding = "zstd"
+)
+
+// PayloadEncoder encodes the messages sent to the process intake.
+//
+// With the zstd dictionary, a message is encoded in protobuf and the whole
+// payload is compressed with the dictionary: the intake decompresses it
+// according to the Content-Encoding and X-Dd-Zstd-Dictionary-Id headers.
+// Otherwise, the messages are encoded by EncodePayload.
+type PayloadEncoder struct {
+	// Set to 1 when the dictionary is used, updated with sync/atomic
+	useDictionary int32
+}
+
+// NewPayloadEncoder returns a new PayloadEncoder
+func NewPayloadEncoder(useDictionary bool) *PayloadEncoder {
+	e := &PayloadEncoder{}
+	if useDictionary {
+		e.useDictionary = 1
+	}
+	return e
+}
+
+// Encode encodes a message into a payload, and sets the headers describing its encoding
+func (e *PayloadEncoder) Encode(m model.MessageBody, extraHeaders http.Header) ([]byte, error) {
+	if atomic.LoadInt32(&e.useDictionary) == 0 {
+		return EncodePayload(m)
+	}
+
+	msgType, err := model.DetectMessageType(m)
+	if err != nil {
+		return nil, fmt.Errorf("unable to detect message type: %s", err)
+	}
+
+	typeTag := "type:" + msgType.String()
+	tlmBytesIn.Add(float64(m.Size()), typeTag)
+
+	encoded, err := model.EncodeMessage(model.Message{
+		Header: model.MessageHeader{
+			Version:  model.MessageV3,
+			Encoding: model.MessageEncodingProtobuf,
+			Type:     msgType,
+		}, Body: m})
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := compressWithDictionary(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	tlmBytesOut.Add(float64(len(compressed)), typeTag)
+	extraHeaders.Set(headers.ContentEncodingHeader, zstdContentEncoding)
+	extraHeaders.Set(headers.ZstdDictionaryHeader, processDictionaryID)
+
+	return compressed, nil
+}
+
+// DisableDictionary stops using the dictionary, when none of the intake endpoints
+// support it. It returns false if the dictionary was already disabled.
+func (e *PayloadEncoder) DisableDictionary() bool {
+	return atomic.CompareAndSwapInt32(&e.useDictionary, 1, 0)
+}
+
+// UsesDictionary returns whether a payload was compressed with the dictionary,
+// according to its headers
+func UsesDictionary(extraHeaders http.Header) bool {
+	return extraHeaders.Get(headers.ZstdDictionaryHeader) != ""
+}
+
+// EncodeWithoutDictionary re-encodes a payload compressed with the dictionary as
+// EncodePayload does, for the intake endpoints which don't support the dictionary.
+// The headers describing the dictionary compression are removed from a copy of
+// the payload headers.
+func EncodeWithoutDictionary(payload []byte, extraHeaders http.Header) ([]byte, http.Header, error) {
+	r := zstd.NewReaderDict(bytes.NewReader(payload), processDictionary)
+	encoded, err := ioutil.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not decompress the payload: %s", err)
+	}
+
+	// the message is encoded in protobuf: only its body is compressed by EncodePayload
+	var header messageHeaderV3
+	if err := binary.Read(bytes.NewReader(encoded), binary.LittleEndian, &header); err != nil {
+		return nil, nil, fmt.Errorf("could not read the message header: %s", err)
+	}
+	if header.Encoding != uint8(model.MessageEncodingProtobuf) {
+		return nil, nil, fmt.Errorf("unexpected message encoding: %d", header.Encoding)
+	}
+	header.Encoding = uint8(model.MessageEncodingZstdPB)
+	headerSize := binary.Size(header)
+	body, err := zstd_0.Compress(nil, encoded[headerSize:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(headerSize + len(body))
+	if err := binary.Write(&buf, binary.LittleEndian, header); err != nil {
+		return nil, nil, err
+	}
+	buf.Write(body)
+
+	fallbackHeaders := extraHeaders.Clone()
+	fallbackHeaders.Del(headers.ContentEncodingHeader)
+	fallbackHeaders.Del(headers.ZstdDictionaryHeader)
+	return buf.Bytes(), fallbackHeaders, nil
+}
+
+func compressWithDictionary(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zstd.NewWriterLevelDict(&buf, zstd.DefaultCompression, processDictionary)
+	if _, err := w.Write(payload); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
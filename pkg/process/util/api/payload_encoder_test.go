@@ -0,0 +1,86 @@
+package api
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/DataDog/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util/api/headers"
+)
+
+func testProcessMessage() *model.CollectorProc {
+	return &model.CollectorProc{
+		HostName: "ip-10-0-0-1.ec2.internal",
+		Processes: []*model.Process{
+			{Pid: 1, Command: &model.Command{Args: []string{"/sbin/init"}}},
+			{Pid: 42, Command: &model.Command{Args: []string{"/usr/local/bin/kubelet", "--config=/var/lib/kubelet/config.yaml"}}},
+		},
+	}
+}
+
+func TestPayloadEncoderWithDictionary(t *testing.T) {
+	e := NewPayloadEncoder(true)
+	extraHeaders := make(http.Header)
+
+	payload, err := e.Encode(testProcessMessage(), extraHeaders)
+	require.NoError(t, err)
+	assert.Equal(t, "zstd", extraHeaders.Get(headers.ContentEncodingHeader))
+	assert.Equal(t, processDictionaryID, extraHeaders.Get(headers.ZstdDictionaryHeader))
+
+	r := zstd.NewReaderDict(bytes.NewReader(payload), processDictionary)
+	defer r.Close()
+	decompressed, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	msg, err := model.DecodeMessage(decompressed)
+	require.NoError(t, err)
+	assert.Equal(t, model.MessageEncodingProtobuf, msg.Header.Encoding)
+	assert.Equal(t, model.TypeCollectorProc, msg.Header.Type)
+	assert.Equal(t, testProcessMessage(), msg.Body)
+}
+
+func TestPayloadEncoderDisableDictionary(t *testing.T) {
+	e := NewPayloadEncoder(true)
+	assert.True(t, e.DisableDictionary())
+	assert.False(t, e.DisableDictionary())
+
+	extraHeaders := make(http.Header)
+	payload, err := e.Encode(testProcessMessage(), extraHeaders)
+	require.NoError(t, err)
+	assert.Empty(t, extraHeaders)
+
+	// the payload is encoded as without the dictionary
+	msg, err := model.DecodeMessage(payload)
+	require.NoError(t, err)
+	assert.Equal(t, model.MessageEncodingZstdPB, msg.Header.Encoding)
+	assert.Equal(t, testProcessMessage(), msg.Body)
+}
+
+func TestEncodeWithoutDictionary(t *testing.T) {
+	e := NewPayloadEncoder(true)
+	extraHeaders := make(http.Header)
+	extraHeaders.Set(headers.HostHeader, "ip-10-0-0-1.ec2.internal")
+
+	payload, err := e.Encode(testProcessMessage(), extraHeaders)
+	require.NoError(t, err)
+	assert.True(t, UsesDictionary(extraHeaders))
+
+	fallback, fallbackHeaders, err := EncodeWithoutDictionary(payload, extraHeaders)
+	require.NoError(t, err)
+	assert.False(t, UsesDictionary(fallbackHeaders))
+	assert.Empty(t, fallbackHeaders.Get(headers.ContentEncodingHeader))
+	assert.Equal(t, "ip-10-0-0-1.ec2.internal", fallbackHeaders.Get(headers.HostHeader))
+	// the headers of the payload compressed with the dictionary are left untouched
+	assert.True(t, UsesDictionary(extraHeaders))
+
+	// the payload is encoded as without the dictionary
+	expected, err := EncodePayload(testProcessMessage())
+	require.NoError(t, err)
+	assert.Equal(t, expected, fallback)
+}
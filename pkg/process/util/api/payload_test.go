@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package api
+
+import (
+	"bytes"
+	"testing"
+
+	model "github.com/DataDog/agent-payload/process"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodePayloadTo(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, EncodePayloadTo(&buf, testProcessMessage()))
+
+	// the header is the same as the one of EncodePayload
+	encoded, err := EncodePayload(testProcessMessage())
+	require.NoError(t, err)
+	assert.Equal(t, encoded[:16], buf.Bytes()[:16])
+
+	msg, err := model.DecodeMessage(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, model.MessageEncodingZstdPB, msg.Header.Encoding)
+	assert.Equal(t, model.TypeCollectorProc, msg.Header.Type)
+	assert.Equal(t, testProcessMessage(), msg.Body)
+}
@@ -197,6 +197,16 @@ func Decrypt(data []byte, origin string) ([]byte, error) {
 	return finalConfig, nil
 }
 
+// Fetch fetches the given secret handles by executing "secret_backend_command",
+// without looking them up in the cache so that the rotated secrets are returned.
+// The cache is updated with the fetched secrets.
+func Fetch(handles []string, origin string) (map[string]string, error) {
+	if secretBackendCommand == "" {
+		return nil, fmt.Errorf("'secret_backend_command' is not configured")
+	}
+	return secretFetcher(handles, origin)
+}
+
 // GetDebugInfo exposes debug informations about secrets to be included in a flare
 func GetDebugInfo() (*SecretInfo, error) {
 	if secretBackendCommand == "" {
@@ -301,6 +301,41 @@ func TestDecryptSecretFullCache(t *testing.T) {
 	assert.Equal(t, testConfDecrypted, newConf)
 }
 
+func TestFetchNoCommand(t *testing.T) {
+	defer func() { secretFetcher = fetchSecret }()
+	secretFetcher = func(secrets []string, origin string) (map[string]string, error) {
+		require.Fail(t, "No secret should be fetched without command")
+		return nil, nil
+	}
+
+	_, err := Fetch([]string{"pass1"}, "test")
+	require.NotNil(t, err)
+}
+
+func TestFetchBypassCache(t *testing.T) {
+	secretBackendCommand = "some_command"
+	defer func() { secretBackendCommand = "" }()
+
+	secretCache["pass1"] = "password1"
+	secretOrigin["pass1"] = common.NewStringSet("previous_test")
+	defer func() {
+		secretCache = map[string]string{}
+		secretOrigin = map[string]common.StringSet{}
+		secretFetcher = fetchSecret
+	}()
+
+	secretFetcher = func(secrets []string, origin string) (map[string]string, error) {
+		assert.Equal(t, []string{"pass1"}, secrets)
+		return map[string]string{
+			"pass1": "rotated_password1",
+		}, nil
+	}
+
+	secrets, err := Fetch([]string{"pass1"}, "test")
+	require.Nil(t, err)
+	assert.Equal(t, map[string]string{"pass1": "rotated_password1"}, secrets)
+}
+
 func TestDebugInfo(t *testing.T) {
 	secretBackendCommand = "some_command"
 
@@ -30,6 +30,10 @@ type Config struct {
 	RuntimeEnabled bool
 	// PoliciesDir defines the folder in which the policy files are located
 	PoliciesDir string
+	// PoliciesBundlePublicKeyFile defines the public key file used to verify the signature of the policies bundles
+	PoliciesBundlePublicKeyFile string
+	// EnforceSignedPoliciesBundles defines if only the policies of signed bundles should be loaded
+	EnforceSignedPoliciesBundles bool
 	// EnableKernelFilters defines if in-kernel filtering should be activated or not
 	EnableKernelFilters bool
 	// EnableApprovers defines if in-kernel approvers should be activated or not
@@ -72,6 +76,10 @@ type Config struct {
 	FIMEnabled bool
 	// CustomSensitiveWords defines words to add to the scrubber
 	CustomSensitiveWords []string
+	// EventOutputIncludeFields defines the only fields of the events forwarded to Datadog, all of them when empty
+	EventOutputIncludeFields []string
+	// EventOutputExcludeFields defines the fields removed from the events forwarded to Datadog
+	EventOutputExcludeFields []string
 	// ERPCDentryResolutionEnabled determines if the ERPC dentry resolution is enabled
 	ERPCDentryResolutionEnabled bool
 	// MapDentryResolutionEnabled determines if the map resolution is enabled
@@ -88,6 +96,14 @@ type Config struct {
 	SelfTestEnabled bool
 	// EnableRemoteConfig defines if configuration should be fetched from the backend
 	EnableRemoteConfig bool
+	// DomainResolutionEnabled defines if the DNS responses are snooped to resolve the destinations of the connect events to domains
+	DomainResolutionEnabled bool
+	// ConfinementDriftEnabled defines if the confinement of the container processes is compared to the one declared by their pod spec
+	ConfinementDriftEnabled bool
+	// UserGroupNSSLookups defines if the ids missing from the host user and group files are looked up with NSS
+	UserGroupNSSLookups bool
+	// UserGroupCacheTTL defines how long the user and group files and the NSS lookups are cached
+	UserGroupCacheTTL time.Duration
 }
 
 // IsEnabled returns true if any feature is enabled. Has to be applied in config package too
@@ -108,6 +124,8 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		SocketPath:                         aconfig.Datadog.GetString("runtime_security_config.socket"),
 		SyscallMonitor:                     aconfig.Datadog.GetBool("runtime_security_config.syscall_monitor.enabled"),
 		PoliciesDir:                        aconfig.Datadog.GetString("runtime_security_config.policies.dir"),
+		PoliciesBundlePublicKeyFile:        aconfig.Datadog.GetString("runtime_security_config.policies.bundle_public_key_file"),
+		EnforceSignedPoliciesBundles:       aconfig.Datadog.GetBool("runtime_security_config.policies.enforce_signed_bundles"),
 		EventServerBurst:                   aconfig.Datadog.GetInt("runtime_security_config.event_server.burst"),
 		EventServerRate:                    aconfig.Datadog.GetInt("runtime_security_config.event_server.rate"),
 		EventServerRetention:               aconfig.Datadog.GetInt("runtime_security_config.event_server.retention"),
@@ -121,6 +139,8 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		StatsdAddr:                         fmt.Sprintf("%s:%d", cfg.StatsdHost, cfg.StatsdPort),
 		AgentMonitoringEvents:              aconfig.Datadog.GetBool("runtime_security_config.agent_monitoring_events"),
 		CustomSensitiveWords:               aconfig.Datadog.GetStringSlice("runtime_security_config.custom_sensitive_words"),
+		EventOutputIncludeFields:           aconfig.Datadog.GetStringSlice("runtime_security_config.event_output.include_fields"),
+		EventOutputExcludeFields:           aconfig.Datadog.GetStringSlice("runtime_security_config.event_output.exclude_fields"),
 		ERPCDentryResolutionEnabled:        aconfig.Datadog.GetBool("runtime_security_config.erpc_dentry_resolution_enabled"),
 		MapDentryResolutionEnabled:         aconfig.Datadog.GetBool("runtime_security_config.map_dentry_resolution_enabled"),
 		DentryCacheSize:                    aconfig.Datadog.GetInt("runtime_security_config.dentry_cache_size"),
@@ -128,6 +148,10 @@ func NewConfig(cfg *config.Config) (*Config, error) {
 		LogPatterns:                        aconfig.Datadog.GetStringSlice("runtime_security_config.log_patterns"),
 		SelfTestEnabled:                    aconfig.Datadog.GetBool("runtime_security_config.self_test.enabled"),
 		EnableRemoteConfig:                 aconfig.Datadog.GetBool("runtime_security_config.enable_remote_configuration"),
+		DomainResolutionEnabled:            aconfig.Datadog.GetBool("runtime_security_config.network.domain_resolution.enabled"),
+		ConfinementDriftEnabled:            aconfig.Datadog.GetBool("runtime_security_config.confinement_drift.enabled"),
+		UserGroupNSSLookups:                aconfig.Datadog.GetBool("runtime_security_config.user_group_resolution.nss_lookups"),
+		UserGroupCacheTTL:                  time.Duration(aconfig.Datadog.GetInt("runtime_security_config.user_group_resolution.cache_ttl")) * time.Second,
 	}
 
 	// if runtime is enabled then we force fim
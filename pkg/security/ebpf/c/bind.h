@@ -0,0 +1,79 @@
+#ifndef _BIND_H_
+#define _BIND_H_
+
+#include "syscalls.h"
+#include "sockaddr.h"
+
+struct bind_event_t {
+    struct kevent_t event;
+    struct process_context_t process;
+    struct span_context_t span;
+    struct container_context_t container;
+    struct syscall_t syscall;
+    struct sockaddr_t addr;
+};
+
+SYSCALL_KPROBE0(bind) {
+    struct policy_t policy = fetch_policy(EVENT_BIND);
+    if (is_discarded_by_process(policy.mode, EVENT_BIND)) {
+        return 0;
+    }
+
+    struct syscall_cache_t syscall = {
+        .type = EVENT_BIND,
+        .policy = policy,
+    };
+
+    cache_syscall(&syscall);
+    return 0;
+}
+
+SEC("kprobe/security_socket_bind")
+int kprobe_security_socket_bind(struct pt_regs *ctx) {
+    struct syscall_cache_t *syscall = peek_syscall(EVENT_BIND);
+    if (!syscall)
+        return 0;
+
+    read_sockaddr((struct sockaddr *)PT_REGS_PARM2(ctx), &syscall->bind.addr);
+    return 0;
+}
+
+int __attribute__((always_inline)) sys_bind_ret(void *ctx, int retval) {
+    struct syscall_cache_t *syscall = pop_syscall(EVENT_BIND);
+    if (!syscall)
+        return 0;
+
+    // only the IPv4 and IPv6 sockets are reported
+    if (!is_inet_family(syscall->bind.addr.family))
+        return 0;
+
+    struct bind_event_t event = {
+        .syscall.retval = retval,
+        .addr = syscall->bind.addr,
+    };
+
+    struct proc_cache_t *entry = fill_process_context(&event.process);
+    fill_container_context(entry, &event.container);
+    fill_span_context(&event.span);
+
+    send_event(ctx, EVENT_BIND, event);
+
+    return 0;
+}
+
+SEC("tracepoint/syscalls/sys_exit_bind")
+int tracepoint_syscalls_sys_exit_bind(struct tracepoint_syscalls_sys_exit_t *args) {
+    return sys_bind_ret(args, args->ret);
+}
+
+SYSCALL_KRETPROBE(bind) {
+    int retval = PT_REGS_RC(ctx);
+    return sys_bind_ret(ctx, retval);
+}
+
+SEC("tracepoint/handle_sys_bind_exit")
+int tracepoint_handle_sys_bind_exit(struct tracepoint_raw_syscalls_sys_exit_t *args) {
+    return sys_bind_ret(args, args->ret);
+}
+
+#endif
@@ -212,6 +212,8 @@ enum event_type
     EVENT_ARGS_ENVS,
     EVENT_MOUNT_RELEASED,
     EVENT_SELINUX,
+    EVENT_CONNECT,
+    EVENT_BIND,
     EVENT_MAX, // has to be the last one
 };
 
@@ -38,6 +38,8 @@
 #include "erpc.h"
 #include "ioctl.h"
 #include "selinux.h"
+#include "connect.h"
+#include "bind.h"
 #include "raw_syscalls.h"
 
 struct invalidate_dentry_event_t {
@@ -0,0 +1,43 @@
+#ifndef _SOCKADDR_H_
+#define _SOCKADDR_H_
+
+#include <linux/socket.h>
+#include <linux/in.h>
+#include <linux/in6.h>
+
+#include "bpf_endian.h"
+
+struct sockaddr_t {
+    u64 addr[2];
+    u16 family;
+    u16 port;
+    u32 padding;
+};
+
+int __attribute__((always_inline)) is_inet_family(u16 family) {
+    return family == AF_INET || family == AF_INET6;
+}
+
+// read_sockaddr copies the family, the port and the IP address of an IPv4 or IPv6 socket address
+void __attribute__((always_inline)) read_sockaddr(struct sockaddr *address, struct sockaddr_t *dst) {
+    bpf_probe_read(&dst->family, sizeof(dst->family), &address->sa_family);
+
+    u16 port = 0;
+    switch (dst->family) {
+    case AF_INET: {
+        struct sockaddr_in *addr_in = (struct sockaddr_in *)address;
+        bpf_probe_read(&port, sizeof(port), &addr_in->sin_port);
+        bpf_probe_read(&dst->addr, sizeof(addr_in->sin_addr.s_addr), &addr_in->sin_addr.s_addr);
+        break;
+    }
+    case AF_INET6: {
+        struct sockaddr_in6 *addr_in6 = (struct sockaddr_in6 *)address;
+        bpf_probe_read(&port, sizeof(port), &addr_in6->sin6_port);
+        bpf_probe_read(&dst->addr, sizeof(dst->addr), &addr_in6->sin6_addr);
+        break;
+    }
+    }
+    dst->port = bpf_ntohs(port);
+}
+
+#endif
@@ -3,6 +3,7 @@
 
 #include "filters.h"
 #include "process.h"
+#include "sockaddr.h"
 
 #define FSTYPE_LEN 16
 
@@ -136,6 +137,14 @@ struct syscall_cache_t {
             u32 event_kind;
             union selinux_write_payload_t payload;
         } selinux;
+
+        struct {
+            struct sockaddr_t addr;
+        } connect;
+
+        struct {
+            struct sockaddr_t addr;
+        } bind;
     };
 };
 
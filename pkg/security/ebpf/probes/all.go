@@ -35,6 +35,7 @@ func AllProbes() []*manager.Probe {
 	allProbes = append(allProbes, getXattrProbes()...)
 	allProbes = append(allProbes, getIoctlProbes()...)
 	allProbes = append(allProbes, getSELinuxProbes()...)
+	allProbes = append(allProbes, getNetworkProbes()...)
 
 	allProbes = append(allProbes,
 		// Syscall monitor
@@ -142,5 +142,21 @@ func getSysExitTailCallRoutes() []manager.TailCallRoute {
 				EBPFFuncName: "tracepoint_handle_sys_commit_creds_exit",
 			},
 		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.ConnectEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFSection:  "tracepoint/handle_sys_connect_exit",
+				EBPFFuncName: "tracepoint_handle_sys_connect_exit",
+			},
+		},
+		{
+			ProgArrayName: "sys_exit_progs",
+			Key:           uint32(model.BindEventType),
+			ProbeIdentificationPair: manager.ProbeIdentificationPair{
+				EBPFSection:  "tracepoint/handle_sys_bind_exit",
+				EBPFFuncName: "tracepoint_handle_sys_bind_exit",
+			},
+		},
 	}
 }
@@ -0,0 +1,185 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const (
+	// accessSummaryFlushInterval is the interval at which the elapsed windows are checked
+	accessSummaryFlushInterval = 10 * time.Second
+	// maxSummaryFiles bounds the number of files detailed in a summary, the accesses
+	// to the other files of the window are only counted
+	maxSummaryFiles = 1000
+	// maxSummaryProcesses bounds the number of executables detailed per file
+	maxSummaryProcesses = 20
+)
+
+// fileAccess is an access matched by an aggregated rule
+type fileAccess struct {
+	path        string
+	processPath string
+	user        string
+}
+
+// newFileAccess returns the access of an event, false when the event doesn't have a file
+func newFileAccess(event *sprobe.Event) (fileAccess, bool) {
+	value, err := event.GetFieldValue(event.GetType() + ".file.path")
+	if err != nil {
+		return fileAccess{}, false
+	}
+	path, _ := value.(string)
+
+	access := fileAccess{path: path}
+	if value, err := event.GetFieldValue("process.file.path"); err == nil {
+		access.processPath, _ = value.(string)
+	}
+	if value, err := event.GetFieldValue("process.user"); err == nil {
+		access.user, _ = value.(string)
+	}
+	return access, true
+}
+
+type fileAccessCounter struct {
+	accesses  int
+	processes map[string]int
+	users     map[string]struct{}
+}
+
+// ruleAccessSummary accumulates the accesses matched by a rule during its current window
+type ruleAccessSummary struct {
+	rule      *rules.Rule
+	start     time.Time
+	accesses  int
+	untracked int
+	files     map[string]*fileAccessCounter
+}
+
+func (s *ruleAccessSummary) record(access fileAccess) {
+	s.accesses++
+
+	counter, found := s.files[access.path]
+	if !found {
+		if len(s.files) >= maxSummaryFiles {
+			s.untracked++
+			return
+		}
+		counter = &fileAccessCounter{
+			processes: make(map[string]int),
+			users:     make(map[string]struct{}),
+		}
+		s.files[access.path] = counter
+	}
+
+	counter.accesses++
+	counter.processes[access.processPath]++
+	if access.user != "" {
+		counter.users[access.user] = struct{}{}
+	}
+}
+
+// event returns the custom event summarizing the window, ending at end
+func (s *ruleAccessSummary) event(end time.Time) *sprobe.CustomEvent {
+	summary := sprobe.FileAccessSummaryEvent{
+		Timestamp:         end,
+		WindowStart:       s.start,
+		Accesses:          s.accesses,
+		UntrackedAccesses: s.untracked,
+	}
+
+	for path, counter := range s.files {
+		file := sprobe.FileAccessSummary{
+			Path:     path,
+			Accesses: counter.accesses,
+		}
+		for processPath, accesses := range counter.processes {
+			file.Processes = append(file.Processes, sprobe.ProcessAccessSummary{Path: processPath, Accesses: accesses})
+		}
+		sort.Slice(file.Processes, func(i, j int) bool {
+			if file.Processes[i].Accesses != file.Processes[j].Accesses {
+				return file.Processes[i].Accesses > file.Processes[j].Accesses
+			}
+			return file.Processes[i].Path < file.Processes[j].Path
+		})
+		if len(file.Processes) > maxSummaryProcesses {
+			file.Processes = file.Processes[:maxSummaryProcesses]
+		}
+		for user := range counter.users {
+			file.Users = append(file.Users, user)
+		}
+		sort.Strings(file.Users)
+		summary.Files = append(summary.Files, file)
+	}
+	sort.Slice(summary.Files, func(i, j int) bool {
+		if summary.Files[i].Accesses != summary.Files[j].Accesses {
+			return summary.Files[i].Accesses > summary.Files[j].Accesses
+		}
+		return summary.Files[i].Path < summary.Files[j].Path
+	})
+
+	return sprobe.NewFileAccessSummaryEvent(summary)
+}
+
+// accessSummary is the summary of the window of a rule, ready to be sent
+type accessSummary struct {
+	rule  *rules.Rule
+	event *sprobe.CustomEvent
+}
+
+// accessSummarizer batches the accesses matched by the rules with an aggregation into a
+// summary per rule and window, so that broad file monitoring doesn't generate an event storm
+type accessSummarizer struct {
+	sync.Mutex
+	summaries map[rules.RuleID]*ruleAccessSummary
+}
+
+func newAccessSummarizer() *accessSummarizer {
+	return &accessSummarizer{
+		summaries: make(map[rules.RuleID]*ruleAccessSummary),
+	}
+}
+
+// record adds an access matched by an aggregated rule to the current window of the rule
+func (a *accessSummarizer) record(rule *rules.Rule, access fileAccess, now time.Time) {
+	a.Lock()
+	defer a.Unlock()
+
+	summary, found := a.summaries[rule.ID]
+	if !found {
+		summary = &ruleAccessSummary{
+			rule:  rule,
+			start: now,
+			files: make(map[string]*fileAccessCounter),
+		}
+		a.summaries[rule.ID] = summary
+	}
+	summary.record(access)
+}
+
+// flush returns the summaries of the windows that elapsed, or of all the pending windows when
+// force is set. The next access matched by their rules starts a new window.
+func (a *accessSummarizer) flush(now time.Time, force bool) []accessSummary {
+	a.Lock()
+	defer a.Unlock()
+
+	var summaries []accessSummary
+	for id, summary := range a.summaries {
+		if !force && now.Sub(summary.start) < summary.rule.Definition.Aggregation.Window {
+			continue
+		}
+		summaries = append(summaries, accessSummary{rule: summary.rule, event: summary.event(now)})
+		delete(a.summaries, id)
+	}
+	return summaries
+}
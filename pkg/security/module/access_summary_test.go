@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func newAggregatedRule(id string, window time.Duration) *rules.Rule {
+	return &rules.Rule{
+		Rule: &eval.Rule{ID: id},
+		Definition: &rules.RuleDefinition{
+			ID:          id,
+			Aggregation: &rules.AggregationDefinition{Window: window},
+		},
+	}
+}
+
+func TestAccessSummarizer(t *testing.T) {
+	etcRule := newAggregatedRule("etc_access", 5*time.Minute)
+	sshRule := newAggregatedRule("ssh_access", time.Minute)
+
+	summarizer := newAccessSummarizer()
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, access := range []fileAccess{
+		{path: "/etc/passwd", processPath: "/usr/bin/id", user: "root"},
+		{path: "/etc/passwd", processPath: "/usr/bin/id", user: "www-data"},
+		{path: "/etc/passwd", processPath: "/usr/sbin/sshd", user: "root"},
+		{path: "/etc/hosts", processPath: "/usr/bin/curl", user: "www-data"},
+	} {
+		summarizer.record(etcRule, access, start)
+	}
+	summarizer.record(sshRule, fileAccess{path: "/etc/ssh/sshd_config", processPath: "/usr/sbin/sshd", user: "root"}, start)
+
+	assert.Empty(t, summarizer.flush(start.Add(30*time.Second), false))
+
+	summaries := summarizer.flush(start.Add(time.Minute), false)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, sshRule, summaries[0].rule)
+	assert.Equal(t, "file_access_summary", summaries[0].event.GetType())
+
+	summaries = summarizer.flush(start.Add(5*time.Minute), false)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, etcRule, summaries[0].rule)
+
+	data, err := summaries[0].event.MarshalJSON()
+	require.NoError(t, err)
+
+	var summary sprobe.FileAccessSummaryEvent
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, start, summary.WindowStart.UTC())
+	assert.Equal(t, start.Add(5*time.Minute), summary.Timestamp.UTC())
+	assert.Equal(t, 4, summary.Accesses)
+	assert.Equal(t, []sprobe.FileAccessSummary{
+		{
+			Path:     "/etc/passwd",
+			Accesses: 3,
+			Processes: []sprobe.ProcessAccessSummary{
+				{Path: "/usr/bin/id", Accesses: 2},
+				{Path: "/usr/sbin/sshd", Accesses: 1},
+			},
+			Users: []string{"root", "www-data"},
+		},
+		{
+			Path:      "/etc/hosts",
+			Accesses:  1,
+			Processes: []sprobe.ProcessAccessSummary{{Path: "/usr/bin/curl", Accesses: 1}},
+			Users:     []string{"www-data"},
+		},
+	}, summary.Files)
+
+	// the next access starts a new window
+	summarizer.record(etcRule, fileAccess{path: "/etc/shadow"}, start.Add(6*time.Minute))
+	assert.Empty(t, summarizer.flush(start.Add(10*time.Minute), false))
+	assert.Len(t, summarizer.flush(start.Add(10*time.Minute), true), 1)
+}
+
+func TestAccessSummarizerMaxFiles(t *testing.T) {
+	rule := newAggregatedRule("etc_access", time.Minute)
+
+	summarizer := newAccessSummarizer()
+	now := time.Now()
+	for i := 0; i < maxSummaryFiles+10; i++ {
+		summarizer.record(rule, fileAccess{path: fmt.Sprintf("/etc/file%d", i)}, now)
+	}
+
+	summaries := summarizer.flush(now, true)
+	require.Len(t, summaries, 1)
+
+	data, err := summaries[0].event.MarshalJSON()
+	require.NoError(t, err)
+
+	var summary sprobe.FileAccessSummaryEvent
+	require.NoError(t, json.Unmarshal(data, &summary))
+	assert.Equal(t, maxSummaryFiles+10, summary.Accesses)
+	assert.Len(t, summary.Files, maxSummaryFiles)
+	assert.Equal(t, 10, summary.UntrackedAccesses)
+}
@@ -57,12 +57,14 @@ type Module struct {
 	grpcServer       *grpc.Server
 	listener         net.Listener
 	rateLimiter      *RateLimiter
+	accessSummarizer *accessSummarizer
 	sigupChan        chan os.Signal
 	ctx              context.Context
 	cancelFnc        context.CancelFunc
 	cancelSubscriber context.CancelFunc
 	rulesLoaded      func(rs *rules.RuleSet)
 	policiesVersions []string
+	policiesBundles  []*PoliciesBundle
 
 	selfTester *SelfTester
 }
@@ -163,6 +165,9 @@ func (m *Module) Start() error {
 	m.wg.Add(1)
 	go m.metricsSender()
 
+	m.wg.Add(1)
+	go m.accessSummarySender()
+
 	signal.Notify(m.sigupChan, syscall.SIGHUP)
 
 	m.wg.Add(1)
@@ -288,13 +293,29 @@ func (m *Module) Reload() error {
 			&seclog.PatternLogger{})
 	}
 
+	bundles, loadBundlesErr := m.loadPoliciesBundles()
+	if m.config.EnforceSignedPoliciesBundles {
+		// only the policies of the signed bundles are loaded
+		policiesDir = ""
+	}
+	bundlesSources := func() []rules.PolicySource {
+		var sources []rules.PolicySource
+		for _, bundle := range bundles {
+			sources = append(sources, bundle.PolicySources()...)
+		}
+		return sources
+	}
+
 	ruleSet := m.probe.NewRuleSet(newRuleSetOpts())
 
-	loadErr := rules.LoadPolicies(policiesDir, ruleSet)
+	loadErr := rules.LoadPoliciesFromSources(policiesDir, bundlesSources(), ruleSet)
+	if loadBundlesErr != nil {
+		loadErr = multierror.Append(loadErr, loadBundlesErr)
+	}
 
 	model := &model.Model{}
 	approverRuleSet := rules.NewRuleSet(model, model.NewEvent, newRuleSetOpts())
-	loadApproversErr := rules.LoadPolicies(policiesDir, approverRuleSet)
+	loadApproversErr := rules.LoadPoliciesFromSources(policiesDir, bundlesSources(), approverRuleSet)
 
 	if loadErr.ErrorOrNil() != nil {
 		logMultiErrors("error while loading policies: %+v", loadErr)
@@ -303,7 +324,11 @@ func (m *Module) Reload() error {
 	}
 
 	monitor := m.probe.GetMonitor()
-	ruleSetLoadedReport := monitor.PrepareRuleSetLoadedReport(ruleSet, loadErr)
+	bundlesVersions := make(map[string]string, len(bundles))
+	for _, bundle := range bundles {
+		bundlesVersions[bundle.Name] = bundle.Version
+	}
+	ruleSetLoadedReport := monitor.PrepareRuleSetLoadedReport(ruleSet, loadErr, bundlesVersions)
 
 	if m.selfTester != nil {
 		if err := m.selfTester.CreateTargetFileIfNeeded(); err != nil {
@@ -318,6 +343,7 @@ func (m *Module) Reload() error {
 	}
 
 	m.policiesVersions = getPoliciesVersions(ruleSet)
+	m.policiesBundles = bundles
 
 	ruleSet.AddListener(m)
 	if m.rulesLoaded != nil {
@@ -328,6 +354,9 @@ func (m *Module) Reload() error {
 	m.ruleSets[currentRuleSet] = ruleSet
 	atomic.StoreUint64(&m.currentRuleSet, currentRuleSet)
 
+	// the pending summaries are sent with the rules they were aggregated for
+	m.sendAccessSummaries(true)
+
 	// analyze the ruleset, push default policies in the kernel and generate the policy report
 	report, err := rsa.Apply(ruleSet, approvers)
 	if err != nil {
@@ -356,6 +385,9 @@ func (m *Module) Close() {
 	if m.cancelSubscriber != nil {
 		m.cancelSubscriber()
 	}
+
+	// the pending summaries are sent while the API server is still running
+	m.sendAccessSummaries(true)
 	m.cancelFnc()
 
 	if m.grpcServer != nil {
@@ -404,6 +436,14 @@ func (m *Module) RuleMatch(rule *rules.Rule, event eval.Event) {
 	// prepare the event
 	m.probe.OnRuleMatch(rule, event.(*sprobe.Event))
 
+	// the accesses of the aggregated rules are sent as periodic summaries
+	if rule.Definition.Aggregation != nil {
+		if access, ok := newFileAccess(event.(*sprobe.Event)); ok {
+			m.accessSummarizer.record(rule, access, time.Now())
+			return
+		}
+	}
+
 	// needs to be resolved here, outside of the callback as using process tree
 	// which can be modified during queuing
 	service := event.(*sprobe.Event).GetProcessServiceTag()
@@ -440,6 +480,32 @@ func (m *Module) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []str
 	}
 }
 
+func (m *Module) accessSummarySender() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(accessSummaryFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sendAccessSummaries(false)
+		case <-m.ctx.Done():
+			// flush the accesses recorded since Close sent the pending summaries
+			m.sendAccessSummaries(true)
+			return
+		}
+	}
+}
+
+// sendAccessSummaries sends the summaries of the aggregated rules whose window elapsed, or all the
+// pending summaries when force is set
+func (m *Module) sendAccessSummaries(force bool) {
+	for _, summary := range m.accessSummarizer.flush(time.Now(), force) {
+		m.SendEvent(summary.rule, summary.event, func() []string { return nil }, "")
+	}
+}
+
 func (m *Module) metricsSender() {
 	defer m.wg.Done()
 
@@ -472,6 +538,9 @@ func (m *Module) metricsSender() {
 			for _, version := range m.policiesVersions {
 				tags = append(tags, fmt.Sprintf("policies_version:%s", version))
 			}
+			for _, bundle := range m.policiesBundles {
+				tags = append(tags, fmt.Sprintf("policies_bundle_version:%s", bundle.Version))
+			}
 			m.RUnlock()
 
 			if m.config.RuntimeEnabled {
@@ -495,6 +564,10 @@ func (m *Module) GetStats() map[string]interface{} {
 		debug["probe"] = "not_running"
 	}
 
+	m.RLock()
+	debug["policies_bundles"] = m.policiesBundles
+	m.RUnlock()
+
 	return debug
 }
 
@@ -546,17 +619,18 @@ func NewModule(cfg *sconfig.Config) (module.Module, error) {
 	}
 
 	m := &Module{
-		config:         cfg,
-		probe:          probe,
-		statsdClient:   statsdClient,
-		apiServer:      NewAPIServer(cfg, probe, statsdClient),
-		grpcServer:     grpc.NewServer(),
-		rateLimiter:    NewRateLimiter(statsdClient, LimiterOpts{Limits: limits}),
-		sigupChan:      make(chan os.Signal, 1),
-		currentRuleSet: 1,
-		ctx:            ctx,
-		cancelFnc:      cancelFnc,
-		selfTester:     selfTester,
+		config:           cfg,
+		probe:            probe,
+		statsdClient:     statsdClient,
+		apiServer:        NewAPIServer(cfg, probe, statsdClient),
+		grpcServer:       grpc.NewServer(),
+		rateLimiter:      NewRateLimiter(statsdClient, LimiterOpts{Limits: limits}),
+		accessSummarizer: newAccessSummarizer(),
+		sigupChan:        make(chan os.Signal, 1),
+		currentRuleSet:   1,
+		ctx:              ctx,
+		cancelFnc:        cancelFnc,
+		selfTester:       selfTester,
 	}
 	m.apiServer.module = m
 
@@ -0,0 +1,133 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+// alwaysIncludedFields are required to process the forwarded events
+var alwaysIncludedFields = []string{"evt", "date"}
+
+// outputFieldFilter filters the fields of the events forwarded to Datadog, so
+// that sensitive fields (command line arguments, environment variables...) can
+// be kept out of the external systems the events are sent to.
+type outputFieldFilter struct {
+	include [][]string
+	exclude [][]string
+}
+
+// newOutputFieldFilter returns the filter of the events of a rule, nil when all
+// the fields are forwarded. The allowlist of the rule overrides the global one,
+// the denylist of the rule is added to the global one.
+func newOutputFieldFilter(cfg *config.Config, ruleDef *rules.RuleDefinition) *outputFieldFilter {
+	include, exclude := cfg.EventOutputIncludeFields, cfg.EventOutputExcludeFields
+	if ruleDef != nil && ruleDef.Output != nil {
+		if len(ruleDef.Output.IncludeFields) > 0 {
+			include = ruleDef.Output.IncludeFields
+		}
+		exclude = append(append([]string{}, exclude...), ruleDef.Output.ExcludeFields...)
+	}
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+
+	f := &outputFieldFilter{}
+	if len(include) > 0 {
+		for _, field := range append(alwaysIncludedFields, include...) {
+			f.include = append(f.include, strings.Split(field, "."))
+		}
+	}
+	for _, field := range exclude {
+		f.exclude = append(f.exclude, strings.Split(field, "."))
+	}
+	return f
+}
+
+// apply returns the JSON event without the filtered fields
+func (f *outputFieldFilter) apply(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep the precision of the 64 bits integers such as the inodes
+	decoder.UseNumber()
+
+	var event interface{}
+	if err := decoder.Decode(&event); err != nil {
+		return nil, err
+	}
+
+	if len(f.include) > 0 {
+		event, _ = projectFields(event, f.include)
+	}
+	for _, path := range f.exclude {
+		removeField(event, path)
+	}
+	return json.Marshal(event)
+}
+
+// projectFields returns the fields of value at the given paths. The paths are
+// applied to each element of the arrays, such as the ancestors of a process.
+func projectFields(value interface{}, paths [][]string) (interface{}, bool) {
+	for _, path := range paths {
+		if len(path) == 0 {
+			return value, true
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		subpaths := make(map[string][][]string)
+		for _, path := range paths {
+			subpaths[path[0]] = append(subpaths[path[0]], path[1:])
+		}
+		projected := make(map[string]interface{})
+		for key, keyPaths := range subpaths {
+			child, ok := v[key]
+			if !ok {
+				continue
+			}
+			if child, ok = projectFields(child, keyPaths); ok {
+				projected[key] = child
+			}
+		}
+		return projected, len(projected) > 0
+	case []interface{}:
+		projected := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			if elem, ok := projectFields(elem, paths); ok {
+				projected = append(projected, elem)
+			}
+		}
+		return projected, len(projected) > 0
+	}
+	// the paths go deeper than a scalar value
+	return nil, false
+}
+
+// removeField removes the field at path from value, from each element of the arrays
+func removeField(value interface{}, path []string) {
+	if len(path) == 0 {
+		return
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			delete(v, path[0])
+			return
+		}
+		removeField(v[path[0]], path[1:])
+	case []interface{}:
+		for _, elem := range v {
+			removeField(elem, path)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+const testEvent = `{"evt":{"name":"exec"},"file":{"path":"/usr/bin/curl","inode":18446744073709551615},` +
+	`"process":{"pid":42,"args":["-H","token"],"envs":["SECRET=1"],` +
+	`"ancestors":[{"pid":1,"args":["--foo"],"envs":["PATH=/bin"]},{"pid":2}]},"date":"2021-01-01T00:00:00Z"}`
+
+func TestOutputFieldFilter(t *testing.T) {
+	cfg := &config.Config{}
+	assert.Nil(t, newOutputFieldFilter(cfg, &rules.RuleDefinition{}))
+
+	for _, test := range []struct {
+		name     string
+		cfg      *config.Config
+		output   *rules.OutputDefinition
+		expected string
+	}{
+		{
+			name: "global-exclude",
+			cfg:  &config.Config{EventOutputExcludeFields: []string{"process.args", "process.envs", "process.ancestors.envs"}},
+			expected: `{"date":"2021-01-01T00:00:00Z","evt":{"name":"exec"},"file":{"inode":18446744073709551615,"path":"/usr/bin/curl"},` +
+				`"process":{"ancestors":[{"args":["--foo"],"pid":1},{"pid":2}],"pid":42}}`,
+		},
+		{
+			name:     "global-include",
+			cfg:      &config.Config{EventOutputIncludeFields: []string{"process.pid", "process.ancestors.pid"}},
+			expected: `{"date":"2021-01-01T00:00:00Z","evt":{"name":"exec"},"process":{"ancestors":[{"pid":1},{"pid":2}],"pid":42}}`,
+		},
+		{
+			name:     "rule-overrides-include",
+			cfg:      &config.Config{EventOutputIncludeFields: []string{"process"}, EventOutputExcludeFields: []string{"file.inode"}},
+			output:   &rules.OutputDefinition{IncludeFields: []string{"file"}},
+			expected: `{"date":"2021-01-01T00:00:00Z","evt":{"name":"exec"},"file":{"path":"/usr/bin/curl"}}`,
+		},
+		{
+			name:     "rule-extends-exclude",
+			cfg:      &config.Config{EventOutputExcludeFields: []string{"process"}},
+			output:   &rules.OutputDefinition{ExcludeFields: []string{"file", "unknown.field"}},
+			expected: `{"date":"2021-01-01T00:00:00Z","evt":{"name":"exec"}}`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			filter := newOutputFieldFilter(test.cfg, &rules.RuleDefinition{Output: test.output})
+			if !assert.NotNil(t, filter) {
+				return
+			}
+			data, err := filter.apply([]byte(testEvent))
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, string(data))
+		})
+	}
+}
@@ -0,0 +1,269 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	policiesBundleExt           = ".tar.gz"
+	policiesBundleManifest      = "manifest.json"
+	policiesBundleSignature     = "manifest.json.sig"
+	policiesBundleMaxFileSize   = 10 * 1024 * 1024
+	policiesBundleMaxFilesCount = 1024
+	// policiesBundleMaxTotalSize caps the uncompressed size of a bundle, its files are held in memory
+	policiesBundleMaxTotalSize = 32 * 1024 * 1024
+)
+
+// PoliciesBundleManifest describes the content of a policies bundle
+type PoliciesBundleManifest struct {
+	Version string `json:"version"`
+	// Files holds the hex encoded sha256 checksums of the files of the bundle
+	Files map[string]string `json:"files"`
+}
+
+// PoliciesBundle is a verified policies bundle
+type PoliciesBundle struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Signed   bool   `json:"signed"`
+	policies map[string][]byte
+}
+
+// PolicySources returns the sources of the policies of the bundle, sorted by name
+func (b *PoliciesBundle) PolicySources() []rules.PolicySource {
+	names := make([]string, 0, len(b.policies))
+	for name := range b.policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sources := make([]rules.PolicySource, 0, len(names))
+	for _, name := range names {
+		sources = append(sources, rules.PolicySource{Name: name, Reader: bytes.NewReader(b.policies[name])})
+	}
+	return sources
+}
+
+// LoadPoliciesBundlePublicKey reads the PEM encoded ed25519 public key used to verify the policies bundles
+func LoadPoliciesBundlePublicKey(filename string) (ed25519.PublicKey, error) {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", filename)
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T, expected an ed25519 key", key)
+	}
+	return publicKey, nil
+}
+
+// LoadPoliciesBundle reads and verifies a policies bundle. The signature of the manifest is verified
+// with the public key, if any, and unsigned bundles are refused when the signature is enforced.
+func LoadPoliciesBundle(r io.Reader, name string, publicKey ed25519.PublicKey, enforceSignature bool) (*PoliciesBundle, error) {
+	files, err := readPoliciesBundleFiles(r)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestContent, found := files[policiesBundleManifest]
+	if !found {
+		return nil, fmt.Errorf("no %s file found", policiesBundleManifest)
+	}
+
+	bundle := &PoliciesBundle{Name: name, policies: make(map[string][]byte)}
+
+	signature, signed := files[policiesBundleSignature]
+	switch {
+	case signed && publicKey != nil:
+		if !ed25519.Verify(publicKey, manifestContent, signature) {
+			return nil, errors.New("invalid signature")
+		}
+		bundle.Signed = true
+	case enforceSignature && !signed:
+		return nil, errors.New("unsigned bundle refused, signed bundles are enforced")
+	case enforceSignature:
+		return nil, errors.New("cannot verify the signature without public key, signed bundles are enforced")
+	}
+
+	var manifest PoliciesBundleManifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "invalid %s file", policiesBundleManifest)
+	}
+	bundle.Version = manifest.Version
+
+	for filename, content := range files {
+		if filename == policiesBundleManifest || filename == policiesBundleSignature {
+			continue
+		}
+
+		checksum, found := manifest.Files[filename]
+		if !found {
+			return nil, fmt.Errorf("file %s not listed in the manifest", filename)
+		}
+		sum := sha256.Sum256(content)
+		if !strings.EqualFold(checksum, hex.EncodeToString(sum[:])) {
+			return nil, fmt.Errorf("invalid checksum of %s", filename)
+		}
+
+		if filepath.Ext(filename) == ".policy" {
+			bundle.policies[filename] = content
+		}
+	}
+
+	for filename := range manifest.Files {
+		if _, found := files[filename]; !found {
+			return nil, fmt.Errorf("file %s of the manifest not found", filename)
+		}
+	}
+
+	return bundle, nil
+}
+
+// readPoliciesBundleFiles returns the content of the files of a gzipped tarball
+func readPoliciesBundleFiles(r io.Reader) (map[string][]byte, error) {
+	gzipReader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gzipReader.Close()
+
+	files := make(map[string][]byte)
+	var totalSize int64
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("unsupported type of file %s", header.Name)
+		}
+
+		// the files of the bundle are expected at the root of the archive
+		filename := path.Clean(strings.TrimPrefix(header.Name, "./"))
+		if strings.Contains(filename, "/") {
+			return nil, fmt.Errorf("unexpected file %s outside of the root of the bundle", header.Name)
+		}
+		if _, exists := files[filename]; exists {
+			return nil, fmt.Errorf("duplicated file %s", filename)
+		}
+		if len(files) >= policiesBundleMaxFilesCount {
+			return nil, fmt.Errorf("too many files, the maximum is %d", policiesBundleMaxFilesCount)
+		}
+		if header.Size > policiesBundleMaxFileSize {
+			return nil, fmt.Errorf("file %s exceeds the maximum size of %d bytes", filename, policiesBundleMaxFileSize)
+		}
+		totalSize += header.Size
+		if totalSize > policiesBundleMaxTotalSize {
+			return nil, fmt.Errorf("the bundle exceeds the maximum size of %d bytes", policiesBundleMaxTotalSize)
+		}
+
+		content, err := ioutil.ReadAll(io.LimitReader(tarReader, policiesBundleMaxFileSize))
+		if err != nil {
+			return nil, err
+		}
+		files[filename] = content
+	}
+
+	return files, nil
+}
+
+// loadPoliciesBundleFile reads and verifies the policies bundle of a file, streaming it not to hold its compressed content in memory
+func loadPoliciesBundleFile(filename string, publicKey ed25519.PublicKey, enforceSignature bool) (*PoliciesBundle, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadPoliciesBundle(f, filepath.Base(filename), publicKey, enforceSignature)
+}
+
+// loadPoliciesBundles loads the policies bundles of the policies directory. The bundles that
+// can't be loaded are reported as policies load errors. The bundles are only read from the
+// policies directory, they aren't delivered by remote configuration.
+func (m *Module) loadPoliciesBundles() ([]*PoliciesBundle, *multierror.Error) {
+	var result *multierror.Error
+
+	var publicKey ed25519.PublicKey
+	if m.config.PoliciesBundlePublicKeyFile != "" {
+		key, err := LoadPoliciesBundlePublicKey(m.config.PoliciesBundlePublicKeyFile)
+		if err != nil {
+			// without the key, the signed bundles can't be verified
+			log.Errorf("failed to load the policies bundle public key: %s", err)
+		}
+		publicKey = key
+	}
+
+	filenames, err := filepath.Glob(filepath.Join(m.config.PoliciesDir, "*"+policiesBundleExt))
+	if err != nil {
+		return nil, multierror.Append(result, rules.ErrPoliciesLoad{Name: m.config.PoliciesDir, Err: err})
+	}
+	sort.Strings(filenames)
+
+	var bundles []*PoliciesBundle
+	for _, filename := range filenames {
+		name := filepath.Base(filename)
+
+		bundle, err := loadPoliciesBundleFile(filename, publicKey, m.config.EnforceSignedPoliciesBundles)
+		if err != nil {
+			result = multierror.Append(result, &rules.ErrPolicyLoad{Name: name, Err: err})
+			continue
+		}
+		if !bundle.Signed {
+			log.Warnf("loading the policies of the unsigned bundle %s", name)
+		}
+		log.Infof("loaded policies bundle %s version %s", name, bundle.Version)
+
+		bundles = append(bundles, bundle)
+	}
+
+	return bundles, result
+}
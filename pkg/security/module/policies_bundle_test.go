@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package module
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBundlePolicy = `---
+version: 1.2.3
+rules:
+  - id: test_rule
+    expression: open.file.path == "/etc/passwd"
+`
+
+type testBundleFile struct {
+	name    string
+	content []byte
+}
+
+func buildTestPoliciesBundle(t *testing.T, files []testBundleFile) []byte {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, file := range files {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{
+			Name:     file.name,
+			Mode:     0644,
+			Size:     int64(len(file.content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tarWriter.Write(file.content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buf.Bytes()
+}
+
+func buildTestManifest(t *testing.T, version string, files ...testBundleFile) []byte {
+	manifest := PoliciesBundleManifest{Version: version, Files: make(map[string]string)}
+	for _, file := range files {
+		sum := sha256.Sum256(file.content)
+		manifest.Files[file.name] = hex.EncodeToString(sum[:])
+	}
+
+	content, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	return content
+}
+
+func TestLoadPoliciesBundle(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	policy := testBundleFile{name: "default.policy", content: []byte(testBundlePolicy)}
+	manifest := buildTestManifest(t, "42", policy)
+	signature := ed25519.Sign(privateKey, manifest)
+
+	t.Run("signed", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: policiesBundleSignature, content: signature},
+			policy,
+		})
+
+		bundle, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, true)
+		require.NoError(t, err)
+		assert.Equal(t, "bundle.tar.gz", bundle.Name)
+		assert.Equal(t, "42", bundle.Version)
+		assert.True(t, bundle.Signed)
+
+		// the readers of the sources can be consumed several times
+		for i := 0; i != 2; i++ {
+			sources := bundle.PolicySources()
+			require.Len(t, sources, 1)
+			assert.Equal(t, "default.policy", sources[0].Name)
+			data, err := ioutil.ReadAll(sources[0].Reader)
+			require.NoError(t, err)
+			assert.Equal(t, testBundlePolicy, string(data))
+		}
+	})
+
+	t.Run("invalid-signature", func(t *testing.T) {
+		otherPublicKey, _, err := ed25519.GenerateKey(nil)
+		require.NoError(t, err)
+
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: policiesBundleSignature, content: signature},
+			policy,
+		})
+
+		_, err = LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", otherPublicKey, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			policy,
+		})
+
+		bundle, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, false)
+		require.NoError(t, err)
+		assert.False(t, bundle.Signed)
+		assert.Equal(t, "42", bundle.Version)
+
+		_, err = LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("no-public-key", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: policiesBundleSignature, content: signature},
+			policy,
+		})
+
+		_, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", nil, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid-checksum", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: policiesBundleSignature, content: signature},
+			{name: policy.name, content: []byte(testBundlePolicy + "  - id: injected\n")},
+		})
+
+		_, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("unlisted-file", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: policiesBundleSignature, content: signature},
+			policy,
+			{name: "other.policy", content: []byte(testBundlePolicy)},
+		})
+
+		_, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("missing-file", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: policiesBundleSignature, content: signature},
+		})
+
+		_, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, true)
+		assert.Error(t, err)
+	})
+
+	t.Run("too-large", func(t *testing.T) {
+		files := []testBundleFile{{name: policiesBundleManifest, content: manifest}}
+		for _, name := range []string{"a.policy", "b.policy", "c.policy", "d.policy"} {
+			files = append(files, testBundleFile{name: name, content: make([]byte, policiesBundleMaxFileSize)})
+		}
+		content := buildTestPoliciesBundle(t, files)
+
+		_, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, false)
+		assert.EqualError(t, err, "the bundle exceeds the maximum size of 33554432 bytes")
+	})
+
+	t.Run("nested-file", func(t *testing.T) {
+		content := buildTestPoliciesBundle(t, []testBundleFile{
+			{name: policiesBundleManifest, content: manifest},
+			{name: "../default.policy", content: policy.content},
+		})
+
+		_, err := LoadPoliciesBundle(bytes.NewReader(content), "bundle.tar.gz", publicKey, false)
+		assert.Error(t, err)
+	})
+}
@@ -257,6 +257,17 @@ func (a *APIServer) SendEvent(rule *rules.Rule, event Event, extTagsCb func() []
 		return
 	}
 
+	// the filtered fields are only kept in the local traces
+	if a.cfg != nil {
+		if filter := newOutputFieldFilter(a.cfg, rule.Definition); filter != nil {
+			seclog.Tracef("Filtering the fields of the event of rule `%s`: `%s`", rule.ID, string(probeJSON))
+			if probeJSON, err = filter.apply(probeJSON); err != nil {
+				log.Error(errors.Wrap(err, "failed to filter event fields"))
+				return
+			}
+		}
+	}
+
 	ruleEventJSON, err := json.Marshal(ruleEvent)
 	if err != nil {
 		log.Error(errors.Wrap(err, "failed to marshal event context"))
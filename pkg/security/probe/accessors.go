@@ -31,18 +31,24 @@ func (m *Model) GetIterator(field eval.Field) (eval.Iterator, error) {
 func (m *Model) GetEventTypes() []eval.EventType {
 	return []eval.EventType{
 
+		eval.EventType("bind"),
+
 		eval.EventType("capset"),
 
 		eval.EventType("chmod"),
 
 		eval.EventType("chown"),
 
+		eval.EventType("connect"),
+
 		eval.EventType("exec"),
 
 		eval.EventType("link"),
 
 		eval.EventType("mkdir"),
 
+		eval.EventType("mount"),
+
 		eval.EventType("open"),
 
 		eval.EventType("removexattr"),
@@ -59,6 +65,8 @@ func (m *Model) GetEventTypes() []eval.EventType {
 
 		eval.EventType("setxattr"),
 
+		eval.EventType("umount"),
+
 		eval.EventType("unlink"),
 
 		eval.EventType("utimes"),
@@ -68,6 +76,46 @@ func (m *Model) GetEventTypes() []eval.EventType {
 func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Evaluator, error) {
 	switch field {
 
+	case "bind.addr.family":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Bind.Addr.Family)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bind.addr.ip":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Bind.Addr.IP
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bind.addr.port":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Bind.Addr.Port)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "bind.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Bind.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "capset.cap_effective":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
@@ -448,6 +496,57 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: eval.FunctionWeight,
 		}, nil
 
+	case "connect.addr.domains":
+		return &eval.StringArrayEvaluator{
+
+			EvalFnc: func(ctx *eval.Context) []string {
+
+				return (*Event)(ctx.Object).ResolveConnectDomains(&(*Event)(ctx.Object).Connect)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "connect.addr.family":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Connect.Addr.Family)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "connect.addr.ip":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).Connect.Addr.IP
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "connect.addr.port":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Connect.Addr.Port)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "connect.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Connect.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "container.id":
 		return &eval.StringEvaluator{
 			EvalFnc: func(ctx *eval.Context) string {
@@ -1363,6 +1462,56 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: eval.FunctionWeight,
 		}, nil
 
+	case "mount.fs_type":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).ResolveMountFSType(&(*Event)(ctx.Object).Mount)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mount.mountpoint.container_id":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).ResolveMountPointContainerID(&(*Event)(ctx.Object).Mount)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mount.mountpoint.path":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).ResolveMountPoint(&(*Event)(ctx.Object).Mount)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "mount.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Mount.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
+	case "mount.source.path":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).ResolveMountRoot(&(*Event)(ctx.Object).Mount)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
 	case "open.file.change_time":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
@@ -3949,6 +4098,36 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 			Weight: eval.FunctionWeight,
 		}, nil
 
+	case "umount.fs_type":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).ResolveUmountFSType(&(*Event)(ctx.Object).Umount)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "umount.mountpoint.path":
+		return &eval.StringEvaluator{
+			EvalFnc: func(ctx *eval.Context) string {
+
+				return (*Event)(ctx.Object).ResolveUmountPoint(&(*Event)(ctx.Object).Umount)
+			},
+			Field:  field,
+			Weight: eval.HandlerWeight,
+		}, nil
+
+	case "umount.retval":
+		return &eval.IntEvaluator{
+			EvalFnc: func(ctx *eval.Context) int {
+
+				return int((*Event)(ctx.Object).Umount.SyscallEvent.Retval)
+			},
+			Field:  field,
+			Weight: eval.FunctionWeight,
+		}, nil
+
 	case "unlink.file.change_time":
 		return &eval.IntEvaluator{
 			EvalFnc: func(ctx *eval.Context) int {
@@ -4257,6 +4436,14 @@ func (m *Model) GetEvaluator(field eval.Field, regID eval.RegisterID) (eval.Eval
 func (e *Event) GetFields() []eval.Field {
 	return []eval.Field{
 
+		"bind.addr.family",
+
+		"bind.addr.ip",
+
+		"bind.addr.port",
+
+		"bind.retval",
+
 		"capset.cap_effective",
 
 		"capset.cap_permitted",
@@ -4333,6 +4520,16 @@ func (e *Event) GetFields() []eval.Field {
 
 		"chown.retval",
 
+		"connect.addr.domains",
+
+		"connect.addr.family",
+
+		"connect.addr.ip",
+
+		"connect.addr.port",
+
+		"connect.retval",
+
 		"container.id",
 
 		"container.tags",
@@ -4515,6 +4712,16 @@ func (e *Event) GetFields() []eval.Field {
 
 		"mkdir.retval",
 
+		"mount.fs_type",
+
+		"mount.mountpoint.container_id",
+
+		"mount.mountpoint.path",
+
+		"mount.retval",
+
+		"mount.source.path",
+
 		"open.file.change_time",
 
 		"open.file.destination.mode",
@@ -4881,6 +5088,12 @@ func (e *Event) GetFields() []eval.Field {
 
 		"setxattr.retval",
 
+		"umount.fs_type",
+
+		"umount.mountpoint.path",
+
+		"umount.retval",
+
 		"unlink.file.change_time",
 
 		"unlink.file.filesystem",
@@ -4946,6 +5159,22 @@ func (e *Event) GetFields() []eval.Field {
 func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 	switch field {
 
+	case "bind.addr.family":
+
+		return int(e.Bind.Addr.Family), nil
+
+	case "bind.addr.ip":
+
+		return e.Bind.Addr.IP, nil
+
+	case "bind.addr.port":
+
+		return int(e.Bind.Addr.Port), nil
+
+	case "bind.retval":
+
+		return int(e.Bind.SyscallEvent.Retval), nil
+
 	case "capset.cap_effective":
 
 		return int(e.Capset.CapEffective), nil
@@ -5098,6 +5327,26 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Chown.SyscallEvent.Retval), nil
 
+	case "connect.addr.domains":
+
+		return e.ResolveConnectDomains(&e.Connect), nil
+
+	case "connect.addr.family":
+
+		return int(e.Connect.Addr.Family), nil
+
+	case "connect.addr.ip":
+
+		return e.Connect.Addr.IP, nil
+
+	case "connect.addr.port":
+
+		return int(e.Connect.Addr.Port), nil
+
+	case "connect.retval":
+
+		return int(e.Connect.SyscallEvent.Retval), nil
+
 	case "container.id":
 
 		return e.ResolveContainerID(&e.ContainerContext), nil
@@ -5462,6 +5711,26 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.Mkdir.SyscallEvent.Retval), nil
 
+	case "mount.fs_type":
+
+		return e.ResolveMountFSType(&e.Mount), nil
+
+	case "mount.mountpoint.container_id":
+
+		return e.ResolveMountPointContainerID(&e.Mount), nil
+
+	case "mount.mountpoint.path":
+
+		return e.ResolveMountPoint(&e.Mount), nil
+
+	case "mount.retval":
+
+		return int(e.Mount.SyscallEvent.Retval), nil
+
+	case "mount.source.path":
+
+		return e.ResolveMountRoot(&e.Mount), nil
+
 	case "open.file.change_time":
 
 		return int(e.Open.File.FileFields.CTime), nil
@@ -6842,6 +7111,18 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 
 		return int(e.SetXAttr.SyscallEvent.Retval), nil
 
+	case "umount.fs_type":
+
+		return e.ResolveUmountFSType(&e.Umount), nil
+
+	case "umount.mountpoint.path":
+
+		return e.ResolveUmountPoint(&e.Umount), nil
+
+	case "umount.retval":
+
+		return int(e.Umount.SyscallEvent.Retval), nil
+
 	case "unlink.file.change_time":
 
 		return int(e.Unlink.File.FileFields.CTime), nil
@@ -6970,6 +7251,18 @@ func (e *Event) GetFieldValue(field eval.Field) (interface{}, error) {
 func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	switch field {
 
+	case "bind.addr.family":
+		return "bind", nil
+
+	case "bind.addr.ip":
+		return "bind", nil
+
+	case "bind.addr.port":
+		return "bind", nil
+
+	case "bind.retval":
+		return "bind", nil
+
 	case "capset.cap_effective":
 		return "capset", nil
 
@@ -7084,6 +7377,21 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "chown.retval":
 		return "chown", nil
 
+	case "connect.addr.domains":
+		return "connect", nil
+
+	case "connect.addr.family":
+		return "connect", nil
+
+	case "connect.addr.ip":
+		return "connect", nil
+
+	case "connect.addr.port":
+		return "connect", nil
+
+	case "connect.retval":
+		return "connect", nil
+
 	case "container.id":
 		return "*", nil
 
@@ -7357,6 +7665,21 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "mkdir.retval":
 		return "mkdir", nil
 
+	case "mount.fs_type":
+		return "mount", nil
+
+	case "mount.mountpoint.container_id":
+		return "mount", nil
+
+	case "mount.mountpoint.path":
+		return "mount", nil
+
+	case "mount.retval":
+		return "mount", nil
+
+	case "mount.source.path":
+		return "mount", nil
+
 	case "open.file.change_time":
 		return "open", nil
 
@@ -7906,6 +8229,15 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 	case "setxattr.retval":
 		return "setxattr", nil
 
+	case "umount.fs_type":
+		return "umount", nil
+
+	case "umount.mountpoint.path":
+		return "umount", nil
+
+	case "umount.retval":
+		return "umount", nil
+
 	case "unlink.file.change_time":
 		return "unlink", nil
 
@@ -8004,6 +8336,22 @@ func (e *Event) GetFieldEventType(field eval.Field) (eval.EventType, error) {
 func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 	switch field {
 
+	case "bind.addr.family":
+
+		return reflect.Int, nil
+
+	case "bind.addr.ip":
+
+		return reflect.String, nil
+
+	case "bind.addr.port":
+
+		return reflect.Int, nil
+
+	case "bind.retval":
+
+		return reflect.Int, nil
+
 	case "capset.cap_effective":
 
 		return reflect.Int, nil
@@ -8156,6 +8504,26 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "connect.addr.domains":
+
+		return reflect.String, nil
+
+	case "connect.addr.family":
+
+		return reflect.Int, nil
+
+	case "connect.addr.ip":
+
+		return reflect.String, nil
+
+	case "connect.addr.port":
+
+		return reflect.Int, nil
+
+	case "connect.retval":
+
+		return reflect.Int, nil
+
 	case "container.id":
 
 		return reflect.String, nil
@@ -8520,6 +8888,26 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "mount.fs_type":
+
+		return reflect.String, nil
+
+	case "mount.mountpoint.container_id":
+
+		return reflect.String, nil
+
+	case "mount.mountpoint.path":
+
+		return reflect.String, nil
+
+	case "mount.retval":
+
+		return reflect.Int, nil
+
+	case "mount.source.path":
+
+		return reflect.String, nil
+
 	case "open.file.change_time":
 
 		return reflect.Int, nil
@@ -9252,6 +9640,18 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "umount.fs_type":
+
+		return reflect.String, nil
+
+	case "umount.mountpoint.path":
+
+		return reflect.String, nil
+
+	case "umount.retval":
+
+		return reflect.Int, nil
+
 	case "unlink.file.change_time":
 
 		return reflect.Int, nil
@@ -9380,6 +9780,47 @@ func (e *Event) GetFieldType(field eval.Field) (reflect.Kind, error) {
 func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 	switch field {
 
+	case "bind.addr.family":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Addr.Family"}
+		}
+		e.Bind.Addr.Family = uint16(v)
+		return nil
+
+	case "bind.addr.ip":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Addr.IP"}
+		}
+		e.Bind.Addr.IP = str
+
+		return nil
+
+	case "bind.addr.port":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.Addr.Port"}
+		}
+		e.Bind.Addr.Port = uint16(v)
+		return nil
+
+	case "bind.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Bind.SyscallEvent.Retval"}
+		}
+		e.Bind.SyscallEvent.Retval = int64(v)
+		return nil
+
 	case "capset.cap_effective":
 
 		var ok bool
@@ -9768,6 +10209,58 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Chown.SyscallEvent.Retval = int64(v)
 		return nil
 
+	case "connect.addr.domains":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Domains"}
+		}
+		e.Connect.Domains = append(e.Connect.Domains, str)
+
+		return nil
+
+	case "connect.addr.family":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.Family"}
+		}
+		e.Connect.Addr.Family = uint16(v)
+		return nil
+
+	case "connect.addr.ip":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.IP"}
+		}
+		e.Connect.Addr.IP = str
+
+		return nil
+
+	case "connect.addr.port":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.Addr.Port"}
+		}
+		e.Connect.Addr.Port = uint16(v)
+		return nil
+
+	case "connect.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Connect.SyscallEvent.Retval"}
+		}
+		e.Connect.SyscallEvent.Retval = int64(v)
+		return nil
+
 	case "container.id":
 
 		var ok bool
@@ -10702,6 +11195,60 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.Mkdir.SyscallEvent.Retval = int64(v)
 		return nil
 
+	case "mount.fs_type":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Mount.FSType"}
+		}
+		e.Mount.FSType = str
+
+		return nil
+
+	case "mount.mountpoint.container_id":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Mount.MountPointContainerID"}
+		}
+		e.Mount.MountPointContainerID = str
+
+		return nil
+
+	case "mount.mountpoint.path":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Mount.MountPointStr"}
+		}
+		e.Mount.MountPointStr = str
+
+		return nil
+
+	case "mount.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Mount.SyscallEvent.Retval"}
+		}
+		e.Mount.SyscallEvent.Retval = int64(v)
+		return nil
+
+	case "mount.source.path":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Mount.RootStr"}
+		}
+		e.Mount.RootStr = str
+
+		return nil
+
 	case "open.file.change_time":
 
 		var ok bool
@@ -12729,6 +13276,38 @@ func (e *Event) SetFieldValue(field eval.Field, value interface{}) error {
 		e.SetXAttr.SyscallEvent.Retval = int64(v)
 		return nil
 
+	case "umount.fs_type":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Umount.FSType"}
+		}
+		e.Umount.FSType = str
+
+		return nil
+
+	case "umount.mountpoint.path":
+
+		var ok bool
+		str, ok := value.(string)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Umount.MountPointStr"}
+		}
+		e.Umount.MountPointStr = str
+
+		return nil
+
+	case "umount.retval":
+
+		var ok bool
+		v, ok := value.(int)
+		if !ok {
+			return &eval.ErrValueTypeMismatch{Field: "Umount.SyscallEvent.Retval"}
+		}
+		e.Umount.SyscallEvent.Retval = int64(v)
+		return nil
+
 	case "unlink.file.change_time":
 
 		var ok bool
@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"strings"
+
+	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/model"
+	"github.com/DataDog/datadog-agent/pkg/security/utils"
+	"github.com/DataDog/datadog-agent/pkg/tagger/collectors"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	confinementKindSeccomp  = "seccomp"
+	confinementKindAppArmor = "apparmor"
+	confinementKindSELinux  = "selinux"
+
+	// reportedDriftsCacheSize is the number of drifts remembered to report them once per container
+	reportedDriftsCacheSize = 4096
+
+	seccompModeFilter = 2
+)
+
+var seccompModes = map[int]string{
+	0:                 "disabled",
+	1:                 "strict",
+	seccompModeFilter: "filter",
+}
+
+// declaredConfinement is the confinement declared by the pod spec of a container, see
+// workloadmeta.ContainerSecurityProfile
+type declaredConfinement struct {
+	seccomp     string
+	appArmor    string
+	seLinuxType string
+}
+
+func (d declaredConfinement) isEmpty() bool {
+	return d == declaredConfinement{}
+}
+
+// processConfinement is the confinement of a process, the fields are empty when it can't be read
+type processConfinement struct {
+	seccompMode    string
+	appArmorLabel  string
+	seLinuxContext string
+}
+
+type reportedDrift struct {
+	containerID string
+	kind        string
+	actual      string
+}
+
+// ConfinementMonitor compares the confinement of the processes executed in the containers to the one declared by
+// their pod spec
+type ConfinementMonitor struct {
+	probe          *Probe
+	reportedDrifts *lru.Cache
+}
+
+// NewConfinementMonitor returns a new ConfinementMonitor
+func NewConfinementMonitor(p *Probe) (*ConfinementMonitor, error) {
+	reportedDrifts, err := lru.New(reportedDriftsCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfinementMonitor{
+		probe:          p,
+		reportedDrifts: reportedDrifts,
+	}, nil
+}
+
+// ProcessEvent checks the confinement of the process of an exec event, a drift is reported once per container
+func (cm *ConfinementMonitor) ProcessEvent(event *Event) {
+	if event.GetEventType() != model.ExecEventType || event.ContainerContext.ID == "" {
+		return
+	}
+
+	// the declared confinement is only known once the tags of the container are available
+	declared := cm.resolveDeclaredConfinement(event.ContainerContext.ID)
+	if declared.isEmpty() {
+		return
+	}
+
+	var drifts []ConfinementDrift
+	for _, drift := range confinementDrifts(declared, readProcessConfinement(int32(event.ProcessContext.Pid), declared)) {
+		key := reportedDrift{containerID: event.ContainerContext.ID, kind: drift.Kind, actual: drift.Actual}
+		if found, _ := cm.reportedDrifts.ContainsOrAdd(key, struct{}{}); !found {
+			drifts = append(drifts, drift)
+		}
+	}
+
+	if len(drifts) > 0 {
+		cm.probe.DispatchCustomEvent(
+			NewConfinementDriftEvent(event, drifts),
+		)
+	}
+}
+
+func (cm *ConfinementMonitor) resolveDeclaredConfinement(containerID string) declaredConfinement {
+	tags := cm.probe.resolvers.TagsResolver.Resolve(containerID)
+	return declaredConfinement{
+		seccomp:     utils.GetTagValue(collectors.TagKeyKubeSeccompProfile, tags),
+		appArmor:    utils.GetTagValue(collectors.TagKeyKubeAppArmorProfile, tags),
+		seLinuxType: utils.GetTagValue(collectors.TagKeyKubeSELinuxType, tags),
+	}
+}
+
+// readProcessConfinement reads from procfs the confinement of a process, only for the kinds of confinement declared
+func readProcessConfinement(pid int32, declared declaredConfinement) processConfinement {
+	var confinement processConfinement
+
+	if declared.seccomp != "" {
+		if mode, err := utils.SeccompMode(pid); err == nil {
+			confinement.seccompMode = seccompModes[mode]
+		} else {
+			log.Debugf("failed to read the seccomp mode of %d: %s", pid, err)
+		}
+	}
+	if declared.appArmor != "" {
+		if label, err := utils.LSMLabel(pid, confinementKindAppArmor); err == nil {
+			confinement.appArmorLabel = label
+		}
+	}
+	if declared.seLinuxType != "" {
+		if label, err := utils.LSMLabel(pid, confinementKindSELinux); err == nil {
+			confinement.seLinuxContext = label
+		}
+	}
+
+	return confinement
+}
+
+// confinementDrifts returns how the confinement of a process is looser than the declared one. The confinement that
+// couldn't be read isn't compared.
+func confinementDrifts(declared declaredConfinement, actual processConfinement) []ConfinementDrift {
+	var drifts []ConfinementDrift
+
+	// RuntimeDefault and Localhost profiles are enforced with a seccomp filter
+	if declared.seccomp != "" && declared.seccomp != "Unconfined" && actual.seccompMode != "" &&
+		actual.seccompMode != seccompModes[seccompModeFilter] {
+		drifts = append(drifts, ConfinementDrift{
+			Kind:     confinementKindSeccomp,
+			Declared: declared.seccomp,
+			Actual:   actual.seccompMode,
+		})
+	}
+
+	if declared.appArmor != "" && declared.appArmor != "unconfined" && actual.appArmorLabel != "" &&
+		!isAppArmorLabelCompliant(declared.appArmor, actual.appArmorLabel) {
+		drifts = append(drifts, ConfinementDrift{
+			Kind:     confinementKindAppArmor,
+			Declared: declared.appArmor,
+			Actual:   actual.appArmorLabel,
+		})
+	}
+
+	if declared.seLinuxType != "" && actual.seLinuxContext != "" {
+		// the SELinux context is user:role:type:level
+		if fields := strings.SplitN(actual.seLinuxContext, ":", 4); len(fields) >= 3 && fields[2] != declared.seLinuxType {
+			drifts = append(drifts, ConfinementDrift{
+				Kind:     confinementKindSELinux,
+				Declared: declared.seLinuxType,
+				Actual:   actual.seLinuxContext,
+			})
+		}
+	}
+
+	return drifts
+}
+
+// isAppArmorLabelCompliant returns whether an AppArmor label, e.g. `docker-default (enforce)`, enforces the declared
+// profile: the runtime default profile is accepted for runtime/default, the named profile is required for
+// localhost/<profile>.
+func isAppArmorLabelCompliant(declared string, label string) bool {
+	profile := strings.TrimSuffix(label, " (enforce)")
+	if profile == label {
+		// unconfined, or a profile in complain mode
+		return false
+	}
+
+	if localProfile := strings.TrimPrefix(declared, "localhost/"); localProfile != declared {
+		return profile == localProfile
+	}
+	return true
+}
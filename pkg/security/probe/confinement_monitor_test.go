@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfinementDrifts(t *testing.T) {
+	tests := []struct {
+		name     string
+		declared declaredConfinement
+		actual   processConfinement
+		expected []ConfinementDrift
+	}{
+		{
+			name:     "compliant",
+			declared: declaredConfinement{seccomp: "RuntimeDefault", appArmor: "runtime/default", seLinuxType: "container_t"},
+			actual: processConfinement{
+				seccompMode:    "filter",
+				appArmorLabel:  "cri-containerd.apparmor.d (enforce)",
+				seLinuxContext: "system_u:system_r:container_t:s0:c123,c456",
+			},
+		},
+		{
+			name:     "unconfined declared",
+			declared: declaredConfinement{seccomp: "Unconfined", appArmor: "unconfined"},
+			actual:   processConfinement{seccompMode: "disabled", appArmorLabel: "unconfined"},
+		},
+		{
+			name:     "unknown actual confinement",
+			declared: declaredConfinement{seccomp: "RuntimeDefault", appArmor: "runtime/default", seLinuxType: "container_t"},
+			actual:   processConfinement{},
+		},
+		{
+			name:     "looser confinement",
+			declared: declaredConfinement{seccomp: "Localhost/profiles/app.json", appArmor: "localhost/app", seLinuxType: "container_t"},
+			actual: processConfinement{
+				seccompMode:    "disabled",
+				appArmorLabel:  "docker-default (enforce)",
+				seLinuxContext: "system_u:system_r:spc_t:s0",
+			},
+			expected: []ConfinementDrift{
+				{Kind: "seccomp", Declared: "Localhost/profiles/app.json", Actual: "disabled"},
+				{Kind: "apparmor", Declared: "localhost/app", Actual: "docker-default (enforce)"},
+				{Kind: "selinux", Declared: "container_t", Actual: "system_u:system_r:spc_t:s0"},
+			},
+		},
+		{
+			name:     "apparmor profile in complain mode",
+			declared: declaredConfinement{appArmor: "localhost/app"},
+			actual:   processConfinement{appArmorLabel: "app (complain)"},
+			expected: []ConfinementDrift{
+				{Kind: "apparmor", Declared: "localhost/app", Actual: "app (complain)"},
+			},
+		},
+		{
+			name:     "apparmor label read as the selinux context",
+			declared: declaredConfinement{seLinuxType: "container_t"},
+			actual:   processConfinement{seLinuxContext: "unconfined"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, confinementDrifts(tt.declared, tt.actual))
+		})
+	}
+}
@@ -28,6 +28,8 @@ const (
 	NoisyProcessRuleID = "noisy_process"
 	// AbnormalPathRuleID is the rule ID for the abnormal_path events
 	AbnormalPathRuleID = "abnormal_path"
+	// ConfinementDriftRuleID is the rule ID for the confinement_drift events
+	ConfinementDriftRuleID = "confinement_drift"
 )
 
 // AllCustomRuleIDs returns the list of custom rule IDs
@@ -37,6 +39,7 @@ func AllCustomRuleIDs() []string {
 		RulesetLoadedRuleID,
 		NoisyProcessRuleID,
 		AbnormalPathRuleID,
+		ConfinementDriftRuleID,
 	}
 }
 
@@ -199,14 +202,16 @@ type PolicyLoaded struct {
 // RulesetLoadedEvent is used to report that a new ruleset was loaded
 // easyjson:json
 type RulesetLoadedEvent struct {
-	Timestamp       time.Time        `json:"date"`
-	PoliciesLoaded  []*PolicyLoaded  `json:"policies"`
-	PoliciesIgnored *PoliciesIgnored `json:"policies_ignored,omitempty"`
-	MacrosLoaded    []rules.MacroID  `json:"macros_loaded"`
+	Timestamp       time.Time         `json:"date"`
+	PoliciesLoaded  []*PolicyLoaded   `json:"policies"`
+	PoliciesIgnored *PoliciesIgnored  `json:"policies_ignored,omitempty"`
+	MacrosLoaded    []rules.MacroID   `json:"macros_loaded"`
+	PoliciesBundles map[string]string `json:"policies_bundles,omitempty"`
 }
 
-// NewRuleSetLoadedEvent returns the rule and a populated custom event for a new_rules_loaded event
-func NewRuleSetLoadedEvent(rs *rules.RuleSet, err *multierror.Error) (*rules.Rule, *CustomEvent) {
+// NewRuleSetLoadedEvent returns the rule and a populated custom event for a new_rules_loaded event,
+// bundlesVersions holds the versions of the loaded policies bundles
+func NewRuleSetLoadedEvent(rs *rules.RuleSet, err *multierror.Error, bundlesVersions map[string]string) (*rules.Rule, *CustomEvent) {
 	mp := make(map[string]*PolicyLoaded)
 
 	var policy *PolicyLoaded
@@ -259,6 +264,7 @@ func NewRuleSetLoadedEvent(rs *rules.RuleSet, err *multierror.Error) (*rules.Rul
 			PoliciesLoaded:  policies,
 			PoliciesIgnored: &PoliciesIgnored{Errors: err},
 			MacrosLoaded:    rs.ListMacroIDs(),
+			PoliciesBundles: bundlesVersions,
 		}.MarshalJSON)
 }
 
@@ -320,3 +326,65 @@ func NewAbnormalPathEvent(event *Event, pathResolutionError error) (*rules.Rule,
 			PathResolutionError: pathResolutionError.Error(),
 		}.MarshalJSON)
 }
+
+// ConfinementDrift describes how the confinement of a process is looser than the one declared for its container
+// easyjson:json
+type ConfinementDrift struct {
+	Kind     string `json:"kind"`
+	Declared string `json:"declared"`
+	Actual   string `json:"actual"`
+}
+
+// ConfinementDriftEvent is used to report that a process of a container runs with a looser confinement than the one
+// declared by its pod spec
+// easyjson:json
+type ConfinementDriftEvent struct {
+	Timestamp time.Time          `json:"date"`
+	Event     *EventSerializer   `json:"triggering_event"`
+	Drifts    []ConfinementDrift `json:"drifts"`
+}
+
+// NewConfinementDriftEvent returns the rule and a populated custom event for a confinement_drift event
+func NewConfinementDriftEvent(event *Event, drifts []ConfinementDrift) (*rules.Rule, *CustomEvent) {
+	return newRule(&rules.RuleDefinition{
+			ID: ConfinementDriftRuleID,
+		}), newCustomEvent(model.CustomConfinementDriftEventType, ConfinementDriftEvent{
+			Timestamp: event.ResolveEventTimestamp(),
+			Event:     NewEventSerializer(event),
+			Drifts:    drifts,
+		}.MarshalJSON)
+}
+
+// ProcessAccessSummary is the number of accesses of an executable to a file during an aggregation window
+// easyjson:json
+type ProcessAccessSummary struct {
+	Path     string `json:"path"`
+	Accesses int    `json:"accesses"`
+}
+
+// FileAccessSummary is the summary of the accesses to a file during an aggregation window
+// easyjson:json
+type FileAccessSummary struct {
+	Path      string                 `json:"path"`
+	Accesses  int                    `json:"accesses"`
+	Processes []ProcessAccessSummary `json:"processes"`
+	Users     []string               `json:"users"`
+}
+
+// FileAccessSummaryEvent is used to report the accesses matched by an aggregated rule during a window,
+// instead of an event per access
+// easyjson:json
+type FileAccessSummaryEvent struct {
+	Timestamp   time.Time           `json:"date"`
+	WindowStart time.Time           `json:"window_start"`
+	Accesses    int                 `json:"accesses"`
+	Files       []FileAccessSummary `json:"files"`
+	// UntrackedAccesses are the accesses to the files exceeding the number of files of a summary
+	UntrackedAccesses int `json:"untracked_accesses"`
+}
+
+// NewFileAccessSummaryEvent returns a populated custom event for a file_access_summary event. The
+// event is sent with the aggregated rule.
+func NewFileAccessSummaryEvent(summary FileAccessSummaryEvent) *CustomEvent {
+	return newCustomEvent(model.CustomFileAccessSummaryEventType, summary.MarshalJSON)
+}
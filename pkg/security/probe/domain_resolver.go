@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+)
+
+// reverseDNS resolves IP addresses to the domains they were resolved from
+type reverseDNS interface {
+	Resolve([]util.Address) map[util.Address][]string
+	Close()
+}
+
+// DomainResolver resolves the IP addresses of the connect events to the domains they were resolved
+// from, according to the DNS responses snooped on the host
+type DomainResolver struct {
+	reverseDNS reverseDNS
+}
+
+// NewDomainResolver returns a new domain resolver, which snoops the DNS responses only when the
+// domain resolution is enabled
+func NewDomainResolver(config *config.Config) (*DomainResolver, error) {
+	if !config.DomainResolutionEnabled {
+		return &DomainResolver{}, nil
+	}
+
+	reverseDNS, err := newReverseDNS()
+	if err != nil {
+		return nil, err
+	}
+	return &DomainResolver{reverseDNS: reverseDNS}, nil
+}
+
+// Resolve returns the domains an IP address was resolved from
+func (r *DomainResolver) Resolve(ip string) []string {
+	if r.reverseDNS == nil || ip == "" {
+		return nil
+	}
+
+	addr := util.AddressFromString(ip)
+	if addr == nil {
+		return nil
+	}
+	return r.reverseDNS.Resolve([]util.Address{addr})[addr]
+}
+
+// Close stops snooping the DNS responses
+func (r *DomainResolver) Close() {
+	if r.reverseDNS != nil {
+		r.reverseDNS.Close()
+	}
+}
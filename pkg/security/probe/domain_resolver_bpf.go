@@ -0,0 +1,20 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux_bpf
+
+package probe
+
+import (
+	netconfig "github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
+)
+
+func newReverseDNS() (reverseDNS, error) {
+	cfg := netconfig.New()
+	// only the reverse resolution is used, not the DNS stats
+	cfg.CollectDNSStats = false
+	return dns.NewReverseDNS(cfg)
+}
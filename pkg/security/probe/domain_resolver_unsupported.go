@@ -0,0 +1,14 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux,!linux_bpf
+
+package probe
+
+import "errors"
+
+func newReverseDNS() (reverseDNS, error) {
+	return nil, errors.New("the domain resolution requires a build with eBPF support")
+}
@@ -151,6 +151,35 @@ func (ev *Event) ResolveMountRoot(e *model.MountEvent) string {
 	return e.RootStr
 }
 
+// ResolveMountFSType resolves the filesystem type of the mount
+func (ev *Event) ResolveMountFSType(e *model.MountEvent) string {
+	return e.GetFSType()
+}
+
+// ResolveMountPointContainerID resolves the ID of the container whose overlay root filesystem holds the mount point
+func (ev *Event) ResolveMountPointContainerID(e *model.MountEvent) string {
+	if len(e.MountPointContainerID) == 0 {
+		e.MountPointContainerID = ev.resolvers.MountResolver.GetOverlayContainerID(e.ParentMountID)
+	}
+	return e.MountPointContainerID
+}
+
+// ResolveUmountFSType resolves the filesystem type of the unmounted mount
+func (ev *Event) ResolveUmountFSType(e *model.UmountEvent) string {
+	if len(e.FSType) == 0 {
+		e.FSType = ev.resolvers.MountResolver.GetFilesystem(e.MountID)
+	}
+	return e.FSType
+}
+
+// ResolveUmountPoint resolves the mount point of the unmounted mount to a full path
+func (ev *Event) ResolveUmountPoint(e *model.UmountEvent) string {
+	if len(e.MountPointStr) == 0 {
+		_, e.MountPointStr, _, _ = ev.resolvers.MountResolver.GetMountPath(e.MountID)
+	}
+	return e.MountPointStr
+}
+
 // ResolveContainerID resolves the container ID of the event
 func (ev *Event) ResolveContainerID(e *model.ContainerContext) string {
 	if len(e.ID) == 0 {
@@ -187,10 +216,20 @@ func (ev *Event) UnmarshalProcess(data []byte) (int, error) {
 	return n, nil
 }
 
+// resolveUser resolves a user id to a username, with the user files of the container of the event
+func (ev *Event) resolveUser(uid uint32) (string, error) {
+	return ev.resolvers.UserGroupResolver.ResolveUser(int(uid), ev.ResolveContainerID(&ev.ContainerContext), ev.ProcessContext.Pid)
+}
+
+// resolveGroup resolves a group id to a group name, with the group files of the container of the event
+func (ev *Event) resolveGroup(gid uint32) (string, error) {
+	return ev.resolvers.UserGroupResolver.ResolveGroup(int(gid), ev.ResolveContainerID(&ev.ContainerContext), ev.ProcessContext.Pid)
+}
+
 // ResolveFileFieldsUser resolves the user id of the file to a username
 func (ev *Event) ResolveFileFieldsUser(e *model.FileFields) string {
 	if len(e.User) == 0 {
-		e.User, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.UID))
+		e.User, _ = ev.resolveUser(e.UID)
 	}
 	return e.User
 }
@@ -198,7 +237,7 @@ func (ev *Event) ResolveFileFieldsUser(e *model.FileFields) string {
 // ResolveFileFieldsGroup resolves the group id of the file to a group name
 func (ev *Event) ResolveFileFieldsGroup(e *model.FileFields) string {
 	if len(e.Group) == 0 {
-		e.Group, _ = ev.resolvers.UserGroupResolver.ResolveGroup(int(e.GID))
+		e.Group, _ = ev.resolveGroup(e.GID)
 	}
 	return e.Group
 }
@@ -211,7 +250,7 @@ func (ev *Event) ResolveRights(e *model.FileFields) int {
 // ResolveChownUID resolves the user id of a chown event to a username
 func (ev *Event) ResolveChownUID(e *model.ChownEvent) string {
 	if len(e.User) == 0 {
-		e.User, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.UID))
+		e.User, _ = ev.resolveUser(e.UID)
 	}
 	return e.User
 }
@@ -219,7 +258,7 @@ func (ev *Event) ResolveChownUID(e *model.ChownEvent) string {
 // ResolveChownGID resolves the group id of a chown event to a group name
 func (ev *Event) ResolveChownGID(e *model.ChownEvent) string {
 	if len(e.Group) == 0 {
-		e.Group, _ = ev.resolvers.UserGroupResolver.ResolveGroup(int(e.GID))
+		e.Group, _ = ev.resolveGroup(e.GID)
 	}
 	return e.Group
 }
@@ -332,7 +371,7 @@ func (ev *Event) ResolveExecEnvs(e *model.ExecEvent) []string {
 // ResolveSetuidUser resolves the user of the Setuid event
 func (ev *Event) ResolveSetuidUser(e *model.SetuidEvent) string {
 	if len(e.User) == 0 && ev != nil {
-		e.User, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.UID))
+		e.User, _ = ev.resolveUser(e.UID)
 	}
 	return e.User
 }
@@ -340,7 +379,7 @@ func (ev *Event) ResolveSetuidUser(e *model.SetuidEvent) string {
 // ResolveSetuidEUser resolves the effective user of the Setuid event
 func (ev *Event) ResolveSetuidEUser(e *model.SetuidEvent) string {
 	if len(e.EUser) == 0 && ev != nil {
-		e.EUser, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.EUID))
+		e.EUser, _ = ev.resolveUser(e.EUID)
 	}
 	return e.EUser
 }
@@ -348,7 +387,7 @@ func (ev *Event) ResolveSetuidEUser(e *model.SetuidEvent) string {
 // ResolveSetuidFSUser resolves the file-system user of the Setuid event
 func (ev *Event) ResolveSetuidFSUser(e *model.SetuidEvent) string {
 	if len(e.FSUser) == 0 && ev != nil {
-		e.FSUser, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.FSUID))
+		e.FSUser, _ = ev.resolveUser(e.FSUID)
 	}
 	return e.FSUser
 }
@@ -356,7 +395,7 @@ func (ev *Event) ResolveSetuidFSUser(e *model.SetuidEvent) string {
 // ResolveSetgidGroup resolves the group of the Setgid event
 func (ev *Event) ResolveSetgidGroup(e *model.SetgidEvent) string {
 	if len(e.Group) == 0 && ev != nil {
-		e.Group, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.GID))
+		e.Group, _ = ev.resolveGroup(e.GID)
 	}
 	return e.Group
 }
@@ -364,7 +403,7 @@ func (ev *Event) ResolveSetgidGroup(e *model.SetgidEvent) string {
 // ResolveSetgidEGroup resolves the effective group of the Setgid event
 func (ev *Event) ResolveSetgidEGroup(e *model.SetgidEvent) string {
 	if len(e.EGroup) == 0 && ev != nil {
-		e.EGroup, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.EGID))
+		e.EGroup, _ = ev.resolveGroup(e.EGID)
 	}
 	return e.EGroup
 }
@@ -372,7 +411,7 @@ func (ev *Event) ResolveSetgidEGroup(e *model.SetgidEvent) string {
 // ResolveSetgidFSGroup resolves the file-system group of the Setgid event
 func (ev *Event) ResolveSetgidFSGroup(e *model.SetgidEvent) string {
 	if len(e.FSGroup) == 0 && ev != nil {
-		e.FSGroup, _ = ev.resolvers.UserGroupResolver.ResolveUser(int(e.FSGID))
+		e.FSGroup, _ = ev.resolveGroup(e.FSGID)
 	}
 	return e.FSGroup
 }
@@ -389,6 +428,14 @@ func (ev *Event) ResolveSELinuxBoolName(e *model.SELinuxEvent) string {
 	return ev.SELinux.BoolName
 }
 
+// ResolveConnectDomains resolves the destination IP address of the connect event to the domains it was resolved from
+func (ev *Event) ResolveConnectDomains(e *model.ConnectEvent) []string {
+	if len(e.Domains) == 0 {
+		e.Domains = ev.resolvers.DomainResolver.Resolve(e.Addr.IP)
+	}
+	return e.Domains
+}
+
 func (ev *Event) String() string {
 	d, err := json.Marshal(ev)
 	if err != nil {
@@ -80,6 +80,9 @@ type MountResolver struct {
 	mounts      map[uint32]*model.MountEvent
 	devices     map[uint32]map[uint32]*model.MountEvent
 	deleteQueue []deleteRequest
+	// overlayContainers maps the device of an overlayfs, shared by all the mounts of the same
+	// upper layer, to the ID of the container using it as root filesystem
+	overlayContainers map[uint32]string
 }
 
 // SyncCache - Snapshots the current mount points of the system by reading through /proc/[pid]/mountinfo.
@@ -143,6 +146,9 @@ func (mr *MountResolver) delete(mount *model.MountEvent) {
 	mounts, exists := mr.devices[mount.Device]
 	if exists {
 		delete(mounts, mount.MountID)
+		if len(mounts) == 0 {
+			delete(mr.overlayContainers, mount.Device)
+		}
 	}
 
 	mr.deleteChildren(mount)
@@ -177,6 +183,43 @@ func (mr *MountResolver) GetFilesystem(mountID uint32) string {
 	return mount.GetFSType()
 }
 
+// SetOverlayContainerID associates the overlayfs of a mountID, and all the other mounts of
+// the same upper layer, with the container using it as root filesystem
+func (mr *MountResolver) SetOverlayContainerID(mountID uint32, containerID string) {
+	mr.lock.Lock()
+	defer mr.lock.Unlock()
+
+	mount, exists := mr.mounts[mountID]
+	if !exists || !mount.IsOverlayFS() {
+		return
+	}
+	mr.overlayContainers[mount.Device] = containerID
+}
+
+// GetOverlayContainerID returns the ID of the container whose overlay root filesystem holds
+// the given mountID, looking up the parent mounts
+func (mr *MountResolver) GetOverlayContainerID(mountID uint32) string {
+	mr.lock.RLock()
+	defer mr.lock.RUnlock()
+
+	visited := make(map[uint32]bool)
+	for mountID != 0 && !visited[mountID] {
+		visited[mountID] = true
+
+		mount, exists := mr.mounts[mountID]
+		if !exists {
+			return ""
+		}
+		if mount.IsOverlayFS() {
+			if containerID, found := mr.overlayContainers[mount.Device]; found {
+				return containerID
+			}
+		}
+		mountID = mount.ParentMountID
+	}
+	return ""
+}
+
 // IsOverlayFS returns the type of a mountID
 func (mr *MountResolver) IsOverlayFS(mountID uint32) bool {
 	mr.lock.RLock()
@@ -413,9 +456,10 @@ func getSuperBlockMagicOffset(probe *Probe) uint64 {
 // NewMountResolver instantiates a new mount resolver
 func NewMountResolver(probe *Probe) *MountResolver {
 	return &MountResolver{
-		probe:   probe,
-		lock:    sync.RWMutex{},
-		devices: make(map[uint32]map[uint32]*model.MountEvent),
-		mounts:  make(map[uint32]*model.MountEvent),
+		probe:             probe,
+		lock:              sync.RWMutex{},
+		devices:           make(map[uint32]map[uint32]*model.MountEvent),
+		mounts:            make(map[uint32]*model.MountEvent),
+		overlayContainers: make(map[uint32]string),
 	}
 }
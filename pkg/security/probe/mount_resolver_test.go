@@ -386,3 +386,30 @@ func BenchmarkGetParentPath(b *testing.B) {
 		_ = mr.getParentPath(0)
 	}
 }
+
+func TestGetOverlayContainerID(t *testing.T) {
+	mr := NewMountResolver(nil)
+	// overlay root filesystem of a container, mounted twice
+	mr.insert(model.MountEvent{MountID: 10, ParentMountID: 1, Device: 52, FSType: "overlay", MountPointStr: "/var/lib/docker/overlay2/f44b5a1fe134f57a31da79fa2e76ea09f8659a34edfa0fa2c3b4f52adbd91963/merged"})
+	mr.insert(model.MountEvent{MountID: 11, ParentMountID: 1, Device: 52, FSType: "overlay", MountPointStr: "/"})
+	// bind mount inside of the container root filesystem
+	mr.insert(model.MountEvent{MountID: 12, ParentMountID: 10, Device: 8, FSType: "ext4", MountPointStr: "/etc/hostname"})
+	// mount on the host
+	mr.insert(model.MountEvent{MountID: 13, ParentMountID: 1, Device: 8, FSType: "ext4", MountPointStr: "/mnt"})
+
+	assert.Equal(t, "", mr.GetOverlayContainerID(12))
+
+	// the ID isn't set for non overlay mounts
+	mr.SetOverlayContainerID(13, "abc")
+	assert.Equal(t, "", mr.GetOverlayContainerID(13))
+
+	mr.SetOverlayContainerID(11, "abc")
+	assert.Equal(t, "abc", mr.GetOverlayContainerID(10))
+	assert.Equal(t, "abc", mr.GetOverlayContainerID(11))
+	assert.Equal(t, "abc", mr.GetOverlayContainerID(12))
+	assert.Equal(t, "", mr.GetOverlayContainerID(13))
+
+	// the association is released with the mounts of the upper layer
+	mr.delete(mr.mounts[10])
+	assert.Equal(t, 0, len(mr.overlayContainers))
+}
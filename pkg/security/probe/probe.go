@@ -11,6 +11,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -386,6 +387,13 @@ func (p *Probe) handleEvent(CPU uint64, data []byte) {
 		err = p.resolvers.MountResolver.Insert(event.Mount)
 		if err != nil {
 			log.Errorf("failed to insert mount event: %v", err)
+		} else if event.Mount.IsOverlayFS() {
+			// containerd mounts the root filesystem of the containers in <bundle>/<container id>/rootfs
+			if mountPoint := event.ResolveMountPoint(&event.Mount); path.Base(mountPoint) == "rootfs" {
+				if containerID := model.FindContainerID(path.Dir(mountPoint)); containerID != "" {
+					p.resolvers.MountResolver.SetOverlayContainerID(event.Mount.MountID, containerID)
+				}
+			}
 		}
 
 		// There could be entries of a previous mount_id in the cache for instance,
@@ -510,6 +518,12 @@ func (p *Probe) handleEvent(CPU uint64, data []byte) {
 		// copy some of the field from the entry
 		event.Exec.Process = event.processCacheEntry.Process
 		event.Exec.FileFields = event.processCacheEntry.Process.FileFields
+
+		// the executables of a container are on its overlay root filesystem, which maps the
+		// upper layer back to the container
+		if containerID := event.processCacheEntry.ContainerID; containerID != "" {
+			p.resolvers.MountResolver.SetOverlayContainerID(event.Exec.FileFields.MountID, containerID)
+		}
 	case model.ExitEventType:
 		defer p.resolvers.ProcessResolver.DeleteEntry(event.ProcessContext.Pid, event.ResolveEventTimestamp())
 	case model.SetuidEventType:
@@ -535,6 +549,16 @@ func (p *Probe) handleEvent(CPU uint64, data []byte) {
 			log.Errorf("failed to decode selinux event: %s (offset %d, len %d)", err, offset, len(data))
 			return
 		}
+	case model.ConnectEventType:
+		if _, err = event.Connect.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode connect event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
+	case model.BindEventType:
+		if _, err = event.Bind.UnmarshalBinary(data[offset:]); err != nil {
+			log.Errorf("failed to decode bind event: %s (offset %d, len %d)", err, offset, len(data))
+			return
+		}
 	default:
 		log.Errorf("unsupported event type %d", eventType)
 		return
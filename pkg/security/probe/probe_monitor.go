@@ -27,10 +27,11 @@ type Monitor struct {
 	probe  *Probe
 	client *statsd.Client
 
-	loadController    *LoadController
-	perfBufferMonitor *PerfBufferMonitor
-	syscallMonitor    *SyscallMonitor
-	reordererMonitor  *ReordererMonitor
+	loadController     *LoadController
+	perfBufferMonitor  *PerfBufferMonitor
+	syscallMonitor     *SyscallMonitor
+	reordererMonitor   *ReordererMonitor
+	confinementMonitor *ConfinementMonitor
 }
 
 // NewMonitor returns a new instance of a ProbeMonitor
@@ -58,6 +59,13 @@ func NewMonitor(p *Probe, client *statsd.Client) (*Monitor, error) {
 		return nil, errors.Wrap(err, "couldn't create the reorder monitor")
 	}
 
+	if p.config.ConfinementDriftEnabled {
+		m.confinementMonitor, err = NewConfinementMonitor(p)
+		if err != nil {
+			return nil, errors.Wrap(err, "couldn't create the confinement monitor")
+		}
+	}
+
 	// create a new syscall monitor if requested
 	if p.config.SyscallMonitor {
 		m.syscallMonitor, err = NewSyscallMonitor(p.manager)
@@ -138,6 +146,10 @@ func (m *Monitor) ProcessEvent(event *Event, size uint64, CPU int, perfMap *mana
 			NewAbnormalPathEvent(event, err),
 		)
 	}
+
+	if m.confinementMonitor != nil {
+		m.confinementMonitor.ProcessEvent(event)
+	}
 }
 
 // ProcessLostEvent processes a lost event through the various monitors and controllers of the probe
@@ -153,8 +165,8 @@ type RuleSetLoadedReport struct {
 }
 
 // PrepareRuleSetLoadedReport prepares a report of new loaded ruleset
-func (m *Monitor) PrepareRuleSetLoadedReport(ruleSet *rules.RuleSet, err *multierror.Error) RuleSetLoadedReport {
-	r, ev := NewRuleSetLoadedEvent(ruleSet, err)
+func (m *Monitor) PrepareRuleSetLoadedReport(ruleSet *rules.RuleSet, err *multierror.Error, bundlesVersions map[string]string) RuleSetLoadedReport {
+	r, ev := NewRuleSetLoadedEvent(ruleSet, err, bundlesVersions)
 	return RuleSetLoadedReport{Rule: r, Event: ev}
 }
 
@@ -33,6 +33,7 @@ type Resolvers struct {
 	ProcessResolver   *ProcessResolver
 	UserGroupResolver *UserGroupResolver
 	TagsResolver      *TagsResolver
+	DomainResolver    *DomainResolver
 }
 
 // NewResolvers creates a new instance of Resolvers
@@ -47,7 +48,12 @@ func NewResolvers(config *config.Config, probe *Probe) (*Resolvers, error) {
 		return nil, err
 	}
 
-	userGroupResolver, err := NewUserGroupResolver()
+	userGroupResolver, err := NewUserGroupResolver(config)
+	if err != nil {
+		return nil, err
+	}
+
+	domainResolver, err := NewDomainResolver(config)
 	if err != nil {
 		return nil, err
 	}
@@ -60,6 +66,7 @@ func NewResolvers(config *config.Config, probe *Probe) (*Resolvers, error) {
 		ContainerResolver: &ContainerResolver{},
 		UserGroupResolver: userGroupResolver,
 		TagsResolver:      NewTagsResolver(config),
+		DomainResolver:    domainResolver,
 	}
 
 	processResolver, err := NewProcessResolver(probe, resolvers, probe.statsdClient, NewProcessResolverOpts(probe.config.CookieCacheSize))
@@ -97,70 +104,24 @@ func (r *Resolvers) resolveFileFieldsPath(e *model.FileFields) (string, error) {
 	return pathStr, err
 }
 
-// ResolveFileFieldsUser resolves the user id of the file to a username
-func (r *Resolvers) ResolveFileFieldsUser(e *model.FileFields) string {
-	if len(e.User) == 0 {
-		e.User, _ = r.UserGroupResolver.ResolveUser(int(e.UID))
-	}
-	return e.User
-}
-
-// ResolveFileFieldsGroup resolves the group id of the file to a group name
-func (r *Resolvers) ResolveFileFieldsGroup(e *model.FileFields) string {
-	if len(e.Group) == 0 {
-		e.Group, _ = r.UserGroupResolver.ResolveGroup(int(e.GID))
-	}
-	return e.Group
-}
-
-// ResolveCredentialsUser resolves the user id of the process to a username
-func (r *Resolvers) ResolveCredentialsUser(e *model.Credentials) string {
+// ResolveFileFieldsUser resolves the user id of the file to a username, with the
+// user files of the container of the process pid
+func (r *Resolvers) ResolveFileFieldsUser(e *model.FileFields, containerID string, pid uint32) string {
 	if len(e.User) == 0 {
-		e.User, _ = r.UserGroupResolver.ResolveUser(int(e.UID))
+		e.User, _ = r.UserGroupResolver.ResolveUser(int(e.UID), containerID, pid)
 	}
 	return e.User
 }
 
-// ResolveCredentialsGroup resolves the group id of the process to a group name
-func (r *Resolvers) ResolveCredentialsGroup(e *model.Credentials) string {
+// ResolveFileFieldsGroup resolves the group id of the file to a group name, with the
+// group files of the container of the process pid
+func (r *Resolvers) ResolveFileFieldsGroup(e *model.FileFields, containerID string, pid uint32) string {
 	if len(e.Group) == 0 {
-		e.Group, _ = r.UserGroupResolver.ResolveGroup(int(e.GID))
+		e.Group, _ = r.UserGroupResolver.ResolveGroup(int(e.GID), containerID, pid)
 	}
 	return e.Group
 }
 
-// ResolveCredentialsEUser resolves the effective user id of the process to a username
-func (r *Resolvers) ResolveCredentialsEUser(e *model.Credentials) string {
-	if len(e.EUser) == 0 {
-		e.EUser, _ = r.UserGroupResolver.ResolveUser(int(e.EUID))
-	}
-	return e.EUser
-}
-
-// ResolveCredentialsEGroup resolves the effective group id of the process to a group name
-func (r *Resolvers) ResolveCredentialsEGroup(e *model.Credentials) string {
-	if len(e.EGroup) == 0 {
-		e.EGroup, _ = r.UserGroupResolver.ResolveGroup(int(e.EGID))
-	}
-	return e.EGroup
-}
-
-// ResolveCredentialsFSUser resolves the file-system user id of the process to a username
-func (r *Resolvers) ResolveCredentialsFSUser(e *model.Credentials) string {
-	if len(e.FSUser) == 0 {
-		e.FSUser, _ = r.UserGroupResolver.ResolveUser(int(e.FSUID))
-	}
-	return e.FSUser
-}
-
-// ResolveCredentialsFSGroup resolves the file-system group id of the process to a group name
-func (r *Resolvers) ResolveCredentialsFSGroup(e *model.Credentials) string {
-	if len(e.FSGroup) == 0 {
-		e.FSGroup, _ = r.UserGroupResolver.ResolveGroup(int(e.FSGID))
-	}
-	return e.FSGroup
-}
-
 // Start the resolvers
 func (r *Resolvers) Start(ctx context.Context) error {
 	if err := r.ProcessResolver.Start(ctx); err != nil {
@@ -246,6 +207,8 @@ func (r *Resolvers) snapshot() error {
 
 // Close cleans up any underlying resolver that requires a cleanup
 func (r *Resolvers) Close() error {
+	r.DomainResolver.Close()
+
 	// clean up the dentry resolver eRPC segment
 	return r.DentryResolver.Close()
 }
@@ -24,6 +24,7 @@ const (
 	FIMCategory     = "File Activity"
 	ProcessActivity = "Process Activity"
 	KernelActivity  = "Kernel Activity"
+	NetworkActivity = "Network Activity"
 )
 
 // FileSerializer serializes a file to JSON
@@ -207,6 +208,21 @@ type SELinuxEventSerializer struct {
 	BoolCommit    *selinuxBoolCommitSerializer    `json:"bool_commit,omitempty" jsonschema_description:"SELinux boolean commit"`
 }
 
+// IPPortSerializer serializes an IP address and a port to JSON
+// easyjson:json
+type IPPortSerializer struct {
+	Family  string   `json:"family" jsonschema_description:"Address family"`
+	IP      string   `json:"ip" jsonschema_description:"IP address"`
+	Port    uint16   `json:"port" jsonschema_description:"Port number"`
+	Domains []string `json:"domains,omitempty" jsonschema_description:"Domains that the IP address was resolved from"`
+}
+
+// NetworkEventSerializer serializes a connect or bind event to JSON
+// easyjson:json
+type NetworkEventSerializer struct {
+	Addr IPPortSerializer `json:"addr" jsonschema_description:"Address of the socket"`
+}
+
 // DDContextSerializer serializes a span context to JSON
 // easyjson:json
 type DDContextSerializer struct {
@@ -220,6 +236,7 @@ type EventSerializer struct {
 	*EventContextSerializer    `json:"evt,omitempty"`
 	*FileEventSerializer       `json:"file,omitempty"`
 	*SELinuxEventSerializer    `json:"selinux,omitempty"`
+	*NetworkEventSerializer    `json:"network,omitempty"`
 	UserContextSerializer      UserContextSerializer       `json:"usr,omitempty"`
 	ProcessContextSerializer   *ProcessContextSerializer   `json:"process,omitempty"`
 	DDContextSerializer        *DDContextSerializer        `json:"dd,omitempty"`
@@ -274,8 +291,8 @@ func newProcessFileSerializerWithResolvers(process *model.Process, r *Resolvers)
 		Mode:                getUint32Pointer(&mode),
 		UID:                 process.FileFields.UID,
 		GID:                 process.FileFields.GID,
-		User:                r.ResolveFileFieldsUser(&process.FileFields),
-		Group:               r.ResolveFileFieldsGroup(&process.FileFields),
+		User:                r.ResolveFileFieldsUser(&process.FileFields, process.ContainerID, process.Pid),
+		Group:               r.ResolveFileFieldsGroup(&process.FileFields, process.ContainerID, process.Pid),
 		Mtime:               getTimeIfNotZero(time.Unix(0, int64(process.FileFields.MTime))),
 		Ctime:               getTimeIfNotZero(time.Unix(0, int64(process.FileFields.CTime))),
 	}
@@ -473,6 +490,19 @@ func newSELinuxSerializer(e *Event) *SELinuxEventSerializer {
 	}
 }
 
+func newIPPortSerializer(addr *model.IPPortContext, domains []string) IPPortSerializer {
+	family := "AF_INET"
+	if addr.Family == syscall.AF_INET6 {
+		family = "AF_INET6"
+	}
+	return IPPortSerializer{
+		Family:  family,
+		IP:      addr.IP,
+		Port:    addr.Port,
+		Domains: domains,
+	}
+}
+
 func serializeSyscallRetval(retval int64) string {
 	switch {
 	case syscall.Errno(retval) == syscall.EACCES || syscall.Errno(retval) == syscall.EPERM:
@@ -669,6 +699,18 @@ func NewEventSerializer(event *Event) *EventSerializer {
 		}
 		s.SELinuxEventSerializer = newSELinuxSerializer(event)
 		s.Category = KernelActivity
+	case model.ConnectEventType:
+		s.NetworkEventSerializer = &NetworkEventSerializer{
+			Addr: newIPPortSerializer(&event.Connect.Addr, event.ResolveConnectDomains(&event.Connect)),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Connect.Retval)
+		s.Category = NetworkActivity
+	case model.BindEventType:
+		s.NetworkEventSerializer = &NetworkEventSerializer{
+			Addr: newIPPortSerializer(&event.Bind.Addr, nil),
+		}
+		s.EventContextSerializer.Outcome = serializeSyscallRetval(event.Bind.Retval)
+		s.Category = NetworkActivity
 	}
 
 	return s
@@ -8,64 +8,278 @@
 package probe
 
 import (
+	"bufio"
+	"fmt"
+	"os"
 	"os/user"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	lru "github.com/hashicorp/golang-lru"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	userGroupFilesCacheSize = 256
+	nssCacheSize            = 1024
+	// userGroupFilesErrorTTL is how long a failure to read the files of a container is cached,
+	// it is shorter than the cache TTL as another process of the container may have them readable
+	userGroupFilesErrorTTL = 30 * time.Second
 )
 
-// UserGroupResolver resolves user and group ids to names
+// nssLocalSources are the NSS sources already covered by the parsing of the user and group files
+var nssLocalSources = map[string]bool{
+	"files":  true,
+	"compat": true,
+}
+
+// userGroupFiles holds the users and groups of an /etc/passwd and /etc/group pair of files,
+// or the error of their reading
+type userGroupFiles struct {
+	users    map[int]string
+	groups   map[int]string
+	err      error
+	loadedAt time.Time
+}
+
+// nssEntry is the result of a NSS lookup, the name is empty when it wasn't found
+type nssEntry struct {
+	name       string
+	resolvedAt time.Time
+}
+
+// UserGroupResolver resolves user and group ids to names.
+//
+// The ids of the host processes and files are resolved with the /etc/passwd and
+// /etc/group files of the host, and the ones of the container processes with the
+// files of their container, read through /proc/<pid>/root. The files are reloaded
+// once they are older than the cache TTL.
+//
+// The ids missing from the host files can be looked up with NSS, following the
+// nsswitch.conf of the host, which can query a directory like LDAP. NSS lookups
+// are slow, so they are disabled by default and their result is cached, whether
+// the id was found or not.
 type UserGroupResolver struct {
-	userCache  *lru.Cache
-	groupCache *lru.Cache
+	procRoot   string
+	cacheTTL   time.Duration
+	nssLookups bool
+
+	// files holds the userGroupFiles by container ID, the host ones under ""
+	files     *lru.Cache
+	nssUsers  *lru.Cache
+	nssGroups *lru.Cache
 }
 
-// ResolveUser resolves a user id to a username
-func (r *UserGroupResolver) ResolveUser(uid int) (string, error) {
-	cachedEntry, found := r.userCache.Get(uid)
-	if found {
-		return cachedEntry.(string), nil
+// ResolveUser resolves a user id to a username, with the user files of the
+// container of the process pid, or of the host when containerID is empty
+func (r *UserGroupResolver) ResolveUser(uid int, containerID string, pid uint32) (string, error) {
+	files, err := r.getFiles(containerID, pid)
+	if err == nil {
+		if name, found := files.users[uid]; found {
+			return name, nil
+		}
+	}
+	if containerID == "" && r.nssLookups {
+		return r.nssLookup(r.nssUsers, uid, lookupUserName)
 	}
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("unknown user id %d", uid)
+}
 
-	var username string
-	u, err := user.LookupId(strconv.Itoa(uid))
+// ResolveGroup resolves a group id to a group name, with the group files of the
+// container of the process pid, or of the host when containerID is empty
+func (r *UserGroupResolver) ResolveGroup(gid int, containerID string, pid uint32) (string, error) {
+	files, err := r.getFiles(containerID, pid)
 	if err == nil {
-		username = u.Username
+		if name, found := files.groups[gid]; found {
+			return name, nil
+		}
 	}
-	r.userCache.Add(uid, username)
-	return username, err
+	if containerID == "" && r.nssLookups {
+		return r.nssLookup(r.nssGroups, gid, lookupGroupName)
+	}
+	if err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("unknown group id %d", gid)
 }
 
-// ResolveGroup resolves a group id to a group name
-func (r *UserGroupResolver) ResolveGroup(gid int) (string, error) {
-	cachedEntry, found := r.groupCache.Get(gid)
-	if found {
-		return cachedEntry.(string), nil
+// getFiles returns the user and group files of a container, or of the host
+func (r *UserGroupResolver) getFiles(containerID string, pid uint32) (*userGroupFiles, error) {
+	if cached, found := r.files.Get(containerID); found {
+		files := cached.(*userGroupFiles)
+		if files.err != nil && time.Since(files.loadedAt) < r.errorTTL() {
+			return nil, files.err
+		}
+		if files.err == nil && time.Since(files.loadedAt) < r.cacheTTL {
+			return files, nil
+		}
+	}
+
+	// the host files are read through the root of the init process, so that
+	// they are found when system-probe runs in a container
+	if containerID == "" {
+		pid = 1
 	}
+	root := filepath.Join(r.procRoot, strconv.FormatUint(uint64(pid), 10), "root")
 
-	var groupname string
+	files := &userGroupFiles{loadedAt: time.Now()}
+	if files.users, files.err = parseUserGroupFile(filepath.Join(root, "etc/passwd")); files.err == nil {
+		files.groups, files.err = parseUserGroupFile(filepath.Join(root, "etc/group"))
+	}
+	// the failures are cached as well, so that the files aren't read again on every lookup
+	r.files.Add(containerID, files)
+	if files.err != nil {
+		return nil, files.err
+	}
+	return files, nil
+}
+
+// errorTTL returns how long a failure to read the user and group files is cached
+func (r *UserGroupResolver) errorTTL() time.Duration {
+	if r.cacheTTL < userGroupFilesErrorTTL {
+		return r.cacheTTL
+	}
+	return userGroupFilesErrorTTL
+}
+
+func (r *UserGroupResolver) nssLookup(cache *lru.Cache, id int, lookup func(id int) (string, error)) (string, error) {
+	if cached, found := cache.Get(id); found {
+		entry := cached.(nssEntry)
+		if time.Since(entry.resolvedAt) < r.cacheTTL {
+			if entry.name == "" {
+				return "", fmt.Errorf("unknown id %d", id)
+			}
+			return entry.name, nil
+		}
+	}
+
+	name, err := lookup(id)
+	cache.Add(id, nssEntry{name: name, resolvedAt: time.Now()})
+	return name, err
+}
+
+func lookupUserName(uid int) (string, error) {
+	u, err := user.LookupId(strconv.Itoa(uid))
+	if err != nil {
+		return "", err
+	}
+	return u.Username, nil
+}
+
+func lookupGroupName(gid int) (string, error) {
 	g, err := user.LookupGroupId(strconv.Itoa(gid))
-	if err == nil {
-		groupname = g.Name
+	if err != nil {
+		return "", err
+	}
+	return g.Name, nil
+}
+
+// parseUserGroupFile parses the names and ids of an /etc/passwd or /etc/group file,
+// both of them holding the name in the first field and the id in the third one
+func parseUserGroupFile(path string) (map[int]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := make(map[int]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// skip the comments and the NIS entries of the compat mode
+		if line == "" || line[0] == '#' || line[0] == '+' || line[0] == '-' {
+			continue
+		}
+
+		fields := strings.SplitN(line, ":", 4)
+		if len(fields) < 3 {
+			continue
+		}
+		id, err := strconv.Atoi(fields[2])
+		if err != nil {
+			continue
+		}
+		// the first entry of an id wins, as with the files NSS source
+		if _, found := entries[id]; !found {
+			entries[id] = fields[0]
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// hasRemoteNSSSources returns whether the passwd or group databases of an
+// nsswitch.conf file use a source other than the local files
+func hasRemoteNSSSources(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 || (fields[0] != "passwd:" && fields[0] != "group:") {
+			continue
+		}
+		for _, source := range fields[1:] {
+			// skip the actions, like `[NOTFOUND=return]`
+			if strings.HasPrefix(source, "[") {
+				continue
+			}
+			if !nssLocalSources[source] {
+				return true
+			}
+		}
 	}
-	r.groupCache.Add(gid, groupname)
-	return groupname, nil
+	return false
 }
 
 // NewUserGroupResolver instantiates a new user and group resolver
-func NewUserGroupResolver() (*UserGroupResolver, error) {
-	userCache, err := lru.New(64)
+func NewUserGroupResolver(cfg *config.Config) (*UserGroupResolver, error) {
+	files, err := lru.New(userGroupFilesCacheSize)
 	if err != nil {
 		return nil, err
 	}
 
-	groupCache, err := lru.New(64)
+	nssUsers, err := lru.New(nssCacheSize)
 	if err != nil {
 		return nil, err
 	}
 
-	return &UserGroupResolver{
-		userCache:  userCache,
-		groupCache: groupCache,
-	}, nil
+	nssGroups, err := lru.New(nssCacheSize)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &UserGroupResolver{
+		procRoot:  util.HostProc(),
+		cacheTTL:  cfg.UserGroupCacheTTL,
+		files:     files,
+		nssUsers:  nssUsers,
+		nssGroups: nssGroups,
+	}
+
+	if cfg.UserGroupNSSLookups {
+		// NSS lookups only matter when a remote source, like LDAP or SSSD, is configured
+		r.nssLookups = hasRemoteNSSSources(filepath.Join(r.procRoot, "1/root/etc/nsswitch.conf"))
+		if !r.nssLookups {
+			log.Infof("No remote NSS source configured for the users and groups, NSS lookups disabled")
+		}
+	}
+
+	return r, nil
 }
@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build linux
+
+package probe
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/security/config"
+)
+
+func writeRootFile(t *testing.T, procRoot, pid, name, content string) {
+	path := filepath.Join(procRoot, pid, "root/etc", name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+}
+
+func newTestUserGroupResolver(t *testing.T, cacheTTL time.Duration) (*UserGroupResolver, string) {
+	procRoot, err := ioutil.TempDir("", "user-resolver")
+	require.NoError(t, err)
+
+	writeRootFile(t, procRoot, "1", "passwd", "# host users\nroot:x:0:0:root:/root:/bin/bash\n+@netgroup\nbob:x:1000:1000::/home/bob:/bin/sh\n")
+	writeRootFile(t, procRoot, "1", "group", "root:x:0:\nbob:x:1000:\n")
+	writeRootFile(t, procRoot, "42", "passwd", "root:x:0:0:root:/root:/bin/sh\nnginx:x:1000:1000::/var/cache/nginx:/sbin/nologin\n")
+	writeRootFile(t, procRoot, "42", "group", "root:x:0:\nnginx:x:1000:\n")
+
+	r, err := NewUserGroupResolver(&config.Config{UserGroupCacheTTL: cacheTTL})
+	require.NoError(t, err)
+	r.procRoot = procRoot
+
+	return r, procRoot
+}
+
+func TestUserGroupResolverContainers(t *testing.T) {
+	r, procRoot := newTestUserGroupResolver(t, time.Minute)
+	defer os.RemoveAll(procRoot)
+
+	user, err := r.ResolveUser(1000, "", 1234)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user)
+
+	user, err = r.ResolveUser(1000, "abcdef", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "nginx", user)
+
+	group, err := r.ResolveGroup(1000, "abcdef", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "nginx", group)
+
+	_, err = r.ResolveUser(1001, "abcdef", 42)
+	assert.Error(t, err)
+
+	// the files of an exited container process can't be read
+	_, err = r.ResolveUser(0, "fedcba", 43)
+	assert.Error(t, err)
+}
+
+func TestUserGroupResolverCachedError(t *testing.T) {
+	r, procRoot := newTestUserGroupResolver(t, time.Minute)
+	defer os.RemoveAll(procRoot)
+
+	_, err := r.ResolveUser(0, "fedcba", 43)
+	assert.Error(t, err)
+
+	// the failure is cached, the files aren't read again until it expires
+	writeRootFile(t, procRoot, "43", "passwd", "root:x:0:0:root:/root:/bin/sh\n")
+	writeRootFile(t, procRoot, "43", "group", "root:x:0:\n")
+	_, err = r.ResolveUser(0, "fedcba", 43)
+	assert.Error(t, err)
+
+	r.cacheTTL = 0
+	user, err := r.ResolveUser(0, "fedcba", 43)
+	require.NoError(t, err)
+	assert.Equal(t, "root", user)
+}
+
+func TestUserGroupResolverCacheTTL(t *testing.T) {
+	r, procRoot := newTestUserGroupResolver(t, time.Minute)
+	defer os.RemoveAll(procRoot)
+
+	user, err := r.ResolveUser(1000, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user)
+
+	writeRootFile(t, procRoot, "1", "passwd", "alice:x:1000:1000::/home/alice:/bin/sh\n")
+
+	user, err = r.ResolveUser(1000, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "bob", user)
+
+	r.cacheTTL = 0
+	user, err = r.ResolveUser(1000, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", user)
+}
+
+func TestHasRemoteNSSSources(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected bool
+	}{
+		{
+			name:     "files",
+			content:  "passwd: files\ngroup: files\nhosts: files dns\n",
+			expected: false,
+		},
+		{
+			name:     "compat",
+			content:  "passwd:         compat\ngroup:          compat [NOTFOUND=return]\n",
+			expected: false,
+		},
+		{
+			name:     "ldap",
+			content:  "# passwd: files sss\npasswd: files ldap\ngroup: files\n",
+			expected: true,
+		},
+		{
+			name:     "sss",
+			content:  "passwd: files\ngroup: files sss # SSSD\n",
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "nsswitch.conf")
+			require.NoError(t, err)
+			defer os.Remove(f.Name())
+
+			_, err = f.WriteString(test.content)
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			assert.Equal(t, test.expected, hasRemoteNSSSources(f.Name()))
+		})
+	}
+
+	assert.False(t, hasRemoteNSSSources("/does/not/exist"))
+}
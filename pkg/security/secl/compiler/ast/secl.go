@@ -175,11 +175,12 @@ type Unary struct {
 }
 
 // Primary describes a single operand. It can be a simple identifier, a number,
-// a string or a full expression in parenthesis
+// a string, an IP network like `cidr("10.0.0.0/8")` or a full expression in parenthesis
 type Primary struct {
 	Pos lexer.Position
 
-	Ident         *string     `parser:"@Ident"`
+	CIDR          *string     `parser:"\"cidr\" \"(\" @String \")\""`
+	Ident         *string     `parser:"| @Ident"`
 	Number        *int        `parser:"| @Int"`
 	String        *string     `parser:"| @String"`
 	Pattern       *string     `parser:"| @Pattern"`
@@ -203,5 +204,7 @@ type Array struct {
 
 	StringMembers []StringMember `parser:"\"[\" @@ { \",\" @@ } \"]\""`
 	Numbers       []int          `parser:"| \"[\" @Int { \",\" @Int } \"]\""`
+	CIDRMembers   []string       `parser:"| \"[\" \"cidr\" \"(\" @String \")\" { \",\" \"cidr\" \"(\" @String \")\" } \"]\""`
+	CIDR          *string        `parser:"| \"cidr\" \"(\" @String \")\""`
 	Ident         *string        `parser:"| @Ident"`
 }
@@ -0,0 +1,146 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package eval
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// CIDRValuesEvaluator returns a set of IP networks, declared with `cidr("10.0.0.0/8")`
+// or with an array of them. Such a set can be named and reused with a macro.
+type CIDRValuesEvaluator struct {
+	Values []net.IPNet
+	Weight int
+
+	isPartial bool
+}
+
+// Eval returns the result of the evaluation
+func (c *CIDRValuesEvaluator) Eval(ctx *Context) interface{} {
+	return c.Values
+}
+
+// IsPartial returns whether the evaluator is partial
+func (c *CIDRValuesEvaluator) IsPartial() bool {
+	return c.isPartial
+}
+
+// GetField returns field name used by this evaluator
+func (c *CIDRValuesEvaluator) GetField() string {
+	return ""
+}
+
+// IsScalar returns whether the evaluator is a scalar
+func (c *CIDRValuesEvaluator) IsScalar() bool {
+	return true
+}
+
+// contains returns whether the IP address is in one of the networks
+func (c *CIDRValuesEvaluator) contains(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range c.Values {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCIDR parses a network in the CIDR notation, an IP address is a network with a single address
+func parseCIDR(s string) (net.IPNet, error) {
+	if strings.Contains(s, "/") {
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return net.IPNet{}, err
+		}
+		return *ipNet, nil
+	}
+
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return net.IPNet{}, fmt.Errorf("invalid IP address: %s", s)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+func newCIDRValuesEvaluator(values []string) (*CIDRValuesEvaluator, error) {
+	var ce CIDRValuesEvaluator
+	for _, value := range values {
+		ipNet, err := parseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+		ce.Values = append(ce.Values, ipNet)
+	}
+	return &ce, nil
+}
+
+// CIDRContains evaluates whether the IP address of a is in one of the networks of b
+func CIDRContains(a *StringEvaluator, b *CIDRValuesEvaluator, opts *Opts, state *state) (*BoolEvaluator, error) {
+	isPartialLeaf := isPartialLeaf(a, b, state)
+
+	if a.EvalFnc == nil {
+		return &BoolEvaluator{
+			Value:     b.contains(a.Value),
+			Weight:    a.Weight + InArrayWeight*len(b.Values),
+			isPartial: isPartialLeaf,
+		}, nil
+	}
+
+	ea := a.EvalFnc
+
+	evalFnc := func(ctx *Context) bool {
+		return b.contains(ea(ctx))
+	}
+
+	return &BoolEvaluator{
+		EvalFnc:   evalFnc,
+		Weight:    a.Weight + InArrayWeight*len(b.Values),
+		isPartial: isPartialLeaf,
+	}, nil
+}
+
+// CIDRArrayContains evaluates whether one of the IP addresses of a is in one of the networks of b
+func CIDRArrayContains(a *StringArrayEvaluator, b *CIDRValuesEvaluator, opts *Opts, state *state) (*BoolEvaluator, error) {
+	isPartialLeaf := isPartialLeaf(a, b, state)
+
+	arrayOp := func(addrs []string) bool {
+		for _, addr := range addrs {
+			if b.contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if a.EvalFnc == nil {
+		return &BoolEvaluator{
+			Value:     arrayOp(a.Values),
+			Weight:    a.Weight + InArrayWeight*len(b.Values),
+			isPartial: isPartialLeaf,
+		}, nil
+	}
+
+	ea := a.EvalFnc
+
+	evalFnc := func(ctx *Context) bool {
+		return arrayOp(ea(ctx))
+	}
+
+	return &BoolEvaluator{
+		EvalFnc:   evalFnc,
+		Weight:    a.Weight + InArrayWeight*len(b.Values),
+		isPartial: isPartialLeaf,
+	}, nil
+}
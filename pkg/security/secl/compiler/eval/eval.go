@@ -428,6 +428,18 @@ func arrayToEvaluator(array *ast.Array, opts *Opts, state *state) (interface{},
 			}
 		}
 		return &se, array.Pos, nil
+	} else if len(array.CIDRMembers) != 0 {
+		ce, err := newCIDRValuesEvaluator(array.CIDRMembers)
+		if err != nil {
+			return nil, array.Pos, NewError(array.Pos, fmt.Sprintf("invalid CIDR: %s", err))
+		}
+		return ce, array.Pos, nil
+	} else if array.CIDR != nil {
+		ce, err := newCIDRValuesEvaluator([]string{*array.CIDR})
+		if err != nil {
+			return nil, array.Pos, NewError(array.Pos, fmt.Sprintf("invalid CIDR: %s", err))
+		}
+		return ce, array.Pos, nil
 	} else if array.Ident != nil {
 		if state.macros != nil {
 			if macro, ok := state.macros[*array.Ident]; ok {
@@ -569,6 +581,15 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, le
 						return Not(boolEvaluator, opts, state), obj.Pos, nil
 					}
 					return boolEvaluator, obj.Pos, nil
+				case *CIDRValuesEvaluator:
+					boolEvaluator, err := CIDRContains(unary, nextString, opts, state)
+					if err != nil {
+						return nil, pos, err
+					}
+					if *obj.ArrayComparison.Op == "notin" {
+						return Not(boolEvaluator, opts, state), obj.Pos, nil
+					}
+					return boolEvaluator, obj.Pos, nil
 				default:
 					return nil, pos, NewTypeError(pos, reflect.Array)
 				}
@@ -583,6 +604,15 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, le
 						return Not(boolEvaluator, opts, state), obj.Pos, nil
 					}
 					return boolEvaluator, obj.Pos, nil
+				case *CIDRValuesEvaluator:
+					boolEvaluator, err := CIDRArrayContains(unary, nextStringArray, opts, state)
+					if err != nil {
+						return nil, pos, err
+					}
+					if *obj.ArrayComparison.Op == "notin" {
+						return Not(boolEvaluator, opts, state), obj.Pos, nil
+					}
+					return boolEvaluator, obj.Pos, nil
 				default:
 					return nil, pos, NewTypeError(pos, reflect.Array)
 				}
@@ -667,6 +697,21 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, le
 				}
 				return nil, pos, NewOpUnknownError(obj.Pos, *obj.ScalarComparison.Op)
 			case *StringEvaluator:
+				// an IP address compared to a network matches the addresses of the network
+				if nextCIDR, ok := next.(*CIDRValuesEvaluator); ok {
+					boolEvaluator, err := CIDRContains(unary, nextCIDR, opts, state)
+					if err != nil {
+						return nil, obj.Pos, err
+					}
+					switch *obj.ScalarComparison.Op {
+					case "!=":
+						return Not(boolEvaluator, opts, state), obj.Pos, nil
+					case "==":
+						return boolEvaluator, obj.Pos, nil
+					}
+					return nil, pos, NewOpUnknownError(obj.Pos, *obj.ScalarComparison.Op)
+				}
+
 				nextString, ok := next.(*StringEvaluator)
 				if !ok {
 					return nil, pos, NewTypeError(pos, reflect.String)
@@ -973,6 +1018,12 @@ func nodeToEvaluator(obj interface{}, opts *Opts, state *state) (interface{}, le
 		return nodeToEvaluator(obj.Primary, opts, state)
 	case *ast.Primary:
 		switch {
+		case obj.CIDR != nil:
+			ce, err := newCIDRValuesEvaluator([]string{*obj.CIDR})
+			if err != nil {
+				return nil, obj.Pos, NewError(obj.Pos, fmt.Sprintf("invalid CIDR: %s", err))
+			}
+			return ce, obj.Pos, nil
 		case obj.Ident != nil:
 			return identToEvaluator(&ident{Pos: obj.Pos, Ident: obj.Ident}, opts, state)
 		case obj.Number != nil:
@@ -370,6 +370,96 @@ func TestInArray(t *testing.T) {
 	}
 }
 
+func TestCIDR(t *testing.T) {
+	event := &testEvent{
+		connect: testConnect{
+			addr: "10.1.2.3",
+		},
+	}
+
+	tests := []struct {
+		Expr     string
+		Expected bool
+	}{
+		{Expr: `connect.addr in cidr("10.0.0.0/8")`, Expected: true},
+		{Expr: `connect.addr in cidr("192.168.0.0/16")`, Expected: false},
+		{Expr: `connect.addr not in cidr("10.0.0.0/8")`, Expected: false},
+		{Expr: `connect.addr not in cidr("192.168.0.0/16")`, Expected: true},
+		{Expr: `connect.addr == cidr("10.1.0.0/16")`, Expected: true},
+		{Expr: `connect.addr != cidr("10.1.0.0/16")`, Expected: false},
+		{Expr: `connect.addr == cidr("10.1.2.3")`, Expected: true},
+		{Expr: `connect.addr == cidr("10.1.2.4")`, Expected: false},
+		{Expr: `connect.addr in [ cidr("192.168.0.0/16"), cidr("10.0.0.0/8") ]`, Expected: true},
+		{Expr: `connect.addr in [ cidr("192.168.0.0/16"), cidr("172.16.0.0/12") ]`, Expected: false},
+		{Expr: `connect.addr not in [ cidr("192.168.0.0/16"), cidr("172.16.0.0/12") ]`, Expected: true},
+		{Expr: `connect.addr in cidr("::/0")`, Expected: false},
+		{Expr: `"2001:db8::1" in cidr("2001:db8::/32")`, Expected: true},
+		{Expr: `"2001:db9::1" in cidr("2001:db8::/32")`, Expected: false},
+		{Expr: `"not an ip" in cidr("0.0.0.0/0")`, Expected: false},
+		{Expr: `process.name in [ "aaa", "bbb" ] || connect.addr in cidr("10.0.0.0/8")`, Expected: true},
+	}
+
+	for _, test := range tests {
+		result, _, err := eval(t, event, test.Expr)
+		if err != nil {
+			t.Fatalf("error while evaluating `%s: %s`", test.Expr, err)
+		}
+
+		if result != test.Expected {
+			t.Errorf("expected result `%t` not found, got `%t`\n%s", test.Expected, result, test.Expr)
+		}
+	}
+
+	for _, expr := range []string{
+		`connect.addr in cidr("10.0.0.0/33")`,
+		`connect.addr in [ cidr("10.0.0.0/8"), cidr("10.0.0") ]`,
+		`process.uid in cidr("10.0.0.0/8")`,
+	} {
+		if _, _, err := eval(t, event, expr); err == nil {
+			t.Errorf("should return an error: %s", expr)
+		}
+	}
+}
+
+func TestMacroIPSet(t *testing.T) {
+	macro := &Macro{
+		ID:         "private_networks",
+		Expression: `[ cidr("10.0.0.0/8"), cidr("172.16.0.0/12"), cidr("192.168.0.0/16") ]`,
+	}
+
+	if err := macro.Parse(); err != nil {
+		t.Fatalf("%s\n%s", err, macro.Expression)
+	}
+
+	model := &testModel{}
+
+	if err := macro.GenEvaluator(model, &Opts{}); err != nil {
+		t.Fatalf("%s\n%s", err, macro.Expression)
+	}
+
+	opts := NewOptsWithParams(make(map[string]interface{}), nil)
+	opts.Macros = map[string]*Macro{
+		"private_networks": macro,
+	}
+
+	expr := `connect.addr not in private_networks`
+
+	rule, err := parseRule(expr, model, opts)
+	if err != nil {
+		t.Fatalf("error while evaluating `%s`: %s", expr, err)
+	}
+
+	event := &testEvent{connect: testConnect{addr: "172.17.0.2"}}
+	if rule.Eval(NewContext(unsafe.Pointer(event))) {
+		t.Fatalf("should return false")
+	}
+
+	event.connect.addr = "8.8.8.8"
+	if !rule.Eval(NewContext(unsafe.Pointer(event))) {
+		t.Fatalf("should return true")
+	}
+}
+
 func TestComplex(t *testing.T) {
 	event := &testEvent{
 		open: testOpen{
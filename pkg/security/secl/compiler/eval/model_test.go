@@ -94,6 +94,10 @@ type testMkdir struct {
 	mode     int
 }
 
+type testConnect struct {
+	addr string
+}
+
 type testEvent struct {
 	id   string
 	kind string
@@ -101,6 +105,7 @@ type testEvent struct {
 	process testProcess
 	open    testOpen
 	mkdir   testMkdir
+	connect testConnect
 
 	listEvaluated bool
 	uidEvaluated  bool
@@ -351,6 +356,13 @@ func (m *testModel) GetEvaluator(field Field, regID RegisterID) (Evaluator, erro
 			EvalFnc: func(ctx *Context) int { return (*testEvent)(ctx.Object).mkdir.mode },
 			Field:   field,
 		}, nil
+
+	case "connect.addr":
+
+		return &StringEvaluator{
+			EvalFnc: func(ctx *Context) string { return (*testEvent)(ctx.Object).connect.addr },
+			Field:   field,
+		}, nil
 	}
 
 	return nil, &ErrFieldNotFound{Field: field}
@@ -399,6 +411,10 @@ func (e *testEvent) GetFieldValue(field Field) (interface{}, error) {
 
 		return e.mkdir.mode, nil
 
+	case "connect.addr":
+
+		return e.connect.addr, nil
+
 	}
 
 	return nil, &ErrFieldNotFound{Field: field}
@@ -471,6 +487,10 @@ func (e *testEvent) GetFieldEventType(field Field) (string, error) {
 
 		return "mkdir", nil
 
+	case "connect.addr":
+
+		return "connect", nil
+
 	}
 
 	return "", &ErrFieldNotFound{Field: field}
@@ -529,6 +549,11 @@ func (e *testEvent) SetFieldValue(field Field, value interface{}) error {
 		e.mkdir.mode = value.(int)
 		return nil
 
+	case "connect.addr":
+
+		e.connect.addr = value.(string)
+		return nil
+
 	}
 
 	return &ErrFieldNotFound{Field: field}
@@ -591,6 +616,10 @@ func (e *testEvent) GetFieldType(field Field) (reflect.Kind, error) {
 
 		return reflect.Int, nil
 
+	case "connect.addr":
+
+		return reflect.String, nil
+
 	}
 
 	return reflect.Invalid, &ErrFieldNotFound{Field: field}
@@ -267,6 +267,11 @@ var (
 		"AT_REMOVEDIR": unix.AT_REMOVEDIR,
 	}
 
+	addressFamilyConstants = map[string]int{
+		"AF_INET":  unix.AF_INET,
+		"AF_INET6": unix.AF_INET6,
+	}
+
 	// SECLConstants are constants available in runtime security agent rules
 	SECLConstants = map[string]interface{}{
 		// boolean
@@ -318,6 +323,12 @@ func initErrorConstants() {
 	}
 }
 
+func initAddressFamilyConstants() {
+	for k, v := range addressFamilyConstants {
+		SECLConstants[k] = &eval.IntEvaluator{Value: v}
+	}
+}
+
 func initKernelCapabilityConstants() {
 	for k, v := range KernelCapabilityConstants {
 		if bits.UintSize == 64 || v < math.MaxInt32 {
@@ -333,6 +344,7 @@ func initConstants() {
 	initChmodConstants()
 	initUnlinkConstanst()
 	initKernelCapabilityConstants()
+	initAddressFamilyConstants()
 }
 
 func bitmaskToStringArray(bitmask int, intToStrMap map[int]string) []string {
@@ -59,6 +59,10 @@ const (
 	MountReleasedEventType
 	// SELinuxEventType selinux event
 	SELinuxEventType
+	// ConnectEventType connect event
+	ConnectEventType
+	// BindEventType bind event
+	BindEventType
 	// MaxEventType is used internally to get the maximum number of kernel events.
 	MaxEventType
 
@@ -80,6 +84,10 @@ const (
 	CustomForkBombEventType
 	// CustomTruncatedParentsEventType is the custom event used to report that the parents of a path were truncated
 	CustomTruncatedParentsEventType
+	// CustomConfinementDriftEventType is the custom event used to report a process running with a looser confinement than declared
+	CustomConfinementDriftEventType
+	// CustomFileAccessSummaryEventType is the custom event used to report the accesses matched by an aggregated rule during a window
+	CustomFileAccessSummaryEventType
 )
 
 func (t EventType) String() string {
@@ -130,6 +138,10 @@ func (t EventType) String() string {
 		return "mount_released"
 	case SELinuxEventType:
 		return "selinux"
+	case ConnectEventType:
+		return "connect"
+	case BindEventType:
+		return "bind"
 
 	case CustomLostReadEventType:
 		return "lost_events_read"
@@ -143,6 +155,10 @@ func (t EventType) String() string {
 		return "fork_bomb"
 	case CustomTruncatedParentsEventType:
 		return "truncated_parents"
+	case CustomConfinementDriftEventType:
+		return "confinement_drift"
+	case CustomFileAccessSummaryEventType:
+		return "file_access_summary"
 	default:
 		return "unknown"
 	}
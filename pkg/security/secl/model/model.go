@@ -140,8 +140,12 @@ type Event struct {
 
 	SELinux SELinuxEvent `field:"selinux" event:"selinux"` // [7.30] [Kernel] An SELinux operation was run
 
-	Mount            MountEvent            `field:"-"`
-	Umount           UmountEvent           `field:"-"`
+	Mount  MountEvent  `field:"mount" event:"mount"`   // [7.33] [File] A filesystem was mounted
+	Umount UmountEvent `field:"umount" event:"umount"` // [7.33] [File] A filesystem was unmounted
+
+	Connect ConnectEvent `field:"connect" event:"connect"` // [7.34] [Network] A process initiated a connection
+	Bind    BindEvent    `field:"bind" event:"bind"`       // [7.34] [Network] A process bound a socket to an address
+
 	InvalidateDentry InvalidateDentryEvent `field:"-"`
 	ArgsEnvs         ArgsEnvsEvent         `field:"-"`
 	MountReleased    MountReleasedEvent    `field:"-"`
@@ -373,20 +377,21 @@ type ArgsEnvsEvent struct {
 // MountEvent represents a mount event
 type MountEvent struct {
 	SyscallEvent
-	MountID                       uint32
-	GroupID                       uint32
-	Device                        uint32
-	ParentMountID                 uint32
-	ParentInode                   uint64
-	FSType                        string
-	MountPointStr                 string
-	MountPointPathResolutionError error
-	RootMountID                   uint32
-	RootInode                     uint64
-	RootStr                       string
-	RootPathResolutionError       error
-
-	FSTypeRaw [16]byte
+	MountID                       uint32 `field:"-"`
+	GroupID                       uint32 `field:"-"`
+	Device                        uint32 `field:"-"`
+	ParentMountID                 uint32 `field:"-"`
+	ParentInode                   uint64 `field:"-"`
+	FSType                        string `field:"fs_type,ResolveMountFSType"`        // Type of the mounted filesystem
+	MountPointStr                 string `field:"mountpoint.path,ResolveMountPoint"` // Path of the mount point
+	MountPointPathResolutionError error  `field:"-"`
+	MountPointContainerID         string `field:"mountpoint.container_id,ResolveMountPointContainerID"` // ID of the container whose overlay root filesystem holds the mount point
+	RootMountID                   uint32 `field:"-"`
+	RootInode                     uint64 `field:"-"`
+	RootStr                       string `field:"source.path,ResolveMountRoot"` // Path of the mounted directory, the source of a bind mount
+	RootPathResolutionError       error  `field:"-"`
+
+	FSTypeRaw [16]byte `field:"-"`
 }
 
 // GetFSType returns the filesystem type of the mountpoint
@@ -561,7 +566,29 @@ type UnlinkEvent struct {
 // UmountEvent represents an umount event
 type UmountEvent struct {
 	SyscallEvent
-	MountID uint32
+	MountID       uint32 `field:"-"`
+	FSType        string `field:"fs_type,ResolveUmountFSType"`        // Type of the unmounted filesystem
+	MountPointStr string `field:"mountpoint.path,ResolveUmountPoint"` // Path of the mount point
+}
+
+// IPPortContext represents the address of an IPv4 or IPv6 socket
+type IPPortContext struct {
+	Family uint16 `field:"family"` // Address family, one of AF_INET or AF_INET6
+	IP     string `field:"ip"`     // IP address
+	Port   uint16 `field:"port"`   // Port number
+}
+
+// ConnectEvent represents a connect event
+type ConnectEvent struct {
+	SyscallEvent
+	Addr    IPPortContext `field:"addr"`
+	Domains []string      `field:"addr.domains,ResolveConnectDomains"` // Domains that the destination IP address was resolved from by the DNS queries of the host
+}
+
+// BindEvent represents a bind event
+type BindEvent struct {
+	SyscallEvent
+	Addr IPPortContext `field:"addr"`
 }
 
 // UtimesEvent represents a utime event
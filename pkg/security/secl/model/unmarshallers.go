@@ -8,8 +8,11 @@
 package model
 
 import (
+	"net"
 	"time"
 	"unsafe"
+
+	"golang.org/x/sys/unix"
 )
 
 // BinaryUnmarshaler interface implemented by every event type
@@ -432,6 +435,34 @@ func (e *UmountEvent) UnmarshalBinary(data []byte) (int, error) {
 	return 8, nil
 }
 
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *IPPortContext) UnmarshalBinary(data []byte) (int, error) {
+	if len(data) < 24 {
+		return 0, ErrNotEnoughData
+	}
+
+	e.Family = ByteOrder.Uint16(data[16:18])
+	e.Port = ByteOrder.Uint16(data[18:20])
+	switch e.Family {
+	case unix.AF_INET:
+		e.IP = net.IP(data[0:4]).String()
+	case unix.AF_INET6:
+		e.IP = net.IP(data[0:16]).String()
+	}
+
+	return 24, nil
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *ConnectEvent) UnmarshalBinary(data []byte) (int, error) {
+	return UnmarshalBinary(data, &e.SyscallEvent, &e.Addr)
+}
+
+// UnmarshalBinary unmarshals a binary representation of itself
+func (e *BindEvent) UnmarshalBinary(data []byte) (int, error) {
+	return UnmarshalBinary(data, &e.SyscallEvent, &e.Addr)
+}
+
 // UnmarshalBinary unmarshals a binary representation of itself
 func (e *UnlinkEvent) UnmarshalBinary(data []byte) (int, error) {
 	n, err := UnmarshalBinary(data, &e.SyscallEvent, &e.File)
@@ -27,6 +27,9 @@ var (
 
 	// ErrEventTypeNotEnabled is returned when an event is not enabled
 	ErrEventTypeNotEnabled = errors.New("event type not enabled")
+
+	// ErrInvalidAggregationWindow is returned when the aggregation window of a rule isn't a positive duration
+	ErrInvalidAggregationWindow = errors.New("aggregation window must be a positive duration")
 )
 
 // ErrFieldTypeUnknown is returned when a field has an unknown type
@@ -92,46 +92,74 @@ func LoadPolicy(r io.Reader, name string) (*Policy, error) {
 	return policy, nil
 }
 
+// PolicySource is a policy read from another location than the policies directory,
+// e.g. from a policies bundle
+type PolicySource struct {
+	Name   string
+	Reader io.Reader
+}
+
 // LoadPolicies loads the policies listed in the configuration and apply them to the given ruleset
 func LoadPolicies(policiesDir string, ruleSet *RuleSet) *multierror.Error {
+	return LoadPoliciesFromSources(policiesDir, nil, ruleSet)
+}
+
+// LoadPoliciesFromSources loads the policies of the policies directory, if not empty, followed
+// by the given policy sources and apply them to the given ruleset
+func LoadPoliciesFromSources(policiesDir string, sources []PolicySource, ruleSet *RuleSet) *multierror.Error {
 	var (
 		result   *multierror.Error
+		policies []*Policy
 		allRules []*RuleDefinition
 	)
 
-	policyFiles, err := ioutil.ReadDir(policiesDir)
-	if err != nil {
-		return multierror.Append(result, ErrPoliciesLoad{Name: policiesDir, Err: err})
-	}
-	sort.Slice(policyFiles, func(i, j int) bool { return policyFiles[i].Name() < policyFiles[j].Name() })
+	if policiesDir != "" {
+		policyFiles, err := ioutil.ReadDir(policiesDir)
+		if err != nil {
+			return multierror.Append(result, ErrPoliciesLoad{Name: policiesDir, Err: err})
+		}
+		sort.Slice(policyFiles, func(i, j int) bool { return policyFiles[i].Name() < policyFiles[j].Name() })
 
-	// Load and parse policies
-	for _, policyPath := range policyFiles {
-		filename := policyPath.Name()
+		// Load and parse policies
+		for _, policyPath := range policyFiles {
+			filename := policyPath.Name()
 
-		// policy path extension check
-		if filepath.Ext(filename) != ".policy" {
-			ruleSet.logger.Debugf("ignoring file `%s` wrong extension `%s`", policyPath.Name(), filepath.Ext(filename))
-			continue
-		}
+			// policy path extension check
+			if filepath.Ext(filename) != ".policy" {
+				ruleSet.logger.Debugf("ignoring file `%s` wrong extension `%s`", policyPath.Name(), filepath.Ext(filename))
+				continue
+			}
 
-		// Open policy path
-		f, err := os.Open(filepath.Join(policiesDir, filename))
-		if err != nil {
-			result = multierror.Append(result, &ErrPolicyLoad{Name: filename, Err: err})
-			continue
+			// Open policy path
+			f, err := os.Open(filepath.Join(policiesDir, filename))
+			if err != nil {
+				result = multierror.Append(result, &ErrPolicyLoad{Name: filename, Err: err})
+				continue
+			}
+			defer f.Close()
+
+			// Parse policy file
+			policy, err := LoadPolicy(f, filepath.Base(filename))
+			if err != nil {
+				result = multierror.Append(result, err)
+				continue
+			}
+			policies = append(policies, policy)
 		}
-		defer f.Close()
+	}
 
-		// Parse policy file
-		policy, err := LoadPolicy(f, filepath.Base(filename))
+	for _, source := range sources {
+		policy, err := LoadPolicy(source.Reader, source.Name)
 		if err != nil {
 			result = multierror.Append(result, err)
 			continue
 		}
+		policies = append(policies, policy)
+	}
 
+	for _, policy := range policies {
 		// Add policy version for logging purposes
-		ruleSet.AddPolicyVersion(filename, policy.Version)
+		ruleSet.AddPolicyVersion(policy.Name, policy.Version)
 
 		macros, rules, mErr := policy.GetValidMacroAndRules()
 		if mErr.ErrorOrNil() != nil {
@@ -141,7 +169,7 @@ func LoadPolicies(policiesDir string, ruleSet *RuleSet) *multierror.Error {
 		if len(macros) > 0 {
 			// Add the macros to the ruleset and generate macros evaluators
 			if mErr := ruleSet.AddMacros(macros); mErr.ErrorOrNil() != nil {
-				result = multierror.Append(result, err)
+				result = multierror.Append(result, mErr)
 			}
 		}
 
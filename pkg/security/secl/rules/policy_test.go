@@ -0,0 +1,52 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package rules
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
+)
+
+func TestLoadPoliciesFromSources(t *testing.T) {
+	enabled := map[eval.EventType]bool{"*": true}
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(testConstants, testSupportedDiscarders, enabled, nil, nil))
+
+	policiesDir := t.TempDir()
+	policy := `
+version: 1.0.0
+macros:
+  - id: etc_files
+    expression: '["/etc/passwd", "/etc/shadow"]'
+`
+	if err := ioutil.WriteFile(filepath.Join(policiesDir, "macros.policy"), []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// the rules of the sources can use the macros of the policies directory
+	source := PolicySource{
+		Name: "bundle.policy",
+		Reader: strings.NewReader(`
+version: 2.0.0
+rules:
+  - id: etc_access
+    expression: open.filename in etc_files
+`),
+	}
+	if err := LoadPoliciesFromSources(policiesDir, []PolicySource{source}, rs); err.ErrorOrNil() != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := rs.GetRules()["etc_access"]; !exists {
+		t.Fatal("rule of the policy source not loaded")
+	}
+	if rs.loadedPolicies["macros_policy"] != "1.0.0" || rs.loadedPolicies["bundle_policy"] != "2.0.0" {
+		t.Fatalf("unexpected policies versions: %v", rs.loadedPolicies)
+	}
+}
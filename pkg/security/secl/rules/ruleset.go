@@ -8,6 +8,7 @@ package rules
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
@@ -35,14 +36,32 @@ type RuleID = string
 
 // RuleDefinition holds the definition of a rule
 type RuleDefinition struct {
-	ID          RuleID            `yaml:"id"`
-	Version     string            `yaml:"version"`
-	Expression  string            `yaml:"expression"`
-	Description string            `yaml:"description"`
-	Tags        map[string]string `yaml:"tags"`
+	ID          RuleID                 `yaml:"id"`
+	Version     string                 `yaml:"version"`
+	Expression  string                 `yaml:"expression"`
+	Description string                 `yaml:"description"`
+	Tags        map[string]string      `yaml:"tags"`
+	Output      *OutputDefinition      `yaml:"output"`
+	Aggregation *AggregationDefinition `yaml:"aggregation"`
 	Policy      *Policy
 }
 
+// OutputDefinition holds the fields of the events of a rule that are forwarded,
+// as JSON paths such as `process.args`
+type OutputDefinition struct {
+	// IncludeFields are the only fields forwarded, overriding the global allowlist
+	IncludeFields []string `yaml:"include_fields"`
+	// ExcludeFields are removed from the forwarded events, in addition to the global denylist
+	ExcludeFields []string `yaml:"exclude_fields"`
+}
+
+// AggregationDefinition holds the aggregation of the events of a rule: instead of an event per
+// match, the accessed files are summarized and forwarded as a single event per window
+type AggregationDefinition struct {
+	// Window is the duration of the summaries, such as `5m`
+	Window time.Duration `yaml:"window"`
+}
+
 // GetTags returns the tags associated to a rule
 func (rd *RuleDefinition) GetTags() []string {
 	tags := []string{}
@@ -204,6 +223,10 @@ func (rs *RuleSet) AddRule(ruleDef *RuleDefinition) (*eval.Rule, error) {
 		return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrDefinitionIDConflict}
 	}
 
+	if ruleDef.Aggregation != nil && ruleDef.Aggregation.Window <= 0 {
+		return nil, &ErrRuleLoad{Definition: ruleDef, Err: ErrInvalidAggregationWindow}
+	}
+
 	var tags []string
 	for k, v := range ruleDef.Tags {
 		tags = append(tags, k+":"+v)
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"syscall"
 	"testing"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/security/secl/compiler/eval"
 )
@@ -415,3 +416,24 @@ func TestRuleSetFilters7(t *testing.T) {
 		t.Fatal("shouldn't get any approver")
 	}
 }
+
+func TestRuleSetAggregationWindow(t *testing.T) {
+	enabled := map[eval.EventType]bool{"*": true}
+	rs := NewRuleSet(&testModel{}, func() eval.Event { return &testEvent{} }, NewOptsWithParams(testConstants, testSupportedDiscarders, enabled, nil, nil))
+
+	if _, err := rs.AddRule(&RuleDefinition{
+		ID:          "invalid",
+		Expression:  `open.filename =~ "/etc/*"`,
+		Aggregation: &AggregationDefinition{},
+	}); err == nil {
+		t.Fatal("a rule without aggregation window shouldn't be loaded")
+	}
+
+	if _, err := rs.AddRule(&RuleDefinition{
+		ID:          "valid",
+		Expression:  `open.filename =~ "/etc/*"`,
+		Aggregation: &AggregationDefinition{Window: 5 * time.Minute},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
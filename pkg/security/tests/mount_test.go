@@ -137,3 +137,58 @@ func TestMount(t *testing.T) {
 		})
 	})
 }
+
+func TestMountRules(t *testing.T) {
+	dstMntBasename := "test-bind-dst"
+
+	ruleDefs := []*rules.RuleDefinition{{
+		ID:         "test_rule_mount",
+		Expression: fmt.Sprintf(`mount.mountpoint.path == "/%s" && mount.fs_type == "xfs" && mount.mountpoint.container_id == ""`, dstMntBasename),
+	}, {
+		ID:         "test_rule_umount",
+		Expression: fmt.Sprintf(`umount.mountpoint.path =~ "*/%s" && umount.fs_type == "xfs"`, dstMntBasename),
+	}}
+
+	testDrive, err := newTestDrive("xfs", []string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDrive.Close()
+
+	test, err := newTestModule(t, nil, ruleDefs, testOpts{testDir: testDrive.Root()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	srcMntPath, _, err := testDrive.Path("test-bind-src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(srcMntPath, 0755)
+	defer os.RemoveAll(srcMntPath)
+
+	dstMntPath, _, err := testDrive.Path(dstMntBasename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.MkdirAll(dstMntPath, 0755)
+	defer os.RemoveAll(dstMntPath)
+
+	t.Run("mount", func(t *testing.T) {
+		test.WaitSignal(t, func() error {
+			return syscall.Mount(srcMntPath, dstMntPath, "bind", syscall.MS_BIND, "")
+		}, func(event *sprobe.Event, rule *rules.Rule) {
+			assertTriggeredRule(t, rule, "test_rule_mount")
+			assert.Equal(t, "/test-bind-src", event.ResolveMountRoot(&event.Mount), "wrong mount source")
+		})
+	})
+
+	t.Run("umount", func(t *testing.T) {
+		test.WaitSignal(t, func() error {
+			return syscall.Unmount(dstMntPath, syscall.MNT_DETACH)
+		}, func(event *sprobe.Event, rule *rules.Rule) {
+			assertTriggeredRule(t, rule, "test_rule_umount")
+		})
+	})
+}
@@ -0,0 +1,87 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build functionaltests
+
+package tests
+
+import (
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	sprobe "github.com/DataDog/datadog-agent/pkg/security/probe"
+	"github.com/DataDog/datadog-agent/pkg/security/secl/rules"
+)
+
+func TestConnect(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	rule := &rules.RuleDefinition{
+		ID:         "test_rule",
+		Expression: fmt.Sprintf(`connect.addr.ip == "127.0.0.1" && connect.addr.port == %d && connect.addr.family == AF_INET && process.file.name == "{{.ProcessName}}"`, port),
+	}
+
+	test, err := newTestModule(t, nil, []*rules.RuleDefinition{rule}, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	test.WaitSignal(t, func() error {
+		conn, err := net.Dial("tcp4", listener.Addr().String())
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}, func(event *sprobe.Event, r *rules.Rule) {
+		assert.Equal(t, "connect", event.GetType(), "wrong event type")
+		assert.Equal(t, "127.0.0.1", event.Connect.Addr.IP, "wrong IP")
+		assert.Equal(t, uint16(port), event.Connect.Addr.Port, "wrong port")
+		assert.Equal(t, uint16(syscall.AF_INET), event.Connect.Addr.Family, "wrong family")
+
+		if !validateNetworkSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+}
+
+func TestBind(t *testing.T) {
+	rule := &rules.RuleDefinition{
+		ID:         "test_rule",
+		Expression: `bind.addr.ip == "::1" && bind.addr.port == 4243 && bind.addr.family == AF_INET6 && process.file.name == "{{.ProcessName}}"`,
+	}
+
+	test, err := newTestModule(t, nil, []*rules.RuleDefinition{rule}, testOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer test.Close()
+
+	test.WaitSignal(t, func() error {
+		listener, err := net.Listen("tcp6", "[::1]:4243")
+		if err != nil {
+			return err
+		}
+		return listener.Close()
+	}, func(event *sprobe.Event, r *rules.Rule) {
+		assert.Equal(t, "bind", event.GetType(), "wrong event type")
+		assert.Equal(t, "::1", event.Bind.Addr.IP, "wrong IP")
+		assert.Equal(t, uint16(4243), event.Bind.Addr.Port, "wrong port")
+		assert.Equal(t, uint16(syscall.AF_INET6), event.Bind.Addr.Family, "wrong family")
+
+		if !validateNetworkSchema(t, event) {
+			t.Error(event.String())
+		}
+	})
+}
@@ -86,6 +86,10 @@ func validateSELinuxSchema(t *testing.T, event *sprobe.Event) bool {
 	return validateSchema(t, event, "file:///schemas/selinux.schema.json")
 }
 
+func validateNetworkSchema(t *testing.T, event *sprobe.Event) bool {
+	return validateSchema(t, event, "file:///schemas/network.schema.json")
+}
+
 func validateLinkSchema(t *testing.T, event *sprobe.Event) bool {
 	return validateSchema(t, event, "file:///schemas/link.schema.json")
 }
@@ -78,6 +78,34 @@ func CapEffCapEprm(pid int32) (uint64, uint64, error) {
 	return capEff, capPrm, nil
 }
 
+// SeccompMode returns the seccomp mode of a process: 0 when disabled, 1 for the strict mode and 2 for the filter mode
+func SeccompMode(pid int32) (int, error) {
+	contents, err := ioutil.ReadFile(StatusPath(pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(contents), "\n") {
+		if value := strings.TrimPrefix(line, "Seccomp:"); value != line {
+			return strconv.Atoi(strings.TrimSpace(value))
+		}
+	}
+	return 0, fmt.Errorf("seccomp mode not found in %s", StatusPath(pid))
+}
+
+// LSMLabel returns the label of a process for the given Linux security module, e.g. apparmor or selinux. It is read
+// from the attributes of the module, or from the attributes of the major module on the kernels without them.
+func LSMLabel(pid int32, lsm string) (string, error) {
+	attrPath := filepath.Join(util.HostProc(), fmt.Sprintf("%d/attr", pid))
+	contents, err := ioutil.ReadFile(filepath.Join(attrPath, lsm, "current"))
+	if os.IsNotExist(err) {
+		contents, err = ioutil.ReadFile(filepath.Join(attrPath, "current"))
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(contents), "\x00\n"), nil
+}
+
 // PidTTY returns the TTY of the given pid
 func PidTTY(pid int32) string {
 	fdPath := filepath.Join(util.HostProc(), fmt.Sprintf("%d/fd/0", pid))
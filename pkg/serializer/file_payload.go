@@ -0,0 +1,159 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package serializer
+
+import (
+	"bytes"
+	"expvar"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const payloadFilePattern = "payload-*"
+
+var (
+	expvarsPayloadsWrittenToFile     = expvar.Int{}
+	expvarsPayloadsWrittenToFileSize = expvar.Int{}
+	expvarsPayloadFileErrors         = expvar.Int{}
+
+	tlmPayloadsWrittenToFile = telemetry.NewCounter("serializer", "payloads_written_to_file",
+		nil, "Count of payloads written to a temporary file because they were too big to be kept in memory")
+	tlmPayloadsWrittenToFileSize = telemetry.NewCounter("serializer", "payloads_written_to_file_bytes",
+		nil, "Size of the payloads written to a temporary file")
+	tlmPayloadFileErrors = telemetry.NewCounter("serializer", "payload_file_errors",
+		nil, "Count of errors when writing a payload to a temporary file, the payload is kept in memory")
+)
+
+func init() {
+	expvars.Set("PayloadsWrittenToFile", &expvarsPayloadsWrittenToFile)
+	expvars.Set("PayloadsWrittenToFileSize", &expvarsPayloadsWrittenToFileSize)
+	expvars.Set("PayloadFileErrors", &expvarsPayloadFileErrors)
+}
+
+// payloadsPathFromConfig returns the folder of the payload files of this process
+func payloadsPathFromConfig() string {
+	payloadsPath := config.Datadog.GetString("serializer_payloads_path")
+	if payloadsPath == "" {
+		payloadsPath = filepath.Join(config.Datadog.GetString("run_path"), "payloads")
+	}
+	// the agents sharing the run path don't remove the files of each other
+	return filepath.Join(payloadsPath, filepath.Base(os.Args[0]))
+}
+
+// removePayloadFiles removes the payload files left by a previous run of the process.
+func removePayloadFiles(dir string) {
+	files, err := filepath.Glob(filepath.Join(dir, payloadFilePattern))
+	if err != nil {
+		return
+	}
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			log.Warnf("Could not remove the payload file %q: %v", f, err)
+		}
+	}
+	if len(files) > 0 {
+		log.Debugf("Removed %d payload files left by a previous run from %q", len(files), dir)
+	}
+}
+
+// payloadWriter keeps the data written to it in memory until it exceeds
+// maxInMemorySize bytes. The data is then moved to a temporary file, where the
+// next writes go, so that the memory used by a giant payload is bounded.
+// When the file cannot be created, the data is kept in memory.
+type payloadWriter struct {
+	dir             string
+	maxInMemorySize int
+
+	buffer bytes.Buffer
+	file   *os.File
+	size   int
+}
+
+// newPayloadWriter returns a payloadWriter, the data is always kept in memory when
+// maxInMemorySize is 0.
+func newPayloadWriter(dir string, maxInMemorySize int) *payloadWriter {
+	return &payloadWriter{
+		dir:             dir,
+		maxInMemorySize: maxInMemorySize,
+	}
+}
+
+// Write implements io.Writer
+func (w *payloadWriter) Write(p []byte) (int, error) {
+	if w.file == nil && w.maxInMemorySize > 0 && w.buffer.Len()+len(p) > w.maxInMemorySize {
+		if err := w.moveToFile(); err != nil {
+			log.Warnf("Could not write a payload of more than %d bytes to a file, keeping it in memory: %v", w.maxInMemorySize, err)
+			expvarsPayloadFileErrors.Add(1)
+			tlmPayloadFileErrors.Inc()
+			w.maxInMemorySize = 0
+		}
+	}
+
+	var n int
+	var err error
+	if w.file != nil {
+		n, err = w.file.Write(p)
+	} else {
+		n, err = w.buffer.Write(p)
+	}
+	w.size += n
+	return n, err
+}
+
+func (w *payloadWriter) moveToFile() error {
+	if err := os.MkdirAll(w.dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(w.dir, payloadFilePattern)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(w.buffer.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+
+	w.file = f
+	w.buffer = bytes.Buffer{}
+	return nil
+}
+
+// close returns the payload, either in memory or in a file. The caller holds a
+// reference on the file payload and must release it.
+func (w *payloadWriter) close() ([]byte, *transaction.FilePayload, error) {
+	if w.file == nil {
+		return w.buffer.Bytes(), nil, nil
+	}
+
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return nil, nil, err
+	}
+
+	expvarsPayloadsWrittenToFile.Add(1)
+	expvarsPayloadsWrittenToFileSize.Add(int64(w.size))
+	tlmPayloadsWrittenToFile.Inc()
+	tlmPayloadsWrittenToFileSize.Add(float64(w.size))
+	log.Debugf("Payload of %d bytes written to %q", w.size, w.file.Name())
+
+	return nil, transaction.NewFilePayload(w.file.Name(), w.size), nil
+}
+
+// abort discards the payload.
+func (w *payloadWriter) abort() {
+	if w.file != nil {
+		w.file.Close()
+		os.Remove(w.file.Name())
+	}
+	w.buffer = bytes.Buffer{}
+}
@@ -0,0 +1,106 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package serializer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayloadWriterInMemory(t *testing.T) {
+	dir := t.TempDir()
+	w := newPayloadWriter(dir, 10)
+	w.Write([]byte("12345"))
+	w.Write([]byte("67890"))
+
+	payload, file, err := w.close()
+	require.NoError(t, err)
+	assert.Nil(t, file)
+	assert.Equal(t, "1234567890", string(payload))
+
+	files, _ := ioutil.ReadDir(dir)
+	assert.Empty(t, files)
+}
+
+func TestPayloadWriterToFile(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "agent")
+	w := newPayloadWriter(dir, 10)
+	w.Write([]byte("12345"))
+	w.Write([]byte("67890"))
+	w.Write([]byte("abc"))
+
+	payload, file, err := w.close()
+	require.NoError(t, err)
+	assert.Nil(t, payload)
+	require.NotNil(t, file)
+	assert.Equal(t, 13, file.Size)
+	assert.Equal(t, dir, filepath.Dir(file.Path))
+
+	content, err := file.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890abc", string(content))
+
+	file.Release()
+	assert.NoFileExists(t, file.Path)
+}
+
+func TestPayloadWriterDisabled(t *testing.T) {
+	w := newPayloadWriter(t.TempDir(), 0)
+	w.Write([]byte("1234567890abc"))
+
+	payload, file, err := w.close()
+	require.NoError(t, err)
+	assert.Nil(t, file)
+	assert.Equal(t, "1234567890abc", string(payload))
+}
+
+func TestPayloadWriterFileError(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, ioutil.WriteFile(dir, nil, 0600))
+
+	// the payload is kept in memory when the file cannot be created
+	w := newPayloadWriter(dir, 10)
+	w.Write([]byte("1234567890abc"))
+	w.Write([]byte("def"))
+
+	payload, file, err := w.close()
+	require.NoError(t, err)
+	assert.Nil(t, file)
+	assert.Equal(t, "1234567890abcdef", string(payload))
+}
+
+func TestPayloadWriterAbort(t *testing.T) {
+	dir := t.TempDir()
+	w := newPayloadWriter(dir, 10)
+	w.Write([]byte("1234567890abc"))
+	w.abort()
+
+	files, _ := ioutil.ReadDir(dir)
+	assert.Empty(t, files)
+}
+
+func TestRemovePayloadFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "payload-1234"), nil, 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "other"), nil, 0600))
+
+	removePayloadFiles(dir)
+
+	assert.NoFileExists(t, filepath.Join(dir, "payload-1234"))
+	assert.FileExists(t, filepath.Join(dir, "other"))
+
+	// no error when the folder doesn't exist
+	removePayloadFiles(filepath.Join(dir, "missing"))
+	_, err := os.Stat(filepath.Join(dir, "missing"))
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package serializer
+
+import (
+	"net/http"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+// routingRuleName returns the name of the routing rule matching the tags, or
+// an empty name when the data is sent to the main organization
+func routingRuleName(rules []config.RoutingRule, tags []string) string {
+	if rule := config.MatchRoutingRule(rules, tags); rule != nil {
+		return rule.Name
+	}
+	return ""
+}
+
+// splitSeriesByRoutingRule splits the series by the name of their routing rule
+func splitSeriesByRoutingRule(rules []config.RoutingRule, series metrics.Series) map[string]metrics.Series {
+	split := make(map[string]metrics.Series)
+	for _, serie := range series {
+		name := routingRuleName(rules, serie.Tags)
+		split[name] = append(split[name], serie)
+	}
+	return split
+}
+
+// splitEventsByRoutingRule splits the events by the name of their routing rule
+func splitEventsByRoutingRule(rules []config.RoutingRule, events metrics.Events) map[string]metrics.Events {
+	split := make(map[string]metrics.Events)
+	for _, event := range events {
+		name := routingRuleName(rules, event.Tags)
+		split[name] = append(split[name], event)
+	}
+	return split
+}
+
+// splitServiceChecksByRoutingRule splits the service checks by the name of their routing rule
+func splitServiceChecksByRoutingRule(rules []config.RoutingRule, serviceChecks metrics.ServiceChecks) map[string]metrics.ServiceChecks {
+	split := make(map[string]metrics.ServiceChecks)
+	for _, serviceCheck := range serviceChecks {
+		name := routingRuleName(rules, serviceCheck.Tags)
+		split[name] = append(split[name], serviceCheck)
+	}
+	return split
+}
+
+// splitSketchesByRoutingRule splits the sketches by the name of their routing rule
+func splitSketchesByRoutingRule(rules []config.RoutingRule, sketches metrics.SketchSeriesList) map[string]metrics.SketchSeriesList {
+	split := make(map[string]metrics.SketchSeriesList)
+	for _, sketch := range sketches {
+		name := routingRuleName(rules, sketch.Tags)
+		split[name] = append(split[name], sketch)
+	}
+	return split
+}
+
+// withRoutingRule returns the extra headers of the payloads of a routing rule,
+// the forwarder sends them with the API key of the rule
+func withRoutingRule(extraHeaders http.Header, ruleName string) http.Header {
+	if ruleName == "" {
+		return extraHeaders
+	}
+	// the extra headers are shared by the payloads of the same type
+	routedHeaders := extraHeaders.Clone()
+	if routedHeaders == nil {
+		routedHeaders = make(http.Header)
+	}
+	routedHeaders.Set(forwarder.RoutingRuleHTTPHeaderKey, ruleName)
+	return routedHeaders
+}
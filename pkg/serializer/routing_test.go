@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build test
+
+package serializer
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
+)
+
+var testRoutingRules = []config.RoutingRule{
+	{Name: "payments", Tags: []string{"team:payments"}, APIKey: "payments-api-key"},
+	{Name: "checkout", Tags: []string{"team:checkout", "env:prod"}, APIKey: "checkout-api-key"},
+}
+
+func TestSplitSeriesByRoutingRule(t *testing.T) {
+	main := &metrics.Serie{Name: "main", Tags: []string{"team:checkout", "env:staging"}}
+	payments := &metrics.Serie{Name: "payments", Tags: []string{"env:prod", "team:payments"}}
+	checkout := &metrics.Serie{Name: "checkout", Tags: []string{"env:prod", "team:checkout"}}
+
+	split := splitSeriesByRoutingRule(testRoutingRules, metrics.Series{main, payments, checkout})
+	assert.Equal(t, map[string]metrics.Series{
+		"":         {main},
+		"payments": {payments},
+		"checkout": {checkout},
+	}, split)
+}
+
+func TestWithRoutingRule(t *testing.T) {
+	headers := make(http.Header)
+	headers.Set("Content-Type", "application/json")
+
+	assert.Equal(t, headers, withRoutingRule(headers, ""))
+
+	routed := withRoutingRule(headers, "payments")
+	assert.Equal(t, "payments", routed.Get(forwarder.RoutingRuleHTTPHeaderKey))
+	assert.Equal(t, "application/json", routed.Get("Content-Type"))
+	// the shared headers aren't modified
+	assert.Empty(t, headers.Get(forwarder.RoutingRuleHTTPHeaderKey))
+}
+
+func TestSendRoutedSeries(t *testing.T) {
+	config.Datadog.Set("enable_stream_payload_serialization", false)
+	defer config.Datadog.Set("enable_stream_payload_serialization", nil)
+
+	f := &forwarder.MockedForwarder{}
+	isRoutedTo := func(ruleName string) interface{} {
+		return mock.MatchedBy(func(headers http.Header) bool {
+			return headers.Get(forwarder.RoutingRuleHTTPHeaderKey) == ruleName
+		})
+	}
+	f.On("SubmitV1Series", mock.Anything, isRoutedTo("")).Return(nil).Times(1)
+	f.On("SubmitV1Series", mock.Anything, isRoutedTo("payments")).Return(nil).Times(1)
+
+	s := NewSerializer(f, nil)
+	s.routingRules = testRoutingRules
+
+	series := metrics.Series{
+		{Name: "main", Tags: []string{"team:checkout"}},
+		{Name: "payments.1", Tags: []string{"team:payments"}},
+		{Name: "payments.2", Tags: []string{"team:payments"}},
+	}
+	require.NoError(t, s.SendSeries(series))
+	f.AssertExpectations(t)
+}
+
+func TestSendRoutedSketches(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		f := &forwarder.MockedForwarder{}
+		isRoutedTo := func(ruleName string) interface{} {
+			return mock.MatchedBy(func(headers http.Header) bool {
+				return headers.Get(forwarder.RoutingRuleHTTPHeaderKey) == ruleName
+			})
+		}
+		f.On("SubmitSketchSeries", mock.Anything, isRoutedTo("")).Return(nil).Times(1)
+		f.On("SubmitSketchSeries", mock.Anything, isRoutedTo("checkout")).Return(nil).Times(1)
+
+		s := NewSerializer(f, nil)
+		s.enableSketchProtobufStream = stream
+		s.routingRules = testRoutingRules
+
+		sketches := metrics.SketchSeriesList{
+			{Name: "main", Tags: []string{"team:checkout"}},
+			{Name: "checkout.1", Tags: []string{"team:checkout", "env:prod"}},
+			{Name: "checkout.2", Tags: []string{"env:prod", "team:checkout"}},
+		}
+		require.NoError(t, s.SendSketch(sketches))
+		f.AssertExpectations(t)
+
+		// the routing header isn't added to the headers shared by the unrouted payloads
+		assert.Empty(t, protobufExtraHeadersWithCompression.Get(forwarder.RoutingRuleHTTPHeaderKey))
+	}
+}
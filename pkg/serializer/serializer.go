@@ -14,8 +14,11 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
+
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	"github.com/DataDog/datadog-agent/pkg/metrics"
 	"github.com/DataDog/datadog-agent/pkg/process/util/api/headers"
 	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 	"github.com/DataDog/datadog-agent/pkg/serializer/split"
@@ -123,6 +126,15 @@ type Serializer struct {
 	enableServiceChecksJSONStream bool
 	enableEventsJSONStream        bool
 	enableSketchProtobufStream    bool
+
+	// The payloads bigger than maxInMemoryPayloadSize are written to a file in
+	// payloadsPath instead of being kept in memory. 0 disables it.
+	maxInMemoryPayloadSize int
+	payloadsPath           string
+
+	// The series, events and service checks matching a routing rule are sent
+	// to the organization of the rule, with its API key
+	routingRules []config.RoutingRule
 }
 
 // NewSerializer returns a new Serializer initialized
@@ -140,8 +152,20 @@ func NewSerializer(forwarder forwarder.Forwarder, orchestratorForwarder forwarde
 		enableServiceChecksJSONStream: stream.Available && config.Datadog.GetBool("enable_service_checks_stream_payload_serialization"),
 		enableEventsJSONStream:        stream.Available && config.Datadog.GetBool("enable_events_stream_payload_serialization"),
 		enableSketchProtobufStream:    stream.Available && config.Datadog.GetBool("enable_sketch_stream_payload_serialization"),
+		maxInMemoryPayloadSize:        config.Datadog.GetInt("serializer_max_in_memory_payload_size"),
+		payloadsPath:                  payloadsPathFromConfig(),
 	}
 
+	if s.maxInMemoryPayloadSize > 0 {
+		removePayloadFiles(s.payloadsPath)
+	}
+
+	routingRules, err := config.GetRoutingRules()
+	if err != nil {
+		log.Errorf("Routing rules are disabled: %v", err)
+	}
+	s.routingRules = routingRules
+
 	if !s.enableEvents {
 		log.Warn("event payloads are disabled: all events will be dropped")
 	}
@@ -248,6 +272,21 @@ func (s *Serializer) SendEvents(e EventsStreamJSONMarshaler) error {
 		return nil
 	}
 
+	if events, ok := e.(metrics.Events); ok && len(s.routingRules) > 0 {
+		var errs error
+		for ruleName, ruleEvents := range splitEventsByRoutingRule(s.routingRules, events) {
+			if err := s.sendEvents(ruleEvents, ruleName); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		return errs
+	}
+	return s.sendEvents(e, "")
+}
+
+// sendEvents sends the events to the organization of the routing rule, or to
+// the main one when ruleName is empty
+func (s *Serializer) sendEvents(e EventsStreamJSONMarshaler, ruleName string) error {
 	useV1API := !config.Datadog.GetBool("use_v2_api.events")
 	var eventPayloads forwarder.Payloads
 	var extraHeaders http.Header
@@ -261,6 +300,7 @@ func (s *Serializer) SendEvents(e EventsStreamJSONMarshaler) error {
 	if err != nil {
 		return fmt.Errorf("dropping event payload: %s", err)
 	}
+	extraHeaders = withRoutingRule(extraHeaders, ruleName)
 
 	if useV1API {
 		return s.Forwarder.SubmitV1Intake(eventPayloads, extraHeaders)
@@ -275,6 +315,21 @@ func (s *Serializer) SendServiceChecks(sc marshaler.StreamJSONMarshaler) error {
 		return nil
 	}
 
+	if serviceChecks, ok := sc.(metrics.ServiceChecks); ok && len(s.routingRules) > 0 {
+		var errs error
+		for ruleName, ruleServiceChecks := range splitServiceChecksByRoutingRule(s.routingRules, serviceChecks) {
+			if err := s.sendServiceChecks(ruleServiceChecks, ruleName); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		return errs
+	}
+	return s.sendServiceChecks(sc, "")
+}
+
+// sendServiceChecks sends the service checks to the organization of the
+// routing rule, or to the main one when ruleName is empty
+func (s *Serializer) sendServiceChecks(sc marshaler.StreamJSONMarshaler, ruleName string) error {
 	useV1API := true
 
 	var serviceCheckPayloads forwarder.Payloads
@@ -289,6 +344,7 @@ func (s *Serializer) SendServiceChecks(sc marshaler.StreamJSONMarshaler) error {
 	if err != nil {
 		return fmt.Errorf("dropping service check payload: %s", err)
 	}
+	extraHeaders = withRoutingRule(extraHeaders, ruleName)
 
 	if useV1API {
 		return s.Forwarder.SubmitV1CheckRuns(serviceCheckPayloads, extraHeaders)
@@ -303,6 +359,21 @@ func (s *Serializer) SendSeries(series marshaler.StreamJSONMarshaler) error {
 		return nil
 	}
 
+	if routedSeries, ok := series.(metrics.Series); ok && len(s.routingRules) > 0 {
+		var errs error
+		for ruleName, ruleSeries := range splitSeriesByRoutingRule(s.routingRules, routedSeries) {
+			if err := s.sendSeries(ruleSeries, ruleName); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		return errs
+	}
+	return s.sendSeries(series, "")
+}
+
+// sendSeries sends the series to the organization of the routing rule, or to
+// the main one when ruleName is empty
+func (s *Serializer) sendSeries(series marshaler.StreamJSONMarshaler, ruleName string) error {
 	const useV1API = true // v2 intake for series is not yet implemented
 
 	var seriesPayloads forwarder.Payloads
@@ -319,7 +390,7 @@ func (s *Serializer) SendSeries(series marshaler.StreamJSONMarshaler) error {
 		return fmt.Errorf("dropping series payload: %s", err)
 	}
 
-	return s.Forwarder.SubmitV1Series(seriesPayloads, extraHeaders)
+	return s.Forwarder.SubmitV1Series(seriesPayloads, withRoutingRule(extraHeaders, ruleName))
 }
 
 // SendSketch serializes a list of SketSeriesList and sends the payload to the forwarder
@@ -329,10 +400,25 @@ func (s *Serializer) SendSketch(sketches marshaler.Marshaler) error {
 		return nil
 	}
 
+	if sketchSeries, ok := sketches.(metrics.SketchSeriesList); ok && len(s.routingRules) > 0 {
+		var errs error
+		for ruleName, ruleSketches := range splitSketchesByRoutingRule(s.routingRules, sketchSeries) {
+			if err := s.sendSketch(ruleSketches, ruleName); err != nil {
+				errs = multierror.Append(errs, err)
+			}
+		}
+		return errs
+	}
+	return s.sendSketch(sketches, "")
+}
+
+// sendSketch sends the sketches to the organization of the routing rule, or
+// to the main one when ruleName is empty
+func (s *Serializer) sendSketch(sketches marshaler.Marshaler, ruleName string) error {
 	if s.enableSketchProtobufStream {
 		payloads, err := sketches.MarshalSplitCompress(marshaler.DefaultBufferContext())
 		if err == nil {
-			return s.Forwarder.SubmitSketchSeries(payloads, protobufExtraHeadersWithCompression)
+			return s.Forwarder.SubmitSketchSeries(payloads, withRoutingRule(protobufExtraHeadersWithCompression, ruleName))
 		}
 		log.Warnf("Error: %v trying to stream compress SketchSeriesList - falling back to split/compress method", err)
 	}
@@ -344,7 +430,7 @@ func (s *Serializer) SendSketch(sketches marshaler.Marshaler) error {
 		return fmt.Errorf("dropping sketch payload: %s", err)
 	}
 
-	return s.Forwarder.SubmitSketchSeries(splitSketches, extraHeaders)
+	return s.Forwarder.SubmitSketchSeries(splitSketches, withRoutingRule(extraHeaders, ruleName))
 }
 
 // SendMetadata serializes a metadata payload and sends it to the forwarder
@@ -418,13 +504,7 @@ func (s *Serializer) SendOrchestratorMetadata(msgs []ProcessMessageBody, hostNam
 		extraHeaders.Set(headers.ClusterIDHeader, clusterID)
 		extraHeaders.Set(headers.TimestampHeader, strconv.Itoa(int(time.Now().Unix())))
 
-		body, err := processPayloadEncoder(m)
-		if err != nil {
-			return log.Errorf("Unable to encode message: %s", err)
-		}
-
-		payloads := forwarder.Payloads{&body}
-		responses, err := s.orchestratorForwarder.SubmitOrchestratorChecks(payloads, extraHeaders, payloadType)
+		responses, err := s.submitOrchestratorPayload(m, extraHeaders, payloadType)
 		if err != nil {
 			return log.Errorf("Unable to submit payload: %s", err)
 		}
@@ -437,3 +517,31 @@ func (s *Serializer) SendOrchestratorMetadata(msgs []ProcessMessageBody, hostNam
 	}
 	return nil
 }
+
+// submitOrchestratorPayload encodes and sends an orchestrator message. When the
+// payloads can be written to a file, the compression output is streamed to a
+// payloadWriter: a payload too big to be kept in memory is sent from a file.
+func (s *Serializer) submitOrchestratorPayload(m ProcessMessageBody, extraHeaders http.Header, payloadType int) (chan forwarder.Response, error) {
+	if s.maxInMemoryPayloadSize == 0 {
+		body, err := processPayloadEncoder(m)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode message: %s", err)
+		}
+		return s.orchestratorForwarder.SubmitOrchestratorChecks(forwarder.Payloads{&body}, extraHeaders, payloadType)
+	}
+
+	w := newPayloadWriter(s.payloadsPath, s.maxInMemoryPayloadSize)
+	if err := processPayloadStreamEncoder(w, m); err != nil {
+		w.abort()
+		return nil, fmt.Errorf("unable to encode message: %s", err)
+	}
+	body, file, err := w.close()
+	if err != nil {
+		return nil, fmt.Errorf("unable to write message: %s", err)
+	}
+	if file != nil {
+		defer file.Release()
+		return s.orchestratorForwarder.SubmitOrchestratorChecksFile(file, extraHeaders, payloadType)
+	}
+	return s.orchestratorForwarder.SubmitOrchestratorChecks(forwarder.Payloads{&body}, extraHeaders, payloadType)
+}
@@ -9,6 +9,7 @@ package serializer
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"testing"
 
@@ -19,6 +20,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/forwarder"
+	"github.com/DataDog/datadog-agent/pkg/forwarder/transaction"
 	"github.com/DataDog/datadog-agent/pkg/serializer/marshaler"
 	"github.com/DataDog/datadog-agent/pkg/util/compression"
 )
@@ -371,6 +373,46 @@ func TestSendProcessesMetadata(t *testing.T) {
 	require.NotNil(t, err)
 }
 
+func TestSubmitOrchestratorPayloadThroughFile(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("serializer_max_in_memory_payload_size", 4)
+	mockConfig.Set("serializer_payloads_path", t.TempDir())
+	defer mockConfig.Set("serializer_max_in_memory_payload_size", 0)
+	defer mockConfig.Set("serializer_payloads_path", "")
+
+	// the encoder streams its output to the payload writer
+	var encoded string
+	defer func(encoder func(io.Writer, ProcessMessageBody) error) { processPayloadStreamEncoder = encoder }(processPayloadStreamEncoder)
+	processPayloadStreamEncoder = func(w io.Writer, m ProcessMessageBody) error {
+		_, err := io.WriteString(w, encoded)
+		return err
+	}
+
+	var path string
+	f := &forwarder.MockedForwarder{}
+	f.On("SubmitOrchestratorChecksFile", mock.MatchedBy(func(p *transaction.FilePayload) bool {
+		path = p.Path
+		payload, err := p.ReadAll()
+		return err == nil && string(payload) == "a big payload"
+	}), http.Header(nil)).Return(nil).Times(1)
+	small := []byte("sm")
+	f.On("SubmitOrchestratorChecks", forwarder.Payloads{&small}, http.Header(nil)).Return(nil).Times(1)
+
+	s := NewSerializer(nil, f)
+
+	var m ProcessMessageBody
+	encoded = "a big payload"
+	_, err := s.submitOrchestratorPayload(m, nil, 0)
+	require.Nil(t, err)
+	encoded = "sm"
+	_, err = s.submitOrchestratorPayload(m, nil, 0)
+	require.Nil(t, err)
+	f.AssertExpectations(t)
+
+	// the file is removed once the forwarder released it
+	assert.NoFileExists(t, path)
+}
+
 func TestSendWithDisabledKind(t *testing.T) {
 	mockConfig := config.Mock()
 
@@ -7,6 +7,8 @@
 
 package serializer
 
+import "io"
+
 // ProcessMessageBody is a type alias for processes proto message body
 // this type alias allows to avoid importing the process agent payload proto
 // in case it's not needed (dogstastd)
@@ -34,3 +36,8 @@ func (stubMessageBody) Size() int      { return 0 }
 var processPayloadEncoder = func(m ProcessMessageBody) ([]byte, error) {
 	return []byte{}, nil
 }
+
+// processPayloadStreamEncoder is a dummy ProcessMessageBody stream encoder
+var processPayloadStreamEncoder = func(w io.Writer, m ProcessMessageBody) error {
+	return nil
+}
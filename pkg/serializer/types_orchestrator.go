@@ -16,3 +16,5 @@ import (
 type ProcessMessageBody = model.MessageBody
 
 var processPayloadEncoder = api.EncodePayload
+
+var processPayloadStreamEncoder = api.EncodePayloadTo
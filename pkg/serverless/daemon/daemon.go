@@ -230,7 +230,7 @@ func (d *Daemon) TriggerFlush(isLastFlushBeforeShutdown bool) {
 	wg := sync.WaitGroup{}
 	wg.Add(3)
 
-	go d.flushMetrics(&wg)
+	go d.flushMetrics(ctx, &wg)
 	go d.flushTraces(&wg)
 	go d.flushLogs(ctx, &wg)
 
@@ -249,12 +249,12 @@ func (d *Daemon) TriggerFlush(isLastFlushBeforeShutdown bool) {
 
 // flushMetrics flushes aggregated metrics to the intake.
 // It is protected by a mutex to ensure only one metrics flush can be in progress at any given time.
-func (d *Daemon) flushMetrics(wg *sync.WaitGroup) {
+func (d *Daemon) flushMetrics(ctx context.Context, wg *sync.WaitGroup) {
 	d.metricsFlushMutex.Lock()
 	flushStartTime := time.Now().Unix()
 	log.Debugf("Beginning metrics flush at time %d", flushStartTime)
 	if d.MetricAgent != nil {
-		d.MetricAgent.Flush()
+		d.MetricAgent.Flush(ctx)
 	}
 	log.Debugf("Finished metrics flush that was started at time %d", flushStartTime)
 	wg.Done()
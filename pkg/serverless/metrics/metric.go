@@ -6,6 +6,7 @@
 package metrics
 
 import (
+	"context"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
@@ -82,10 +83,12 @@ func (c *ServerlessMetricAgent) IsReady() bool {
 	return c.dogStatDServer != nil
 }
 
-// Flush triggers a DogStatsD flush
-func (c *ServerlessMetricAgent) Flush() {
+// Flush synchronously flushes the DogStatsD metrics, until the context is done
+func (c *ServerlessMetricAgent) Flush(ctx context.Context) {
 	if c.IsReady() {
-		c.dogStatDServer.ServerlessFlush()
+		if err := c.dogStatDServer.FlushWithDeadline(ctx); err != nil {
+			log.Debugf("The metrics flush didn't complete: %s", err)
+		}
 	}
 }
 
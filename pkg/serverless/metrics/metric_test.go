@@ -6,6 +6,7 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"net/http"
@@ -101,7 +102,7 @@ func TestRaceFlushVersusAddSample(t *testing.T) {
 		for i := 0; i < 1000; i++ {
 			n := rand.Intn(10)
 			time.Sleep(time.Duration(n) * time.Microsecond)
-			go metricAgent.Flush()
+			go metricAgent.Flush(context.Background())
 		}
 	}()
 
@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package devicelease coordinates the SNMP discovery of agents and CLC runners
+// through the cluster agent, so that a device found by several agents
+// discovering overlapping subnets is polled by only one of them.
+package devicelease
+
+import (
+	"context"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const requestTimeout = 10 * time.Second
+
+type leaseClient interface {
+	LeaseSNMPDevices(ctx context.Context, identifier string, request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error)
+}
+
+// Leaser leases SNMP devices from the cluster agent. Devices are identified by
+// their device ID (`<namespace>:<ip>`). A nil Leaser grants every device.
+type Leaser struct {
+	identifier string
+	ttl        time.Duration
+	getClient  func() (leaseClient, error)
+}
+
+// NewLeaser returns a Leaser renewing leases every discoveryInterval,
+// or nil when `network_devices.discovery.cluster_agent_leases` is disabled.
+func NewLeaser(discoveryInterval time.Duration) *Leaser {
+	if !config.Datadog.GetBool("cluster_agent.enabled") || !config.Datadog.GetBool("network_devices.discovery.cluster_agent_leases") {
+		return nil
+	}
+
+	identifier := config.Datadog.GetString("clc_runner_id")
+	if identifier == "" {
+		identifier, _ = util.GetHostname(context.TODO())
+	}
+
+	return &Leaser{
+		identifier: identifier,
+		// leases survive a missed discovery run
+		ttl: 2 * discoveryInterval,
+		getClient: func() (leaseClient, error) {
+			return clusteragent.GetClusterAgentClient()
+		},
+	}
+}
+
+// Acquire leases or renews the leases of the given devices and returns the granted ones.
+// Every device is granted when the cluster agent can't be reached, to avoid not
+// polling a device at all.
+func (l *Leaser) Acquire(deviceIDs ...string) []string {
+	if l == nil || len(deviceIDs) == 0 {
+		return deviceIDs
+	}
+
+	response, err := l.lease(types.SNMPLeaseRequest{Devices: deviceIDs})
+	if err != nil {
+		log.Warnf("Couldn't lease SNMP devices %v from the cluster agent, polling them anyway: %s", deviceIDs, err)
+		return deviceIDs
+	}
+	for device, owner := range response.Owners {
+		log.Debugf("SNMP device %s is leased to %s", device, owner)
+	}
+	return response.Granted
+}
+
+// Release gives the leases of the given devices back so that other agents can poll them
+func (l *Leaser) Release(deviceIDs ...string) {
+	if l == nil || len(deviceIDs) == 0 {
+		return
+	}
+
+	if _, err := l.lease(types.SNMPLeaseRequest{Release: deviceIDs}); err != nil {
+		log.Debugf("Couldn't release SNMP devices %v: %s", deviceIDs, err)
+	}
+}
+
+func (l *Leaser) lease(request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error) {
+	client, err := l.getClient()
+	if err != nil {
+		return types.SNMPLeaseResponse{}, err
+	}
+
+	request.TTL = int(l.ttl.Seconds())
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	return client.LeaseSNMPDevices(ctx, l.identifier, request)
+}
@@ -0,0 +1,118 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package devices holds a registry of the devices monitored by the snmp corecheck,
+// so that the traps sent by these devices can be correlated with their polled metrics.
+package devices
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// registrationTTL is the duration after which a device that is not polled anymore is forgotten,
+// devices are registered again at every run of the check.
+const registrationTTL = time.Hour
+
+// Device is a device monitored by the snmp corecheck
+type Device struct {
+	Namespace string
+	DeviceID  string
+	Profile   string
+	// Tags are the tags of the device shared by the metrics and the traps
+	Tags []string
+}
+
+type registration struct {
+	device       Device
+	registeredAt time.Time
+}
+
+// Registry maps the IP addresses of the monitored devices to their identity
+type Registry struct {
+	mu      sync.RWMutex
+	devices map[string]map[string]registration
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// DefaultRegistry is the registry shared by the snmp corecheck and the traps listener
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry returns a new empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		devices: make(map[string]map[string]registration),
+		ttl:     registrationTTL,
+		now:     time.Now,
+	}
+}
+
+// Register adds or refreshes a device monitored at the given IP address
+func (r *Registry) Register(ip string, device Device) {
+	device.Tags = append([]string(nil), device.Tags...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	byID, ok := r.devices[ip]
+	if !ok {
+		byID = make(map[string]registration)
+		r.devices[ip] = byID
+	}
+	byID[device.DeviceID] = registration{device: device, registeredAt: r.now()}
+}
+
+// Unregister removes a device from the registry
+func (r *Registry) Unregister(ip string, deviceID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if byID, ok := r.devices[ip]; ok {
+		delete(byID, deviceID)
+		if len(byID) == 0 {
+			delete(r.devices, ip)
+		}
+	}
+}
+
+// Lookup returns the device monitored at the given IP address. When several namespaces
+// monitor the same IP address, the device with the lowest device ID is returned.
+func (r *Registry) Lookup(ip string) (Device, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var candidates []Device
+	now := r.now()
+	for _, reg := range r.devices[ip] {
+		if now.Sub(reg.registeredAt) > r.ttl {
+			continue
+		}
+		candidates = append(candidates, reg.device)
+	}
+	if len(candidates) == 0 {
+		return Device{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].DeviceID < candidates[j].DeviceID
+	})
+	device := candidates[0]
+	device.Tags = append([]string(nil), device.Tags...)
+	return device, true
+}
+
+// Register adds or refreshes a device of the default registry
+func Register(ip string, device Device) {
+	DefaultRegistry.Register(ip, device)
+}
+
+// Unregister removes a device from the default registry
+func Unregister(ip string, deviceID string) {
+	DefaultRegistry.Unregister(ip, deviceID)
+}
+
+// Lookup returns the device of the default registry monitored at the given IP address
+func Lookup(ip string) (Device, bool) {
+	return DefaultRegistry.Lookup(ip)
+}
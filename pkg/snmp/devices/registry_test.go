@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package devices
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+
+	_, found := r.Lookup("10.0.0.1")
+	assert.False(t, found)
+
+	tags := []string{"snmp_profile:cisco", "device_namespace:ns2"}
+	r.Register("10.0.0.1", Device{Namespace: "ns2", DeviceID: "ns2:10.0.0.1", Profile: "cisco", Tags: tags})
+	r.Register("10.0.0.1", Device{Namespace: "ns1", DeviceID: "ns1:10.0.0.1", Profile: "f5"})
+	tags[0] = "modified"
+
+	device, found := r.Lookup("10.0.0.1")
+	assert.True(t, found)
+	assert.Equal(t, "ns1:10.0.0.1", device.DeviceID)
+
+	r.Unregister("10.0.0.1", "ns1:10.0.0.1")
+	device, found = r.Lookup("10.0.0.1")
+	assert.True(t, found)
+	assert.Equal(t, Device{Namespace: "ns2", DeviceID: "ns2:10.0.0.1", Profile: "cisco", Tags: []string{"snmp_profile:cisco", "device_namespace:ns2"}}, device)
+
+	r.Unregister("10.0.0.1", "ns2:10.0.0.1")
+	_, found = r.Lookup("10.0.0.1")
+	assert.False(t, found)
+	assert.Empty(t, r.devices)
+}
+
+func TestRegistryExpiration(t *testing.T) {
+	now := time.Now()
+	r := NewRegistry()
+	r.now = func() time.Time { return now }
+
+	r.Register("10.0.0.1", Device{Namespace: "default", DeviceID: "default:10.0.0.1"})
+
+	now = now.Add(registrationTTL)
+	_, found := r.Lookup("10.0.0.1")
+	assert.True(t, found)
+
+	now = now.Add(time.Second)
+	_, found = r.Lookup("10.0.0.1")
+	assert.False(t, found)
+
+	// registering the device again refreshes it
+	r.Register("10.0.0.1", Device{Namespace: "default", DeviceID: "default:10.0.0.1"})
+	_, found = r.Lookup("10.0.0.1")
+	assert.True(t, found)
+}
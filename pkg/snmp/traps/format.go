@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"github.com/gosnmp/gosnmp"
+
+	"github.com/DataDog/datadog-agent/pkg/snmp/devices"
 )
 
 const (
@@ -18,16 +20,49 @@ const (
 )
 
 // FormatPacketToJSON converts an SNMP trap packet to a JSON-serializable object.
+// When the trap is sent by a device monitored by the snmp corecheck, the identity
+// of the device is added so that the trap can be correlated with its metrics.
 func FormatPacketToJSON(packet *SnmpPacket) (map[string]interface{}, error) {
-	return formatTrapPDUs(packet.Content.Variables)
+	data, err := formatTrapPDUs(packet.Content.Variables)
+	if err != nil {
+		return nil, err
+	}
+	if device, found := devices.Lookup(packet.Addr.IP.String()); found {
+		data["device"] = map[string]interface{}{
+			"namespace": device.Namespace,
+			"device_id": device.DeviceID,
+			"profile":   device.Profile,
+		}
+	}
+	return data, nil
 }
 
 // GetTags returns a list of tags associated to an SNMP trap packet.
+// The tags of the device are added when it is monitored by the snmp corecheck.
 func GetTags(packet *SnmpPacket) []string {
-	return []string{
+	ip := packet.Addr.IP.String()
+	tags := []string{
 		fmt.Sprintf("snmp_version:%s", formatVersion(packet)),
-		fmt.Sprintf("snmp_device:%s", packet.Addr.IP.String()),
+		fmt.Sprintf("snmp_device:%s", ip),
+	}
+	if device, found := devices.Lookup(ip); found {
+		tags = append(tags, fmt.Sprintf("device_id:%s", device.DeviceID))
+		for _, tag := range device.Tags {
+			if !containsTag(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return tags
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
 	}
+	return false
 }
 
 func formatVersion(packet *SnmpPacket) string {
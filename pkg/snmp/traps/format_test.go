@@ -12,6 +12,8 @@ import (
 	"github.com/gosnmp/gosnmp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/snmp/devices"
 )
 
 func createTestPacket() *SnmpPacket {
@@ -96,3 +98,37 @@ func TestGetTagsForUnsupportedVersionShouldStillSucceed(t *testing.T) {
 		"snmp_device:127.0.0.1",
 	})
 }
+
+func TestFormatPacketFromMonitoredDevice(t *testing.T) {
+	devices.Register("127.0.0.1", devices.Device{
+		Namespace: "default",
+		DeviceID:  "default:127.0.0.1",
+		Profile:   "generic-router",
+		Tags:      []string{"device_namespace:default", "snmp_device:127.0.0.1", "snmp_profile:generic-router"},
+	})
+	defer devices.Unregister("127.0.0.1", "default:127.0.0.1")
+	packet := createTestPacket()
+
+	data, err := FormatPacketToJSON(packet)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"namespace": "default",
+		"device_id": "default:127.0.0.1",
+		"profile":   "generic-router",
+	}, data["device"])
+
+	assert.Equal(t, []string{
+		"snmp_version:2",
+		"snmp_device:127.0.0.1",
+		"device_id:default:127.0.0.1",
+		"device_namespace:default",
+		"snmp_profile:generic-router",
+	}, GetTags(packet))
+
+	// traps from other devices are not enriched
+	packet.Addr.IP = net.ParseIP("127.0.0.2")
+	data, err = FormatPacketToJSON(packet)
+	require.NoError(t, err)
+	assert.NotContains(t, data, "device")
+	assert.Equal(t, []string{"snmp_version:2", "snmp_device:127.0.0.2"}, GetTags(packet))
+}
@@ -23,6 +23,13 @@ func RegisterLiveness(name string) *Handle {
 	return readinessAndLivenessCatalog.register(name)
 }
 
+// RegisterLivenessWithRestart registers a component for liveness check like RegisterLiveness,
+// the watchdog calls restart when the component is wedged instead of stopping the agent.
+// restart must start a new instance of the component, which registers itself again.
+func RegisterLivenessWithRestart(name string, restart func()) *Handle {
+	return readinessAndLivenessCatalog.registerWithRestart(name, restart)
+}
+
 // Deregister a component from the healthcheck
 func Deregister(handle *Handle) error {
 	if readinessAndLivenessCatalog.deregister(handle) == nil {
@@ -28,6 +28,10 @@ type component struct {
 	name       string
 	healthChan chan time.Time
 	healthy    bool
+	// unhealthySince is the first ping the component missed in a row, zero when healthy
+	unhealthySince time.Time
+	// restart replaces the component when the watchdog finds it wedged, nil if it can't be restarted
+	restart func()
 }
 
 type catalog struct {
@@ -45,6 +49,11 @@ func newCatalog() *catalog {
 
 // register a component with the default 30 seconds timeout, returns a token
 func (c *catalog) register(name string) *Handle {
+	return c.registerWithRestart(name, nil)
+}
+
+// registerWithRestart registers a component that the watchdog can restart by calling restart
+func (c *catalog) registerWithRestart(name string, restart func()) *Handle {
 	c.Lock()
 	defer c.Unlock()
 
@@ -56,6 +65,7 @@ func (c *catalog) register(name string) *Handle {
 		name:       name,
 		healthChan: make(chan time.Time, bufferSize),
 		healthy:    false,
+		restart:    restart,
 	}
 	h := &Handle{
 		C: component.healthChan,
@@ -99,8 +109,12 @@ func (c *catalog) pingComponents(healthDeadline time.Time) bool {
 		select {
 		case component.healthChan <- healthDeadline:
 			component.healthy = true
+			component.unhealthySince = time.Time{}
 		default:
 			component.healthy = false
+			if component.unhealthySince.IsZero() {
+				component.unhealthySince = time.Now()
+			}
 		}
 	}
 	c.latestRun = time.Now()
@@ -119,6 +133,33 @@ func (c *catalog) deregister(handle *Handle) error {
 	return nil
 }
 
+// wedgedComponent is a component that missed its pings for too long
+type wedgedComponent struct {
+	handle  *Handle
+	name    string
+	since   time.Time
+	restart func()
+}
+
+// getWedged returns the components that have been unhealthy since before the deadline
+func (c *catalog) getWedged(deadline time.Time) []wedgedComponent {
+	c.RLock()
+	defer c.RUnlock()
+
+	var wedged []wedgedComponent
+	for handle, component := range c.components {
+		if !component.unhealthySince.IsZero() && component.unhealthySince.Before(deadline) {
+			wedged = append(wedged, wedgedComponent{
+				handle:  handle,
+				name:    component.name,
+				since:   component.unhealthySince,
+				restart: component.restart,
+			})
+		}
+	}
+	return wedged
+}
+
 // Status represents the current status of registered components
 // it is built and returned by GetStatus()
 type Status struct {
@@ -0,0 +1,155 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package health
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Watchdog looks for the components registered for liveness that stopped reading their
+// health channel for too long. A wedged component is restarted when it registered a restart
+// function, otherwise, or when it was restarted too many times, the watchdog writes a
+// diagnostic dump and asks the agent to exit so that the service manager restarts it.
+type Watchdog struct {
+	catalog     *catalog
+	timeout     time.Duration
+	maxRestarts int
+	dumpDir     string
+	exit        func()
+
+	m        sync.Mutex
+	restarts map[string]int
+	exited   bool
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewWatchdog returns a watchdog for the components registered for liveness. A component is
+// wedged when it has been unhealthy for longer than timeout, it is restarted at most
+// maxRestarts times. The diagnostic dump is written in dumpDir before calling exit.
+func NewWatchdog(timeout time.Duration, maxRestarts int, dumpDir string, exit func()) *Watchdog {
+	return newWatchdog(readinessAndLivenessCatalog, timeout, maxRestarts, dumpDir, exit)
+}
+
+func newWatchdog(c *catalog, timeout time.Duration, maxRestarts int, dumpDir string, exit func()) *Watchdog {
+	return &Watchdog{
+		catalog:     c,
+		timeout:     timeout,
+		maxRestarts: maxRestarts,
+		dumpDir:     dumpDir,
+		exit:        exit,
+		restarts:    make(map[string]int),
+		stop:        make(chan struct{}),
+		stopped:     make(chan struct{}),
+	}
+}
+
+// Start starts checking the components at the frequency of the health pings
+func (w *Watchdog) Start() {
+	go func() {
+		defer close(w.stopped)
+
+		ticker := time.NewTicker(pingFrequency)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				w.check(now)
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the watchdog
+func (w *Watchdog) Stop() {
+	close(w.stop)
+	<-w.stopped
+}
+
+// check restarts the wedged components, or exits if one of them can't be restarted
+func (w *Watchdog) check(now time.Time) {
+	wedged := w.catalog.getWedged(now.Add(-w.timeout))
+	if len(wedged) == 0 {
+		return
+	}
+
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	if w.exited {
+		return
+	}
+
+	var unrecoverable []wedgedComponent
+	for _, component := range wedged {
+		if component.restart == nil || w.restarts[component.name] >= w.maxRestarts {
+			unrecoverable = append(unrecoverable, component)
+			continue
+		}
+
+		w.restarts[component.name]++
+		log.Warnf("Watchdog: component %s has been unhealthy since %s, restarting it (%d/%d)",
+			component.name, component.since.Format(time.RFC3339), w.restarts[component.name], w.maxRestarts)
+
+		// the new instance of the component registers itself again
+		w.catalog.deregister(component.handle) //nolint:errcheck
+		go component.restart()
+	}
+
+	if len(unrecoverable) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(unrecoverable))
+	for _, component := range unrecoverable {
+		names = append(names, component.name)
+	}
+	sort.Strings(names)
+
+	w.exited = true
+	dumpPath, err := w.dump(now, unrecoverable)
+	if err != nil {
+		log.Errorf("Watchdog: unable to write the diagnostic dump: %v", err)
+	}
+	log.Criticalf("Watchdog: components %v are wedged and can't be restarted, exiting. Diagnostic dump: %s", names, dumpPath)
+	w.exit()
+}
+
+// dump writes the wedged components, the health status and the stacks of all goroutines
+// to a new file in the dump directory and returns its path
+func (w *Watchdog) dump(now time.Time, wedged []wedgedComponent) (string, error) {
+	path := filepath.Join(w.dumpDir, fmt.Sprintf("watchdog-%s.log", now.UTC().Format("20060102-150405")))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	fmt.Fprintf(f, "Watchdog diagnostic dump, %s\n\nWedged components:\n", now.UTC().Format(time.RFC3339))
+	for _, component := range wedged {
+		fmt.Fprintf(f, "  %s: unhealthy since %s, restarted %d times\n",
+			component.name, component.since.UTC().Format(time.RFC3339), w.restarts[component.name])
+	}
+
+	status := w.catalog.getStatus()
+	fmt.Fprintf(f, "\nHealthy components: %v\nUnhealthy components: %v\n\nGoroutines:\n", status.Healthy, status.Unhealthy)
+
+	if err := pprof.Lookup("goroutine").WriteTo(f, 2); err != nil {
+		return path, err
+	}
+	return path, nil
+}
@@ -0,0 +1,84 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package health
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchdogHealthyComponents(t *testing.T) {
+	cat := newCatalog()
+	h := cat.register("test1")
+	<-h.C
+	<-h.C
+	cat.pingComponents(time.Now())
+
+	exited := false
+	w := newWatchdog(cat, time.Minute, 1, t.TempDir(), func() { exited = true })
+	w.check(time.Now().Add(time.Hour))
+	assert.False(t, exited)
+	assert.Empty(t, cat.getWedged(time.Now().Add(time.Hour)))
+}
+
+func TestWatchdogRestart(t *testing.T) {
+	cat := newCatalog()
+	restarted := make(chan struct{}, 1)
+	var h *Handle
+	h = cat.registerWithRestart("test1", func() {
+		h = cat.registerWithRestart("test1", nil)
+		restarted <- struct{}{}
+	})
+	old := h
+	cat.pingComponents(time.Now())
+
+	exited := false
+	w := newWatchdog(cat, time.Minute, 1, t.TempDir(), func() { exited = true })
+
+	// not wedged for long enough
+	w.check(time.Now())
+	assert.Len(t, cat.components, 1)
+
+	w.check(time.Now().Add(time.Hour))
+	select {
+	case <-restarted:
+	case <-time.After(time.Second):
+		t.Fatal("the component wasn't restarted")
+	}
+	assert.False(t, exited)
+	_, found := cat.components[old]
+	assert.False(t, found)
+	assert.Len(t, cat.components, 1)
+	assert.Equal(t, 1, w.restarts["test1"])
+}
+
+func TestWatchdogExitAfterMaxRestarts(t *testing.T) {
+	cat := newCatalog()
+	restarts := 0
+	cat.registerWithRestart("test1", func() { restarts++ })
+	cat.pingComponents(time.Now())
+
+	dumpDir := t.TempDir()
+	exited := 0
+	w := newWatchdog(cat, time.Minute, 0, dumpDir, func() { exited++ })
+
+	now := time.Now().Add(time.Hour)
+	w.check(now)
+	w.check(now)
+	assert.Equal(t, 0, restarts)
+	assert.Equal(t, 1, exited)
+
+	dump, err := ioutil.ReadFile(filepath.Join(dumpDir, "watchdog-"+now.UTC().Format("20060102-150405")+".log"))
+	require.NoError(t, err)
+	assert.Contains(t, string(dump), "test1: unhealthy since")
+	assert.Contains(t, string(dump), "Goroutines:")
+	assert.Contains(t, string(dump), "TestWatchdogExitAfterMaxRestarts")
+}
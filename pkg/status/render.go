@@ -17,6 +17,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/otlp"
 	"github.com/DataDog/datadog-agent/pkg/snmp/traps"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -74,8 +75,12 @@ func FormatStatus(data []byte) (string, error) {
 	if traps.IsEnabled() {
 		renderStatusTemplate(b, "/snmp-traps.tmpl", snmpTrapsStats)
 	}
+	if otlp.IsEnabled(config.Datadog) {
+		renderStatusTemplate(b, "/otlp.tmpl", stats["otlpStats"])
+	}
 	if config.IsContainerized() {
 		renderAutodiscoveryStats(b, stats["adEnabledFeatures"], stats["adConfigErrors"], stats["filterErrors"])
+		renderStatusTemplate(b, "/workloadmeta.tmpl", stats)
 	}
 
 	return b.String(), nil
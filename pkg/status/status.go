@@ -24,6 +24,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/logs"
 	"github.com/DataDog/datadog-agent/pkg/metadata/host"
+	"github.com/DataDog/datadog-agent/pkg/otlp"
 	"github.com/DataDog/datadog-agent/pkg/snmp/traps"
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
@@ -31,6 +32,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/apiserver"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/version"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 
 	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 )
@@ -84,7 +86,12 @@ func GetStatus() (map[string]interface{}, error) {
 		httputils.NoProxyMapMutex.Unlock()
 	}
 
+	if otlp.IsEnabled(config.Datadog) {
+		stats["otlpStats"] = common.OTLP.GetStatus()
+	}
+
 	if config.IsContainerized() {
+		stats["workloadmetaCollectors"] = workloadmeta.GetGlobalStore().CollectorsHealth()
 		stats["adEnabledFeatures"] = config.GetDetectedFeatures()
 		if common.AC != nil {
 			stats["adConfigErrors"] = common.AC.GetAutodiscoveryErrors()
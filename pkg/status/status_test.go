@@ -49,6 +49,23 @@ func Test_convertExpvarRunnerStats(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:      "cpu time present",
+			inputJSON: []byte(`{"Checks": {"foo": {"id1": {"AverageExecutionTime": 42, "AverageCPUTime": 12, "MetricSamples": 100, "LastError": ""}}}}`),
+			want: CLCChecks{
+				Checks: map[string]map[string]CLCStats{
+					"foo": {
+						"id1": {
+							AverageExecutionTime: 42,
+							AverageCPUTime:       12,
+							MetricSamples:        100,
+							LastExecFailed:       false,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name:      "bad json",
 			inputJSON: []byte(`{"Checks": bad-json{}}`),
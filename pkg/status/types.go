@@ -19,6 +19,7 @@ type CLCChecks struct {
 // CLCStats is used to unmarshall the stats needed from the runner expvar payload
 type CLCStats struct {
 	AverageExecutionTime int  `json:"AverageExecutionTime"`
+	AverageCPUTime       int  `json:"AverageCPUTime"`
 	MetricSamples        int  `json:"MetricSamples"`
 	LastExecFailed       bool `json:"LastExecFailed"`
 }
@@ -30,6 +31,7 @@ func (d *CLCStats) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	d.AverageExecutionTime = int(stats.AverageExecutionTime)
+	d.AverageCPUTime = int(stats.AverageCPUTime)
 	d.MetricSamples = int(stats.MetricSamples)
 	if stats.LastError != "" {
 		d.LastExecFailed = true
@@ -19,6 +19,13 @@ const (
 	tagKeyKubeAppPartOf    = "kube_app_part_of"
 	tagKeyKubeAppManagedBy = "kube_app_managed_by"
 
+	// TagKeyKubeSeccompProfile is the tag key of the seccomp profile declared by the pod spec of a container
+	TagKeyKubeSeccompProfile = "kube_seccomp_profile"
+	// TagKeyKubeAppArmorProfile is the tag key of the AppArmor profile declared by the pod spec of a container
+	TagKeyKubeAppArmorProfile = "kube_apparmor_profile"
+	// TagKeyKubeSELinuxType is the tag key of the SELinux type declared by the pod spec of a container
+	TagKeyKubeSELinuxType = "kube_selinux_type"
+
 	// Standard tag - Environment variables
 	envVarEnv     = "DD_ENV"
 	envVarVersion = "DD_VERSION"
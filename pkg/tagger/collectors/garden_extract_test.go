@@ -89,6 +89,10 @@ func (fakeDCAClient) GetNodeLabels(nodeName string) (map[string]string, error) {
 	panic("implement me")
 }
 
+func (fakeDCAClient) GetNodeTaints(nodeName string) ([]string, error) {
+	panic("implement me")
+}
+
 func (fakeDCAClient) GetNamespaceLabels(nsName string) (map[string]string, error) {
 	panic("implement me")
 }
@@ -113,6 +117,14 @@ func (fakeDCAClient) GetEndpointsCheckConfigs(ctx context.Context, nodeName stri
 	panic("implement me")
 }
 
+func (fakeDCAClient) GetCheckTemplates(ctx context.Context, version, etag string) (types.CheckTemplatesResponse, string, bool, error) {
+	panic("implement me")
+}
+
+func (fakeDCAClient) LeaseSNMPDevices(ctx context.Context, identifier string, request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error) {
+	panic("implement me")
+}
+
 func (fakeDCAClient) GetKubernetesClusterID() (string, error) {
 	panic("implement me")
 }
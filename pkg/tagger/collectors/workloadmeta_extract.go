@@ -401,6 +401,15 @@ func (c *WorkloadMetaCollector) extractTagsFromPodContainer(pod *workloadmeta.Ku
 	tags.AddLow("image_tag", image.Tag)
 	tags.AddLow("image_id", image.ID)
 
+	// confinement declared by the pod spec, used by the runtime security
+	// agent to detect the processes running with a looser confinement
+	if c.collectConfinementTags {
+		securityProfile := podContainer.SecurityProfile
+		tags.AddLow(TagKeyKubeSeccompProfile, securityProfile.Seccomp)
+		tags.AddLow(TagKeyKubeAppArmorProfile, securityProfile.AppArmor)
+		tags.AddLow(TagKeyKubeSELinuxType, securityProfile.SELinuxType)
+	}
+
 	// enrich with standard tags from labels for this container if present
 	standardTagKeys := map[string]string{
 		fmt.Sprintf(podStandardLabelPrefix+"%s.%s", container.Name, tagKeyEnv):     tagKeyEnv,
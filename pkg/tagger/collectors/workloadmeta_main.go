@@ -49,6 +49,7 @@ type WorkloadMetaCollector struct {
 	globContainerEnvLabels map[string]glob.Glob
 
 	collectEC2ResourceTags bool
+	collectConfinementTags bool
 }
 
 // Detect initializes the WorkloadMetaCollector.
@@ -57,6 +58,7 @@ func (c *WorkloadMetaCollector) Detect(ctx context.Context, out chan<- []*TagInf
 	c.stop = make(chan struct{})
 	c.children = make(map[string]map[string]struct{})
 	c.collectEC2ResourceTags = config.Datadog.GetBool("ecs_collect_resource_tags_ec2")
+	c.collectConfinementTags = config.Datadog.GetBool("runtime_security_config.confinement_drift.enabled")
 
 	containerLabelsAsTags := mergeMaps(
 		retrieveMappingFromConfig("docker_labels_as_tags"),
@@ -78,12 +78,13 @@ func TestHandleKubePod(t *testing.T) {
 	})
 
 	tests := []struct {
-		name              string
-		labelsAsTags      map[string]string
-		annotationsAsTags map[string]string
-		nsLabelsAsTags    map[string]string
-		pod               workloadmeta.KubernetesPod
-		expected          []*TagInfo
+		name                   string
+		labelsAsTags           map[string]string
+		annotationsAsTags      map[string]string
+		nsLabelsAsTags         map[string]string
+		collectConfinementTags bool
+		pod                    workloadmeta.KubernetesPod
+		expected               []*TagInfo
 	}{
 		{
 			name: "fully formed pod (no containers)",
@@ -295,6 +296,109 @@ func TestHandleKubePod(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:                   "pod with container, declared security profile",
+			collectConfinementTags: true,
+			pod: workloadmeta.KubernetesPod{
+				EntityID: podEntityID,
+				EntityMeta: workloadmeta.EntityMeta{
+					Name:      podName,
+					Namespace: podNamespace,
+				},
+				Containers: []workloadmeta.OrchestratorContainer{
+					{
+						ID:   noEnvContainerID,
+						Name: containerName,
+						SecurityProfile: workloadmeta.ContainerSecurityProfile{
+							Seccomp:  "RuntimeDefault",
+							AppArmor: "runtime/default",
+						},
+					},
+				},
+			},
+			expected: []*TagInfo{
+				{
+					Source:       podSource,
+					Entity:       podTaggerEntityID,
+					HighCardTags: []string{},
+					OrchestratorCardTags: []string{
+						fmt.Sprintf("pod_name:%s", podName),
+					},
+					LowCardTags: append([]string{
+						fmt.Sprintf("kube_namespace:%s", podNamespace),
+					}),
+					StandardTags: []string{},
+				},
+				{
+					Source: podSource,
+					Entity: noEnvContainerTaggerEntityID,
+					HighCardTags: []string{
+						fmt.Sprintf("container_id:%s", noEnvContainerID),
+						fmt.Sprintf("display_container_name:%s_%s", containerName, podName),
+					},
+					OrchestratorCardTags: []string{
+						fmt.Sprintf("pod_name:%s", podName),
+					},
+					LowCardTags: []string{
+						fmt.Sprintf("kube_namespace:%s", podNamespace),
+						fmt.Sprintf("kube_container_name:%s", containerName),
+						"kube_apparmor_profile:runtime/default",
+						"kube_seccomp_profile:RuntimeDefault",
+					},
+					StandardTags: []string{},
+				},
+			},
+		},
+		{
+			name: "pod with container, declared security profile, confinement drift disabled",
+			pod: workloadmeta.KubernetesPod{
+				EntityID: podEntityID,
+				EntityMeta: workloadmeta.EntityMeta{
+					Name:      podName,
+					Namespace: podNamespace,
+				},
+				Containers: []workloadmeta.OrchestratorContainer{
+					{
+						ID:   noEnvContainerID,
+						Name: containerName,
+						SecurityProfile: workloadmeta.ContainerSecurityProfile{
+							Seccomp:  "RuntimeDefault",
+							AppArmor: "runtime/default",
+						},
+					},
+				},
+			},
+			expected: []*TagInfo{
+				{
+					Source:       podSource,
+					Entity:       podTaggerEntityID,
+					HighCardTags: []string{},
+					OrchestratorCardTags: []string{
+						fmt.Sprintf("pod_name:%s", podName),
+					},
+					LowCardTags: append([]string{
+						fmt.Sprintf("kube_namespace:%s", podNamespace),
+					}),
+					StandardTags: []string{},
+				},
+				{
+					Source: podSource,
+					Entity: noEnvContainerTaggerEntityID,
+					HighCardTags: []string{
+						fmt.Sprintf("container_id:%s", noEnvContainerID),
+						fmt.Sprintf("display_container_name:%s_%s", containerName, podName),
+					},
+					OrchestratorCardTags: []string{
+						fmt.Sprintf("pod_name:%s", podName),
+					},
+					LowCardTags: []string{
+						fmt.Sprintf("kube_namespace:%s", podNamespace),
+						fmt.Sprintf("kube_container_name:%s", containerName),
+					},
+					StandardTags: []string{},
+				},
+			},
+		},
 		{
 			name: "pod from openshift deployment",
 			pod: workloadmeta.KubernetesPod{
@@ -331,8 +435,9 @@ func TestHandleKubePod(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			collector := &WorkloadMetaCollector{
-				store:    store,
-				children: make(map[string]map[string]struct{}),
+				store:                  store,
+				children:               make(map[string]map[string]struct{}),
+				collectConfinementTags: tt.collectConfinementTags,
 			}
 
 			collector.initPodMetaAsTags(tt.labelsAsTags, tt.annotationsAsTags, tt.nsLabelsAsTags)
@@ -7,6 +7,8 @@ package utils
 
 import (
 	"strings"
+	"sync"
+	"text/template"
 
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/tmplvar"
@@ -17,12 +19,20 @@ import (
 // InitMetadataAsTags prepares labels and annotations as tags
 // - It lower-case all the labels in metadataAsTags
 // - It compiles all the patterns and stores them in a map of glob.Glob objects
+// - It compiles the Go templates used as tag names, invalid templates are dropped
 func InitMetadataAsTags(metadataAsTags map[string]string) (map[string]string, map[string]glob.Glob) {
 	// We lower-case the values collected by viper as well as the ones from inspecting the pod labels/annotations.
 	globMap := map[string]glob.Glob{}
 	for label, value := range metadataAsTags {
 		delete(metadataAsTags, label)
 		pattern := strings.ToLower(label)
+		if isGoTemplate(value) {
+			if _, err := compileTagTemplate(value); err != nil {
+				log.Errorf("Failed to compile tag template for [%s]: %v", pattern, err)
+				delete(metadataAsTags, pattern)
+				continue
+			}
+		}
 		metadataAsTags[pattern] = value
 		if strings.Index(pattern, "*") != -1 {
 			g, err := glob.Compile(pattern)
@@ -47,10 +57,77 @@ func AddMetadataAsTags(name, value string, metadataAsTags map[string]string, glo
 		} else if pattern != n {
 			continue
 		}
+		if isGoTemplate(tmpl) {
+			addTemplatedTag(tmpl, name, value, tags)
+			continue
+		}
 		tags.AddAuto(resolveTag(tmpl, name), value)
 	}
 }
 
+// tagTemplateFuncs are the functions available in the tag templates,
+// the arguments follow the order of the sprig functions of the same name
+var tagTemplateFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"trim":       strings.TrimSpace,
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"replace":    func(old, new, s string) string { return strings.Replace(s, old, new, -1) },
+}
+
+// tagTemplateData is the data a tag template is executed with
+type tagTemplateData struct {
+	// Name is the name of the label, annotation or environment variable
+	Name string
+	// Value is its value
+	Value string
+}
+
+// tagTemplates caches the compiled tag templates, keyed by their source
+var tagTemplates sync.Map
+
+func isGoTemplate(tmpl string) bool {
+	return strings.Contains(tmpl, "{{")
+}
+
+func compileTagTemplate(tmpl string) (*template.Template, error) {
+	if t, ok := tagTemplates.Load(tmpl); ok {
+		return t.(*template.Template), nil
+	}
+	t, err := template.New("tag").Funcs(tagTemplateFuncs).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	tagTemplates.Store(tmpl, t)
+	return t, nil
+}
+
+// addTemplatedTag executes a tag template. It renders either the tag name, the
+// tag value being the label value, or `name:value` to transform the value too.
+func addTemplatedTag(tmpl, name, value string, tags *TagList) {
+	t, err := compileTagTemplate(tmpl)
+	if err != nil {
+		log.Debugf("Failed to compile tag template %q: %v", tmpl, err)
+		return
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, tagTemplateData{Name: name, Value: value}); err != nil {
+		log.Debugf("Failed to execute tag template %q for %q: %v", tmpl, name, err)
+		return
+	}
+
+	tagName, tagValue := sb.String(), value
+	if i := strings.Index(tagName, ":"); i >= 0 {
+		tagName, tagValue = tagName[:i], tagName[i+1:]
+	}
+	if tagName == "" || tagName == "+" || tagValue == "" {
+		return
+	}
+	tags.AddAuto(tagName, tagValue)
+}
+
 var templateVariables = map[string]struct{}{
 	"label":      {},
 	"annotation": {},
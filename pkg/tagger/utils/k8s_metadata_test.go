@@ -97,6 +97,41 @@ func TestMetadataAsTags(t *testing.T) {
 			metadataAsTags: map[string]string{"*": "%%env%%"},
 			want:           []string{"foo:bar"},
 		},
+		{
+			name:           "go template tag name",
+			k:              "app.kubernetes.io/Name",
+			v:              "bar",
+			metadataAsTags: map[string]string{"app.kubernetes.io/*": `{{ .Name | trimPrefix "app.kubernetes.io/" | lower }}`},
+			want:           []string{"name:bar"},
+		},
+		{
+			name:           "go template tag name and value",
+			k:              "team",
+			v:              "Team-Payments",
+			metadataAsTags: map[string]string{"team": `team:{{ .Value | lower | trimPrefix "team-" }}`},
+			want:           []string{"team:payments"},
+		},
+		{
+			name:           "go template with replace",
+			k:              "example.com/cost-center",
+			v:              "a.b",
+			metadataAsTags: map[string]string{"example.com/*": `{{ .Name | trimPrefix "example.com/" | replace "-" "_" }}:{{ .Value | upper }}`},
+			want:           []string{"cost_center:A.B"},
+		},
+		{
+			name:           "go template with empty value",
+			k:              "foo",
+			v:              "bar",
+			metadataAsTags: map[string]string{"foo": `foo:{{ .Value | trimPrefix "bar" }}`},
+			want:           []string{},
+		},
+		{
+			name:           "invalid go template",
+			k:              "foo",
+			v:              "bar",
+			metadataAsTags: map[string]string{"foo": "{{ .Name | unknown }}"},
+			want:           []string{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -109,6 +144,15 @@ func TestMetadataAsTags(t *testing.T) {
 	}
 }
 
+func TestMetadataAsTagsHighCardinalityTemplate(t *testing.T) {
+	tagList := NewTagList()
+	m, g := InitMetadataAsTags(map[string]string{"*": `+{{ .Name | lower }}`})
+	AddMetadataAsTags("Foo", "bar", m, g, tagList)
+	low, _, high, _ := tagList.Compute()
+	assert.Empty(t, low)
+	assert.ElementsMatch(t, []string{"foo:bar"}, high)
+}
+
 func TestResolveTag(t *testing.T) {
 	testCases := []struct {
 		tmpl, label, expected string
@@ -0,0 +1,185 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package telemetry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// OpenMetricsPrefix prefixes the name of the families exposed by the OpenMetrics endpoint
+const OpenMetricsPrefix = "datadog_agent_"
+
+// StableFamilies maps the internal name of the telemetry families exposed by the OpenMetrics
+// endpoint to their stable name, without OpenMetricsPrefix. The stable names are a contract with
+// the scrapers of the endpoint, documented in docs/agent/telemetry.md: when an internal metric
+// is renamed, its entry is updated to keep its stable name.
+var StableFamilies = map[string]string{
+	// aggregator
+	"aggregator__flush":              "aggregator_flush",
+	"aggregator__processed":          "aggregator_processed",
+	"aggregator__dogstatsd_contexts": "aggregator_dogstatsd_contexts",
+	"aggregator__hostname_update":    "aggregator_hostname_update",
+	"aggregator__tag_cardinality":    "aggregator_tag_cardinality",
+
+	// forwarder
+	"transactions__input_count":      "forwarder_transactions_input_count",
+	"transactions__input_bytes":      "forwarder_transactions_input_bytes",
+	"transactions__success":          "forwarder_transactions_success",
+	"transactions__success_bytes":    "forwarder_transactions_success_bytes",
+	"transactions__errors":           "forwarder_transactions_errors",
+	"transactions__http_errors":      "forwarder_transactions_http_errors",
+	"transactions__dropped":          "forwarder_transactions_dropped",
+	"transactions__requeued":         "forwarder_transactions_requeued",
+	"transactions__retries":          "forwarder_transactions_retries",
+	"transactions__retry_queue_size": "forwarder_transactions_retry_queue_size",
+
+	// dogstatsd
+	"dogstatsd__processed":            "dogstatsd_processed",
+	"dogstatsd__udp_packets":          "dogstatsd_udp_packets",
+	"dogstatsd__udp_packets_bytes":    "dogstatsd_udp_packets_bytes",
+	"dogstatsd__uds_packets":          "dogstatsd_uds_packets",
+	"dogstatsd__uds_packets_bytes":    "dogstatsd_uds_packets_bytes",
+	"dogstatsd__packets_channel_size": "dogstatsd_packets_channel_size",
+
+	// logs
+	"logs__decoded":            "logs_decoded",
+	"logs__processed":          "logs_processed",
+	"logs__sent":               "logs_sent",
+	"logs__dropped":            "logs_dropped",
+	"logs__rate_limited":       "logs_rate_limited",
+	"logs__network_errors":     "logs_network_errors",
+	"logs__bytes_sent":         "logs_bytes_sent",
+	"logs__encoded_bytes_sent": "logs_encoded_bytes_sent",
+	"logs__sender_latency":     "logs_sender_latency",
+}
+
+// stableGatherer gathers the families of StableFamilies from the telemetry registry, renamed
+// to their stable name, along with the Go runtime and process families
+type stableGatherer struct{}
+
+var _ prometheus.Gatherer = stableGatherer{}
+
+func (stableGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := telemetryRegistry.Gather()
+	stable := make([]*dto.MetricFamily, 0, len(families))
+	for _, family := range families {
+		name := family.GetName()
+		if strings.HasPrefix(name, "go_") || strings.HasPrefix(name, "process_") {
+			stable = append(stable, family)
+			continue
+		}
+		stableName, ok := StableFamilies[name]
+		if !ok {
+			continue
+		}
+		family.Name = proto.String(OpenMetricsPrefix + stableName)
+		stable = append(stable, family)
+	}
+	sort.Slice(stable, func(i, j int) bool { return stable[i].GetName() < stable[j].GetName() })
+	return stable, err
+}
+
+// OpenMetricsHandler serves the stable telemetry families, in the OpenMetrics format when the
+// scraper accepts it and in the Prometheus text format otherwise.
+func OpenMetricsHandler() http.Handler {
+	return promhttp.HandlerFor(stableGatherer{}, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// OpenMetricsServerConfig configures the OpenMetrics telemetry server
+type OpenMetricsServerConfig struct {
+	// Addr is the address the server listens on
+	Addr string
+	// CertFile and KeyFile enable TLS, both must be set
+	CertFile string
+	KeyFile  string
+	// ClientCAFile, when set, requires the clients to present a certificate signed by one of its CAs
+	ClientCAFile string
+}
+
+// NewOpenMetricsServer returns the server exposing OpenMetricsHandler on /telemetry
+func NewOpenMetricsServer(cfg OpenMetricsServerConfig) (*http.Server, error) {
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return nil, fmt.Errorf("both the certificate and the key files must be set to enable TLS")
+	}
+	if cfg.ClientCAFile != "" && cfg.CertFile == "" {
+		return nil, fmt.Errorf("client certificate authentication requires TLS, the certificate and key files must be set")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/telemetry", OpenMetricsHandler())
+	server := &http.Server{
+		Addr:         cfg.Addr,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	if cfg.CertFile == "" {
+		return server, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the telemetry server certificate: %v", err)
+	}
+	server.TLSConfig = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if cfg.ClientCAFile != "" {
+		caPEM, err := ioutil.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the telemetry client CA file: %v", err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no valid certificate found in the telemetry client CA file %s", cfg.ClientCAFile)
+		}
+		server.TLSConfig.ClientCAs = clientCAs
+		server.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return server, nil
+}
+
+// StartOpenMetricsServer starts the OpenMetrics telemetry server in the background. The listener
+// is created synchronously so that an unavailable port is reported to the caller.
+func StartOpenMetricsServer(cfg OpenMetricsServerConfig) (*http.Server, error) {
+	server, err := NewOpenMetricsServer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen on %s: %v", cfg.Addr, err)
+	}
+
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			log.Errorf("Error serving the OpenMetrics telemetry on %s: %v", cfg.Addr, err)
+		}
+	}()
+	return server, nil
+}
@@ -0,0 +1,76 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package telemetry
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStableGatherer(t *testing.T) {
+	// Reset telemetry registry data
+	Reset()
+	defer Reset()
+
+	NewCounter("aggregator", "flush", []string{"data_type", "state"}, "help docs").Inc("series", "ok")
+	NewGauge("logs", "processed", nil, "help docs").Set(3)
+	NewCounter("subsystem", "internal", nil, "help docs").Inc()
+
+	families, err := stableGatherer{}.Gather()
+	require.NoError(t, err)
+
+	var names []string
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	assert.Equal(t, []string{"datadog_agent_aggregator_flush", "datadog_agent_logs_processed"}, names)
+	assert.Equal(t, float64(1), families[0].GetMetric()[0].GetCounter().GetValue())
+}
+
+func TestOpenMetricsHandler(t *testing.T) {
+	// Reset telemetry registry data
+	Reset()
+	defer Reset()
+
+	NewCounter("transactions", "success", []string{"domain", "endpoint"}, "Successful transaction count").Inc("datadoghq.com", "series_v1")
+
+	req := httptest.NewRequest("GET", "/telemetry", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+	rec := httptest.NewRecorder()
+	OpenMetricsHandler().ServeHTTP(rec, req)
+
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "application/openmetrics-text"))
+	body := rec.Body.String()
+	assert.Contains(t, body, `datadog_agent_forwarder_transactions_success_total{domain="datadoghq.com",endpoint="series_v1"} 1`)
+	assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+}
+
+func TestNewOpenMetricsServer(t *testing.T) {
+	server, err := NewOpenMetricsServer(OpenMetricsServerConfig{Addr: "localhost:0"})
+	require.NoError(t, err)
+	assert.Nil(t, server.TLSConfig)
+
+	_, err = NewOpenMetricsServer(OpenMetricsServerConfig{Addr: "localhost:0", CertFile: "cert.pem"})
+	assert.Error(t, err)
+
+	_, err = NewOpenMetricsServer(OpenMetricsServerConfig{Addr: "localhost:0", ClientCAFile: "ca.pem"})
+	assert.Error(t, err)
+
+	dir, err := ioutil.TempDir("", "telemetry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "cert.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, []byte("not a certificate"), 0600))
+	_, err = NewOpenMetricsServer(OpenMetricsServerConfig{Addr: "localhost:0", CertFile: certFile, KeyFile: certFile})
+	assert.Error(t, err)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/trace/osutil"
 	"github.com/DataDog/datadog-agent/pkg/trace/watchdog"
 	"github.com/DataDog/datadog-agent/pkg/util"
+	"github.com/DataDog/datadog-agent/pkg/util/fips"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/profiling"
 	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
@@ -65,6 +66,9 @@ func Run(ctx context.Context) {
 		}
 		osutil.Exitf("%v", err)
 	}
+	if err := fips.Verify(); err != nil {
+		osutil.Exitf("Invalid FIPS configuration: %v", err)
+	}
 	err = info.InitInfo(cfg) // for expvar & -info option
 	if err != nil {
 		osutil.Exitf("%v", err)
@@ -348,6 +348,14 @@ func convertSpan(rattr map[string]string, lib *otlppb.InstrumentationLibrary, in
 		case *otlppb.AnyValue_IntValue:
 			span.Metrics[kv.Key] = float64(v.IntValue)
 		default:
+			if kv.Key == sampler.KeySamplingRateGlobal {
+				// the sample rate weighs the stats of the trace, it must be a metric
+				// even when the sender converted it to a string
+				if rate, err := strconv.ParseFloat(anyValueString(kv.Value), 64); err == nil {
+					span.Metrics[kv.Key] = rate
+					continue
+				}
+			}
 			span.Meta[kv.Key] = anyValueString(kv.Value)
 		}
 	}
@@ -678,6 +678,35 @@ func TestOTLPConvertSpan(t *testing.T) {
 	}
 }
 
+func TestOTLPConvertSpanSampleRate(t *testing.T) {
+	lib := &otlppb.InstrumentationLibrary{}
+	for _, tt := range []struct {
+		value *otlppb.AnyValue
+		rate  float64
+		meta  string
+	}{
+		{value: &otlppb.AnyValue{Value: &otlppb.AnyValue_DoubleValue{DoubleValue: 0.25}}, rate: 0.25},
+		{value: &otlppb.AnyValue{Value: &otlppb.AnyValue_StringValue{StringValue: "0.25"}}, rate: 0.25},
+		{value: &otlppb.AnyValue{Value: &otlppb.AnyValue_StringValue{StringValue: "invalid"}}, meta: "invalid"},
+	} {
+		in := &otlppb.Span{
+			TraceId: otlpTestID128,
+			SpanId:  otlpTestID128,
+			Attributes: []*otlppb.KeyValue{
+				{Key: "_sample_rate", Value: tt.value},
+			},
+		}
+		span := convertSpan(map[string]string{}, lib, in)
+		if tt.meta != "" {
+			assert.Equal(t, tt.meta, span.Meta["_sample_rate"])
+			assert.NotContains(t, span.Metrics, "_sample_rate")
+			continue
+		}
+		assert.Equal(t, tt.rate, span.Metrics["_sample_rate"])
+		assert.NotContains(t, span.Meta, "_sample_rate")
+	}
+}
+
 func TestMarshalEvents(t *testing.T) {
 	for _, tt := range []struct {
 		in  []*otlppb.Span_Event
@@ -20,10 +20,10 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
-	coreconfig "github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/proto/pbgo"
 	"github.com/DataDog/datadog-agent/pkg/trace/config/features"
 	"github.com/DataDog/datadog-agent/pkg/util/fargate"
+	"github.com/DataDog/datadog-agent/pkg/util/fips"
 	"github.com/DataDog/datadog-agent/pkg/util/grpc"
 	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -289,8 +289,10 @@ func (c *AgentConfig) NewHTTPClient() *http.Client {
 // NewHTTPTransport returns a new http.Transport to be used for outgoing connections to
 // the Datadog API.
 func (c *AgentConfig) NewHTTPTransport() *http.Transport {
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.SkipSSLValidation}
+	fips.ConfigureTLS(tlsConfig)
 	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{InsecureSkipVerify: c.SkipSSLValidation},
+		TLSClientConfig: tlsConfig,
 		// below field values are from http.DefaultTransport (go1.12)
 		Proxy: http.ProxyFromEnvironment,
 		DialContext: (&net.Dialer{
@@ -303,9 +305,10 @@ func (c *AgentConfig) NewHTTPTransport() *http.Transport {
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 	}
-	if p := coreconfig.GetProxies(); p != nil {
-		transport.Proxy = httputils.GetProxyTransportFunc(p)
+	if proxyFunc := httputils.GetAgentProxyTransportFunc(); proxyFunc != nil {
+		transport.Proxy = proxyFunc
 	}
+	httputils.GetEgressValidator().Apply(transport)
 	return transport
 }
 
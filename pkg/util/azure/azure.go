@@ -38,7 +38,8 @@ func IsRunningOn(ctx context.Context) bool {
 }
 
 var vmIDFetcher = cachedfetch.Fetcher{
-	Name: "Azure vmID",
+	Name:    "Azure vmID",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		res, err := getResponseWithMaxLength(ctx,
 			metadataURL+"/metadata/instance/compute/vmId?api-version=2017-04-02&format=text",
@@ -56,7 +57,8 @@ func GetHostAlias(ctx context.Context) (string, error) {
 }
 
 var resourceGroupNameFetcher = cachedfetch.Fetcher{
-	Name: "Azure Cluster Name",
+	Name:    "Azure Cluster Name",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		rg, err := getResponse(ctx,
 			metadataURL+"/metadata/instance/compute/resourceGroupName?api-version=2017-08-01&format=text")
@@ -118,7 +120,8 @@ func GetHostname(ctx context.Context, options map[string]interface{}) (string, e
 }
 
 var instanceMetaFetcher = cachedfetch.Fetcher{
-	Name: "Azure Instance Metadata",
+	Name:    "Azure Instance Metadata",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		metadataJSON, err := getResponse(ctx,
 			metadataURL+"/metadata/instance/compute?api-version=2017-08-01")
@@ -4,10 +4,20 @@ package cachedfetch
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/util/backoff"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+// DefaultBackoffPolicy is the backoff policy of the fetchers of cloud metadata
+// endpoints.  After a first failure, the endpoint is queried again after 1 to 2
+// seconds, then exponentially less often, up to every 5 minutes.
+var DefaultBackoffPolicy = backoff.NewPolicy(2, 1, 300, 0, true)
+
+// now is overridden in tests
+var now = time.Now
+
 // Fetcher supports fetching a value, such as from a cloud service API.  An
 // attempt is made to fetch the value on each call to Fetch, but if that
 // attempt fails then a cached value from the last successful attempt is
@@ -15,6 +25,12 @@ import (
 // temporary failures in cloud APIs while still fetching fresh data when those
 // APIs are functioning properly.  Cached values do not expire.
 //
+// When Backoff is set, the failures are cached as well until a first attempt
+// succeeds: the error is returned without a new attempt until the jittered,
+// exponential backoff duration of the consecutive failures has elapsed.  This
+// keeps hosts that are not running on a cloud provider from querying its API on
+// every call.
+//
 // Callers should instantiate one fetcher per piece of data required.
 type Fetcher struct {
 	// function that attempts to fetch the value
@@ -30,31 +46,58 @@ type Fetcher struct {
 	// Name.
 	LogFailure func(error, interface{})
 
+	// backoff policy applied to the failures while no attempt has succeeded.
+	// If left nil, an attempt is made on each call.
+	Backoff *backoff.Policy
+
 	// previous successfully fetched value
 	lastValue interface{}
 
-	// mutex to protect access to lastValue
+	// last error, number of consecutive errors and time before which no attempt
+	// is made, while no attempt has succeeded
+	lastErr    error
+	numErrors  int
+	retryAfter time.Time
+
+	// mutex to protect access to lastValue and the backoff state
 	sync.Mutex
 }
 
 // Fetch attempts to fetch the value, returning the result or the last successful
 // value, or an error if no attempt has ever been successful.  No special handling
 // is included for the Context: both context.Cancelled and context.DeadlineExceeded
-// are handled like any other error by returning the cached value.
+// are handled like any other error by returning the cached value, but they are
+// not counted as failures by the backoff.
 //
 // This can be called from multiple goroutines, in which case it will call Attempt
 // concurrently.
 func (f *Fetcher) Fetch(ctx context.Context) (interface{}, error) {
+	f.Lock()
+	if f.Backoff != nil && f.lastValue == nil && f.lastErr != nil && now().Before(f.retryAfter) {
+		// attempt was never successful and failed recently
+		err := f.lastErr
+		f.Unlock()
+		return nil, err
+	}
+	f.Unlock()
+
 	value, err := f.Attempt(ctx)
 	if err == nil {
 		f.Lock()
 		f.lastValue = value
+		f.lastErr = nil
+		f.numErrors = 0
 		f.Unlock()
 		return value, nil
 	}
 
 	f.Lock()
 	lastValue := f.lastValue
+	if f.Backoff != nil && lastValue == nil && ctx.Err() == nil {
+		f.lastErr = err
+		f.numErrors = f.Backoff.IncError(f.numErrors)
+		f.retryAfter = now().Add(f.Backoff.GetBackoffDuration(f.numErrors))
+	}
 	f.Unlock()
 
 	if lastValue == nil {
@@ -80,9 +123,12 @@ func (f *Fetcher) FetchString(ctx context.Context) (string, error) {
 	return v.(string), nil
 }
 
-// Reset resets the cached value (used for testing)
+// Reset resets the cached value and error (used for testing)
 func (f *Fetcher) Reset() {
 	f.Lock()
 	f.lastValue = nil
+	f.lastErr = nil
+	f.numErrors = 0
+	f.retryAfter = time.Time{}
 	f.Unlock()
 }
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/backoff"
 )
 
 // If Attempt never succeeds, f.Fetch returns an error
@@ -130,3 +133,66 @@ func TestReset(t *testing.T) {
 	require.Equal(t, "", v)
 	require.Error(t, err)
 }
+
+func TestFetcherBackoff(t *testing.T) {
+	defer func() { now = time.Now }()
+	ts := time.Now()
+	now = func() time.Time { return ts }
+
+	count := 0
+	fail := func(ctx context.Context) (interface{}, error) {
+		count++
+		return nil, fmt.Errorf("uhoh %d", count)
+	}
+	policy := backoff.NewPolicy(2, 1, 8, 0, true)
+	f := Fetcher{Backoff: &policy, Attempt: fail}
+
+	// the failures are cached during the backoff
+	for i := 0; i < 3; i++ {
+		v, err := f.Fetch(context.TODO())
+		require.Nil(t, v)
+		require.EqualError(t, err, "uhoh 1")
+	}
+	require.Equal(t, 1, count)
+
+	// the backoff grows with the consecutive failures, up to its maximum
+	for i, maxBackoff := range []time.Duration{2, 4, 8, 8, 8} {
+		ts = ts.Add(maxBackoff * time.Second)
+		_, err := f.Fetch(context.TODO())
+		require.EqualError(t, err, fmt.Sprintf("uhoh %d", i+2))
+	}
+
+	// the failures are not cached anymore once an attempt succeeded
+	ts = ts.Add(8 * time.Second)
+	f.Attempt = func(ctx context.Context) (interface{}, error) { return "yay", nil }
+	v, err := f.FetchString(context.TODO())
+	require.Equal(t, "yay", v)
+	require.NoError(t, err)
+
+	f.Attempt = fail
+	for i := 0; i < 2; i++ {
+		v, err = f.FetchString(context.TODO())
+		require.Equal(t, "yay", v)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 8, count)
+}
+
+func TestFetcherBackoffIgnoresCancelledContext(t *testing.T) {
+	count := 0
+	f := Fetcher{
+		Backoff: &DefaultBackoffPolicy,
+		Attempt: func(ctx context.Context) (interface{}, error) {
+			count++
+			return nil, ctx.Err()
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		_, err := f.Fetch(ctx)
+		require.Error(t, err)
+	}
+	require.Equal(t, 3, count)
+}
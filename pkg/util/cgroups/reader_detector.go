@@ -10,6 +10,7 @@ package cgroups
 
 import (
 	"bufio"
+	"errors"
 	"os"
 	"path"
 	"path/filepath"
@@ -78,3 +79,21 @@ func isCgroup2(cgroupMountPoints map[string]string) bool {
 
 	return false
 }
+
+// DetectLayout returns the version of the cgroup hierarchy used by the host, "v1" or "v2",
+// based on the cgroup mounts listed in `procFsPath`/mounts
+func DetectLayout(hostPrefix, procFsPath string) (string, error) {
+	cgroupMounts, err := discoverCgroupMountPoints(hostPrefix, procFsPath)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case isCgroup1(cgroupMounts):
+		return "v1", nil
+	case isCgroup2(cgroupMounts):
+		return "v2", nil
+	default:
+		return "", errors.New("no cgroup mount point found")
+	}
+}
@@ -0,0 +1,60 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+// +build linux
+
+package cgroups
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLayout(t *testing.T) {
+	tests := []struct {
+		name           string
+		mounts         string
+		expectedLayout string
+		expectedErr    bool
+	}{
+		{
+			name: "cgroup v1",
+			mounts: `cgroup /sys/fs/cgroup/cpu,cpuacct cgroup rw,nosuid,nodev,noexec,relatime,cpu,cpuacct 0 0
+cgroup /sys/fs/cgroup/memory cgroup rw,nosuid,nodev,noexec,relatime,memory 0 0
+`,
+			expectedLayout: "v1",
+		},
+		{
+			name:           "cgroup v2",
+			mounts:         "cgroup2 /sys/fs/cgroup cgroup2 rw,nosuid,nodev,noexec,relatime 0 0\n",
+			expectedLayout: "v2",
+		},
+		{
+			name:        "no cgroup",
+			mounts:      "proc /proc proc rw,nosuid,nodev,noexec,relatime 0 0\n",
+			expectedErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			procPath := t.TempDir()
+			require.NoError(t, ioutil.WriteFile(filepath.Join(procPath, "mounts"), []byte(test.mounts), 0644))
+
+			layout, err := DetectLayout("", procPath)
+			if test.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedLayout, layout)
+		})
+	}
+}
@@ -15,7 +15,9 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/ecs"
 	ecscommon "github.com/DataDog/datadog-agent/pkg/util/ecs/common"
 	"github.com/DataDog/datadog-agent/pkg/util/gce"
+	"github.com/DataDog/datadog-agent/pkg/util/ibm"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/oracle"
 	"github.com/DataDog/datadog-agent/pkg/util/tencent"
 )
 
@@ -36,6 +38,8 @@ type cloudProviderNTPDetector struct {
 // * Azure
 // * Alibaba
 // * Tencent
+// * Oracle
+// * IBM
 func DetectCloudProvider(ctx context.Context) {
 	detectors := []cloudProviderDetector{
 		{name: ecscommon.CloudProviderName, callback: ecs.IsRunningOn},
@@ -44,6 +48,8 @@ func DetectCloudProvider(ctx context.Context) {
 		{name: azure.CloudProviderName, callback: azure.IsRunningOn},
 		{name: alibaba.CloudProviderName, callback: alibaba.IsRunningOn},
 		{name: tencent.CloudProviderName, callback: tencent.IsRunningOn},
+		{name: oracle.CloudProviderName, callback: oracle.IsRunningOn},
+		{name: ibm.CloudProviderName, callback: ibm.IsRunningOn},
 	}
 
 	for _, cloudDetector := range detectors {
@@ -65,6 +71,8 @@ func GetCloudProviderNTPHosts(ctx context.Context) []string {
 		{name: azure.CloudProviderName, callback: azure.GetNTPHosts},
 		{name: alibaba.CloudProviderName, callback: alibaba.GetNTPHosts},
 		{name: tencent.CloudProviderName, callback: tencent.GetNTPHosts},
+		{name: oracle.CloudProviderName, callback: oracle.GetNTPHosts},
+		{name: ibm.CloudProviderName, callback: ibm.GetNTPHosts},
 	}
 
 	for _, cloudNTPDetector := range detectors {
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package clusteragent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+)
+
+const dcaCheckTemplatesPath = "api/v1/checktemplates"
+
+// GetCheckTemplates is called by the check templates config provider. version pins
+// the version of the templates, the latest version is returned when it's empty.
+// When etag is the ETag of the templates, they are not sent again and
+// the returned boolean is false.
+func (c *DCAClient) GetCheckTemplates(ctx context.Context, version, etag string) (types.CheckTemplatesResponse, string, bool, error) {
+	var templates types.CheckTemplatesResponse
+
+	// https://host:port/api/v1/checktemplates?version={version}
+	rawURL := fmt.Sprintf("%s/%s", c.clusterAgentAPIEndpoint, dcaCheckTemplatesPath)
+	if version != "" {
+		rawURL += "?" + url.Values{"version": []string{version}}.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return templates, "", false, err
+	}
+	req.Header = c.clusterAgentAPIRequestHeaders.Clone()
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := c.clusterAgentAPIClient.Do(req)
+	if err != nil {
+		return templates, "", false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return templates, etag, false, nil
+	default:
+		return templates, "", false, fmt.Errorf("unexpected response: %d - %s", resp.StatusCode, resp.Status)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return templates, "", false, err
+	}
+	err = json.Unmarshal(b, &templates)
+	return templates, resp.Header.Get("ETag"), err == nil, err
+}
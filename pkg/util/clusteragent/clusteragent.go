@@ -49,6 +49,7 @@ type DCAClientInterface interface {
 
 	GetVersion() (version.Version, error)
 	GetNodeLabels(nodeName string) (map[string]string, error)
+	GetNodeTaints(nodeName string) ([]string, error)
 	GetNamespaceLabels(nsName string) (map[string]string, error)
 	GetPodsMetadataForNode(nodeName string) (apiv1.NamespacesPodsStringsSet, error)
 	GetKubernetesMetadataNames(nodeName, ns, podName string) ([]string, error)
@@ -57,6 +58,8 @@ type DCAClientInterface interface {
 	PostClusterCheckStatus(ctx context.Context, nodeName string, status types.NodeStatus) (types.StatusResponse, error)
 	GetClusterCheckConfigs(ctx context.Context, nodeName string) (types.ConfigResponse, error)
 	GetEndpointsCheckConfigs(ctx context.Context, nodeName string) (types.ConfigResponse, error)
+	GetCheckTemplates(ctx context.Context, version, etag string) (types.CheckTemplatesResponse, string, bool, error)
+	LeaseSNMPDevices(ctx context.Context, identifier string, request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error)
 	GetKubernetesClusterID() (string, error)
 }
 
@@ -70,6 +73,7 @@ type DCAClient struct {
 	clusterAgentAPIClient         *http.Client
 	clusterAgentAPIRequestHeaders http.Header
 	leaderClient                  *leaderClient
+	metadataRequester             *metadataRequester
 }
 
 // resetGlobalClusterAgentClient is a helper to remove the current DCAClient global
@@ -119,6 +123,9 @@ func (c *DCAClient) init() error {
 	c.clusterAgentAPIClient = util.GetClient(false)
 	c.clusterAgentAPIClient.Timeout = 2 * time.Second
 
+	// Cache, hedge and shed the tags and metadata requests sent by the node agent checks
+	c.metadataRequester = newMetadataRequester(c.clusterAgentAPIClient, c.clusterAgentAPIRequestHeaders, getMetadataRequesterConfig())
+
 	// Validate the cluster-agent client by checking the version
 	c.ClusterAgentVersion, err = c.GetVersion()
 	if err != nil {
@@ -249,28 +256,21 @@ func (c *DCAClient) GetNodeLabels(nodeName string) (map[string]string, error) {
 	// https://host:port/api/v1/tags/node/{nodeName}
 	rawURL := fmt.Sprintf("%s/%s/%s", c.clusterAgentAPIEndpoint, dcaNodeMeta, nodeName)
 
-	req, err := http.NewRequest("GET", rawURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header = c.clusterAgentAPIRequestHeaders
+	err = c.getMetadata(rawURL, &labels)
+	return labels, err
+}
 
-	resp, err := c.clusterAgentAPIClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// GetNodeTaints returns the node taints from the Cluster Agent, formatted as `key=value:effect`.
+func (c *DCAClient) GetNodeTaints(nodeName string) ([]string, error) {
+	const dcaNodeMeta = "api/v1/tags/node"
+	var err error
+	var taints []string
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
-	}
+	// https://host:port/api/v1/tags/node/{nodeName}/taints
+	rawURL := fmt.Sprintf("%s/%s/%s/taints", c.clusterAgentAPIEndpoint, dcaNodeMeta, nodeName)
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(body, &labels)
-	return labels, err
+	err = c.getMetadata(rawURL, &taints)
+	return taints, err
 }
 
 // GetNamespaceLabels returns the namespace labels from the Cluster Agent.
@@ -282,27 +282,7 @@ func (c *DCAClient) GetNamespaceLabels(nsName string) (map[string]string, error)
 	// https://host:port/api/v1/tags/namespace/{nsName}
 	rawURL := fmt.Sprintf("%s/%s/%s", c.clusterAgentAPIEndpoint, dcaNamespaceMeta, nsName)
 
-	req, err := http.NewRequest("GET", rawURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header = c.clusterAgentAPIRequestHeaders
-
-	resp, err := c.clusterAgentAPIClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(body, &labels)
+	err = c.getMetadata(rawURL, &labels)
 	return labels, err
 }
 
@@ -315,27 +295,7 @@ func (c *DCAClient) GetCFAppsMetadataForNode(nodename string) (map[string][]stri
 	// https://host:port/api/v1/tags/cf/apps/{nodename}
 	rawURL := fmt.Sprintf("%s/%s/%s", c.clusterAgentAPIEndpoint, dcaCFAppsMeta, nodename)
 
-	req, err := http.NewRequest("GET", rawURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header = c.clusterAgentAPIRequestHeaders
-
-	resp, err := c.clusterAgentAPIClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
-	}
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	err = json.Unmarshal(body, &tags)
+	err = c.getMetadata(rawURL, &tags)
 	return tags, err
 }
 
@@ -371,27 +331,8 @@ func (c *DCAClient) GetPodsMetadataForNode(nodeName string) (apiv1.NamespacesPod
 	}
 	*/
 	rawURL := fmt.Sprintf("%s/%s/%s", c.clusterAgentAPIEndpoint, dcaMetadataPath, nodeName)
-	req, err := http.NewRequest("GET", rawURL, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header = c.clusterAgentAPIRequestHeaders
-
-	resp, err := c.clusterAgentAPIClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
-	}
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
 	metadataPodPayload := apiv1.NewMetadataResponse()
-	if err = json.Unmarshal(b, metadataPodPayload); err != nil {
+	if err = c.getMetadata(rawURL, metadataPodPayload); err != nil {
 		return nil, err
 	}
 
@@ -417,32 +358,21 @@ func (c *DCAClient) GetKubernetesMetadataNames(nodeName, ns, podName string) ([]
 
 	// https://host:port/api/v1/metadata/{nodeName}/{ns}/{pod-[0-9a-z]+}
 	rawURL := fmt.Sprintf("%s/%s/%s/%s/%s", c.clusterAgentAPIEndpoint, dcaMetadataPath, nodeName, ns, podName)
-	req, err := http.NewRequest("GET", rawURL, nil)
-	if err != nil {
-		return metadataNames, err
-	}
-	req.Header = c.clusterAgentAPIRequestHeaders
-
-	resp, err := c.clusterAgentAPIClient.Do(req)
+	err = c.getMetadata(rawURL, &metadataNames)
 	if err != nil {
 		return metadataNames, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return metadataNames, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
-	}
+	return metadataNames, nil
+}
 
-	b, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return metadataNames, err
-	}
-	err = json.Unmarshal(b, &metadataNames)
+// getMetadata unmarshals the response of the cluster agent to a tags or metadata request
+func (c *DCAClient) getMetadata(rawURL string, v interface{}) error {
+	body, err := c.metadataRequester.get(rawURL)
 	if err != nil {
-		return metadataNames, err
+		return err
 	}
-
-	return metadataNames, nil
+	return json.Unmarshal(body, v)
 }
 
 // GetKubernetesClusterID queries the datadog cluster agent to get the Kubernetes cluster ID
@@ -55,6 +55,9 @@ func newDummyClusterAgent() (*dummyClusterAgent, error) {
 				"label2": "value4",
 			},
 		},
+		rawResponses: map[string]string{
+			"/api/v1/tags/node/node1/taints": `["dedicated=gpu:NoSchedule","node.kubernetes.io/unschedulable:NoSchedule"]`,
+		},
 		responses: map[string][]string{
 			"pod/node1/foo/pod-00001": {"kube_service:svc1"},
 			"pod/node1/foo/pod-00002": {"kube_service:svc1", "kube_service:svc2"},
@@ -449,6 +452,27 @@ func (suite *clusterAgentSuite) TestGetKubernetesNodeLabels() {
 	}
 }
 
+func (suite *clusterAgentSuite) TestGetKubernetesNodeTaints() {
+	dca, err := newDummyClusterAgent()
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+
+	ts, p, err := dca.StartTLS()
+	defer ts.Close()
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+
+	mockConfig.Set("cluster_agent.url", fmt.Sprintf("https://127.0.0.1:%d", p))
+
+	ca, err := GetClusterAgentClient()
+	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
+
+	taints, err := ca.GetNodeTaints("node1")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"dedicated=gpu:NoSchedule", "node.kubernetes.io/unschedulable:NoSchedule"}, taints)
+
+	_, err = ca.GetNodeTaints("fake")
+	assert.Equal(suite.T(), fmt.Errorf("unexpected status code from cluster agent: 404"), err)
+}
+
 func (suite *clusterAgentSuite) TestGetKubernetesMetadataNames() {
 	dca, err := newDummyClusterAgent()
 	require.Nil(suite.T(), err, fmt.Sprintf("%v", err))
@@ -0,0 +1,277 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package clusteragent
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// metadataCacheMaxStaleness is how long the expired responses are still served while the
+	// requests are shed or fail
+	metadataCacheMaxStaleness = 5 * time.Minute
+	// metadataCachePruneInterval is the minimum interval between two prunings of the responses
+	// older than metadataCacheMaxStaleness
+	metadataCachePruneInterval = time.Minute
+	// metadataLatencyWeight is the weight of the latest request in the moving average of the latency
+	metadataLatencyWeight = 0.2
+)
+
+// errMetadataRequestShed is returned instead of querying the cluster agent while it's too slow to answer
+var errMetadataRequestShed = errors.New("cluster agent request shed, the cluster agent is too slow to answer")
+
+// metadataRequesterConfig is the configuration of the metadataRequester, a zero duration disables
+// the corresponding feature
+type metadataRequesterConfig struct {
+	cacheTTL         time.Duration
+	hedgingDelay     time.Duration
+	latencyThreshold time.Duration
+	sheddingCooldown time.Duration
+}
+
+func getMetadataRequesterConfig() metadataRequesterConfig {
+	return metadataRequesterConfig{
+		cacheTTL:         time.Duration(config.Datadog.GetInt("cluster_agent.client.cache_ttl_seconds")) * time.Second,
+		hedgingDelay:     time.Duration(config.Datadog.GetInt("cluster_agent.client.hedging_delay_ms")) * time.Millisecond,
+		latencyThreshold: time.Duration(config.Datadog.GetInt("cluster_agent.client.load_shedding.latency_threshold_ms")) * time.Millisecond,
+		sheddingCooldown: time.Duration(config.Datadog.GetInt("cluster_agent.client.load_shedding.cooldown_seconds")) * time.Second,
+	}
+}
+
+type metadataCacheEntry struct {
+	body      []byte
+	fetchedAt time.Time
+}
+
+type metadataResult struct {
+	body []byte
+	err  error
+}
+
+// metadataRequester sends the tags and metadata requests of the node agent to the cluster agent, so that
+// the checks of the node agent don't block while the cluster agent is slow, e.g. during its rollouts.
+// The responses are cached for cacheTTL. The requests still pending after hedgingDelay are sent again
+// on a new connection, which the Kubernetes service may route to another replica, and the first
+// response is used. The requests are shed for sheddingCooldown once the moving average of the latency
+// exceeds latencyThreshold, the expired responses being served meanwhile.
+type metadataRequester struct {
+	client      *http.Client
+	hedgeClient *http.Client
+	headers     http.Header
+	config      metadataRequesterConfig
+
+	mu         sync.Mutex
+	cache      map[string]metadataCacheEntry
+	lastPrune  time.Time
+	latency    time.Duration
+	shedUntil  time.Time
+	isShedding bool
+}
+
+func newMetadataRequester(client *http.Client, headers http.Header, cfg metadataRequesterConfig) *metadataRequester {
+	// Keep-alives are disabled so that every hedged request is balanced again by the service
+	// TODO remove insecure
+	hedgeTransport := &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		DisableKeepAlives: true,
+	}
+	httputils.GetEgressValidator().Apply(hedgeTransport)
+	hedgeClient := &http.Client{
+		Timeout:   client.Timeout,
+		Transport: hedgeTransport,
+	}
+
+	return &metadataRequester{
+		client:      client,
+		hedgeClient: hedgeClient,
+		headers:     headers,
+		config:      cfg,
+		cache:       make(map[string]metadataCacheEntry),
+	}
+}
+
+// get returns the body of the response of the cluster agent to a GET request of rawURL
+func (r *metadataRequester) get(rawURL string) ([]byte, error) {
+	now := time.Now()
+
+	entry, cached := r.getCached(rawURL)
+	if cached && now.Sub(entry.fetchedAt) < r.config.cacheTTL {
+		return entry.body, nil
+	}
+	cached = cached && now.Sub(entry.fetchedAt) < metadataCacheMaxStaleness
+
+	if r.shedding(now) {
+		if cached {
+			return entry.body, nil
+		}
+		return nil, errMetadataRequestShed
+	}
+
+	body, err := r.fetch(rawURL)
+	r.observeLatency(time.Since(now))
+	if err != nil {
+		if cached {
+			log.Debugf("Serving the cached response of %s, the cluster agent request failed: %v", rawURL, err)
+			return entry.body, nil
+		}
+		return nil, err
+	}
+
+	r.setCached(rawURL, body)
+	return body, nil
+}
+
+// fetch sends the request, and hedges it if it is still pending after the hedging delay
+func (r *metadataRequester) fetch(rawURL string) ([]byte, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// buffered so that the request not used doesn't block once cancelled
+	results := make(chan metadataResult, 2)
+	send := func(client *http.Client) {
+		body, err := r.do(ctx, client, rawURL)
+		results <- metadataResult{body: body, err: err}
+	}
+
+	go send(r.client)
+	pending := 1
+
+	var hedge <-chan time.Time
+	if r.config.hedgingDelay > 0 {
+		timer := time.NewTimer(r.config.hedgingDelay)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	var err error
+	for pending > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			log.Tracef("Hedging the cluster agent request %s, no response after %s", rawURL, r.config.hedgingDelay)
+			go send(r.hedgeClient)
+			pending++
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.body, nil
+			}
+			// the request failing before the hedging delay isn't hedged, it's not slow
+			hedge = nil
+			err = result.err
+		}
+	}
+	return nil, err
+}
+
+func (r *metadataRequester) do(ctx context.Context, client *http.Client, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.headers
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code from cluster agent: %d", resp.StatusCode)
+	}
+
+	return ioutil.ReadAll(resp.Body)
+}
+
+// shedding returns whether the requests are shed, the first request after the cooldown is sent
+// to check whether the cluster agent recovered
+func (r *metadataRequester) shedding(now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.isShedding {
+		return false
+	}
+	if now.Before(r.shedUntil) {
+		return true
+	}
+	r.shedUntil = now.Add(r.config.sheddingCooldown)
+	return false
+}
+
+// observeLatency updates the moving average of the latency of the cluster agent, and starts or stops
+// shedding the requests accordingly
+func (r *metadataRequester) observeLatency(latency time.Duration) {
+	if r.config.latencyThreshold <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.isShedding {
+		// only the requests checking whether the cluster agent recovered are sent while shedding
+		r.latency = latency
+	} else {
+		r.latency = time.Duration(metadataLatencyWeight*float64(latency) + (1-metadataLatencyWeight)*float64(r.latency))
+	}
+
+	switch {
+	case r.latency > r.config.latencyThreshold && !r.isShedding:
+		log.Warnf("The cluster agent latency (%s) exceeds %s, shedding the requests for %s", r.latency, r.config.latencyThreshold, r.config.sheddingCooldown)
+		r.isShedding = true
+		r.shedUntil = time.Now().Add(r.config.sheddingCooldown)
+	case r.latency <= r.config.latencyThreshold && r.isShedding:
+		log.Infof("The cluster agent latency (%s) is back under %s, not shedding the requests anymore", r.latency, r.config.latencyThreshold)
+		r.isShedding = false
+	}
+}
+
+func (r *metadataRequester) getCached(rawURL string) (metadataCacheEntry, bool) {
+	if r.config.cacheTTL <= 0 {
+		return metadataCacheEntry{}, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, found := r.cache[rawURL]
+	return entry, found
+}
+
+func (r *metadataRequester) setCached(rawURL string, body []byte) {
+	if r.config.cacheTTL <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	r.cache[rawURL] = metadataCacheEntry{body: body, fetchedAt: now}
+
+	if now.Sub(r.lastPrune) < metadataCachePruneInterval {
+		return
+	}
+	r.lastPrune = now
+	for key, entry := range r.cache {
+		if now.Sub(entry.fetchedAt) >= metadataCacheMaxStaleness {
+			delete(r.cache, key)
+		}
+	}
+}
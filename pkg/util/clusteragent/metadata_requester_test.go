@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package clusteragent
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetadataRequester(cfg metadataRequesterConfig) *metadataRequester {
+	return newMetadataRequester(&http.Client{Timeout: 5 * time.Second}, http.Header{}, cfg)
+}
+
+func TestMetadataRequesterCache(t *testing.T) {
+	var requests int32
+	var failing int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	r := newTestMetadataRequester(metadataRequesterConfig{cacheTTL: time.Minute})
+	for i := 0; i < 2; i++ {
+		body, err := r.get(ts.URL + "/node1")
+		require.NoError(t, err)
+		assert.Equal(t, "/node1", string(body))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// the error responses aren't cached
+	atomic.StoreInt32(&failing, 1)
+	for i := 0; i < 2; i++ {
+		_, err := r.get(ts.URL + "/node2")
+		assert.EqualError(t, err, "unexpected status code from cluster agent: 503")
+	}
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// the expired responses are served when the request fails
+	r.cache[ts.URL+"/node1"] = metadataCacheEntry{body: []byte("stale"), fetchedAt: time.Now().Add(-2 * time.Minute)}
+	body, err := r.get(ts.URL + "/node1")
+	require.NoError(t, err)
+	assert.Equal(t, "stale", string(body))
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	// unless they're too old
+	r.cache[ts.URL+"/node1"] = metadataCacheEntry{body: []byte("stale"), fetchedAt: time.Now().Add(-metadataCacheMaxStaleness)}
+	_, err = r.get(ts.URL + "/node1")
+	assert.Error(t, err)
+
+	// without cache, every call sends a request
+	atomic.StoreInt32(&failing, 0)
+	r = newTestMetadataRequester(metadataRequesterConfig{})
+	for i := 0; i < 2; i++ {
+		_, err := r.get(ts.URL + "/node1")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(7), atomic.LoadInt32(&requests))
+	assert.Empty(t, r.cache)
+}
+
+func TestMetadataRequesterHedging(t *testing.T) {
+	var requests int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// the first request hangs until it's cancelled
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+		w.Write([]byte("hedged"))
+	}))
+	defer ts.Close()
+
+	r := newTestMetadataRequester(metadataRequesterConfig{hedgingDelay: 10 * time.Millisecond})
+	body, err := r.get(ts.URL + "/node1")
+	require.NoError(t, err)
+	assert.Equal(t, "hedged", string(body))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// the fast requests aren't hedged
+	_, err = r.get(ts.URL + "/node1")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestMetadataRequesterLoadShedding(t *testing.T) {
+	var requests int32
+	var delay int64 = int64(50 * time.Millisecond)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(time.Duration(atomic.LoadInt64(&delay)))
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	r := newTestMetadataRequester(metadataRequesterConfig{
+		cacheTTL:         time.Nanosecond,
+		latencyThreshold: 5 * time.Millisecond,
+		sheddingCooldown: time.Hour,
+	})
+
+	// the slow response starts the shedding
+	_, err := r.get(ts.URL + "/node1")
+	require.NoError(t, err)
+	assert.True(t, r.isShedding)
+
+	// the expired responses are served meanwhile
+	body, err := r.get(ts.URL + "/node1")
+	require.NoError(t, err)
+	assert.Equal(t, "/node1", string(body))
+
+	_, err = r.get(ts.URL + "/node2")
+	assert.Equal(t, errMetadataRequestShed, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+
+	// after the cooldown, a request checks whether the cluster agent recovered
+	atomic.StoreInt64(&delay, 0)
+	r.shedUntil = time.Now()
+	body, err = r.get(ts.URL + "/node2")
+	require.NoError(t, err)
+	assert.Equal(t, "/node2", string(body))
+	assert.False(t, r.isShedding)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,68 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package clusteragent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/clusterchecks/types"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const dcaSNMPLeasesPath = dcaClusterChecksPath + "/snmp/leases"
+
+// LeaseSNMPDevices is called by the SNMP discovery to lease the devices it polls
+func (c *DCAClient) LeaseSNMPDevices(ctx context.Context, identifier string, request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error) {
+	// Retry on the main URL if the leader fails
+	willRetry := c.leaderClient.hasLeader()
+
+	result, err := c.doLeaseSNMPDevices(ctx, identifier, request)
+	if err != nil && willRetry {
+		log.Debugf("Got error on leader, retrying via the service: %s", err)
+		c.leaderClient.resetURL()
+		return c.doLeaseSNMPDevices(ctx, identifier, request)
+	}
+	return result, err
+}
+
+func (c *DCAClient) doLeaseSNMPDevices(ctx context.Context, identifier string, request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error) {
+	var response types.SNMPLeaseResponse
+
+	queryBody, err := json.Marshal(request)
+	if err != nil {
+		return response, err
+	}
+
+	// https://host:port/api/v1/clusterchecks/snmp/leases/{identifier}
+	rawURL := c.leaderClient.buildURL(dcaSNMPLeasesPath, identifier)
+	req, err := http.NewRequestWithContext(ctx, "POST", rawURL, bytes.NewBuffer(queryBody))
+	if err != nil {
+		return response, err
+	}
+	req.Header = c.clusterAgentAPIRequestHeaders
+
+	resp, err := c.leaderClient.Do(req)
+	if err != nil {
+		return response, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return response, fmt.Errorf("unexpected response: %d - %s", resp.StatusCode, resp.Status)
+	}
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return response, err
+	}
+	err = json.Unmarshal(b, &response)
+	return response, err
+}
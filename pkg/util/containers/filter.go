@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
@@ -65,6 +66,8 @@ const (
 	imageFilterPrefix         = `image:`
 	nameFilterPrefix          = `name:`
 	kubeNamespaceFilterPrefix = `kube_namespace:`
+	labelFilterPrefix         = `label:`
+	ageFilterPrefix           = `age`
 )
 
 // Filter holds the state for the container filtering logic
@@ -77,11 +80,70 @@ type Filter struct {
 	NameExcludeList      []*regexp.Regexp
 	NamespaceExcludeList []*regexp.Regexp
 	Errors               map[string]struct{}
+
+	labelIncludeList []labelFilter
+	labelExcludeList []labelFilter
+	ageIncludeList   []ageFilter
+	ageExcludeList   []ageFilter
+}
+
+// ContainerFilterInfo holds the attributes of a container that the filters match
+type ContainerFilterInfo struct {
+	Name      string
+	Image     string
+	Namespace string
+	// Labels are the labels of the pod of the container, or the labels of the container
+	// when it doesn't run in a pod. `label` filters don't match containers without labels.
+	Labels map[string]string
+	// StartedAt is used to compute the age of the container, `age` filters don't match
+	// containers with an unknown start time.
+	StartedAt time.Time
+}
+
+// labelFilter matches the containers having a label, with a value matching a regex if set.
+// It is written `label:<key>` or `label:<key>=<regex>`.
+type labelFilter struct {
+	key   string
+	value *regexp.Regexp
+}
+
+func (f labelFilter) match(labels map[string]string) bool {
+	value, found := labels[f.key]
+	if !found {
+		return false
+	}
+	return f.value == nil || f.value.MatchString(value)
+}
+
+// ageFilter matches the containers younger or older than a duration.
+// It is written `age<DURATION` or `age>DURATION`, for example `age<5m`.
+type ageFilter struct {
+	olderThan bool
+	age       time.Duration
+}
+
+func (f ageFilter) match(startedAt, now time.Time) bool {
+	if startedAt.IsZero() {
+		return false
+	}
+	if f.olderThan {
+		return now.Sub(startedAt) > f.age
+	}
+	return now.Sub(startedAt) < f.age
+}
+
+// filterList holds the filters of an include or exclude list
+type filterList struct {
+	images     []*regexp.Regexp
+	names      []*regexp.Regexp
+	namespaces []*regexp.Regexp
+	labels     []labelFilter
+	ages       []ageFilter
 }
 
 var sharedFilter *Filter
 
-func parseFilters(filters []string) (imageFilters, nameFilters, namespaceFilters []*regexp.Regexp, filterErrs []string, err error) {
+func parseFilters(filters []string) (list filterList, filterErrs []string, err error) {
 	var filterWarnings []string
 	for _, filter := range filters {
 		switch {
@@ -91,32 +153,73 @@ func parseFilters(filters []string) (imageFilters, nameFilters, namespaceFilters
 				filterErrs = append(filterErrs, err.Error())
 				continue
 			}
-			imageFilters = append(imageFilters, r)
+			list.images = append(list.images, r)
 		case strings.HasPrefix(filter, nameFilterPrefix):
 			r, err := filterToRegex(filter, nameFilterPrefix)
 			if err != nil {
 				filterErrs = append(filterErrs, err.Error())
 				continue
 			}
-			nameFilters = append(nameFilters, r)
+			list.names = append(list.names, r)
 		case strings.HasPrefix(filter, kubeNamespaceFilterPrefix):
 			r, err := filterToRegex(filter, kubeNamespaceFilterPrefix)
 			if err != nil {
 				filterErrs = append(filterErrs, err.Error())
 				continue
 			}
-			namespaceFilters = append(namespaceFilters, r)
+			list.namespaces = append(list.namespaces, r)
+		case strings.HasPrefix(filter, labelFilterPrefix):
+			f, err := parseLabelFilter(filter)
+			if err != nil {
+				filterErrs = append(filterErrs, err.Error())
+				continue
+			}
+			list.labels = append(list.labels, f)
+		case strings.HasPrefix(filter, ageFilterPrefix+"<"), strings.HasPrefix(filter, ageFilterPrefix+">"):
+			f, err := parseAgeFilter(filter)
+			if err != nil {
+				filterErrs = append(filterErrs, err.Error())
+				continue
+			}
+			list.ages = append(list.ages, f)
 		default:
-			warnmsg := fmt.Sprintf("Container filter %q is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'", filter)
+			warnmsg := fmt.Sprintf("Container filter %q is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'", filter)
 			log.Warnf(warnmsg)
 			filterWarnings = append(filterWarnings, warnmsg)
 
 		}
 	}
 	if len(filterErrs) > 0 {
-		return nil, nil, nil, append(filterErrs, filterWarnings...), errors.New(filterErrs[0])
+		return filterList{}, append(filterErrs, filterWarnings...), errors.New(filterErrs[0])
+	}
+	return list, filterWarnings, nil
+}
+
+// parseLabelFilter parses a `label:<key>` or `label:<key>=<regex>` filter
+func parseLabelFilter(filter string) (labelFilter, error) {
+	parts := strings.SplitN(strings.TrimPrefix(filter, labelFilterPrefix), "=", 2)
+	f := labelFilter{key: parts[0]}
+	if f.key == "" {
+		return labelFilter{}, fmt.Errorf("invalid label filter '%s': the label key is empty", filter)
+	}
+	if len(parts) == 2 {
+		r, err := regexp.Compile(parts[1])
+		if err != nil {
+			return labelFilter{}, fmt.Errorf("invalid regex '%s': %s", parts[1], err)
+		}
+		f.value = r
 	}
-	return imageFilters, nameFilters, namespaceFilters, filterWarnings, nil
+	return f, nil
+}
+
+// parseAgeFilter parses an `age<DURATION` or `age>DURATION` filter
+func parseAgeFilter(filter string) (ageFilter, error) {
+	expr := strings.TrimPrefix(filter, ageFilterPrefix)
+	age, err := time.ParseDuration(strings.TrimSpace(expr[1:]))
+	if err != nil {
+		return ageFilter{}, fmt.Errorf("invalid age filter '%s': %s", filter, err)
+	}
+	return ageFilter{olderThan: expr[0] == '>', age: age}, nil
 }
 
 // filterToRegex checks a filter's regex
@@ -188,11 +291,13 @@ func GetFilterErrors() map[string]struct{} {
 
 // NewFilter creates a new container filter from a two slices of
 // regexp patterns for a include list and exclude list. Each pattern should have
-// the following format: "field:pattern" where field can be: [image, name, kube_namespace].
+// the following format: "field:pattern" where field can be: [image, name, kube_namespace],
+// or "label:key=pattern" to match the value of a label. Containers can also be matched
+// on their age with "age<duration" or "age>duration", for example "age<5m".
 // An error is returned if any of the expression don't compile.
 func NewFilter(includeList, excludeList []string) (*Filter, error) {
-	imgIncl, nameIncl, nsIncl, filterErrsIncl, errIncl := parseFilters(includeList)
-	imgExcl, nameExcl, nsExcl, filterErrsExcl, errExcl := parseFilters(excludeList)
+	incl, filterErrsIncl, errIncl := parseFilters(includeList)
+	excl, filterErrsExcl, errExcl := parseFilters(excludeList)
 
 	errors := append(filterErrsIncl, filterErrsExcl...)
 	errorsMap := make(map[string]struct{})
@@ -211,13 +316,17 @@ func NewFilter(includeList, excludeList []string) (*Filter, error) {
 
 	return &Filter{
 		Enabled:              len(includeList) > 0 || len(excludeList) > 0,
-		ImageIncludeList:     imgIncl,
-		NameIncludeList:      nameIncl,
-		NamespaceIncludeList: nsIncl,
-		ImageExcludeList:     imgExcl,
-		NameExcludeList:      nameExcl,
-		NamespaceExcludeList: nsExcl,
+		ImageIncludeList:     incl.images,
+		NameIncludeList:      incl.names,
+		NamespaceIncludeList: incl.namespaces,
+		ImageExcludeList:     excl.images,
+		NameExcludeList:      excl.names,
+		NamespaceExcludeList: excl.namespaces,
 		Errors:               errorsMap,
+		labelIncludeList:     incl.labels,
+		labelExcludeList:     excl.labels,
+		ageIncludeList:       incl.ages,
+		ageExcludeList:       excl.ages,
 	}, nil
 }
 
@@ -293,10 +402,24 @@ func NewAutodiscoveryFilter(filter FilterType) (*Filter, error) {
 
 // IsExcluded returns a bool indicating if the container should be excluded
 // based on the filters in the containerFilter instance.
+// The label and age filters don't match, use IsContainerExcluded to apply them.
+// It is only used by the callers that don't know the ID of the container.
 func (cf Filter) IsExcluded(containerName, containerImage, podNamespace string) bool {
+	return cf.IsContainerExcluded(ContainerFilterInfo{
+		Name:      containerName,
+		Image:     containerImage,
+		Namespace: podNamespace,
+	})
+}
+
+// IsContainerExcluded returns a bool indicating if the container should be excluded
+// based on the filters in the containerFilter instance.
+func (cf Filter) IsContainerExcluded(info ContainerFilterInfo) bool {
 	if !cf.Enabled {
 		return false
 	}
+	containerName, containerImage, podNamespace := info.Name, info.Image, info.Namespace
+	now := time.Now()
 
 	// Any includeListed take precedence on excluded
 	for _, r := range cf.ImageIncludeList {
@@ -314,6 +437,16 @@ func (cf Filter) IsExcluded(containerName, containerImage, podNamespace string)
 			return false
 		}
 	}
+	for _, f := range cf.labelIncludeList {
+		if f.match(info.Labels) {
+			return false
+		}
+	}
+	for _, f := range cf.ageIncludeList {
+		if f.match(info.StartedAt, now) {
+			return false
+		}
+	}
 
 	// Check if excludeListed
 	for _, r := range cf.ImageExcludeList {
@@ -331,6 +464,47 @@ func (cf Filter) IsExcluded(containerName, containerImage, podNamespace string)
 			return true
 		}
 	}
+	for _, f := range cf.labelExcludeList {
+		if f.match(info.Labels) {
+			return true
+		}
+	}
+	for _, f := range cf.ageExcludeList {
+		if f.match(info.StartedAt, now) {
+			return true
+		}
+	}
 
 	return false
 }
+
+// HasLabelOrAgeFilters returns whether the filter has label or age filters, which
+// need the labels or the start time of the containers to match.
+func (cf Filter) HasLabelOrAgeFilters() bool {
+	return cf.Enabled && (len(cf.labelIncludeList) > 0 || len(cf.labelExcludeList) > 0 ||
+		len(cf.ageIncludeList) > 0 || len(cf.ageExcludeList) > 0)
+}
+
+// AgeFilterChangeTime returns the next time an age filter starts or stops matching
+// the container, after which IsContainerExcluded may return another result.
+// It returns the zero time when no age filter will change.
+func (cf Filter) AgeFilterChangeTime(info ContainerFilterInfo, now time.Time) time.Time {
+	var next time.Time
+	if !cf.Enabled || info.StartedAt.IsZero() {
+		return next
+	}
+
+	for _, list := range [][]ageFilter{cf.ageIncludeList, cf.ageExcludeList} {
+		for _, f := range list {
+			change := info.StartedAt.Add(f.age)
+			if !change.After(now) {
+				continue
+			}
+			if next.IsZero() || change.Before(next) {
+				next = change
+			}
+		}
+	}
+
+	return next
+}
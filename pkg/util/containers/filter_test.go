@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -418,7 +419,7 @@ func TestNewAutodiscoveryFilter(t *testing.T) {
 	assert.False(t, f.IsExcluded("dummy", "k8s.gcr.io/pause-amd64:3.1", ""))
 	assert.False(t, f.IsExcluded("dummy", "rancher/pause-amd64:3.1", ""))
 	fe := map[string]struct{}{
-		"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'": {},
+		"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'": {},
 	}
 	assert.Equal(t, fe, GetFilterErrors())
 	ResetSharedFilter()
@@ -432,8 +433,8 @@ func TestNewAutodiscoveryFilter(t *testing.T) {
 	assert.Error(t, err, errors.New("invalid regex '?': error parsing regexp: missing argument to repetition operator: `?`"))
 	assert.NotNil(t, f)
 	fe = map[string]struct{}{
-		"invalid regex '?': error parsing regexp: missing argument to repetition operator: `?`":                                {},
-		"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'": {},
+		"invalid regex '?': error parsing regexp: missing argument to repetition operator: `?`":                                                {},
+		"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'": {},
 	}
 	assert.Equal(t, fe, GetFilterErrors())
 	ResetSharedFilter()
@@ -528,8 +529,8 @@ func TestParseFilters(t *testing.T) {
 			namespaceFilters: []*regexp.Regexp{regexp.MustCompile("dev-.*")},
 			expectedErrMsg:   nil,
 			filterErrors: []string{
-				"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'",
-				"Container filter \"also invalid\" is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'",
+				"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'",
+				"Container filter \"also invalid\" is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'",
 			},
 		},
 		{
@@ -542,16 +543,16 @@ func TestParseFilters(t *testing.T) {
 			filterErrors: []string{
 				"invalid regex 'a(?=b)': error parsing regexp: invalid or unsupported Perl syntax: `(?=`",
 				"invalid regex '?': error parsing regexp: missing argument to repetition operator: `?`",
-				"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'",
-				"Container filter \"also invalid\" is unknown, ignoring it. The supported filters are 'image', 'name' and 'kube_namespace'",
+				"Container filter \"invalid\" is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'",
+				"Container filter \"also invalid\" is unknown, ignoring it. The supported filters are 'image', 'name', 'kube_namespace', 'label' and 'age'",
 			},
 		},
 	} {
 		t.Run(fmt.Sprintf("case %d: %s", filters, tc.desc), func(t *testing.T) {
-			imageFilters, nameFilters, namespaceFilters, filterErrors, err := parseFilters(tc.filters)
-			assert.Equal(t, tc.imageFilters, imageFilters)
-			assert.Equal(t, tc.nameFilters, nameFilters)
-			assert.Equal(t, tc.namespaceFilters, namespaceFilters)
+			list, filterErrors, err := parseFilters(tc.filters)
+			assert.Equal(t, tc.imageFilters, list.images)
+			assert.Equal(t, tc.nameFilters, list.names)
+			assert.Equal(t, tc.namespaceFilters, list.namespaces)
 			assert.Equal(t, tc.filterErrors, filterErrors)
 			assert.Equal(t, tc.expectedErrMsg, err)
 		})
@@ -569,3 +570,104 @@ func resetConfig() {
 	config.Datadog.SetDefault("ac_include", []string{})
 	config.Datadog.SetDefault("ac_exclude", []string{})
 }
+
+func TestParseLabelAndAgeFilters(t *testing.T) {
+	list, filterErrors, err := parseFilters([]string{"label:team=ci", "label:ephemeral", "age<5m", "age>1h30m", "name:abc"})
+	require.NoError(t, err)
+	assert.Empty(t, filterErrors)
+	assert.Equal(t, []labelFilter{
+		{key: "team", value: regexp.MustCompile("ci")},
+		{key: "ephemeral"},
+	}, list.labels)
+	assert.Equal(t, []ageFilter{
+		{olderThan: false, age: 5 * time.Minute},
+		{olderThan: true, age: 90 * time.Minute},
+	}, list.ages)
+	assert.Equal(t, []*regexp.Regexp{regexp.MustCompile("abc")}, list.names)
+
+	for _, filter := range []string{"label:=ci", "label:team=a(?=b)", "age<5", "age>"} {
+		_, _, err := parseFilters([]string{filter})
+		assert.Error(t, err, filter)
+	}
+}
+
+func TestIsContainerExcluded(t *testing.T) {
+	now := time.Now()
+	f, err := NewFilter(
+		[]string{"label:datadog/monitored=true", "age>1h"},
+		[]string{"label:team=^ci$", "age<5m", "kube_namespace:sandbox"},
+	)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		desc     string
+		info     ContainerFilterInfo
+		excluded bool
+	}{
+		{
+			desc:     "no labels nor start time",
+			info:     ContainerFilterInfo{Name: "app", Image: "app:1.0", Namespace: "default"},
+			excluded: false,
+		},
+		{
+			desc:     "excluded label",
+			info:     ContainerFilterInfo{Name: "app", Labels: map[string]string{"team": "ci"}, StartedAt: now.Add(-10 * time.Minute)},
+			excluded: true,
+		},
+		{
+			desc:     "label value not matching",
+			info:     ContainerFilterInfo{Name: "app", Labels: map[string]string{"team": "cinema"}, StartedAt: now.Add(-10 * time.Minute)},
+			excluded: false,
+		},
+		{
+			desc:     "young container",
+			info:     ContainerFilterInfo{Name: "app", StartedAt: now.Add(-time.Minute)},
+			excluded: true,
+		},
+		{
+			desc:     "included label takes precedence",
+			info:     ContainerFilterInfo{Name: "app", Labels: map[string]string{"team": "ci", "datadog/monitored": "true"}, StartedAt: now.Add(-time.Minute)},
+			excluded: false,
+		},
+		{
+			desc:     "old container is included",
+			info:     ContainerFilterInfo{Name: "app", Namespace: "sandbox", Labels: map[string]string{"team": "ci"}, StartedAt: now.Add(-2 * time.Hour)},
+			excluded: false,
+		},
+		{
+			desc:     "excluded namespace",
+			info:     ContainerFilterInfo{Name: "app", Namespace: "sandbox", StartedAt: now.Add(-10 * time.Minute)},
+			excluded: true,
+		},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			assert.Equal(t, tc.excluded, f.IsContainerExcluded(tc.info))
+		})
+	}
+
+	// the label and age filters can't match without the labels and start time
+	assert.False(t, f.IsExcluded("app", "app:1.0", "default"))
+	assert.True(t, f.IsExcluded("app", "app:1.0", "sandbox"))
+}
+
+func TestAgeFilterChangeTime(t *testing.T) {
+	now := time.Now()
+	f, err := NewFilter([]string{"age>1h"}, []string{"age<5m"})
+	require.NoError(t, err)
+	assert.True(t, f.HasLabelOrAgeFilters())
+
+	startedAt := now.Add(-time.Minute)
+	assert.Equal(t, startedAt.Add(5*time.Minute), f.AgeFilterChangeTime(ContainerFilterInfo{StartedAt: startedAt}, now))
+
+	startedAt = now.Add(-10 * time.Minute)
+	assert.Equal(t, startedAt.Add(time.Hour), f.AgeFilterChangeTime(ContainerFilterInfo{StartedAt: startedAt}, now))
+
+	// no age filter changes anymore, or the start time is unknown
+	assert.True(t, f.AgeFilterChangeTime(ContainerFilterInfo{StartedAt: now.Add(-2 * time.Hour)}, now).IsZero())
+	assert.True(t, f.AgeFilterChangeTime(ContainerFilterInfo{}, now).IsZero())
+
+	f, err = NewFilter(nil, []string{"name:abc"})
+	require.NoError(t, err)
+	assert.False(t, f.HasLabelOrAgeFilters())
+	assert.True(t, f.AgeFilterChangeTime(ContainerFilterInfo{StartedAt: startedAt}, now).IsZero())
+}
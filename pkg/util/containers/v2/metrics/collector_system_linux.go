@@ -11,6 +11,7 @@ package metrics
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
@@ -18,6 +19,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/system"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 const (
@@ -30,7 +32,7 @@ func init() {
 		priority: 0,
 		runtimes: allLinuxRuntimes,
 		factory: func() (Collector, error) {
-			return newCgroupCollector()
+			return newCgroupCollector(workloadmeta.GetGlobalStore())
 		},
 	})
 }
@@ -38,9 +40,15 @@ func init() {
 type cgroupCollector struct {
 	reader   *cgroups.Reader
 	procPath string
+	store    workloadmeta.Store
+
+	// containerIDs are the IDs of the containers known by workloadmeta, used to
+	// find the cgroups of the containers whose ID has an unusual format
+	containerIDs     map[string]struct{}
+	containerIDsLock sync.RWMutex
 }
 
-func newCgroupCollector() (*cgroupCollector, error) {
+func newCgroupCollector(store workloadmeta.Store) (*cgroupCollector, error) {
 	var err error
 	var hostPrefix string
 
@@ -49,11 +57,17 @@ func newCgroupCollector() (*cgroupCollector, error) {
 		hostPrefix = "/host"
 	}
 
-	reader, err := cgroups.NewReader(
+	c := &cgroupCollector{
+		procPath:     procPath,
+		store:        store,
+		containerIDs: make(map[string]struct{}),
+	}
+
+	c.reader, err = cgroups.NewReader(
 		cgroups.WithCgroupV1BaseController("freezer"),
 		cgroups.WithProcPath(procPath),
 		cgroups.WithHostPrefix(hostPrefix),
-		cgroups.WithReaderFilter(cgroups.ContainerFilter),
+		cgroups.WithReaderFilter(c.containerFilter),
 	)
 	if err != nil {
 		// Cgroup provider is pretty static. Except not having required mounts, it should always work.
@@ -61,10 +75,7 @@ func newCgroupCollector() (*cgroupCollector, error) {
 		return nil, ErrPermaFail
 	}
 
-	return &cgroupCollector{
-		reader:   reader,
-		procPath: procPath,
-	}, nil
+	return c, nil
 }
 
 func (c *cgroupCollector) ID() string {
@@ -104,6 +115,10 @@ func (c *cgroupCollector) GetContainerNetworkStats(containerID string, cacheVali
 func (c *cgroupCollector) getCgroup(containerID string, cacheValidity time.Duration) (cgroups.Cgroup, error) {
 	cg := c.reader.GetCgroup(containerID)
 	if cg == nil {
+		if err := c.refreshContainerIDs(); err != nil {
+			log.Debugf("Unable to list the containers known by workloadmeta, err: %v", err)
+		}
+
 		err := c.reader.RefreshCgroups(cacheValidity)
 		if err != nil {
 			return nil, fmt.Errorf("containerdID not found and unable to refresh cgroups, err: %w", err)
@@ -118,6 +133,51 @@ func (c *cgroupCollector) getCgroup(containerID string, cacheValidity time.Durat
 	return cg, nil
 }
 
+// refreshContainerIDs updates the IDs of the containers looked up in cgroupfs
+// with the containers currently known by workloadmeta
+func (c *cgroupCollector) refreshContainerIDs() error {
+	containers, err := c.store.ListContainers()
+	if err != nil {
+		return err
+	}
+
+	containerIDs := make(map[string]struct{}, len(containers))
+	for _, container := range containers {
+		containerIDs[container.ID] = struct{}{}
+	}
+
+	c.containerIDsLock.Lock()
+	c.containerIDs = containerIDs
+	c.containerIDsLock.Unlock()
+
+	return nil
+}
+
+// containerFilter matches the cgroup folders named after a container ID, or
+// containing the ID of a container known by workloadmeta
+func (c *cgroupCollector) containerFilter(path, name string) (string, error) {
+	if containerID, err := cgroups.ContainerFilter(path, name); containerID != "" || err != nil {
+		return containerID, err
+	}
+
+	// With systemd cgroup driver, there may be a `.mount` cgroup on top of the normal one
+	// While existing, no process is attached to it and thus holds no stats
+	if strings.HasSuffix(name, ".mount") {
+		return "", nil
+	}
+
+	c.containerIDsLock.RLock()
+	defer c.containerIDsLock.RUnlock()
+
+	for containerID := range c.containerIDs {
+		if strings.Contains(name, containerID) {
+			return containerID, nil
+		}
+	}
+
+	return "", nil
+}
+
 func (c *cgroupCollector) buildContainerMetrics(cgs cgroups.Stats) *ContainerStats {
 	cs := &ContainerStats{
 		Timestamp: time.Now(),
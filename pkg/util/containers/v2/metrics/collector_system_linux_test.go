@@ -14,6 +14,8 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util"
 	"github.com/DataDog/datadog-agent/pkg/util/cgroups"
 	"github.com/DataDog/datadog-agent/pkg/util/system"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+	workloadmetatesting "github.com/DataDog/datadog-agent/pkg/workloadmeta/testing"
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 )
@@ -144,3 +146,50 @@ func TestBuildContainerMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestCgroupCollectorContainerFilter(t *testing.T) {
+	store := workloadmetatesting.NewStore()
+	for _, id := range []string{"3a1cd7f2-b6e4-4c3d-7e21-9d0b", "cri-containerd-foo"} {
+		store.Set(&workloadmeta.Container{
+			EntityID: workloadmeta.EntityID{
+				Kind: workloadmeta.KindContainer,
+				ID:   id,
+			},
+		})
+	}
+
+	c := &cgroupCollector{
+		store:        store,
+		containerIDs: make(map[string]struct{}),
+	}
+	assert.NoError(t, c.refreshContainerIDs())
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "docker-a2de6ee3c1fec9d9d3d6a2e5e0d0a2cbe5d4c2e0e8a3f6f0c1b9d8a7e6f5d4c3.scope", want: "a2de6ee3c1fec9d9d3d6a2e5e0d0a2cbe5d4c2e0e8a3f6f0c1b9d8a7e6f5d4c3"},
+		{name: "3a1cd7f2-b6e4-4c3d-7e21-9d0b", want: "3a1cd7f2-b6e4-4c3d-7e21-9d0b"},
+		{name: "cri-containerd-foo.scope", want: "cri-containerd-foo"},
+		{name: "cri-containerd-foo.scope.mount", want: ""},
+		{name: "kubepods-besteffort.slice", want: ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			id, err := c.containerFilter("/sys/fs/cgroup/memory/"+test.name, test.name)
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, id)
+		})
+	}
+
+	store.Unset(&workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   "cri-containerd-foo",
+		},
+	})
+	assert.NoError(t, c.refreshContainerIDs())
+	id, err := c.containerFilter("/sys/fs/cgroup/memory/cri-containerd-foo.scope", "cri-containerd-foo.scope")
+	assert.NoError(t, err)
+	assert.Equal(t, "", id)
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/containers/metrics"
 	"github.com/DataDog/datadog-agent/pkg/util/containers/providers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 )
 
 var healthRe = regexp.MustCompile(`\(health: (\w+)\)`)
@@ -228,7 +229,18 @@ func (d *DockerUtil) dockerContainers(ctx context.Context, cfg *ContainerListCon
 		}
 
 		pauseContainerExcluded := config.Datadog.GetBool("exclude_pause_container") && containers.IsPauseContainer(c.Labels)
-		excluded := pauseContainerExcluded || d.cfg.filter.IsExcluded(c.Names[0], image, c.Labels["io.kubernetes.pod.namespace"])
+		filterInfo := containers.ContainerFilterInfo{
+			Name:      c.Names[0],
+			Image:     image,
+			Namespace: c.Labels["io.kubernetes.pod.namespace"],
+		}
+		if d.cfg.filter.HasLabelOrAgeFilters() {
+			filterInfo = workloadmeta.GetContainerFilterInfo(workloadmeta.GetGlobalStore(), c.ID, filterInfo)
+			if filterInfo.Labels == nil {
+				filterInfo.Labels = c.Labels
+			}
+		}
+		excluded := pauseContainerExcluded || d.cfg.filter.IsContainerExcluded(filterInfo)
 		if excluded && !cfg.FlagExcluded {
 			continue
 		}
@@ -18,6 +18,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/util/containers"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
 
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/events"
@@ -70,7 +71,14 @@ func (d *DockerUtil) processContainerEvent(ctx context.Context, msg events.Messa
 			log.Warnf("can't resolve image name %s: %s", imageName, err)
 		}
 	}
-	if filter != nil && filter.IsExcluded(containerName, imageName, "") {
+	filterInfo := containers.ContainerFilterInfo{
+		Name:  containerName,
+		Image: imageName,
+	}
+	if filter != nil && filter.HasLabelOrAgeFilters() {
+		filterInfo = workloadmeta.GetContainerFilterInfo(workloadmeta.GetGlobalStore(), msg.Actor.ID, filterInfo)
+	}
+	if filter != nil && filter.IsContainerExcluded(filterInfo) {
 		log.Tracef("events from %s are skipped as the image is excluded for the event collection", containerName)
 		return nil, nil
 	}
@@ -7,12 +7,16 @@ package ec2
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
 )
 
 func init() {
 	diagnosis.Register("EC2 Metadata availability", diagnose)
+	diagnosis.Register("EC2 IMDSv2 token availability", diagnoseIMDSv2)
 }
 
 // diagnose the ec2 metadata API availability
@@ -20,3 +24,13 @@ func diagnose() error {
 	_, err := GetHostname(context.TODO())
 	return err
 }
+
+// diagnoseIMDSv2 requests a new IMDSv2 token, bypassing the cached one, to
+// report the hop limit issues of the containerized agents
+func diagnoseIMDSv2() error {
+	if !config.IsCloudProviderEnabled(CloudProviderName) {
+		return fmt.Errorf("cloud provider is disabled by configuration")
+	}
+	_, err := fetchToken(context.TODO(), time.Minute)
+	return err
+}
@@ -9,26 +9,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"net"
-	"net/http"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/cachedfetch"
 	"github.com/DataDog/datadog-agent/pkg/util/common"
-	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
-type ec2Token struct {
-	expirationDate time.Time
-	value          string
-	sync.RWMutex
-}
-
 // declare these as vars not const to ease testing
 var (
 	metadataURL        = "http://169.254.169.254/latest/meta-data"
@@ -37,12 +27,14 @@ var (
 	defaultPrefixes    = []string{"ip-", "domu", "ec2amaz-"}
 	token              = ec2Token{}
 	tokenRenewalWindow = 15 * time.Second
+	tokenRetryInterval = 5 * time.Minute
 	// CloudProviderName contains the inventory name of for EC2
 	CloudProviderName = "AWS"
 )
 
 var instanceIDFetcher = cachedfetch.Fetcher{
-	Name: "EC2 InstanceID",
+	Name:    "EC2 InstanceID",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		return getMetadataItemWithMaxLength(ctx,
 			"/instance-id",
@@ -57,7 +49,8 @@ func GetInstanceID(ctx context.Context) (string, error) {
 }
 
 var localIPv4Fetcher = cachedfetch.Fetcher{
-	Name: "EC2 Local IPv4 Address",
+	Name:    "EC2 Local IPv4 Address",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		return getMetadataItem(ctx, "/local-ipv4")
 	},
@@ -74,7 +67,8 @@ func GetLocalIPv4() ([]string, error) {
 }
 
 var publicIPv4Fetcher = cachedfetch.Fetcher{
-	Name: "EC2 Public IPv4 Address",
+	Name:    "EC2 Public IPv4 Address",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		return getMetadataItem(ctx, "/public-ipv4")
 	},
@@ -94,7 +88,8 @@ func IsRunningOn(ctx context.Context) bool {
 }
 
 var hostnameFetcher = cachedfetch.Fetcher{
-	Name: "EC2 Hostname",
+	Name:    "EC2 Hostname",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		return getMetadataItemWithMaxLength(ctx,
 			"/hostname",
@@ -109,7 +104,8 @@ func GetHostname(ctx context.Context) (string, error) {
 }
 
 var networkIDFetcher = cachedfetch.Fetcher{
-	Name: "VPC IDs",
+	Name:    "VPC IDs",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		resp, err := getMetadataItem(ctx, "/network/interfaces/macs")
 		if err != nil {
@@ -239,77 +235,6 @@ func extractClusterName(tags []string) (string, error) {
 	return clusterName, nil
 }
 
-func doHTTPRequest(ctx context.Context, url string) (string, error) {
-	headers := map[string]string{}
-	if config.Datadog.GetBool("ec2_prefer_imdsv2") {
-		token, err := getToken(ctx)
-		if err != nil {
-			log.Warnf("ec2_prefer_imdsv2 is set to true in the configuration but the agent was unable to proceed: %s", err)
-		} else {
-			headers["X-aws-ec2-metadata-token"] = token
-		}
-	}
-
-	return httputils.Get(ctx, url, headers, time.Duration(config.Datadog.GetInt("ec2_metadata_timeout"))*time.Millisecond)
-}
-
-func getToken(ctx context.Context) (string, error) {
-
-	token.RLock()
-	// The token renewal window is open, refreshing the token
-	if time.Now().Before(token.expirationDate) {
-		val := token.value
-		token.RUnlock()
-		return val, nil
-	}
-	token.RUnlock()
-	token.Lock()
-	defer token.Unlock()
-	// Token has been refreshed by another caller
-	if time.Now().Before(token.expirationDate) {
-		return token.value, nil
-	}
-
-	client := http.Client{
-		Transport: httputils.CreateHTTPTransport(),
-		Timeout:   time.Duration(config.Datadog.GetInt("ec2_metadata_timeout")) * time.Millisecond,
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPut, tokenURL, nil)
-	if err != nil {
-		return "", err
-	}
-
-	tokenLifetime := time.Duration(config.Datadog.GetInt("ec2_metadata_token_lifetime")) * time.Second
-	req.Header.Add("X-aws-ec2-metadata-token-ttl-seconds", fmt.Sprintf("%d", int(tokenLifetime.Seconds())))
-	// Set the local expiration date before requesting the metadata endpoint so the local expiration date will always
-	// expire before the expiration date computed on the AWS side. The expiration date is set minus the renewal window
-	// to ensure the token will be refreshed before it expires.
-	token.expirationDate = time.Now().Add(tokenLifetime - tokenRenewalWindow)
-	res, err := client.Do(req)
-	if err != nil {
-		// Re-mark the token as expired now, so it will be refreshed next time
-		token.expirationDate = time.Now()
-		return "", err
-	}
-
-	if res.StatusCode != 200 {
-		// Re-mark the token as expired now, so it will be refreshed next time
-		token.expirationDate = time.Now()
-		return "", fmt.Errorf("status code %d trying to fetch %s", res.StatusCode, tokenURL)
-	}
-
-	defer res.Body.Close()
-	all, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		// Re-mark the token as expired now, so it will be refreshed next time
-		token.expirationDate = time.Now()
-		return "", fmt.Errorf("unable to read response body, %s", err)
-	}
-	token.value = string(all)
-	return token.value, nil
-}
-
 // IsDefaultHostname returns whether the given hostname is a default one for EC2
 func IsDefaultHostname(hostname string) bool {
 	return isDefaultHostname(hostname, config.Datadog.GetBool("ec2_use_windows_prefix_detection"))
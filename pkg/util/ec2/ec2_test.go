@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 	"time"
 
@@ -135,6 +136,16 @@ func TestGetHostname(t *testing.T) {
 	assert.Equal(t, "", val)
 	assert.Equal(t, lastRequest.URL.Path, "/hostname")
 
+	// the error is cached during the backoff, the API is not queried again
+	lastRequest = nil
+	val, err = GetHostname(ctx)
+	assert.NotNil(t, err)
+	assert.Equal(t, "", val)
+	assert.Nil(t, lastRequest)
+
+	// clear the cached error
+	hostnameFetcher.Reset()
+
 	// API successful, should return hostname
 	responseCode = http.StatusOK
 	val, err = GetHostname(ctx)
@@ -481,6 +492,112 @@ func TestMetedataRequestWithoutToken(t *testing.T) {
 	assert.Equal(t, http.MethodGet, requestWithoutToken.Method)
 }
 
+func TestGetTokenRetryInterval(t *testing.T) {
+	ctx := context.Background()
+	var tokenRequests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+	tokenURL = ts.URL
+	config.Datadog.Set("ec2_metadata_timeout", 1000)
+	defer resetPackageVars()
+
+	_, err := getToken(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 1, tokenRequests)
+
+	// the error is cached until the retry interval elapsed
+	_, err2 := getToken(ctx)
+	assert.Equal(t, err, err2)
+	assert.Equal(t, 1, tokenRequests)
+
+	token.retryDate = time.Now()
+	_, err = getToken(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 2, tokenRequests)
+}
+
+func TestMetadataRequestIMDSv2Required(t *testing.T) {
+	var requestsWithoutToken, requestsForToken, requestsWithToken int
+	config.Datadog.SetDefault("ec2_prefer_imdsv2", false)
+
+	ipv4 := "198.51.100.1"
+	tok := "AQAAAFKw7LyqwVmmBMkqXHpDBuDWw2GnfGswTHi2yiIOGvzD7OMaWw=="
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		switch {
+		case r.Method == http.MethodPut:
+			requestsForToken++
+			io.WriteString(w, tok)
+		case r.Header.Get("X-aws-ec2-metadata-token") != tok:
+			// IMDSv1 is disabled on the instance
+			requestsWithoutToken++
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			requestsWithToken++
+			io.WriteString(w, ipv4)
+		}
+	}))
+	defer ts.Close()
+	metadataURL = ts.URL
+	tokenURL = ts.URL
+	config.Datadog.Set("ec2_metadata_timeout", 1000)
+	defer resetPackageVars()
+
+	val, err := GetPublicIPv4(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, ipv4, val)
+	assert.Equal(t, 1, requestsWithoutToken)
+	assert.Equal(t, 1, requestsForToken)
+	assert.Equal(t, 1, requestsWithToken)
+
+	// the next requests use the cached token directly
+	ips, err := GetLocalIPv4()
+	require.NoError(t, err)
+	assert.Equal(t, []string{ipv4}, ips)
+	assert.Equal(t, 1, requestsWithoutToken)
+	assert.Equal(t, 1, requestsForToken)
+	assert.Equal(t, 2, requestsWithToken)
+}
+
+func TestMetadataRequestTokenRevoked(t *testing.T) {
+	config.Datadog.SetDefault("ec2_prefer_imdsv2", true)
+	defer config.Datadog.SetDefault("ec2_prefer_imdsv2", false)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			io.WriteString(w, "token")
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+	metadataURL = ts.URL
+	tokenURL = ts.URL
+	config.Datadog.Set("ec2_metadata_timeout", 1000)
+	defer resetPackageVars()
+
+	_, err := doHTTPRequest(context.Background(), metadataURL+"/hostname")
+	require.Error(t, err)
+	// the token is requested again by the next request
+	assert.False(t, time.Now().Before(token.expirationDate))
+}
+
+func TestExplainTokenError(t *testing.T) {
+	timeoutErr := fmt.Errorf("request failed: %w", context.DeadlineExceeded)
+	otherErr := errors.New("connection refused")
+
+	assert.Equal(t, timeoutErr, explainTokenError(timeoutErr))
+
+	os.Setenv("DOCKER_DD_AGENT", "true")
+	defer os.Unsetenv("DOCKER_DD_AGENT")
+	assert.Contains(t, explainTokenError(timeoutErr).Error(), "HttpPutResponseHopLimit")
+	assert.Equal(t, otherErr, explainTokenError(otherErr))
+}
+
 func TestGetNTPHosts(t *testing.T) {
 	ctx := context.Background()
 	expectedHosts := []string{"169.254.169.123"}
@@ -0,0 +1,231 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ec2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	tokenHeader         = "X-aws-ec2-metadata-token"
+	tokenLifetimeHeader = "X-aws-ec2-metadata-token-ttl-seconds"
+)
+
+// ec2Token caches the IMDSv2 session token shared by all the requests to the
+// metadata endpoints (hostname, cluster name, host tags, network ID...).
+type ec2Token struct {
+	expirationDate time.Time
+	value          string
+
+	// After a failure, no token is requested before retryDate and the requests
+	// fall back to IMDSv1, so that they don't all wait for the token timeout.
+	retryDate time.Time
+	lastErr   error
+
+	// required is set once an IMDSv1 request was rejected, which happens when
+	// the instance enforces IMDSv2 (HttpTokens=required).
+	required bool
+
+	sync.RWMutex
+}
+
+// doHTTPRequest queries a metadata endpoint, with an IMDSv2 token when
+// `ec2_prefer_imdsv2` is set or when the instance requires it.
+func doHTTPRequest(ctx context.Context, url string) (string, error) {
+	headers := map[string]string{}
+	if config.Datadog.GetBool("ec2_prefer_imdsv2") || token.isRequired() {
+		if value, err := getToken(ctx); err != nil {
+			log.Debugf("Requesting %s without IMDSv2 token: %s", url, err)
+		} else {
+			headers[tokenHeader] = value
+		}
+	}
+
+	res, statusCode, err := getMetadata(ctx, url, headers)
+	if statusCode != http.StatusUnauthorized {
+		return res, err
+	}
+
+	if _, withToken := headers[tokenHeader]; withToken {
+		// the token was revoked or expired before the local expiration date
+		token.expire()
+		return res, err
+	}
+
+	if token.setRequired() {
+		log.Infof("The EC2 metadata endpoint rejected a request without token, IMDSv2 is required on this instance: using IMDSv2 for all the requests")
+	}
+	value, tokenErr := getToken(ctx)
+	if tokenErr != nil {
+		return "", fmt.Errorf("IMDSv2 is required on this instance but no token could be fetched: %s", tokenErr)
+	}
+	headers[tokenHeader] = value
+	res, _, err = getMetadata(ctx, url, headers)
+	return res, err
+}
+
+// getMetadata sends a GET request to a metadata endpoint and returns the body
+// along with the status code, which is 0 when no response was received.
+func getMetadata(ctx context.Context, url string, headers map[string]string) (string, int, error) {
+	client := http.Client{
+		Transport: httputils.CreateHTTPTransport(),
+		Timeout:   time.Duration(config.Datadog.GetInt("ec2_metadata_timeout")) * time.Millisecond,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", 0, err
+	}
+	for header, value := range headers {
+		req.Header.Add(header, value)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", res.StatusCode, fmt.Errorf("status code %d trying to GET %s", res.StatusCode, url)
+	}
+
+	all, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", res.StatusCode, fmt.Errorf("unable to read response body, %s", err)
+	}
+	return string(all), res.StatusCode, nil
+}
+
+// getToken returns the cached IMDSv2 token, and fetches a new one when it
+// expired. The last error is returned until tokenRetryInterval elapsed.
+func getToken(ctx context.Context) (string, error) {
+	token.RLock()
+	value, cached, err := token.cachedLocked()
+	token.RUnlock()
+	if cached {
+		return value, err
+	}
+
+	token.Lock()
+	defer token.Unlock()
+	// Token has been refreshed by another caller
+	if value, cached, err := token.cachedLocked(); cached {
+		return value, err
+	}
+
+	tokenLifetime := time.Duration(config.Datadog.GetInt("ec2_metadata_token_lifetime")) * time.Second
+	// Compute the local expiration date before requesting the metadata endpoint so the local expiration date will always
+	// expire before the expiration date computed on the AWS side. The expiration date is set minus the renewal window
+	// to ensure the token will be refreshed before it expires.
+	expirationDate := time.Now().Add(tokenLifetime - tokenRenewalWindow)
+	value, err = fetchToken(ctx, tokenLifetime)
+	if err != nil {
+		log.Warnf("Unable to fetch an IMDSv2 token, falling back to IMDSv1 for %s: %s", tokenRetryInterval, err)
+		token.expirationDate = time.Now()
+		token.retryDate = time.Now().Add(tokenRetryInterval)
+		token.lastErr = err
+		return "", err
+	}
+
+	if token.lastErr != nil {
+		log.Infof("IMDSv2 token fetched successfully")
+	}
+	token.value = value
+	token.expirationDate = expirationDate
+	token.lastErr = nil
+	return value, nil
+}
+
+// cachedLocked returns the valid token or the last error, cached is false when
+// a new token must be requested. The caller must hold the lock.
+func (t *ec2Token) cachedLocked() (string, bool, error) {
+	now := time.Now()
+	if now.Before(t.expirationDate) {
+		return t.value, true, nil
+	}
+	if t.lastErr != nil && now.Before(t.retryDate) {
+		return "", true, t.lastErr
+	}
+	return "", false, nil
+}
+
+func (t *ec2Token) expire() {
+	t.Lock()
+	defer t.Unlock()
+	t.expirationDate = time.Now()
+}
+
+func (t *ec2Token) isRequired() bool {
+	t.RLock()
+	defer t.RUnlock()
+	return t.required
+}
+
+// setRequired marks IMDSv2 as required, and returns false when it already was
+func (t *ec2Token) setRequired() bool {
+	t.Lock()
+	defer t.Unlock()
+	if t.required {
+		return false
+	}
+	t.required = true
+	return true
+}
+
+// fetchToken requests a new IMDSv2 token
+func fetchToken(ctx context.Context, tokenLifetime time.Duration) (string, error) {
+	client := http.Client{
+		Transport: httputils.CreateHTTPTransport(),
+		Timeout:   time.Duration(config.Datadog.GetInt("ec2_metadata_timeout")) * time.Millisecond,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, tokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add(tokenLifetimeHeader, fmt.Sprintf("%d", int(tokenLifetime.Seconds())))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", explainTokenError(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status code %d trying to fetch %s", res.StatusCode, tokenURL)
+	}
+
+	all, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read response body, %s", err)
+	}
+	return string(all), nil
+}
+
+// explainTokenError adds a hint to the token request timeouts of the
+// containerized agents: the responses to the token requests are dropped when
+// the hop limit of the instance is too low to reach the container network.
+func explainTokenError(err error) error {
+	var netErr net.Error
+	timeout := errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+	if !timeout || !config.IsContainerized() {
+		return err
+	}
+	return fmt.Errorf("%s: the agent runs in a container, the IMDSv2 token responses don't reach it when the "+
+		"hop limit of the instance metadata options (HttpPutResponseHopLimit) is 1, set it to 2 or more", err)
+}
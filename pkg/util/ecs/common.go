@@ -53,7 +53,15 @@ func ListContainersInCurrentTask() ([]*containers.Container, error) {
 
 	for _, c := range task.Containers {
 		// Not using c.DockerName as it's generated with ecs task name, thus probably not easy to match
-		if filter == nil || !filter.IsExcluded(c.Name, c.Image, "") {
+		filterInfo := containers.ContainerFilterInfo{
+			Name:   c.Name,
+			Image:  c.Image,
+			Labels: c.Labels,
+		}
+		if startedAt, err := time.Parse(time.RFC3339, c.StartedAt); err == nil {
+			filterInfo.StartedAt = startedAt
+		}
+		if filter == nil || !filter.IsContainerExcluded(filterInfo) {
 			c, e := convertMetaV2Container(c, task.Limits)
 			cList = append(cList, c)
 			if e != nil {
@@ -6,9 +6,17 @@
 package common
 
 import (
+	"net/http"
+	"sync"
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+var (
+	metadataTransport     *http.Transport
+	metadataTransportOnce sync.Once
 )
 
 // CloudProviderName contains the inventory name of for ECS
@@ -18,3 +26,12 @@ const CloudProviderName = "AWS"
 func MetadataTimeout() time.Duration {
 	return config.Datadog.GetDuration("ecs_metadata_timeout") * time.Millisecond
 }
+
+// MetadataTransport returns the transport shared by the ECS metadata clients,
+// its connections go through the air-gapped egress validator
+func MetadataTransport() *http.Transport {
+	metadataTransportOnce.Do(func() {
+		metadataTransport = httputils.NewEgressValidatedTransport()
+	})
+	return metadataTransport
+}
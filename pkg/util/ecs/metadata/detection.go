@@ -20,6 +20,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/docker"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/ecs/common"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 
 	v1 "github.com/DataDog/datadog-agent/pkg/util/ecs/metadata/v1"
@@ -100,7 +101,7 @@ func getAgentV1ContainerURLs(ctx context.Context) ([]string, error) {
 
 // testURLs trys a set of URLs and returns the first one that succeeds.
 func testURLs(urls []string, timeout time.Duration) string {
-	client := &http.Client{Timeout: timeout}
+	client := &http.Client{Timeout: timeout, Transport: common.MetadataTransport()}
 	for _, url := range urls {
 		r, err := client.Get(url)
 		if err != nil {
@@ -74,7 +74,7 @@ func (c *Client) GetTaskWithTags(ctx context.Context) (*Task, error) {
 }
 
 func (c *Client) get(ctx context.Context, path string, v interface{}) error {
-	client := http.Client{Timeout: common.MetadataTimeout()}
+	client := http.Client{Timeout: common.MetadataTimeout(), Transport: common.MetadataTransport()}
 	url, err := c.makeURL(path)
 	if err != nil {
 		return fmt.Errorf("Error constructing metadata request URL: %w", err)
@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package fips implements the FIPS mode of the Agent: when `fips.enabled` is
+// set, the outgoing TLS connections are restricted to FIPS approved protocol
+// versions, cipher suites and curves, and the Agent refuses to start with a
+// configuration using non-compliant code paths.
+package fips
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+// approvedCipherSuites are the TLS 1.2 cipher suites approved by NIST SP 800-52r2
+// and supported by Go. The TLS 1.3 cipher suites aren't configurable, the FIPS
+// builds of Go restrict them to the approved ones.
+var approvedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// approvedCurves are the elliptic curves approved for the key exchange
+var approvedCurves = []tls.CurveID{
+	tls.CurveP256,
+	tls.CurveP384,
+	tls.CurveP521,
+}
+
+// Enabled returns whether the FIPS mode is enabled
+func Enabled() bool {
+	return config.Datadog.GetBool("fips.enabled")
+}
+
+// ConfigureTLS restricts tlsConfig to TLS 1.2+ with approved cipher suites
+// and curves when the FIPS mode is enabled, it's left unchanged otherwise.
+func ConfigureTLS(tlsConfig *tls.Config) {
+	if !Enabled() {
+		return
+	}
+	restrictTLS(tlsConfig)
+}
+
+func restrictTLS(tlsConfig *tls.Config) {
+	if tlsConfig.MinVersion < tls.VersionTLS12 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+	tlsConfig.CipherSuites = approvedCipherSuites
+	tlsConfig.CurvePreferences = approvedCurves
+	tlsConfig.InsecureSkipVerify = false
+}
+
+// Verify returns an error when the FIPS mode is enabled but the Agent wasn't
+// built with a FIPS validated cryptographic module, or when the configuration
+// enables code paths which aren't compliant.
+func Verify() error {
+	if !Enabled() {
+		return nil
+	}
+
+	if !BuildEnabled {
+		return errors.New("fips.enabled is set but the Agent was not built with a FIPS validated cryptographic module")
+	}
+
+	var errs []string
+	for _, key := range []string{"skip_ssl_validation", "logs_config.logs_no_ssl"} {
+		if config.Datadog.GetBool(key) {
+			errs = append(errs, key)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("fips.enabled is set, the following options are not FIPS compliant and must be disabled: %v", errs)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build fips
+
+package fips
+
+import (
+	// restrict crypto/tls to the FIPS approved settings, this package is only
+	// provided by the FIPS toolchains (BoringCrypto or system OpenSSL)
+	_ "crypto/tls/fipsonly"
+)
+
+// BuildEnabled is true when the Agent is built with a FIPS validated cryptographic module
+const BuildEnabled = true
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package fips
+
+import (
+	"crypto/tls"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+func TestConfigureTLSDisabled(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("fips.enabled", false)
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	ConfigureTLS(tlsConfig)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+	assert.Zero(t, tlsConfig.MinVersion)
+	assert.Nil(t, tlsConfig.CipherSuites)
+}
+
+func TestConfigureTLSEnabled(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("fips.enabled", true)
+	defer mockConfig.Set("fips.enabled", false)
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: true}
+	ConfigureTLS(tlsConfig)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Equal(t, approvedCipherSuites, tlsConfig.CipherSuites)
+	assert.Equal(t, approvedCurves, tlsConfig.CurvePreferences)
+
+	// a higher minimum version is kept
+	tlsConfig = &tls.Config{MinVersion: tls.VersionTLS13}
+	ConfigureTLS(tlsConfig)
+	assert.Equal(t, uint16(tls.VersionTLS13), tlsConfig.MinVersion)
+}
+
+func TestVerify(t *testing.T) {
+	mockConfig := config.Mock()
+	mockConfig.Set("fips.enabled", false)
+	mockConfig.Set("skip_ssl_validation", true)
+	assert.NoError(t, Verify())
+
+	mockConfig.Set("fips.enabled", true)
+	defer mockConfig.Set("fips.enabled", false)
+	defer mockConfig.Set("skip_ssl_validation", false)
+	assert.Error(t, Verify())
+
+	mockConfig.Set("skip_ssl_validation", false)
+	if BuildEnabled {
+		assert.NoError(t, Verify())
+	} else {
+		assert.Error(t, Verify())
+	}
+}
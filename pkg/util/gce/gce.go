@@ -35,7 +35,8 @@ func IsRunningOn(ctx context.Context) bool {
 }
 
 var hostnameFetcher = cachedfetch.Fetcher{
-	Name: "GCP Hostname",
+	Name:    "GCP Hostname",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		hostname, err := getResponseWithMaxLength(ctx, metadataURL+"/instance/hostname",
 			config.Datadog.GetInt("metadata_endpoints_max_hostname_size"))
@@ -72,7 +73,8 @@ func GetHostAliases(ctx context.Context) ([]string, error) {
 }
 
 var nameFetcher = cachedfetch.Fetcher{
-	Name: "GCP Instance Name",
+	Name:    "GCP Instance Name",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		return getResponseWithMaxLength(ctx,
 			metadataURL+"/instance/name",
@@ -81,7 +83,8 @@ var nameFetcher = cachedfetch.Fetcher{
 }
 
 var projectIDFetcher = cachedfetch.Fetcher{
-	Name: "GCP Project ID",
+	Name:    "GCP Project ID",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		projectID, err := getResponseWithMaxLength(ctx,
 			metadataURL+"/project/project-id",
@@ -115,7 +118,8 @@ func getInstanceAlias(ctx context.Context, hostname string) (string, error) {
 }
 
 var clusterNameFetcher = cachedfetch.Fetcher{
-	Name: "GCP Cluster Name",
+	Name:    "GCP Cluster Name",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		clusterName, err := getResponseWithMaxLength(ctx, metadataURL+"/instance/attributes/cluster-name",
 			config.Datadog.GetInt("metadata_endpoints_max_hostname_size"))
@@ -132,7 +136,8 @@ func GetClusterName(ctx context.Context) (string, error) {
 }
 
 var publicIPv4Fetcher = cachedfetch.Fetcher{
-	Name: "GCP Public IP",
+	Name:    "GCP Public IP",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		publicIPv4, err := getResponseWithMaxLength(ctx, metadataURL+"/instance/network-interfaces/0/access-configs/0/external-ip",
 			config.Datadog.GetInt("metadata_endpoints_max_hostname_size"))
@@ -149,7 +154,8 @@ func GetPublicIPv4(ctx context.Context) (string, error) {
 }
 
 var networkIDFetcher = cachedfetch.Fetcher{
-	Name: "GCP Network ID",
+	Name:    "GCP Network ID",
+	Backoff: &cachedfetch.DefaultBackoffPolicy,
 	Attempt: func(ctx context.Context) (interface{}, error) {
 		resp, err := getResponse(ctx, metadataURL+"/instance/network-interfaces/")
 		if err != nil {
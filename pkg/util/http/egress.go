@@ -0,0 +1,148 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+var (
+	tlmEgressViolations = telemetry.NewCounter("egress", "violations",
+		nil, "Count of outbound connections refused because the host is not in the air-gapped egress allowlist")
+
+	// egressRefusedWarningMap contains the hosts for which a refused connection was already logged
+	egressRefusedWarningMap = make(map[string]bool)
+)
+
+// EgressValidator validates outbound connections when the agent runs in
+// air-gapped mode (`airgapped: true`). Only the hosts listed in
+// `airgapped_egress_allowlist` and the loopback addresses can be reached.
+type EgressValidator struct {
+	enabled bool
+	hosts   map[string]struct{}
+	// suffixes holds the `*.example.com` entries of the allowlist as `.example.com`
+	suffixes []string
+}
+
+// NewEgressValidator creates a new EgressValidator. When `enabled` is false,
+// every host is allowed.
+func NewEgressValidator(enabled bool, allowlist []string) *EgressValidator {
+	v := &EgressValidator{
+		enabled: enabled,
+		hosts:   make(map[string]struct{}, len(allowlist)),
+	}
+	for _, entry := range allowlist {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if strings.HasPrefix(entry, "*.") {
+			v.suffixes = append(v.suffixes, entry[1:])
+			continue
+		}
+		v.hosts[entry] = struct{}{}
+	}
+	return v
+}
+
+// GetEgressValidator returns an EgressValidator built from the agent configuration
+func GetEgressValidator() *EgressValidator {
+	return NewEgressValidator(config.Datadog.GetBool("airgapped"), config.Datadog.GetStringSlice("airgapped_egress_allowlist"))
+}
+
+// IsAllowed returns whether the agent is allowed to connect to `host`.
+// `host` can contain a port.
+func (v *EgressValidator) IsAllowed(host string) bool {
+	if !v.enabled {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+
+	// connections to the local host, like the agent commands, aren't egress
+	if host == "localhost" {
+		return true
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	if _, found := v.hosts[host]; found {
+		return true
+	}
+	for _, suffix := range v.suffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate returns an error and reports a violation if the agent is not allowed
+// to connect to `host`.
+func (v *EgressValidator) Validate(host string) error {
+	if v.IsAllowed(host) {
+		return nil
+	}
+	tlmEgressViolations.Inc()
+	warnOnce(egressRefusedWarningMap, host, "Refusing outbound connections to %q: the host is not in 'airgapped_egress_allowlist'", host)
+	return fmt.Errorf("outbound connection to %q refused by the air-gapped egress allowlist", host)
+}
+
+// wrapProxyFunc validates the host of each request before resolving its proxy.
+// The proxy itself is validated when dialing.
+func (v *EgressValidator) wrapProxyFunc(proxyFunc func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(r *http.Request) (*url.URL, error) {
+		if err := v.Validate(r.URL.Host); err != nil {
+			return nil, err
+		}
+		if proxyFunc == nil {
+			return nil, nil
+		}
+		return proxyFunc(r)
+	}
+}
+
+// wrapDialContext validates the dialed address before opening the connection.
+func (v *EgressValidator) wrapDialContext(dialContext func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := v.Validate(addr); err != nil {
+			return nil, err
+		}
+		return dialContext(ctx, network, addr)
+	}
+}
+
+// Apply makes every connection opened by `transport` go through the validator.
+// It is a no-op when the air-gapped mode is disabled.
+func (v *EgressValidator) Apply(transport *http.Transport) {
+	if !v.enabled {
+		return
+	}
+	transport.Proxy = v.wrapProxyFunc(transport.Proxy)
+	if transport.DialContext == nil {
+		transport.DialContext = (&net.Dialer{}).DialContext
+	}
+	transport.DialContext = v.wrapDialContext(transport.DialContext)
+}
+
+// NewEgressValidatedTransport returns a copy of http.DefaultTransport going through the egress validator,
+// for the clients that must not use the proxy settings of the agent, like the metadata endpoints clients.
+func NewEgressValidatedTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	GetEgressValidator().Apply(transport)
+	return transport
+}
@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEgressValidatorDisabled(t *testing.T) {
+	v := NewEgressValidator(false, nil)
+	assert.True(t, v.IsAllowed("app.datadoghq.com"))
+	assert.NoError(t, v.Validate("app.datadoghq.com:443"))
+}
+
+func TestEgressValidatorAllowlist(t *testing.T) {
+	v := NewEgressValidator(true, []string{"intake.example.com", "*.internal.example.com", "10.0.0.1", "::1"})
+
+	assert.True(t, v.IsAllowed("intake.example.com"))
+	assert.True(t, v.IsAllowed("INTAKE.example.com:443"))
+	assert.True(t, v.IsAllowed("metrics.internal.example.com:443"))
+	assert.True(t, v.IsAllowed("10.0.0.1:8080"))
+	assert.True(t, v.IsAllowed("[::1]:443"))
+
+	assert.True(t, v.IsAllowed("localhost:5001"))
+	assert.True(t, v.IsAllowed("127.0.0.1:5001"))
+
+	assert.False(t, v.IsAllowed("internal.example.com"))
+	assert.False(t, v.IsAllowed("app.datadoghq.com:443"))
+	assert.False(t, v.IsAllowed("intake.example.com.evil.com"))
+	assert.Error(t, v.Validate("app.datadoghq.com:443"))
+}
+
+func TestEgressValidatorApply(t *testing.T) {
+	v := NewEgressValidator(true, []string{"intake.example.com"})
+	transport := &http.Transport{}
+	v.Apply(transport)
+
+	allowed, err := http.NewRequest("GET", "https://intake.example.com/api/v1", nil)
+	require.NoError(t, err)
+	proxyURL, err := transport.Proxy(allowed)
+	assert.NoError(t, err)
+	assert.Nil(t, proxyURL)
+
+	refused, err := http.NewRequest("GET", "https://app.datadoghq.com/api/v1", nil)
+	require.NoError(t, err)
+	_, err = transport.Proxy(refused)
+	assert.Error(t, err)
+
+	_, err = transport.DialContext(refused.Context(), "tcp", "app.datadoghq.com:443")
+	assert.Error(t, err)
+}
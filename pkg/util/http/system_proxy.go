@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// systemProxyCacheTTL is how long the proxy resolved for a host is reused
+// before evaluating the system proxy configuration (PAC file...) again
+const systemProxyCacheTTL = 5 * time.Minute
+
+// systemProxyResolver resolves the proxy of an URL from the proxy settings of
+// the system, nil when the URL is reached directly.
+type systemProxyResolver func(u *url.URL) (*url.URL, error)
+
+type cachedSystemProxy struct {
+	proxy   *url.URL
+	expires time.Time
+}
+
+// newSystemProxyTransportFunc returns a proxy function for a http.Transport
+// using resolve, caching its results per scheme and host.
+func newSystemProxyTransportFunc(resolve systemProxyResolver) func(*http.Request) (*url.URL, error) {
+	var mu sync.Mutex
+	cache := make(map[string]cachedSystemProxy)
+
+	return func(r *http.Request) (*url.URL, error) {
+		key := r.URL.Scheme + "://" + r.URL.Host
+		now := time.Now()
+
+		mu.Lock()
+		cached, ok := cache[key]
+		mu.Unlock()
+		if ok && now.Before(cached.expires) {
+			return cached.proxy, nil
+		}
+
+		proxy, err := resolve(r.URL)
+		if err != nil {
+			log.Warnf("Could not resolve the system proxy for URL '%s', not using any proxy: %s", key, err)
+			return nil, nil
+		}
+		if proxy != nil {
+			log.Debugf("Using system proxy %s://%s for URL '%s'", proxy.Scheme, proxy.Host, key)
+		} else {
+			log.Debugf("System proxy settings: not using any proxy for URL '%s'", key)
+		}
+
+		mu.Lock()
+		cache[key] = cachedSystemProxy{proxy: proxy, expires: now.Add(systemProxyCacheTTL)}
+		mu.Unlock()
+		return proxy, nil
+	}
+}
+
+// parseSystemProxyList returns the proxy to use for scheme from a proxy list
+// as returned by WinHTTP and PAC files, such as `proxy:8080`,
+// `http=proxy:8080;https=secure-proxy:8443` or `PROXY proxy:8080; DIRECT`.
+// It returns nil when the list doesn't contain any proxy for scheme.
+func parseSystemProxyList(list string, scheme string) (*url.URL, error) {
+	var fallback string
+	for _, entry := range strings.Split(list, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Fields(entry)
+		switch strings.ToUpper(fields[0]) {
+		case "DIRECT":
+			if fallback == "" {
+				return nil, nil
+			}
+			continue
+		case "PROXY", "HTTP", "HTTPS":
+			if len(fields) > 1 {
+				entry = fields[1]
+			}
+		}
+
+		if i := strings.Index(entry, "="); i >= 0 {
+			if strings.EqualFold(entry[:i], scheme) {
+				return parseSystemProxy(entry[i+1:])
+			}
+			continue
+		}
+		if fallback == "" {
+			fallback = entry
+		}
+	}
+
+	if fallback == "" {
+		return nil, nil
+	}
+	return parseSystemProxy(fallback)
+}
+
+func parseSystemProxy(proxy string) (*url.URL, error) {
+	proxy = strings.TrimSpace(proxy)
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("invalid system proxy %q: %s", proxy, err)
+	}
+	return u, nil
+}
+
+// matchSystemProxyBypass returns whether host must be reached directly
+// according to a proxy bypass list such as `<local>;*.example.com;10.*`.
+func matchSystemProxyBypass(bypass string, host string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	for _, pattern := range strings.FieldsFunc(bypass, func(r rune) bool { return r == ';' || r == ',' || r == ' ' }) {
+		if pattern == "<local>" {
+			// hosts without any dot, as defined by WinINet
+			if !strings.Contains(hostname, ".") && net.ParseIP(hostname) == nil {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(hostname)); matched {
+			return true
+		}
+		if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(host)); matched {
+			return true
+		}
+	}
+	return false
+}
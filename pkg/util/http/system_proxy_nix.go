@@ -0,0 +1,22 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build !windows
+
+package http
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// getSystemProxyTransportFunc is only implemented on Windows, the proxy
+// settings of the other systems are read from the environment variables.
+func getSystemProxyTransportFunc() func(*http.Request) (*url.URL, error) {
+	log.Debug("use_system_proxy is only supported on Windows, ignoring it")
+	return nil
+}
@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSystemProxyList(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     string
+		scheme   string
+		expected string
+	}{
+		{"empty", "", "https", ""},
+		{"single proxy", "proxy:8080", "https", "http://proxy:8080"},
+		{"proxy with scheme", "https://proxy:8443", "https", "https://proxy:8443"},
+		{"proxy per scheme", "http=proxy:8080;https=secure-proxy:8443", "https", "http://secure-proxy:8443"},
+		{"no proxy for scheme", "http=proxy:8080", "https", ""},
+		{"PAC result", "PROXY proxy1:8080; PROXY proxy2:8080; DIRECT", "https", "http://proxy1:8080"},
+		{"PAC direct", "DIRECT", "https", ""},
+		{"WinHTTP list", "proxy1:8080; proxy2:8080", "http", "http://proxy1:8080"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxy, err := parseSystemProxyList(tt.list, tt.scheme)
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, proxy)
+				return
+			}
+			require.NotNil(t, proxy)
+			assert.Equal(t, tt.expected, proxy.String())
+		})
+	}
+}
+
+func TestMatchSystemProxyBypass(t *testing.T) {
+	bypass := "<local>;*.example.com;10.*"
+
+	assert.True(t, matchSystemProxyBypass(bypass, "intranet"))
+	assert.True(t, matchSystemProxyBypass(bypass, "intranet:8080"))
+	assert.True(t, matchSystemProxyBypass(bypass, "app.EXAMPLE.com:443"))
+	assert.True(t, matchSystemProxyBypass(bypass, "10.0.0.1"))
+	assert.False(t, matchSystemProxyBypass(bypass, "app.datadoghq.com:443"))
+	assert.False(t, matchSystemProxyBypass(bypass, "192.168.0.1"))
+	assert.False(t, matchSystemProxyBypass("", "intranet"))
+}
+
+func TestSystemProxyTransportFuncCache(t *testing.T) {
+	calls := 0
+	proxyFunc := newSystemProxyTransportFunc(func(u *url.URL) (*url.URL, error) {
+		calls++
+		if u.Host == "direct.example.com" {
+			return nil, nil
+		}
+		return url.Parse("http://proxy:8080")
+	})
+
+	req, _ := http.NewRequest("GET", "https://app.datadoghq.com/api/v1/validate", nil)
+	proxy, err := proxyFunc(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy:8080", proxy.String())
+
+	req, _ = http.NewRequest("GET", "https://app.datadoghq.com/api/v1/series", nil)
+	proxy, err = proxyFunc(req)
+	require.NoError(t, err)
+	assert.Equal(t, "http://proxy:8080", proxy.String())
+	assert.Equal(t, 1, calls)
+
+	req, _ = http.NewRequest("GET", "https://direct.example.com", nil)
+	proxy, err = proxyFunc(req)
+	require.NoError(t, err)
+	assert.Nil(t, proxy)
+	assert.Equal(t, 2, calls)
+}
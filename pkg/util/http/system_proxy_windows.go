@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build windows
+
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var (
+	modwinhttp = windows.NewLazySystemDLL("winhttp.dll")
+	modkernel  = windows.NewLazySystemDLL("kernel32.dll")
+
+	procWinHTTPOpen                           = modwinhttp.NewProc("WinHttpOpen")
+	procWinHTTPCloseHandle                    = modwinhttp.NewProc("WinHttpCloseHandle")
+	procWinHTTPGetIEProxyConfigForCurrentUser = modwinhttp.NewProc("WinHttpGetIEProxyConfigForCurrentUser")
+	procWinHTTPGetProxyForURL                 = modwinhttp.NewProc("WinHttpGetProxyForUrl")
+	procGlobalFree                            = modkernel.NewProc("GlobalFree")
+)
+
+const (
+	winHTTPAccessTypeNoProxy    = 1
+	winHTTPAccessTypeNamedProxy = 3
+
+	winHTTPAutoProxyAutoDetect = 0x00000001
+	winHTTPAutoProxyConfigURL  = 0x00000002
+
+	winHTTPAutoDetectTypeDHCP = 0x00000001
+	winHTTPAutoDetectTypeDNSA = 0x00000002
+)
+
+// WINHTTP_CURRENT_USER_IE_PROXY_CONFIG
+type winHTTPCurrentUserIEProxyConfig struct {
+	fAutoDetect       int32
+	lpszAutoConfigURL *uint16
+	lpszProxy         *uint16
+	lpszProxyBypass   *uint16
+}
+
+// WINHTTP_AUTOPROXY_OPTIONS
+type winHTTPAutoProxyOptions struct {
+	dwFlags                uint32
+	dwAutoDetectFlags      uint32
+	lpszAutoConfigURL      *uint16
+	lpvReserved            uintptr
+	dwReserved             uint32
+	fAutoLogonIfChallenged int32
+}
+
+// WINHTTP_PROXY_INFO
+type winHTTPProxyInfo struct {
+	dwAccessType    uint32
+	lpszProxy       *uint16
+	lpszProxyBypass *uint16
+}
+
+// globalFreeString returns the content of a string allocated by WinHTTP and frees it
+func globalFreeString(s *uint16) string {
+	if s == nil {
+		return ""
+	}
+	defer procGlobalFree.Call(uintptr(unsafe.Pointer(s))) //nolint:errcheck
+	return windows.UTF16PtrToString(s)
+}
+
+// getSystemProxyTransportFunc returns a proxy function for a http.Transport
+// using the proxy settings of the system: the static proxy, the PAC file or
+// the proxy auto-detection (WPAD) configured in the Internet Options.
+// It returns nil when the system doesn't define any proxy.
+func getSystemProxyTransportFunc() func(*http.Request) (*url.URL, error) {
+	var ieConfig winHTTPCurrentUserIEProxyConfig
+	if r, _, err := procWinHTTPGetIEProxyConfigForCurrentUser.Call(uintptr(unsafe.Pointer(&ieConfig))); r == 0 {
+		log.Warnf("Could not read the system proxy settings, not using them: %s", err)
+		return nil
+	}
+	autoDetect := ieConfig.fAutoDetect != 0
+	autoConfigURL := globalFreeString(ieConfig.lpszAutoConfigURL)
+	proxy := globalFreeString(ieConfig.lpszProxy)
+	proxyBypass := globalFreeString(ieConfig.lpszProxyBypass)
+
+	if autoDetect || autoConfigURL != "" {
+		log.Infof("Using the system proxy auto-configuration (auto-detect: %t, PAC file: '%s')", autoDetect, autoConfigURL)
+		return newSystemProxyTransportFunc(func(u *url.URL) (*url.URL, error) {
+			list, bypass, err := getProxyForURL(u, autoDetect, autoConfigURL)
+			if err != nil {
+				// fall back on the static proxy settings, if any
+				if proxy == "" {
+					return nil, err
+				}
+				log.Debugf("Could not evaluate the proxy auto-configuration for '%s://%s', using the static system proxy: %s", u.Scheme, u.Host, err)
+				list, bypass = proxy, proxyBypass
+			}
+			if matchSystemProxyBypass(bypass, u.Host) {
+				return nil, nil
+			}
+			return parseSystemProxyList(list, u.Scheme)
+		})
+	}
+
+	if proxy != "" {
+		log.Infof("Using the system proxy '%s'", proxy)
+		return newSystemProxyTransportFunc(func(u *url.URL) (*url.URL, error) {
+			if matchSystemProxyBypass(proxyBypass, u.Host) {
+				return nil, nil
+			}
+			return parseSystemProxyList(proxy, u.Scheme)
+		})
+	}
+
+	return nil
+}
+
+// getProxyForURL evaluates the proxy auto-configuration for u with WinHTTP,
+// returning the resulting proxy and bypass lists.
+func getProxyForURL(u *url.URL, autoDetect bool, autoConfigURL string) (string, string, error) {
+	// WINHTTP_ACCESS_TYPE_NO_PROXY, the session only evaluates the PAC files
+	agent, _ := windows.UTF16PtrFromString("datadog-agent")
+	session, _, err := procWinHTTPOpen.Call(uintptr(unsafe.Pointer(agent)), winHTTPAccessTypeNoProxy, 0, 0, 0)
+	if session == 0 {
+		return "", "", fmt.Errorf("could not open a WinHTTP session: %s", err)
+	}
+	defer procWinHTTPCloseHandle.Call(session) //nolint:errcheck
+
+	options := winHTTPAutoProxyOptions{
+		// the credentials of the agent are needed by some PAC servers
+		fAutoLogonIfChallenged: 1,
+	}
+	if autoConfigURL != "" {
+		options.dwFlags |= winHTTPAutoProxyConfigURL
+		options.lpszAutoConfigURL, err = windows.UTF16PtrFromString(autoConfigURL)
+		if err != nil {
+			return "", "", err
+		}
+	}
+	if autoDetect {
+		options.dwFlags |= winHTTPAutoProxyAutoDetect
+		options.dwAutoDetectFlags = winHTTPAutoDetectTypeDHCP | winHTTPAutoDetectTypeDNSA
+	}
+
+	target, err := windows.UTF16PtrFromString(u.String())
+	if err != nil {
+		return "", "", err
+	}
+
+	var info winHTTPProxyInfo
+	r, _, err := procWinHTTPGetProxyForURL.Call(session, uintptr(unsafe.Pointer(target)), uintptr(unsafe.Pointer(&options)), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return "", "", fmt.Errorf("could not evaluate the proxy auto-configuration: %s", err)
+	}
+	list := globalFreeString(info.lpszProxy)
+	bypass := globalFreeString(info.lpszProxyBypass)
+	if info.dwAccessType != winHTTPAccessTypeNamedProxy {
+		return "", "", nil
+	}
+	return list, bypass, nil
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/fips"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"golang.org/x/net/http/httpproxy"
 )
@@ -30,8 +31,12 @@ var (
 	// NoProxyChanged map containing URL's whos proxy behavior will change in the future
 	NoProxyChanged = make(map[string]bool)
 
-	// NoProxyMapMutex Lock for all no proxy maps
+	// NoProxyMapMutex Lock for all no proxy maps and egressRefusedWarningMap
 	NoProxyMapMutex = sync.Mutex{}
+
+	// the system proxy settings are read once, the transports share the proxies resolved from them
+	systemProxyOnce sync.Once
+	systemProxyFunc func(*http.Request) (*url.URL, error)
 )
 
 func logSafeURLString(url *url.URL) string {
@@ -60,6 +65,8 @@ func CreateHTTPTransport() *http.Transport {
 		tlsConfig.MinVersion = tls.VersionTLS12
 	}
 
+	fips.ConfigureTLS(tlsConfig)
+
 	// Most of the following timeouts are a copy of Golang http.DefaultTransport
 	// They are mostly used to act as safeguards in case we forget to add a general
 	// timeout to our http clients.  Setting DialContext and TLSClientConfig has the
@@ -83,13 +90,32 @@ func CreateHTTPTransport() *http.Transport {
 		ExpectContinueTimeout: 1 * time.Second,
 	}
 
-	if proxies := config.GetProxies(); proxies != nil {
-		transport.Proxy = GetProxyTransportFunc(proxies)
+	if proxyFunc := GetAgentProxyTransportFunc(); proxyFunc != nil {
+		transport.Proxy = proxyFunc
 	}
 
+	GetEgressValidator().Apply(transport)
+
 	return transport
 }
 
+// GetAgentProxyTransportFunc returns the proxy function for a http.Transport
+// following the proxy settings of the Agent configuration or, when
+// use_system_proxy is set and the Agent doesn't configure any proxy, the ones
+// of the system. It returns nil when no proxy is configured.
+func GetAgentProxyTransportFunc() func(*http.Request) (*url.URL, error) {
+	if proxies := config.GetProxies(); proxies != nil {
+		return GetProxyTransportFunc(proxies)
+	}
+	if config.Datadog.GetBool("use_system_proxy") {
+		systemProxyOnce.Do(func() {
+			systemProxyFunc = getSystemProxyTransportFunc()
+		})
+		return systemProxyFunc
+	}
+	return nil
+}
+
 // GetProxyTransportFunc return a proxy function for a http.Transport that
 // would return the right proxy depending on the configuration.
 func GetProxyTransportFunc(p *config.Proxy) func(*http.Request) (*url.URL, error) {
@@ -0,0 +1,229 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ibm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/cachedfetch"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+// declare these as vars not const to ease testing
+var (
+	metadataURL = "http://169.254.169.254"
+	timeout     = 300 * time.Millisecond
+
+	// CloudProviderName contains the inventory name of for IBM Cloud
+	CloudProviderName = "IBM"
+)
+
+const (
+	apiVersion = "2021-10-12"
+	// the token is only used to fetch the instance metadata once, it doesn't need to live long
+	tokenLifetimeSeconds = 300
+)
+
+type reference struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// instanceMetadata is the subset of the VPC instance metadata used by the agent
+// See https://cloud.ibm.com/docs/vpc?topic=vpc-imd-about
+type instanceMetadata struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Zone          reference `json:"zone"`
+	Profile       reference `json:"profile"`
+	VPC           reference `json:"vpc"`
+	ResourceGroup reference `json:"resource_group"`
+}
+
+// IsRunningOn returns true if the agent is running on IBM Cloud
+func IsRunningOn(ctx context.Context) bool {
+	if _, err := GetInstanceID(ctx); err == nil {
+		return true
+	}
+	return false
+}
+
+var instanceFetcher = cachedfetch.Fetcher{
+	Name: "IBM Cloud instance metadata",
+	Attempt: func(ctx context.Context) (interface{}, error) {
+		if !config.IsCloudProviderEnabled(CloudProviderName) {
+			return nil, fmt.Errorf("cloud provider is disabled by configuration")
+		}
+
+		token, err := getToken(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get an IBM Cloud metadata token: %s", err)
+		}
+
+		res, err := httputils.Get(ctx, metadataURL+"/metadata/v1/instance?version="+apiVersion,
+			map[string]string{"Authorization": "Bearer " + token}, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch IBM Cloud Metadata API, %s", err)
+		}
+
+		var metadata instanceMetadata
+		if err := json.Unmarshal([]byte(res), &metadata); err != nil {
+			return nil, fmt.Errorf("unable to parse IBM Cloud instance metadata: %s", err)
+		}
+		if metadata.ID == "" {
+			return nil, fmt.Errorf("IBM Cloud instance metadata has no instance ID")
+		}
+		return &metadata, nil
+	},
+}
+
+func getInstanceMetadata(ctx context.Context) (*instanceMetadata, error) {
+	metadata, err := instanceFetcher.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.(*instanceMetadata), nil
+}
+
+// GetInstanceID fetches the instance ID for current host from the IBM Cloud metadata API
+func GetInstanceID(ctx context.Context) (string, error) {
+	metadata, err := getInstanceMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(metadata.ID) > config.Datadog.GetInt("metadata_endpoints_max_hostname_size") {
+		return "", fmt.Errorf("IBM Cloud instance ID is longer than %d", config.Datadog.GetInt("metadata_endpoints_max_hostname_size"))
+	}
+	return metadata.ID, nil
+}
+
+// GetHostAlias returns the instance ID from the IBM Cloud metadata API
+func GetHostAlias(ctx context.Context) (string, error) {
+	return GetInstanceID(ctx)
+}
+
+// GetTags returns the region, zone, profile, VPC and resource group of the instance.
+// The user tags of IBM Cloud aren't exposed by the metadata API.
+func GetTags(ctx context.Context) ([]string, error) {
+	metadata, err := getInstanceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for name, value := range map[string]string{
+		"region":         regionFromZone(metadata.Zone.Name),
+		"zone":           metadata.Zone.Name,
+		"instance-type":  metadata.Profile.Name,
+		"vpc":            metadata.VPC.Name,
+		"resource-group": metadata.ResourceGroup.Name,
+	} {
+		if value != "" {
+			tags = append(tags, name+":"+value)
+		}
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// GetClusterName returns the name of the IBM Cloud Kubernetes Service cluster of the instance by parsing its name.
+// It expects the instance name of the worker nodes to have the format kube-cluster-id-cluster-name-worker-pool-index,
+// where the worker pool name, e.g. default, has no dash.
+func GetClusterName(ctx context.Context) (string, error) {
+	metadata, err := getInstanceMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	splitAll := strings.Split(metadata.Name, "-")
+	if len(splitAll) < 5 || splitAll[0] != "kube" || !isNumeric(splitAll[len(splitAll)-1]) {
+		return "", fmt.Errorf("cannot parse the clustername from instance name: %s", metadata.Name)
+	}
+
+	return strings.Join(splitAll[2:len(splitAll)-2], "-"), nil
+}
+
+// GetNTPHosts returns the NTP hosts for IBM Cloud if it is detected as the cloud provider, otherwise an empty array.
+// See https://cloud.ibm.com/docs/vpc?topic=vpc-time-servers
+func GetNTPHosts(ctx context.Context) []string {
+	if IsRunningOn(ctx) {
+		return []string{"161.26.0.6"}
+	}
+
+	return nil
+}
+
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// regionFromZone returns the region of a zone, e.g. "us-south" for "us-south-1"
+func regionFromZone(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// getToken requests an instance identity access token, the metadata API rejects the requests without it
+func getToken(ctx context.Context) (string, error) {
+	client := http.Client{
+		Transport: httputils.CreateHTTPTransport(),
+		Timeout:   timeout,
+	}
+
+	body := fmt.Sprintf(`{"expires_in": %d}`, tokenLifetimeSeconds)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, metadataURL+"/instance_identity/v1/token?version="+apiVersion, bytes.NewBufferString(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add("Metadata-Flavor", "ibm")
+	req.Header.Add("Content-Type", "application/json")
+
+	res, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status code %d trying to PUT %s", res.StatusCode, req.URL)
+	}
+
+	all, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(all, &token); err != nil {
+		return "", err
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("empty access token")
+	}
+	return token.AccessToken, nil
+}
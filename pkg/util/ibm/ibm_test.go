@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package ibm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+const instanceJSON = `{
+  "id": "0717_1e09281b-f177-46fb-baf1-bc152f8c8b4a",
+  "name": "my-instance",
+  "profile": {"name": "bx2-2x8"},
+  "resource_group": {"id": "fee82deba12e4c0fb69c3b09d1f12345", "name": "default"},
+  "vpc": {"id": "r006-4727d842-f94f-4a2d-824a-9bc9b02c523b", "name": "my-vpc"},
+  "zone": {"name": "us-south-1"}
+}`
+
+func setupServer(t *testing.T) {
+	setupServerWithInstance(t, instanceJSON)
+}
+
+func setupServerWithInstance(t *testing.T, instance string) {
+	holdValue := config.Datadog.Get("cloud_provider_metadata")
+	t.Cleanup(func() { config.Datadog.Set("cloud_provider_metadata", holdValue) })
+	config.Datadog.Set("cloud_provider_metadata", []string{"ibm"})
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/instance_identity/v1/token" && r.Header.Get("Metadata-Flavor") == "ibm":
+			io.WriteString(w, `{"access_token": "secret"}`)
+		case r.Method == http.MethodGet && r.URL.Path == "/metadata/v1/instance" && r.Header.Get("Authorization") == "Bearer secret":
+			io.WriteString(w, instance)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	t.Cleanup(ts.Close)
+
+	metadataURL = ts.URL
+	instanceFetcher.Reset()
+	t.Cleanup(instanceFetcher.Reset)
+}
+
+func TestGetInstanceID(t *testing.T) {
+	setupServer(t)
+
+	val, err := GetInstanceID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "0717_1e09281b-f177-46fb-baf1-bc152f8c8b4a", val)
+	assert.True(t, IsRunningOn(context.Background()))
+}
+
+func TestGetInstanceIDDisabled(t *testing.T) {
+	setupServer(t)
+	config.Datadog.Set("cloud_provider_metadata", []string{"aws"})
+
+	_, err := GetInstanceID(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGetTags(t *testing.T) {
+	setupServer(t)
+
+	tags, err := GetTags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"instance-type:bx2-2x8",
+		"region:us-south",
+		"resource-group:default",
+		"vpc:my-vpc",
+		"zone:us-south-1",
+	}, tags)
+}
+
+func TestGetClusterName(t *testing.T) {
+	tests := []struct {
+		name         string
+		instanceName string
+		want         string
+		wantErr      bool
+	}{
+		{
+			name:         "worker node",
+			instanceName: "kube-c5ka8lsd0b2kbulf8d30-my-cluster-default-00000146",
+			want:         "my-cluster",
+		},
+		{
+			name:         "not a worker node",
+			instanceName: "my-instance",
+			wantErr:      true,
+		},
+		{
+			name:         "no worker index",
+			instanceName: "kube-c5ka8lsd0b2kbulf8d30-my-cluster-default",
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setupServerWithInstance(t, strings.Replace(instanceJSON, `"my-instance"`, `"`+tt.instanceName+`"`, 1))
+
+			got, err := GetClusterName(context.Background())
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetNTPHosts(t *testing.T) {
+	setupServer(t)
+
+	assert.Equal(t, []string{"161.26.0.6"}, GetNTPHosts(context.Background()))
+}
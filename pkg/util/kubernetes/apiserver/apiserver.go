@@ -529,6 +529,24 @@ func (c *APIClient) NodeLabels(nodeName string) (map[string]string, error) {
 	return node.Labels, nil
 }
 
+// NodeTaints is used to fetch the taints of a given node, formatted as `key=value:effect`.
+func (c *APIClient) NodeTaints(nodeName string) ([]string, error) {
+	node, err := c.Cl.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return formatTaints(node.Spec.Taints), nil
+}
+
+// formatTaints formats taints as `key=value:effect`, or `key:effect` for the taints without value
+func formatTaints(taints []v1.Taint) []string {
+	formatted := make([]string, 0, len(taints))
+	for i := range taints {
+		formatted = append(formatted, taints[i].ToString())
+	}
+	return formatted
+}
+
 // GetNodeForPod retrieves a pod and returns the name of the node it is scheduled on
 func (c *APIClient) GetNodeForPod(ctx context.Context, namespace, podName string) (string, error) {
 	pod, err := c.Cl.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
@@ -64,6 +64,12 @@ func GetNodeLabels(_ *APIClient, nodeName string) (map[string]string, error) {
 	return nil, nil
 }
 
+// GetNodeTaints retrieves the taints of the queried node from the cache of the shared informer.
+func GetNodeTaints(_ *APIClient, nodeName string) ([]string, error) {
+	log.Errorf("GetNodeTaints not implemented %s", ErrNotCompiled.Error())
+	return nil, nil
+}
+
 // GetKubeClient returns a Kubernetes client.
 func GetKubeClient(timeout time.Duration) (kubernetes.Interface, error) {
 	return nil, ErrNotCompiled
@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package apiserver
+
+import (
+	"time"
+
+	"github.com/DataDog/watermarkpodautoscaler/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/custommetrics"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/autoscalers"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// autoscalerThresholdCrossedEvent is the reason of the events emitted when an external metric crosses the threshold of an autoscaler
+const autoscalerThresholdCrossedEvent = "ExternalMetricThresholdCrossed"
+
+// auditExternalMetrics emits an audit event for each valid external metric whose value crossed
+// the threshold of the autoscaler referencing it since the previous refresh.
+func (h *AutoscalersController) auditExternalMetrics(emList map[string]custommetrics.ExternalMetricValue) {
+	if h.auditor == nil {
+		return
+	}
+	now := time.Now()
+	for _, em := range emList {
+		if !em.Valid {
+			continue
+		}
+		obj, threshold, found := h.getAutoscalerThreshold(em)
+		if !found {
+			continue
+		}
+		event, crossed := h.auditor.Audit(em, threshold, now)
+		if !crossed {
+			continue
+		}
+		log.Infof("Autoscaler audit for %s %s/%s: %s", em.Ref.Type, em.Ref.Namespace, em.Ref.Name, event)
+		if h.EventRecorder != nil {
+			h.EventRecorder.Event(obj, corev1.EventTypeNormal, autoscalerThresholdCrossedEvent, event.String())
+		}
+	}
+}
+
+// getAutoscalerThreshold returns the autoscaler referencing the external metric and the threshold it defines for it.
+func (h *AutoscalersController) getAutoscalerThreshold(em custommetrics.ExternalMetricValue) (runtime.Object, autoscalers.Threshold, bool) {
+	switch em.Ref.Type {
+	case "horizontal":
+		if h.autoscalersLister == nil {
+			return nil, autoscalers.Threshold{}, false
+		}
+		hpa, err := h.autoscalersLister.HorizontalPodAutoscalers(em.Ref.Namespace).Get(em.Ref.Name)
+		if err != nil {
+			log.Debugf("Could not retrieve the HorizontalPodAutoscaler %s/%s to audit the external metric %s: %v", em.Ref.Namespace, em.Ref.Name, em.MetricName, err)
+			return nil, autoscalers.Threshold{}, false
+		}
+		threshold, found := autoscalers.HPAThreshold(hpa, em)
+		return hpa, threshold, found
+	case "watermark":
+		if h.wpaLister == nil {
+			return nil, autoscalers.Threshold{}, false
+		}
+		obj, err := h.wpaLister.ByNamespace(em.Ref.Namespace).Get(em.Ref.Name)
+		if err != nil {
+			log.Debugf("Could not retrieve the WatermarkPodAutoscaler %s/%s to audit the external metric %s: %v", em.Ref.Namespace, em.Ref.Name, em.MetricName, err)
+			return nil, autoscalers.Threshold{}, false
+		}
+		wpa := &v1alpha1.WatermarkPodAutoscaler{}
+		if err := UnstructuredIntoWPA(obj, wpa); err != nil {
+			log.Debugf("Could not cast the WatermarkPodAutoscaler %s/%s to audit the external metric %s: %v", em.Ref.Namespace, em.Ref.Name, em.MetricName, err)
+			return nil, autoscalers.Threshold{}, false
+		}
+		threshold, found := autoscalers.WPAThreshold(wpa, em)
+		return wpa, threshold, found
+	}
+	return nil, autoscalers.Threshold{}, false
+}
@@ -39,6 +39,10 @@ func NewAutoscalersController(client kubernetes.Interface, eventRecorder record.
 
 	h.toStore.data = make(map[string]custommetrics.ExternalMetricValue)
 
+	if config.Datadog.GetBool("external_metrics_provider.audit_events") {
+		h.auditor = autoscalers.NewAuditor()
+	}
+
 	gcPeriodSeconds := config.Datadog.GetInt("hpa_watcher_gc_period")
 	refreshPeriod := config.Datadog.GetInt("external_metrics_provider.refresh_period")
 
@@ -141,6 +145,9 @@ func (h *AutoscalersController) deleteFromLocalStore(toDelete []custommetrics.Ex
 		key := custommetrics.ExternalMetricValueKeyFunc(d)
 		delete(h.toStore.data, key)
 	}
+	if h.auditor != nil {
+		h.auditor.Forget(toDelete)
+	}
 }
 
 func (h *AutoscalersController) handleErr(err error, key interface{}) {
@@ -203,7 +210,9 @@ func (h *AutoscalersController) updateExternalMetrics() {
 	err = h.store.SetExternalMetricValues(updated)
 	if err != nil {
 		log.Errorf("Not able to store the updated metrics in the Global Store: %v", err)
+		return
 	}
+	h.auditExternalMetrics(updated)
 }
 
 // processingLoop is a go routine that schedules the garbage collection and the refreshing of external metrics
@@ -62,6 +62,9 @@ type AutoscalersController struct {
 
 	EventRecorder record.EventRecorder
 
+	// auditor reports the external metrics crossing the thresholds of the autoscalers, nil if disabled
+	auditor *autoscalers.Auditor
+
 	// used in unit tests to wait until hpas are synced
 	autoscalers chan interface{}
 
@@ -328,6 +328,22 @@ func GetNodeLabels(as *APIClient, nodeName string) (map[string]string, error) {
 	return node.Labels, nil
 }
 
+// GetNodeTaints retrieves the taints of the queried node from the cache of the shared informer,
+// formatted as `key=value:effect`.
+func GetNodeTaints(as *APIClient, nodeName string) ([]string, error) {
+	if !config.Datadog.GetBool("kubernetes_collect_metadata_tags") {
+		return nil, log.Errorf("Metadata collection is disabled on the Cluster Agent")
+	}
+	node, err := as.InformerFactory.Core().V1().Nodes().Lister().Get(nodeName)
+	if err != nil {
+		return nil, err
+	}
+	if node == nil {
+		return nil, fmt.Errorf("cannot get node %s from the informer's cache", nodeName)
+	}
+	return formatTaints(node.Spec.Taints), nil
+}
+
 // GetNamespaceLabels retrieves the labels of the queried namespace from the cache of the shared informer.
 func GetNamespaceLabels(nsName string) (map[string]string, error) {
 	if !config.Datadog.GetBool("kubernetes_collect_metadata_tags") {
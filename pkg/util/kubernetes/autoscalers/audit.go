@@ -0,0 +1,213 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2017-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package autoscalers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/custommetrics"
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/watermarkpodautoscaler/api/v1alpha1"
+)
+
+// hpaTolerance is the default tolerance of the Horizontal Pod Autoscaler controller,
+// the ratio between a value and its target needs to be outside of it to trigger a scaling.
+const hpaTolerance = 0.1
+
+// ThresholdState is the position of the value of an external metric relative to the thresholds of an autoscaler.
+type ThresholdState string
+
+// Threshold states
+const (
+	ThresholdBelow  ThresholdState = "below"
+	ThresholdWithin ThresholdState = "within"
+	ThresholdAbove  ThresholdState = "above"
+)
+
+// Threshold is the range in which the value of an external metric doesn't trigger a scaling.
+type Threshold struct {
+	Low  float64
+	High float64
+	// Replicas is set when the value is averaged over the replicas of the target before being compared.
+	Replicas int32
+}
+
+// State returns the position of the value relative to the threshold.
+func (t Threshold) State(value float64) ThresholdState {
+	if t.Replicas > 0 {
+		value = value / float64(t.Replicas)
+	}
+	switch {
+	case value < t.Low:
+		return ThresholdBelow
+	case value > t.High:
+		return ThresholdAbove
+	default:
+		return ThresholdWithin
+	}
+}
+
+// HPAThreshold returns the threshold of the external metric in the spec of the HorizontalPodAutoscaler,
+// returns false if the HorizontalPodAutoscaler doesn't target the metric.
+func HPAThreshold(hpa *autoscalingv2.HorizontalPodAutoscaler, em custommetrics.ExternalMetricValue) (Threshold, bool) {
+	for _, metricSpec := range hpa.Spec.Metrics {
+		if metricSpec.Type != autoscalingv2.ExternalMetricSourceType || metricSpec.External == nil {
+			continue
+		}
+		if !matchesExternalMetric(metricSpec.External.MetricName, metricSpec.External.MetricSelector, em) {
+			continue
+		}
+		switch {
+		case metricSpec.External.TargetValue != nil:
+			target := metricSpec.External.TargetValue.AsApproximateFloat64()
+			return Threshold{Low: target * (1 - hpaTolerance), High: target * (1 + hpaTolerance)}, true
+		case metricSpec.External.TargetAverageValue != nil:
+			target := metricSpec.External.TargetAverageValue.AsApproximateFloat64()
+			return Threshold{Low: target * (1 - hpaTolerance), High: target * (1 + hpaTolerance), Replicas: replicasOrOne(hpa.Status.CurrentReplicas)}, true
+		}
+	}
+	return Threshold{}, false
+}
+
+// WPAThreshold returns the watermarks of the external metric in the spec of the WatermarkPodAutoscaler,
+// returns false if the WatermarkPodAutoscaler doesn't target the metric.
+func WPAThreshold(wpa *v1alpha1.WatermarkPodAutoscaler, em custommetrics.ExternalMetricValue) (Threshold, bool) {
+	tolerance := wpa.Spec.Tolerance.AsApproximateFloat64()
+	for _, metricSpec := range wpa.Spec.Metrics {
+		if metricSpec.Type != v1alpha1.ExternalMetricSourceType || metricSpec.External == nil {
+			continue
+		}
+		if !matchesExternalMetric(metricSpec.External.MetricName, metricSpec.External.MetricSelector, em) {
+			continue
+		}
+		if metricSpec.External.LowWatermark == nil || metricSpec.External.HighWatermark == nil {
+			return Threshold{}, false
+		}
+		threshold := Threshold{
+			Low:  metricSpec.External.LowWatermark.AsApproximateFloat64() * (1 - tolerance),
+			High: metricSpec.External.HighWatermark.AsApproximateFloat64() * (1 + tolerance),
+		}
+		if wpa.Spec.Algorithm == "average" {
+			threshold.Replicas = replicasOrOne(wpa.Status.CurrentReplicas)
+		}
+		return threshold, true
+	}
+	return Threshold{}, false
+}
+
+func matchesExternalMetric(metricName string, selector *metav1.LabelSelector, em custommetrics.ExternalMetricValue) bool {
+	if metricName != em.MetricName {
+		return false
+	}
+	var matchLabels map[string]string
+	if selector != nil {
+		matchLabels = selector.MatchLabels
+	}
+	if len(matchLabels) != len(em.Labels) {
+		return false
+	}
+	for k, v := range matchLabels {
+		if em.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+func replicasOrOne(replicas int32) int32 {
+	if replicas < 1 {
+		return 1
+	}
+	return replicas
+}
+
+// ExternalMetricQuery returns the query used to get the value of the external metric from Datadog.
+func ExternalMetricQuery(em custommetrics.ExternalMetricValue) string {
+	return getKey(em.MetricName, em.Labels, config.Datadog.GetString("external_metrics.aggregator"), config.Datadog.GetInt("external_metrics_provider.rollup"))
+}
+
+// AuditEvent records the value of an external metric crossing the threshold of an autoscaler.
+type AuditEvent struct {
+	Autoscaler    custommetrics.ObjectReference
+	MetricName    string
+	Query         string
+	Value         float64
+	Threshold     Threshold
+	PreviousState ThresholdState
+	State         ThresholdState
+	// Freshness is the age of the value when it was evaluated.
+	Freshness time.Duration
+}
+
+// String returns a representation of the event that can be used in logs and Kubernetes events.
+func (e AuditEvent) String() string {
+	previous := e.PreviousState
+	if previous == "" {
+		previous = "unknown"
+	}
+	msg := fmt.Sprintf("External metric %s is now %s its threshold (was %s): value=%v low=%v high=%v query=%q freshness=%s",
+		e.MetricName, e.State, previous, e.Value, e.Threshold.Low, e.Threshold.High, e.Query, e.Freshness)
+	if e.Threshold.Replicas > 0 {
+		msg += fmt.Sprintf(" replicas=%d", e.Threshold.Replicas)
+	}
+	return msg
+}
+
+// Auditor keeps track of the threshold state of the external metrics to report when their values cross
+// the thresholds of the autoscalers, so that scaling decisions can be explained without debug logs.
+type Auditor struct {
+	mu     sync.Mutex
+	states map[string]ThresholdState
+}
+
+// NewAuditor returns a new Auditor
+func NewAuditor() *Auditor {
+	return &Auditor{
+		states: make(map[string]ThresholdState),
+	}
+}
+
+// Audit returns an event if the value of the external metric crossed the threshold since the previous call,
+// or if the first value seen for the metric is outside of the threshold.
+func (a *Auditor) Audit(em custommetrics.ExternalMetricValue, threshold Threshold, now time.Time) (AuditEvent, bool) {
+	id := custommetrics.ExternalMetricValueKeyFunc(em)
+	state := threshold.State(em.Value)
+
+	a.mu.Lock()
+	previous, found := a.states[id]
+	a.states[id] = state
+	a.mu.Unlock()
+
+	if previous == state || (!found && state == ThresholdWithin) {
+		return AuditEvent{}, false
+	}
+	return AuditEvent{
+		Autoscaler:    em.Ref,
+		MetricName:    em.MetricName,
+		Query:         ExternalMetricQuery(em),
+		Value:         em.Value,
+		Threshold:     threshold,
+		PreviousState: previous,
+		State:         state,
+		Freshness:     now.Sub(time.Unix(em.Timestamp, 0)),
+	}, true
+}
+
+// Forget removes the state of the external metrics that are not evaluated anymore.
+func (a *Auditor) Forget(emList []custommetrics.ExternalMetricValue) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, em := range emList {
+		delete(a.states, custommetrics.ExternalMetricValueKeyFunc(em))
+	}
+}
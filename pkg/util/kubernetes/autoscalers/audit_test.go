@@ -0,0 +1,123 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2017-present Datadog, Inc.
+
+// +build kubeapiserver
+
+package autoscalers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/custommetrics"
+	"github.com/DataDog/watermarkpodautoscaler/api/v1alpha1"
+)
+
+func TestHPAThreshold(t *testing.T) {
+	em := custommetrics.ExternalMetricValue{MetricName: "requests", Labels: map[string]string{"foo": "bar"}}
+
+	spec := makeSpec("requests", map[string]string{"foo": "bar"})
+	target := resource.MustParse("100")
+	spec.Metrics[0].External.TargetValue = &target
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{Spec: spec}
+
+	threshold, found := HPAThreshold(hpa, em)
+	require.True(t, found)
+	assert.InDelta(t, 90, threshold.Low, 0.001)
+	assert.InDelta(t, 110, threshold.High, 0.001)
+	assert.Equal(t, ThresholdBelow, threshold.State(80))
+	assert.Equal(t, ThresholdWithin, threshold.State(105))
+	assert.Equal(t, ThresholdAbove, threshold.State(120))
+
+	// the value is averaged over the replicas
+	spec.Metrics[0].External.TargetValue = nil
+	spec.Metrics[0].External.TargetAverageValue = &target
+	hpa = &autoscalingv2.HorizontalPodAutoscaler{Spec: spec, Status: autoscalingv2.HorizontalPodAutoscalerStatus{CurrentReplicas: 3}}
+	threshold, found = HPAThreshold(hpa, em)
+	require.True(t, found)
+	assert.Equal(t, int32(3), threshold.Replicas)
+	assert.Equal(t, ThresholdWithin, threshold.State(300))
+	assert.Equal(t, ThresholdAbove, threshold.State(400))
+
+	// the labels don't match
+	_, found = HPAThreshold(hpa, custommetrics.ExternalMetricValue{MetricName: "requests"})
+	assert.False(t, found)
+}
+
+func TestWPAThreshold(t *testing.T) {
+	em := custommetrics.ExternalMetricValue{MetricName: "requests", Labels: map[string]string{"foo": "bar"}}
+
+	spec := makeWPASpec("requests", map[string]string{"foo": "bar"})
+	low, high := resource.MustParse("50"), resource.MustParse("100")
+	spec.Metrics[0].External.LowWatermark = &low
+	spec.Metrics[0].External.HighWatermark = &high
+	spec.Tolerance = resource.MustParse("0.1")
+	wpa := &v1alpha1.WatermarkPodAutoscaler{Spec: spec}
+
+	threshold, found := WPAThreshold(wpa, em)
+	require.True(t, found)
+	assert.InDelta(t, 45, threshold.Low, 0.001)
+	assert.InDelta(t, 110, threshold.High, 0.001)
+	assert.Equal(t, int32(0), threshold.Replicas)
+	assert.Equal(t, ThresholdBelow, threshold.State(40))
+	assert.Equal(t, ThresholdWithin, threshold.State(75))
+	assert.Equal(t, ThresholdAbove, threshold.State(111))
+
+	_, found = WPAThreshold(wpa, custommetrics.ExternalMetricValue{MetricName: "errors", Labels: map[string]string{"foo": "bar"}})
+	assert.False(t, found)
+}
+
+func TestAuditor(t *testing.T) {
+	auditor := NewAuditor()
+	threshold := Threshold{Low: 90, High: 110}
+	now := time.Unix(1000, 0)
+	em := custommetrics.ExternalMetricValue{
+		MetricName: "requests",
+		Labels:     map[string]string{"foo": "bar"},
+		Ref:        custommetrics.ObjectReference{Type: "horizontal", Name: "hpa", Namespace: "default"},
+		Timestamp:  970,
+		Valid:      true,
+	}
+
+	// the first value within the threshold is not reported
+	em.Value = 100
+	_, crossed := auditor.Audit(em, threshold, now)
+	assert.False(t, crossed)
+
+	em.Value = 150
+	event, crossed := auditor.Audit(em, threshold, now)
+	require.True(t, crossed)
+	assert.Equal(t, ThresholdWithin, event.PreviousState)
+	assert.Equal(t, ThresholdAbove, event.State)
+	assert.Equal(t, 150.0, event.Value)
+	assert.Equal(t, 30*time.Second, event.Freshness)
+	assert.Equal(t, "avg:requests{foo:bar}.rollup(30)", event.Query)
+	assert.Equal(t, em.Ref, event.Autoscaler)
+
+	// staying above the threshold is not reported again
+	em.Value = 160
+	_, crossed = auditor.Audit(em, threshold, now)
+	assert.False(t, crossed)
+
+	em.Value = 100
+	event, crossed = auditor.Audit(em, threshold, now)
+	require.True(t, crossed)
+	assert.Equal(t, ThresholdAbove, event.PreviousState)
+	assert.Equal(t, ThresholdWithin, event.State)
+
+	// the first value outside of the threshold is reported
+	auditor.Forget([]custommetrics.ExternalMetricValue{em})
+	em.Value = 10
+	event, crossed = auditor.Audit(em, threshold, now)
+	require.True(t, crossed)
+	assert.Equal(t, ThresholdState(""), event.PreviousState)
+	assert.Equal(t, ThresholdBelow, event.State)
+	assert.Contains(t, event.String(), "is now below its threshold (was unknown)")
+}
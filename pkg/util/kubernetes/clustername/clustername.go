@@ -19,8 +19,10 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/clusteragent"
 	"github.com/DataDog/datadog-agent/pkg/util/ec2"
 	"github.com/DataDog/datadog-agent/pkg/util/gce"
+	"github.com/DataDog/datadog-agent/pkg/util/ibm"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/hostinfo"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/oracle"
 )
 
 const (
@@ -59,9 +61,11 @@ var defaultClusterNameData *clusterNameData
 func init() {
 	defaultClusterNameData = newClusterNameData()
 	ProviderCatalog = map[string]Provider{
-		"gce":   gce.GetClusterName,
-		"azure": azure.GetClusterName,
-		"ec2":   ec2.GetClusterName,
+		"gce":    gce.GetClusterName,
+		"azure":  azure.GetClusterName,
+		"ec2":    ec2.GetClusterName,
+		"oracle": oracle.GetClusterName,
+		"ibm":    ibm.GetClusterName,
 	}
 }
 
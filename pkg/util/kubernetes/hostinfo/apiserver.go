@@ -28,3 +28,14 @@ func apiserverNodeLabels(nodeName string) (map[string]string, error) {
 	}
 	return client.NodeLabels(nodeName)
 }
+
+func apiserverNodeTaints(nodeName string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiserverTimeout)
+	defer cancel()
+
+	client, err := apiserver.WaitForAPIClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.NodeTaints(nodeName)
+}
@@ -14,6 +14,11 @@ func GetNodeLabels(ctx context.Context) (map[string]string, error) {
 	return nil, nil
 }
 
+// GetNodeTaints returns node taints for this host
+func GetNodeTaints(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 // GetNodeClusterNameLabel returns clustername by fetching a node label
 func GetNodeClusterNameLabel(ctx context.Context) (string, error) {
 	return "", nil
@@ -7,9 +7,15 @@
 
 package hostinfo
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // GetTags gets the tags from the kubernetes apiserver
 func GetTags(ctx context.Context) ([]string, error) {
 	return nil, nil
 }
+
+// RefreshTagsOnChange does nothing, the node tags are not collected
+func RefreshTagsOnChange(ctx context.Context, interval time.Duration, onChange func()) {}
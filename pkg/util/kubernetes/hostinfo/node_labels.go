@@ -37,6 +37,28 @@ func GetNodeLabels(ctx context.Context) (map[string]string, error) {
 	return apiserverNodeLabels(nodeName)
 }
 
+// GetNodeTaints returns node taints for this host, formatted as `key=value:effect`
+func GetNodeTaints(ctx context.Context) ([]string, error) {
+	ku, err := kubelet.GetKubeUtil()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeName, err := ku.GetNodename(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Datadog.GetBool("cluster_agent.enabled") {
+		cl, err := clusteragent.GetClusterAgentClient()
+		if err != nil {
+			return nil, err
+		}
+		return cl.GetNodeTaints(nodeName)
+	}
+	return apiserverNodeTaints(nodeName)
+}
+
 // GetNodeClusterNameLabel returns clustername by fetching a node label
 func GetNodeClusterNameLabel(ctx context.Context) (string, error) {
 	nodeLabels, err := GetNodeLabels(ctx)
@@ -9,17 +9,24 @@ package hostinfo
 
 import (
 	"context"
+	"reflect"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/tagger/utils"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+const nodeTaintTagName = "kube_node_taint"
+
 // GetTags gets the tags from the kubernetes apiserver
 func GetTags(ctx context.Context) ([]string, error) {
 	labelsToTags := getLabelsToTags()
-	if len(labelsToTags) == 0 {
+	taintsAllowlist := config.Datadog.GetStringSlice("kubernetes_node_taints_tags_allowlist")
+	if len(labelsToTags) == 0 && len(taintsAllowlist) == 0 {
 		// Nothing to extract
 		return nil, nil
 	}
@@ -28,8 +35,20 @@ func GetTags(ctx context.Context) ([]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	tags := extractTags(nodeLabels, labelsToTags)
+
+	if len(taintsAllowlist) > 0 {
+		nodeTaints, err := GetNodeTaints(ctx)
+		if err != nil {
+			// Cluster Agents older than the node agent don't serve the node taints,
+			// the tags from the node labels are still reported
+			log.Debugf("Unable to get the node taints, no host tags are extracted from them: %v", err)
+		} else {
+			tags = append(tags, extractTaintTags(nodeTaints, taintsAllowlist)...)
+		}
+	}
 
-	return extractTags(nodeLabels, labelsToTags), nil
+	return tags, nil
 }
 
 func getDefaultLabelsToTags() map[string]string {
@@ -40,6 +59,9 @@ func getDefaultLabelsToTags() map[string]string {
 
 func getLabelsToTags() map[string]string {
 	labelsToTags := getDefaultLabelsToTags()
+	for _, label := range config.Datadog.GetStringSlice("kubernetes_node_labels_tags_allowlist") {
+		labelsToTags[strings.ToLower(label)] = allowlistTagName(label)
+	}
 	for k, v := range config.Datadog.GetStringMapString("kubernetes_node_labels_as_tags") {
 		// viper lower-cases map keys from yaml, but not from envvars
 		labelsToTags[strings.ToLower(k)] = v
@@ -48,6 +70,12 @@ func getLabelsToTags() map[string]string {
 	return labelsToTags
 }
 
+// allowlistTagName returns the name of the tag of an allowlisted label: the label name without
+// its prefix, e.g. `zone` for `topology.kubernetes.io/zone`
+func allowlistTagName(label string) string {
+	return strings.ToLower(label[strings.LastIndexByte(label, '/')+1:])
+}
+
 func extractTags(nodeLabels, labelsToTags map[string]string) []string {
 	tagList := utils.NewTagList()
 	labelsToTags, glob := utils.InitMetadataAsTags(labelsToTags)
@@ -59,3 +87,58 @@ func extractTags(nodeLabels, labelsToTags map[string]string) []string {
 	tags, _, _, _ := tagList.Compute()
 	return tags
 }
+
+// extractTaintTags returns a `kube_node_taint:key=value:effect` tag for each node taint whose key
+// is in the allowlist
+func extractTaintTags(nodeTaints, allowlist []string) []string {
+	allowed := make(map[string]struct{}, len(allowlist))
+	for _, key := range allowlist {
+		allowed[key] = struct{}{}
+	}
+
+	var tags []string
+	for _, taint := range nodeTaints {
+		key := taint
+		if idx := strings.IndexAny(taint, "=:"); idx != -1 {
+			key = taint[:idx]
+		}
+		if _, found := allowed[key]; found {
+			tags = append(tags, nodeTaintTagName+":"+taint)
+		}
+	}
+	return tags
+}
+
+// RefreshTagsOnChange gets the node tags every interval and calls onChange when they differ from
+// the previous ones, so that the host tags follow the updates of the node labels and taints.
+// It returns when ctx is cancelled.
+func RefreshTagsOnChange(ctx context.Context, interval time.Duration, onChange func()) {
+	getSortedTags := func() ([]string, error) {
+		tags, err := GetTags(ctx)
+		sort.Strings(tags)
+		return tags, err
+	}
+
+	lastTags, _ := getSortedTags()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		tags, err := getSortedTags()
+		if err != nil {
+			log.Debugf("Unable to refresh the node tags: %v", err)
+			continue
+		}
+		if reflect.DeepEqual(tags, lastTags) {
+			continue
+		}
+		log.Infof("The node tags changed from %v to %v, refreshing the host tags", lastTags, tags)
+		lastTags = tags
+		onChange()
+	}
+}
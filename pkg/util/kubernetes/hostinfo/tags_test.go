@@ -118,6 +118,7 @@ func TestGetLabelsToTags(t *testing.T) {
 	tests := []struct {
 		name               string
 		configLabelsAsTags map[string]string
+		configAllowlist    []string
 		expectLabelsAsTags map[string]string
 	}{
 		{
@@ -145,15 +146,51 @@ func TestGetLabelsToTags(t *testing.T) {
 				"a":                  "a",
 			},
 		},
+		{
+			name:            "allowlisted labels",
+			configAllowlist: []string{"topology.kubernetes.io/zone", "node.kubernetes.io/instance-type", "Pool"},
+			expectLabelsAsTags: map[string]string{
+				"kubernetes.io/role":               "kube_node_role",
+				"topology.kubernetes.io/zone":      "zone",
+				"node.kubernetes.io/instance-type": "instance-type",
+				"pool":                             "pool",
+			},
+		},
+		{
+			name: "labels as tags override the allowlist",
+			configLabelsAsTags: map[string]string{
+				"topology.kubernetes.io/zone": "kube_zone",
+			},
+			configAllowlist: []string{"topology.kubernetes.io/zone"},
+			expectLabelsAsTags: map[string]string{
+				"kubernetes.io/role":          "kube_node_role",
+				"topology.kubernetes.io/zone": "kube_zone",
+			},
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			config := config.Mock()
 			config.Set("kubernetes_node_labels_as_tags", test.configLabelsAsTags)
+			config.Set("kubernetes_node_labels_tags_allowlist", test.configAllowlist)
 
 			actuaLabelsAsTags := getLabelsToTags()
 			assert.Equal(t, test.expectLabelsAsTags, actuaLabelsAsTags)
 		})
 	}
 }
+
+func TestExtractTaintTags(t *testing.T) {
+	nodeTaints := []string{
+		"dedicated=gpu:NoSchedule",
+		"node.kubernetes.io/unschedulable:NoSchedule",
+		"spot=true:PreferNoSchedule",
+	}
+
+	assert.Nil(t, extractTaintTags(nodeTaints, nil))
+	assert.Equal(t, []string{
+		"kube_node_taint:dedicated=gpu:NoSchedule",
+		"kube_node_taint:node.kubernetes.io/unschedulable:NoSchedule",
+	}, extractTaintTags(nodeTaints, []string{"dedicated", "node.kubernetes.io/unschedulable", "spot=true"}))
+}
@@ -19,6 +19,18 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
+// containerStartTime returns the start time of a running or terminated
+// container, zero otherwise.
+func containerStartTime(status ContainerStatus) time.Time {
+	switch {
+	case status.State.Running != nil:
+		return status.State.Running.StartedAt
+	case status.State.Terminated != nil:
+		return status.State.Terminated.StartedAt
+	}
+	return time.Time{}
+}
+
 // ListContainers lists all non-excluded running containers, and retrieves their performance metrics
 func (ku *KubeUtil) ListContainers(ctx context.Context) ([]*containers.Container, error) {
 	pods, err := ku.GetLocalPodList(ctx)
@@ -35,7 +47,14 @@ func (ku *KubeUtil) ListContainers(ctx context.Context) ([]*containers.Container
 
 	for _, pod := range pods {
 		for _, c := range pod.Status.GetAllContainers() {
-			if ku.filter.IsExcluded(c.Name, c.Image, pod.Metadata.Namespace) {
+			filterInfo := containers.ContainerFilterInfo{
+				Name:      c.Name,
+				Image:     c.Image,
+				Namespace: pod.Metadata.Namespace,
+				Labels:    pod.Metadata.Labels,
+				StartedAt: containerStartTime(c),
+			}
+			if ku.filter.IsContainerExcluded(filterInfo) {
 				continue
 			}
 			container, err := parseContainerInPod(c, pod)
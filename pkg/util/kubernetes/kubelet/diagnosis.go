@@ -9,11 +9,18 @@
 package kubelet
 
 import (
+	"errors"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 func init() {
 	diagnosis.Register("Kubelet availability", diagnose)
+	diagnosis.RegisterWhen("Kubelet TLS verification", diagnoseTLSVerification, func() bool {
+		return config.IsFeaturePresent(config.Kubernetes)
+	})
 }
 
 // diagnose the API server availability
@@ -21,3 +28,23 @@ func diagnose() error {
 	_, err := GetKubeUtil()
 	return err
 }
+
+// diagnoseTLSVerification checks that the agent reaches the kubelet over HTTPS and verifies its certificate
+func diagnoseTLSVerification() error {
+	ku, err := GetKubeUtil()
+	if err != nil {
+		return err
+	}
+	return checkTLSVerification(ku.kubeletClient.config)
+}
+
+func checkTLSVerification(config kubeletClientConfig) error {
+	if config.scheme != "https" {
+		return errors.New("the kubelet is reached over plain HTTP")
+	}
+	if !config.tlsVerify {
+		return errors.New("the kubelet certificate is not verified, kubelet_tls_verify is disabled")
+	}
+	log.Infof("The kubelet certificate is verified, CA: %q", config.caPath)
+	return nil
+}
@@ -0,0 +1,21 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// +build docker
+// +build kubelet
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckTLSVerification(t *testing.T) {
+	assert.NoError(t, checkTLSVerification(kubeletClientConfig{scheme: "https", tlsVerify: true}))
+	assert.Error(t, checkTLSVerification(kubeletClientConfig{scheme: "https", tlsVerify: false}))
+	assert.Error(t, checkTLSVerification(kubeletClientConfig{scheme: "http", tlsVerify: true}))
+}
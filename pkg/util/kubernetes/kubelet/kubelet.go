@@ -439,11 +439,14 @@ func IsPodReady(pod *Pod) bool {
 	return false
 }
 
-// isPodStatic identifies whether a pod is static or not based on an annotation
-// Static pods can be sent to the kubelet from files or an http endpoint.
+// isPodStatic identifies whether a pod is static and without container statuses
 func isPodStatic(pod *Pod) bool {
-	if source, ok := pod.Metadata.Annotations[configSourceAnnotation]; ok == true && (source == "file" || source == "http") {
-		return len(pod.Status.Containers) == 0
-	}
-	return false
+	return IsStaticPod(pod) && len(pod.Status.Containers) == 0
+}
+
+// IsStaticPod identifies whether a pod is static or not based on an annotation
+// Static pods can be sent to the kubelet from files or an http endpoint.
+func IsStaticPod(pod *Pod) bool {
+	source, ok := pod.Metadata.Annotations[configSourceAnnotation]
+	return ok && (source == "file" || source == "http")
 }
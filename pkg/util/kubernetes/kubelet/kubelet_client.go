@@ -22,6 +22,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/config"
 	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
 	"github.com/DataDog/datadog-agent/pkg/util/kubernetes"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -75,6 +76,7 @@ func newForConfig(config kubeletClientConfig, timeout time.Duration) (*kubeletCl
 		}
 	}
 	customTransport.TLSClientConfig = tlsConfig
+	httputils.GetEgressValidator().Apply(customTransport)
 
 	// Do not use token in plain text
 	headers := http.Header{}
@@ -43,21 +43,44 @@ type PodOwner struct {
 
 // Spec contains fields for unmarshalling a Pod.Spec
 type Spec struct {
-	HostNetwork       bool            `json:"hostNetwork,omitempty"`
-	NodeName          string          `json:"nodeName,omitempty"`
-	InitContainers    []ContainerSpec `json:"initContainers,omitempty"`
-	Containers        []ContainerSpec `json:"containers,omitempty"`
-	Volumes           []VolumeSpec    `json:"volumes,omitempty"`
-	PriorityClassName string          `json:"priorityClassName,omitempty"`
+	HostNetwork       bool                 `json:"hostNetwork,omitempty"`
+	NodeName          string               `json:"nodeName,omitempty"`
+	InitContainers    []ContainerSpec      `json:"initContainers,omitempty"`
+	Containers        []ContainerSpec      `json:"containers,omitempty"`
+	Volumes           []VolumeSpec         `json:"volumes,omitempty"`
+	PriorityClassName string               `json:"priorityClassName,omitempty"`
+	SecurityContext   *SecurityContextSpec `json:"securityContext,omitempty"`
 }
 
 // ContainerSpec contains fields for unmarshalling a Pod.Spec.Containers
 type ContainerSpec struct {
-	Name           string              `json:"name"`
-	Image          string              `json:"image,omitempty"`
-	Ports          []ContainerPortSpec `json:"ports,omitempty"`
-	ReadinessProbe *ContainerProbe     `json:"readinessProbe,omitempty"`
-	Env            []EnvVar            `json:"env,omitempty"`
+	Name            string               `json:"name"`
+	Image           string               `json:"image,omitempty"`
+	Ports           []ContainerPortSpec  `json:"ports,omitempty"`
+	ReadinessProbe  *ContainerProbe      `json:"readinessProbe,omitempty"`
+	Env             []EnvVar             `json:"env,omitempty"`
+	SecurityContext *SecurityContextSpec `json:"securityContext,omitempty"`
+}
+
+// SecurityContextSpec contains fields for unmarshalling a Pod.Spec.SecurityContext
+// and a Pod.Spec.Containers.SecurityContext
+type SecurityContextSpec struct {
+	SeccompProfile *SeccompProfileSpec `json:"seccompProfile,omitempty"`
+	SELinuxOptions *SELinuxOptionsSpec `json:"seLinuxOptions,omitempty"`
+}
+
+// SeccompProfileSpec contains fields for unmarshalling a SecurityContext.SeccompProfile
+type SeccompProfileSpec struct {
+	Type             string `json:"type"`
+	LocalhostProfile string `json:"localhostProfile,omitempty"`
+}
+
+// SELinuxOptionsSpec contains fields for unmarshalling a SecurityContext.SELinuxOptions
+type SELinuxOptionsSpec struct {
+	User  string `json:"user,omitempty"`
+	Role  string `json:"role,omitempty"`
+	Type  string `json:"type,omitempty"`
+	Level string `json:"level,omitempty"`
 }
 
 // ContainerPortSpec contains fields for unmarshalling a Pod.Spec.Containers.Ports
@@ -49,6 +49,12 @@ type DatadogLogger struct {
 	extra       map[string]seelog.LoggerInterface
 	l           sync.RWMutex
 	contextLock sync.Mutex
+
+	// moduleLevels overrides the level of the messages logged from some modules
+	moduleLevels      map[string]seelog.LogLevel
+	minModuleLevel    seelog.LogLevel
+	maxModuleLevel    seelog.LogLevel
+	moduleLevelsCache *sync.Map
 }
 
 // SetupLogger setup agent wide logger
@@ -73,8 +79,9 @@ func SetupJMXLogger(i seelog.LoggerInterface, level string) {
 func setupCommonLogger(i seelog.LoggerInterface, level string) *DatadogLogger {
 
 	l := &DatadogLogger{
-		inner: i,
-		extra: make(map[string]seelog.LoggerInterface),
+		inner:             i,
+		extra:             make(map[string]seelog.LoggerInterface),
+		moduleLevelsCache: &sync.Map{},
 	}
 
 	lvl, ok := seelog.LogLevelFromString(level)
@@ -126,10 +133,16 @@ func (sw *DatadogLogger) changeLogLevel(level string) error {
 
 func (sw *DatadogLogger) shouldLog(level seelog.LogLevel) bool {
 	sw.l.RLock()
-	shouldLog := level >= sw.level
-	sw.l.RUnlock()
+	defer sw.l.RUnlock()
 
-	return shouldLog
+	// only look up the module of the caller when its level can change the outcome
+	if len(sw.moduleLevels) == 0 || (level >= sw.level && level >= sw.maxModuleLevel) {
+		return level >= sw.level
+	}
+	if level < sw.level && level < sw.minModuleLevel {
+		return false
+	}
+	return level >= sw.callerLogLevel()
 }
 
 func (sw *DatadogLogger) registerAdditionalLogger(n string, l seelog.LoggerInterface) error {
@@ -0,0 +1,190 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cihub/seelog"
+)
+
+// logPackageDir is the directory of this package, its frames are skipped to find the caller of the logger
+var logPackageDir string
+
+func init() {
+	_, file, _, ok := runtime.Caller(0)
+	if ok {
+		logPackageDir = filepath.Dir(file)
+	}
+}
+
+// moduleLevel is the log level override found for a source file
+type moduleLevel struct {
+	level seelog.LogLevel
+	found bool
+}
+
+// ParseModuleLogLevels parses a list of `module:level` pairs separated by commas, for example
+// `snmp:debug, forwarder:warn`. A module matches the source files in a directory with that name,
+// it can also be a path like `collector/corechecks`.
+func ParseModuleLogLevels(s string) (map[string]seelog.LogLevel, error) {
+	levels := make(map[string]seelog.LogLevel)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid module log level '%s', expected 'module:level'", pair)
+		}
+		module := strings.Trim(strings.TrimSpace(parts[0]), "/")
+		if module == "" {
+			return nil, fmt.Errorf("invalid module log level '%s', the module is empty", pair)
+		}
+		levelName := strings.ToLower(strings.TrimSpace(parts[1]))
+		if levelName == "warning" {
+			levelName = "warn"
+		}
+		level, ok := seelog.LogLevelFromString(levelName)
+		if !ok {
+			return nil, fmt.Errorf("invalid log level '%s' for module '%s'", parts[1], module)
+		}
+		levels[module] = level
+	}
+	return levels, nil
+}
+
+// FormatModuleLogLevels returns the representation of the module log levels parsed by ParseModuleLogLevels
+func FormatModuleLogLevels(levels map[string]seelog.LogLevel) string {
+	pairs := make([]string, 0, len(levels))
+	for module, level := range levels {
+		pairs = append(pairs, module+":"+level.String())
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
+// MinLogLevel returns the lowest level between the given one and the module log levels,
+// which is the level the underlying seelog logger has to be configured with to not drop
+// the messages of the modules logging at a lower level than the rest of the agent.
+func MinLogLevel(level seelog.LogLevel, levels map[string]seelog.LogLevel) seelog.LogLevel {
+	for _, l := range levels {
+		if l < level {
+			level = l
+		}
+	}
+	return level
+}
+
+func (sw *DatadogLogger) setModuleLogLevels(levels map[string]seelog.LogLevel) {
+	sw.l.Lock()
+	defer sw.l.Unlock()
+
+	sw.moduleLevels = make(map[string]seelog.LogLevel, len(levels))
+	sw.minModuleLevel, sw.maxModuleLevel = seelog.Off, seelog.TraceLvl
+	for module, level := range levels {
+		sw.moduleLevels[module] = level
+		if level < sw.minModuleLevel {
+			sw.minModuleLevel = level
+		}
+		if level > sw.maxModuleLevel {
+			sw.maxModuleLevel = level
+		}
+	}
+	sw.moduleLevelsCache = &sync.Map{}
+}
+
+func (sw *DatadogLogger) getModuleLogLevels() map[string]seelog.LogLevel {
+	sw.l.RLock()
+	defer sw.l.RUnlock()
+
+	levels := make(map[string]seelog.LogLevel, len(sw.moduleLevels))
+	for module, level := range sw.moduleLevels {
+		levels[module] = level
+	}
+	return levels
+}
+
+// callerLogLevel returns the level of the module of the function calling the logger,
+// or the global level if the module doesn't override it. Must be called with the read lock held.
+func (sw *DatadogLogger) callerLogLevel() seelog.LogLevel {
+	file := callerFile()
+	if cached, ok := sw.moduleLevelsCache.Load(file); ok {
+		if l := cached.(moduleLevel); l.found {
+			return l.level
+		}
+		return sw.level
+	}
+
+	l := sw.moduleLevelForFile(file)
+	sw.moduleLevelsCache.Store(file, l)
+	if l.found {
+		return l.level
+	}
+	return sw.level
+}
+
+// moduleLevelForFile returns the level of the most specific module matching the directory of the file
+func (sw *DatadogLogger) moduleLevelForFile(file string) moduleLevel {
+	dir := filepath.ToSlash(filepath.Dir(file)) + "/"
+	var l moduleLevel
+	bestIndex, bestLen := -1, 0
+	for module, level := range sw.moduleLevels {
+		i := strings.LastIndex(dir, "/"+module+"/")
+		if i < 0 {
+			continue
+		}
+		// the deepest match wins, for example `snmp` over `collector` for collector/corechecks/snmp
+		end := i + len(module)
+		if end > bestIndex || (end == bestIndex && len(module) > bestLen) {
+			bestIndex, bestLen = end, len(module)
+			l = moduleLevel{level: level, found: true}
+		}
+	}
+	return l
+}
+
+// callerFile returns the file of the first function outside of this package in the call stack
+func callerFile() string {
+	var pcs [16]uintptr
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != logPackageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.File
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// SetModuleLogLevels overrides the log level of the given modules, messages logged from these modules
+// are filtered with their own level instead of the global one. The underlying seelog logger needs to be
+// configured with a level lower or equal to the levels of the modules, see MinLogLevel.
+func SetModuleLogLevels(levels map[string]seelog.LogLevel) error {
+	if logger != nil && logger.inner != nil {
+		logger.setModuleLogLevels(levels)
+		return nil
+	}
+	return errors.New("cannot set module log levels: logger not initialized")
+}
+
+// GetModuleLogLevels returns the log level overrides of the modules
+func GetModuleLogLevels() map[string]seelog.LogLevel {
+	if logger != nil && logger.inner != nil {
+		return logger.getModuleLogLevels()
+	}
+	return map[string]seelog.LogLevel{}
+}
@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/cihub/seelog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseModuleLogLevels(t *testing.T) {
+	levels, err := ParseModuleLogLevels("snmp:debug, forwarder:WARNING,collector/corechecks:trace,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]seelog.LogLevel{
+		"snmp":                 seelog.DebugLvl,
+		"forwarder":            seelog.WarnLvl,
+		"collector/corechecks": seelog.TraceLvl,
+	}, levels)
+	assert.Equal(t, "collector/corechecks:trace, forwarder:warn, snmp:debug", FormatModuleLogLevels(levels))
+	assert.Equal(t, seelog.LogLevel(seelog.TraceLvl), MinLogLevel(seelog.InfoLvl, levels))
+	assert.Equal(t, seelog.LogLevel(seelog.InfoLvl), MinLogLevel(seelog.InfoLvl, nil))
+
+	levels, err = ParseModuleLogLevels("")
+	require.NoError(t, err)
+	assert.Len(t, levels, 0)
+
+	for _, invalid := range []string{"snmp", ":debug", "snmp:verbose"} {
+		_, err = ParseModuleLogLevels(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestModuleLevelForFile(t *testing.T) {
+	l := &DatadogLogger{}
+	l.setModuleLogLevels(map[string]seelog.LogLevel{
+		"collector":            seelog.WarnLvl,
+		"snmp":                 seelog.DebugLvl,
+		"collector/corechecks": seelog.ErrorLvl,
+	})
+
+	for file, expected := range map[string]moduleLevel{
+		"/src/datadog-agent/pkg/collector/corechecks/snmp/devicecheck/devicecheck.go": {level: seelog.DebugLvl, found: true},
+		"/src/datadog-agent/pkg/collector/corechecks/system/cpu.go":                   {level: seelog.ErrorLvl, found: true},
+		"/src/datadog-agent/pkg/collector/runner/runner.go":                           {level: seelog.WarnLvl, found: true},
+		"/src/datadog-agent/pkg/snmp/traps/listener.go":                               {level: seelog.DebugLvl, found: true},
+		"/src/datadog-agent/pkg/forwarder/forwarder.go":                               {},
+		"/src/datadog-agent/pkg/snmpwalk/walk.go":                                     {},
+	} {
+		assert.Equal(t, expected, l.moduleLevelForFile(file), file)
+	}
+}
+
+func TestModuleLogLevels(t *testing.T) {
+	var b bytes.Buffer
+	w := bufio.NewWriter(&b)
+
+	l, err := seelog.LoggerFromWriterWithMinLevelAndFormat(w, seelog.TraceLvl, "[%LEVEL] %Msg\n")
+	require.NoError(t, err)
+
+	SetupLogger(l, "info")
+	require.NoError(t, SetModuleLogLevels(map[string]seelog.LogLevel{"util/log": seelog.DebugLvl}))
+
+	Tracef("%s", "foo")
+	Debugf("%s", "foo")
+	Infof("%s", "foo")
+	w.Flush()
+
+	// this package logs at the debug level while the global level is info
+	assert.Equal(t, 2, strings.Count(b.String(), "foo"))
+	assert.True(t, ShouldLog(seelog.DebugLvl))
+	assert.Equal(t, "util/log:debug", FormatModuleLogLevels(GetModuleLogLevels()))
+
+	require.NoError(t, SetModuleLogLevels(map[string]seelog.LogLevel{"util/log": seelog.ErrorLvl}))
+	b.Reset()
+	Infof("%s", "bar")
+	Errorf("%s", "bar")
+	w.Flush()
+	assert.Equal(t, 1, strings.Count(b.String(), "bar"))
+
+	require.NoError(t, SetModuleLogLevels(nil))
+	b.Reset()
+	Debugf("%s", "baz")
+	Infof("%s", "baz")
+	w.Flush()
+	assert.Equal(t, 1, strings.Count(b.String(), "baz"))
+}
@@ -0,0 +1,22 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package oracle
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-agent/pkg/diagnose/diagnosis"
+)
+
+func init() {
+	diagnosis.Register("Oracle Cloud Metadata availability", diagnose)
+}
+
+// diagnose the Oracle Cloud metadata API availability
+func diagnose() error {
+	_, err := GetInstanceID(context.TODO())
+	return err
+}
@@ -0,0 +1,165 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+	"github.com/DataDog/datadog-agent/pkg/util/cachedfetch"
+	httputils "github.com/DataDog/datadog-agent/pkg/util/http"
+)
+
+// declare these as vars not const to ease testing
+var (
+	metadataURL = "http://169.254.169.254"
+	timeout     = 300 * time.Millisecond
+
+	// CloudProviderName contains the inventory name of for Oracle Cloud Infrastructure
+	CloudProviderName = "Oracle"
+)
+
+// the v2 metadata endpoint rejects the requests without this header
+var metadataHeaders = map[string]string{"Authorization": "Bearer Oracle"}
+
+// instanceMetadata is the subset of the instance metadata used by the agent
+// See https://docs.oracle.com/en-us/iaas/Content/Compute/Tasks/gettingmetadata.htm
+type instanceMetadata struct {
+	ID                 string                       `json:"id"`
+	Region             string                       `json:"canonicalRegionName"`
+	AvailabilityDomain string                       `json:"availabilityDomain"`
+	FaultDomain        string                       `json:"faultDomain"`
+	Shape              string                       `json:"shape"`
+	CompartmentID      string                       `json:"compartmentId"`
+	FreeformTags       map[string]string            `json:"freeformTags"`
+	DefinedTags        map[string]map[string]string `json:"definedTags"`
+	Metadata           map[string]string            `json:"metadata"`
+}
+
+// okeClusterNameKey is the instance metadata key set by Oracle Container Engine for Kubernetes on the worker nodes
+const okeClusterNameKey = "oke-cluster-display-name"
+
+// IsRunningOn returns true if the agent is running on Oracle Cloud Infrastructure
+func IsRunningOn(ctx context.Context) bool {
+	if _, err := GetInstanceID(ctx); err == nil {
+		return true
+	}
+	return false
+}
+
+var instanceFetcher = cachedfetch.Fetcher{
+	Name: "Oracle instance metadata",
+	Attempt: func(ctx context.Context) (interface{}, error) {
+		res, err := getMetadataItem(ctx, metadataURL+"/opc/v2/instance/")
+		if err != nil {
+			return nil, fmt.Errorf("unable to get Oracle Cloud instance metadata: %s", err)
+		}
+
+		var metadata instanceMetadata
+		if err := json.Unmarshal([]byte(res), &metadata); err != nil {
+			return nil, fmt.Errorf("unable to parse Oracle Cloud instance metadata: %s", err)
+		}
+		if metadata.ID == "" {
+			return nil, fmt.Errorf("Oracle Cloud instance metadata has no instance ID")
+		}
+		return &metadata, nil
+	},
+}
+
+func getInstanceMetadata(ctx context.Context) (*instanceMetadata, error) {
+	metadata, err := instanceFetcher.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.(*instanceMetadata), nil
+}
+
+// GetInstanceID fetches the instance OCID for current host from the Oracle Cloud metadata API
+func GetInstanceID(ctx context.Context) (string, error) {
+	metadata, err := getInstanceMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	if len(metadata.ID) > config.Datadog.GetInt("metadata_endpoints_max_hostname_size") {
+		return "", fmt.Errorf("Oracle Cloud instance ID is longer than %d", config.Datadog.GetInt("metadata_endpoints_max_hostname_size"))
+	}
+	return metadata.ID, nil
+}
+
+// GetHostAlias returns the instance OCID from the Oracle Cloud metadata API
+func GetHostAlias(ctx context.Context) (string, error) {
+	return GetInstanceID(ctx)
+}
+
+// GetTags returns the region, placement and shape of the instance and its freeform and defined tags
+func GetTags(ctx context.Context) ([]string, error) {
+	metadata, err := getInstanceMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for name, value := range map[string]string{
+		"region":              metadata.Region,
+		"availability-domain": metadata.AvailabilityDomain,
+		"fault-domain":        metadata.FaultDomain,
+		"instance-type":       metadata.Shape,
+		"compartment-id":      metadata.CompartmentID,
+	} {
+		if value != "" {
+			tags = append(tags, name+":"+value)
+		}
+	}
+	for key, value := range metadata.FreeformTags {
+		tags = append(tags, key+":"+value)
+	}
+	for namespace, definedTags := range metadata.DefinedTags {
+		for key, value := range definedTags {
+			tags = append(tags, namespace+"."+key+":"+value)
+		}
+	}
+
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// GetClusterName returns the name of the Oracle Container Engine for Kubernetes cluster of the instance
+func GetClusterName(ctx context.Context) (string, error) {
+	metadata, err := getInstanceMetadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	if name := metadata.Metadata[okeClusterNameKey]; name != "" {
+		return name, nil
+	}
+	return "", fmt.Errorf("the instance doesn't belong to an OKE cluster")
+}
+
+// GetNTPHosts returns the NTP hosts for Oracle Cloud if it is detected as the cloud provider, otherwise an empty array.
+// See https://docs.oracle.com/en-us/iaas/Content/Compute/Tasks/configuringntpservice.htm
+func GetNTPHosts(ctx context.Context) []string {
+	if IsRunningOn(ctx) {
+		return []string{"169.254.169.254"}
+	}
+
+	return nil
+}
+
+func getMetadataItem(ctx context.Context, endpoint string) (string, error) {
+	if !config.IsCloudProviderEnabled(CloudProviderName) {
+		return "", fmt.Errorf("cloud provider is disabled by configuration")
+	}
+
+	res, err := httputils.Get(ctx, endpoint, metadataHeaders, timeout)
+	if err != nil {
+		return "", fmt.Errorf("unable to fetch Oracle Cloud Metadata API, %s", err)
+	}
+	return res, nil
+}
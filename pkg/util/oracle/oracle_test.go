@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package oracle
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/config"
+)
+
+const instanceJSON = `{
+  "availabilityDomain": "EMIr:PHX-AD-1",
+  "canonicalRegionName": "us-phoenix-1",
+  "compartmentId": "ocid1.compartment.oc1..aaaa",
+  "definedTags": {"Operations": {"CostCenter": "42"}},
+  "faultDomain": "FAULT-DOMAIN-3",
+  "freeformTags": {"team": "sre"},
+  "id": "ocid1.instance.oc1.phx.abyhqljr",
+  "metadata": {"oke-cluster-display-name": "prod-cluster"},
+  "shape": "VM.Standard2.1"
+}`
+
+func setupServer(t *testing.T, body string) *http.Request {
+	holdValue := config.Datadog.Get("cloud_provider_metadata")
+	t.Cleanup(func() { config.Datadog.Set("cloud_provider_metadata", holdValue) })
+	config.Datadog.Set("cloud_provider_metadata", []string{"oracle"})
+
+	lastRequest := &http.Request{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*lastRequest = *r
+		if r.Header.Get("Authorization") != "Bearer Oracle" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(ts.Close)
+
+	metadataURL = ts.URL
+	instanceFetcher.Reset()
+	t.Cleanup(instanceFetcher.Reset)
+	return lastRequest
+}
+
+func TestGetInstanceID(t *testing.T) {
+	lastRequest := setupServer(t, instanceJSON)
+
+	val, err := GetInstanceID(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "ocid1.instance.oc1.phx.abyhqljr", val)
+	assert.Equal(t, "/opc/v2/instance/", lastRequest.URL.Path)
+	assert.True(t, IsRunningOn(context.Background()))
+}
+
+func TestGetInstanceIDDisabled(t *testing.T) {
+	setupServer(t, instanceJSON)
+	config.Datadog.Set("cloud_provider_metadata", []string{"aws"})
+
+	_, err := GetInstanceID(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGetTags(t *testing.T) {
+	setupServer(t, instanceJSON)
+
+	tags, err := GetTags(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"Operations.CostCenter:42",
+		"availability-domain:EMIr:PHX-AD-1",
+		"compartment-id:ocid1.compartment.oc1..aaaa",
+		"fault-domain:FAULT-DOMAIN-3",
+		"instance-type:VM.Standard2.1",
+		"region:us-phoenix-1",
+		"team:sre",
+	}, tags)
+}
+
+func TestGetClusterName(t *testing.T) {
+	setupServer(t, instanceJSON)
+
+	name, err := GetClusterName(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "prod-cluster", name)
+}
+
+func TestGetClusterNameNotOKE(t *testing.T) {
+	setupServer(t, `{"id": "ocid1.instance.oc1.phx.abyhqljr"}`)
+
+	_, err := GetClusterName(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGetNTPHosts(t *testing.T) {
+	setupServer(t, instanceJSON)
+
+	assert.Equal(t, []string{"169.254.169.254"}, GetNTPHosts(context.Background()))
+}
@@ -45,6 +45,8 @@ func ProcessPodList(podList []*v1.Pod, groupID int32, hostName string, clusterID
 
 	for _, p := range podList {
 		redact.RemoveLastAppliedConfigurationAnnotation(p.Annotations)
+		// remove the dropped annotations before they are extracted into the metadata
+		cfg.ManifestScrubber.DropObjectAnnotations(orchestrator.K8sPod.String(), p.Annotations)
 
 		// extract pod info
 		podModel := extractPodMessage(p)
@@ -104,6 +106,11 @@ func ProcessPodList(podList []*v1.Pod, groupID int32, hostName string, clusterID
 			log.Warnf("Could not marshal pod to JSON: %s", err)
 			continue
 		}
+		jsonPod, err = cfg.ManifestScrubber.ScrubManifest(orchestrator.K8sPod.String(), jsonPod)
+		if err != nil {
+			log.Warnf("Could not scrub pod manifest: %s", err)
+			continue
+		}
 		podModel.Yaml = jsonPod
 
 		podMsgs = append(podMsgs, podModel)
@@ -0,0 +1,163 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package startup starts the components of an agent concurrently, following their dependencies
+package startup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+var tlmStartDuration = telemetry.NewGauge("startup", "component_duration_seconds",
+	[]string{"component"}, "Time taken to start a component of the agent")
+
+// StartFunc starts a component. It should only return an error the agent can't run without the component,
+// the components depending on it are then not started.
+type StartFunc func() error
+
+type component struct {
+	name      string
+	start     StartFunc
+	dependsOn []string
+
+	done     chan struct{}
+	err      error
+	duration time.Duration
+}
+
+// Graph starts a set of components concurrently, each component being started once the components it
+// depends on are started
+type Graph struct {
+	components []*component
+	byName     map[string]*component
+}
+
+// NewGraph returns an empty Graph
+func NewGraph() *Graph {
+	return &Graph{
+		byName: make(map[string]*component),
+	}
+}
+
+// Add adds a component to the graph, started once the dependsOn components are
+func (g *Graph) Add(name string, start StartFunc, dependsOn ...string) {
+	c := &component{
+		name:      name,
+		start:     start,
+		dependsOn: dependsOn,
+		done:      make(chan struct{}),
+	}
+	g.components = append(g.components, c)
+	g.byName[name] = c
+}
+
+// validate checks that the dependencies exist and don't form a cycle
+func (g *Graph) validate() error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(g.components))
+
+	var visit func(c *component) error
+	visit = func(c *component) error {
+		switch state[c.name] {
+		case visiting:
+			return fmt.Errorf("dependency cycle on component %q", c.name)
+		case visited:
+			return nil
+		}
+		state[c.name] = visiting
+		for _, name := range c.dependsOn {
+			dep, found := g.byName[name]
+			if !found {
+				return fmt.Errorf("component %q depends on unknown component %q", c.name, name)
+			}
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[c.name] = visited
+		return nil
+	}
+
+	for _, c := range g.components {
+		if err := visit(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Start starts the components and waits for all of them to be started. It returns the error of the first
+// component, in the order they were added, that failed to start.
+func (g *Graph) Start() error {
+	if err := g.validate(); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	for _, c := range g.components {
+		wg.Add(1)
+		go func(c *component) {
+			defer wg.Done()
+			defer close(c.done)
+
+			for _, name := range c.dependsOn {
+				dep := g.byName[name]
+				<-dep.done
+				if dep.err != nil {
+					c.err = fmt.Errorf("%s not started, it depends on %s: %w", c.name, dep.name, dep.err)
+					return
+				}
+			}
+
+			start := time.Now()
+			c.err = c.start()
+			c.duration = time.Since(start)
+
+			tlmStartDuration.Set(c.duration.Seconds(), c.name)
+			log.Debugf("Component %s started in %s", c.name, c.duration)
+		}(c)
+	}
+	wg.Wait()
+
+	for _, c := range g.components {
+		if c.err != nil {
+			return c.err
+		}
+	}
+	return nil
+}
+
+// Durations returns the time taken to start each component started by Start
+func (g *Graph) Durations() map[string]time.Duration {
+	durations := make(map[string]time.Duration, len(g.components))
+	for _, c := range g.components {
+		durations[c.name] = c.duration
+	}
+	return durations
+}
+
+// DurationsSummary returns the time taken to start each component, slowest first, to be logged
+func (g *Graph) DurationsSummary() string {
+	components := make([]*component, len(g.components))
+	copy(components, g.components)
+	sort.SliceStable(components, func(i, j int) bool { return components[i].duration > components[j].duration })
+
+	summary := make([]string, 0, len(components))
+	for _, c := range components {
+		summary = append(summary, fmt.Sprintf("%s: %s", c.name, c.duration.Round(time.Millisecond)))
+	}
+	return strings.Join(summary, ", ")
+}
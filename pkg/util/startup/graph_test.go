@@ -0,0 +1,108 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package startup
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type startRecorder struct {
+	sync.Mutex
+	started []string
+}
+
+func (r *startRecorder) start(name string, delay time.Duration) StartFunc {
+	return func() error {
+		time.Sleep(delay)
+		r.Lock()
+		defer r.Unlock()
+		r.started = append(r.started, name)
+		return nil
+	}
+}
+
+func (r *startRecorder) index(name string) int {
+	for i, n := range r.started {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestGraphStartOrder(t *testing.T) {
+	r := &startRecorder{}
+	g := NewGraph()
+	g.Add("forwarder", r.start("forwarder", 20*time.Millisecond))
+	g.Add("aggregator", r.start("aggregator", 0), "forwarder")
+	g.Add("dogstatsd", r.start("dogstatsd", 0), "aggregator")
+	g.Add("logs", r.start("logs", 0))
+	g.Add("autoconfig", r.start("autoconfig", 0), "aggregator", "logs")
+
+	require.NoError(t, g.Start())
+	require.Len(t, r.started, 5)
+
+	// logs doesn't wait for the slow forwarder
+	assert.Less(t, r.index("logs"), r.index("forwarder"))
+	assert.Less(t, r.index("forwarder"), r.index("aggregator"))
+	assert.Less(t, r.index("aggregator"), r.index("dogstatsd"))
+	assert.Less(t, r.index("aggregator"), r.index("autoconfig"))
+	assert.Less(t, r.index("logs"), r.index("autoconfig"))
+
+	durations := g.Durations()
+	assert.Len(t, durations, 5)
+	assert.GreaterOrEqual(t, int64(durations["forwarder"]), int64(20*time.Millisecond))
+	assert.Regexp(t, `^forwarder: \d+ms, `, g.DurationsSummary())
+}
+
+func TestGraphStartConcurrently(t *testing.T) {
+	g := NewGraph()
+	for _, name := range []string{"a", "b", "c", "d"} {
+		g.Add(name, func() error {
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		})
+	}
+
+	start := time.Now()
+	require.NoError(t, g.Start())
+	assert.Less(t, int64(time.Since(start)), int64(150*time.Millisecond))
+}
+
+func TestGraphStartError(t *testing.T) {
+	r := &startRecorder{}
+	failure := errors.New("no API key")
+	g := NewGraph()
+	g.Add("forwarder", func() error { return failure })
+	g.Add("aggregator", r.start("aggregator", 0), "forwarder")
+	g.Add("dogstatsd", r.start("dogstatsd", 0), "aggregator")
+	g.Add("logs", r.start("logs", 0))
+
+	err := g.Start()
+	assert.Equal(t, failure, err)
+	// the components depending on the failed one, directly or not, aren't started
+	assert.Equal(t, []string{"logs"}, r.started)
+}
+
+func TestGraphValidation(t *testing.T) {
+	noop := func() error { return nil }
+
+	g := NewGraph()
+	g.Add("aggregator", noop, "forwarder")
+	assert.EqualError(t, g.Start(), `component "aggregator" depends on unknown component "forwarder"`)
+
+	g = NewGraph()
+	g.Add("a", noop, "c")
+	g.Add("b", noop, "a")
+	g.Add("c", noop, "b")
+	assert.Error(t, g.Start())
+}
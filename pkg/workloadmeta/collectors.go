@@ -20,6 +20,14 @@ type Collector interface {
 	Pull(context.Context) error
 }
 
+// StoppableCollector is implemented by the collectors running goroutines until
+// the context given to Start is done. Stopped returns a channel closed once
+// they returned, the store waits for it before restarting the collector on a
+// resync.
+type StoppableCollector interface {
+	Stopped() <-chan struct{}
+}
+
 type collectorFactory func() Collector
 
 var collectorCatalog = make(map[string]collectorFactory)
@@ -62,6 +62,7 @@ type collector struct {
 	containerdClient cutil.ContainerdItf
 	eventsChan       <-chan *containerdevents.Envelope
 	errorsChan       <-chan error
+	stopped          chan struct{}
 }
 
 func init() {
@@ -92,7 +93,9 @@ func (c *collector) Start(ctx context.Context, store workloadmeta.Store) error {
 		return err
 	}
 
+	c.stopped = make(chan struct{})
 	go func() {
+		defer close(c.stopped)
 		defer cancelEvents()
 		c.stream(ctx)
 	}()
@@ -104,6 +107,12 @@ func (c *collector) Pull(ctx context.Context) error {
 	return nil
 }
 
+// Stopped returns a channel closed once the collector stopped streaming the
+// container events.
+func (c *collector) Stopped() <-chan struct{} {
+	return c.stopped
+}
+
 func (c *collector) stream(ctx context.Context) {
 	healthHandle := health.RegisterLiveness(componentName)
 	ctx, cancel := context.WithCancel(ctx)
@@ -41,6 +41,7 @@ type collector struct {
 	dockerUtil *docker.DockerUtil
 	eventCh    <-chan *docker.ContainerEvent
 	errCh      <-chan error
+	stopped    chan struct{}
 }
 
 func init() {
@@ -77,6 +78,7 @@ func (c *collector) Start(ctx context.Context, store workloadmeta.Store) error {
 		return err
 	}
 
+	c.stopped = make(chan struct{})
 	go c.stream(ctx)
 
 	return nil
@@ -86,7 +88,15 @@ func (c *collector) Pull(ctx context.Context) error {
 	return nil
 }
 
+// Stopped returns a channel closed once the collector stopped streaming the
+// container events.
+func (c *collector) Stopped() <-chan struct{} {
+	return c.stopped
+}
+
 func (c *collector) stream(ctx context.Context) {
+	defer close(c.stopped)
+
 	health := health.RegisterLiveness(componentName)
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -112,7 +122,7 @@ func (c *collector) stream(ctx context.Context) {
 		case <-ctx.Done():
 			var err error
 
-			err = c.dockerUtil.UnsubscribeFromContainerEvents("DockerCollector")
+			err = c.dockerUtil.UnsubscribeFromContainerEvents(componentName)
 			if err != nil {
 				log.Warnf("error unsubscribbing from container events: %s", err)
 			}
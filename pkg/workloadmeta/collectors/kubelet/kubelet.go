@@ -33,6 +33,7 @@ type collector struct {
 	store      workloadmeta.Store
 	lastExpire time.Time
 	expireFreq time.Duration
+	staticPods map[string]*staticPod
 }
 
 func init() {
@@ -51,6 +52,7 @@ func (c *collector) Start(_ context.Context, store workloadmeta.Store) error {
 	c.store = store
 	c.lastExpire = time.Now()
 	c.expireFreq = expireFreq
+	c.staticPods = make(map[string]*staticPod)
 	c.watcher, err = kubelet.NewPodWatcher(expireFreq, true)
 	if err != nil {
 		return err
@@ -65,13 +67,15 @@ func (c *collector) Pull(ctx context.Context) error {
 		return err
 	}
 
-	events := c.parsePods(updatedPods)
+	updatedPods, staticPodEvents := c.correlateStaticPods(updatedPods)
+	events := append(staticPodEvents, c.parsePods(updatedPods)...)
 
 	if time.Now().Sub(c.lastExpire) >= c.expireFreq {
 		var expiredIDs []string
 		expiredIDs, err = c.watcher.Expire()
 		if err == nil {
 			events = append(events, c.parseExpires(expiredIDs)...)
+			events = append(events, c.expireStaticPods(expiredIDs)...)
 			c.lastExpire = time.Now()
 		}
 	}
@@ -99,6 +103,7 @@ func (c *collector) parsePods(pods []*kubelet.Pod) []workloadmeta.CollectorEvent
 		containerSpecs = append(containerSpecs, pod.Spec.Containers...)
 
 		podContainers, containerEvents := c.parsePodContainers(
+			pod,
 			containerSpecs,
 			pod.Status.GetAllContainers(),
 		)
@@ -145,6 +150,7 @@ func (c *collector) parsePods(pods []*kubelet.Pod) []workloadmeta.CollectorEvent
 }
 
 func (c *collector) parsePodContainers(
+	pod *kubelet.Pod,
 	containerSpecs []kubelet.ContainerSpec,
 	containerStatuses []kubelet.ContainerStatus,
 ) ([]workloadmeta.OrchestratorContainer, []workloadmeta.CollectorEvent) {
@@ -176,6 +182,7 @@ func (c *collector) parsePodContainers(
 		}
 
 		containerSpec := findContainerSpec(container.Name, containerSpecs)
+		podContainer.SecurityProfile = declaredSecurityProfile(pod, container.Name, containerSpec)
 		if containerSpec != nil {
 			env = extractEnvFromSpec(containerSpec.Env)
 
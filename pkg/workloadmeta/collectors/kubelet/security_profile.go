@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package kubelet
+
+import (
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const (
+	appArmorContainerAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+	seccompContainerAnnotationPrefix  = "container.seccomp.security.alpha.kubernetes.io/"
+	seccompPodAnnotation              = "seccomp.security.alpha.kubernetes.io/pod"
+
+	seccompRuntimeDefault = "RuntimeDefault"
+	seccompUnconfined     = "Unconfined"
+	seccompLocalhost      = "Localhost"
+)
+
+// declaredSecurityProfile returns the confinement declared for a container of
+// a pod. The container security context takes precedence over the pod one,
+// which takes precedence over the deprecated annotations.
+func declaredSecurityProfile(pod *kubelet.Pod, containerName string, spec *kubelet.ContainerSpec) workloadmeta.ContainerSecurityProfile {
+	var profile workloadmeta.ContainerSecurityProfile
+	var containerContext *kubelet.SecurityContextSpec
+	if spec != nil {
+		containerContext = spec.SecurityContext
+	}
+
+	for _, sc := range []*kubelet.SecurityContextSpec{containerContext, pod.Spec.SecurityContext} {
+		if sc == nil {
+			continue
+		}
+		if profile.Seccomp == "" && sc.SeccompProfile != nil {
+			profile.Seccomp = seccompProfileFromSpec(sc.SeccompProfile)
+		}
+		if profile.SELinuxType == "" && sc.SELinuxOptions != nil {
+			profile.SELinuxType = sc.SELinuxOptions.Type
+		}
+	}
+
+	annotations := pod.Metadata.Annotations
+	if profile.Seccomp == "" {
+		if value, found := annotations[seccompContainerAnnotationPrefix+containerName]; found {
+			profile.Seccomp = seccompProfileFromAnnotation(value)
+		} else if value, found := annotations[seccompPodAnnotation]; found {
+			profile.Seccomp = seccompProfileFromAnnotation(value)
+		}
+	}
+	profile.AppArmor = annotations[appArmorContainerAnnotationPrefix+containerName]
+
+	return profile
+}
+
+func seccompProfileFromSpec(spec *kubelet.SeccompProfileSpec) string {
+	if spec.Type == seccompLocalhost {
+		return seccompLocalhost + "/" + spec.LocalhostProfile
+	}
+	return spec.Type
+}
+
+// seccompProfileFromAnnotation converts the value of a seccomp annotation to
+// the profile type of the security contexts
+func seccompProfileFromAnnotation(value string) string {
+	switch {
+	case value == "runtime/default", value == "docker/default":
+		return seccompRuntimeDefault
+	case value == "unconfined":
+		return seccompUnconfined
+	case strings.HasPrefix(value, "localhost/"):
+		return seccompLocalhost + "/" + strings.TrimPrefix(value, "localhost/")
+	default:
+		return value
+	}
+}
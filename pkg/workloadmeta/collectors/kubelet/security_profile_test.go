@@ -0,0 +1,97 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+func TestDeclaredSecurityProfile(t *testing.T) {
+	tests := []struct {
+		name     string
+		pod      *kubelet.Pod
+		spec     *kubelet.ContainerSpec
+		expected workloadmeta.ContainerSecurityProfile
+	}{
+		{
+			name:     "nothing declared",
+			pod:      &kubelet.Pod{},
+			spec:     &kubelet.ContainerSpec{Name: "app"},
+			expected: workloadmeta.ContainerSecurityProfile{},
+		},
+		{
+			name: "container security context takes precedence",
+			pod: &kubelet.Pod{
+				Spec: kubelet.Spec{
+					SecurityContext: &kubelet.SecurityContextSpec{
+						SeccompProfile: &kubelet.SeccompProfileSpec{Type: "Unconfined"},
+						SELinuxOptions: &kubelet.SELinuxOptionsSpec{Type: "spc_t"},
+					},
+				},
+			},
+			spec: &kubelet.ContainerSpec{
+				Name: "app",
+				SecurityContext: &kubelet.SecurityContextSpec{
+					SeccompProfile: &kubelet.SeccompProfileSpec{Type: "Localhost", LocalhostProfile: "profiles/app.json"},
+				},
+			},
+			expected: workloadmeta.ContainerSecurityProfile{
+				Seccomp:     "Localhost/profiles/app.json",
+				SELinuxType: "spc_t",
+			},
+		},
+		{
+			name: "annotations",
+			pod: &kubelet.Pod{
+				Metadata: kubelet.PodMetadata{
+					Annotations: map[string]string{
+						"seccomp.security.alpha.kubernetes.io/pod":               "runtime/default",
+						"container.apparmor.security.beta.kubernetes.io/app":     "localhost/app-profile",
+						"container.apparmor.security.beta.kubernetes.io/sidecar": "unconfined",
+					},
+				},
+			},
+			spec: &kubelet.ContainerSpec{Name: "app"},
+			expected: workloadmeta.ContainerSecurityProfile{
+				Seccomp:  "RuntimeDefault",
+				AppArmor: "localhost/app-profile",
+			},
+		},
+		{
+			name: "security context takes precedence over annotations",
+			pod: &kubelet.Pod{
+				Metadata: kubelet.PodMetadata{
+					Annotations: map[string]string{
+						"container.seccomp.security.alpha.kubernetes.io/app": "unconfined",
+					},
+				},
+				Spec: kubelet.Spec{
+					SecurityContext: &kubelet.SecurityContextSpec{
+						SeccompProfile: &kubelet.SeccompProfileSpec{Type: "RuntimeDefault"},
+					},
+				},
+			},
+			spec: nil,
+			expected: workloadmeta.ContainerSecurityProfile{
+				Seccomp: "RuntimeDefault",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, declaredSecurityProfile(tt.pod, "app", tt.spec))
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package kubelet
+
+import (
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+)
+
+const (
+	podUIDLabel        = "io.kubernetes.pod.uid"
+	containerNameLabel = "io.kubernetes.container.name"
+)
+
+// staticPod is a pod started by the kubelet from a manifest, such as the
+// control plane components. Its status in the pod list of the kubelet is never
+// updated, as the kubelet reports it on the mirror pod it creates in the API
+// server, so its containers are correlated with the runtime containers
+// labelled with its UID instead.
+type staticPod struct {
+	pod *kubelet.Pod
+	// containers are the running state of the containers last reported, by ID
+	containers map[string]bool
+}
+
+// correlateStaticPods tracks the static pods without container statuses of the
+// updated pods, and returns the updated pods with the static pods whose
+// containers changed, populated with the statuses of their runtime containers.
+// It also returns the events unsetting the containers that were removed from
+// the static pods.
+func (c *collector) correlateStaticPods(updatedPods []*kubelet.Pod) ([]*kubelet.Pod, []workloadmeta.CollectorEvent) {
+	pods := make([]*kubelet.Pod, 0, len(updatedPods))
+	updatedStaticPods := make(map[string]struct{})
+	for _, pod := range updatedPods {
+		if !kubelet.IsStaticPod(pod) || len(pod.Status.GetAllContainers()) > 0 {
+			pods = append(pods, pod)
+			continue
+		}
+
+		previous := c.staticPods[pod.Metadata.UID]
+		c.staticPods[pod.Metadata.UID] = &staticPod{pod: pod}
+		if previous != nil {
+			c.staticPods[pod.Metadata.UID].containers = previous.containers
+		}
+		updatedStaticPods[pod.Metadata.UID] = struct{}{}
+	}
+
+	if len(c.staticPods) == 0 {
+		return pods, nil
+	}
+
+	runtimeContainers := c.runtimeContainersByPodUID()
+
+	var events []workloadmeta.CollectorEvent
+	for uid, static := range c.staticPods {
+		statuses := runtimeContainerStatuses(runtimeContainers[uid])
+
+		current := make(map[string]bool, len(statuses))
+		for _, status := range statuses {
+			current[status.ID] = status.State.Running != nil
+		}
+
+		_, updated := updatedStaticPods[uid]
+		if !updated && equalContainerStates(static.containers, current) {
+			continue
+		}
+
+		for id := range static.containers {
+			if _, found := current[id]; !found {
+				events = append(events, unsetContainerEvent(id))
+			}
+		}
+		static.containers = current
+
+		pod := *static.pod
+		pod.Status = correlatedStatus(static.pod.Status, statuses)
+		pods = append(pods, &pod)
+	}
+
+	return pods, events
+}
+
+// expireStaticPods stops tracking the expired static pods, and returns the
+// events unsetting their containers
+func (c *collector) expireStaticPods(expiredIDs []string) []workloadmeta.CollectorEvent {
+	var events []workloadmeta.CollectorEvent
+	for _, expiredID := range expiredIDs {
+		prefix, uid := containers.SplitEntityName(expiredID)
+		if prefix != kubelet.KubePodEntityName {
+			continue
+		}
+
+		static, found := c.staticPods[uid]
+		if !found {
+			continue
+		}
+
+		for id := range static.containers {
+			events = append(events, unsetContainerEvent(id))
+		}
+		delete(c.staticPods, uid)
+	}
+
+	return events
+}
+
+// runtimeContainersByPodUID returns the containers of the store created by the
+// kubelet, by the UID of their pod
+func (c *collector) runtimeContainersByPodUID() map[string][]*workloadmeta.Container {
+	byPodUID := make(map[string][]*workloadmeta.Container)
+
+	runtimeContainers, err := c.store.ListContainers()
+	if err != nil {
+		return byPodUID
+	}
+
+	for _, container := range runtimeContainers {
+		uid := container.Labels[podUIDLabel]
+		if uid == "" || containers.IsPauseContainer(container.Labels) {
+			continue
+		}
+		byPodUID[uid] = append(byPodUID[uid], container)
+	}
+
+	return byPodUID
+}
+
+// runtimeContainerStatuses returns the statuses of runtime containers, as
+// reported by the kubelet for the other pods
+func runtimeContainerStatuses(runtimeContainers []*workloadmeta.Container) []kubelet.ContainerStatus {
+	statuses := make([]kubelet.ContainerStatus, 0, len(runtimeContainers))
+	for _, container := range runtimeContainers {
+		status := kubelet.ContainerStatus{
+			Name:    container.Labels[containerNameLabel],
+			Image:   container.Image.RawName,
+			ImageID: container.Image.ID,
+			ID:      containers.BuildEntityName(string(container.Runtime), container.ID),
+			Ready:   container.State.Running,
+		}
+
+		if container.State.Running {
+			status.State.Running = &kubelet.ContainerStateRunning{
+				StartedAt: container.State.StartedAt,
+			}
+		} else if !container.State.FinishedAt.IsZero() {
+			status.State.Terminated = &kubelet.ContainerStateTerminated{
+				StartedAt:  container.State.StartedAt,
+				FinishedAt: container.State.FinishedAt,
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Name != statuses[j].Name {
+			return statuses[i].Name < statuses[j].Name
+		}
+		return statuses[i].ID < statuses[j].ID
+	})
+
+	return statuses
+}
+
+// correlatedStatus returns the status of a static pod with the statuses of its
+// runtime containers, and the phase and readiness its mirror pod would report
+func correlatedStatus(status kubelet.Status, statuses []kubelet.ContainerStatus) kubelet.Status {
+	status.Containers = statuses
+	status.AllContainers = statuses
+
+	running, ready := false, len(statuses) > 0
+	for _, s := range statuses {
+		running = running || s.Ready
+		ready = ready && s.Ready
+	}
+
+	if running {
+		status.Phase = "Running"
+	}
+
+	readyCondition := "False"
+	if ready {
+		readyCondition = "True"
+	}
+	status.Conditions = []kubelet.Conditions{{Type: "Ready", Status: readyCondition}}
+
+	return status
+}
+
+func equalContainerStates(a, b map[string]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for id, running := range a {
+		if r, found := b[id]; !found || r != running {
+			return false
+		}
+	}
+	return true
+}
+
+func unsetContainerEvent(entityName string) workloadmeta.CollectorEvent {
+	_, id := containers.SplitEntityName(entityName)
+	return workloadmeta.CollectorEvent{
+		Source: workloadmeta.SourceKubelet,
+		Type:   workloadmeta.EventTypeUnset,
+		Entity: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   id,
+		},
+	}
+}
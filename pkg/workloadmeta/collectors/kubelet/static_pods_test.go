@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubelet
+// +build kubelet
+
+package kubelet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/util/kubernetes/kubelet"
+	"github.com/DataDog/datadog-agent/pkg/workloadmeta"
+	workloadmetatesting "github.com/DataDog/datadog-agent/pkg/workloadmeta/testing"
+)
+
+func newRuntimeContainer(id, podUID, name string, running bool) *workloadmeta.Container {
+	return &workloadmeta.Container{
+		EntityID: workloadmeta.EntityID{
+			Kind: workloadmeta.KindContainer,
+			ID:   id,
+		},
+		EntityMeta: workloadmeta.EntityMeta{
+			Labels: map[string]string{
+				podUIDLabel:              podUID,
+				containerNameLabel:       name,
+				"io.kubernetes.pod.name": "kube-apiserver-master",
+			},
+		},
+		Image:   workloadmeta.ContainerImage{RawName: "k8s.gcr.io/kube-apiserver:v1.21.0"},
+		Runtime: workloadmeta.ContainerRuntimeContainerd,
+		State: workloadmeta.ContainerState{
+			Running:   running,
+			StartedAt: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestCorrelateStaticPods(t *testing.T) {
+	store := workloadmetatesting.NewStore()
+	c := &collector{
+		store:      store,
+		staticPods: make(map[string]*staticPod),
+	}
+
+	staticPod := &kubelet.Pod{
+		Metadata: kubelet.PodMetadata{
+			Name:        "kube-apiserver-master",
+			Namespace:   "kube-system",
+			UID:         "a8b8ad5e2a7d0a5e",
+			Annotations: map[string]string{"kubernetes.io/config.source": "file"},
+		},
+		Status: kubelet.Status{Phase: "Pending"},
+	}
+	regularPod := &kubelet.Pod{
+		Metadata: kubelet.PodMetadata{Name: "nginx", UID: "nginx-uid"},
+	}
+
+	apiserver := newRuntimeContainer("apiserver-1", "a8b8ad5e2a7d0a5e", "kube-apiserver", true)
+	store.Set(apiserver)
+	store.Set(newRuntimeContainer("pause", "a8b8ad5e2a7d0a5e", "POD", true))
+	store.Set(newRuntimeContainer("nginx", "nginx-uid", "nginx", true))
+
+	pods, events := c.correlateStaticPods([]*kubelet.Pod{regularPod, staticPod})
+	assert.Empty(t, events)
+	require.Len(t, pods, 2)
+	assert.Equal(t, regularPod, pods[0])
+
+	correlated := pods[1]
+	assert.Equal(t, "Running", correlated.Status.Phase)
+	assert.True(t, kubelet.IsPodReady(correlated))
+	assert.Equal(t, []kubelet.ContainerStatus{
+		{
+			Name:  "kube-apiserver",
+			Image: "k8s.gcr.io/kube-apiserver:v1.21.0",
+			ID:    "containerd://apiserver-1",
+			Ready: true,
+			State: kubelet.ContainerState{
+				Running: &kubelet.ContainerStateRunning{StartedAt: apiserver.State.StartedAt},
+			},
+		},
+	}, correlated.Status.GetAllContainers())
+	assert.Empty(t, staticPod.Status.Containers)
+
+	// the static pod is only reported again when its containers change
+	pods, events = c.correlateStaticPods(nil)
+	assert.Empty(t, pods)
+	assert.Empty(t, events)
+
+	store.Unset(apiserver)
+	store.Set(newRuntimeContainer("apiserver-2", "a8b8ad5e2a7d0a5e", "kube-apiserver", true))
+
+	pods, events = c.correlateStaticPods(nil)
+	require.Len(t, pods, 1)
+	assert.Equal(t, "containerd://apiserver-2", pods[0].Status.GetAllContainers()[0].ID)
+	assert.Equal(t, []workloadmeta.CollectorEvent{unsetContainerEvent("containerd://apiserver-1")}, events)
+
+	events = c.expireStaticPods([]string{kubelet.PodUIDToEntityName("nginx-uid"), kubelet.PodUIDToEntityName("a8b8ad5e2a7d0a5e")})
+	assert.Equal(t, []workloadmeta.CollectorEvent{unsetContainerEvent("containerd://apiserver-2")}, events)
+	assert.Empty(t, c.staticPods)
+}
@@ -37,6 +37,9 @@ type FakeDCAClient struct {
 	NodeLabels    map[string]string
 	NodeLabelsErr error
 
+	NodeTaints    []string
+	NodeTaintsErr error
+
 	NamespaceLabels    map[string]string
 	NamespaceLabelsErr error
 
@@ -75,6 +78,10 @@ func (f *FakeDCAClient) GetNodeLabels(nodeName string) (map[string]string, error
 	return f.NodeLabels, f.NodeLabelsErr
 }
 
+func (f *FakeDCAClient) GetNodeTaints(nodeName string) ([]string, error) {
+	return f.NodeTaints, f.NodeTaintsErr
+}
+
 func (f *FakeDCAClient) GetNamespaceLabels(nsName string) (map[string]string, error) {
 	return f.NamespaceLabels, f.NamespaceLabelsErr
 }
@@ -99,6 +106,14 @@ func (f *FakeDCAClient) GetEndpointsCheckConfigs(ctx context.Context, nodeName s
 	return f.EndpointsCheckConfigs, f.EndpointsCheckConfigsErr
 }
 
+func (f *FakeDCAClient) GetCheckTemplates(ctx context.Context, version, etag string) (types.CheckTemplatesResponse, string, bool, error) {
+	panic("implement me")
+}
+
+func (f *FakeDCAClient) LeaseSNMPDevices(ctx context.Context, identifier string, request types.SNMPLeaseRequest) (types.SNMPLeaseResponse, error) {
+	panic("implement me")
+}
+
 func (f *FakeDCAClient) GetKubernetesClusterID() (string, error) {
 	return f.ClusterID, f.ClusterIDErr
 }
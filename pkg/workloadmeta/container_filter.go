@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package workloadmeta
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/util/containers"
+)
+
+// GetContainerFilterInfo completes the attributes matched by the container
+// filters with the ones known to the store: the start time of the container,
+// and the labels of its pod, or its own labels when it doesn't run in a pod.
+// The attributes already set are kept, and the ones of the containers unknown
+// to the store are left empty.
+func GetContainerFilterInfo(store Store, containerID string, info containers.ContainerFilterInfo) containers.ContainerFilterInfo {
+	container, err := store.GetContainer(containerID)
+	if err != nil {
+		return info
+	}
+
+	if info.StartedAt.IsZero() {
+		info.StartedAt = container.State.StartedAt
+	}
+
+	if info.Labels == nil {
+		if pod, err := store.GetKubernetesPodForContainer(containerID); err == nil {
+			info.Labels = pod.Labels
+		} else {
+			info.Labels = container.Labels
+		}
+	}
+
+	return info
+}
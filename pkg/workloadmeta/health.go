@@ -0,0 +1,184 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package workloadmeta
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// CollectorHealth is the health of a collector, reported in the agent status.
+type CollectorHealth struct {
+	ID      string `json:"id"`
+	Started bool   `json:"started"`
+	// LastSync is the time, in nanoseconds since epoch, of the last
+	// successful pull or of the last events sent by the collector.
+	LastSync    int64  `json:"last_sync"`
+	ErrorStreak int    `json:"error_streak"`
+	LastError   string `json:"last_error,omitempty"`
+}
+
+type collectorEntityKey struct {
+	EntityID
+	source Source
+}
+
+// collectorStatus tracks the health of a collector and the entities it
+// reported, so that they can be removed if the collector doesn't report them
+// again when it's resynced. It outlives the instances of the collector.
+type collectorStatus struct {
+	mu          sync.Mutex
+	started     bool
+	lastSync    time.Time
+	errorStreak int
+	lastError   string
+	entities    map[collectorEntityKey]Entity
+	// resyncedEntities are the entities reported before a resync, until
+	// the first successful pull of the restarted collector
+	resyncedEntities map[collectorEntityKey]Entity
+}
+
+func newCollectorStatus() *collectorStatus {
+	return &collectorStatus{
+		entities: make(map[collectorEntityKey]Entity),
+	}
+}
+
+func (cs *collectorStatus) recordSuccess() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.lastSync = time.Now()
+	cs.errorStreak = 0
+	cs.lastError = ""
+}
+
+func (cs *collectorStatus) recordError(err error) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.errorStreak++
+	cs.lastError = err.Error()
+}
+
+func (cs *collectorStatus) setStarted(started bool) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	cs.started = started
+}
+
+func (cs *collectorStatus) recordEvents(events []CollectorEvent) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	for _, ev := range events {
+		key := collectorEntityKey{EntityID: ev.Entity.GetID(), source: ev.Source}
+		switch ev.Type {
+		case EventTypeSet:
+			cs.entities[key] = ev.Entity
+		case EventTypeUnset:
+			delete(cs.entities, key)
+		}
+	}
+
+	cs.lastSync = time.Now()
+}
+
+// resetEntities forgets the entities reported by the collector, they're
+// checked against the entities it reports again by staleEvents.
+func (cs *collectorStatus) resetEntities() {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.resyncedEntities == nil {
+		cs.resyncedEntities = cs.entities
+	} else {
+		// the previous resync wasn't followed by a successful pull
+		for key, entity := range cs.entities {
+			cs.resyncedEntities[key] = entity
+		}
+	}
+	cs.entities = make(map[collectorEntityKey]Entity)
+}
+
+// staleEvents returns the events unsetting the entities reported before the
+// last resync that the collector didn't report again. It must be called once
+// the restarted collector reported all its entities, after its first
+// successful pull.
+func (cs *collectorStatus) staleEvents() []CollectorEvent {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	previous := cs.resyncedEntities
+	cs.resyncedEntities = nil
+
+	var events []CollectorEvent
+	for key, entity := range previous {
+		if _, ok := cs.entities[key]; ok {
+			continue
+		}
+
+		events = append(events, CollectorEvent{
+			Type:   EventTypeUnset,
+			Source: key.source,
+			Entity: entity,
+		})
+	}
+
+	return events
+}
+
+func (cs *collectorStatus) health(id string) CollectorHealth {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	health := CollectorHealth{
+		ID:          id,
+		Started:     cs.started,
+		ErrorStreak: cs.errorStreak,
+		LastError:   cs.lastError,
+	}
+	if !cs.lastSync.IsZero() {
+		health.LastSync = cs.lastSync.UnixNano()
+	}
+
+	return health
+}
+
+// collectorStore is the store given to a collector, it records the events the
+// collector sends to the store.
+type collectorStore struct {
+	*store
+	status *collectorStatus
+}
+
+// Notify records the events in the status of the collector and notifies the
+// store.
+func (cs *collectorStore) Notify(events []CollectorEvent) {
+	if len(events) > 0 {
+		cs.status.recordEvents(events)
+	}
+
+	cs.store.Notify(events)
+}
+
+// CollectorsHealth returns the health of the collectors that are started or
+// that will be retried, sorted by ID.
+func (s *store) CollectorsHealth() []CollectorHealth {
+	s.collectorMut.RLock()
+	defer s.collectorMut.RUnlock()
+
+	healths := make([]CollectorHealth, 0, len(s.statuses))
+	for id, status := range s.statuses {
+		healths = append(healths, status.health(id))
+	}
+
+	sort.Slice(healths, func(i, j int) bool { return healths[i].ID < healths[j].ID })
+
+	return healths
+}
@@ -0,0 +1,179 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package workloadmeta
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCollector struct {
+	containers []*Container
+	pullErr    error
+}
+
+func (c *fakeCollector) Start(_ context.Context, store Store) error {
+	for _, container := range c.containers {
+		store.Notify([]CollectorEvent{
+			{
+				Type:   EventTypeSet,
+				Source: fooSource,
+				Entity: container,
+			},
+		})
+	}
+
+	return nil
+}
+
+func (c *fakeCollector) Pull(_ context.Context) error {
+	return c.pullErr
+}
+
+// fakePullCollector only reports its containers when it's pulled, like the
+// kubelet collector
+type fakePullCollector struct {
+	store      Store
+	containers *[]*Container
+	pullErr    *error
+}
+
+func (c *fakePullCollector) Start(_ context.Context, store Store) error {
+	c.store = store
+	return nil
+}
+
+func (c *fakePullCollector) Pull(_ context.Context) error {
+	if *c.pullErr != nil {
+		return *c.pullErr
+	}
+
+	events := make([]CollectorEvent, 0, len(*c.containers))
+	for _, container := range *c.containers {
+		events = append(events, CollectorEvent{
+			Type:   EventTypeSet,
+			Source: fooSource,
+			Entity: container,
+		})
+	}
+	c.store.Notify(events)
+
+	return nil
+}
+
+func newTestContainer(id string) *Container {
+	return &Container{
+		EntityID: EntityID{
+			Kind: KindContainer,
+			ID:   id,
+		},
+	}
+}
+
+func receiveEvents(t *testing.T, s *store) []CollectorEvent {
+	var events []CollectorEvent
+	for {
+		select {
+		case evs := <-s.eventCh:
+			events = append(events, evs...)
+		default:
+			return events
+		}
+	}
+}
+
+func TestCollectorsHealthAndResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	containers := []*Container{newTestContainer("foo"), newTestContainer("bar")}
+	pullErr := errors.New("connection refused")
+	s := NewStore(map[string]collectorFactory{
+		"fake": func() Collector {
+			return &fakeCollector{containers: containers, pullErr: pullErr}
+		},
+	}).(*store)
+	s.ctx = ctx
+
+	s.startCandidates(ctx)
+	assert.Len(t, receiveEvents(t, s), 2)
+
+	health := s.CollectorsHealth()
+	require.Len(t, health, 1)
+	assert.Equal(t, "fake", health[0].ID)
+	assert.True(t, health[0].Started)
+	assert.NotZero(t, health[0].LastSync)
+	assert.Zero(t, health[0].ErrorStreak)
+
+	s.pull(ctx)
+	s.pull(ctx)
+	assert.Eventually(t, func() bool {
+		return s.CollectorsHealth()[0].ErrorStreak == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "connection refused", s.CollectorsHealth()[0].LastError)
+
+	// bar was deleted while the collector was stuck, it's unset by the resync
+	containers = []*Container{newTestContainer("foo")}
+	pullErr = nil
+	require.NoError(t, s.Resync("fake"))
+
+	events := receiveEvents(t, s)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventTypeSet, events[0].Type)
+	assert.Equal(t, "foo", events[0].Entity.GetID().ID)
+	assert.Equal(t, EventTypeUnset, events[1].Type)
+	assert.Equal(t, "bar", events[1].Entity.GetID().ID)
+	assert.Equal(t, Source(fooSource), events[1].Source)
+
+	health = s.CollectorsHealth()
+	assert.True(t, health[0].Started)
+	assert.Zero(t, health[0].ErrorStreak)
+
+	assert.Error(t, s.Resync("unknown"))
+}
+
+func TestResyncPullCollector(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	containers := []*Container{newTestContainer("foo"), newTestContainer("bar")}
+	var pullErr error
+	s := NewStore(map[string]collectorFactory{
+		"fake": func() Collector {
+			return &fakePullCollector{containers: &containers, pullErr: &pullErr}
+		},
+	}).(*store)
+	s.ctx = ctx
+
+	s.startCandidates(ctx)
+	s.pullCollector(ctx, "fake", s.collectors["fake"].collector, s.statuses["fake"])
+	assert.Len(t, receiveEvents(t, s), 2)
+
+	// the entities aren't unset before the restarted collector reports them
+	containers = []*Container{newTestContainer("foo")}
+	pullErr = errors.New("connection refused")
+	require.NoError(t, s.Resync("fake"))
+	assert.Empty(t, receiveEvents(t, s))
+
+	pullErr = nil
+	s.pullCollector(ctx, "fake", s.collectors["fake"].collector, s.statuses["fake"])
+
+	events := receiveEvents(t, s)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventTypeSet, events[0].Type)
+	assert.Equal(t, "foo", events[0].Entity.GetID().ID)
+	assert.Equal(t, EventTypeUnset, events[1].Type)
+	assert.Equal(t, "bar", events[1].Entity.GetID().ID)
+
+	// the stale entities are only unset once
+	s.pullCollector(ctx, "fake", s.collectors["fake"].collector, s.statuses["fake"])
+	assert.Len(t, receiveEvents(t, s), 1)
+}
@@ -7,6 +7,7 @@ package workloadmeta
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"sync"
 	"time"
@@ -25,6 +26,7 @@ var (
 const (
 	retryCollectorInterval = 30 * time.Second
 	pullCollectorInterval  = 5 * time.Second
+	resyncStopTimeout      = 30 * time.Second
 	eventBundleChTimeout   = 1 * time.Second
 	eventChBufferSize      = 50
 )
@@ -82,14 +84,24 @@ type store struct {
 	subscribers    []subscriber
 
 	collectorMut sync.RWMutex
+	ctx          context.Context
+	catalog      map[string]collectorFactory
 	candidates   map[string]Collector
-	collectors   map[string]Collector
+	collectors   map[string]collectorInstance
+	statuses     map[string]*collectorStatus
 
 	eventCh chan []CollectorEvent
 }
 
 var _ Store = &store{}
 
+// collectorInstance is a started collector, along with the function stopping
+// it when it's resynced.
+type collectorInstance struct {
+	collector Collector
+	cancel    context.CancelFunc
+}
+
 // NewStore creates a new workload metadata store, building a new instance of
 // each collector in the catalog. Call Start to start the store and its
 // collectors.
@@ -101,8 +113,10 @@ func NewStore(catalog map[string]collectorFactory) Store {
 
 	return &store{
 		store:      make(map[Kind]map[string]sourceToEntity),
+		catalog:    catalog,
 		candidates: candidates,
-		collectors: make(map[string]Collector),
+		collectors: make(map[string]collectorInstance),
+		statuses:   make(map[string]*collectorStatus),
 		eventCh:    make(chan []CollectorEvent, eventChBufferSize),
 	}
 }
@@ -115,6 +129,10 @@ func (s *store) Start(ctx context.Context) {
 
 	pullCtx, pullCancel := context.WithTimeout(ctx, pullCollectorInterval)
 
+	s.collectorMut.Lock()
+	s.ctx = ctx
+	s.collectorMut.Unlock()
+
 	// Start processing events before starting collectors, as in some cases
 	// they may be able to generate more events than what fits in eventCh's
 	// buffer, and the store will deadlock.
@@ -136,11 +154,11 @@ func (s *store) Start(ctx context.Context) {
 				s.handleEvents(evs)
 
 			case <-retryTicker.C:
-				stop := s.startCandidates(ctx)
-
-				if stop {
-					retryTicker.Stop()
-				}
+				// the ticker isn't stopped once all the
+				// candidates are started, the collectors
+				// failing to restart on a resync become
+				// candidates again
+				s.startCandidates(ctx)
 
 			case <-ctx.Done():
 				retryTicker.Stop()
@@ -313,12 +331,18 @@ func (s *store) Notify(events []CollectorEvent) {
 	}
 }
 
-func (s *store) startCandidates(ctx context.Context) bool {
+func (s *store) startCandidates(ctx context.Context) {
 	s.collectorMut.Lock()
 	defer s.collectorMut.Unlock()
 
 	for id, c := range s.candidates {
-		err := c.Start(ctx, s)
+		status, ok := s.statuses[id]
+		if !ok {
+			status = newCollectorStatus()
+			s.statuses[id] = status
+		}
+
+		instance, err := s.startCollector(ctx, c, status)
 
 		// Leave candidates that returned a retriable error to be
 		// re-started in the next tick
@@ -330,9 +354,12 @@ func (s *store) startCandidates(ctx context.Context) bool {
 		// Store successfully started collectors for future reference
 		if err == nil {
 			log.Infof("workloadmeta collector %q started successfully", id)
-			s.collectors[id] = c
+			s.collectors[id] = instance
 		} else {
 			log.Infof("workloadmeta collector %q could not start. error: %s", id, err)
+			// the disabled collectors aren't reported in the
+			// collectors health
+			delete(s.statuses, id)
 		}
 
 		// Remove non-retriable and successfully started collectors
@@ -340,23 +367,120 @@ func (s *store) startCandidates(ctx context.Context) bool {
 		// next tick
 		delete(s.candidates, id)
 	}
+}
+
+// startCollector starts a collector with its own context, to be able to stop
+// it when it's resynced, and a store recording its events in its status.
+func (s *store) startCollector(ctx context.Context, c Collector, status *collectorStatus) (collectorInstance, error) {
+	collectorCtx, cancel := context.WithCancel(ctx)
+
+	err := c.Start(collectorCtx, &collectorStore{store: s, status: status})
+	if err != nil {
+		cancel()
+		status.recordError(err)
+		return collectorInstance{}, err
+	}
+
+	status.setStarted(true)
+	status.recordSuccess()
+
+	return collectorInstance{collector: c, cancel: cancel}, nil
+}
+
+// Resync restarts a collector, e.g. to recover from a stuck stream of events.
+// The entities the collector reported before the resync but doesn't report
+// again once restarted are removed from the store.
+func (s *store) Resync(id string) error {
+	s.collectorMut.Lock()
+	instance, ok := s.collectors[id]
+	if ok {
+		delete(s.collectors, id)
+	}
+	factory := s.catalog[id]
+	status := s.statuses[id]
+	ctx := s.ctx
+	s.collectorMut.Unlock()
+
+	if !ok {
+		return fmt.Errorf("workloadmeta collector %q is not running", id)
+	}
+
+	log.Infof("resyncing workloadmeta collector %q", id)
+
+	instance.cancel()
+	status.setStarted(false)
+	status.resetEntities()
+
+	// the new instance can't be started before the previous one released
+	// what it holds, e.g. its subscription to the runtime events
+	if stoppable, ok := instance.collector.(StoppableCollector); ok {
+		select {
+		case <-stoppable.Stopped():
+		case <-time.After(resyncStopTimeout):
+			go func() {
+				<-stoppable.Stopped()
+				s.collectorMut.Lock()
+				s.candidates[id] = factory()
+				s.collectorMut.Unlock()
+			}()
+			return fmt.Errorf("workloadmeta collector %q did not stop after %s, it will be restarted once stopped", id, resyncStopTimeout)
+		}
+	}
+
+	// the collector is started without holding collectorMut, as the
+	// events it sends while starting are handled by the same goroutine as
+	// the pulls, which need to acquire it
+	c := factory()
+	newInstance, err := s.startCollector(ctx, c, status)
+	if err != nil {
+		s.collectorMut.Lock()
+		defer s.collectorMut.Unlock()
+
+		if retry.IsErrWillRetry(err) {
+			s.candidates[id] = c
+		}
+		return fmt.Errorf("workloadmeta collector %q could not restart: %w", id, err)
+	}
+
+	// the collectors only reporting their entities when they're pulled
+	// haven't reported them yet, the stale entities are unset after the
+	// first successful pull, here or in the next pulls
+	pullCtx, pullCancel := context.WithTimeout(ctx, pullCollectorInterval)
+	s.pullCollector(pullCtx, id, c, status)
+	pullCancel()
 
-	return len(s.candidates) == 0
+	s.collectorMut.Lock()
+	s.collectors[id] = newInstance
+	s.collectorMut.Unlock()
+
+	log.Infof("workloadmeta collector %q resynced successfully", id)
+
+	return nil
 }
 
 func (s *store) pull(ctx context.Context) {
 	s.collectorMut.RLock()
 	defer s.collectorMut.RUnlock()
 
-	for id, c := range s.collectors {
+	for id, instance := range s.collectors {
 		// Run each pull in its own separate goroutine to reduce
 		// latency and unlock the main goroutine to do other work.
-		go func(id string, c Collector) {
-			err := c.Pull(ctx)
-			if err != nil {
-				log.Warnf("error pulling from collector %q: %s", id, err.Error())
-			}
-		}(id, c)
+		go s.pullCollector(ctx, id, instance.collector, s.statuses[id])
+	}
+}
+
+func (s *store) pullCollector(ctx context.Context, id string, c Collector, status *collectorStatus) {
+	err := c.Pull(ctx)
+	if err != nil {
+		log.Warnf("error pulling from collector %q: %s", id, err.Error())
+		status.recordError(err)
+		return
+	}
+
+	status.recordSuccess()
+
+	if events := status.staleEvents(); len(events) > 0 {
+		s.Notify(events)
 	}
 }
 
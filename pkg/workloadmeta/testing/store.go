@@ -141,6 +141,16 @@ func (s *Store) Dump(verbose bool) workloadmeta.WorkloadDumpResponse {
 	panic("not implemented")
 }
 
+// CollectorsHealth is not implemented in the testing store.
+func (s *Store) CollectorsHealth() []workloadmeta.CollectorHealth {
+	panic("not implemented")
+}
+
+// Resync is not implemented in the testing store.
+func (s *Store) Resync(collectorID string) error {
+	panic("not implemented")
+}
+
 func (s *Store) getEntityByKind(kind workloadmeta.Kind, id string) (workloadmeta.Entity, error) {
 	entitiesOfKind, ok := s.store[kind]
 	if !ok {
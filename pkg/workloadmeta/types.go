@@ -32,6 +32,8 @@ type Store interface {
 	GetECSTask(id string) (*ECSTask, error)
 	Notify(events []CollectorEvent)
 	Dump(verbose bool) WorkloadDumpResponse
+	CollectorsHealth() []CollectorHealth
+	Resync(collectorID string) error
 }
 
 // Kind is the kind of an entity.
@@ -224,9 +226,10 @@ func (c ContainerPort) String(verbose bool) string {
 // OrchestratorContainer is a reference to a Container with
 // orchestrator-specific data attached to it.
 type OrchestratorContainer struct {
-	ID    string
-	Name  string
-	Image ContainerImage
+	ID              string
+	Name            string
+	Image           ContainerImage
+	SecurityProfile ContainerSecurityProfile
 }
 
 // String returns a string representation of OrchestratorContainer.
@@ -234,6 +237,19 @@ func (o OrchestratorContainer) String(_ bool) string {
 	return fmt.Sprintln("Name:", o.Name, "ID:", o.ID)
 }
 
+// ContainerSecurityProfile is the confinement of a container declared by its
+// orchestrator, the fields are empty when it isn't declared.
+type ContainerSecurityProfile struct {
+	// Seccomp is the seccomp profile: RuntimeDefault, Unconfined or
+	// Localhost/<profile>
+	Seccomp string
+	// AppArmor is the AppArmor profile: runtime/default, unconfined or
+	// localhost/<profile>
+	AppArmor string
+	// SELinuxType is the SELinux type of the container processes
+	SELinuxType string
+}
+
 // Container is a containerized workload.
 type Container struct {
 	EntityID
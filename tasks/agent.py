@@ -56,6 +56,7 @@ AGENT_CORECHECKS = [
     "oom_kill",
     "systemd",
     "tcp_queue_length",
+    "tcp_retransmit",
     "uptime",
     "winproc",
     "jetson",
@@ -100,11 +101,15 @@ def build(
     exclude_rtloader=False,
     go_mod="mod",
     windows_sysprobe=False,
+    fips=False,
 ):
     """
     Build the agent. If the bits to include in the build are not specified,
     the values from `invoke.yaml` will be used.
 
+    The `--fips` flag builds the FIPS variant of the agent, which requires the `go`
+    binary in the PATH to be a FIPS toolchain (BoringCrypto or system OpenSSL backed).
+
     Example invokation:
         inv agent.build --build-exclude=systemd
     """
@@ -169,6 +174,11 @@ def build(
         build_exclude = [] if build_exclude is None else build_exclude.split(",")
         build_tags = get_build_tags(build_include, build_exclude)
 
+    if fips:
+        # The FIPS validated cryptographic modules are linked through cgo
+        build_tags.append("fips")
+        env["CGO_ENABLED"] = "1"
+
     # Generating go source from templates by running go generate on ./pkg/status
     generate(ctx)
 
@@ -19,6 +19,7 @@ ALL_TAGS = set(
         "ec2",
         "etcd",
         "fargateprocess",
+        "fips",  # Requires a FIPS Go toolchain (BoringCrypto or system OpenSSL backed)
         "gce",
         "jmx",
         "jetson",
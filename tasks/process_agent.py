@@ -34,9 +34,13 @@ def build(
     python_runtimes='3',
     arch="x64",
     go_mod="mod",
+    fips=False,
 ):
     """
     Build the process agent
+
+    The `--fips` flag builds the FIPS variant of the process agent, which requires the `go`
+    binary in the PATH to be a FIPS toolchain (BoringCrypto or system OpenSSL backed).
     """
     ldflags, gcflags, env = get_build_flags(ctx, major_version=major_version, python_runtimes=python_runtimes)
 
@@ -95,6 +99,11 @@ def build(
     if sys.platform == 'win32' and "secrets" in build_tags:
         build_tags.remove("secrets")
 
+    if fips:
+        # The FIPS validated cryptographic modules are linked through cgo
+        build_tags.append("fips")
+        env["CGO_ENABLED"] = "1"
+
     # TODO static option
     cmd = 'go build -mod={go_mod} {race_opt} {build_type} -tags "{go_build_tags}" '
     cmd += '-o {agent_bin} -gcflags="{gcflags}" -ldflags="{ldflags}" {REPO_PATH}/cmd/process-agent'
@@ -652,6 +652,7 @@ def generate_runtime_files(ctx):
     runtime_compiler_files = [
         "./pkg/collector/corechecks/ebpf/probe/oom_kill.go",
         "./pkg/collector/corechecks/ebpf/probe/tcp_queue_length.go",
+        "./pkg/collector/corechecks/ebpf/probe/tcp_retransmit.go",
         "./pkg/network/tracer/compile.go",
         "./pkg/network/tracer/connection/kprobe/compile.go",
         "./pkg/security/probe/compile.go",